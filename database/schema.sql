@@ -12,8 +12,8 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE TABLE IF NOT EXISTS symbols (
     id SERIAL PRIMARY KEY,
     symbol VARCHAR(20) NOT NULL UNIQUE,
-    base_asset VARCHAR(10) NOT NULL,
-    quote_asset VARCHAR(10) NOT NULL,
+    base_asset VARCHAR(20) NOT NULL,
+    quote_asset VARCHAR(20) NOT NULL,
     status VARCHAR(20) DEFAULT 'TRADING',
     min_qty DECIMAL(20,8),
     max_qty DECIMAL(20,8),
@@ -26,6 +26,10 @@ CREATE TABLE IF NOT EXISTS symbols (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 交易所同步的资产名可能超过10个字符，已有数据库需放宽字段长度
+ALTER TABLE symbols ALTER COLUMN base_asset TYPE VARCHAR(20);
+ALTER TABLE symbols ALTER COLUMN quote_asset TYPE VARCHAR(20);
+
 -- 2. K线数据表 (主表)
 CREATE TABLE IF NOT EXISTS klines (
     id BIGSERIAL PRIMARY KEY,
@@ -70,9 +74,13 @@ CREATE TABLE IF NOT EXISTS backtest_runs (
     total_commission DECIMAL(20,8),
     status VARCHAR(20) DEFAULT 'RUNNING',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    completed_at TIMESTAMP
+    completed_at TIMESTAMP,
+    manifest JSONB
 );
 
+-- 回测复现清单（代码版本、配置快照、数据哈希、随机种子），已有数据库需补充字段
+ALTER TABLE backtest_runs ADD COLUMN IF NOT EXISTS manifest JSONB;
+
 -- 4. 交易记录表
 CREATE TABLE IF NOT EXISTS trades (
     id BIGSERIAL PRIMARY KEY,
@@ -117,6 +125,90 @@ CREATE TABLE IF NOT EXISTS sync_status (
     UNIQUE(symbol, timeframe)
 );
 
+-- 7. 交易日历表 (交易所公告的维护、集合竞价等禁止交易时段)
+CREATE TABLE IF NOT EXISTS trading_calendars (
+    id SERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,              -- 交易对，'*' 表示对所有交易对生效
+    start_time TIMESTAMP NOT NULL,            -- 暂停开始时间（UTC）
+    end_time TIMESTAMP NOT NULL,              -- 恢复交易时间（UTC）
+    reason VARCHAR(200),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CHECK (end_time > start_time)
+);
+
+-- 8. 模拟盘会话表 (Dry Run 模拟账户状态，重启后恢复)
+CREATE TABLE IF NOT EXISTS paper_sessions (
+    session_id VARCHAR(100) PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    state JSONB NOT NULL,                     -- 现金、持仓、成交记录等完整状态
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 9. 账户快照表 (实盘/Dry Run 定期记录余额、持仓和按市价计算的权益，pnl 命令按日/周汇总)
+CREATE TABLE IF NOT EXISTS equity_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    session_id VARCHAR(100) NOT NULL,         -- 实盘 live_<交易所>_<交易对>，测试网 testnet_...，Dry Run 为模拟盘会话名
+    symbol VARCHAR(20) NOT NULL,
+    mode VARCHAR(10) NOT NULL,                -- live / testnet / dry
+    snapshot_time TIMESTAMP NOT NULL,
+    price DECIMAL(20,8) NOT NULL,             -- 估值价格（K线收盘价）
+    cash DECIMAL(30,8) NOT NULL,
+    position DECIMAL(30,8) NOT NULL,
+    equity DECIMAL(30,8) NOT NULL,
+    cost_basis DECIMAL(30,8) NOT NULL,        -- 持仓成本（含买入手续费）
+    realized_pnl DECIMAL(30,8) NOT NULL,      -- 会话累计已实现盈亏
+    unrealized_pnl DECIMAL(30,8) NOT NULL,    -- 持仓市值 - 持仓成本
+    trace_id VARCHAR(64),                     -- 写入快照的运行跟踪ID（每次启动不同，与日志 trace=<id> 对应）
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 已有数据库升级：添加运行跟踪ID列
+ALTER TABLE equity_snapshots ADD COLUMN IF NOT EXISTS trace_id VARCHAR(64);
+
+-- 10. 策略状态表 (实盘/Dry Run/只发信号的策略内部状态，每根K线后保存，重启后恢复)
+CREATE TABLE IF NOT EXISTS strategy_states (
+    state_key VARCHAR(150) PRIMARY KEY,       -- <会话名>_<周期>，如 live_binance_BTCUSDT_4h
+    strategy VARCHAR(100) NOT NULL,           -- 策略名称
+    state JSONB NOT NULL,                     -- 移动止盈最高价、分批止盈级别、冷却期、K线历史等
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 11. 盘口买一卖一记录表 (实盘/Dry Run 定期记录，回测按价差统计模拟吃单成本)
+CREATE TABLE IF NOT EXISTS book_tickers (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    captured_at TIMESTAMP NOT NULL,
+    bid_price DECIMAL(20,8) NOT NULL,
+    bid_qty DECIMAL(30,8) NOT NULL,
+    ask_price DECIMAL(20,8) NOT NULL,
+    ask_qty DECIMAL(30,8) NOT NULL
+);
+
+-- 12. 永续合约资金费率表 (futures-sync 下载，作为现货策略的辅助数据)
+CREATE TABLE IF NOT EXISTS funding_rates (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    funding_time TIMESTAMP NOT NULL,
+    funding_rate DECIMAL(20,10) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(symbol, funding_time)
+);
+
+-- 13. 永续合约持仓量表 (按统计周期记录)
+CREATE TABLE IF NOT EXISTS open_interest (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    period VARCHAR(10) NOT NULL,
+    snapshot_time TIMESTAMP NOT NULL,
+    open_interest DECIMAL(30,8) NOT NULL,
+    open_interest_value DECIMAL(30,8) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(symbol, period, snapshot_time)
+);
+
 -- 创建索引优化查询性能
 -- K线数据查询索引
 CREATE INDEX IF NOT EXISTS idx_klines_symbol_timeframe ON klines(symbol, timeframe);
@@ -124,6 +216,12 @@ CREATE INDEX IF NOT EXISTS idx_klines_open_time ON klines(open_time);
 CREATE INDEX IF NOT EXISTS idx_klines_symbol_timeframe_time ON klines(symbol, timeframe, open_time);
 CREATE INDEX IF NOT EXISTS idx_klines_close_time ON klines(close_time);
 
+-- 账户快照索引
+CREATE INDEX IF NOT EXISTS idx_equity_snapshots_session_time ON equity_snapshots(session_id, snapshot_time);
+
+-- 盘口记录索引
+CREATE INDEX IF NOT EXISTS idx_book_tickers_symbol_time ON book_tickers(symbol, captured_at);
+
 -- 回测相关索引
 CREATE INDEX IF NOT EXISTS idx_backtest_runs_symbol ON backtest_runs(symbol);
 CREATE INDEX IF NOT EXISTS idx_backtest_runs_created_at ON backtest_runs(created_at);
@@ -133,6 +231,9 @@ CREATE INDEX IF NOT EXISTS idx_trades_symbol_timestamp ON trades(symbol, timesta
 -- 同步状态索引
 CREATE INDEX IF NOT EXISTS idx_sync_status_symbol_timeframe ON sync_status(symbol, timeframe);
 
+-- 交易日历索引
+CREATE INDEX IF NOT EXISTS idx_trading_calendars_symbol_time ON trading_calendars(symbol, start_time);
+
 -- 创建更新时间触发器函数
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
@@ -155,6 +256,12 @@ CREATE TRIGGER update_strategy_configs_updated_at BEFORE UPDATE ON strategy_conf
 CREATE TRIGGER update_sync_status_updated_at BEFORE UPDATE ON sync_status
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_paper_sessions_updated_at BEFORE UPDATE ON paper_sessions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_strategy_states_updated_at BEFORE UPDATE ON strategy_states
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
 -- 插入一些常用的交易对
 INSERT INTO symbols (symbol, base_asset, quote_asset) VALUES
 ('BTCUSDT', 'BTC', 'USDT'),
@@ -10,6 +10,7 @@ require (
 	github.com/xpwu/go-cmd v0.2.0
 	github.com/xpwu/go-config v0.1.0
 	github.com/xpwu/go-log v0.1.0
+	github.com/xpwu/go-x v0.1.0
 )
 
 require (
@@ -21,6 +22,5 @@ require (
 	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/xpwu/go-x v0.1.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
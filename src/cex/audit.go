@@ -0,0 +1,95 @@
+package cex
+
+import (
+	"context"
+	"time"
+)
+
+// 订单审计动作
+const (
+	AuditActionBuy       = "buy"
+	AuditActionSell      = "sell"
+	AuditActionStopLoss  = "place_stop_loss"
+	AuditActionCancel    = "cancel_order"
+	AuditActionOCOSell   = "place_oco_sell"
+	AuditActionCancelOCO = "cancel_oco"
+)
+
+// OrderAuditRecord 订单审计记录：一次下单/撤单请求及交易所的响应
+type OrderAuditRecord struct {
+	Time       time.Time   `json:"time"`
+	Exchange   string      `json:"exchange"`
+	Action     string      `json:"action"`
+	Symbol     string      `json:"symbol"`
+	Request    interface{} `json:"request"`
+	Response   interface{} `json:"response,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+	TraceID    string      `json:"trace_id,omitempty"` // 发起请求的运行（回测或实盘会话）跟踪ID
+}
+
+// OrderAuditor 订单审计日志（由 logging.AuditLog 实现，写入失败由实现方记录错误，不影响下单）
+type OrderAuditor interface {
+	RecordOrder(ctx context.Context, record *OrderAuditRecord)
+}
+
+// AuditOrder 调用交易所下单/撤单接口并记录请求和响应，auditor 为空时只调用接口
+func AuditOrder[T any](ctx context.Context, auditor OrderAuditor, exchange, action string, pair TradingPair, request interface{}, call func() (T, error)) (T, error) {
+	if auditor == nil {
+		return call()
+	}
+
+	start := time.Now()
+	response, err := call()
+
+	record := &OrderAuditRecord{
+		Time:       start.UTC(),
+		Exchange:   exchange,
+		Action:     action,
+		Symbol:     pair.String(),
+		Request:    request,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Response = response
+	}
+
+	auditor.RecordOrder(ctx, record)
+	return response, err
+}
+
+// AuditCancel 调用交易所撤单接口并记录请求和结果
+func AuditCancel(ctx context.Context, auditor OrderAuditor, exchange, action string, pair TradingPair, request interface{}, call func() error) error {
+	_, err := AuditOrder(ctx, auditor, exchange, action, pair, request, func() (interface{}, error) {
+		return nil, call()
+	})
+	return err
+}
+
+// orderAuditors 依次交给多个审计记录器
+type orderAuditors []OrderAuditor
+
+func (a orderAuditors) RecordOrder(ctx context.Context, record *OrderAuditRecord) {
+	for _, auditor := range a {
+		auditor.RecordOrder(ctx, record)
+	}
+}
+
+// CombineOrderAuditors 合并多个审计记录器（如审计日志和下单延迟统计），忽略 nil，没有时返回 nil
+func CombineOrderAuditors(auditors ...OrderAuditor) OrderAuditor {
+	var combined orderAuditors
+	for _, auditor := range auditors {
+		if auditor != nil {
+			combined = append(combined, auditor)
+		}
+	}
+	switch len(combined) {
+	case 0:
+		return nil
+	case 1:
+		return combined[0]
+	}
+	return combined
+}
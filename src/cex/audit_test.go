@@ -0,0 +1,80 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingAuditor 记录审计记录
+type recordingAuditor struct {
+	records []*OrderAuditRecord
+}
+
+func (a *recordingAuditor) RecordOrder(ctx context.Context, record *OrderAuditRecord) {
+	a.records = append(a.records, record)
+}
+
+func TestAuditOrder_RecordsRequestAndResponse(t *testing.T) {
+	auditor := &recordingAuditor{}
+	pair := TradingPair{Base: "ETH", Quote: "USDT"}
+
+	result, err := AuditOrder(context.Background(), auditor, "bybit", AuditActionSell, pair, "request", func() (*OrderResult, error) {
+		return &OrderResult{OrderID: "7"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "7", result.OrderID)
+	if assert.Len(t, auditor.records, 1) {
+		record := auditor.records[0]
+		assert.Equal(t, "bybit", record.Exchange)
+		assert.Equal(t, AuditActionSell, record.Action)
+		assert.Equal(t, "ETH/USDT", record.Symbol)
+		assert.Equal(t, "request", record.Request)
+		assert.Equal(t, result, record.Response)
+		assert.Empty(t, record.Error)
+	}
+}
+
+func TestAuditOrder_RecordsErrorWithoutResponse(t *testing.T) {
+	auditor := &recordingAuditor{}
+	rejected := errors.New("insufficient balance")
+
+	_, err := AuditOrder(context.Background(), auditor, "binance", AuditActionBuy, TradingPair{Base: "BTC", Quote: "USDT"}, nil, func() (*OrderResult, error) {
+		return nil, rejected
+	})
+
+	assert.ErrorIs(t, err, rejected)
+	if assert.Len(t, auditor.records, 1) {
+		assert.Equal(t, "insufficient balance", auditor.records[0].Error)
+		assert.Nil(t, auditor.records[0].Response)
+	}
+}
+
+func TestAuditOrder_NilAuditorOnlyCalls(t *testing.T) {
+	calls := 0
+	err := AuditCancel(context.Background(), nil, "binance", AuditActionCancel, TradingPair{}, nil, func() error {
+		calls++
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCombineOrderAuditors(t *testing.T) {
+	assert.Nil(t, CombineOrderAuditors(nil, nil))
+
+	first := &recordingAuditor{}
+	assert.Same(t, first, CombineOrderAuditors(nil, first))
+
+	second := &recordingAuditor{}
+	combined := CombineOrderAuditors(first, nil, second)
+	_, _ = AuditOrder(context.Background(), combined, "binance", AuditActionBuy, TradingPair{Base: "BTC", Quote: "USDT"}, nil, func() (*OrderResult, error) {
+		return &OrderResult{}, nil
+	})
+	assert.Len(t, first.records, 1)
+	assert.Len(t, second.records, 1)
+}
@@ -3,6 +3,7 @@ package binance
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,37 +11,46 @@ import (
 	"tradingbot/src/database"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
 	"github.com/shopspring/decimal"
 )
 
 // Client Binance客户端实现
 type Client struct {
 	client    *binance.Client
+	futures   *futures.Client // U 本位合约公开行情（资金费率、持仓量）
 	apiKey    string
 	secretKey string
 	database  *database.PostgresDB // 内部管理的数据库连接
+	retryer   *cex.Retryer         // REST 请求重试
+	testnet   bool                 // 是否连接现货测试网
 }
 
 // NewClient 创建Binance客户端
 func NewClient(apiKey, secretKey string) *Client {
+	config := &ConfigValue
+
+	// 测试网开关是 go-binance 的全局变量，需在创建客户端前设置（同时影响账户数据流的 WebSocket 地址）
+	binance.UseTestnet = config.Testnet
 	binanceClient := binance.NewClient(apiKey, secretKey)
+	if baseURL := config.APIBaseURL(); baseURL != "" {
+		binanceClient.BaseURL = baseURL
+	}
+	if config.Testnet {
+		fmt.Printf("🧪 Binance TESTNET: %s\n", config.APIBaseURL())
+		if apiKey == "" {
+			fmt.Println("⚠️ TestnetAPIKey not set, only public market data is available on testnet")
+		}
+	}
 
 	// 初始化数据库连接
-	config := &ConfigValue
 	dbConfig := database.GetDatabaseConfigForCEX(config.DBName)
 
 	var db *database.PostgresDB
 	if dbConfig.Host != "" {
 		fmt.Printf("🗄️ Connecting to binance database...")
 		var err error
-		db, err = database.NewPostgresDB(
-			dbConfig.Host,
-			dbConfig.Port,
-			dbConfig.User,
-			dbConfig.Password,
-			dbConfig.DBName,
-			dbConfig.SSLMode,
-		)
+		db, err = database.OpenPostgresDB(dbConfig)
 		if err != nil {
 			fmt.Printf(" failed: %v\n", err)
 			fmt.Println("⚠️ Database unavailable, using network only")
@@ -50,11 +60,20 @@ func NewClient(apiKey, secretKey string) *Client {
 		}
 	}
 
+	retryConfig := config.Retry
+	if err := retryConfig.Validate(); err != nil {
+		fmt.Printf("⚠️ Invalid binance retry config (%v), using defaults\n", err)
+		retryConfig = cex.DefaultRetryConfig()
+	}
+
 	return &Client{
 		client:    binanceClient,
+		futures:   futures.NewClient("", ""),
 		apiKey:    apiKey,
 		secretKey: secretKey,
 		database:  db,
+		retryer:   cex.NewRetryer(retryConfig, isRetryableError),
+		testnet:   config.Testnet,
 	}
 }
 
@@ -63,15 +82,31 @@ func (c *Client) GetName() string {
 	return "binance"
 }
 
+// IsTestnet 是否连接现货测试网
+func (c *Client) IsTestnet() bool {
+	return c.testnet
+}
+
 // GetDatabase 获取数据库连接
 func (c *Client) GetDatabase() interface{} {
 	return c.database
 }
 
-// GetTradingFee 获取交易手续费率
+// GetTradingFee 获取交易手续费率（当前等级的 taker 费率，含 BNB 抵扣）
 func (c *Client) GetTradingFee() float64 {
+	return c.GetFeeSchedule().Rate(false, 0)
+}
+
+// GetFeeSchedule 获取手续费表
+func (c *Client) GetFeeSchedule() cex.FeeSchedule {
+	config := &ConfigValue
+	return config.Fees
+}
+
+// TradingPermission 配置的交易开关（EnableTrading、ReadOnly）
+func (c *Client) TradingPermission() cex.TradingPermission {
 	config := &ConfigValue
-	return config.Fee
+	return cex.TradingPermission{EnableTrading: config.EnableTrading, ReadOnly: config.ReadOnly}
 }
 
 // tradingPairToSymbol 将标准化交易对转换为Binance格式
@@ -93,13 +128,13 @@ func (c *Client) convertKlineData(kline *binance.Kline, pair cex.TradingPair) *c
 
 	return &cex.KlineData{
 		TradingPair:         pair,
-		OpenTime:            time.Unix(kline.OpenTime/1000, 0),
+		OpenTime:            time.UnixMilli(kline.OpenTime),
 		Open:                open,
 		High:                high,
 		Low:                 low,
 		Close:               close,
 		Volume:              volume,
-		CloseTime:           time.Unix(kline.CloseTime/1000, 0),
+		CloseTime:           time.UnixMilli(kline.CloseTime),
 		QuoteVolume:         quoteVolume,
 		TakerBuyVolume:      takerBuyVolume,
 		TakerBuyQuoteVolume: takerBuyQuoteVolume,
@@ -110,11 +145,15 @@ func (c *Client) convertKlineData(kline *binance.Kline, pair cex.TradingPair) *c
 func (c *Client) GetKlines(ctx context.Context, pair cex.TradingPair, interval string, limit int) ([]*cex.KlineData, error) {
 	symbol := c.tradingPairToSymbol(pair)
 
-	klines, err := c.client.NewKlinesService().
-		Symbol(symbol).
-		Interval(interval).
-		Limit(limit).
-		Do(ctx)
+	var klines []*binance.Kline
+	err := c.retryer.Do(ctx, "Binance GetKlines", func(int) (err error) {
+		klines, err = c.client.NewKlinesService().
+			Symbol(symbol).
+			Interval(interval).
+			Limit(limit).
+			Do(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get klines from Binance: %w", err)
 	}
@@ -127,121 +166,306 @@ func (c *Client) GetKlines(ctx context.Context, pair cex.TradingPair, interval s
 	return result, nil
 }
 
-// GetKlinesWithTimeRange 获取指定时间范围的K线数据
+// maxKlinesPerRequest 币安单次K线请求上限
+const maxKlinesPerRequest = 1000
+
+// GetKlinesWithTimeRange 获取指定时间范围的K线数据，自动分页直到取完整个范围
+// limit 为单次请求数量，超过币安上限（1000）时按上限分页
 func (c *Client) GetKlinesWithTimeRange(ctx context.Context, pair cex.TradingPair, interval string, startTime, endTime time.Time, limit int) ([]*cex.KlineData, error) {
 	symbol := c.tradingPairToSymbol(pair)
 
-	// 批量获取数据以克服1000条限制
-	var allKlines []*cex.KlineData
-	currentStart := startTime
+	if limit <= 0 || limit > maxKlinesPerRequest {
+		limit = maxKlinesPerRequest
+	}
 
-	for currentStart.Before(endTime) {
-		klines, err := c.client.NewKlinesService().
-			Symbol(symbol).
-			Interval(interval).
-			StartTime(currentStart.UnixMilli()).
-			EndTime(endTime.UnixMilli()).
-			Limit(limit).
-			Do(ctx)
+	fetchPage := func(ctx context.Context, start, end time.Time, pageSize int) ([]*cex.KlineData, error) {
+		var klines []*binance.Kline
+		err := c.retryer.Do(ctx, "Binance GetKlines", func(int) (err error) {
+			klines, err = c.client.NewKlinesService().
+				Symbol(symbol).
+				Interval(interval).
+				StartTime(start.UnixMilli()).
+				EndTime(end.UnixMilli()).
+				Limit(pageSize).
+				Do(ctx)
+			return err
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get klines from Binance: %w", err)
 		}
 
-		if len(klines) == 0 {
-			break
+		result := make([]*cex.KlineData, len(klines))
+		for i, kline := range klines {
+			result[i] = c.convertKlineData(kline, pair)
 		}
+		return result, nil
+	}
 
-		// 转换数据
-		for _, kline := range klines {
-			allKlines = append(allKlines, c.convertKlineData(kline, pair))
-		}
+	return cex.PaginateKlines(ctx, startTime, endTime, limit, fetchPage, pair.String()+" "+interval)
+}
 
-		// 更新下一批的开始时间
-		lastKline := klines[len(klines)-1]
-		currentStart = time.Unix(lastKline.CloseTime/1000, 0).Add(time.Millisecond)
+// Buy 买入
+func (c *Client) Buy(ctx context.Context, order cex.BuyOrderRequest) (*cex.OrderResult, error) {
+	result, err := c.createOrder(ctx, "Binance Buy", order.TradingPair, func(service *binance.CreateOrderService) *binance.CreateOrderService {
+		service = service.
+			Side(binance.SideTypeBuy).
+			Type(binance.OrderType(order.Type))
+		if order.IsQuoteOrder() {
+			// 按计价资产金额买入，成交数量由交易所按步长计算
+			service = service.QuoteOrderQty(order.QuoteQuantity.String())
+		} else {
+			service = service.Quantity(order.Quantity.String())
+		}
+		if order.Type == cex.OrderTypeLimit {
+			service = service.Price(order.Price.String()).TimeInForce(binance.TimeInForceType(order.TimeInForce.ExchangeValue()))
+		}
+		return service
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place buy order on Binance: %w", err)
+	}
+	return result, nil
+}
 
-		// 如果返回的数据少于限制，说明已经获取完毕
-		if len(klines) < limit {
-			break
+// Sell 卖出
+func (c *Client) Sell(ctx context.Context, order cex.SellOrderRequest) (*cex.OrderResult, error) {
+	result, err := c.createOrder(ctx, "Binance Sell", order.TradingPair, func(service *binance.CreateOrderService) *binance.CreateOrderService {
+		service = service.
+			Side(binance.SideTypeSell).
+			Type(binance.OrderType(order.Type)).
+			Quantity(order.Quantity.String())
+		if order.Type == cex.OrderTypeLimit {
+			service = service.Price(order.Price.String()).TimeInForce(binance.TimeInForceType(order.TimeInForce.ExchangeValue()))
 		}
+		return service
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place sell order on Binance: %w", err)
+	}
+	return result, nil
+}
+
+// PlaceStopLossOrder 下止损卖单（STOP_LOSS：触发后按市价卖出）
+func (c *Client) PlaceStopLossOrder(ctx context.Context, pair cex.TradingPair, quantity, stopPrice decimal.Decimal) (*cex.OrderResult, error) {
+	result, err := c.createOrder(ctx, "Binance PlaceStopLossOrder", pair, func(service *binance.CreateOrderService) *binance.CreateOrderService {
+		return service.
+			Side(binance.SideTypeSell).
+			Type(binance.OrderTypeStopLoss).
+			Quantity(quantity.String()).
+			StopPrice(stopPrice.String())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place stop loss order on Binance: %w", err)
 	}
 
-	return allKlines, nil
+	result.Price = stopPrice
+	result.Quantity = quantity
+	return result, nil
 }
 
-// Buy 买入
-func (c *Client) Buy(ctx context.Context, order cex.BuyOrderRequest) (*cex.OrderResult, error) {
-	symbol := c.tradingPairToSymbol(order.TradingPair)
+// createOrder 下单：重试时复用同一客户端订单ID，并先查询上次请求是否已生效，避免重复下单
+func (c *Client) createOrder(ctx context.Context, operation string, pair cex.TradingPair, build func(service *binance.CreateOrderService) *binance.CreateOrderService) (*cex.OrderResult, error) {
+	symbol := c.tradingPairToSymbol(pair)
+	clientOrderID := cex.NewClientOrderID()
+
+	var result *cex.OrderResult
+	err := c.retryer.Do(ctx, operation, func(attempt int) error {
+		if attempt > 0 {
+			existing, err := c.client.NewGetOrderService().
+				Symbol(symbol).
+				OrigClientOrderID(clientOrderID).
+				Do(ctx)
+			if err == nil {
+				result = convertOrder(existing, pair)
+				return nil
+			}
+			if !isOrderNotFound(err) {
+				return err
+			}
+		}
 
-	service := c.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(binance.SideTypeBuy).
-		Type(binance.OrderType(order.Type)).
-		Quantity(order.Quantity.String())
+		response, err := build(c.client.NewCreateOrderService().Symbol(symbol).NewClientOrderID(clientOrderID)).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		price, _ := decimal.NewFromString(response.Price)
+		quantity, _ := decimal.NewFromString(response.ExecutedQuantity)
+		if quoteQuantity, err := decimal.NewFromString(response.CummulativeQuoteQuantity); err == nil && !price.IsPositive() && quantity.IsPositive() {
+			// 市价单返回的价格为 0，按成交金额计算均价
+			price = quoteQuantity.Div(quantity)
+		}
+		result = &cex.OrderResult{
+			TradingPair:   pair,
+			OrderID:       fmt.Sprintf("%d", response.OrderID),
+			ClientOrderID: response.ClientOrderID,
+			Price:         price,
+			Quantity:      quantity,
+			Side:          cex.OrderSide(response.Side),
+			Status:        string(response.Status),
+			Type:          cex.OrderType(response.Type),
+			TransactTime:  time.Unix(response.TransactTime/1000, 0),
+		}
+		return nil
+	})
+	return result, err
+}
 
-	if order.Type == cex.OrderTypeLimit {
-		service = service.Price(order.Price.String()).TimeInForce(binance.TimeInForceTypeGTC)
+// CancelOrder 撤销订单
+func (c *Client) CancelOrder(ctx context.Context, pair cex.TradingPair, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Binance order id %q: %w", orderID, err)
 	}
 
-	result, err := service.Do(ctx)
+	err = c.retryer.Do(ctx, "Binance CancelOrder", func(attempt int) error {
+		_, err := c.client.NewCancelOrderService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			OrderID(id).
+			Do(ctx)
+		if attempt > 0 && isOrderNotFound(err) {
+			// 上次请求已撤单成功
+			return nil
+		}
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to place buy order on Binance: %w", err)
+		return fmt.Errorf("failed to cancel order %s on Binance: %w", orderID, err)
 	}
+	return nil
+}
 
-	price, _ := decimal.NewFromString(result.Price)
-	quantity, _ := decimal.NewFromString(result.ExecutedQuantity)
+// PlaceOCOSellOrder 下 OCO 卖单（止盈限价 + 止损限价，一个成交后交易所自动撤销另一个）
+func (c *Client) PlaceOCOSellOrder(ctx context.Context, pair cex.TradingPair, quantity, takeProfitPrice, stopPrice, stopLimitPrice decimal.Decimal) (*cex.OCOOrderResult, error) {
+	symbol := c.tradingPairToSymbol(pair)
+	limitClientOrderID, stopClientOrderID := cex.NewClientOrderID(), cex.NewClientOrderID()
+
+	var oco *cex.OCOOrderResult
+	err := c.retryer.Do(ctx, "Binance PlaceOCOSellOrder", func(attempt int) error {
+		if attempt > 0 {
+			// 上次请求已生效时两腿都可按客户端订单ID查到
+			existing, err := c.findOCOOrder(ctx, pair, limitClientOrderID, stopClientOrderID)
+			if err != nil || existing != nil {
+				oco = existing
+				return err
+			}
+		}
 
-	return &cex.OrderResult{
-		TradingPair:   order.TradingPair,
-		OrderID:       fmt.Sprintf("%d", result.OrderID),
-		ClientOrderID: result.ClientOrderID,
-		Price:         price,
-		Quantity:      quantity,
-		Side:          cex.OrderSideBuy,
-		Status:        string(result.Status),
-		Type:          cex.OrderType(result.Type),
-		TransactTime:  time.Unix(result.TransactTime/1000, 0),
-	}, nil
+		result, err := c.client.NewCreateOCOService().
+			Symbol(symbol).
+			Side(binance.SideTypeSell).
+			Quantity(quantity.String()).
+			Price(takeProfitPrice.String()).
+			LimitClientOrderID(limitClientOrderID).
+			StopPrice(stopPrice.String()).
+			StopLimitPrice(stopLimitPrice.String()).
+			StopClientOrderID(stopClientOrderID).
+			StopLimitTimeInForce(binance.TimeInForceTypeGTC).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		oco = &cex.OCOOrderResult{OrderListID: fmt.Sprintf("%d", result.OrderListID)}
+		for _, report := range result.OrderReports {
+			price, _ := decimal.NewFromString(report.Price)
+			origQuantity, _ := decimal.NewFromString(report.OrigQuantity)
+			oco.Orders = append(oco.Orders, &cex.OrderResult{
+				TradingPair:   pair,
+				OrderID:       fmt.Sprintf("%d", report.OrderID),
+				ClientOrderID: report.ClientOrderID,
+				Price:         price,
+				Quantity:      origQuantity,
+				Side:          cex.OrderSideSell,
+				Status:        string(report.Status),
+				Type:          cex.OrderType(report.Type),
+				TransactTime:  time.Unix(report.TransactionTime/1000, 0),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place OCO order on Binance: %w", err)
+	}
+	return oco, nil
 }
 
-// Sell 卖出
-func (c *Client) Sell(ctx context.Context, order cex.SellOrderRequest) (*cex.OrderResult, error) {
-	symbol := c.tradingPairToSymbol(order.TradingPair)
+// findOCOOrder 按两腿的客户端订单ID查询 OCO 订单，不存在时返回 nil
+func (c *Client) findOCOOrder(ctx context.Context, pair cex.TradingPair, clientOrderIDs ...string) (*cex.OCOOrderResult, error) {
+	var oco *cex.OCOOrderResult
+	for _, clientOrderID := range clientOrderIDs {
+		order, err := c.client.NewGetOrderService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			OrigClientOrderID(clientOrderID).
+			Do(ctx)
+		if isOrderNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	service := c.client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(binance.SideTypeSell).
-		Type(binance.OrderType(order.Type)).
-		Quantity(order.Quantity.String())
+		result := convertOrder(order, pair)
+		if oco == nil {
+			oco = &cex.OCOOrderResult{OrderListID: result.OrderListID}
+		}
+		oco.Orders = append(oco.Orders, result)
+	}
+	return oco, nil
+}
 
-	if order.Type == cex.OrderTypeLimit {
-		service = service.Price(order.Price.String()).TimeInForce(binance.TimeInForceTypeGTC)
+// CancelOCOOrder 撤销 OCO 订单组
+func (c *Client) CancelOCOOrder(ctx context.Context, pair cex.TradingPair, orderListID string) error {
+	id, err := strconv.ParseInt(orderListID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Binance order list id %q: %w", orderListID, err)
 	}
 
-	result, err := service.Do(ctx)
+	err = c.retryer.Do(ctx, "Binance CancelOCOOrder", func(attempt int) error {
+		_, err := c.client.NewCancelOCOService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			OrderListID(id).
+			Do(ctx)
+		if attempt > 0 && isOrderNotFound(err) {
+			// 上次请求已撤单成功
+			return nil
+		}
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to place sell order on Binance: %w", err)
+		return fmt.Errorf("failed to cancel OCO order %s on Binance: %w", orderListID, err)
 	}
+	return nil
+}
 
-	price, _ := decimal.NewFromString(result.Price)
-	quantity, _ := decimal.NewFromString(result.ExecutedQuantity)
+// GetAPIKeyPermissions 通过账户接口查询 API 密钥能否下现货订单（canTrade 且账户权限包含 SPOT）
+func (c *Client) GetAPIKeyPermissions(ctx context.Context) (*cex.APIKeyPermissions, error) {
+	var account *binance.Account
+	err := c.retryer.Do(ctx, "Binance GetAccount", func(int) (err error) {
+		account, err = c.client.NewGetAccountService().Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account from Binance: %w", err)
+	}
 
-	return &cex.OrderResult{
-		TradingPair:   order.TradingPair,
-		OrderID:       fmt.Sprintf("%d", result.OrderID),
-		ClientOrderID: result.ClientOrderID,
-		Price:         price,
-		Quantity:      quantity,
-		Side:          cex.OrderSideSell,
-		Status:        string(result.Status),
-		Type:          cex.OrderType(result.Type),
-		TransactTime:  time.Unix(result.TransactTime/1000, 0),
-	}, nil
+	spot := false
+	for _, permission := range account.Permissions {
+		if permission == "SPOT" {
+			spot = true
+			break
+		}
+	}
+	return &cex.APIKeyPermissions{CanTrade: account.CanTrade && spot, Permissions: account.Permissions}, nil
 }
 
 // GetAccount 获取账户信息
 func (c *Client) GetAccount(ctx context.Context) ([]*cex.AccountBalance, error) {
-	account, err := c.client.NewGetAccountService().Do(ctx)
+	var account *binance.Account
+	err := c.retryer.Do(ctx, "Binance GetAccount", func(int) (err error) {
+		account, err = c.client.NewGetAccountService().Do(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account from Binance: %w", err)
 	}
@@ -261,9 +485,246 @@ func (c *Client) GetAccount(ctx context.Context) ([]*cex.AccountBalance, error)
 	return balances, nil
 }
 
+// GetOpenOrders 获取交易对当前所有未完成挂单
+func (c *Client) GetOpenOrders(ctx context.Context, pair cex.TradingPair) ([]*cex.OrderResult, error) {
+	var orders []*binance.Order
+	err := c.retryer.Do(ctx, "Binance GetOpenOrders", func(int) (err error) {
+		orders, err = c.client.NewListOpenOrdersService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders from Binance: %w", err)
+	}
+
+	results := make([]*cex.OrderResult, len(orders))
+	for i, order := range orders {
+		results[i] = convertOrder(order, pair)
+	}
+	return results, nil
+}
+
+// GetRecentTrades 获取交易对最近 limit 笔成交（币安最多 1000 笔）
+func (c *Client) GetRecentTrades(ctx context.Context, pair cex.TradingPair, limit int) ([]*cex.AccountTrade, error) {
+	var trades []*binance.TradeV3
+	err := c.retryer.Do(ctx, "Binance ListTrades", func(int) (err error) {
+		trades, err = c.client.NewListTradesService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			Limit(limit).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades from Binance: %w", err)
+	}
+
+	results := make([]*cex.AccountTrade, len(trades))
+	for i, trade := range trades {
+		side := cex.OrderSideSell
+		if trade.IsBuyer {
+			side = cex.OrderSideBuy
+		}
+		price, _ := decimal.NewFromString(trade.Price)
+		quantity, _ := decimal.NewFromString(trade.Quantity)
+		quoteQuantity, _ := decimal.NewFromString(trade.QuoteQuantity)
+		commission, _ := decimal.NewFromString(trade.Commission)
+		results[i] = &cex.AccountTrade{
+			TradingPair:     pair,
+			TradeID:         strconv.FormatInt(trade.ID, 10),
+			OrderID:         strconv.FormatInt(trade.OrderID, 10),
+			Side:            side,
+			Price:           price,
+			Quantity:        quantity,
+			QuoteQuantity:   quoteQuantity,
+			Commission:      commission,
+			CommissionAsset: trade.CommissionAsset,
+			IsMaker:         trade.IsMaker,
+			Time:            time.UnixMilli(trade.Time),
+		}
+	}
+	return results, nil
+}
+
+// GetOrder 查询订单
+func (c *Client) GetOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*cex.OrderResult, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Binance order id %q: %w", orderID, err)
+	}
+
+	var order *binance.Order
+	err = c.retryer.Do(ctx, "Binance GetOrder", func(int) (err error) {
+		order, err = c.client.NewGetOrderService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			OrderID(id).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %s from Binance: %w", orderID, err)
+	}
+	return convertOrder(order, pair), nil
+}
+
+// convertOrder 转换币安订单：Quantity 为已成交数量，Price 为成交均价（未成交时为挂单价或触发价）
+func convertOrder(order *binance.Order, pair cex.TradingPair) *cex.OrderResult {
+	origQuantity, _ := decimal.NewFromString(order.OrigQuantity)
+	executed, _ := decimal.NewFromString(order.ExecutedQuantity)
+	quoteQuantity, _ := decimal.NewFromString(order.CummulativeQuoteQuantity)
+
+	price, _ := decimal.NewFromString(order.Price)
+	if executed.IsPositive() && quoteQuantity.IsPositive() {
+		price = quoteQuantity.Div(executed)
+	} else if !price.IsPositive() {
+		price, _ = decimal.NewFromString(order.StopPrice)
+	}
+
+	result := &cex.OrderResult{
+		TradingPair:   pair,
+		OrderID:       fmt.Sprintf("%d", order.OrderID),
+		ClientOrderID: order.ClientOrderID,
+		Price:         price,
+		Quantity:      executed,
+		Side:          cex.OrderSide(order.Side),
+		Status:        string(order.Status),
+		Type:          cex.OrderType(order.Type),
+		TransactTime:  time.UnixMilli(order.UpdateTime),
+		OrigQuantity:  origQuantity,
+	}
+	// 不属于 OCO 的订单 orderListId 为 -1
+	if order.OrderListId >= 0 {
+		result.OrderListID = fmt.Sprintf("%d", order.OrderListId)
+	}
+	return result
+}
+
+// GetOrderBook 获取前 limit 档盘口
+func (c *Client) GetOrderBook(ctx context.Context, pair cex.TradingPair, limit int) (*cex.OrderBook, error) {
+	var depth *binance.DepthResponse
+	err := c.retryer.Do(ctx, "Binance GetOrderBook", func(int) (err error) {
+		depth, err = c.client.NewDepthService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			Limit(limit).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book from Binance: %w", err)
+	}
+
+	book := &cex.OrderBook{
+		TradingPair: pair,
+		Bids:        make([]cex.OrderBookLevel, len(depth.Bids)),
+		Asks:        make([]cex.OrderBookLevel, len(depth.Asks)),
+		Time:        time.Now(),
+	}
+	for i, bid := range depth.Bids {
+		price, _ := decimal.NewFromString(bid.Price)
+		quantity, _ := decimal.NewFromString(bid.Quantity)
+		book.Bids[i] = cex.OrderBookLevel{Price: price, Quantity: quantity}
+	}
+	for i, ask := range depth.Asks {
+		price, _ := decimal.NewFromString(ask.Price)
+		quantity, _ := decimal.NewFromString(ask.Quantity)
+		book.Asks[i] = cex.OrderBookLevel{Price: price, Quantity: quantity}
+	}
+
+	return book, nil
+}
+
+// GetServerTime 获取交易所服务器时间
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	serverTime, err := c.client.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get server time from Binance: %w", err)
+	}
+	return time.UnixMilli(serverTime), nil
+}
+
+// GetSymbolFilters 从 exchangeInfo 获取交易对下单规则（LOT_SIZE、PRICE_FILTER、NOTIONAL/MIN_NOTIONAL）
+func (c *Client) GetSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	symbol := c.tradingPairToSymbol(pair)
+
+	info, err := c.getExchangeInfo(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	for i := range info.Symbols {
+		if info.Symbols[i].Symbol == symbol {
+			return convertSymbolFilters(&info.Symbols[i]), nil
+		}
+	}
+	return nil, fmt.Errorf("symbol %s not found in Binance exchange info", symbol)
+}
+
+// GetExchangeSymbols 从 exchangeInfo 获取全部现货交易对及其下单规则
+func (c *Client) GetExchangeSymbols(ctx context.Context) ([]*cex.ExchangeSymbol, error) {
+	info, err := c.getExchangeInfo(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]*cex.ExchangeSymbol, 0, len(info.Symbols))
+	for i := range info.Symbols {
+		s := &info.Symbols[i]
+		if !s.IsSpotTradingAllowed {
+			continue
+		}
+		symbols = append(symbols, &cex.ExchangeSymbol{
+			BaseAsset:  s.BaseAsset,
+			QuoteAsset: s.QuoteAsset,
+			Status:     s.Status,
+			Filters:    *convertSymbolFilters(s),
+		})
+	}
+	return symbols, nil
+}
+
+// getExchangeInfo 查询 exchangeInfo，symbol 为空时返回全部交易对
+func (c *Client) getExchangeInfo(ctx context.Context, symbol string) (*binance.ExchangeInfo, error) {
+	var info *binance.ExchangeInfo
+	err := c.retryer.Do(ctx, "Binance GetExchangeInfo", func(int) (err error) {
+		service := c.client.NewExchangeInfoService()
+		if symbol != "" {
+			service = service.Symbol(symbol)
+		}
+		info, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info from Binance: %w", err)
+	}
+	return info, nil
+}
+
+// convertSymbolFilters 转换 exchangeInfo 中交易对的下单规则
+func convertSymbolFilters(s *binance.Symbol) *cex.SymbolFilters {
+	filters := &cex.SymbolFilters{Symbol: s.Symbol}
+	if lot := s.LotSizeFilter(); lot != nil {
+		filters.MinQty, _ = decimal.NewFromString(lot.MinQuantity)
+		filters.MaxQty, _ = decimal.NewFromString(lot.MaxQuantity)
+		filters.StepSize, _ = decimal.NewFromString(lot.StepSize)
+	}
+	if price := s.PriceFilter(); price != nil {
+		filters.MinPrice, _ = decimal.NewFromString(price.MinPrice)
+		filters.MaxPrice, _ = decimal.NewFromString(price.MaxPrice)
+		filters.TickSize, _ = decimal.NewFromString(price.TickSize)
+	}
+	// 新交易对使用 NOTIONAL，旧交易对仍为 MIN_NOTIONAL
+	if notional := s.NotionalFilter(); notional != nil {
+		filters.MinNotional, _ = decimal.NewFromString(notional.MinNotional)
+	} else if notional := s.MinNotionalFilter(); notional != nil {
+		filters.MinNotional, _ = decimal.NewFromString(notional.MinNotional)
+	}
+	return filters
+}
+
 // Ping 测试连接
 func (c *Client) Ping(ctx context.Context) error {
-	err := c.client.NewPingService().Do(ctx)
+	err := c.retryer.Do(ctx, "Binance Ping", func(int) error {
+		return c.client.NewPingService().Do(ctx)
+	})
 	if err != nil {
 		return fmt.Errorf("Binance ping failed: %w", err)
 	}
@@ -1,19 +1,30 @@
 package binance
 
 import (
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/envconfig"
+
 	"github.com/xpwu/go-config/configs"
 )
 
 // Config 币安配置
 type Config struct {
-	APIKey        string  `json:"api_key"`        // API密钥
-	SecretKey     string  `json:"secret_key"`     // API私钥
-	BaseURL       string  `json:"base_url"`       // API地址
-	Timeout       int     `json:"timeout"`        // 请求超时时间(秒)
-	EnableTrading bool    `json:"enable_trading"` // 启用交易权限
-	ReadOnly      bool    `json:"read_only"`      // 只读模式
-	Fee           float64 `json:"fee"`            // 交易手续费率
-	DBName        string  `json:"db_name"`        // 数据库名称
+	APIKey        string          `json:"api_key"`        // API密钥
+	SecretKey     string          `json:"secret_key"`     // API私钥
+	BaseURL       string          `json:"base_url"`       // API地址
+	Testnet       bool            `json:"testnet"`        // 使用现货测试网（https://testnet.binance.vision），REST 和 WebSocket 均切换
+	Timeout       int             `json:"timeout"`        // 请求超时时间(秒)
+	EnableTrading bool            `json:"enable_trading"` // 启用交易权限
+	ReadOnly      bool            `json:"read_only"`      // 只读模式
+	Fees          cex.FeeSchedule `json:"fees"`           // 手续费表（maker/taker、BNB抵扣、成交额等级）
+	Retry         cex.RetryConfig `json:"retry"`          // REST 请求失败重试（指数退避+抖动）
+	DBName        string          `json:"db_name"`        // 数据库名称
+
+	// 测试网 API 密钥（在 testnet.binance.vision 申请）：启用测试网时只使用这组密钥，不会把实盘密钥发到测试网
+	TestnetAPIKey    string `json:"testnet_api_key"`
+	TestnetSecretKey string `json:"testnet_secret_key"`
 }
 
 // ConfigValue 币安配置实例
@@ -21,13 +32,53 @@ var ConfigValue = Config{
 	APIKey:        "",
 	SecretKey:     "",
 	BaseURL:       "https://api.binance.com",
+	Testnet:       false,
 	Timeout:       10,
 	EnableTrading: false,
 	ReadOnly:      true,
-	Fee:           0.001, // 币安现货交易手续费0.1%
-	DBName:        "tradingbot_binance",
+	Fees: cex.FeeSchedule{ // 币安现货普通用户 maker/taker 均为 0.1%，BNB 抵扣 25%
+		MakerRate:      0.001,
+		TakerRate:      0.001,
+		UseBNBDiscount: false,
+		BNBDiscount:    0.25,
+		Volume30d:      0,
+		Tiers:          []cex.FeeTier{},
+	},
+	Retry:  cex.DefaultRetryConfig(),
+	DBName: "tradingbot_binance",
 }
 
 func init() {
 	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("BINANCE", &ConfigValue)
+}
+
+// TestnetBaseURL 币安现货测试网 REST 地址
+const TestnetBaseURL = "https://testnet.binance.vision"
+
+// Validate 检查测试网和实盘密钥没有混用
+func (c Config) Validate() error {
+	if (c.TestnetAPIKey == "") != (c.TestnetSecretKey == "") {
+		return fmt.Errorf("TestnetAPIKey and TestnetSecretKey must be set together")
+	}
+	if c.TestnetAPIKey != "" && (c.TestnetAPIKey == c.APIKey || c.TestnetSecretKey == c.SecretKey) {
+		return fmt.Errorf("testnet keys must differ from the live APIKey/SecretKey")
+	}
+	return nil
+}
+
+// Credentials 当前使用的 API 密钥：启用测试网时为测试网密钥（未配置时为空，只能访问公开行情）
+func (c Config) Credentials() (apiKey, secretKey string) {
+	if c.Testnet {
+		return c.TestnetAPIKey, c.TestnetSecretKey
+	}
+	return c.APIKey, c.SecretKey
+}
+
+// APIBaseURL 当前使用的 REST 地址
+func (c Config) APIBaseURL() string {
+	if c.Testnet {
+		return TestnetBaseURL
+	}
+	return c.BaseURL
 }
@@ -0,0 +1,38 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Testnet(t *testing.T) {
+	config := Config{APIKey: "live-key", SecretKey: "live-secret", BaseURL: "https://api.binance.com"}
+	apiKey, secretKey := config.Credentials()
+	assert.Equal(t, "live-key", apiKey)
+	assert.Equal(t, "live-secret", secretKey)
+	assert.Equal(t, "https://api.binance.com", config.APIBaseURL())
+
+	// 启用测试网后只使用测试网密钥，未配置时为空（不会退回实盘密钥）
+	config.Testnet = true
+	apiKey, secretKey = config.Credentials()
+	assert.Empty(t, apiKey)
+	assert.Empty(t, secretKey)
+	assert.Equal(t, TestnetBaseURL, config.APIBaseURL())
+
+	config.TestnetAPIKey, config.TestnetSecretKey = "test-key", "test-secret"
+	apiKey, secretKey = config.Credentials()
+	assert.Equal(t, "test-key", apiKey)
+	assert.Equal(t, "test-secret", secretKey)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{APIKey: "live-key", SecretKey: "live-secret"}.Validate())
+	assert.NoError(t, Config{APIKey: "live-key", SecretKey: "live-secret", Testnet: true}.Validate())
+	assert.NoError(t, Config{APIKey: "live-key", SecretKey: "live-secret", TestnetAPIKey: "test-key", TestnetSecretKey: "test-secret"}.Validate())
+
+	// 测试网密钥不完整或与实盘密钥相同
+	assert.Error(t, Config{TestnetAPIKey: "test-key"}.Validate())
+	assert.Error(t, Config{APIKey: "live-key", SecretKey: "live-secret", TestnetAPIKey: "live-key", TestnetSecretKey: "test-secret"}.Validate())
+	assert.Error(t, Config{APIKey: "live-key", SecretKey: "live-secret", TestnetAPIKey: "test-key", TestnetSecretKey: "live-secret"}.Validate())
+}
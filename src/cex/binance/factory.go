@@ -7,10 +7,14 @@ import (
 // BinanceFactory Binance工厂实现
 type BinanceFactory struct{}
 
-// CreateClient 创建Binance客户端
+// CreateClient 创建Binance客户端（启用测试网时使用测试网密钥）
 func (f *BinanceFactory) CreateClient() cex.CEXClient {
-	config := &ConfigValue
-	return NewClient(config.APIKey, config.SecretKey)
+	return NewClient(ConfigValue.Credentials())
+}
+
+// Validate 创建客户端前检查配置
+func (f *BinanceFactory) Validate() error {
+	return ConfigValue.Validate()
 }
 
 // 注册Binance工厂
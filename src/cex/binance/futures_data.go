@@ -0,0 +1,99 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// 币安合约行情单次请求上限
+const (
+	maxFundingRatesPerRequest = 1000
+	maxOpenInterestPerRequest = 500
+)
+
+// GetFundingRates 获取U本位永续合约的资金费率（/fapi/v1/fundingRate，公开行情，测试网客户端也读取正式网数据）
+func (c *Client) GetFundingRates(ctx context.Context, pair cex.TradingPair, startTime, endTime time.Time, limit int) ([]*cex.FundingRate, error) {
+	if limit <= 0 || limit > maxFundingRatesPerRequest {
+		limit = maxFundingRatesPerRequest
+	}
+
+	var rates []*cex.FundingRate
+	err := c.retryer.Do(ctx, "Binance GetFundingRates", func(int) error {
+		response, err := c.futures.NewFundingRateService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			StartTime(startTime.UnixMilli()).
+			EndTime(endTime.UnixMilli()).
+			Limit(limit).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		rates = make([]*cex.FundingRate, 0, len(response))
+		for _, r := range response {
+			rate, err := decimal.NewFromString(r.FundingRate)
+			if err != nil {
+				return fmt.Errorf("invalid funding rate %q: %w", r.FundingRate, err)
+			}
+			rates = append(rates, &cex.FundingRate{
+				TradingPair: pair,
+				Time:        time.UnixMilli(r.FundingTime).UTC(),
+				Rate:        rate,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding rates from Binance: %w", err)
+	}
+	return rates, nil
+}
+
+// GetOpenInterestHistory 获取U本位永续合约的持仓量统计（/futures/data/openInterestHist，只保留最近 30 天）
+func (c *Client) GetOpenInterestHistory(ctx context.Context, pair cex.TradingPair, period string, startTime, endTime time.Time, limit int) ([]*cex.OpenInterest, error) {
+	if limit <= 0 || limit > maxOpenInterestPerRequest {
+		limit = maxOpenInterestPerRequest
+	}
+
+	var history []*cex.OpenInterest
+	err := c.retryer.Do(ctx, "Binance GetOpenInterestHistory", func(int) error {
+		response, err := c.futures.NewOpenInterestStatisticsService().
+			Symbol(c.tradingPairToSymbol(pair)).
+			Period(period).
+			StartTime(startTime.UnixMilli()).
+			EndTime(endTime.UnixMilli()).
+			Limit(limit).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		history = make([]*cex.OpenInterest, 0, len(response))
+		for _, s := range response {
+			openInterest, err := decimal.NewFromString(s.SumOpenInterest)
+			if err != nil {
+				return fmt.Errorf("invalid open interest %q: %w", s.SumOpenInterest, err)
+			}
+			value, err := decimal.NewFromString(s.SumOpenInterestValue)
+			if err != nil {
+				return fmt.Errorf("invalid open interest value %q: %w", s.SumOpenInterestValue, err)
+			}
+			history = append(history, &cex.OpenInterest{
+				TradingPair:  pair,
+				Time:         time.UnixMilli(s.Timestamp).UTC(),
+				OpenInterest: openInterest,
+				Value:        value,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open interest history from Binance: %w", err)
+	}
+	return history, nil
+}
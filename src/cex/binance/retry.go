@@ -0,0 +1,44 @@
+package binance
+
+import (
+	"errors"
+
+	"tradingbot/src/cex"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// retryableCodes 可重试的币安错误码
+var retryableCodes = map[int64]bool{
+	0:     true, // 非 JSON 响应（网关错误、5xx）
+	-1000: true, // UNKNOWN：处理请求时发生未知错误
+	-1001: true, // DISCONNECTED：内部错误，无法处理请求
+	-1003: true, // TOO_MANY_REQUESTS：触发限频
+	-1006: true, // UNEXPECTED_RESP：消息总线返回异常，执行状态未知
+	-1007: true, // TIMEOUT：等待后端响应超时，执行状态未知
+	-1008: true, // SERVER_BUSY：服务器繁忙
+	-1021: true, // INVALID_TIMESTAMP：时间戳超出 recvWindow
+}
+
+// isRetryableError 判断币安错误是否可重试（余额不足、参数错误等业务错误不重试）
+func isRetryableError(err error) bool {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		return retryableCodes[apiErr.Code]
+	}
+	return false
+}
+
+// isOrderNotFound 订单不存在（-2013 查询不到订单，-2011 撤单时订单不存在）
+func isOrderNotFound(err error) bool {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == -2013 || apiErr.Code == -2011
+	}
+	return false
+}
+
+// SetRateLimiter 实现 cex.RateLimitedClient，所有 REST 请求（包括重试）按共享限频器取得令牌
+func (c *Client) SetRateLimiter(limiter *cex.RateLimiter) {
+	c.retryer.SetRateLimiter(limiter)
+}
@@ -0,0 +1,119 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// userStreamKeepalive listenKey 续期间隔（60分钟未续期即失效）
+const userStreamKeepalive = 30 * time.Minute
+
+// SubscribeUserData 订阅账户数据流（executionReport、outboundAccountPosition），
+// 阻塞直到 ctx 取消（返回 nil）或连接断开（返回错误）
+func (c *Client) SubscribeUserData(ctx context.Context, handler cex.UserDataHandler) error {
+	var listenKey string
+	err := c.retryer.Do(ctx, "Binance StartUserStream", func(int) (err error) {
+		listenKey, err = c.client.NewStartUserStreamService().Do(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start Binance user data stream: %w", err)
+	}
+	defer func() {
+		// ctx 可能已取消，用独立的超时关闭 listenKey
+		closeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = c.client.NewCloseUserStreamService().ListenKey(listenKey).Do(closeCtx)
+	}()
+
+	errC := make(chan error, 1)
+	doneC, stopC, err := binance.WsUserDataServe(listenKey, func(event *binance.WsUserDataEvent) {
+		switch event.Event {
+		case binance.UserDataEventTypeExecutionReport:
+			handler.OnOrderUpdate(convertOrderUpdate(&event.OrderUpdate))
+		case binance.UserDataEventTypeOutboundAccountPosition:
+			handler.OnAccountUpdate(convertAccountUpdate(event))
+		}
+	}, func(err error) {
+		select {
+		case errC <- err:
+		default:
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect Binance user data stream: %w", err)
+	}
+
+	keepalive := time.NewTicker(userStreamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(stopC)
+			<-doneC
+			return nil
+		case <-doneC:
+			select {
+			case err := <-errC:
+				return fmt.Errorf("binance user data stream closed: %w", err)
+			default:
+				return fmt.Errorf("binance user data stream closed")
+			}
+		case <-keepalive.C:
+			if err := c.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx); err != nil {
+				close(stopC)
+				<-doneC
+				return fmt.Errorf("failed to keep Binance user data stream alive: %w", err)
+			}
+		}
+	}
+}
+
+// convertOrderUpdate 转换 executionReport 事件
+func convertOrderUpdate(update *binance.WsOrderUpdate) *cex.OrderUpdate {
+	lastQuantity, _ := decimal.NewFromString(update.LatestVolume)
+	lastPrice, _ := decimal.NewFromString(update.LatestPrice)
+	filledQuantity, _ := decimal.NewFromString(update.FilledVolume)
+	filledQuoteQuantity, _ := decimal.NewFromString(update.FilledQuoteVolume)
+	commission, _ := decimal.NewFromString(update.FeeCost)
+
+	result := &cex.OrderUpdate{
+		Symbol:              update.Symbol,
+		OrderID:             fmt.Sprintf("%d", update.Id),
+		ClientOrderID:       update.ClientOrderId,
+		Side:                cex.OrderSide(update.Side),
+		Type:                cex.OrderType(update.Type),
+		Status:              update.Status,
+		ExecutionType:       update.ExecutionType,
+		LastQuantity:        lastQuantity,
+		LastPrice:           lastPrice,
+		FilledQuantity:      filledQuantity,
+		FilledQuoteQuantity: filledQuoteQuantity,
+		Commission:          commission,
+		CommissionAsset:     update.FeeAsset,
+		Time:                time.UnixMilli(update.TransactionTime),
+	}
+	// 不属于 OCO 的订单 orderListId 为 -1
+	if update.OrderListId >= 0 {
+		result.OrderListID = fmt.Sprintf("%d", update.OrderListId)
+	}
+	return result
+}
+
+// convertAccountUpdate 转换 outboundAccountPosition 事件
+func convertAccountUpdate(event *binance.WsUserDataEvent) *cex.AccountUpdate {
+	balances := make([]*cex.AccountBalance, len(event.AccountUpdate.WsAccountUpdates))
+	for i, update := range event.AccountUpdate.WsAccountUpdates {
+		free, _ := decimal.NewFromString(update.Free)
+		locked, _ := decimal.NewFromString(update.Locked)
+		balances[i] = &cex.AccountBalance{Asset: update.Asset, Free: free, Locked: locked}
+	}
+	return &cex.AccountUpdate{Balances: balances, Time: time.UnixMilli(event.Time)}
+}
@@ -0,0 +1,949 @@
+package bybit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxKlinesPerRequest Bybit单次K线请求上限
+const maxKlinesPerRequest = 1000
+
+// categorySpot 现货产品类型
+const categorySpot = "spot"
+
+// Client Bybit客户端实现（V5 REST API）
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	secretKey  string
+	recvWindow int
+	database   *database.PostgresDB // 内部管理的数据库连接
+
+	mu           sync.Mutex
+	stopOrderIDs map[string]bool // 条件单ID，撤单时需要指定 orderFilter
+	retryer      *cex.Retryer    // REST 请求重试
+}
+
+// NewClient 创建Bybit客户端
+func NewClient(apiKey, secretKey string) *Client {
+	config := &ConfigValue
+	client := newClient(config.BaseURL, apiKey, secretKey, &http.Client{Timeout: time.Duration(config.Timeout) * time.Second})
+
+	retryConfig := config.Retry
+	if err := retryConfig.Validate(); err != nil {
+		fmt.Printf("⚠️ Invalid bybit retry config (%v), using defaults\n", err)
+		retryConfig = cex.DefaultRetryConfig()
+	}
+	client.retryer = cex.NewRetryer(retryConfig, isRetryableError)
+
+	// 初始化数据库连接
+	dbConfig := database.GetDatabaseConfigForCEX(config.DBName)
+	if dbConfig.Host != "" {
+		fmt.Printf("🗄️ Connecting to bybit database...")
+		db, err := database.OpenPostgresDB(dbConfig)
+		if err != nil {
+			fmt.Printf(" failed: %v\n", err)
+			fmt.Println("⚠️ Database unavailable, using network only")
+		} else {
+			fmt.Println(" connected!")
+			client.database = db
+		}
+	}
+
+	return client
+}
+
+// newClient 创建不连接数据库的客户端
+func newClient(baseURL, apiKey, secretKey string, httpClient *http.Client) *Client {
+	recvWindow := ConfigValue.RecvWindow
+	if recvWindow <= 0 {
+		recvWindow = 5000
+	}
+	return &Client{
+		httpClient:   httpClient,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		apiKey:       apiKey,
+		secretKey:    secretKey,
+		recvWindow:   recvWindow,
+		stopOrderIDs: make(map[string]bool),
+		retryer:      cex.NewRetryer(cex.RetryConfig{}, isRetryableError),
+	}
+}
+
+// APIError Bybit接口返回的业务错误
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bybit api error %d: %s", e.Code, e.Message)
+}
+
+// HTTPError Bybit接口返回的非 200 响应
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("bybit http %d: %s", e.StatusCode, e.Body)
+}
+
+// apiResponse Bybit V5 统一响应结构
+type apiResponse struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// GetName 获取交易所名称
+func (c *Client) GetName() string {
+	return "bybit"
+}
+
+// GetDatabase 获取数据库连接
+func (c *Client) GetDatabase() interface{} {
+	return c.database
+}
+
+// GetTradingFee 获取交易手续费率（当前等级的 taker 费率）
+func (c *Client) GetTradingFee() float64 {
+	return c.GetFeeSchedule().Rate(false, 0)
+}
+
+// GetFeeSchedule 获取手续费表
+func (c *Client) GetFeeSchedule() cex.FeeSchedule {
+	config := &ConfigValue
+	return config.Fees
+}
+
+// TradingPermission 配置的交易开关（EnableTrading、ReadOnly）
+func (c *Client) TradingPermission() cex.TradingPermission {
+	config := &ConfigValue
+	return cex.TradingPermission{EnableTrading: config.EnableTrading, ReadOnly: config.ReadOnly}
+}
+
+// tradingPairToSymbol 将标准化交易对转换为Bybit格式
+func (c *Client) tradingPairToSymbol(pair cex.TradingPair) string {
+	// Bybit格式与币安相同: BTCUSDT (无分隔符)
+	return strings.ToUpper(pair.Base) + strings.ToUpper(pair.Quote)
+}
+
+// convertInterval 将时间周期（1m/1h/1d...）转换为Bybit的 interval 参数
+func convertInterval(interval string) (string, timeframes.Timeframe, error) {
+	tf, err := timeframes.ParseTimeframe(interval)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch tf {
+	case timeframes.Timeframe1d:
+		return "D", tf, nil
+	case timeframes.Timeframe1w:
+		return "W", tf, nil
+	case timeframes.Timeframe1M:
+		return "M", tf, nil
+	case timeframes.Timeframe1s, timeframes.Timeframe8h, timeframes.Timeframe3d:
+		return "", "", fmt.Errorf("timeframe %s is not supported by Bybit", tf)
+	default:
+		minutes, err := tf.GetMinutes()
+		if err != nil {
+			return "", "", err
+		}
+		return strconv.FormatInt(minutes, 10), tf, nil
+	}
+}
+
+// do 发送请求并解析统一响应，signed 为 true 时添加签名头。
+// GET 请求失败时按重试配置自动重试；POST（下单、撤单）非幂等，由调用方处理重试
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, signed bool, result interface{}) error {
+	if method != http.MethodGet {
+		return c.send(ctx, method, path, query, body, signed, result)
+	}
+	return c.retryer.Do(ctx, "Bybit "+path, func(int) error {
+		return c.send(ctx, method, path, query, body, signed, result)
+	})
+}
+
+// send 发送一次请求
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}, signed bool, result interface{}) error {
+	endpoint := c.baseURL + path
+	queryString := query.Encode()
+	if queryString != "" {
+		endpoint += "?" + queryString
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if signed {
+		// GET 对查询字符串签名，POST 对请求体签名
+		signPayload := queryString
+		if method == http.MethodPost {
+			signPayload = string(payload)
+		}
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		recvWindow := strconv.Itoa(c.recvWindow)
+		req.Header.Set("X-BAPI-API-KEY", c.apiKey)
+		req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+		req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+		req.Header.Set("X-BAPI-SIGN", c.sign(timestamp+c.apiKey+recvWindow+signPayload))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &HTTPError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	var envelope apiResponse
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if envelope.RetCode != 0 {
+		return &APIError{Code: envelope.RetCode, Message: envelope.RetMsg}
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
+
+// sign HMAC-SHA256 签名
+func (c *Client) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(c.secretKey))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// klineResult K线接口返回：list 按开盘时间倒序，每项为
+// [startTime, open, high, low, close, volume, turnover]
+type klineResult struct {
+	List [][]string `json:"list"`
+}
+
+// convertKlines 转换Bybit K线数据为标准格式（升序）
+func convertKlines(list [][]string, pair cex.TradingPair, tf timeframes.Timeframe) ([]*cex.KlineData, error) {
+	klines := make([]*cex.KlineData, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		item := list[i]
+		if len(item) < 7 {
+			return nil, fmt.Errorf("unexpected bybit kline format: %v", item)
+		}
+
+		startMs, err := strconv.ParseInt(item[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bybit kline start time %q: %w", item[0], err)
+		}
+		openTime := time.UnixMilli(startMs)
+		nextOpen, err := timeframes.NextOpenTime(openTime, tf)
+		if err != nil {
+			return nil, err
+		}
+
+		open, _ := decimal.NewFromString(item[1])
+		high, _ := decimal.NewFromString(item[2])
+		low, _ := decimal.NewFromString(item[3])
+		close, _ := decimal.NewFromString(item[4])
+		volume, _ := decimal.NewFromString(item[5])
+		turnover, _ := decimal.NewFromString(item[6])
+
+		// Bybit 不提供主动买入成交量
+		klines = append(klines, &cex.KlineData{
+			TradingPair: pair,
+			OpenTime:    openTime,
+			Open:        open,
+			High:        high,
+			Low:         low,
+			Close:       close,
+			Volume:      volume,
+			CloseTime:   time.UnixMilli(nextOpen.UnixMilli() - 1),
+			QuoteVolume: turnover,
+		})
+	}
+	return klines, nil
+}
+
+// GetKlines 获取K线数据
+func (c *Client) GetKlines(ctx context.Context, pair cex.TradingPair, interval string, limit int) ([]*cex.KlineData, error) {
+	bybitInterval, tf, err := convertInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("category", categorySpot)
+	query.Set("symbol", c.tradingPairToSymbol(pair))
+	query.Set("interval", bybitInterval)
+	query.Set("limit", strconv.Itoa(limit))
+
+	var result klineResult
+	if err := c.do(ctx, http.MethodGet, "/v5/market/kline", query, nil, false, &result); err != nil {
+		return nil, fmt.Errorf("failed to get klines from Bybit: %w", err)
+	}
+
+	return convertKlines(result.List, pair, tf)
+}
+
+// GetKlinesWithTimeRange 获取指定时间范围的K线数据，自动分页直到取完整个范围
+func (c *Client) GetKlinesWithTimeRange(ctx context.Context, pair cex.TradingPair, interval string, startTime, endTime time.Time, limit int) ([]*cex.KlineData, error) {
+	bybitInterval, tf, err := convertInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	duration, _ := tf.GetDuration()
+	symbol := c.tradingPairToSymbol(pair)
+
+	if limit <= 0 || limit > maxKlinesPerRequest {
+		limit = maxKlinesPerRequest
+	}
+
+	fetchPage := func(ctx context.Context, start, end time.Time, pageSize int) ([]*cex.KlineData, error) {
+		// Bybit 在区间内返回最新的 limit 根，因此每页的区间不超过 pageSize 根K线
+		if windowEnd := start.Add(time.Duration(pageSize)*duration - time.Millisecond); windowEnd.Before(end) {
+			end = windowEnd
+		}
+
+		query := url.Values{}
+		query.Set("category", categorySpot)
+		query.Set("symbol", symbol)
+		query.Set("interval", bybitInterval)
+		query.Set("start", strconv.FormatInt(start.UnixMilli(), 10))
+		query.Set("end", strconv.FormatInt(end.UnixMilli(), 10))
+		query.Set("limit", strconv.Itoa(pageSize))
+
+		var result klineResult
+		if err := c.do(ctx, http.MethodGet, "/v5/market/kline", query, nil, false, &result); err != nil {
+			return nil, fmt.Errorf("failed to get klines from Bybit: %w", err)
+		}
+		return convertKlines(result.List, pair, tf)
+	}
+
+	return cex.PaginateKlines(ctx, startTime, endTime, limit, fetchPage, pair.String()+" "+interval)
+}
+
+// orderRequest 下单请求
+type orderRequest struct {
+	Category     string `json:"category"`
+	Symbol       string `json:"symbol"`
+	Side         string `json:"side"`
+	OrderType    string `json:"orderType"`
+	Qty          string `json:"qty"`
+	Price        string `json:"price,omitempty"`
+	TimeInForce  string `json:"timeInForce,omitempty"`
+	MarketUnit   string `json:"marketUnit,omitempty"`
+	TriggerPrice string `json:"triggerPrice,omitempty"`
+	OrderFilter  string `json:"orderFilter,omitempty"`
+	OrderLinkID  string `json:"orderLinkId,omitempty"` // 客户端订单ID，重试时据此去重
+}
+
+// orderCreateResult 下单返回
+type orderCreateResult struct {
+	OrderID     string `json:"orderId"`
+	OrderLinkID string `json:"orderLinkId"`
+}
+
+// orderInfo 订单查询返回的单个订单
+type orderInfo struct {
+	OrderID      string `json:"orderId"`
+	OrderLinkID  string `json:"orderLinkId"`
+	Price        string `json:"price"`
+	AvgPrice     string `json:"avgPrice"`
+	Qty          string `json:"qty"`
+	CumExecQty   string `json:"cumExecQty"`
+	Side         string `json:"side"`
+	TriggerPrice string `json:"triggerPrice"`
+	OrderStatus  string `json:"orderStatus"`
+	OrderType    string `json:"orderType"`
+	UpdatedTime  string `json:"updatedTime"`
+}
+
+// orderFilters 现货普通单和条件单需要分别查询
+var orderFilters = []string{"Order", "StopOrder"}
+
+// convertOrder 转换Bybit订单：Quantity 为已成交数量，Price 为成交均价（未成交时为挂单价或触发价）
+func convertOrder(order *orderInfo, pair cex.TradingPair) *cex.OrderResult {
+	origQuantity, _ := decimal.NewFromString(order.Qty)
+	executed, _ := decimal.NewFromString(order.CumExecQty)
+
+	price, _ := decimal.NewFromString(order.AvgPrice)
+	if !price.IsPositive() {
+		price, _ = decimal.NewFromString(order.Price)
+	}
+	if !price.IsPositive() {
+		price, _ = decimal.NewFromString(order.TriggerPrice)
+	}
+
+	result := &cex.OrderResult{
+		TradingPair:   pair,
+		OrderID:       order.OrderID,
+		ClientOrderID: order.OrderLinkID,
+		Price:         price,
+		Quantity:      executed,
+		Side:          cex.OrderSide(strings.ToUpper(order.Side)),
+		Status:        normalizeOrderStatus(order.OrderStatus),
+		Type:          cex.OrderType(strings.ToUpper(order.OrderType)),
+		OrigQuantity:  origQuantity,
+	}
+	if updated, err := strconv.ParseInt(order.UpdatedTime, 10, 64); err == nil {
+		result.TransactTime = time.UnixMilli(updated)
+	}
+	return result
+}
+
+// normalizeOrderStatus 将Bybit订单状态转换为与币安一致的格式
+func normalizeOrderStatus(status string) string {
+	switch status {
+	case "New", "Untriggered", "Triggered":
+		return cex.OrderStatusNew
+	case "PartiallyFilled":
+		return cex.OrderStatusPartiallyFilled
+	case "Filled":
+		return cex.OrderStatusFilled
+	case "Cancelled", "PartiallyFilledCanceled", "Deactivated":
+		return cex.OrderStatusCanceled
+	case "Rejected":
+		return cex.OrderStatusRejected
+	default:
+		return strings.ToUpper(status)
+	}
+}
+
+// placeOrder 下单并查询成交情况
+// quoteQuantity 大于 0 时市价买单按计价资产金额下单
+func (c *Client) placeOrder(ctx context.Context, pair cex.TradingPair, side cex.OrderSide, orderType cex.OrderType, quantity, quoteQuantity, price decimal.Decimal, timeInForce cex.TimeInForce) (*cex.OrderResult, error) {
+	request := orderRequest{
+		Category:  categorySpot,
+		Symbol:    c.tradingPairToSymbol(pair),
+		Side:      "Buy",
+		OrderType: "Market",
+		Qty:       quantity.String(),
+	}
+	if side == cex.OrderSideSell {
+		request.Side = "Sell"
+	}
+	if orderType == cex.OrderTypeLimit {
+		request.OrderType = "Limit"
+		request.Price = price.String()
+		request.TimeInForce = string(timeInForce.ExchangeValue())
+	} else if side == cex.OrderSideBuy && quoteQuantity.IsPositive() {
+		request.Qty = quoteQuantity.String()
+		request.MarketUnit = "quoteCoin"
+	} else {
+		// 现货市价买单默认按计价资产数量，统一按基础资产数量下单
+		request.MarketUnit = "baseCoin"
+	}
+
+	created, err := c.createOrder(ctx, "Bybit order create", pair, request)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &cex.OrderResult{
+		TradingPair:   pair,
+		OrderID:       created.OrderID,
+		ClientOrderID: created.OrderLinkID,
+		Price:         price,
+		Quantity:      decimal.Zero,
+		Side:          side,
+		Status:        cex.OrderStatusNew,
+		Type:          orderType,
+		TransactTime:  time.Now(),
+	}
+
+	// 查询成交均价和成交数量，查询失败时仍返回已下单的结果，避免重复下单
+	order, err := c.getOrder(ctx, pair, created.OrderID)
+	if err != nil {
+		fmt.Printf("⚠️ Bybit order %s placed but status query failed: %v\n", created.OrderID, err)
+		return result, nil
+	}
+
+	if avgPrice, err := decimal.NewFromString(order.AvgPrice); err == nil && avgPrice.IsPositive() {
+		result.Price = avgPrice
+	}
+	result.Quantity, _ = decimal.NewFromString(order.CumExecQty)
+	result.Status = normalizeOrderStatus(order.OrderStatus)
+	if updated, err := strconv.ParseInt(order.UpdatedTime, 10, 64); err == nil {
+		result.TransactTime = time.UnixMilli(updated)
+	}
+	return result, nil
+}
+
+// createOrder 下单：重试时复用同一 orderLinkId，并先查询上次请求是否已生效，避免重复下单
+func (c *Client) createOrder(ctx context.Context, operation string, pair cex.TradingPair, request orderRequest) (*orderCreateResult, error) {
+	request.OrderLinkID = cex.NewClientOrderID()
+
+	var created orderCreateResult
+	err := c.retryer.Do(ctx, operation, func(attempt int) error {
+		if attempt > 0 {
+			query := url.Values{}
+			query.Set("orderLinkId", request.OrderLinkID)
+			if request.OrderFilter != "" {
+				query.Set("orderFilter", request.OrderFilter)
+			}
+			orders, err := c.listOrders(ctx, "/v5/order/realtime", pair, query)
+			if err != nil {
+				return err
+			}
+			if len(orders) > 0 {
+				created = orderCreateResult{OrderID: orders[0].OrderID, OrderLinkID: orders[0].OrderLinkID}
+				return nil
+			}
+		}
+		return c.send(ctx, http.MethodPost, "/v5/order/create", nil, request, true, &created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// getOrder 查询订单
+func (c *Client) getOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*orderInfo, error) {
+	query := url.Values{}
+	query.Set("orderId", orderID)
+
+	orders, err := c.listOrders(ctx, "/v5/order/realtime", pair, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	return orders[0], nil
+}
+
+// listOrders 查询订单列表
+func (c *Client) listOrders(ctx context.Context, path string, pair cex.TradingPair, query url.Values) ([]*orderInfo, error) {
+	query.Set("category", categorySpot)
+	query.Set("symbol", c.tradingPairToSymbol(pair))
+
+	var result struct {
+		List []*orderInfo `json:"list"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, query, nil, true, &result); err != nil {
+		return nil, err
+	}
+	return result.List, nil
+}
+
+// GetOpenOrders 获取交易对当前所有未完成挂单（普通单和条件单）
+func (c *Client) GetOpenOrders(ctx context.Context, pair cex.TradingPair) ([]*cex.OrderResult, error) {
+	var results []*cex.OrderResult
+	for _, filter := range orderFilters {
+		query := url.Values{}
+		query.Set("orderFilter", filter)
+		orders, err := c.listOrders(ctx, "/v5/order/realtime", pair, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open orders from Bybit: %w", err)
+		}
+		for _, order := range orders {
+			results = append(results, convertOrder(order, pair))
+		}
+	}
+	return results, nil
+}
+
+// GetRecentTrades 获取交易对最近 limit 笔成交（Bybit 单次最多 100 笔，最近 7 天）
+func (c *Client) GetRecentTrades(ctx context.Context, pair cex.TradingPair, limit int) ([]*cex.AccountTrade, error) {
+	query := url.Values{}
+	query.Set("category", categorySpot)
+	query.Set("symbol", c.tradingPairToSymbol(pair))
+	query.Set("limit", strconv.Itoa(limit))
+
+	var result struct {
+		List []struct {
+			ExecID      string `json:"execId"`
+			OrderID     string `json:"orderId"`
+			Side        string `json:"side"`
+			ExecPrice   string `json:"execPrice"`
+			ExecQty     string `json:"execQty"`
+			ExecValue   string `json:"execValue"`
+			ExecFee     string `json:"execFee"`
+			FeeCurrency string `json:"feeCurrency"`
+			IsMaker     bool   `json:"isMaker"`
+			ExecTime    string `json:"execTime"`
+		} `json:"list"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v5/execution/list", query, nil, true, &result); err != nil {
+		return nil, fmt.Errorf("failed to get trades from Bybit: %w", err)
+	}
+
+	// Bybit 按时间从新到旧返回
+	trades := make([]*cex.AccountTrade, 0, len(result.List))
+	for i := len(result.List) - 1; i >= 0; i-- {
+		execution := result.List[i]
+		price, _ := decimal.NewFromString(execution.ExecPrice)
+		quantity, _ := decimal.NewFromString(execution.ExecQty)
+		value, _ := decimal.NewFromString(execution.ExecValue)
+		fee, _ := decimal.NewFromString(execution.ExecFee)
+		trade := &cex.AccountTrade{
+			TradingPair:     pair,
+			TradeID:         execution.ExecID,
+			OrderID:         execution.OrderID,
+			Side:            cex.OrderSide(strings.ToUpper(execution.Side)),
+			Price:           price,
+			Quantity:        quantity,
+			QuoteQuantity:   value,
+			Commission:      fee,
+			CommissionAsset: execution.FeeCurrency,
+			IsMaker:         execution.IsMaker,
+		}
+		if execTime, err := strconv.ParseInt(execution.ExecTime, 10, 64); err == nil {
+			trade.Time = time.UnixMilli(execTime)
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// GetOrder 查询订单：先查活动订单，再查历史订单
+func (c *Client) GetOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*cex.OrderResult, error) {
+	for _, path := range []string{"/v5/order/realtime", "/v5/order/history"} {
+		for _, filter := range orderFilters {
+			query := url.Values{}
+			query.Set("orderId", orderID)
+			query.Set("orderFilter", filter)
+			orders, err := c.listOrders(ctx, path, pair, query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get order %s from Bybit: %w", orderID, err)
+			}
+			if len(orders) > 0 {
+				return convertOrder(orders[0], pair), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("order %s not found on Bybit", orderID)
+}
+
+// Buy 买入
+func (c *Client) Buy(ctx context.Context, order cex.BuyOrderRequest) (*cex.OrderResult, error) {
+	result, err := c.placeOrder(ctx, order.TradingPair, cex.OrderSideBuy, order.Type, order.Quantity, order.QuoteQuantity, order.Price, order.TimeInForce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place buy order on Bybit: %w", err)
+	}
+	return result, nil
+}
+
+// Sell 卖出
+func (c *Client) Sell(ctx context.Context, order cex.SellOrderRequest) (*cex.OrderResult, error) {
+	result, err := c.placeOrder(ctx, order.TradingPair, cex.OrderSideSell, order.Type, order.Quantity, decimal.Zero, order.Price, order.TimeInForce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place sell order on Bybit: %w", err)
+	}
+	return result, nil
+}
+
+// PlaceStopLossOrder 下止损卖单（现货条件单：价格跌到 stopPrice 时按市价卖出）
+func (c *Client) PlaceStopLossOrder(ctx context.Context, pair cex.TradingPair, quantity, stopPrice decimal.Decimal) (*cex.OrderResult, error) {
+	request := orderRequest{
+		Category:     categorySpot,
+		Symbol:       c.tradingPairToSymbol(pair),
+		Side:         "Sell",
+		OrderType:    "Market",
+		Qty:          quantity.String(),
+		MarketUnit:   "baseCoin",
+		TriggerPrice: stopPrice.String(),
+		OrderFilter:  "StopOrder",
+	}
+
+	created, err := c.createOrder(ctx, "Bybit stop order create", pair, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place stop loss order on Bybit: %w", err)
+	}
+
+	c.mu.Lock()
+	c.stopOrderIDs[created.OrderID] = true
+	c.mu.Unlock()
+
+	return &cex.OrderResult{
+		TradingPair:   pair,
+		OrderID:       created.OrderID,
+		ClientOrderID: created.OrderLinkID,
+		Price:         stopPrice,
+		Quantity:      quantity,
+		Side:          cex.OrderSideSell,
+		Status:        cex.OrderStatusNew,
+		Type:          cex.OrderTypeMarket,
+		TransactTime:  time.Now(),
+	}, nil
+}
+
+// CancelOrder 撤销订单（条件单需指定 orderFilter）
+func (c *Client) CancelOrder(ctx context.Context, pair cex.TradingPair, orderID string) error {
+	c.mu.Lock()
+	isStop := c.stopOrderIDs[orderID]
+	c.mu.Unlock()
+
+	request := map[string]string{
+		"category": categorySpot,
+		"symbol":   c.tradingPairToSymbol(pair),
+		"orderId":  orderID,
+	}
+	if isStop {
+		request["orderFilter"] = "StopOrder"
+	}
+
+	err := c.retryer.Do(ctx, "Bybit order cancel", func(attempt int) error {
+		err := c.send(ctx, http.MethodPost, "/v5/order/cancel", nil, request, true, nil)
+		if attempt > 0 && isOrderNotFound(err) {
+			// 上次请求已撤单成功
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order %s on Bybit: %w", orderID, err)
+	}
+
+	c.mu.Lock()
+	delete(c.stopOrderIDs, orderID)
+	c.mu.Unlock()
+	return nil
+}
+
+// GetAccount 获取账户信息
+func (c *Client) GetAccount(ctx context.Context) ([]*cex.AccountBalance, error) {
+	query := url.Values{}
+	query.Set("accountType", ConfigValue.AccountType)
+
+	var result struct {
+		List []struct {
+			Coin []struct {
+				Coin          string `json:"coin"`
+				WalletBalance string `json:"walletBalance"`
+				Locked        string `json:"locked"`
+			} `json:"coin"`
+		} `json:"list"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v5/account/wallet-balance", query, nil, true, &result); err != nil {
+		return nil, fmt.Errorf("failed to get account from Bybit: %w", err)
+	}
+
+	var balances []*cex.AccountBalance
+	for _, account := range result.List {
+		for _, coin := range account.Coin {
+			wallet, _ := decimal.NewFromString(coin.WalletBalance)
+			locked, _ := decimal.NewFromString(coin.Locked)
+			balances = append(balances, &cex.AccountBalance{
+				Asset:  coin.Coin,
+				Free:   wallet.Sub(locked),
+				Locked: locked,
+			})
+		}
+	}
+
+	return balances, nil
+}
+
+// GetAPIKeyPermissions 查询 API 密钥权限，非只读且有 SpotTrade 权限时可以下现货订单
+func (c *Client) GetAPIKeyPermissions(ctx context.Context) (*cex.APIKeyPermissions, error) {
+	var result struct {
+		ReadOnly    int                 `json:"readOnly"`
+		Permissions map[string][]string `json:"permissions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v5/user/query-api", nil, nil, true, &result); err != nil {
+		return nil, fmt.Errorf("failed to get api key info from Bybit: %w", err)
+	}
+
+	spotPermissions := result.Permissions["Spot"]
+	canTrade := false
+	for _, permission := range spotPermissions {
+		if permission == "SpotTrade" {
+			canTrade = true
+			break
+		}
+	}
+	return &cex.APIKeyPermissions{CanTrade: result.ReadOnly == 0 && canTrade, Permissions: spotPermissions}, nil
+}
+
+// GetOrderBook 获取前 limit 档盘口
+func (c *Client) GetOrderBook(ctx context.Context, pair cex.TradingPair, limit int) (*cex.OrderBook, error) {
+	query := url.Values{}
+	query.Set("category", categorySpot)
+	query.Set("symbol", c.tradingPairToSymbol(pair))
+	query.Set("limit", strconv.Itoa(limit))
+
+	// b/a 每项为 [price, size]，买单价格从高到低，卖单价格从低到高
+	var result struct {
+		Bids [][]string `json:"b"`
+		Asks [][]string `json:"a"`
+		Ts   int64      `json:"ts"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v5/market/orderbook", query, nil, false, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order book from Bybit: %w", err)
+	}
+
+	book := &cex.OrderBook{
+		TradingPair: pair,
+		Bids:        convertBookLevels(result.Bids),
+		Asks:        convertBookLevels(result.Asks),
+		Time:        time.UnixMilli(result.Ts),
+	}
+	return book, nil
+}
+
+// GetServerTime 获取交易所服务器时间
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	var result struct {
+		TimeNano string `json:"timeNano"`
+	}
+	if err := c.send(ctx, http.MethodGet, "/v5/market/time", nil, nil, false, &result); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get server time from Bybit: %w", err)
+	}
+	nanos, err := strconv.ParseInt(result.TimeNano, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Bybit server time %q: %w", result.TimeNano, err)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// convertBookLevels 转换盘口档位
+func convertBookLevels(items [][]string) []cex.OrderBookLevel {
+	levels := make([]cex.OrderBookLevel, 0, len(items))
+	for _, item := range items {
+		if len(item) < 2 {
+			continue
+		}
+		price, _ := decimal.NewFromString(item[0])
+		quantity, _ := decimal.NewFromString(item[1])
+		levels = append(levels, cex.OrderBookLevel{Price: price, Quantity: quantity})
+	}
+	return levels
+}
+
+// instrumentInfo 现货交易对信息（/v5/market/instruments-info）
+type instrumentInfo struct {
+	Symbol        string `json:"symbol"`
+	BaseCoin      string `json:"baseCoin"`
+	QuoteCoin     string `json:"quoteCoin"`
+	Status        string `json:"status"`
+	LotSizeFilter struct {
+		BasePrecision string `json:"basePrecision"`
+		MinOrderQty   string `json:"minOrderQty"`
+		MaxOrderQty   string `json:"maxOrderQty"`
+		MinOrderAmt   string `json:"minOrderAmt"`
+	} `json:"lotSizeFilter"`
+	PriceFilter struct {
+		TickSize string `json:"tickSize"`
+	} `json:"priceFilter"`
+}
+
+// filters 转换下单规则（现货数量步长为 basePrecision）
+func (i *instrumentInfo) filters() *cex.SymbolFilters {
+	filters := &cex.SymbolFilters{Symbol: i.Symbol}
+	filters.MinQty, _ = decimal.NewFromString(i.LotSizeFilter.MinOrderQty)
+	filters.MaxQty, _ = decimal.NewFromString(i.LotSizeFilter.MaxOrderQty)
+	filters.StepSize, _ = decimal.NewFromString(i.LotSizeFilter.BasePrecision)
+	filters.TickSize, _ = decimal.NewFromString(i.PriceFilter.TickSize)
+	filters.MinNotional, _ = decimal.NewFromString(i.LotSizeFilter.MinOrderAmt)
+	return filters
+}
+
+// getInstruments 查询现货交易对信息，symbol 为空时返回全部交易对（现货不分页）
+func (c *Client) getInstruments(ctx context.Context, symbol string) ([]instrumentInfo, error) {
+	query := url.Values{}
+	query.Set("category", categorySpot)
+	if symbol != "" {
+		query.Set("symbol", symbol)
+	}
+
+	var result struct {
+		List []instrumentInfo `json:"list"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v5/market/instruments-info", query, nil, false, &result); err != nil {
+		return nil, fmt.Errorf("failed to get instruments info from Bybit: %w", err)
+	}
+	return result.List, nil
+}
+
+// GetSymbolFilters 从 instruments-info 获取交易对下单规则
+func (c *Client) GetSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	symbol := c.tradingPairToSymbol(pair)
+	instruments, err := c.getInstruments(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	for i := range instruments {
+		if instruments[i].Symbol == symbol {
+			return instruments[i].filters(), nil
+		}
+	}
+	return nil, fmt.Errorf("symbol %s not found in Bybit instruments info", symbol)
+}
+
+// GetExchangeSymbols 从 instruments-info 获取全部现货交易对及其下单规则
+func (c *Client) GetExchangeSymbols(ctx context.Context) ([]*cex.ExchangeSymbol, error) {
+	instruments, err := c.getInstruments(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]*cex.ExchangeSymbol, 0, len(instruments))
+	for i := range instruments {
+		instrument := &instruments[i]
+		// Bybit 状态为 Trading / PreLaunch 等，统一为大写下划线格式
+		status := strings.ToUpper(instrument.Status)
+		if status == "PRELAUNCH" {
+			status = "PRE_LAUNCH"
+		}
+		symbols = append(symbols, &cex.ExchangeSymbol{
+			BaseAsset:  instrument.BaseCoin,
+			QuoteAsset: instrument.QuoteCoin,
+			Status:     status,
+			Filters:    *instrument.filters(),
+		})
+	}
+	return symbols, nil
+}
+
+// Ping 测试连接
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.do(ctx, http.MethodGet, "/v5/market/time", nil, nil, false, nil); err != nil {
+		return fmt.Errorf("Bybit ping failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package bybit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+// writeResult 按 Bybit 统一响应格式返回 result
+func writeResult(w http.ResponseWriter, result interface{}) {
+	data, _ := json.Marshal(result)
+	fmt.Fprintf(w, `{"retCode":0,"retMsg":"OK","result":%s}`, data)
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return newClient(server.URL, "key", "secret", server.Client())
+}
+
+func TestConvertInterval(t *testing.T) {
+	for interval, expected := range map[string]string{"1m": "1", "1h": "60", "4h": "240", "1d": "D", "1w": "W", "1M": "M"} {
+		got, _, err := convertInterval(interval)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got, interval)
+	}
+
+	_, _, err := convertInterval("8h")
+	assert.Error(t, err)
+}
+
+func TestGetKlinesWithTimeRange_Paginates(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	var windows [][2]int64
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v5/market/kline", r.URL.Path)
+		assert.Equal(t, "spot", r.URL.Query().Get("category"))
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "60", r.URL.Query().Get("interval"))
+
+		from, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		windows = append(windows, [2]int64{from, to})
+
+		// Bybit 按开盘时间倒序返回
+		var list [][]string
+		for ms := to - to%time.Hour.Milliseconds(); ms >= from; ms -= time.Hour.Milliseconds() {
+			list = append(list, []string{strconv.FormatInt(ms, 10), "100", "110", "90", "105", "2", "210"})
+		}
+		writeResult(w, map[string]interface{}{"list": list})
+	})
+
+	klines, err := client.GetKlinesWithTimeRange(context.Background(), testPair, "1h", start, end, 2)
+	require.NoError(t, err)
+
+	// 5根K线，每页2根：每页区间限制在2根K线内
+	require.Len(t, klines, 5)
+	require.Len(t, windows, 3)
+	assert.Equal(t, [2]int64{start.UnixMilli(), start.Add(2*time.Hour - time.Millisecond).UnixMilli()}, windows[0])
+	for i, k := range klines {
+		assert.Equal(t, start.Add(time.Duration(i)*time.Hour), k.OpenTime.UTC())
+		assert.Equal(t, k.OpenTime.Add(time.Hour-time.Millisecond), k.CloseTime)
+	}
+	assert.True(t, decimal.NewFromInt(210).Equal(klines[0].QuoteVolume))
+}
+
+func TestBuy_SignsRequestAndQueriesFill(t *testing.T) {
+	var created map[string]string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		timestamp := r.Header.Get("X-BAPI-TIMESTAMP")
+		recvWindow := r.Header.Get("X-BAPI-RECV-WINDOW")
+		assert.Equal(t, "key", r.Header.Get("X-BAPI-API-KEY"))
+
+		var payload string
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			payload = string(body)
+		} else {
+			payload = r.URL.RawQuery
+		}
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(timestamp + "key" + recvWindow + payload))
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-BAPI-SIGN"))
+
+		switch r.URL.Path {
+		case "/v5/order/create":
+			require.NoError(t, json.Unmarshal([]byte(payload), &created))
+			writeResult(w, map[string]string{"orderId": "123", "orderLinkId": "link"})
+		case "/v5/order/realtime":
+			assert.Equal(t, "123", r.URL.Query().Get("orderId"))
+			writeResult(w, map[string]interface{}{"list": []map[string]string{{
+				"orderId": "123", "avgPrice": "42000.5", "cumExecQty": "0.01", "orderStatus": "Filled", "updatedTime": "1704067200000",
+			}}})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	result, err := client.Buy(context.Background(), cex.BuyOrderRequest{
+		TradingPair: testPair,
+		Type:        cex.OrderTypeMarket,
+		Quantity:    decimal.RequireFromString("0.01"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Buy", created["side"])
+	assert.Equal(t, "Market", created["orderType"])
+	assert.Equal(t, "baseCoin", created["marketUnit"])
+	assert.Equal(t, "123", result.OrderID)
+	assert.Equal(t, "FILLED", result.Status)
+	assert.True(t, decimal.RequireFromString("42000.5").Equal(result.Price))
+	assert.True(t, decimal.RequireFromString("0.01").Equal(result.Quantity))
+	assert.Equal(t, int64(1704067200000), result.TransactTime.UnixMilli())
+}
+
+func TestBuy_QuoteQuantity(t *testing.T) {
+	var created map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v5/order/create":
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &created))
+			writeResult(w, map[string]string{"orderId": "123", "orderLinkId": "link"})
+		case "/v5/order/realtime":
+			writeResult(w, map[string]interface{}{"list": []map[string]string{{
+				"orderId": "123", "avgPrice": "0.00001234", "cumExecQty": "40518638", "orderStatus": "Filled", "updatedTime": "1704067200000",
+			}}})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	result, err := client.Buy(context.Background(), cex.BuyOrderRequest{
+		TradingPair:   testPair,
+		Type:          cex.OrderTypeMarket,
+		QuoteQuantity: decimal.NewFromInt(500),
+	})
+	require.NoError(t, err)
+
+	// 按计价资产金额下单，成交数量以交易所返回为准
+	assert.Equal(t, "500", created["qty"])
+	assert.Equal(t, "quoteCoin", created["marketUnit"])
+	assert.True(t, decimal.NewFromInt(40518638).Equal(result.Quantity))
+}
+
+func TestSell_LimitTimeInForce(t *testing.T) {
+	var created []map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v5/order/create":
+			body, _ := io.ReadAll(r.Body)
+			var request map[string]string
+			require.NoError(t, json.Unmarshal(body, &request))
+			created = append(created, request)
+			writeResult(w, map[string]string{"orderId": "123"})
+		case "/v5/order/realtime":
+			writeResult(w, map[string]interface{}{"list": []map[string]string{{
+				"orderId": "123", "avgPrice": "0", "cumExecQty": "0", "orderStatus": "Cancelled", "updatedTime": "1704067200000",
+			}}})
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	// 现货不支持 GTD，按 GTC 下单；未指定时为 GTC
+	for _, tif := range []cex.TimeInForce{cex.TimeInForceIOC, cex.TimeInForceFOK, cex.TimeInForceGTD, ""} {
+		_, err := client.Sell(context.Background(), cex.SellOrderRequest{
+			TradingPair: testPair,
+			Type:        cex.OrderTypeLimit,
+			Quantity:    decimal.RequireFromString("0.01"),
+			Price:       decimal.RequireFromString("43000"),
+			TimeInForce: tif,
+		})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, created, 4)
+	assert.Equal(t, "Limit", created[0]["orderType"])
+	assert.Equal(t, "43000", created[0]["price"])
+	assert.Equal(t, []string{"IOC", "FOK", "GTC", "GTC"},
+		[]string{created[0]["timeInForce"], created[1]["timeInForce"], created[2]["timeInForce"], created[3]["timeInForce"]})
+}
+
+func TestCancelOrder_StopOrderFilter(t *testing.T) {
+	var cancels []map[string]string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.URL.Path {
+		case "/v5/order/create":
+			assert.Equal(t, "StopOrder", body["orderFilter"])
+			assert.Equal(t, "40000", body["triggerPrice"])
+			writeResult(w, map[string]string{"orderId": "stop-1"})
+		case "/v5/order/cancel":
+			cancels = append(cancels, body)
+			writeResult(w, map[string]string{"orderId": body["orderId"]})
+		}
+	})
+
+	order, err := client.PlaceStopLossOrder(context.Background(), testPair, decimal.NewFromInt(1), decimal.NewFromInt(40000))
+	require.NoError(t, err)
+	require.NoError(t, client.CancelOrder(context.Background(), testPair, order.OrderID))
+	require.NoError(t, client.CancelOrder(context.Background(), testPair, "limit-1"))
+
+	require.Len(t, cancels, 2)
+	assert.Equal(t, "StopOrder", cancels[0]["orderFilter"])
+	assert.Empty(t, cancels[1]["orderFilter"])
+}
+
+func TestGetOrder_FallsBackToHistory(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.Query().Get("orderFilter"))
+		var list []map[string]string
+		if r.URL.Path == "/v5/order/history" && r.URL.Query().Get("orderFilter") == "StopOrder" {
+			list = append(list, map[string]string{
+				"orderId": "stop-1", "side": "Sell", "orderType": "Market", "qty": "1", "cumExecQty": "1",
+				"avgPrice": "39990", "triggerPrice": "40000", "orderStatus": "Filled",
+			})
+		}
+		writeResult(w, map[string]interface{}{"list": list})
+	})
+
+	order, err := client.GetOrder(context.Background(), testPair, "stop-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/v5/order/realtime?Order", "/v5/order/realtime?StopOrder", "/v5/order/history?Order", "/v5/order/history?StopOrder"}, paths)
+	assert.Equal(t, cex.OrderStatusFilled, order.Status)
+	assert.Equal(t, cex.OrderSideSell, order.Side)
+	assert.True(t, decimal.NewFromInt(39990).Equal(order.Price))
+	assert.True(t, decimal.NewFromInt(1).Equal(order.OrigQuantity))
+}
+
+func TestGetAccount(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v5/account/wallet-balance", r.URL.Path)
+		writeResult(w, map[string]interface{}{"list": []interface{}{map[string]interface{}{
+			"coin": []map[string]string{{"coin": "USDT", "walletBalance": "100.5", "locked": "20"}},
+		}}})
+	})
+
+	balances, err := client.GetAccount(context.Background())
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	assert.Equal(t, "USDT", balances[0].Asset)
+	assert.True(t, decimal.RequireFromString("80.5").Equal(balances[0].Free))
+	assert.True(t, decimal.NewFromInt(20).Equal(balances[0].Locked))
+}
+
+func TestGetAPIKeyPermissions(t *testing.T) {
+	readOnly := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v5/user/query-api", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("X-BAPI-SIGN"))
+		writeResult(w, map[string]interface{}{
+			"readOnly":    readOnly,
+			"permissions": map[string][]string{"Spot": {"SpotTrade"}, "Wallet": {"AccountTransfer"}},
+		})
+	})
+
+	permissions, err := client.GetAPIKeyPermissions(context.Background())
+	require.NoError(t, err)
+	assert.True(t, permissions.CanTrade)
+	assert.Equal(t, []string{"SpotTrade"}, permissions.Permissions)
+
+	// 只读密钥不能下单
+	readOnly = 1
+	permissions, err = client.GetAPIKeyPermissions(context.Background())
+	require.NoError(t, err)
+	assert.False(t, permissions.CanTrade)
+}
+
+func TestGetRecentTrades(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v5/execution/list", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		writeResult(w, map[string]interface{}{"list": []map[string]interface{}{
+			{"execId": "e2", "orderId": "o2", "side": "Sell", "execPrice": "41000", "execQty": "0.1", "execValue": "4100", "execFee": "4.1", "feeCurrency": "USDT", "isMaker": true, "execTime": "1704070800000"},
+			{"execId": "e1", "orderId": "o1", "side": "Buy", "execPrice": "40000", "execQty": "0.1", "execValue": "4000", "execFee": "0.0001", "feeCurrency": "BTC", "isMaker": false, "execTime": "1704067200000"},
+		}})
+	})
+
+	trades, err := client.GetRecentTrades(context.Background(), cex.TradingPair{Base: "BTC", Quote: "USDT"}, 2)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+
+	// 按时间从早到晚
+	assert.Equal(t, "e1", trades[0].TradeID)
+	assert.Equal(t, cex.OrderSideBuy, trades[0].Side)
+	assert.Equal(t, "BTC", trades[0].CommissionAsset)
+	assert.Equal(t, time.UnixMilli(1704067200000), trades[0].Time)
+	assert.Equal(t, cex.OrderSideSell, trades[1].Side)
+	assert.True(t, decimal.NewFromInt(4100).Equal(trades[1].QuoteQuantity))
+	assert.True(t, trades[1].IsMaker)
+}
+
+func TestGetSymbolFilters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v5/market/instruments-info", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+		writeResult(w, map[string]interface{}{"list": []interface{}{map[string]interface{}{
+			"symbol": "BTCUSDT",
+			"lotSizeFilter": map[string]string{
+				"basePrecision": "0.000001", "minOrderQty": "0.000048", "maxOrderQty": "71.73956243", "minOrderAmt": "1",
+			},
+			"priceFilter": map[string]string{"tickSize": "0.01"},
+		}}})
+	})
+
+	filters, err := client.GetSymbolFilters(context.Background(), testPair)
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", filters.Symbol)
+	assert.Equal(t, "0.000001", filters.StepSize.String())
+	assert.Equal(t, "0.000048", filters.MinQty.String())
+	assert.Equal(t, "0.01", filters.TickSize.String())
+	assert.Equal(t, "1", filters.MinNotional.String())
+}
+
+func TestGetServerTime(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v5/market/time", r.URL.Path)
+		writeResult(w, map[string]string{"timeSecond": "1704067200", "timeNano": "1704067200123456789"})
+	})
+
+	serverTime, err := client.GetServerTime(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 123456789, time.UTC), serverTime.UTC())
+}
+
+func TestBuy_RetryDoesNotDuplicateOrder(t *testing.T) {
+	var creates []map[string]string
+	var lookups int
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v5/order/create":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			creates = append(creates, body)
+			// 请求已生效但响应丢失
+			w.WriteHeader(http.StatusBadGateway)
+		case "/v5/order/realtime":
+			lookups++
+			if linkID := r.URL.Query().Get("orderLinkId"); linkID != "" {
+				assert.Equal(t, creates[0]["orderLinkId"], linkID)
+				writeResult(w, map[string]interface{}{"list": []map[string]string{{"orderId": "123", "orderLinkId": linkID}}})
+				return
+			}
+			writeResult(w, map[string]interface{}{"list": []map[string]string{{
+				"orderId": "123", "avgPrice": "100", "cumExecQty": "1", "orderStatus": "Filled",
+			}}})
+		}
+	})
+	client.retryer = cex.NewRetryer(cex.RetryConfig{MaxRetries: 2, InitialBackoffMs: 1, MaxBackoffMs: 1, Multiplier: 1}, isRetryableError)
+
+	result, err := client.Buy(context.Background(), cex.BuyOrderRequest{
+		TradingPair: testPair,
+		Type:        cex.OrderTypeMarket,
+		Quantity:    decimal.NewFromInt(1),
+	})
+	require.NoError(t, err)
+	require.Len(t, creates, 1)
+	assert.NotEmpty(t, creates[0]["orderLinkId"])
+	assert.Equal(t, 2, lookups)
+	assert.Equal(t, "123", result.OrderID)
+	assert.Equal(t, cex.OrderStatusFilled, result.Status)
+}
+
+func TestGet_RetriesRateLimit(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			fmt.Fprint(w, `{"retCode":10006,"retMsg":"Too many visits","result":{}}`)
+			return
+		}
+		writeResult(w, map[string]string{})
+	})
+	client.retryer = cex.NewRetryer(cex.RetryConfig{MaxRetries: 2, InitialBackoffMs: 1, MaxBackoffMs: 1, Multiplier: 1}, isRetryableError)
+
+	require.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"retCode":10001,"retMsg":"params error","result":{}}`)
+	})
+
+	err := client.Ping(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 10001, apiErr.Code)
+}
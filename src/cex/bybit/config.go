@@ -0,0 +1,50 @@
+package bybit
+
+import (
+	"tradingbot/src/cex"
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// Config Bybit配置
+type Config struct {
+	APIKey        string          `json:"api_key"`        // API密钥
+	SecretKey     string          `json:"secret_key"`     // API私钥
+	BaseURL       string          `json:"base_url"`       // API地址（测试网: https://api-testnet.bybit.com）
+	Timeout       int             `json:"timeout"`        // 请求超时时间(秒)
+	RecvWindow    int             `json:"recv_window"`    // 签名请求有效时间窗口(毫秒)
+	AccountType   string          `json:"account_type"`   // 查询余额的账户类型（统一账户为 UNIFIED）
+	EnableTrading bool            `json:"enable_trading"` // 启用交易权限
+	ReadOnly      bool            `json:"read_only"`      // 只读模式
+	Fees          cex.FeeSchedule `json:"fees"`           // 手续费表（maker/taker、成交额等级）
+	Retry         cex.RetryConfig `json:"retry"`          // REST 请求失败重试（指数退避+抖动）
+	DBName        string          `json:"db_name"`        // 数据库名称
+}
+
+// ConfigValue Bybit配置实例
+var ConfigValue = Config{
+	APIKey:        "",
+	SecretKey:     "",
+	BaseURL:       "https://api.bybit.com",
+	Timeout:       10,
+	RecvWindow:    5000,
+	AccountType:   "UNIFIED",
+	EnableTrading: false,
+	ReadOnly:      true,
+	Fees: cex.FeeSchedule{ // Bybit 现货普通用户 maker/taker 均为 0.1%，无平台币抵扣
+		MakerRate:      0.001,
+		TakerRate:      0.001,
+		UseBNBDiscount: false,
+		BNBDiscount:    0,
+		Volume30d:      0,
+		Tiers:          []cex.FeeTier{},
+	},
+	Retry:  cex.DefaultRetryConfig(),
+	DBName: "tradingbot_bybit",
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("BYBIT", &ConfigValue)
+}
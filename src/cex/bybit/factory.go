@@ -0,0 +1,19 @@
+package bybit
+
+import (
+	"tradingbot/src/cex"
+)
+
+// BybitFactory Bybit工厂实现
+type BybitFactory struct{}
+
+// CreateClient 创建Bybit客户端
+func (f *BybitFactory) CreateClient() cex.CEXClient {
+	config := &ConfigValue
+	return NewClient(config.APIKey, config.SecretKey)
+}
+
+// 注册Bybit工厂
+func init() {
+	cex.RegisterCEXFactory("bybit", &BybitFactory{})
+}
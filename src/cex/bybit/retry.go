@@ -0,0 +1,44 @@
+package bybit
+
+import (
+	"errors"
+	"net/http"
+
+	"tradingbot/src/cex"
+)
+
+// retryableCodes 可重试的 Bybit retCode
+var retryableCodes = map[int]bool{
+	10000: true, // 服务端超时
+	10002: true, // 请求时间超出 recv_window
+	10006: true, // 触发接口限频
+	10016: true, // 服务端错误
+	10429: true, // 触发系统级限频保护
+}
+
+// isRetryableError 判断 Bybit 错误是否可重试（余额不足、参数错误等业务错误不重试）
+func isRetryableError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryableCodes[apiErr.Code]
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// isOrderNotFound 订单不存在（170213 现货订单不存在，110001 订单不存在或已无法撤销）
+func isOrderNotFound(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 170213 || apiErr.Code == 110001
+	}
+	return false
+}
+
+// SetRateLimiter 实现 cex.RateLimitedClient，所有 REST 请求（包括重试）按共享限频器取得令牌
+func (c *Client) SetRateLimiter(limiter *cex.RateLimiter) {
+	c.retryer.SetRateLimiter(limiter)
+}
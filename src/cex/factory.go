@@ -9,6 +9,11 @@ type CEXFactory interface {
 	CreateClient() CEXClient
 }
 
+// ConfigValidator 创建客户端前检查配置的工厂（可选能力，通过类型断言使用）
+type ConfigValidator interface {
+	Validate() error
+}
+
 // CEXFactoryRegistry CEX工厂注册表
 var CEXFactoryRegistry = make(map[string]CEXFactory)
 
@@ -24,6 +29,12 @@ func CreateCEXClient(cexName string) (CEXClient, error) {
 		return nil, fmt.Errorf("unsupported CEX: %s", cexName)
 	}
 
+	if validator, ok := factory.(ConfigValidator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s config: %w", cexName, err)
+		}
+	}
+
 	// 创建客户端，所有信息都从客户端获取
 	client := factory.CreateClient()
 
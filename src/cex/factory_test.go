@@ -107,6 +107,30 @@ func TestCreateCEXClient_Success(t *testing.T) {
 	assert.Equal(t, "test-cex", client.GetName())
 }
 
+// validatingCEXFactory 创建客户端前检查配置的工厂mock
+type validatingCEXFactory struct {
+	mockCEXFactory
+	err error
+}
+
+func (f *validatingCEXFactory) Validate() error {
+	return f.err
+}
+
+func TestCreateCEXClient_ValidatesConfig(t *testing.T) {
+	CEXFactoryRegistry = make(map[string]CEXFactory)
+	RegisterCEXFactory("valid", &validatingCEXFactory{mockCEXFactory: mockCEXFactory{clientName: "valid"}})
+	RegisterCEXFactory("invalid", &validatingCEXFactory{err: assert.AnError})
+
+	client, err := CreateCEXClient("valid")
+	require.NoError(t, err)
+	assert.Equal(t, "valid", client.GetName())
+
+	client, err = CreateCEXClient("invalid")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, client)
+}
+
 func TestCreateCEXClient_UnsupportedCEX(t *testing.T) {
 	// 清空注册表
 	CEXFactoryRegistry = make(map[string]CEXFactory)
@@ -0,0 +1,84 @@
+package cex
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeTier 手续费等级：30天成交额达到 MinVolume 后使用该等级费率
+type FeeTier struct {
+	MinVolume float64 `json:"min_volume"` // 30天成交额下限（计价资产）
+	MakerRate float64 `json:"maker_rate"` // 挂单（maker）费率
+	TakerRate float64 `json:"taker_rate"` // 吃单（taker）费率
+}
+
+// FeeSchedule 交易所手续费表：区分 maker/taker，支持平台币抵扣和按成交额分级
+type FeeSchedule struct {
+	MakerRate      float64   `json:"maker_rate"`       // 基础挂单费率
+	TakerRate      float64   `json:"taker_rate"`       // 基础吃单费率
+	UseBNBDiscount bool      `json:"use_bnb_discount"` // 是否使用 BNB 抵扣手续费
+	BNBDiscount    float64   `json:"bnb_discount"`     // BNB 抵扣折扣比例（币安现货为 0.25）
+	Volume30d      float64   `json:"volume_30d"`       // 账户已有的30天成交额，用于查找费率等级
+	Tiers          []FeeTier `json:"tiers"`            // 费率等级，为空时只使用基础费率
+}
+
+// FeeScheduleProvider 支持手续费表的交易所客户端
+type FeeScheduleProvider interface {
+	// GetFeeSchedule 获取手续费表
+	GetFeeSchedule() FeeSchedule
+}
+
+// Validate 验证手续费表
+func (s FeeSchedule) Validate() error {
+	if s.MakerRate < 0 || s.MakerRate >= 1 || s.TakerRate < 0 || s.TakerRate >= 1 {
+		return fmt.Errorf("fee rates must be in [0, 1), got maker=%f taker=%f", s.MakerRate, s.TakerRate)
+	}
+	if s.BNBDiscount < 0 || s.BNBDiscount >= 1 {
+		return fmt.Errorf("bnb discount must be in [0, 1), got %f", s.BNBDiscount)
+	}
+	if s.Volume30d < 0 {
+		return fmt.Errorf("30-day volume cannot be negative, got %f", s.Volume30d)
+	}
+	for i, tier := range s.Tiers {
+		if tier.MinVolume < 0 || tier.MakerRate < 0 || tier.MakerRate >= 1 || tier.TakerRate < 0 || tier.TakerRate >= 1 {
+			return fmt.Errorf("invalid fee tier %d: %+v", i, tier)
+		}
+	}
+	return nil
+}
+
+// TierFor 根据30天成交额查找适用的费率（maker, taker），未达到任何等级时使用基础费率
+func (s FeeSchedule) TierFor(volume30d float64) (float64, float64) {
+	maker, taker := s.MakerRate, s.TakerRate
+
+	tiers := append([]FeeTier(nil), s.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinVolume < tiers[j].MinVolume })
+	for _, tier := range tiers {
+		if volume30d < tier.MinVolume {
+			break
+		}
+		maker, taker = tier.MakerRate, tier.TakerRate
+	}
+	return maker, taker
+}
+
+// Rate 计算实际费率：按成交额查找等级，再应用 BNB 抵扣
+// volume30d 为不含 Volume30d 的新增成交额
+func (s FeeSchedule) Rate(isMaker bool, volume30d float64) float64 {
+	maker, taker := s.TierFor(s.Volume30d + volume30d)
+	rate := taker
+	if isMaker {
+		rate = maker
+	}
+	if s.UseBNBDiscount {
+		rate *= 1 - s.BNBDiscount
+	}
+	return rate
+}
+
+// Commission 计算一笔成交的手续费（计价资产）
+func (s FeeSchedule) Commission(notional decimal.Decimal, isMaker bool, volume30d float64) decimal.Decimal {
+	return notional.Mul(decimal.NewFromFloat(s.Rate(isMaker, volume30d)))
+}
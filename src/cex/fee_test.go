@@ -0,0 +1,48 @@
+package cex
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeSchedule_Rate(t *testing.T) {
+	schedule := FeeSchedule{
+		MakerRate:   0.001,
+		TakerRate:   0.001,
+		BNBDiscount: 0.25,
+		Tiers: []FeeTier{
+			{MinVolume: 5000000, MakerRate: 0.0008, TakerRate: 0.001},
+			{MinVolume: 1000000, MakerRate: 0.0009, TakerRate: 0.001},
+		},
+	}
+	assert.NoError(t, schedule.Validate())
+
+	// 未达到任何等级使用基础费率
+	assert.InDelta(t, 0.001, schedule.Rate(true, 0), 1e-12)
+
+	// 按成交额查找等级（等级无需按顺序配置）
+	assert.InDelta(t, 0.0009, schedule.Rate(true, 1000000), 1e-12)
+	assert.InDelta(t, 0.0008, schedule.Rate(true, 6000000), 1e-12)
+	assert.InDelta(t, 0.001, schedule.Rate(false, 6000000), 1e-12)
+
+	// 账户已有成交额计入等级
+	schedule.Volume30d = 4000000
+	assert.InDelta(t, 0.0008, schedule.Rate(true, 1000000), 1e-12)
+
+	// BNB 抵扣
+	schedule.UseBNBDiscount = true
+	assert.InDelta(t, 0.0006, schedule.Rate(true, 1000000), 1e-12)
+
+	commission := schedule.Commission(decimal.NewFromInt(10000), true, 1000000)
+	assert.InDelta(t, 6, commission.InexactFloat64(), 1e-9)
+}
+
+func TestFeeSchedule_Validate(t *testing.T) {
+	assert.Error(t, FeeSchedule{MakerRate: -0.001}.Validate())
+	assert.Error(t, FeeSchedule{TakerRate: 1}.Validate())
+	assert.Error(t, FeeSchedule{BNBDiscount: 1}.Validate())
+	assert.Error(t, FeeSchedule{Tiers: []FeeTier{{MinVolume: -1}}}.Validate())
+	assert.NoError(t, FeeSchedule{}.Validate())
+}
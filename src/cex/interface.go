@@ -51,10 +51,17 @@ const (
 
 // BuyOrderRequest 买入订单请求
 type BuyOrderRequest struct {
-	TradingPair TradingPair     `json:"trading_pair"`
-	Type        OrderType       `json:"type"`
-	Quantity    decimal.Decimal `json:"quantity"`
-	Price       decimal.Decimal `json:"price,omitempty"` // 限价单时需要
+	TradingPair   TradingPair     `json:"trading_pair"`
+	Type          OrderType       `json:"type"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Price         decimal.Decimal `json:"price,omitempty"`          // 限价单时需要
+	TimeInForce   TimeInForce     `json:"time_in_force,omitempty"`  // 限价单有效方式，空为 GTC
+	QuoteQuantity decimal.Decimal `json:"quote_quantity,omitempty"` // 市价单按计价资产金额买入（如 500 USDT），大于 0 时忽略 Quantity，由交易所计算成交数量
+}
+
+// IsQuoteOrder 是否按计价资产金额下的市价买单
+func (r BuyOrderRequest) IsQuoteOrder() bool {
+	return r.Type == OrderTypeMarket && r.QuoteQuantity.IsPositive()
 }
 
 // SellOrderRequest 卖出订单请求
@@ -62,9 +69,20 @@ type SellOrderRequest struct {
 	TradingPair TradingPair     `json:"trading_pair"`
 	Type        OrderType       `json:"type"`
 	Quantity    decimal.Decimal `json:"quantity"`
-	Price       decimal.Decimal `json:"price,omitempty"` // 限价单时需要
+	Price       decimal.Decimal `json:"price,omitempty"`         // 限价单时需要
+	TimeInForce TimeInForce     `json:"time_in_force,omitempty"` // 限价单有效方式，空为 GTC
 }
 
+// 订单状态（各交易所统一为币安格式）
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusRejected        = "REJECTED"
+	OrderStatusExpired         = "EXPIRED"
+)
+
 // OrderResult 订单结果
 type OrderResult struct {
 	TradingPair   TradingPair     `json:"trading_pair"`
@@ -76,6 +94,10 @@ type OrderResult struct {
 	Status        string          `json:"status"`
 	Type          OrderType       `json:"type"`
 	TransactTime  time.Time       `json:"transact_time"`
+
+	// 查询挂单/订单时填充：Quantity 为已成交数量，Price 为成交均价（未成交时为挂单价或触发价）
+	OrigQuantity decimal.Decimal `json:"orig_quantity"`           // 下单数量
+	OrderListID  string          `json:"order_list_id,omitempty"` // 所属 OCO 订单组ID
 }
 
 // AccountBalance 账户余额
@@ -114,3 +136,201 @@ type CEXClient interface {
 	// Ping 测试连接
 	Ping(ctx context.Context) error
 }
+
+// StopOrderClient 支持止损单的交易所客户端（可选能力，通过类型断言使用）
+type StopOrderClient interface {
+	// PlaceStopLossOrder 下止损卖单，价格跌到 stopPrice 时按市价卖出
+	PlaceStopLossOrder(ctx context.Context, pair TradingPair, quantity, stopPrice decimal.Decimal) (*OrderResult, error)
+
+	// CancelOrder 撤销订单
+	CancelOrder(ctx context.Context, pair TradingPair, orderID string) error
+}
+
+// OpenOrderClient 支持查询挂单和订单状态的交易所客户端（可选能力，通过类型断言使用）
+type OpenOrderClient interface {
+	// GetOpenOrders 获取交易对当前所有未完成挂单（含止损单）
+	GetOpenOrders(ctx context.Context, pair TradingPair) ([]*OrderResult, error)
+
+	// GetOrder 查询订单（包括已成交、已撤销的订单）
+	GetOrder(ctx context.Context, pair TradingPair, orderID string) (*OrderResult, error)
+}
+
+// AccountTrade 账户成交记录
+type AccountTrade struct {
+	TradingPair     TradingPair     `json:"trading_pair"`
+	TradeID         string          `json:"trade_id"`
+	OrderID         string          `json:"order_id"`
+	Side            OrderSide       `json:"side"`
+	Price           decimal.Decimal `json:"price"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	QuoteQuantity   decimal.Decimal `json:"quote_quantity"`
+	Commission      decimal.Decimal `json:"commission"`
+	CommissionAsset string          `json:"commission_asset"`
+	IsMaker         bool            `json:"is_maker"`
+	Time            time.Time       `json:"time"`
+}
+
+// TradeHistoryClient 支持查询账户成交记录的交易所客户端（可选能力，通过类型断言使用）
+type TradeHistoryClient interface {
+	// GetRecentTrades 获取交易对最近 limit 笔成交，按时间从早到晚排序
+	GetRecentTrades(ctx context.Context, pair TradingPair, limit int) ([]*AccountTrade, error)
+}
+
+// OrderUpdate 订单状态推送（成交回报）
+type OrderUpdate struct {
+	Symbol        string    `json:"symbol"`
+	OrderID       string    `json:"order_id"`
+	ClientOrderID string    `json:"client_order_id"`
+	OrderListID   string    `json:"order_list_id,omitempty"` // 所属 OCO 订单组ID
+	Side          OrderSide `json:"side"`
+	Type          OrderType `json:"type"`
+	Status        string    `json:"status"`         // 订单当前状态（OrderStatus*）
+	ExecutionType string    `json:"execution_type"` // 本次事件类型：NEW / TRADE / CANCELED / EXPIRED 等
+
+	LastQuantity        decimal.Decimal `json:"last_quantity"`         // 本次成交数量
+	LastPrice           decimal.Decimal `json:"last_price"`            // 本次成交价格
+	FilledQuantity      decimal.Decimal `json:"filled_quantity"`       // 累计成交数量
+	FilledQuoteQuantity decimal.Decimal `json:"filled_quote_quantity"` // 累计成交额
+	Commission          decimal.Decimal `json:"commission"`            // 本次成交手续费
+	CommissionAsset     string          `json:"commission_asset"`      // 手续费资产
+	Time                time.Time       `json:"time"`
+}
+
+// IsTrade 是否为成交事件
+func (u *OrderUpdate) IsTrade() bool {
+	return u.ExecutionType == "TRADE" && u.LastQuantity.IsPositive()
+}
+
+// IsClosed 订单是否已结束（全部成交、撤销、拒绝或过期）
+func (u *OrderUpdate) IsClosed() bool {
+	switch u.Status {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// AccountUpdate 账户余额推送（只包含发生变化的资产）
+type AccountUpdate struct {
+	Balances []*AccountBalance `json:"balances"`
+	Time     time.Time         `json:"time"`
+}
+
+// UserDataHandler 账户数据流事件处理
+type UserDataHandler interface {
+	OnOrderUpdate(update *OrderUpdate)
+	OnAccountUpdate(update *AccountUpdate)
+}
+
+// UserDataStreamClient 支持推送账户数据流（成交回报、余额变化）的交易所客户端（可选能力，通过类型断言使用）
+type UserDataStreamClient interface {
+	// SubscribeUserData 订阅账户数据流，阻塞直到 ctx 取消（返回 nil）或连接断开（返回错误）
+	SubscribeUserData(ctx context.Context, handler UserDataHandler) error
+}
+
+// TestnetClient 可连接测试网的交易所客户端（可选能力，通过类型断言使用）
+type TestnetClient interface {
+	// IsTestnet 是否连接测试网
+	IsTestnet() bool
+}
+
+// IsTestnet 客户端是否连接测试网（不支持测试网的客户端返回 false）
+func IsTestnet(client CEXClient) bool {
+	testnet, ok := client.(TestnetClient)
+	return ok && testnet.IsTestnet()
+}
+
+// OCOOrderResult OCO 订单结果
+type OCOOrderResult struct {
+	OrderListID string         `json:"order_list_id"` // 交易所订单组ID
+	Orders      []*OrderResult `json:"orders"`        // 止盈腿和止损腿
+}
+
+// OCOOrderClient 支持 OCO（一个成交自动撤销另一个）订单的交易所客户端（可选能力，通过类型断言使用）
+type OCOOrderClient interface {
+	// PlaceOCOSellOrder 下 OCO 卖单：takeProfitPrice 限价止盈，价格跌到 stopPrice 时以 stopLimitPrice 限价止损
+	PlaceOCOSellOrder(ctx context.Context, pair TradingPair, quantity, takeProfitPrice, stopPrice, stopLimitPrice decimal.Decimal) (*OCOOrderResult, error)
+
+	// CancelOCOOrder 撤销整个 OCO 订单组
+	CancelOCOOrder(ctx context.Context, pair TradingPair, orderListID string) error
+}
+
+// OrderBookLevel 盘口档位
+type OrderBookLevel struct {
+	Price    decimal.Decimal `json:"price"`
+	Quantity decimal.Decimal `json:"quantity"`
+}
+
+// OrderBook 订单簿快照：Bids 按价格从高到低，Asks 按价格从低到高
+type OrderBook struct {
+	TradingPair TradingPair      `json:"trading_pair"`
+	Bids        []OrderBookLevel `json:"bids"`
+	Asks        []OrderBookLevel `json:"asks"`
+	Time        time.Time        `json:"time"`
+}
+
+// BestBid 买一价（无买单时返回0）
+func (b *OrderBook) BestBid() decimal.Decimal {
+	if len(b.Bids) == 0 {
+		return decimal.Zero
+	}
+	return b.Bids[0].Price
+}
+
+// BestAsk 卖一价（无卖单时返回0）
+func (b *OrderBook) BestAsk() decimal.Decimal {
+	if len(b.Asks) == 0 {
+		return decimal.Zero
+	}
+	return b.Asks[0].Price
+}
+
+// MidPrice 买一卖一中间价（单边为空时返回另一边的价格）
+func (b *OrderBook) MidPrice() decimal.Decimal {
+	bid, ask := b.BestBid(), b.BestAsk()
+	switch {
+	case bid.IsZero():
+		return ask
+	case ask.IsZero():
+		return bid
+	default:
+		return bid.Add(ask).Div(decimal.NewFromInt(2))
+	}
+}
+
+// OrderBookProvider 支持查询订单簿的交易所客户端（可选能力，通过类型断言使用）
+type OrderBookProvider interface {
+	// GetOrderBook 获取前 limit 档盘口
+	GetOrderBook(ctx context.Context, pair TradingPair, limit int) (*OrderBook, error)
+}
+
+// ServerTimeProvider 支持查询交易所服务器时间的客户端（可选能力，用于检测本地时钟偏差）
+type ServerTimeProvider interface {
+	// GetServerTime 获取交易所服务器时间（不重试，避免重试耗时影响偏差计算）
+	GetServerTime(ctx context.Context) (time.Time, error)
+}
+
+// FundingRate 永续合约资金费率结算记录
+type FundingRate struct {
+	TradingPair TradingPair     `json:"trading_pair"`
+	Time        time.Time       `json:"time"` // 结算时间
+	Rate        decimal.Decimal `json:"rate"` // 正数为多头支付空头，负数为空头支付多头
+}
+
+// OpenInterest 永续合约持仓量快照
+type OpenInterest struct {
+	TradingPair  TradingPair     `json:"trading_pair"`
+	Time         time.Time       `json:"time"`
+	OpenInterest decimal.Decimal `json:"open_interest"` // 持仓量（基础货币）
+	Value        decimal.Decimal `json:"value"`         // 持仓价值（计价货币）
+}
+
+// FuturesDataProvider 支持查询永续合约资金费率和持仓量历史的交易所客户端（可选能力，通过类型断言使用）
+// 现货交易对对应同名的 U 本位永续合约（如 BTC/USDT 对应 BTCUSDT 永续），只读取公开行情，现货交易也可使用
+type FuturesDataProvider interface {
+	// GetFundingRates 获取结算时间在 [startTime, endTime] 内的资金费率，最多 limit 条，按时间升序
+	GetFundingRates(ctx context.Context, pair TradingPair, startTime, endTime time.Time, limit int) ([]*FundingRate, error)
+
+	// GetOpenInterestHistory 获取 [startTime, endTime] 内按 period（如 1h）统计的持仓量，最多 limit 条，按时间升序
+	GetOpenInterestHistory(ctx context.Context, pair TradingPair, period string, startTime, endTime time.Time, limit int) ([]*OpenInterest, error)
+}
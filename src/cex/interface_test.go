@@ -225,6 +225,21 @@ func TestTradingPair_EdgeCases(t *testing.T) {
 }
 
 // 性能基准测试
+func TestOrderBook_Prices(t *testing.T) {
+	book := &OrderBook{
+		Bids: []OrderBookLevel{{Price: decimal.NewFromFloat(99.5), Quantity: decimal.NewFromInt(1)}},
+		Asks: []OrderBookLevel{{Price: decimal.NewFromFloat(100.5), Quantity: decimal.NewFromInt(1)}},
+	}
+	assert.True(t, decimal.NewFromFloat(99.5).Equal(book.BestBid()))
+	assert.True(t, decimal.NewFromFloat(100.5).Equal(book.BestAsk()))
+	assert.True(t, decimal.NewFromInt(100).Equal(book.MidPrice()))
+
+	// 单边盘口
+	book.Bids = nil
+	assert.True(t, book.BestBid().IsZero())
+	assert.True(t, decimal.NewFromFloat(100.5).Equal(book.MidPrice()))
+}
+
 func BenchmarkTradingPair_String(b *testing.B) {
 	pair := TradingPair{Base: "BTC", Quote: "USDT"}
 	b.ResetTimer()
@@ -0,0 +1,59 @@
+package cex
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// klinePageProgressInterval 每加载多少页输出一次进度
+const klinePageProgressInterval = 10
+
+// KlinePageFetcher 获取一页K线：开盘时间在 [startTime, endTime] 内，最多 limit 根，按开盘时间升序
+type KlinePageFetcher func(ctx context.Context, startTime, endTime time.Time, limit int) ([]*KlineData, error)
+
+// PaginateKlines 按页循环请求，直到加载完 [startTime, endTime] 内的全部K线
+// pageSize 为交易所单次请求上限；label 用于进度输出（如 "BTC/USDT 1h"）
+func PaginateKlines(ctx context.Context, startTime, endTime time.Time, pageSize int, fetch KlinePageFetcher, label string) ([]*KlineData, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("kline page size must be positive, got %d", pageSize)
+	}
+
+	var allKlines []*KlineData
+	currentStart := startTime
+	pages := 0
+
+	for !currentStart.After(endTime) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("kline loading cancelled after %d klines: %w", len(allKlines), err)
+		}
+
+		klines, err := fetch(ctx, currentStart, endTime, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		pages++
+
+		if len(klines) == 0 {
+			break
+		}
+		allKlines = append(allKlines, klines...)
+
+		lastOpen := klines[len(klines)-1].OpenTime
+		if pages%klinePageProgressInterval == 0 {
+			fmt.Printf("📥 %s: loaded %d klines (up to %s)...\n", label, len(allKlines), lastOpen.UTC().Format("2006-01-02 15:04"))
+		}
+
+		// 不足一页说明已经取完；开盘时间没有前进时停止，避免死循环
+		if len(klines) < pageSize || lastOpen.Before(currentStart) {
+			break
+		}
+		currentStart = lastOpen.Add(time.Millisecond)
+	}
+
+	if pages > 1 {
+		fmt.Printf("📥 %s: loaded %d klines in %d requests\n", label, len(allKlines), pages)
+	}
+
+	return allKlines, nil
+}
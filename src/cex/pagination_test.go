@@ -0,0 +1,76 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourlyPageFetcher 模拟交易所：返回 [startTime, endTime] 内的整点K线，最多 limit 根
+func hourlyPageFetcher(requests *int) KlinePageFetcher {
+	return func(ctx context.Context, startTime, endTime time.Time, limit int) ([]*KlineData, error) {
+		*requests++
+		var klines []*KlineData
+		t := startTime.Truncate(time.Hour)
+		if t.Before(startTime) {
+			t = t.Add(time.Hour)
+		}
+		for ; !t.After(endTime) && len(klines) < limit; t = t.Add(time.Hour) {
+			klines = append(klines, &KlineData{OpenTime: t, CloseTime: t.Add(time.Hour - time.Millisecond)})
+		}
+		return klines, nil
+	}
+}
+
+func TestPaginateKlines_LoadsFullRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	requests := 0
+
+	klines, err := PaginateKlines(context.Background(), start, end, 10, hourlyPageFetcher(&requests), "BTC/USDT 1h")
+	require.NoError(t, err)
+
+	// 25根K线，每页10根：10 + 10 + 5
+	require.Len(t, klines, 25)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, start, klines[0].OpenTime)
+	assert.Equal(t, end, klines[24].OpenTime)
+	for i := 1; i < len(klines); i++ {
+		assert.Equal(t, klines[i-1].OpenTime.Add(time.Hour), klines[i].OpenTime)
+	}
+}
+
+func TestPaginateKlines_ExactPageBoundary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+
+	// 恰好一整页且已到结束时间时不再多请求
+	klines, err := PaginateKlines(context.Background(), start, start.Add(9*time.Hour), 10, hourlyPageFetcher(&requests), "test")
+	require.NoError(t, err)
+	assert.Len(t, klines, 10)
+	assert.Equal(t, 1, requests)
+}
+
+func TestPaginateKlines_Errors(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := PaginateKlines(ctx, start, start.Add(time.Hour), 10, hourlyPageFetcher(&requests), "test")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, requests)
+
+	failing := func(ctx context.Context, startTime, endTime time.Time, limit int) ([]*KlineData, error) {
+		return nil, errors.New("rate limited")
+	}
+	_, err = PaginateKlines(context.Background(), start, start.Add(time.Hour), 10, failing, "test")
+	assert.EqualError(t, err, "rate limited")
+
+	_, err = PaginateKlines(context.Background(), start, start.Add(time.Hour), 0, hourlyPageFetcher(&requests), "test")
+	assert.Error(t, err)
+}
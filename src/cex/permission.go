@@ -0,0 +1,74 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTradingDisabled 配置禁止下单（ReadOnly 为 true 或 EnableTrading 为 false）
+var ErrTradingDisabled = errors.New("trading disabled by config")
+
+// ErrAPIKeyCannotTrade API 密钥没有现货交易权限
+var ErrAPIKeyCannotTrade = errors.New("api key has no spot trading permission")
+
+// TradingPermission 交易所配置中的交易开关
+type TradingPermission struct {
+	EnableTrading bool // 启用交易
+	ReadOnly      bool // 只读模式，优先于 EnableTrading
+}
+
+// Check 只读或未启用交易时返回 ErrTradingDisabled
+func (p TradingPermission) Check(exchange string) error {
+	if p.ReadOnly {
+		return fmt.Errorf("%w: %s ReadOnly is true", ErrTradingDisabled, exchange)
+	}
+	if !p.EnableTrading {
+		return fmt.Errorf("%w: %s EnableTrading is false", ErrTradingDisabled, exchange)
+	}
+	return nil
+}
+
+// TradingPermissionClient 提供配置交易开关的交易所客户端（可选能力，通过类型断言使用）
+type TradingPermissionClient interface {
+	TradingPermission() TradingPermission
+}
+
+// APIKeyPermissions 交易所返回的 API 密钥权限
+type APIKeyPermissions struct {
+	CanTrade    bool     // 可以下现货订单
+	Permissions []string // 交易所返回的权限列表（如 SPOT、SpotTrade）
+}
+
+// APIKeyPermissionClient 可查询 API 密钥权限的交易所客户端（可选能力，通过类型断言使用）
+type APIKeyPermissionClient interface {
+	GetAPIKeyPermissions(ctx context.Context) (*APIKeyPermissions, error)
+}
+
+// CheckTradingEnabled 按配置的交易开关检查能否下单，客户端未提供开关时不限制
+func CheckTradingEnabled(client CEXClient) error {
+	provider, ok := client.(TradingPermissionClient)
+	if !ok {
+		return nil
+	}
+	return provider.TradingPermission().Check(client.GetName())
+}
+
+// VerifyTradingPermissions 实盘启动前检查配置的交易开关和 API 密钥的交易权限（查询账户接口）
+func VerifyTradingPermissions(ctx context.Context, client CEXClient) error {
+	if err := CheckTradingEnabled(client); err != nil {
+		return err
+	}
+	checker, ok := client.(APIKeyPermissionClient)
+	if !ok {
+		return nil
+	}
+	permissions, err := checker.GetAPIKeyPermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s api key permissions: %w", client.GetName(), err)
+	}
+	if !permissions.CanTrade {
+		return fmt.Errorf("%w: %s permissions=%v", ErrAPIKeyCannotTrade, client.GetName(), permissions.Permissions)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// permissionMockClient 带交易开关和 API 密钥权限的客户端
+type permissionMockClient struct {
+	mockCEXClient
+	permission  TradingPermission
+	apiKey      *APIKeyPermissions
+	apiKeyError error
+}
+
+func (m *permissionMockClient) TradingPermission() TradingPermission {
+	return m.permission
+}
+
+func (m *permissionMockClient) GetAPIKeyPermissions(ctx context.Context) (*APIKeyPermissions, error) {
+	return m.apiKey, m.apiKeyError
+}
+
+func TestTradingPermission_Check(t *testing.T) {
+	assert.NoError(t, TradingPermission{EnableTrading: true}.Check("binance"))
+
+	err := TradingPermission{EnableTrading: true, ReadOnly: true}.Check("binance")
+	assert.ErrorIs(t, err, ErrTradingDisabled)
+	assert.Contains(t, err.Error(), "ReadOnly")
+
+	err = TradingPermission{}.Check("bybit")
+	assert.ErrorIs(t, err, ErrTradingDisabled)
+	assert.Contains(t, err.Error(), "bybit EnableTrading is false")
+}
+
+func TestVerifyTradingPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	// 未提供开关的客户端不限制
+	require.NoError(t, CheckTradingEnabled(&mockCEXClient{name: "mock"}))
+	require.NoError(t, VerifyTradingPermissions(ctx, &mockCEXClient{name: "mock"}))
+
+	client := &permissionMockClient{
+		mockCEXClient: mockCEXClient{name: "binance"},
+		permission:    TradingPermission{EnableTrading: true},
+		apiKey:        &APIKeyPermissions{CanTrade: true, Permissions: []string{"SPOT"}},
+	}
+	require.NoError(t, VerifyTradingPermissions(ctx, client))
+
+	// 配置禁止交易时不查询 API 密钥
+	client.permission.ReadOnly = true
+	client.apiKeyError = errors.New("should not be called")
+	assert.ErrorIs(t, VerifyTradingPermissions(ctx, client), ErrTradingDisabled)
+
+	client.permission.ReadOnly = false
+	err := VerifyTradingPermissions(ctx, client)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to verify binance api key permissions")
+
+	client.apiKeyError = nil
+	client.apiKey = &APIKeyPermissions{CanTrade: false, Permissions: []string{"MARGIN"}}
+	err = VerifyTradingPermissions(ctx, client)
+	assert.ErrorIs(t, err, ErrAPIKeyCannotTrade)
+	assert.Contains(t, err.Error(), "[MARGIN]")
+}
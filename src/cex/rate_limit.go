@@ -0,0 +1,87 @@
+package cex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig 交易所 REST 请求限频配置（同一进程内访问同一交易所的请求共享）
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requests_per_second"` // 平均每秒请求数，0 表示不限频
+	Burst             int     `json:"burst"`               // 允许的突发请求数
+}
+
+// Validate 检查配置
+func (c RateLimitConfig) Validate() error {
+	if c.RequestsPerSecond < 0 {
+		return fmt.Errorf("RequestsPerSecond must be non-negative, got %v", c.RequestsPerSecond)
+	}
+	if c.RequestsPerSecond > 0 && c.Burst <= 0 {
+		return fmt.Errorf("Burst must be positive, got %d", c.Burst)
+	}
+	return nil
+}
+
+// NewRateLimiter 按配置创建限频器，未启用时返回 nil
+func (c RateLimitConfig) NewRateLimiter() (*RateLimiter, error) {
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+	if c.RequestsPerSecond == 0 {
+		return nil, nil
+	}
+	return NewRateLimiter(c.RequestsPerSecond, c.Burst), nil
+}
+
+// RateLimiter 令牌桶限频器，多个客户端共享同一实例时合计请求速率不超过上限
+type RateLimiter struct {
+	rate  float64 // 每秒补充的令牌数
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter 创建限频器，初始令牌数为 burst
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+// Wait 取得一个令牌，令牌不足时等待到可用（ctx 取消时返回错误）
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// 先预占令牌，等待期间其他请求按顺序排在后面
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	return l.sleep(ctx, delay)
+}
+
+// RateLimitedClient 支持共享限频器的交易所客户端（多机器人共用同一交易所账户时）
+type RateLimitedClient interface {
+	// SetRateLimiter 设置限频器，每次 REST 请求（包括重试）前取得令牌，为空时不限频
+	SetRateLimiter(limiter *RateLimiter)
+}
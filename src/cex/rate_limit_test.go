@@ -0,0 +1,85 @@
+package cex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRateLimiter 创建使用模拟时钟、不实际等待的限频器
+func newTestRateLimiter(requestsPerSecond float64, burst int, now *time.Time, delays *[]time.Duration) *RateLimiter {
+	limiter := NewRateLimiter(requestsPerSecond, burst)
+	limiter.last = *now
+	limiter.now = func() time.Time { return *now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return ctx.Err()
+	}
+	return limiter
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+	limiter := newTestRateLimiter(10, 2, &now, &delays)
+	ctx := context.Background()
+
+	// 突发额度内不等待
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+	assert.Empty(t, delays)
+
+	// 额度用完后按速率排队：第3、4个请求分别等待 100ms、200ms
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, delays)
+
+	// 1秒后令牌补满（不超过突发额度）
+	now = now.Add(time.Second)
+	delays = nil
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+	assert.Empty(t, delays)
+}
+
+func TestRetryer_WaitsForSharedRateLimiter(t *testing.T) {
+	now := time.Now()
+	var limitDelays, retryDelays []time.Duration
+	limiter := newTestRateLimiter(5, 1, &now, &limitDelays)
+
+	// 两个客户端的重试器共享同一限频器
+	first := newTestRetryer(DefaultRetryConfig(), &retryDelays)
+	second := newTestRetryer(DefaultRetryConfig(), &retryDelays)
+	first.SetRateLimiter(limiter)
+	second.SetRateLimiter(limiter)
+
+	require.NoError(t, first.Do(context.Background(), "GetKlines", func(int) error { return nil }))
+	require.NoError(t, second.Do(context.Background(), "GetKlines", func(int) error { return nil }))
+	assert.Equal(t, []time.Duration{200 * time.Millisecond}, limitDelays)
+
+	// 等待限频时取消，不再发出请求
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := first.Do(ctx, "Buy", func(int) error { calls++; return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, calls)
+}
+
+func TestRateLimitConfig_NewRateLimiter(t *testing.T) {
+	limiter, err := RateLimitConfig{}.NewRateLimiter()
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+
+	limiter, err = RateLimitConfig{RequestsPerSecond: 10, Burst: 20}.NewRateLimiter()
+	require.NoError(t, err)
+	assert.NotNil(t, limiter)
+
+	_, err = RateLimitConfig{RequestsPerSecond: -1}.NewRateLimiter()
+	assert.Error(t, err)
+	_, err = RateLimitConfig{RequestsPerSecond: 10}.NewRateLimiter()
+	assert.Error(t, err)
+}
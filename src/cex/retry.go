@@ -0,0 +1,186 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryConfig 交易所 REST 请求重试配置（各交易所配置段中的 Retry）
+type RetryConfig struct {
+	MaxRetries       int     `json:"max_retries"`        // 失败后最多重试次数，0 表示不重试
+	InitialBackoffMs int     `json:"initial_backoff_ms"` // 首次重试前的等待时间（毫秒）
+	MaxBackoffMs     int     `json:"max_backoff_ms"`     // 单次等待时间上限（毫秒）
+	Multiplier       float64 `json:"multiplier"`         // 每次重试等待时间的倍数
+	Jitter           float64 `json:"jitter"`             // 随机抖动比例（0.2 表示等待时间在 ±20% 内浮动）
+}
+
+// DefaultRetryConfig 默认重试配置：最多重试3次，等待 200ms、400ms、800ms（±20%）
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:       3,
+		InitialBackoffMs: 200,
+		MaxBackoffMs:     5000,
+		Multiplier:       2,
+		Jitter:           0.2,
+	}
+}
+
+// Validate 检查配置
+func (c RetryConfig) Validate() error {
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("MaxRetries must be non-negative, got %d", c.MaxRetries)
+	}
+	if c.MaxRetries == 0 {
+		return nil
+	}
+	if c.InitialBackoffMs <= 0 {
+		return fmt.Errorf("InitialBackoffMs must be positive, got %d", c.InitialBackoffMs)
+	}
+	if c.MaxBackoffMs < c.InitialBackoffMs {
+		return fmt.Errorf("MaxBackoffMs (%d) must not be less than InitialBackoffMs (%d)", c.MaxBackoffMs, c.InitialBackoffMs)
+	}
+	if c.Multiplier < 1 {
+		return fmt.Errorf("Multiplier must be at least 1, got %v", c.Multiplier)
+	}
+	if c.Jitter < 0 || c.Jitter >= 1 {
+		return fmt.Errorf("jitter must be in [0, 1), got %v", c.Jitter)
+	}
+	return nil
+}
+
+// RetryClassifier 判断交易所返回的业务错误是否可重试（网络层临时错误已统一按可重试处理）
+type RetryClassifier func(err error) bool
+
+// Retryer 按指数退避和随机抖动重试交易所请求
+type Retryer struct {
+	config      RetryConfig
+	isRetryable RetryClassifier
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	sleep   func(ctx context.Context, d time.Duration) error
+	limiter *RateLimiter // 为空时不限频
+}
+
+// NewRetryer 创建重试器，isRetryable 为交易所的错误分类
+func NewRetryer(config RetryConfig, isRetryable RetryClassifier) *Retryer {
+	return &Retryer{
+		config:      config,
+		isRetryable: isRetryable,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		sleep:       sleepContext,
+	}
+}
+
+// SetRateLimiter 设置限频器，每次请求（包括重试）前取得令牌
+func (r *Retryer) SetRateLimiter(limiter *RateLimiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limiter = limiter
+}
+
+// Do 执行 fn，可重试的错误按退避时间重试；attempt 从 0 开始，
+// 下单等非幂等操作可在 attempt > 0 时先按客户端订单ID确认上次请求是否已生效
+func (r *Retryer) Do(ctx context.Context, operation string, fn func(attempt int) error) error {
+	r.mu.Lock()
+	limiter := r.limiter
+	r.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return fmt.Errorf("%s cancelled while waiting for rate limit: %w", operation, err)
+			}
+		}
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if attempt >= r.config.MaxRetries || !r.retryable(ctx, err) {
+			if attempt > 0 {
+				return fmt.Errorf("%s failed after %d attempts: %w", operation, attempt+1, err)
+			}
+			return err
+		}
+
+		delay := r.Backoff(attempt + 1)
+		fmt.Printf("⚠️ %s failed (%v), retrying in %s (%d/%d)...\n", operation, err, delay, attempt+1, r.config.MaxRetries)
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			return fmt.Errorf("%s cancelled while retrying: %w", operation, err)
+		}
+	}
+}
+
+// Backoff 第 retry 次重试（从1开始）前的等待时间
+func (r *Retryer) Backoff(retry int) time.Duration {
+	backoff := float64(r.config.InitialBackoffMs) * math.Pow(r.config.Multiplier, float64(retry-1))
+	if maxBackoff := float64(r.config.MaxBackoffMs); backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	if r.config.Jitter > 0 {
+		r.mu.Lock()
+		backoff *= 1 + r.config.Jitter*(2*r.rand.Float64()-1)
+		r.mu.Unlock()
+	}
+	return time.Duration(backoff * float64(time.Millisecond))
+}
+
+// retryable 判断错误是否可重试：调用方已取消时不重试
+func (r *Retryer) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if IsTransientNetworkError(err) {
+		return true
+	}
+	return r.isRetryable != nil && r.isRetryable(err)
+}
+
+// IsTransientNetworkError 网络层临时错误（超时、连接被重置或拒绝、连接意外关闭）
+func IsTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var (
+	clientOrderIDMu   sync.Mutex
+	clientOrderIDRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// NewClientOrderID 生成客户端订单ID（不超过36个字符）。下单重试时复用同一ID，
+// 交易所据此去重，也可据此查询上次请求是否已生效
+func NewClientOrderID() string {
+	clientOrderIDMu.Lock()
+	suffix := clientOrderIDRand.Int63n(36 * 36 * 36 * 36)
+	clientOrderIDMu.Unlock()
+	return "tb" + strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatInt(suffix, 36)
+}
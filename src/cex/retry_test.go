@@ -0,0 +1,97 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRateLimited = errors.New("rate limited")
+
+// newTestRetryer 创建记录等待时间、不实际等待的重试器
+func newTestRetryer(config RetryConfig, delays *[]time.Duration) *Retryer {
+	retryer := NewRetryer(config, func(err error) bool { return errors.Is(err, errRateLimited) })
+	retryer.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return ctx.Err()
+	}
+	return retryer
+}
+
+func TestRetryer_RetriesWithExponentialBackoff(t *testing.T) {
+	var delays []time.Duration
+	config := RetryConfig{MaxRetries: 3, InitialBackoffMs: 100, MaxBackoffMs: 300, Multiplier: 2}
+	retryer := newTestRetryer(config, &delays)
+
+	var attempts []int
+	err := retryer.Do(context.Background(), "GetKlines", func(attempt int) error {
+		attempts = append(attempts, attempt)
+		if attempt < 3 {
+			return errRateLimited
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, attempts)
+	// 100ms、200ms，第三次被上限截断为 300ms
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, delays)
+}
+
+func TestRetryer_StopsOnFatalErrorAndExhaustion(t *testing.T) {
+	var delays []time.Duration
+	retryer := newTestRetryer(DefaultRetryConfig(), &delays)
+
+	// 不可重试的业务错误直接返回
+	fatal := errors.New("insufficient balance")
+	calls := 0
+	err := retryer.Do(context.Background(), "Buy", func(int) error { calls++; return fatal })
+	assert.Same(t, fatal, err)
+	assert.Equal(t, 1, calls)
+
+	// 网络错误重试到上限
+	calls = 0
+	err = retryer.Do(context.Background(), "Buy", func(int) error { calls++; return syscall.ECONNRESET })
+	assert.ErrorIs(t, err, syscall.ECONNRESET)
+	assert.Equal(t, 4, calls)
+
+	// 调用方已取消时不再重试
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = retryer.Do(ctx, "Buy", func(int) error { calls++; return io.ErrUnexpectedEOF })
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryer_BackoffJitter(t *testing.T) {
+	retryer := NewRetryer(DefaultRetryConfig(), nil)
+	for i := 0; i < 100; i++ {
+		delay := retryer.Backoff(1)
+		assert.GreaterOrEqual(t, delay, 160*time.Millisecond)
+		assert.LessOrEqual(t, delay, 240*time.Millisecond)
+	}
+}
+
+func TestRetryConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultRetryConfig().Validate())
+	assert.NoError(t, RetryConfig{}.Validate())
+	assert.Error(t, RetryConfig{MaxRetries: 2, InitialBackoffMs: 100, MaxBackoffMs: 50, Multiplier: 2}.Validate())
+	assert.Error(t, RetryConfig{MaxRetries: 2, InitialBackoffMs: 100, MaxBackoffMs: 500, Multiplier: 2, Jitter: 1}.Validate())
+}
+
+func TestNewClientOrderID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := NewClientOrderID()
+		assert.LessOrEqual(t, len(id), 36)
+		assert.Regexp(t, `^[a-z0-9]+$`, id)
+		assert.False(t, seen[id])
+		seen[id] = true
+	}
+}
@@ -0,0 +1,89 @@
+package cex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tradingbot/src/precision"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrBelowMinQuantity 按步长取整后数量低于交易所最小下单量
+	ErrBelowMinQuantity = errors.New("order quantity below exchange minimum")
+
+	// ErrBelowMinNotional 订单金额低于交易所最小下单金额
+	ErrBelowMinNotional = errors.New("order notional below exchange minimum")
+)
+
+// SymbolFilters 交易对下单规则（币安 exchangeInfo 的 LOT_SIZE、PRICE_FILTER、MIN_NOTIONAL），0 表示不限制
+type SymbolFilters struct {
+	Symbol      string          `json:"symbol"`
+	MinQty      decimal.Decimal `json:"min_qty"`      // 最小下单数量
+	MaxQty      decimal.Decimal `json:"max_qty"`      // 最大下单数量
+	StepSize    decimal.Decimal `json:"step_size"`    // 数量步长
+	MinPrice    decimal.Decimal `json:"min_price"`    // 最低价格
+	MaxPrice    decimal.Decimal `json:"max_price"`    // 最高价格
+	TickSize    decimal.Decimal `json:"tick_size"`    // 价格最小变动单位
+	MinNotional decimal.Decimal `json:"min_notional"` // 最小下单金额（数量 × 价格）
+}
+
+// SymbolFilterProvider 支持查询交易对下单规则的交易所客户端（可选能力，通过类型断言使用）
+type SymbolFilterProvider interface {
+	// GetSymbolFilters 获取交易对的下单规则
+	GetSymbolFilters(ctx context.Context, pair TradingPair) (*SymbolFilters, error)
+}
+
+// RoundQuantity 数量按步长向下取整，超过最大下单量时取最大下单量
+func (f *SymbolFilters) RoundQuantity(quantity decimal.Decimal) decimal.Decimal {
+	if f.MaxQty.IsPositive() && quantity.GreaterThan(f.MaxQty) {
+		quantity = f.MaxQty
+	}
+	return precision.FloorToStep(quantity, f.StepSize)
+}
+
+// RoundPrice 价格按最小变动单位取整：买单向下、卖单向上，保证不比原价格更差
+func (f *SymbolFilters) RoundPrice(price decimal.Decimal, side OrderSide) decimal.Decimal {
+	if side == OrderSideSell {
+		price = precision.CeilToStep(price, f.TickSize)
+	} else {
+		price = precision.FloorToStep(price, f.TickSize)
+	}
+	if f.MinPrice.IsPositive() && price.LessThan(f.MinPrice) {
+		price = f.MinPrice
+	}
+	if f.MaxPrice.IsPositive() && price.GreaterThan(f.MaxPrice) {
+		price = f.MaxPrice
+	}
+	return price
+}
+
+// Check 检查已取整的订单是否满足最小下单量和最小下单金额
+func (f *SymbolFilters) Check(quantity, price decimal.Decimal) error {
+	if !quantity.IsPositive() || (f.MinQty.IsPositive() && quantity.LessThan(f.MinQty)) {
+		return fmt.Errorf("%w: %s quantity %s < %s", ErrBelowMinQuantity, f.Symbol, quantity.String(), f.MinQty.String())
+	}
+	if notional := quantity.Mul(price); f.MinNotional.IsPositive() && notional.LessThan(f.MinNotional) {
+		return fmt.Errorf("%w: %s notional %s < %s", ErrBelowMinNotional, f.Symbol, notional.String(), f.MinNotional.String())
+	}
+	return nil
+}
+
+// SymbolStatusTrading 交易对正常交易状态
+const SymbolStatusTrading = "TRADING"
+
+// ExchangeSymbol 交易所上架的交易对及其下单规则
+type ExchangeSymbol struct {
+	BaseAsset  string        `json:"base_asset"`
+	QuoteAsset string        `json:"quote_asset"`
+	Status     string        `json:"status"` // 交易状态，正常交易为 SymbolStatusTrading
+	Filters    SymbolFilters `json:"filters"`
+}
+
+// ExchangeInfoProvider 支持获取全部现货交易对的交易所客户端（可选能力，通过类型断言使用）
+type ExchangeInfoProvider interface {
+	// GetExchangeSymbols 获取交易所全部现货交易对及其下单规则
+	GetExchangeSymbols(ctx context.Context) ([]*ExchangeSymbol, error)
+}
@@ -0,0 +1,44 @@
+package cex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolFilters_Round(t *testing.T) {
+	filters := &SymbolFilters{
+		Symbol:   "BTCUSDT",
+		MinQty:   decimal.RequireFromString("0.00001"),
+		MaxQty:   decimal.NewFromInt(9000),
+		StepSize: decimal.RequireFromString("0.00001"),
+		TickSize: decimal.RequireFromString("0.01"),
+	}
+
+	// 数量向下取整到步长，超过上限取上限
+	assert.Equal(t, "0.12345", filters.RoundQuantity(decimal.RequireFromString("0.123459")).String())
+	assert.Equal(t, "9000", filters.RoundQuantity(decimal.NewFromInt(10000)).String())
+
+	// 买单价格向下、卖单价格向上取整
+	price := decimal.RequireFromString("43210.123")
+	assert.Equal(t, "43210.12", filters.RoundPrice(price, OrderSideBuy).String())
+	assert.Equal(t, "43210.13", filters.RoundPrice(price, OrderSideSell).String())
+
+	// 未配置步长时原样返回
+	assert.Equal(t, "1.23456789", (&SymbolFilters{}).RoundQuantity(decimal.RequireFromString("1.23456789")).String())
+}
+
+func TestSymbolFilters_Check(t *testing.T) {
+	filters := &SymbolFilters{
+		Symbol:      "BTCUSDT",
+		MinQty:      decimal.RequireFromString("0.00001"),
+		MinNotional: decimal.NewFromInt(5),
+	}
+
+	assert.NoError(t, filters.Check(decimal.RequireFromString("0.0001"), decimal.NewFromInt(60000)))
+	assert.True(t, errors.Is(filters.Check(decimal.RequireFromString("0.00005"), decimal.NewFromInt(60000)), ErrBelowMinNotional))
+	assert.True(t, errors.Is(filters.Check(decimal.RequireFromString("0.000001"), decimal.NewFromInt(60000)), ErrBelowMinQuantity))
+	assert.True(t, errors.Is(filters.Check(decimal.Zero, decimal.NewFromInt(60000)), ErrBelowMinQuantity))
+}
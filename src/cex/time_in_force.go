@@ -0,0 +1,39 @@
+package cex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimeInForce 限价单有效方式
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "GTC" // 一直有效直到成交或撤单
+	TimeInForceIOC TimeInForce = "IOC" // 立即成交能成交的部分，剩余撤销
+	TimeInForceFOK TimeInForce = "FOK" // 立即全部成交，否则整单撤销
+	TimeInForceGTD TimeInForce = "GTD" // 有效至指定时间，到期撤销
+)
+
+// ParseTimeInForce 解析有效方式（不区分大小写），空字符串返回空值表示使用默认
+func ParseTimeInForce(value string) (TimeInForce, error) {
+	tif := TimeInForce(strings.ToUpper(strings.TrimSpace(value)))
+	switch tif {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForceGTD:
+		return tif, nil
+	}
+	return "", fmt.Errorf("unknown time in force %q (supported: GTC, IOC, FOK, GTD)", value)
+}
+
+// Immediate 是否只在下单时立即撮合（IOC/FOK），未成交部分不挂在盘口
+func (t TimeInForce) Immediate() bool {
+	return t == TimeInForceIOC || t == TimeInForceFOK
+}
+
+// ExchangeValue 下单时传给交易所的有效方式：现货不支持 GTD，按 GTC 下单（过期时间只记录在挂单上）；空值为 GTC
+func (t TimeInForce) ExchangeValue() TimeInForce {
+	if t == TimeInForceIOC || t == TimeInForceFOK {
+		return t
+	}
+	return TimeInForceGTC
+}
@@ -0,0 +1,27 @@
+package cex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeInForce(t *testing.T) {
+	tif, err := ParseTimeInForce(" ioc ")
+	require.NoError(t, err)
+	assert.Equal(t, TimeInForceIOC, tif)
+	assert.True(t, tif.Immediate())
+
+	tif, err = ParseTimeInForce("")
+	require.NoError(t, err)
+	assert.Equal(t, TimeInForceGTC, tif.ExchangeValue())
+	assert.False(t, tif.Immediate())
+
+	// 现货不支持 GTD，按 GTC 下单
+	assert.Equal(t, TimeInForceGTC, TimeInForceGTD.ExchangeValue())
+	assert.Equal(t, TimeInForceFOK, TimeInForceFOK.ExchangeValue())
+
+	_, err = ParseTimeInForce("day")
+	assert.Error(t, err)
+}
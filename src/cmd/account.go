@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterAccountCmd 注册账户概览命令
+func RegisterAccountCmd() {
+	var cexName string
+	var pairs string
+	var quote string
+	fills := 10
+
+	cmd.RegisterCmd("account", "show account balances, open orders and recent fills on the configured exchange (verify API keys before going live)", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange (default: binance)")
+		args.String(&pairs, "pairs", "comma-separated BASE/QUOTE symbols for open orders and fills (default: non-zero balance assets against -quote)")
+		args.String(&quote, "quote", "quote asset used to derive symbols from balances (default: USDT)")
+		args.Int(&fills, "fills", "recent fills per symbol, 0 to skip")
+		args.Parse()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+		if quote == "" {
+			quote = "USDT"
+		}
+		if fills < 0 {
+			fmt.Println("❌ Error: -fills must be non-negative")
+			os.Exit(1)
+		}
+
+		if err := runAccount(cexName, pairs, strings.ToUpper(quote), fills); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// runAccount 连接交易所，输出余额、挂单和最近成交
+func runAccount(cexName, pairs, quote string, fills int) error {
+	var tradingPairs []cex.TradingPair
+	if pairs != "" {
+		var err error
+		if tradingPairs, err = trading.ParseTradingPairs(strings.Split(pairs, ",")); err != nil {
+			return err
+		}
+	}
+
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	overview, err := trading.FetchAccountOverview(context.Background(), client, tradingPairs, quote, fills)
+	if err != nil {
+		return err
+	}
+	trading.WriteAccountOverview(os.Stdout, overview)
+	return nil
+}
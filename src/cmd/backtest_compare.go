@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/trading"
+)
+
+const (
+	compareChartWidth  = 60 // 资金曲线图宽度（刻度数）
+	compareChartHeight = 12 // 资金曲线图高度（行数）
+)
+
+// compareBacktests 对比两次回测（参数为回测记录ID或 -result-out 写出的结果文件），a 为基准
+func compareBacktests(ctx context.Context, cexName, a, b string) error {
+	loader := &backtestResultLoader{cexName: cexName}
+	previous, err := loader.load(ctx, a)
+	if err != nil {
+		return err
+	}
+	current, err := loader.load(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	comparison := trading.CompareBacktestResults(previous, current)
+	printBacktestComparison(previous, current, comparison)
+	return nil
+}
+
+// backtestResultLoader 按参数读取结果文件或数据库中的回测记录（需要时才连接数据库）
+type backtestResultLoader struct {
+	cexName string
+	store   trading.BacktestRunStore
+}
+
+// load 参数是已存在的文件或以 .json 结尾时读取结果文件，否则按回测记录ID查询数据库
+func (l *backtestResultLoader) load(ctx context.Context, ref string) (*trading.BacktestResult, error) {
+	if _, err := os.Stat(ref); err == nil || strings.HasSuffix(strings.ToLower(ref), ".json") {
+		return trading.LoadBacktestResultFile(ref)
+	}
+
+	if l.store == nil {
+		db, err := openBacktestDatabase(l.cexName)
+		if err != nil {
+			return nil, err
+		}
+		l.store = db
+	}
+	return trading.LoadBacktestResult(ctx, l.store, ref)
+}
+
+// printBacktestComparison 打印两次回测的并排对比、参数变化和合并的资金曲线
+func printBacktestComparison(previous, current *trading.BacktestResult, comparison *trading.BacktestComparison) {
+	fmt.Println("============================================================")
+	fmt.Println("⚖️  BACKTEST COMPARISON")
+	fmt.Println("============================================================")
+	printComparedRun("A", previous)
+	printComparedRun("B", current)
+	for _, warning := range comparison.Warnings {
+		fmt.Printf("⚠️ %s\n", warning)
+	}
+
+	fmt.Println("\n⚙️  PARAM CHANGES (A → B)")
+	fmt.Println("------------------------------")
+	if len(comparison.Params) == 0 {
+		fmt.Println("(same params)")
+	}
+	for _, change := range comparison.Params {
+		fmt.Printf("%s: %s → %s\n", change.Name, formatParamValue(change.Previous), formatParamValue(change.Current))
+	}
+
+	fmt.Println("\n📈 METRICS")
+	fmt.Println(strings.Repeat("-", 62))
+	fmt.Printf("%-16s %14s %14s %14s\n", "Metric", "A", "B", "Δ")
+	fmt.Println(strings.Repeat("-", 62))
+	for _, d := range comparison.Metrics {
+		delta := "0"
+		if d.Delta != 0 {
+			delta = fmt.Sprintf("%+.2f", d.Delta)
+		}
+		switch d.Improvement() {
+		case 1:
+			delta = "🟢 " + delta
+		case -1:
+			delta = "🔴 " + delta
+		}
+		fmt.Printf("%-16s %14s %14s %14s\n", d.Name, fmt.Sprintf("%.2f%s", d.Previous, d.Unit), fmt.Sprintf("%.2f%s", d.Current, d.Unit), delta)
+	}
+
+	if improved := comparison.Improved(); len(improved) > 0 {
+		fmt.Printf("\n🟢 Improved: %s\n", strings.Join(improved, ", "))
+	}
+	if worsened := comparison.Worsened(); len(worsened) > 0 {
+		fmt.Printf("🔴 Worsened: %s\n", strings.Join(worsened, ", "))
+	}
+
+	fmt.Println("\n📉 EQUITY CURVES (return %, A = baseline, B = compared, * = both)")
+	for _, line := range renderEquityChart(previous.Equity, current.Equity) {
+		fmt.Println(line)
+	}
+	fmt.Println("============================================================")
+}
+
+// printComparedRun 打印参与对比的一次回测
+func printComparedRun(label string, result *trading.BacktestResult) {
+	run := result.Run
+	id := run.ID
+	if id == "" {
+		id = "(result file)"
+	}
+	fmt.Printf("%s: %s  %s %s %s %s~%s\n", label, id, run.StrategyName, run.Symbol, run.Timeframe,
+		run.StartTime.Format("2006-01-02"), run.EndTime.Format("2006-01-02"))
+}
+
+// formatParamValue 格式化参数值，未设置时显示 -
+func formatParamValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// renderEquityChart 把两条资金曲线（按收益率）画在同一张字符图上
+func renderEquityChart(a, b []engine.EquityPoint) []string {
+	times, returnsA, returnsB := trading.MergeEquityCurves(a, b, compareChartWidth)
+	if len(times) == 0 {
+		return []string{"(no equity data)"}
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, series := range [][]float64{returnsA, returnsB} {
+		for _, value := range series {
+			if !math.IsNaN(value) {
+				low, high = math.Min(low, value), math.Max(high, value)
+			}
+		}
+	}
+	if math.IsInf(low, 1) {
+		return []string{"(no equity data)"}
+	}
+	if high == low {
+		high, low = high+1, low-1
+	}
+
+	grid := make([][]rune, compareChartHeight)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", len(times)))
+	}
+	plot := func(series []float64, mark rune) {
+		for col, value := range series {
+			if math.IsNaN(value) {
+				continue
+			}
+			row := int(math.Round((high - value) / (high - low) * float64(compareChartHeight-1)))
+			if grid[row][col] != ' ' && grid[row][col] != mark {
+				grid[row][col] = '*'
+			} else {
+				grid[row][col] = mark
+			}
+		}
+	}
+	plot(returnsA, 'A')
+	plot(returnsB, 'B')
+
+	lines := make([]string, 0, compareChartHeight+2)
+	for i, row := range grid {
+		value := high - (high-low)*float64(i)/float64(compareChartHeight-1)
+		lines = append(lines, fmt.Sprintf("%8.2f%% │%s", value, string(row)))
+	}
+	lines = append(lines, fmt.Sprintf("%10s└%s", "", strings.Repeat("─", len(times))))
+	start, end := times[0].Format("2006-01-02"), times[len(times)-1].Format("2006-01-02")
+	lines = append(lines, fmt.Sprintf("%11s%s%s%s", "", start, strings.Repeat(" ", max(1, len(times)-len(start)-len(end))), end))
+	return lines
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/precision"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterBacktestsCmd 注册回测记录查询命令
+func RegisterBacktestsCmd() {
+	var cexName string
+	var base string
+	var quote string
+	var limit int
+
+	cmd.RegisterCmd("backtests", "query saved backtest runs (list | show <id> | compare <a> <b>)", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange whose database stores the runs (default: binance)")
+		args.String(&base, "base", "filter by base currency (list only)")
+		args.String(&quote, "quote", "filter by quote currency (list only)")
+		args.Int(&limit, "limit", "max number of runs to list (default: 20)")
+		args.Parse()
+
+		// 支持子命令后继续带参数: backtests list -limit 5
+		rest := args.FlagSet.Args()
+		if len(rest) == 0 {
+			printBacktestsUsage()
+			os.Exit(1)
+		}
+		subCmd := rest[0]
+		if err := args.FlagSet.Parse(rest[1:]); err != nil {
+			os.Exit(1)
+		}
+		rest = args.FlagSet.Args()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+		if limit <= 0 {
+			limit = 20
+		}
+
+		ctx := context.Background()
+
+		// compare 的两个参数都是结果文件时不需要数据库
+		if subCmd == "compare" {
+			if len(rest) != 2 {
+				fmt.Printf("❌ Error: compare requires two backtest run ids or result files\n")
+				printBacktestsUsage()
+				os.Exit(1)
+			}
+			if err := compareBacktests(ctx, cexName, rest[0], rest[1]); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		db, err := openBacktestDatabase(cexName)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		switch subCmd {
+		case "list":
+			symbol := ""
+			if base != "" && quote != "" {
+				symbol = trading.DatabaseSymbol(trading.CreateTradingPair(base, quote))
+			}
+			err = listBacktestRuns(ctx, db, symbol, limit)
+		case "show":
+			if len(rest) == 0 {
+				fmt.Printf("❌ Error: backtest run id is required\n")
+				printBacktestsUsage()
+				os.Exit(1)
+			}
+			err = showBacktestRun(ctx, db, rest[0])
+		default:
+			fmt.Printf("❌ Error: unknown subcommand %s\n", subCmd)
+			printBacktestsUsage()
+			os.Exit(1)
+		}
+
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printBacktestsUsage 打印回测记录命令用法
+func printBacktestsUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot backtests list [-base BASE -quote QUOTE] [-limit N]\n")
+	fmt.Printf("          ./bin/tradingbot backtests show <id>\n")
+	fmt.Printf("          ./bin/tradingbot backtests compare <id|result.json> <id|result.json>\n")
+}
+
+// openBacktestDatabase 通过CEX客户端获取回测数据库连接
+func openBacktestDatabase(cexName string) (*database.PostgresDB, error) {
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	return trading.GetPostgresDB(client)
+}
+
+// listBacktestRuns 列出历史回测记录
+func listBacktestRuns(ctx context.Context, db *database.PostgresDB, symbol string, limit int) error {
+	runs, err := db.ListBacktestRuns(ctx, symbol, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("📭 No saved backtest runs")
+		return nil
+	}
+
+	fmt.Printf("📚 Saved Backtest Runs: %d\n", len(runs))
+	fmt.Println(strings.Repeat("=", 130))
+	fmt.Printf("%-36s  %-16s  %-10s  %-4s  %-21s  %9s  %8s  %6s  %7s\n",
+		"ID", "Created", "Symbol", "TF", "Period", "Return%", "MaxDD%", "Trades", "Win%")
+	fmt.Println(strings.Repeat("=", 130))
+
+	for _, run := range runs {
+		fmt.Printf("%-36s  %-16s  %-10s  %-4s  %-21s  %9.2f  %8.2f  %6d  %7.2f\n",
+			run.ID,
+			run.CreatedAt.Format("2006-01-02 15:04"),
+			run.Symbol,
+			run.Timeframe,
+			run.StartTime.Format("2006-01-02")+"~"+run.EndTime.Format("2006-01-02"),
+			run.TotalReturn.InexactFloat64()*100,
+			run.MaxDrawdown.InexactFloat64(),
+			run.TotalTrades,
+			run.WinRate.InexactFloat64()*100,
+		)
+	}
+
+	return nil
+}
+
+// showBacktestRun 显示单次回测详情和交易记录
+func showBacktestRun(ctx context.Context, db *database.PostgresDB, id string) error {
+	run, err := db.GetBacktestRun(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	trades, err := db.GetTrades(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("============================================================")
+	fmt.Printf("📊 BACKTEST RUN %s\n", run.ID)
+	fmt.Println("============================================================")
+	fmt.Printf("Name: %s\n", run.Name)
+	fmt.Printf("Strategy: %s\n", run.StrategyName)
+	fmt.Printf("Symbol: %s\n", run.Symbol)
+	fmt.Printf("Timeframe: %s\n", run.Timeframe)
+	fmt.Printf("Period: %s ~ %s\n", run.StartTime.Format("2006-01-02 15:04"), run.EndTime.Format("2006-01-02 15:04"))
+	fmt.Printf("Status: %s\n", run.Status)
+	fmt.Printf("Created: %s\n", run.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	if len(run.StrategyParams) > 0 {
+		fmt.Println("\n⚙️  STRATEGY PARAMS")
+		fmt.Println("------------------------------")
+		keys := make([]string, 0, len(run.StrategyParams))
+		for key := range run.StrategyParams {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s: %v\n", key, run.StrategyParams[key])
+		}
+	}
+
+	fmt.Println("\n📈 PERFORMANCE METRICS")
+	fmt.Println("------------------------------")
+	fmt.Printf("Initial Capital: $%.2f\n", run.InitialCapital.InexactFloat64())
+	fmt.Printf("Final Capital: $%.2f\n", run.FinalCapital.InexactFloat64())
+	fmt.Printf("Total Return: %.2f%%\n", run.TotalReturn.InexactFloat64()*100)
+	fmt.Printf("Max Drawdown: %.2f%%\n", run.MaxDrawdown.InexactFloat64())
+	fmt.Printf("Sharpe Ratio: %.2f\n", run.SharpeRatio.InexactFloat64())
+	fmt.Printf("Trades: %d (win %d / loss %d, win rate %.2f%%)\n",
+		run.TotalTrades, run.WinningTrades, run.LosingTrades, run.WinRate.InexactFloat64()*100)
+	fmt.Printf("Total Commission: $%.2f\n", run.TotalCommission.InexactFloat64())
+
+	if len(trades) > 0 {
+		fmt.Printf("\n📋 TRADES: %d\n", len(trades))
+		fmt.Println(strings.Repeat("=", 100))
+		fmt.Println("Time              Side      Quantity          Price        P&L   Reason")
+		fmt.Println(strings.Repeat("=", 100))
+		for _, trade := range trades {
+			pnlStr := "-"
+			if trade.Side == "SELL" {
+				pnlStr = fmt.Sprintf("$%.2f", trade.PnL.InexactFloat64())
+			}
+			fmt.Printf("%s  %4s  %14s  %13s  %9s   %s\n",
+				trade.Timestamp.Format("2006-01-02 15:04"),
+				trade.Side,
+				precision.FormatSignificant(trade.Quantity, 8),
+				precision.FormatSignificant(trade.Price, 6),
+				pnlStr,
+				trade.Reason,
+			)
+		}
+	}
+
+	fmt.Println("\n============================================================")
+	return nil
+}
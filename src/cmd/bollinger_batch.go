@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/optimizer"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-log/log"
+	"github.com/xpwu/go-log/log/level"
+)
+
+// runBollingerBatch 用同一组策略参数并发回测多个交易对，按优化目标排名输出汇总表
+func runBollingerBatch(symbolsFlag, symbolsFile, quote, timeframe, cexName, startDate, endDate string, initialCapital float64, baseParams *strategy.BollingerBandsParams,
+	objectiveStr string, workers int, maxCorrelation float64) error {
+
+	if objectiveStr == "" {
+		objectiveStr = string(optimizer.ObjectiveSharpe)
+	}
+	objective, err := optimizer.ParseObjective(objectiveStr)
+	if err != nil {
+		return err
+	}
+
+	// 交易对来源：-symbols-file 优先，其次 -symbols，最后配置 Symbols
+	symbols := trading.TradingConfigValue.Symbols
+	if symbolsFlag != "" {
+		symbols = strings.Split(symbolsFlag, ",")
+	}
+	if symbolsFile != "" {
+		if symbols, err = trading.LoadSymbolsFile(symbolsFile); err != nil {
+			return err
+		}
+	}
+	pairs, err := trading.ParseBatchSymbols(symbols, quote)
+	if err != nil {
+		return err
+	}
+
+	// 所有交易对共用一个请求限频器（沿用多机器人的限频配置）
+	limiter, err := trading.TradingConfigValue.Bots.RateLimit.NewRateLimiter()
+	if err != nil {
+		return fmt.Errorf("invalid rate limit config: %w", err)
+	}
+
+	names := make([]string, len(pairs))
+	for i, pair := range pairs {
+		names[i] = pair.String()
+	}
+
+	fmt.Println("📚 Bollinger Bands Batch Backtest")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Symbols: %s (%d)\n", strings.Join(names, ", "), len(pairs))
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("🏢 Exchange: %s\n", cexName)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f (per symbol)\n", initialCapital)
+	fmt.Printf("🎯 Ranking: %s\n", objective)
+	if trading.TradingConfigValue.SaveBacktest {
+		fmt.Println("💾 Saving every run to database")
+	}
+	printFillModelHeader()
+	printPositionSizingHeader()
+
+	// Ctrl+C 取消未开始的回测
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Cancelling batch backtest...")
+		cancel()
+	}()
+
+	// 并发回测时引擎日志过多，只保留警告以上
+	log.SetLevel(level.WARNING)
+	defer log.SetLevel(level.DEBUG)
+
+	fmt.Printf("🚀 Running %d backtests...\n", len(pairs))
+	begin := time.Now()
+	results := trading.RunBatchBacktest(ctx, pairs, workers, func(ctx context.Context, pair cex.TradingPair) (*trading.BacktestStatistics, []engine.EquityPoint, error) {
+		params := *baseParams // 每个交易对使用独立的参数副本
+		return trading.BacktestSymbol(ctx, pair, timeframe, cexName, startDate, endDate, initialCapital, &params, limiter)
+	})
+	fmt.Printf("✅ Batch completed in %s\n", time.Since(begin).Round(time.Millisecond))
+
+	trading.RankBatchResults(results, objective.Score)
+	printBatchResults(results, objective)
+
+	// 按排名计算交易对之间的相关性，提示近似重复的交易对
+	if succeeded := countSucceeded(results); succeeded >= 2 {
+		risk, err := trading.BatchPortfolioRisk(results, timeframes.Timeframe(timeframe), maxCorrelation)
+		if err != nil {
+			fmt.Printf("\n⚠️ Portfolio risk unavailable: %v\n", err)
+		} else {
+			printPortfolioRisk(risk, maxCorrelation)
+		}
+	}
+	return nil
+}
+
+// countSucceeded 成功回测的交易对数量
+func countSucceeded(results []*trading.BatchResult) int {
+	succeeded := 0
+	for _, r := range results {
+		if r.Err == nil {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// printPortfolioRisk 打印相关系数矩阵、等权组合的波动率和分散化比率，以及近似重复的交易对
+func printPortfolioRisk(risk *trading.PortfolioRisk, maxCorrelation float64) {
+	if maxCorrelation <= 0 {
+		maxCorrelation = trading.DefaultMaxCorrelation
+	}
+
+	fmt.Printf("\n🔗 CORRELATION (per-bar strategy returns, %d bars)\n", risk.Observations)
+	fmt.Println(strings.Repeat("=", 110))
+	fmt.Printf("%-14s", "")
+	for i := range risk.Symbols {
+		fmt.Printf("  %6d", i+1)
+	}
+	fmt.Printf("  %8s\n", "Vol%")
+	for i, symbol := range risk.Symbols {
+		fmt.Printf("%-14s", fmt.Sprintf("%d %s", i+1, symbol))
+		for _, correlation := range risk.Correlation[i] {
+			fmt.Printf("  %6.2f", correlation)
+		}
+		fmt.Printf("  %8.2f\n", risk.Volatility[i]*100)
+	}
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("📉 Equal-weight Portfolio Volatility: %.2f%% (annualized)\n", risk.PortfolioVolatility*100)
+	fmt.Printf("🧩 Diversification Ratio: %.2f (1.00 = no diversification)\n", risk.DiversificationRatio)
+
+	if len(risk.Correlated) == 0 {
+		fmt.Printf("✅ No symbol pairs correlated at or above %.2f\n", maxCorrelation)
+		return
+	}
+	fmt.Printf("\n⚠️ Near-duplicate pairs (correlation >= %.2f), allocating to both adds little diversification:\n", maxCorrelation)
+	for _, pair := range risk.Correlated {
+		fmt.Printf("   %s ~ %s: %.2f (prefer %s)\n", pair.First, pair.Second, pair.Correlation, pair.First)
+	}
+	fmt.Printf("💡 Suggested symbols: %s\n", strings.Join(risk.Selected, ", "))
+}
+
+// printBatchResults 打印按得分排名的各交易对回测结果和汇总
+func printBatchResults(results []*trading.BatchResult, objective optimizer.Objective) {
+	fmt.Printf("\n🏆 SYMBOL RANKING (by %s)\n", objective)
+	fmt.Println(strings.Repeat("=", 110))
+	fmt.Printf("%-4s  %-14s  %10s  %9s  %8s  %8s  %6s  %7s  %s\n", "#", "Symbol", "Score", "Return%", "Sharpe", "MaxDD%", "Trades", "Win%", "Run ID")
+	fmt.Println(strings.Repeat("=", 110))
+
+	succeeded, profitable := 0, 0
+	totalReturn := 0.0
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		succeeded++
+
+		winRate := 0.0
+		if r.Stats.TotalTrades > 0 {
+			winRate = float64(r.Stats.WinningTrades) / float64(r.Stats.TotalTrades) * 100
+		}
+		returnPercent := r.Stats.TotalReturn.InexactFloat64() * 100
+		totalReturn += returnPercent
+		if r.Stats.TotalReturn.IsPositive() {
+			profitable++
+		}
+
+		fmt.Printf("%-4d  %-14s  %10.4f  %9.2f  %8.2f  %8.2f  %6d  %7.2f  %s\n",
+			succeeded,
+			r.Pair.String(),
+			r.Score,
+			returnPercent,
+			r.Stats.SharpeRatio.InexactFloat64(),
+			r.Stats.MaxDrawdownPercent.InexactFloat64(),
+			r.Stats.TotalTrades,
+			winRate,
+			r.Stats.RunID,
+		)
+	}
+
+	if succeeded == 0 {
+		fmt.Println("📭 No successful backtests")
+	} else {
+		fmt.Println(strings.Repeat("-", 110))
+		fmt.Printf("📈 Profitable: %d/%d symbols, Average Return: %.2f%%\n", profitable, succeeded, totalReturn/float64(succeeded))
+	}
+
+	if failed := len(results) - succeeded; failed > 0 {
+		fmt.Printf("\n⚠️ %d symbols failed\n", failed)
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Printf("   %s: %v\n", r.Pair.String(), r.Err)
+			}
+		}
+	}
+}
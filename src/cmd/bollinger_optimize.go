@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/optimizer"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-log/log"
+	"github.com/xpwu/go-log/log/level"
+)
+
+// runBollingerOptimizeWithPair 运行布林道参数优化（网格搜索、遗传算法或 TPE）
+func runBollingerOptimizeWithPair(base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, baseParams *strategy.BollingerBandsParams,
+	rangesStr, objectiveStr string, workers, top, screen int, methodStr, constraintsStr string, budget int) error {
+
+	if rangesStr == "" {
+		rangesStr = "period=10:50:5,multiplier=1.5:3.0:0.25"
+	}
+	if objectiveStr == "" {
+		objectiveStr = string(optimizer.ObjectiveSharpe)
+	}
+	if top <= 0 {
+		top = 10
+	}
+	if methodStr == "" {
+		methodStr = string(optimizer.MethodGrid)
+	}
+
+	ranges, err := optimizer.ParseParamRanges(rangesStr)
+	if err != nil {
+		return fmt.Errorf("invalid ranges: %w", err)
+	}
+	objective, err := optimizer.ParseObjective(objectiveStr)
+	if err != nil {
+		return err
+	}
+	method, err := optimizer.ParseMethod(methodStr)
+	if err != nil {
+		return err
+	}
+	if screen > 0 && method != optimizer.MethodGrid {
+		return fmt.Errorf("-screen only applies to grid search, got -method %s", method)
+	}
+	constraints, err := optimizer.ParseConstraints(constraintsStr)
+	if err != nil {
+		return fmt.Errorf("invalid constraints: %w", err)
+	}
+
+	opt := optimizer.NewGridOptimizer(baseParams, ranges, objective, workers)
+	opt.SetConstraints(constraints)
+	combinations, err := opt.CountCandidates()
+	if err != nil {
+		return err
+	}
+	search := optimizer.SearchConfig{Budget: budget, Seed: trading.TradingConfigValue.Backtest.Seed}
+
+	fmt.Println("🔬 Bollinger Bands Parameter Optimization")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("🏢 Exchange: %s\n", cex)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+	fmt.Printf("🎯 Objective: %s\n", objective)
+	fmt.Printf("🧮 Ranges: %s (%d combinations)\n", rangesStr, combinations)
+	if method != optimizer.MethodGrid {
+		fmt.Printf("🧬 Search: %s (budget %d, seed %d)\n", method, search.Budget, search.Seed)
+	}
+	if len(constraints) > 0 {
+		fmt.Printf("🚧 Constraints: %s\n", constraintsStr)
+	}
+	if screen > 0 {
+		fmt.Printf("⚡ Screening: vectorized backtest for all combinations, full engine for the best %d\n", screen)
+	}
+	printFillModelHeader()
+
+	tradingSystem, err := trading.NewTradingSystem()
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer tradingSystem.Stop()
+
+	pair := trading.CreateTradingPair(base, quote)
+	if err := tradingSystem.SetTradingPairTimeframeAndCEX(pair, timeframe, cex); err != nil {
+		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return fmt.Errorf("invalid timeframe: %w", err)
+	}
+	startTime, endTime, err := trading.ParseBacktestRange(startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	// K线只加载一次，所有参数组合共享（只读）
+	fmt.Println("📊 Loading historical data...")
+	klines, err := tradingSystem.LoadBacktestKlines(pair, tf, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C 取消剩余的回测（正在运行的回测通过 ctx 中止）
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Cancelling optimization...")
+		cancel()
+	}()
+
+	// 并发回测时引擎日志过多，只保留警告以上
+	log.SetLevel(level.WARNING)
+	defer log.SetLevel(level.DEBUG)
+
+	fullBacktest := func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		return tradingSystem.RunBacktestOnKlines(ctx, pair, tf, klines, startTime, endTime, initialCapital, params)
+	}
+
+	// 实时输出进度和中间最优结果
+	progress := &optimizeProgress{ranges: ranges}
+	opt.SetProgressHandler(progress.handle)
+
+	begin := time.Now()
+	var results []*optimizer.Result
+	if screen > 0 {
+		// 向量化快速回测粗筛，完整引擎只回测排名靠前的组合
+		feeRate, err := tradingSystem.TakerFeeRate()
+		if err != nil {
+			return err
+		}
+		fast, err := optimizer.NewVectorizedBacktester(klines, optimizer.VectorizedConfig{
+			InitialCapital:      initialCapital,
+			PositionSizePercent: trading.TradingConfigValue.PositionSizePercent,
+			MinTradeAmount:      trading.TradingConfigValue.MinTradeAmount,
+			FeeRate:             feeRate,
+			Timeframe:           tf,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create vectorized backtest: %w", err)
+		}
+		fmt.Printf("🚀 Screening %d combinations, then running %d full backtests...\n", combinations, min(screen, combinations))
+		results, err = opt.RunScreened(ctx, fast.Backtest, fullBacktest, screen)
+	} else if method != optimizer.MethodGrid && combinations > search.Budget {
+		fmt.Printf("🚀 Searching with up to %d backtests...\n", search.Budget)
+		results, err = opt.Search(ctx, method, fullBacktest, search)
+	} else {
+		fmt.Printf("🚀 Running %d backtests...\n", combinations)
+		results, err = opt.Run(ctx, fullBacktest)
+	}
+	if err != nil {
+		if ctx.Err() != nil && progress.best != nil {
+			fmt.Printf("\n🏁 Best so far (%s, %d/%d done): score %.4f, %s\n", progress.best.stage, progress.best.done, progress.best.total,
+				progress.best.result.Score, formatOptimizedParams(progress.best.result.Params, ranges))
+		}
+		return fmt.Errorf("optimization failed: %w", err)
+	}
+	fmt.Printf("✅ Optimization completed in %s\n", time.Since(begin).Round(time.Millisecond))
+
+	printOptimizeResults(results, ranges, objective, top, screen > 0)
+	return nil
+}
+
+// printOptimizeResults 打印排名靠前的参数组合，screened 时附带快速回测的粗筛得分
+func printOptimizeResults(results []*optimizer.Result, ranges []optimizer.ParamRange, objective optimizer.Objective, top int, screened bool) {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+
+	fmt.Printf("\n🏆 TOP %d PARAMETER SETS (by %s)\n", top, objective)
+	fmt.Println(strings.Repeat("=", 110))
+
+	header := fmt.Sprintf("%-4s", "#")
+	for _, r := range ranges {
+		header += fmt.Sprintf("  %-13s", r.Name)
+	}
+	header += fmt.Sprintf("  %10s  %9s  %8s  %8s  %6s  %7s", "Score", "Return%", "Sharpe", "MaxDD%", "Trades", "Win%")
+	if screened {
+		header += fmt.Sprintf("  %10s", "Screen")
+	}
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("=", 110))
+
+	shown := 0
+	for _, r := range results {
+		if r.Err != nil || shown >= top {
+			break
+		}
+		shown++
+
+		winRate := 0.0
+		if r.Stats.TotalTrades > 0 {
+			winRate = float64(r.Stats.WinningTrades) / float64(r.Stats.TotalTrades) * 100
+		}
+
+		line := fmt.Sprintf("%-4d", shown)
+		for _, pr := range ranges {
+			line += fmt.Sprintf("  %-13s", formatOptimizedParam(r.Params, pr.Name))
+		}
+		line += fmt.Sprintf("  %10.4f  %9.2f  %8.2f  %8.2f  %6d  %7.2f",
+			r.Score,
+			r.Stats.TotalReturn.InexactFloat64()*100,
+			r.Stats.SharpeRatio.InexactFloat64(),
+			r.Stats.MaxDrawdownPercent.InexactFloat64(),
+			r.Stats.TotalTrades,
+			winRate,
+		)
+		if screened {
+			line += fmt.Sprintf("  %10.4f", r.ScreenScore)
+		}
+		fmt.Println(line)
+	}
+
+	if shown == 0 {
+		fmt.Println("📭 No successful backtests")
+	}
+	if failed > 0 {
+		fmt.Printf("\n⚠️ %d parameter sets failed (invalid params, backtest error or constraint violation)\n", failed)
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Printf("   e.g. %v\n", r.Err)
+				break
+			}
+		}
+	}
+}
+
+// optimizeProgress 在控制台输出优化进度，最优结果刷新时单独打印一行
+type optimizeProgress struct {
+	ranges []optimizer.ParamRange
+	best   *optimizeBest
+}
+
+// optimizeBest 目前的最优结果及其所在阶段的进度
+type optimizeBest struct {
+	stage       string
+	done, total int
+	result      *optimizer.Result
+}
+
+func (p *optimizeProgress) handle(progress optimizer.Progress) {
+	if progress.Improved {
+		p.best = &optimizeBest{stage: progress.Stage, done: progress.Done, total: progress.Total, result: progress.Best}
+		fmt.Printf("\r📈 [%s %d/%d] new best score %.4f: %s\n", progress.Stage, progress.Done, progress.Total,
+			progress.Best.Score, formatOptimizedParams(progress.Best.Params, p.ranges))
+	}
+	fmt.Printf("\r⏳ [%s %d/%d]", progress.Stage, progress.Done, progress.Total)
+	if progress.Done == progress.Total {
+		fmt.Println()
+	}
+}
+
+// formatOptimizedParams 格式化所有被扫描的参数，如 "period=20, multiplier=2.00"
+func formatOptimizedParams(params *strategy.BollingerBandsParams, ranges []optimizer.ParamRange) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		parts = append(parts, fmt.Sprintf("%s=%s", r.Name, formatOptimizedParam(params, r.Name)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatOptimizedParam 格式化被扫描的参数值
+func formatOptimizedParam(params *strategy.BollingerBandsParams, name string) string {
+	switch name {
+	case "period":
+		return fmt.Sprintf("%d", params.Period)
+	case "multiplier":
+		return fmt.Sprintf("%.2f", params.Multiplier)
+	case "position_size":
+		return fmt.Sprintf("%.2f", params.PositionSizePercent)
+	case "stop_loss":
+		return fmt.Sprintf("%.3f", params.StopLossPercent)
+	case "take_profit":
+		return fmt.Sprintf("%.3f", params.TakeProfitPercent)
+	case "cooldown":
+		return fmt.Sprintf("%d", params.CooldownBars)
+	case "min_band_width":
+		return fmt.Sprintf("%.3f", params.MinBandWidth)
+	case "max_band_width":
+		return fmt.Sprintf("%.3f", params.MaxBandWidth)
+	case "volume_multiplier":
+		return fmt.Sprintf("%.2f", params.VolumeMultiplier)
+	default:
+		return "-"
+	}
+}
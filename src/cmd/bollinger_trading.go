@@ -9,6 +9,9 @@ import (
 	"syscall"
 	"time"
 
+	"tradingbot/src/cex/binance"
+	"tradingbot/src/engine"
+	"tradingbot/src/i18n"
 	"tradingbot/src/strategy"
 	"tradingbot/src/trading"
 
@@ -29,8 +32,37 @@ func RegisterBollingerCmd() {
 	var quote string
 	var timeframe string
 	var cex string
-	var live bool // 是否实盘交易
-	var dry bool  // 是否Dry Run模式（实时运行但不真实下单）
+	var live bool          // 是否实盘交易
+	var dry bool           // 是否Dry Run模式（实时运行但不真实下单）
+	var session string     // Dry Run 模拟盘会话名（重启后恢复）
+	var signalOnly bool    // 只发信号模式（实时运行，信号发送到通知，不下单）
+	var tuiMode bool       // 实时运行时显示终端界面
+	var testnet bool       // 使用 Binance 现货测试网（覆盖配置 testnet）
+	var save bool          // 是否持久化回测结果
+	var equityOut string   // 资金曲线导出文件
+	var resultOut string   // 回测结果文件（backtests compare 对比）
+	var journalOut string  // 交易日志导出文件
+	var paramsFile string  // JSON策略参数文件（覆盖命令行参数）
+	var watch bool         // 监听参数文件变化自动重跑回测
+	var illiquid bool      // 回测启用流动性成交模型
+	var sizing string      // 仓位计算方式（覆盖配置 PositionSizing.Method）
+	var lotMatching string // 交易分析的持仓批次匹配方式（覆盖配置 Backtest.LotMatching）
+	var accounting string  // 回测记账货币（覆盖配置 AccountingCurrency）
+	var timeInForce string // 信号挂单有效方式（覆盖配置 TimeInForce）
+	var chaseBars int      // 买入挂单未成交多少根K线后追价（覆盖配置 OrderChase.AfterBars）
+	var chaseMax float64   // 最大追价幅度（覆盖配置 OrderChase.MaxDistance）
+	var signalCooldown int // 引擎层同方向信号冷却K线数（覆盖配置 SignalThrottle 的买入和卖出冷却）
+	var maxPending int     // 每个方向信号挂单数量上限（覆盖配置 SignalThrottle.MaxPendingPerSide）
+	var noDupSignals bool  // 已有同方向信号挂单时忽略新信号（覆盖配置 SignalThrottle.SuppressDuplicates）
+	var seed int           // 随机成交模型的种子（覆盖配置 Backtest.Seed 和 IlliquidFill.Seed）
+	var stream bool        // 从数据库分批流式读取K线（覆盖配置 Backtest.StreamKlines）
+	var intrabar string    // K线内撮合使用的细粒度K线周期（覆盖配置 Backtest.IntrabarTimeframe）
+	var spread bool        // 按记录的盘口价差模拟吃单成本（覆盖配置 Backtest.CapturedSpread）
+	var checkpoint string  // 回测断点文件（覆盖配置 Backtest.CheckpointFile）
+	var checkpointBars int // 每多少根K线保存一次断点（覆盖配置 Backtest.CheckpointEveryBars）
+	var notation string    // 交易明细数字显示方式（覆盖配置 Backtest.NumberNotation）
+	var regimes bool       // 报告各市场状态下的策略表现（覆盖配置 Backtest.Regimes.Enabled）
+	var lang string        // 输出语言（覆盖配置 locale）
 
 	var startDate string
 	var endDate string
@@ -49,15 +81,52 @@ func RegisterBollingerCmd() {
 	var sellStrategy string
 	var sellStrategyParams string
 	var listSellStrategies bool
-
-	cmd.RegisterCmd("bollinger", "run Bollinger Bands trading (default: backtest)", func(args *arg.Arg) {
+	var takeProfitLadder bool
+	var trailingStop float64
+	var oco bool
+	var atrPeriod int
+	var atrStop float64
+	var atrTakeProfit float64
+	var minBandWidth float64
+	var maxBandWidth float64
+	var volumePeriod int
+	var volumeMultiplier float64
+	var maxFundingRate float64
+
+	// 参数优化（bollinger optimize）
+	var optimizeRanges string
+	var optimizeObjective string
+	var optimizeWorkers int
+	var optimizeTop int
+	var optimizeScreen int
+	var optimizeMethod string
+	var optimizeBudget int
+	var optimizeConstraints string
+
+	// 批量回测（bollinger batch）
+	var batchSymbols string
+	var batchSymbolsFile string
+	var batchMaxCorr float64
+
+	cmd.RegisterCmd("bollinger", "run Bollinger Bands trading (default: backtest; 'optimize' for grid search; 'batch' for many symbols)", func(args *arg.Arg) {
 		args.String(&configFile, "c", "config file path")
 		args.String(&base, "base", "base currency (e.g., BTC, ETH, PEPE, WIF)")
 		args.String(&quote, "quote", "quote currency (e.g., USDT, USDC, BTC)")
 		args.String(&timeframe, "t", "timeframe (e.g., 1h, 4h, 1d)")
-		args.String(&cex, "cex", "centralized exchange (default: binance, currently only supports: binance)")
+		args.String(&cex, "cex", "centralized exchange (default: binance, supports: binance, bybit)")
 		args.Bool(&live, "live", "run in live trading mode (default: false, backtest mode)")
 		args.Bool(&dry, "dry", "run in dry run mode (live data but no real orders)")
+		args.String(&session, "session", "dry run: paper trading session name, resumed after restarts (default: paper_<cex>_<BASE><QUOTE>)")
+		args.Bool(&signalOnly, "signal-only", "run on live data and only publish BUY/SELL signals to notifications, never place orders")
+		args.Bool(&testnet, "testnet", "binance only: connect to the spot testnet (https://testnet.binance.vision) instead of the real exchange")
+		args.Bool(&tuiMode, "tui", "live/dry/signal-only: show a terminal UI with price, indicators, orders, position and logs; keys: p pause/resume entries, f flatten, q stop")
+		args.Bool(&save, "save", "save backtest run and trades to database (overrides config SaveBacktest)")
+		args.String(&equityOut, "equity-out", "export backtest equity curve to file (.csv or .json)")
+		args.String(&resultOut, "result-out", "write backtest run, trades and equity curve to a JSON file (for 'backtests compare')")
+		args.String(&journalOut, "journal-out", "export completed trades with signal reasons, indicators and slippage to file (.csv or .json)")
+		args.String(&paramsFile, "params", "JSON strategy params file (e.g. {\"period\": 25, \"multiplier\": 2.2}), overrides flags")
+		args.Bool(&watch, "watch", "backtest: re-run automatically when the -params file changes and show metric diffs")
+		args.Bool(&illiquid, "illiquid", "backtest: simulate fill probability and slippage from order size vs bar volume (for PEPE/WIF-style pairs)")
 
 		// 回测参数
 		args.String(&startDate, "start", "backtest start date (YYYY-MM-DD HH:MM:SS or YYYY-MM-DD, e.g., 2024-01-01 14:30:00) - required for backtest")
@@ -68,18 +137,90 @@ func RegisterBollingerCmd() {
 		args.Int(&period, "period", "Bollinger Bands period (default: 20)")
 		args.Float64(&multiplier, "multiplier", "Bollinger Bands multiplier (default: 2.0)")
 		args.Float64(&positionSizePercent, "position-size", "position size percent (default: 0.95)")
+		args.String(&sizing, "sizing", "position sizing method (fixed_percent, fixed_notional, kelly, volatility; default: config PositionSizing.Method)")
+		args.String(&accounting, "accounting-currency", "backtest: also report results converted to this currency, e.g. USDT for BTC-quoted pairs (default: config AccountingCurrency)")
+		args.String(&timeInForce, "tif", "time in force for signal limit orders: GTC, IOC, FOK or GTD (default: config TimeInForce, GTD with OrderValidityHours)")
+		args.Int(&chaseBars, "chase-bars", "re-place an unfilled buy limit closer to market after N bars (default: config OrderChase.AfterBars, 0 disables)")
+		args.Float64(&chaseMax, "chase-max", "maximum chase distance from the first limit price, e.g. 0.01 = 1% (default: config OrderChase.MaxDistance)")
+		args.Int(&signalCooldown, "signal-cooldown", "engine: ignore same-side signals for N bars after one placed an order (default: config SignalThrottle)")
+		args.Int(&maxPending, "max-pending", "engine: maximum pending signal orders per side (default: config SignalThrottle.MaxPendingPerSide, 0 means unlimited)")
+		args.Bool(&noDupSignals, "no-dup-signals", "engine: ignore a signal while a same-side signal order is still pending (default: config SignalThrottle.SuppressDuplicates)")
+		args.Int(&seed, "seed", "backtest: random seed for stochastic fill models (partial fills, -illiquid); recorded in the run manifest (default: config seeds)")
+		args.Bool(&stream, "stream", "backtest: stream klines from the database in batches instead of loading the whole range (requires 'sync' first)")
+		args.String(&intrabar, "intrabar", "backtest: match orders within each bar on finer klines from the database in time order (e.g., 1s, 1m; requires 'sync' of that timeframe)")
+		args.Bool(&spread, "spread", "backtest: add half of the bid/ask spread recorded during live/dry runs (book_ticker_seconds) to market and stop fills")
+		args.String(&checkpoint, "checkpoint", "backtest: save state to this file periodically; re-running with the same arguments resumes from it, deleted when the backtest completes")
+		args.Int(&checkpointBars, "checkpoint-every", "backtest: save a checkpoint every N bars (default: config Backtest.CheckpointEveryBars, 1000)")
+		args.String(&lang, "lang", "output language: zh, en or auto (default: config locale, auto detects from LANG)")
+		args.String(&notation, "notation", "backtest: number notation in the trade table: fixed, scientific or compact (default: config Backtest.NumberNotation, fixed)")
+		args.Bool(&regimes, "regimes", "backtest: report performance per market regime (trending up/down, ranging by moving-average slope; see config Backtest.Regimes)")
+		args.String(&lotMatching, "lot-matching", "backtest: match partial sells to buy lots by fifo, lifo or average cost (default: config Backtest.LotMatching)")
 		args.Float64(&minTradeAmount, "min-trade", "minimum trade amount (default: 10.0)")
 		args.Float64(&stopLossPercent, "stop-loss", "stop loss percent (default: 1.0, means no stop loss)")
 		args.Float64(&takeProfitPercent, "take-profit", "take profit percent (default: 0.2)")
 		args.Int(&cooldownBars, "cooldown", "cooldown bars (default: 1)")
 
 		// 卖出策略参数
-		args.String(&sellStrategy, "sell-strategy", "sell strategy (conservative, moderate, aggressive, trailing_5, trailing_10, combo_smart, partial_pyramid)")
-		args.String(&sellStrategyParams, "sell-strategy-params", "sell strategy parameters (e.g., 'take_profit=0.25' for 25% fixed profit)")
+		args.String(&sellStrategy, "sell-strategy", "sell strategy (conservative, moderate, aggressive, trailing_5, trailing_10, combo_smart, partial_pyramid, stop_loss_5, atr_stop, weekend_flat)")
+		args.String(&sellStrategyParams, "sell-strategy-params", "sell strategy parameters (e.g., 'take_profit=0.25' for 25% fixed profit; combo/stop_loss accept 'stop_loss=0.05' or 'atr_multiple=2,atr_period=14'; combo/time_exit accept 'max_bars=48', 'max_hours=72', 'exit_hour=20,exit_weekday=5' (UTC); combo/break_even accept 'break_even_trigger=0.05,break_even_fee=0.001')")
 		args.Bool(&listSellStrategies, "list-sell-strategies", "list all available sell strategies")
+		args.Bool(&takeProfitLadder, "tp-ladder", "pre-place all take-profit levels as limit orders right after entry (requires a partial sell strategy, e.g. partial_pyramid)")
+		args.Float64(&trailingStop, "trailing-stop", "place a trailing stop order after entry that ratchets with each new high (e.g., 0.05 = sell on 5% pullback; default: 0, disabled)")
+		args.Bool(&oco, "oco", "place a one-cancels-other take-profit limit + stop-loss pair after entry (uses -take-profit and -stop-loss)")
+		args.Int(&atrPeriod, "atr-period", "ATR period for -atr-stop/-atr-tp (default: 14)")
+		args.Float64(&atrStop, "atr-stop", "stop loss at entry - N×ATR instead of -stop-loss (e.g., 2; default: 0, disabled)")
+		args.Float64(&atrTakeProfit, "atr-tp", "take profit at entry + N×ATR instead of -take-profit (e.g., 3; default: 0, disabled)")
+		args.Float64(&minBandWidth, "min-bandwidth", "skip buys when band width (upper-lower)/middle is below this squeeze threshold (e.g., 0.02; default: 0, disabled)")
+		args.Float64(&maxBandWidth, "max-bandwidth", "skip buys when band width (upper-lower)/middle is above this blow-off threshold (e.g., 0.3; default: 0, disabled)")
+		args.Int(&volumePeriod, "volume-period", "volume confirmation: average volume period for -volume-mult (default: 20)")
+		args.Float64(&volumeMultiplier, "volume-mult", "volume confirmation: only buy when volume > N× average of the previous -volume-period bars (e.g., 1.5; default: 0, disabled)")
+		args.Float64(&maxFundingRate, "max-funding", "funding filter: skip buys when the latest funding rate of the same-name perpetual is above this rate (e.g., 0.0005 = 0.05%; default: 0, disabled; run futures-sync first)")
+
+		// 参数优化
+		args.String(&optimizeRanges, "ranges", "optimize: parameter ranges name=min:max:step (default: 'period=10:50:5,multiplier=1.5:3.0:0.25')")
+		args.String(&optimizeObjective, "objective", "optimize/batch: ranking objective (sharpe, return, profit_factor, return_drawdown; default: sharpe)")
+		args.Int(&optimizeWorkers, "workers", "optimize/batch: number of parallel backtest workers (default: GOMAXPROCS)")
+		args.Int(&optimizeTop, "top", "optimize: number of best parameter sets to show (default: 10)")
+		args.String(&optimizeMethod, "method", "optimize: search method (grid, genetic, tpe; default: grid); genetic/tpe run at most -budget backtests, seeded by -seed")
+		args.Int(&optimizeBudget, "budget", "optimize: max backtests for -method genetic/tpe (default: 100)")
+		args.String(&optimizeConstraints, "constraints", "optimize: result constraints, e.g. 'max_drawdown<20,trades>=10' (metrics: max_drawdown, return, annual_return, sharpe, profit_factor, trades, win_rate; percentages in %)")
+		args.Int(&optimizeScreen, "screen", "optimize: screen all combinations with the fast vectorized backtest, then re-run the best N with the full engine (default: 0, full engine for all)")
+
+		// 批量回测
+		args.String(&batchSymbols, "symbols", "batch: comma-separated symbols, BASE/QUOTE or BASE with -quote (default: config Symbols)")
+		args.String(&batchSymbolsFile, "symbols-file", "batch: file with one or more symbols per line, # starts a comment (overrides -symbols)")
+		args.Float64(&batchMaxCorr, "max-corr", "batch: flag symbol pairs whose strategy returns correlate at or above this level as near-duplicates (default: 0.9)")
 
 		args.Parse()
 
+		if lang != "" {
+			locale, err := i18n.ParseLocale(lang)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			i18n.SetLocale(locale)
+		}
+
+		// 支持子命令后继续带参数: bollinger optimize -base BTC -quote USDT -start 2024-01-01
+		optimize, batch := false, false
+		if rest := args.FlagSet.Args(); len(rest) > 0 {
+			switch rest[0] {
+			case "optimize":
+				optimize = true
+			case "batch":
+				batch = true
+			default:
+				fmt.Println(i18n.T("cli.unknown_subcommand", rest[0]))
+				fmt.Printf("💡 Usage: ./bin/tradingbot bollinger optimize -base BASE -quote QUOTE -start YYYY-MM-DD [-ranges RANGES] [-objective sharpe]\n")
+				fmt.Printf("   or: ./bin/tradingbot bollinger batch -symbols BTC,ETH,SOL -quote USDT -start YYYY-MM-DD [-workers N] [-objective sharpe]\n")
+				os.Exit(1)
+			}
+			if err := args.FlagSet.Parse(rest[1:]); err != nil {
+				os.Exit(1)
+			}
+		}
+
 		// 如果只是列出卖出策略
 		if listSellStrategies {
 			listAvailableSellStrategies()
@@ -124,9 +265,9 @@ func RegisterBollingerCmd() {
 			configFile = filepath.Join(exe.Exe.AbsDir, configFile)
 		}
 
-		// 验证必需参数
-		if base == "" {
-			fmt.Printf("❌ Error: base currency is required\n")
+		// 验证必需参数（批量回测的交易对来自 -symbols）
+		if base == "" && !batch {
+			fmt.Println(i18n.T("cli.base_required"))
 			if live {
 				fmt.Printf("💡 Usage: ./bin/tradingbot bollinger -base BASE -quote QUOTE --live\n")
 				fmt.Printf("   Example: ./bin/tradingbot bollinger -base PEPE -quote USDT --live\n")
@@ -136,8 +277,8 @@ func RegisterBollingerCmd() {
 			}
 			os.Exit(1)
 		}
-		if quote == "" {
-			fmt.Printf("❌ Error: quote currency is required\n")
+		if quote == "" && !batch {
+			fmt.Println(i18n.T("cli.quote_required"))
 			if live {
 				fmt.Printf("💡 Usage: ./bin/tradingbot bollinger -base BASE -quote QUOTE --live\n")
 				fmt.Printf("   Example: ./bin/tradingbot bollinger -base PEPE -quote USDT --live\n")
@@ -148,14 +289,55 @@ func RegisterBollingerCmd() {
 			os.Exit(1)
 		}
 
+		// 参数优化和批量回测只支持回测
+		if (optimize || batch) && (live || dry) {
+			fmt.Println(i18n.T("cli.backtest_only"))
+			os.Exit(1)
+		}
+
+		// 只发信号模式只支持实时运行
+		if signalOnly && (live || dry || optimize || batch || watch || startDate != "") {
+			fmt.Println(i18n.T("cli.signal_only"))
+			os.Exit(1)
+		}
+
+		// 监听模式只支持单个交易对回测，且需要参数文件
+		if watch {
+			if live || dry || optimize || batch {
+				fmt.Println(i18n.T("cli.watch_backtest"))
+				os.Exit(1)
+			}
+			if paramsFile == "" {
+				fmt.Println(i18n.T("cli.watch_params"))
+				fmt.Printf("💡 Usage: ./bin/tradingbot bollinger -base BASE -quote QUOTE -start YYYY-MM-DD -params params.json --watch\n")
+				os.Exit(1)
+			}
+		}
+
+		// 终端界面只用于实时运行
+		if tuiMode && !(live || signalOnly || (dry && startDate == "")) {
+			fmt.Println(i18n.T("cli.tui_realtime"))
+			os.Exit(1)
+		}
+
+		// 测试网只支持 Binance
+		if testnet {
+			if cex != "" && cex != "binance" {
+				fmt.Println(i18n.T("cli.testnet_binance"))
+				os.Exit(1)
+			}
+			binance.ConfigValue.Testnet = true
+		}
+
 		// 回测模式需要开始日期（但实时dry run不需要）
-		if !live && !dry && startDate == "" {
-			fmt.Printf("❌ Error: start date is required for backtest mode\n")
+		if !live && !dry && !signalOnly && startDate == "" {
+			fmt.Println(i18n.T("cli.start_required"))
 			fmt.Printf("💡 Usage: ./bin/tradingbot bollinger -base BASE -quote QUOTE -start YYYY-MM-DD [-end YYYY-MM-DD]\n")
 			fmt.Printf("   Example: ./bin/tradingbot bollinger -base PEPE -quote USDT -start 2024-01-01\n")
 			fmt.Printf("🔴 For live trading: ./bin/tradingbot bollinger -base PEPE -quote USDT --live\n")
 			fmt.Printf("📝 For dry run (real-time): ./bin/tradingbot bollinger -base PEPE -quote USDT --dry\n")
 			fmt.Printf("📝 For dry run (historical): ./bin/tradingbot bollinger -base PEPE -quote USDT --dry -start 2024-01-01\n")
+			fmt.Printf("📣 For signals only (no orders): ./bin/tradingbot bollinger -base PEPE -quote USDT --signal-only\n")
 			os.Exit(1)
 		}
 
@@ -170,6 +352,69 @@ func RegisterBollingerCmd() {
 			initialCapital = 10000.0 // 默认初始资金
 		}
 
+		if save {
+			trading.TradingConfigValue.SaveBacktest = true
+		}
+		if illiquid {
+			trading.TradingConfigValue.IlliquidFill.Enabled = true
+		}
+		if sizing != "" {
+			trading.TradingConfigValue.PositionSizing.Method = sizing
+		}
+		if lotMatching != "" {
+			trading.TradingConfigValue.Backtest.LotMatching = lotMatching
+		}
+		if accounting != "" {
+			trading.TradingConfigValue.AccountingCurrency = accounting
+		}
+		if timeInForce != "" {
+			trading.TradingConfigValue.TimeInForce = timeInForce
+		}
+		if chaseBars > 0 {
+			trading.TradingConfigValue.OrderChase.AfterBars = chaseBars
+		}
+		if chaseMax > 0 {
+			trading.TradingConfigValue.OrderChase.MaxDistance = chaseMax
+		}
+		if signalCooldown > 0 {
+			trading.TradingConfigValue.SignalThrottle.BuyCooldownBars = signalCooldown
+			trading.TradingConfigValue.SignalThrottle.SellCooldownBars = signalCooldown
+		}
+		if maxPending > 0 {
+			trading.TradingConfigValue.SignalThrottle.MaxPendingPerSide = maxPending
+		}
+		if noDupSignals {
+			trading.TradingConfigValue.SignalThrottle.SuppressDuplicates = true
+		}
+		if seed != 0 {
+			trading.TradingConfigValue.SetSeed(int64(seed))
+		}
+		if stream {
+			trading.TradingConfigValue.Backtest.StreamKlines = true
+		}
+		if intrabar != "" {
+			trading.TradingConfigValue.Backtest.IntrabarTimeframe = intrabar
+		}
+		if spread {
+			trading.TradingConfigValue.Backtest.CapturedSpread = true
+		}
+		if checkpoint != "" {
+			trading.TradingConfigValue.Backtest.CheckpointFile = checkpoint
+		}
+		if checkpointBars != 0 {
+			trading.TradingConfigValue.Backtest.CheckpointEveryBars = checkpointBars
+		}
+		if notation != "" {
+			if _, err := trading.ParseNumberNotation(notation); err != nil {
+				fmt.Println(i18n.T("cli.error", err))
+				os.Exit(1)
+			}
+			trading.TradingConfigValue.Backtest.NumberNotation = notation
+		}
+		if regimes {
+			trading.TradingConfigValue.Backtest.Regimes.Enabled = true
+		}
+
 		// 如果没有设置endDate，使用当前时间（回测模式或有start参数的dry模式）
 		if !live && endDate == "" && startDate != "" {
 			endDate = time.Now().Format("2006-01-02 15:04:05")
@@ -181,11 +426,18 @@ func RegisterBollingerCmd() {
 		if sellStrategyParams != "" {
 			parsedSellParams, err = strategy.ParseSellStrategyParams(sellStrategyParams)
 			if err != nil {
-				fmt.Printf("❌ Failed to parse sell strategy parameters: %v\n", err)
+				fmt.Println(i18n.T("cli.sell_params_failed", err))
 				os.Exit(1)
 			}
 		}
 
+		if (atrStop > 0 || atrTakeProfit > 0) && atrPeriod == 0 {
+			atrPeriod = 14 // 默认ATR周期
+		}
+		if volumeMultiplier > 0 && volumePeriod == 0 {
+			volumePeriod = 20 // 默认平均成交量周期
+		}
+
 		// 创建策略参数
 		strategyParams := &strategy.BollingerBandsParams{
 			Period:              period,
@@ -197,27 +449,59 @@ func RegisterBollingerCmd() {
 			CooldownBars:        cooldownBars,
 			SellStrategyName:    sellStrategy,
 			SellStrategyParams:  parsedSellParams,
+			TakeProfitLadder:    takeProfitLadder,
+			TrailingStop:        trailingStop,
+			OCO:                 oco,
+
+			ATRPeriod:             atrPeriod,
+			ATRStopMultiple:       atrStop,
+			ATRTakeProfitMultiple: atrTakeProfit,
+
+			MinBandWidth: minBandWidth,
+			MaxBandWidth: maxBandWidth,
+
+			VolumePeriod:     volumePeriod,
+			VolumeMultiplier: volumeMultiplier,
+
+			MaxFundingRate: maxFundingRate,
+		}
+
+		// 参数文件覆盖命令行参数（监听模式每次重跑时重新读取）
+		if paramsFile != "" && !watch {
+			strategyParams, err = strategy.LoadBollingerBandsParamsFile(paramsFile, strategyParams)
+			if err != nil {
+				fmt.Println(i18n.T("cli.error", err))
+				os.Exit(1)
+			}
 		}
 
 		// 根据模式运行
-		if live || (dry && startDate == "") {
-			// 实时模式：真实交易或实时Dry Run
-			err = runBollingerLiveWithPair(configFile, base, quote, timeframe, cex, initialCapital, strategyParams, dry)
+		if watch {
+			err = runBollingerWatchWithPair(base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyParams, paramsFile)
+		} else if optimize {
+			err = runBollingerOptimizeWithPair(base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyParams,
+				optimizeRanges, optimizeObjective, optimizeWorkers, optimizeTop, optimizeScreen, optimizeMethod, optimizeConstraints, optimizeBudget)
+		} else if batch {
+			err = runBollingerBatch(batchSymbols, batchSymbolsFile, quote, timeframe, cex, startDate, endDate, initialCapital, strategyParams,
+				optimizeObjective, optimizeWorkers, batchMaxCorr)
+		} else if live || signalOnly || (dry && startDate == "") {
+			// 实时模式：真实交易、实时Dry Run或只发信号
+			err = runBollingerLiveWithPair(configFile, base, quote, timeframe, cex, initialCapital, strategyParams, dry, signalOnly, session, tuiMode)
 		} else {
 			// 回测模式：历史数据回测或Dry Run回测
 			isDryBacktest := dry && startDate != ""
-			err = runBollingerBacktestWithPair(configFile, base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyParams, isDryBacktest)
+			err = runBollingerBacktestWithPair(configFile, base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyParams, isDryBacktest, equityOut, resultOut, journalOut)
 		}
 
 		if err != nil {
-			fmt.Printf("❌ Trading system error: %v\n", err)
+			fmt.Println(i18n.T("cli.system_error", err))
 			os.Exit(1)
 		}
 	})
 }
 
 // runBollingerBacktestWithPair 运行布林道回测系统
-func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, strategyParams *strategy.BollingerBandsParams, isDryBacktest bool) error {
+func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, strategyParams *strategy.BollingerBandsParams, isDryBacktest bool, equityOut, resultOut, journalOut string) error {
 	if isDryBacktest {
 		fmt.Println("🤖 Bollinger Bands Dry Run System (Historical Data)")
 	} else {
@@ -227,6 +511,8 @@ func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, start
 	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
 	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
 	fmt.Printf("🏢 Exchange: %s\n", cex)
+	printFillModelHeader()
+	printPositionSizingHeader()
 
 	// 创建交易系统
 	fmt.Println("📋 Using global config")
@@ -242,6 +528,10 @@ func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, start
 		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
 	}
 
+	if tradingSystem.Testnet() {
+		fmt.Println("🧪 TESTNET: connected to the exchange testnet, balances and orders are not real")
+	}
+
 	// 设置信号处理
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
@@ -263,6 +553,7 @@ func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, start
 	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
 
 	// 运行回测
+	tradingSystem.SetBacktestResultFile(resultOut)
 	stats, err := tradingSystem.RunBacktestWithParamsAndCapital(pair, startDate, endDate, initialCapital, strategyParams)
 	if err != nil {
 		return fmt.Errorf("backtest failed: %w", err)
@@ -271,11 +562,68 @@ func runBollingerBacktestWithPair(configPath, base, quote, timeframe, cex, start
 	// 打印结果
 	tradingSystem.PrintBacktestResults(pair, stats)
 
+	// 导出资金曲线
+	if equityOut != "" {
+		curve := tradingSystem.GetEquityCurve()
+		if err := engine.ExportEquityCurve(equityOut, curve, stats.TraceID); err != nil {
+			return fmt.Errorf("failed to export equity curve: %w", err)
+		}
+		fmt.Printf("📈 Equity curve exported: %s (%d points)\n", equityOut, len(curve))
+	}
+	if err := exportTradeJournal(journalOut, stats); err != nil {
+		return err
+	}
+	if resultOut != "" {
+		fmt.Printf("📄 Backtest result written: %s\n", resultOut)
+	}
+
+	return nil
+}
+
+// exportTradeJournal 导出回测的交易日志，path 为空时不导出
+func exportTradeJournal(path string, stats *trading.BacktestStatistics) error {
+	if path == "" {
+		return nil
+	}
+	journal := trading.BuildTradeJournal(stats.Trades)
+	for i := range journal {
+		journal[i].TraceID = stats.TraceID
+	}
+	if err := trading.ExportTradeJournal(path, journal); err != nil {
+		return fmt.Errorf("failed to export trade journal: %w", err)
+	}
+	fmt.Printf("📒 Trade journal exported: %s (%d trades)\n", path, len(journal))
 	return nil
 }
 
+// printFillModelHeader 打印回测使用的成交模型参数
+func printFillModelHeader() {
+	model, err := trading.TradingConfigValue.NewFillModel()
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		return
+	}
+	if model != nil {
+		fmt.Printf("💧 Fill Model: %s\n", model.Describe())
+	}
+	if trading.TradingConfigValue.Backtest.CapturedSpread {
+		fmt.Printf("↔️ Spread: half of the recorded bid/ask spread on market and stop fills\n")
+	}
+}
+
+// printPositionSizingHeader 在回测报告头部打印仓位计算方式
+func printPositionSizingHeader() {
+	config := trading.TradingConfigValue
+	sizer, err := config.PositionSizing.NewPositionSizer(config.PositionSizePercent)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		return
+	}
+	fmt.Printf("📐 Position Sizing: %s\n", sizer.Describe())
+}
+
 // runBollingerLiveWithPair 运行布林道实盘交易
-func runBollingerLiveWithPair(configFile, base, quote, timeframe, cex string, initialCapital float64, strategyParams *strategy.BollingerBandsParams, dryRun bool) error {
+func runBollingerLiveWithPair(configFile, base, quote, timeframe, cex string, initialCapital float64, strategyParams *strategy.BollingerBandsParams, dryRun, signalOnly bool, session string, tuiMode bool) error {
 	fmt.Println("🤖 Bollinger Bands Live Trading System")
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
@@ -298,26 +646,45 @@ func runBollingerLiveWithPair(configFile, base, quote, timeframe, cex string, in
 		return fmt.Errorf("failed to set trading parameters: %w", err)
 	}
 
-	// 设置信号处理
-	signalChan := make(chan os.Signal, 1)
+	// 设置信号处理：第一次信号按 Shutdown 配置停止，第二次立即退出
+	signalChan := make(chan os.Signal, 2)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
+		sig := <-signalChan
+		fmt.Println("\n🔄 Shutting down... (press Ctrl+C again to exit immediately)")
+		tradingSystem.RequestShutdown("received " + sig.String())
 		<-signalChan
-		fmt.Println("\n🔄 Shutting down...")
-		tradingSystem.Stop()
-		os.Exit(0)
+		fmt.Println("⚠️ Forced exit, open orders and positions were not handled")
+		os.Exit(1)
 	}()
 
 	// 显示模式信息
-	if dryRun {
+	if signalOnly {
+		fmt.Println("📣 Signal-only mode")
+		fmt.Println("💡 Using real-time data; BUY/SELL signals are sent to notifications, no orders are placed")
+		tradingSystem.SetSignalOnly(true)
+		tradingSystem.SetPaperSession(session, initialCapital)
+	} else if dryRun {
 		fmt.Println("🧪 Dry Run mode")
-		fmt.Println("💡 Using real-time data with simulated orders")
+		fmt.Println("💡 Using real-time data with simulated orders filled against the live order book")
+		tradingSystem.SetPaperSession(session, initialCapital)
 	} else {
-		fmt.Println("🔴 Live trading mode")
-		fmt.Println("⚠️  WARNING: This will use real money!")
+		if tradingSystem.Testnet() {
+			fmt.Println("🔴 Live trading mode (TESTNET)")
+			fmt.Println("💡 Orders are placed on the testnet with test funds")
+		} else {
+			fmt.Println("🔴 Live trading mode")
+			fmt.Println("⚠️  WARNING: This will use real money!")
+		}
+
+		// 安全门：显示摘要并要求输入 ARM 确认
+		if err := tradingSystem.ArmLive(pair, strategyParams, os.Stdin, os.Stdout); err != nil {
+			return fmt.Errorf("live trading not started: %w", err)
+		}
 	}
 	fmt.Println("Press Ctrl+C to stop...")
+	tradingSystem.SetTUI(tuiMode)
 
 	// 运行实盘交易
 	err = tradingSystem.RunLiveTradingWithParams(pair, strategyParams, dryRun)
@@ -351,7 +718,7 @@ func listAvailableSellStrategies() {
 			fmt.Printf("   Fixed: %.1f%%, Trailing: %.1f%% after %.1f%%\n",
 				config.FixedTakeProfit*100, config.TrailingPercent*100, config.MinProfitForTrailing*100)
 			fmt.Printf("   Max Holding: %d days\n", config.MaxHoldingDays)
-			fmt.Printf("   Custom: --sell-strategy-params \"take_profit=0.22,trailing_percent=0.06\"\n")
+			fmt.Printf("   Custom: --sell-strategy-params \"take_profit=0.22,trailing_percent=0.06,stop_loss=0.05\"\n")
 		case strategy.SellStrategyPartial:
 			fmt.Printf("   Levels: %d\n", len(config.PartialLevels))
 			for i, level := range config.PartialLevels {
@@ -359,6 +726,19 @@ func listAvailableSellStrategies() {
 					i+1, level.ProfitPercent*100, level.SellPercent*100)
 			}
 			fmt.Printf("   Custom: (partial levels are complex, use defaults)\n")
+			fmt.Printf("   Ladder: --tp-ladder (pre-place all levels as limit orders after entry)\n")
+		case strategy.SellStrategyStopLoss:
+			if config.ATRStopMultiple > 0 {
+				fmt.Printf("   ATR Stop: entry - %.1f×ATR%d (fallback %.1f%%)\n", config.ATRStopMultiple, config.ATRPeriod, config.StopLossPercent*100)
+			} else {
+				fmt.Printf("   Stop Loss: %.1f%%\n", config.StopLossPercent*100)
+			}
+			fmt.Printf("   Custom: --sell-strategy-params \"stop_loss=0.03\" or \"atr_multiple=2.5,atr_period=20\"\n")
+		case strategy.SellStrategyTimeExit:
+			if timeExit, err := strategy.CreateSellStrategy(config); err == nil {
+				fmt.Printf("   Exit: %s\n", timeExit.GetName())
+			}
+			fmt.Printf("   Custom: --sell-strategy-params \"max_bars=48\", \"max_hours=72\" or \"exit_hour=20,exit_weekday=5\" (UTC, 0=Sunday)\n")
 		}
 		fmt.Println()
 	}
@@ -367,12 +747,32 @@ func listAvailableSellStrategies() {
 	fmt.Printf("   Fixed 25%% profit: --sell-strategy conservative --sell-strategy-params \"take_profit=0.25\"\n")
 	fmt.Printf("   Trailing 8%% after 18%% profit: --sell-strategy trailing_5 --sell-strategy-params \"trailing_percent=0.08,min_profit=0.18\"\n")
 	fmt.Printf("   Custom aggressive 35%%: --sell-strategy aggressive --sell-strategy-params \"take_profit=0.35\"\n")
+	fmt.Printf("   Combo with 2×ATR stop: --sell-strategy combo_smart --sell-strategy-params \"atr_multiple=2,stop_loss=0.05\"\n")
+	fmt.Printf("   Break-even stop after 8%% profit: --sell-strategy break_even --sell-strategy-params \"break_even_trigger=0.08\"\n")
+	fmt.Printf("   Combo with break-even stop: --sell-strategy combo_smart --sell-strategy-params \"break_even_trigger=0.1\"\n")
+	fmt.Printf("   Combo flat before weekends: --sell-strategy combo_smart --sell-strategy-params \"exit_hour=20,exit_weekday=5\"\n")
 	fmt.Println()
 }
 
 // RegisterAllTradingCommands 注册所有交易相关命令
 func RegisterAllTradingCommands() {
 	RegisterBollingerTradingCmd()
+	RegisterBacktestsCmd()
+	RegisterPnLCmd()
+	RegisterStatusCmd()
+	RegisterAccountCmd()
+	RegisterMarketCmds()
+	RegisterIndicatorsCmd()
+	RegisterSyncCmd()
+	RegisterFuturesSyncCmd()
+	RegisterSymbolsCmd()
+	RegisterDashboardCmd()
+	RegisterBotsCmd()
+	RegisterScheduleCmd()
+	RegisterScriptCmd()
+	RegisterCompositeCmd()
+	RegisterPairsCmd()
+	RegisterNewStrategyCmd()
 
 	// 可以添加其他交易策略命令
 	// RegisterMACDTradingCmd()
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-log/log"
+	"github.com/xpwu/go-log/log/level"
+)
+
+// watchPollInterval 参数文件检查间隔
+const watchPollInterval = time.Second
+
+// fileVersion 文件版本（修改时间 + 大小），用于判断文件是否变化
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFileVersion 获取文件当前版本
+func statFileVersion(path string) (fileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileVersion{}, err
+	}
+	return fileVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// runBollingerWatchWithPair 监听参数文件，变化后使用缓存的K线重新回测并对比关键指标
+func runBollingerWatchWithPair(base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, baseParams *strategy.BollingerBandsParams, paramsFile string) error {
+	fmt.Println("👀 Bollinger Bands Backtest Watch Mode")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("🏢 Exchange: %s\n", cex)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+	fmt.Printf("📝 Params File: %s\n", paramsFile)
+	printFillModelHeader()
+
+	tradingSystem, err := trading.NewTradingSystem()
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer tradingSystem.Stop()
+
+	pair := trading.CreateTradingPair(base, quote)
+	if err := tradingSystem.SetTradingPairTimeframeAndCEX(pair, timeframe, cex); err != nil {
+		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return fmt.Errorf("invalid timeframe: %w", err)
+	}
+	startTime, endTime, err := trading.ParseBacktestRange(startDate, endDate)
+	if err != nil {
+		return err
+	}
+
+	// K线只加载一次，每次重跑复用
+	fmt.Println("📊 Loading historical data...")
+	klines, err := tradingSystem.LoadBacktestKlines(pair, tf, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Stopping watch mode...")
+		cancel()
+	}()
+
+	// 重复回测时只关心指标，引擎日志只保留警告以上
+	log.SetLevel(level.WARNING)
+	defer log.SetLevel(level.DEBUG)
+
+	var previous *trading.BacktestStatistics
+	runOnce := func() {
+		params, err := strategy.LoadBollingerBandsParamsFile(paramsFile, baseParams)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		begin := time.Now()
+		stats, err := tradingSystem.RunBacktestOnKlines(ctx, pair, tf, klines, startTime, endTime, initialCapital, params)
+		if err != nil {
+			fmt.Printf("❌ Backtest failed: %v\n", err)
+			return
+		}
+
+		fmt.Printf("\n🔁 [%s] Backtest finished in %s\n", time.Now().Format("15:04:05"), time.Since(begin).Round(time.Millisecond))
+		fmt.Printf("⚙️ Params: %+v\n", *params)
+		printMetricsDiff(trading.CompareBacktestMetrics(previous, stats), previous != nil)
+		previous = stats
+	}
+
+	lastVersion, err := statFileVersion(paramsFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat params file: %w", err)
+	}
+	runOnce()
+	fmt.Println("\n👀 Watching for changes... Press Ctrl+C to stop")
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			version, err := statFileVersion(paramsFile)
+			if err != nil {
+				// 编辑器保存时可能短暂删除文件，下次再检查
+				continue
+			}
+			if version == lastVersion {
+				continue
+			}
+			lastVersion = version
+			fmt.Printf("\n📝 %s changed, re-running backtest...\n", paramsFile)
+			runOnce()
+		}
+	}
+}
+
+// printMetricsDiff 打印关键指标及相对上一次回测的变化
+func printMetricsDiff(diffs []trading.MetricDiff, hasPrevious bool) {
+	fmt.Println(strings.Repeat("-", 56))
+	fmt.Printf("%-16s %12s %12s %12s\n", "Metric", "Previous", "Current", "Δ")
+	fmt.Println(strings.Repeat("-", 56))
+
+	for _, d := range diffs {
+		previous, delta := "-", "-"
+		if hasPrevious {
+			previous = fmt.Sprintf("%.2f%s", d.Previous, d.Unit)
+			switch {
+			case d.Delta > 0:
+				delta = fmt.Sprintf("🔼 %+.2f", d.Delta)
+			case d.Delta < 0:
+				delta = fmt.Sprintf("🔽 %+.2f", d.Delta)
+			default:
+				delta = "0"
+			}
+		}
+		fmt.Printf("%-16s %12s %12s %12s\n", d.Name, previous, fmt.Sprintf("%.2f%s", d.Current, d.Unit), delta)
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/dashboard"
+	"tradingbot/src/scheduler"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterBotsCmd 注册多机器人命令（run 启动管理器；list / start / stop 通过管理器的 REST 接口控制）
+func RegisterBotsCmd() {
+	var addr string
+
+	cmd.RegisterCmd("bots", "run several bots in one process (run | list | start <name> | stop <name>)", func(args *arg.Arg) {
+		args.String(&addr, "addr", "bot manager API address (default: config dashboard LiveAddr, or 127.0.0.1:8080)")
+		args.Parse()
+
+		// 支持子命令后继续带参数: bots start btc-4h -addr 127.0.0.1:8080
+		rest := args.FlagSet.Args()
+		if len(rest) == 0 {
+			printBotsUsage()
+			os.Exit(1)
+		}
+		subCmd := rest[0]
+		if err := args.FlagSet.Parse(rest[1:]); err != nil {
+			os.Exit(1)
+		}
+		rest = args.FlagSet.Args()
+
+		if addr == "" {
+			addr = dashboard.ConfigValue.LiveAddr
+		}
+		if addr == "" {
+			addr = "127.0.0.1:8080"
+		}
+
+		var err error
+		switch subCmd {
+		case "run":
+			err = runBotManager(addr)
+		case "list", "status":
+			err = listBots(addr)
+		case "start", "stop":
+			if len(rest) == 0 {
+				fmt.Printf("❌ Error: bot name is required\n")
+				printBotsUsage()
+				os.Exit(1)
+			}
+			err = controlBot(addr, subCmd, rest[0])
+		default:
+			fmt.Printf("❌ Error: unknown subcommand %s\n", subCmd)
+			printBotsUsage()
+			os.Exit(1)
+		}
+
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printBotsUsage 打印多机器人命令用法
+func printBotsUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot bots run [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot bots list [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot bots start <name> [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot bots stop <name> [-addr HOST:PORT]\n")
+}
+
+// runBotManager 启动配置中的机器人管理器和 REST 接口，收到退出信号后停止所有机器人
+func runBotManager(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	manager, err := trading.NewBotManager(ctx, trading.TradingConfigValue.Bots)
+	if err != nil {
+		return err
+	}
+
+	server := dashboard.NewServer(addr, nil, nil)
+	server.SetBotController(manager)
+
+	// 配置了定时任务时在同一进程内调度，优化出的参数可以直接推送给运行中的机器人
+	var schedule *scheduler.Service
+	if len(scheduler.ConfigValue.Jobs) > 0 {
+		if schedule, err = scheduler.NewService(ctx, scheduler.ConfigValue, manager); err != nil {
+			return err
+		}
+		server.SetScheduleController(schedule)
+	}
+
+	listenAddr, err := server.Start(ctx)
+	if err != nil {
+		return err
+	}
+	var healthAddr string
+	if addr := dashboard.ConfigValue.HealthAddr; addr != "" {
+		if healthAddr, err = dashboard.StartHealthServer(ctx, addr, dashboard.HealthChecks); err != nil {
+			return err
+		}
+	}
+
+	statuses := manager.BotStatuses()
+	fmt.Println("🤖 Bot Manager")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📋 Bots: %d configured, %d auto-started\n", len(statuses), manager.StartAutoStart())
+	fmt.Printf("🌐 API: http://%s/api/bots (Ctrl+C to stop)\n", listenAddr)
+	if healthAddr != "" {
+		fmt.Printf("💓 Health: http://%s/healthz\n", healthAddr)
+	}
+	if schedule != nil {
+		fmt.Printf("⏰ Schedule: %d jobs\n", len(scheduler.ConfigValue.Jobs))
+		go schedule.Run()
+	}
+
+	<-ctx.Done()
+	fmt.Println("\n🔄 Shutting down...")
+	manager.StopAll()
+	return nil
+}
+
+// listBots 打印所有机器人的状态
+func listBots(addr string) error {
+	var statuses []*dashboard.BotStatus
+	if err := botsRequest(http.MethodGet, addr, "/api/bots", &statuses); err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		fmt.Println("📭 No bots configured")
+		return nil
+	}
+
+	fmt.Printf("🤖 Bots: %d\n", len(statuses))
+	fmt.Println(strings.Repeat("=", 110))
+	fmt.Printf("%-16s  %-8s  %-12s  %-4s  %-10s  %-7s  %-8s  %-19s\n",
+		"Name", "Exchange", "Symbol", "TF", "Strategy", "Mode", "State", "Since")
+	fmt.Println(strings.Repeat("=", 110))
+	for _, status := range statuses {
+		printBotStatus(status)
+	}
+	return nil
+}
+
+// controlBot 启动或停止机器人
+func controlBot(addr, action, name string) error {
+	var status dashboard.BotStatus
+	if err := botsRequest(http.MethodPost, addr, fmt.Sprintf("/api/bots/%s/%s", name, action), &status); err != nil {
+		return err
+	}
+	if action == "start" {
+		fmt.Printf("✅ Bot %s started\n", name)
+	} else {
+		fmt.Printf("🛑 Bot %s stopped\n", name)
+	}
+	printBotStatus(&status)
+	return nil
+}
+
+// printBotStatus 打印一行机器人状态
+func printBotStatus(status *dashboard.BotStatus) {
+	mode := "live"
+	if status.SignalOnly {
+		mode = "signal-only"
+	} else if status.DryRun {
+		mode = "dry-run"
+	}
+	since := "-"
+	if status.State == dashboard.BotStateRunning && status.StartedAt != nil {
+		since = status.StartedAt.Local().Format("2006-01-02 15:04:05")
+	} else if status.StoppedAt != nil {
+		since = status.StoppedAt.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%-16s  %-8s  %-12s  %-4s  %-10s  %-7s  %-8s  %-19s\n",
+		status.Name, status.Exchange, status.Symbol, status.Timeframe, status.Strategy, mode, status.State, since)
+	if status.Error != "" {
+		fmt.Printf("  ⚠️ %s\n", status.Error)
+	}
+}
+
+// botsRequest 调用机器人管理器（或定时任务）的 REST 接口并解析响应
+func botsRequest(method, addr, path string, result interface{}) error {
+	request, err := http.NewRequest(method, "http://"+addr+path, nil)
+	if err != nil {
+		return err
+	}
+	// 停止机器人会等待引擎退出
+	client := &http.Client{Timeout: time.Minute}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to reach bot manager at %s (is 'bots run', 'schedule run' or a live bot with dashboard LiveAddr running?): %w", addr, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return fmt.Errorf("%s %s returned %s", method, path, response.Status)
+		}
+		return errors.New(apiErr.Error)
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterCompositeCmd 注册组合策略回测命令
+func RegisterCompositeCmd() {
+	var file string
+	var base string
+	var quote string
+	var timeframe string
+	var cex string
+	var startDate string
+	var endDate string
+	var initialCapital float64
+	var buyRule string
+	var sellRule string
+	var threshold float64
+	var check bool
+	var resultOut string
+	var journalOut string
+
+	cmd.RegisterCmd("composite", "backtest a composite strategy combining registered strategies with all/any/weighted rules", func(args *arg.Arg) {
+		args.String(&file, "file", "composite strategy JSON file (buy_rule, sell_rule, threshold, members) - required")
+		args.String(&base, "base", "base currency (e.g., BTC, ETH)")
+		args.String(&quote, "quote", "quote currency (e.g., USDT)")
+		args.String(&timeframe, "t", "timeframe (e.g., 1h, 4h, 1d)")
+		args.String(&cex, "cex", "centralized exchange (default: binance)")
+		args.String(&startDate, "start", "backtest start date (YYYY-MM-DD) - required")
+		args.String(&endDate, "end", "backtest end date (YYYY-MM-DD)")
+		args.Float64(&initialCapital, "capital", "initial capital (default: 10000.0)")
+		args.String(&buyRule, "buy-rule", "override the file's buy rule: all, any or weighted")
+		args.String(&sellRule, "sell-rule", "override the file's sell rule: all, any or weighted")
+		args.Float64(&threshold, "threshold", "override the file's weighted vote threshold (0-1)")
+		args.Bool(&check, "check", "only load the members and validate their parameters, then exit")
+		args.String(&resultOut, "result-out", "write backtest run, trades and equity curve to a JSON file (for 'backtests compare')")
+		args.String(&journalOut, "journal-out", "export completed trades with signal reasons, indicators and slippage to file (.csv or .json)")
+		args.Parse()
+
+		if file == "" {
+			fmt.Printf("❌ Error: -file is required\n")
+			printCompositeUsage()
+			os.Exit(1)
+		}
+		params, err := loadCompositeParams(file)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if buyRule != "" {
+			params.BuyRule = strategy.CompositeRule(buyRule)
+		}
+		if sellRule != "" {
+			params.SellRule = strategy.CompositeRule(sellRule)
+		}
+		if threshold != 0 {
+			params.Threshold = threshold
+		}
+
+		strategyImpl := strategies.NewCompositeStrategy()
+		if err := strategyImpl.SetParams(params); err != nil {
+			fmt.Printf("❌ invalid composite %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		if check {
+			fmt.Printf("✅ %s: %s\n", file, strategyImpl.GetName())
+			return
+		}
+
+		if base == "" || quote == "" || startDate == "" {
+			fmt.Printf("❌ Error: -base, -quote and -start are required\n")
+			printCompositeUsage()
+			os.Exit(1)
+		}
+		if endDate == "" {
+			endDate = time.Now().Format("2006-01-02 15:04:05")
+		}
+		if timeframe == "" {
+			timeframe = "4h"
+		}
+		if cex == "" {
+			cex = "binance"
+		}
+		if initialCapital == 0 {
+			initialCapital = 10000.0
+		}
+
+		fmt.Println("🧩 Composite Strategy Backtest")
+		fmt.Println(strings.Repeat("=", 50))
+		fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
+		fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+		fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+		fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+		fmt.Printf("🧩 Strategy: %s\n", strategyImpl.GetName())
+
+		if err := runStrategyBacktest(base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyImpl, resultOut, journalOut); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printCompositeUsage 打印组合策略命令用法
+func printCompositeUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot composite -file combo.json -base BTC -quote USDT -start 2024-01-01 [-end 2024-06-30] [-t 4h]\n")
+	fmt.Printf("          ./bin/tradingbot composite -file combo.json -check\n")
+	fmt.Printf("💡 Rules: all (every member agrees), any (at least one member), weighted (weighted strength vote >= threshold)\n")
+	fmt.Printf("💡 Strategies: %s\n", strings.Join(strategies.GetSupportedStrategies(), ", "))
+}
+
+// loadCompositeParams 从JSON文件加载组合策略参数（未出现的字段使用默认值）
+func loadCompositeParams(path string) (*strategy.CompositeParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read composite file: %w", err)
+	}
+	params := strategy.GetDefaultCompositeParams()
+	if err := json.Unmarshal(data, params); err != nil {
+		return nil, fmt.Errorf("failed to parse composite file %s: %w", path, err)
+	}
+	return params, nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tradingbot/src/dashboard"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterDashboardCmd 注册监控面板命令（浏览历史回测；实盘面板随实盘启动）
+func RegisterDashboardCmd() {
+	var cexName string
+	var addr string
+
+	cmd.RegisterCmd("dashboard", "serve the web dashboard for browsing saved backtest runs", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange whose database stores the runs (default: binance)")
+		args.String(&addr, "addr", "listen address (default: 127.0.0.1:8080)")
+		args.Parse()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+		if addr == "" {
+			addr = "127.0.0.1:8080"
+		}
+
+		if err := runDashboard(cexName, addr); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// runDashboard 启动监控面板，收到退出信号后关闭
+func runDashboard(cexName, addr string) error {
+	db, err := openBacktestDatabase(cexName)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listenAddr, err := dashboard.NewServer(addr, nil, db).Start(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📊 Dashboard: http://%s (Ctrl+C to stop)\n", listenAddr)
+
+	<-ctx.Done()
+	fmt.Println("\n🔄 Shutting down...")
+	return nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterSyncCmd 注册历史K线同步命令
+func RegisterSyncCmd() {
+	var cexName string
+	var base string
+	var quote string
+	var timeframeList string
+	var startDate string
+
+	cmd.RegisterCmd("sync", "download kline history into the database (resume, back-fill gaps)", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange to download from (default: binance)")
+		args.String(&base, "base", "base currency, overrides config Sync.Pairs (requires -quote)")
+		args.String(&quote, "quote", "quote currency, overrides config Sync.Pairs (requires -base)")
+		args.String(&timeframeList, "t", "comma-separated timeframes (e.g., 1h,4h), overrides config Sync.Timeframes")
+		args.String(&startDate, "start", "first sync start date in UTC (YYYY-MM-DD or YYYY-MM-DD HH:MM), overrides config Sync.StartDate")
+		args.Parse()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+
+		config := trading.TradingConfigValue.Sync
+		if base != "" || quote != "" {
+			if base == "" || quote == "" {
+				fmt.Printf("❌ Error: -base and -quote must be used together\n")
+				os.Exit(1)
+			}
+			config.Pairs = []string{base + "/" + quote}
+		}
+		if timeframeList != "" {
+			config.Timeframes = strings.Split(timeframeList, ",")
+		}
+		if startDate != "" {
+			config.StartDate = startDate
+		}
+
+		if err := runKlineSync(cexName, config); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// runKlineSync 依次同步配置中的每个交易对和周期
+func runKlineSync(cexName string, config trading.SyncConfig) error {
+	pairs, err := trading.ParseTradingPairs(config.Pairs)
+	if err != nil {
+		return err
+	}
+	tfs := make([]timeframes.Timeframe, 0, len(config.Timeframes))
+	for _, s := range config.Timeframes {
+		tf, err := timeframes.ParseTimeframe(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		tfs = append(tfs, tf)
+	}
+	startTime, err := config.StartTime()
+	if err != nil {
+		return err
+	}
+	if len(pairs) == 0 || len(tfs) == 0 {
+		return fmt.Errorf("nothing to sync: configure Sync.Pairs and Sync.Timeframes or use -base/-quote/-t")
+	}
+
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	db, err := trading.GetPostgresDB(client)
+	if err != nil {
+		return err
+	}
+	syncer, err := trading.NewKlineSyncer(client, db, config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔄 Kline History Sync")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("🏢 Exchange: %s\n", cexName)
+	fmt.Printf("📊 Pairs: %s\n", strings.Join(config.Pairs, ", "))
+	fmt.Printf("⏰ Timeframes: %s\n", strings.Join(config.Timeframes, ", "))
+	fmt.Printf("📅 Start: %s UTC\n", startTime.Format("2006-01-02 15:04"))
+	fmt.Printf("🐢 Rate Limit: %dms between requests, %d klines per request\n", config.RequestIntervalMs, config.BatchSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Stopping sync, progress is saved...")
+		cancel()
+	}()
+
+	var failed int
+	for _, pair := range pairs {
+		for _, tf := range tfs {
+			fmt.Printf("\n⬇️ Syncing %s %s...\n", pair.String(), tf)
+			result, err := syncer.Sync(ctx, pair, tf, startTime)
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("sync cancelled")
+				}
+				fmt.Printf("❌ %s %s failed: %v\n", pair.String(), tf, err)
+				failed++
+				continue
+			}
+
+			latest := "-"
+			if !result.LastOpenTime.IsZero() {
+				latest = result.LastOpenTime.Format("2006-01-02 15:04")
+			}
+			fmt.Printf("✅ %s %s: +%d klines, %d gaps filled, %d total, latest %s\n",
+				result.Symbol, tf, result.Fetched, result.GapsFilled, result.TotalRecords, latest)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d sync job(s) failed", failed)
+	}
+	fmt.Println("\n🎉 Sync completed")
+	return nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterFuturesSyncCmd 注册永续合约资金费率和持仓量同步命令
+func RegisterFuturesSyncCmd() {
+	var cexName string
+	var pairList string
+	var startDate string
+	var period string
+
+	cmd.RegisterCmd("futures-sync", "download perpetual futures funding rates and open interest into the database (auxiliary data for strategy filters, also for spot trading)", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange to download from (default: binance)")
+		args.String(&pairList, "pairs", "comma-separated pairs BASE/QUOTE (e.g., BTC/USDT,ETH/USDT), overrides config FuturesData.Pairs")
+		args.String(&startDate, "start", "first funding rate sync start date in UTC (YYYY-MM-DD), overrides config FuturesData.StartDate")
+		args.String(&period, "period", "open interest period (5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d), overrides config FuturesData.OpenInterestPeriod")
+		args.Parse()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+
+		config := trading.TradingConfigValue.FuturesData
+		if len(config.Pairs) == 0 {
+			config.Pairs = trading.TradingConfigValue.Sync.Pairs
+		}
+		if config.StartDate == "" {
+			config.StartDate = trading.TradingConfigValue.Sync.StartDate
+		}
+		if pairList != "" {
+			config.Pairs = strings.Split(pairList, ",")
+		}
+		if startDate != "" {
+			config.StartDate = startDate
+		}
+		if period != "" {
+			config.OpenInterestPeriod = period
+		}
+
+		if err := runFuturesSync(cexName, config); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// runFuturesSync 依次同步每个交易对同名永续合约的资金费率和持仓量
+func runFuturesSync(cexName string, config trading.FuturesDataConfig) error {
+	pairs, err := trading.ParseTradingPairs(config.Pairs)
+	if err != nil {
+		return err
+	}
+	if len(pairs) == 0 {
+		return fmt.Errorf("nothing to sync: configure FuturesData.Pairs (or Sync.Pairs) or use -pairs")
+	}
+	startTime, err := trading.SyncConfig{StartDate: config.StartDate}.StartTime()
+	if err != nil {
+		return err
+	}
+
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	provider, ok := client.(cex.FuturesDataProvider)
+	if !ok {
+		return fmt.Errorf("%s does not provide futures funding rates and open interest", cexName)
+	}
+	db, err := trading.GetPostgresDB(client)
+	if err != nil {
+		return err
+	}
+	syncer, err := trading.NewFuturesDataSyncer(provider, db, config, trading.TradingConfigValue.Sync.RequestIntervalMs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔄 Futures Data Sync")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("🏢 Exchange: %s\n", cexName)
+	fmt.Printf("📊 Pairs: %s (same-name perpetual contracts)\n", strings.Join(config.Pairs, ", "))
+	fmt.Printf("📅 Funding Rates Start: %s UTC\n", startTime.Format("2006-01-02"))
+	fmt.Printf("📈 Open Interest: %s period, last 30 days only\n", config.OpenInterestPeriod)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Stopping sync, progress is saved...")
+		cancel()
+	}()
+
+	var failed int
+	for _, pair := range pairs {
+		fmt.Printf("\n⬇️ Syncing %s futures data...\n", pair.String())
+		result, err := syncer.Sync(ctx, pair, startTime)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("sync cancelled")
+			}
+			fmt.Printf("❌ %s failed: %v\n", pair.String(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s: +%d funding rates (latest %s), +%d open interest (latest %s)\n", result.Symbol,
+			result.FundingRates, formatSyncTime(result.LatestFunding), result.OpenInterest, formatSyncTime(result.LatestOpenInterest))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d sync job(s) failed", failed)
+	}
+	fmt.Println("\n🎉 Sync completed")
+	return nil
+}
+
+// formatSyncTime 同步进度时间，无数据时为 "-"
+func formatSyncTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04")
+}
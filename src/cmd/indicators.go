@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tradingbot/src/i18n"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// 指标预览的输出格式
+const (
+	indicatorFormatChart = "chart"
+	indicatorFormatCSV   = "csv"
+	indicatorFormatHTML  = "html"
+)
+
+// RegisterIndicatorsCmd 注册指标预览命令
+func RegisterIndicatorsCmd() {
+	var cexName string
+	var base string
+	var quote string
+	var timeframe string
+	var startDate string
+	var endDate string
+	var specs string
+	var format string
+	var out string
+	width := 100
+	height := 16
+
+	cmd.RegisterCmd("indicators", "compute Bollinger Bands and other indicators over a date range and print CSV, an ASCII chart or an HTML chart", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange (default: binance)")
+		args.String(&base, "base", "base currency (e.g., BTC)")
+		args.String(&quote, "quote", "quote currency (default: USDT)")
+		args.String(&timeframe, "t", "timeframe (default: config Timeframe)")
+		args.String(&startDate, "start", "start date (YYYY-MM-DD, YYYY-MM-DD HH:MM or YYYY-MM-DD HH:MM:SS) - required")
+		args.String(&endDate, "end", "end date (default: now)")
+		args.String(&specs, "i", "comma separated indicators: bb[:period[:multiplier]], atr[:period], adx[:period], stoch[:k[:smooth[:d]]], vwap[:period], obv (default: bb:20:2)")
+		args.String(&format, "format", "chart, csv or html (default: from -out extension, otherwise chart)")
+		args.String(&out, "out", "write to this file instead of stdout")
+		args.Int(&width, "width", "chart: columns of the ASCII chart, bars are sampled when there are more")
+		args.Int(&height, "height", "chart: rows of the price panel, indicator panels use half")
+		args.Parse()
+
+		if err := runIndicators(cexName, base, quote, timeframe, startDate, endDate, specs, format, out, width, height); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// runIndicators 加载区间K线（含预热），计算指标并按格式输出
+func runIndicators(cexName, base, quote, timeframe, startDate, endDate, specValue, format, out string, width, height int) error {
+	if startDate == "" {
+		return fmt.Errorf("-start is required (e.g., -start 2024-01-01)")
+	}
+	if endDate == "" {
+		endDate = time.Now().Format("2006-01-02 15:04:05")
+	}
+	startTime, endTime, err := trading.ParseBacktestRange(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	if !endTime.After(startTime) {
+		return fmt.Errorf("-end must be after -start")
+	}
+	if format == "" {
+		format = indicatorFormatChart
+		switch strings.ToLower(filepath.Ext(out)) {
+		case ".csv":
+			format = indicatorFormatCSV
+		case ".html", ".htm":
+			format = indicatorFormatHTML
+		}
+	}
+	if format != indicatorFormatChart && format != indicatorFormatCSV && format != indicatorFormatHTML {
+		return fmt.Errorf("unknown -format %q (supported: %s, %s, %s)", format, indicatorFormatChart, indicatorFormatCSV, indicatorFormatHTML)
+	}
+	if timeframe == "" {
+		timeframe = trading.TradingConfigValue.Timeframe
+	}
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	specs, err := trading.ParseIndicatorSpecs(specValue)
+	if err != nil {
+		return err
+	}
+
+	pair, client, err := marketArgs(cexName, base, quote)
+	if err != nil {
+		return err
+	}
+	klines, err := trading.LoadIndicatorKlines(context.Background(), client, pair, tf, startTime, endTime, trading.IndicatorWarmup(specs))
+	if err != nil {
+		return err
+	}
+	preview, err := trading.ComputeIndicatorPreview(klines, specs, startTime)
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, len(specs))
+	for i, spec := range specs {
+		labels[i] = spec.Label()
+	}
+	startLabel, endLabel := startTime.Format("2006-01-02 15:04"), endTime.Format("2006-01-02 15:04")
+	if len(preview.Rows) == 0 {
+		fmt.Println(i18n.T("indicators.empty", pair.String(), tf, startLabel, endLabel))
+		return nil
+	}
+	title := i18n.T("indicators.title", pair.String(), tf, startLabel, endLabel, len(preview.Rows), strings.Join(labels, ", "))
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case indicatorFormatCSV:
+		err = trading.WriteIndicatorCSV(w, preview)
+	case indicatorFormatHTML:
+		err = trading.WriteIndicatorHTML(w, preview, title)
+	default:
+		fmt.Fprintln(w, title)
+		trading.WriteIndicatorChart(w, preview, width, height)
+	}
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		fmt.Println(i18n.T("indicators.written", format, out))
+	}
+	return nil
+}
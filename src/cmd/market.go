@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/i18n"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterMarketCmds 注册行情查看命令（price、klines）
+func RegisterMarketCmds() {
+	registerPriceCmd()
+	registerKlinesCmd()
+}
+
+// registerPriceCmd 注册当前价格命令
+func registerPriceCmd() {
+	var cexName string
+	var base string
+	var quote string
+
+	cmd.RegisterCmd("price", "show the current price and best bid/ask of a symbol", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange (default: binance)")
+		args.String(&base, "base", "base currency (e.g., BTC)")
+		args.String(&quote, "quote", "quote currency (default: USDT)")
+		args.Parse()
+
+		if err := runPrice(cexName, base, quote); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// registerKlinesCmd 注册K线查看命令
+func registerKlinesCmd() {
+	var cexName string
+	var base string
+	var quote string
+	var timeframe string
+	var source string
+	var csvOut bool
+	limit := 20
+
+	cmd.RegisterCmd("klines", "print the last N klines of a symbol/timeframe from the exchange or the database", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange (default: binance)")
+		args.String(&base, "base", "base currency (e.g., BTC)")
+		args.String(&quote, "quote", "quote currency (default: USDT)")
+		args.String(&timeframe, "t", "timeframe (default: config Timeframe)")
+		args.Int(&limit, "n", fmt.Sprintf("number of klines, at most %d", trading.MaxInspectKlines))
+		args.String(&source, "source", "exchange or db (default: exchange)")
+		args.Bool(&csvOut, "csv", "print CSV instead of a table")
+		args.Parse()
+
+		if err := runKlines(cexName, base, quote, timeframe, source, limit, csvOut); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// marketArgs 解析交易对并创建交易所客户端
+func marketArgs(cexName, base, quote string) (cex.TradingPair, cex.CEXClient, error) {
+	if base == "" {
+		return cex.TradingPair{}, nil, fmt.Errorf("-base is required (e.g., -base BTC -quote USDT)")
+	}
+	if cexName == "" {
+		cexName = "binance"
+	}
+	if quote == "" {
+		quote = "USDT"
+	}
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return cex.TradingPair{}, nil, fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	return trading.CreateTradingPair(base, quote), client, nil
+}
+
+// runPrice 查询并输出当前价格
+func runPrice(cexName, base, quote string) error {
+	pair, client, err := marketArgs(cexName, base, quote)
+	if err != nil {
+		return err
+	}
+	priceQuote, err := trading.FetchPriceQuote(context.Background(), client, pair)
+	if err != nil {
+		return err
+	}
+	trading.WritePriceQuote(os.Stdout, priceQuote)
+	return nil
+}
+
+// runKlines 获取最近的K线并输出表格或CSV，数据库来源时提示缺失的K线
+func runKlines(cexName, base, quote, timeframe, source string, limit int, csvOut bool) error {
+	if limit <= 0 || limit > trading.MaxInspectKlines {
+		return fmt.Errorf("-n must be between 1 and %d", trading.MaxInspectKlines)
+	}
+	if timeframe == "" {
+		timeframe = trading.TradingConfigValue.Timeframe
+	}
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	if source == "" {
+		source = trading.KlineSourceExchange
+	}
+
+	pair, client, err := marketArgs(cexName, base, quote)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var klines []*cex.KlineData
+	switch source {
+	case trading.KlineSourceExchange:
+		klines, err = trading.FetchRecentKlines(ctx, client, pair, tf, limit)
+	case trading.KlineSourceDB:
+		db, dbErr := trading.GetPostgresDB(client)
+		if dbErr != nil {
+			return dbErr
+		}
+		klines, err = trading.LoadRecentKlines(ctx, db, pair, tf, limit)
+	default:
+		return fmt.Errorf("unknown -source %q (supported: %s, %s)", source, trading.KlineSourceExchange, trading.KlineSourceDB)
+	}
+	if err != nil {
+		return err
+	}
+
+	if csvOut {
+		return trading.WriteKlinesCSV(os.Stdout, klines)
+	}
+	if len(klines) == 0 {
+		fmt.Println(i18n.T("klines.empty", pair.String(), tf, source))
+		return nil
+	}
+	fmt.Println(i18n.T("klines.title", pair.String(), tf, source, len(klines)))
+	trading.WriteKlinesTable(os.Stdout, klines)
+	if gaps := trading.CountKlineGaps(klines, tf); gaps > 0 {
+		fmt.Println(i18n.T("klines.gaps", gaps))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"tradingbot/src/scaffold"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterNewStrategyCmd 注册新策略脚手架生成命令
+func RegisterNewStrategyCmd() {
+	var root string
+
+	cmd.RegisterCmd("new-strategy", "generate a new strategy scaffold (new-strategy NAME)", func(args *arg.Arg) {
+		args.String(&root, "dir", "project root directory (default: current directory)")
+		args.Parse()
+
+		rest := args.FlagSet.Args()
+		if len(rest) != 1 {
+			fmt.Printf("❌ Error: strategy name is required\n")
+			fmt.Printf("💡 Usage: ./bin/tradingbot new-strategy [-dir ROOT] NAME (e.g. mean-reversion)\n")
+			os.Exit(1)
+		}
+		if root == "" {
+			root = "."
+		}
+
+		names, err := scaffold.ParseStrategyName(rest[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		written, err := scaffold.GenerateStrategy(root, names)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Generated %s strategy scaffold:\n", names.Display)
+		for _, path := range written {
+			fmt.Printf("   📄 %s\n", path)
+		}
+		fmt.Println("\n💡 Next steps:")
+		fmt.Printf("   1. Implement OnData in src/strategies/%s_strategy.go\n", names.Snake)
+		fmt.Printf("   2. go test ./src/strategies/ -run %sStrategy\n", names.Camel)
+		fmt.Printf("   3. ./bin/tradingbot %s -base BTC -quote USDT -start 2024-01-01\n", names.Command)
+	})
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/pairs"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterPairsCmd 注册配对交易回测命令
+func RegisterPairsCmd() {
+	var symbolA string
+	var symbolB string
+	var timeframe string
+	var cex string
+	var startDate string
+	var endDate string
+	var initialCapital float64
+	var lookback int
+	var entryZ float64
+	var exitZ float64
+	var stopZ float64
+	var noStop bool
+	var maxBars int
+	var hedge string
+	var size float64
+	var borrowRate float64
+
+	cmd.RegisterCmd("pairs", "backtest a two-legged pairs (statistical arbitrage) strategy on the z-score of the spread between two symbols", func(args *arg.Arg) {
+		args.String(&symbolA, "a", "leg A symbol, BASE/QUOTE (e.g., ETH/USDT) - required")
+		args.String(&symbolB, "b", "leg B symbol, BASE/QUOTE (e.g., BTC/USDT) - required")
+		args.String(&timeframe, "t", "timeframe (e.g., 1h, 4h, 1d; default: 1h)")
+		args.String(&cex, "cex", "centralized exchange (default: binance)")
+		args.String(&startDate, "start", "backtest start date (YYYY-MM-DD) - required")
+		args.String(&endDate, "end", "backtest end date (YYYY-MM-DD)")
+		args.Float64(&initialCapital, "capital", "initial capital (default: 10000.0)")
+		args.Int(&lookback, "lookback", "bars used for the spread mean, standard deviation and OLS hedge ratio (default: 60)")
+		args.Float64(&entryZ, "entry-z", "open when |z-score| reaches this level (default: 2.0)")
+		args.Float64(&exitZ, "exit-z", "close when |z-score| reverts within this level (default: 0.5)")
+		args.Float64(&stopZ, "stop-z", "close when |z-score| widens to this level (default: 4.0)")
+		args.Bool(&noStop, "no-stop", "disable the z-score stop")
+		args.Int(&maxBars, "max-bars", "close after holding N bars (default: 0, unlimited)")
+		args.String(&hedge, "hedge", "hedge ratio: ratio (equal notional, spread = ln A/B) or ols (rolling regression beta; default: ratio)")
+		args.Float64(&size, "size", "leg A notional as a fraction of portfolio value, leg B is hedge × leg A (default: 0.5)")
+		args.Float64(&borrowRate, "borrow-rate", "annual borrow rate charged on the short leg, e.g. 0.1 = 10% (default: 0)")
+		args.Parse()
+
+		if symbolA == "" || symbolB == "" || startDate == "" {
+			fmt.Printf("❌ Error: -a, -b and -start are required\n")
+			printPairsUsage()
+			os.Exit(1)
+		}
+		if endDate == "" {
+			endDate = time.Now().Format("2006-01-02 15:04:05")
+		}
+		if timeframe == "" {
+			timeframe = "1h"
+		}
+		if cex == "" {
+			cex = "binance"
+		}
+		if initialCapital == 0 {
+			initialCapital = 10000.0
+		}
+
+		params := pairs.GetDefaultParams()
+		if lookback != 0 {
+			params.Lookback = lookback
+		}
+		if entryZ != 0 {
+			params.EntryZ = entryZ
+		}
+		if exitZ != 0 {
+			params.ExitZ = exitZ
+		}
+		if stopZ != 0 {
+			params.StopZ = stopZ
+		}
+		if noStop {
+			params.StopZ = 0
+		}
+		params.MaxHoldingBars = maxBars
+		if hedge != "" {
+			params.Hedge = pairs.HedgeMethod(hedge)
+		}
+		if size != 0 {
+			params.PositionSizePercent = size
+		}
+		if err := params.Validate(); err != nil {
+			fmt.Printf("❌ invalid pairs parameters: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := runPairsBacktest(symbolA, symbolB, timeframe, cex, startDate, endDate, initialCapital, borrowRate, params); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printPairsUsage 打印配对交易命令用法
+func printPairsUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot pairs -a ETH/USDT -b BTC/USDT -start 2024-01-01 [-end 2024-06-30] [-t 1h] [-lookback 60] [-entry-z 2] [-exit-z 0.5] [-hedge ratio|ols]\n")
+}
+
+// runPairsBacktest 加载两个交易对的K线，对齐后回测配对交易
+func runPairsBacktest(symbolA, symbolB, timeframe, cexName, startDate, endDate string, initialCapital, borrowRate float64, params *pairs.Params) error {
+	legs, err := trading.ParseTradingPairs([]string{symbolA, symbolB})
+	if err != nil {
+		return err
+	}
+	if legs[0] == legs[1] {
+		return fmt.Errorf("legs A and B must be different symbols, got %s twice", legs[0].String())
+	}
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return fmt.Errorf("invalid timeframe: %w", err)
+	}
+	startTime, endTime, err := trading.ParseBacktestRange(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	fmt.Println("⚖️ Pairs Trading Backtest")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Legs: A=%s, B=%s\n", legs[0].String(), legs[1].String())
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("🏢 Exchange: %s\n", cexName)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+	fmt.Printf("📐 Spread: hedge=%s, lookback=%d, entry |z|>=%.2f, exit |z|<=%.2f", params.Hedge, params.Lookback, params.EntryZ, params.ExitZ)
+	if params.StopZ > 0 {
+		fmt.Printf(", stop |z|>=%.2f", params.StopZ)
+	}
+	if params.MaxHoldingBars > 0 {
+		fmt.Printf(", max %d bars", params.MaxHoldingBars)
+	}
+	fmt.Printf("\n📦 Size: leg A %.0f%% of portfolio value, short leg borrow rate %.2f%%/year\n", params.PositionSizePercent*100, borrowRate*100)
+
+	tradingSystem, err := trading.NewTradingSystem()
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer tradingSystem.Stop()
+	if err := tradingSystem.SetTradingPairTimeframeAndCEX(legs[0], timeframe, cexName); err != nil {
+		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+	feeRate, err := tradingSystem.TakerFeeRate()
+	if err != nil {
+		return err
+	}
+
+	// 开始时间之前多加载 lookback 根K线用于计算价差
+	fmt.Println("📊 Loading historical data...")
+	warmupStart := startTime.Add(-time.Duration(params.Lookback) * duration)
+	klinesA, err := tradingSystem.LoadBacktestKlines(legs[0], tf, warmupStart, endTime)
+	if err != nil {
+		return fmt.Errorf("%s: %w", legs[0].String(), err)
+	}
+	klinesB, err := tradingSystem.LoadBacktestKlines(legs[1], tf, warmupStart, endTime)
+	if err != nil {
+		return fmt.Errorf("%s: %w", legs[1].String(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		fmt.Println("\n🔄 Cancelling pairs backtest...")
+		cancel()
+	}()
+
+	feedA, feedB := engine.NewBacktestDataFeed(klinesA), engine.NewBacktestDataFeed(klinesB)
+	for _, feed := range []engine.DataFeed{feedA, feedB} {
+		if err := feed.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start data feed: %w", err)
+		}
+		defer feed.Stop()
+	}
+
+	result, err := pairs.Backtest(ctx, pairs.NewSyncFeed(feedA, feedB), params, pairs.Config{
+		SymbolA:        legs[0].String(),
+		SymbolB:        legs[1].String(),
+		InitialCapital: initialCapital,
+		FeeRate:        feeRate,
+		BorrowRate:     borrowRate,
+		Timeframe:      tf,
+		StartTime:      startTime,
+	})
+	if err != nil {
+		return fmt.Errorf("pairs backtest failed: %w", err)
+	}
+
+	printPairsResult(result)
+	return nil
+}
+
+// printPairsResult 打印配对交易回测的汇总和逐笔交易
+func printPairsResult(result *pairs.Result) {
+	fmt.Println("\n📊 PAIRS BACKTEST RESULTS")
+	fmt.Println(strings.Repeat("=", 110))
+	fmt.Printf("Bars: %d (skipped %d %s / %d %s bars missing on the other leg)\n", result.Bars,
+		result.SkippedBars[0], result.Config.SymbolA, result.SkippedBars[1], result.Config.SymbolB)
+	fmt.Printf("Final Portfolio: $%.2f\n", result.FinalValue)
+	fmt.Printf("Total Return: %.2f%%\n", result.TotalReturn*100)
+	fmt.Printf("Max Drawdown: %.2f%%\n", result.MaxDrawdownPercent)
+	fmt.Printf("Sharpe Ratio: %.2f\n", result.SharpeRatio)
+	winRate := 0.0
+	if len(result.Trades) > 0 {
+		winRate = float64(result.WinningTrades) / float64(len(result.Trades)) * 100
+	}
+	fmt.Printf("Trades: %d (win rate %.2f%%)\n", len(result.Trades), winRate)
+	fmt.Printf("Fees: $%.2f, Borrow Cost: $%.2f\n", result.Fees, result.BorrowCost)
+
+	if result.OpenLegs[0].Quantity != 0 {
+		fmt.Printf("\n📌 Open since %s (marked at the last close):\n", result.OpenSince.Format("2006-01-02 15:04"))
+		for _, leg := range result.OpenLegs {
+			fmt.Printf("   %-12s %14.6f @ %.6f\n", leg.Symbol, leg.Quantity, leg.EntryPrice)
+		}
+	}
+
+	if len(result.Trades) == 0 {
+		return
+	}
+	fmt.Println("\n📋 TRADES")
+	fmt.Println(strings.Repeat("-", 110))
+	fmt.Printf("%-4s  %-12s  %-16s  %-16s  %7s  %7s  %6s  %10s  %10s  %10s  %s\n",
+		"#", "Direction", "Entry", "Exit", "EntryZ", "ExitZ", "Hedge", "PnL A", "PnL B", "Net PnL", "Exit")
+	for i, trade := range result.Trades {
+		fmt.Printf("%-4d  %-12s  %-16s  %-16s  %7.2f  %7.2f  %6.2f  %10.2f  %10.2f  %10.2f  %s\n",
+			i+1,
+			trade.Direction,
+			trade.EntryTime.Format("2006-01-02 15:04"),
+			trade.ExitTime.Format("2006-01-02 15:04"),
+			trade.EntryZ,
+			trade.ExitZ,
+			trade.Hedge,
+			trade.Legs[0].PnL,
+			trade.Legs[1].PnL,
+			trade.PnL,
+			trade.ExitReason,
+		)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tradingbot/src/database"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterPnLCmd 注册盈亏报表命令
+func RegisterPnLCmd() {
+	var cexName string
+	var session string
+	var period string
+	var days int
+
+	cmd.RegisterCmd("pnl", "show daily/weekly realized and unrealized PnL from live and dry-run equity snapshots", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange whose database stores the snapshots (default: binance)")
+		args.String(&session, "session", "snapshot session id (omit to list sessions)")
+		args.String(&period, "period", "daily or weekly (default: daily)")
+		args.Int(&days, "days", "only include the last N days (default: all)")
+		args.Parse()
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+		pnlPeriod, err := trading.ParsePnLPeriod(period)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		db, err := openBacktestDatabase(cexName)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		if session == "" {
+			err = listEquitySessions(ctx, db)
+		} else {
+			err = showPnL(ctx, db, session, pnlPeriod, days)
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// listEquitySessions 列出有账户快照的会话
+func listEquitySessions(ctx context.Context, db *database.PostgresDB) error {
+	sessions, err := db.ListEquitySessions(ctx)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		fmt.Println("📭 No equity snapshots (run --live or --dry to record them)")
+		return nil
+	}
+
+	fmt.Printf("📚 Equity Snapshot Sessions: %d\n", len(sessions))
+	fmt.Println(strings.Repeat("=", 110))
+	fmt.Printf("%-36s  %-10s  %-7s  %-33s  %9s  %14s\n", "Session", "Symbol", "Mode", "Period", "Snapshots", "Equity")
+	fmt.Println(strings.Repeat("=", 110))
+	for _, s := range sessions {
+		fmt.Printf("%-36s  %-10s  %-7s  %-33s  %9d  %14s\n",
+			s.SessionID, s.Symbol, s.Mode,
+			s.FirstTime.Format("2006-01-02 15:04")+" ~ "+s.LastTime.Format("2006-01-02 15:04"),
+			s.Snapshots, s.Equity.StringFixed(2))
+	}
+	fmt.Printf("💡 Usage: ./bin/tradingbot pnl -session <id> [-period daily|weekly] [-days N]\n")
+	return nil
+}
+
+// showPnL 按周期显示会话的盈亏
+func showPnL(ctx context.Context, db *database.PostgresDB, session string, period trading.PnLPeriod, days int) error {
+	var start time.Time
+	if days > 0 {
+		start = time.Now().UTC().AddDate(0, 0, -days)
+	}
+	snapshots, err := db.GetEquitySnapshots(ctx, session, start, time.Time{})
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("📭 No equity snapshots for session %s\n", session)
+		return nil
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	fmt.Printf("💰 %s PnL: session=%s, symbol=%s, mode=%s, %s ~ %s (UTC)\n", period, session, first.Symbol, first.Mode,
+		first.Time.Format("2006-01-02 15:04"), last.Time.Format("2006-01-02 15:04"))
+	trading.WritePnLReport(os.Stdout, trading.SummarizePnL(snapshots, period))
+	return nil
+}
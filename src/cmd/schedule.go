@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"tradingbot/src/dashboard"
+	"tradingbot/src/scheduler"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterScheduleCmd 注册定时任务命令（run 启动调度；其余子命令通过 REST 接口查看任务、审批参数提案）
+func RegisterScheduleCmd() {
+	var addr string
+
+	cmd.RegisterCmd("schedule", "scheduled backtests and re-optimization (run | jobs | trigger <job> | proposals | approve <id> | reject <id>)", func(args *arg.Arg) {
+		args.String(&addr, "addr", "scheduler API address (default: config dashboard LiveAddr, or 127.0.0.1:8080)")
+		args.Parse()
+
+		// 支持子命令后继续带参数: schedule approve <id> -addr 127.0.0.1:8080
+		rest := args.FlagSet.Args()
+		if len(rest) == 0 {
+			printScheduleUsage()
+			os.Exit(1)
+		}
+		subCmd := rest[0]
+		if err := args.FlagSet.Parse(rest[1:]); err != nil {
+			os.Exit(1)
+		}
+		rest = args.FlagSet.Args()
+
+		if addr == "" {
+			addr = dashboard.ConfigValue.LiveAddr
+		}
+		if addr == "" {
+			addr = "127.0.0.1:8080"
+		}
+
+		var err error
+		switch subCmd {
+		case "run":
+			err = runScheduler(addr)
+		case "jobs":
+			err = listScheduleJobs(addr)
+		case "proposals":
+			err = listProposals(addr)
+		case "trigger", "approve", "reject":
+			if len(rest) == 0 {
+				fmt.Printf("❌ Error: job name or proposal id is required\n")
+				printScheduleUsage()
+				os.Exit(1)
+			}
+			err = controlSchedule(addr, subCmd, rest[0])
+		default:
+			fmt.Printf("❌ Error: unknown subcommand %s\n", subCmd)
+			printScheduleUsage()
+			os.Exit(1)
+		}
+
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printScheduleUsage 打印定时任务命令用法
+func printScheduleUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot schedule run [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot schedule jobs [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot schedule trigger <job> [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot schedule proposals [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot schedule approve <id> [-addr HOST:PORT]\n")
+	fmt.Printf("          ./bin/tradingbot schedule reject <id> [-addr HOST:PORT]\n")
+	fmt.Printf("💡 Jobs also run inside 'bots run' when configured, so approved params restart running bots\n")
+}
+
+// runScheduler 单独运行定时任务（机器人不在本进程运行，批准的参数只写入机器人参数文件，下次启动生效）
+func runScheduler(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	manager, err := trading.NewBotManager(ctx, trading.TradingConfigValue.Bots)
+	if err != nil {
+		return err
+	}
+	service, err := scheduler.NewService(ctx, scheduler.ConfigValue, manager)
+	if err != nil {
+		return err
+	}
+
+	server := dashboard.NewServer(addr, nil, nil)
+	server.SetScheduleController(service)
+	listenAddr, err := server.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("⏰ Scheduler")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📋 Jobs: %d\n", len(scheduler.ConfigValue.Jobs))
+	fmt.Printf("🌐 API: http://%s/api/schedule/jobs (Ctrl+C to stop)\n", listenAddr)
+
+	service.Run()
+	fmt.Println("\n🔄 Scheduler stopped")
+	return nil
+}
+
+// listScheduleJobs 打印定时任务状态
+func listScheduleJobs(addr string) error {
+	var jobs []*dashboard.ScheduleJobStatus
+	if err := botsRequest(http.MethodGet, addr, "/api/schedule/jobs", &jobs); err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Println("📭 No schedule jobs configured")
+		return nil
+	}
+
+	fmt.Printf("⏰ Schedule Jobs: %d\n", len(jobs))
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("%-16s  %-8s  %-14s  %-7s  %-19s  %-19s\n", "Name", "Type", "Schedule", "State", "Last Run", "Next Run")
+	fmt.Println(strings.Repeat("=", 100))
+	for _, job := range jobs {
+		printScheduleJob(job)
+	}
+	return nil
+}
+
+// printScheduleJob 打印一行定时任务状态
+func printScheduleJob(job *dashboard.ScheduleJobStatus) {
+	state := "idle"
+	if job.Running {
+		state = "running"
+	}
+	lastRun, nextRun := "-", "-"
+	if job.LastRun != nil {
+		lastRun = job.LastRun.Local().Format("2006-01-02 15:04:05")
+	}
+	if job.NextRun != nil {
+		nextRun = job.NextRun.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%-16s  %-8s  %-14s  %-7s  %-19s  %-19s\n", job.Name, job.Type, job.Schedule, state, lastRun, nextRun)
+	if job.LastError != "" {
+		fmt.Printf("  ⚠️ %s\n", job.LastError)
+	}
+}
+
+// listProposals 打印参数提案
+func listProposals(addr string) error {
+	var proposals []*dashboard.ParamsProposal
+	if err := botsRequest(http.MethodGet, addr, "/api/schedule/proposals", &proposals); err != nil {
+		return err
+	}
+	if len(proposals) == 0 {
+		fmt.Println("📭 No params proposals")
+		return nil
+	}
+
+	fmt.Printf("📝 Params Proposals: %d\n", len(proposals))
+	fmt.Println(strings.Repeat("=", 100))
+	for _, proposal := range proposals {
+		printProposal(proposal)
+	}
+	return nil
+}
+
+// printProposal 打印提案及其参数
+func printProposal(proposal *dashboard.ParamsProposal) {
+	fmt.Printf("%s  bot=%s  state=%s  %s: %.4f -> %.4f  created=%s\n",
+		proposal.ID, proposal.Bot, proposal.State, proposal.Objective, proposal.CurrentScore, proposal.Score,
+		proposal.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("%s\n\n", proposal.Params)
+}
+
+// controlSchedule 立即运行任务，或批准 / 拒绝参数提案
+func controlSchedule(addr, action, name string) error {
+	if action == "trigger" {
+		var job dashboard.ScheduleJobStatus
+		if err := botsRequest(http.MethodPost, addr, fmt.Sprintf("/api/schedule/jobs/%s/run", name), &job); err != nil {
+			return err
+		}
+		fmt.Printf("🚀 Job %s started\n", name)
+		printScheduleJob(&job)
+		return nil
+	}
+
+	var proposal dashboard.ParamsProposal
+	if err := botsRequest(http.MethodPost, addr, fmt.Sprintf("/api/schedule/proposals/%s/%s", name, action), &proposal); err != nil {
+		return err
+	}
+	if action == "approve" {
+		fmt.Printf("✅ Proposal %s applied to bot %s\n", name, proposal.Bot)
+	} else {
+		fmt.Printf("🚫 Proposal %s rejected\n", name)
+	}
+	printProposal(&proposal)
+	return nil
+}
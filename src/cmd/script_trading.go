@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tradingbot/src/script"
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterScriptCmd 注册脚本策略回测命令
+func RegisterScriptCmd() {
+	var file string
+	var base string
+	var quote string
+	var timeframe string
+	var cex string
+	var startDate string
+	var endDate string
+	var initialCapital float64
+	var check bool
+	var resultOut string
+	var journalOut string
+
+	cmd.RegisterCmd("script", "backtest a script strategy (buy/sell expressions in a JSON file, no recompiling)", func(args *arg.Arg) {
+		args.String(&file, "file", "script strategy JSON file (buy, sell, vars, history) - required")
+		args.String(&base, "base", "base currency (e.g., BTC, ETH)")
+		args.String(&quote, "quote", "quote currency (e.g., USDT)")
+		args.String(&timeframe, "t", "timeframe (e.g., 1h, 4h, 1d)")
+		args.String(&cex, "cex", "centralized exchange (default: binance)")
+		args.String(&startDate, "start", "backtest start date (YYYY-MM-DD) - required")
+		args.String(&endDate, "end", "backtest end date (YYYY-MM-DD)")
+		args.Float64(&initialCapital, "capital", "initial capital (default: 10000.0)")
+		args.Bool(&check, "check", "only compile the buy/sell expressions and exit")
+		args.String(&resultOut, "result-out", "write backtest run, trades and equity curve to a JSON file (for 'backtests compare')")
+		args.String(&journalOut, "journal-out", "export completed trades with signal reasons, indicators and slippage to file (.csv or .json)")
+		args.Parse()
+
+		if file == "" {
+			fmt.Printf("❌ Error: -file is required\n")
+			printScriptUsage()
+			os.Exit(1)
+		}
+		params, err := loadScriptParams(file)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if check {
+			fmt.Printf("✅ %s: buy and sell expressions compiled\n", file)
+			return
+		}
+
+		if base == "" || quote == "" || startDate == "" {
+			fmt.Printf("❌ Error: -base, -quote and -start are required\n")
+			printScriptUsage()
+			os.Exit(1)
+		}
+		if endDate == "" {
+			endDate = time.Now().Format("2006-01-02 15:04:05")
+		}
+		if timeframe == "" {
+			timeframe = "4h"
+		}
+		if cex == "" {
+			cex = "binance"
+		}
+		if initialCapital == 0 {
+			initialCapital = 10000.0
+		}
+
+		if err := runScriptBacktest(base, quote, timeframe, cex, startDate, endDate, initialCapital, params, resultOut, journalOut); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// printScriptUsage 打印脚本策略命令用法
+func printScriptUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot script -file rsi.json -base BTC -quote USDT -start 2024-01-01 [-end 2024-06-30] [-t 4h]\n")
+	fmt.Printf("          ./bin/tradingbot script -file rsi.json -check\n")
+	fmt.Printf("💡 Functions: %s\n", strings.Join(script.Functions(), ", "))
+	fmt.Printf("💡 Variables: open, high, low, close, volume, %s and the file's vars\n", strings.Join(strategy.ScriptVars, ", "))
+}
+
+// loadScriptParams 从JSON文件加载脚本策略参数（未出现的字段使用默认值）并编译表达式
+func loadScriptParams(path string) (*strategy.ScriptParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script file: %w", err)
+	}
+	params := strategy.GetDefaultScriptParams()
+	if err := json.Unmarshal(data, params); err != nil {
+		return nil, fmt.Errorf("failed to parse script file %s: %w", path, err)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid script %s: %w", path, err)
+	}
+	return params, nil
+}
+
+// runScriptBacktest 运行脚本策略回测
+func runScriptBacktest(base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, params *strategy.ScriptParams, resultOut, journalOut string) error {
+	fmt.Println("📜 Script Strategy Backtest")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+	fmt.Printf("🟢 Buy: %s\n", params.Buy)
+	fmt.Printf("🔴 Sell: %s\n", params.Sell)
+
+	strategyImpl := strategies.NewScriptStrategy()
+	if err := strategyImpl.SetParams(params); err != nil {
+		return fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+	return runStrategyBacktest(base, quote, timeframe, cex, startDate, endDate, initialCapital, strategyImpl, resultOut, journalOut)
+}
+
+// runStrategyBacktest 用已设置参数的策略运行回测，打印结果并按需导出交易日志
+func runStrategyBacktest(base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, strategyImpl strategy.Strategy, resultOut, journalOut string) error {
+	tradingSystem, err := trading.NewTradingSystem()
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer tradingSystem.Stop()
+
+	pair := trading.CreateTradingPair(base, quote)
+	if err := tradingSystem.SetTradingPairTimeframeAndCEX(pair, timeframe, cex); err != nil {
+		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+
+	tradingSystem.SetBacktestResultFile(resultOut)
+	stats, err := tradingSystem.RunBacktestWithStrategy(pair, startDate, endDate, initialCapital, strategyImpl)
+	if err != nil {
+		return err
+	}
+
+	tradingSystem.PrintBacktestResults(pair, stats)
+	if err := exportTradeJournal(journalOut, stats); err != nil {
+		return err
+	}
+	if resultOut != "" {
+		fmt.Printf("📄 Backtest result written: %s\n", resultOut)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"tradingbot/src/dashboard"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterStatusCmd 注册运行状态命令（读取运行中机器人的监控面板接口）
+func RegisterStatusCmd() {
+	var addr string
+	var bot string
+	var jsonOut bool
+	var watch int
+
+	cmd.RegisterCmd("status", "show a running live/dry bot's latest kline, bands, position, pending orders and next bar close", func(args *arg.Arg) {
+		args.String(&addr, "addr", "dashboard or bot manager address (default: config dashboard LiveAddr, or 127.0.0.1:8080)")
+		args.String(&bot, "bot", "bot name when the bots are run by 'bots run' (omit for a single bot started with dashboard LiveAddr)")
+		args.Bool(&jsonOut, "json", "print the raw JSON snapshot")
+		args.Int(&watch, "watch", "refresh every N seconds until Ctrl+C (default: print once)")
+		args.Parse()
+
+		if addr == "" {
+			addr = dashboard.ConfigValue.LiveAddr
+		}
+		if addr == "" {
+			addr = "127.0.0.1:8080"
+		}
+		path := "/api/live"
+		if bot != "" {
+			path = fmt.Sprintf("/api/bots/%s/live", bot)
+		}
+
+		for {
+			if err := showStatus(addr, path, jsonOut); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			if watch <= 0 {
+				return
+			}
+			time.Sleep(time.Duration(watch) * time.Second)
+			fmt.Println()
+		}
+	})
+}
+
+// showStatus 读取实盘状态快照并打印
+func showStatus(addr, path string, jsonOut bool) error {
+	var snapshot dashboard.LiveSnapshot
+	if err := botsRequest(http.MethodGet, addr, path, &snapshot); err != nil {
+		return err
+	}
+	if jsonOut {
+		// 资金曲线、信号和成交较长，状态只输出当前值
+		snapshot.EquityCurve, snapshot.Signals, snapshot.Fills = nil, nil, nil
+		data, err := json.MarshalIndent(&snapshot, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(dashboard.FormatStatus(&snapshot, time.Now()))
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// RegisterSymbolsCmd 注册交易对信息同步命令
+func RegisterSymbolsCmd() {
+	var cexName string
+
+	cmd.RegisterCmd("symbols", "sync exchange symbols and their order filters into the database (sync)", func(args *arg.Arg) {
+		args.String(&cexName, "cex", "centralized exchange to sync from (default: binance)")
+		args.Parse()
+
+		// 支持子命令后继续带参数: symbols sync -cex bybit
+		rest := args.FlagSet.Args()
+		if len(rest) == 0 {
+			printSymbolsUsage()
+			os.Exit(1)
+		}
+		subCmd := rest[0]
+		if err := args.FlagSet.Parse(rest[1:]); err != nil {
+			os.Exit(1)
+		}
+
+		if cexName == "" {
+			cexName = "binance"
+		}
+
+		switch subCmd {
+		case "sync":
+			if err := runSymbolSync(cexName); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Printf("❌ Error: unknown subcommand %s\n", subCmd)
+			printSymbolsUsage()
+			os.Exit(1)
+		}
+	})
+}
+
+// printSymbolsUsage 打印交易对命令用法
+func printSymbolsUsage() {
+	fmt.Printf("💡 Usage: ./bin/tradingbot symbols sync [-cex binance|bybit]\n")
+}
+
+// runSymbolSync 拉取交易所全部现货交易对，写入 symbols 表
+func runSymbolSync(cexName string) error {
+	client, err := cex.CreateCEXClient(cexName)
+	if err != nil {
+		return fmt.Errorf("failed to create CEX client: %w", err)
+	}
+	db, err := trading.GetPostgresDB(client)
+	if err != nil {
+		return err
+	}
+	syncer, err := trading.NewSymbolSyncer(client, db)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔄 Symbols Sync")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("🏢 Exchange: %s\n", cexName)
+
+	result, err := syncer.Sync(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Saved %d trading symbols (%d listed on %s)\n", result.Trading, result.Total, cexName)
+	return nil
+}
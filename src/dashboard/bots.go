@@ -0,0 +1,127 @@
+package dashboard
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrBotNotFound 机器人不存在
+var ErrBotNotFound = errors.New("bot not found")
+
+// 机器人运行状态
+const (
+	BotStateStopped = "stopped"
+	BotStateRunning = "running"
+	BotStateFailed  = "failed" // 引擎异常退出
+)
+
+// BotStatus 机器人状态
+type BotStatus struct {
+	Name       string     `json:"name"`
+	Exchange   string     `json:"exchange"`
+	Symbol     string     `json:"symbol"`
+	Timeframe  string     `json:"timeframe"`
+	Strategy   string     `json:"strategy"`
+	DryRun     bool       `json:"dry_run"`
+	SignalOnly bool       `json:"signal_only"`
+	State      string     `json:"state"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	StoppedAt  *time.Time `json:"stopped_at,omitempty"`
+	Error      string     `json:"error,omitempty"`    // 异常退出原因
+	TraceID    string     `json:"trace_id,omitempty"` // 最近一次启动的跟踪ID，与日志 trace=<id> 对应
+}
+
+// BotController 多机器人管理（由 trading.BotManager 实现），名称不存在时返回 ErrBotNotFound
+type BotController interface {
+	BotStatuses() []*BotStatus
+	BotStatus(name string) (*BotStatus, error)
+	BotLive(name string) (*LiveSnapshot, error)
+	StartBot(name string) error
+	StopBot(name string) error
+}
+
+// SetBotController 设置多机器人管理，提供 /api/bots 接口（为空时不提供）
+func (s *Server) SetBotController(bots BotController) {
+	s.bots = bots
+}
+
+// handleListBots 机器人列表
+func (s *Server) handleListBots(w http.ResponseWriter, r *http.Request) {
+	if s.bots == nil {
+		writeError(w, http.StatusNotFound, "bot manager is not running in this process")
+		return
+	}
+	writeJSON(w, s.bots.BotStatuses())
+}
+
+// handleGetBot 单个机器人状态
+func (s *Server) handleGetBot(w http.ResponseWriter, r *http.Request) {
+	if s.bots == nil {
+		writeError(w, http.StatusNotFound, "bot manager is not running in this process")
+		return
+	}
+	status, err := s.bots.BotStatus(r.PathValue("name"))
+	if err != nil {
+		writeBotError(w, err)
+		return
+	}
+	writeJSON(w, status)
+}
+
+// handleBotLive 单个机器人的实盘状态
+func (s *Server) handleBotLive(w http.ResponseWriter, r *http.Request) {
+	if s.bots == nil {
+		writeError(w, http.StatusNotFound, "bot manager is not running in this process")
+		return
+	}
+	snapshot, err := s.bots.BotLive(r.PathValue("name"))
+	if err != nil {
+		writeBotError(w, err)
+		return
+	}
+	writeJSON(w, snapshot)
+}
+
+// handleStartBot 启动机器人，返回启动后的状态
+func (s *Server) handleStartBot(w http.ResponseWriter, r *http.Request) {
+	s.controlBot(w, r, true)
+}
+
+// handleStopBot 停止机器人，返回停止后的状态
+func (s *Server) handleStopBot(w http.ResponseWriter, r *http.Request) {
+	s.controlBot(w, r, false)
+}
+
+// controlBot 启动或停止机器人并返回机器人状态
+func (s *Server) controlBot(w http.ResponseWriter, r *http.Request, start bool) {
+	if s.bots == nil {
+		writeError(w, http.StatusNotFound, "bot manager is not running in this process")
+		return
+	}
+
+	name := r.PathValue("name")
+	action := s.bots.StopBot
+	if start {
+		action = s.bots.StartBot
+	}
+	if err := action(name); err != nil {
+		writeBotError(w, err)
+		return
+	}
+	status, err := s.bots.BotStatus(name)
+	if err != nil {
+		writeBotError(w, err)
+		return
+	}
+	writeJSON(w, status)
+}
+
+// writeBotError 机器人不存在返回 404，其余（如重复启动）返回 409
+func writeBotError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrBotNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusConflict, err.Error())
+}
@@ -0,0 +1,26 @@
+package dashboard
+
+import (
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// Config 监控面板配置
+type Config struct {
+	LiveAddr    string `json:"live_addr"`    // 实盘运行时面板监听地址（如 127.0.0.1:8080），为空时不启动
+	HistorySize int    `json:"history_size"` // 实盘资金曲线、信号和成交各保留的条数
+	HealthAddr  string `json:"health_addr"`  // 只提供 /healthz 的健康检查监听地址（如 0.0.0.0:8081，供容器探针使用），为空时不启动；面板也提供 /healthz
+}
+
+// ConfigValue 监控面板配置实例
+var ConfigValue = Config{
+	LiveAddr:    "",
+	HistorySize: 500,
+	HealthAddr:  "",
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("DASHBOARD", &ConfigValue)
+}
@@ -0,0 +1,120 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// 健康检查结果
+const (
+	HealthStatusOK        = "ok"
+	HealthStatusUnhealthy = "unhealthy"
+)
+
+// ComponentHealth 单个组件（如一个机器人的数据喂入）的健康状态
+type ComponentHealth struct {
+	Name    string      `json:"name"`
+	Healthy bool        `json:"healthy"`
+	Detail  interface{} `json:"detail,omitempty"`
+}
+
+// HealthReport /healthz 的响应：全部组件健康时 status 为 ok
+type HealthReport struct {
+	Status     string            `json:"status"`
+	Time       time.Time         `json:"time"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// HealthRegistry 进程内的健康检查集合，实盘运行时注册数据喂入看门狗等组件
+type HealthRegistry struct {
+	mu     sync.Mutex
+	checks map[string]func() ComponentHealth
+}
+
+// NewHealthRegistry 创建健康检查集合
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{checks: make(map[string]func() ComponentHealth)}
+}
+
+// HealthChecks 进程默认的健康检查集合，监控面板和健康检查服务的 /healthz 使用
+var HealthChecks = NewHealthRegistry()
+
+// Register 注册健康检查（同名时覆盖），返回注销函数
+func (r *HealthRegistry) Register(name string, check func() ComponentHealth) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.checks, name)
+	}
+}
+
+// Report 执行全部健康检查，按名称排序；没有注册组件时为 ok
+func (r *HealthRegistry) Report() HealthReport {
+	r.mu.Lock()
+	checks := make(map[string]func() ComponentHealth, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.Unlock()
+
+	report := HealthReport{Status: HealthStatusOK, Time: time.Now(), Components: make([]ComponentHealth, 0, len(checks))}
+	for name, check := range checks {
+		component := check()
+		component.Name = name
+		if !component.Healthy {
+			report.Status = HealthStatusUnhealthy
+		}
+		report.Components = append(report.Components, component)
+	}
+	sort.Slice(report.Components, func(i, j int) bool { return report.Components[i].Name < report.Components[j].Name })
+	return report
+}
+
+// HealthHandler /healthz：全部组件健康时返回 200，否则返回 503（供容器编排的存活/就绪探针使用）
+func HealthHandler(registry *HealthRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := registry.Report()
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
+
+// StartHealthServer 在独立地址上只提供 /healthz，ctx 取消后关闭
+func StartHealthServer(ctx context.Context, addr string, registry *HealthRegistry) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", HealthHandler(registry))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, logger := log.WithCtx(ctx)
+			logger.Error("健康检查服务异常退出", "error", err)
+		}
+	}()
+	return listener.Addr().String(), nil
+}
@@ -0,0 +1,55 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthz(t *testing.T) {
+	server := NewServer("", nil, nil)
+	server.health = NewHealthRegistry()
+	handler := server.Handler()
+
+	get := func() (int, HealthReport) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var report HealthReport
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		return recorder.Code, report
+	}
+
+	// 没有注册组件时健康
+	code, report := get()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusOK, report.Status)
+	assert.Empty(t, report.Components)
+
+	healthy := true
+	server.health.Register("feed/binance/BTC/USDT/4h", func() ComponentHealth {
+		return ComponentHealth{Healthy: healthy, Detail: map[string]int{"restarts": 0}}
+	})
+	unregister := server.health.Register("feed/bybit/ETH/USDT/1h", func() ComponentHealth {
+		return ComponentHealth{Healthy: true}
+	})
+
+	code, report = get()
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, report.Components, 2)
+	assert.Equal(t, "feed/binance/BTC/USDT/4h", report.Components[0].Name)
+
+	// 任一组件不健康时返回 503
+	healthy = false
+	code, report = get()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusUnhealthy, report.Status)
+	assert.False(t, report.Components[0].Healthy)
+
+	unregister()
+	_, report = get()
+	assert.Len(t, report.Components, 1)
+}
@@ -0,0 +1,244 @@
+package dashboard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// EquitySample 资金曲线采样点
+type EquitySample struct {
+	Time     time.Time       `json:"time"`
+	Price    decimal.Decimal `json:"price"`
+	Cash     decimal.Decimal `json:"cash"`
+	Position decimal.Decimal `json:"position"`
+	Equity   decimal.Decimal `json:"equity"`
+}
+
+// SignalRecord 策略信号记录
+type SignalRecord struct {
+	Time     time.Time       `json:"time"`
+	Type     string          `json:"type"`
+	Strength float64         `json:"strength"`
+	Reason   string          `json:"reason"`
+	Price    decimal.Decimal `json:"price"`
+}
+
+// FillRecord 成交记录
+type FillRecord struct {
+	Time       time.Time       `json:"time"`
+	Side       string          `json:"side"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	Price      decimal.Decimal `json:"price"`
+	Commission decimal.Decimal `json:"commission"`
+}
+
+// KlineSample 最近处理的K线
+type KlineSample struct {
+	OpenTime  time.Time       `json:"open_time"`
+	CloseTime time.Time       `json:"close_time"`
+	Open      decimal.Decimal `json:"open"`
+	High      decimal.Decimal `json:"high"`
+	Low       decimal.Decimal `json:"low"`
+	Close     decimal.Decimal `json:"close"`
+	Volume    decimal.Decimal `json:"volume"`
+}
+
+// LiveSnapshot 实盘状态快照
+type LiveSnapshot struct {
+	Running       bool                    `json:"running"`
+	TradingPair   string                  `json:"trading_pair"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	Kline         *KlineSample            `json:"kline,omitempty"`      // 最近处理的K线
+	Indicators    map[string]float64      `json:"indicators,omitempty"` // 最近一根K线的策略指标（如布林道上中下轨、%B）
+	Price         decimal.Decimal         `json:"price"`
+	Cash          decimal.Decimal         `json:"cash"`
+	Position      decimal.Decimal         `json:"position"`
+	CostBasis     decimal.Decimal         `json:"cost_basis"`     // 持仓成本（平均成本，含买入手续费；启动前已有的持仓按首根K线收盘价计）
+	UnrealizedPnL decimal.Decimal         `json:"unrealized_pnl"` // 持仓市值 - 持仓成本
+	Equity        decimal.Decimal         `json:"equity"`
+	PeakEquity    decimal.Decimal         `json:"peak_equity"` // 启动以来的最高权益
+	Drawdown      decimal.Decimal         `json:"drawdown"`    // 当前权益相对峰值的回撤比例（0.2 表示 20%）
+	Risk          string                  `json:"risk"`        // 最近一次风控状态变化
+	StopReason    string                  `json:"stop_reason"` // 引擎退出原因
+	PendingOrders []engine.PendingOrder   `json:"pending_orders"`
+	Latency       *engine.LatencySnapshot `json:"latency,omitempty"` // 实盘K线处理延迟、下单往返耗时和时钟偏差
+	EquityCurve   []EquitySample          `json:"equity_curve"`
+	Signals       []SignalRecord          `json:"signals"` // 最新的在前
+	Fills         []FillRecord            `json:"fills"`   // 最新的在前
+}
+
+// LiveState 订阅事件总线，维护实盘资金曲线、持仓和最近的信号、成交
+type LiveState struct {
+	mu          sync.RWMutex
+	historySize int
+	snapshot    LiveSnapshot
+
+	// 按成交记账的持仓数量，与账户持仓对齐后计算持仓成本
+	ledgerPosition decimal.Decimal
+}
+
+// NewLiveState 创建实盘状态，historySize 为资金曲线、信号和成交各自保留的条数
+func NewLiveState(historySize int) *LiveState {
+	if historySize <= 0 {
+		historySize = 500
+	}
+	return &LiveState{historySize: historySize, snapshot: LiveSnapshot{Running: true}}
+}
+
+// Subscribe 订阅事件总线
+func (s *LiveState) Subscribe(bus *engine.EventBus) {
+	bus.Subscribe(s.handle,
+		engine.EventKlineProcessed, engine.EventSignalGenerated, engine.EventOrderFilled,
+		engine.EventRisk, engine.EventEngineStopped, engine.EventEngineRestarted)
+}
+
+// handle 处理引擎事件
+func (s *LiveState) handle(ctx context.Context, event *engine.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := &s.snapshot
+	snapshot.TradingPair = event.TradingPair.String()
+	snapshot.UpdatedAt = event.Time
+
+	switch event.Type {
+	case engine.EventKlineProcessed:
+		kline := event.Kline
+		snapshot.Kline = &KlineSample{OpenTime: kline.OpenTime, CloseTime: kline.CloseTime, Open: kline.Open,
+			High: kline.High, Low: kline.Low, Close: kline.Close, Volume: kline.Volume}
+		snapshot.Indicators = event.Indicators
+		snapshot.PendingOrders = event.PendingOrders
+		snapshot.Latency = event.Latency
+		s.alignCostBasis(event.Portfolio.Position, kline.Close)
+		snapshot.UnrealizedPnL = event.Portfolio.Position.Mul(kline.Close).Sub(snapshot.CostBasis)
+
+		sample := EquitySample{
+			Time:     event.Time,
+			Price:    event.Kline.Close,
+			Cash:     event.Portfolio.Cash,
+			Position: event.Portfolio.Position,
+			Equity:   event.Portfolio.Cash.Add(event.Portfolio.Position.Mul(event.Kline.Close)),
+		}
+		snapshot.Price, snapshot.Cash, snapshot.Position, snapshot.Equity = sample.Price, sample.Cash, sample.Position, sample.Equity
+		if sample.Equity.GreaterThan(snapshot.PeakEquity) {
+			snapshot.PeakEquity = sample.Equity
+		}
+		snapshot.Drawdown = decimal.Zero
+		if snapshot.PeakEquity.IsPositive() {
+			snapshot.Drawdown = snapshot.PeakEquity.Sub(sample.Equity).Div(snapshot.PeakEquity)
+		}
+		snapshot.EquityCurve = appendBounded(snapshot.EquityCurve, sample, s.historySize)
+	case engine.EventSignalGenerated:
+		record := SignalRecord{
+			Time:     event.Time,
+			Type:     event.Signal.Type,
+			Strength: event.Signal.Strength,
+			Reason:   event.Signal.Reason,
+			Price:    event.Kline.Close,
+		}
+		snapshot.Signals = prependBounded(snapshot.Signals, record, s.historySize)
+	case engine.EventOrderFilled:
+		record := FillRecord{
+			Time:       event.Fill.Timestamp,
+			Side:       string(event.Fill.Side),
+			Quantity:   event.Fill.Quantity,
+			Price:      event.Fill.Price,
+			Commission: event.Fill.Commission,
+		}
+		if record.Time.IsZero() {
+			record.Time = event.Time
+		}
+		snapshot.Fills = prependBounded(snapshot.Fills, record, s.historySize)
+		s.applyFill(event.Fill)
+	case engine.EventRisk:
+		snapshot.Risk = string(event.Risk.Type) + ": " + event.Risk.Reason
+	case engine.EventEngineStopped:
+		snapshot.Running = false
+		snapshot.StopReason = event.Message
+	case engine.EventEngineRestarted:
+		snapshot.Running = true
+		snapshot.StopReason = ""
+	}
+}
+
+// applyFill 按平均成本记账：买入增加成本，卖出按比例扣减成本
+func (s *LiveState) applyFill(fill *executor.OrderResult) {
+	if !fill.Quantity.IsPositive() {
+		return
+	}
+	if fill.Side == executor.OrderSideBuy {
+		s.snapshot.CostBasis = s.snapshot.CostBasis.Add(fill.Quantity.Mul(fill.Price)).Add(fill.Commission)
+		s.ledgerPosition = s.ledgerPosition.Add(fill.Quantity)
+		return
+	}
+	closed := decimal.Min(fill.Quantity, s.ledgerPosition)
+	if s.ledgerPosition.IsPositive() {
+		s.snapshot.CostBasis = s.snapshot.CostBasis.Sub(s.snapshot.CostBasis.Mul(closed).Div(s.ledgerPosition))
+	}
+	s.ledgerPosition = s.ledgerPosition.Sub(closed)
+}
+
+// alignCostBasis 与账户持仓对齐：没有成交记录的增加部分（启动前已有、对账校正）按当前价格计成本，减少的部分按比例扣减
+func (s *LiveState) alignCostBasis(position, price decimal.Decimal) {
+	diff := position.Sub(s.ledgerPosition)
+	if diff.IsZero() {
+		return
+	}
+	if diff.IsPositive() {
+		s.snapshot.CostBasis = s.snapshot.CostBasis.Add(diff.Mul(price))
+	} else if s.ledgerPosition.IsPositive() {
+		s.snapshot.CostBasis = s.snapshot.CostBasis.Mul(position).Div(s.ledgerPosition)
+	}
+	s.ledgerPosition = position
+}
+
+// Snapshot 当前实盘状态（副本）
+func (s *LiveState) Snapshot() *LiveSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := s.snapshot
+	if s.snapshot.Kline != nil {
+		kline := *s.snapshot.Kline
+		snapshot.Kline = &kline
+	}
+	if s.snapshot.Indicators != nil {
+		snapshot.Indicators = make(map[string]float64, len(s.snapshot.Indicators))
+		for name, value := range s.snapshot.Indicators {
+			snapshot.Indicators[name] = value
+		}
+	}
+	snapshot.PendingOrders = append([]engine.PendingOrder(nil), s.snapshot.PendingOrders...)
+	if s.snapshot.Latency != nil {
+		latency := *s.snapshot.Latency
+		snapshot.Latency = &latency
+	}
+	snapshot.EquityCurve = append([]EquitySample(nil), s.snapshot.EquityCurve...)
+	snapshot.Signals = append([]SignalRecord(nil), s.snapshot.Signals...)
+	snapshot.Fills = append([]FillRecord(nil), s.snapshot.Fills...)
+	return &snapshot
+}
+
+// appendBounded 追加到末尾，超出上限时丢弃最早的
+func appendBounded[T any](items []T, item T, limit int) []T {
+	items = append(items, item)
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	return items
+}
+
+// prependBounded 插入到开头，超出上限时丢弃最早的
+func prependBounded[T any](items []T, item T, limit int) []T {
+	items = append([]T{item}, items...)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
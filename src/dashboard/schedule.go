@@ -0,0 +1,139 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrJobNotFound 定时任务不存在
+var ErrJobNotFound = errors.New("schedule job not found")
+
+// ErrProposalNotFound 参数提案不存在
+var ErrProposalNotFound = errors.New("params proposal not found")
+
+// 参数提案状态
+const (
+	ProposalStatePending    = "pending"    // 等待人工审批
+	ProposalStateApplied    = "applied"    // 已推送给机器人
+	ProposalStateRejected   = "rejected"   // 人工拒绝
+	ProposalStateSuperseded = "superseded" // 审批前同一机器人有了更新的提案
+)
+
+// ScheduleJobStatus 定时任务状态
+type ScheduleJobStatus struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	Schedule     string     `json:"schedule"`
+	Running      bool       `json:"running"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// ParamsProposal 定时优化得到的机器人参数提案
+type ParamsProposal struct {
+	ID           string          `json:"id"`
+	Job          string          `json:"job"`
+	Bot          string          `json:"bot"`
+	State        string          `json:"state"`
+	Objective    string          `json:"objective"`
+	Score        float64         `json:"score"`         // 新参数在优化区间上的得分
+	CurrentScore float64         `json:"current_score"` // 机器人当前参数在同一区间上的得分
+	Params       json.RawMessage `json:"params"`        // 新的策略参数（与机器人参数文件格式一致）
+	CreatedAt    time.Time       `json:"created_at"`
+	DecidedAt    *time.Time      `json:"decided_at,omitempty"`
+}
+
+// ScheduleController 定时回测和参数优化（由 scheduler.Service 实现），
+// 不存在时分别返回 ErrJobNotFound、ErrProposalNotFound
+type ScheduleController interface {
+	ScheduleJobs() []*ScheduleJobStatus
+	RunScheduleJob(name string) error
+	ParamsProposals() []*ParamsProposal
+	ApproveProposal(id string) (*ParamsProposal, error)
+	RejectProposal(id string) (*ParamsProposal, error)
+}
+
+// SetScheduleController 设置定时任务管理，提供 /api/schedule 接口（为空时不提供）
+func (s *Server) SetScheduleController(schedule ScheduleController) {
+	s.schedule = schedule
+}
+
+// handleListScheduleJobs 定时任务列表
+func (s *Server) handleListScheduleJobs(w http.ResponseWriter, r *http.Request) {
+	if s.schedule == nil {
+		writeError(w, http.StatusNotFound, "scheduler is not running in this process")
+		return
+	}
+	writeJSON(w, s.schedule.ScheduleJobs())
+}
+
+// handleRunScheduleJob 立即在后台运行定时任务
+func (s *Server) handleRunScheduleJob(w http.ResponseWriter, r *http.Request) {
+	if s.schedule == nil {
+		writeError(w, http.StatusNotFound, "scheduler is not running in this process")
+		return
+	}
+	name := r.PathValue("name")
+	if err := s.schedule.RunScheduleJob(name); err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	for _, job := range s.schedule.ScheduleJobs() {
+		if job.Name == name {
+			writeJSON(w, job)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, ErrJobNotFound.Error())
+}
+
+// handleListProposals 参数提案列表
+func (s *Server) handleListProposals(w http.ResponseWriter, r *http.Request) {
+	if s.schedule == nil {
+		writeError(w, http.StatusNotFound, "scheduler is not running in this process")
+		return
+	}
+	writeJSON(w, s.schedule.ParamsProposals())
+}
+
+// handleApproveProposal 批准提案并推送参数给机器人
+func (s *Server) handleApproveProposal(w http.ResponseWriter, r *http.Request) {
+	s.decideProposal(w, r, true)
+}
+
+// handleRejectProposal 拒绝提案
+func (s *Server) handleRejectProposal(w http.ResponseWriter, r *http.Request) {
+	s.decideProposal(w, r, false)
+}
+
+// decideProposal 批准或拒绝提案并返回提案
+func (s *Server) decideProposal(w http.ResponseWriter, r *http.Request, approve bool) {
+	if s.schedule == nil {
+		writeError(w, http.StatusNotFound, "scheduler is not running in this process")
+		return
+	}
+
+	decide := s.schedule.RejectProposal
+	if approve {
+		decide = s.schedule.ApproveProposal
+	}
+	proposal, err := decide(r.PathValue("id"))
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+	writeJSON(w, proposal)
+}
+
+// writeScheduleError 任务或提案不存在返回 404，其余（如任务正在运行、提案已处理）返回 409
+func writeScheduleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrJobNotFound) || errors.Is(err, ErrProposalNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusConflict, err.Error())
+}
@@ -0,0 +1,196 @@
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tradingbot/src/database"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// BacktestStore 回测记录查询（由 database.PostgresDB 实现）
+type BacktestStore interface {
+	ListBacktestRuns(ctx context.Context, symbol string, limit int) ([]*database.BacktestRun, error)
+	GetBacktestRun(ctx context.Context, id string) (*database.BacktestRun, error)
+	GetTrades(ctx context.Context, backtestRunID string) ([]*database.TradeRecord, error)
+}
+
+// BacktestDetail 回测详情：运行记录、逐笔成交和按已实现盈亏计算的资金曲线
+type BacktestDetail struct {
+	Run         *database.BacktestRun   `json:"run"`
+	Trades      []*database.TradeRecord `json:"trades"`
+	EquityCurve []EquityStep            `json:"equity_curve"`
+}
+
+// EquityStep 回测资金曲线点
+type EquityStep struct {
+	Time   time.Time       `json:"time"`
+	Equity decimal.Decimal `json:"equity"`
+}
+
+// Server 网页监控面板：实盘状态和历史回测浏览
+type Server struct {
+	addr      string
+	live      *LiveState         // 为空时不提供实盘状态
+	backtests BacktestStore      // 为空时不提供回测浏览
+	bots      BotController      // 为空时不提供多机器人管理
+	schedule  ScheduleController // 为空时不提供定时任务管理
+	health    *HealthRegistry    // /healthz 使用的健康检查集合
+}
+
+// NewServer 创建监控面板服务
+func NewServer(addr string, live *LiveState, backtests BacktestStore) *Server {
+	return &Server{addr: addr, live: live, backtests: backtests, health: HealthChecks}
+}
+
+// Handler 路由：静态页面和 JSON 接口
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", HealthHandler(s.health))
+	mux.HandleFunc("GET /api/live", s.handleLive)
+	mux.HandleFunc("GET /api/backtests", s.handleListBacktests)
+	mux.HandleFunc("GET /api/backtests/{id}", s.handleGetBacktest)
+	mux.HandleFunc("GET /api/bots", s.handleListBots)
+	mux.HandleFunc("GET /api/bots/{name}", s.handleGetBot)
+	mux.HandleFunc("GET /api/bots/{name}/live", s.handleBotLive)
+	mux.HandleFunc("POST /api/bots/{name}/start", s.handleStartBot)
+	mux.HandleFunc("POST /api/bots/{name}/stop", s.handleStopBot)
+	mux.HandleFunc("GET /api/schedule/jobs", s.handleListScheduleJobs)
+	mux.HandleFunc("POST /api/schedule/jobs/{name}/run", s.handleRunScheduleJob)
+	mux.HandleFunc("GET /api/schedule/proposals", s.handleListProposals)
+	mux.HandleFunc("POST /api/schedule/proposals/{id}/approve", s.handleApproveProposal)
+	mux.HandleFunc("POST /api/schedule/proposals/{id}/reject", s.handleRejectProposal)
+	mux.Handle("GET /", http.FileServerFS(static))
+	return mux
+}
+
+// Start 后台监听，ctx 取消后关闭（端口被占用等错误直接返回）
+func (s *Server) Start(ctx context.Context) (string, error) {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	server := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_, logger := log.WithCtx(ctx)
+			logger.Error("监控面板服务异常退出", "error", err)
+		}
+	}()
+	return listener.Addr().String(), nil
+}
+
+// handleLive 实盘状态
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	if s.live == nil {
+		writeError(w, http.StatusNotFound, "live trading is not running in this process")
+		return
+	}
+	writeJSON(w, s.live.Snapshot())
+}
+
+// handleListBacktests 回测记录列表，支持 symbol、limit 参数
+func (s *Server) handleListBacktests(w http.ResponseWriter, r *http.Request) {
+	if s.backtests == nil {
+		writeError(w, http.StatusServiceUnavailable, "backtest database is not available")
+		return
+	}
+
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := s.backtests.ListBacktestRuns(r.Context(), r.URL.Query().Get("symbol"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if runs == nil {
+		runs = []*database.BacktestRun{}
+	}
+	writeJSON(w, runs)
+}
+
+// handleGetBacktest 回测详情
+func (s *Server) handleGetBacktest(w http.ResponseWriter, r *http.Request) {
+	if s.backtests == nil {
+		writeError(w, http.StatusServiceUnavailable, "backtest database is not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	run, err := s.backtests.GetBacktestRun(r.Context(), id)
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	trades, err := s.backtests.GetTrades(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if trades == nil {
+		trades = []*database.TradeRecord{}
+	}
+	writeJSON(w, &BacktestDetail{Run: run, Trades: trades, EquityCurve: BacktestEquityCurve(run, trades)})
+}
+
+// BacktestEquityCurve 按卖出成交的已实现盈亏累计资金曲线，终点为回测期末资金
+func BacktestEquityCurve(run *database.BacktestRun, trades []*database.TradeRecord) []EquityStep {
+	equity := run.InitialCapital
+	curve := []EquityStep{{Time: run.StartTime, Equity: equity}}
+	for _, trade := range trades {
+		if trade.Side != "SELL" {
+			continue
+		}
+		equity = equity.Add(trade.PnL)
+		curve = append(curve, EquityStep{Time: trade.Timestamp, Equity: equity})
+	}
+	return append(curve, EquityStep{Time: run.EndTime, Equity: run.FinalCapital})
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError 输出 JSON 错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
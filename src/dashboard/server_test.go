@@ -0,0 +1,310 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBacktestStore 内存回测记录
+type memoryBacktestStore struct {
+	runs   []*database.BacktestRun
+	trades map[string][]*database.TradeRecord
+}
+
+func (s *memoryBacktestStore) ListBacktestRuns(ctx context.Context, symbol string, limit int) ([]*database.BacktestRun, error) {
+	var runs []*database.BacktestRun
+	for _, run := range s.runs {
+		if symbol == "" || run.Symbol == symbol {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (s *memoryBacktestStore) GetBacktestRun(ctx context.Context, id string) (*database.BacktestRun, error) {
+	for _, run := range s.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("backtest run %s %w", id, database.ErrNotFound)
+}
+
+func (s *memoryBacktestStore) GetTrades(ctx context.Context, backtestRunID string) ([]*database.TradeRecord, error) {
+	return s.trades[backtestRunID], nil
+}
+
+func newTestBacktestStore() *memoryBacktestStore {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &memoryBacktestStore{
+		runs: []*database.BacktestRun{{
+			ID: "run-1", Symbol: "BTCUSDT", Timeframe: "4h", StrategyName: "bollinger",
+			StartTime: start, EndTime: start.Add(30 * 24 * time.Hour),
+			InitialCapital: decimal.NewFromInt(1000), FinalCapital: decimal.NewFromInt(1080),
+		}},
+		trades: map[string][]*database.TradeRecord{"run-1": {
+			{Side: "BUY", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Timestamp: start.Add(time.Hour)},
+			{Side: "SELL", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(150), PnL: decimal.NewFromInt(50), Timestamp: start.Add(48 * time.Hour)},
+			{Side: "BUY", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(150), Timestamp: start.Add(72 * time.Hour)},
+			{Side: "SELL", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(180), PnL: decimal.NewFromInt(30), Timestamp: start.Add(96 * time.Hour)},
+		}},
+	}
+}
+
+func get(t *testing.T, handler http.Handler, url string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+	return recorder
+}
+
+func TestServer_Backtests(t *testing.T) {
+	handler := NewServer("", nil, newTestBacktestStore()).Handler()
+
+	resp := get(t, handler, "/api/backtests?symbol=BTCUSDT")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var runs []*database.BacktestRun
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &runs))
+	require.Len(t, runs, 1)
+
+	resp = get(t, handler, "/api/backtests/run-1")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var detail BacktestDetail
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &detail))
+	assert.Len(t, detail.Trades, 4)
+	require.Len(t, detail.EquityCurve, 4)
+	assert.Equal(t, "1050", detail.EquityCurve[1].Equity.String())
+	assert.Equal(t, "1080", detail.EquityCurve[3].Equity.String())
+
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/backtests/missing").Code)
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/backtests?limit=abc").Code)
+
+	// 实盘未运行
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/live").Code)
+}
+
+func TestServer_ServesStaticAssets(t *testing.T) {
+	handler := NewServer("", nil, nil).Handler()
+
+	resp := get(t, handler, "/")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "tradingbot")
+	assert.Equal(t, http.StatusOK, get(t, handler, "/app.js").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, handler, "/api/backtests").Code)
+}
+
+// memoryBotController 内存机器人管理
+type memoryBotController struct {
+	bots map[string]*BotStatus
+}
+
+func (c *memoryBotController) BotStatuses() []*BotStatus {
+	return []*BotStatus{c.bots["btc"]}
+}
+
+func (c *memoryBotController) BotStatus(name string) (*BotStatus, error) {
+	status, exists := c.bots[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrBotNotFound, name)
+	}
+	return status, nil
+}
+
+func (c *memoryBotController) BotLive(name string) (*LiveSnapshot, error) {
+	if _, err := c.BotStatus(name); err != nil {
+		return nil, err
+	}
+	return &LiveSnapshot{TradingPair: "BTC/USDT"}, nil
+}
+
+func (c *memoryBotController) StartBot(name string) error {
+	status, err := c.BotStatus(name)
+	if err != nil {
+		return err
+	}
+	if status.State == BotStateRunning {
+		return fmt.Errorf("bot %s is already running", name)
+	}
+	status.State = BotStateRunning
+	return nil
+}
+
+func (c *memoryBotController) StopBot(name string) error {
+	status, err := c.BotStatus(name)
+	if err != nil {
+		return err
+	}
+	status.State = BotStateStopped
+	return nil
+}
+
+func TestServer_Bots(t *testing.T) {
+	server := NewServer("", nil, nil)
+	handler := server.Handler()
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/bots").Code)
+
+	server.SetBotController(&memoryBotController{bots: map[string]*BotStatus{
+		"btc": {Name: "btc", Symbol: "BTC/USDT", State: BotStateStopped},
+	}})
+	handler = server.Handler()
+
+	resp := get(t, handler, "/api/bots")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var statuses []*BotStatus
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.Equal(t, BotStateStopped, statuses[0].State)
+
+	post := func(url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, url, nil))
+		return recorder
+	}
+
+	resp = post("/api/bots/btc/start")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var status BotStatus
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	assert.Equal(t, BotStateRunning, status.State)
+
+	// 重复启动返回 409，不存在返回 404
+	assert.Equal(t, http.StatusConflict, post("/api/bots/btc/start").Code)
+	assert.Equal(t, http.StatusNotFound, post("/api/bots/eth/start").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/bots/eth").Code)
+
+	resp = get(t, handler, "/api/bots/btc/live")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "BTC/USDT")
+
+	require.Equal(t, http.StatusOK, post("/api/bots/btc/stop").Code)
+	resp = get(t, handler, "/api/bots/btc")
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	assert.Equal(t, BotStateStopped, status.State)
+}
+
+// memoryScheduleController 内存定时任务管理
+type memoryScheduleController struct {
+	proposals []*ParamsProposal
+}
+
+func (c *memoryScheduleController) ScheduleJobs() []*ScheduleJobStatus {
+	return []*ScheduleJobStatus{{Name: "nightly", Type: "optimize", Schedule: "@daily"}}
+}
+
+func (c *memoryScheduleController) RunScheduleJob(name string) error {
+	if name != "nightly" {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	return nil
+}
+
+func (c *memoryScheduleController) ParamsProposals() []*ParamsProposal {
+	return c.proposals
+}
+
+func (c *memoryScheduleController) ApproveProposal(id string) (*ParamsProposal, error) {
+	return c.decide(id, ProposalStateApplied)
+}
+
+func (c *memoryScheduleController) RejectProposal(id string) (*ParamsProposal, error) {
+	return c.decide(id, ProposalStateRejected)
+}
+
+func (c *memoryScheduleController) decide(id, state string) (*ParamsProposal, error) {
+	for _, proposal := range c.proposals {
+		if proposal.ID != id {
+			continue
+		}
+		if proposal.State != ProposalStatePending {
+			return nil, fmt.Errorf("proposal %s is already %s", id, proposal.State)
+		}
+		proposal.State = state
+		return proposal, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrProposalNotFound, id)
+}
+
+func TestServer_Schedule(t *testing.T) {
+	server := NewServer("", nil, nil)
+	assert.Equal(t, http.StatusNotFound, get(t, server.Handler(), "/api/schedule/jobs").Code)
+
+	server.SetScheduleController(&memoryScheduleController{proposals: []*ParamsProposal{
+		{ID: "nightly-1", Bot: "btc", State: ProposalStatePending, Params: json.RawMessage(`{"period":25}`)},
+	}})
+	handler := server.Handler()
+	post := func(url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, url, nil))
+		return recorder
+	}
+
+	resp := get(t, handler, "/api/schedule/jobs")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"schedule":"@daily"`)
+	assert.Equal(t, http.StatusOK, post("/api/schedule/jobs/nightly/run").Code)
+	assert.Equal(t, http.StatusNotFound, post("/api/schedule/jobs/weekly/run").Code)
+
+	resp = get(t, handler, "/api/schedule/proposals")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"params":{"period":25}`)
+
+	resp = post("/api/schedule/proposals/nightly-1/approve")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var proposal ParamsProposal
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &proposal))
+	assert.Equal(t, ProposalStateApplied, proposal.State)
+
+	// 已处理返回 409，不存在返回 404
+	assert.Equal(t, http.StatusConflict, post("/api/schedule/proposals/nightly-1/reject").Code)
+	assert.Equal(t, http.StatusNotFound, post("/api/schedule/proposals/nightly-2/reject").Code)
+}
+
+func TestLiveState_TracksEvents(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus := engine.NewEventBus()
+	live := NewLiveState(2)
+	live.Subscribe(bus)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := int64(0); i < 3; i++ {
+		kline := &cex.KlineData{TradingPair: pair, Close: decimal.NewFromInt(100 + i)}
+		bus.Publish(ctx, &engine.Event{Type: engine.EventKlineProcessed, Time: now.Add(time.Duration(i) * time.Hour), TradingPair: pair,
+			Kline: kline, Portfolio: &executor.Portfolio{Cash: decimal.NewFromInt(500), Position: decimal.NewFromInt(5)}})
+	}
+	bus.Publish(ctx, &engine.Event{Type: engine.EventSignalGenerated, Time: now, TradingPair: pair,
+		Kline: &cex.KlineData{Close: decimal.NewFromInt(102)}, Signal: &strategy.Signal{Type: "BUY", Strength: 0.8, Reason: "lower band"}})
+	bus.Publish(ctx, &engine.Event{Type: engine.EventOrderFilled, Time: now, TradingPair: pair,
+		Fill: &executor.OrderResult{Side: executor.OrderSideBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(101), Success: true}})
+	bus.Publish(ctx, &engine.Event{Type: engine.EventEngineStopped, Time: now, TradingPair: pair, Message: "context cancelled"})
+
+	snapshot := live.Snapshot()
+	assert.Equal(t, "BTC/USDT", snapshot.TradingPair)
+	assert.Equal(t, "1010", snapshot.Equity.String())
+	require.Len(t, snapshot.EquityCurve, 2) // 只保留最近 2 条
+	assert.Equal(t, "101", snapshot.EquityCurve[0].Price.String())
+	require.Len(t, snapshot.Signals, 1)
+	assert.Equal(t, "lower band", snapshot.Signals[0].Reason)
+	require.Len(t, snapshot.Fills, 1)
+	assert.False(t, snapshot.Running)
+	assert.Equal(t, "context cancelled", snapshot.StopReason)
+
+	resp := get(t, NewServer("", live, nil).Handler(), "/api/live")
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, strings.Contains(resp.Body.String(), `"equity":"1010"`))
+}
@@ -0,0 +1,195 @@
+// 监控面板：实盘状态每 5 秒刷新，回测记录按需加载
+(function () {
+  const LIVE_REFRESH_MS = 5000;
+
+  const $ = (id) => document.getElementById(id);
+  const num = (value) => parseFloat(value || 0);
+  const fmt = (value, digits) => num(value).toLocaleString(undefined, { maximumFractionDigits: digits });
+  const time = (value) => new Date(value).toISOString().slice(0, 16).replace("T", " ");
+
+  async function getJSON(url) {
+    const resp = await fetch(url);
+    const body = await resp.json();
+    if (!resp.ok) {
+      throw new Error(body.error || resp.statusText);
+    }
+    return body;
+  }
+
+  function showError(id, err) {
+    const el = $(id);
+    el.textContent = err ? `❌ ${err.message}` : "";
+    el.hidden = !err;
+  }
+
+  function cell(text, className) {
+    const td = document.createElement("td");
+    td.textContent = text;
+    if (className) {
+      td.className = className;
+    }
+    return td;
+  }
+
+  function fillTable(id, rows, columns, onClick) {
+    const tbody = $(id);
+    tbody.replaceChildren();
+    for (const row of rows) {
+      const tr = document.createElement("tr");
+      for (const column of columns) {
+        const [text, className] = column(row);
+        tr.appendChild(cell(text, className));
+      }
+      if (onClick) {
+        tr.className = "clickable";
+        tr.addEventListener("click", () => onClick(row));
+      }
+      tbody.appendChild(tr);
+    }
+  }
+
+  // drawLine 在 SVG 中绘制折线（按数据范围缩放）
+  function drawLine(id, values) {
+    const svg = $(id);
+    svg.replaceChildren();
+    if (values.length < 2) {
+      return;
+    }
+    const min = Math.min(...values);
+    const max = Math.max(...values);
+    const span = max - min || 1;
+    const points = values.map((value, i) => {
+      const x = (i / (values.length - 1)) * 800;
+      const y = 230 - ((value - min) / span) * 220;
+      return `${x.toFixed(1)},${y.toFixed(1)}`;
+    });
+    const line = document.createElementNS("http://www.w3.org/2000/svg", "polyline");
+    line.setAttribute("points", points.join(" "));
+    svg.appendChild(line);
+  }
+
+  const sideClass = (side) => (side === "BUY" ? "buy" : "sell");
+
+  async function refreshLive() {
+    try {
+      const live = await getJSON("api/live");
+      showError("live-error", null);
+      $("live-content").hidden = false;
+      $("live-pair").textContent = live.trading_pair || "-";
+      $("live-status").textContent = live.running ? "🟢 running" : `🔴 stopped (${live.stop_reason})`;
+      $("live-price").textContent = fmt(live.price, 8);
+      $("live-equity").textContent = fmt(live.equity, 2);
+      $("live-cash").textContent = fmt(live.cash, 2);
+      $("live-position").textContent = fmt(live.position, 8);
+      $("live-drawdown").textContent = `${fmt(num(live.drawdown) * 100, 1)}%`;
+      $("live-risk").hidden = !live.risk;
+      $("live-risk").textContent = live.risk ? `🛡️ ${live.risk}` : "";
+
+      drawLine("live-chart", live.equity_curve.map((point) => num(point.equity)));
+      fillTable("live-signals", live.signals, [
+        (s) => [time(s.time)],
+        (s) => [s.type, sideClass(s.type)],
+        (s) => [fmt(s.price, 8)],
+        (s) => [s.strength.toFixed(1)],
+        (s) => [s.reason],
+      ]);
+      fillTable("live-fills", live.fills, [
+        (f) => [time(f.time)],
+        (f) => [f.side, sideClass(f.side)],
+        (f) => [fmt(f.quantity, 8)],
+        (f) => [fmt(f.price, 8)],
+        (f) => [fmt(f.commission, 8)],
+      ]);
+    } catch (err) {
+      $("live-content").hidden = true;
+      showError("live-error", err);
+    }
+  }
+
+  async function loadBacktests(symbol, limit) {
+    const query = new URLSearchParams({ limit: limit || 50 });
+    if (symbol) {
+      query.set("symbol", symbol.toUpperCase());
+    }
+    try {
+      const runs = await getJSON(`api/backtests?${query}`);
+      showError("backtests-error", null);
+      fillTable("backtests-list", runs, [
+        (r) => [time(r.created_at)],
+        (r) => [r.symbol],
+        (r) => [r.timeframe],
+        (r) => [r.strategy_name],
+        (r) => [`${r.start_time.slice(0, 10)} ~ ${r.end_time.slice(0, 10)}`],
+        (r) => [(num(r.total_return) * 100).toFixed(2), num(r.total_return) >= 0 ? "positive" : "negative"],
+        (r) => [num(r.max_drawdown).toFixed(2)],
+        (r) => [String(r.total_trades)],
+        (r) => [(num(r.win_rate) * 100).toFixed(2)],
+      ], (r) => loadBacktest(r.id));
+    } catch (err) {
+      showError("backtests-error", err);
+    }
+  }
+
+  async function loadBacktest(id) {
+    try {
+      const detail = await getJSON(`api/backtests/${encodeURIComponent(id)}`);
+      const run = detail.run;
+      $("backtest-detail").hidden = false;
+      $("backtest-title").textContent = `${run.strategy_name} · ${run.symbol} ${run.timeframe} · ${run.id}`;
+
+      const metrics = [
+        ["Initial", fmt(run.initial_capital, 2)],
+        ["Final", fmt(run.final_capital, 2)],
+        ["Return", `${(num(run.total_return) * 100).toFixed(2)}%`],
+        ["Max DD", `${num(run.max_drawdown).toFixed(2)}%`],
+        ["Sharpe", num(run.sharpe_ratio).toFixed(2)],
+        ["Trades", `${run.total_trades} (${run.winning_trades}W / ${run.losing_trades}L)`],
+        ["Commission", fmt(run.total_commission, 2)],
+      ];
+      $("backtest-metrics").replaceChildren(...metrics.map(([label, value]) => {
+        const card = document.createElement("div");
+        card.className = "card";
+        const span = document.createElement("span");
+        span.textContent = label;
+        const strong = document.createElement("strong");
+        strong.textContent = value;
+        card.append(span, strong);
+        return card;
+      }));
+
+      drawLine("backtest-chart", detail.equity_curve.map((point) => num(point.equity)));
+      fillTable("backtest-trades", detail.trades, [
+        (t) => [time(t.timestamp)],
+        (t) => [t.side, sideClass(t.side)],
+        (t) => [fmt(t.quantity, 8)],
+        (t) => [fmt(t.price, 8)],
+        (t) => [fmt(t.commission, 8)],
+        (t) => (t.side === "SELL" ? [fmt(t.pnl, 2), num(t.pnl) >= 0 ? "positive" : "negative"] : ["-"]),
+        (t) => [t.reason],
+      ]);
+    } catch (err) {
+      showError("backtests-error", err);
+    }
+  }
+
+  function showTab(name) {
+    for (const section of document.querySelectorAll(".tab")) {
+      section.hidden = section.id !== name;
+    }
+    for (const link of document.querySelectorAll("nav a")) {
+      link.classList.toggle("active", link.dataset.tab === name);
+    }
+  }
+
+  $("backtests-filter").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const form = new FormData(event.target);
+    loadBacktests(form.get("symbol"), form.get("limit"));
+  });
+  window.addEventListener("hashchange", () => showTab(location.hash.slice(1) || "live"));
+
+  showTab(location.hash.slice(1) || "live");
+  refreshLive();
+  setInterval(refreshLive, LIVE_REFRESH_MS);
+  loadBacktests();
+})();
@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingbot/src/precision"
+
+	"github.com/shopspring/decimal"
+)
+
+// bandIndicators 单独显示的布林道指标（其余指标按名称排序显示）
+var bandIndicators = []string{"bb_upper", "bb_middle", "bb_lower", "bb_percent_b", "bb_width"}
+
+// NextBarClose 最近K线之后第一个晚于 now 的收盘时间（最近K线尚未收盘时为其收盘时间），没有K线时返回零值
+func (s *LiveSnapshot) NextBarClose(now time.Time) time.Time {
+	if s.Kline == nil || s.Kline.CloseTime.IsZero() {
+		return time.Time{}
+	}
+	// 币安K线收盘时间为下一根开盘前 1ms，周期取整到秒
+	interval := s.Kline.CloseTime.Sub(s.Kline.OpenTime).Round(time.Second)
+	next := s.Kline.CloseTime
+	if interval <= 0 {
+		return next
+	}
+	if now.After(next) {
+		next = next.Add(interval * (now.Sub(next)/interval + 1))
+	}
+	return next
+}
+
+// FormatStatus 运行中机器人的状态文本：最近K线、布林道、持仓和未实现盈亏、挂单、距下一根K线收盘的时间
+func FormatStatus(snapshot *LiveSnapshot, now time.Time) string {
+	var b strings.Builder
+
+	state := "● running"
+	if !snapshot.Running {
+		state = "■ stopped: " + snapshot.StopReason
+	}
+	fmt.Fprintf(&b, "📊 %s  %s\n", snapshot.TradingPair, state)
+	if snapshot.Kline == nil {
+		b.WriteString("⏳ No kline processed yet\n")
+		return b.String()
+	}
+
+	kline := snapshot.Kline
+	fmt.Fprintf(&b, "🕯️ Kline %s: O %s  H %s  L %s  C %s  V %s\n", kline.OpenTime.Local().Format("2006-01-02 15:04"),
+		statusNumber(kline.Open), statusNumber(kline.High), statusNumber(kline.Low), statusNumber(kline.Close), statusNumber(kline.Volume))
+	if next := snapshot.NextBarClose(now); !next.IsZero() {
+		fmt.Fprintf(&b, "⏰ Next bar close: %s (in %s)\n", next.Local().Format("2006-01-02 15:04:05"), next.Sub(now).Round(time.Second))
+	}
+
+	if upper, ok := snapshot.Indicators["bb_upper"]; ok {
+		fmt.Fprintf(&b, "📈 Bands: upper %s  middle %s  lower %s", statusFloat(upper),
+			statusFloat(snapshot.Indicators["bb_middle"]), statusFloat(snapshot.Indicators["bb_lower"]))
+		if percentB, ok := snapshot.Indicators["bb_percent_b"]; ok {
+			fmt.Fprintf(&b, "  %%B %.3f", percentB)
+		}
+		if width, ok := snapshot.Indicators["bb_width"]; ok {
+			fmt.Fprintf(&b, "  width %.4f", width)
+		}
+		b.WriteString("\n")
+	}
+	if others := otherIndicators(snapshot.Indicators); others != "" {
+		fmt.Fprintf(&b, "🔢 Indicators: %s\n", others)
+	}
+
+	if snapshot.Position.IsPositive() {
+		fmt.Fprintf(&b, "💼 Position: %s @ avg %s, unrealized PnL %s", statusNumber(snapshot.Position),
+			statusNumber(snapshot.CostBasis.Div(snapshot.Position)), snapshot.UnrealizedPnL.StringFixed(2))
+		if snapshot.CostBasis.IsPositive() {
+			fmt.Fprintf(&b, " (%s%%)", snapshot.UnrealizedPnL.Div(snapshot.CostBasis).Mul(decimal.NewFromInt(100)).StringFixed(2))
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString("💼 Position: flat\n")
+	}
+	fmt.Fprintf(&b, "💰 Cash %s  Equity %s  Drawdown %s%%\n", snapshot.Cash.StringFixed(2), snapshot.Equity.StringFixed(2),
+		snapshot.Drawdown.Mul(decimal.NewFromInt(100)).StringFixed(2))
+	if snapshot.Risk != "" {
+		fmt.Fprintf(&b, "🛡️ Risk: %s\n", snapshot.Risk)
+	}
+	if latency := snapshot.Latency; latency != nil {
+		fmt.Fprintf(&b, "⏱️ Latency: data avg %.0fms max %.0fms (forming bars %d)  order avg %.0fms max %.0fms",
+			latency.DataDelay.AvgMs, latency.DataDelay.MaxMs, latency.FormingBars,
+			latency.OrderRoundTrip.AvgMs, latency.OrderRoundTrip.MaxMs)
+		if !latency.ClockCheckedAt.IsZero() {
+			fmt.Fprintf(&b, "  clock drift %+.0fms", latency.ClockDriftMs)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "📋 Pending orders: %d\n", len(snapshot.PendingOrders))
+	for _, order := range snapshot.PendingOrders {
+		fmt.Fprintf(&b, "   %-22s %-12s %14s @ %-14s %s\n", order.ID, order.Type, statusNumber(order.Quantity),
+			statusNumber(order.Price), order.Reason)
+	}
+	return b.String()
+}
+
+// otherIndicators 布林道以外的指标，按名称排序
+func otherIndicators(indicators map[string]float64) string {
+	var names []string
+	for name := range indicators {
+		if !slices.Contains(bandIndicators, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + statusFloat(indicators[name])
+	}
+	return strings.Join(parts, "  ")
+}
+
+// statusNumber 按有效数字显示价格和数量（低价币不显示成 0）
+func statusNumber(value decimal.Decimal) string {
+	return precision.FormatSignificant(value, 8)
+}
+
+// statusFloat 按有效数字显示指标值
+func statusFloat(value float64) string {
+	return statusNumber(decimal.NewFromFloat(value))
+}
@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveState_TracksStatus(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus := engine.NewEventBus()
+	live := NewLiveState(10)
+	live.Subscribe(bus)
+	ctx := context.Background()
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kline := &cex.KlineData{TradingPair: pair, OpenTime: open, CloseTime: open.Add(time.Hour - time.Millisecond),
+		Open: decimal.NewFromInt(100), High: decimal.NewFromInt(112), Low: decimal.NewFromInt(98), Close: decimal.NewFromInt(110), Volume: decimal.NewFromInt(7)}
+
+	// 买入 2 @ 100，卖出 1 后剩 1，成本按比例扣减为 100
+	bus.Publish(ctx, &engine.Event{Type: engine.EventOrderFilled, Time: open, TradingPair: pair,
+		Fill: &executor.OrderResult{Side: executor.OrderSideBuy, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(100), Success: true}})
+	bus.Publish(ctx, &engine.Event{Type: engine.EventOrderFilled, Time: open, TradingPair: pair,
+		Fill: &executor.OrderResult{Side: executor.OrderSideSell, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(105), Success: true}})
+	bus.Publish(ctx, &engine.Event{Type: engine.EventKlineProcessed, Time: open, TradingPair: pair, Kline: kline,
+		Portfolio:  &executor.Portfolio{Cash: decimal.NewFromInt(900), Position: decimal.NewFromInt(1)},
+		Indicators: map[string]float64{"bb_upper": 115, "bb_middle": 105, "bb_lower": 95, "bb_percent_b": 0.75, "rsi": 61.5},
+		PendingOrders: []engine.PendingOrder{{ID: "sl-1", Type: engine.PendingOrderTypeStopLoss, TradingPair: pair,
+			Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(95), Reason: "stop loss"}},
+		Latency: &engine.LatencySnapshot{FormingBars: 1, ClockDriftMs: 120}})
+
+	snapshot := live.Snapshot()
+	require.NotNil(t, snapshot.Kline)
+	assert.Equal(t, "112", snapshot.Kline.High.String())
+	assert.Equal(t, 0.75, snapshot.Indicators["bb_percent_b"])
+	assert.Equal(t, "100", snapshot.CostBasis.String())
+	assert.Equal(t, "10", snapshot.UnrealizedPnL.String())
+	require.Len(t, snapshot.PendingOrders, 1)
+	require.NotNil(t, snapshot.Latency)
+	assert.Equal(t, 1, snapshot.Latency.FormingBars)
+
+	// 快照是副本
+	snapshot.Indicators["bb_upper"] = 0
+	snapshot.PendingOrders[0].ID = "changed"
+	assert.Equal(t, float64(115), live.Snapshot().Indicators["bb_upper"])
+	assert.Equal(t, "sl-1", live.Snapshot().PendingOrders[0].ID)
+
+	// 账户持仓增加（启动前已有）按当前价格计成本
+	bus.Publish(ctx, &engine.Event{Type: engine.EventKlineProcessed, Time: open, TradingPair: pair, Kline: kline,
+		Portfolio: &executor.Portfolio{Cash: decimal.NewFromInt(900), Position: decimal.NewFromInt(2)}})
+	assert.Equal(t, "210", live.Snapshot().CostBasis.String())
+	assert.Equal(t, "10", live.Snapshot().UnrealizedPnL.String())
+}
+
+func TestLiveSnapshot_NextBarClose(t *testing.T) {
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := &LiveSnapshot{Kline: &KlineSample{OpenTime: open, CloseTime: open.Add(time.Hour - time.Millisecond)}}
+	closeTime := open.Add(time.Hour - time.Millisecond)
+
+	// 最近K线尚未收盘
+	assert.Equal(t, closeTime, snapshot.NextBarClose(open.Add(20*time.Minute)))
+	// 已收盘，跳到之后的收盘时间
+	assert.Equal(t, closeTime.Add(time.Hour), snapshot.NextBarClose(open.Add(70*time.Minute)))
+	assert.Equal(t, closeTime.Add(3*time.Hour), snapshot.NextBarClose(open.Add(3*time.Hour+10*time.Minute)))
+
+	assert.True(t, (&LiveSnapshot{}).NextBarClose(open).IsZero())
+}
+
+func TestFormatStatus(t *testing.T) {
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := &LiveSnapshot{
+		TradingPair: "BTC/USDT",
+		Running:     true,
+		Kline: &KlineSample{OpenTime: open, CloseTime: open.Add(time.Hour - time.Millisecond), Open: decimal.NewFromInt(100),
+			High: decimal.NewFromInt(112), Low: decimal.NewFromInt(98), Close: decimal.NewFromInt(110), Volume: decimal.NewFromInt(7)},
+		Indicators:    map[string]float64{"bb_upper": 115, "bb_middle": 105, "bb_lower": 95, "bb_percent_b": 0.75, "rsi": 61.5},
+		Position:      decimal.NewFromInt(2),
+		CostBasis:     decimal.NewFromInt(200),
+		UnrealizedPnL: decimal.NewFromInt(20),
+		Cash:          decimal.NewFromInt(780),
+		Equity:        decimal.NewFromInt(1000),
+		PendingOrders: []engine.PendingOrder{{ID: "sl-1", Type: engine.PendingOrderTypeStopLoss,
+			Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(95), Reason: "stop loss"}},
+		Latency: &engine.LatencySnapshot{DataDelay: engine.LatencyStat{Count: 2, AvgMs: 850, MaxMs: 1200},
+			OrderRoundTrip: engine.LatencyStat{Count: 1, AvgMs: 240, MaxMs: 240}, ClockDriftMs: -35, ClockCheckedAt: open},
+	}
+
+	text := FormatStatus(snapshot, open.Add(30*time.Minute))
+	assert.Contains(t, text, "BTC/USDT  ● running")
+	assert.Contains(t, text, "C 110")
+	assert.Contains(t, text, "(in 30m0s)")
+	assert.Contains(t, text, "upper 115  middle 105  lower 95  %B 0.750")
+	assert.Contains(t, text, "rsi=61.5")
+	assert.Contains(t, text, "Position: 2 @ avg 100, unrealized PnL 20.00 (10.00%)")
+	assert.Contains(t, text, "Pending orders: 1")
+	assert.Contains(t, text, "STOP_LOSS")
+	assert.Contains(t, text, "Latency: data avg 850ms max 1200ms (forming bars 0)  order avg 240ms max 240ms  clock drift -35ms")
+
+	snapshot.Position = decimal.Zero
+	assert.Contains(t, FormatStatus(snapshot, open), "Position: flat")
+	assert.Contains(t, FormatStatus(&LiveSnapshot{TradingPair: "BTC/USDT"}, open), "No kline processed yet")
+}
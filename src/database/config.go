@@ -1,6 +1,8 @@
 package database
 
 import (
+	"tradingbot/src/envconfig"
+
 	"github.com/xpwu/go-config/configs"
 )
 
@@ -14,6 +16,10 @@ type DatabaseConfig struct {
 	SSLMode      string `json:"sslmode"`        // SSL模式
 	MaxOpenConns int    `json:"max_open_conns"` // 最大连接数
 	MaxIdleConns int    `json:"max_idle_conns"` // 最大空闲连接数
+
+	HealthCheckSeconds         int `json:"health_check_seconds"`          // 连接健康检查间隔（秒），0 表示不检查、断线不暂存写入
+	ReconnectMaxBackoffSeconds int `json:"reconnect_max_backoff_seconds"` // 断线重连的最大退避间隔（秒）
+	WriteBufferSize            int `json:"write_buffer_size"`             // 断线期间最多暂存的写入数，超出时丢弃最早的写入（0 表示不限制）
 }
 
 // GlobalDatabaseConfig 全局数据库配置实例
@@ -26,6 +32,10 @@ var GlobalDatabaseConfig = DatabaseConfig{
 	SSLMode:      "disable",
 	MaxOpenConns: 25,
 	MaxIdleConns: 5,
+
+	HealthCheckSeconds:         defaultHealthSeconds,
+	ReconnectMaxBackoffSeconds: 60,
+	WriteBufferSize:            10000,
 }
 
 // GetDatabaseConfigForCEX 获取指定CEX的数据库配置
@@ -42,4 +52,5 @@ func GetDefaultDatabaseConfig(dbname string) DatabaseConfig {
 
 func init() {
 	configs.Unmarshal(&GlobalDatabaseConfig)
+	envconfig.Register("DB", &GlobalDatabaseConfig)
 }
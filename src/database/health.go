@@ -0,0 +1,392 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/xpwu/go-log/log"
+)
+
+// 健康检查和重连参数
+const (
+	healthPingTimeout    = 5 * time.Second
+	minReconnectBackoff  = time.Second
+	flushWriteTimeout    = 30 * time.Second
+	closeFlushTimeout    = 10 * time.Second
+	defaultHealthSeconds = 15
+)
+
+// IsConnectionError 是否为连接层错误（数据库重启、网络中断等），这类写入可以暂存后重试
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// 08: connection_exception；57P01-57P03: 管理员关闭、崩溃关闭、暂时无法连接
+		switch {
+		case pqErr.Code.Class() == "08":
+			return true
+		case pqErr.Code == "57P01", pqErr.Code == "57P02", pqErr.Code == "57P03":
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// HealthStatus 数据库连接健康状态
+type HealthStatus struct {
+	Healthy       bool
+	LastError     string
+	LastCheck     time.Time
+	DownSince     time.Time // 不可用的开始时间，健康时为零值
+	Reconnects    int       // 断开后恢复的次数
+	PendingWrites int       // 等待补写的写入数
+	DroppedWrites int       // 暂存队列已满丢弃的写入数
+}
+
+// pendingWrite 暂存的写入
+type pendingWrite struct {
+	name  string
+	write func(ctx context.Context) error
+}
+
+// writeBuffer 数据库短暂不可用时按顺序暂存写入，容量满时丢弃最早的写入
+type writeBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	pending  []pendingWrite
+	dropped  int
+}
+
+// newWriteBuffer 创建暂存队列
+func newWriteBuffer(capacity int) *writeBuffer {
+	return &writeBuffer{capacity: capacity}
+}
+
+// push 追加写入，返回被丢弃的最早写入（没有丢弃时 ok 为 false）
+func (b *writeBuffer) push(w pendingWrite) (dropped pendingWrite, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.capacity > 0 && len(b.pending) >= b.capacity {
+		dropped, ok = b.pending[0], true
+		b.pending = b.pending[1:]
+		b.dropped++
+	}
+	b.pending = append(b.pending, w)
+	return dropped, ok
+}
+
+// peek 最早的写入
+func (b *writeBuffer) peek() (pendingWrite, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) == 0 {
+		return pendingWrite{}, false
+	}
+	return b.pending[0], true
+}
+
+// pop 移除最早的写入
+func (b *writeBuffer) pop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) > 0 {
+		b.pending[0] = pendingWrite{}
+		b.pending = b.pending[1:]
+	}
+}
+
+// Len 等待补写的写入数
+func (b *writeBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+// Dropped 累计丢弃的写入数
+func (b *writeBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// connHealth 连接健康状态（由健康检查和写入失败更新）
+type connHealth struct {
+	healthy atomic.Bool
+
+	mu         sync.Mutex
+	lastError  string
+	lastCheck  time.Time
+	downSince  time.Time
+	reconnects int
+}
+
+// markUp 标记连接可用，返回是否从不可用恢复
+func (h *connHealth) markUp(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastCheck = now
+	recovered := !h.downSince.IsZero()
+	if recovered {
+		h.reconnects++
+		h.downSince = time.Time{}
+	}
+	h.healthy.Store(true)
+	return recovered
+}
+
+// markDown 标记连接不可用，返回是否刚刚断开
+func (h *connHealth) markDown(now time.Time, err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastCheck = now
+	h.lastError = err.Error()
+	wentDown := h.downSince.IsZero()
+	if wentDown {
+		h.downSince = now
+	}
+	h.healthy.Store(false)
+	return wentDown
+}
+
+// Health 连接健康状态和暂存队列情况
+func (p *PostgresDB) Health() HealthStatus {
+	p.health.mu.Lock()
+	status := HealthStatus{
+		Healthy:    p.health.healthy.Load(),
+		LastError:  p.health.lastError,
+		LastCheck:  p.health.lastCheck,
+		DownSince:  p.health.downSince,
+		Reconnects: p.health.reconnects,
+	}
+	p.health.mu.Unlock()
+	if p.buffer != nil {
+		status.PendingWrites = p.buffer.Len()
+		status.DroppedWrites = p.buffer.Dropped()
+	}
+	return status
+}
+
+// startHealthCheck 启动后台健康检查：定期 ping，断开后按指数退避重连，恢复后按顺序补写暂存的写入
+func (p *PostgresDB) startHealthCheck(interval, maxBackoff time.Duration, bufferSize int) {
+	if maxBackoff < minReconnectBackoff {
+		maxBackoff = minReconnectBackoff
+	}
+	p.healthInterval = interval
+	p.maxBackoff = maxBackoff
+	p.buffer = newWriteBuffer(bufferSize)
+	p.wake = make(chan struct{}, 1)
+	p.health.markUp(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.stopHealth = cancel
+	p.healthDone = make(chan struct{})
+	go p.monitor(ctx)
+}
+
+// monitor 健康检查循环，写入遇到连接错误时立即唤醒
+func (p *PostgresDB) monitor(ctx context.Context) {
+	defer close(p.healthDone)
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+		p.checkHealth(ctx)
+	}
+}
+
+// checkHealth 检查连接，不可用时重连直到恢复，然后补写暂存的写入
+func (p *PostgresDB) checkHealth(ctx context.Context) {
+	err := p.pingOnce(ctx)
+	for ctx.Err() == nil {
+		if err != nil {
+			p.setDown(ctx, err)
+			if !p.reconnect(ctx) {
+				return
+			}
+		} else {
+			p.setUp(ctx)
+		}
+		// 补写中途再次断开时回到重连
+		if err = p.flushPending(ctx); err == nil {
+			return
+		}
+	}
+}
+
+// reconnect 按指数退避重建连接直到 ping 成功，ctx 取消时返回 false
+func (p *PostgresDB) reconnect(ctx context.Context) bool {
+	_, logger := log.WithCtx(ctx)
+	backoff := minReconnectBackoff
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		// 丢弃空闲连接，避免复用数据库重启前的失效连接
+		p.resetIdleConns()
+		err := p.pingOnce(ctx)
+		if err == nil {
+			p.setUp(ctx)
+			return true
+		}
+		p.health.markDown(time.Now(), err)
+		logger.Warning(fmt.Sprintf("数据库重连失败: attempt=%d, backoff=%s, pending=%d, err=%v", attempt, backoff, p.buffer.Len(), err))
+
+		backoff *= 2
+		if backoff > p.maxBackoff {
+			backoff = p.maxBackoff
+		}
+	}
+}
+
+// flushPending 按顺序补写暂存的写入，遇到连接错误时停止并返回该错误，其他错误的写入记录日志后丢弃
+func (p *PostgresDB) flushPending(ctx context.Context) error {
+	_, logger := log.WithCtx(ctx)
+	flushed := 0
+	for {
+		w, ok := p.buffer.peek()
+		if !ok {
+			if flushed > 0 {
+				logger.Info(fmt.Sprintf("数据库暂存写入已补写: count=%d", flushed))
+			}
+			return nil
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, flushWriteTimeout)
+		err := w.write(writeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if IsConnectionError(err) {
+			return err
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("数据库暂存写入补写失败，已丢弃: write=%s, err=%v", w.name, err))
+		}
+		p.buffer.pop()
+		flushed++
+	}
+}
+
+// setUp 标记连接可用，从断开恢复时记录日志
+func (p *PostgresDB) setUp(ctx context.Context) {
+	status := p.Health()
+	if p.health.markUp(time.Now()) {
+		_, logger := log.WithCtx(ctx)
+		logger.Info(fmt.Sprintf("数据库连接已恢复: down=%s, pending=%d", time.Since(status.DownSince).Round(time.Second), status.PendingWrites))
+	}
+}
+
+// setDown 标记连接不可用，刚断开时记录日志
+func (p *PostgresDB) setDown(ctx context.Context, err error) {
+	if p.health.markDown(time.Now(), err) {
+		_, logger := log.WithCtx(ctx)
+		logger.Warning(fmt.Sprintf("数据库连接不可用，写入暂存等待重连: err=%v", err))
+	}
+}
+
+// pingOnce 带超时的连接检查
+func (p *PostgresDB) pingOnce(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthPingTimeout)
+	defer cancel()
+	if p.ping != nil {
+		return p.ping(ctx)
+	}
+	return p.db.PingContext(ctx)
+}
+
+// resetIdleConns 关闭所有空闲连接，之后的请求建立新连接
+func (p *PostgresDB) resetIdleConns() {
+	if p.db == nil {
+		return
+	}
+	p.db.SetMaxIdleConns(0)
+	p.db.SetMaxIdleConns(p.maxIdleConns)
+}
+
+// bufferedWrite 执行写入；连接不可用或已有暂存写入时排队，恢复后按顺序补写
+// 排队的写入返回 nil，未启用健康检查时直接执行
+func (p *PostgresDB) bufferedWrite(ctx context.Context, name string, write func(ctx context.Context) error) error {
+	if p.buffer == nil {
+		return write(ctx)
+	}
+	if !p.health.healthy.Load() || p.buffer.Len() > 0 {
+		p.enqueue(ctx, name, write)
+		return nil
+	}
+
+	err := write(ctx)
+	if err == nil || !IsConnectionError(err) || ctx.Err() != nil {
+		return err
+	}
+	p.setDown(ctx, err)
+	p.enqueue(ctx, name, write)
+	return nil
+}
+
+// enqueue 暂存写入并唤醒健康检查
+func (p *PostgresDB) enqueue(ctx context.Context, name string, write func(ctx context.Context) error) {
+	if dropped, ok := p.buffer.push(pendingWrite{name: name, write: write}); ok {
+		_, logger := log.WithCtx(ctx)
+		logger.Error(fmt.Sprintf("数据库暂存队列已满，丢弃最早的写入: write=%s, capacity=%d", dropped.name, p.buffer.capacity))
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stopHealthCheck 停止健康检查，连接可用时尝试补写剩余的暂存写入
+func (p *PostgresDB) stopHealthCheck() {
+	if p.stopHealth == nil {
+		return
+	}
+	p.stopHealth()
+	<-p.healthDone
+	p.stopHealth = nil
+
+	if p.buffer.Len() == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), closeFlushTimeout)
+	defer cancel()
+	if p.pingOnce(ctx) == nil {
+		_ = p.flushPending(ctx)
+	}
+	if n := p.buffer.Len(); n > 0 {
+		_, logger := log.WithCtx(ctx)
+		logger.Error(fmt.Sprintf("数据库关闭时仍有未补写的写入: count=%d", n))
+	}
+}
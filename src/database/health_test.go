@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBufferedTestDB 不连接数据库、启用暂存队列的 PostgresDB，ping 返回 *pingErr
+func newBufferedTestDB(capacity int, pingErr *error) *PostgresDB {
+	p := &PostgresDB{
+		buffer:     newWriteBuffer(capacity),
+		wake:       make(chan struct{}, 1),
+		maxBackoff: minReconnectBackoff,
+		ping:       func(context.Context) error { return *pingErr },
+	}
+	p.health.markUp(time.Now())
+	return p
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.False(t, IsConnectionError(nil))
+	assert.True(t, IsConnectionError(driver.ErrBadConn))
+	assert.True(t, IsConnectionError(fmt.Errorf("failed to save: %w", io.ErrUnexpectedEOF)))
+	assert.True(t, IsConnectionError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	assert.True(t, IsConnectionError(fmt.Errorf("wrapped: %w", &pq.Error{Code: "08006"})))
+	assert.True(t, IsConnectionError(&pq.Error{Code: "57P01"}))
+
+	assert.False(t, IsConnectionError(&pq.Error{Code: "23505"}), "unique violation is not a connection error")
+	assert.False(t, IsConnectionError(errors.New("syntax error")))
+	assert.False(t, IsConnectionError(context.Canceled))
+}
+
+func TestWriteBuffer_DropsOldestWhenFull(t *testing.T) {
+	b := newWriteBuffer(2)
+	_, dropped := b.push(pendingWrite{name: "a"})
+	assert.False(t, dropped)
+	b.push(pendingWrite{name: "b"})
+
+	oldest, dropped := b.push(pendingWrite{name: "c"})
+	require.True(t, dropped)
+	assert.Equal(t, "a", oldest.name)
+	assert.Equal(t, 2, b.Len())
+	assert.Equal(t, 1, b.Dropped())
+
+	w, ok := b.peek()
+	require.True(t, ok)
+	assert.Equal(t, "b", w.name)
+}
+
+func TestBufferedWrite_QueuesDuringOutageAndFlushesInOrder(t *testing.T) {
+	var pingErr error
+	p := newBufferedTestDB(10, &pingErr)
+	ctx := context.Background()
+
+	var written []string
+	down := true
+	write := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			if down {
+				return fmt.Errorf("failed to begin transaction: %w", driver.ErrBadConn)
+			}
+			written = append(written, name)
+			return nil
+		}
+	}
+
+	// 连接错误：写入暂存，不返回错误
+	require.NoError(t, p.bufferedWrite(ctx, "k1", write("k1")))
+	status := p.Health()
+	assert.False(t, status.Healthy)
+	assert.False(t, status.DownSince.IsZero())
+	assert.Equal(t, 1, status.PendingWrites)
+
+	// 断线期间的写入直接排队，保持顺序
+	down = false
+	require.NoError(t, p.bufferedWrite(ctx, "k2", write("k2")))
+	assert.Empty(t, written)
+	assert.Equal(t, 2, p.Health().PendingWrites)
+
+	// 连接恢复后按顺序补写
+	p.checkHealth(ctx)
+	assert.Equal(t, []string{"k1", "k2"}, written)
+	status = p.Health()
+	assert.True(t, status.Healthy)
+	assert.True(t, status.DownSince.IsZero())
+	assert.Equal(t, 1, status.Reconnects)
+	assert.Equal(t, 0, status.PendingWrites)
+
+	// 恢复后直接写入
+	require.NoError(t, p.bufferedWrite(ctx, "k3", write("k3")))
+	assert.Equal(t, []string{"k1", "k2", "k3"}, written)
+}
+
+func TestBufferedWrite_ReturnsNonConnectionErrors(t *testing.T) {
+	var pingErr error
+	p := newBufferedTestDB(10, &pingErr)
+
+	err := p.bufferedWrite(context.Background(), "trades", func(context.Context) error {
+		return &pq.Error{Code: "23503", Message: "foreign key violation"}
+	})
+	require.Error(t, err)
+	assert.True(t, p.Health().Healthy)
+	assert.Equal(t, 0, p.Health().PendingWrites)
+}
+
+func TestBufferedWrite_WithoutHealthCheckWritesDirectly(t *testing.T) {
+	p := &PostgresDB{}
+	err := p.bufferedWrite(context.Background(), "klines", func(context.Context) error {
+		return driver.ErrBadConn
+	})
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+}
+
+func TestFlushPending_StopsOnConnectionErrorAndDropsFailedWrites(t *testing.T) {
+	var pingErr error
+	p := newBufferedTestDB(10, &pingErr)
+	ctx := context.Background()
+
+	var written []string
+	p.buffer.push(pendingWrite{name: "bad", write: func(context.Context) error { return errors.New("invalid input") }})
+	p.buffer.push(pendingWrite{name: "ok", write: func(context.Context) error { written = append(written, "ok"); return nil }})
+	p.buffer.push(pendingWrite{name: "down", write: func(context.Context) error { return io.EOF }})
+	p.buffer.push(pendingWrite{name: "later", write: func(context.Context) error { written = append(written, "later"); return nil }})
+
+	err := p.flushPending(ctx)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []string{"ok"}, written)
+	assert.Equal(t, 2, p.buffer.Len(), "write failing with a connection error stays queued")
+}
+
+func TestReconnect_BacksOffUntilPingSucceeds(t *testing.T) {
+	pingErr := error(syscall.ECONNREFUSED)
+	p := newBufferedTestDB(10, &pingErr)
+	p.setDown(context.Background(), pingErr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(1200 * time.Millisecond)
+		cancel()
+	}()
+	assert.False(t, p.reconnect(ctx), "cancelled while the database stays down")
+	assert.False(t, p.Health().Healthy)
+
+	pingErr = nil
+	assert.True(t, p.reconnect(context.Background()))
+	assert.True(t, p.Health().Healthy)
+	assert.Equal(t, 1, p.Health().Reconnects)
+}
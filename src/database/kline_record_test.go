@@ -0,0 +1,45 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKlineRecord_RoundTrip(t *testing.T) {
+	openTime := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	kline := &cex.KlineData{
+		OpenTime:            openTime,
+		CloseTime:           openTime.Add(time.Hour - time.Millisecond),
+		Open:                decimal.RequireFromString("100.5"),
+		High:                decimal.RequireFromString("110"),
+		Low:                 decimal.RequireFromString("99"),
+		Close:               decimal.RequireFromString("105.25"),
+		Volume:              decimal.RequireFromString("12.5"),
+		QuoteVolume:         decimal.RequireFromString("1300"),
+		TakerBuyVolume:      decimal.RequireFromString("6"),
+		TakerBuyQuoteVolume: decimal.RequireFromString("640"),
+	}
+
+	record := NewKlineRecord("BTCUSDT", "1h", kline)
+	assert.Equal(t, "BTCUSDT", record.Symbol)
+	assert.Equal(t, "1h", record.Timeframe)
+	assert.Equal(t, openTime.UnixMilli(), record.OpenTime)
+	assert.Equal(t, kline.CloseTime.UnixMilli(), record.CloseTime)
+	assert.Len(t, record.values(), 12)
+
+	assert.Equal(t, kline, record.ToKlineData())
+}
+
+func TestKlineRecord_ToKlineDataUsesUTC(t *testing.T) {
+	local := time.Date(2024, 3, 1, 16, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	record := NewKlineRecord("ETHUSDT", "4h", &cex.KlineData{OpenTime: local, CloseTime: local.Add(4 * time.Hour)})
+
+	kline := record.ToKlineData()
+	assert.Equal(t, time.UTC, kline.OpenTime.Location())
+	assert.True(t, kline.OpenTime.Equal(local))
+}
@@ -3,19 +3,34 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 	"tradingbot/src/cex"
 
-	"github.com/lib/pq"
 	_ "github.com/lib/pq"
 	"github.com/shopspring/decimal"
 )
 
+// ErrNotFound 查询的记录不存在
+var ErrNotFound = errors.New("not found")
+
 // PostgresDB PostgreSQL数据库连接
 type PostgresDB struct {
-	db *sql.DB
+	db           *sql.DB
+	maxIdleConns int
+
+	// 健康检查和断线暂存（HealthCheckSeconds 为 0 时不启用）
+	health         connHealth
+	buffer         *writeBuffer
+	wake           chan struct{}
+	healthInterval time.Duration
+	maxBackoff     time.Duration
+	stopHealth     context.CancelFunc
+	healthDone     chan struct{}
+	ping           func(ctx context.Context) error // 测试替换连接检查
 }
 
 // KlineRecord K线数据记录
@@ -37,6 +52,49 @@ type KlineRecord struct {
 	UpdatedAt           time.Time       `json:"updated_at"`
 }
 
+// NewKlineRecord 将 cex.KlineData 转换为数据库记录（open_time/close_time 以毫秒时间戳存储）
+func NewKlineRecord(symbol, timeframe string, kline *cex.KlineData) *KlineRecord {
+	return &KlineRecord{
+		Symbol:              symbol,
+		Timeframe:           timeframe,
+		OpenTime:            kline.OpenTime.UnixMilli(),
+		CloseTime:           kline.CloseTime.UnixMilli(),
+		OpenPrice:           kline.Open,
+		HighPrice:           kline.High,
+		LowPrice:            kline.Low,
+		ClosePrice:          kline.Close,
+		Volume:              kline.Volume,
+		QuoteVolume:         kline.QuoteVolume,
+		TakerBuyVolume:      kline.TakerBuyVolume,
+		TakerBuyQuoteVolume: kline.TakerBuyQuoteVolume,
+	}
+}
+
+// ToKlineData 将数据库记录转换为 cex.KlineData（UTC 时间）
+func (r *KlineRecord) ToKlineData() *cex.KlineData {
+	return &cex.KlineData{
+		OpenTime:            time.UnixMilli(r.OpenTime).UTC(),
+		CloseTime:           time.UnixMilli(r.CloseTime).UTC(),
+		Open:                r.OpenPrice,
+		High:                r.HighPrice,
+		Low:                 r.LowPrice,
+		Close:               r.ClosePrice,
+		Volume:              r.Volume,
+		QuoteVolume:         r.QuoteVolume,
+		TakerBuyVolume:      r.TakerBuyVolume,
+		TakerBuyQuoteVolume: r.TakerBuyQuoteVolume,
+	}
+}
+
+// values K线写入参数，顺序与 INSERT INTO klines 的列一致
+func (r *KlineRecord) values() []interface{} {
+	return []interface{}{
+		r.Symbol, r.Timeframe, r.OpenTime, r.CloseTime,
+		r.OpenPrice, r.HighPrice, r.LowPrice, r.ClosePrice,
+		r.Volume, r.QuoteVolume, r.TakerBuyVolume, r.TakerBuyQuoteVolume,
+	}
+}
+
 // BacktestRun 回测运行记录
 type BacktestRun struct {
 	ID              string                 `json:"id"`
@@ -60,6 +118,9 @@ type BacktestRun struct {
 	Status          string                 `json:"status"`
 	CreatedAt       time.Time              `json:"created_at"`
 	CompletedAt     *time.Time             `json:"completed_at"`
+
+	// 复现清单（JSON）：代码版本、配置快照、数据哈希和随机种子，旧记录为空
+	Manifest json.RawMessage `json:"manifest,omitempty"`
 }
 
 // TradeRecord 交易记录
@@ -92,19 +153,88 @@ type SyncStatus struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-// NewPostgresDB 创建PostgreSQL数据库连接
+// TradingCalendarRecord 禁止交易时段记录
+type TradingCalendarRecord struct {
+	ID        int       `json:"id"`
+	Symbol    string    `json:"symbol"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EquitySnapshotRecord 实盘/Dry Run 账户快照：余额、持仓和按市价计算的权益
+type EquitySnapshotRecord struct {
+	ID            int64           `json:"id"`
+	SessionID     string          `json:"session_id"` // 实盘 live_<交易所>_<交易对>，Dry Run 为模拟盘会话名
+	Symbol        string          `json:"symbol"`
+	Mode          string          `json:"mode"` // live / testnet / dry
+	Time          time.Time       `json:"time"`
+	Price         decimal.Decimal `json:"price"` // 估值价格（K线收盘价）
+	Cash          decimal.Decimal `json:"cash"`
+	Position      decimal.Decimal `json:"position"`
+	Equity        decimal.Decimal `json:"equity"`             // 现金 + 持仓 × 价格
+	CostBasis     decimal.Decimal `json:"cost_basis"`         // 持仓成本（含买入手续费）
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`       // 会话累计已实现盈亏
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`     // 持仓市值 - 持仓成本
+	TraceID       string          `json:"trace_id,omitempty"` // 写入快照的运行跟踪ID，旧记录为空
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// BookTickerRecord 盘口买一卖一记录
+type BookTickerRecord struct {
+	Symbol      string          `json:"symbol"`
+	Time        time.Time       `json:"time"`
+	BidPrice    decimal.Decimal `json:"bid_price"`
+	BidQuantity decimal.Decimal `json:"bid_qty"`
+	AskPrice    decimal.Decimal `json:"ask_price"`
+	AskQuantity decimal.Decimal `json:"ask_qty"`
+}
+
+// SpreadHourStat 按 UTC 小时汇总的盘口价差，价差为 (卖一 - 买一) / 中间价
+type SpreadHourStat struct {
+	Hour       int
+	Samples    int
+	MeanSpread float64
+}
+
+// EquitySessionSummary 有账户快照的会话
+type EquitySessionSummary struct {
+	SessionID string
+	Symbol    string
+	Mode      string
+	FirstTime time.Time
+	LastTime  time.Time
+	Snapshots int
+	Equity    decimal.Decimal // 最近一条快照的权益
+}
+
+// NewPostgresDB 创建PostgreSQL数据库连接（连接池和健康检查参数取全局配置）
 func NewPostgresDB(host, port, user, password, dbname string, sslmode string) (*PostgresDB, error) {
+	config := GlobalDatabaseConfig
+	config.Host = host
+	config.Port = port
+	config.User = user
+	config.Password = password
+	config.DBName = dbname
+	config.SSLMode = sslmode
+	return OpenPostgresDB(config)
+}
+
+// OpenPostgresDB 按配置创建PostgreSQL数据库连接，并启动后台健康检查
+func OpenPostgresDB(config DatabaseConfig) (*PostgresDB, error) {
+	sslmode := config.SSLMode
 	if sslmode == "" {
 		sslmode = "disable"
 	}
 
 	var psqlInfo string
-	if password == "" {
+	if config.Password == "" {
 		psqlInfo = fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s",
-			host, port, user, dbname, sslmode)
+			config.Host, config.Port, config.User, config.DBName, sslmode)
 	} else {
 		psqlInfo = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			host, port, user, password, dbname, sslmode)
+			config.Host, config.Port, config.User, config.Password, config.DBName, sslmode)
 	}
 
 	db, err := sql.Open("postgres", psqlInfo)
@@ -115,19 +245,33 @@ func NewPostgresDB(host, port, user, password, dbname string, sslmode string) (*
 	// 测试连接
 	err = db.Ping()
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	// 设置连接池参数
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
+	maxOpenConns, maxIdleConns := config.MaxOpenConns, config.MaxIdleConns
+	if maxOpenConns <= 0 {
+		maxOpenConns = 25
+	}
+	if maxIdleConns <= 0 {
+		maxIdleConns = 5
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	return &PostgresDB{db: db}, nil
+	p := &PostgresDB{db: db, maxIdleConns: maxIdleConns}
+	if config.HealthCheckSeconds > 0 {
+		p.startHealthCheck(time.Duration(config.HealthCheckSeconds)*time.Second,
+			time.Duration(config.ReconnectMaxBackoffSeconds)*time.Second, config.WriteBufferSize)
+	}
+	return p, nil
 }
 
-// Close 关闭数据库连接
+// Close 停止健康检查并关闭数据库连接
 func (p *PostgresDB) Close() error {
+	p.stopHealthCheck()
 	return p.db.Close()
 }
 
@@ -137,6 +281,14 @@ func (p *PostgresDB) SaveKlines(ctx context.Context, symbol, timeframe string, k
 		return nil
 	}
 
+	klines = append([]*cex.KlineData(nil), klines...)
+	return p.bufferedWrite(ctx, "klines "+symbol+" "+timeframe, func(ctx context.Context) error {
+		return p.saveKlines(ctx, symbol, timeframe, klines)
+	})
+}
+
+// saveKlines 逐条写入K线
+func (p *PostgresDB) saveKlines(ctx context.Context, symbol, timeframe string, klines []*cex.KlineData) error {
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -179,11 +331,7 @@ func (p *PostgresDB) SaveKlines(ctx context.Context, symbol, timeframe string, k
 	defer stmt.Close()
 
 	for _, kline := range klines {
-		_, err = stmt.ExecContext(ctx,
-			symbol, timeframe, kline.OpenTime, kline.CloseTime,
-			kline.Open, kline.High, kline.Low, kline.Close,
-			kline.Volume, kline.QuoteVolume, kline.TakerBuyVolume, kline.TakerBuyQuoteVolume,
-		)
+		_, err = stmt.ExecContext(ctx, NewKlineRecord(symbol, timeframe, kline).values()...)
 		if err != nil {
 			return fmt.Errorf("failed to insert kline: %w", err)
 		}
@@ -198,7 +346,7 @@ func (p *PostgresDB) SaveKlinesBatch(ctx context.Context, symbol, timeframe stri
 		return nil
 	}
 
-	// 分批处理，避免SQL语句过长
+	// 分批处理，避免SQL语句过长；每批作为一次写入，断线时暂存
 	const batchSize = 100
 	for i := 0; i < len(klines); i += batchSize {
 		end := i + batchSize
@@ -206,7 +354,11 @@ func (p *PostgresDB) SaveKlinesBatch(ctx context.Context, symbol, timeframe stri
 			end = len(klines)
 		}
 
-		if err := p.saveBatch(ctx, symbol, timeframe, klines[i:end]); err != nil {
+		batch := append([]*cex.KlineData(nil), klines[i:end]...)
+		err := p.bufferedWrite(ctx, "klines "+symbol+" "+timeframe, func(ctx context.Context) error {
+			return p.saveBatch(ctx, symbol, timeframe, batch)
+		})
+		if err != nil {
 			return err
 		}
 	}
@@ -230,11 +382,7 @@ func (p *PostgresDB) saveBatch(ctx context.Context, symbol, timeframe string, kl
 		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
 			i*12+1, i*12+2, i*12+3, i*12+4, i*12+5, i*12+6, i*12+7, i*12+8, i*12+9, i*12+10, i*12+11, i*12+12))
 
-		valueArgs = append(valueArgs,
-			symbol, timeframe, kline.OpenTime, kline.CloseTime,
-			kline.Open, kline.High, kline.Low, kline.Close,
-			kline.Volume, kline.QuoteVolume, kline.TakerBuyVolume, kline.TakerBuyQuoteVolume,
-		)
+		valueArgs = append(valueArgs, NewKlineRecord(symbol, timeframe, kline).values()...)
 	}
 
 	query := `
@@ -314,17 +462,21 @@ func (p *PostgresDB) GetKlines(ctx context.Context, symbol, timeframe string, st
 
 	var klines []*cex.KlineData
 	for rows.Next() {
-		kline := &cex.KlineData{}
+		record := &KlineRecord{Symbol: symbol, Timeframe: timeframe}
+		var takerBuyVolume, takerBuyQuoteVolume decimal.NullDecimal
 		err := rows.Scan(
-			&kline.OpenTime, &kline.CloseTime,
-			&kline.Open, &kline.High, &kline.Low, &kline.Close,
-			&kline.Volume, &kline.QuoteVolume,
-			&kline.TakerBuyVolume, &kline.TakerBuyQuoteVolume,
+			&record.OpenTime, &record.CloseTime,
+			&record.OpenPrice, &record.HighPrice, &record.LowPrice, &record.ClosePrice,
+			&record.Volume, &record.QuoteVolume,
+			&takerBuyVolume, &takerBuyQuoteVolume,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan kline: %w", err)
 		}
-		klines = append(klines, kline)
+		// 旧数据的主动买入量可能为 NULL，按 0 处理
+		record.TakerBuyVolume = takerBuyVolume.Decimal
+		record.TakerBuyQuoteVolume = takerBuyQuoteVolume.Decimal
+		klines = append(klines, record.ToKlineData())
 	}
 
 	return klines, rows.Err()
@@ -349,7 +501,44 @@ func (p *PostgresDB) GetLatestKlineTime(ctx context.Context, symbol, timeframe s
 	return openTime.Int64, nil
 }
 
-// SaveBacktestRun 保存回测运行记录
+// GetKlineOpenTimes 获取已保存K线的开盘时间（毫秒，升序），用于检测缺口；startTime/endTime 为 0 表示不限制
+func (p *PostgresDB) GetKlineOpenTimes(ctx context.Context, symbol, timeframe string, startTime, endTime int64) ([]int64, error) {
+	query := "SELECT open_time FROM klines WHERE symbol = $1 AND timeframe = $2"
+	args := []interface{}{symbol, timeframe}
+	argIndex := 3
+
+	if startTime > 0 {
+		query += fmt.Sprintf(" AND open_time >= $%d", argIndex)
+		args = append(args, startTime)
+		argIndex++
+	}
+
+	if endTime > 0 {
+		query += fmt.Sprintf(" AND open_time <= $%d", argIndex)
+		args = append(args, endTime)
+	}
+
+	query += " ORDER BY open_time ASC"
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kline open times: %w", err)
+	}
+	defer rows.Close()
+
+	var openTimes []int64
+	for rows.Next() {
+		var openTime int64
+		if err := rows.Scan(&openTime); err != nil {
+			return nil, fmt.Errorf("failed to scan kline open time: %w", err)
+		}
+		openTimes = append(openTimes, openTime)
+	}
+
+	return openTimes, rows.Err()
+}
+
+// SaveBacktestRun 保存回测运行记录（ID为空时由数据库生成，并回填到 run.ID）
 func (p *PostgresDB) SaveBacktestRun(ctx context.Context, run *BacktestRun) error {
 	query := `
 		INSERT INTO backtest_runs (
@@ -357,27 +546,144 @@ func (p *PostgresDB) SaveBacktestRun(ctx context.Context, run *BacktestRun) erro
 			start_time, end_time, initial_capital, final_capital,
 			total_return, max_drawdown, sharpe_ratio, win_rate,
 			total_trades, winning_trades, losing_trades, total_commission,
-			status, completed_at
+			status, completed_at, manifest
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
-			$11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+			COALESCE(NULLIF($1, '')::uuid, uuid_generate_v4()), $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
 		)
+		RETURNING id
 	`
 
-	paramsJSON, err := pq.Array(run.StrategyParams).Value()
+	paramsJSON, err := json.Marshal(run.StrategyParams)
 	if err != nil {
 		return fmt.Errorf("failed to marshal strategy params: %w", err)
 	}
 
-	_, err = p.db.ExecContext(ctx, query,
+	var manifest interface{}
+	if len(run.Manifest) > 0 {
+		manifest = []byte(run.Manifest)
+	}
+
+	err = p.db.QueryRowContext(ctx, query,
 		run.ID, run.Name, run.Symbol, run.Timeframe, run.StrategyName, paramsJSON,
 		run.StartTime, run.EndTime, run.InitialCapital, run.FinalCapital,
 		run.TotalReturn, run.MaxDrawdown, run.SharpeRatio, run.WinRate,
 		run.TotalTrades, run.WinningTrades, run.LosingTrades, run.TotalCommission,
-		run.Status, run.CompletedAt,
+		run.Status, run.CompletedAt, manifest,
+	).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert backtest run: %w", err)
+	}
+
+	return nil
+}
+
+// backtestRunColumns 回测记录查询字段
+const backtestRunColumns = `
+	id, COALESCE(name, ''), symbol, timeframe, strategy_name, strategy_params,
+	start_time, end_time, initial_capital, final_capital,
+	total_return, max_drawdown, sharpe_ratio, win_rate,
+	COALESCE(total_trades, 0), COALESCE(winning_trades, 0), COALESCE(losing_trades, 0), total_commission,
+	COALESCE(status, ''), created_at, completed_at, manifest
+`
+
+// rowScanner 兼容 *sql.Row 和 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanBacktestRun 扫描一条回测记录
+func scanBacktestRun(row rowScanner) (*BacktestRun, error) {
+	var run BacktestRun
+	var paramsJSON []byte
+	var finalCapital, totalReturn, maxDrawdown, sharpeRatio, winRate, totalCommission decimal.NullDecimal
+	var completedAt sql.NullTime
+	var manifest []byte
+
+	err := row.Scan(
+		&run.ID, &run.Name, &run.Symbol, &run.Timeframe, &run.StrategyName, &paramsJSON,
+		&run.StartTime, &run.EndTime, &run.InitialCapital, &finalCapital,
+		&totalReturn, &maxDrawdown, &sharpeRatio, &winRate,
+		&run.TotalTrades, &run.WinningTrades, &run.LosingTrades, &totalCommission,
+		&run.Status, &run.CreatedAt, &completedAt, &manifest,
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	if len(paramsJSON) > 0 {
+		if err := json.Unmarshal(paramsJSON, &run.StrategyParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal strategy params: %w", err)
+		}
+	}
+
+	run.FinalCapital = finalCapital.Decimal
+	run.TotalReturn = totalReturn.Decimal
+	run.MaxDrawdown = maxDrawdown.Decimal
+	run.SharpeRatio = sharpeRatio.Decimal
+	run.WinRate = winRate.Decimal
+	run.TotalCommission = totalCommission.Decimal
+	if completedAt.Valid {
+		run.CompletedAt = &completedAt.Time
+	}
+	if len(manifest) > 0 {
+		run.Manifest = manifest
+	}
+
+	return &run, nil
+}
+
+// ListBacktestRuns 查询回测记录（按创建时间倒序，symbol为空表示不过滤）
+func (p *PostgresDB) ListBacktestRuns(ctx context.Context, symbol string, limit int) ([]*BacktestRun, error) {
+	query := "SELECT " + backtestRunColumns + " FROM backtest_runs"
+	args := []interface{}{}
+	argIndex := 1
+
+	if symbol != "" {
+		query += fmt.Sprintf(" WHERE symbol = $%d", argIndex)
+		args = append(args, symbol)
+		argIndex++
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, limit)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backtest runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*BacktestRun
+	for rows.Next() {
+		run, err := scanBacktestRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan backtest run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// GetBacktestRun 获取指定回测记录
+func (p *PostgresDB) GetBacktestRun(ctx context.Context, id string) (*BacktestRun, error) {
+	row := p.db.QueryRowContext(ctx,
+		"SELECT "+backtestRunColumns+" FROM backtest_runs WHERE id = $1", id)
+
+	run, err := scanBacktestRun(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("backtest run %s %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backtest run: %w", err)
+	}
+
+	return run, nil
 }
 
 // SaveTrades 批量保存交易记录
@@ -386,6 +692,14 @@ func (p *PostgresDB) SaveTrades(ctx context.Context, trades []*TradeRecord) erro
 		return nil
 	}
 
+	trades = append([]*TradeRecord(nil), trades...)
+	return p.bufferedWrite(ctx, "trades", func(ctx context.Context) error {
+		return p.saveTrades(ctx, trades)
+	})
+}
+
+// saveTrades 在一个事务中写入交易记录
+func (p *PostgresDB) saveTrades(ctx context.Context, trades []*TradeRecord) error {
 	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -416,6 +730,45 @@ func (p *PostgresDB) SaveTrades(ctx context.Context, trades []*TradeRecord) erro
 	return tx.Commit()
 }
 
+// GetTrades 获取指定回测的交易记录（按时间排序）
+func (p *PostgresDB) GetTrades(ctx context.Context, backtestRunID string) ([]*TradeRecord, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, backtest_run_id, symbol, side, quantity, price,
+		       commission, pnl, reason, timestamp, kline_open_time, created_at
+		FROM trades
+		WHERE backtest_run_id = $1
+		ORDER BY timestamp ASC, id ASC
+	`, backtestRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []*TradeRecord
+	for rows.Next() {
+		var trade TradeRecord
+		var commission, pnl decimal.NullDecimal
+		var reason sql.NullString
+		var klineOpenTime sql.NullInt64
+
+		err := rows.Scan(
+			&trade.ID, &trade.BacktestRunID, &trade.Symbol, &trade.Side, &trade.Quantity, &trade.Price,
+			&commission, &pnl, &reason, &trade.Timestamp, &klineOpenTime, &trade.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
+		}
+
+		trade.Commission = commission.Decimal
+		trade.PnL = pnl.Decimal
+		trade.Reason = reason.String
+		trade.KlineOpenTime = klineOpenTime.Int64
+		trades = append(trades, &trade)
+	}
+
+	return trades, rows.Err()
+}
+
 // UpdateSyncStatus 更新同步状态
 func (p *PostgresDB) UpdateSyncStatus(ctx context.Context, symbol, timeframe string, lastOpenTime int64, totalRecords int, status, errorMsg string) error {
 	query := `
@@ -461,6 +814,296 @@ func (p *PostgresDB) GetSyncStatus(ctx context.Context, symbol, timeframe string
 	return &status, nil
 }
 
+// SavePaperSession 保存模拟盘会话状态（JSON），已存在时覆盖
+func (p *PostgresDB) SavePaperSession(ctx context.Context, sessionID, symbol string, state []byte) error {
+	query := `
+		INSERT INTO paper_sessions (session_id, symbol, state)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (session_id)
+		DO UPDATE SET
+			symbol = $2,
+			state = $3,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := p.db.ExecContext(ctx, query, sessionID, symbol, state); err != nil {
+		return fmt.Errorf("failed to save paper session: %w", err)
+	}
+	return nil
+}
+
+// GetPaperSession 获取模拟盘会话状态（JSON），不存在时返回 nil
+func (p *PostgresDB) GetPaperSession(ctx context.Context, sessionID string) ([]byte, error) {
+	var state []byte
+	err := p.db.QueryRowContext(ctx,
+		"SELECT state FROM paper_sessions WHERE session_id = $1",
+		sessionID,
+	).Scan(&state)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper session: %w", err)
+	}
+
+	return state, nil
+}
+
+// SaveStrategyState 保存策略内部状态（JSON），已存在时覆盖
+func (p *PostgresDB) SaveStrategyState(ctx context.Context, key, strategyName string, state []byte) error {
+	query := `
+		INSERT INTO strategy_states (state_key, strategy, state)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (state_key)
+		DO UPDATE SET
+			strategy = $2,
+			state = $3,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := p.db.ExecContext(ctx, query, key, strategyName, state); err != nil {
+		return fmt.Errorf("failed to save strategy state: %w", err)
+	}
+	return nil
+}
+
+// GetStrategyState 获取策略内部状态（JSON），不存在时返回 nil
+func (p *PostgresDB) GetStrategyState(ctx context.Context, key string) ([]byte, error) {
+	var state []byte
+	err := p.db.QueryRowContext(ctx,
+		"SELECT state FROM strategy_states WHERE state_key = $1",
+		key,
+	).Scan(&state)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy state: %w", err)
+	}
+
+	return state, nil
+}
+
+// SaveEquitySnapshot 保存一条实盘/Dry Run 账户快照
+func (p *PostgresDB) SaveEquitySnapshot(ctx context.Context, record *EquitySnapshotRecord) error {
+	query := `
+		INSERT INTO equity_snapshots (session_id, symbol, mode, snapshot_time, price, cash, position, equity,
+			cost_basis, realized_pnl, unrealized_pnl, trace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
+	`
+
+	snapshot := *record
+	return p.bufferedWrite(ctx, "equity snapshot "+record.SessionID, func(ctx context.Context) error {
+		_, err := p.db.ExecContext(ctx, query,
+			snapshot.SessionID, snapshot.Symbol, snapshot.Mode, snapshot.Time.UTC(), snapshot.Price, snapshot.Cash, snapshot.Position,
+			snapshot.Equity, snapshot.CostBasis, snapshot.RealizedPnL, snapshot.UnrealizedPnL, snapshot.TraceID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save equity snapshot: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetEquitySnapshots 获取会话在 [start, end) 内的账户快照（按时间排序），零值时间表示不限制
+func (p *PostgresDB) GetEquitySnapshots(ctx context.Context, sessionID string, start, end time.Time) ([]*EquitySnapshotRecord, error) {
+	query := `
+		SELECT id, session_id, symbol, mode, snapshot_time, price, cash, position, equity,
+			cost_basis, realized_pnl, unrealized_pnl, COALESCE(trace_id, ''), created_at
+		FROM equity_snapshots
+		WHERE session_id = $1
+			AND ($2::timestamp IS NULL OR snapshot_time >= $2)
+			AND ($3::timestamp IS NULL OR snapshot_time < $3)
+		ORDER BY snapshot_time
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, sessionID, nullableTime(start), nullableTime(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*EquitySnapshotRecord
+	for rows.Next() {
+		record, err := scanEquitySnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate equity snapshots: %w", err)
+	}
+	return records, nil
+}
+
+// GetLatestEquitySnapshot 获取会话最近一条账户快照，不存在时返回 nil
+func (p *PostgresDB) GetLatestEquitySnapshot(ctx context.Context, sessionID string) (*EquitySnapshotRecord, error) {
+	row := p.db.QueryRowContext(ctx, `
+		SELECT id, session_id, symbol, mode, snapshot_time, price, cash, position, equity,
+			cost_basis, realized_pnl, unrealized_pnl, COALESCE(trace_id, ''), created_at
+		FROM equity_snapshots
+		WHERE session_id = $1
+		ORDER BY snapshot_time DESC
+		LIMIT 1
+	`, sessionID)
+
+	record, err := scanEquitySnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return record, err
+}
+
+// SaveBookTicker 保存一条盘口买一卖一记录
+func (p *PostgresDB) SaveBookTicker(ctx context.Context, record *BookTickerRecord) error {
+	ticker := *record
+	return p.bufferedWrite(ctx, "book ticker "+record.Symbol, func(ctx context.Context) error {
+		_, err := p.db.ExecContext(ctx, `
+			INSERT INTO book_tickers (symbol, captured_at, bid_price, bid_qty, ask_price, ask_qty)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, ticker.Symbol, ticker.Time.UTC(), ticker.BidPrice, ticker.BidQuantity, ticker.AskPrice, ticker.AskQuantity)
+		if err != nil {
+			return fmt.Errorf("failed to save book ticker: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetSpreadHourStats 按 UTC 小时汇总交易对在 [start, end) 内记录的盘口价差，零值时间表示不限制
+func (p *PostgresDB) GetSpreadHourStats(ctx context.Context, symbol string, start, end time.Time) ([]*SpreadHourStat, error) {
+	query := `
+		SELECT EXTRACT(HOUR FROM captured_at)::int AS hour, COUNT(*),
+			AVG((ask_price - bid_price) / ((ask_price + bid_price) / 2))::float8
+		FROM book_tickers
+		WHERE symbol = $1 AND bid_price > 0 AND ask_price >= bid_price
+			AND ($2::timestamp IS NULL OR captured_at >= $2)
+			AND ($3::timestamp IS NULL OR captured_at < $3)
+		GROUP BY hour
+		ORDER BY hour
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, symbol, nullableTime(start), nullableTime(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spread stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*SpreadHourStat
+	for rows.Next() {
+		stat := &SpreadHourStat{}
+		if err := rows.Scan(&stat.Hour, &stat.Samples, &stat.MeanSpread); err != nil {
+			return nil, fmt.Errorf("failed to scan spread stats: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate spread stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ListEquitySessions 列出有账户快照的会话（按最近快照时间倒序）
+func (p *PostgresDB) ListEquitySessions(ctx context.Context) ([]*EquitySessionSummary, error) {
+	query := `
+		SELECT s.session_id, s.symbol, s.mode, s.first_time, s.last_time, s.snapshots, e.equity
+		FROM (
+			SELECT session_id, MAX(symbol) AS symbol, MAX(mode) AS mode,
+				MIN(snapshot_time) AS first_time, MAX(snapshot_time) AS last_time, COUNT(*) AS snapshots
+			FROM equity_snapshots
+			GROUP BY session_id
+		) s
+		JOIN equity_snapshots e ON e.session_id = s.session_id AND e.snapshot_time = s.last_time
+		ORDER BY s.last_time DESC
+	`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query equity sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*EquitySessionSummary
+	for rows.Next() {
+		var session EquitySessionSummary
+		if err := rows.Scan(&session.SessionID, &session.Symbol, &session.Mode, &session.FirstTime, &session.LastTime,
+			&session.Snapshots, &session.Equity); err != nil {
+			return nil, fmt.Errorf("failed to scan equity session: %w", err)
+		}
+		session.FirstTime = toUTC(session.FirstTime)
+		session.LastTime = toUTC(session.LastTime)
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate equity sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// scanEquitySnapshot 读取一条账户快照
+func scanEquitySnapshot(row rowScanner) (*EquitySnapshotRecord, error) {
+	var record EquitySnapshotRecord
+	err := row.Scan(&record.ID, &record.SessionID, &record.Symbol, &record.Mode, &record.Time, &record.Price,
+		&record.Cash, &record.Position, &record.Equity, &record.CostBasis, &record.RealizedPnL, &record.UnrealizedPnL,
+		&record.TraceID, &record.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan equity snapshot: %w", err)
+	}
+	// TIMESTAMP 列不带时区，按UTC解释
+	record.Time = toUTC(record.Time)
+	return &record, nil
+}
+
+// nullableTime 零值时间转为 NULL
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// GetTradingCalendar 获取交易对的禁止交易时段（包含对所有交易对生效的 '*' 记录），按开始时间排序
+func (p *PostgresDB) GetTradingCalendar(ctx context.Context, symbol string) ([]*TradingCalendarRecord, error) {
+	query := `
+		SELECT id, symbol, start_time, end_time, COALESCE(reason, ''), created_at
+		FROM trading_calendars
+		WHERE symbol = $1 OR symbol = '*'
+		ORDER BY start_time
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trading calendar: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*TradingCalendarRecord
+	for rows.Next() {
+		var record TradingCalendarRecord
+		if err := rows.Scan(&record.ID, &record.Symbol, &record.StartTime, &record.EndTime, &record.Reason, &record.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trading calendar: %w", err)
+		}
+		// TIMESTAMP 列不带时区，按UTC解释
+		record.StartTime = toUTC(record.StartTime)
+		record.EndTime = toUTC(record.EndTime)
+		records = append(records, &record)
+	}
+
+	return records, rows.Err()
+}
+
+// toUTC 将不带时区的时间按UTC解释
+func toUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
 // SymbolInfo 交易对信息结构体
 type SymbolInfo struct {
 	ID          int             `json:"id"`
@@ -483,8 +1126,9 @@ type SymbolInfo struct {
 func (db *PostgresDB) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
 	query := `
 		SELECT id, symbol, base_asset, quote_asset, status, 
-		       min_qty, max_qty, step_size, min_price, max_price, 
-		       tick_size, min_notional, created_at, updated_at
+		       COALESCE(min_qty, 0), COALESCE(max_qty, 0), COALESCE(step_size, 0),
+		       COALESCE(min_price, 0), COALESCE(max_price, 0), COALESCE(tick_size, 0),
+		       COALESCE(min_notional, 0), created_at, updated_at
 		FROM symbols 
 		WHERE symbol = $1 AND status = 'TRADING'
 	`
@@ -507,6 +1151,68 @@ func (db *PostgresDB) GetSymbolInfo(symbol string) (*SymbolInfo, error) {
 	return &info, nil
 }
 
+// upsertSymbolQuery 写入交易对信息，已存在时覆盖
+const upsertSymbolQuery = `
+	INSERT INTO symbols (symbol, base_asset, quote_asset, status,
+	                     min_qty, max_qty, step_size, min_price, max_price, tick_size, min_notional)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (symbol)
+	DO UPDATE SET
+		base_asset = EXCLUDED.base_asset,
+		quote_asset = EXCLUDED.quote_asset,
+		status = EXCLUDED.status,
+		min_qty = EXCLUDED.min_qty,
+		max_qty = EXCLUDED.max_qty,
+		step_size = EXCLUDED.step_size,
+		min_price = EXCLUDED.min_price,
+		max_price = EXCLUDED.max_price,
+		tick_size = EXCLUDED.tick_size,
+		min_notional = EXCLUDED.min_notional,
+		updated_at = CURRENT_TIMESTAMP
+`
+
+// symbolArgs 交易对信息的写入参数
+func symbolArgs(info *SymbolInfo) []interface{} {
+	return []interface{}{
+		info.Symbol, info.BaseAsset, info.QuoteAsset, info.Status,
+		info.MinQty, info.MaxQty, info.StepSize, info.MinPrice, info.MaxPrice, info.TickSize, info.MinNotional,
+	}
+}
+
+// SaveSymbolInfo 保存交易对下单规则（来自交易所 exchangeInfo），已存在时覆盖
+func (db *PostgresDB) SaveSymbolInfo(info *SymbolInfo) error {
+	if _, err := db.db.Exec(upsertSymbolQuery, symbolArgs(info)...); err != nil {
+		return fmt.Errorf("failed to save symbol info: %w", err)
+	}
+	return nil
+}
+
+// SaveSymbolInfos 在一个事务中批量保存交易对信息，已存在时覆盖
+func (db *PostgresDB) SaveSymbolInfos(ctx context.Context, infos []*SymbolInfo) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, upsertSymbolQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, info := range infos {
+		if _, err := stmt.ExecContext(ctx, symbolArgs(info)...); err != nil {
+			return fmt.Errorf("failed to save symbol %s: %w", info.Symbol, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // IsSymbolSupported 检查交易对是否支持
 func (db *PostgresDB) IsSymbolSupported(symbol string) (bool, error) {
 	query := `SELECT COUNT(*) FROM symbols WHERE symbol = $1 AND status = 'TRADING'`
@@ -545,3 +1251,168 @@ func (db *PostgresDB) GetSupportedSymbols() ([]string, error) {
 
 	return symbols, nil
 }
+
+// SaveFundingRates 批量保存永续合约资金费率，已存在的结算时间覆盖费率
+func (p *PostgresDB) SaveFundingRates(ctx context.Context, symbol string, rates []*cex.FundingRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO funding_rates (symbol, funding_time, funding_rate)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (symbol, funding_time) DO UPDATE SET funding_rate = EXCLUDED.funding_rate
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, rate := range rates {
+		if _, err := stmt.ExecContext(ctx, symbol, rate.Time.UTC(), rate.Rate); err != nil {
+			return fmt.Errorf("failed to save funding rate: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLatestFundingTime 获取最新资金费率的结算时间，无数据时返回零值
+func (p *PostgresDB) GetLatestFundingTime(ctx context.Context, symbol string) (time.Time, error) {
+	var latest sql.NullTime
+	err := p.db.QueryRowContext(ctx, "SELECT MAX(funding_time) FROM funding_rates WHERE symbol = $1", symbol).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest funding time: %w", err)
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+	return toUTC(latest.Time), nil
+}
+
+// GetFundingRates 获取 [start, end] 内的资金费率（按结算时间升序），零值时间表示不限制
+func (p *PostgresDB) GetFundingRates(ctx context.Context, symbol string, start, end time.Time) ([]*cex.FundingRate, error) {
+	query := `
+		SELECT funding_time, funding_rate
+		FROM funding_rates
+		WHERE symbol = $1
+			AND ($2::timestamp IS NULL OR funding_time >= $2)
+			AND ($3::timestamp IS NULL OR funding_time <= $3)
+		ORDER BY funding_time
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, symbol, nullableTime(start), nullableTime(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []*cex.FundingRate
+	for rows.Next() {
+		var rate cex.FundingRate
+		if err := rows.Scan(&rate.Time, &rate.Rate); err != nil {
+			return nil, fmt.Errorf("failed to scan funding rate: %w", err)
+		}
+		// TIMESTAMP 列不带时区，按UTC解释
+		rate.Time = toUTC(rate.Time)
+		rates = append(rates, &rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate funding rates: %w", err)
+	}
+	return rates, nil
+}
+
+// SaveOpenInterest 批量保存永续合约持仓量，已存在的统计时间覆盖数值
+func (p *PostgresDB) SaveOpenInterest(ctx context.Context, symbol, period string, history []*cex.OpenInterest) error {
+	if len(history) == 0 {
+		return nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO open_interest (symbol, period, snapshot_time, open_interest, open_interest_value)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (symbol, period, snapshot_time) DO UPDATE SET
+			open_interest = EXCLUDED.open_interest,
+			open_interest_value = EXCLUDED.open_interest_value
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, oi := range history {
+		if _, err := stmt.ExecContext(ctx, symbol, period, oi.Time.UTC(), oi.OpenInterest, oi.Value); err != nil {
+			return fmt.Errorf("failed to save open interest: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLatestOpenInterestTime 获取最新持仓量的统计时间，无数据时返回零值
+func (p *PostgresDB) GetLatestOpenInterestTime(ctx context.Context, symbol, period string) (time.Time, error) {
+	var latest sql.NullTime
+	err := p.db.QueryRowContext(ctx,
+		"SELECT MAX(snapshot_time) FROM open_interest WHERE symbol = $1 AND period = $2",
+		symbol, period,
+	).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest open interest time: %w", err)
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+	return toUTC(latest.Time), nil
+}
+
+// GetOpenInterest 获取 [start, end] 内按 period 统计的持仓量（按时间升序），零值时间表示不限制
+func (p *PostgresDB) GetOpenInterest(ctx context.Context, symbol, period string, start, end time.Time) ([]*cex.OpenInterest, error) {
+	query := `
+		SELECT snapshot_time, open_interest, open_interest_value
+		FROM open_interest
+		WHERE symbol = $1 AND period = $2
+			AND ($3::timestamp IS NULL OR snapshot_time >= $3)
+			AND ($4::timestamp IS NULL OR snapshot_time <= $4)
+		ORDER BY snapshot_time
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, symbol, period, nullableTime(start), nullableTime(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open interest: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*cex.OpenInterest
+	for rows.Next() {
+		var oi cex.OpenInterest
+		if err := rows.Scan(&oi.Time, &oi.OpenInterest, &oi.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan open interest: %w", err)
+		}
+		// TIMESTAMP 列不带时区，按UTC解释
+		oi.Time = toUTC(oi.Time)
+		history = append(history, &oi)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate open interest: %w", err)
+	}
+	return history, nil
+}
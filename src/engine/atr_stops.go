@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"tradingbot/src/cex"
+	"tradingbot/src/indicators"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+)
+
+// klineATR 计算最后一根K线的 ATR，K线不足 period+1 根时返回 false
+func klineATR(klines []*cex.KlineData, period int) (decimal.Decimal, bool) {
+	if period <= 0 || len(klines) < period+1 {
+		return decimal.Zero, false
+	}
+
+	highs := make([]decimal.Decimal, len(klines))
+	lows := make([]decimal.Decimal, len(klines))
+	closes := make([]decimal.Decimal, len(klines))
+	for i, kline := range klines {
+		highs[i], lows[i], closes[i] = kline.High, kline.Low, kline.Close
+	}
+
+	atr, err := indicators.NewATR(period).Calculate(highs, lows, closes)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return atr, true
+}
+
+// atrStopPercents 将 N×ATR 的止盈/止损距离换算为相对开仓价的比例
+// 策略未启用 ATR 止损或K线不足时返回 false，调用方使用固定比例
+func (e *TradingEngine) atrStopPercents(entryPrice decimal.Decimal) (float64, float64, bool) {
+	provider, ok := e.strategy.(strategy.ATRStopProvider)
+	if !ok || !entryPrice.IsPositive() {
+		return 0, 0, false
+	}
+	period, takeProfitMultiple, stopLossMultiple := provider.GetATRStops()
+	if takeProfitMultiple <= 0 || stopLossMultiple <= 0 {
+		return 0, 0, false
+	}
+
+	atr, ok := klineATR(e.lastKlines, period)
+	if !ok || !atr.IsPositive() {
+		return 0, 0, false
+	}
+
+	takeProfit := atr.Mul(decimal.NewFromFloat(takeProfitMultiple)).Div(entryPrice).InexactFloat64()
+	stopLoss := atr.Mul(decimal.NewFromFloat(stopLossMultiple)).Div(entryPrice).InexactFloat64()
+	if stopLoss >= 1 {
+		return 0, 0, false
+	}
+	return takeProfit, stopLoss, true
+}
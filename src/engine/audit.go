@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// SetOrderAuditor 设置订单审计日志，止损单、OCO 的下单和撤单请求及响应都会记录（为空时不记录）
+func (m *LiveOrderManager) SetOrderAuditor(auditor cex.OrderAuditor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auditor = auditor
+}
+
+// placeStopLoss 在交易所挂出止损单并记录审计日志
+func (m *LiveOrderManager) placeStopLoss(ctx context.Context, client cex.StopOrderClient, pair cex.TradingPair, quantity, stopPrice decimal.Decimal) (*cex.OrderResult, error) {
+	request := map[string]interface{}{"quantity": quantity, "stop_price": stopPrice}
+	return cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionStopLoss, pair, request, func() (*cex.OrderResult, error) {
+		return client.PlaceStopLossOrder(ctx, pair, quantity, stopPrice)
+	})
+}
+
+// cancelExchangeOrder 撤销交易所挂单并记录审计日志
+func (m *LiveOrderManager) cancelExchangeOrder(ctx context.Context, client cex.StopOrderClient, pair cex.TradingPair, exchangeID string) error {
+	request := map[string]interface{}{"order_id": exchangeID}
+	return cex.AuditCancel(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionCancel, pair, request, func() error {
+		return client.CancelOrder(ctx, pair, exchangeID)
+	})
+}
+
+// placeOCOSell 在交易所挂出 OCO 卖单并记录审计日志
+func (m *LiveOrderManager) placeOCOSell(ctx context.Context, client cex.OCOOrderClient, pair cex.TradingPair, quantity, takeProfitPrice, stopPrice, stopLimitPrice decimal.Decimal) (*cex.OCOOrderResult, error) {
+	request := map[string]interface{}{
+		"quantity":          quantity,
+		"take_profit_price": takeProfitPrice,
+		"stop_price":        stopPrice,
+		"stop_limit_price":  stopLimitPrice,
+	}
+	return cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionOCOSell, pair, request, func() (*cex.OCOOrderResult, error) {
+		return client.PlaceOCOSellOrder(ctx, pair, quantity, takeProfitPrice, stopPrice, stopLimitPrice)
+	})
+}
+
+// cancelExchangeOCO 撤销交易所 OCO 订单组并记录审计日志
+func (m *LiveOrderManager) cancelExchangeOCO(ctx context.Context, client cex.OCOOrderClient, pair cex.TradingPair, orderListID string) error {
+	request := map[string]interface{}{"order_list_id": orderListID}
+	return cex.AuditCancel(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionCancelOCO, pair, request, func() error {
+		return client.CancelOCOOrder(ctx, pair, orderListID)
+	})
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// NoTradeWindow 一次性禁止交易时间窗口（计划维护、集合竞价等），区间 [Start, End)
+type NoTradeWindow struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason"`
+}
+
+// DailyNoTradeWindow 每日重复的禁止交易时段（UTC，相对零点的偏移），End<Start 表示跨越零点
+type DailyNoTradeWindow struct {
+	Start  time.Duration `json:"start"`
+	End    time.Duration `json:"end"`
+	Reason string        `json:"reason"`
+}
+
+// TradingCalendar 交易对的交易日历，记录无法交易的时间段
+// nil 日历表示任何时间都可交易
+type TradingCalendar struct {
+	windows []NoTradeWindow
+	daily   []DailyNoTradeWindow
+}
+
+// NewTradingCalendar 创建空的交易日历
+func NewTradingCalendar() *TradingCalendar {
+	return &TradingCalendar{}
+}
+
+// AddWindow 添加一次性禁止交易窗口
+func (c *TradingCalendar) AddWindow(window NoTradeWindow) error {
+	if !window.End.After(window.Start) {
+		return fmt.Errorf("invalid no-trade window: end %s must be after start %s",
+			window.End.Format(time.RFC3339), window.Start.Format(time.RFC3339))
+	}
+
+	c.windows = append(c.windows, window)
+	sort.Slice(c.windows, func(i, j int) bool {
+		return c.windows[i].Start.Before(c.windows[j].Start)
+	})
+	return nil
+}
+
+// AddDailyWindow 添加每日重复的禁止交易时段
+func (c *TradingCalendar) AddDailyWindow(window DailyNoTradeWindow) error {
+	if window.Start < 0 || window.Start >= 24*time.Hour || window.End < 0 || window.End > 24*time.Hour {
+		return fmt.Errorf("invalid daily no-trade window: %s-%s", window.Start, window.End)
+	}
+	if window.Start == window.End {
+		return fmt.Errorf("invalid daily no-trade window: start equals end (%s)", window.Start)
+	}
+
+	c.daily = append(c.daily, window)
+	return nil
+}
+
+// IsEmpty 日历中是否没有任何禁止交易窗口
+func (c *TradingCalendar) IsEmpty() bool {
+	return c == nil || (len(c.windows) == 0 && len(c.daily) == 0)
+}
+
+// WindowCount 禁止交易窗口数量（一次性 + 每日）
+func (c *TradingCalendar) WindowCount() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.windows) + len(c.daily)
+}
+
+// IsTradingAllowed 判断某一时刻是否可以交易，不可交易时返回原因
+func (c *TradingCalendar) IsTradingAllowed(t time.Time) (bool, string) {
+	blocked, reason := c.IsBlockedDuring(t, t)
+	return !blocked, reason
+}
+
+// IsBlockedDuring 判断时间段 [start, end] 是否与任一禁止交易窗口重叠
+// 回测中一根K线内无法确定成交时刻，只要K线区间触及禁止窗口就视为不可成交
+func (c *TradingCalendar) IsBlockedDuring(start, end time.Time) (bool, string) {
+	if c.IsEmpty() {
+		return false, ""
+	}
+
+	for _, w := range c.windows {
+		if w.Start.After(end) {
+			break // 已按开始时间排序
+		}
+		if start.Before(w.End) {
+			return true, w.Reason
+		}
+	}
+
+	for _, w := range c.daily {
+		if dailyWindowOverlaps(w, start.UTC(), end.UTC()) {
+			return true, w.Reason
+		}
+	}
+
+	return false, ""
+}
+
+// dailyWindowOverlaps 判断每日时段是否与 [start, end] 重叠
+func dailyWindowOverlaps(w DailyNoTradeWindow, start, end time.Time) bool {
+	// 从前一天开始逐日展开，覆盖跨零点的时段
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for !day.After(end) {
+		windowStart := day.Add(w.Start)
+		windowEnd := day.Add(w.End)
+		if w.End < w.Start {
+			windowEnd = windowEnd.Add(24 * time.Hour)
+		}
+
+		if !windowStart.After(end) && start.Before(windowEnd) {
+			return true
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return false
+}
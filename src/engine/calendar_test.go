@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingCalendar_NilAllowsTrading(t *testing.T) {
+	var calendar *TradingCalendar
+
+	allowed, reason := calendar.IsTradingAllowed(time.Now())
+	assert.True(t, allowed)
+	assert.Empty(t, reason)
+	assert.True(t, calendar.IsEmpty())
+}
+
+func TestTradingCalendar_OneOffWindow(t *testing.T) {
+	calendar := NewTradingCalendar()
+	start := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	require.NoError(t, calendar.AddWindow(NoTradeWindow{Start: start, End: start.Add(2 * time.Hour), Reason: "maintenance"}))
+
+	allowed, reason := calendar.IsTradingAllowed(start.Add(30 * time.Minute))
+	assert.False(t, allowed)
+	assert.Equal(t, "maintenance", reason)
+
+	allowed, _ = calendar.IsTradingAllowed(start.Add(-time.Minute))
+	assert.True(t, allowed)
+
+	// 窗口为左闭右开区间
+	allowed, _ = calendar.IsTradingAllowed(start.Add(2 * time.Hour))
+	assert.True(t, allowed)
+
+	// K线区间部分重叠即视为暂停
+	blocked, _ := calendar.IsBlockedDuring(start.Add(-4*time.Hour), start)
+	assert.True(t, blocked)
+	blocked, _ = calendar.IsBlockedDuring(start.Add(-4*time.Hour), start.Add(-time.Millisecond))
+	assert.False(t, blocked)
+
+	assert.Error(t, calendar.AddWindow(NoTradeWindow{Start: start, End: start}))
+}
+
+func TestTradingCalendar_DailyWindow(t *testing.T) {
+	calendar := NewTradingCalendar()
+	require.NoError(t, calendar.AddDailyWindow(DailyNoTradeWindow{Start: 23*time.Hour + 50*time.Minute, End: 10 * time.Minute, Reason: "daily settlement"}))
+
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	allowed, reason := calendar.IsTradingAllowed(day.Add(5 * time.Minute))
+	assert.False(t, allowed)
+	assert.Equal(t, "daily settlement", reason)
+
+	allowed, _ = calendar.IsTradingAllowed(day.Add(23*time.Hour + 55*time.Minute))
+	assert.False(t, allowed)
+
+	allowed, _ = calendar.IsTradingAllowed(day.Add(12 * time.Hour))
+	assert.True(t, allowed)
+
+	// 4h K线 [04:00, 08:00) 不受影响，[20:00, 24:00) 受影响
+	blocked, _ := calendar.IsBlockedDuring(day.Add(4*time.Hour), day.Add(8*time.Hour-time.Millisecond))
+	assert.False(t, blocked)
+	blocked, _ = calendar.IsBlockedDuring(day.Add(20*time.Hour), day.Add(24*time.Hour-time.Millisecond))
+	assert.True(t, blocked)
+
+	assert.Error(t, calendar.AddDailyWindow(DailyNoTradeWindow{Start: 25 * time.Hour, End: time.Hour}))
+	assert.Equal(t, 1, calendar.WindowCount())
+}
+
+func TestBacktestOrderManager_CheckAndExecuteOrders_TradingPaused(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(100000), decimal.Zero)
+	manager := NewBacktestOrderManager(mockExecutor)
+
+	openTime := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	calendar := NewTradingCalendar()
+	require.NoError(t, calendar.AddWindow(NoTradeWindow{Start: openTime.Add(time.Hour), End: openTime.Add(5 * time.Hour), Reason: "maintenance"}))
+	manager.SetTradingCalendar(calendar)
+
+	ctx := context.Background()
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_1", decimal.NewFromInt(50000))))
+
+	// 维护期间价格触及挂单也不成交
+	kline := CreateTestKlineWithPrices(openTime, decimal.NewFromInt(50500), decimal.NewFromInt(51000), decimal.NewFromInt(49000), decimal.NewFromInt(50200))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, 0, mockExecutor.buyCallCount)
+	assert.Equal(t, 1, manager.GetOrderCount())
+
+	// 恢复交易后正常成交
+	kline = CreateTestKlineWithPrices(openTime.Add(8*time.Hour), decimal.NewFromInt(50500), decimal.NewFromInt(51000), decimal.NewFromInt(49000), decimal.NewFromInt(50200))
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestTradingEngine_Run_SkipsSignalsWhenTradingPaused(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := CreateTestKlines(3, startTime, 4*time.Hour)
+
+	mockStrategy := &mockTradingStrategy{} // 第一根K线产生买入信号
+	mockOrderManager := &mockTradingOrderManager{}
+	engine := createTestTradingEngineWithMocks(
+		mockStrategy,
+		newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero),
+		&mockTradingDataFeed{klines: klines},
+		mockOrderManager,
+	)
+
+	// 全程暂停交易
+	calendar := NewTradingCalendar()
+	require.NoError(t, calendar.AddWindow(NoTradeWindow{Start: startTime, End: startTime.Add(24 * time.Hour), Reason: "delisting auction"}))
+	engine.SetTradingCalendar(calendar)
+
+	require.NoError(t, engine.Run(context.Background()))
+	assert.Equal(t, 3, mockStrategy.onDataCalls)
+	assert.Equal(t, 0, mockOrderManager.placeCallCount)
+}
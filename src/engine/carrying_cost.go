@@ -0,0 +1,24 @@
+package engine
+
+import (
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// CarryingCostAccruer 可按K线计提资金成本的执行器（回测执行器配置了资金成本模型时生效）
+type CarryingCostAccruer interface {
+	AccrueCarryingCost(price decimal.Decimal, period time.Duration) decimal.Decimal
+}
+
+var _ CarryingCostAccruer = (*executor.TradingExecutor)(nil)
+
+// accrueCarryingCost 按收盘价计提这根K线期间的资金成本（闲置现金收益、持仓成本）
+func (e *TradingEngine) accrueCarryingCost(kline *cex.KlineData) {
+	if accruer, ok := e.executor.(CarryingCostAccruer); ok {
+		accruer.AccrueCarryingCost(kline.Close, e.getTimeframeInterval())
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingEngine_AccruesCarryingCostPerBar(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var klines []*cex.KlineData
+	for i := 0; i < 3; i++ {
+		price := decimal.NewFromInt(5000)
+		klines = append(klines, CreateTestKlineWithPrices(startTime.Add(time.Duration(i)*4*time.Hour), price, price, price, price))
+	}
+
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	tradingExecutor := executor.NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	tradingExecutor.RecordFill(context.Background(), &executor.OrderResult{Side: executor.OrderSideBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(5000)})
+	// 每根4小时K线：持仓成本 5000 × 21.9% × 4/8760 = 0.5，现金收益 5000 × 8.76% × 4/8760 = 0.2
+	tradingExecutor.SetCarryingCost(executor.CarryingCostModel{CashRate: 0.0876, PositionRate: 0.219})
+
+	engine := createTestTradingEngineWithMocks(&mockTradingStrategy{shouldError: true}, tradingExecutor,
+		&mockTradingDataFeed{klines: klines}, &mockTradingOrderManager{})
+	require.NoError(t, engine.Run(context.Background()))
+
+	assert.InDelta(t, 0.9, tradingExecutor.CarryingCost().InexactFloat64(), 1e-3)
+
+	// 资金曲线包含计提后的现金
+	curve := engine.GetEquityCurve()
+	require.Len(t, curve, 3)
+	assert.InDelta(t, 4999.7, curve[0].Cash.InexactFloat64(), 1e-3)
+	assert.InDelta(t, 4999.1, curve[2].Cash.InexactFloat64(), 1e-3)
+}
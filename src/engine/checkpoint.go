@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// checkpointVersion 断点文件格式版本，格式不兼容时递增
+const checkpointVersion = 1
+
+// BacktestCheckpoint 回测断点：最后处理的K线，以及引擎、执行器、挂单、风控和策略的状态
+type BacktestCheckpoint struct {
+	Version    int       `json:"version"`
+	RunKey     string    `json:"run_key"` // 回测参数（交易对、周期、策略参数、时间范围、初始资金、配置）的摘要，不同时不恢复
+	Strategy   string    `json:"strategy"`
+	KlineTime  time.Time `json:"kline_time"` // 最后处理的K线开盘时间，恢复后从下一根K线继续
+	KlineCount int       `json:"kline_count"`
+	SavedAt    time.Time `json:"saved_at"`
+
+	StrategyState    json.RawMessage          `json:"strategy_state,omitempty"`
+	Executor         executor.ExecutorState   `json:"executor"`
+	PendingOrders    []CheckpointOrder        `json:"pending_orders"`
+	ProtectedEntries map[string]*PendingOrder `json:"protected_entries,omitempty"`
+	LastSignalOrder  map[string]time.Time     `json:"last_signal_order,omitempty"`
+	EquityCurve      []EquityPoint            `json:"equity_curve"`
+	Drawdown         DrawdownState            `json:"drawdown"`
+	Risk             *RiskState               `json:"risk,omitempty"`
+}
+
+// CheckpointOrder 断点中的挂单（含分批成交的累计结果）
+type CheckpointOrder struct {
+	PendingOrder
+	Filled *executor.OrderResult `json:"filled,omitempty"`
+}
+
+// CheckpointExecutor 可导出和恢复账户状态的执行器（回测的 executor.TradingExecutor）
+type CheckpointExecutor interface {
+	ExportState() executor.ExecutorState
+	RestoreState(state executor.ExecutorState)
+}
+
+// DrawdownState 回撤跟踪器的状态
+type DrawdownState struct {
+	InitialValue       decimal.Decimal `json:"initial_value"`
+	LastValue          decimal.Decimal `json:"last_value"`
+	PeakValue          decimal.Decimal `json:"peak_value"`
+	MaxDrawdown        decimal.Decimal `json:"max_drawdown"`
+	MaxDrawdownPercent decimal.Decimal `json:"max_drawdown_percent"`
+	Started            bool            `json:"started"`
+	PeakTime           time.Time       `json:"peak_time"`
+	MaxPeakTime        time.Time       `json:"max_peak_time"`
+	TroughTime         time.Time       `json:"trough_time"`
+	RecoveryTime       time.Time       `json:"recovery_time"`
+	LastTime           time.Time       `json:"last_time"`
+	LongestUnderwater  time.Duration   `json:"longest_underwater"`
+	Underwater         bool            `json:"underwater"`
+	MaxDrawdownOpen    bool            `json:"max_drawdown_open"`
+}
+
+// state 导出回撤跟踪器的状态
+func (t *DrawdownTracker) state() DrawdownState {
+	return DrawdownState{
+		InitialValue: t.initialValue, LastValue: t.lastValue, PeakValue: t.peakValue,
+		MaxDrawdown: t.maxDrawdown, MaxDrawdownPercent: t.maxDrawdownPercent, Started: t.started,
+		PeakTime: t.peakTime, MaxPeakTime: t.maxPeakTime, TroughTime: t.troughTime, RecoveryTime: t.recoveryTime,
+		LastTime: t.lastTime, LongestUnderwater: t.longestUnderwater, Underwater: t.underwater, MaxDrawdownOpen: t.maxDrawdownOpen,
+	}
+}
+
+// newDrawdownTrackerFromState 按导出的状态创建回撤跟踪器
+func newDrawdownTrackerFromState(s DrawdownState) *DrawdownTracker {
+	return &DrawdownTracker{
+		initialValue: s.InitialValue, lastValue: s.LastValue, peakValue: s.PeakValue,
+		maxDrawdown: s.MaxDrawdown, maxDrawdownPercent: s.MaxDrawdownPercent, started: s.Started,
+		peakTime: s.PeakTime, maxPeakTime: s.MaxPeakTime, troughTime: s.TroughTime, recoveryTime: s.RecoveryTime,
+		lastTime: s.LastTime, longestUnderwater: s.LongestUnderwater, underwater: s.Underwater, maxDrawdownOpen: s.MaxDrawdownOpen,
+	}
+}
+
+// RiskState 风控管理器的状态（风控限制按当前配置，不保存）
+type RiskState struct {
+	Position          decimal.Decimal `json:"position"`
+	CostBasis         decimal.Decimal `json:"cost_basis"`
+	Day               time.Time       `json:"day"`
+	DayStartEquity    decimal.Decimal `json:"day_start_equity"`
+	DailyRealizedPnL  decimal.Decimal `json:"daily_realized_pnl"`
+	ConsecutiveLosses int             `json:"consecutive_losses"`
+	DailyPaused       bool            `json:"daily_paused"`
+	PeakEquity        decimal.Decimal `json:"peak_equity"`
+	DrawdownBreached  bool            `json:"drawdown_breached"`
+	ManualPaused      bool            `json:"manual_paused"`
+	Cash              decimal.Decimal `json:"cash"`
+	Held              decimal.Decimal `json:"held"`
+	Price             decimal.Decimal `json:"price"`
+	Halted            bool            `json:"halted"`
+	HaltReason        string          `json:"halt_reason,omitempty"`
+}
+
+// state 导出风控状态
+func (m *RiskManager) state() *RiskState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &RiskState{
+		Position: m.position, CostBasis: m.costBasis, Day: m.day, DayStartEquity: m.dayStartEquity,
+		DailyRealizedPnL: m.dailyRealizedPnL, ConsecutiveLosses: m.consecutiveLosses, DailyPaused: m.dailyPaused,
+		PeakEquity: m.peakEquity, DrawdownBreached: m.drawdownBreached, ManualPaused: m.manualPaused,
+		Cash: m.cash, Held: m.held, Price: m.price, Halted: m.halted, HaltReason: m.haltReason,
+	}
+}
+
+// restore 恢复导出的风控状态
+func (m *RiskManager) restore(s *RiskState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.position, m.costBasis, m.day, m.dayStartEquity = s.Position, s.CostBasis, s.Day, s.DayStartEquity
+	m.dailyRealizedPnL, m.consecutiveLosses, m.dailyPaused = s.DailyRealizedPnL, s.ConsecutiveLosses, s.DailyPaused
+	m.peakEquity, m.drawdownBreached, m.manualPaused = s.PeakEquity, s.DrawdownBreached, s.ManualPaused
+	m.cash, m.held, m.price, m.halted, m.haltReason = s.Cash, s.Held, s.Price, s.Halted, s.HaltReason
+}
+
+// LoadBacktestCheckpoint 读取断点文件，文件不存在时返回 nil
+func LoadBacktestCheckpoint(path string) (*BacktestCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+	var checkpoint BacktestCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Version != checkpointVersion {
+		return nil, fmt.Errorf("checkpoint %s has version %d, expected %d", path, checkpoint.Version, checkpointVersion)
+	}
+	return &checkpoint, nil
+}
+
+// SetCheckpoint 回测每处理 everyBars 根K线把状态写入 path（先写临时文件再改名，中断时不会留下不完整的断点），
+// runKey 标识回测参数，恢复时必须一致
+func (e *TradingEngine) SetCheckpoint(path string, everyBars int, runKey string) error {
+	if everyBars <= 0 {
+		return fmt.Errorf("checkpoint interval must be positive, got %d", everyBars)
+	}
+	if _, ok := e.executor.(CheckpointExecutor); !ok {
+		return fmt.Errorf("executor %s does not support checkpoints", e.executor.GetName())
+	}
+	if _, ok := e.orderManager.(*BacktestOrderManager); !ok {
+		return errors.New("checkpoints require the backtest order manager")
+	}
+	e.checkpointPath = path
+	e.checkpointEvery = everyBars
+	e.checkpointKey = runKey
+	return nil
+}
+
+// ResumeFrom 从断点恢复回测：Run 开始时恢复状态，跳过断点及之前的K线（仍保留供统计）
+func (e *TradingEngine) ResumeFrom(checkpoint *BacktestCheckpoint) error {
+	if checkpoint.RunKey != e.checkpointKey {
+		return errors.New("checkpoint belongs to a backtest with different parameters, data range or config")
+	}
+	if checkpoint.Strategy != e.strategy.GetName() {
+		return fmt.Errorf("checkpoint belongs to strategy %s, current strategy is %s", checkpoint.Strategy, e.strategy.GetName())
+	}
+	e.resume = checkpoint
+	return nil
+}
+
+// restoreCheckpoint 在 Run 开始时恢复断点状态（设置了 ResumeFrom 时），返回恢复的断点
+func (e *TradingEngine) restoreCheckpoint(ctx context.Context) (*BacktestCheckpoint, error) {
+	checkpoint := e.resume
+	if checkpoint == nil {
+		return nil, nil
+	}
+	_, logger := log.WithCtx(ctx)
+
+	if err := e.strategy.Load(checkpoint.StrategyState); err != nil {
+		return nil, fmt.Errorf("failed to restore strategy state from checkpoint: %w", err)
+	}
+	e.executor.(CheckpointExecutor).RestoreState(checkpoint.Executor)
+
+	orderManager := e.orderManager.(*BacktestOrderManager)
+	orderManager.mu.Lock()
+	orderManager.pendingOrders = make(map[string]*PendingOrder, len(checkpoint.PendingOrders))
+	for _, saved := range checkpoint.PendingOrders {
+		order := saved.PendingOrder
+		order.filled = saved.Filled
+		orderManager.pendingOrders[order.ID] = &order
+	}
+	orderManager.mu.Unlock()
+
+	e.protectedEntries = checkpoint.ProtectedEntries
+	e.lastSignalOrder = checkpoint.LastSignalOrder
+	e.equityCurve = append([]EquityPoint(nil), checkpoint.EquityCurve...)
+	e.drawdown = newDrawdownTrackerFromState(checkpoint.Drawdown)
+	if e.riskManager != nil && checkpoint.Risk != nil {
+		e.riskManager.restore(checkpoint.Risk)
+	}
+
+	logger.Info(fmt.Sprintf("⏯️ 从回测断点恢复: kline_time=%s, klines=%d, orders=%d, saved_at=%s",
+		checkpoint.KlineTime.Format("2006-01-02 15:04"), checkpoint.KlineCount, len(checkpoint.Executor.Orders),
+		checkpoint.SavedAt.Format("2006-01-02 15:04:05")))
+	return checkpoint, nil
+}
+
+// writeCheckpoint 每处理 checkpointEvery 根K线写一次断点（失败只记录错误，不影响回测）
+func (e *TradingEngine) writeCheckpoint(ctx context.Context, kline *cex.KlineData, klineCount int) {
+	if e.checkpointPath == "" || klineCount%e.checkpointEvery != 0 {
+		return
+	}
+	_, logger := log.WithCtx(ctx)
+
+	if err := e.saveCheckpoint(kline, klineCount); err != nil {
+		logger.Error("写入回测断点失败", "path", e.checkpointPath, "error", err)
+	}
+}
+
+// saveCheckpoint 把当前状态写入断点文件
+func (e *TradingEngine) saveCheckpoint(kline *cex.KlineData, klineCount int) error {
+	strategyState, err := e.strategy.Save()
+	if err != nil {
+		return fmt.Errorf("failed to save strategy state: %w", err)
+	}
+
+	checkpoint := &BacktestCheckpoint{
+		Version:          checkpointVersion,
+		RunKey:           e.checkpointKey,
+		Strategy:         e.strategy.GetName(),
+		KlineTime:        kline.OpenTime,
+		KlineCount:       klineCount,
+		SavedAt:          time.Now(),
+		StrategyState:    strategyState,
+		Executor:         e.executor.(CheckpointExecutor).ExportState(),
+		ProtectedEntries: e.protectedEntries,
+		LastSignalOrder:  e.lastSignalOrder,
+		EquityCurve:      e.equityCurve,
+		Drawdown:         e.drawdown.state(),
+	}
+	for _, order := range e.orderManager.GetPendingOrders() {
+		checkpoint.PendingOrders = append(checkpoint.PendingOrders, CheckpointOrder{PendingOrder: *order, Filled: order.filled})
+	}
+	if e.riskManager != nil {
+		checkpoint.Risk = e.riskManager.state()
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if dir := filepath.Dir(e.checkpointPath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := e.checkpointPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.checkpointPath)
+}
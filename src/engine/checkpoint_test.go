@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cycleTestStrategy 按已处理K线数循环买卖，计数保存在策略状态中
+type cycleTestStrategy struct {
+	Bars int `json:"bars"`
+}
+
+func (s *cycleTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.Bars++
+	switch {
+	case s.Bars%10 == 3 && portfolio.Position.IsZero():
+		return []*strategy.Signal{{Type: "BUY", Strength: 0.8, Reason: "cycle buy"}}, nil
+	case s.Bars%10 == 8 && portfolio.Position.IsPositive():
+		return []*strategy.Signal{{Type: "SELL", Strength: 0.8, Reason: "cycle sell"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *cycleTestStrategy) GetName() string                                { return "CycleTestStrategy" }
+func (s *cycleTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *cycleTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *cycleTestStrategy) Save() ([]byte, error)                          { return json.Marshal(s) }
+func (s *cycleTestStrategy) Load(data []byte) error                         { return json.Unmarshal(data, s) }
+
+// newCheckpointTestEngine 使用回测执行器的引擎
+func newCheckpointTestEngine(klines []*cex.KlineData) (*TradingEngine, *executor.TradingExecutor) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	tradingExecutor := executor.NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	tradingExecutor.SetOrderStrategy(executor.NewBacktestOrderStrategy(pair))
+	engine := createTestTradingEngineWithMocks(&cycleTestStrategy{}, tradingExecutor, NewBacktestDataFeed(klines), NewBacktestOrderManager(tradingExecutor))
+	engine.SetRiskManager(NewRiskManager(RiskLimits{}))
+	return engine, tradingExecutor
+}
+
+func TestTradingEngine_ResumeFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	klines := CreateTestKlines(60, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Hour)
+	path := filepath.Join(t.TempDir(), "backtest.checkpoint.json")
+
+	// 完整运行一次作为基准
+	full, fullExecutor := newCheckpointTestEngine(klines)
+	require.NoError(t, full.Run(ctx))
+	require.NotEmpty(t, fullExecutor.GetOrders())
+
+	// 只运行前 25 根K线（模拟中断），第 20 根K线时写入断点
+	interrupted, _ := newCheckpointTestEngine(klines[:25])
+	require.NoError(t, interrupted.SetCheckpoint(path, 20, "run-1"))
+	require.NoError(t, interrupted.Run(ctx))
+
+	checkpoint, err := LoadBacktestCheckpoint(path)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, 20, checkpoint.KlineCount)
+	assert.Equal(t, klines[19].OpenTime, checkpoint.KlineTime)
+	assert.Equal(t, "CycleTestStrategy", checkpoint.Strategy)
+	require.NotNil(t, checkpoint.Risk)
+
+	// 从断点继续，结果与完整运行一致
+	resumed, resumedExecutor := newCheckpointTestEngine(klines)
+	require.NoError(t, resumed.SetCheckpoint(path, 20, "run-1"))
+	require.NoError(t, resumed.ResumeFrom(checkpoint))
+	require.NoError(t, resumed.Run(ctx))
+
+	assertSameJSON(t, withoutOrderIDs(fullExecutor.ExportState()), withoutOrderIDs(resumedExecutor.ExportState()))
+	assertSameJSON(t, full.GetEquityCurve(), resumed.GetEquityCurve())
+	assertSameJSON(t, full.GetDrawdown(), resumed.GetDrawdown())
+	assert.Len(t, resumed.GetKlines(), len(klines))
+}
+
+// withoutOrderIDs 清除按时间生成的订单ID
+func withoutOrderIDs(state executor.ExecutorState) executor.ExecutorState {
+	for i := range state.Orders {
+		state.Orders[i].OrderID, state.Orders[i].ClientOrderID = "", ""
+	}
+	return state
+}
+
+// assertSameJSON 按 JSON 比较（断点恢复的 decimal 数值相同但内部精度表示可能不同）
+func assertSameJSON(t *testing.T, expected, actual any) {
+	expectedJSON, err := json.Marshal(expected)
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestTradingEngine_CheckpointValidation(t *testing.T) {
+	klines := CreateTestKlines(5, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Hour)
+	engine, _ := newCheckpointTestEngine(klines)
+
+	assert.Error(t, engine.SetCheckpoint("checkpoint.json", 0, "run-1"))
+	require.NoError(t, engine.SetCheckpoint("checkpoint.json", 10, "run-1"))
+	assert.Error(t, engine.ResumeFrom(&BacktestCheckpoint{RunKey: "run-2", Strategy: "CycleTestStrategy"}))
+	assert.Error(t, engine.ResumeFrom(&BacktestCheckpoint{RunKey: "run-1", Strategy: "OtherStrategy"}))
+	assert.NoError(t, engine.ResumeFrom(&BacktestCheckpoint{RunKey: "run-1", Strategy: "CycleTestStrategy"}))
+
+	// 不支持导出状态的执行器
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(100), decimal.Zero)
+	mock := createTestTradingEngineWithMocks(&cycleTestStrategy{}, mockExecutor, NewBacktestDataFeed(klines), NewBacktestOrderManager(mockExecutor))
+	assert.Error(t, mock.SetCheckpoint("checkpoint.json", 10, "run-1"))
+}
+
+func TestLoadBacktestCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+
+	checkpoint, err := LoadBacktestCheckpoint(filepath.Join(dir, "missing.json"))
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	path := filepath.Join(dir, "old.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 0}`), 0o644))
+	_, err = LoadBacktestCheckpoint(path)
+	assert.Error(t, err)
+}
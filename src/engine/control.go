@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// ErrNoRiskManager 未设置风控管理器时不支持手动暂停开仓
+var ErrNoRiskManager = errors.New("risk manager not set")
+
+// PauseEntries 手动暂停开仓：撤销开仓挂单，之后的买单被风控拒绝，止盈止损等平仓挂单不受影响。
+// 在两根K线之间执行，可从其他 goroutine（终端界面、管理接口）调用
+func (e *TradingEngine) PauseEntries(ctx context.Context, reason string) error {
+	if e.riskManager == nil {
+		return ErrNoRiskManager
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event := e.riskManager.SetEntriesPaused(true, reason, time.Now())
+	if event == nil {
+		return nil
+	}
+	_, logger := log.WithCtx(ctx)
+	logger.Warning(fmt.Sprintf("⏸️ 手动暂停开仓: %s", reason))
+	e.cancelEntryOrders(ctx)
+	e.events.Publish(ctx, &Event{Type: EventRisk, Time: event.Time, TradingPair: e.tradingPair, Risk: event})
+	return nil
+}
+
+// ResumeEntries 手动恢复开仓（当日亏损、回撤或熔断造成的暂停不受影响）
+func (e *TradingEngine) ResumeEntries(ctx context.Context, reason string) error {
+	if e.riskManager == nil {
+		return ErrNoRiskManager
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event := e.riskManager.SetEntriesPaused(false, reason, time.Now())
+	if event == nil {
+		return nil
+	}
+	_, logger := log.WithCtx(ctx)
+	logger.Info(fmt.Sprintf("▶️ 手动恢复开仓: %s", reason))
+	e.events.Publish(ctx, &Event{Type: EventRisk, Time: event.Time, TradingPair: e.tradingPair, Risk: event})
+	return nil
+}
+
+// EntriesPaused 是否手动暂停开仓
+func (e *TradingEngine) EntriesPaused() bool {
+	return e.riskManager != nil && e.riskManager.IsManuallyPaused()
+}
+
+// Flatten 手动清仓：暂停开仓，撤销全部挂单后按最近一根K线的收盘价市价卖出全部持仓
+func (e *TradingEngine) Flatten(ctx context.Context, reason string) error {
+	if e.riskManager == nil {
+		return ErrNoRiskManager
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.lastKlines) == 0 {
+		return errors.New("no kline processed yet")
+	}
+	kline := e.lastKlines[len(e.lastKlines)-1]
+	portfolio, err := e.executor.GetPortfolio(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	now := time.Now()
+	e.riskManager.SetEntriesPaused(true, reason, now)
+	e.flattenPosition(ctx, kline, portfolio, OriginManualFlatten, reason)
+
+	event := &RiskEvent{Type: RiskEventManualFlatten, Reason: reason, DailyPnL: e.riskManager.DailyPnL(), Time: now}
+	e.events.Publish(ctx, &Event{Type: EventRisk, Time: now, TradingPair: e.tradingPair, Risk: event})
+	return nil
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingEngine_PauseAndResumeEntries(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &cancelCountingOrderManager{}
+	orderManager.placedOrders = []*PendingOrder{
+		{ID: "buy-1", Type: PendingOrderTypeBuyLimit},
+		{ID: "stop-1", Type: PendingOrderTypeStopLoss},
+	}
+	bus := NewEventBus()
+	var events []*RiskEvent
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event.Risk) }, EventRisk)
+	engine := &TradingEngine{orderManager: orderManager, tradingPair: pair}
+	engine.SetRiskManager(NewRiskManager(RiskLimits{}))
+	engine.SetEventBus(bus)
+
+	// 暂停：只撤销开仓挂单，之后的买单被拒绝，卖单不受影响
+	require.NoError(t, engine.PauseEntries(context.Background(), "test"))
+	assert.True(t, engine.EntriesPaused())
+	assert.Equal(t, []string{"buy-1"}, orderManager.cancelledOrders)
+	require.Len(t, events, 1)
+	assert.Equal(t, RiskEventManualPaused, events[0].Type)
+
+	buy := &PendingOrder{TradingPair: pair, Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}
+	sell := &PendingOrder{TradingPair: pair, Type: PendingOrderTypeSellLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}
+	assert.ErrorIs(t, engine.riskManager.CheckOrder(buy), ErrEntriesPaused)
+	assert.NoError(t, engine.riskManager.CheckOrder(sell))
+
+	// 重复暂停不重复撤单和发布事件
+	require.NoError(t, engine.PauseEntries(context.Background(), "test"))
+	assert.Len(t, orderManager.cancelledOrders, 1)
+	assert.Len(t, events, 1)
+
+	require.NoError(t, engine.ResumeEntries(context.Background(), "test"))
+	assert.False(t, engine.EntriesPaused())
+	require.Len(t, events, 2)
+	assert.Equal(t, RiskEventManualResumed, events[1].Type)
+	assert.NoError(t, engine.riskManager.CheckOrder(buy))
+}
+
+func TestTradingEngine_Flatten(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &cancelCountingOrderManager{}
+	bus := NewEventBus()
+	var events []*RiskEvent
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event.Risk) }, EventRisk)
+	engine := &TradingEngine{
+		orderManager: orderManager,
+		executor:     newMockOrderExecutor(decimal.NewFromInt(100), decimal.NewFromInt(2)),
+		tradingPair:  pair,
+	}
+	engine.SetRiskManager(NewRiskManager(RiskLimits{}))
+	engine.SetEventBus(bus)
+
+	// 还没有K线时无法确定价格
+	assert.Error(t, engine.Flatten(context.Background(), "test"))
+
+	engine.lastKlines = append(engine.lastKlines, CreateTestKlineWithPrices(time.Now(),
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100)))
+	require.NoError(t, engine.Flatten(context.Background(), "test"))
+
+	assert.Equal(t, 1, orderManager.cancelAllCount)
+	require.Len(t, orderManager.placedOrders, 1)
+	order := orderManager.placedOrders[0]
+	assert.Equal(t, PendingOrderTypeSellMarket, order.Type)
+	assert.Equal(t, OriginManualFlatten, order.OriginSignal)
+	assert.True(t, decimal.NewFromInt(2).Equal(order.Quantity))
+	assert.True(t, engine.EntriesPaused())
+	require.Len(t, events, 1)
+	assert.Equal(t, RiskEventManualFlatten, events[0].Type)
+}
+
+func TestTradingEngine_ControlWithoutRiskManager(t *testing.T) {
+	engine := &TradingEngine{orderManager: &mockTradingOrderManager{}}
+
+	assert.ErrorIs(t, engine.PauseEntries(context.Background(), "test"), ErrNoRiskManager)
+	assert.ErrorIs(t, engine.ResumeEntries(context.Background(), "test"), ErrNoRiskManager)
+	assert.ErrorIs(t, engine.Flatten(context.Background(), "test"), ErrNoRiskManager)
+	assert.False(t, engine.EntriesPaused())
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"tradingbot/src/cex"
@@ -86,28 +87,43 @@ func (f *BacktestDataFeed) GetCurrentTime() time.Time {
 
 // LiveDataFeed 实盘数据喂入器
 type LiveDataFeed struct {
-	cexClient   cex.CEXClient
-	tradingPair cex.TradingPair
-	interval    string
-	ticker      *time.Ticker
-	stopChan    chan struct{}
-	currentTime time.Time
+	cexClient      cex.CEXClient
+	tradingPair    cex.TradingPair
+	interval       string
+	tickerInterval time.Duration
+	ticker         *time.Ticker
+	stopChan       chan struct{}
+	currentTime    time.Time
+
+	// 看门狗重启：取消卡住的请求并立即重新获取
+	restart       chan struct{}
+	mu            sync.Mutex
+	cancelRequest context.CancelFunc
 }
 
 // NewLiveDataFeed 创建实盘数据喂入器
 func NewLiveDataFeed(cexClient cex.CEXClient, tradingPair cex.TradingPair, interval string, tickerInterval time.Duration) *LiveDataFeed {
 	return &LiveDataFeed{
-		cexClient:   cexClient,
-		tradingPair: tradingPair,
-		interval:    interval,
-		ticker:      time.NewTicker(tickerInterval),
-		stopChan:    make(chan struct{}),
-		currentTime: time.Now(),
+		cexClient:      cexClient,
+		tradingPair:    tradingPair,
+		interval:       interval,
+		tickerInterval: tickerInterval,
+		ticker:         time.NewTicker(tickerInterval),
+		stopChan:       make(chan struct{}),
+		currentTime:    time.Now(),
+		restart:        make(chan struct{}, 1),
 	}
 }
 
 func (f *LiveDataFeed) Start(ctx context.Context) error {
 	f.currentTime = time.Now()
+	// 引擎重启时重新创建已停止的数据流
+	select {
+	case <-f.stopChan:
+		f.stopChan = make(chan struct{})
+		f.ticker = time.NewTicker(f.tickerInterval)
+	default:
+	}
 	return nil
 }
 
@@ -120,29 +136,55 @@ func (f *LiveDataFeed) GetNext(ctx context.Context) (*cex.KlineData, error) {
 	case <-f.stopChan:
 		return nil, nil // 数据流结束
 	case <-f.ticker.C:
-		f.currentTime = time.Now()
-		logger.Info("📡 LiveDataFeed开始获取数据",
-			"trading_pair", f.tradingPair.String(),
-			"interval", f.interval,
-			"current_time", f.currentTime.Format("15:04:05"))
-
-		// 获取最新K线数据
-		klines, err := f.cexClient.GetKlines(ctx, f.tradingPair, f.interval, 1)
-		if err != nil {
-			logger.Error("❌ 获取K线数据失败", "error", err)
-			return nil, err
-		}
-
-		if len(klines) == 0 {
-			logger.Info("⚠️ 没有获取到K线数据")
-			return nil, nil
-		}
-
-		logger.Info("✅ 成功获取K线数据",
-			"klines_count", len(klines),
-			"kline_open_time", klines[0].OpenTime.Format("15:04:05"),
-			"close_price", klines[0].Close.String())
-		return klines[0], nil
+	case <-f.restart:
+	}
+
+	f.currentTime = time.Now()
+	logger.Info("📡 LiveDataFeed开始获取数据",
+		"trading_pair", f.tradingPair.String(),
+		"interval", f.interval,
+		"current_time", f.currentTime.Format("15:04:05"))
+
+	// 获取最新K线数据（看门狗重启时取消）
+	requestCtx, cancel := context.WithCancel(ctx)
+	f.mu.Lock()
+	f.cancelRequest = cancel
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.cancelRequest = nil
+		f.mu.Unlock()
+		cancel()
+	}()
+
+	klines, err := f.cexClient.GetKlines(requestCtx, f.tradingPair, f.interval, 1)
+	if err != nil {
+		logger.Error("❌ 获取K线数据失败", "error", err)
+		return nil, err
+	}
+
+	if len(klines) == 0 {
+		logger.Info("⚠️ 没有获取到K线数据")
+		return nil, nil
+	}
+
+	logger.Info("✅ 成功获取K线数据",
+		"klines_count", len(klines),
+		"kline_open_time", klines[0].OpenTime.Format("15:04:05"),
+		"close_price", klines[0].Close.String())
+	return klines[0], nil
+}
+
+// Restart 看门狗判定停滞时调用：取消正在进行的请求并立即重新获取最新K线
+func (f *LiveDataFeed) Restart() {
+	f.mu.Lock()
+	if f.cancelRequest != nil {
+		f.cancelRequest()
+	}
+	f.mu.Unlock()
+	select {
+	case f.restart <- struct{}{}:
+	default:
 	}
 }
 
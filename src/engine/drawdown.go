@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// DrawdownInfo 回撤信息结构
+type DrawdownInfo struct {
+	MaxDrawdown        decimal.Decimal // 最大回撤金额
+	MaxDrawdownPercent decimal.Decimal // 最大回撤百分比
+	DrawdownDuration   time.Duration   // 最大回撤持续时间（峰值到恢复，未恢复时到最后一根K线）
+	CurrentDrawdown    decimal.Decimal // 当前回撤
+	PeakValue          decimal.Decimal // 历史最高价值
+
+	DrawdownPeakTime   time.Time     // 最大回撤开始前的峰值时间
+	DrawdownTroughTime time.Time     // 最大回撤的谷底时间
+	RecoveryTime       time.Time     // 回到峰值的时间，未恢复时为零值
+	RecoveryDuration   time.Duration // 谷底到恢复的时间，未恢复时为0
+	LongestUnderwater  time.Duration // 最长水下时间（低于前高的最长连续时间，不一定是最大回撤那一段）
+}
+
+// DrawdownTracker 逐根K线跟踪组合价值的峰值、谷底和回撤，每根K线 O(1)，不需要保留K线或资金曲线
+type DrawdownTracker struct {
+	initialValue       decimal.Decimal
+	lastValue          decimal.Decimal
+	peakValue          decimal.Decimal
+	maxDrawdown        decimal.Decimal
+	maxDrawdownPercent decimal.Decimal
+
+	started                                         bool
+	peakTime, maxPeakTime, troughTime, recoveryTime time.Time
+	lastTime                                        time.Time
+	longestUnderwater                               time.Duration
+	underwater                                      bool // 当前低于峰值
+	maxDrawdownOpen                                 bool // 最大回撤所在的水下区间尚未恢复
+}
+
+// NewDrawdownTracker 创建回撤跟踪器，initialValue 视为第一根K线开盘时的峰值（为0时以第一根K线收盘时的价值为起点）
+func NewDrawdownTracker(initialValue decimal.Decimal) *DrawdownTracker {
+	return &DrawdownTracker{
+		initialValue: initialValue,
+		lastValue:    initialValue,
+		peakValue:    initialValue,
+		maxDrawdown:  decimal.Zero,
+	}
+}
+
+// Update 记录K线收盘时的组合价值
+func (t *DrawdownTracker) Update(kline *cex.KlineData, value decimal.Decimal) {
+	if !t.started {
+		t.started = true
+		t.peakTime = kline.OpenTime
+		if t.peakTime.IsZero() {
+			t.peakTime = kline.CloseTime
+		}
+	}
+	t.lastTime = kline.CloseTime
+	t.lastValue = value
+
+	// 回到或超过峰值：结束水下区间并更新峰值
+	if value.GreaterThanOrEqual(t.peakValue) {
+		if t.underwater {
+			t.longestUnderwater = max(t.longestUnderwater, kline.CloseTime.Sub(t.peakTime))
+			if t.maxDrawdownOpen {
+				t.recoveryTime = kline.CloseTime
+				t.maxDrawdownOpen = false
+			}
+			t.underwater = false
+		}
+		if value.GreaterThan(t.peakValue) {
+			t.peakValue = value
+		}
+		t.peakTime = kline.CloseTime
+	} else {
+		t.underwater = true
+	}
+
+	// 更新最大回撤
+	currentDrawdown := t.peakValue.Sub(value)
+	if currentDrawdown.GreaterThan(t.maxDrawdown) {
+		t.maxDrawdown = currentDrawdown
+		t.maxDrawdownPercent = decimal.Zero
+		if t.peakValue.IsPositive() {
+			t.maxDrawdownPercent = currentDrawdown.Div(t.peakValue).Mul(decimal.NewFromInt(100))
+		}
+		t.maxPeakTime = t.peakTime
+		t.troughTime = kline.CloseTime
+		t.maxDrawdownOpen = true
+	}
+}
+
+// Drawdown 截至最近一根K线的回撤统计
+func (t *DrawdownTracker) Drawdown() DrawdownInfo {
+	if !t.started {
+		return DrawdownInfo{
+			PeakValue: t.initialValue,
+		}
+	}
+
+	// 仍在水下：持续时间计到最近一根K线
+	longestUnderwater := t.longestUnderwater
+	if t.underwater {
+		longestUnderwater = max(longestUnderwater, t.lastTime.Sub(t.peakTime))
+	}
+	var drawdownDuration, recoveryDuration time.Duration
+	if t.maxDrawdown.IsPositive() {
+		if t.maxDrawdownOpen {
+			drawdownDuration = t.lastTime.Sub(t.maxPeakTime)
+		} else {
+			drawdownDuration = t.recoveryTime.Sub(t.maxPeakTime)
+			recoveryDuration = t.recoveryTime.Sub(t.troughTime)
+		}
+	}
+
+	return DrawdownInfo{
+		MaxDrawdown:        t.maxDrawdown,
+		MaxDrawdownPercent: t.maxDrawdownPercent,
+		DrawdownDuration:   drawdownDuration,
+		CurrentDrawdown:    t.peakValue.Sub(t.lastValue),
+		PeakValue:          t.peakValue,
+		DrawdownPeakTime:   t.maxPeakTime,
+		DrawdownTroughTime: t.troughTime,
+		RecoveryTime:       t.recoveryTime,
+		RecoveryDuration:   recoveryDuration,
+		LongestUnderwater:  longestUnderwater,
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func drawdownTestKline(start time.Time, i int, price int64) *cex.KlineData {
+	return &cex.KlineData{
+		OpenTime:  start.Add(time.Duration(i) * time.Hour),
+		CloseTime: start.Add(time.Duration(i+1) * time.Hour),
+		Open:      decimal.NewFromInt(price), High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price),
+	}
+}
+
+func TestDrawdownTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewDrawdownTracker(decimal.NewFromInt(1000))
+
+	info := tracker.Drawdown()
+	assert.True(t, info.MaxDrawdown.IsZero())
+	assert.Equal(t, "1000", info.PeakValue.String())
+
+	// 1100 峰值 → 880 谷底（-20%）→ 1100 恢复 → 1050 当前回撤
+	for i, value := range []int64{1050, 1100, 990, 880, 1000, 1100, 1050} {
+		tracker.Update(drawdownTestKline(start, i, value), decimal.NewFromInt(value))
+	}
+
+	info = tracker.Drawdown()
+	assert.Equal(t, "220", info.MaxDrawdown.String())
+	assert.Equal(t, "20", info.MaxDrawdownPercent.String())
+	assert.Equal(t, "1100", info.PeakValue.String())
+	assert.Equal(t, "50", info.CurrentDrawdown.String())
+	assert.Equal(t, start.Add(2*time.Hour), info.DrawdownPeakTime)
+	assert.Equal(t, start.Add(4*time.Hour), info.DrawdownTroughTime)
+	assert.Equal(t, start.Add(6*time.Hour), info.RecoveryTime)
+	assert.Equal(t, 4*time.Hour, info.DrawdownDuration)
+	assert.Equal(t, 2*time.Hour, info.RecoveryDuration)
+	assert.Equal(t, 4*time.Hour, info.LongestUnderwater)
+}
+
+func TestDrawdownTracker_UnknownInitialValue(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := NewDrawdownTracker(decimal.Zero)
+
+	// 初始价值未知时以第一根K线收盘时的价值为峰值，未恢复的回撤计到最后一根K线
+	tracker.Update(drawdownTestKline(start, 0, 500), decimal.NewFromInt(500))
+	tracker.Update(drawdownTestKline(start, 1, 400), decimal.NewFromInt(400))
+
+	info := tracker.Drawdown()
+	assert.Equal(t, "500", info.PeakValue.String())
+	assert.Equal(t, "20", info.MaxDrawdownPercent.String())
+	assert.True(t, info.RecoveryTime.IsZero())
+	assert.Equal(t, time.Hour, info.DrawdownDuration)
+}
+
+func TestTradingEngine_TracksDrawdownPerKline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var klines []*cex.KlineData
+	for i, price := range []int64{100, 90, 95, 80, 110} {
+		klines = append(klines, drawdownTestKline(start, i, price))
+	}
+
+	mockStrategy := &mockTradingStrategy{}
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	engine := createTestTradingEngineWithMocks(mockStrategy, mockExecutor, NewBacktestDataFeed(klines), NewBacktestOrderManager(mockExecutor))
+	require.NoError(t, engine.Run(context.Background()))
+
+	// 与按资金曲线重新计算的结果一致
+	expected := NewDrawdownTracker(decimal.NewFromInt(10000))
+	for i, point := range engine.GetEquityCurve() {
+		expected.Update(klines[i], point.PortfolioValue)
+	}
+	assert.Equal(t, expected.Drawdown(), engine.GetDrawdown())
+}
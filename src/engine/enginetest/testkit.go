@@ -0,0 +1,97 @@
+// Package enginetest 策略测试工具：构造K线并在回测引擎上运行策略
+package enginetest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPair 测试默认交易对
+var DefaultPair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+// DefaultStartTime 测试K线默认起始时间
+var DefaultStartTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewKline 创建一根K线
+func NewKline(openTime time.Time, interval time.Duration, open, high, low, close float64) *cex.KlineData {
+	return &cex.KlineData{
+		TradingPair: DefaultPair,
+		OpenTime:    openTime,
+		Open:        decimal.NewFromFloat(open),
+		High:        decimal.NewFromFloat(high),
+		Low:         decimal.NewFromFloat(low),
+		Close:       decimal.NewFromFloat(close),
+		Volume:      decimal.NewFromInt(1000),
+		CloseTime:   openTime.Add(interval - time.Millisecond),
+		QuoteVolume: decimal.NewFromFloat(close * 1000),
+	}
+}
+
+// KlinesFromCloses 根据收盘价序列生成连续K线（开盘价为上一根收盘价）
+func KlinesFromCloses(interval time.Duration, closes ...float64) []*cex.KlineData {
+	klines := make([]*cex.KlineData, 0, len(closes))
+	for i, c := range closes {
+		open := c
+		if i > 0 {
+			open = closes[i-1]
+		}
+		high, low := open, open
+		if c > high {
+			high = c
+		}
+		if c < low {
+			low = c
+		}
+		klines = append(klines, NewKline(DefaultStartTime.Add(time.Duration(i)*interval), interval, open, high, low, c))
+	}
+	return klines
+}
+
+// Result 回测运行结果
+type Result struct {
+	Engine    *engine.TradingEngine
+	Executor  *executor.TradingExecutor
+	Orders    []executor.OrderResult
+	Portfolio *executor.Portfolio
+}
+
+// RunBacktest 在回测引擎上用给定K线运行策略（与 trading 包的回测流程一致）
+func RunBacktest(ctx context.Context, strategyImpl strategy.Strategy, timeframe timeframes.Timeframe, klines []*cex.KlineData, initialCapital float64) (*Result, error) {
+	backtestExecutor := executor.NewTradingExecutor(DefaultPair, decimal.NewFromFloat(initialCapital))
+	backtestExecutor.SetOrderStrategy(executor.NewBacktestOrderStrategy(DefaultPair))
+
+	tradingEngine := engine.NewTradingEngine(
+		DefaultPair,
+		timeframe,
+		strategyImpl,
+		backtestExecutor,
+		nil,
+		engine.NewBacktestDataFeed(klines),
+		engine.NewBacktestOrderManager(backtestExecutor),
+	)
+
+	if err := tradingEngine.Run(ctx); err != nil {
+		return nil, fmt.Errorf("backtest failed: %w", err)
+	}
+
+	portfolio, err := backtestExecutor.GetPortfolio(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	return &Result{
+		Engine:    tradingEngine,
+		Executor:  backtestExecutor,
+		Orders:    backtestExecutor.GetOrders(),
+		Portfolio: portfolio,
+	}, nil
+}
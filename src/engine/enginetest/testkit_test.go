@@ -0,0 +1,60 @@
+package enginetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buyThenSellStrategy 第1根K线买入，第3根K线卖出
+type buyThenSellStrategy struct {
+	bar int
+}
+
+func (s *buyThenSellStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.bar++
+	switch s.bar {
+	case 1:
+		return []*strategy.Signal{{Type: "BUY", Strength: 1, Reason: "test buy"}}, nil
+	case 3:
+		return []*strategy.Signal{{Type: "SELL", Strength: 1, Reason: "test sell"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *buyThenSellStrategy) GetName() string                                { return "BuyThenSell" }
+func (s *buyThenSellStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *buyThenSellStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *buyThenSellStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *buyThenSellStrategy) Load(data []byte) error                         { return nil }
+
+func TestKlinesFromCloses(t *testing.T) {
+	klines := KlinesFromCloses(time.Hour, 100, 105, 95)
+	require.Len(t, klines, 3)
+	assert.Equal(t, DefaultStartTime.Add(time.Hour), klines[1].OpenTime)
+	assert.Equal(t, "100", klines[1].Open.String())
+	assert.Equal(t, "105", klines[1].High.String())
+	assert.Equal(t, "95", klines[2].Low.String())
+	assert.True(t, klines[0].CloseTime.Before(klines[1].OpenTime))
+}
+
+func TestRunBacktest(t *testing.T) {
+	klines := KlinesFromCloses(4*time.Hour, 100, 99, 110, 120, 120)
+
+	result, err := RunBacktest(context.Background(), &buyThenSellStrategy{}, timeframes.Timeframe4h, klines, 10000)
+	require.NoError(t, err)
+
+	require.Len(t, result.Orders, 2)
+	assert.Equal(t, executor.OrderSideBuy, result.Orders[0].Side)
+	assert.Equal(t, executor.OrderSideSell, result.Orders[1].Side)
+	assert.True(t, result.Portfolio.Position.IsZero())
+	assert.Len(t, result.Engine.GetEquityCurve(), len(klines))
+}
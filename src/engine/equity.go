@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// EquityPoint 资金曲线上的一个点（每根K线收盘时记录）
+type EquityPoint struct {
+	Timestamp      time.Time       `json:"timestamp"`       // K线收盘时间
+	Price          decimal.Decimal `json:"price"`           // K线收盘价
+	Cash           decimal.Decimal `json:"cash"`            // 现金余额
+	Position       decimal.Decimal `json:"position"`        // 持仓数量
+	PortfolioValue decimal.Decimal `json:"portfolio_value"` // 组合价值 = 现金 + 持仓 × 收盘价
+}
+
+// newEquityPoint 根据K线和投资组合生成资金曲线点
+func newEquityPoint(kline *cex.KlineData, portfolio *executor.Portfolio) EquityPoint {
+	return EquityPoint{
+		Timestamp:      kline.CloseTime,
+		Price:          kline.Close,
+		Cash:           portfolio.Cash,
+		Position:       portfolio.Position,
+		PortfolioValue: portfolio.Cash.Add(portfolio.Position.Mul(kline.Close)),
+	}
+}
+
+// WriteEquityCurveCSV 以CSV格式写出资金曲线，每行带上运行跟踪ID（可为空）
+func WriteEquityCurveCSV(w io.Writer, curve []EquityPoint, traceID string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"timestamp", "price", "cash", "position", "portfolio_value", "trace_id"}); err != nil {
+		return fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+
+	for _, point := range curve {
+		record := []string{
+			point.Timestamp.UTC().Format(time.RFC3339),
+			point.Price.String(),
+			point.Cash.String(),
+			point.Position.String(),
+			point.PortfolioValue.String(),
+			traceID,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV记录失败: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// tracedEquityPoint 导出时带运行跟踪ID的资金曲线点
+type tracedEquityPoint struct {
+	EquityPoint
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// WriteEquityCurveJSON 以JSON格式写出资金曲线，每个点带上运行跟踪ID（为空时省略）
+func WriteEquityCurveJSON(w io.Writer, curve []EquityPoint, traceID string) error {
+	points := make([]tracedEquityPoint, 0, len(curve))
+	for _, point := range curve {
+		points = append(points, tracedEquityPoint{EquityPoint: point, TraceID: traceID})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(points); err != nil {
+		return fmt.Errorf("写入JSON失败: %w", err)
+	}
+	return nil
+}
+
+// ExportEquityCurve 导出资金曲线到文件，按扩展名选择格式（.csv 或 .json），traceID 为产生该曲线的运行跟踪ID
+func ExportEquityCurve(path string, curve []EquityPoint, traceID string) error {
+	var write func(io.Writer, []EquityPoint, string) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		write = WriteEquityCurveCSV
+	case ".json":
+		write = WriteEquityCurveJSON
+	default:
+		return fmt.Errorf("unsupported equity curve format: %s (expected .csv or .json)", path)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建资金曲线文件失败: %w", err)
+	}
+
+	if err := write(file, curve, traceID); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingEngine_Run_RecordsEquityCurve(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(99), decimal.NewFromInt(100)),
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), decimal.NewFromInt(100), decimal.NewFromInt(102), decimal.NewFromInt(98), decimal.NewFromInt(102)),
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), decimal.NewFromInt(102), decimal.NewFromInt(110), decimal.NewFromInt(101), decimal.NewFromInt(110)),
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	engine := createTestTradingEngineWithMocks(
+		&ladderTestStrategy{},
+		mockExecutor,
+		&mockTradingDataFeed{klines: klines},
+		NewBacktestOrderManager(mockExecutor),
+	)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	curve := engine.GetEquityCurve()
+	require.Len(t, curve, len(klines))
+	for i, point := range curve {
+		assert.Equal(t, klines[i].CloseTime, point.Timestamp)
+		assert.True(t, point.Price.Equal(klines[i].Close))
+		assert.True(t, point.PortfolioValue.Equal(point.Cash.Add(point.Position.Mul(point.Price))))
+	}
+
+	// 第一根K线只产生信号，第二根K线买单成交后开始持仓
+	assert.True(t, curve[0].Position.IsZero())
+	assert.True(t, curve[0].PortfolioValue.Equal(decimal.NewFromInt(10000)))
+	assert.True(t, curve[1].Position.IsPositive())
+	assert.True(t, curve[2].PortfolioValue.GreaterThan(curve[1].PortfolioValue))
+}
+
+func TestExportEquityCurve(t *testing.T) {
+	curve := []EquityPoint{
+		{
+			Timestamp:      time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC),
+			Price:          decimal.NewFromInt(100),
+			Cash:           decimal.NewFromInt(500),
+			Position:       decimal.NewFromInt(95),
+			PortfolioValue: decimal.NewFromInt(10000),
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteEquityCurveCSV(&buf, curve, "backtest-20240101T000000Z-abcdef"))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, "timestamp,price,cash,position,portfolio_value,trace_id", lines[0])
+		assert.Equal(t, "2024-01-01T04:00:00Z,100,500,95,10000,backtest-20240101T000000Z-abcdef", lines[1])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "equity.json")
+		require.NoError(t, ExportEquityCurve(path, curve, "backtest-20240101T000000Z-abcdef"))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var decoded []EquityPoint
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Len(t, decoded, 1)
+		assert.True(t, decoded[0].PortfolioValue.Equal(decimal.NewFromInt(10000)))
+		assert.Contains(t, string(data), `"trace_id": "backtest-20240101T000000Z-abcdef"`)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := ExportEquityCurve(filepath.Join(t.TempDir(), "equity.txt"), curve, "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventKlineProcessed  EventType = "kline_processed"  // 一根K线处理完成（挂单撮合、策略分析、信号处理）
+	EventSignalGenerated EventType = "signal_generated" // 策略产生交易信号
+	EventOrderPlaced     EventType = "order_placed"     // 挂单已提交
+	EventOrderFilled     EventType = "order_filled"     // 挂单成交（含部分成交）
+	EventOrderCancelled  EventType = "order_cancelled"  // 挂单在交易所被撤销、拒绝或过期
+	EventPositionClosed  EventType = "position_closed"  // 卖出成交后持仓清空
+	EventRisk            EventType = "risk"             // 风控状态变化（熔断、暂停开仓、恢复）
+	EventError           EventType = "error"            // 运行错误
+	EventEngineStopped   EventType = "engine_stopped"   // 引擎退出
+	EventEngineRestarted EventType = "engine_restarted" // 引擎意外退出后被自动重启
+	EventFeedStalled     EventType = "feed_stalled"     // 实盘数据喂入停滞（超时没有新K线）
+	EventFeedRecovered   EventType = "feed_recovered"   // 停滞的数据喂入恢复
+	EventShutdown        EventType = "shutdown"         // 实盘停止处理完成（撤单、清仓、保存状态）
+)
+
+// KnownEventTypes 所有事件类型
+func KnownEventTypes() []EventType {
+	return []EventType{
+		EventKlineProcessed, EventSignalGenerated, EventOrderPlaced, EventOrderFilled, EventOrderCancelled,
+		EventPositionClosed, EventRisk, EventError, EventEngineStopped, EventEngineRestarted,
+		EventFeedStalled, EventFeedRecovered, EventShutdown,
+	}
+}
+
+// Event 引擎事件
+type Event struct {
+	Type          EventType
+	Time          time.Time
+	TradingPair   cex.TradingPair
+	Kline         *cex.KlineData        // kline_processed / signal_generated
+	Signal        *strategy.Signal      // signal_generated
+	Indicators    map[string]float64    // signal_generated / kline_processed：策略的指标快照（策略实现 strategy.IndicatorProvider 时）
+	Portfolio     *executor.Portfolio   // kline_processed / position_closed
+	PendingOrders []PendingOrder        // kline_processed：处理完成后仍在挂单的订单（副本）
+	Latency       *LatencySnapshot      // kline_processed：实盘延迟和时钟偏差（设置了延迟统计时）
+	Order         *PendingOrder         // order_placed / order_cancelled
+	Fill          *executor.OrderResult // order_filled / position_closed（清仓的卖出成交）
+	Risk          *RiskEvent            // risk
+	Shutdown      *ShutdownReport       // shutdown
+	Message       string                // error：出错的操作；order_cancelled：交易所订单状态；engine_stopped：退出原因；engine_restarted：重启原因和次数；feed_stalled / feed_recovered：停滞时长；shutdown：处理摘要
+	Err           error                 // error
+}
+
+// EventHandler 事件处理函数，同步调用，不应阻塞
+type EventHandler func(ctx context.Context, event *Event)
+
+// eventSubscription 事件订阅
+type eventSubscription struct {
+	types   map[EventType]bool // 为空时接收全部事件
+	handler EventHandler
+}
+
+// EventBus 进程内事件总线：引擎和挂单管理器发布事件，通知等组件订阅
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions []eventSubscription
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe 订阅事件，不指定类型时接收全部事件
+func (b *EventBus) Subscribe(handler EventHandler, types ...EventType) {
+	subscription := eventSubscription{handler: handler}
+	if len(types) > 0 {
+		subscription.types = make(map[EventType]bool, len(types))
+		for _, eventType := range types {
+			subscription.types[eventType] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription)
+}
+
+// Publish 发布事件（未设置事件总线时忽略）
+func (b *EventBus) Publish(ctx context.Context, event *Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, subscription := range subscriptions {
+		if subscription.types == nil || subscription.types[event.Type] {
+			subscription.handler(ctx, event)
+		}
+	}
+}
+
+// SetEventBus 设置事件总线
+func (e *TradingEngine) SetEventBus(bus *EventBus) {
+	e.events = bus
+}
+
+// publishFills 发布本根K线的成交事件，卖出成交后持仓清空时发布平仓事件
+func (e *TradingEngine) publishFills(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) {
+	var lastSell *executor.OrderResult
+	for _, result := range executed {
+		if result == nil || !result.Success {
+			continue
+		}
+		e.events.Publish(ctx, &Event{Type: EventOrderFilled, Time: kline.CloseTime, TradingPair: e.tradingPair, Fill: result})
+		if result.Side == executor.OrderSideSell {
+			lastSell = result
+		}
+	}
+
+	if lastSell != nil && !portfolio.Position.IsPositive() {
+		e.events.Publish(ctx, &Event{Type: EventPositionClosed, Time: kline.CloseTime, TradingPair: e.tradingPair,
+			Fill: lastSell, Portfolio: portfolio})
+	}
+}
+
+// pendingOrderSnapshot 当前挂单的副本（按创建时间排序），未设置事件总线时返回 nil
+func (e *TradingEngine) pendingOrderSnapshot() []PendingOrder {
+	if e.events == nil {
+		return nil
+	}
+	pending := e.orderManager.GetPendingOrders()
+	orders := make([]PendingOrder, 0, len(pending))
+	for _, order := range pending {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreateTime.Equal(orders[j].CreateTime) {
+			return orders[i].ID < orders[j].ID
+		}
+		return orders[i].CreateTime.Before(orders[j].CreateTime)
+	})
+	return orders
+}
+
+// publishError 发布运行错误事件
+func (e *TradingEngine) publishError(ctx context.Context, message string, err error) {
+	e.events.Publish(ctx, &Event{Type: EventError, Time: time.Now(), TradingPair: e.tradingPair, Message: message, Err: err})
+}
+
+// SetEventBus 设置事件总线，交易所撤销、拒绝或过期的挂单发布 order_cancelled 事件
+func (m *LiveOrderManager) SetEventBus(bus *EventBus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = bus
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus_SubscribeByType(t *testing.T) {
+	bus := NewEventBus()
+	var all, fills []EventType
+	bus.Subscribe(func(ctx context.Context, event *Event) { all = append(all, event.Type) })
+	bus.Subscribe(func(ctx context.Context, event *Event) { fills = append(fills, event.Type) }, EventOrderFilled)
+
+	bus.Publish(context.Background(), &Event{Type: EventOrderPlaced})
+	bus.Publish(context.Background(), &Event{Type: EventOrderFilled})
+
+	assert.Equal(t, []EventType{EventOrderPlaced, EventOrderFilled}, all)
+	assert.Equal(t, []EventType{EventOrderFilled}, fills)
+
+	// 未设置事件总线时忽略
+	var empty *EventBus
+	empty.Publish(context.Background(), &Event{Type: EventError})
+}
+
+func TestTradingEngine_PublishesOrderEvents(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus := NewEventBus()
+	var events []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event) })
+
+	engine := &TradingEngine{
+		orderManager:        &mockTradingOrderManager{},
+		tradingPair:         pair,
+		positionSizePercent: decimal.NewFromFloat(0.5),
+		minTradeAmount:      decimal.NewFromInt(1),
+	}
+	engine.SetEventBus(bus)
+
+	kline := CreateTestKlineWithPrices(time.Now(),
+		decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	signal := &strategy.Signal{Type: "BUY", Strength: 1, Reason: "test"}
+	require.NoError(t, engine.handleBuySignal(context.Background(), signal, kline, &executor.Portfolio{Cash: decimal.NewFromInt(1000)}))
+
+	engine.publishFills(context.Background(), []*executor.OrderResult{
+		{Side: executor.OrderSideBuy, Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(100), Success: true},
+		{Success: false},
+	}, kline, &executor.Portfolio{Cash: decimal.NewFromInt(500), Position: decimal.NewFromInt(5)})
+
+	require.Len(t, events, 2)
+	assert.Equal(t, EventOrderPlaced, events[0].Type)
+	assert.Equal(t, PendingOrderTypeBuyLimit, events[0].Order.Type)
+	// 挂单携带信号上下文（信号价格为信号K线收盘价）
+	require.NotNil(t, events[0].Order.Signal)
+	assert.Equal(t, "test", events[0].Order.Signal.Reason)
+	assert.Equal(t, "100", events[0].Order.Signal.Price.String())
+	assert.Equal(t, EventOrderFilled, events[1].Type)
+	assert.Equal(t, pair, events[1].TradingPair)
+
+	// 卖出后持仓清空，发布平仓事件
+	events = nil
+	engine.publishFills(context.Background(), []*executor.OrderResult{
+		{Side: executor.OrderSideSell, Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(110), Success: true},
+	}, kline, &executor.Portfolio{Cash: decimal.NewFromInt(1050)})
+	require.Len(t, events, 2)
+	assert.Equal(t, EventPositionClosed, events[1].Type)
+	assert.Equal(t, "110", events[1].Fill.Price.String())
+}
+
+func TestLiveOrderManager_PublishesCancelledOrders(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewLiveOrderManager(&MockCEXClient{})
+	bus := NewEventBus()
+	var events []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event) }, EventOrderCancelled)
+	manager.SetEventBus(bus)
+
+	manager.pendingOrders["trail_1"] = &PendingOrder{ID: "trail_1", TradingPair: pair, Type: PendingOrderTypeTrailingStop}
+	manager.stopOrderIDs["trail_1"] = "100"
+	manager.applyOrderUpdate(&cex.OrderUpdate{OrderID: "100", Status: cex.OrderStatusCanceled, ExecutionType: "CANCELED"}, pair)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "trail_1", events[0].Order.ID)
+	assert.Equal(t, cex.OrderStatusCanceled, events[0].Message)
+}
+
+func TestTradingEngine_RunPublishesLifecycleEvents(t *testing.T) {
+	klines := CreateTestKlines(3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 4*time.Hour)
+	engine := createTestTradingEngineWithMocks(
+		&mockTradingStrategy{},
+		newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero),
+		&mockTradingDataFeed{klines: klines},
+		&mockTradingOrderManager{},
+	)
+
+	bus := NewEventBus()
+	counts := make(map[EventType]int)
+	var stopReason string
+	bus.Subscribe(func(ctx context.Context, event *Event) {
+		counts[event.Type]++
+		if event.Type == EventEngineStopped {
+			stopReason = event.Message
+		}
+	})
+	engine.SetEventBus(bus)
+
+	require.NoError(t, engine.Run(context.Background()))
+	assert.Equal(t, 3, counts[EventKlineProcessed])
+	assert.Equal(t, 2, counts[EventSignalGenerated]) // 第1、3根K线产生信号
+	assert.Equal(t, 1, counts[EventEngineStopped])
+	assert.Equal(t, "data feed finished", stopReason)
+}
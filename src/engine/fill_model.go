@@ -0,0 +1,283 @@
+package engine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// FillDecision 成交模型对一次撮合的判定
+type FillDecision struct {
+	Filled   bool            // 本根K线是否成交
+	Price    decimal.Decimal // 成交价格（含滑点）
+	Quantity decimal.Decimal // 成交数量，为零表示全部成交；小于挂单数量时剩余部分继续挂单
+	Reason   string          // 未成交原因或滑点说明
+}
+
+// FillModel 回测成交模型：决定挂单触价后能否成交以及实际成交价格
+type FillModel interface {
+	// Fill 判定挂单在该K线上的成交情况，price 为不考虑流动性时的成交价格
+	Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision
+
+	// Describe 模型参数说明（用于回测报告头部）
+	Describe() string
+}
+
+// IlliquidityFillModel 流动性感知的成交模型，适用于 PEPE/WIF 等流动性较差的币种
+// 参与率 = 订单金额 / K线成交额，成交概率和滑点都随参与率变化：
+//
+//	滑点     = SlippageCoefficient × 参与率^SlippageExponent（不超过 MaxSlippage）
+//	成交概率 = 1 / (1 + (参与率 / HalfFillParticipation)^FillCurveExponent)
+type IlliquidityFillModel struct {
+	SlippageCoefficient   float64 // 滑点系数
+	SlippageExponent      float64 // 滑点曲线指数（0.5 即平方根冲击模型）
+	MaxSlippage           float64 // 最大滑点比例
+	HalfFillParticipation float64 // 成交概率为50%时的参与率
+	FillCurveExponent     float64 // 成交概率曲线陡峭程度
+	Seed                  int64   // 随机种子
+}
+
+// NewIlliquidityFillModel 创建流动性感知成交模型
+// 是否成交由 seed、K线时间和挂单价格确定性地决定，相同参数的回测结果可复现
+func NewIlliquidityFillModel(slippageCoefficient, slippageExponent, maxSlippage, halfFillParticipation, fillCurveExponent float64, seed int64) *IlliquidityFillModel {
+	return &IlliquidityFillModel{
+		SlippageCoefficient:   slippageCoefficient,
+		SlippageExponent:      slippageExponent,
+		MaxSlippage:           maxSlippage,
+		HalfFillParticipation: halfFillParticipation,
+		FillCurveExponent:     fillCurveExponent,
+		Seed:                  seed,
+	}
+}
+
+// Validate 验证模型参数
+func (m *IlliquidityFillModel) Validate() error {
+	if m.SlippageCoefficient < 0 || m.SlippageExponent < 0 || m.MaxSlippage < 0 {
+		return fmt.Errorf("slippage parameters must be non-negative")
+	}
+	if m.MaxSlippage >= 1 {
+		return fmt.Errorf("max slippage must be less than 1, got %f", m.MaxSlippage)
+	}
+	if m.HalfFillParticipation <= 0 {
+		return fmt.Errorf("half fill participation must be positive, got %f", m.HalfFillParticipation)
+	}
+	if m.FillCurveExponent <= 0 {
+		return fmt.Errorf("fill curve exponent must be positive, got %f", m.FillCurveExponent)
+	}
+	return nil
+}
+
+// Participation 订单金额占K线成交额的比例，无成交额时返回 +Inf
+func (m *IlliquidityFillModel) Participation(notional decimal.Decimal, kline *cex.KlineData) float64 {
+	if !kline.QuoteVolume.IsPositive() {
+		return math.Inf(1)
+	}
+	return notional.Div(kline.QuoteVolume).InexactFloat64()
+}
+
+// FillProbability 给定参与率下的成交概率
+func (m *IlliquidityFillModel) FillProbability(participation float64) float64 {
+	if math.IsInf(participation, 1) {
+		return 0
+	}
+	return 1 / (1 + math.Pow(participation/m.HalfFillParticipation, m.FillCurveExponent))
+}
+
+// Slippage 给定参与率下的滑点比例
+func (m *IlliquidityFillModel) Slippage(participation float64) float64 {
+	slippage := m.SlippageCoefficient * math.Pow(participation, m.SlippageExponent)
+	return math.Min(slippage, m.MaxSlippage)
+}
+
+// Fill 按参与率随机判定是否成交，成交价向不利方向偏移（不超出K线最高/最低价）
+func (m *IlliquidityFillModel) Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision {
+	participation := m.Participation(order.Quantity.Mul(price), kline)
+
+	probability := m.FillProbability(participation)
+	if m.draw(order, kline) >= probability {
+		return FillDecision{
+			Filled: false,
+			Reason: fmt.Sprintf("insufficient liquidity: participation=%.2f%%, fill_probability=%.1f%%",
+				participation*100, probability*100),
+		}
+	}
+
+	slippage := m.Slippage(participation)
+	return FillDecision{
+		Filled: true,
+		Price:  slippedPrice(order, kline, price, slippage),
+		Reason: fmt.Sprintf("participation=%.2f%%, slippage=%.3f%%", participation*100, slippage*100),
+	}
+}
+
+// draw 生成 [0, 1) 的确定性伪随机数
+func (m *IlliquidityFillModel) draw(order *PendingOrder, kline *cex.KlineData) float64 {
+	return deterministicDraw(m.Seed, order, kline)
+}
+
+// Describe 模型参数说明
+func (m *IlliquidityFillModel) Describe() string {
+	return fmt.Sprintf("illiquid (slippage=%.3f×participation^%.2f, max %.1f%%; 50%% fill at %.1f%% of bar volume, curve exponent %.1f)",
+		m.SlippageCoefficient, m.SlippageExponent, m.MaxSlippage*100, m.HalfFillParticipation*100, m.FillCurveExponent)
+}
+
+// deterministicDraw 由种子、K线时间和挂单生成 [0, 1) 的确定性伪随机数（不依赖挂单遍历顺序，可并发使用）
+func deterministicDraw(seed int64, order *PendingOrder, kline *cex.KlineData) float64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%d|%s|%s|%s", seed, kline.OpenTime.UnixMilli(), order.Type, order.Price.String(), order.Quantity.String())
+	return float64(h.Sum64()>>11) / float64(1<<53)
+}
+
+// slippedPrice 成交价向不利方向偏移 slippage 比例（买入上移、卖出下移），不超出K线最高/最低价
+func slippedPrice(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal, slippage float64) decimal.Decimal {
+	if slippage <= 0 {
+		return price
+	}
+	ratio := decimal.NewFromFloat(slippage)
+	if order.side() == cex.OrderSideBuy {
+		return decimal.Min(price.Mul(decimal.NewFromInt(1).Add(ratio)), decimal.Max(kline.High, price))
+	}
+	return decimal.Max(price.Mul(decimal.NewFromInt(1).Sub(ratio)), decimal.Min(kline.Low, price))
+}
+
+// FixedSlippageFillModel 固定滑点成交模型：触价即成交，成交价向不利方向偏移固定基点
+type FixedSlippageFillModel struct {
+	SlippageBps float64 // 滑点（基点，1bp = 0.01%）
+}
+
+// NewFixedSlippageFillModel 创建固定滑点成交模型
+func NewFixedSlippageFillModel(slippageBps float64) *FixedSlippageFillModel {
+	return &FixedSlippageFillModel{SlippageBps: slippageBps}
+}
+
+// Validate 验证模型参数
+func (m *FixedSlippageFillModel) Validate() error {
+	if m.SlippageBps < 0 || m.SlippageBps >= 10000 {
+		return fmt.Errorf("slippage bps must be in [0, 10000), got %f", m.SlippageBps)
+	}
+	return nil
+}
+
+// Fill 全部成交，价格包含固定滑点
+func (m *FixedSlippageFillModel) Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision {
+	return FillDecision{
+		Filled: true,
+		Price:  slippedPrice(order, kline, price, m.SlippageBps/10000),
+		Reason: fmt.Sprintf("slippage=%.1fbps", m.SlippageBps),
+	}
+}
+
+// Describe 模型参数说明
+func (m *FixedSlippageFillModel) Describe() string {
+	return fmt.Sprintf("fixed slippage %.1fbps", m.SlippageBps)
+}
+
+// VolumeCapFillModel 成交量上限模型：单根K线最多成交该K线成交量的 MaxParticipation 比例，
+// 超出部分继续挂单；PartialFills 为 true 时可成交量再乘以 (0, 1] 的随机比例，模拟盘口深度波动
+type VolumeCapFillModel struct {
+	MaxParticipation float64 // 单根K线最多成交的成交量比例
+	PartialFills     bool    // 是否随机部分成交
+	Seed             int64   // 随机种子
+}
+
+// NewVolumeCapFillModel 创建成交量上限模型
+func NewVolumeCapFillModel(maxParticipation float64, partialFills bool, seed int64) *VolumeCapFillModel {
+	return &VolumeCapFillModel{
+		MaxParticipation: maxParticipation,
+		PartialFills:     partialFills,
+		Seed:             seed,
+	}
+}
+
+// Validate 验证模型参数
+func (m *VolumeCapFillModel) Validate() error {
+	if m.MaxParticipation <= 0 || m.MaxParticipation > 1 {
+		return fmt.Errorf("max participation must be in (0, 1], got %f", m.MaxParticipation)
+	}
+	return nil
+}
+
+// Fill 按K线成交量计算可成交数量，不足时部分成交
+func (m *VolumeCapFillModel) Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision {
+	available := kline.Volume.Mul(decimal.NewFromFloat(m.MaxParticipation))
+	if m.PartialFills {
+		// 1 - draw 落在 (0, 1]
+		available = available.Mul(decimal.NewFromFloat(1 - deterministicDraw(m.Seed, order, kline)))
+	}
+
+	if !available.IsPositive() {
+		return FillDecision{Filled: false, Reason: "no volume available in bar"}
+	}
+	if available.GreaterThanOrEqual(order.Quantity) {
+		return FillDecision{Filled: true, Price: price}
+	}
+
+	return FillDecision{
+		Filled:   true,
+		Price:    price,
+		Quantity: available,
+		Reason: fmt.Sprintf("partial fill %s/%s (volume cap %.1f%% of %s)",
+			available.String(), order.Quantity.String(), m.MaxParticipation*100, kline.Volume.String()),
+	}
+}
+
+// Describe 模型参数说明
+func (m *VolumeCapFillModel) Describe() string {
+	if m.PartialFills {
+		return fmt.Sprintf("volume cap %.1f%% of bar volume with random partial fills", m.MaxParticipation*100)
+	}
+	return fmt.Sprintf("volume cap %.1f%% of bar volume", m.MaxParticipation*100)
+}
+
+// ChainFillModel 组合成交模型：依次应用各模型，价格逐个叠加，数量取最小值，任一模型未成交则不成交
+type ChainFillModel struct {
+	Models []FillModel
+}
+
+// NewChainFillModel 组合多个成交模型，只有一个时直接返回该模型，没有时返回 nil
+func NewChainFillModel(models ...FillModel) FillModel {
+	switch len(models) {
+	case 0:
+		return nil
+	case 1:
+		return models[0]
+	}
+	return &ChainFillModel{Models: models}
+}
+
+// Fill 依次应用各成交模型
+func (m *ChainFillModel) Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision {
+	result := FillDecision{Filled: true, Price: price}
+	var reasons []string
+
+	for _, model := range m.Models {
+		decision := model.Fill(order, kline, result.Price)
+		if !decision.Filled {
+			return decision
+		}
+		result.Price = decision.Price
+		if decision.Quantity.IsPositive() && (result.Quantity.IsZero() || decision.Quantity.LessThan(result.Quantity)) {
+			result.Quantity = decision.Quantity
+		}
+		if decision.Reason != "" {
+			reasons = append(reasons, decision.Reason)
+		}
+	}
+
+	result.Reason = strings.Join(reasons, "; ")
+	return result
+}
+
+// Describe 模型参数说明
+func (m *ChainFillModel) Describe() string {
+	descriptions := make([]string, 0, len(m.Models))
+	for _, model := range m.Models {
+		descriptions = append(descriptions, model.Describe())
+	}
+	return strings.Join(descriptions, " + ")
+}
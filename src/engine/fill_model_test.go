@@ -0,0 +1,216 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIlliquidityFillModel() *IlliquidityFillModel {
+	return NewIlliquidityFillModel(0.1, 0.5, 0.05, 0.1, 2, 1)
+}
+
+func TestIlliquidityFillModel_Curves(t *testing.T) {
+	model := newTestIlliquidityFillModel()
+	require.NoError(t, model.Validate())
+
+	// 参与率越高，成交概率越低、滑点越大
+	assert.InDelta(t, 1.0, model.FillProbability(0), 1e-9)
+	assert.InDelta(t, 0.5, model.FillProbability(0.1), 1e-9)
+	assert.Less(t, model.FillProbability(0.5), model.FillProbability(0.1))
+
+	assert.InDelta(t, 0.01, model.Slippage(0.01), 1e-9)
+	assert.InDelta(t, 0.05, model.Slippage(1), 1e-9) // 不超过最大滑点
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	kline.QuoteVolume = decimal.NewFromInt(10000)
+	assert.InDelta(t, 0.01, model.Participation(decimal.NewFromInt(100), kline), 1e-9)
+
+	kline.QuoteVolume = decimal.Zero
+	assert.Zero(t, model.FillProbability(model.Participation(decimal.NewFromInt(100), kline)))
+
+	assert.Error(t, NewIlliquidityFillModel(0.1, 0.5, 0.05, 0, 2, 1).Validate())
+}
+
+func TestIlliquidityFillModel_Fill(t *testing.T) {
+	model := newTestIlliquidityFillModel()
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(110), decimal.NewFromInt(90), decimal.NewFromInt(100))
+
+	t.Run("liquid bar fills with small slippage", func(t *testing.T) {
+		kline.QuoteVolume = decimal.NewFromInt(1000000)
+		order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100)) // 参与率 0.01%
+
+		decision := model.Fill(order, kline, order.Price)
+		require.True(t, decision.Filled)
+		assert.True(t, decision.Price.GreaterThan(order.Price))
+		assert.True(t, decision.Price.LessThan(decimal.NewFromFloat(100.2)))
+
+		sell := CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell", decimal.NewFromInt(100))
+		decision = model.Fill(sell, kline, sell.Price)
+		require.True(t, decision.Filled)
+		assert.True(t, decision.Price.LessThan(sell.Price))
+	})
+
+	t.Run("zero volume bar never fills", func(t *testing.T) {
+		kline.QuoteVolume = decimal.Zero
+		order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+		decision := model.Fill(order, kline, order.Price)
+		assert.False(t, decision.Filled)
+		assert.Contains(t, decision.Reason, "insufficient liquidity")
+	})
+
+	t.Run("slippage stays within bar range", func(t *testing.T) {
+		kline.QuoteVolume = decimal.NewFromInt(1000000)
+		kline.High = decimal.NewFromFloat(100.001)
+		order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+		decision := model.Fill(order, kline, order.Price)
+		require.True(t, decision.Filled)
+		assert.True(t, decision.Price.LessThanOrEqual(kline.High))
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		kline.QuoteVolume = decimal.NewFromInt(1000)
+		order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100)) // 参与率 10%
+		first := model.Fill(order, kline, order.Price)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, first, newTestIlliquidityFillModel().Fill(order, kline, order.Price))
+		}
+	})
+}
+
+func TestBacktestOrderManager_FillModelKeepsUnfilledOrders(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(100000), decimal.Zero)
+	manager := NewBacktestOrderManager(mockExecutor)
+	manager.SetFillModel(newTestIlliquidityFillModel())
+
+	ctx := context.Background()
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))))
+
+	// 触价但K线没有成交额：不成交，挂单保留
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(101), decimal.NewFromInt(102), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, 1, manager.GetOrderCount())
+
+	// 流动性充足：成交价含滑点
+	kline.QuoteVolume = decimal.NewFromInt(10000000)
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Price.GreaterThan(decimal.NewFromInt(100)))
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestFixedSlippageFillModel_Fill(t *testing.T) {
+	model := NewFixedSlippageFillModel(50) // 0.5%
+	require.NoError(t, model.Validate())
+	assert.Error(t, NewFixedSlippageFillModel(-1).Validate())
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(110), decimal.NewFromInt(90), decimal.NewFromInt(100))
+
+	buy := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+	decision := model.Fill(buy, kline, buy.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Price.Equal(decimal.NewFromFloat(100.5)))
+	assert.True(t, decision.Quantity.IsZero())
+
+	sell := CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell", decimal.NewFromInt(100))
+	decision = model.Fill(sell, kline, sell.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Price.Equal(decimal.NewFromFloat(99.5)))
+}
+
+func TestVolumeCapFillModel_Fill(t *testing.T) {
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(110), decimal.NewFromInt(90), decimal.NewFromInt(100))
+	kline.Volume = decimal.NewFromInt(10)
+	assert.Error(t, NewVolumeCapFillModel(0, false, 1).Validate())
+
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+	order.Quantity = decimal.NewFromInt(5)
+
+	// 上限 10% × 10 = 1，部分成交
+	decision := NewVolumeCapFillModel(0.1, false, 1).Fill(order, kline, order.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Quantity.Equal(decimal.NewFromInt(1)))
+	assert.True(t, decision.Price.Equal(order.Price))
+
+	// 上限足够，全部成交
+	decision = NewVolumeCapFillModel(0.5, false, 1).Fill(order, kline, order.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Quantity.IsZero())
+
+	// 随机部分成交不超过上限且可复现
+	model := NewVolumeCapFillModel(0.1, true, 7)
+	decision = model.Fill(order, kline, order.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Quantity.IsPositive())
+	assert.True(t, decision.Quantity.LessThanOrEqual(decimal.NewFromInt(1)))
+	assert.Equal(t, decision, model.Fill(order, kline, order.Price))
+
+	// 无成交量不成交
+	kline.Volume = decimal.Zero
+	assert.False(t, NewVolumeCapFillModel(0.1, false, 1).Fill(order, kline, order.Price).Filled)
+}
+
+func TestChainFillModel(t *testing.T) {
+	assert.Nil(t, NewChainFillModel())
+	single := NewFixedSlippageFillModel(10)
+	assert.Same(t, single, NewChainFillModel(single))
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(110), decimal.NewFromInt(90), decimal.NewFromInt(100))
+	kline.Volume = decimal.NewFromInt(10)
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+	order.Quantity = decimal.NewFromInt(5)
+
+	model := NewChainFillModel(NewFixedSlippageFillModel(100), NewVolumeCapFillModel(0.2, false, 1))
+	decision := model.Fill(order, kline, order.Price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Price.Equal(decimal.NewFromInt(101)))
+	assert.True(t, decision.Quantity.Equal(decimal.NewFromInt(2)))
+	assert.Contains(t, model.Describe(), " + ")
+
+	kline.Volume = decimal.Zero
+	assert.False(t, model.Fill(order, kline, order.Price).Filled)
+}
+
+func TestBacktestOrderManager_PartialFillKeepsRemainder(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(100000), decimal.Zero)
+	manager := NewBacktestOrderManager(mockExecutor)
+	manager.SetFillModel(NewVolumeCapFillModel(0.1, false, 1))
+
+	ctx := context.Background()
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy", decimal.NewFromInt(100))
+	order.Quantity = decimal.NewFromInt(3)
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(101), decimal.NewFromInt(102), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	kline.Volume = decimal.NewFromInt(20) // 每根K线最多成交 2
+
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Quantity.Equal(decimal.NewFromInt(2)))
+	assert.True(t, results[0].RemainingQuantity.Equal(decimal.NewFromInt(1)))
+	assert.True(t, results[0].IsPartiallyFilled())
+	require.Equal(t, 1, manager.GetOrderCount())
+	assert.True(t, manager.GetPendingOrders()[0].Quantity.Equal(decimal.NewFromInt(1)))
+
+	// 剩余数量在下一根K线成交（开盘价 99 更优），结果累计两次成交
+	kline = CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(99), decimal.NewFromInt(102), decimal.NewFromInt(98), decimal.NewFromInt(100))
+	kline.Volume = decimal.NewFromInt(20)
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Quantity.Equal(decimal.NewFromInt(3)))
+	assert.False(t, results[0].IsPartiallyFilled())
+	require.Len(t, results[0].Fills, 2)
+	assert.True(t, results[0].Fills[1].Quantity.Equal(decimal.NewFromInt(1)))
+	assert.InDelta(t, (2*100.0+99)/3, results[0].Price.InexactFloat64(), 1e-9) // 加权均价
+	assert.Equal(t, 0, manager.GetOrderCount())
+	assert.Equal(t, 2, mockExecutor.buyCallCount)
+}
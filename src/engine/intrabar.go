@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// IntrabarSource 回测K线内的细粒度行情（如 1s K线），用于按时间先后撮合同一根K线内触发的挂单
+type IntrabarSource interface {
+	// Intrabar 返回开盘时间在 [kline.OpenTime, kline.CloseTime] 内的细粒度K线，按开盘时间升序；没有数据时返回空
+	Intrabar(ctx context.Context, kline *cex.KlineData) ([]*cex.KlineData, error)
+
+	// Describe 数据来源说明（用于日志）
+	Describe() string
+}
+
+// SetIntrabarSource 设置K线内细粒度行情：有挂单时逐根细粒度K线撮合，
+// 同一根K线内的止损、止盈、限价单按实际触价先后成交，而不是按 OHLC 保守推断
+func (m *BacktestOrderManager) SetIntrabarSource(source IntrabarSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intrabar = source
+}
+
+// intrabarBarsLocked 撮合使用的K线序列：没有挂单、未设置细粒度行情或该K线内没有数据时只用K线本身
+func (m *BacktestOrderManager) intrabarBarsLocked(ctx context.Context, kline *cex.KlineData) []*cex.KlineData {
+	if m.intrabar == nil || len(m.pendingOrders) == 0 {
+		return []*cex.KlineData{kline}
+	}
+	_, logger := log.WithCtx(ctx)
+
+	bars, err := m.intrabar.Intrabar(ctx, kline)
+	if err != nil {
+		logger.Warning("读取K线内细粒度行情失败，按整根K线撮合", "source", m.intrabar.Describe(),
+			"open_time", kline.OpenTime.Format("2006-01-02 15:04"), "error", err)
+		return []*cex.KlineData{kline}
+	}
+	if len(bars) == 0 {
+		logger.Debug(fmt.Sprintf("K线内没有细粒度行情，按整根K线撮合: source=%s, open_time=%s",
+			m.intrabar.Describe(), kline.OpenTime.Format("2006-01-02 15:04")))
+		return []*cex.KlineData{kline}
+	}
+	return bars
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticIntrabarSource 返回固定的细粒度K线，记录读取次数
+type staticIntrabarSource struct {
+	bars  []*cex.KlineData
+	err   error
+	calls int
+}
+
+func (s *staticIntrabarSource) Intrabar(ctx context.Context, kline *cex.KlineData) ([]*cex.KlineData, error) {
+	s.calls++
+	return s.bars, s.err
+}
+
+func (s *staticIntrabarSource) Describe() string { return "static" }
+
+// intrabarPath 按价格路径生成每分钟一根的细粒度K线（开盘价为上一个价格）
+func intrabarPath(start time.Time, prices ...float64) []*cex.KlineData {
+	bars := make([]*cex.KlineData, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		open, close := decimal.NewFromFloat(prices[i-1]), decimal.NewFromFloat(prices[i])
+		bars = append(bars, &cex.KlineData{
+			OpenTime:  start.Add(time.Duration(i-1) * time.Minute),
+			CloseTime: start.Add(time.Duration(i)*time.Minute - time.Millisecond),
+			Open:      open, High: decimal.Max(open, close), Low: decimal.Min(open, close), Close: close,
+			Volume: decimal.NewFromInt(10),
+		})
+	}
+	return bars
+}
+
+func TestBacktestOrderManager_Intrabar_OCOTakeProfitFirst(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExecutor)
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// K线内先涨到止盈再跌破止损：整根K线撮合保守按止损成交，细粒度撮合按实际顺序止盈成交
+	manager.SetIntrabarSource(&staticIntrabarSource{bars: intrabarPath(start, 100, 110, 125, 100, 85, 100)})
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, price(100), decimal.NewFromInt(1), 0.2, 0.1, start)
+	require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, price(100), price(125), price(85), price(100)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, takeProfit.ID, results[0].OrderID)
+	assert.True(t, results[0].Price.Equal(price(120)))
+	assert.Equal(t, start.Add(time.Minute), results[0].Timestamp) // 成交时间为触价的细粒度K线
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestBacktestOrderManager_Intrabar_TrailingStopRatchetsWithinBar(t *testing.T) {
+	manager := NewBacktestOrderManager(newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1)))
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 先涨到 120 上移止损到 108，再回落触发
+	manager.SetIntrabarSource(&staticIntrabarSource{bars: intrabarPath(start, 100, 120, 105, 110)})
+	order := CreateTestPendingOrder(PendingOrderTypeTrailingStop, "trail", price(90))
+	order.TrailingPercent = 0.1
+	order.HighWaterMark = price(100)
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, price(100), price(120), price(105), price(110)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Price.Equal(price(108)))
+}
+
+func TestBacktestOrderManager_Intrabar_Fallback(t *testing.T) {
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kline := CreateTestKlineWithPrices(start, price(100), price(125), price(85), price(100))
+
+	for name, source := range map[string]*staticIntrabarSource{
+		"no data": {},
+		"error":   {err: errors.New("database down")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			manager := NewBacktestOrderManager(newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1)))
+			manager.SetIntrabarSource(source)
+
+			// 没有挂单时不读取细粒度行情
+			_, err := manager.CheckAndExecuteOrders(ctx, kline)
+			require.NoError(t, err)
+			assert.Equal(t, 0, source.calls)
+
+			// 没有细粒度行情时按整根K线撮合（同时触及保守按止损成交）
+			takeProfit, stopLoss := BuildOCOOrders(testOCOPair, price(100), decimal.NewFromInt(1), 0.2, 0.1, start)
+			require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+			results, err := manager.CheckAndExecuteOrders(ctx, kline)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, stopLoss.ID, results[0].OrderID)
+			assert.Equal(t, 1, source.calls)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OriginTakeProfitLadder 止盈阶梯挂单的来源标记
+const OriginTakeProfitLadder = "TAKE_PROFIT_LADDER"
+
+// BuildTakeProfitLadder 根据开仓价和持仓数量生成全部止盈阶梯挂单
+// SellPercent 为卖出当时剩余仓位的比例（与 PartialSellStrategy 一致），最后一级卖出全部剩余仓位
+func BuildTakeProfitLadder(pair cex.TradingPair, entryPrice, position decimal.Decimal, levels []strategy.PartialLevel, createTime time.Time) []*PendingOrder {
+	var orders []*PendingOrder
+	remaining := position
+
+	for i, level := range levels {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		quantity := remaining.Mul(decimal.NewFromFloat(level.SellPercent))
+		if i == len(levels)-1 || level.SellPercent >= 1 {
+			quantity = remaining
+		}
+		if !quantity.IsPositive() {
+			continue
+		}
+		remaining = remaining.Sub(quantity)
+
+		orders = append(orders, &PendingOrder{
+			ID:          generateShortOrderID(fmt.Sprintf("tp%d", i+1), pair.Base),
+			Type:        PendingOrderTypeSellLimit,
+			TradingPair: pair,
+			Quantity:    quantity,
+			Price:       entryPrice.Mul(decimal.NewFromFloat(1 + level.ProfitPercent)),
+			CreateTime:  createTime,
+			ExpireTime:  nil, // 持仓期间一直有效
+			Reason: fmt.Sprintf("take profit ladder level %d: +%.0f%% (sell %.0f%%)",
+				i+1, level.ProfitPercent*100, level.SellPercent*100),
+			OriginSignal: OriginTakeProfitLadder,
+		})
+	}
+
+	return orders
+}
+
+// syncTakeProfitLadder 维护止盈阶梯挂单：开仓成交后立即挂出整组止盈单，清仓后撤销剩余阶梯
+func (e *TradingEngine) syncTakeProfitLadder(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	provider, ok := e.strategy.(strategy.TakeProfitLadderProvider)
+	if !ok {
+		return nil
+	}
+	levels := provider.GetTakeProfitLadder()
+	if len(levels) == 0 {
+		return nil
+	}
+
+	ctx, logger := log.WithCtx(ctx)
+
+	var entry *executor.OrderResult
+	for _, result := range executed {
+		if result != nil && result.Success && result.Side == executor.OrderSideBuy {
+			entry = result
+		}
+	}
+
+	// 新开仓或已清仓时，旧的阶梯挂单都不再有效
+	if entry != nil || portfolio.Position.IsZero() {
+		for _, order := range e.orderManager.GetPendingOrders() {
+			if order.OriginSignal == OriginTakeProfitLadder {
+				if err := e.orderManager.CancelOrder(ctx, order.ID); err != nil {
+					logger.Error("取消止盈阶梯挂单失败", "id", order.ID, "error", err)
+				}
+			}
+		}
+	}
+
+	if entry == nil || portfolio.Position.IsZero() {
+		return nil
+	}
+
+	orders := BuildTakeProfitLadder(e.tradingPair, entry.Price, portfolio.Position, levels, kline.OpenTime)
+	logger.Info(fmt.Sprintf("🪜 挂出止盈阶梯: entry=%s, position=%s, levels=%d",
+		entry.Price.String(), portfolio.Position.String(), len(orders)))
+
+	for _, order := range orders {
+		if err := e.placeOrder(ctx, order); err != nil {
+			return fmt.Errorf("挂出止盈阶梯失败: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLadderLevels = []strategy.PartialLevel{
+	{ProfitPercent: 0.20, SellPercent: 0.30},
+	{ProfitPercent: 0.40, SellPercent: 0.40},
+	{ProfitPercent: 0.60, SellPercent: 1.00},
+}
+
+// ladderTestStrategy 第一根K线买入，止盈交给阶梯挂单
+type ladderTestStrategy struct {
+	onDataCalls int
+}
+
+func (s *ladderTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.onDataCalls++
+	if s.onDataCalls == 1 {
+		return []*strategy.Signal{{Type: "BUY", Strength: 0.8, Reason: "ladder test buy"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *ladderTestStrategy) GetName() string                                { return "LadderTestStrategy" }
+func (s *ladderTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *ladderTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *ladderTestStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *ladderTestStrategy) Load(data []byte) error                         { return nil }
+func (s *ladderTestStrategy) GetTakeProfitLadder() []strategy.PartialLevel   { return testLadderLevels }
+
+func TestBuildTakeProfitLadder(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orders := BuildTakeProfitLadder(pair, decimal.NewFromInt(100), decimal.NewFromInt(10), testLadderLevels, time.Now())
+	require.Len(t, orders, 3)
+
+	// 每级卖出剩余仓位的比例：3, 2.8, 4.2
+	assert.True(t, orders[0].Quantity.Equal(decimal.NewFromInt(3)))
+	assert.True(t, orders[1].Quantity.Equal(decimal.NewFromFloat(2.8)))
+	assert.True(t, orders[2].Quantity.Equal(decimal.NewFromFloat(4.2)))
+
+	assert.True(t, orders[0].Price.Equal(decimal.NewFromInt(120)))
+	assert.True(t, orders[1].Price.Equal(decimal.NewFromInt(140)))
+	assert.True(t, orders[2].Price.Equal(decimal.NewFromInt(160)))
+
+	total := decimal.Zero
+	for _, order := range orders {
+		assert.Equal(t, PendingOrderTypeSellLimit, order.Type)
+		assert.Equal(t, OriginTakeProfitLadder, order.OriginSignal)
+		assert.Nil(t, order.ExpireTime)
+		total = total.Add(order.Quantity)
+	}
+	assert.True(t, total.Equal(decimal.NewFromInt(10)))
+}
+
+func TestTradingEngine_Run_TakeProfitLadder(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(101), price(99.5), price(100)),                  // 买入信号
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(99), price(100)),   // 买单成交，挂出阶梯
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(101), price(150), price(100), price(105)),  // 快速插针，成交两级
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(105), price(170), price(104), price(110)), // 成交最后一级
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(
+		&ladderTestStrategy{},
+		mockExecutor,
+		&mockTradingDataFeed{klines: klines},
+		orderManager,
+	)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	assert.Equal(t, 1, mockExecutor.buyCallCount)
+	require.Len(t, mockExecutor.sellResults, 3)
+	assert.True(t, mockExecutor.position.IsZero())
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+
+	entryPrice := mockExecutor.buyResults[0].Price
+	for _, result := range mockExecutor.sellResults {
+		assert.True(t, result.Price.GreaterThan(entryPrice.Mul(decimal.NewFromFloat(1.19))))
+	}
+}
+
+func TestTradingEngine_SyncTakeProfitLadder_CancelsWhenFlat(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(&ladderTestStrategy{}, mockExecutor, &mockTradingDataFeed{}, orderManager)
+
+	ctx := context.Background()
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	for _, order := range BuildTakeProfitLadder(engine.tradingPair, decimal.NewFromInt(100), decimal.NewFromInt(1), testLadderLevels, kline.OpenTime) {
+		require.NoError(t, orderManager.PlaceOrder(ctx, order))
+	}
+	require.NoError(t, orderManager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_other", decimal.NewFromInt(90))))
+
+	// 仓位已被其他卖单清空，剩余阶梯挂单应撤销，其他挂单保留
+	err := engine.syncTakeProfitLadder(ctx, nil, kline, &executor.Portfolio{Cash: decimal.NewFromInt(10000), Position: decimal.Zero})
+	require.NoError(t, err)
+	assert.Equal(t, 1, orderManager.GetOrderCount())
+	assert.Equal(t, "buy_other", orderManager.GetPendingOrders()[0].ID)
+}
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// LatencyLimits 实盘延迟告警阈值（0 表示不告警）
+type LatencyLimits struct {
+	MaxDataDelayMs    int64 `json:"max_data_delay_ms"`    // K线收盘到引擎处理的最大延迟
+	MaxOrderLatencyMs int64 `json:"max_order_latency_ms"` // 下单/撤单请求到交易所响应的最大耗时
+	MaxClockDriftMs   int64 `json:"max_clock_drift_ms"`   // 本地时钟与交易所服务器时间的最大偏差
+	ClockCheckMinutes int   `json:"clock_check_minutes"`  // 检查时钟偏差的间隔（分钟），0 表示不检查
+}
+
+// Validate 检查阈值
+func (l LatencyLimits) Validate() error {
+	if l.MaxDataDelayMs < 0 || l.MaxOrderLatencyMs < 0 || l.MaxClockDriftMs < 0 || l.ClockCheckMinutes < 0 {
+		return fmt.Errorf("latency limits cannot be negative")
+	}
+	return nil
+}
+
+// LatencyStat 一类延迟的统计（毫秒）
+type LatencyStat struct {
+	Count  int     `json:"count"`
+	LastMs float64 `json:"last_ms"`
+	AvgMs  float64 `json:"avg_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// add 记录一个样本
+func (s *LatencyStat) add(ms float64) {
+	s.Count++
+	s.LastMs = ms
+	s.AvgMs += (ms - s.AvgMs) / float64(s.Count)
+	s.MaxMs = math.Max(s.MaxMs, ms)
+}
+
+// LatencySnapshot 实盘延迟和时钟偏差
+type LatencySnapshot struct {
+	DataDelay      LatencyStat `json:"data_delay"`       // K线收盘到引擎处理的延迟（只统计已收盘的K线）
+	FormingBars    int         `json:"forming_bars"`     // 收盘前处理的K线数（数据喂入取到的是未收盘K线）
+	OrderRoundTrip LatencyStat `json:"order_round_trip"` // 下单/撤单请求到交易所响应的耗时
+	ClockDriftMs   float64     `json:"clock_drift_ms"`   // 本地时间 - 交易所服务器时间（按请求往返中点估算）
+	ClockCheckedAt time.Time   `json:"clock_checked_at,omitempty"`
+}
+
+// LatencyMonitor 统计实盘数据延迟、下单往返耗时和本地时钟偏差，超过阈值时记录警告
+// 实现 cex.OrderAuditor，与审计日志一起接收下单/撤单的耗时
+type LatencyMonitor struct {
+	limits LatencyLimits
+
+	mu       sync.Mutex
+	snapshot LatencySnapshot
+}
+
+// NewLatencyMonitor 创建延迟统计
+func NewLatencyMonitor(limits LatencyLimits) *LatencyMonitor {
+	return &LatencyMonitor{limits: limits}
+}
+
+// ObserveKline 记录K线收盘到处理的延迟，未收盘的K线只计数
+func (m *LatencyMonitor) ObserveKline(ctx context.Context, kline *cex.KlineData, processedAt time.Time) {
+	if m == nil || kline == nil || kline.CloseTime.IsZero() {
+		return
+	}
+	delay := processedAt.Sub(kline.CloseTime)
+
+	m.mu.Lock()
+	if delay < 0 {
+		m.snapshot.FormingBars++
+		m.mu.Unlock()
+		return
+	}
+	delayMs := durationMs(delay)
+	m.snapshot.DataDelay.add(delayMs)
+	m.mu.Unlock()
+
+	if m.limits.MaxDataDelayMs > 0 && delayMs > float64(m.limits.MaxDataDelayMs) {
+		_, logger := log.WithCtx(ctx)
+		logger.Warning(fmt.Sprintf("🐢 K线处理延迟过高: close_time=%s, delay=%.0fms, max=%dms",
+			kline.CloseTime.Format("2006-01-02 15:04:05"), delayMs, m.limits.MaxDataDelayMs))
+	}
+}
+
+// RecordOrder 记录下单/撤单请求的往返耗时（cex.OrderAuditor）
+func (m *LatencyMonitor) RecordOrder(ctx context.Context, record *cex.OrderAuditRecord) {
+	latencyMs := float64(record.DurationMs)
+
+	m.mu.Lock()
+	m.snapshot.OrderRoundTrip.add(latencyMs)
+	m.mu.Unlock()
+
+	if m.limits.MaxOrderLatencyMs > 0 && record.DurationMs > m.limits.MaxOrderLatencyMs {
+		_, logger := log.WithCtx(ctx)
+		logger.Warning(fmt.Sprintf("🐢 交易所下单响应过慢: action=%s, symbol=%s, latency=%dms, max=%dms",
+			record.Action, record.Symbol, record.DurationMs, m.limits.MaxOrderLatencyMs))
+	}
+}
+
+// CheckClock 查询交易所服务器时间，按请求往返中点估算本地时钟偏差
+func (m *LatencyMonitor) CheckClock(ctx context.Context, provider cex.ServerTimeProvider) error {
+	sent := time.Now()
+	serverTime, err := provider.GetServerTime(ctx)
+	if err != nil {
+		return err
+	}
+	received := time.Now()
+	driftMs := durationMs(sent.Add(received.Sub(sent) / 2).Sub(serverTime))
+
+	m.mu.Lock()
+	m.snapshot.ClockDriftMs = driftMs
+	m.snapshot.ClockCheckedAt = received
+	m.mu.Unlock()
+
+	if m.limits.MaxClockDriftMs > 0 && math.Abs(driftMs) > float64(m.limits.MaxClockDriftMs) {
+		_, logger := log.WithCtx(ctx)
+		logger.Warning(fmt.Sprintf("⏱️ 本地时钟与交易所偏差过大，请同步系统时间: drift=%.0fms, max=%dms, round_trip=%dms",
+			driftMs, m.limits.MaxClockDriftMs, received.Sub(sent).Milliseconds()))
+	}
+	return nil
+}
+
+// StartClockCheck 立即检查一次时钟偏差，之后每隔 ClockCheckMinutes 检查，ctx 取消后退出
+func (m *LatencyMonitor) StartClockCheck(ctx context.Context, provider cex.ServerTimeProvider) {
+	if m.limits.ClockCheckMinutes <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(m.limits.ClockCheckMinutes) * time.Minute)
+		defer ticker.Stop()
+		for {
+			if err := m.CheckClock(ctx, provider); err != nil && ctx.Err() == nil {
+				_, logger := log.WithCtx(ctx)
+				logger.Warning(fmt.Sprintf("⚠️ 查询交易所服务器时间失败: %v", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Snapshot 当前统计（副本）
+func (m *LatencyMonitor) Snapshot() *LatencySnapshot {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := m.snapshot
+	return &snapshot
+}
+
+// SetLatencyMonitor 设置实盘延迟统计（回测不设置），K线处理完成事件携带当前统计
+func (e *TradingEngine) SetLatencyMonitor(monitor *LatencyMonitor) {
+	e.latency = monitor
+}
+
+// durationMs 时长的毫秒数
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServerTime 返回固定偏移的服务器时间
+type fakeServerTime struct {
+	offset time.Duration
+	err    error
+}
+
+func (f *fakeServerTime) GetServerTime(ctx context.Context) (time.Time, error) {
+	if f.err != nil {
+		return time.Time{}, f.err
+	}
+	return time.Now().Add(f.offset), nil
+}
+
+func TestLatencyLimits_Validate(t *testing.T) {
+	assert.NoError(t, LatencyLimits{}.Validate())
+	assert.NoError(t, LatencyLimits{MaxDataDelayMs: 10000, MaxOrderLatencyMs: 2000, MaxClockDriftMs: 1000, ClockCheckMinutes: 10}.Validate())
+	assert.Error(t, LatencyLimits{MaxOrderLatencyMs: -1}.Validate())
+}
+
+func TestLatencyMonitor_ObserveKline(t *testing.T) {
+	ctx := context.Background()
+	monitor := NewLatencyMonitor(LatencyLimits{MaxDataDelayMs: 1000})
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kline := &cex.KlineData{OpenTime: open, CloseTime: open.Add(time.Minute - time.Millisecond)}
+
+	// 收盘前处理（数据喂入取到未收盘K线）只计数
+	monitor.ObserveKline(ctx, kline, open.Add(30*time.Second))
+	monitor.ObserveKline(ctx, kline, kline.CloseTime.Add(500*time.Millisecond))
+	monitor.ObserveKline(ctx, kline, kline.CloseTime.Add(1500*time.Millisecond))
+
+	snapshot := monitor.Snapshot()
+	assert.Equal(t, 1, snapshot.FormingBars)
+	assert.Equal(t, 2, snapshot.DataDelay.Count)
+	assert.InDelta(t, 1500, snapshot.DataDelay.LastMs, 0.001)
+	assert.InDelta(t, 1000, snapshot.DataDelay.AvgMs, 0.001)
+	assert.InDelta(t, 1500, snapshot.DataDelay.MaxMs, 0.001)
+
+	// 未设置延迟统计时不统计
+	var none *LatencyMonitor
+	none.ObserveKline(ctx, kline, time.Now())
+	assert.Nil(t, none.Snapshot())
+}
+
+func TestLatencyMonitor_RecordOrder(t *testing.T) {
+	ctx := context.Background()
+	monitor := NewLatencyMonitor(LatencyLimits{MaxOrderLatencyMs: 500})
+	auditor := cex.CombineOrderAuditors(nil, monitor)
+
+	auditor.RecordOrder(ctx, &cex.OrderAuditRecord{Action: cex.AuditActionBuy, Symbol: "BTCUSDT", DurationMs: 200})
+	auditor.RecordOrder(ctx, &cex.OrderAuditRecord{Action: cex.AuditActionCancel, Symbol: "BTCUSDT", DurationMs: 800})
+
+	stat := monitor.Snapshot().OrderRoundTrip
+	assert.Equal(t, 2, stat.Count)
+	assert.InDelta(t, 800, stat.LastMs, 0.001)
+	assert.InDelta(t, 500, stat.AvgMs, 0.001)
+	assert.InDelta(t, 800, stat.MaxMs, 0.001)
+}
+
+func TestLatencyMonitor_CheckClock(t *testing.T) {
+	ctx := context.Background()
+	monitor := NewLatencyMonitor(LatencyLimits{MaxClockDriftMs: 1000})
+
+	// 服务器时间比本地快 2 秒，本地时钟偏差约 -2000ms
+	require.NoError(t, monitor.CheckClock(ctx, &fakeServerTime{offset: 2 * time.Second}))
+	snapshot := monitor.Snapshot()
+	assert.InDelta(t, -2000, snapshot.ClockDriftMs, 50)
+	assert.False(t, snapshot.ClockCheckedAt.IsZero())
+
+	// 查询失败时保留上一次的结果
+	assert.Error(t, monitor.CheckClock(ctx, &fakeServerTime{err: errors.New("timeout")}))
+	assert.InDelta(t, -2000, monitor.Snapshot().ClockDriftMs, 50)
+}
@@ -0,0 +1,208 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// liveLimitOrder 挂在交易所上的限价单
+type liveLimitOrder struct {
+	exchangeID  string
+	filled      decimal.Decimal // 已返回给引擎的成交数量（轮询订单状态时按增量返回）
+	filledQuote decimal.Decimal // 已返回给引擎的成交额
+}
+
+// recordFill 记录已返回给引擎的成交
+func (o *liveLimitOrder) recordFill(quantity, price decimal.Decimal) {
+	o.filled = o.filled.Add(quantity)
+	o.filledQuote = o.filledQuote.Add(quantity.Mul(price))
+}
+
+// newFill 按交易所返回的累计成交数量和成交均价计算尚未返回给引擎的成交，价格按累计成交额的增量计算
+func (o *liveLimitOrder) newFill(filled, averagePrice decimal.Decimal) (quantity, price decimal.Decimal, ok bool) {
+	quantity = filled.Sub(o.filled)
+	if !quantity.IsPositive() {
+		return decimal.Zero, decimal.Zero, false
+	}
+	return quantity, filled.Mul(averagePrice).Sub(o.filledQuote).Div(quantity), true
+}
+
+// isLimit 是否限价单
+func (o *PendingOrder) isLimit() bool {
+	return o.Type == PendingOrderTypeBuyLimit || o.Type == PendingOrderTypeSellLimit
+}
+
+// orderStatusClosed 订单是否已结束（全部成交、撤销、拒绝或过期）
+func orderStatusClosed(status string) bool {
+	switch status {
+	case cex.OrderStatusFilled, cex.OrderStatusCanceled, cex.OrderStatusRejected, cex.OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// placeLimitOrderLocked 按挂单的有效方式向交易所下限价单：GTC/IOC/FOK 由交易所执行，
+// GTD 按 GTC 下单、到 ExpireTime 后由本地撤销。下单即结束的订单（IOC/FOK 或立即全部成交）的成交在下次检查挂单时返回，
+// 其余挂单跟踪到成交或撤销（调用方需持有锁）
+func (m *LiveOrderManager) placeLimitOrderLocked(ctx context.Context, order *PendingOrder) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	// 挂在盘口的限价单需要撤单（过期、追价、停止运行）
+	if !order.TimeInForce.Immediate() {
+		if _, ok := m.cexClient.(cex.StopOrderClient); !ok {
+			return fmt.Errorf("%s does not support cancelling orders, cannot place %s limit order", m.cexClient.GetName(), order.TimeInForce)
+		}
+	}
+
+	var result *cex.OrderResult
+	var err error
+	if order.side() == cex.OrderSideBuy {
+		request := cex.BuyOrderRequest{TradingPair: order.TradingPair, Type: cex.OrderTypeLimit, Quantity: order.Quantity, Price: order.Price, TimeInForce: order.TimeInForce}
+		result, err = cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionBuy, order.TradingPair, request,
+			func() (*cex.OrderResult, error) {
+				return m.cexClient.Buy(ctx, request)
+			})
+	} else {
+		request := cex.SellOrderRequest{TradingPair: order.TradingPair, Type: cex.OrderTypeLimit, Quantity: order.Quantity, Price: order.Price, TimeInForce: order.TimeInForce}
+		result, err = cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionSell, order.TradingPair, request,
+			func() (*cex.OrderResult, error) {
+				return m.cexClient.Sell(ctx, request)
+			})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to place limit order %s: %w", order.ID, err)
+	}
+
+	if orderStatusClosed(result.Status) {
+		if result.Quantity.IsPositive() {
+			m.limitFills = append(m.limitFills, limitOrderFill(order, result.OrderID, result.Quantity, result.Price, result.TransactTime))
+		}
+		logger.Info(fmt.Sprintf("⚡ 实盘限价单下单即结束: id=%s, exchange_id=%s, tif=%s, status=%s, filled=%s/%s, price=%s",
+			order.ID, result.OrderID, order.TimeInForce, result.Status, result.Quantity.String(), order.Quantity.String(), order.Price.String()))
+		return nil
+	}
+
+	m.pendingOrders[order.ID] = order
+	m.limitOrders[order.ID] = &liveLimitOrder{exchangeID: result.OrderID}
+	logger.Info(fmt.Sprintf("📝 实盘限价单已挂出: id=%s, exchange_id=%s, side=%s, tif=%s, qty=%s, price=%s",
+		order.ID, result.OrderID, order.side(), order.TimeInForce, order.Quantity.String(), order.Price.String()))
+	return nil
+}
+
+// limitOrderFill 限价单成交结果
+func limitOrderFill(order *PendingOrder, exchangeID string, quantity, price decimal.Decimal, timestamp time.Time) *executor.OrderResult {
+	return &executor.OrderResult{
+		OrderID:       exchangeID,
+		ClientOrderID: order.ID,
+		TradingPair:   order.TradingPair,
+		Side:          executor.OrderSide(order.side()),
+		Quantity:      quantity,
+		Price:         price,
+		Timestamp:     timestamp,
+		Success:       true,
+		Reason:        order.Reason,
+		Origin:        order.OriginSignal,
+		Signal:        order.Signal,
+	}
+}
+
+// drainLimitFillsLocked 取出下单即结束的限价单成交（调用方需持有锁）
+func (m *LiveOrderManager) drainLimitFillsLocked() []*executor.OrderResult {
+	fills := m.limitFills
+	m.limitFills = nil
+	return fills
+}
+
+// cancelLimitOrderLocked 撤销交易所限价单并移除本地挂单，撤单失败时保留以便重试（调用方需持有锁）
+func (m *LiveOrderManager) cancelLimitOrderLocked(ctx context.Context, orderID string) error {
+	order, tracked := m.pendingOrders[orderID], m.limitOrders[orderID]
+	client, ok := m.cexClient.(cex.StopOrderClient)
+	if !ok {
+		return fmt.Errorf("%s does not support cancelling orders", m.cexClient.GetName())
+	}
+	if err := m.cancelExchangeOrder(ctx, client, order.TradingPair, tracked.exchangeID); err != nil {
+		return fmt.Errorf("failed to cancel limit order %s: %w", orderID, err)
+	}
+	delete(m.pendingOrders, orderID)
+	delete(m.limitOrders, orderID)
+	return nil
+}
+
+// sortedLimitOrderIDsLocked 按ID排序的限价挂单（调用方需持有锁）
+func (m *LiveOrderManager) sortedLimitOrderIDsLocked() []string {
+	ids := make([]string, 0, len(m.limitOrders))
+	for id := range m.limitOrders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// pollLimitOrdersLocked 未接入账户数据流时查询限价挂单状态，返回新增的成交，已结束的挂单不再跟踪（调用方需持有锁）
+func (m *LiveOrderManager) pollLimitOrdersLocked(ctx context.Context) ([]*executor.OrderResult, error) {
+	if len(m.limitOrders) == 0 {
+		return nil, nil
+	}
+	client, ok := m.cexClient.(cex.OpenOrderClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support querying orders, limit order fills need the user data stream", m.cexClient.GetName())
+	}
+
+	var fills []*executor.OrderResult
+	for _, id := range m.sortedLimitOrderIDsLocked() {
+		order, tracked := m.pendingOrders[id], m.limitOrders[id]
+		result, err := client.GetOrder(ctx, order.TradingPair, tracked.exchangeID)
+		if err != nil {
+			return fills, fmt.Errorf("failed to query limit order %s: %w", id, err)
+		}
+
+		// 查询结果的 Quantity 为累计成交数量，Price 为成交均价
+		if quantity, price, ok := tracked.newFill(result.Quantity, result.Price); ok {
+			fills = append(fills, limitOrderFill(order, tracked.exchangeID, quantity, price, time.Now()))
+			tracked.recordFill(quantity, price)
+			order.Quantity = order.Quantity.Sub(quantity)
+		}
+		if orderStatusClosed(result.Status) {
+			if result.Status != cex.OrderStatusFilled {
+				m.events.Publish(ctx, &Event{Type: EventOrderCancelled, Time: time.Now(), TradingPair: order.TradingPair, Order: order, Message: result.Status})
+			}
+			delete(m.pendingOrders, id)
+			delete(m.limitOrders, id)
+		}
+	}
+	return fills, nil
+}
+
+// expireLimitOrdersLocked 撤销开盘时间超过 ExpireTime 的限价挂单（GTD 的过期由本地执行，与回测一致），
+// 撤单失败时下根K线重试（调用方需持有锁）
+func (m *LiveOrderManager) expireLimitOrdersLocked(ctx context.Context, kline *cex.KlineData) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	var errs []error
+	for _, id := range m.sortedLimitOrderIDsLocked() {
+		order := m.pendingOrders[id]
+		if order.ExpireTime == nil || !kline.OpenTime.After(*order.ExpireTime) {
+			continue
+		}
+		exchangeID := m.limitOrders[id].exchangeID
+		if err := m.cancelLimitOrderLocked(ctx, id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info(fmt.Sprintf("⌛ 实盘限价单过期，已撤销: id=%s, exchange_id=%s, expire_time=%s", id, exchangeID, order.ExpireTime))
+		m.events.Publish(ctx, &Event{Type: EventOrderCancelled, Time: kline.OpenTime, TradingPair: order.TradingPair, Order: order, Message: cex.OrderStatusExpired})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to expire limit orders: %w", errors.Join(errs...))
+	}
+	return nil
+}
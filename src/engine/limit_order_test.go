@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLimitOrderCEXClient 记录限价单请求、可查询订单状态和撤单的CEX客户端mock
+type mockLimitOrderCEXClient struct {
+	mockStopOrderCEXClient
+	buys   []cex.BuyOrderRequest
+	sells  []cex.SellOrderRequest
+	status string                      // 下单返回的状态，为空时为 NEW
+	orders map[string]*cex.OrderResult // 交易所订单ID -> 查询结果
+}
+
+func (m *mockLimitOrderCEXClient) placed(quantity decimal.Decimal) *cex.OrderResult {
+	result := &cex.OrderResult{OrderID: fmt.Sprintf("ex_%d", len(m.buys)+len(m.sells)), Status: cex.OrderStatusNew}
+	if m.status != "" {
+		result.Status = m.status
+		result.Quantity = quantity
+		result.Price = decimal.NewFromInt(49990)
+	}
+	return result
+}
+
+func (m *mockLimitOrderCEXClient) Buy(ctx context.Context, req cex.BuyOrderRequest) (*cex.OrderResult, error) {
+	m.buys = append(m.buys, req)
+	return m.placed(req.Quantity), nil
+}
+
+func (m *mockLimitOrderCEXClient) Sell(ctx context.Context, req cex.SellOrderRequest) (*cex.OrderResult, error) {
+	m.sells = append(m.sells, req)
+	return m.placed(req.Quantity), nil
+}
+
+func (m *mockLimitOrderCEXClient) GetOpenOrders(ctx context.Context, pair cex.TradingPair) ([]*cex.OrderResult, error) {
+	return nil, nil
+}
+
+func (m *mockLimitOrderCEXClient) GetOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*cex.OrderResult, error) {
+	if result, ok := m.orders[orderID]; ok {
+		return result, nil
+	}
+	return &cex.OrderResult{OrderID: orderID, Status: cex.OrderStatusNew}, nil
+}
+
+func TestLiveOrderManager_LimitOrderPolledFills(t *testing.T) {
+	ctx := context.Background()
+	client := &mockLimitOrderCEXClient{orders: map[string]*cex.OrderResult{}}
+	manager := NewLiveOrderManager(client)
+
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_1", decimal.NewFromInt(50000))
+	order.TimeInForce = cex.TimeInForceGTC
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+	require.Len(t, client.buys, 1)
+	assert.Equal(t, cex.OrderTypeLimit, client.buys[0].Type)
+	assert.Equal(t, cex.TimeInForceGTC, client.buys[0].TimeInForce)
+	assert.Equal(t, "50000", client.buys[0].Price.String())
+	assert.Equal(t, 1, manager.GetOrderCount())
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// 部分成交：返回增量，剩余数量继续挂单
+	client.orders["ex_1"] = &cex.OrderResult{OrderID: "ex_1", Status: cex.OrderStatusPartiallyFilled, Quantity: decimal.NewFromFloat(0.4), Price: decimal.NewFromInt(50000)}
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, executor.OrderSideBuy, results[0].Side)
+	assert.Equal(t, "0.4", results[0].Quantity.String())
+	assert.Equal(t, "buy_1", results[0].ClientOrderID)
+	require.Equal(t, 1, manager.GetOrderCount())
+	assert.Equal(t, "0.6", manager.GetPendingOrders()[0].Quantity.String())
+
+	// 全部成交：只返回剩余部分，价格按累计成交额的增量计算（均价 49940 → (49940 - 20000) / 0.6）
+	client.orders["ex_1"] = &cex.OrderResult{OrderID: "ex_1", Status: cex.OrderStatusFilled, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(49940)}
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "0.6", results[0].Quantity.String())
+	assert.Equal(t, "49900", results[0].Price.String())
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestLiveOrderManager_LimitOrderPollAfterStream(t *testing.T) {
+	ctx := context.Background()
+	client := &mockLimitOrderCEXClient{orders: map[string]*cex.OrderResult{}}
+	manager := NewLiveOrderManager(client)
+	manager.setStreaming(true)
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell_1", decimal.NewFromInt(50000))))
+	manager.applyOrderUpdate(tradeUpdate("ex_1", cex.OrderStatusPartiallyFilled, 0.4, 50000), pair)
+
+	// 数据流断开后轮询：已推送的成交不再返回
+	manager.setStreaming(false)
+	client.orders["ex_1"] = &cex.OrderResult{OrderID: "ex_1", Status: cex.OrderStatusFilled, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50060)}
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "0.4", results[0].Quantity.String())
+	assert.Equal(t, "0.6", results[1].Quantity.String())
+	assert.Equal(t, "50100", results[1].Price.String())
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestLiveOrderManager_LimitOrderExpiresLocally(t *testing.T) {
+	ctx := context.Background()
+	client := &mockLimitOrderCEXClient{orders: map[string]*cex.OrderResult{}}
+	manager := NewLiveOrderManager(client)
+	bus := NewEventBus()
+	manager.SetEventBus(bus)
+	var cancelled []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { cancelled = append(cancelled, event) }, EventOrderCancelled)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expire := start.Add(time.Hour)
+	order := CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell_1", decimal.NewFromInt(51000))
+	order.TimeInForce = cex.TimeInForceGTD
+	order.ExpireTime = &expire
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+	require.Len(t, client.sells, 1)
+	assert.Equal(t, cex.TimeInForceGTD, client.sells[0].TimeInForce)
+
+	// 到期前保留
+	price := decimal.NewFromInt(50000)
+	_, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(expire, price, price, price, price))
+	require.NoError(t, err)
+	assert.Equal(t, 1, manager.GetOrderCount())
+	assert.Empty(t, client.cancelled)
+
+	// 开盘时间超过 ExpireTime 后在交易所撤单
+	_, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(expire.Add(time.Hour), price, price, price, price))
+	require.NoError(t, err)
+	assert.Equal(t, 0, manager.GetOrderCount())
+	assert.Equal(t, []string{"ex_1"}, client.cancelled)
+	require.Len(t, cancelled, 1)
+	assert.Equal(t, cex.OrderStatusExpired, cancelled[0].Message)
+}
+
+func TestLiveOrderManager_ImmediateLimitOrder(t *testing.T) {
+	ctx := context.Background()
+	client := &mockLimitOrderCEXClient{status: cex.OrderStatusExpired}
+	manager := NewLiveOrderManager(client)
+
+	// IOC 下单即结束：成交在下次检查挂单时返回一次，不跟踪
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "ioc", decimal.NewFromInt(50000))
+	order.TimeInForce = cex.TimeInForceIOC
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+	assert.Equal(t, cex.TimeInForceIOC, client.buys[0].TimeInForce)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "49990", results[0].Price.String())
+
+	results, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestLiveOrderManager_CancelLimitOrders(t *testing.T) {
+	ctx := context.Background()
+	client := &mockLimitOrderCEXClient{orders: map[string]*cex.OrderResult{}}
+	manager := NewLiveOrderManager(client)
+
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_1", decimal.NewFromInt(49000))))
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_2", decimal.NewFromInt(48000))))
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell_1", decimal.NewFromInt(52000))))
+
+	require.NoError(t, manager.CancelOrder(ctx, "buy_2"))
+	assert.Equal(t, []string{"ex_2"}, client.cancelled)
+
+	require.NoError(t, manager.CancelAllOrders(ctx))
+	assert.Equal(t, []string{"ex_2", "ex_1", "ex_3"}, client.cancelled)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestLiveOrderManager_LimitOrderNeedsCancel(t *testing.T) {
+	ctx := context.Background()
+	manager := NewLiveOrderManager(&MockCEXClient{})
+
+	// 不支持撤单时无法挂出需要过期撤销的限价单
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_1", decimal.NewFromInt(50000))
+	order.TimeInForce = cex.TimeInForceGTD
+	err := manager.PlaceOrder(ctx, order)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support cancelling orders")
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// defaultLimitOffset 信号限价单相对收盘价的默认偏移：买入低 0.1%，卖出高 0.1%
+const defaultLimitOffset = 0.001
+
+// isMarket 是否市价单
+func (o *PendingOrder) isMarket() bool {
+	return o.Type == PendingOrderTypeBuyMarket || o.Type == PendingOrderTypeSellMarket
+}
+
+// isQuoteOrder 是否按计价资产金额下的市价买单
+func (o *PendingOrder) isQuoteOrder() bool {
+	return o.Type == PendingOrderTypeBuyMarket && o.QuoteQuantity.IsPositive()
+}
+
+// signalOrderPrice 信号挂单的类型和价格：市价单以收盘价为参考价（用于计算数量和风控），
+// 限价单使用信号指定的限价，未指定时按偏移（默认 0.1%）计算，买入低于收盘价、卖出高于收盘价
+func signalOrderPrice(signal *strategy.Signal, close decimal.Decimal, side cex.OrderSide) (PendingOrderType, decimal.Decimal) {
+	if signal.OrderType == executor.OrderTypeMarket {
+		if side == cex.OrderSideBuy {
+			return PendingOrderTypeBuyMarket, close
+		}
+		return PendingOrderTypeSellMarket, close
+	}
+
+	if signal.LimitPrice.IsPositive() {
+		if side == cex.OrderSideBuy {
+			return PendingOrderTypeBuyLimit, signal.LimitPrice
+		}
+		return PendingOrderTypeSellLimit, signal.LimitPrice
+	}
+
+	offset := defaultLimitOffset
+	if signal.LimitOffset != nil {
+		offset = *signal.LimitOffset
+	}
+	if side == cex.OrderSideBuy {
+		return PendingOrderTypeBuyLimit, close.Mul(decimal.NewFromFloat(1 - offset))
+	}
+	return PendingOrderTypeSellLimit, close.Mul(decimal.NewFromFloat(1 + offset))
+}
+
+// placeMarketOrderLocked 向交易所下市价单，成交结果在下次检查挂单时返回（调用方需持有锁）
+func (m *LiveOrderManager) placeMarketOrderLocked(ctx context.Context, order *PendingOrder) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	var result *cex.OrderResult
+	var err error
+	if order.side() == cex.OrderSideBuy {
+		request := cex.BuyOrderRequest{TradingPair: order.TradingPair, Type: cex.OrderTypeMarket, Quantity: order.Quantity, QuoteQuantity: order.QuoteQuantity}
+		result, err = cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionBuy, order.TradingPair, request,
+			func() (*cex.OrderResult, error) {
+				return m.cexClient.Buy(ctx, request)
+			})
+	} else {
+		request := cex.SellOrderRequest{TradingPair: order.TradingPair, Type: cex.OrderTypeMarket, Quantity: order.Quantity}
+		result, err = cex.AuditOrder(ctx, m.auditor, m.cexClient.GetName(), cex.AuditActionSell, order.TradingPair, request,
+			func() (*cex.OrderResult, error) {
+				return m.cexClient.Sell(ctx, request)
+			})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to place market order %s: %w", order.ID, err)
+	}
+
+	fill := &executor.OrderResult{
+		OrderID:       result.OrderID,
+		ClientOrderID: order.ID,
+		TradingPair:   order.TradingPair,
+		Side:          executor.OrderSide(order.side()),
+		Quantity:      result.Quantity,
+		Price:         result.Price,
+		Timestamp:     result.TransactTime,
+		Success:       true,
+		Reason:        order.Reason,
+		Origin:        order.OriginSignal,
+		Signal:        order.Signal,
+	}
+	m.marketFills = append(m.marketFills, fill)
+
+	logger.Info(fmt.Sprintf("⚡ 实盘市价单成交: id=%s, exchange_id=%s, side=%s, qty=%s, price=%s, reference_price=%s",
+		order.ID, result.OrderID, order.side(), result.Quantity.String(), result.Price.String(), order.Price.String()))
+	return nil
+}
+
+// drainMarketFillsLocked 取出市价单成交结果（调用方需持有锁）
+func (m *LiveOrderManager) drainMarketFillsLocked() []*executor.OrderResult {
+	fills := m.marketFills
+	m.marketFills = nil
+	return fills
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignalOrderPrice(t *testing.T) {
+	close := decimal.NewFromInt(100)
+
+	orderType, price := signalOrderPrice(&strategy.Signal{Type: "BUY"}, close, cex.OrderSideBuy)
+	assert.Equal(t, PendingOrderTypeBuyLimit, orderType)
+	assert.Equal(t, "99.9", price.String())
+
+	offset := 0.005
+	orderType, price = signalOrderPrice(&strategy.Signal{Type: "SELL", LimitOffset: &offset}, close, cex.OrderSideSell)
+	assert.Equal(t, PendingOrderTypeSellLimit, orderType)
+	assert.Equal(t, "100.5", price.String())
+
+	// 偏移为 0 时按收盘价挂单
+	zero := 0.0
+	_, price = signalOrderPrice(&strategy.Signal{Type: "BUY", LimitOffset: &zero}, close, cex.OrderSideBuy)
+	assert.Equal(t, "100", price.String())
+
+	orderType, price = signalOrderPrice(&strategy.Signal{Type: "BUY", OrderType: executor.OrderTypeMarket}, close, cex.OrderSideBuy)
+	assert.Equal(t, PendingOrderTypeBuyMarket, orderType)
+	assert.Equal(t, "100", price.String())
+	orderType, _ = signalOrderPrice(&strategy.Signal{Type: "SELL", OrderType: executor.OrderTypeMarket}, close, cex.OrderSideSell)
+	assert.Equal(t, PendingOrderTypeSellMarket, orderType)
+}
+
+func TestBacktestOrderManager_MarketOrdersFillAtNextOpen(t *testing.T) {
+	ctx := context.Background()
+	mockExec := newMockOrderExecutor(decimal.NewFromInt(1000000), decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExec)
+	manager.SetFillModel(NewFixedSlippageFillModel(10))
+
+	buy := CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_1", decimal.NewFromInt(50000))
+	sell := CreateTestPendingOrder(PendingOrderTypeSellMarket, "sell_1", decimal.NewFromInt(50000))
+	require.NoError(t, manager.PlaceOrder(ctx, buy))
+	require.NoError(t, manager.PlaceOrder(ctx, sell))
+
+	// 开盘价 51000，买入向上、卖出向下滑点 10bps
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(51000), decimal.NewFromInt(52000), decimal.NewFromInt(50000), decimal.NewFromInt(51500))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	require.Len(t, mockExec.buyResults, 1)
+	assert.Equal(t, "51051", mockExec.buyResults[0].Price.String())
+	require.Len(t, mockExec.sellResults, 1)
+	assert.Equal(t, "50949", mockExec.sellResults[0].Price.String())
+
+	// 执行失败的市价单不保留
+	mockExec.shouldFailBuy = true
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_2", decimal.NewFromInt(50000))))
+	_, err = manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestBacktestOrderManager_QuoteMarketOrder(t *testing.T) {
+	ctx := context.Background()
+	mockExec := newMockOrderExecutor(decimal.NewFromInt(1000), decimal.Zero)
+	manager := NewBacktestOrderManager(mockExec)
+	manager.SetFillModel(NewFixedSlippageFillModel(10))
+
+	// 按 500 USDT 买入，下单时按参考价估算数量
+	buy := CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_1", decimal.RequireFromString("0.00001"))
+	buy.Quantity = decimal.NewFromInt(50000000)
+	buy.QuoteQuantity = decimal.NewFromInt(500)
+	require.NoError(t, manager.PlaceOrder(ctx, buy))
+
+	// 开盘价 0.0000125，滑点 10bps 后 0.0000125125，成交数量按成交价计算
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.RequireFromString("0.0000125"), decimal.RequireFromString("0.000013"),
+		decimal.RequireFromString("0.000012"), decimal.RequireFromString("0.0000126"))
+	results, err := manager.CheckAndExecuteOrders(ctx, kline)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	fill := mockExec.buyResults[0]
+	assert.Equal(t, "0.0000125125", fill.Price.String())
+	assert.True(t, fill.Quantity.Mul(fill.Price).Sub(decimal.NewFromInt(500)).Abs().LessThan(decimal.RequireFromString("0.000001")))
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+// mockMarketOrderCEXClient 记录市价单请求的CEX客户端mock
+type mockMarketOrderCEXClient struct {
+	MockCEXClient
+	buys []cex.BuyOrderRequest
+}
+
+func (m *mockMarketOrderCEXClient) Buy(ctx context.Context, req cex.BuyOrderRequest) (*cex.OrderResult, error) {
+	m.buys = append(m.buys, req)
+	return &cex.OrderResult{OrderID: "ex_1", Price: decimal.NewFromInt(50100), Quantity: req.Quantity,
+		Status: cex.OrderStatusFilled, TransactTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func TestLiveOrderManager_MarketOrder(t *testing.T) {
+	ctx := context.Background()
+	client := &mockMarketOrderCEXClient{}
+	manager := NewLiveOrderManager(client)
+
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_1", decimal.NewFromInt(50000))))
+	require.Len(t, client.buys, 1)
+	assert.Equal(t, cex.OrderTypeMarket, client.buys[0].Type)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	// 成交在下次检查挂单时返回，只返回一次
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000), decimal.NewFromInt(50000))
+	results, _ := manager.CheckAndExecuteOrders(ctx, kline)
+	require.Len(t, results, 1)
+	assert.Equal(t, executor.OrderSideBuy, results[0].Side)
+	assert.Equal(t, "50100", results[0].Price.String())
+	assert.Equal(t, "ex_1", results[0].OrderID)
+
+	results, _ = manager.CheckAndExecuteOrders(ctx, kline)
+	assert.Empty(t, results)
+}
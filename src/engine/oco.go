@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OriginOCO OCO 挂单的来源标记
+const OriginOCO = "OCO"
+
+// ocoStopLimitSlippage 实盘 OCO 止损腿的限价相对触发价的让价比例，保证触发后能成交
+const ocoStopLimitSlippage = 0.005
+
+// OCOOrderManager 支持整组下 OCO 挂单的挂单管理器
+type OCOOrderManager interface {
+	// PlaceOCOOrder 同时下止盈限价单和止损单，两者 GroupID 相同，一个成交后撤销另一个
+	PlaceOCOOrder(ctx context.Context, takeProfit, stopLoss *PendingOrder) error
+}
+
+// BuildOCOOrders 根据开仓价生成 OCO 止盈/止损挂单
+func BuildOCOOrders(pair cex.TradingPair, entryPrice, quantity decimal.Decimal, takeProfitPercent, stopLossPercent float64, createTime time.Time) (*PendingOrder, *PendingOrder) {
+	groupID := generateShortOrderID("oco", pair.Base)
+
+	takeProfit := &PendingOrder{
+		ID:           groupID + "-tp",
+		Type:         PendingOrderTypeSellLimit,
+		TradingPair:  pair,
+		Quantity:     quantity,
+		Price:        entryPrice.Mul(decimal.NewFromFloat(1 + takeProfitPercent)),
+		CreateTime:   createTime,
+		Reason:       fmt.Sprintf("OCO take profit: +%.1f%%", takeProfitPercent*100),
+		OriginSignal: OriginOCO,
+		GroupID:      groupID,
+	}
+	stopLoss := &PendingOrder{
+		ID:           groupID + "-sl",
+		Type:         PendingOrderTypeStopLoss,
+		TradingPair:  pair,
+		Quantity:     quantity,
+		Price:        entryPrice.Mul(decimal.NewFromFloat(1 - stopLossPercent)),
+		CreateTime:   createTime,
+		Reason:       fmt.Sprintf("OCO stop loss: -%.1f%%", stopLossPercent*100),
+		OriginSignal: OriginOCO,
+		GroupID:      groupID,
+	}
+	return takeProfit, stopLoss
+}
+
+// PlaceOCOOrder 回测：两腿作为同组挂单，由 CheckAndExecuteOrders 在一腿成交后撤销另一腿
+func (m *BacktestOrderManager) PlaceOCOOrder(ctx context.Context, takeProfit, stopLoss *PendingOrder) error {
+	if takeProfit.GroupID == "" || takeProfit.GroupID != stopLoss.GroupID {
+		return fmt.Errorf("OCO orders must share a group id")
+	}
+	if err := m.PlaceOrder(ctx, takeProfit); err != nil {
+		return err
+	}
+	return m.PlaceOrder(ctx, stopLoss)
+}
+
+// PlaceOCOOrder 实盘：使用交易所 OCO 接口下单，交易所负责撤销另一腿
+func (m *LiveOrderManager) PlaceOCOOrder(ctx context.Context, takeProfit, stopLoss *PendingOrder) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	if takeProfit.GroupID == "" || takeProfit.GroupID != stopLoss.GroupID {
+		return fmt.Errorf("OCO orders must share a group id")
+	}
+
+	client, ok := m.cexClient.(cex.OCOOrderClient)
+	if !ok {
+		return fmt.Errorf("%s does not support OCO orders", m.cexClient.GetName())
+	}
+	if err := cex.CheckTradingEnabled(m.cexClient); err != nil {
+		logger.Error(fmt.Sprintf("🚫 交易未启用，拒绝 OCO 挂单: group=%s, error=%v", takeProfit.GroupID, err))
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// OCO 在交易所计为两个挂单
+	symbol := takeProfit.TradingPair.String()
+	openCount := m.countOpenOrdersLocked(symbol) + 1
+	if m.limits.exceedsHard(openCount) {
+		return fmt.Errorf("%w: %s has %d open orders (hard limit %d)", ErrOpenOrderLimitExceeded, symbol, openCount-1, m.limits.HardLimit)
+	}
+
+	stopLimitPrice := stopLoss.Price.Mul(decimal.NewFromFloat(1 - ocoStopLimitSlippage))
+	stopLimitPrice = m.symbolFilters.RoundPrice(stopLoss.TradingPair, stopLimitPrice, cex.OrderSideSell)
+	result, err := m.placeOCOSell(ctx, client, takeProfit.TradingPair, takeProfit.Quantity, takeProfit.Price, stopLoss.Price, stopLimitPrice)
+	if err != nil {
+		return fmt.Errorf("failed to place OCO order: %w", err)
+	}
+
+	m.pendingOrders[takeProfit.ID] = takeProfit
+	m.pendingOrders[stopLoss.ID] = stopLoss
+	m.ocoListIDs[takeProfit.GroupID] = result.OrderListID
+
+	logger.Info(fmt.Sprintf("🔗 实盘 OCO 已挂出: group=%s, list_id=%s, take_profit=%s, stop=%s, stop_limit=%s",
+		takeProfit.GroupID, result.OrderListID, takeProfit.Price.String(), stopLoss.Price.String(), stopLimitPrice.String()))
+	return nil
+}
+
+// cancelOCOLocked 撤销交易所 OCO 订单组并移除两腿（调用方需持有锁）
+func (m *LiveOrderManager) cancelOCOLocked(ctx context.Context, groupID string) error {
+	var pair cex.TradingPair
+	for id, order := range m.pendingOrders {
+		if order.GroupID == groupID {
+			pair = order.TradingPair
+			delete(m.pendingOrders, id)
+		}
+	}
+
+	listID := m.ocoListIDs[groupID]
+	delete(m.ocoListIDs, groupID)
+
+	if client, ok := m.cexClient.(cex.OCOOrderClient); ok {
+		if err := m.cancelExchangeOCO(ctx, client, pair, listID); err != nil {
+			return fmt.Errorf("failed to cancel OCO group %s: %w", groupID, err)
+		}
+	}
+	return nil
+}
+
+// syncOCO 维护 OCO 挂单：开仓成交后挂出止盈/止损，清仓后撤销剩余挂单
+func (e *TradingEngine) syncOCO(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	provider, ok := e.strategy.(strategy.OCOProvider)
+	if !ok {
+		return nil
+	}
+	takeProfitPercent, stopLossPercent := provider.GetOCOPercents()
+	if takeProfitPercent <= 0 || stopLossPercent <= 0 {
+		return nil
+	}
+
+	ctx, logger := log.WithCtx(ctx)
+
+	var entry *executor.OrderResult
+	for _, result := range executed {
+		if result != nil && result.Success && result.Side == executor.OrderSideBuy {
+			entry = result
+		}
+	}
+
+	// 新开仓或已清仓时，旧的 OCO 挂单都不再有效（撤销一腿即撤销整组）
+	if entry != nil || portfolio.Position.IsZero() {
+		cancelled := make(map[string]bool)
+		for _, order := range e.orderManager.GetPendingOrders() {
+			if order.OriginSignal != OriginOCO || cancelled[order.GroupID] {
+				continue
+			}
+			cancelled[order.GroupID] = true
+			if err := e.orderManager.CancelOrder(ctx, order.ID); err != nil {
+				logger.Error("取消OCO挂单失败", "id", order.ID, "error", err)
+			}
+		}
+	}
+
+	if entry == nil || portfolio.Position.IsZero() {
+		return nil
+	}
+
+	// 策略启用 ATR 止损时按开仓价 ± N×ATR 挂单
+	levels := "fixed"
+	if atrTakeProfit, atrStopLoss, ok := e.atrStopPercents(entry.Price); ok {
+		takeProfitPercent, stopLossPercent = atrTakeProfit, atrStopLoss
+		levels = "ATR"
+	}
+
+	takeProfit, stopLoss := BuildOCOOrders(e.tradingPair, entry.Price, portfolio.Position, takeProfitPercent, stopLossPercent, kline.OpenTime)
+	logger.Info(fmt.Sprintf("🔗 挂出OCO: entry=%s, position=%s, take_profit=%s, stop_loss=%s, levels=%s",
+		entry.Price.String(), portfolio.Position.String(), takeProfit.Price.String(), stopLoss.Price.String(), levels))
+
+	if !e.normalizeOrder(ctx, takeProfit) || !e.normalizeOrder(ctx, stopLoss) || !e.checkRisk(ctx, takeProfit) {
+		return nil
+	}
+
+	if manager, ok := e.orderManager.(OCOOrderManager); ok {
+		if err := manager.PlaceOCOOrder(ctx, takeProfit, stopLoss); err != nil {
+			return fmt.Errorf("挂出OCO失败: %w", err)
+		}
+		for _, order := range []*PendingOrder{takeProfit, stopLoss} {
+			e.events.Publish(ctx, &Event{Type: EventOrderPlaced, Time: order.CreateTime, TradingPair: order.TradingPair, Order: order})
+		}
+		return nil
+	}
+
+	return fmt.Errorf("挂出OCO失败: order manager does not support OCO orders")
+}
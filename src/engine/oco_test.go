@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ocoTestStrategy 第一根K线买入，离场交给 OCO 挂单
+type ocoTestStrategy struct {
+	onDataCalls int
+}
+
+func (s *ocoTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.onDataCalls++
+	if s.onDataCalls == 1 {
+		return []*strategy.Signal{{Type: "BUY", Strength: 0.8, Reason: "oco test buy"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *ocoTestStrategy) GetName() string                                { return "OCOTestStrategy" }
+func (s *ocoTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *ocoTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *ocoTestStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *ocoTestStrategy) Load(data []byte) error                         { return nil }
+func (s *ocoTestStrategy) GetOCOPercents() (float64, float64)             { return 0.2, 0.1 }
+
+// atrOCOTestStrategy OCO 止盈止损按 ATR 设置
+type atrOCOTestStrategy struct {
+	ocoTestStrategy
+}
+
+func (s *atrOCOTestStrategy) GetATRStops() (int, float64, float64) { return 1, 3, 2 }
+
+// mockOCOCEXClient 支持 OCO 订单的CEX客户端mock
+type mockOCOCEXClient struct {
+	MockCEXClient
+	placed    int
+	cancelled []string
+}
+
+func (m *mockOCOCEXClient) PlaceOCOSellOrder(ctx context.Context, pair cex.TradingPair, quantity, takeProfitPrice, stopPrice, stopLimitPrice decimal.Decimal) (*cex.OCOOrderResult, error) {
+	m.placed++
+	return &cex.OCOOrderResult{OrderListID: "42"}, nil
+}
+
+func (m *mockOCOCEXClient) CancelOCOOrder(ctx context.Context, pair cex.TradingPair, orderListID string) error {
+	m.cancelled = append(m.cancelled, orderListID)
+	return nil
+}
+
+var testOCOPair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+func TestBuildOCOOrders(t *testing.T) {
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, decimal.NewFromInt(100), decimal.NewFromInt(2), 0.2, 0.1, time.Now())
+
+	assert.Equal(t, PendingOrderTypeSellLimit, takeProfit.Type)
+	assert.Equal(t, PendingOrderTypeStopLoss, stopLoss.Type)
+	assert.True(t, takeProfit.Price.Equal(decimal.NewFromInt(120)))
+	assert.True(t, stopLoss.Price.Equal(decimal.NewFromInt(90)))
+	assert.NotEmpty(t, takeProfit.GroupID)
+	assert.Equal(t, takeProfit.GroupID, stopLoss.GroupID)
+	assert.NotEqual(t, takeProfit.ID, stopLoss.ID)
+}
+
+func TestBacktestOrderManager_OCO_TakeProfitCancelsStop(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExecutor)
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, price(100), decimal.NewFromInt(1), 0.2, 0.1, time.Now())
+	require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+	assert.Equal(t, 2, manager.GetOrderCount())
+
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(time.Now(), price(110), price(125), price(105), price(122)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Price.Equal(price(120)))
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestBacktestOrderManager_OCO_BothTouchedStopWins(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExecutor)
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, price(100), decimal.NewFromInt(1), 0.2, 0.1, time.Now())
+	require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+
+	// 同一根K线同时触及止盈和止损，保守按止损成交
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(time.Now(), price(100), price(125), price(85), price(100)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, stopLoss.ID, results[0].OrderID)
+	assert.True(t, results[0].Price.Equal(price(90)))
+	assert.Equal(t, 1, mockExecutor.sellCallCount)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestBacktestOrderManager_OCO_CancelOneLegCancelsGroup(t *testing.T) {
+	manager := NewBacktestOrderManager(newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1)))
+	ctx := context.Background()
+
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, decimal.NewFromInt(100), decimal.NewFromInt(1), 0.2, 0.1, time.Now())
+	require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_other", decimal.NewFromInt(90))))
+
+	require.NoError(t, manager.CancelOrder(ctx, stopLoss.ID))
+	require.Equal(t, 1, manager.GetOrderCount())
+	assert.Equal(t, "buy_other", manager.GetPendingOrders()[0].ID)
+}
+
+func TestTradingEngine_Run_OCO(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(101), price(99.5), price(100)),                // 买入信号
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(99), price(100)), // 买单成交，挂出OCO
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(100), price(102), price(80), price(85)),  // 止损成交，止盈撤销
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(85), price(150), price(84), price(140)), // 已无止盈挂单
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(&ocoTestStrategy{}, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	assert.Equal(t, 1, mockExecutor.buyCallCount)
+	require.Len(t, mockExecutor.sellResults, 1)
+	entryPrice := mockExecutor.buyResults[0].Price
+	assert.True(t, mockExecutor.sellResults[0].Price.Equal(entryPrice.Mul(price(0.9))))
+	assert.True(t, mockExecutor.position.IsZero())
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+}
+
+func TestTradingEngine_Run_OCO_ATRLevels(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(101), price(99.5), price(100)),                  // 买入信号
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(99), price(100)),   // 买单成交，ATR(1) = 2
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(100), price(100.5), price(95), price(96)),  // 跌破 entry - 2×ATR
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(96), price(120), price(95.5), price(118)), // 已无止盈挂单
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(&atrOCOTestStrategy{}, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	require.Len(t, mockExecutor.buyResults, 1)
+	require.Len(t, mockExecutor.sellResults, 1)
+	entryPrice := mockExecutor.buyResults[0].Price.InexactFloat64()
+	// 固定比例止损为 -10%，ATR 止损为 entry - 4
+	assert.InDelta(t, entryPrice-4, mockExecutor.sellResults[0].Price.InexactFloat64(), 1e-6)
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+}
+
+func TestLiveOrderManager_PlaceOCOOrder(t *testing.T) {
+	client := &mockOCOCEXClient{}
+	manager := NewLiveOrderManager(client)
+	ctx := context.Background()
+
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, decimal.NewFromInt(100), decimal.NewFromInt(1), 0.2, 0.1, time.Now())
+	require.NoError(t, manager.PlaceOCOOrder(ctx, takeProfit, stopLoss))
+	assert.Equal(t, 1, client.placed)
+	assert.Equal(t, 2, manager.GetOrderCount())
+
+	// 撤销一腿即撤销交易所订单组
+	require.NoError(t, manager.CancelOrder(ctx, takeProfit.ID))
+	assert.Equal(t, []string{"42"}, client.cancelled)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestLiveOrderManager_PlaceOCOOrder_Unsupported(t *testing.T) {
+	manager := NewLiveOrderManager(&MockCEXClient{})
+
+	takeProfit, stopLoss := BuildOCOOrders(testOCOPair, decimal.NewFromInt(100), decimal.NewFromInt(1), 0.2, 0.1, time.Now())
+	err := manager.PlaceOCOOrder(context.Background(), takeProfit, stopLoss)
+	assert.Error(t, err)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OrderChasePolicy 未成交买入挂单的追价策略：连续 AfterBars 根K线未成交后撤单，按当前收盘价重新挂单（或改为市价），
+// 相对首次挂单价的追价幅度不超过 MaxDistance
+type OrderChasePolicy struct {
+	AfterBars   int     `json:"after_bars"`   // 挂单连续未成交的K线数，达到后追价（0 表示不追价）
+	MaxDistance float64 `json:"max_distance"` // 相对首次挂单价的最大追价幅度（如 0.01 = 1%）
+	MaxChases   int     `json:"max_chases"`   // 最多追价次数（0 表示不限制，直到达到 MaxDistance）
+	Market      bool    `json:"market"`       // 改为市价：按最大追价价格挂单，下一根K线开盘价不高于该价格时按开盘价成交
+}
+
+// Enabled 是否启用追价
+func (p OrderChasePolicy) Enabled() bool {
+	return p.AfterBars > 0 && p.MaxDistance > 0
+}
+
+// Validate 检查追价参数
+func (p OrderChasePolicy) Validate() error {
+	if p.AfterBars < 0 || p.MaxChases < 0 {
+		return fmt.Errorf("AfterBars and MaxChases cannot be negative, got %d and %d", p.AfterBars, p.MaxChases)
+	}
+	if p.MaxDistance < 0 || p.MaxDistance >= 1 {
+		return fmt.Errorf("MaxDistance must be in [0, 1), got %v", p.MaxDistance)
+	}
+	if p.AfterBars > 0 && p.MaxDistance == 0 {
+		return fmt.Errorf("MaxDistance is required when AfterBars is set")
+	}
+	return nil
+}
+
+// chasePrice 追价后的挂单价：当前收盘价（市价模式为最大追价价格），不超过首次挂单价 × (1 + MaxDistance)
+func (p OrderChasePolicy) chasePrice(anchor, close decimal.Decimal) decimal.Decimal {
+	limit := anchor.Mul(decimal.NewFromFloat(1 + p.MaxDistance))
+	if p.Market || close.GreaterThan(limit) {
+		return limit
+	}
+	return close
+}
+
+// SetOrderChasePolicy 设置未成交买入挂单的追价策略
+func (e *TradingEngine) SetOrderChasePolicy(policy OrderChasePolicy) {
+	e.chasePolicy = policy
+}
+
+// chaseUnfilledOrders 信号买入挂单连续未成交时撤单并按更接近市场的价格重新挂单，买入金额不变
+func (e *TradingEngine) chaseUnfilledOrders(ctx context.Context, kline *cex.KlineData) {
+	if !e.chasePolicy.Enabled() || e.signalOnly {
+		return
+	}
+	if allowed, _ := e.calendar.IsTradingAllowed(e.orderTime(kline)); !allowed {
+		return
+	}
+
+	ctx, logger := log.WithCtx(ctx)
+	interval := e.getTimeframeInterval()
+
+	for _, order := range e.orderManager.GetPendingOrders() {
+		// 只追信号生成的买入挂单；部分成交的挂单保留剩余数量，不再追价
+		if order.Type != PendingOrderTypeBuyLimit || order.OriginSignal != "BUY" || order.TimeInForce.Immediate() || order.filled != nil {
+			continue
+		}
+		if int(kline.OpenTime.Sub(order.CreateTime)/interval) < e.chasePolicy.AfterBars {
+			continue
+		}
+		if e.chasePolicy.MaxChases > 0 && order.ChaseCount >= e.chasePolicy.MaxChases {
+			continue
+		}
+
+		anchor := order.ChaseAnchor
+		if anchor.IsZero() {
+			anchor = order.Price
+		}
+		price := e.chasePolicy.chasePrice(anchor, kline.Close)
+		if !price.GreaterThan(order.Price) {
+			continue // 已追到上限
+		}
+
+		if err := e.orderManager.CancelOrder(ctx, order.ID); err != nil {
+			logger.Error(fmt.Sprintf("❌ 追价撤单失败: id=%s, error=%v", order.ID, err))
+			continue
+		}
+
+		chased := *order
+		chased.ID = generateShortOrderID("chase", e.tradingPair.Base)
+		chased.Quantity = order.Quantity.Mul(order.Price).Div(price)
+		chased.Price = price
+		chased.CreateTime = kline.OpenTime
+		chased.ChaseCount = order.ChaseCount + 1
+		chased.ChaseAnchor = anchor
+
+		logger.Info(fmt.Sprintf("🏃 追价重新挂单: old_id=%s, new_id=%s, old_price=%s, new_price=%s, anchor=%s, chase=%d",
+			order.ID, chased.ID, order.Price.String(), price.String(), anchor.String(), chased.ChaseCount))
+		if err := e.placeOrder(ctx, &chased); err != nil {
+			logger.Error(fmt.Sprintf("❌ 追价挂单失败: id=%s, error=%v", chased.ID, err))
+		}
+	}
+}
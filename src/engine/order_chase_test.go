@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chaseTestStrategy 第一根K线买入，之后不再发出信号
+type chaseTestStrategy struct {
+	onDataCalls int
+}
+
+func (s *chaseTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.onDataCalls++
+	if s.onDataCalls == 1 {
+		return []*strategy.Signal{{Type: "BUY", Strength: 0.8, Reason: "chase test buy"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *chaseTestStrategy) GetName() string                                { return "ChaseTestStrategy" }
+func (s *chaseTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *chaseTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *chaseTestStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *chaseTestStrategy) Load(data []byte) error                         { return nil }
+
+func TestTradingEngine_Run_ChasesUnfilledEntry(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(100), price(100), price(100)),                       // 买入信号，限价 99.9
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(100.5), price(101)),    // 未成交，追价到收盘价 101
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(101), price(103), price(101.5), price(103)),    // 未成交，追到上限 99.9 × 1.02 = 101.898
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(103), price(104), price(102), price(103)),     // 未成交，已到上限不再追价
+		CreateTestKlineWithPrices(startTime.Add(16*time.Hour), price(102), price(102), price(101.5), price(101.5)), // 按上限价成交
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(&chaseTestStrategy{}, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+	engine.SetOrderChasePolicy(OrderChasePolicy{AfterBars: 1, MaxDistance: 0.02})
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	require.Equal(t, 1, mockExecutor.buyCallCount)
+	require.Len(t, mockExecutor.buyResults, 1)
+	assert.Equal(t, "101.898", mockExecutor.buyResults[0].Price.String())
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+
+	// 追价后买入金额不变
+	initial := decimal.NewFromInt(10000).Mul(engine.positionSizePercent)
+	assert.InDelta(t, initial.InexactFloat64(), mockExecutor.buyResults[0].Price.Mul(mockExecutor.buyResults[0].Quantity).InexactFloat64(), 1e-6)
+}
+
+func TestOrderChasePolicy(t *testing.T) {
+	assert.False(t, OrderChasePolicy{}.Enabled())
+	assert.NoError(t, OrderChasePolicy{}.Validate())
+	assert.Error(t, OrderChasePolicy{AfterBars: 2}.Validate())
+	assert.Error(t, OrderChasePolicy{AfterBars: 2, MaxDistance: 1}.Validate())
+
+	policy := OrderChasePolicy{AfterBars: 2, MaxDistance: 0.01}
+	assert.True(t, policy.Enabled())
+	assert.NoError(t, policy.Validate())
+	assert.Equal(t, "100.5", policy.chasePrice(decimal.NewFromInt(100), decimal.NewFromFloat(100.5)).String())
+	assert.Equal(t, "101", policy.chasePrice(decimal.NewFromInt(100), decimal.NewFromInt(105)).String())
+
+	// 市价模式直接按上限价挂单
+	policy.Market = true
+	assert.Equal(t, "101", policy.chasePrice(decimal.NewFromInt(100), decimal.NewFromFloat(100.2)).String())
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrOpenOrderLimitExceeded 挂单数量达到硬上限
+var ErrOpenOrderLimitExceeded = errors.New("open order limit exceeded")
+
+// ErrMaxNotionalExceeded 开仓挂单金额超过单笔上限
+var ErrMaxNotionalExceeded = errors.New("max order notional exceeded")
+
+// OpenOrderLimits 单个交易对的挂单数量限制（0 表示不限制）
+// 交易所对每个交易对的挂单数量有上限（如 Binance 为 200），网格/阶梯挂单容易触及
+type OpenOrderLimits struct {
+	SoftLimit int // 软上限：达到后仍下单，但输出警告
+	HardLimit int // 硬上限：达到后拒绝新挂单
+}
+
+// Validate 验证限制配置
+func (l OpenOrderLimits) Validate() error {
+	if l.SoftLimit < 0 || l.HardLimit < 0 {
+		return fmt.Errorf("open order limits must be non-negative, got soft=%d hard=%d", l.SoftLimit, l.HardLimit)
+	}
+	if l.SoftLimit > 0 && l.HardLimit > 0 && l.SoftLimit > l.HardLimit {
+		return fmt.Errorf("open order soft limit %d exceeds hard limit %d", l.SoftLimit, l.HardLimit)
+	}
+	return nil
+}
+
+// exceedsHard 再下一单是否超过硬上限
+func (l OpenOrderLimits) exceedsHard(current int) bool {
+	return l.HardLimit > 0 && current >= l.HardLimit
+}
+
+// reachesSoft 再下一单后是否达到软上限
+func (l OpenOrderLimits) reachesSoft(current int) bool {
+	return l.SoftLimit > 0 && current+1 >= l.SoftLimit
+}
+
+// OpenOrderCounter 可按交易对统计挂单数量的挂单管理器
+type OpenOrderCounter interface {
+	// GetOpenOrderCounts 获取每个交易对的挂单数量
+	GetOpenOrderCounts() map[string]int
+}
+
+// notional 挂单金额（计价资产）：按金额下的市价买单为下单金额，其他为数量 × 价格
+func (o *PendingOrder) notional() decimal.Decimal {
+	if o.isQuoteOrder() {
+		return o.QuoteQuantity
+	}
+	return o.Quantity.Mul(o.Price)
+}
+
+// exceedsMaxNotional 开仓挂单金额是否超过单笔上限（maxNotional 为 0 时不限制，卖单不受限制以免无法平仓）
+func (o *PendingOrder) exceedsMaxNotional(maxNotional decimal.Decimal) bool {
+	return maxNotional.IsPositive() && o.side() == cex.OrderSideBuy && o.notional().GreaterThan(maxNotional)
+}
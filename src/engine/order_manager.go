@@ -2,7 +2,9 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,8 +19,12 @@ import (
 type PendingOrderType string
 
 const (
-	PendingOrderTypeBuyLimit  PendingOrderType = "BUY_LIMIT"
-	PendingOrderTypeSellLimit PendingOrderType = "SELL_LIMIT"
+	PendingOrderTypeBuyLimit     PendingOrderType = "BUY_LIMIT"
+	PendingOrderTypeSellLimit    PendingOrderType = "SELL_LIMIT"
+	PendingOrderTypeTrailingStop PendingOrderType = "TRAILING_STOP" // 移动止损卖单，Price 为当前触发价
+	PendingOrderTypeStopLoss     PendingOrderType = "STOP_LOSS"     // 止损卖单，Price 为触发价
+	PendingOrderTypeBuyMarket    PendingOrderType = "BUY_MARKET"    // 市价买单，Price 为下单时的参考价
+	PendingOrderTypeSellMarket   PendingOrderType = "SELL_MARKET"   // 市价卖单，Price 为下单时的参考价
 )
 
 // PendingOrder 挂单
@@ -27,11 +33,32 @@ type PendingOrder struct {
 	Type         PendingOrderType `json:"type"`
 	TradingPair  cex.TradingPair  `json:"trading_pair"`
 	Quantity     decimal.Decimal  `json:"quantity"`
-	Price        decimal.Decimal  `json:"price"`         // 挂单价格
-	CreateTime   time.Time        `json:"create_time"`   // 挂单时间
-	ExpireTime   *time.Time       `json:"expire_time"`   // 过期时间（可选）
-	Reason       string           `json:"reason"`        // 挂单原因
-	OriginSignal string           `json:"origin_signal"` // 原始信号类型
+	Price        decimal.Decimal  `json:"price"`                   // 挂单价格
+	CreateTime   time.Time        `json:"create_time"`             // 挂单时间
+	ExpireTime   *time.Time       `json:"expire_time"`             // 过期时间（可选）
+	Reason       string           `json:"reason"`                  // 挂单原因
+	OriginSignal string           `json:"origin_signal"`           // 原始信号类型
+	GroupID      string           `json:"group_id,omitempty"`      // OCO 组ID：同组挂单一个成交后撤销其余
+	TimeInForce  cex.TimeInForce  `json:"time_in_force,omitempty"` // 有效方式：IOC/FOK 只在下单后第一根K线撮合，GTD 到 ExpireTime 撤销
+
+	// 市价买单按计价资产金额下单（如 500 USDT）：成交数量按实际成交价计算，Quantity 为按参考价估算的数量（用于风控和下单规则检查）
+	QuoteQuantity decimal.Decimal `json:"quote_quantity"`
+
+	// 信号指定的止损/止盈价：开仓挂单成交后由引擎挂出保护单
+	StopLossPrice   decimal.Decimal `json:"stop_loss_price"`
+	TakeProfitPrice decimal.Decimal `json:"take_profit_price"`
+
+	// 追价：撤单重挂的次数和首次挂单价（用于限制最大追价幅度）
+	ChaseCount  int             `json:"chase_count,omitempty"`
+	ChaseAnchor decimal.Decimal `json:"chase_anchor"`
+
+	// 移动止损单：触发价 = 最高价 × (1 - TrailingPercent)，随新高上移
+	TrailingPercent float64         `json:"trailing_percent,omitempty"`
+	HighWaterMark   decimal.Decimal `json:"high_water_mark"`
+
+	Signal *executor.SignalContext `json:"signal,omitempty"` // 策略信号挂单的信号上下文，成交后带到 OrderResult
+
+	filled *executor.OrderResult // 回测分批成交的累计结果
 }
 
 // OrderManager 挂单管理器接口
@@ -61,6 +88,9 @@ type BacktestOrderManager struct {
 	pendingOrders map[string]*PendingOrder
 	mu            sync.RWMutex
 	currentTime   time.Time
+	calendar      *TradingCalendar // 禁止交易时段内不模拟成交
+	fillModel     FillModel        // 成交模型（为空时触价即按挂单价成交）
+	intrabar      IntrabarSource   // K线内细粒度行情（为空时按整根K线撮合）
 }
 
 // NewBacktestOrderManager 创建回测挂单管理器
@@ -72,14 +102,28 @@ func NewBacktestOrderManager(executor executor.Executor) *BacktestOrderManager {
 	}
 }
 
+// SetTradingCalendar 设置交易日历
+func (m *BacktestOrderManager) SetTradingCalendar(calendar *TradingCalendar) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calendar = calendar
+}
+
+// SetFillModel 设置成交模型（流动性、滑点模拟）
+func (m *BacktestOrderManager) SetFillModel(model FillModel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fillModel = model
+}
+
 func (m *BacktestOrderManager) PlaceOrder(ctx context.Context, order *PendingOrder) error {
 	ctx, logger := log.WithCtx(ctx)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	logger.Info(fmt.Sprintf("📋 挂单: %s %s @ %s", 
-		order.Type, order.Quantity.String(), order.Price.String()))
+	logger.Info(fmt.Sprintf("📋 挂单: %s %s @ %s order_id=%s, symbol=%s, signal_reason=%q", 
+		order.Type, order.Quantity.String(), order.Price.String(), order.ID, order.TradingPair.String(), order.Reason))
 
 	m.pendingOrders[order.ID] = order
 	return nil
@@ -91,9 +135,19 @@ func (m *BacktestOrderManager) CancelOrder(ctx context.Context, orderID string)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.pendingOrders[orderID]; exists {
+	if order, exists := m.pendingOrders[orderID]; exists {
 		delete(m.pendingOrders, orderID)
 		logger.Info(fmt.Sprintf("取消挂单: id=%s", orderID))
+
+		// OCO：撤销一腿即撤销整组
+		if order.GroupID != "" {
+			for id, sibling := range m.pendingOrders {
+				if sibling.GroupID == order.GroupID {
+					delete(m.pendingOrders, id)
+					logger.Info(fmt.Sprintf("取消OCO同组挂单: id=%s, group=%s", id, order.GroupID))
+				}
+			}
+		}
 		return nil
 	}
 
@@ -114,16 +168,41 @@ func (m *BacktestOrderManager) CancelAllOrders(ctx context.Context) error {
 }
 
 func (m *BacktestOrderManager) CheckAndExecuteOrders(ctx context.Context, kline *cex.KlineData) ([]*executor.OrderResult, error) {
-	ctx, logger := log.WithCtx(ctx)
-
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// 有K线内细粒度行情时按时间顺序逐根撮合
+	var executedResults []*executor.OrderResult
+	for _, bar := range m.intrabarBarsLocked(ctx, kline) {
+		executedResults = append(executedResults, m.checkBarLocked(ctx, bar)...)
+	}
+	return executedResults, nil
+}
+
+// checkBarLocked 在一根K线上撮合挂单（调用方持有锁）
+func (m *BacktestOrderManager) checkBarLocked(ctx context.Context, kline *cex.KlineData) []*executor.OrderResult {
+	ctx, logger := log.WithCtx(ctx)
+
 	m.currentTime = kline.OpenTime
 	var executedResults []*executor.OrderResult
 	var toRemove []string
 
-	for orderID, pendingOrder := range m.pendingOrders {
+	// 交易暂停期间（维护、集合竞价等）实际无法成交，挂单保留到恢复交易
+	blocked, blockReason := m.calendar.IsBlockedDuring(kline.OpenTime, kline.CloseTime)
+	if blocked && len(m.pendingOrders) > 0 {
+		logger.Info(fmt.Sprintf("⏸️ 交易暂停，跳过挂单撮合: time=%s, reason=%s",
+			kline.OpenTime.Format("2006-01-02 15:04"), blockReason))
+	}
+
+	executedGroups := make(map[string]bool)
+	for _, orderID := range m.sortedPendingOrderIDsLocked() {
+		pendingOrder := m.pendingOrders[orderID]
+
+		// OCO 同组已有挂单成交，本单在循环结束后撤销
+		if pendingOrder.GroupID != "" && executedGroups[pendingOrder.GroupID] {
+			continue
+		}
+
 		// 检查是否过期
 		if pendingOrder.ExpireTime != nil && m.currentTime.After(*pendingOrder.ExpireTime) {
 			logger.Info(fmt.Sprintf("挂单过期，自动取消: id=%s, expire_time=%s", orderID, pendingOrder.ExpireTime))
@@ -131,6 +210,15 @@ func (m *BacktestOrderManager) CheckAndExecuteOrders(ctx context.Context, kline
 			continue
 		}
 
+		if blocked {
+			// IOC/FOK 无法立即成交，直接撤销
+			if pendingOrder.TimeInForce.Immediate() {
+				logger.Info(fmt.Sprintf("⌛ %s 挂单交易暂停期间无法成交，撤销: id=%s", pendingOrder.TimeInForce, orderID))
+				toRemove = append(toRemove, orderID)
+			}
+			continue
+		}
+
 		// 检查是否满足执行条件
 		shouldExecute := false
 		var executionPrice decimal.Decimal
@@ -159,6 +247,67 @@ func (m *BacktestOrderManager) CheckAndExecuteOrders(ctx context.Context, kline
 					executionPrice = pendingOrder.Price
 				}
 			}
+
+		case PendingOrderTypeTrailingStop:
+			// 移动止损单：先按上一根K线结束时的触发价判断，未触发再用本根最高价上移触发价
+			// （无法得知K线内高低点先后顺序，保守处理）
+			if triggered, price := stopOrderTriggered(pendingOrder, kline); triggered {
+				shouldExecute = true
+				executionPrice = price
+			} else if ratchetTrailingStop(pendingOrder, kline.High) {
+				logger.Info(fmt.Sprintf("📈 移动止损上移: id=%s, high=%s, stop=%s",
+					orderID, pendingOrder.HighWaterMark.String(), pendingOrder.Price.String()))
+			}
+
+		case PendingOrderTypeStopLoss:
+			// 止损单：最低价跌破触发价时按市价卖出，跳空低开按开盘价成交
+			shouldExecute, executionPrice = stopOrderTriggered(pendingOrder, kline)
+
+		case PendingOrderTypeBuyMarket, PendingOrderTypeSellMarket:
+			// 市价单：按下单后第一根K线的开盘价成交，滑点由成交模型计算
+			shouldExecute = true
+			executionPrice = kline.Open
+			if pendingOrder.isQuoteOrder() && executionPrice.IsPositive() {
+				// 按金额买入：成交数量按开盘价换算
+				pendingOrder.Quantity = pendingOrder.QuoteQuantity.Div(executionPrice)
+			}
+		}
+
+		// IOC/FOK 只在下单后第一根K线撮合，未触及价格时整单撤销
+		if !shouldExecute && pendingOrder.TimeInForce.Immediate() {
+			logger.Info(fmt.Sprintf("⌛ %s 挂单未能立即成交，撤销: id=%s, price=%s", pendingOrder.TimeInForce, orderID, pendingOrder.Price.String()))
+			toRemove = append(toRemove, orderID)
+			continue
+		}
+
+		// 流动性不足时本根K线不成交，挂单保留；成交时价格包含滑点，成交量不足时部分成交
+		executionQuantity := pendingOrder.Quantity
+		if shouldExecute && m.fillModel != nil {
+			decision := m.fillModel.Fill(pendingOrder, kline, executionPrice)
+			if !decision.Filled {
+				logger.Info(fmt.Sprintf("💧 挂单未成交: id=%s, %s", orderID, decision.Reason))
+				if pendingOrder.TimeInForce.Immediate() {
+					toRemove = append(toRemove, orderID)
+				}
+				continue
+			}
+			executionPrice = decision.Price
+			if pendingOrder.isQuoteOrder() && executionPrice.IsPositive() {
+				// 滑点后的成交价买到的数量
+				pendingOrder.Quantity = pendingOrder.QuoteQuantity.Div(executionPrice)
+				executionQuantity = pendingOrder.Quantity
+			}
+			if decision.Quantity.IsPositive() && decision.Quantity.LessThan(pendingOrder.Quantity) {
+				executionQuantity = decision.Quantity
+			}
+		}
+
+		// FOK 不能全部成交时整单撤销
+		if shouldExecute && pendingOrder.TimeInForce == cex.TimeInForceFOK && executionQuantity.LessThan(pendingOrder.Quantity) {
+			logger.Info(fmt.Sprintf("⌛ FOK 挂单无法全部成交，撤销: id=%s, qty=%s, available=%s",
+				orderID, pendingOrder.Quantity.String(), executionQuantity.String()))
+			toRemove = append(toRemove, orderID)
+			continue
 		}
 
 		if shouldExecute {
@@ -169,41 +318,93 @@ func (m *BacktestOrderManager) CheckAndExecuteOrders(ctx context.Context, kline
 			var err error
 
 			switch pendingOrder.Type {
-			case PendingOrderTypeBuyLimit:
+			case PendingOrderTypeBuyLimit, PendingOrderTypeBuyMarket:
+				orderType := executor.OrderTypeLimit
+				if pendingOrder.Type == PendingOrderTypeBuyMarket {
+					orderType = executor.OrderTypeMarket
+				}
 				buyOrder := &executor.BuyOrder{
 					ID:          pendingOrder.ID,
 					TradingPair: pendingOrder.TradingPair,
-					Type:        executor.OrderTypeLimit,
-					Quantity:    pendingOrder.Quantity,
+					Type:        orderType,
+					Quantity:    executionQuantity,
 					Price:       executionPrice,
 					Timestamp:   kline.OpenTime,
-					Reason:      fmt.Sprintf("执行买入挂单: %s", pendingOrder.Reason),
+					Reason:      pendingOrder.Reason,
+					Origin:      pendingOrder.OriginSignal,
+					TimeInForce: pendingOrder.TimeInForce,
+					Signal:      pendingOrder.Signal,
+				}
+				if pendingOrder.isQuoteOrder() {
+					buyOrder.QuoteQuantity = executionQuantity.Mul(executionPrice)
 				}
 				result, err = m.executor.Buy(ctx, buyOrder)
 
-			case PendingOrderTypeSellLimit:
+			case PendingOrderTypeSellLimit, PendingOrderTypeTrailingStop, PendingOrderTypeStopLoss, PendingOrderTypeSellMarket:
+				orderType := executor.OrderTypeLimit
+				if pendingOrder.Type != PendingOrderTypeSellLimit {
+					orderType = executor.OrderTypeMarket // 市价卖单、止损触发后按市价卖出
+				}
 				sellOrder := &executor.SellOrder{
 					ID:          pendingOrder.ID,
 					TradingPair: pendingOrder.TradingPair,
-					Type:        executor.OrderTypeLimit,
-					Quantity:    pendingOrder.Quantity,
+					Type:        orderType,
+					Quantity:    executionQuantity,
 					Price:       executionPrice,
 					Timestamp:   kline.OpenTime,
-					Reason:      fmt.Sprintf("执行卖出挂单: %s", pendingOrder.Reason),
+					Reason:      pendingOrder.Reason,
+					Origin:      pendingOrder.OriginSignal,
+					TimeInForce: pendingOrder.TimeInForce,
+					Signal:      pendingOrder.Signal,
 				}
 				result, err = m.executor.Sell(ctx, sellOrder)
 			}
 
 			if err != nil {
 				logger.Error("挂单执行失败", "id", orderID, "error", err)
-				// 执行失败，保留挂单
+				// 执行失败，保留挂单（IOC/FOK 和市价单撤销）
+				if pendingOrder.TimeInForce.Immediate() || pendingOrder.isMarket() {
+					toRemove = append(toRemove, orderID)
+				}
+				continue
+			}
+
+			if result != nil && result.Success {
+				result.ClientOrderID = pendingOrder.ID
+			}
+
+			// 执行器只成交了一部分（如模拟盘按实时盘口深度撮合），按实际成交量处理
+			if result != nil && result.Success && result.Quantity.IsPositive() && result.Quantity.LessThan(executionQuantity) {
+				executionQuantity = result.Quantity
+			}
+
+			if result != nil && result.Success && executionQuantity.LessThan(pendingOrder.Quantity) {
+				// 部分成交：剩余数量继续挂单，OCO 同组挂单同步减少数量
+				m.reducePendingQuantityLocked(pendingOrder, executionQuantity)
+				executedResults = append(executedResults, accumulateFill(pendingOrder, result))
+				if pendingOrder.TimeInForce.Immediate() {
+					// IOC：未成交部分撤销，不再挂单
+					toRemove = append(toRemove, orderID)
+					logger.Info(fmt.Sprintf("⌛ %s 挂单部分成交，剩余撤销: id=%s, filled=%s, canceled=%s",
+						pendingOrder.TimeInForce, orderID, executionQuantity.String(), pendingOrder.Quantity.String()))
+					continue
+				}
+				logger.Info(fmt.Sprintf("🧩 挂单部分成交: id=%s, filled=%s, remaining=%s",
+					orderID, executionQuantity.String(), pendingOrder.Quantity.String()))
 				continue
 			}
 
 			if result != nil && result.Success {
 				// 挂单执行详情已在executor中记录，此处无需重复
+				if pendingOrder.filled != nil {
+					pendingOrder.Quantity = pendingOrder.Quantity.Sub(executionQuantity)
+					result = accumulateFill(pendingOrder, result)
+				}
 				executedResults = append(executedResults, result)
 				toRemove = append(toRemove, orderID)
+				if pendingOrder.GroupID != "" {
+					executedGroups[pendingOrder.GroupID] = true
+				}
 			}
 		}
 	}
@@ -213,7 +414,81 @@ func (m *BacktestOrderManager) CheckAndExecuteOrders(ctx context.Context, kline
 		delete(m.pendingOrders, orderID)
 	}
 
-	return executedResults, nil
+	// OCO：撤销已成交挂单的同组挂单
+	for orderID, pendingOrder := range m.pendingOrders {
+		if pendingOrder.GroupID != "" && executedGroups[pendingOrder.GroupID] {
+			delete(m.pendingOrders, orderID)
+			logger.Info(fmt.Sprintf("🔗 OCO 同组挂单已成交，撤销: id=%s, group=%s", orderID, pendingOrder.GroupID))
+		}
+	}
+
+	return executedResults
+}
+
+// accumulateFill 将本次成交累加到挂单的累计结果，返回累计结果的快照
+// （累计成交量、加权均价、剩余数量和每次成交明细）
+func accumulateFill(order *PendingOrder, result *executor.OrderResult) *executor.OrderResult {
+	if order.filled == nil {
+		order.filled = &executor.OrderResult{
+			OrderID:       result.OrderID,
+			ClientOrderID: result.ClientOrderID,
+			TradingPair:   result.TradingPair,
+			Side:          result.Side,
+			Success:       true,
+			Reason:        result.Reason,
+			Origin:        result.Origin,
+			Signal:        result.Signal,
+		}
+	}
+	order.filled.AddFill(executor.Fill{
+		Quantity:   result.Quantity,
+		Price:      result.Price,
+		Commission: result.Commission,
+		Timestamp:  result.Timestamp,
+	})
+	order.filled.RemainingQuantity = order.Quantity
+
+	snapshot := *order.filled
+	snapshot.Fills = append([]executor.Fill(nil), order.filled.Fills...)
+	return &snapshot
+}
+
+// reducePendingQuantityLocked 部分成交后减少挂单及其 OCO 同组挂单的剩余数量（调用方需持有锁）
+func (m *BacktestOrderManager) reducePendingQuantityLocked(order *PendingOrder, filled decimal.Decimal) {
+	order.Quantity = order.Quantity.Sub(filled)
+	// 按金额买入的剩余部分按数量继续成交
+	order.QuoteQuantity = decimal.Zero
+	if order.GroupID == "" {
+		return
+	}
+	for _, sibling := range m.pendingOrders {
+		if sibling != order && sibling.GroupID == order.GroupID {
+			sibling.Quantity = decimal.Max(sibling.Quantity.Sub(filled), decimal.Zero)
+		}
+	}
+}
+
+// sortedPendingOrderIDsLocked 按撮合顺序返回挂单ID（调用方需持有锁）
+// 止损类挂单优先：同一根K线内止盈和止损都触及时无法得知先后顺序，保守按止损成交
+func (m *BacktestOrderManager) sortedPendingOrderIDsLocked() []string {
+	ids := make([]string, 0, len(m.pendingOrders))
+	for id := range m.pendingOrders {
+		ids = append(ids, id)
+	}
+	isStop := func(order *PendingOrder) bool {
+		return order.Type == PendingOrderTypeStopLoss || order.Type == PendingOrderTypeTrailingStop
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := m.pendingOrders[ids[i]], m.pendingOrders[ids[j]]
+		if isStop(a) != isStop(b) {
+			return isStop(a)
+		}
+		if !a.CreateTime.Equal(b.CreateTime) {
+			return a.CreateTime.Before(b.CreateTime)
+		}
+		return a.ID < b.ID
+	})
+	return ids
 }
 
 func (m *BacktestOrderManager) GetPendingOrders() []*PendingOrder {
@@ -238,6 +513,22 @@ type LiveOrderManager struct {
 	cexClient     cex.CEXClient
 	pendingOrders map[string]*PendingOrder
 	mu            sync.RWMutex
+	limits        OpenOrderLimits            // 每个交易对的挂单数量限制
+	maxNotional   decimal.Decimal            // 单笔开仓挂单金额上限（0 表示不限制）
+	stopOrderIDs  map[string]string          // 移动止损挂单ID -> 交易所止损单ID
+	ocoListIDs    map[string]string          // OCO 组ID -> 交易所订单组ID
+	limitOrders   map[string]*liveLimitOrder // 限价挂单ID -> 交易所订单
+	symbolFilters *SymbolFilterService       // 交易对下单规则（为空时不取整）
+
+	// 账户数据流推送的成交（在下次检查挂单时返回给引擎）
+	streaming   bool
+	streamFills []*executor.OrderResult
+
+	marketFills []*executor.OrderResult // 市价单成交（在下次检查挂单时返回给引擎）
+	limitFills  []*executor.OrderResult // 下单即结束或对账发现的限价单成交（在下次检查挂单时返回给引擎）
+
+	events  *EventBus        // 事件总线（为空时不发布）
+	auditor cex.OrderAuditor // 订单审计日志（为空时不记录）
 }
 
 // NewLiveOrderManager 创建实盘挂单管理器
@@ -245,12 +536,73 @@ func NewLiveOrderManager(cexClient cex.CEXClient) *LiveOrderManager {
 	return &LiveOrderManager{
 		cexClient:     cexClient,
 		pendingOrders: make(map[string]*PendingOrder),
+		stopOrderIDs:  make(map[string]string),
+		ocoListIDs:    make(map[string]string),
+		limitOrders:   make(map[string]*liveLimitOrder),
 	}
 }
 
+// SetOpenOrderLimits 设置每个交易对的挂单数量限制
+func (m *LiveOrderManager) SetOpenOrderLimits(limits OpenOrderLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limits = limits
+}
+
+// SetMaxOrderNotional 设置单笔开仓挂单金额上限（计价资产，0 表示不限制）
+func (m *LiveOrderManager) SetMaxOrderNotional(maxNotional decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxNotional = maxNotional
+}
+
 func (m *LiveOrderManager) PlaceOrder(ctx context.Context, order *PendingOrder) error {
 	ctx, logger := log.WithCtx(ctx)
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 安全门：配置只读或未启用交易时拒绝下单
+	if err := cex.CheckTradingEnabled(m.cexClient); err != nil {
+		logger.Error(fmt.Sprintf("🚫 交易未启用，拒绝挂单: symbol=%s, id=%s, error=%v", order.TradingPair.String(), order.ID, err))
+		return err
+	}
+
+	// 安全门：单笔开仓金额上限
+	if order.exceedsMaxNotional(m.maxNotional) {
+		logger.Error(fmt.Sprintf("🚫 开仓挂单金额超过单笔上限，拒绝挂单: symbol=%s, notional=%s, max=%s, id=%s",
+			order.TradingPair.String(), order.notional().String(), m.maxNotional.String(), order.ID))
+		return fmt.Errorf("%w: %s > %s", ErrMaxNotionalExceeded, order.notional().String(), m.maxNotional.String())
+	}
+
+	// 检查该交易对的挂单数量限制
+	symbol := order.TradingPair.String()
+	openCount := m.countOpenOrdersLocked(symbol)
+	if m.limits.exceedsHard(openCount) {
+		logger.Error(fmt.Sprintf("🚫 挂单数量达到硬上限，拒绝挂单: symbol=%s, open=%d, hard_limit=%d, id=%s",
+			symbol, openCount, m.limits.HardLimit, order.ID))
+		return fmt.Errorf("%w: %s has %d open orders (hard limit %d)", ErrOpenOrderLimitExceeded, symbol, openCount, m.limits.HardLimit)
+	}
+	if m.limits.reachesSoft(openCount) {
+		logger.Warning(fmt.Sprintf("⚠️ 挂单数量接近交易所上限: symbol=%s, open=%d, soft_limit=%d, hard_limit=%d",
+			symbol, openCount+1, m.limits.SoftLimit, m.limits.HardLimit))
+	}
+
+	// 移动止损单：交易所不支持直接修改止损价，用止损单 + 撤单重挂模拟
+	if order.Type == PendingOrderTypeTrailingStop {
+		return m.placeTrailingStopLocked(ctx, order)
+	}
+
+	// 市价单：直接向交易所下单
+	if order.isMarket() {
+		return m.placeMarketOrderLocked(ctx, order)
+	}
+
+	// 限价单：按有效方式向交易所下单
+	if order.isLimit() {
+		return m.placeLimitOrderLocked(ctx, order)
+	}
+
 	// TODO: 实现真实的挂单API调用
 	logger.Info("下实盘挂单（暂未实现）",
 		"id", order.ID,
@@ -258,8 +610,6 @@ func (m *LiveOrderManager) PlaceOrder(ctx context.Context, order *PendingOrder)
 		"price", order.Price.String(),
 		"quantity", order.Quantity.String())
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.pendingOrders[order.ID] = order
 
 	return fmt.Errorf("live order placement not implemented yet")
@@ -268,11 +618,23 @@ func (m *LiveOrderManager) PlaceOrder(ctx context.Context, order *PendingOrder)
 func (m *LiveOrderManager) CancelOrder(ctx context.Context, orderID string) error {
 	ctx, logger := log.WithCtx(ctx)
 
-	// TODO: 实现真实的取消挂单API调用
-	logger.Info(fmt.Sprintf("取消实盘挂单（暂未实现）: id=%s", orderID))
-
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	if _, ok := m.stopOrderIDs[orderID]; ok {
+		return m.cancelTrailingStopLocked(ctx, orderID)
+	}
+	if _, ok := m.limitOrders[orderID]; ok {
+		return m.cancelLimitOrderLocked(ctx, orderID)
+	}
+	if order, ok := m.pendingOrders[orderID]; ok && order.GroupID != "" {
+		if _, isOCO := m.ocoListIDs[order.GroupID]; isOCO {
+			return m.cancelOCOLocked(ctx, order.GroupID)
+		}
+	}
+
+	// TODO: 实现真实的取消挂单API调用
+	logger.Info(fmt.Sprintf("取消实盘挂单（暂未实现）: id=%s", orderID))
 	delete(m.pendingOrders, orderID)
 
 	return fmt.Errorf("live order cancellation not implemented yet")
@@ -284,16 +646,65 @@ func (m *LiveOrderManager) CancelAllOrders(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// 交易所上的限价单、移动止损和 OCO 逐个撤销，撤单失败的保留以便重试；其余挂单只在本地移除
 	count := len(m.pendingOrders)
-	m.pendingOrders = make(map[string]*PendingOrder)
+	ids := make([]string, 0, count)
+	for id := range m.pendingOrders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
 
-	logger.Info(fmt.Sprintf("取消所有实盘挂单（暂未实现）: count=%d", count))
-	return fmt.Errorf("live order cancellation not implemented yet")
+	var errs []error
+	for _, id := range ids {
+		order, exists := m.pendingOrders[id]
+		if !exists {
+			continue // OCO 另一腿已随整组撤销
+		}
+		_, isStop := m.stopOrderIDs[id]
+		var err error
+		switch {
+		case m.limitOrders[id] != nil:
+			err = m.cancelLimitOrderLocked(ctx, id)
+		case isStop:
+			err = m.cancelTrailingStopLocked(ctx, id)
+		case order.GroupID != "" && m.ocoListIDs[order.GroupID] != "":
+			err = m.cancelOCOLocked(ctx, order.GroupID)
+		default:
+			delete(m.pendingOrders, id)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	logger.Info(fmt.Sprintf("取消所有实盘挂单: count=%d, remaining=%d", count, len(m.pendingOrders)))
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to cancel live orders: %w", errors.Join(errs...))
+	}
+	return nil
 }
 
 func (m *LiveOrderManager) CheckAndExecuteOrders(ctx context.Context, kline *cex.KlineData) ([]*executor.OrderResult, error) {
-	// TODO: 实现真实的挂单状态检查
-	return []*executor.OrderResult{}, fmt.Errorf("live order execution check not implemented yet")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ratchetTrailingStopsLocked(ctx, kline)
+	fills := append(m.drainMarketFillsLocked(), m.drainLimitFillsLocked()...)
+	// 数据流断开前推送的成交同样返回
+	fills = append(fills, m.drainStreamFillsLocked()...)
+
+	// 接入账户数据流后成交由推送实时更新；否则查询限价挂单的状态
+	var err error
+	if !m.streaming {
+		var polled []*executor.OrderResult
+		polled, err = m.pollLimitOrdersLocked(ctx)
+		fills = append(fills, polled...)
+	}
+
+	// 成交之后再撤销过期的限价单（GTD）
+	if expireErr := m.expireLimitOrdersLocked(ctx, kline); expireErr != nil {
+		err = errors.Join(err, expireErr)
+	}
+	return fills, err
 }
 
 func (m *LiveOrderManager) GetPendingOrders() []*PendingOrder {
@@ -307,6 +718,29 @@ func (m *LiveOrderManager) GetPendingOrders() []*PendingOrder {
 	return orders
 }
 
+// GetOpenOrderCounts 获取每个交易对的挂单数量
+func (m *LiveOrderManager) GetOpenOrderCounts() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, order := range m.pendingOrders {
+		counts[order.TradingPair.String()]++
+	}
+	return counts
+}
+
+// countOpenOrdersLocked 统计某交易对的挂单数量（调用方需持有锁）
+func (m *LiveOrderManager) countOpenOrdersLocked(symbol string) int {
+	count := 0
+	for _, order := range m.pendingOrders {
+		if order.TradingPair.String() == symbol {
+			count++
+		}
+	}
+	return count
+}
+
 func (m *LiveOrderManager) GetOrderCount() int {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
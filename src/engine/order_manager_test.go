@@ -54,6 +54,7 @@ type mockOrderExecutor struct {
 	shouldFailSell bool
 	buyCallCount   int
 	sellCallCount  int
+	buyFillLimit   decimal.Decimal // 大于0时每次买入最多成交该数量（模拟盘口深度不足）
 }
 
 func newMockOrderExecutor(cash, position decimal.Decimal) *mockOrderExecutor {
@@ -71,7 +72,11 @@ func (m *mockOrderExecutor) Buy(ctx context.Context, order *executor.BuyOrder) (
 	}
 
 	// 模拟成功买入
-	cost := order.Quantity.Mul(order.Price)
+	quantity := order.Quantity
+	if m.buyFillLimit.IsPositive() && m.buyFillLimit.LessThan(quantity) {
+		quantity = m.buyFillLimit
+	}
+	cost := quantity.Mul(order.Price)
 	if cost.GreaterThan(m.cash) {
 		return &executor.OrderResult{
 			Success: false,
@@ -80,12 +85,12 @@ func (m *mockOrderExecutor) Buy(ctx context.Context, order *executor.BuyOrder) (
 	}
 
 	m.cash = m.cash.Sub(cost)
-	m.position = m.position.Add(order.Quantity)
+	m.position = m.position.Add(quantity)
 
 	result := &executor.OrderResult{
 		Success:   true,
 		OrderID:   order.ID,
-		Quantity:  order.Quantity,
+		Quantity:  quantity,
 		Price:     order.Price,
 		Timestamp: order.Timestamp,
 		Side:      "BUY",
@@ -466,6 +471,32 @@ func TestBacktestOrderManager_CheckAndExecuteOrders_ExecutionFailure(t *testing.
 	assert.Equal(t, 1, mockExec.buyCallCount)   // 尝试了执行
 }
 
+func TestBacktestOrderManager_CheckAndExecuteOrders_ExecutorPartialFill(t *testing.T) {
+	// 执行器按实际深度只成交一部分时，剩余数量继续挂单
+	mockExec := newMockOrderExecutor(decimal.NewFromInt(100000), decimal.Zero)
+	mockExec.buyFillLimit = decimal.NewFromFloat(0.4)
+	manager := NewBacktestOrderManager(mockExec)
+	ctx := context.Background()
+
+	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "buy_1", decimal.NewFromFloat(50000))
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+
+	price := decimal.NewFromFloat(49000)
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(time.Now(), price, price, price, price))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, decimal.NewFromFloat(0.4).Equal(results[0].Quantity))
+	assert.True(t, decimal.NewFromFloat(0.6).Equal(results[0].RemainingQuantity))
+	assert.Equal(t, 1, manager.GetOrderCount())
+
+	mockExec.buyFillLimit = decimal.Zero
+	results, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(time.Now(), price, price, price, price))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, decimal.NewFromInt(1).Equal(results[0].Quantity))
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
 func TestBacktestOrderManager_CheckAndExecuteOrders_MultipleOrders(t *testing.T) {
 	mockExec := newMockOrderExecutor(decimal.NewFromInt(100000), decimal.NewFromInt(2))
 	manager := NewBacktestOrderManager(mockExec)
@@ -683,12 +714,12 @@ func TestLiveOrderManager_PlaceOrder_NotImplemented(t *testing.T) {
 	mockClient := &MockCEXClient{}
 	manager := NewLiveOrderManager(mockClient)
 
-	order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "live_buy", decimal.NewFromFloat(50000))
+	order := CreateTestPendingOrder(PendingOrderTypeStopLoss, "live_stop", decimal.NewFromFloat(50000))
 
 	ctx := context.Background()
 	err := manager.PlaceOrder(ctx, order)
 
-	// 应该返回未实现错误
+	// 单独的止损挂单应该返回未实现错误
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not implemented")
 
@@ -708,7 +739,7 @@ func TestLiveOrderManager_CancelOrder_NotImplemented(t *testing.T) {
 	assert.Contains(t, err.Error(), "not implemented")
 }
 
-func TestLiveOrderManager_CheckAndExecuteOrders_NotImplemented(t *testing.T) {
+func TestLiveOrderManager_CheckAndExecuteOrders_NoOrders(t *testing.T) {
 	mockClient := &MockCEXClient{}
 	manager := NewLiveOrderManager(mockClient)
 
@@ -723,9 +754,8 @@ func TestLiveOrderManager_CheckAndExecuteOrders_NotImplemented(t *testing.T) {
 	ctx := context.Background()
 	results, err := manager.CheckAndExecuteOrders(ctx, kline)
 
-	// 应该返回未实现错误
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not implemented")
+	// 没有挂单时不查询交易所
+	assert.NoError(t, err)
 	assert.Len(t, results, 0)
 }
 
@@ -840,8 +870,7 @@ func TestLiveOrderManager_CancelAllOrders(t *testing.T) {
 
 	ctx := context.Background()
 	err := liveOrderManager.CancelAllOrders(ctx)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not implemented")
+	assert.NoError(t, err)
 }
 
 func TestLiveOrderManager_GetPendingOrders(t *testing.T) {
@@ -850,3 +879,85 @@ func TestLiveOrderManager_GetPendingOrders(t *testing.T) {
 	orders := liveOrderManager.GetPendingOrders()
 	assert.Empty(t, orders)
 }
+
+func TestLiveOrderManager_OpenOrderLimits(t *testing.T) {
+	manager := NewLiveOrderManager(&mockLimitOrderCEXClient{})
+	manager.SetOpenOrderLimits(OpenOrderLimits{SoftLimit: 2, HardLimit: 3})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		order := CreateTestPendingOrder(PendingOrderTypeSellLimit, fmt.Sprintf("grid_%d", i), decimal.NewFromInt(int64(50000+i*100)))
+		// 未触及硬上限
+		require.NoError(t, manager.PlaceOrder(ctx, order))
+	}
+	assert.Equal(t, 3, manager.GetOrderCount())
+
+	// 达到硬上限后拒绝挂单，且不加入本地管理
+	err := manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeSellLimit, "grid_3", decimal.NewFromInt(50300)))
+	assert.ErrorIs(t, err, ErrOpenOrderLimitExceeded)
+	assert.Equal(t, 3, manager.GetOrderCount())
+
+	// 其他交易对单独计数
+	ethOrder := CreateTestPendingOrder(PendingOrderTypeBuyLimit, "eth_buy", decimal.NewFromInt(3000))
+	ethOrder.TradingPair = cex.TradingPair{Base: "ETH", Quote: "USDT"}
+	require.NoError(t, manager.PlaceOrder(ctx, ethOrder))
+
+	counts := manager.GetOpenOrderCounts()
+	assert.Equal(t, map[string]int{"BTC/USDT": 3, "ETH/USDT": 1}, counts)
+}
+
+func TestLiveOrderManager_MaxOrderNotional(t *testing.T) {
+	ctx := context.Background()
+	client := &mockMarketOrderCEXClient{}
+	manager := NewLiveOrderManager(client)
+	manager.SetMaxOrderNotional(decimal.NewFromInt(1000))
+
+	// 1 BTC @ 50000 超过上限，不提交到交易所
+	err := manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_1", decimal.NewFromInt(50000)))
+	assert.ErrorIs(t, err, ErrMaxNotionalExceeded)
+	assert.Empty(t, client.buys)
+
+	// 按金额下单时按下单金额检查
+	quoteOrder := CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_2", decimal.NewFromInt(50000))
+	quoteOrder.QuoteQuantity = decimal.NewFromInt(500)
+	require.NoError(t, manager.PlaceOrder(ctx, quoteOrder))
+	assert.Len(t, client.buys, 1)
+
+	// 卖单不受限制
+	err = manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeSellLimit, "sell_1", decimal.NewFromInt(50000)))
+	assert.NotErrorIs(t, err, ErrMaxNotionalExceeded)
+}
+
+// permissionCEXClient 带配置交易开关的客户端
+type permissionCEXClient struct {
+	mockMarketOrderCEXClient
+	permission cex.TradingPermission
+}
+
+func (c *permissionCEXClient) TradingPermission() cex.TradingPermission {
+	return c.permission
+}
+
+func TestLiveOrderManager_TradingDisabled(t *testing.T) {
+	ctx := context.Background()
+	client := &permissionCEXClient{permission: cex.TradingPermission{EnableTrading: true, ReadOnly: true}}
+	manager := NewLiveOrderManager(client)
+
+	// 只读模式下不提交到交易所
+	err := manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_1", decimal.NewFromInt(50000)))
+	assert.ErrorIs(t, err, cex.ErrTradingDisabled)
+	assert.Empty(t, client.buys)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	client.permission = cex.TradingPermission{EnableTrading: true}
+	require.NoError(t, manager.PlaceOrder(ctx, CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy_2", decimal.NewFromInt(50000))))
+	assert.Len(t, client.buys, 1)
+}
+
+func TestOpenOrderLimits_Validate(t *testing.T) {
+	assert.NoError(t, OpenOrderLimits{}.Validate())
+	assert.NoError(t, OpenOrderLimits{SoftLimit: 150, HardLimit: 200}.Validate())
+	assert.NoError(t, OpenOrderLimits{SoftLimit: 150}.Validate())
+	assert.Error(t, OpenOrderLimits{SoftLimit: 250, HardLimit: 200}.Validate())
+	assert.Error(t, OpenOrderLimits{SoftLimit: -1}.Validate())
+}
@@ -0,0 +1,203 @@
+package engine
+
+import (
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+)
+
+// SizingInput 仓位计算输入
+type SizingInput struct {
+	Portfolio  *executor.Portfolio
+	EntryPrice decimal.Decimal  // 计划入场价（买入限价）
+	Klines     []*cex.KlineData // 截至当前K线的历史数据（含当前K线）
+	Signal     *strategy.Signal
+}
+
+// PositionSizer 仓位计算器：根据账户和行情计算本次买入金额（计价资产）
+type PositionSizer interface {
+	// Size 计算买入金额，返回 0 表示不买入；结果由引擎再按可用现金截断
+	Size(in SizingInput) decimal.Decimal
+
+	// Describe 仓位计算方式说明
+	Describe() string
+}
+
+// equity 账户总权益：现金 + 持仓按入场价估值
+func (in SizingInput) equity() decimal.Decimal {
+	return in.Portfolio.Cash.Add(in.Portfolio.Position.Mul(in.EntryPrice))
+}
+
+// FixedPercentSizer 固定比例：可用现金 × Percent
+type FixedPercentSizer struct {
+	Percent float64
+}
+
+// NewFixedPercentSizer 创建固定比例仓位计算器
+func NewFixedPercentSizer(percent float64) *FixedPercentSizer {
+	return &FixedPercentSizer{Percent: percent}
+}
+
+// Validate 验证参数
+func (s *FixedPercentSizer) Validate() error {
+	if s.Percent <= 0 || s.Percent > 1 {
+		return fmt.Errorf("position size percent must be in (0, 1], got %f", s.Percent)
+	}
+	return nil
+}
+
+// Size 计算买入金额
+func (s *FixedPercentSizer) Size(in SizingInput) decimal.Decimal {
+	return in.Portfolio.Cash.Mul(decimal.NewFromFloat(s.Percent))
+}
+
+// Describe 仓位计算方式说明
+func (s *FixedPercentSizer) Describe() string {
+	return fmt.Sprintf("fixed percent %.1f%% of cash", s.Percent*100)
+}
+
+// FixedNotionalSizer 固定金额：每次买入 Notional（现金不足时买入全部可用现金）
+type FixedNotionalSizer struct {
+	Notional float64
+}
+
+// NewFixedNotionalSizer 创建固定金额仓位计算器
+func NewFixedNotionalSizer(notional float64) *FixedNotionalSizer {
+	return &FixedNotionalSizer{Notional: notional}
+}
+
+// Validate 验证参数
+func (s *FixedNotionalSizer) Validate() error {
+	if s.Notional <= 0 {
+		return fmt.Errorf("fixed notional must be positive, got %f", s.Notional)
+	}
+	return nil
+}
+
+// Size 计算买入金额
+func (s *FixedNotionalSizer) Size(in SizingInput) decimal.Decimal {
+	return decimal.NewFromFloat(s.Notional)
+}
+
+// Describe 仓位计算方式说明
+func (s *FixedNotionalSizer) Describe() string {
+	return fmt.Sprintf("fixed notional %.2f", s.Notional)
+}
+
+// KellySizer 凯利公式：f* = W - (1 - W) / R，按 Fraction 缩小（如半凯利 0.5），不超过 MaxPercent
+type KellySizer struct {
+	WinRate     float64 // 胜率 W
+	PayoffRatio float64 // 平均盈利 / 平均亏损 R
+	Fraction    float64 // 凯利比例缩放
+	MaxPercent  float64 // 单次仓位占权益的上限
+}
+
+// NewKellySizer 创建凯利仓位计算器
+func NewKellySizer(winRate, payoffRatio, fraction, maxPercent float64) *KellySizer {
+	return &KellySizer{
+		WinRate:     winRate,
+		PayoffRatio: payoffRatio,
+		Fraction:    fraction,
+		MaxPercent:  maxPercent,
+	}
+}
+
+// Validate 验证参数
+func (s *KellySizer) Validate() error {
+	if s.WinRate <= 0 || s.WinRate >= 1 {
+		return fmt.Errorf("kelly win rate must be in (0, 1), got %f", s.WinRate)
+	}
+	if s.PayoffRatio <= 0 {
+		return fmt.Errorf("kelly payoff ratio must be positive, got %f", s.PayoffRatio)
+	}
+	if s.Fraction <= 0 || s.Fraction > 1 {
+		return fmt.Errorf("kelly fraction must be in (0, 1], got %f", s.Fraction)
+	}
+	if s.MaxPercent <= 0 || s.MaxPercent > 1 {
+		return fmt.Errorf("max position percent must be in (0, 1], got %f", s.MaxPercent)
+	}
+	return nil
+}
+
+// KellyFraction 缩放后的凯利仓位比例（期望为负时为 0）
+func (s *KellySizer) KellyFraction() float64 {
+	kelly := (s.WinRate - (1-s.WinRate)/s.PayoffRatio) * s.Fraction
+	if kelly < 0 {
+		return 0
+	}
+	if kelly > s.MaxPercent {
+		return s.MaxPercent
+	}
+	return kelly
+}
+
+// Size 计算买入金额
+func (s *KellySizer) Size(in SizingInput) decimal.Decimal {
+	return in.equity().Mul(decimal.NewFromFloat(s.KellyFraction()))
+}
+
+// Describe 仓位计算方式说明
+func (s *KellySizer) Describe() string {
+	return fmt.Sprintf("kelly %.2f× (win rate %.1f%%, payoff %.2f) = %.1f%% of equity",
+		s.Fraction, s.WinRate*100, s.PayoffRatio, s.KellyFraction()*100)
+}
+
+// VolatilitySizer 波动率目标：止损距离 = ATR × ATRMultiplier，
+// 仓位使止损时亏损约为权益的 RiskPercent，不超过 MaxPercent
+type VolatilitySizer struct {
+	RiskPercent   float64 // 每笔交易愿意承担的权益风险比例
+	ATRPeriod     int     // ATR 周期
+	ATRMultiplier float64 // 止损距离的 ATR 倍数
+	MaxPercent    float64 // 单次仓位占权益的上限
+}
+
+// NewVolatilitySizer 创建波动率目标仓位计算器
+func NewVolatilitySizer(riskPercent float64, atrPeriod int, atrMultiplier, maxPercent float64) *VolatilitySizer {
+	return &VolatilitySizer{
+		RiskPercent:   riskPercent,
+		ATRPeriod:     atrPeriod,
+		ATRMultiplier: atrMultiplier,
+		MaxPercent:    maxPercent,
+	}
+}
+
+// Validate 验证参数
+func (s *VolatilitySizer) Validate() error {
+	if s.RiskPercent <= 0 || s.RiskPercent >= 1 {
+		return fmt.Errorf("risk percent must be in (0, 1), got %f", s.RiskPercent)
+	}
+	if s.ATRPeriod <= 0 {
+		return fmt.Errorf("ATR period must be positive, got %d", s.ATRPeriod)
+	}
+	if s.ATRMultiplier <= 0 {
+		return fmt.Errorf("ATR multiplier must be positive, got %f", s.ATRMultiplier)
+	}
+	if s.MaxPercent <= 0 || s.MaxPercent > 1 {
+		return fmt.Errorf("max position percent must be in (0, 1], got %f", s.MaxPercent)
+	}
+	return nil
+}
+
+// Size 计算买入金额，K线不足以计算 ATR 时返回 0
+func (s *VolatilitySizer) Size(in SizingInput) decimal.Decimal {
+	atr, ok := klineATR(in.Klines, s.ATRPeriod)
+	if !ok || !atr.IsPositive() {
+		return decimal.Zero
+	}
+
+	equity := in.equity()
+	stopDistance := atr.Mul(decimal.NewFromFloat(s.ATRMultiplier))
+	quantity := equity.Mul(decimal.NewFromFloat(s.RiskPercent)).Div(stopDistance)
+
+	return decimal.Min(quantity.Mul(in.EntryPrice), equity.Mul(decimal.NewFromFloat(s.MaxPercent)))
+}
+
+// Describe 仓位计算方式说明
+func (s *VolatilitySizer) Describe() string {
+	return fmt.Sprintf("volatility target: risk %.2f%% of equity per %.1f×ATR(%d), max %.1f%%",
+		s.RiskPercent*100, s.ATRMultiplier, s.ATRPeriod, s.MaxPercent*100)
+}
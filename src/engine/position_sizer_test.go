@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// constantRangeKlines 生成每根K线真实波幅为 rangeWidth 的K线
+func constantRangeKlines(count int, price, rangeWidth float64) []*cex.KlineData {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]*cex.KlineData, count)
+	for i := range klines {
+		klines[i] = CreateTestKlineWithPrices(start.Add(time.Duration(i)*time.Hour),
+			decimal.NewFromFloat(price), decimal.NewFromFloat(price+rangeWidth/2),
+			decimal.NewFromFloat(price-rangeWidth/2), decimal.NewFromFloat(price))
+	}
+	return klines
+}
+
+func TestPositionSizers(t *testing.T) {
+	input := SizingInput{
+		Portfolio:  &executor.Portfolio{Cash: decimal.NewFromInt(8000), Position: decimal.NewFromInt(20)},
+		EntryPrice: decimal.NewFromInt(100), // 权益 = 8000 + 20 × 100 = 10000
+	}
+
+	t.Run("fixed percent of cash", func(t *testing.T) {
+		sizer := NewFixedPercentSizer(0.5)
+		require.NoError(t, sizer.Validate())
+		assert.True(t, sizer.Size(input).Equal(decimal.NewFromInt(4000)))
+		assert.Error(t, NewFixedPercentSizer(0).Validate())
+	})
+
+	t.Run("fixed notional", func(t *testing.T) {
+		sizer := NewFixedNotionalSizer(1500)
+		require.NoError(t, sizer.Validate())
+		assert.True(t, sizer.Size(input).Equal(decimal.NewFromInt(1500)))
+		assert.Error(t, NewFixedNotionalSizer(-1).Validate())
+	})
+
+	t.Run("kelly fraction of equity", func(t *testing.T) {
+		// f* = 0.6 - 0.4 / 2 = 0.4，半凯利 0.2
+		sizer := NewKellySizer(0.6, 2, 0.5, 0.95)
+		require.NoError(t, sizer.Validate())
+		assert.InDelta(t, 0.2, sizer.KellyFraction(), 1e-9)
+		assert.InDelta(t, 2000, sizer.Size(input).InexactFloat64(), 1e-6)
+
+		// 期望为负时不开仓
+		assert.True(t, NewKellySizer(0.3, 1, 1, 0.95).Size(input).IsZero())
+		// 不超过上限
+		assert.InDelta(t, 0.25, NewKellySizer(0.9, 5, 1, 0.25).KellyFraction(), 1e-9)
+		assert.Error(t, NewKellySizer(1, 2, 0.5, 0.95).Validate())
+	})
+
+	t.Run("volatility target", func(t *testing.T) {
+		sizer := NewVolatilitySizer(0.01, 14, 2, 0.95)
+		require.NoError(t, sizer.Validate())
+
+		// K线不足时不开仓
+		input.Klines = constantRangeKlines(10, 100, 4)
+		assert.True(t, sizer.Size(input).IsZero())
+
+		// ATR = 4，止损距离 8，风险 100 → 数量 12.5，金额 1250
+		input.Klines = constantRangeKlines(30, 100, 4)
+		assert.InDelta(t, 1250, sizer.Size(input).InexactFloat64(), 1e-6)
+
+		// 波动很小时受仓位上限约束
+		input.Klines = constantRangeKlines(30, 100, 0.01)
+		assert.InDelta(t, 9500, sizer.Size(input).InexactFloat64(), 1e-6)
+	})
+}
+
+func TestTradingEngine_ProcessSignal_UsesPositionSizer(t *testing.T) {
+	mockOrderManager := &mockTradingOrderManager{}
+	engine := createTestTradingEngineWithMocks(
+		&mockTradingStrategy{},
+		newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero),
+		&mockTradingDataFeed{},
+		mockOrderManager,
+	)
+	engine.SetPositionSizer(NewFixedNotionalSizer(999))
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(99), decimal.NewFromInt(100))
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(10000)}
+	signal := &strategy.Signal{Type: "BUY", Strength: 0.8, Reason: "sizer test"}
+
+	require.NoError(t, engine.processSignal(context.Background(), signal, kline, portfolio))
+	require.Len(t, mockOrderManager.placedOrders, 1)
+	order := mockOrderManager.placedOrders[0]
+	assert.InDelta(t, 999, order.Quantity.Mul(order.Price).InexactFloat64(), 1e-6)
+
+	// 买入金额不超过可用现金
+	portfolio.Cash = decimal.NewFromInt(500)
+	require.NoError(t, engine.processSignal(context.Background(), signal, kline, portfolio))
+	require.Len(t, mockOrderManager.placedOrders, 2)
+	order = mockOrderManager.placedOrders[1]
+	assert.InDelta(t, 500, order.Quantity.Mul(order.Price).InexactFloat64(), 1e-6)
+}
@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// BalanceSyncer 可用交易所余额校正本地状态的执行器（由 executor.TradingExecutor 实现）
+type BalanceSyncer interface {
+	GetPortfolio(ctx context.Context) (*executor.Portfolio, error)
+	GetOrders() []executor.OrderResult
+
+	// SyncBalances 用交易所余额覆盖本地现金和持仓，ordersSeen 之后有新成交时放弃并返回 false
+	SyncBalances(cash, position decimal.Decimal, ordersSeen int) bool
+}
+
+// TrackedOrder 本地跟踪的交易所挂单
+type TrackedOrder struct {
+	LocalID    string // 本地挂单ID
+	ExchangeID string // 交易所订单ID（OCO 为空，重挂失败时为空）
+	ListID     string // 交易所 OCO 订单组ID
+	GroupID    string // 本地 OCO 组ID
+	Order      *PendingOrder
+}
+
+// OrderDiscrepancy 本地认为仍在挂单、交易所已不在挂单中的订单
+type OrderDiscrepancy struct {
+	TrackedOrder
+	Status   string          // 交易所订单状态（无法查询时为空）
+	Quantity decimal.Decimal // 已成交数量
+	Price    decimal.Decimal // 成交均价
+}
+
+// ReconcileReport 一次对账的结果
+type ReconcileReport struct {
+	Time time.Time
+
+	ClosedOrders  []OrderDiscrepancy // 离线期间成交或被撤销的挂单，已从本地移除
+	UnknownOrders []*cex.OrderResult // 交易所有、本地未跟踪的挂单（仅记录，不做处理）
+	LocalCash     decimal.Decimal    // 对账前本地现金
+	LocalPosition decimal.Decimal    // 对账前本地持仓
+	Cash          decimal.Decimal    // 交易所计价资产余额（可用+冻结）
+	Position      decimal.Decimal    // 交易所基础资产余额（可用+冻结）
+	BalanceDrift  bool               // 余额偏差超出容差
+	Repaired      bool               // 本地余额已按交易所校正
+}
+
+// HasDiscrepancies 是否存在差异
+func (r *ReconcileReport) HasDiscrepancies() bool {
+	return len(r.ClosedOrders) > 0 || len(r.UnknownOrders) > 0 || r.BalanceDrift
+}
+
+// Reconciler 实盘对账：定期拉取交易所挂单和余额，与本地挂单管理器、执行器比对并修复偏差
+type Reconciler struct {
+	client    cex.CEXClient
+	pair      cex.TradingPair
+	orders    *LiveOrderManager // 为空时不核对挂单
+	balances  BalanceSyncer
+	tolerance decimal.Decimal // 余额相对偏差容差
+
+	mu         sync.Mutex // 保证同一时刻只有一次对账
+	lastReport *ReconcileReport
+}
+
+// NewReconciler 创建对账器，tolerance 为余额相对偏差容差（如 0.001 表示 0.1%）
+func NewReconciler(client cex.CEXClient, pair cex.TradingPair, orders *LiveOrderManager, balances BalanceSyncer, tolerance float64) *Reconciler {
+	return &Reconciler{
+		client:    client,
+		pair:      pair,
+		orders:    orders,
+		balances:  balances,
+		tolerance: decimal.NewFromFloat(tolerance),
+	}
+}
+
+// Validate 检查配置
+func (r *Reconciler) Validate() error {
+	if r.tolerance.IsNegative() {
+		return fmt.Errorf("reconcile tolerance must be non-negative, got %s", r.tolerance.String())
+	}
+	return nil
+}
+
+// LastReport 最近一次对账结果（尚未对账时为空）
+func (r *Reconciler) LastReport() *ReconcileReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastReport
+}
+
+// Run 每隔 interval 对账一次，直到 ctx 取消
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) {
+	ctx, logger := log.WithCtx(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				logger.Error("⚠️ 实盘对账失败，下个周期重试", "error", err)
+			}
+		}
+	}
+}
+
+// Reconcile 执行一次对账
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	ctx, logger := log.WithCtx(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &ReconcileReport{Time: time.Now()}
+
+	if err := r.reconcileOrders(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileBalances(ctx, report); err != nil {
+		return nil, err
+	}
+
+	for _, closed := range report.ClosedOrders {
+		if closed.ListID != "" {
+			logger.Warning(fmt.Sprintf("🔄 OCO 订单组已不在交易所挂单中，已从本地移除: group=%s, list_id=%s",
+				closed.GroupID, closed.ListID))
+			continue
+		}
+		logger.Warning(fmt.Sprintf("🔄 挂单已不在交易所挂单中，已从本地移除: id=%s, exchange_id=%s, status=%s, filled=%s @ %s",
+			closed.LocalID, closed.ExchangeID, closed.Status, closed.Quantity.String(), closed.Price.String()))
+	}
+	for _, unknown := range report.UnknownOrders {
+		logger.Warning(fmt.Sprintf("❓ 交易所存在本地未跟踪的挂单: exchange_id=%s, side=%s, type=%s, quantity=%s, price=%s",
+			unknown.OrderID, unknown.Side, unknown.Type, unknown.OrigQuantity.String(), unknown.Price.String()))
+	}
+	if report.BalanceDrift {
+		action := "已按交易所校正"
+		if !report.Repaired {
+			action = "期间有新成交，下次对账再校正"
+		}
+		logger.Warning(fmt.Sprintf("💱 余额与交易所不一致，%s: cash %s → %s %s, position %s → %s %s",
+			action, report.LocalCash.String(), report.Cash.String(), r.pair.Quote,
+			report.LocalPosition.String(), report.Position.String(), r.pair.Base))
+	}
+
+	r.lastReport = report
+	return report, nil
+}
+
+// reconcileOrders 核对本地跟踪的挂单与交易所挂单
+func (r *Reconciler) reconcileOrders(ctx context.Context, report *ReconcileReport) error {
+	if r.orders == nil {
+		return nil
+	}
+	client, ok := r.client.(cex.OpenOrderClient)
+	if !ok {
+		return nil
+	}
+
+	open, err := client.GetOpenOrders(ctx, r.pair)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile open orders: %w", err)
+	}
+	openIDs := make(map[string]bool, len(open))
+	openListIDs := make(map[string]bool)
+	for _, order := range open {
+		openIDs[order.OrderID] = true
+		if order.OrderListID != "" {
+			openListIDs[order.OrderListID] = true
+		}
+	}
+
+	trackedIDs := make(map[string]bool)
+	trackedListIDs := make(map[string]bool)
+	for _, tracked := range r.orders.trackedOrders(r.pair) {
+		if tracked.ListID != "" {
+			trackedListIDs[tracked.ListID] = true
+		} else if tracked.ExchangeID != "" {
+			trackedIDs[tracked.ExchangeID] = true
+		}
+
+		switch {
+		case tracked.ListID != "":
+			if openListIDs[tracked.ListID] {
+				continue
+			}
+		case tracked.ExchangeID == "":
+			// 重挂失败的移动止损，由挂单管理器在下根K线重挂
+			continue
+		case openIDs[tracked.ExchangeID]:
+			continue
+		}
+
+		closed := OrderDiscrepancy{TrackedOrder: tracked}
+		if tracked.ExchangeID != "" {
+			order, err := client.GetOrder(ctx, r.pair, tracked.ExchangeID)
+			if err != nil {
+				return fmt.Errorf("failed to query closed order %s: %w", tracked.ExchangeID, err)
+			}
+			closed.Status = order.Status
+			closed.Quantity = order.Quantity
+			closed.Price = order.Price
+			if order.Status == cex.OrderStatusNew || order.Status == cex.OrderStatusPartiallyFilled {
+				// 挂单列表与订单查询之间状态不一致（如条件单刚触发），下次对账再处理
+				continue
+			}
+		}
+
+		r.orders.forgetClosedOrder(closed)
+		report.ClosedOrders = append(report.ClosedOrders, closed)
+	}
+
+	for _, order := range open {
+		if trackedIDs[order.OrderID] || (order.OrderListID != "" && trackedListIDs[order.OrderListID]) {
+			continue
+		}
+		report.UnknownOrders = append(report.UnknownOrders, order)
+	}
+	return nil
+}
+
+// reconcileBalances 核对本地现金、持仓与交易所余额
+func (r *Reconciler) reconcileBalances(ctx context.Context, report *ReconcileReport) error {
+	// 先记录订单数，再读取交易所余额：期间若有新成交，余额快照已过期，不能覆盖本地状态
+	ordersSeen := len(r.balances.GetOrders())
+	portfolio, err := r.balances.GetPortfolio(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local portfolio: %w", err)
+	}
+
+	accountBalances, err := r.client.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile balances: %w", err)
+	}
+
+	report.LocalCash = portfolio.Cash
+	report.LocalPosition = portfolio.Position
+	report.Cash = decimal.Zero
+	report.Position = decimal.Zero
+	for _, balance := range accountBalances {
+		switch balance.Asset {
+		case r.pair.Base:
+			report.Position = balance.Free.Add(balance.Locked)
+		case r.pair.Quote:
+			report.Cash = balance.Free.Add(balance.Locked)
+		}
+	}
+
+	report.BalanceDrift = r.drifted(report.LocalCash, report.Cash) || r.drifted(report.LocalPosition, report.Position)
+	// 挂单管理器中还有引擎尚未记录的成交（如本次对账发现的限价单成交），记录后再校正
+	if report.BalanceDrift && (r.orders == nil || !r.orders.hasUnreportedFills()) {
+		report.Repaired = r.balances.SyncBalances(report.Cash, report.Position, ordersSeen)
+	}
+	return nil
+}
+
+// drifted 本地值与交易所值的相对偏差是否超出容差
+func (r *Reconciler) drifted(local, exchange decimal.Decimal) bool {
+	diff := local.Sub(exchange).Abs()
+	if diff.IsZero() {
+		return false
+	}
+	scale := decimal.Max(local.Abs(), exchange.Abs())
+	return diff.GreaterThan(scale.Mul(r.tolerance))
+}
+
+// trackedOrders 本地跟踪的交易所挂单：限价单、移动止损单和 OCO 订单组（每组一条）
+func (m *LiveOrderManager) trackedOrders(pair cex.TradingPair) []TrackedOrder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tracked []TrackedOrder
+	seenGroups := make(map[string]bool)
+	ids := make([]string, 0, len(m.pendingOrders))
+	for id := range m.pendingOrders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		order := m.pendingOrders[id]
+		if order.TradingPair != pair {
+			continue
+		}
+		if exchangeID, ok := m.stopOrderIDs[id]; ok {
+			tracked = append(tracked, TrackedOrder{LocalID: id, ExchangeID: exchangeID, Order: order})
+			continue
+		}
+		if limit, ok := m.limitOrders[id]; ok {
+			tracked = append(tracked, TrackedOrder{LocalID: id, ExchangeID: limit.exchangeID, Order: order})
+			continue
+		}
+		if listID, ok := m.ocoListIDs[order.GroupID]; ok && order.GroupID != "" && !seenGroups[order.GroupID] {
+			seenGroups[order.GroupID] = true
+			tracked = append(tracked, TrackedOrder{LocalID: id, ListID: listID, GroupID: order.GroupID, Order: order})
+		}
+	}
+	return tracked
+}
+
+// forgetClosedOrder 移除交易所已成交或已撤销的挂单（不调用交易所接口），OCO 移除整组；
+// 限价单尚未返回给引擎的成交在下次检查挂单时返回
+func (m *LiveOrderManager) forgetClosedOrder(closed OrderDiscrepancy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tracked := closed.TrackedOrder
+
+	if tracked.GroupID != "" {
+		for id, order := range m.pendingOrders {
+			if order.GroupID == tracked.GroupID {
+				delete(m.pendingOrders, id)
+			}
+		}
+		delete(m.ocoListIDs, tracked.GroupID)
+		return
+	}
+
+	if limit, ok := m.limitOrders[tracked.LocalID]; ok {
+		if limit.exchangeID != tracked.ExchangeID {
+			return
+		}
+		if quantity, price, ok := limit.newFill(closed.Quantity, closed.Price); ok {
+			m.limitFills = append(m.limitFills, limitOrderFill(tracked.Order, tracked.ExchangeID, quantity, price, time.Now()))
+		}
+		delete(m.pendingOrders, tracked.LocalID)
+		delete(m.limitOrders, tracked.LocalID)
+		return
+	}
+
+	// 对账期间移动止损可能已重挂为新订单，此时保留本地挂单
+	if m.stopOrderIDs[tracked.LocalID] != tracked.ExchangeID {
+		return
+	}
+	delete(m.pendingOrders, tracked.LocalID)
+	delete(m.stopOrderIDs, tracked.LocalID)
+}
+
+// hasUnreportedFills 是否有尚未返回给引擎的成交
+func (m *LiveOrderManager) hasUnreportedFills() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.marketFills)+len(m.limitFills)+len(m.streamFills) > 0
+}
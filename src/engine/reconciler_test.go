@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReconcileCEXClient 支持挂单查询的CEX客户端mock
+type mockReconcileCEXClient struct {
+	MockCEXClient
+	open     []*cex.OrderResult
+	orders   map[string]*cex.OrderResult
+	balances []*cex.AccountBalance
+}
+
+func (m *mockReconcileCEXClient) GetOpenOrders(ctx context.Context, pair cex.TradingPair) ([]*cex.OrderResult, error) {
+	return m.open, nil
+}
+
+func (m *mockReconcileCEXClient) GetOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*cex.OrderResult, error) {
+	return m.orders[orderID], nil
+}
+
+func (m *mockReconcileCEXClient) GetAccount(ctx context.Context) ([]*cex.AccountBalance, error) {
+	return m.balances, nil
+}
+
+func newReconcileTestSetup(client *mockReconcileCEXClient) (*LiveOrderManager, *executor.TradingExecutor, *Reconciler) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewLiveOrderManager(client)
+	tradingExecutor := executor.NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	return manager, tradingExecutor, NewReconciler(client, pair, manager, tradingExecutor, 0.001)
+}
+
+func TestReconciler_RepairsOrdersAndBalances(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	client := &mockReconcileCEXClient{
+		open: []*cex.OrderResult{
+			{OrderID: "201", OrderListID: "7"}, // OCO 两腿仍在挂单
+			{OrderID: "202", OrderListID: "7"},
+			{OrderID: "300"}, // 手动挂出的订单
+		},
+		orders: map[string]*cex.OrderResult{
+			"100": {OrderID: "100", Status: cex.OrderStatusFilled, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(95)},
+		},
+		balances: []*cex.AccountBalance{
+			{Asset: "USDT", Free: decimal.NewFromInt(400), Locked: decimal.NewFromInt(100)},
+			{Asset: "BTC", Free: decimal.Zero},
+		},
+	}
+	manager, tradingExecutor, reconciler := newReconcileTestSetup(client)
+
+	// 移动止损单离线期间已触发成交
+	manager.pendingOrders["trail_1"] = &PendingOrder{ID: "trail_1", TradingPair: pair, Type: PendingOrderTypeTrailingStop}
+	manager.stopOrderIDs["trail_1"] = "100"
+	manager.pendingOrders["tp_1"] = &PendingOrder{ID: "tp_1", TradingPair: pair, GroupID: "oco_1"}
+	manager.pendingOrders["sl_1"] = &PendingOrder{ID: "sl_1", TradingPair: pair, GroupID: "oco_1"}
+	manager.ocoListIDs["oco_1"] = "7"
+
+	report, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.HasDiscrepancies())
+
+	require.Len(t, report.ClosedOrders, 1)
+	assert.Equal(t, "trail_1", report.ClosedOrders[0].LocalID)
+	assert.Equal(t, cex.OrderStatusFilled, report.ClosedOrders[0].Status)
+	assert.Equal(t, 2, manager.GetOrderCount())
+	assert.NotContains(t, manager.stopOrderIDs, "trail_1")
+
+	require.Len(t, report.UnknownOrders, 1)
+	assert.Equal(t, "300", report.UnknownOrders[0].OrderID)
+
+	assert.True(t, report.BalanceDrift)
+	assert.True(t, report.Repaired)
+	portfolio, err := tradingExecutor.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(500).Equal(portfolio.Cash))
+	assert.True(t, portfolio.Position.IsZero())
+	assert.Same(t, report, reconciler.LastReport())
+}
+
+func TestReconciler_ClosedOCOGroupAndTolerance(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	client := &mockReconcileCEXClient{
+		balances: []*cex.AccountBalance{{Asset: "USDT", Free: decimal.NewFromFloat(9995)}},
+	}
+	manager, _, reconciler := newReconcileTestSetup(client)
+
+	manager.pendingOrders["tp_1"] = &PendingOrder{ID: "tp_1", TradingPair: pair, GroupID: "oco_1"}
+	manager.pendingOrders["sl_1"] = &PendingOrder{ID: "sl_1", TradingPair: pair, GroupID: "oco_1"}
+	manager.ocoListIDs["oco_1"] = "7"
+	// 重挂失败的移动止损不参与核对
+	manager.pendingOrders["trail_1"] = &PendingOrder{ID: "trail_1", TradingPair: pair, Type: PendingOrderTypeTrailingStop}
+	manager.stopOrderIDs["trail_1"] = ""
+
+	report, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, report.ClosedOrders, 1)
+	assert.Equal(t, "7", report.ClosedOrders[0].ListID)
+	assert.Equal(t, 1, manager.GetOrderCount())
+	assert.Empty(t, manager.ocoListIDs)
+
+	// 偏差 0.05% 在容差内
+	assert.False(t, report.BalanceDrift)
+	assert.False(t, report.Repaired)
+}
+
+func TestReconciler_ClosedLimitOrderReturnsFill(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	client := &mockReconcileCEXClient{
+		orders: map[string]*cex.OrderResult{
+			"400": {OrderID: "400", Status: cex.OrderStatusFilled, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(99)},
+		},
+		balances: []*cex.AccountBalance{
+			{Asset: "USDT", Free: decimal.NewFromInt(9901)},
+			{Asset: "BTC", Free: decimal.NewFromInt(1)},
+		},
+	}
+	manager, _, reconciler := newReconcileTestSetup(client)
+
+	// 轮询已返回 0.4 @ 100，剩余部分在对账前成交
+	manager.pendingOrders["buy_1"] = &PendingOrder{ID: "buy_1", TradingPair: pair, Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromFloat(0.6)}
+	manager.limitOrders["buy_1"] = &liveLimitOrder{exchangeID: "400"}
+	manager.limitOrders["buy_1"].recordFill(decimal.NewFromFloat(0.4), decimal.NewFromInt(100))
+
+	report, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.ClosedOrders, 1)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	// 成交尚未交给引擎记录，余额暂不校正
+	assert.True(t, report.BalanceDrift)
+	assert.False(t, report.Repaired)
+
+	executed, err := manager.CheckAndExecuteOrders(context.Background(), &cex.KlineData{TradingPair: pair})
+	require.NoError(t, err)
+	require.Len(t, executed, 1)
+	assert.Equal(t, executor.OrderSideBuy, executed[0].Side)
+	assert.Equal(t, "0.6", executed[0].Quantity.String())
+	assert.Equal(t, "98.3333333333333333", executed[0].Price.String())
+	assert.Equal(t, "buy_1", executed[0].ClientOrderID)
+}
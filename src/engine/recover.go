@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// PanicError 策略、挂单管理器或引擎循环 panic 恢复后的错误，保留 panic 值和调用栈
+type PanicError struct {
+	Op    string // 发生 panic 的操作
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Op, e.Value)
+}
+
+// IsPanic 错误是否由 panic 恢复而来
+func IsPanic(err error) bool {
+	var panicErr *PanicError
+	return errors.As(err, &panicErr)
+}
+
+// panicError 把 recover() 的返回值转换为 *PanicError 并记录调用栈（recovered 为 nil 时返回 nil）
+func (e *TradingEngine) panicError(ctx context.Context, op string, recovered any) error {
+	if recovered == nil {
+		return nil
+	}
+	panicErr := &PanicError{Op: op, Value: recovered, Stack: debug.Stack()}
+	_, logger := log.WithCtx(ctx)
+	logger.Error(fmt.Sprintf("🔥 %s panic 已恢复: %v\n%s", op, recovered, panicErr.Stack))
+	return panicErr
+}
+
+// callStrategy 调用策略 OnData，策略 panic 时恢复并返回 *PanicError，本根K线不产生信号
+func (e *TradingEngine) callStrategy(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) (signals []*strategy.Signal, err error) {
+	defer func() {
+		if panicErr := e.panicError(ctx, "策略执行", recover()); panicErr != nil {
+			signals, err = nil, panicErr
+		}
+	}()
+	return e.strategy.OnData(ctx, kline, portfolio)
+}
+
+// checkOrders 撮合挂单，挂单管理器 panic 时恢复并返回 *PanicError
+func (e *TradingEngine) checkOrders(ctx context.Context, kline *cex.KlineData) (executed []*executor.OrderResult, err error) {
+	defer func() {
+		if panicErr := e.panicError(ctx, "挂单撮合", recover()); panicErr != nil {
+			executed, err = nil, panicErr
+		}
+	}()
+	return e.orderManager.CheckAndExecuteOrders(ctx, kline)
+}
+
+// handleSignal 处理交易信号（生成挂单或只记录信号），下单过程 panic 时恢复并返回 *PanicError
+func (e *TradingEngine) handleSignal(ctx context.Context, signal *strategy.Signal, kline *cex.KlineData, portfolio *executor.Portfolio) (err error) {
+	defer func() {
+		if panicErr := e.panicError(ctx, "处理交易信号", recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+	if e.signalOnly {
+		return e.recordSignalOnly(ctx, signal, kline, portfolio)
+	}
+	return e.processSignal(ctx, signal, kline, portfolio)
+}
+
+// runKline 处理一根K线并保存策略状态和回测断点，其余环节 panic 时恢复并返回 *PanicError，引擎随后退出（调用方需持有 e.mu）
+func (e *TradingEngine) runKline(ctx context.Context, kline *cex.KlineData, klineCount int) (err error) {
+	defer func() {
+		if panicErr := e.panicError(ctx, "K线处理", recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+	e.processKline(ctx, kline)
+	e.saveStrategyState(ctx)
+	e.writeCheckpoint(ctx, kline, klineCount)
+	return nil
+}
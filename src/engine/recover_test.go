@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingStrategy 第 panicAt 次调用 OnData 时 panic
+type panickingStrategy struct {
+	mockTradingStrategy
+	panicAt int
+}
+
+func (s *panickingStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	if s.onDataCalls+1 == s.panicAt {
+		s.onDataCalls++
+		var indicators map[string]float64
+		indicators["bb_upper"] = 1 // nil map 写入
+	}
+	return s.mockTradingStrategy.OnData(ctx, kline, portfolio)
+}
+
+// panickingOrderManager 撮合挂单时 panic
+type panickingOrderManager struct {
+	mockTradingOrderManager
+}
+
+func (m *panickingOrderManager) CheckAndExecuteOrders(ctx context.Context, kline *cex.KlineData) ([]*executor.OrderResult, error) {
+	m.checkCallCount++
+	panic("order book corrupted")
+}
+
+// panickingDataFeed 第二次获取K线时 panic
+type panickingDataFeed struct {
+	mockTradingDataFeed
+}
+
+func (f *panickingDataFeed) GetNext(ctx context.Context) (*cex.KlineData, error) {
+	if f.currentIdx == 1 {
+		panic("feed decoder failed")
+	}
+	return f.mockTradingDataFeed.GetNext(ctx)
+}
+
+func subscribeAll(bus *EventBus) *[]*Event {
+	var events []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event) })
+	return &events
+}
+
+func TestTradingEngine_Run_RecoversStrategyPanic(t *testing.T) {
+	klines := CreateTestKlines(3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 4*time.Hour)
+	strat := &panickingStrategy{panicAt: 2}
+	engine := createTestTradingEngineWithMocks(strat,
+		newMockOrderExecutor(decimal.NewFromInt(1000), decimal.Zero),
+		&mockTradingDataFeed{klines: klines},
+		&mockTradingOrderManager{})
+	bus := NewEventBus()
+	events := subscribeAll(bus)
+	engine.SetEventBus(bus)
+
+	// 策略 panic 不终止引擎，跳过这根K线继续处理
+	require.NoError(t, engine.Run(context.Background()))
+	assert.Equal(t, 3, strat.onDataCalls)
+	assert.Len(t, engine.GetEquityCurve(), 3)
+
+	var errorEvents []*Event
+	for _, event := range *events {
+		if event.Type == EventError {
+			errorEvents = append(errorEvents, event)
+		}
+	}
+	require.Len(t, errorEvents, 1)
+	assert.True(t, IsPanic(errorEvents[0].Err))
+	var panicErr *PanicError
+	require.ErrorAs(t, errorEvents[0].Err, &panicErr)
+	assert.Equal(t, "策略执行", panicErr.Op)
+	assert.Contains(t, string(panicErr.Stack), "panickingStrategy")
+}
+
+func TestTradingEngine_Run_RecoversOrderManagerPanic(t *testing.T) {
+	klines := CreateTestKlines(2, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 4*time.Hour)
+	strat := &mockTradingStrategy{}
+	orderManager := &panickingOrderManager{}
+	engine := createTestTradingEngineWithMocks(strat,
+		newMockOrderExecutor(decimal.NewFromInt(1000), decimal.Zero),
+		&mockTradingDataFeed{klines: klines},
+		orderManager)
+	bus := NewEventBus()
+	events := subscribeAll(bus)
+	engine.SetEventBus(bus)
+
+	require.NoError(t, engine.Run(context.Background()))
+	assert.Equal(t, 2, orderManager.checkCallCount)
+	assert.Equal(t, 2, strat.onDataCalls, "strategy still runs after order manager panic")
+
+	errors := 0
+	for _, event := range *events {
+		if event.Type == EventError {
+			errors++
+			assert.Contains(t, event.Err.Error(), "order book corrupted")
+		}
+	}
+	assert.Equal(t, 2, errors)
+}
+
+func TestTradingEngine_Run_ReturnsPanicErrorFromLoop(t *testing.T) {
+	klines := CreateTestKlines(3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 4*time.Hour)
+	feed := &panickingDataFeed{mockTradingDataFeed{klines: klines}}
+	engine := createTestTradingEngineWithMocks(&mockTradingStrategy{},
+		newMockOrderExecutor(decimal.NewFromInt(1000), decimal.Zero),
+		feed,
+		&mockTradingOrderManager{})
+	bus := NewEventBus()
+	events := subscribeAll(bus)
+	engine.SetEventBus(bus)
+
+	err := engine.Run(context.Background())
+	require.Error(t, err)
+	assert.True(t, IsPanic(err))
+	assert.Contains(t, err.Error(), "feed decoder failed")
+	assert.False(t, engine.isRunning.Load())
+	assert.True(t, feed.stopped)
+
+	last := (*events)[len(*events)-1]
+	assert.Equal(t, EventEngineStopped, last.Type)
+	assert.Equal(t, err.Error(), last.Message)
+
+	// 引擎可以再次运行（由上层重启）
+	feed.stopped = false
+	assert.Error(t, engine.Run(context.Background()))
+}
@@ -0,0 +1,608 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+var (
+	// ErrRiskLimitExceeded 挂单超出持仓市值或交易对敞口限制
+	ErrRiskLimitExceeded = errors.New("risk limit exceeded")
+
+	// ErrTradingHalted 风控熔断后停止交易
+	ErrTradingHalted = errors.New("trading halted by risk manager")
+
+	// ErrDailyLossLimit 当日亏损超限，暂停开仓到下一个 UTC 日
+	ErrDailyLossLimit = errors.New("daily loss limit reached")
+
+	// ErrDrawdownLimit 权益从峰值回撤超限，暂停开仓
+	ErrDrawdownLimit = errors.New("drawdown limit reached")
+
+	// ErrEntriesPaused 手动暂停开仓
+	ErrEntriesPaused = errors.New("entries paused manually")
+)
+
+// 清仓卖单的来源标记
+const (
+	OriginDrawdownFlatten = "DRAWDOWN_FLATTEN" // 回撤超限清仓
+	OriginManualFlatten   = "MANUAL_FLATTEN"   // 手动清仓
+)
+
+// RiskEventType 风控状态变化类型
+type RiskEventType string
+
+const (
+	RiskEventHalted      RiskEventType = "HALTED"       // 熔断：撤销全部挂单并停止交易，需重启恢复
+	RiskEventDailyPaused RiskEventType = "DAILY_PAUSED" // 当日亏损超限：撤销开仓挂单并暂停开仓
+	RiskEventResumed     RiskEventType = "RESUMED"      // 跨过 UTC 0 点，恢复开仓
+	RiskEventDrawdown    RiskEventType = "DRAWDOWN"     // 权益从峰值回撤超限，按 DrawdownAction 处理
+	RiskEventRecovered   RiskEventType = "RECOVERED"    // 回撤回到阈值以内，恢复开仓
+
+	RiskEventManualPaused  RiskEventType = "MANUAL_PAUSED"  // 手动暂停开仓
+	RiskEventManualResumed RiskEventType = "MANUAL_RESUMED" // 手动恢复开仓
+	RiskEventManualFlatten RiskEventType = "MANUAL_FLATTEN" // 手动清仓（同时暂停开仓）
+)
+
+// 回撤超限时的处理方式
+const (
+	DrawdownActionNotify  = "notify"  // 只发通知
+	DrawdownActionPause   = "pause"   // 撤销开仓挂单并暂停开仓，回撤回到阈值以内后恢复
+	DrawdownActionFlatten = "flatten" // 撤销全部挂单、市价卖出全部持仓并暂停开仓
+)
+
+// RiskEvent 风控状态变化
+type RiskEvent struct {
+	Type     RiskEventType
+	Reason   string
+	DailyPnL decimal.Decimal // 当日盈亏（已实现 + 未实现）
+	Time     time.Time
+
+	// 回撤事件：当前权益相对峰值的回撤比例（0.2 表示 20%）和处理方式
+	Drawdown       decimal.Decimal
+	DrawdownAction string
+}
+
+// RiskLimits 全局风控限制（0 表示不限制）
+type RiskLimits struct {
+	MaxPositionValue     float64          `json:"max_position_value"`     // 最大持仓市值（计价资产）
+	MaxDailyLoss         float64          `json:"max_daily_loss"`         // 单日（UTC）最大亏损（计价资产，已实现 + 未实现），超出后暂停开仓到下一个 UTC 日
+	MaxDailyLossPercent  float64          `json:"max_daily_loss_percent"` // 单日最大亏损占当日起始权益的比例（0.05 表示 5%）
+	MaxConsecutiveLosses int              `json:"max_consecutive_losses"` // 最大连续亏损交易次数，达到后熔断
+	MaxSymbolExposure    float64          `json:"max_symbol_exposure"`    // 单个交易对持仓市值占组合价值的比例上限（0.5 表示 50%）
+	SymbolExposure       []SymbolExposure `json:"symbol_exposure"`        // 按交易对覆盖敞口上限
+	MaxDrawdownPercent   float64          `json:"max_drawdown_percent"`   // 权益从峰值回撤的比例上限（0.2 表示 20%）
+	DrawdownAction       string           `json:"drawdown_action"`        // 回撤超限时的处理：notify/pause/flatten，默认 notify
+}
+
+// SymbolExposure 单个交易对的敞口上限
+type SymbolExposure struct {
+	Pair        string  `json:"pair"`         // 交易对，格式 BASE/QUOTE（如 PEPE/USDT）
+	MaxExposure float64 `json:"max_exposure"` // 持仓市值占组合价值的比例上限
+}
+
+// Validate 检查风控配置
+func (l RiskLimits) Validate() error {
+	if l.MaxPositionValue < 0 || l.MaxDailyLoss < 0 || l.MaxConsecutiveLosses < 0 {
+		return fmt.Errorf("risk limits must be non-negative, got Risk.MaxPositionValue=%v Risk.MaxDailyLoss=%v Risk.MaxConsecutiveLosses=%d",
+			l.MaxPositionValue, l.MaxDailyLoss, l.MaxConsecutiveLosses)
+	}
+	if l.MaxDailyLossPercent < 0 || l.MaxDailyLossPercent > 1 {
+		return fmt.Errorf("Risk.MaxDailyLossPercent must be in [0, 1], got %v", l.MaxDailyLossPercent)
+	}
+	if l.MaxSymbolExposure < 0 || l.MaxSymbolExposure > 1 {
+		return fmt.Errorf("Risk.MaxSymbolExposure must be in [0, 1], got %v", l.MaxSymbolExposure)
+	}
+	if l.MaxDrawdownPercent < 0 || l.MaxDrawdownPercent > 1 {
+		return fmt.Errorf("Risk.MaxDrawdownPercent must be in [0, 1], got %v", l.MaxDrawdownPercent)
+	}
+	switch l.DrawdownAction {
+	case "", DrawdownActionNotify, DrawdownActionPause, DrawdownActionFlatten:
+	default:
+		return fmt.Errorf("Risk.DrawdownAction must be notify, pause or flatten, got %q", l.DrawdownAction)
+	}
+	for _, symbol := range l.SymbolExposure {
+		if !strings.Contains(symbol.Pair, "/") {
+			return fmt.Errorf("Risk.SymbolExposure pair must be BASE/QUOTE, got %q", symbol.Pair)
+		}
+		if symbol.MaxExposure < 0 || symbol.MaxExposure > 1 {
+			return fmt.Errorf("Risk.SymbolExposure for %s must be in [0, 1], got %v", symbol.Pair, symbol.MaxExposure)
+		}
+	}
+	return nil
+}
+
+// Enabled 是否配置了任一限制
+func (l RiskLimits) Enabled() bool {
+	return l.MaxPositionValue > 0 || l.MaxDailyLoss > 0 || l.MaxDailyLossPercent > 0 || l.MaxConsecutiveLosses > 0 ||
+		l.MaxSymbolExposure > 0 || len(l.SymbolExposure) > 0 || l.MaxDrawdownPercent > 0
+}
+
+// Describe 已配置的限制（未配置时返回空字符串）
+func (l RiskLimits) Describe() string {
+	var parts []string
+	if l.MaxPositionValue > 0 {
+		parts = append(parts, fmt.Sprintf("MaxPositionValue=%v", l.MaxPositionValue))
+	}
+	if l.MaxDailyLoss > 0 {
+		parts = append(parts, fmt.Sprintf("MaxDailyLoss=%v", l.MaxDailyLoss))
+	}
+	if l.MaxDailyLossPercent > 0 {
+		parts = append(parts, fmt.Sprintf("MaxDailyLossPercent=%v", l.MaxDailyLossPercent))
+	}
+	if l.MaxConsecutiveLosses > 0 {
+		parts = append(parts, fmt.Sprintf("MaxConsecutiveLosses=%d", l.MaxConsecutiveLosses))
+	}
+	if l.MaxSymbolExposure > 0 {
+		parts = append(parts, fmt.Sprintf("MaxSymbolExposure=%v", l.MaxSymbolExposure))
+	}
+	for _, symbol := range l.SymbolExposure {
+		parts = append(parts, fmt.Sprintf("exposure[%s]=%v", symbol.Pair, symbol.MaxExposure))
+	}
+	if l.MaxDrawdownPercent > 0 {
+		parts = append(parts, fmt.Sprintf("MaxDrawdownPercent=%v (%s)", l.MaxDrawdownPercent, l.drawdownAction()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// drawdownAction 回撤超限时的处理方式（未配置时只通知）
+func (l RiskLimits) drawdownAction() string {
+	if l.DrawdownAction == "" {
+		return DrawdownActionNotify
+	}
+	return l.DrawdownAction
+}
+
+// exposureFor 交易对的敞口上限（0 表示不限制）
+func (l RiskLimits) exposureFor(pair cex.TradingPair) float64 {
+	for _, symbol := range l.SymbolExposure {
+		if symbol.Pair == pair.String() {
+			return symbol.MaxExposure
+		}
+	}
+	return l.MaxSymbolExposure
+}
+
+// RiskManager 全局风控：下单前检查持仓市值和交易对敞口；
+// 当日亏损（已实现 + 未实现）超限时暂停开仓，跨过 UTC 0 点自动恢复；
+// 权益从峰值回撤超限时按配置通知、暂停开仓或清仓；
+// 连续亏损超限时熔断（撤销全部挂单并停止交易，需人工处理后重启）
+type RiskManager struct {
+	limits RiskLimits
+
+	mu sync.Mutex
+
+	// 持仓成本（按成交回报计算已实现盈亏）
+	position  decimal.Decimal
+	costBasis decimal.Decimal // 持仓总成本（含买入手续费）
+
+	day               time.Time       // 当前统计日（UTC 0 点）
+	dayStartEquity    decimal.Decimal // 当日起始权益（上一日最后一次评估时的组合价值）
+	dailyRealizedPnL  decimal.Decimal // 当日已实现盈亏
+	consecutiveLosses int
+	dailyPaused       bool // 当日亏损超限，暂停开仓
+
+	peakEquity       decimal.Decimal // 启动以来的最高权益
+	drawdownBreached bool            // 回撤超过阈值，未回到阈值以内前不重复告警
+
+	manualPaused bool // 手动暂停开仓，手动恢复前一直生效
+
+	// 最近一次评估时的投资组合，用于下单前检查
+	cash  decimal.Decimal
+	held  decimal.Decimal
+	price decimal.Decimal
+
+	halted     bool
+	haltReason string
+}
+
+// NewRiskManager 创建风控管理器
+func NewRiskManager(limits RiskLimits) *RiskManager {
+	return &RiskManager{limits: limits}
+}
+
+// Limits 当前风控限制
+func (m *RiskManager) Limits() RiskLimits {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.limits
+}
+
+// SetLimits 运行中更新风控限制（实盘热更新配置），持仓成本、当日盈亏和连续亏损次数保留，
+// 新限制从下一次评估开始生效；已熔断的状态不会因放宽限制而解除
+func (m *RiskManager) SetLimits(limits RiskLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limits = limits
+	return nil
+}
+
+// IsHalted 是否已熔断，返回熔断原因
+func (m *RiskManager) IsHalted() (bool, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.halted, m.haltReason
+}
+
+// DailyRealizedPnL 当日已实现盈亏
+func (m *RiskManager) DailyRealizedPnL() decimal.Decimal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dailyRealizedPnL
+}
+
+// DailyPnL 当日盈亏（已实现 + 未实现），即组合价值相对当日起始权益的变化
+func (m *RiskManager) DailyPnL() decimal.Decimal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dailyPnLLocked()
+}
+
+// IsDailyPaused 是否因当日亏损超限暂停开仓
+func (m *RiskManager) IsDailyPaused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dailyPaused
+}
+
+// Drawdown 当前权益相对峰值的回撤金额和比例（0.2 表示 20%）
+func (m *RiskManager) Drawdown() (decimal.Decimal, decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peakEquity.Sub(m.equityLocked()), m.drawdownPercentLocked()
+}
+
+// PeakEquity 启动以来的最高权益
+func (m *RiskManager) PeakEquity() decimal.Decimal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peakEquity
+}
+
+// IsDrawdownPaused 是否因回撤超限暂停开仓（DrawdownAction 为 notify 时不暂停）
+func (m *RiskManager) IsDrawdownPaused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.drawdownPausedLocked()
+}
+
+// SetEntriesPaused 手动暂停或恢复开仓，状态变化时返回事件（无变化返回 nil）
+func (m *RiskManager) SetEntriesPaused(paused bool, reason string, now time.Time) *RiskEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if paused == m.manualPaused {
+		return nil
+	}
+	m.manualPaused = paused
+	if paused {
+		return m.eventLocked(RiskEventManualPaused, reason, now)
+	}
+	return m.eventLocked(RiskEventManualResumed, reason, now)
+}
+
+// IsManuallyPaused 是否手动暂停开仓
+func (m *RiskManager) IsManuallyPaused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.manualPaused
+}
+
+// ConsecutiveLosses 当前连续亏损次数
+func (m *RiskManager) ConsecutiveLosses() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.consecutiveLosses
+}
+
+// Update 记录本根K线的成交并更新投资组合快照，风控状态变化时返回事件（无变化返回 nil）
+func (m *RiskManager) Update(executed []*executor.OrderResult, portfolio *executor.Portfolio, price decimal.Decimal, now time.Time) *RiskEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	resumed := m.rollDayLocked(now, portfolio, price)
+	for _, result := range executed {
+		if result != nil && result.Success && result.Quantity.IsPositive() {
+			m.recordFillLocked(result)
+		}
+	}
+	m.cash, m.held, m.price = portfolio.Cash, portfolio.Position, price
+	if equity := m.equityLocked(); equity.GreaterThan(m.peakEquity) {
+		m.peakEquity = equity
+	}
+
+	if m.halted {
+		return nil
+	}
+	if m.limits.MaxConsecutiveLosses > 0 && m.consecutiveLosses >= m.limits.MaxConsecutiveLosses {
+		m.halted = true
+		m.haltReason = fmt.Sprintf("%d consecutive losing trades (limit %d)", m.consecutiveLosses, m.limits.MaxConsecutiveLosses)
+		return m.eventLocked(RiskEventHalted, m.haltReason, now)
+	}
+	if event := m.checkDrawdownLocked(now); event != nil {
+		return event
+	}
+	if !m.dailyPaused {
+		if reason := m.dailyLossBreachLocked(); reason != "" {
+			m.dailyPaused = true
+			return m.eventLocked(RiskEventDailyPaused, reason, now)
+		}
+	}
+	if resumed {
+		return m.eventLocked(RiskEventResumed, "new UTC day", now)
+	}
+	return nil
+}
+
+// CheckOrder 下单前检查：熔断后拒绝所有挂单，买单不能使持仓市值或交易对敞口超限
+func (m *RiskManager) CheckOrder(order *PendingOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.halted {
+		return fmt.Errorf("%w: %s", ErrTradingHalted, m.haltReason)
+	}
+	if order.side() != cex.OrderSideBuy {
+		return nil
+	}
+	if m.manualPaused {
+		return ErrEntriesPaused
+	}
+	if m.dailyPaused {
+		return fmt.Errorf("%w: daily pnl %s, entries paused until next UTC day", ErrDailyLossLimit, m.dailyPnLLocked().StringFixed(2))
+	}
+	if m.drawdownPausedLocked() {
+		return fmt.Errorf("%w: drawdown %s%% from peak %s, entries paused", ErrDrawdownLimit,
+			m.drawdownPercentLocked().Mul(decimal.NewFromInt(100)).StringFixed(1), m.peakEquity.StringFixed(2))
+	}
+
+	price := m.price
+	if price.IsZero() {
+		price = order.Price
+	}
+	positionValue := m.held.Mul(price).Add(order.Quantity.Mul(order.Price))
+	if m.limits.MaxPositionValue > 0 && positionValue.GreaterThan(decimal.NewFromFloat(m.limits.MaxPositionValue)) {
+		return fmt.Errorf("%w: position value %s would exceed max %v", ErrRiskLimitExceeded, positionValue.StringFixed(2), m.limits.MaxPositionValue)
+	}
+
+	// 买入只是现金换持仓，组合价值不变
+	portfolioValue := m.cash.Add(m.held.Mul(price))
+	if exposure := m.limits.exposureFor(order.TradingPair); exposure > 0 && portfolioValue.IsPositive() {
+		if positionValue.GreaterThan(portfolioValue.Mul(decimal.NewFromFloat(exposure))) {
+			return fmt.Errorf("%w: %s exposure %s/%s would exceed %.0f%%", ErrRiskLimitExceeded,
+				order.TradingPair.String(), positionValue.StringFixed(2), portfolioValue.StringFixed(2), exposure*100)
+		}
+	}
+	return nil
+}
+
+// rollDayLocked 跨过 UTC 0 点时重置当日盈亏并恢复开仓，返回是否从暂停中恢复（调用方需持有锁）
+func (m *RiskManager) rollDayLocked(now time.Time, portfolio *executor.Portfolio, price decimal.Decimal) bool {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if day.Equal(m.day) {
+		return false
+	}
+
+	// 首次评估时没有上一日快照，以当前组合价值为起点
+	startEquity := m.equityLocked()
+	if m.day.IsZero() {
+		startEquity = portfolio.Cash.Add(portfolio.Position.Mul(price))
+	}
+	m.day = day
+	m.dayStartEquity = startEquity
+	m.dailyRealizedPnL = decimal.Zero
+
+	resumed := m.dailyPaused
+	m.dailyPaused = false
+	return resumed
+}
+
+// equityLocked 最近一次评估时的组合价值（调用方需持有锁）
+func (m *RiskManager) equityLocked() decimal.Decimal {
+	return m.cash.Add(m.held.Mul(m.price))
+}
+
+// dailyPnLLocked 当日盈亏（调用方需持有锁）
+func (m *RiskManager) dailyPnLLocked() decimal.Decimal {
+	return m.equityLocked().Sub(m.dayStartEquity)
+}
+
+// dailyLossBreachLocked 当日亏损超过金额或比例上限时返回原因（调用方需持有锁）
+func (m *RiskManager) dailyLossBreachLocked() string {
+	pnl := m.dailyPnLLocked()
+	if m.limits.MaxDailyLoss > 0 && pnl.LessThanOrEqual(decimal.NewFromFloat(-m.limits.MaxDailyLoss)) {
+		return fmt.Sprintf("daily loss %s exceeds limit %v", pnl.Neg().StringFixed(2), m.limits.MaxDailyLoss)
+	}
+	if m.limits.MaxDailyLossPercent > 0 && m.dayStartEquity.IsPositive() {
+		limit := m.dayStartEquity.Mul(decimal.NewFromFloat(m.limits.MaxDailyLossPercent))
+		if pnl.LessThanOrEqual(limit.Neg()) {
+			return fmt.Sprintf("daily loss %s exceeds %.1f%% of day start equity %s",
+				pnl.Neg().StringFixed(2), m.limits.MaxDailyLossPercent*100, m.dayStartEquity.StringFixed(2))
+		}
+	}
+	return ""
+}
+
+// drawdownPercentLocked 当前权益相对峰值的回撤比例（调用方需持有锁）
+func (m *RiskManager) drawdownPercentLocked() decimal.Decimal {
+	if !m.peakEquity.IsPositive() {
+		return decimal.Zero
+	}
+	return m.peakEquity.Sub(m.equityLocked()).Div(m.peakEquity)
+}
+
+// drawdownPausedLocked 回撤超限且处理方式要求暂停开仓（调用方需持有锁）
+func (m *RiskManager) drawdownPausedLocked() bool {
+	return m.drawdownBreached && m.limits.drawdownAction() != DrawdownActionNotify
+}
+
+// checkDrawdownLocked 回撤越过阈值时返回回撤事件，回到阈值以内（或取消限制）时返回恢复事件（调用方需持有锁）
+func (m *RiskManager) checkDrawdownLocked(now time.Time) *RiskEvent {
+	drawdown := m.drawdownPercentLocked()
+	limit := decimal.NewFromFloat(m.limits.MaxDrawdownPercent)
+	breached := m.limits.MaxDrawdownPercent > 0 && drawdown.GreaterThanOrEqual(limit)
+	if breached == m.drawdownBreached {
+		return nil
+	}
+
+	m.drawdownBreached = breached
+	var event *RiskEvent
+	if breached {
+		event = m.eventLocked(RiskEventDrawdown, fmt.Sprintf("drawdown %s%% from peak equity %s exceeds %.1f%%",
+			drawdown.Mul(decimal.NewFromInt(100)).StringFixed(1), m.peakEquity.StringFixed(2), m.limits.MaxDrawdownPercent*100), now)
+	} else {
+		event = m.eventLocked(RiskEventRecovered, fmt.Sprintf("drawdown %s%% back within limit",
+			drawdown.Mul(decimal.NewFromInt(100)).StringFixed(1)), now)
+	}
+	event.Drawdown = drawdown
+	event.DrawdownAction = m.limits.drawdownAction()
+	return event
+}
+
+// eventLocked 生成风控事件（调用方需持有锁）
+func (m *RiskManager) eventLocked(eventType RiskEventType, reason string, now time.Time) *RiskEvent {
+	return &RiskEvent{Type: eventType, Reason: reason, DailyPnL: m.dailyPnLLocked(), Time: now}
+}
+
+// recordFillLocked 按平均成本计算卖出的已实现盈亏，更新当日盈亏和连续亏损次数（调用方需持有锁）
+func (m *RiskManager) recordFillLocked(result *executor.OrderResult) {
+	if result.Side == executor.OrderSideBuy {
+		m.position = m.position.Add(result.Quantity)
+		m.costBasis = m.costBasis.Add(result.Quantity.Mul(result.Price)).Add(result.Commission)
+		return
+	}
+
+	// 启动前已有的持仓没有成本记录，不计入盈亏
+	if !m.position.IsPositive() {
+		return
+	}
+	quantity := decimal.Min(result.Quantity, m.position)
+	cost := m.costBasis.Mul(quantity).Div(m.position)
+	pnl := quantity.Mul(result.Price).Sub(result.Commission).Sub(cost)
+
+	m.position = m.position.Sub(quantity)
+	m.costBasis = m.costBasis.Sub(cost)
+	m.dailyRealizedPnL = m.dailyRealizedPnL.Add(pnl)
+	if pnl.IsNegative() {
+		m.consecutiveLosses++
+	} else {
+		m.consecutiveLosses = 0
+	}
+}
+
+// SetRiskManager 设置风控管理器
+func (e *TradingEngine) SetRiskManager(manager *RiskManager) {
+	e.riskManager = manager
+}
+
+// updateRisk 每根K线更新风控状态：熔断时撤销全部挂单，当日亏损超限时撤销开仓挂单，
+// 回撤超限时按配置撤销开仓挂单或清仓
+func (e *TradingEngine) updateRisk(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) {
+	if e.riskManager == nil {
+		return
+	}
+	ctx, logger := log.WithCtx(ctx)
+
+	event := e.riskManager.Update(executed, portfolio, kline.Close, kline.CloseTime)
+	if event == nil {
+		return
+	}
+
+	switch event.Type {
+	case RiskEventHalted:
+		logger.Error(fmt.Sprintf("🛑 风控熔断，撤销全部挂单并停止交易: %s", event.Reason))
+		if err := e.orderManager.CancelAllOrders(ctx); err != nil {
+			logger.Error("熔断撤销挂单失败", "error", err)
+		}
+	case RiskEventDailyPaused:
+		logger.Error(fmt.Sprintf("⏸️ 当日亏损超限，撤销开仓挂单并暂停开仓到下一个 UTC 日: %s", event.Reason))
+		e.cancelEntryOrders(ctx)
+	case RiskEventResumed:
+		logger.Info(fmt.Sprintf("▶️ 新的 UTC 日，恢复开仓: daily_pnl=%s", event.DailyPnL.StringFixed(2)))
+	case RiskEventDrawdown:
+		logger.Error(fmt.Sprintf("📉 回撤超限 (action=%s): %s", event.DrawdownAction, event.Reason))
+		switch event.DrawdownAction {
+		case DrawdownActionPause:
+			e.cancelEntryOrders(ctx)
+		case DrawdownActionFlatten:
+			e.flattenPosition(ctx, kline, portfolio, OriginDrawdownFlatten, "drawdown limit: flatten position")
+		}
+	case RiskEventRecovered:
+		logger.Info(fmt.Sprintf("▶️ 回撤回到阈值以内: %s", event.Reason))
+	}
+
+	e.events.Publish(ctx, &Event{Type: EventRisk, Time: event.Time, TradingPair: e.tradingPair, Risk: event})
+}
+
+// cancelEntryOrders 撤销所有开仓（买入）挂单，保留止盈止损等平仓挂单
+func (e *TradingEngine) cancelEntryOrders(ctx context.Context) {
+	_, logger := log.WithCtx(ctx)
+
+	// 先收集再撤销，撤单会修改挂单列表
+	var ids []string
+	for _, order := range e.orderManager.GetPendingOrders() {
+		if order.side() == cex.OrderSideBuy {
+			ids = append(ids, order.ID)
+		}
+	}
+	for _, id := range ids {
+		if err := e.orderManager.CancelOrder(ctx, id); err != nil {
+			logger.Error("撤销开仓挂单失败", "order_id", id, "error", err)
+		}
+	}
+}
+
+// flattenPosition 撤销全部挂单（释放止盈止损占用的持仓）后市价卖出全部持仓，origin 为卖单来源标记
+func (e *TradingEngine) flattenPosition(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio, origin, reason string) {
+	_, logger := log.WithCtx(ctx)
+
+	if err := e.orderManager.CancelAllOrders(ctx); err != nil {
+		logger.Error("清仓撤销挂单失败", "error", err)
+	}
+	if !portfolio.Position.IsPositive() {
+		return
+	}
+
+	order := &PendingOrder{
+		ID:           generateShortOrderID("flat", e.tradingPair.Base),
+		Type:         PendingOrderTypeSellMarket,
+		TradingPair:  e.tradingPair,
+		Quantity:     portfolio.Position,
+		Price:        kline.Close,
+		CreateTime:   kline.OpenTime,
+		Reason:       reason,
+		OriginSignal: origin,
+	}
+	logger.Warning(fmt.Sprintf("🔴 清仓: origin=%s, order_id=%s, qty=%s, price=%s", origin, order.ID, order.Quantity.String(), kline.Close.String()))
+	if err := e.placeOrder(ctx, order); err != nil {
+		logger.Error("清仓卖单挂单失败", "error", err)
+		e.publishError(ctx, "清仓挂单失败", err)
+	}
+}
+
+// checkRisk 下单前风控检查，不通过时记录日志并返回 false
+func (e *TradingEngine) checkRisk(ctx context.Context, order *PendingOrder) bool {
+	if e.riskManager == nil {
+		return true
+	}
+	_, logger := log.WithCtx(ctx)
+
+	if err := e.riskManager.CheckOrder(order); err != nil {
+		logger.Warning(fmt.Sprintf("🚫 风控拒绝挂单: id=%s, type=%s, qty=%s, price=%s, reason=%v",
+			order.ID, order.Type, order.Quantity.String(), order.Price.String(), err))
+		return false
+	}
+	return true
+}
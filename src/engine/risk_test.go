@@ -0,0 +1,310 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelCountingOrderManager 记录撤销全部挂单次数的挂单管理器mock
+type cancelCountingOrderManager struct {
+	mockTradingOrderManager
+	cancelAllCount int
+}
+
+func (m *cancelCountingOrderManager) CancelAllOrders(ctx context.Context) error {
+	m.cancelAllCount++
+	return nil
+}
+
+func newRiskFill(side executor.OrderSide, quantity, price float64) *executor.OrderResult {
+	return &executor.OrderResult{
+		Side:     side,
+		Quantity: decimal.NewFromFloat(quantity),
+		Price:    decimal.NewFromFloat(price),
+		Success:  true,
+	}
+}
+
+func TestRiskLimits_Validate(t *testing.T) {
+	assert.NoError(t, RiskLimits{}.Validate())
+	assert.False(t, RiskLimits{}.Enabled())
+	assert.True(t, RiskLimits{MaxConsecutiveLosses: 3}.Enabled())
+
+	assert.Error(t, RiskLimits{MaxDailyLoss: -1}.Validate())
+	assert.Error(t, RiskLimits{MaxSymbolExposure: 1.5}.Validate())
+	assert.Error(t, RiskLimits{SymbolExposure: []SymbolExposure{{Pair: "PEPEUSDT", MaxExposure: 0.2}}}.Validate())
+}
+
+func TestRiskLimits_Describe(t *testing.T) {
+	assert.Empty(t, RiskLimits{}.Describe())
+	assert.Equal(t, "MaxPositionValue=1000, exposure[PEPE/USDT]=0.1, MaxDrawdownPercent=0.2 (notify)",
+		RiskLimits{MaxPositionValue: 1000, SymbolExposure: []SymbolExposure{{Pair: "PEPE/USDT", MaxExposure: 0.1}}, MaxDrawdownPercent: 0.2}.Describe())
+}
+
+func TestRiskManager_CheckOrderExposure(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewRiskManager(RiskLimits{
+		MaxPositionValue:  1000,
+		MaxSymbolExposure: 0.5,
+		SymbolExposure:    []SymbolExposure{{Pair: "PEPE/USDT", MaxExposure: 0.1}},
+	})
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1500), Position: decimal.NewFromInt(5)}
+	manager.Update(nil, portfolio, decimal.NewFromInt(100), time.Now())
+
+	buy := func(pair cex.TradingPair, quantity int64) *PendingOrder {
+		return &PendingOrder{TradingPair: pair, Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromInt(quantity), Price: decimal.NewFromInt(100)}
+	}
+
+	// 持仓 500 + 买入 400 = 900，组合价值 2000 的 45%
+	assert.NoError(t, manager.CheckOrder(buy(pair, 4)))
+	// 持仓 1100 超过 1000
+	assert.ErrorIs(t, manager.CheckOrder(buy(pair, 6)), ErrRiskLimitExceeded)
+	// 交易对单独限制 10%
+	assert.ErrorIs(t, manager.CheckOrder(buy(cex.TradingPair{Base: "PEPE", Quote: "USDT"}, 1)), ErrRiskLimitExceeded)
+	// 卖单不受敞口限制
+	sell := &PendingOrder{TradingPair: pair, Type: PendingOrderTypeSellLimit, Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(100)}
+	assert.NoError(t, manager.CheckOrder(sell))
+}
+
+func TestRiskManager_SetLimits(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewRiskManager(RiskLimits{})
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	manager.Update(nil, portfolio, decimal.NewFromInt(100), time.Now())
+	buy := &PendingOrder{TradingPair: pair, Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(100)}
+
+	assert.NoError(t, manager.CheckOrder(buy))
+
+	require.NoError(t, manager.SetLimits(RiskLimits{MaxPositionValue: 300}))
+	assert.Equal(t, 300.0, manager.Limits().MaxPositionValue)
+	assert.ErrorIs(t, manager.CheckOrder(buy), ErrRiskLimitExceeded)
+
+	// 无效限制不生效
+	assert.Error(t, manager.SetLimits(RiskLimits{MaxDailyLossPercent: 2}))
+	assert.Equal(t, 300.0, manager.Limits().MaxPositionValue)
+}
+
+func TestRiskManager_HaltsOnConsecutiveLosses(t *testing.T) {
+	manager := NewRiskManager(RiskLimits{MaxConsecutiveLosses: 2})
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	// 盈利交易重置连续亏损次数
+	manager.Update([]*executor.OrderResult{newRiskFill(executor.OrderSideBuy, 1, 100), newRiskFill(executor.OrderSideSell, 1, 90)}, portfolio, decimal.NewFromInt(90), now)
+	manager.Update([]*executor.OrderResult{newRiskFill(executor.OrderSideBuy, 1, 100), newRiskFill(executor.OrderSideSell, 1, 110)}, portfolio, decimal.NewFromInt(110), now)
+	assert.Equal(t, 0, manager.ConsecutiveLosses())
+	assert.True(t, manager.DailyRealizedPnL().IsZero())
+
+	event := manager.Update([]*executor.OrderResult{newRiskFill(executor.OrderSideBuy, 1, 100), newRiskFill(executor.OrderSideSell, 1, 95)}, portfolio, decimal.NewFromInt(95), now)
+	assert.Nil(t, event)
+	event = manager.Update([]*executor.OrderResult{newRiskFill(executor.OrderSideBuy, 1, 100), newRiskFill(executor.OrderSideSell, 1, 95)}, portfolio, decimal.NewFromInt(95), now)
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventHalted, event.Type)
+	assert.Contains(t, event.Reason, "consecutive")
+
+	// 熔断不会在次日自动恢复，卖单也被拒绝
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(95), now.Add(24*time.Hour)))
+	halted, _ := manager.IsHalted()
+	assert.True(t, halted)
+	sell := &PendingOrder{Type: PendingOrderTypeSellLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}
+	assert.True(t, errors.Is(manager.CheckOrder(sell), ErrTradingHalted))
+}
+
+func TestRiskManager_DailyLossPausesUntilNextUTCDay(t *testing.T) {
+	manager := NewRiskManager(RiskLimits{MaxDailyLoss: 50})
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buy := &PendingOrder{Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(80)}
+	sell := &PendingOrder{Type: PendingOrderTypeSellLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(80)}
+
+	// 当日起始权益 1000（现金 500 + 持仓 5 × 100）
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(500), Position: decimal.NewFromInt(5)}
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(100), day.Add(time.Hour)))
+
+	// 未实现亏损 40，未超限
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(92), day.Add(2*time.Hour)))
+	assert.Equal(t, "-40", manager.DailyPnL().String())
+
+	// 未实现亏损 60，暂停开仓，平仓不受影响
+	event := manager.Update(nil, portfolio, decimal.NewFromInt(88), day.Add(3*time.Hour))
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventDailyPaused, event.Type)
+	assert.Equal(t, "-60", event.DailyPnL.String())
+	assert.ErrorIs(t, manager.CheckOrder(buy), ErrDailyLossLimit)
+	assert.NoError(t, manager.CheckOrder(sell))
+
+	// 当日继续亏损不重复通知
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(80), day.Add(20*time.Hour)))
+
+	// 次日以上一日最后的权益为起点，恢复开仓
+	event = manager.Update(nil, portfolio, decimal.NewFromInt(80), day.Add(25*time.Hour))
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventResumed, event.Type)
+	assert.True(t, manager.DailyPnL().IsZero())
+	assert.NoError(t, manager.CheckOrder(buy))
+}
+
+func TestRiskManager_DailyLossPercent(t *testing.T) {
+	manager := NewRiskManager(RiskLimits{MaxDailyLossPercent: 0.05})
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(100), day))
+
+	// 卖出亏损（已实现）使权益降到 940
+	portfolio = &executor.Portfolio{Cash: decimal.NewFromInt(940)}
+	event := manager.Update(nil, portfolio, decimal.NewFromInt(100), day.Add(time.Hour))
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventDailyPaused, event.Type)
+}
+
+func TestTradingEngine_RiskKillSwitch(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &cancelCountingOrderManager{}
+	bus := NewEventBus()
+	var events []*RiskEvent
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event.Risk) }, EventRisk)
+	engine := &TradingEngine{
+		orderManager:        orderManager,
+		tradingPair:         pair,
+		positionSizePercent: decimal.NewFromFloat(0.5),
+		minTradeAmount:      decimal.NewFromInt(1),
+	}
+	engine.SetRiskManager(NewRiskManager(RiskLimits{MaxConsecutiveLosses: 1}))
+	engine.SetEventBus(bus)
+
+	kline := CreateTestKlineWithPrices(time.Now(),
+		decimal.NewFromInt(100), decimal.NewFromInt(101), decimal.NewFromInt(89), decimal.NewFromInt(90))
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	signal := &strategy.Signal{Type: "BUY", Strength: 1, Reason: "test"}
+
+	require.NoError(t, engine.handleBuySignal(context.Background(), signal, kline, portfolio))
+	assert.Len(t, orderManager.placedOrders, 1)
+
+	// 亏损交易达到连续亏损限制：撤销全部挂单，之后不再挂单
+	executed := []*executor.OrderResult{newRiskFill(executor.OrderSideBuy, 1, 100), newRiskFill(executor.OrderSideSell, 1, 85)}
+	engine.updateRisk(context.Background(), executed, kline, portfolio)
+	assert.Equal(t, 1, orderManager.cancelAllCount)
+	require.Len(t, events, 1)
+	assert.Equal(t, RiskEventHalted, events[0].Type)
+
+	require.NoError(t, engine.handleBuySignal(context.Background(), signal, kline, portfolio))
+	assert.Len(t, orderManager.placedOrders, 1)
+
+	// 已熔断时不重复撤单
+	engine.updateRisk(context.Background(), nil, kline, portfolio)
+	assert.Equal(t, 1, orderManager.cancelAllCount)
+}
+
+func TestTradingEngine_DailyLossCancelsEntryOrders(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &cancelCountingOrderManager{}
+	orderManager.placedOrders = []*PendingOrder{
+		{ID: "buy-1", Type: PendingOrderTypeBuyLimit},
+		{ID: "stop-1", Type: PendingOrderTypeStopLoss},
+	}
+	engine := &TradingEngine{orderManager: orderManager, tradingPair: pair}
+	engine.SetRiskManager(NewRiskManager(RiskLimits{MaxDailyLoss: 10}))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(100), Position: decimal.NewFromInt(1)}
+	kline := CreateTestKlineWithPrices(start,
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100))
+	engine.updateRisk(context.Background(), nil, kline, portfolio)
+
+	kline = CreateTestKlineWithPrices(start.Add(time.Hour),
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(80), decimal.NewFromInt(85))
+	engine.updateRisk(context.Background(), nil, kline, portfolio)
+
+	assert.Equal(t, []string{"buy-1"}, orderManager.cancelledOrders)
+	assert.Equal(t, 0, orderManager.cancelAllCount)
+}
+
+func TestRiskManager_DrawdownPausesEntries(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewRiskManager(RiskLimits{MaxDrawdownPercent: 0.2, DrawdownAction: DrawdownActionPause})
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(0), Position: decimal.NewFromInt(10)}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buy := &PendingOrder{TradingPair: pair, Type: PendingOrderTypeBuyLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(80)}
+
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(100), now))
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(90), now))
+	_, percent := manager.Drawdown()
+	assert.True(t, decimal.NewFromFloat(0.1).Equal(percent))
+
+	// 1000 -> 750，回撤 25%
+	event := manager.Update(nil, portfolio, decimal.NewFromInt(75), now)
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventDrawdown, event.Type)
+	assert.Equal(t, DrawdownActionPause, event.DrawdownAction)
+	assert.True(t, decimal.NewFromFloat(0.25).Equal(event.Drawdown))
+	assert.True(t, manager.IsDrawdownPaused())
+	assert.ErrorIs(t, manager.CheckOrder(buy), ErrDrawdownLimit)
+
+	// 仍在阈值以外时不重复告警
+	assert.Nil(t, manager.Update(nil, portfolio, decimal.NewFromInt(70), now))
+
+	// 回到阈值以内恢复开仓
+	event = manager.Update(nil, portfolio, decimal.NewFromInt(85), now)
+	require.NotNil(t, event)
+	assert.Equal(t, RiskEventRecovered, event.Type)
+	assert.NoError(t, manager.CheckOrder(buy))
+	assert.True(t, decimal.NewFromInt(1000).Equal(manager.PeakEquity()))
+}
+
+func TestRiskManager_DrawdownNotifyOnly(t *testing.T) {
+	manager := NewRiskManager(RiskLimits{MaxDrawdownPercent: 0.1})
+	portfolio := &executor.Portfolio{Position: decimal.NewFromInt(1)}
+	now := time.Now()
+	manager.Update(nil, portfolio, decimal.NewFromInt(100), now)
+
+	event := manager.Update(nil, portfolio, decimal.NewFromInt(80), now)
+	require.NotNil(t, event)
+	assert.Equal(t, DrawdownActionNotify, event.DrawdownAction)
+	assert.False(t, manager.IsDrawdownPaused())
+
+	assert.Error(t, RiskLimits{MaxDrawdownPercent: 1.5}.Validate())
+	assert.Error(t, RiskLimits{DrawdownAction: "close"}.Validate())
+	assert.True(t, RiskLimits{MaxDrawdownPercent: 0.1}.Enabled())
+}
+
+func TestTradingEngine_DrawdownFlattensPosition(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &cancelCountingOrderManager{}
+	bus := NewEventBus()
+	var events []*RiskEvent
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event.Risk) }, EventRisk)
+	engine := &TradingEngine{orderManager: orderManager, tradingPair: pair}
+	engine.SetRiskManager(NewRiskManager(RiskLimits{MaxDrawdownPercent: 0.1, DrawdownAction: DrawdownActionFlatten}))
+	engine.SetEventBus(bus)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(100), Position: decimal.NewFromInt(2)}
+	kline := CreateTestKlineWithPrices(start,
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100))
+	engine.updateRisk(context.Background(), nil, kline, portfolio)
+	assert.Empty(t, orderManager.placedOrders)
+
+	// 300 -> 200，回撤 33%：撤销全部挂单并市价卖出全部持仓
+	kline = CreateTestKlineWithPrices(start.Add(time.Hour),
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(50), decimal.NewFromInt(50))
+	engine.updateRisk(context.Background(), nil, kline, portfolio)
+
+	assert.Equal(t, 1, orderManager.cancelAllCount)
+	require.Len(t, orderManager.placedOrders, 1)
+	order := orderManager.placedOrders[0]
+	assert.Equal(t, PendingOrderTypeSellMarket, order.Type)
+	assert.Equal(t, OriginDrawdownFlatten, order.OriginSignal)
+	assert.True(t, decimal.NewFromInt(2).Equal(order.Quantity))
+	require.Len(t, events, 1)
+	assert.Equal(t, RiskEventDrawdown, events[0].Type)
+}
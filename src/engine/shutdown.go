@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OriginShutdown 停止时清仓卖单的来源标记
+const OriginShutdown = "SHUTDOWN"
+
+// ShutdownPolicy 实盘停止时对挂单和持仓的处理，默认都保留（重启后继续管理）
+type ShutdownPolicy struct {
+	CancelOrders  bool `json:"cancel_orders"`  // 撤销全部挂单（止损单一并撤销）
+	ClosePosition bool `json:"close_position"` // 撤销全部挂单后市价卖出全部持仓
+}
+
+// Describe 返回处理方式的描述
+func (p ShutdownPolicy) Describe() string {
+	switch {
+	case p.ClosePosition:
+		return "cancel orders and close position"
+	case p.CancelOrders:
+		return "cancel orders, keep position"
+	default:
+		return "keep orders and position"
+	}
+}
+
+// ShutdownReport 停止时的处理结果
+type ShutdownReport struct {
+	Reason          string
+	Abnormal        bool // 引擎非正常退出（不是收到停止请求）
+	Policy          ShutdownPolicy
+	CancelledOrders int             // 撤销的挂单数
+	ClosedQuantity  decimal.Decimal // 市价卖出成交的数量
+	Position        decimal.Decimal // 处理后的持仓
+	OpenOrders      int             // 处理后保留的挂单数
+	Errors          []error
+}
+
+// Err 处理过程中的错误（没有时为 nil）
+func (r *ShutdownReport) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// Summary 一行摘要，用于日志和通知
+func (r *ShutdownReport) Summary() string {
+	parts := []string{r.Reason, r.Policy.Describe()}
+	if r.CancelledOrders > 0 {
+		parts = append(parts, fmt.Sprintf("cancelled %d orders", r.CancelledOrders))
+	}
+	if r.ClosedQuantity.IsPositive() {
+		parts = append(parts, fmt.Sprintf("sold %s", r.ClosedQuantity.String()))
+	}
+	parts = append(parts, fmt.Sprintf("position %s, open orders %d", r.Position.String(), r.OpenOrders))
+	for _, err := range r.Errors {
+		parts = append(parts, fmt.Sprintf("error: %v", err))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Shutdown 引擎退出后按策略撤销挂单、市价清仓，保存策略状态并发布 shutdown 事件（需在 Run 返回后调用）。
+// ctx 应独立于已取消的运行 ctx，否则无法向交易所撤单和下单
+func (e *TradingEngine) Shutdown(ctx context.Context, policy ShutdownPolicy, reason string, abnormal bool) *ShutdownReport {
+	_, logger := log.WithCtx(ctx)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := &ShutdownReport{Reason: reason, Abnormal: abnormal, Policy: policy}
+	if e.signalOnly {
+		// 只发信号模式没有挂单，持仓是假想的
+		report.Policy = ShutdownPolicy{}
+	}
+
+	if report.Policy.CancelOrders || report.Policy.ClosePosition {
+		pending := len(e.orderManager.GetPendingOrders())
+		if err := e.orderManager.CancelAllOrders(ctx); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("cancel orders: %w", err))
+		} else {
+			report.CancelledOrders = pending
+		}
+	}
+	if report.Policy.ClosePosition {
+		if err := e.closePositionOnShutdown(ctx, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("close position: %w", err))
+		}
+	}
+
+	e.saveStrategyState(ctx)
+
+	portfolio, err := e.executor.GetPortfolio(ctx)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("get portfolio: %w", err))
+	} else {
+		report.Position = portfolio.Position
+	}
+	report.OpenOrders = len(e.orderManager.GetPendingOrders())
+
+	if report.Abnormal || len(report.Errors) > 0 {
+		logger.Error(fmt.Sprintf("🛑 交易引擎停止: %s", report.Summary()))
+	} else {
+		logger.Info(fmt.Sprintf("🛑 交易引擎停止: %s", report.Summary()))
+	}
+	e.events.Publish(ctx, &Event{Type: EventShutdown, Time: time.Now(), TradingPair: e.tradingPair, Shutdown: report, Message: report.Summary()})
+	return report
+}
+
+// closePositionOnShutdown 按最近一根K线的收盘价市价卖出全部持仓（不受风控熔断限制），并立即撮合取回成交
+func (e *TradingEngine) closePositionOnShutdown(ctx context.Context, report *ShutdownReport) error {
+	_, logger := log.WithCtx(ctx)
+
+	if len(e.lastKlines) == 0 {
+		return errors.New("no kline processed yet")
+	}
+	kline := e.lastKlines[len(e.lastKlines)-1]
+	portfolio, err := e.executor.GetPortfolio(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	if !portfolio.Position.IsPositive() {
+		return nil
+	}
+
+	order := &PendingOrder{
+		ID:           generateShortOrderID("shut", e.tradingPair.Base),
+		Type:         PendingOrderTypeSellMarket,
+		TradingPair:  e.tradingPair,
+		Quantity:     portfolio.Position,
+		Price:        kline.Close,
+		CreateTime:   kline.OpenTime,
+		Reason:       report.Reason,
+		OriginSignal: OriginShutdown,
+	}
+	logger.Warning(fmt.Sprintf("🔴 停止前清仓: order_id=%s, qty=%s, price=%s", order.ID, order.Quantity.String(), kline.Close.String()))
+	// 不经过风控检查：熔断后同样需要清仓；不满足下单规则时报错，不能当作已清仓
+	if err := e.symbolFilters.NormalizeOrder(order); err != nil {
+		return fmt.Errorf("sell %s: %w", order.Quantity.String(), err)
+	}
+	if err := e.submitOrder(ctx, order); err != nil {
+		return err
+	}
+
+	executed, err := e.orderManager.CheckAndExecuteOrders(ctx, kline)
+	if err != nil {
+		return err
+	}
+	for _, result := range executed {
+		if result != nil && result.Success && result.Side == executor.OrderSideSell {
+			report.ClosedQuantity = report.ClosedQuantity.Add(result.Quantity)
+		}
+	}
+	if portfolio, err = e.executor.GetPortfolio(ctx); err == nil {
+		e.publishFills(ctx, executed, kline, portfolio)
+	}
+	return nil
+}
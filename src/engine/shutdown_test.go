@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newShutdownTestEngine 持有 2 个 BTC 和一个止损挂单的引擎
+func newShutdownTestEngine(t *testing.T) (*TradingEngine, *mockOrderExecutor, *[]*Event) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(100), decimal.NewFromInt(2))
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	require.NoError(t, orderManager.PlaceOrder(context.Background(), &PendingOrder{ID: "sl-1", Type: PendingOrderTypeStopLoss,
+		TradingPair: pair, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(90)}))
+
+	bus := NewEventBus()
+	var events []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { events = append(events, event) }, EventShutdown, EventOrderFilled)
+	engine := &TradingEngine{orderManager: orderManager, executor: mockExecutor, tradingPair: pair}
+	engine.SetEventBus(bus)
+	engine.lastKlines = append(engine.lastKlines, CreateTestKlineWithPrices(time.Now(),
+		decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100), decimal.NewFromInt(100)))
+	return engine, mockExecutor, &events
+}
+
+func TestShutdownPolicy_Describe(t *testing.T) {
+	assert.Equal(t, "keep orders and position", ShutdownPolicy{}.Describe())
+	assert.Equal(t, "cancel orders, keep position", ShutdownPolicy{CancelOrders: true}.Describe())
+	assert.Equal(t, "cancel orders and close position", ShutdownPolicy{ClosePosition: true}.Describe())
+}
+
+func TestTradingEngine_ShutdownKeepsOrdersAndPosition(t *testing.T) {
+	engine, _, events := newShutdownTestEngine(t)
+
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{}, "received interrupt", false)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 0, report.CancelledOrders)
+	assert.Equal(t, 1, report.OpenOrders)
+	assert.True(t, decimal.NewFromInt(2).Equal(report.Position))
+
+	require.Len(t, *events, 1)
+	event := (*events)[0]
+	assert.Equal(t, EventShutdown, event.Type)
+	assert.Same(t, report, event.Shutdown)
+	assert.Equal(t, "received interrupt; keep orders and position; position 2, open orders 1", event.Message)
+}
+
+func TestTradingEngine_ShutdownCancelsOrders(t *testing.T) {
+	engine, _, _ := newShutdownTestEngine(t)
+
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{CancelOrders: true}, "stopped", false)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 1, report.CancelledOrders)
+	assert.Equal(t, 0, report.OpenOrders)
+	assert.True(t, decimal.NewFromInt(2).Equal(report.Position))
+}
+
+func TestTradingEngine_ShutdownClosesPosition(t *testing.T) {
+	engine, mockExecutor, events := newShutdownTestEngine(t)
+
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{ClosePosition: true}, "stopped", false)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 1, report.CancelledOrders)
+	assert.Equal(t, 1, mockExecutor.sellCallCount)
+	assert.True(t, decimal.NewFromInt(2).Equal(report.ClosedQuantity))
+	assert.True(t, report.Position.IsZero())
+	assert.Equal(t, 0, report.OpenOrders)
+
+	// 清仓成交先于 shutdown 事件发布
+	require.Len(t, *events, 2)
+	assert.Equal(t, EventOrderFilled, (*events)[0].Type)
+	assert.Equal(t, EventShutdown, (*events)[1].Type)
+}
+
+func TestTradingEngine_ShutdownReportsErrors(t *testing.T) {
+	engine, _, _ := newShutdownTestEngine(t)
+	engine.lastKlines = nil
+
+	// 没有K线时无法确定清仓价格，处理出错但仍发布事件
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{ClosePosition: true}, "data feed finished", true)
+	assert.Error(t, report.Err())
+	assert.True(t, report.Abnormal)
+	assert.True(t, decimal.NewFromInt(2).Equal(report.Position))
+	assert.Contains(t, report.Summary(), "error: close position: no kline processed yet")
+
+	// 只发信号模式不处理挂单和假想持仓
+	engine, _, _ = newShutdownTestEngine(t)
+	engine.SetSignalOnly(true)
+	report = engine.Shutdown(context.Background(), ShutdownPolicy{ClosePosition: true}, "stopped", false)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 1, report.OpenOrders)
+}
+
+func TestTradingEngine_ShutdownClosesPositionWhenHalted(t *testing.T) {
+	engine, mockExecutor, _ := newShutdownTestEngine(t)
+	riskManager := NewRiskManager(RiskLimits{})
+	riskManager.halted = true
+	engine.SetRiskManager(riskManager)
+
+	// 风控熔断只拦截交易挂单，停止清仓照常卖出
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{ClosePosition: true}, "stopped", false)
+	assert.NoError(t, report.Err())
+	assert.Equal(t, 1, mockExecutor.sellCallCount)
+	assert.True(t, decimal.NewFromInt(2).Equal(report.ClosedQuantity))
+	assert.True(t, report.Position.IsZero())
+}
+
+func TestTradingEngine_ShutdownCloseBelowMinNotional(t *testing.T) {
+	engine, mockExecutor, _ := newShutdownTestEngine(t)
+	filters := NewSymbolFilterService(nil, nil)
+	limits := newTestSymbolFilters()
+	limits.MinNotional = decimal.NewFromInt(1000)
+	filters.SetFilters(engine.tradingPair, limits)
+	engine.SetSymbolFilters(filters)
+
+	// 持仓低于最小下单金额无法卖出，报告错误而不是清仓成功
+	report := engine.Shutdown(context.Background(), ShutdownPolicy{ClosePosition: true}, "stopped", false)
+	require.Error(t, report.Err())
+	assert.ErrorIs(t, report.Err(), cex.ErrBelowMinNotional)
+	assert.Equal(t, 0, mockExecutor.sellCallCount)
+	assert.True(t, report.ClosedQuantity.IsZero())
+	assert.True(t, decimal.NewFromInt(2).Equal(report.Position))
+}
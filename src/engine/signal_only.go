@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// SetSignalOnly 设置只发信号模式：策略照常分析行情并发布信号事件，引擎不下单
+// 策略看到的是按信号收盘价假想成交的持仓，以便持仓后继续产生卖出信号
+func (e *TradingEngine) SetSignalOnly(enabled bool) {
+	e.signalOnly = enabled
+	e.signalPortfolio = nil
+}
+
+// signalOnlyPortfolio 只发信号模式下交给策略的假想组合（首次调用时以执行器的现金初始化）
+func (e *TradingEngine) signalOnlyPortfolio(portfolio *executor.Portfolio) *executor.Portfolio {
+	if e.signalPortfolio == nil {
+		e.signalPortfolio = &executor.Portfolio{Cash: portfolio.Cash, Position: portfolio.Position}
+	}
+	virtual := *e.signalPortfolio
+	virtual.Timestamp = portfolio.Timestamp
+	return &virtual
+}
+
+// recordSignalOnly 按K线收盘价假想成交信号，只更新假想持仓
+func (e *TradingEngine) recordSignalOnly(ctx context.Context, signal *strategy.Signal, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	_, logger := log.WithCtx(ctx)
+
+	virtual := e.signalPortfolio
+	switch signal.Type {
+	case "BUY":
+		amount := decimal.Min(e.sizer().Size(SizingInput{
+			Portfolio:  portfolio,
+			EntryPrice: kline.Close,
+			Klines:     e.lastKlines,
+			Signal:     signal,
+		}), virtual.Cash)
+		if amount.LessThan(e.minTradeAmount) {
+			logger.Info(fmt.Sprintf("📣 只发信号：假想金额过小，不记入持仓: amount=%s, min=%s", amount.String(), e.minTradeAmount.String()))
+			return nil
+		}
+		quantity := amount.Div(kline.Close)
+		virtual.Cash = virtual.Cash.Sub(amount)
+		virtual.Position = virtual.Position.Add(quantity)
+	case "SELL":
+		quantity := virtual.Position
+		if signal.Strength > 0 && signal.Strength < 1 {
+			quantity = quantity.Mul(decimal.NewFromFloat(signal.Strength))
+		}
+		virtual.Cash = virtual.Cash.Add(quantity.Mul(kline.Close))
+		virtual.Position = virtual.Position.Sub(quantity)
+	default:
+		return fmt.Errorf("未知信号类型: %s", signal.Type)
+	}
+
+	logger.Info(fmt.Sprintf("📣 只发信号，不下单: symbol=%s, type=%s, price=%s, virtual_cash=%s, virtual_position=%s",
+		e.tradingPair.String(), signal.Type, kline.Close.String(), virtual.Cash.StringFixed(2), virtual.Position.String()))
+	return nil
+}
+
+// signalIndicators 策略支持时获取当前指标快照，随信号事件发布
+func (e *TradingEngine) signalIndicators() map[string]float64 {
+	provider, ok := e.strategy.(strategy.IndicatorProvider)
+	if !ok {
+		return nil
+	}
+	return provider.GetIndicators()
+}
+
+// signalContext 挂单携带的信号上下文（信号价格为信号K线收盘价）
+func (e *TradingEngine) signalContext(signal *strategy.Signal, kline *cex.KlineData) *executor.SignalContext {
+	return &executor.SignalContext{
+		Type:       signal.Type,
+		Reason:     signal.Reason,
+		Price:      kline.Close,
+		Time:       kline.CloseTime,
+		Indicators: e.signalIndicators(),
+	}
+}
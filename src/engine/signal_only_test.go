@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signalOnlyTestStrategy 空仓时买入、持仓时全部卖出，记录每根K线看到的持仓
+type signalOnlyTestStrategy struct {
+	positions []string
+	close     float64
+}
+
+func (s *signalOnlyTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.positions = append(s.positions, portfolio.Position.String())
+	s.close = kline.Close.InexactFloat64()
+	if portfolio.Position.IsZero() {
+		return []*strategy.Signal{{Type: "BUY", Strength: 1, Reason: "flat"}}, nil
+	}
+	return []*strategy.Signal{{Type: "SELL", Strength: 1, Reason: "holding"}}, nil
+}
+
+func (s *signalOnlyTestStrategy) GetName() string                                { return "signal_only_test" }
+func (s *signalOnlyTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *signalOnlyTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *signalOnlyTestStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *signalOnlyTestStrategy) Load(data []byte) error                         { return nil }
+
+func (s *signalOnlyTestStrategy) GetIndicators() map[string]float64 {
+	return map[string]float64{"close": s.close}
+}
+
+func TestTradingEngine_SignalOnly(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var klines []*cex.KlineData
+	for i, price := range []int64{100, 110, 120} {
+		close := decimal.NewFromInt(price)
+		klines = append(klines, CreateTestKlineWithPrices(startTime.Add(time.Duration(i)*4*time.Hour), close, close, close, close))
+	}
+
+	testStrategy := &signalOnlyTestStrategy{}
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := &mockTradingOrderManager{}
+	engine := createTestTradingEngineWithMocks(testStrategy, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+	engine.SetSignalOnly(true)
+
+	bus := NewEventBus()
+	var signals []*Event
+	bus.Subscribe(func(ctx context.Context, event *Event) { signals = append(signals, event) }, EventSignalGenerated)
+	engine.SetEventBus(bus)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	// 不下单，执行器账户不变
+	assert.Zero(t, orderManager.placeCallCount)
+	assert.Zero(t, mockExecutor.buyCallCount)
+	assert.Zero(t, mockExecutor.sellCallCount)
+
+	// 策略看到按收盘价假想成交的持仓：95% 资金买入 95 个，下一根K线全部卖出
+	assert.Equal(t, []string{"0", "95", "0"}, testStrategy.positions)
+
+	require.Len(t, signals, 3)
+	assert.Equal(t, []string{"BUY", "SELL", "BUY"}, []string{signals[0].Signal.Type, signals[1].Signal.Type, signals[2].Signal.Type})
+	assert.Equal(t, map[string]float64{"close": 110}, signals[1].Indicators)
+
+	// 资金曲线按假想持仓估值：500 现金 + 95 × 110
+	curve := engine.GetEquityCurve()
+	require.Len(t, curve, 3)
+	assert.True(t, curve[1].PortfolioValue.Equal(decimal.NewFromInt(10950)), curve[1].PortfolioValue.String())
+}
@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OriginSignalProtection 信号止损/止盈保护单的来源标记
+const OriginSignalProtection = "SIGNAL_PROTECTION"
+
+// hasProtection 开仓挂单是否带有信号指定的止损或止盈价
+func (o *PendingOrder) hasProtection() bool {
+	return o.side() == cex.OrderSideBuy && (o.StopLossPrice.IsPositive() || o.TakeProfitPrice.IsPositive())
+}
+
+// trackProtectedEntry 记录带止损/止盈价的开仓挂单，成交后挂出保护单
+func (e *TradingEngine) trackProtectedEntry(order *PendingOrder) {
+	if !order.hasProtection() {
+		return
+	}
+	if e.protectedEntries == nil {
+		e.protectedEntries = make(map[string]*PendingOrder)
+	}
+	e.protectedEntries[order.ID] = order
+}
+
+// BuildProtectiveOrders 按信号的止损/止盈价生成保护单，两者都有时为同组 OCO（止盈限价单在前）
+func BuildProtectiveOrders(pair cex.TradingPair, quantity, stopLoss, takeProfit decimal.Decimal, createTime time.Time) []*PendingOrder {
+	groupID := ""
+	if stopLoss.IsPositive() && takeProfit.IsPositive() {
+		groupID = generateShortOrderID("prot", pair.Base)
+	}
+
+	var orders []*PendingOrder
+	if takeProfit.IsPositive() {
+		orders = append(orders, &PendingOrder{
+			ID:           generateShortOrderID("tp", pair.Base),
+			Type:         PendingOrderTypeSellLimit,
+			TradingPair:  pair,
+			Quantity:     quantity,
+			Price:        takeProfit,
+			CreateTime:   createTime,
+			Reason:       fmt.Sprintf("signal take profit: %s", takeProfit.String()),
+			OriginSignal: OriginSignalProtection,
+			GroupID:      groupID,
+		})
+	}
+	if stopLoss.IsPositive() {
+		orders = append(orders, &PendingOrder{
+			ID:           generateShortOrderID("sl", pair.Base),
+			Type:         PendingOrderTypeStopLoss,
+			TradingPair:  pair,
+			Quantity:     quantity,
+			Price:        stopLoss,
+			CreateTime:   createTime,
+			Reason:       fmt.Sprintf("signal stop loss: %s", stopLoss.String()),
+			OriginSignal: OriginSignalProtection,
+			GroupID:      groupID,
+		})
+	}
+	return orders
+}
+
+// placeProtectiveOrders 挂出保护单，止损和止盈同组时按 OCO 下单
+func (e *TradingEngine) placeProtectiveOrders(ctx context.Context, orders []*PendingOrder) error {
+	for _, order := range orders {
+		if !e.normalizeOrder(ctx, order) {
+			return nil
+		}
+	}
+
+	if len(orders) == 2 {
+		manager, ok := e.orderManager.(OCOOrderManager)
+		if !ok {
+			return fmt.Errorf("order manager does not support OCO orders")
+		}
+		if err := manager.PlaceOCOOrder(ctx, orders[0], orders[1]); err != nil {
+			return err
+		}
+		for _, order := range orders {
+			e.events.Publish(ctx, &Event{Type: EventOrderPlaced, Time: order.CreateTime, TradingPair: order.TradingPair, Order: order})
+		}
+		return nil
+	}
+
+	for _, order := range orders {
+		if err := e.placeOrder(ctx, order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastFillQuantity 本次成交数量（分批成交的累计结果取最后一次成交）
+func lastFillQuantity(result *executor.OrderResult) decimal.Decimal {
+	if len(result.Fills) > 0 {
+		return result.Fills[len(result.Fills)-1].Quantity
+	}
+	return result.Quantity
+}
+
+// syncSignalProtection 维护信号保护单：开仓挂单成交后按成交数量挂出止损/止盈，持仓减少时从最新的保护单开始缩减，清仓后撤销
+func (e *TradingEngine) syncSignalProtection(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	for _, result := range executed {
+		if result == nil || !result.Success || result.Side != executor.OrderSideBuy {
+			continue
+		}
+		entry, ok := e.protectedEntries[result.ClientOrderID]
+		if !ok {
+			continue
+		}
+		if !result.IsPartiallyFilled() {
+			delete(e.protectedEntries, result.ClientOrderID)
+		}
+
+		quantity := lastFillQuantity(result)
+		logger.Info(fmt.Sprintf("🛡️ 挂出信号保护单: entry=%s, qty=%s, stop_loss=%s, take_profit=%s",
+			entry.ID, quantity.String(), entry.StopLossPrice.String(), entry.TakeProfitPrice.String()))
+		orders := BuildProtectiveOrders(e.tradingPair, quantity, entry.StopLossPrice, entry.TakeProfitPrice, kline.OpenTime)
+		if err := e.placeProtectiveOrders(ctx, orders); err != nil {
+			return fmt.Errorf("挂出信号保护单失败: %w", err)
+		}
+	}
+
+	// 开仓挂单已过期或撤销（含追价重挂的旧挂单）
+	pending := make(map[string]*PendingOrder)
+	for _, order := range e.orderManager.GetPendingOrders() {
+		pending[order.ID] = order
+	}
+	for id := range e.protectedEntries {
+		if _, ok := pending[id]; !ok {
+			delete(e.protectedEntries, id)
+		}
+	}
+
+	return e.resizeSignalProtection(ctx, pending, kline, portfolio)
+}
+
+// resizeSignalProtection 保护单总数量超过持仓时（策略卖出、其他止盈止损成交）从最新的保护单开始撤销或按剩余数量重挂
+func (e *TradingEngine) resizeSignalProtection(ctx context.Context, pending map[string]*PendingOrder, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	// 按组汇总：OCO 两腿数量相同，只计一次
+	type protection struct {
+		orders   []*PendingOrder
+		quantity decimal.Decimal
+		created  time.Time
+	}
+	groups := make(map[string]*protection)
+	for _, order := range pending {
+		if order.OriginSignal != OriginSignalProtection {
+			continue
+		}
+		key := order.GroupID
+		if key == "" {
+			key = order.ID
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &protection{quantity: order.Quantity, created: order.CreateTime}
+			groups[key] = group
+		}
+		group.orders = append(group.orders, order)
+	}
+
+	total := decimal.Zero
+	keys := make([]string, 0, len(groups))
+	for key, group := range groups {
+		total = total.Add(group.quantity)
+		keys = append(keys, key)
+	}
+	excess := total.Sub(portfolio.Position)
+	if !excess.IsPositive() {
+		return nil
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := groups[keys[i]], groups[keys[j]]
+		if !a.created.Equal(b.created) {
+			return a.created.After(b.created)
+		}
+		return keys[i] > keys[j]
+	})
+	for _, key := range keys {
+		if !excess.IsPositive() {
+			break
+		}
+		group := groups[key]
+		// 撤销一腿即撤销整组
+		if err := e.orderManager.CancelOrder(ctx, group.orders[0].ID); err != nil {
+			logger.Error("取消信号保护单失败", "id", group.orders[0].ID, "error", err)
+			continue
+		}
+		if group.quantity.LessThanOrEqual(excess) {
+			excess = excess.Sub(group.quantity)
+			logger.Info(fmt.Sprintf("🛡️ 持仓减少，撤销信号保护单: group=%s, qty=%s", key, group.quantity.String()))
+			continue
+		}
+
+		remaining := group.quantity.Sub(excess)
+		excess = decimal.Zero
+		var stopLoss, takeProfit decimal.Decimal
+		for _, order := range group.orders {
+			if order.Type == PendingOrderTypeStopLoss {
+				stopLoss = order.Price
+			} else {
+				takeProfit = order.Price
+			}
+		}
+		logger.Info(fmt.Sprintf("🛡️ 持仓减少，按剩余数量重挂信号保护单: group=%s, qty=%s -> %s", key, group.quantity.String(), remaining.String()))
+		if err := e.placeProtectiveOrders(ctx, BuildProtectiveOrders(e.tradingPair, remaining, stopLoss, takeProfit, kline.OpenTime)); err != nil {
+			return fmt.Errorf("重挂信号保护单失败: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedStrategy 按K线序号返回预设信号
+type scriptedStrategy struct {
+	signals     map[int][]*strategy.Signal
+	onDataCalls int
+}
+
+func (s *scriptedStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.onDataCalls++
+	return s.signals[s.onDataCalls], nil
+}
+
+func (s *scriptedStrategy) GetName() string                                { return "ScriptedStrategy" }
+func (s *scriptedStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *scriptedStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *scriptedStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *scriptedStrategy) Load(data []byte) error                         { return nil }
+
+func TestTradingEngine_Run_SignalProtection(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(101), price(99.5), price(100)),                  // 买入信号：限价 99，数量 10，止损 95，止盈 110
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(98), price(100)),   // 按 99 成交，挂出 OCO 保护单
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(100), price(105), price(99), price(104)),   // 策略卖出 4 个
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(104), price(105), price(103), price(104)), // 卖单成交，保护单缩减为 6 个
+		CreateTestKlineWithPrices(startTime.Add(16*time.Hour), price(104), price(111), price(103), price(110)), // 触及止盈
+	}
+	strat := &scriptedStrategy{signals: map[int][]*strategy.Signal{
+		1: {{Type: "BUY", Reason: "entry", LimitPrice: price(99), Quantity: price(10), StopLoss: price(95), TakeProfit: price(110)}},
+		3: {{Type: "SELL", Reason: "scale out", Quantity: price(4)}},
+	}}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(strat, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	require.Len(t, mockExecutor.buyResults, 1)
+	assert.Equal(t, "99", mockExecutor.buyResults[0].Price.String())
+	assert.Equal(t, "10", mockExecutor.buyResults[0].Quantity.String())
+
+	require.Len(t, mockExecutor.sellResults, 2)
+	assert.Equal(t, "4", mockExecutor.sellResults[0].Quantity.String())
+	assert.Equal(t, "6", mockExecutor.sellResults[1].Quantity.String())
+	assert.Equal(t, "110", mockExecutor.sellResults[1].Price.String())
+
+	// 止盈成交后止损腿撤销
+	assert.True(t, mockExecutor.position.IsZero())
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+	assert.Empty(t, engine.protectedEntries)
+}
+
+func TestBuildProtectiveOrders(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	now := time.Now()
+
+	orders := BuildProtectiveOrders(pair, decimal.NewFromInt(2), decimal.NewFromInt(90), decimal.NewFromInt(120), now)
+	require.Len(t, orders, 2)
+	assert.Equal(t, PendingOrderTypeSellLimit, orders[0].Type)
+	assert.Equal(t, PendingOrderTypeStopLoss, orders[1].Type)
+	assert.NotEmpty(t, orders[0].GroupID)
+	assert.Equal(t, orders[0].GroupID, orders[1].GroupID)
+
+	// 只有止损时为单独的止损单
+	orders = BuildProtectiveOrders(pair, decimal.NewFromInt(2), decimal.NewFromInt(90), decimal.Zero, now)
+	require.Len(t, orders, 1)
+	assert.Equal(t, PendingOrderTypeStopLoss, orders[0].Type)
+	assert.Empty(t, orders[0].GroupID)
+	assert.Equal(t, OriginSignalProtection, orders[0].OriginSignal)
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+)
+
+// SignalThrottle 引擎层信号节流：策略在条件持续满足时可能每根K线都发出同方向信号，节流后不会反复生成挂单（默认不节流）
+type SignalThrottle struct {
+	BuyCooldownBars    int  `json:"buy_cooldown_bars"`    // 买入信号生成挂单后的 N 根K线内忽略新的买入信号（0 表示不限制）
+	SellCooldownBars   int  `json:"sell_cooldown_bars"`   // 卖出信号生成挂单后的 N 根K线内忽略新的卖出信号（0 表示不限制）
+	SuppressDuplicates bool `json:"suppress_duplicates"`  // 已有同方向的信号挂单未成交时忽略新信号（卖出信号不再撤单重挂）
+	MaxPendingPerSide  int  `json:"max_pending_per_side"` // 每个方向未成交信号挂单的数量上限，达到后忽略新信号（0 表示不限制）
+}
+
+// Validate 检查节流参数
+func (t SignalThrottle) Validate() error {
+	if t.BuyCooldownBars < 0 || t.SellCooldownBars < 0 {
+		return fmt.Errorf("cooldown bars cannot be negative, got buy=%d sell=%d", t.BuyCooldownBars, t.SellCooldownBars)
+	}
+	if t.MaxPendingPerSide < 0 {
+		return fmt.Errorf("MaxPendingPerSide cannot be negative, got %d", t.MaxPendingPerSide)
+	}
+	return nil
+}
+
+// cooldownBars 信号方向对应的冷却K线数
+func (t SignalThrottle) cooldownBars(signalType string) int {
+	if signalType == "BUY" {
+		return t.BuyCooldownBars
+	}
+	return t.SellCooldownBars
+}
+
+// SetSignalThrottle 设置引擎层信号节流，同时清空冷却记录
+func (e *TradingEngine) SetSignalThrottle(throttle SignalThrottle) {
+	e.signalThrottle = throttle
+	e.lastSignalOrder = nil
+}
+
+// throttleSignal 检查信号是否被节流，返回忽略原因（为空表示放行）
+func (e *TradingEngine) throttleSignal(signal *strategy.Signal, kline *cex.KlineData) string {
+	if cooldown := e.signalThrottle.cooldownBars(signal.Type); cooldown > 0 {
+		if last, ok := e.lastSignalOrder[signal.Type]; ok {
+			if bars := int(kline.OpenTime.Sub(last) / e.getTimeframeInterval()); bars <= cooldown {
+				return fmt.Sprintf("cooldown %d/%d bars", bars, cooldown)
+			}
+		}
+	}
+
+	if !e.signalThrottle.SuppressDuplicates && e.signalThrottle.MaxPendingPerSide == 0 {
+		return ""
+	}
+	pending := 0
+	for _, order := range e.orderManager.GetPendingOrders() {
+		if order.OriginSignal == signal.Type {
+			pending++
+		}
+	}
+	if e.signalThrottle.SuppressDuplicates && pending > 0 {
+		return fmt.Sprintf("duplicate of %d pending %s order(s)", pending, signal.Type)
+	}
+	if limit := e.signalThrottle.MaxPendingPerSide; limit > 0 && pending >= limit {
+		return fmt.Sprintf("max pending %s orders reached (%d/%d)", signal.Type, pending, limit)
+	}
+	return ""
+}
+
+// recordSignalOrder 记录信号挂单的生成时间，作为同方向信号冷却的起点（追价重挂不重新计时）
+func (e *TradingEngine) recordSignalOrder(order *PendingOrder) {
+	if order.ChaseCount > 0 || (order.OriginSignal != "BUY" && order.OriginSignal != "SELL") {
+		return
+	}
+	if e.lastSignalOrder == nil {
+		e.lastSignalOrder = make(map[string]time.Time)
+	}
+	e.lastSignalOrder[order.OriginSignal] = order.CreateTime
+}
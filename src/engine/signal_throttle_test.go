@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradingEngine_Run_SignalThrottle(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := decimal.NewFromInt(100)
+
+	// 价格始终高于买入限价 99.9，挂单不会成交；策略每根K线都发出买入信号
+	var klines []*cex.KlineData
+	signals := make(map[int][]*strategy.Signal)
+	for i := 0; i < 6; i++ {
+		klines = append(klines, CreateTestKlineWithPrices(startTime.Add(time.Duration(i)*4*time.Hour), price, price, price, price))
+		signals[i+1] = []*strategy.Signal{{Type: "BUY", Reason: "under band", Quantity: decimal.NewFromInt(1)}}
+	}
+
+	tests := []struct {
+		name     string
+		throttle SignalThrottle
+		pending  int
+	}{
+		{"no throttle", SignalThrottle{}, 6},
+		{"buy cooldown", SignalThrottle{BuyCooldownBars: 2}, 2},        // 第 1、4 根K线
+		{"sell cooldown only", SignalThrottle{SellCooldownBars: 2}, 6}, // 卖出冷却不影响买入
+		{"max pending", SignalThrottle{MaxPendingPerSide: 3}, 3},
+		{"suppress duplicates", SignalThrottle{SuppressDuplicates: true}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+			orderManager := NewBacktestOrderManager(mockExecutor)
+			engine := createTestTradingEngineWithMocks(&scriptedStrategy{signals: signals}, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+			engine.SetSignalThrottle(tt.throttle)
+
+			require.NoError(t, engine.Run(context.Background()))
+
+			assert.Equal(t, 0, mockExecutor.buyCallCount)
+			assert.Equal(t, tt.pending, orderManager.GetOrderCount())
+		})
+	}
+}
+
+func TestSignalThrottle_Validate(t *testing.T) {
+	assert.NoError(t, SignalThrottle{}.Validate())
+	assert.NoError(t, SignalThrottle{BuyCooldownBars: 3, SellCooldownBars: 1, SuppressDuplicates: true, MaxPendingPerSide: 2}.Validate())
+	assert.Error(t, SignalThrottle{BuyCooldownBars: -1}.Validate())
+	assert.Error(t, SignalThrottle{MaxPendingPerSide: -1}.Validate())
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// SpreadStats 实盘/Dry Run 记录的盘口价差统计，价差为 (卖一 - 买一) / 中间价
+type SpreadStats struct {
+	Samples     int         // 样本数
+	Mean        float64     // 全部样本的平均价差
+	Hourly      [24]float64 // 按 UTC 小时统计的平均价差
+	HourSamples [24]int     // 每个小时的样本数
+}
+
+// At 时间 t 所在小时的平均价差，该小时没有样本时使用全部样本的平均价差
+func (s SpreadStats) At(t time.Time) float64 {
+	hour := t.UTC().Hour()
+	if s.HourSamples[hour] > 0 {
+		return s.Hourly[hour]
+	}
+	return s.Mean
+}
+
+// SpreadFillModel 盘口价差成交模型：市价单、止损单和移动止损单按吃单成交，
+// 成交价向不利方向偏移半个价差（K线价格按成交价近似中间价）；限价单按挂单价成交，不受价差影响
+type SpreadFillModel struct {
+	Stats SpreadStats
+}
+
+// NewSpreadFillModel 创建盘口价差成交模型
+func NewSpreadFillModel(stats SpreadStats) *SpreadFillModel {
+	return &SpreadFillModel{Stats: stats}
+}
+
+// Validate 验证价差统计
+func (m *SpreadFillModel) Validate() error {
+	if m.Stats.Samples <= 0 {
+		return fmt.Errorf("spread stats have no samples")
+	}
+	if m.Stats.Mean < 0 || m.Stats.Mean >= 1 {
+		return fmt.Errorf("mean spread must be in [0, 1), got %f", m.Stats.Mean)
+	}
+	return nil
+}
+
+// Fill 吃单成交的挂单加上半个价差
+func (m *SpreadFillModel) Fill(order *PendingOrder, kline *cex.KlineData, price decimal.Decimal) FillDecision {
+	if !order.isTaker() {
+		return FillDecision{Filled: true, Price: price}
+	}
+	halfSpread := m.Stats.At(kline.OpenTime) / 2
+	return FillDecision{
+		Filled: true,
+		Price:  slippedPrice(order, kline, price, halfSpread),
+		Reason: fmt.Sprintf("half spread=%.2fbps", halfSpread*10000),
+	}
+}
+
+// Describe 模型参数说明
+func (m *SpreadFillModel) Describe() string {
+	return fmt.Sprintf("captured spread (mean %.2fbps over %d samples, by UTC hour)", m.Stats.Mean*10000, m.Stats.Samples)
+}
+
+// isTaker 是否按吃单成交（市价单、止损单、移动止损单）
+func (o *PendingOrder) isTaker() bool {
+	return o.isMarket() || o.Type == PendingOrderTypeStopLoss || o.Type == PendingOrderTypeTrailingStop
+}
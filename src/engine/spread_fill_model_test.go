@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpreadStats_At(t *testing.T) {
+	stats := SpreadStats{Samples: 30, Mean: 0.001}
+	stats.Hourly[3], stats.HourSamples[3] = 0.004, 10
+
+	assert.Equal(t, 0.004, stats.At(time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC)))
+	assert.Equal(t, 0.001, stats.At(time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC))) // 该小时没有样本
+	// 按 UTC 小时
+	assert.Equal(t, 0.004, stats.At(time.Date(2024, 1, 1, 11, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))))
+}
+
+func TestSpreadFillModel_Fill(t *testing.T) {
+	stats := SpreadStats{Samples: 10, Mean: 0.002} // 20bps，半个价差 10bps
+	model := NewSpreadFillModel(stats)
+	require.NoError(t, model.Validate())
+	assert.Error(t, NewSpreadFillModel(SpreadStats{}).Validate())
+
+	kline := CreateTestKlineWithPrices(time.Now(), decimal.NewFromInt(100), decimal.NewFromInt(110), decimal.NewFromInt(90), decimal.NewFromInt(100))
+	price := decimal.NewFromInt(100)
+
+	// 市价、止损、移动止损按吃单成交
+	decision := model.Fill(CreateTestPendingOrder(PendingOrderTypeBuyMarket, "buy", price), kline, price)
+	require.True(t, decision.Filled)
+	assert.True(t, decision.Price.Equal(decimal.NewFromFloat(100.1)), decision.Price.String())
+	for _, orderType := range []PendingOrderType{PendingOrderTypeSellMarket, PendingOrderTypeStopLoss, PendingOrderTypeTrailingStop} {
+		decision = model.Fill(CreateTestPendingOrder(orderType, "sell", price), kline, price)
+		assert.True(t, decision.Price.Equal(decimal.NewFromFloat(99.9)), "%s: %s", orderType, decision.Price.String())
+	}
+
+	// 限价单按挂单价成交
+	for _, orderType := range []PendingOrderType{PendingOrderTypeBuyLimit, PendingOrderTypeSellLimit} {
+		decision = model.Fill(CreateTestPendingOrder(orderType, "limit", price), kline, price)
+		require.True(t, decision.Filled)
+		assert.True(t, decision.Price.Equal(price), "%s", orderType)
+	}
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// StrategyStateStore 策略内部状态存储（实盘重启后恢复移动止盈最高价、分批止盈级别、冷却期等）
+type StrategyStateStore interface {
+	// LoadStrategyState 读取状态，不存在时返回 nil
+	LoadStrategyState(ctx context.Context, key string) ([]byte, error)
+
+	// SaveStrategyState 保存状态，已存在时覆盖
+	SaveStrategyState(ctx context.Context, key, strategyName string, state []byte) error
+}
+
+// StrategyState 保存的策略状态
+type StrategyState struct {
+	Strategy string          `json:"strategy"` // 策略名称，与当前策略不同时不恢复
+	State    json.RawMessage `json:"state,omitempty"`
+	SavedAt  time.Time       `json:"saved_at"`
+}
+
+// SetStrategyStateStore 设置策略状态存储：Run 开始时恢复 key 对应的状态，之后每根K线处理完成后保存
+func (e *TradingEngine) SetStrategyStateStore(store StrategyStateStore, key string) {
+	e.stateStore = store
+	e.stateKey = key
+}
+
+// restoreStrategyState 恢复保存的策略状态，策略名称不同时忽略（配置已更换策略）
+func (e *TradingEngine) restoreStrategyState(ctx context.Context) error {
+	if e.stateStore == nil {
+		return nil
+	}
+	_, logger := log.WithCtx(ctx)
+
+	data, err := e.stateStore.LoadStrategyState(ctx, e.stateKey)
+	if err != nil {
+		return fmt.Errorf("failed to load strategy state %s: %w", e.stateKey, err)
+	}
+	if data == nil {
+		logger.Info("没有保存的策略状态，从头开始", "key", e.stateKey)
+		return nil
+	}
+
+	var saved StrategyState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to decode strategy state %s: %w", e.stateKey, err)
+	}
+	if saved.Strategy != e.strategy.GetName() {
+		logger.Warning("保存的策略状态属于其他策略，从头开始", "key", e.stateKey, "saved", saved.Strategy, "current", e.strategy.GetName())
+		return nil
+	}
+	if err := e.strategy.Load(saved.State); err != nil {
+		return fmt.Errorf("failed to restore strategy state %s: %w", e.stateKey, err)
+	}
+	logger.Info("已恢复策略状态", "key", e.stateKey, "strategy", saved.Strategy, "saved_at", saved.SavedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// saveStrategyState 保存策略状态（失败只记录错误，不影响交易）
+func (e *TradingEngine) saveStrategyState(ctx context.Context) {
+	if e.stateStore == nil {
+		return
+	}
+	_, logger := log.WithCtx(ctx)
+
+	state, err := e.strategy.Save()
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(StrategyState{Strategy: e.strategy.GetName(), State: state, SavedAt: time.Now()})
+		if err == nil {
+			err = e.stateStore.SaveStrategyState(ctx, e.stateKey, e.strategy.GetName(), data)
+		}
+	}
+	if err != nil {
+		logger.Error("保存策略状态失败", "key", e.stateKey, "error", err)
+		e.publishError(ctx, "保存策略状态失败", err)
+	}
+}
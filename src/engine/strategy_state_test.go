@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStrategy 记录处理过的K线数量（内部状态）
+type countingStrategy struct {
+	name string
+	bars int
+}
+
+func (s *countingStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.bars++
+	return nil, nil
+}
+
+func (s *countingStrategy) GetName() string                                { return s.name }
+func (s *countingStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *countingStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *countingStrategy) Save() ([]byte, error)                          { return []byte(strconv.Itoa(s.bars)), nil }
+
+func (s *countingStrategy) Load(data []byte) error {
+	bars, err := strconv.Atoi(string(data))
+	s.bars = bars
+	return err
+}
+
+// memoryStateStore 内存中的策略状态存储
+type memoryStateStore struct {
+	states map[string][]byte
+	saves  int
+}
+
+func (m *memoryStateStore) LoadStrategyState(ctx context.Context, key string) ([]byte, error) {
+	return m.states[key], nil
+}
+
+func (m *memoryStateStore) SaveStrategyState(ctx context.Context, key, strategyName string, state []byte) error {
+	m.states[key] = state
+	m.saves++
+	return nil
+}
+
+func TestTradingEngine_StrategyState(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(testStrategy strategy.Strategy, store StrategyStateStore) error {
+		var klines []*cex.KlineData
+		for i := 0; i < 3; i++ {
+			price := decimal.NewFromInt(100)
+			klines = append(klines, CreateTestKlineWithPrices(startTime.Add(time.Duration(i)*4*time.Hour), price, price, price, price))
+		}
+		engine := createTestTradingEngineWithMocks(testStrategy, newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero),
+			&mockTradingDataFeed{klines: klines}, &mockTradingOrderManager{})
+		engine.SetStrategyStateStore(store, "live_mock_BTCUSDT_4h")
+		return engine.Run(context.Background())
+	}
+
+	store := &memoryStateStore{states: map[string][]byte{}}
+	first := &countingStrategy{name: "counting"}
+	require.NoError(t, run(first, store))
+	assert.Equal(t, 3, first.bars)
+	assert.Equal(t, 3, store.saves, "state saved after every kline")
+
+	var saved StrategyState
+	require.NoError(t, json.Unmarshal(store.states["live_mock_BTCUSDT_4h"], &saved))
+	assert.Equal(t, "counting", saved.Strategy)
+	assert.Equal(t, "3", string(saved.State))
+
+	// 重启后从保存的状态继续
+	restarted := &countingStrategy{name: "counting"}
+	require.NoError(t, run(restarted, store))
+	assert.Equal(t, 6, restarted.bars)
+
+	// 换成其他策略时不恢复
+	other := &countingStrategy{name: "other"}
+	require.NoError(t, run(other, store))
+	assert.Equal(t, 3, other.bars)
+
+	// 状态无法恢复时不启动
+	store.states["live_mock_BTCUSDT_4h"] = []byte(`{"strategy": "counting", "state": "\"x\""}`)
+	assert.Error(t, run(&countingStrategy{name: "counting"}, store))
+}
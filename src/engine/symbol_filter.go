@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// SymbolFilterStore 交易对下单规则的本地存储（交易所不可用时回退）
+type SymbolFilterStore interface {
+	// LoadSymbolFilters 读取下单规则，不存在时返回 nil
+	LoadSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error)
+
+	// SaveSymbolFilters 保存从交易所获取的下单规则
+	SaveSymbolFilters(ctx context.Context, pair cex.TradingPair, filters *cex.SymbolFilters) error
+}
+
+// SymbolFilterService 交易对下单规则：下单前按步长/最小变动单位取整数量和价格，
+// 拒绝低于最小下单量、最小下单金额的订单，避免被交易所拒单
+type SymbolFilterService struct {
+	client cex.CEXClient
+	store  SymbolFilterStore // 为空时只从交易所获取
+
+	mu      sync.RWMutex
+	filters map[cex.TradingPair]*cex.SymbolFilters
+}
+
+// NewSymbolFilterService 创建下单规则服务
+func NewSymbolFilterService(client cex.CEXClient, store SymbolFilterStore) *SymbolFilterService {
+	return &SymbolFilterService{
+		client:  client,
+		store:   store,
+		filters: make(map[cex.TradingPair]*cex.SymbolFilters),
+	}
+}
+
+// Load 加载交易对下单规则：优先从交易所获取并写入本地存储，交易所不支持或请求失败时使用本地存储
+func (s *SymbolFilterService) Load(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	ctx, logger := log.WithCtx(ctx)
+
+	var exchangeErr error
+	if provider, ok := s.client.(cex.SymbolFilterProvider); ok {
+		filters, err := provider.GetSymbolFilters(ctx, pair)
+		if err == nil {
+			if s.store != nil {
+				if err := s.store.SaveSymbolFilters(ctx, pair, filters); err != nil {
+					logger.Warning("保存交易对下单规则失败", "symbol", pair.String(), "error", err)
+				}
+			}
+			s.SetFilters(pair, filters)
+			return filters, nil
+		}
+		exchangeErr = err
+		logger.Warning("从交易所获取交易对下单规则失败，使用本地存储", "symbol", pair.String(), "error", err)
+	} else {
+		exchangeErr = fmt.Errorf("%s does not provide symbol filters", s.client.GetName())
+	}
+
+	if s.store == nil {
+		return nil, exchangeErr
+	}
+	filters, err := s.store.LoadSymbolFilters(ctx, pair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol filters for %s: %w", pair.String(), err)
+	}
+	if filters == nil {
+		return nil, fmt.Errorf("no symbol filters for %s: %w", pair.String(), exchangeErr)
+	}
+	s.SetFilters(pair, filters)
+	return filters, nil
+}
+
+// SetFilters 设置交易对下单规则
+func (s *SymbolFilterService) SetFilters(pair cex.TradingPair, filters *cex.SymbolFilters) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filters[pair] = filters
+}
+
+// Filters 获取交易对下单规则（未加载或服务为空时为空）
+func (s *SymbolFilterService) Filters(pair cex.TradingPair) *cex.SymbolFilters {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filters[pair]
+}
+
+// NormalizeOrder 按下单规则取整挂单数量和价格（未加载规则的交易对原样保留），
+// 不满足最小下单量或最小下单金额时返回 cex.ErrBelowMinQuantity / cex.ErrBelowMinNotional
+func (s *SymbolFilterService) NormalizeOrder(order *PendingOrder) error {
+	filters := s.Filters(order.TradingPair)
+	if filters == nil {
+		return nil
+	}
+	order.Quantity = filters.RoundQuantity(order.Quantity)
+	order.Price = filters.RoundPrice(order.Price, order.side())
+	return filters.Check(order.Quantity, order.Price)
+}
+
+// RoundPrice 按最小变动单位取整价格（未加载规则的交易对原样返回）
+func (s *SymbolFilterService) RoundPrice(pair cex.TradingPair, price decimal.Decimal, side cex.OrderSide) decimal.Decimal {
+	filters := s.Filters(pair)
+	if filters == nil {
+		return price
+	}
+	return filters.RoundPrice(price, side)
+}
+
+// side 挂单方向：只有买入限价单和市价买单是买单
+func (o *PendingOrder) side() cex.OrderSide {
+	if o.Type == PendingOrderTypeBuyLimit || o.Type == PendingOrderTypeBuyMarket {
+		return cex.OrderSideBuy
+	}
+	return cex.OrderSideSell
+}
+
+// SetSymbolFilters 设置交易对下单规则，新挂单按规则取整
+func (e *TradingEngine) SetSymbolFilters(filters *SymbolFilterService) {
+	e.symbolFilters = filters
+}
+
+// placeOrder 按交易对下单规则取整后下挂单，不满足规则或被风控拒绝的挂单跳过
+func (e *TradingEngine) placeOrder(ctx context.Context, order *PendingOrder) error {
+	if !e.normalizeOrder(ctx, order) || !e.checkRisk(ctx, order) {
+		return nil
+	}
+	return e.submitOrder(ctx, order)
+}
+
+// submitOrder 下挂单并发布 order_placed 事件（调用方已完成取整和风控检查）
+func (e *TradingEngine) submitOrder(ctx context.Context, order *PendingOrder) error {
+	if err := e.orderManager.PlaceOrder(ctx, order); err != nil {
+		return err
+	}
+	e.trackProtectedEntry(order)
+	e.recordSignalOrder(order)
+	e.events.Publish(ctx, &Event{Type: EventOrderPlaced, Time: order.CreateTime, TradingPair: order.TradingPair, Order: order})
+	return nil
+}
+
+// normalizeOrder 按交易对下单规则取整挂单，低于最小下单量或最小下单金额时记录日志并返回 false
+func (e *TradingEngine) normalizeOrder(ctx context.Context, order *PendingOrder) bool {
+	_, logger := log.WithCtx(ctx)
+
+	if err := e.symbolFilters.NormalizeOrder(order); err != nil {
+		logger.Warning(fmt.Sprintf("🚫 挂单不满足交易所下单规则，跳过: id=%s, type=%s, qty=%s, price=%s, reason=%v",
+			order.ID, order.Type, order.Quantity.String(), order.Price.String(), err))
+		return false
+	}
+	return true
+}
+
+// SetSymbolFilters 设置交易对下单规则，重挂止损单时按规则取整触发价
+func (m *LiveOrderManager) SetSymbolFilters(filters *SymbolFilterService) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.symbolFilters = filters
+}
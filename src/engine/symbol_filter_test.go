@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSymbolFilterClient 支持查询下单规则的CEX客户端mock
+type mockSymbolFilterClient struct {
+	MockCEXClient
+	filters *cex.SymbolFilters
+	err     error
+}
+
+func (m *mockSymbolFilterClient) GetSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	return m.filters, m.err
+}
+
+// memorySymbolFilterStore 内存下单规则存储
+type memorySymbolFilterStore struct {
+	filters map[cex.TradingPair]*cex.SymbolFilters
+}
+
+func (s *memorySymbolFilterStore) LoadSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	return s.filters[pair], nil
+}
+
+func (s *memorySymbolFilterStore) SaveSymbolFilters(ctx context.Context, pair cex.TradingPair, filters *cex.SymbolFilters) error {
+	s.filters[pair] = filters
+	return nil
+}
+
+func newTestSymbolFilters() *cex.SymbolFilters {
+	return &cex.SymbolFilters{
+		Symbol:      "BTCUSDT",
+		MinQty:      decimal.RequireFromString("0.00001"),
+		StepSize:    decimal.RequireFromString("0.00001"),
+		TickSize:    decimal.RequireFromString("0.01"),
+		MinNotional: decimal.NewFromInt(5),
+	}
+}
+
+func TestSymbolFilterService_LoadFallsBackToStore(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	store := &memorySymbolFilterStore{filters: make(map[cex.TradingPair]*cex.SymbolFilters)}
+	client := &mockSymbolFilterClient{filters: newTestSymbolFilters()}
+
+	// 从交易所获取并写入本地存储
+	filters, err := NewSymbolFilterService(client, store).Load(context.Background(), pair)
+	require.NoError(t, err)
+	assert.Equal(t, "0.01", filters.TickSize.String())
+	assert.NotNil(t, store.filters[pair])
+
+	// 交易所请求失败时使用本地存储
+	client.err = errors.New("timeout")
+	service := NewSymbolFilterService(client, store)
+	filters, err = service.Load(context.Background(), pair)
+	require.NoError(t, err)
+	assert.Equal(t, filters, service.Filters(pair))
+
+	// 两者都不可用
+	_, err = NewSymbolFilterService(client, nil).Load(context.Background(), pair)
+	assert.Error(t, err)
+}
+
+func TestTradingEngine_RoundsOrdersToSymbolFilters(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	orderManager := &mockTradingOrderManager{}
+	service := NewSymbolFilterService(&MockCEXClient{}, nil)
+	service.SetFilters(pair, newTestSymbolFilters())
+
+	engine := &TradingEngine{
+		orderManager:        orderManager,
+		tradingPair:         pair,
+		positionSizePercent: decimal.NewFromFloat(0.95),
+		minTradeAmount:      decimal.NewFromInt(1),
+	}
+	engine.SetSymbolFilters(service)
+
+	kline := CreateTestKlineWithPrices(time.Now(),
+		decimal.NewFromInt(43000), decimal.NewFromInt(43500), decimal.NewFromInt(42500), decimal.RequireFromString("43210.57"))
+	signal := &strategy.Signal{Type: "BUY", Strength: 1, Reason: "test"}
+
+	// 买单价格向下取整到 0.01，数量向下取整到 0.00001
+	portfolio := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	require.NoError(t, engine.handleBuySignal(context.Background(), signal, kline, portfolio))
+	require.Len(t, orderManager.placedOrders, 1)
+	order := orderManager.placedOrders[0]
+	assert.Equal(t, "43167.35", order.Price.String())
+	assert.Equal(t, "0.022", order.Quantity.String())
+
+	// 卖单金额低于最小下单金额，不提交
+	portfolio = &executor.Portfolio{Cash: decimal.NewFromInt(1000), Position: decimal.RequireFromString("0.0001")}
+	signal = &strategy.Signal{Type: "SELL", Strength: 1, Reason: "test"}
+	require.NoError(t, engine.handleSellSignal(context.Background(), signal, kline, portfolio))
+	assert.Len(t, orderManager.placedOrders, 1)
+}
@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+)
+
+// defaultOrderValidity 信号挂单默认有效期
+const defaultOrderValidity = 24 * time.Hour
+
+// SetTimeInForce 设置信号挂单的默认有效方式和 GTD 有效期（信号指定时以信号为准），validity 为 0 时为 24 小时
+func (e *TradingEngine) SetTimeInForce(tif cex.TimeInForce, validity time.Duration) {
+	e.timeInForce = tif
+	e.orderValidity = validity
+}
+
+// orderTimeInForce 信号挂单的有效方式和过期时间：GTC 不过期，IOC/FOK 只在下一根K线撮合，GTD（默认）在有效期后过期
+func (e *TradingEngine) orderTimeInForce(signal *strategy.Signal, createTime time.Time) (cex.TimeInForce, *time.Time) {
+	tif := signal.TimeInForce
+	if tif == "" {
+		tif = e.timeInForce
+	}
+	if tif == "" {
+		tif = cex.TimeInForceGTD
+	}
+	if tif != cex.TimeInForceGTD {
+		return tif, nil
+	}
+
+	validity := signal.ValidFor
+	if validity <= 0 {
+		validity = e.orderValidity
+	}
+	if validity <= 0 {
+		validity = defaultOrderValidity
+	}
+	expireTime := createTime.Add(validity)
+	return tif, &expireTime
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacktestOrderManager_TimeInForce(t *testing.T) {
+	ctx := context.Background()
+	limit := decimal.NewFromInt(50000)
+	above := decimal.NewFromInt(51000)
+	below := decimal.NewFromInt(49000)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	place := func(manager *BacktestOrderManager, id string, tif cex.TimeInForce) {
+		order := CreateTestPendingOrder(PendingOrderTypeBuyLimit, id, limit)
+		order.TimeInForce = tif
+		require.NoError(t, manager.PlaceOrder(ctx, order))
+	}
+
+	// 未触及限价：IOC/FOK 撤销，GTC 保留
+	manager := NewBacktestOrderManager(newMockOrderExecutor(decimal.NewFromInt(1000000), decimal.Zero))
+	place(manager, "ioc", cex.TimeInForceIOC)
+	place(manager, "fok", cex.TimeInForceFOK)
+	place(manager, "gtc", cex.TimeInForceGTC)
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, above, above, above, above))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	require.Equal(t, 1, manager.GetOrderCount())
+	assert.Equal(t, "gtc", manager.GetPendingOrders()[0].ID)
+
+	// 只能部分成交：IOC 成交可成交部分后撤销剩余，FOK 整单撤销
+	mockExec := newMockOrderExecutor(decimal.NewFromInt(1000000), decimal.Zero)
+	mockExec.buyFillLimit = decimal.NewFromFloat(0.4)
+	manager = NewBacktestOrderManager(mockExec)
+	place(manager, "ioc", cex.TimeInForceIOC)
+	results, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, below, below, below, below))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, decimal.NewFromFloat(0.4).Equal(results[0].Quantity))
+	assert.True(t, decimal.NewFromFloat(0.6).Equal(results[0].RemainingQuantity))
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	manager = NewBacktestOrderManager(newMockOrderExecutor(decimal.NewFromInt(1000000), decimal.Zero))
+	manager.SetFillModel(NewVolumeCapFillModel(0.0004, true, 1)) // 成交量 1000 × 0.04% = 0.4
+	place(manager, "fok", cex.TimeInForceFOK)
+	results, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, below, below, below, below))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, 0, manager.GetOrderCount())
+
+	// 能全部成交时 FOK 正常成交
+	manager = NewBacktestOrderManager(newMockOrderExecutor(decimal.NewFromInt(1000000), decimal.Zero))
+	place(manager, "fok", cex.TimeInForceFOK)
+	results, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(start, below, below, below, below))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, decimal.NewFromInt(1).Equal(results[0].Quantity))
+}
+
+func TestTradingEngine_OrderTimeInForce(t *testing.T) {
+	engine := &TradingEngine{}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 默认 GTD 24 小时
+	tif, expire := engine.orderTimeInForce(&strategy.Signal{Type: "BUY"}, created)
+	assert.Equal(t, cex.TimeInForceGTD, tif)
+	require.NotNil(t, expire)
+	assert.Equal(t, created.Add(24*time.Hour), *expire)
+
+	// 引擎默认值
+	engine.SetTimeInForce(cex.TimeInForceGTD, 4*time.Hour)
+	_, expire = engine.orderTimeInForce(&strategy.Signal{Type: "BUY"}, created)
+	assert.Equal(t, created.Add(4*time.Hour), *expire)
+
+	// 信号指定时以信号为准
+	_, expire = engine.orderTimeInForce(&strategy.Signal{Type: "BUY", ValidFor: time.Hour}, created)
+	assert.Equal(t, created.Add(time.Hour), *expire)
+
+	tif, expire = engine.orderTimeInForce(&strategy.Signal{Type: "BUY", TimeInForce: cex.TimeInForceIOC}, created)
+	assert.Equal(t, cex.TimeInForceIOC, tif)
+	assert.Nil(t, expire)
+
+	engine.SetTimeInForce(cex.TimeInForceGTC, 0)
+	tif, expire = engine.orderTimeInForce(&strategy.Signal{Type: "SELL"}, created)
+	assert.Equal(t, cex.TimeInForceGTC, tif)
+	assert.Nil(t, expire)
+}
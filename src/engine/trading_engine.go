@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"tradingbot/src/cex"
 	"tradingbot/src/executor"
+	"tradingbot/src/i18n"
 	"tradingbot/src/strategy"
 	"tradingbot/src/timeframes"
 
@@ -15,11 +18,11 @@ import (
 	"github.com/xpwu/go-log/log"
 )
 
-// generateShortOrderID 生成简短的订单ID
+// generateShortOrderID 生成简短的订单ID：前缀_基础资产_8位hex，日志和数据库中可直接看出交易对
 func generateShortOrderID(prefix string, base string) string {
 	fullID := fmt.Sprintf("%s_%d_%s", prefix, time.Now().UnixNano(), base)
 	hash := md5.Sum([]byte(fullID))
-	return fmt.Sprintf("%s_%x", prefix, hash[:4]) // 取前8个字符的hex
+	return fmt.Sprintf("%s_%s_%x", prefix, base, hash[:4])
 }
 
 // TradingEngine 统一的交易引擎（支持回测和实盘）
@@ -33,17 +36,73 @@ type TradingEngine struct {
 	// 配置
 	positionSizePercent decimal.Decimal
 	minTradeAmount      decimal.Decimal
+	positionSizer       PositionSizer    // 仓位计算器（为空时按 positionSizePercent 固定比例）
+	timeInForce         cex.TimeInForce  // 信号挂单默认有效方式（为空时为 GTD）
+	orderValidity       time.Duration    // GTD 挂单默认有效期（为 0 时为 24 小时）
+	chasePolicy         OrderChasePolicy // 未成交买入挂单的追价策略（默认不追价）
+	signalThrottle      SignalThrottle   // 引擎层信号节流（默认不节流）
+
+	// 各方向信号最近一次生成挂单的时间（信号冷却的起点）
+	lastSignalOrder map[string]time.Time
+
+	// 带信号止损/止盈价的开仓挂单（成交后挂出保护单）
+	protectedEntries map[string]*PendingOrder
 
 	// 统一数据喂入和挂单管理
 	dataFeed     DataFeed
 	orderManager OrderManager
 
+	// 交易日历（禁止交易时段不生成新挂单）
+	calendar *TradingCalendar
+
+	// 交易对下单规则（为空时不取整）
+	symbolFilters *SymbolFilterService
+
+	// 全局风控（为空时不检查）
+	riskManager *RiskManager
+
+	// 事件总线（为空时不发布）
+	events *EventBus
+
+	// 只发信号模式：不下单，策略按假想持仓运行
+	signalOnly      bool
+	signalPortfolio *executor.Portfolio
+
 	// 运行状态
-	isRunning bool
+	isRunning atomic.Bool
 	stopChan  chan struct{}
+	stopOnce  sync.Once // TUI 退出键和 Ctrl+C 可能先后调用 Stop
+
+	// K线处理和手动操作（暂停开仓、清仓）互斥
+	mu sync.Mutex
 
 	// K线数据存储（用于回撤计算等）
 	lastKlines []*cex.KlineData
+
+	// 最多保留的最近K线数量（0 表示保留全部，流式回测时限制内存）
+	klineWindow int
+
+	// 资金曲线（每根K线记录一次）
+	equityCurve []EquityPoint
+
+	// 组合价值的峰值和回撤（每根K线随资金曲线更新）
+	drawdown *DrawdownTracker
+
+	// 策略状态存储（为空时不保存，回测不使用）
+	stateStore StrategyStateStore
+	stateKey   string
+
+	// 实盘延迟统计（为空时不统计，回测不使用）
+	latency *LatencyMonitor
+
+	// 实盘数据喂入看门狗（为空时不检查，回测不使用）
+	watchdog *FeedWatchdog
+
+	// 回测断点：每 checkpointEvery 根K线写入 checkpointPath（为空时不写），resume 为待恢复的断点
+	checkpointPath  string
+	checkpointEvery int
+	checkpointKey   string
+	resume          *BacktestCheckpoint
 }
 
 // NewTradingEngine 创建交易引擎
@@ -77,11 +136,27 @@ func (e *TradingEngine) SetPositionSizePercent(percent float64) {
 	e.positionSizePercent = decimal.NewFromFloat(percent)
 }
 
+// SetPositionSizer 设置仓位计算器
+func (e *TradingEngine) SetPositionSizer(sizer PositionSizer) {
+	e.positionSizer = sizer
+}
+
 // SetMinTradeAmount 设置最小交易金额
 func (e *TradingEngine) SetMinTradeAmount(amount float64) {
 	e.minTradeAmount = decimal.NewFromFloat(amount)
 }
 
+// SetTradingCalendar 设置交易日历
+func (e *TradingEngine) SetTradingCalendar(calendar *TradingCalendar) {
+	e.calendar = calendar
+}
+
+// SetKlineWindow 设置最多保留的最近K线数量（0 表示保留全部）
+// 保留窗口需覆盖策略辅助计算（ATR止损、波动率仓位）所需的回看长度，GetKlines 只返回窗口内的K线
+func (e *TradingEngine) SetKlineWindow(window int) {
+	e.klineWindow = window
+}
+
 // RunBacktest 运行回测（使用统一的数据喂入机制）
 func (e *TradingEngine) RunBacktest(ctx context.Context, startTime, endTime time.Time) error {
 	return e.Run(ctx)
@@ -93,19 +168,23 @@ func (e *TradingEngine) RunLive(ctx context.Context) error {
 }
 
 // Run 统一的运行方法（支持回测和实盘）
-func (e *TradingEngine) Run(ctx context.Context) error {
+func (e *TradingEngine) Run(ctx context.Context) (err error) {
 	ctx, logger := log.WithCtx(ctx)
 	logger.PushPrefix("TradingEngine")
 
-	logger.Info("🚀 开始交易引擎",
-		"trading_symbol", e.tradingPair.String(),
-		"timeframe", e.timeframe.String())
+	logger.Info(i18n.T("engine.start",
+		e.tradingPair.String(), e.timeframe.String()))
 
-	e.isRunning = true
-	defer func() { e.isRunning = false }()
+	e.isRunning.Store(true)
+	defer e.isRunning.Store(false)
+
+	// 恢复重启前的策略状态（在第一根K线之前）
+	if err := e.restoreStrategyState(ctx); err != nil {
+		return err
+	}
 
 	// 启动数据喂入
-	err := e.dataFeed.Start(ctx)
+	err = e.dataFeed.Start(ctx)
 	if err != nil {
 		return fmt.Errorf("启动数据喂入失败: %w", err)
 	}
@@ -113,78 +192,84 @@ func (e *TradingEngine) Run(ctx context.Context) error {
 
 	var klineCount int
 	var allKlines []*cex.KlineData
+	e.equityCurve = nil
+	e.drawdown = e.newDrawdownTracker(ctx)
+
+	// 从回测断点恢复，断点及之前的K线不再处理
+	resume, err := e.restoreCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	if resume != nil {
+		klineCount = resume.KlineCount
+	}
+
+	// 引擎退出时发布 engine_stopped；数据喂入等环节 panic 时恢复为错误返回，由上层决定是否重启
+	stopReason := "data feed finished"
+	defer func() {
+		if panicErr := e.panicError(ctx, "引擎运行", recover()); panicErr != nil {
+			err = panicErr
+			stopReason = panicErr.Error()
+		}
+		e.events.Publish(ctx, &Event{Type: EventEngineStopped, Time: time.Now(), TradingPair: e.tradingPair, Message: stopReason})
+	}()
 
 	for {
 		select {
 		case <-ctx.Done():
-			logger.Info("收到停止信号，退出交易")
+			logger.Info(i18n.T("engine.ctx_done"))
+			stopReason = "context cancelled"
 			return ctx.Err()
 
 		case <-e.stopChan:
-			logger.Info("手动停止交易")
+			logger.Info(i18n.T("engine.stopped"))
+			stopReason = "stopped manually"
 			goto finished
 
 		default:
 			// 获取下一个K线数据
 			kline, err := e.dataFeed.GetNext(ctx)
 			if err != nil {
-				logger.Error("获取K线数据失败", "error", err)
+				logger.Error(i18n.T("engine.kline_failed"), "error", err)
 				continue
 			}
 
 			if kline == nil {
-				logger.Info("数据流结束")
+				logger.Info(i18n.T("engine.feed_finished"))
 				goto finished
 			}
+			e.latency.ObserveKline(ctx, kline, time.Now())
+			e.watchdog.ObserveKline(ctx, kline, time.Now())
+
+			// 手动操作（暂停开仓、清仓）在两根K线之间执行
+			e.mu.Lock()
 
 			// 存储K线数据
 			allKlines = append(allKlines, kline)
-			klineCount++
-
-			// 1️⃣ 首先检查并执行挂单
-			_, err = e.orderManager.CheckAndExecuteOrders(ctx, kline)
-			if err != nil {
-				logger.Error("检查挂单失败", "error", err)
+			if e.klineWindow > 0 && len(allKlines) >= 2*e.klineWindow {
+				// 达到窗口两倍时复制最近窗口，释放旧K线
+				allKlines = append([]*cex.KlineData(nil), allKlines[len(allKlines)-e.klineWindow:]...)
 			}
-
-			// 2️⃣ 获取当前投资组合状态
-			portfolio, err := e.executor.GetPortfolio(ctx)
-			if err != nil {
-				logger.Error("获取投资组合失败", "error", err)
+			e.lastKlines = allKlines
+			if resume != nil && !kline.OpenTime.After(resume.KlineTime) {
+				e.mu.Unlock()
 				continue
 			}
+			klineCount++
 
-			// 更新时间
-			portfolio.Timestamp = kline.OpenTime
-
-			// 3️⃣ 执行策略分析
-			// 删除频繁的策略分析日志
-
-			signals, err := e.strategy.OnData(ctx, kline, portfolio)
+			err = e.runKline(ctx, kline, klineCount)
+			e.mu.Unlock()
 			if err != nil {
-				logger.Error("❌ 策略执行失败", "error", err)
-				continue
-			}
-
-			// 信号处理详情在下方的信号循环中记录
-
-			// 4️⃣ 处理交易信号（生成新挂单）
-			for _, signal := range signals {
-				logger.Info("")  // 空行分隔
-				logger.Info(fmt.Sprintf("🎯 %s信号: %s (强度%.1f)", 
-					signal.Type, signal.Reason, signal.Strength))
-
-				err := e.processSignal(ctx, signal, kline, portfolio)
-				if err != nil {
-					logger.Error("❌ 处理交易信号失败", "error", err)
-				}
+				e.publishError(ctx, "K线处理失败", err)
+				stopReason = err.Error()
+				return err
 			}
 
 			// 定期输出进度 - 降低频率，只在重要节点显示
 			if klineCount%200 == 0 && klineCount > 0 {
 				logger.Info("")  // 空行分隔
-				logger.Info(fmt.Sprintf("📈 回测进度: %d根K线已处理, 时间: %s", 
-					klineCount, e.dataFeed.GetCurrentTime().Format("2006-01-02")))
+				logger.Info(i18n.T("engine.progress", 
+					klineCount, e.dataFeed.GetCurrentTime().Format("2006-01-02"), e.GetOpenOrderCounts()))
 			}
 		}
 	}
@@ -192,14 +277,125 @@ func (e *TradingEngine) Run(ctx context.Context) error {
 finished:
 	// 保存K线数据供后续使用（如回撤计算）
 	e.lastKlines = allKlines
-	logger.Info(fmt.Sprintf("交易完成: total_klines=%d", len(allKlines)))
+	logger.Info(i18n.T("engine.finished", klineCount))
 	return nil
 }
 
+// processKline 处理一根K线：撮合挂单、更新资金曲线和风控、执行策略并处理信号（调用方需持有 e.mu）
+func (e *TradingEngine) processKline(ctx context.Context, kline *cex.KlineData) {
+	ctx, logger := log.WithCtx(ctx)
+
+	// 1️⃣ 首先检查并执行挂单
+	executed, err := e.checkOrders(ctx, kline)
+	if err != nil {
+		logger.Error(i18n.T("engine.check_failed"), "error", err)
+		if IsPanic(err) {
+			e.publishError(ctx, "挂单撮合失败", err)
+		}
+	}
+
+	// 计提这根K线期间的资金成本（回测配置了资金成本模型时）
+	e.accrueCarryingCost(kline)
+
+	// 2️⃣ 获取当前投资组合状态
+	portfolio, err := e.executor.GetPortfolio(ctx)
+	if err != nil {
+		logger.Error(i18n.T("engine.portfolio_failed"), "error", err)
+		e.publishError(ctx, "获取投资组合失败", err)
+		return
+	}
+	if e.signalOnly {
+		portfolio = e.signalOnlyPortfolio(portfolio)
+	}
+
+	// 记录资金曲线和回撤（挂单成交后的状态）
+	point := newEquityPoint(kline, portfolio)
+	e.equityCurve = append(e.equityCurve, point)
+	e.drawdown.Update(kline, point.PortfolioValue)
+
+	e.publishFills(ctx, executed, kline, portfolio)
+
+	// 风控：记录成交盈亏，单日亏损或连续亏损超限时熔断并撤销全部挂单
+	e.updateRisk(ctx, executed, kline, portfolio)
+
+	// 开仓成交后立即挂出止盈阶梯（策略启用时）
+	if err := e.syncTakeProfitLadder(ctx, executed, kline, portfolio); err != nil {
+		logger.Error(i18n.T("engine.ladder_failed"), "error", err)
+		e.publishError(ctx, "止盈阶梯挂单失败", err)
+	}
+
+	// 开仓成交后挂出移动止损单（策略启用时）
+	if err := e.syncTrailingStop(ctx, executed, kline, portfolio); err != nil {
+		logger.Error(i18n.T("engine.trailing_failed"), "error", err)
+		e.publishError(ctx, "移动止损挂单失败", err)
+	}
+
+	// 开仓成交后按信号的止损/止盈价挂出保护单
+	if err := e.syncSignalProtection(ctx, executed, kline, portfolio); err != nil {
+		logger.Error(i18n.T("engine.protect_failed"), "error", err)
+		e.publishError(ctx, "信号保护单挂单失败", err)
+	}
+
+	// 开仓成交后挂出OCO止盈/止损（策略启用时）
+	if err := e.syncOCO(ctx, executed, kline, portfolio); err != nil {
+		logger.Error(i18n.T("engine.oco_failed"), "error", err)
+		e.publishError(ctx, "OCO挂单失败", err)
+	}
+
+	// 信号买入挂单连续未成交时追价重挂（策略启用时）
+	e.chaseUnfilledOrders(ctx, kline)
+
+	// 更新时间
+	portfolio.Timestamp = kline.OpenTime
+
+	// 3️⃣ 执行策略分析
+	// 删除频繁的策略分析日志
+
+	signals, err := e.callStrategy(ctx, kline, portfolio)
+	if err != nil {
+		logger.Error(i18n.T("engine.strategy_failed"), "error", err)
+		e.publishError(ctx, "策略执行失败", err)
+		return
+	}
+
+	// 信号处理详情在下方的信号循环中记录
+	indicators := e.signalIndicators()
+	for _, signal := range signals {
+		e.events.Publish(ctx, &Event{Type: EventSignalGenerated, Time: kline.CloseTime, TradingPair: e.tradingPair,
+			Kline: kline, Signal: signal, Indicators: indicators})
+	}
+
+	// 4️⃣ 处理交易信号（生成新挂单）
+	// 下单时刻交易暂停则无法下单
+	if len(signals) > 0 {
+		orderTime := e.orderTime(kline)
+		if allowed, reason := e.calendar.IsTradingAllowed(orderTime); !allowed {
+			logger.Info(i18n.T("engine.paused",
+				len(signals), orderTime.Format("2006-01-02 15:04"), reason))
+			signals = nil
+		}
+	}
+
+	for _, signal := range signals {
+		logger.Info("")  // 空行分隔
+		logger.Info(i18n.T("engine.signal", 
+			signal.Type, e.tradingPair.String(), signal.Reason, signal.Strength))
+
+		if err := e.handleSignal(ctx, signal, kline, portfolio); err != nil {
+			logger.Error(i18n.T("engine.signal_failed"), "error", err)
+			e.publishError(ctx, "处理交易信号失败", err)
+		}
+	}
+
+	e.events.Publish(ctx, &Event{Type: EventKlineProcessed, Time: kline.CloseTime, TradingPair: e.tradingPair,
+		Kline: kline, Portfolio: portfolio, Indicators: indicators, PendingOrders: e.pendingOrderSnapshot(),
+		Latency: e.latency.Snapshot()})
+}
+
 // Stop 停止交易引擎
 func (e *TradingEngine) Stop() {
-	if e.isRunning {
-		close(e.stopChan)
+	if e.isRunning.Load() {
+		e.stopOnce.Do(func() { close(e.stopChan) })
 	}
 }
 
@@ -208,12 +404,57 @@ func (e *TradingEngine) GetKlines() []*cex.KlineData {
 	return e.lastKlines
 }
 
+// GetDrawdown 获取截至最近一根K线的回撤统计（按每根K线收盘时的组合价值，含手续费和资金成本）
+func (e *TradingEngine) GetDrawdown() DrawdownInfo {
+	if e.drawdown == nil {
+		return DrawdownInfo{}
+	}
+	return e.drawdown.Drawdown()
+}
+
+// newDrawdownTracker 以运行开始时的账户现金为初始峰值；已有持仓时无法估值，以第一根K线收盘时的组合价值为起点
+func (e *TradingEngine) newDrawdownTracker(ctx context.Context) *DrawdownTracker {
+	portfolio, err := e.executor.GetPortfolio(ctx)
+	if err != nil || !portfolio.Position.IsZero() {
+		return NewDrawdownTracker(decimal.Zero)
+	}
+	return NewDrawdownTracker(portfolio.Cash)
+}
+
+// GetOpenOrderCounts 获取每个交易对的挂单数量
+func (e *TradingEngine) GetOpenOrderCounts() map[string]int {
+	if counter, ok := e.orderManager.(OpenOrderCounter); ok {
+		return counter.GetOpenOrderCounts()
+	}
+	return map[string]int{e.tradingPair.String(): e.orderManager.GetOrderCount()}
+}
+
+// GetEquityCurve 获取资金曲线（每根K线的现金、持仓和组合价值）
+func (e *TradingEngine) GetEquityCurve() []EquityPoint {
+	return e.equityCurve
+}
+
+// orderTime 信号对应的下单时刻
+// 回测中信号在K线收盘时产生；实盘中K线尚未收盘，以当前时间为准
+func (e *TradingEngine) orderTime(kline *cex.KlineData) time.Time {
+	current := e.dataFeed.GetCurrentTime()
+	if current.After(kline.OpenTime) && current.Before(kline.CloseTime) {
+		return current
+	}
+	return kline.CloseTime
+}
+
 // processSignal 处理交易信号（统一生成挂单）
 func (e *TradingEngine) processSignal(ctx context.Context, signal *strategy.Signal, kline *cex.KlineData, portfolio *executor.Portfolio) error {
 	ctx, logger := log.WithCtx(ctx)
 
-	logger.Info(fmt.Sprintf("📋 处理交易信号: type=%s, reason=%s, strength=%.1f, price=%s", 
-		signal.Type, signal.Reason, signal.Strength, kline.Close.String()))
+	logger.Info(i18n.T("engine.process_signal", 
+		kline.TradingPair.String(), signal.Type, signal.Reason, signal.Strength, kline.Close.String()))
+
+	if reason := e.throttleSignal(signal, kline); reason != "" {
+		logger.Info(i18n.T("engine.throttled", signal.Type, reason))
+		return nil
+	}
 
 	switch signal.Type {
 	case "BUY":
@@ -229,40 +470,68 @@ func (e *TradingEngine) processSignal(ctx context.Context, signal *strategy.Sign
 func (e *TradingEngine) handleBuySignal(ctx context.Context, signal *strategy.Signal, kline *cex.KlineData, portfolio *executor.Portfolio) error {
 	ctx, logger := log.WithCtx(ctx)
 
-	// 计算买入数量
+	// 设置买入限价：默认比当前价格低0.1%（更优价格）；市价单以当前价格计算数量
+	orderType, limitPrice := signalOrderPrice(signal, kline.Close, cex.OrderSideBuy)
+
+	// 计算买入金额（不超过可用现金）
 	availableCash := portfolio.Cash
-	tradeAmount := availableCash.Mul(e.positionSizePercent)
+	tradeAmount := decimal.Min(e.sizer().Size(SizingInput{
+		Portfolio:  portfolio,
+		EntryPrice: limitPrice,
+		Klines:     e.lastKlines,
+		Signal:     signal,
+	}), availableCash)
 
 	if tradeAmount.LessThan(e.minTradeAmount) {
-		logger.Info(fmt.Sprintf("交易金额过小，跳过买入: amount=%s, min=%s", tradeAmount.String(), e.minTradeAmount.String()))
+		logger.Info(i18n.T("engine.amount_too_small", tradeAmount.String(), e.minTradeAmount.String()))
 		return nil
 	}
 
-	// 设置买入限价：比当前价格低0.1%（更优价格）
-	buySlippage := decimal.NewFromFloat(0.001) // 0.1%
-	limitPrice := kline.Close.Mul(decimal.NewFromInt(1).Sub(buySlippage))
 	quantity := tradeAmount.Div(limitPrice)
+	quoteQuantity := decimal.Zero
+	if signal.Quantity.IsPositive() {
+		// 信号指定数量，不超过可用现金
+		quantity = decimal.Min(signal.Quantity, availableCash.Div(limitPrice))
+	} else if orderType == PendingOrderTypeBuyMarket {
+		// 市价单按金额买入，成交数量由交易所（回测按成交价）计算，避免低价币的数量精度问题
+		quoteQuantity = tradeAmount
+	}
 
 	// 创建挂单
 	orderID := generateShortOrderID("buy", e.tradingPair.Base)
-	expireTime := kline.OpenTime.Add(24 * time.Hour) // 24小时过期
+	timeInForce, expireTime := e.orderTimeInForce(signal, kline.OpenTime)
 
 	pendingOrder := &PendingOrder{
 		ID:           orderID,
-		Type:         PendingOrderTypeBuyLimit,
+		Type:         orderType,
 		TradingPair:  e.tradingPair,
 		Quantity:     quantity,
 		Price:        limitPrice,
 		CreateTime:   kline.OpenTime,
-		ExpireTime:   &expireTime,
+		ExpireTime:   expireTime,
 		Reason:       signal.Reason,
 		OriginSignal: signal.Type,
+		TimeInForce:  timeInForce,
+		Signal:       e.signalContext(signal, kline),
+
+		QuoteQuantity: quoteQuantity,
+
+		StopLossPrice:   signal.StopLoss,
+		TakeProfitPrice: signal.TakeProfit,
 	}
 
-	logger.Info(fmt.Sprintf("🔵 生成买入限价单: id=%s, limit_price=%s, qty=%s, current_price=%s", 
-		orderID, limitPrice.String(), quantity.String(), kline.Close.String()))
+	logger.Info(i18n.T("engine.buy_order", 
+		orderType, orderID, kline.TradingPair.String(), limitPrice.String(), quantity.String(), kline.Close.String(), signal.Reason))
 
-	return e.orderManager.PlaceOrder(ctx, pendingOrder)
+	return e.placeOrder(ctx, pendingOrder)
+}
+
+// sizer 当前使用的仓位计算器
+func (e *TradingEngine) sizer() PositionSizer {
+	if e.positionSizer != nil {
+		return e.positionSizer
+	}
+	return NewFixedPercentSizer(e.positionSizePercent.InexactFloat64())
 }
 
 // handleSellSignal 处理卖出信号 - 生成限价卖单
@@ -270,59 +539,63 @@ func (e *TradingEngine) handleSellSignal(ctx context.Context, signal *strategy.S
 	ctx, logger := log.WithCtx(ctx)
 
 	if portfolio.Position.IsZero() {
-		logger.Info("无持仓，跳过卖出信号")
+		logger.Info(i18n.T("engine.no_position"))
 		return nil
 	}
 
 	// 计算卖出数量（支持部分卖出）
 	var sellQuantity decimal.Decimal
-	if signal.Strength <= 0 || signal.Strength > 1 {
+	if signal.Quantity.IsPositive() {
+		sellQuantity = decimal.Min(signal.Quantity, portfolio.Position)
+		logger.Info(i18n.T("engine.sell_quantity", signal.Quantity.String(), portfolio.Position.String()))
+	} else if signal.Strength <= 0 || signal.Strength > 1 {
 		sellQuantity = portfolio.Position
-		logger.Info(fmt.Sprintf("信号强度无效，执行全仓卖出: strength=%.1f", signal.Strength))
+		logger.Info(i18n.T("engine.sell_all", signal.Strength))
 	} else {
 		sellQuantity = portfolio.Position.Mul(decimal.NewFromFloat(signal.Strength))
 		if sellQuantity.GreaterThan(portfolio.Position) {
 			sellQuantity = portfolio.Position
 		}
-		logger.Info("执行部分卖出",
+		logger.Info(i18n.T("engine.sell_partial"),
 			"strength", signal.Strength,
 			"sell_quantity", sellQuantity.String(),
 			"total_position", portfolio.Position.String())
 	}
 
-	// 设置卖出限价：比当前价格高0.1%（更优价格）
-	sellSlippage := decimal.NewFromFloat(0.001) // 0.1%
-	limitPrice := kline.Close.Mul(decimal.NewFromInt(1).Add(sellSlippage))
+	// 设置卖出限价：默认比当前价格高0.1%（更优价格）；市价单以当前价格作为参考价
+	orderType, limitPrice := signalOrderPrice(signal, kline.Close, cex.OrderSideSell)
 
 	// 取消现有的卖出挂单（避免重复挂单）
 	pendingOrders := e.orderManager.GetPendingOrders()
 	for _, order := range pendingOrders {
-		if order.Type == PendingOrderTypeSellLimit {
-			logger.Info(fmt.Sprintf("取消现有卖出挂单: id=%s", order.ID))
+		if (order.Type == PendingOrderTypeSellLimit || order.Type == PendingOrderTypeSellMarket) && order.OriginSignal != OriginSignalProtection {
+			logger.Info(i18n.T("engine.cancel_sell", order.ID))
 			e.orderManager.CancelOrder(ctx, order.ID)
 		}
 	}
 
 	// 创建新的卖出挂单
 	orderID := generateShortOrderID("sell", e.tradingPair.Base)
-	expireTime := kline.OpenTime.Add(24 * time.Hour) // 24小时过期
+	timeInForce, expireTime := e.orderTimeInForce(signal, kline.OpenTime)
 
 	pendingOrder := &PendingOrder{
 		ID:           orderID,
-		Type:         PendingOrderTypeSellLimit,
+		Type:         orderType,
 		TradingPair:  e.tradingPair,
 		Quantity:     sellQuantity,
 		Price:        limitPrice,
 		CreateTime:   kline.OpenTime,
-		ExpireTime:   &expireTime,
+		ExpireTime:   expireTime,
 		Reason:       signal.Reason,
 		OriginSignal: signal.Type,
+		TimeInForce:  timeInForce,
+		Signal:       e.signalContext(signal, kline),
 	}
 
-	logger.Info(fmt.Sprintf("🔴 生成卖出限价单: id=%s, limit_price=%s, qty=%s, current_price=%s", 
-		orderID, limitPrice.String(), sellQuantity.String(), kline.Close.String()))
+	logger.Info(i18n.T("engine.sell_order", 
+		orderType, orderID, kline.TradingPair.String(), limitPrice.String(), sellQuantity.String(), kline.Close.String(), signal.Reason))
 
-	return e.orderManager.PlaceOrder(ctx, pendingOrder)
+	return e.placeOrder(ctx, pendingOrder)
 }
 
 // getTimeframeInterval 获取时间周期对应的时间间隔
@@ -136,6 +136,14 @@ func (s *mockTradingStrategy) SetParams(params strategy.StrategyParams) error {
 	return nil
 }
 
+func (s *mockTradingStrategy) Save() ([]byte, error) {
+	return nil, nil
+}
+
+func (s *mockTradingStrategy) Load(data []byte) error {
+	return nil
+}
+
 // MockDataFeed for testing
 type mockTradingDataFeed struct {
 	klines       []*cex.KlineData
@@ -275,7 +283,7 @@ func TestTradingEngine_NewTradingEngine(t *testing.T) {
 	assert.Equal(t, mockOrderManager, engine.orderManager)
 	assert.True(t, engine.positionSizePercent.Equal(decimal.NewFromFloat(0.95)))
 	assert.True(t, engine.minTradeAmount.Equal(decimal.NewFromFloat(10.0)))
-	assert.False(t, engine.isRunning)
+	assert.False(t, engine.isRunning.Load())
 }
 
 func TestTradingEngine_SetPositionSizePercent(t *testing.T) {
@@ -314,7 +322,7 @@ func TestTradingEngine_Run_Success(t *testing.T) {
 	err := engine.Run(ctx)
 
 	assert.NoError(t, err)
-	assert.False(t, engine.isRunning) // 运行完成后应该设为false
+	assert.False(t, engine.isRunning.Load()) // 运行完成后应该设为false
 	assert.True(t, mockDataFeed.started)
 	assert.True(t, mockDataFeed.stopped)
 	assert.Equal(t, 5, mockStrategy.onDataCalls)        // 每个K线调用一次策略
@@ -336,7 +344,7 @@ func TestTradingEngine_Run_DataFeedStartError(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "启动数据喂入失败")
-	assert.False(t, engine.isRunning)
+	assert.False(t, engine.isRunning.Load())
 }
 
 func TestTradingEngine_Run_ContextCancellation(t *testing.T) {
@@ -361,7 +369,7 @@ func TestTradingEngine_Run_ContextCancellation(t *testing.T) {
 	if err != nil {
 		assert.Equal(t, context.DeadlineExceeded, err)
 	}
-	assert.False(t, engine.isRunning)
+	assert.False(t, engine.isRunning.Load())
 }
 
 func TestTradingEngine_Run_StrategyError(t *testing.T) {
@@ -478,10 +486,11 @@ func TestTradingEngine_Stop(t *testing.T) {
 	engine := createTestTradingEngine()
 
 	// 启动引擎
-	engine.isRunning = true
+	engine.isRunning.Store(true)
 
-	// 停止引擎
+	// 停止引擎，重复调用（TUI 退出后再按 Ctrl+C）不会 panic
 	engine.Stop()
+	assert.NotPanics(t, engine.Stop)
 
 	// 验证stopChan被关闭（通过尝试读取验证）
 	select {
@@ -1112,3 +1121,31 @@ func TestTradingEngine_HandleSellSignal_WithExistingSellOrders(t *testing.T) {
 	pendingOrders := orderManager.GetPendingOrders()
 	assert.NotEqual(t, "existing_sell_order", pendingOrders[0].ID, "应该是新的挂单ID")
 }
+
+// TestKlineWindow 限制保留的K线数量时只保留最近的K线，所有K线仍交给策略
+func TestKlineWindow(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]*cex.KlineData, 25)
+	for i := range klines {
+		price := decimal.NewFromInt(int64(100 + i))
+		klines[i] = &cex.KlineData{
+			OpenTime:  startTime.Add(time.Duration(i) * 4 * time.Hour),
+			CloseTime: startTime.Add(time.Duration(i+1)*4*time.Hour - time.Millisecond),
+			Open:      price, High: price, Low: price, Close: price,
+		}
+	}
+
+	mockStrategy := &mockTradingStrategy{}
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	engine := createTestTradingEngineWithMocks(mockStrategy, mockExecutor, NewBacktestDataFeed(klines), NewBacktestOrderManager(mockExecutor))
+	engine.SetKlineWindow(5)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	assert.Equal(t, len(klines), mockStrategy.onDataCalls)
+	retained := engine.GetKlines()
+	assert.GreaterOrEqual(t, len(retained), 5)
+	assert.Less(t, len(retained), 10)
+	assert.Equal(t, klines[len(klines)-1], retained[len(retained)-1])
+	assert.Len(t, engine.GetEquityCurve(), len(klines))
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// OriginTrailingStop 移动止损挂单的来源标记
+const OriginTrailingStop = "TRAILING_STOP"
+
+// NewTrailingStopOrder 创建移动止损卖单，初始触发价 = referencePrice × (1 - trailingPercent)
+func NewTrailingStopOrder(pair cex.TradingPair, quantity, referencePrice decimal.Decimal, trailingPercent float64, createTime time.Time) *PendingOrder {
+	return &PendingOrder{
+		ID:              generateShortOrderID("ts", pair.Base),
+		Type:            PendingOrderTypeTrailingStop,
+		TradingPair:     pair,
+		Quantity:        quantity,
+		Price:           trailingStopPrice(referencePrice, trailingPercent),
+		CreateTime:      createTime,
+		ExpireTime:      nil, // 持仓期间一直有效
+		Reason:          fmt.Sprintf("trailing stop: %.1f%% from high", trailingPercent*100),
+		OriginSignal:    OriginTrailingStop,
+		TrailingPercent: trailingPercent,
+		HighWaterMark:   referencePrice,
+	}
+}
+
+// trailingStopPrice 根据最高价计算触发价
+func trailingStopPrice(high decimal.Decimal, trailingPercent float64) decimal.Decimal {
+	return high.Mul(decimal.NewFromFloat(1 - trailingPercent))
+}
+
+// ratchetTrailingStop 出现新高时上移触发价，触发价只升不降；返回是否上移
+func ratchetTrailingStop(order *PendingOrder, high decimal.Decimal) bool {
+	if !high.GreaterThan(order.HighWaterMark) {
+		return false
+	}
+	order.HighWaterMark = high
+	order.Price = trailingStopPrice(high, order.TrailingPercent)
+	return true
+}
+
+// stopOrderTriggered 判断K线是否触发止损类挂单，跳空低开时按开盘价成交
+func stopOrderTriggered(order *PendingOrder, kline *cex.KlineData) (bool, decimal.Decimal) {
+	if kline.Low.GreaterThan(order.Price) {
+		return false, decimal.Zero
+	}
+	if kline.Open.LessThan(order.Price) {
+		return true, kline.Open
+	}
+	return true, order.Price
+}
+
+// syncTrailingStop 维护移动止损挂单：开仓成交后挂出，分批卖出后同步数量，清仓后撤销
+func (e *TradingEngine) syncTrailingStop(ctx context.Context, executed []*executor.OrderResult, kline *cex.KlineData, portfolio *executor.Portfolio) error {
+	provider, ok := e.strategy.(strategy.TrailingStopProvider)
+	if !ok {
+		return nil
+	}
+	trailingPercent := provider.GetTrailingStopPercent()
+	if trailingPercent <= 0 {
+		return nil
+	}
+
+	ctx, logger := log.WithCtx(ctx)
+
+	var entry *executor.OrderResult
+	for _, result := range executed {
+		if result != nil && result.Success && result.Side == executor.OrderSideBuy {
+			entry = result
+		}
+	}
+
+	for _, order := range e.orderManager.GetPendingOrders() {
+		if order.Type != PendingOrderTypeTrailingStop {
+			continue
+		}
+		// 新开仓或已清仓时撤销旧止损单
+		if entry != nil || portfolio.Position.IsZero() {
+			if err := e.orderManager.CancelOrder(ctx, order.ID); err != nil {
+				logger.Error("取消移动止损挂单失败", "id", order.ID, "error", err)
+			}
+			continue
+		}
+		// 其它卖单（如止盈阶梯）部分成交后，止损数量不能超过剩余持仓
+		if order.Quantity.GreaterThan(portfolio.Position) {
+			order.Quantity = portfolio.Position
+			if filters := e.symbolFilters.Filters(order.TradingPair); filters != nil {
+				order.Quantity = filters.RoundQuantity(order.Quantity)
+			}
+		}
+	}
+
+	if entry == nil || portfolio.Position.IsZero() {
+		return nil
+	}
+
+	order := NewTrailingStopOrder(e.tradingPair, portfolio.Position, entry.Price, trailingPercent, kline.OpenTime)
+	logger.Info(fmt.Sprintf("🛡️ 挂出移动止损: entry=%s, position=%s, stop=%s, trailing=%.1f%%",
+		entry.Price.String(), portfolio.Position.String(), order.Price.String(), trailingPercent*100))
+
+	if err := e.placeOrder(ctx, order); err != nil {
+		return fmt.Errorf("挂出移动止损失败: %w", err)
+	}
+	return nil
+}
+
+// placeTrailingStopLocked 在交易所挂出止损单（调用方需持有锁）
+func (m *LiveOrderManager) placeTrailingStopLocked(ctx context.Context, order *PendingOrder) error {
+	ctx, logger := log.WithCtx(ctx)
+
+	client, ok := m.cexClient.(cex.StopOrderClient)
+	if !ok {
+		return fmt.Errorf("%s does not support stop orders", m.cexClient.GetName())
+	}
+
+	result, err := m.placeStopLoss(ctx, client, order.TradingPair, order.Quantity, order.Price)
+	if err != nil {
+		return fmt.Errorf("failed to place trailing stop: %w", err)
+	}
+
+	m.pendingOrders[order.ID] = order
+	m.stopOrderIDs[order.ID] = result.OrderID
+	logger.Info(fmt.Sprintf("🛡️ 实盘移动止损已挂出: id=%s, exchange_id=%s, stop=%s",
+		order.ID, result.OrderID, order.Price.String()))
+	return nil
+}
+
+// cancelTrailingStopLocked 撤销交易所止损单（调用方需持有锁）
+func (m *LiveOrderManager) cancelTrailingStopLocked(ctx context.Context, orderID string) error {
+	order := m.pendingOrders[orderID]
+	if client, ok := m.cexClient.(cex.StopOrderClient); ok && order != nil && m.stopOrderIDs[orderID] != "" {
+		if err := m.cancelExchangeOrder(ctx, client, order.TradingPair, m.stopOrderIDs[orderID]); err != nil {
+			return fmt.Errorf("failed to cancel trailing stop %s: %w", orderID, err)
+		}
+	}
+	delete(m.pendingOrders, orderID)
+	delete(m.stopOrderIDs, orderID)
+	return nil
+}
+
+// ratchetTrailingStopsLocked 出现新高时撤销旧止损单并按新触发价重挂（调用方需持有锁）
+func (m *LiveOrderManager) ratchetTrailingStopsLocked(ctx context.Context, kline *cex.KlineData) {
+	ctx, logger := log.WithCtx(ctx)
+
+	client, ok := m.cexClient.(cex.StopOrderClient)
+	if !ok {
+		return
+	}
+
+	for orderID, order := range m.pendingOrders {
+		exchangeID, isStop := m.stopOrderIDs[orderID]
+		if !isStop || order.TradingPair != kline.TradingPair {
+			continue
+		}
+
+		if exchangeID == "" {
+			// 上次重挂失败，直接按最新触发价重挂
+			ratchetTrailingStop(order, kline.High)
+			order.Price = m.symbolFilters.RoundPrice(order.TradingPair, order.Price, cex.OrderSideSell)
+		} else {
+			previousHigh, previousPrice := order.HighWaterMark, order.Price
+			if !ratchetTrailingStop(order, kline.High) {
+				continue
+			}
+			order.Price = m.symbolFilters.RoundPrice(order.TradingPair, order.Price, cex.OrderSideSell)
+
+			if err := m.cancelExchangeOrder(ctx, client, order.TradingPair, exchangeID); err != nil {
+				// 撤单失败（可能已触发成交），保持原止损价，下根K线重试
+				order.HighWaterMark, order.Price = previousHigh, previousPrice
+				logger.Error("移动止损撤单失败", "id", orderID, "exchange_id", exchangeID, "error", err)
+				continue
+			}
+		}
+
+		result, err := m.placeStopLoss(ctx, client, order.TradingPair, order.Quantity, order.Price)
+		if err != nil {
+			// 旧单已撤、新单失败：持仓暂无保护，保留本地挂单以便下根K线重挂
+			m.stopOrderIDs[orderID] = ""
+			logger.Error("⚠️ 移动止损重挂失败，持仓暂无止损保护", "id", orderID, "error", err)
+			continue
+		}
+
+		m.stopOrderIDs[orderID] = result.OrderID
+		logger.Info(fmt.Sprintf("📈 实盘移动止损上移: id=%s, high=%s, stop=%s, exchange_id=%s",
+			orderID, order.HighWaterMark.String(), order.Price.String(), result.OrderID))
+	}
+}
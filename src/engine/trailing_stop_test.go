@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trailingTestStrategy 第一根K线买入，离场交给移动止损挂单
+type trailingTestStrategy struct {
+	onDataCalls int
+}
+
+func (s *trailingTestStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.onDataCalls++
+	if s.onDataCalls == 1 {
+		return []*strategy.Signal{{Type: "BUY", Strength: 0.8, Reason: "trailing test buy"}}, nil
+	}
+	return nil, nil
+}
+
+func (s *trailingTestStrategy) GetName() string                                { return "TrailingTestStrategy" }
+func (s *trailingTestStrategy) GetParams() strategy.StrategyParams             { return nil }
+func (s *trailingTestStrategy) SetParams(params strategy.StrategyParams) error { return nil }
+func (s *trailingTestStrategy) Save() ([]byte, error)                          { return nil, nil }
+func (s *trailingTestStrategy) Load(data []byte) error                         { return nil }
+func (s *trailingTestStrategy) GetTrailingStopPercent() float64                { return 0.1 }
+
+// mockStopOrderCEXClient 支持止损单的CEX客户端mock
+type mockStopOrderCEXClient struct {
+	MockCEXClient
+	placed    []decimal.Decimal // 每次挂出的止损价
+	cancelled []string
+}
+
+func (m *mockStopOrderCEXClient) PlaceStopLossOrder(ctx context.Context, pair cex.TradingPair, quantity, stopPrice decimal.Decimal) (*cex.OrderResult, error) {
+	m.placed = append(m.placed, stopPrice)
+	return &cex.OrderResult{OrderID: fmt.Sprintf("%d", len(m.placed)), Price: stopPrice, Quantity: quantity}, nil
+}
+
+func (m *mockStopOrderCEXClient) CancelOrder(ctx context.Context, pair cex.TradingPair, orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	return nil
+}
+
+func TestRatchetTrailingStop(t *testing.T) {
+	order := NewTrailingStopOrder(cex.TradingPair{Base: "BTC", Quote: "USDT"}, decimal.NewFromInt(1), decimal.NewFromInt(100), 0.05, time.Now())
+	assert.Equal(t, PendingOrderTypeTrailingStop, order.Type)
+	assert.True(t, order.Price.Equal(decimal.NewFromInt(95)))
+
+	assert.True(t, ratchetTrailingStop(order, decimal.NewFromInt(110)))
+	assert.True(t, order.Price.Equal(decimal.NewFromFloat(104.5)))
+
+	// 回落不下移
+	assert.False(t, ratchetTrailingStop(order, decimal.NewFromInt(105)))
+	assert.True(t, order.Price.Equal(decimal.NewFromFloat(104.5)))
+	assert.True(t, order.HighWaterMark.Equal(decimal.NewFromInt(110)))
+}
+
+func TestBacktestOrderManager_TrailingStop(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExecutor)
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+	order := NewTrailingStopOrder(cex.TradingPair{Base: "BTC", Quote: "USDT"}, decimal.NewFromInt(1), price(100), 0.1, startTime)
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+
+	// 未触发（最低价 95 > 90），最高价 120 上移触发价到 108
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(startTime, price(100), price(120), price(95), price(118)))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.True(t, order.Price.Equal(price(108)))
+
+	// 跌破 108 触发，按触发价成交
+	results, err = manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(115), price(116), price(105), price(106)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Price.Equal(price(108)))
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
+
+func TestBacktestOrderManager_TrailingStop_GapDown(t *testing.T) {
+	mockExecutor := newMockOrderExecutor(decimal.Zero, decimal.NewFromInt(1))
+	manager := NewBacktestOrderManager(mockExecutor)
+	ctx := context.Background()
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+	order := NewTrailingStopOrder(cex.TradingPair{Base: "BTC", Quote: "USDT"}, decimal.NewFromInt(1), price(100), 0.1, time.Now())
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+
+	// 跳空低开于触发价之下，按开盘价成交
+	results, err := manager.CheckAndExecuteOrders(ctx, CreateTestKlineWithPrices(time.Now(), price(80), price(85), price(78), price(82)))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Price.Equal(price(80)))
+}
+
+func TestTradingEngine_Run_TrailingStop(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+	klines := []*cex.KlineData{
+		CreateTestKlineWithPrices(startTime, price(100), price(101), price(99.5), price(100)),                  // 买入信号
+		CreateTestKlineWithPrices(startTime.Add(4*time.Hour), price(100), price(101), price(99), price(100)),   // 买单成交，挂出移动止损
+		CreateTestKlineWithPrices(startTime.Add(8*time.Hour), price(101), price(150), price(100), price(145)),  // 触发价上移到 135
+		CreateTestKlineWithPrices(startTime.Add(12*time.Hour), price(140), price(141), price(120), price(125)), // 跌破 135 止损
+	}
+
+	mockExecutor := newMockOrderExecutor(decimal.NewFromInt(10000), decimal.Zero)
+	orderManager := NewBacktestOrderManager(mockExecutor)
+	engine := createTestTradingEngineWithMocks(&trailingTestStrategy{}, mockExecutor, &mockTradingDataFeed{klines: klines}, orderManager)
+
+	require.NoError(t, engine.Run(context.Background()))
+
+	assert.Equal(t, 1, mockExecutor.buyCallCount)
+	require.Len(t, mockExecutor.sellResults, 1)
+	assert.True(t, mockExecutor.sellResults[0].Price.Equal(price(135)))
+	assert.True(t, mockExecutor.position.IsZero())
+	assert.Equal(t, 0, orderManager.GetOrderCount())
+}
+
+func TestLiveOrderManager_TrailingStop_AmendsExchangeOrder(t *testing.T) {
+	client := &mockStopOrderCEXClient{}
+	manager := NewLiveOrderManager(client)
+	ctx := context.Background()
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	price := func(v float64) decimal.Decimal { return decimal.NewFromFloat(v) }
+
+	order := NewTrailingStopOrder(pair, decimal.NewFromInt(1), price(100), 0.1, time.Now())
+	require.NoError(t, manager.PlaceOrder(ctx, order))
+	require.Len(t, client.placed, 1)
+	assert.True(t, client.placed[0].Equal(price(90)))
+
+	// 新高：撤销旧止损单，按新触发价重挂
+	kline := CreateTestKlineWithPrices(time.Now(), price(100), price(120), price(99), price(118))
+	kline.TradingPair = pair
+	_, _ = manager.CheckAndExecuteOrders(ctx, kline)
+	assert.Equal(t, []string{"1"}, client.cancelled)
+	require.Len(t, client.placed, 2)
+	assert.True(t, client.placed[1].Equal(price(108)))
+
+	// 没有新高不改单
+	kline = CreateTestKlineWithPrices(time.Now(), price(118), price(119), price(110), price(112))
+	kline.TradingPair = pair
+	_, _ = manager.CheckAndExecuteOrders(ctx, kline)
+	assert.Len(t, client.placed, 2)
+
+	require.NoError(t, manager.CancelOrder(ctx, order.ID))
+	assert.Equal(t, []string{"1", "2"}, client.cancelled)
+	assert.Equal(t, 0, manager.GetOrderCount())
+}
@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// FillRecorder 记录交易所直接成交的订单（由 executor.TradingExecutor 实现）
+type FillRecorder interface {
+	BalanceSyncer
+	RecordFill(ctx context.Context, result *executor.OrderResult)
+}
+
+// 数据流断开后的重连等待时间
+const (
+	userStreamMinBackoff = time.Second
+	userStreamMaxBackoff = time.Minute
+)
+
+// UserDataStream 订阅交易所账户数据流：成交回报直接更新挂单管理器和执行器，余额推送校正本地余额
+type UserDataStream struct {
+	client     cex.UserDataStreamClient
+	pair       cex.TradingPair
+	orders     *LiveOrderManager
+	executor   FillRecorder
+	reconciler *Reconciler // 断线重连前对账，补上断线期间错过的事件；为空时跳过
+
+	ctx context.Context // 事件回调使用的上下文（由 Run 设置）
+}
+
+// NewUserDataStream 创建账户数据流订阅
+func NewUserDataStream(client cex.UserDataStreamClient, pair cex.TradingPair, orders *LiveOrderManager, fillRecorder FillRecorder) *UserDataStream {
+	return &UserDataStream{
+		client:   client,
+		pair:     pair,
+		orders:   orders,
+		executor: fillRecorder,
+		ctx:      context.Background(),
+	}
+}
+
+// SetReconciler 设置断线重连前执行的对账
+func (s *UserDataStream) SetReconciler(reconciler *Reconciler) {
+	s.reconciler = reconciler
+}
+
+// Run 保持订阅直到 ctx 取消，断线后按指数退避重连
+func (s *UserDataStream) Run(ctx context.Context) {
+	ctx, logger := log.WithCtx(ctx)
+	s.ctx = ctx
+	s.orders.setStreaming(true)
+	defer s.orders.setStreaming(false)
+
+	backoff := userStreamMinBackoff
+	for attempt := 0; ; attempt++ {
+		connected := time.Now()
+		if s.reconciler != nil && attempt > 0 {
+			// 补上断线期间错过的成交；对账到订阅建立之间的短暂间隔由后台定期对账兜底
+			if _, err := s.reconciler.Reconcile(ctx); err != nil {
+				logger.Error("⚠️ 账户数据流连接前对账失败", "error", err)
+			}
+		}
+
+		err := s.client.SubscribeUserData(ctx, s)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 连接保持足够久说明不是持续失败，重置退避时间
+		if time.Since(connected) > userStreamMaxBackoff {
+			backoff = userStreamMinBackoff
+		}
+		logger.Error(fmt.Sprintf("⚠️ 账户数据流断开，%s 后重连", backoff), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > userStreamMaxBackoff {
+			backoff = userStreamMaxBackoff
+		}
+	}
+}
+
+// OnOrderUpdate 处理成交回报：本地跟踪的挂单成交后立即记入执行器
+func (s *UserDataStream) OnOrderUpdate(update *cex.OrderUpdate) {
+	_, logger := log.WithCtx(s.ctx)
+
+	result := s.orders.applyOrderUpdate(update, s.pair)
+	if result == nil {
+		return
+	}
+	s.executor.RecordFill(s.ctx, result)
+	logger.Info(fmt.Sprintf("⚡ 实时成交回报: exchange_id=%s, %s %s @ %s, status=%s",
+		update.OrderID, update.Side, result.Quantity.String(), result.Price.String(), update.Status))
+}
+
+// OnAccountUpdate 处理余额推送：用交易所余额覆盖本地现金和持仓
+func (s *UserDataStream) OnAccountUpdate(update *cex.AccountUpdate) {
+	_, logger := log.WithCtx(s.ctx)
+
+	// 先记录订单数再读取本地状态：期间有新成交时推送的余额可能早于该成交，放弃校正
+	ordersSeen := len(s.executor.GetOrders())
+	portfolio, err := s.executor.GetPortfolio(s.ctx)
+	if err != nil {
+		logger.Error("获取投资组合失败", "error", err)
+		return
+	}
+
+	cash, position := portfolio.Cash, portfolio.Position
+	changed := false
+	for _, balance := range update.Balances {
+		total := balance.Free.Add(balance.Locked)
+		switch balance.Asset {
+		case s.pair.Quote:
+			changed = changed || !total.Equal(cash)
+			cash = total
+		case s.pair.Base:
+			changed = changed || !total.Equal(position)
+			position = total
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if s.executor.SyncBalances(cash, position, ordersSeen) {
+		logger.Info(fmt.Sprintf("💱 余额推送: cash %s → %s %s, position %s → %s %s",
+			portfolio.Cash.String(), cash.String(), s.pair.Quote,
+			portfolio.Position.String(), position.String(), s.pair.Base))
+	}
+}
+
+// setStreaming 标记是否已接入账户数据流（接入后成交状态由推送更新）
+func (m *LiveOrderManager) setStreaming(streaming bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streaming = streaming
+}
+
+// applyOrderUpdate 按成交回报更新本地跟踪的挂单，成交时返回成交结果（未跟踪的订单返回 nil）
+func (m *LiveOrderManager) applyOrderUpdate(update *cex.OrderUpdate, pair cex.TradingPair) *executor.OrderResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	localIDs := m.localOrderIDsLocked(update)
+	if len(localIDs) == 0 {
+		// 引擎直接下的市价单等，由执行器自行记录
+		return nil
+	}
+
+	var result *executor.OrderResult
+	if update.IsTrade() {
+		quantity := update.LastQuantity
+		commission := decimal.Zero
+		switch update.CommissionAsset {
+		case pair.Quote:
+			commission = update.Commission
+		case pair.Base:
+			// 手续费从到账的基础资产中扣除
+			quantity = quantity.Sub(update.Commission)
+		}
+
+		result = &executor.OrderResult{
+			OrderID:     update.OrderID,
+			TradingPair: pair,
+			Side:        executor.OrderSide(update.Side),
+			Quantity:    quantity,
+			Price:       update.LastPrice,
+			Commission:  commission,
+			Timestamp:   update.Time,
+			Success:     true,
+		}
+		if order := m.filledOrderLocked(update, localIDs); order != nil {
+			result.Reason, result.Origin, result.Signal = order.Reason, order.OriginSignal, order.Signal
+		}
+		m.streamFills = append(m.streamFills, result)
+
+		// 限价单记录推送的成交，数据流断开后轮询订单状态时只返回之后的成交
+		for _, id := range localIDs {
+			if limit := m.limitOrders[id]; limit != nil {
+				limit.recordFill(update.LastQuantity, update.LastPrice)
+			}
+		}
+
+		// 部分成交：剩余数量继续挂单
+		if !update.IsClosed() {
+			for _, id := range localIDs {
+				m.pendingOrders[id].Quantity = m.pendingOrders[id].Quantity.Sub(update.LastQuantity)
+			}
+		}
+	}
+
+	// OCO 一腿成交后另一腿以 EXPIRED 结束，以成交腿的状态为准关闭整组
+	closed := update.IsClosed()
+	if update.OrderListID != "" && update.Status == cex.OrderStatusExpired {
+		closed = false
+	}
+	if closed {
+		for _, id := range localIDs {
+			order := m.pendingOrders[id]
+			if order != nil && update.Status != cex.OrderStatusFilled {
+				m.events.Publish(context.Background(), &Event{Type: EventOrderCancelled, Time: update.Time,
+					TradingPair: pair, Order: order, Message: update.Status})
+			}
+			if order != nil && order.GroupID != "" {
+				delete(m.ocoListIDs, order.GroupID)
+			}
+			delete(m.pendingOrders, id)
+			delete(m.stopOrderIDs, id)
+			delete(m.limitOrders, id)
+		}
+	}
+	return result
+}
+
+// localOrderIDsLocked 查找成交回报对应的本地挂单ID，OCO 返回整组（调用方需持有锁）
+func (m *LiveOrderManager) localOrderIDsLocked(update *cex.OrderUpdate) []string {
+	for localID, exchangeID := range m.stopOrderIDs {
+		if exchangeID != "" && exchangeID == update.OrderID {
+			return []string{localID}
+		}
+	}
+	for localID, order := range m.limitOrders {
+		if order.exchangeID == update.OrderID {
+			return []string{localID}
+		}
+	}
+
+	if update.OrderListID == "" {
+		return nil
+	}
+	for groupID, listID := range m.ocoListIDs {
+		if listID != update.OrderListID {
+			continue
+		}
+		var ids []string
+		for id, order := range m.pendingOrders {
+			if order.GroupID == groupID {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+// filledOrderLocked 成交回报对应的本地挂单：OCO 按订单类型区分止损腿和止盈腿（调用方需持有锁）
+func (m *LiveOrderManager) filledOrderLocked(update *cex.OrderUpdate, localIDs []string) *PendingOrder {
+	if len(localIDs) == 1 {
+		return m.pendingOrders[localIDs[0]]
+	}
+	stopLeg := strings.HasPrefix(string(update.Type), "STOP_LOSS")
+	for _, id := range localIDs {
+		if order := m.pendingOrders[id]; order != nil && (order.Type == PendingOrderTypeStopLoss) == stopLeg {
+			return order
+		}
+	}
+	return nil
+}
+
+// drainStreamFillsLocked 取出数据流推送的成交结果（调用方需持有锁）
+func (m *LiveOrderManager) drainStreamFillsLocked() []*executor.OrderResult {
+	fills := m.streamFills
+	m.streamFills = nil
+	return fills
+}
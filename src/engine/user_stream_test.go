@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserStreamTestSetup() (*LiveOrderManager, *executor.TradingExecutor, *UserDataStream) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager := NewLiveOrderManager(&MockCEXClient{})
+	manager.setStreaming(true)
+	tradingExecutor := executor.NewTradingExecutor(pair, decimal.NewFromInt(1000))
+	return manager, tradingExecutor, NewUserDataStream(nil, pair, manager, tradingExecutor)
+}
+
+func tradeUpdate(orderID, status string, quantity, price float64) *cex.OrderUpdate {
+	return &cex.OrderUpdate{
+		OrderID:         orderID,
+		Side:            cex.OrderSideSell,
+		Status:          status,
+		ExecutionType:   "TRADE",
+		LastQuantity:    decimal.NewFromFloat(quantity),
+		LastPrice:       decimal.NewFromFloat(price),
+		Commission:      decimal.NewFromFloat(0.1),
+		CommissionAsset: "USDT",
+		Time:            time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestUserDataStream_TrailingStopFills(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager, tradingExecutor, stream := newUserStreamTestSetup()
+	require.True(t, tradingExecutor.SyncBalances(decimal.Zero, decimal.NewFromInt(1), 0))
+
+	manager.pendingOrders["trail_1"] = &PendingOrder{ID: "trail_1", TradingPair: pair, Type: PendingOrderTypeTrailingStop, Quantity: decimal.NewFromInt(1)}
+	manager.stopOrderIDs["trail_1"] = "100"
+
+	// 引擎直接下的市价单不在跟踪范围内
+	stream.OnOrderUpdate(tradeUpdate("999", cex.OrderStatusFilled, 1, 100))
+	assert.Empty(t, tradingExecutor.GetOrders())
+
+	// 部分成交：剩余数量继续挂单
+	stream.OnOrderUpdate(tradeUpdate("100", cex.OrderStatusPartiallyFilled, 0.4, 100))
+	assert.Equal(t, 1, manager.GetOrderCount())
+	assert.True(t, decimal.NewFromFloat(0.6).Equal(manager.pendingOrders["trail_1"].Quantity))
+
+	stream.OnOrderUpdate(tradeUpdate("100", cex.OrderStatusFilled, 0.6, 100))
+	assert.Equal(t, 0, manager.GetOrderCount())
+	assert.Empty(t, manager.stopOrderIDs)
+
+	portfolio, err := tradingExecutor.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, portfolio.Position.IsZero())
+	assert.True(t, decimal.NewFromFloat(99.8).Equal(portfolio.Cash))
+
+	// 成交在下次检查挂单时返回给引擎
+	executed, err := manager.CheckAndExecuteOrders(context.Background(), &cex.KlineData{TradingPair: pair})
+	require.NoError(t, err)
+	require.Len(t, executed, 2)
+	assert.Equal(t, executor.OrderSideSell, executed[1].Side)
+
+	executed, err = manager.CheckAndExecuteOrders(context.Background(), &cex.KlineData{TradingPair: pair})
+	require.NoError(t, err)
+	assert.Empty(t, executed)
+}
+
+func TestUserDataStream_OCOClosesOnFilledLeg(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	manager, tradingExecutor, stream := newUserStreamTestSetup()
+
+	manager.pendingOrders["tp_1"] = &PendingOrder{ID: "tp_1", TradingPair: pair, Type: PendingOrderTypeSellLimit, GroupID: "oco_1",
+		Quantity: decimal.NewFromInt(1), Reason: "OCO take profit: +10.0%", OriginSignal: OriginOCO}
+	manager.pendingOrders["sl_1"] = &PendingOrder{ID: "sl_1", TradingPair: pair, Type: PendingOrderTypeStopLoss, GroupID: "oco_1",
+		Quantity: decimal.NewFromInt(1), Reason: "OCO stop loss: -5.0%", OriginSignal: OriginOCO}
+	manager.ocoListIDs["oco_1"] = "7"
+
+	// 止损腿因止盈腿成交而过期，等待成交腿的回报
+	stream.OnOrderUpdate(&cex.OrderUpdate{OrderID: "201", OrderListID: "7", Status: cex.OrderStatusExpired, ExecutionType: "EXPIRED"})
+	assert.Equal(t, 2, manager.GetOrderCount())
+
+	filled := tradeUpdate("202", cex.OrderStatusFilled, 1, 110)
+	filled.OrderListID = "7"
+	filled.Type = "LIMIT_MAKER"
+	stream.OnOrderUpdate(filled)
+	assert.Equal(t, 0, manager.GetOrderCount())
+	assert.Empty(t, manager.ocoListIDs)
+	require.Len(t, tradingExecutor.GetOrders(), 1)
+	// 成交记录带上成交腿的挂单原因
+	assert.Equal(t, "OCO take profit: +10.0%", tradingExecutor.GetOrders()[0].Reason)
+	assert.Equal(t, OriginOCO, tradingExecutor.GetOrders()[0].Origin)
+}
+
+func TestUserDataStream_AccountUpdateSyncsBalances(t *testing.T) {
+	_, tradingExecutor, stream := newUserStreamTestSetup()
+
+	stream.OnAccountUpdate(&cex.AccountUpdate{Balances: []*cex.AccountBalance{
+		{Asset: "USDT", Free: decimal.NewFromInt(700), Locked: decimal.NewFromInt(50)},
+		{Asset: "BNB", Free: decimal.NewFromInt(1)},
+	}})
+
+	portfolio, err := tradingExecutor.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(750).Equal(portfolio.Cash))
+	assert.True(t, portfolio.Position.IsZero())
+}
@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// 看门狗检查间隔的上下限
+const (
+	minWatchdogCheck = time.Second
+	maxWatchdogCheck = time.Minute
+)
+
+// RestartableFeed 停滞时可以重启的数据喂入（放弃卡住的请求并立即重新获取）
+type RestartableFeed interface {
+	Restart()
+}
+
+// FeedHealth 数据喂入健康状态
+type FeedHealth struct {
+	Healthy        bool      `json:"healthy"`
+	LastKlineAt    time.Time `json:"last_kline_at"`           // 最近一根新K线到达的时间（未收到时为启动时间）
+	LastKline      time.Time `json:"last_kline,omitempty"`    // 最近一根新K线的开盘时间
+	StalledSince   time.Time `json:"stalled_since,omitempty"` // 判定停滞的时间，健康时为零值
+	TimeoutSeconds int64     `json:"timeout_seconds"`         // 超过该时长没有新K线判定停滞
+	Restarts       int       `json:"restarts"`                // 停滞后重启数据喂入的次数
+}
+
+// FeedWatchdog 实盘数据喂入看门狗：超过 timeout 没有新K线（接口卡住、持续报错）时判定停滞，
+// 发布 feed_stalled 告警并重启数据喂入，停滞期间每隔 timeout 重试一次，收到新K线后发布 feed_recovered
+type FeedWatchdog struct {
+	pair    cex.TradingPair
+	timeout time.Duration
+	feed    DataFeed
+	events  *EventBus
+
+	mu           sync.Mutex
+	lastKline    time.Time // 最近一根新K线的开盘时间
+	lastArrival  time.Time
+	stalledSince time.Time
+	lastRestart  time.Time
+	restarts     int
+}
+
+// NewFeedWatchdog 创建看门狗，从创建时开始计时
+func NewFeedWatchdog(pair cex.TradingPair, timeout time.Duration, feed DataFeed, events *EventBus) *FeedWatchdog {
+	return &FeedWatchdog{pair: pair, timeout: timeout, feed: feed, events: events, lastArrival: time.Now()}
+}
+
+// SetFeedWatchdog 设置数据喂入看门狗（回测不设置），引擎收到K线时通知看门狗
+func (e *TradingEngine) SetFeedWatchdog(watchdog *FeedWatchdog) {
+	e.watchdog = watchdog
+}
+
+// ObserveKline 记录收到的K线，开盘时间比上一根新时才算新K线；停滞期间收到新K线时发布 feed_recovered
+func (w *FeedWatchdog) ObserveKline(ctx context.Context, kline *cex.KlineData, arrivedAt time.Time) {
+	if w == nil || kline == nil {
+		return
+	}
+	w.mu.Lock()
+	if !kline.OpenTime.After(w.lastKline) {
+		w.mu.Unlock()
+		return
+	}
+	w.lastKline = kline.OpenTime
+	w.lastArrival = arrivedAt
+	stalledSince := w.stalledSince
+	w.stalledSince = time.Time{}
+	w.mu.Unlock()
+
+	if stalledSince.IsZero() {
+		return
+	}
+	_, logger := log.WithCtx(ctx)
+	message := fmt.Sprintf("new kline %s after %s stalled", kline.OpenTime.UTC().Format("2006-01-02 15:04"), arrivedAt.Sub(stalledSince).Round(time.Second))
+	logger.Info(fmt.Sprintf("✅ 数据喂入已恢复: symbol=%s, %s", w.pair.String(), message))
+	w.events.Publish(ctx, &Event{Type: EventFeedRecovered, Time: arrivedAt, TradingPair: w.pair, Message: message})
+}
+
+// Check 检查是否停滞：刚停滞时发布 feed_stalled 告警，停滞期间每隔 timeout 重启一次数据喂入
+func (w *FeedWatchdog) Check(ctx context.Context, now time.Time) {
+	w.mu.Lock()
+	idle := now.Sub(w.lastArrival)
+	if idle <= w.timeout {
+		w.mu.Unlock()
+		return
+	}
+	wentStalled := w.stalledSince.IsZero()
+	if wentStalled {
+		w.stalledSince = now
+	}
+	restart := now.Sub(w.lastRestart) >= w.timeout
+	if restart {
+		w.lastRestart = now
+		w.restarts++
+	}
+	restarts := w.restarts
+	w.mu.Unlock()
+
+	_, logger := log.WithCtx(ctx)
+	if wentStalled {
+		message := fmt.Sprintf("no new kline for %s (timeout %s)", idle.Round(time.Second), w.timeout)
+		logger.Error(fmt.Sprintf("🚨 数据喂入停滞: symbol=%s, %s", w.pair.String(), message))
+		w.events.Publish(ctx, &Event{Type: EventFeedStalled, Time: now, TradingPair: w.pair, Message: message})
+	}
+	if !restart {
+		return
+	}
+	feed, ok := w.feed.(RestartableFeed)
+	if !ok {
+		return
+	}
+	logger.Warning(fmt.Sprintf("🔄 重启数据喂入: symbol=%s, attempt=%d", w.pair.String(), restarts))
+	feed.Restart()
+}
+
+// Run 后台定期检查，ctx 取消后返回
+func (w *FeedWatchdog) Run(ctx context.Context) {
+	interval := min(max(w.timeout/10, minWatchdogCheck), maxWatchdogCheck)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.Check(ctx, now)
+		}
+	}
+}
+
+// Health 当前健康状态
+func (w *FeedWatchdog) Health() FeedHealth {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return FeedHealth{
+		Healthy:        w.stalledSince.IsZero(),
+		LastKlineAt:    w.lastArrival,
+		LastKline:      w.lastKline,
+		StalledSince:   w.stalledSince,
+		TimeoutSeconds: int64(w.timeout / time.Second),
+		Restarts:       w.restarts,
+	}
+}
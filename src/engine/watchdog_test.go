@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restartCountingFeed 记录重启次数的数据喂入
+type restartCountingFeed struct {
+	mockTradingDataFeed
+	restarts int
+}
+
+func (f *restartCountingFeed) Restart() {
+	f.restarts++
+}
+
+// hangingKlineClient 请求一直阻塞到 ctx 取消
+type hangingKlineClient struct {
+	mockLiveDataCEXClient
+	started chan struct{}
+}
+
+func (c *hangingKlineClient) GetKlines(ctx context.Context, pair cex.TradingPair, interval string, limit int) ([]*cex.KlineData, error) {
+	c.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestFeedWatchdog_StallRestartAndRecovery(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus := NewEventBus()
+	events := subscribeAll(bus)
+	feed := &restartCountingFeed{}
+	watchdog := NewFeedWatchdog(pair, 8*time.Hour, feed, bus)
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	watchdog.ObserveKline(ctx, &cex.KlineData{OpenTime: start}, start)
+
+	// 超时之前健康
+	watchdog.Check(ctx, start.Add(8*time.Hour))
+	assert.True(t, watchdog.Health().Healthy)
+	assert.Empty(t, *events)
+
+	// 超时：告警一次并重启数据喂入
+	stalledAt := start.Add(8*time.Hour + time.Minute)
+	watchdog.Check(ctx, stalledAt)
+	health := watchdog.Health()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, stalledAt, health.StalledSince)
+	assert.Equal(t, 1, feed.restarts)
+	require.Len(t, *events, 1)
+	assert.Equal(t, EventFeedStalled, (*events)[0].Type)
+	assert.Contains(t, (*events)[0].Message, "no new kline for 8h1m0s")
+
+	// 停滞期间不重复告警，每隔 timeout 再重启一次
+	watchdog.Check(ctx, stalledAt.Add(time.Hour))
+	assert.Equal(t, 1, feed.restarts)
+	watchdog.Check(ctx, stalledAt.Add(8*time.Hour))
+	assert.Equal(t, 2, feed.restarts)
+	assert.Len(t, *events, 1)
+
+	// 重复的旧K线不算恢复
+	watchdog.ObserveKline(ctx, &cex.KlineData{OpenTime: start}, stalledAt.Add(9*time.Hour))
+	assert.False(t, watchdog.Health().Healthy)
+
+	// 新K线到达后恢复
+	recoveredAt := stalledAt.Add(9 * time.Hour)
+	watchdog.ObserveKline(ctx, &cex.KlineData{OpenTime: start.Add(16 * time.Hour)}, recoveredAt)
+	health = watchdog.Health()
+	assert.True(t, health.Healthy)
+	assert.Equal(t, recoveredAt, health.LastKlineAt)
+	assert.Equal(t, 2, health.Restarts)
+	assert.Equal(t, int64(8*3600), health.TimeoutSeconds)
+	require.Len(t, *events, 2)
+	assert.Equal(t, EventFeedRecovered, (*events)[1].Type)
+
+	// 未设置看门狗时忽略
+	var empty *FeedWatchdog
+	empty.ObserveKline(ctx, &cex.KlineData{OpenTime: start}, start)
+}
+
+func TestLiveDataFeed_RestartCancelsHangingRequest(t *testing.T) {
+	client := &hangingKlineClient{started: make(chan struct{}, 1)}
+	feed := NewLiveDataFeed(client, cex.TradingPair{Base: "BTC", Quote: "USDT"}, "4h", time.Hour)
+	defer feed.Stop()
+
+	// 重启立即触发获取，不等待 ticker
+	feed.Restart()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := feed.GetNext(context.Background())
+		errCh <- err
+	}()
+
+	select {
+	case <-client.started:
+	case <-time.After(time.Second):
+		t.Fatal("restart did not trigger an immediate fetch")
+	}
+
+	// 请求卡住时重启取消该请求
+	feed.Restart()
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("restart did not cancel the hanging request")
+	}
+}
@@ -0,0 +1,174 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Prefix 环境变量前缀
+const Prefix = "TRADINGBOT"
+
+// Section 可被环境变量覆盖的配置段
+type Section struct {
+	Name   string      // 环境变量中的段名（如 BINANCE、DB）
+	Config interface{} // 配置结构体指针
+}
+
+// sections 已注册的配置段
+var sections []Section
+
+// Register 注册配置段（在各包的 init 中与 configs.Unmarshal 一起调用），段名重复时 panic
+func Register(name string, config interface{}) {
+	for _, section := range sections {
+		if section.Name == name {
+			panic(fmt.Sprintf("envconfig: section %s registered twice", name))
+		}
+	}
+	sections = append(sections, Section{Name: name, Config: config})
+}
+
+// Sections 已注册的配置段（按段名排序）
+func Sections() []Section {
+	result := append([]Section(nil), sections...)
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ApplyRegistered 用环境变量覆盖全部已注册的配置段（读取 config.json 之后调用），返回生效的变量名
+func ApplyRegistered(lookup func(string) (string, bool)) ([]string, error) {
+	return Apply(lookup, Sections()...)
+}
+
+// Apply 用环境变量覆盖配置段：变量名为 TRADINGBOT_<段名>_<字段名>，字段名按驼峰拆成大写下划线（APIKey → API_KEY），
+// 嵌套结构体逐级拼接（如 TRADINGBOT_TRADING_RISK_MAX_DAILY_LOSS）；字符串切片按逗号分隔，结构体切片不支持覆盖。
+// 未设置或为空的变量不覆盖；返回生效的变量名（不含值，避免密钥写入日志）
+func Apply(lookup func(string) (string, bool), sections ...Section) ([]string, error) {
+	var applied []string
+	for _, section := range sections {
+		value := reflect.ValueOf(section.Config)
+		if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+			return applied, fmt.Errorf("envconfig: section %s must be a struct pointer", section.Name)
+		}
+		names, err := applyStruct(lookup, Prefix+"_"+section.Name, value.Elem())
+		applied = append(applied, names...)
+		if err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// applyStruct 逐个字段查找并覆盖
+func applyStruct(lookup func(string) (string, bool), prefix string, value reflect.Value) ([]string, error) {
+	var applied []string
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := prefix + "_" + EnvName(field.Name)
+		fieldValue := value.Field(i)
+
+		if fieldValue.Kind() == reflect.Struct {
+			names, err := applyStruct(lookup, name, fieldValue)
+			applied = append(applied, names...)
+			if err != nil {
+				return applied, err
+			}
+			continue
+		}
+
+		raw, ok := lookup(name)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setValue(fieldValue, raw); err != nil {
+			return applied, fmt.Errorf("invalid environment variable %s: %w", name, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+// setValue 按字段类型解析变量值
+func setValue(value reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("list of %s cannot be set from the environment", value.Type().Elem())
+		}
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items).Convert(value.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// EnvName 字段名转为环境变量片段：APIKey → API_KEY，MaxOpenConns → MAX_OPEN_CONNS，DBName → DB_NAME
+func EnvName(field string) string {
+	runes := []rune(field)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// ApplyTo 按已注册配置段的类型用环境变量覆盖配置副本（如热更新时从配置文件重新解析的配置），类型未注册时忽略
+func ApplyTo(lookup func(string) (string, bool), configs ...interface{}) ([]string, error) {
+	var matched []Section
+	for _, config := range configs {
+		for _, section := range sections {
+			if reflect.TypeOf(section.Config) == reflect.TypeOf(config) {
+				matched = append(matched, Section{Name: section.Name, Config: config})
+				break
+			}
+		}
+	}
+	return Apply(lookup, matched...)
+}
@@ -0,0 +1,126 @@
+package envconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLimits struct {
+	MaxDailyLoss float64
+	MaxOpenConns int
+}
+
+type testConfig struct {
+	APIKey   string
+	DBName   string
+	Enabled  bool
+	Port     uint16
+	Channels []string
+	Limits   testLimits
+	Items    []testLimits
+	private  string
+}
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "API_KEY", EnvName("APIKey"))
+	assert.Equal(t, "MAX_OPEN_CONNS", EnvName("MaxOpenConns"))
+	assert.Equal(t, "DB_NAME", EnvName("DBName"))
+	assert.Equal(t, "HOST", EnvName("Host"))
+	assert.Equal(t, "TELEGRAM_BOT_TOKEN", EnvName("TelegramBotToken"))
+}
+
+func TestApply(t *testing.T) {
+	config := testConfig{APIKey: "from-file", DBName: "tradingbot", Port: 5432, private: "kept"}
+	applied, err := Apply(lookupMap(map[string]string{
+		"TRADINGBOT_TEST_API_KEY":               "secret",
+		"TRADINGBOT_TEST_DB_NAME":               "", // 空值不覆盖
+		"TRADINGBOT_TEST_ENABLED":               "true",
+		"TRADINGBOT_TEST_PORT":                  "6432",
+		"TRADINGBOT_TEST_CHANNELS":              "telegram, webhook,",
+		"TRADINGBOT_TEST_LIMITS_MAX_DAILY_LOSS": "12.5",
+		"TRADINGBOT_TEST_LIMITS_MAX_OPEN_CONNS": "8",
+		"TRADINGBOT_OTHER_API_KEY":              "ignored",
+	}), Section{Name: "TEST", Config: &config})
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", config.APIKey)
+	assert.Equal(t, "tradingbot", config.DBName)
+	assert.True(t, config.Enabled)
+	assert.Equal(t, uint16(6432), config.Port)
+	assert.Equal(t, []string{"telegram", "webhook"}, config.Channels)
+	assert.Equal(t, testLimits{MaxDailyLoss: 12.5, MaxOpenConns: 8}, config.Limits)
+	assert.Equal(t, "kept", config.private)
+	assert.ElementsMatch(t, []string{
+		"TRADINGBOT_TEST_API_KEY", "TRADINGBOT_TEST_ENABLED", "TRADINGBOT_TEST_PORT", "TRADINGBOT_TEST_CHANNELS",
+		"TRADINGBOT_TEST_LIMITS_MAX_DAILY_LOSS", "TRADINGBOT_TEST_LIMITS_MAX_OPEN_CONNS",
+	}, applied)
+}
+
+func TestApply_InvalidValues(t *testing.T) {
+	config := testConfig{}
+	_, err := Apply(lookupMap(map[string]string{"TRADINGBOT_TEST_PORT": "70000"}), Section{Name: "TEST", Config: &config})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TRADINGBOT_TEST_PORT")
+
+	_, err = Apply(lookupMap(map[string]string{"TRADINGBOT_TEST_ENABLED": "maybe"}), Section{Name: "TEST", Config: &config})
+	assert.Error(t, err)
+
+	// 结构体切片无法从环境变量设置
+	_, err = Apply(lookupMap(map[string]string{"TRADINGBOT_TEST_ITEMS": "a"}), Section{Name: "TEST", Config: &config})
+	assert.Error(t, err)
+
+	_, err = Apply(lookupMap(nil), Section{Name: "TEST", Config: config})
+	assert.Error(t, err)
+}
+
+func TestApplyTo(t *testing.T) {
+	registered := testConfig{}
+	Register("APPLY_TO_TEST", &registered)
+	defer func() { sections = sections[:len(sections)-1] }()
+
+	copied := testConfig{APIKey: "from-file"}
+	other := testLimits{}
+	applied, err := ApplyTo(lookupMap(map[string]string{
+		"TRADINGBOT_APPLY_TO_TEST_API_KEY": "secret",
+	}), &copied, &other)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TRADINGBOT_APPLY_TO_TEST_API_KEY"}, applied)
+	assert.Equal(t, "secret", copied.APIKey)
+	assert.Empty(t, registered.APIKey)
+
+	assert.Panics(t, func() { Register("APPLY_TO_TEST", &testLimits{}) })
+}
+
+func TestConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	explicitFile := filepath.Join(dir, "custom.json")
+
+	path, explicit := ConfigPath(explicitFile, lookupMap(map[string]string{ConfigFileEnv: "/ignored.json"}))
+	assert.Equal(t, explicitFile, path)
+	assert.True(t, explicit)
+
+	path, explicit = ConfigPath("", lookupMap(map[string]string{ConfigFileEnv: explicitFile}))
+	assert.Equal(t, explicitFile, path)
+	assert.True(t, explicit)
+
+	path, explicit = ConfigPath("", lookupMap(nil))
+	assert.Equal(t, defaultConfigFile, filepath.Base(path))
+	assert.True(t, filepath.IsAbs(path))
+	assert.False(t, explicit)
+
+	// 指定的配置文件不存在时报错
+	_, _, err := Load("", lookupMap(map[string]string{ConfigFileEnv: filepath.Join(dir, "missing.json")}))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,71 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/xpwu/go-cmd/exe"
+	"github.com/xpwu/go-config/configs"
+	"github.com/xpwu/go-x/jsontype"
+)
+
+// ConfigFileEnv 指定配置文件路径的环境变量
+const ConfigFileEnv = Prefix + "_CONFIG"
+
+// defaultConfigFile 未指定时在程序所在目录查找的配置文件
+const defaultConfigFile = "config.json"
+
+// defaultsOnly 没有配置文件时只使用各配置段的默认值
+type defaultsOnly struct{}
+
+func (defaultsOnly) Read(defaults jsontype.Type) (jsontype.Type, error) {
+	return defaults, nil
+}
+
+func (defaultsOnly) Print(defaults jsontype.Type) error {
+	return errors.New("no config file in use, run the print command to generate one")
+}
+
+// ConfigPath 配置文件路径：依次取 -c 参数、TRADINGBOT_CONFIG、程序目录下的 config.json，相对路径相对于程序目录；
+// explicit 表示路径由参数或环境变量指定（文件必须存在）
+func ConfigPath(flagPath string, lookup func(string) (string, bool)) (path string, explicit bool) {
+	path, explicit = flagPath, flagPath != ""
+	if !explicit {
+		if value, ok := lookup(ConfigFileEnv); ok && value != "" {
+			path, explicit = value, true
+		}
+	}
+	if !explicit {
+		path = defaultConfigFile
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(exe.Exe.AbsDir, path)
+	}
+	return path, explicit
+}
+
+// Load 读取配置文件并用环境变量覆盖，优先级：环境变量 > 配置文件 > 默认值（命令行参数在各命令中再覆盖）。
+// 默认位置没有配置文件时只使用默认值和环境变量（容器中不挂载配置文件）；返回使用的配置文件（未使用时为空）和生效的环境变量名
+func Load(flagPath string, lookup func(string) (string, bool)) (string, []string, error) {
+	path, explicit := ConfigPath(flagPath, lookup)
+	if _, err := os.Stat(path); err != nil {
+		if explicit || !errors.Is(err, os.ErrNotExist) {
+			return "", nil, fmt.Errorf("can't read config file %s: %w", path, err)
+		}
+		path = ""
+		configs.SetConfigurator(defaultsOnly{})
+	} else {
+		configs.SetConfigurator(&configs.JsonConfig{ReadFile: path})
+	}
+	if err := configs.ReadWithErr(); err != nil {
+		return "", nil, err
+	}
+
+	applied, err := ApplyRegistered(lookup)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, applied, nil
+}
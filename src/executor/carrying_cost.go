@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CarryingCostModel 持仓期间的资金成本（年化利率），回测时每根K线按时长计提
+type CarryingCostModel struct {
+	CashRate     float64 `json:"cash_rate"`     // 闲置现金的年化收益（如稳定币理财利率），计入现金
+	PositionRate float64 `json:"position_rate"` // 持仓市值的年化成本（放弃的稳定币收益或融资利率），从现金扣除
+}
+
+// Enabled 是否计提资金成本
+func (m CarryingCostModel) Enabled() bool {
+	return m.CashRate != 0 || m.PositionRate != 0
+}
+
+// Validate 检查利率范围
+func (m CarryingCostModel) Validate() error {
+	if m.CashRate < 0 || m.CashRate > 1 {
+		return fmt.Errorf("CashRate must be between 0 and 1, got %v", m.CashRate)
+	}
+	if m.PositionRate < 0 || m.PositionRate > 1 {
+		return fmt.Errorf("PositionRate must be between 0 and 1, got %v", m.PositionRate)
+	}
+	return nil
+}
+
+// accrue 计算一段时间内的净资金成本（正数为成本，负数为现金收益）
+func (m CarryingCostModel) accrue(cash, positionValue decimal.Decimal, period time.Duration) decimal.Decimal {
+	yearFraction := period.Hours() / (365 * 24)
+	cost := positionValue.Mul(decimal.NewFromFloat(m.PositionRate * yearFraction))
+	if cash.IsPositive() {
+		cost = cost.Sub(cash.Mul(decimal.NewFromFloat(m.CashRate * yearFraction)))
+	}
+	return cost
+}
+
+// SetCarryingCost 设置资金成本模型（回测使用，默认不计提）
+func (e *TradingExecutor) SetCarryingCost(model CarryingCostModel) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.carryingModel = model
+}
+
+// AccrueCarryingCost 按当前价格计提一段时间的资金成本，调整现金和组合价值，返回本次净成本
+func (e *TradingExecutor) AccrueCarryingCost(price decimal.Decimal, period time.Duration) decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.carryingModel.Enabled() || period <= 0 {
+		return decimal.Zero
+	}
+	cost := e.carryingModel.accrue(e.cash, e.position.Mul(price), period)
+	e.cash = e.cash.Sub(cost)
+	e.portfolio = e.portfolio.Sub(cost)
+	e.carryingCost = e.carryingCost.Add(cost)
+	return cost
+}
+
+// CarryingCost 累计净资金成本（正数为成本）
+func (e *TradingExecutor) CarryingCost() decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.carryingCost
+}
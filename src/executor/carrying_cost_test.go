@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTradingExecutor_AccrueCarryingCost(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	executor.RecordFill(context.Background(), &OrderResult{Side: OrderSideBuy, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(5000)})
+
+	// 未设置模型时不计提
+	assert.True(t, executor.AccrueCarryingCost(decimal.NewFromInt(5000), 24*time.Hour).IsZero())
+
+	// 一年：持仓 5000 × 10% 成本，闲置现金 5000 × 4% 收益
+	executor.SetCarryingCost(CarryingCostModel{CashRate: 0.04, PositionRate: 0.10})
+	cost := executor.AccrueCarryingCost(decimal.NewFromInt(5000), 365*24*time.Hour)
+	assert.InDelta(t, 300, cost.InexactFloat64(), 1e-6)
+	assert.InDelta(t, 300, executor.CarryingCost().InexactFloat64(), 1e-6)
+
+	portfolio, err := executor.GetPortfolio(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 4700, portfolio.Cash.InexactFloat64(), 1e-6)
+
+	assert.Error(t, CarryingCostModel{PositionRate: -0.1}.Validate())
+	assert.Error(t, CarryingCostModel{CashRate: 2}.Validate())
+	assert.NoError(t, CarryingCostModel{CashRate: 0.05, PositionRate: 0.05}.Validate())
+}
@@ -33,7 +33,20 @@ type BuyOrder struct {
 	Quantity    decimal.Decimal `json:"quantity"`
 	Price       decimal.Decimal `json:"price"` // 限价单价格，市价单可为空
 	Timestamp   time.Time       `json:"timestamp"`
-	Reason      string          `json:"reason"` // 交易原因
+	Reason      string          `json:"reason"`                  // 交易原因
+	Origin      string          `json:"origin,omitempty"`        // 挂单来源：信号类型（BUY/SELL）或保护单类型（OCO、TRAILING_STOP 等）
+	TimeInForce cex.TimeInForce `json:"time_in_force,omitempty"` // 限价单有效方式，空为 GTC
+
+	// QuoteQuantity 市价单按计价资产金额买入（如 500 USDT），大于 0 时忽略 Quantity，
+	// 成交数量由交易所或回测撮合按成交价计算，避免低价币数量精度问题
+	QuoteQuantity decimal.Decimal `json:"quote_quantity,omitempty"`
+
+	Signal *SignalContext `json:"signal,omitempty"` // 产生该订单的策略信号，带到 OrderResult
+}
+
+// IsQuoteOrder 是否按计价资产金额下的市价买单
+func (o *BuyOrder) IsQuoteOrder() bool {
+	return o.Type == OrderTypeMarket && o.QuoteQuantity.IsPositive()
 }
 
 // SellOrder 卖出订单信息
@@ -44,7 +57,11 @@ type SellOrder struct {
 	Quantity    decimal.Decimal `json:"quantity"`
 	Price       decimal.Decimal `json:"price"` // 限价单价格，市价单可为空
 	Timestamp   time.Time       `json:"timestamp"`
-	Reason      string          `json:"reason"` // 交易原因
+	Reason      string          `json:"reason"`                  // 交易原因
+	Origin      string          `json:"origin,omitempty"`        // 挂单来源：信号类型（BUY/SELL）或保护单类型（OCO、TRAILING_STOP 等）
+	TimeInForce cex.TimeInForce `json:"time_in_force,omitempty"` // 限价单有效方式，空为 GTC
+
+	Signal *SignalContext `json:"signal,omitempty"` // 产生该订单的策略信号，带到 OrderResult
 }
 
 // OrderResult 订单执行结果
@@ -53,10 +70,56 @@ type OrderResult struct {
 	TradingPair cex.TradingPair `json:"trading_pair"`
 	Side        OrderSide       `json:"side"`
 	Quantity    decimal.Decimal `json:"quantity"`
-	Price       decimal.Decimal `json:"price"` // 实际成交价格
+	Price       decimal.Decimal `json:"price"`      // 实际成交价格
+	Commission  decimal.Decimal `json:"commission"` // 手续费（计价资产）
 	Timestamp   time.Time       `json:"timestamp"`
 	Success     bool            `json:"success"`
 	Error       string          `json:"error,omitempty"`
+
+	// 分批成交：Quantity 为累计成交量，Price 为成交量加权均价
+	RemainingQuantity decimal.Decimal `json:"remaining_quantity"` // 尚未成交、仍在挂单的数量
+	Fills             []Fill          `json:"fills,omitempty"`    // 每次成交明细
+
+	ClientOrderID string `json:"client_order_id,omitempty"` // 对应的引擎挂单ID（挂单成交时填充）
+
+	// 挂单原因和来源（如 "price below lower band" / BUY、"trailing stop: 5.0% from high" / TRAILING_STOP），用于交易统计和报告
+	Reason string         `json:"reason,omitempty"`
+	Origin string         `json:"origin,omitempty"`
+	Signal *SignalContext `json:"signal,omitempty"` // 产生该订单的策略信号（止损止盈等保护单为空）
+}
+
+// SignalContext 下单时的策略信号：原因、信号价格（信号K线收盘价）和指标快照，用于交易日志和滑点分析
+type SignalContext struct {
+	Type       string             `json:"type"`
+	Reason     string             `json:"reason"`
+	Price      decimal.Decimal    `json:"price"`
+	Time       time.Time          `json:"time"`
+	Indicators map[string]float64 `json:"indicators,omitempty"`
+}
+
+// Fill 单次成交明细
+type Fill struct {
+	Quantity   decimal.Decimal `json:"quantity"`
+	Price      decimal.Decimal `json:"price"`
+	Commission decimal.Decimal `json:"commission"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
+
+// AddFill 累加一次成交，更新累计成交量、加权均价和手续费
+func (r *OrderResult) AddFill(fill Fill) {
+	notional := r.Quantity.Mul(r.Price).Add(fill.Quantity.Mul(fill.Price))
+	r.Quantity = r.Quantity.Add(fill.Quantity)
+	if r.Quantity.IsPositive() {
+		r.Price = notional.Div(r.Quantity)
+	}
+	r.Commission = r.Commission.Add(fill.Commission)
+	r.Timestamp = fill.Timestamp
+	r.Fills = append(r.Fills, fill)
+}
+
+// IsPartiallyFilled 是否部分成交（仍有剩余数量挂单）
+func (r *OrderResult) IsPartiallyFilled() bool {
+	return r.RemainingQuantity.IsPositive()
 }
 
 // Portfolio 投资组合状态
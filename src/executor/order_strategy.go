@@ -26,11 +26,16 @@ func NewBacktestOrderStrategy(pair cex.TradingPair) *BacktestOrderStrategy {
 // ExecuteBuy 执行买入订单（模拟）
 func (e *BacktestOrderStrategy) ExecuteBuy(ctx context.Context, order *BuyOrder) (*OrderResult, error) {
 	// 回测模式：只需要生成订单记录，无真实API调用
+	quantity := order.Quantity
+	if order.IsQuoteOrder() && order.Price.IsPositive() {
+		// 按金额买入：成交数量 = 金额 / 成交价
+		quantity = order.QuoteQuantity.Div(order.Price)
+	}
 	result := &OrderResult{
 		OrderID:     fmt.Sprintf("backtest_%d", time.Now().UnixNano()),
 		TradingPair: order.TradingPair,
 		Side:        OrderSideBuy,
-		Quantity:    order.Quantity,
+		Quantity:    quantity,
 		Price:       order.Price, // 回测使用精确价格，无滑点
 		Timestamp:   order.Timestamp,
 		Success:     true,
@@ -81,6 +86,7 @@ func (e *BacktestOrderStrategy) GetRealPortfolio(ctx context.Context, pair cex.T
 type LiveOrderStrategy struct {
 	cexClient   cex.CEXClient
 	tradingPair cex.TradingPair
+	auditor     cex.OrderAuditor // 订单审计日志（为空时不记录）
 }
 
 // NewLiveOrderStrategy 创建实盘订单策略
@@ -91,20 +97,25 @@ func NewLiveOrderStrategy(cexClient cex.CEXClient, pair cex.TradingPair) *LiveOr
 	}
 }
 
+// SetOrderAuditor 设置订单审计日志，每次买入/卖出请求及交易所响应都会记录（为空时不记录）
+func (e *LiveOrderStrategy) SetOrderAuditor(auditor cex.OrderAuditor) {
+	e.auditor = auditor
+}
+
 // validateTradingEnabled 验证交易是否启用
 func (e *LiveOrderStrategy) validateTradingEnabled(ctx context.Context) error {
 	ctx, logger := log.WithCtx(ctx)
 
+	// 配置只读或未启用交易时拒绝下单（API 密钥权限在实盘启动时检查）
+	if err := cex.CheckTradingEnabled(e.cexClient); err != nil {
+		return err
+	}
+
 	// 测试连接
 	if err := e.cexClient.Ping(ctx); err != nil {
 		return fmt.Errorf("CEX连接失败: %w", err)
 	}
 
-	// 这里可以添加更多安全检查
-	// 例如：检查配置文件中的 EnableTrading 标志
-	// 例如：检查API权限
-	// 例如：检查余额是否足够
-
 	logger.Info("✅ 实盘交易安全检查通过")
 	return nil
 }
@@ -121,14 +132,19 @@ func (e *LiveOrderStrategy) ExecuteBuy(ctx context.Context, order *BuyOrder) (*O
 
 	// 创建币安买入订单请求
 	buyRequest := cex.BuyOrderRequest{
-		TradingPair: e.tradingPair,
-		Type:        cex.OrderType(order.Type),
-		Quantity:    order.Quantity,
-		Price:       order.Price,
+		TradingPair:   e.tradingPair,
+		Type:          cex.OrderType(order.Type),
+		Quantity:      order.Quantity,
+		Price:         order.Price,
+		TimeInForce:   order.TimeInForce,
+		QuoteQuantity: order.QuoteQuantity,
 	}
 
 	// 执行真实的币安API调用
-	cexResult, err := e.cexClient.Buy(ctx, buyRequest)
+	cexResult, err := cex.AuditOrder(ctx, e.auditor, e.cexClient.GetName(), cex.AuditActionBuy, e.tradingPair, buyRequest,
+		func() (*cex.OrderResult, error) {
+			return e.cexClient.Buy(ctx, buyRequest)
+		})
 	if err != nil {
 		logger.Error(fmt.Sprintf("币安买入订单失败: %v", err))
 		return &OrderResult{
@@ -157,17 +173,15 @@ func (e *LiveOrderStrategy) ExecuteBuy(ctx context.Context, order *BuyOrder) (*O
 	// TODO: 保存到本地数据库
 
 	// 打印结构化日志用于数据分析
-	logger.Info("TRADE_RECORD",
-		"mode", "LIVE",
-		"action", "BUY",
-		"order_id", result.OrderID,
-		"symbol", result.TradingPair.String(),
-		"quantity", result.Quantity.String(),
-		"price", result.Price.String(),
-		"notional", result.Quantity.Mul(result.Price).String(),
-		"timestamp", result.Timestamp.Format("2006-01-02T15:04:05Z"),
-		"reason", order.Reason,
-		"cex_order_id", cexResult.OrderID)
+	logger.Info(fmt.Sprintf("TRADE_RECORD: mode=LIVE, action=BUY, order_id=%s, symbol=%s, quantity=%s, price=%s, notional=%s, timestamp=%s, signal_reason=%q, cex_order_id=%s",
+		result.OrderID,
+		result.TradingPair.String(),
+		result.Quantity.String(),
+		result.Price.String(),
+		result.Quantity.Mul(result.Price).String(),
+		result.Timestamp.Format("2006-01-02T15:04:05Z"),
+		order.Reason,
+		cexResult.OrderID))
 
 	logger.Info(fmt.Sprintf("实盘买入订单成功: OrderID=%s, ExecutedQty=%s, ExecutedPrice=%s",
 		result.OrderID,
@@ -193,10 +207,14 @@ func (e *LiveOrderStrategy) ExecuteSell(ctx context.Context, order *SellOrder) (
 		Type:        cex.OrderType(order.Type),
 		Quantity:    order.Quantity,
 		Price:       order.Price,
+		TimeInForce: order.TimeInForce,
 	}
 
 	// 执行真实的币安API调用
-	cexResult, err := e.cexClient.Sell(ctx, sellRequest)
+	cexResult, err := cex.AuditOrder(ctx, e.auditor, e.cexClient.GetName(), cex.AuditActionSell, e.tradingPair, sellRequest,
+		func() (*cex.OrderResult, error) {
+			return e.cexClient.Sell(ctx, sellRequest)
+		})
 	if err != nil {
 		logger.Error(fmt.Sprintf("币安卖出订单失败: %v", err))
 		return &OrderResult{
@@ -225,17 +243,15 @@ func (e *LiveOrderStrategy) ExecuteSell(ctx context.Context, order *SellOrder) (
 	// TODO: 保存到本地数据库
 
 	// 打印结构化日志用于数据分析
-	logger.Info("TRADE_RECORD",
-		"mode", "LIVE",
-		"action", "SELL",
-		"order_id", result.OrderID,
-		"symbol", result.TradingPair.String(),
-		"quantity", result.Quantity.String(),
-		"price", result.Price.String(),
-		"notional", result.Quantity.Mul(result.Price).String(),
-		"timestamp", result.Timestamp.Format("2006-01-02T15:04:05Z"),
-		"reason", order.Reason,
-		"cex_order_id", cexResult.OrderID)
+	logger.Info(fmt.Sprintf("TRADE_RECORD: mode=LIVE, action=SELL, order_id=%s, symbol=%s, quantity=%s, price=%s, notional=%s, timestamp=%s, signal_reason=%q, cex_order_id=%s",
+		result.OrderID,
+		result.TradingPair.String(),
+		result.Quantity.String(),
+		result.Price.String(),
+		result.Quantity.Mul(result.Price).String(),
+		result.Timestamp.Format("2006-01-02T15:04:05Z"),
+		order.Reason,
+		cexResult.OrderID))
 
 	logger.Info(fmt.Sprintf("实盘卖出订单成功: OrderID=%s, ExecutedQty=%s, ExecutedPrice=%s",
 		result.OrderID,
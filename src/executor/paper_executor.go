@@ -0,0 +1,410 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/precision"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// defaultPaperBookDepth 模拟盘撮合使用的盘口档数
+const defaultPaperBookDepth = 20
+
+// ErrPaperOrderNotFilled 实时盘口无法成交（限价单价格未触及或盘口为空），挂单应保留
+var ErrPaperOrderNotFilled = errors.New("paper order not filled against live order book")
+
+// PaperState 模拟盘账户状态（持久化后可在重启时恢复）
+type PaperState struct {
+	SessionID      string          `json:"session_id"`
+	Symbol         string          `json:"symbol"`
+	InitialCapital decimal.Decimal `json:"initial_capital"`
+	Cash           decimal.Decimal `json:"cash"`
+	Position       decimal.Decimal `json:"position"`
+	CostBasis      decimal.Decimal `json:"cost_basis"` // 当前持仓的买入成本（含手续费）
+	MarkPrice      decimal.Decimal `json:"mark_price"` // 最近一次估值价格
+	Orders         []OrderResult   `json:"orders"`
+	TotalTrades    int             `json:"total_trades"`
+	WinningTrades  int             `json:"winning_trades"`
+	LosingTrades   int             `json:"losing_trades"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// PaperStateStore 模拟盘状态存储
+type PaperStateStore interface {
+	// LoadPaperState 读取会话状态，不存在时返回 nil
+	LoadPaperState(ctx context.Context, sessionID string) (*PaperState, error)
+
+	// SavePaperState 保存会话状态（已存在时覆盖）
+	SavePaperState(ctx context.Context, state *PaperState) error
+}
+
+// PaperExecutor 模拟盘执行器：维护本地模拟账户，按交易所实时盘口撮合、按实时价格估值，
+// 每次成交后持久化状态，重启后继续同一会话
+type PaperExecutor struct {
+	book        cex.OrderBookProvider
+	tradingPair cex.TradingPair
+	store       PaperStateStore // 为空时不持久化
+	feeSchedule *cex.FeeSchedule
+	bookDepth   int
+	resumed     bool            // 是否从已保存的会话恢复
+	stepSize    decimal.Decimal // 数量步长：按金额买入时成交数量向下取整（与交易所一致），为 0 时不取整
+
+	mu    sync.Mutex
+	state PaperState
+	now   func() time.Time
+}
+
+// NewPaperExecutor 创建模拟盘执行器；store 中已有同名会话时恢复其状态，否则以 initialCapital 开始新会话
+func NewPaperExecutor(ctx context.Context, client cex.CEXClient, pair cex.TradingPair, sessionID string, initialCapital decimal.Decimal, store PaperStateStore) (*PaperExecutor, error) {
+	book, ok := client.(cex.OrderBookProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s does not provide order book data required for paper trading", client.GetName())
+	}
+	if sessionID == "" {
+		return nil, fmt.Errorf("paper session ID is required")
+	}
+
+	e := &PaperExecutor{
+		book:        book,
+		tradingPair: pair,
+		store:       store,
+		bookDepth:   defaultPaperBookDepth,
+		now:         time.Now,
+	}
+
+	if store != nil {
+		saved, err := store.LoadPaperState(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load paper session %s: %w", sessionID, err)
+		}
+		if saved != nil {
+			if saved.Symbol != pair.String() {
+				return nil, fmt.Errorf("paper session %s trades %s, not %s", sessionID, saved.Symbol, pair.String())
+			}
+			e.state = *saved
+			e.resumed = true
+			return e, nil
+		}
+	}
+
+	now := e.now()
+	e.state = PaperState{
+		SessionID:      sessionID,
+		Symbol:         pair.String(),
+		InitialCapital: initialCapital,
+		Cash:           initialCapital,
+		Orders:         make([]OrderResult, 0),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	return e, nil
+}
+
+// SetFeeSchedule 设置手续费表（与回测、实盘规则一致）
+func (e *PaperExecutor) SetFeeSchedule(schedule cex.FeeSchedule) {
+	e.feeSchedule = &schedule
+}
+
+// SetQuantityStep 设置数量步长（交易对 LOT_SIZE），按金额买入的成交数量按步长向下取整
+func (e *PaperExecutor) SetQuantityStep(step decimal.Decimal) {
+	e.stepSize = step
+}
+
+// State 当前会话状态的副本
+func (e *PaperExecutor) State() PaperState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state := e.state
+	state.Orders = append([]OrderResult(nil), e.state.Orders...)
+	return state
+}
+
+// IsResumed 是否从已保存的会话恢复
+func (e *PaperExecutor) IsResumed() bool {
+	return e.resumed
+}
+
+// Buy 按实时卖盘撮合买单：市价单逐档吃单，限价单只吃价格不高于限价的档位，深度不足时部分成交
+func (e *PaperExecutor) Buy(ctx context.Context, order *BuyOrder) (*OrderResult, error) {
+	ctx, logger := log.WithCtx(ctx)
+	logger.PushPrefix("PaperExecutor")
+
+	book, err := e.book.GetOrderBook(ctx, e.tradingPair, e.bookDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	limit := decimal.Zero
+	if order.Type == OrderTypeLimit {
+		limit = order.Price
+	}
+	quantity, price := fillAgainstBook(book.Asks, order.Quantity, limit, true)
+	remaining := order.Quantity.Sub(quantity)
+	if order.IsQuoteOrder() {
+		quantity, price = fillQuoteAgainstBook(book.Asks, order.QuoteQuantity)
+		quantity = precision.FloorToStep(quantity, e.stepSize)
+		remaining = decimal.Zero // 金额没有用完（深度不足）时剩余部分不再挂单
+	}
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: buy %s @ %s, best ask %s", ErrPaperOrderNotFilled,
+			order.Quantity.String(), order.Price.String(), book.BestAsk().String())
+	}
+	if order.TimeInForce == cex.TimeInForceFOK && remaining.IsPositive() {
+		return nil, fmt.Errorf("%w: FOK buy %s @ %s, only %s available", ErrPaperOrderNotFilled,
+			order.Quantity.String(), order.Price.String(), quantity.String())
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	timestamp := e.now()
+	notional := quantity.Mul(price)
+	commission := e.commissionLocked(notional, order.Type, timestamp)
+	required := notional.Add(commission)
+	if e.state.Cash.LessThan(required) {
+		return &OrderResult{
+			OrderID:     fmt.Sprintf("failed_%d", time.Now().UnixNano()),
+			TradingPair: order.TradingPair,
+			Side:        OrderSideBuy,
+			Quantity:    order.Quantity,
+			Price:       price,
+			Timestamp:   timestamp,
+			Success:     false,
+			Error:       "insufficient cash",
+		}, fmt.Errorf("insufficient cash: required %s, available %s", required.String(), e.state.Cash.String())
+	}
+
+	result := e.newResultLocked(order.TradingPair, OrderSideBuy, quantity, price, commission, remaining, timestamp)
+	result.Reason, result.Origin, result.Signal = order.Reason, order.Origin, order.Signal
+	e.state.Cash = e.state.Cash.Sub(required)
+	e.state.Position = e.state.Position.Add(quantity)
+	e.state.CostBasis = e.state.CostBasis.Add(required)
+	e.state.MarkPrice = book.MidPrice()
+	e.state.Orders = append(e.state.Orders, *result)
+	e.saveLocked(ctx)
+
+	logger.Info(fmt.Sprintf("📝 模拟买入: %s @ %s (卖一 %s), 手续费: %s, 余额: %s",
+		quantity.String(), price.String(), book.BestAsk().String(), commission.String(), e.state.Cash.String()))
+
+	return result, nil
+}
+
+// Sell 按实时买盘撮合卖单：市价单逐档吃单，限价单只吃价格不低于限价的档位，深度不足时部分成交
+func (e *PaperExecutor) Sell(ctx context.Context, order *SellOrder) (*OrderResult, error) {
+	ctx, logger := log.WithCtx(ctx)
+	logger.PushPrefix("PaperExecutor")
+
+	e.mu.Lock()
+	position := e.state.Position
+	e.mu.Unlock()
+	if position.LessThan(order.Quantity) {
+		return &OrderResult{
+			OrderID:     fmt.Sprintf("failed_%d", time.Now().UnixNano()),
+			TradingPair: order.TradingPair,
+			Side:        OrderSideSell,
+			Quantity:    order.Quantity,
+			Price:       order.Price,
+			Timestamp:   e.now(),
+			Success:     false,
+			Error:       "insufficient position",
+		}, fmt.Errorf("insufficient position: required %s, available %s", order.Quantity.String(), position.String())
+	}
+
+	book, err := e.book.GetOrderBook(ctx, e.tradingPair, e.bookDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	limit := decimal.Zero
+	if order.Type == OrderTypeLimit {
+		limit = order.Price
+	}
+	quantity, price := fillAgainstBook(book.Bids, order.Quantity, limit, false)
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: sell %s @ %s, best bid %s", ErrPaperOrderNotFilled,
+			order.Quantity.String(), order.Price.String(), book.BestBid().String())
+	}
+	if order.TimeInForce == cex.TimeInForceFOK && quantity.LessThan(order.Quantity) {
+		return nil, fmt.Errorf("%w: FOK sell %s @ %s, only %s available", ErrPaperOrderNotFilled,
+			order.Quantity.String(), order.Price.String(), quantity.String())
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	timestamp := e.now()
+	notional := quantity.Mul(price)
+	commission := e.commissionLocked(notional, order.Type, timestamp)
+
+	// 按卖出比例结转持仓成本计算盈亏
+	cost := e.state.CostBasis
+	if quantity.LessThan(e.state.Position) {
+		cost = e.state.CostBasis.Mul(quantity).Div(e.state.Position)
+	}
+	pnl := notional.Sub(commission).Sub(cost)
+	if pnl.IsPositive() {
+		e.state.WinningTrades++
+	} else {
+		e.state.LosingTrades++
+	}
+	e.state.TotalTrades++
+
+	result := e.newResultLocked(order.TradingPair, OrderSideSell, quantity, price, commission, order.Quantity.Sub(quantity), timestamp)
+	result.Reason, result.Origin, result.Signal = order.Reason, order.Origin, order.Signal
+	e.state.Cash = e.state.Cash.Add(notional).Sub(commission)
+	e.state.Position = e.state.Position.Sub(quantity)
+	e.state.CostBasis = e.state.CostBasis.Sub(cost)
+	e.state.MarkPrice = book.MidPrice()
+	e.state.Orders = append(e.state.Orders, *result)
+	e.saveLocked(ctx)
+
+	logger.Info(fmt.Sprintf("📝 模拟卖出: %s @ %s (买一 %s), 手续费: %s, 盈亏: %s, 余额: %s",
+		quantity.String(), price.String(), book.BestBid().String(), commission.String(), pnl.String(), e.state.Cash.String()))
+
+	return result, nil
+}
+
+// GetPortfolio 获取模拟账户状态，持仓按实时中间价估值（获取盘口失败时使用上次估值价格）
+func (e *PaperExecutor) GetPortfolio(ctx context.Context) (*Portfolio, error) {
+	ctx, logger := log.WithCtx(ctx)
+
+	if book, err := e.book.GetOrderBook(ctx, e.tradingPair, 1); err != nil {
+		logger.Warning(fmt.Sprintf("⚠️ 获取盘口失败，使用上次估值价格: %v", err))
+	} else if mid := book.MidPrice(); mid.IsPositive() {
+		e.mu.Lock()
+		e.state.MarkPrice = mid
+		e.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return &Portfolio{
+		Cash:      e.state.Cash,
+		Position:  e.state.Position,
+		Portfolio: e.state.Cash.Add(e.state.Position.Mul(e.state.MarkPrice)),
+		Timestamp: e.now(),
+	}, nil
+}
+
+// GetOrders 获取本会话所有成交记录
+func (e *PaperExecutor) GetOrders() []OrderResult {
+	return e.State().Orders
+}
+
+// GetName 获取执行器名称
+func (e *PaperExecutor) GetName() string {
+	return "PaperExecutor"
+}
+
+// Close 关闭执行器，保存最新状态
+func (e *PaperExecutor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.store == nil {
+		return nil
+	}
+	e.state.UpdatedAt = e.now()
+	return e.store.SavePaperState(context.Background(), &e.state)
+}
+
+// newResultLocked 生成模拟成交结果（调用方需持有锁）
+func (e *PaperExecutor) newResultLocked(pair cex.TradingPair, side OrderSide, quantity, price, commission, remaining decimal.Decimal, timestamp time.Time) *OrderResult {
+	return &OrderResult{
+		OrderID:           fmt.Sprintf("paper_%d", timestamp.UnixNano()),
+		TradingPair:       pair,
+		Side:              side,
+		Quantity:          quantity,
+		Price:             price,
+		Commission:        commission,
+		Timestamp:         timestamp,
+		Success:           true,
+		RemainingQuantity: remaining,
+	}
+}
+
+// commissionLocked 计算手续费：限价单按 maker、市价单按 taker（调用方需持有锁）
+func (e *PaperExecutor) commissionLocked(notional decimal.Decimal, orderType OrderType, timestamp time.Time) decimal.Decimal {
+	if e.feeSchedule == nil {
+		return decimal.Zero
+	}
+	volume := decimal.Zero
+	since := timestamp.Add(-feeVolumeWindow)
+	for _, order := range e.state.Orders {
+		if order.Success && !order.Timestamp.Before(since) {
+			volume = volume.Add(order.Quantity.Mul(order.Price))
+		}
+	}
+	return e.feeSchedule.Commission(notional, orderType == OrderTypeLimit, volume.InexactFloat64())
+}
+
+// saveLocked 持久化状态，失败时只记录日志，本次成交仍然有效（调用方需持有锁）
+func (e *PaperExecutor) saveLocked(ctx context.Context) {
+	e.state.UpdatedAt = e.now()
+	if e.store == nil {
+		return
+	}
+	if err := e.store.SavePaperState(ctx, &e.state); err != nil {
+		_, logger := log.WithCtx(ctx)
+		logger.Error(fmt.Sprintf("⚠️ 保存模拟盘状态失败: %v", err))
+	}
+}
+
+// fillQuoteAgainstBook 按计价资产金额逐档吃卖盘，返回成交数量和成交均价
+func fillQuoteAgainstBook(levels []cex.OrderBookLevel, quoteQuantity decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	filled := decimal.Zero
+	spent := decimal.Zero
+
+	for _, level := range levels {
+		if spent.GreaterThanOrEqual(quoteQuantity) || !level.Price.IsPositive() {
+			break
+		}
+		take := decimal.Min(level.Quantity, quoteQuantity.Sub(spent).Div(level.Price))
+		filled = filled.Add(take)
+		spent = spent.Add(take.Mul(level.Price))
+	}
+
+	if !filled.IsPositive() {
+		return decimal.Zero, decimal.Zero
+	}
+	return filled, spent.Div(filled)
+}
+
+// fillAgainstBook 逐档吃单，返回成交数量和成交均价
+// limit 为零时不限价；买单只吃价格不高于 limit 的卖盘，卖单只吃价格不低于 limit 的买盘
+func fillAgainstBook(levels []cex.OrderBookLevel, quantity, limit decimal.Decimal, isBuy bool) (decimal.Decimal, decimal.Decimal) {
+	filled := decimal.Zero
+	notional := decimal.Zero
+
+	for _, level := range levels {
+		if filled.GreaterThanOrEqual(quantity) {
+			break
+		}
+		if limit.IsPositive() {
+			if isBuy && level.Price.GreaterThan(limit) {
+				break
+			}
+			if !isBuy && level.Price.LessThan(limit) {
+				break
+			}
+		}
+		take := decimal.Min(level.Quantity, quantity.Sub(filled))
+		filled = filled.Add(take)
+		notional = notional.Add(take.Mul(level.Price))
+	}
+
+	if !filled.IsPositive() {
+		return decimal.Zero, decimal.Zero
+	}
+	return filled, notional.Div(filled)
+}
@@ -0,0 +1,202 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var paperTestPair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+// mockBookClient 返回固定盘口的交易所mock
+type mockBookClient struct {
+	cex.CEXClient
+	book *cex.OrderBook
+	err  error
+}
+
+func (m *mockBookClient) GetName() string { return "mock" }
+
+func (m *mockBookClient) GetOrderBook(ctx context.Context, pair cex.TradingPair, limit int) (*cex.OrderBook, error) {
+	return m.book, m.err
+}
+
+// memoryPaperStore 内存模拟盘状态存储
+type memoryPaperStore struct {
+	states map[string]PaperState
+	saves  int
+}
+
+func (s *memoryPaperStore) LoadPaperState(ctx context.Context, sessionID string) (*PaperState, error) {
+	state, ok := s.states[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (s *memoryPaperStore) SavePaperState(ctx context.Context, state *PaperState) error {
+	saved := *state
+	saved.Orders = append([]OrderResult(nil), state.Orders...)
+	s.states[state.SessionID] = saved
+	s.saves++
+	return nil
+}
+
+func level(price, quantity float64) cex.OrderBookLevel {
+	return cex.OrderBookLevel{Price: decimal.NewFromFloat(price), Quantity: decimal.NewFromFloat(quantity)}
+}
+
+func newTestBook() *cex.OrderBook {
+	return &cex.OrderBook{
+		TradingPair: paperTestPair,
+		Bids:        []cex.OrderBookLevel{level(99, 1), level(98, 2)},
+		Asks:        []cex.OrderBookLevel{level(101, 1), level(102, 2)},
+	}
+}
+
+func TestPaperExecutor_MarketOrdersWalkTheBook(t *testing.T) {
+	client := &mockBookClient{book: newTestBook()}
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	require.NoError(t, err)
+	e.SetFeeSchedule(cex.FeeSchedule{MakerRate: 0.001, TakerRate: 0.002})
+
+	// 买2个：卖一 101×1 + 卖二 102×1，均价101.5
+	result, err := e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(2)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(101.5).Equal(result.Price))
+	assert.True(t, decimal.NewFromInt(2).Equal(result.Quantity))
+	assert.True(t, decimal.NewFromFloat(0.406).Equal(result.Commission))
+
+	// 持仓按中间价100估值
+	portfolio, err := e.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(796.594).Equal(portfolio.Cash))
+	assert.True(t, decimal.NewFromFloat(996.594).Equal(portfolio.Portfolio))
+
+	// 卖2个：买一 99×1 + 买二 98×1，均价98.5，亏损
+	result, err = e.Sell(context.Background(), &SellOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(2)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(98.5).Equal(result.Price))
+
+	state := e.State()
+	assert.True(t, state.Position.IsZero())
+	assert.True(t, state.CostBasis.IsZero())
+	assert.Equal(t, 1, state.TotalTrades)
+	assert.Equal(t, 1, state.LosingTrades)
+}
+
+func TestPaperExecutor_QuoteQuantityBuy(t *testing.T) {
+	client := &mockBookClient{book: newTestBook()}
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	require.NoError(t, err)
+
+	// 买入 152：卖一 101×1，剩余 51 按 102 买 0.5
+	result, err := e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, QuoteQuantity: decimal.NewFromInt(152)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(1.5).Equal(result.Quantity))
+	assert.True(t, decimal.NewFromInt(152).Equal(result.Quantity.Mul(result.Price).Round(8)))
+	assert.True(t, result.RemainingQuantity.IsZero())
+	assert.True(t, decimal.NewFromInt(848).Equal(e.State().Cash.Round(8)))
+}
+
+func TestPaperExecutor_LimitOrdersRespectPriceAndDepth(t *testing.T) {
+	client := &mockBookClient{book: newTestBook()}
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	require.NoError(t, err)
+
+	// 限价100低于卖一，不成交
+	_, err = e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)})
+	assert.True(t, errors.Is(err, ErrPaperOrderNotFilled))
+
+	// FOK 深度不足时整单不成交
+	_, err = e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(3), Price: decimal.NewFromInt(101), TimeInForce: cex.TimeInForceFOK})
+	assert.True(t, errors.Is(err, ErrPaperOrderNotFilled))
+	assert.True(t, e.State().Position.IsZero())
+
+	// 限价101只能吃到卖一的1个，部分成交
+	result, err := e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeLimit, Quantity: decimal.NewFromInt(3), Price: decimal.NewFromInt(101)})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(result.Quantity))
+	assert.True(t, decimal.NewFromInt(2).Equal(result.RemainingQuantity))
+	assert.True(t, result.IsPartiallyFilled())
+
+	// 持仓不足
+	_, err = e.Sell(context.Background(), &SellOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(2)})
+	assert.Error(t, err)
+}
+
+func TestPaperExecutor_PersistsAndResumesSession(t *testing.T) {
+	store := &memoryPaperStore{states: make(map[string]PaperState)}
+	client := &mockBookClient{book: newTestBook()}
+
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), store)
+	require.NoError(t, err)
+	assert.False(t, e.IsResumed())
+	_, err = e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.saves)
+
+	// 重启后恢复同一会话，初始资金参数被忽略
+	resumed, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(5000), store)
+	require.NoError(t, err)
+	assert.True(t, resumed.IsResumed())
+	state := resumed.State()
+	assert.True(t, decimal.NewFromInt(899).Equal(state.Cash))
+	assert.True(t, decimal.NewFromInt(1).Equal(state.Position))
+	assert.Len(t, state.Orders, 1)
+
+	// 会话属于其他交易对
+	_, err = NewPaperExecutor(context.Background(), client, cex.TradingPair{Base: "ETH", Quote: "USDT"}, "s1", decimal.NewFromInt(1000), store)
+	assert.Error(t, err)
+}
+
+func TestPaperExecutor_MarkPriceFallback(t *testing.T) {
+	client := &mockBookClient{book: newTestBook()}
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	require.NoError(t, err)
+	e.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	_, err = e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, Quantity: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+
+	// 获取盘口失败时沿用上次估值价格
+	client.err = errors.New("timeout")
+	portfolio, err := e.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(999).Equal(portfolio.Portfolio))
+}
+
+func TestNewPaperExecutor_RequiresOrderBook(t *testing.T) {
+	_, err := NewPaperExecutor(context.Background(), &mockNoBookClient{}, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	assert.Error(t, err)
+}
+
+// mockNoBookClient 不支持订单簿的交易所mock
+type mockNoBookClient struct {
+	cex.CEXClient
+}
+
+func (m *mockNoBookClient) GetName() string { return "mock" }
+
+func TestPaperExecutor_QuoteQuantityRoundsToStep(t *testing.T) {
+	client := &mockBookClient{book: &cex.OrderBook{
+		TradingPair: paperTestPair,
+		Asks:        []cex.OrderBookLevel{{Price: decimal.RequireFromString("0.00001234"), Quantity: decimal.NewFromInt(100000000)}},
+	}}
+	e, err := NewPaperExecutor(context.Background(), client, paperTestPair, "s1", decimal.NewFromInt(1000), nil)
+	require.NoError(t, err)
+	e.SetQuantityStep(decimal.NewFromInt(1))
+
+	// 500 / 0.00001234 = 40518638.57…，按整数步长向下取整
+	result, err := e.Buy(context.Background(), &BuyOrder{TradingPair: paperTestPair, Type: OrderTypeMarket, QuoteQuantity: decimal.NewFromInt(500)})
+	require.NoError(t, err)
+	assert.Equal(t, "40518638", result.Quantity.String())
+	assert.True(t, result.Quantity.Mul(result.Price).LessThanOrEqual(decimal.NewFromInt(500)))
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ExecutorState 执行器的账户状态和交易记录（回测断点保存和恢复）
+type ExecutorState struct {
+	InitialCapital decimal.Decimal `json:"initial_capital"`
+	Cash           decimal.Decimal `json:"cash"`
+	Position       decimal.Decimal `json:"position"`
+	Portfolio      decimal.Decimal `json:"portfolio"`
+	Orders         []OrderResult   `json:"orders"`
+	TotalTrades    int             `json:"total_trades"`
+	WinningTrades  int             `json:"winning_trades"`
+	LosingTrades   int             `json:"losing_trades"`
+	CarryingCost   decimal.Decimal `json:"carrying_cost"`
+}
+
+// ExportState 导出账户状态和交易记录（副本）
+func (e *TradingExecutor) ExportState() ExecutorState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return ExecutorState{
+		InitialCapital: e.initialCapital,
+		Cash:           e.cash,
+		Position:       e.position,
+		Portfolio:      e.portfolio,
+		Orders:         append([]OrderResult(nil), e.orders...),
+		TotalTrades:    e.totalTrades,
+		WinningTrades:  e.winningTrades,
+		LosingTrades:   e.losingTrades,
+		CarryingCost:   e.carryingCost,
+	}
+}
+
+// RestoreState 恢复导出的账户状态和交易记录（订单策略、手续费表和资金成本模型保持不变）
+func (e *TradingExecutor) RestoreState(state ExecutorState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.initialCapital = state.InitialCapital
+	e.cash = state.Cash
+	e.position = state.Position
+	e.portfolio = state.Portfolio
+	e.orders = append(make([]OrderResult, 0, len(state.Orders)), state.Orders...)
+	e.totalTrades = state.TotalTrades
+	e.winningTrades = state.WinningTrades
+	e.losingTrades = state.LosingTrades
+	e.carryingCost = state.CarryingCost
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"tradingbot/src/cex"
@@ -16,8 +17,10 @@ type TradingExecutor struct {
 	tradingPair    cex.TradingPair
 	initialCapital decimal.Decimal
 	orderStrategy  OrderStrategy
+	feeSchedule    *cex.FeeSchedule // 手续费表（为空时不扣手续费）
 
-	// 本地状态管理（回测和实盘都需要）
+	// 本地状态管理（回测和实盘都需要）；实盘对账协程会并发校正余额
+	mu        sync.Mutex
 	cash      decimal.Decimal
 	position  decimal.Decimal
 	portfolio decimal.Decimal
@@ -27,6 +30,10 @@ type TradingExecutor struct {
 	totalTrades   int
 	winningTrades int
 	losingTrades  int
+
+	// 资金成本（回测按K线计提）
+	carryingModel CarryingCostModel
+	carryingCost  decimal.Decimal
 }
 
 // NewTradingExecutor 创建交易执行器
@@ -38,6 +45,7 @@ func NewTradingExecutor(pair cex.TradingPair, initialCapital decimal.Decimal) *T
 		position:       decimal.Zero,
 		portfolio:      initialCapital,
 		orders:         make([]OrderResult, 0),
+		carryingCost:   decimal.Zero,
 	}
 }
 
@@ -46,20 +54,55 @@ func (e *TradingExecutor) SetOrderStrategy(strategy OrderStrategy) {
 	e.orderStrategy = strategy
 }
 
+// SetFeeSchedule 设置手续费表（回测和实盘共用，按 maker/taker 和成交额等级计算手续费）
+func (e *TradingExecutor) SetFeeSchedule(schedule cex.FeeSchedule) {
+	e.feeSchedule = &schedule
+}
+
+// commission 计算手续费：限价单按 maker、市价单按 taker，等级按最近30天成交额查找
+func (e *TradingExecutor) commission(notional decimal.Decimal, orderType OrderType, timestamp time.Time) decimal.Decimal {
+	if e.feeSchedule == nil {
+		return decimal.Zero
+	}
+	return e.feeSchedule.Commission(notional, orderType == OrderTypeLimit, e.volumeSince(timestamp.Add(-feeVolumeWindow)))
+}
+
+// feeVolumeWindow 费率等级的成交额统计窗口
+const feeVolumeWindow = 30 * 24 * time.Hour
+
+// volumeSince 统计指定时间之后的成交额（计价资产）
+func (e *TradingExecutor) volumeSince(since time.Time) float64 {
+	volume := decimal.Zero
+	for _, order := range e.orders {
+		if order.Success && !order.Timestamp.Before(since) {
+			volume = volume.Add(order.Quantity.Mul(order.Price))
+		}
+	}
+	return volume.InexactFloat64()
+}
+
 // Buy 执行买入订单（统一业务逻辑）
 func (e *TradingExecutor) Buy(ctx context.Context, order *BuyOrder) (*OrderResult, error) {
 	ctx, logger := log.WithCtx(ctx)
 	logger.PushPrefix("TradingExecutor")
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	// 删除详细的执行步骤日志，买入结果将在最后统一记录
 
 	// 1. 业务逻辑检查（回测和实盘都需要）
 	executionPrice := order.Price
 	notional := order.Quantity.Mul(executionPrice)
+	if order.IsQuoteOrder() {
+		notional = order.QuoteQuantity
+	}
+	commission := e.commission(notional, order.Type, order.Timestamp)
+	required := notional.Add(commission)
 
-	// 资金充足性检查
-	if e.cash.LessThan(notional) {
-		logger.Error("资金不足", "required", notional.String(), "available", e.cash.String())
+	// 资金充足性检查（含手续费）
+	if e.cash.LessThan(required) {
+		logger.Error("资金不足", "required", required.String(), "available", e.cash.String())
 		return &OrderResult{
 			OrderID:     fmt.Sprintf("failed_%d", time.Now().UnixNano()),
 			TradingPair: order.TradingPair,
@@ -69,7 +112,7 @@ func (e *TradingExecutor) Buy(ctx context.Context, order *BuyOrder) (*OrderResul
 			Timestamp:   order.Timestamp,
 			Success:     false,
 			Error:       "insufficient cash",
-		}, fmt.Errorf("insufficient cash: required %s, available %s", notional.String(), e.cash.String())
+		}, fmt.Errorf("insufficient cash: required %s, available %s", required.String(), e.cash.String())
 	}
 
 	// 2. 委托给具体的订单策略（差异化处理）
@@ -79,14 +122,24 @@ func (e *TradingExecutor) Buy(ctx context.Context, order *BuyOrder) (*OrderResul
 	}
 
 	// 3. 更新本地状态（回测和实盘都需要）
-	e.cash = e.cash.Sub(notional)
-	e.position = e.position.Add(order.Quantity)
+	quantity := order.Quantity
+	if order.IsQuoteOrder() {
+		// 按金额买入：以实际成交数量和均价记账
+		quantity, executionPrice = result.Quantity, result.Price
+		notional = quantity.Mul(executionPrice)
+		commission = e.commission(notional, order.Type, order.Timestamp)
+		required = notional.Add(commission)
+	}
+	result.Commission = commission
+	result.Reason, result.Origin, result.Signal = order.Reason, order.Origin, order.Signal
+	e.cash = e.cash.Sub(required)
+	e.position = e.position.Add(quantity)
 
 	// 4. 记录订单和统计（回测和实盘都需要）
 	e.orders = append(e.orders, *result)
 
-	logger.Info(fmt.Sprintf("💰 买入完成: %s @ %s, 余额: %s", 
-		order.Quantity.String(), executionPrice.String(), e.cash.String()))
+	logger.Info(fmt.Sprintf("💰 买入完成: %s @ %s, 手续费: %s, 余额: %s", 
+		quantity.String(), executionPrice.String(), commission.String(), e.cash.String()))
 
 	return result, nil
 }
@@ -96,6 +149,9 @@ func (e *TradingExecutor) Sell(ctx context.Context, order *SellOrder) (*OrderRes
 	ctx, logger := log.WithCtx(ctx)
 	logger.PushPrefix("TradingExecutor")
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	// 删除详细的执行步骤日志，卖出结果将在最后统一记录
 
 	// 1. 业务逻辑检查（回测和实盘都需要）
@@ -122,17 +178,40 @@ func (e *TradingExecutor) Sell(ctx context.Context, order *SellOrder) (*OrderRes
 	// 3. 更新本地状态（回测和实盘都需要）
 	executionPrice := result.Price
 	notional := order.Quantity.Mul(executionPrice)
+	commission := e.commission(notional, order.Type, order.Timestamp)
 
-	e.cash = e.cash.Add(notional)
+	result.Commission = commission
+	result.Reason, result.Origin, result.Signal = order.Reason, order.Origin, order.Signal
+	e.cash = e.cash.Add(notional).Sub(commission)
 	e.position = e.position.Sub(order.Quantity)
 
 	// 4. 计算盈亏和统计（回测和实盘都需要）
+	e.recordTradePnLLocked(logger, order.Quantity, executionPrice, commission)
+
+	// 5. 更新投资组合价值
+	e.portfolio = e.cash.Add(e.position.Mul(executionPrice))
+
+	// 6. 记录订单
+	e.orders = append(e.orders, *result)
+
+	logger.Info(fmt.Sprintf("💎 卖出完成: %s @ %s, 手续费: %s, 余额: %s", 
+		order.Quantity.String(), executionPrice.String(), commission.String(), e.cash.String()))
+
+	return result, nil
+}
+
+// recordTradePnLLocked 按最近的买入订单计算卖出盈亏并更新统计（调用方需持有锁）
+func (e *TradingExecutor) recordTradePnLLocked(logger *log.Logger, quantity, executionPrice, commission decimal.Decimal) {
 	if len(e.orders) > 0 {
 		// 找到最近的买入订单计算盈亏
 		for i := len(e.orders) - 1; i >= 0; i-- {
 			if e.orders[i].Side == OrderSideBuy {
 				buyPrice := e.orders[i].Price
-				pnl := order.Quantity.Mul(executionPrice.Sub(buyPrice))
+				pnl := quantity.Mul(executionPrice.Sub(buyPrice)).Sub(commission)
+				if e.orders[i].Quantity.IsPositive() {
+					// 按卖出数量分摊买入手续费
+					pnl = pnl.Sub(e.orders[i].Commission.Mul(quantity).Div(e.orders[i].Quantity))
+				}
 
 				// 更新盈亏统计
 				if pnl.GreaterThan(decimal.Zero) {
@@ -151,23 +230,38 @@ func (e *TradingExecutor) Sell(ctx context.Context, order *SellOrder) (*OrderRes
 			}
 		}
 	}
+}
 
-	// 5. 更新投资组合价值
-	e.portfolio = e.cash.Add(e.position.Mul(executionPrice))
+// RecordFill 记录交易所直接成交的订单（如交易所触发的止损单、OCO），更新本地现金、持仓和统计
+func (e *TradingExecutor) RecordFill(ctx context.Context, result *OrderResult) {
+	ctx, logger := log.WithCtx(ctx)
+	logger.PushPrefix("TradingExecutor")
 
-	// 6. 记录订单
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	notional := result.Quantity.Mul(result.Price)
+	if result.Side == OrderSideBuy {
+		e.cash = e.cash.Sub(notional).Sub(result.Commission)
+		e.position = e.position.Add(result.Quantity)
+	} else {
+		e.cash = e.cash.Add(notional).Sub(result.Commission)
+		e.position = e.position.Sub(result.Quantity)
+		e.recordTradePnLLocked(logger, result.Quantity, result.Price, result.Commission)
+		e.portfolio = e.cash.Add(e.position.Mul(result.Price))
+	}
 	e.orders = append(e.orders, *result)
 
-	logger.Info(fmt.Sprintf("💎 卖出完成: %s @ %s, 余额: %s", 
-		order.Quantity.String(), executionPrice.String(), e.cash.String()))
-
-	return result, nil
+	logger.Info(fmt.Sprintf("🔔 交易所成交回报: %s %s @ %s, 手续费: %s, 余额: %s",
+		result.Side, result.Quantity.String(), result.Price.String(), result.Commission.String(), e.cash.String()))
 }
 
 // GetPortfolio 获取当前投资组合状态
 func (e *TradingExecutor) GetPortfolio(ctx context.Context) (*Portfolio, error) {
 	// 对于实盘交易，可以选择返回本地状态或从CEX获取实时状态
 	// 这里先返回本地维护的状态，保持一致性
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	return &Portfolio{
 		Cash:      e.cash,
 		Position:  e.position,
@@ -178,11 +272,39 @@ func (e *TradingExecutor) GetPortfolio(ctx context.Context) (*Portfolio, error)
 
 // GetOrders 获取所有订单记录
 func (e *TradingExecutor) GetOrders() []OrderResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	return e.orders
 }
 
+// SyncBalances 用交易所余额校正本地现金和持仓。ordersSeen 为读取交易所余额前的订单记录数，
+// 期间有新成交时交易所余额快照已过期，放弃本次校正并返回 false
+func (e *TradingExecutor) SyncBalances(cash, position decimal.Decimal, ordersSeen int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.orders) != ordersSeen {
+		return false
+	}
+
+	e.cash = cash
+	e.position = position
+	if len(e.orders) == 0 {
+		// 尚无成交：账户余额即初始资金（持仓暂无成交价可估值）
+		e.portfolio = cash
+		e.initialCapital = cash
+		return true
+	}
+	// 持仓按最近成交价估值
+	e.portfolio = cash.Add(position.Mul(e.orders[len(e.orders)-1].Price))
+	return true
+}
+
 // GetStatistics 获取交易统计
 func (e *TradingExecutor) GetStatistics() map[string]interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	totalReturn := decimal.Zero
 	if !e.initialCapital.IsZero() {
 		totalReturn = e.portfolio.Sub(e.initialCapital).Div(e.initialCapital)
@@ -77,6 +77,57 @@ func TestTradingExecutor_WithBacktestOrderStrategy(t *testing.T) {
 	assert.Equal(t, 2, len(orders)) // 2个订单
 }
 
+func TestTradingExecutor_QuoteQuantityBuy(t *testing.T) {
+	pair := cex.TradingPair{Base: "PEPE", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(1000))
+	executor.SetOrderStrategy(NewBacktestOrderStrategy(pair))
+	executor.SetFeeSchedule(cex.FeeSchedule{MakerRate: 0.001, TakerRate: 0.001})
+
+	// 买入 500 USDT，成交数量 = 500 / 0.00001234
+	result, err := executor.Buy(context.Background(), &BuyOrder{
+		TradingPair:   pair,
+		Type:          OrderTypeMarket,
+		Price:         decimal.RequireFromString("0.00001234"),
+		QuoteQuantity: decimal.NewFromInt(500),
+		Timestamp:     time.Now(),
+	})
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(500).Div(decimal.RequireFromString("0.00001234")).Equal(result.Quantity))
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(result.Commission.Round(8)))
+
+	portfolio, err := executor.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Quantity.Equal(portfolio.Position))
+	assert.True(t, decimal.NewFromFloat(499.5).Equal(portfolio.Cash.Round(8)))
+}
+
+func TestTradingExecutor_RecordsSignalContext(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(1000))
+	executor.SetOrderStrategy(NewBacktestOrderStrategy(pair))
+
+	signal := &SignalContext{Type: "BUY", Reason: "lower band touch", Price: decimal.NewFromInt(100), Indicators: map[string]float64{"bb_percent_b": -0.1}}
+	result, err := executor.Buy(context.Background(), &BuyOrder{
+		TradingPair: pair,
+		Type:        OrderTypeLimit,
+		Quantity:    decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(101),
+		Timestamp:   time.Now(),
+		Reason:      "lower band touch",
+		Origin:      "BUY",
+		Signal:      signal,
+	})
+	require.NoError(t, err)
+	assert.Same(t, signal, result.Signal)
+	assert.Equal(t, "lower band touch", result.Reason)
+	assert.Equal(t, "BUY", result.Origin)
+
+	// 成交记录保留信号上下文，供交易日志使用
+	orders := executor.GetOrders()
+	require.Len(t, orders, 1)
+	assert.Equal(t, "lower band touch", orders[0].Signal.Reason)
+}
+
 // TestTradingExecutor_InsufficientCash 测试资金不足
 func TestTradingExecutor_InsufficientCash(t *testing.T) {
 	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
@@ -183,3 +234,121 @@ func TestTradingExecutor_WinRateCalculation(t *testing.T) {
 	orders := executor.GetOrders()
 	assert.Equal(t, 4, len(orders)) // 总共4个订单（2买+2卖）
 }
+
+// TestOrderResult_AddFill 测试分批成交累计加权均价
+func TestOrderResult_AddFill(t *testing.T) {
+	result := &OrderResult{Side: OrderSideBuy, Success: true}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	result.AddFill(Fill{Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Commission: decimal.NewFromFloat(0.1), Timestamp: start})
+	result.AddFill(Fill{Quantity: decimal.NewFromInt(3), Price: decimal.NewFromInt(104), Commission: decimal.NewFromFloat(0.3), Timestamp: start.Add(time.Hour)})
+
+	assert.True(t, result.Quantity.Equal(decimal.NewFromInt(4)))
+	assert.True(t, result.Price.Equal(decimal.NewFromInt(103)))
+	assert.True(t, result.Commission.Equal(decimal.NewFromFloat(0.4)))
+	assert.Equal(t, start.Add(time.Hour), result.Timestamp)
+	require.Len(t, result.Fills, 2)
+
+	assert.False(t, result.IsPartiallyFilled())
+	result.RemainingQuantity = decimal.NewFromInt(1)
+	assert.True(t, result.IsPartiallyFilled())
+}
+
+// TestTradingExecutor_FeeSchedule 测试按 maker/taker 扣除手续费
+func TestTradingExecutor_FeeSchedule(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	executor.SetOrderStrategy(NewBacktestOrderStrategy(pair))
+	executor.SetFeeSchedule(cex.FeeSchedule{MakerRate: 0.001, TakerRate: 0.002})
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 限价买入按 maker 费率
+	result, err := executor.Buy(ctx, &BuyOrder{
+		TradingPair: pair,
+		Type:        OrderTypeLimit,
+		Quantity:    decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(1000),
+		Timestamp:   start,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Commission.Equal(decimal.NewFromInt(1)))
+
+	// 市价卖出按 taker 费率
+	result, err = executor.Sell(ctx, &SellOrder{
+		TradingPair: pair,
+		Type:        OrderTypeMarket,
+		Quantity:    decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(1001),
+		Timestamp:   start.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Commission.Equal(decimal.NewFromFloat(2.002)))
+
+	portfolio, err := executor.GetPortfolio(ctx)
+	require.NoError(t, err)
+	assert.True(t, portfolio.Cash.Equal(decimal.NewFromFloat(9997.998)))
+
+	// 价差不足以覆盖手续费，计为亏损交易
+	stats := executor.GetStatistics()
+	assert.Equal(t, 1, stats["losing_trades"])
+}
+
+func TestTradingExecutor_SyncBalances(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(10000))
+	executor.SetOrderStrategy(NewBacktestOrderStrategy(pair))
+
+	// 尚无成交：交易所余额即初始资金
+	assert.True(t, executor.SyncBalances(decimal.NewFromInt(2000), decimal.Zero, 0))
+	stats := executor.GetStatistics()
+	assert.True(t, decimal.NewFromInt(2000).Equal(stats["initial_capital"].(decimal.Decimal)))
+
+	_, err := executor.Buy(context.Background(), &BuyOrder{
+		TradingPair: pair,
+		Quantity:    decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(1000),
+		Timestamp:   time.Now(),
+	})
+	require.NoError(t, err)
+
+	// 余额快照读取后发生了成交，放弃校正
+	assert.False(t, executor.SyncBalances(decimal.NewFromInt(2000), decimal.Zero, 0))
+
+	// 持仓按最近成交价估值
+	assert.True(t, executor.SyncBalances(decimal.NewFromInt(900), decimal.NewFromInt(1), 1))
+	portfolio, err := executor.GetPortfolio(context.Background())
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(900).Equal(portfolio.Cash))
+	assert.True(t, decimal.NewFromInt(1900).Equal(portfolio.Portfolio))
+}
+
+func TestTradingExecutor_RecordFill(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	executor := NewTradingExecutor(pair, decimal.NewFromInt(1000))
+	executor.SetOrderStrategy(NewBacktestOrderStrategy(pair))
+	ctx := context.Background()
+
+	_, err := executor.Buy(ctx, &BuyOrder{TradingPair: pair, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	// 交易所触发的止损单成交
+	executor.RecordFill(ctx, &OrderResult{
+		OrderID:     "stop_1",
+		TradingPair: pair,
+		Side:        OrderSideSell,
+		Quantity:    decimal.NewFromInt(1),
+		Price:       decimal.NewFromInt(90),
+		Commission:  decimal.NewFromFloat(0.5),
+		Success:     true,
+	})
+
+	portfolio, err := executor.GetPortfolio(ctx)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(989.5).Equal(portfolio.Cash))
+	assert.True(t, portfolio.Position.IsZero())
+	stats := executor.GetStatistics()
+	assert.Equal(t, 1, stats["losing_trades"])
+	assert.Len(t, executor.GetOrders(), 2)
+}
@@ -0,0 +1,32 @@
+package i18n
+
+import (
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// Config 本地化配置
+type Config struct {
+	Locale string `json:"locale"` // 输出语言：zh、en 或 auto（按 LANG 环境变量检测，默认）
+}
+
+// ConfigValue 本地化配置实例
+var ConfigValue = Config{
+	Locale: "auto",
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("I18N", &ConfigValue)
+}
+
+// Apply 按配置设置输出语言（配置加载后调用）
+func (c Config) Apply() error {
+	locale, err := ParseLocale(c.Locale)
+	if err != nil {
+		return err
+	}
+	SetLocale(locale)
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package i18n 命令行输出的本地化：回测报告、引擎日志和命令行错误按语言（zh/en）从消息表取文案
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Locale 输出语言
+type Locale string
+
+const (
+	LocaleZH Locale = "zh" // 中文
+	LocaleEN Locale = "en" // 英文
+)
+
+// current 当前语言，默认按环境变量检测
+var current atomic.Value
+
+func init() {
+	current.Store(DetectLocale())
+}
+
+// ParseLocale 解析语言设置：zh / en，空字符串或 auto 时按环境变量检测
+func ParseLocale(s string) (Locale, error) {
+	switch strings.ToLower(strings.ReplaceAll(s, "_", "-")) {
+	case "", "auto":
+		return DetectLocale(), nil
+	case "zh", "zh-cn", "cn":
+		return LocaleZH, nil
+	case "en", "en-us":
+		return LocaleEN, nil
+	}
+	return "", fmt.Errorf("unknown locale %q (supported: zh, en, auto)", s)
+}
+
+// DetectLocale 按 LC_ALL、LC_MESSAGES、LANG 检测语言，zh 开头为中文，其余为英文
+func DetectLocale() Locale {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			if strings.HasPrefix(strings.ToLower(value), "zh") {
+				return LocaleZH
+			}
+			return LocaleEN
+		}
+	}
+	return LocaleEN
+}
+
+// SetLocale 设置当前语言
+func SetLocale(locale Locale) {
+	current.Store(locale)
+}
+
+// Current 当前语言
+func Current() Locale {
+	return current.Load().(Locale)
+}
+
+// T 按当前语言取文案，有参数时按 fmt.Sprintf 格式化
+func T(key string, args ...any) string {
+	return Current().T(key, args...)
+}
+
+// T 按语言取文案；当前语言缺少的文案使用英文，都没有时返回 key
+func (l Locale) T(key string, args ...any) string {
+	format, ok := catalog[l][key]
+	if !ok {
+		if format, ok = catalog[LocaleEN][key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
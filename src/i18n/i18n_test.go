@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verbPattern fmt 格式动词（表头中的 % 后跟空格，不算动词）
+var verbPattern = regexp.MustCompile(`%[-+#0-9.]*[a-zA-Z]`)
+
+func TestCatalogComplete(t *testing.T) {
+	for key, en := range catalog[LocaleEN] {
+		zh, ok := catalog[LocaleZH][key]
+		if !assert.True(t, ok, "zh missing %s", key) {
+			continue
+		}
+		// 两种语言的格式动词一致，参数按同样顺序传入
+		assert.Equal(t, verbPattern.FindAllString(en, -1), verbPattern.FindAllString(zh, -1), key)
+	}
+	for key := range catalog[LocaleZH] {
+		_, ok := catalog[LocaleEN][key]
+		assert.True(t, ok, "en missing %s", key)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "zh_CN.UTF-8")
+
+	tests := map[string]Locale{"zh": LocaleZH, "zh_CN": LocaleZH, "EN": LocaleEN, "en-US": LocaleEN, "": LocaleZH, "auto": LocaleZH}
+	for input, want := range tests {
+		locale, err := ParseLocale(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, locale, input)
+	}
+
+	_, err := ParseLocale("fr")
+	assert.Error(t, err)
+}
+
+func TestDetectLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+	assert.Equal(t, LocaleEN, DetectLocale())
+
+	t.Setenv("LANG", "zh_TW.UTF-8")
+	assert.Equal(t, LocaleZH, DetectLocale())
+
+	// LC_ALL 优先于 LANG
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+	assert.Equal(t, LocaleEN, DetectLocale())
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Win Rate: 55.50%", LocaleEN.T("report.win_rate", 55.5))
+	assert.Equal(t, "胜率: 55.50%", LocaleZH.T("report.win_rate", 55.5))
+	// 没有参数时不经过 Sprintf
+	assert.Equal(t, "📅 MONTHLY RETURNS (%)", LocaleEN.T("report.monthly_returns"))
+	// 未知 key 原样返回
+	assert.Equal(t, "no.such.key", LocaleZH.T("no.such.key"))
+
+	previous := Current()
+	defer SetLocale(previous)
+	SetLocale(LocaleZH)
+	assert.Equal(t, "❌ 交易系统错误: boom", T("cli.system_error", "boom"))
+}
@@ -0,0 +1,365 @@
+package i18n
+
+// catalog 消息表：key 按模块前缀分组（report 回测报告、engine 引擎日志、cli 命令行），文案为 fmt 格式串
+var catalog = map[Locale]map[string]string{
+	LocaleEN: {
+		// 回测报告
+		"report.title":              "📊 BACKTEST RESULTS",
+		"report.strategy":           "Strategy: %s",
+		"report.default_strategy":   "Bollinger Bands Strategy",
+		"report.symbol":             "Symbol: %s",
+		"report.timeframe":          "Timeframe: %s",
+		"report.trace_id":           "Trace ID: %s",
+		"report.initial_capital":    "Initial Capital: $%.2f",
+		"report.performance":        "📈 PERFORMANCE METRICS",
+		"report.total_return":       "Total Return: %.2f%%",
+		"report.annual_return":      "Annual Return (APR): %.2f%%",
+		"report.period":             "Backtest Period: %d days",
+		"report.trading_stats":      "📊 TRADING STATISTICS",
+		"report.total_orders":       "Total Orders: %d",
+		"report.completed_pairs":    "Completed Trade Pairs: %d",
+		"report.winning_trades":     "Winning Trades: %d",
+		"report.losing_trades":      "Losing Trades: %d",
+		"report.win_rate":           "Win Rate: %.2f%%",
+		"report.total_pnl":          "Total P&L: $%.2f",
+		"report.realized_pnl":       "  Realized P&L: $%.2f",
+		"report.unrealized_pnl":     "  Unrealized P&L: $%.2f (open positions valued $%.2f at last close)",
+		"report.total_commission":   "Total Commission: $%.2f",
+		"report.carrying_cost":      "Carrying Cost: $%.2f",
+		"report.recent_trades":      "📋 RECENT TRADES (Last 10)",
+		"report.notation_fallback":  "⚠️  %v, using fixed",
+		"report.col.time":           "Time",
+		"report.col.side":           "Side",
+		"report.col.quantity":       "Quantity",
+		"report.col.price":          "Price",
+		"report.col.amount":         "Amount($)",
+		"report.col.pnl":            "P&L",
+		"report.detailed":           "🔍 DETAILED ANALYSIS",
+		"report.completed_trades":   "Completed Trades: %d",
+		"report.avg_holding":        "Average Holding Time: %v",
+		"report.max_holding":        "Max Holding Time: %v",
+		"report.min_holding":        "Min Holding Time: %v",
+		"report.avg_win":            "Average Winning P&L: $%.2f",
+		"report.avg_loss":           "Average Losing P&L: $%.2f",
+		"report.max_win":            "Max Win: $%.2f",
+		"report.max_loss":           "Max Loss: $%.2f",
+		"report.profit_factor":      "Profit Factor: %.2f",
+		"report.open_positions":     "🔓 OPEN POSITIONS: %d",
+		"report.open_header":        "Buy Time   Buy Price    Quantity     Cost         Mark Price   Unrealized        Reason",
+		"report.all_trades":         "📊 ALL COMPLETED TRADES: %d",
+		"report.all_header":         "No.  Buy Time      Buy Price    Buy Amount   Sell Time     Sell Price   Sell Amount   Profit%  Net P&L$    Holding     Sell Reason",
+		"report.reason_upper_band":  "upper band",
+		"report.profit_dist":        "📈 PROFIT DISTRIBUTION",
+		"report.profit_range":       "%s: %2d trades, total profit: $%8.2f, average: $%7.2f",
+		"report.best_worst":         "🏆 BEST & WORST TRADES",
+		"report.best_trade":         "🥇 Best Trade: %s -> %s (%.2f%%) P&L: $%.2f Duration: %v",
+		"report.worst_trade":        "🥉 Worst Trade: %s -> %s (%.2f%%) P&L: $%.2f Duration: %v",
+		"report.risk_metrics":       "📉 RISK METRICS",
+		"report.max_drawdown":       "Max Drawdown: $%.2f (%.2f%%)",
+		"report.drawdown_duration":  "Drawdown Duration: %v (peak %s, trough %s)",
+		"report.not_recovered":      "Recovery: not recovered",
+		"report.recovery":           "Recovery: %s (%v after trough)",
+		"report.longest_underwater": "Longest Underwater: %v",
+		"report.peak_value":         "Peak Portfolio Value: $%.2f",
+		"report.sharpe":             "Sharpe Ratio: %.2f",
+		"report.current_drawdown":   "Current Drawdown: $%.2f (%.2f%%)",
+
+		"report.attribution":        "🧭 RETURN ATTRIBUTION (vs Buy & Hold)",
+		"report.beta":               "Beta: %.2f",
+		"report.buy_hold":           "Buy & Hold Return: %.2f%%",
+		"report.market_component":   "Market Component: %.2f%%",
+		"report.alpha":              "Strategy Alpha: %.2f%%",
+		"report.attribution_header": "Month      Strategy%   Market%   Beta×Mkt%   Alpha%",
+		"report.regimes":            "🌦 PERFORMANCE BY MARKET REGIME (MA%d slope over %d bars, threshold ±%.1f%%)",
+		"report.regime_header":      "Regime           Bars  Share%  Segs  Strategy%   Market%   Excess% Trades   Win%         PnL",
+		"regime.trending_up":        "Trending up",
+		"regime.trending_down":      "Trending down",
+		"regime.ranging":            "Ranging",
+		"regime.warmup":             "Warmup",
+		"report.monthly_returns":    "📅 MONTHLY RETURNS (%)",
+		"report.year":               "Year",
+		"report.best_month":         "Best Month: %s (%s%%)",
+		"report.worst_month":        "Worst Month: %s (%s%%)",
+		"report.positive_months":    "Positive Months: %d/%d",
+		"report.accounting":         "💱 ACCOUNTING (%s)",
+		"report.conversion":         "Conversion: %s %s → %s",
+		"report.acc_initial":        "Initial Capital: %.2f %s",
+		"report.acc_final":          "Final Portfolio: %.2f %s",
+		"report.acc_total_return":   "Total Return: %.2f%%",
+		"report.acc_realized":       "Realized P&L: %.2f %s",
+		"report.acc_unrealized":     "Unrealized P&L: %.2f %s",
+		"report.acc_currency_pnl":   "Currency P&L: %.2f %s",
+		"report.acc_max_drawdown":   "Max Drawdown: %.2f%%",
+
+		// 引擎日志
+		"engine.start":            "🚀 Trading engine started: symbol=%s, timeframe=%s",
+		"engine.ctx_done":         "Stop signal received, exiting",
+		"engine.stopped":          "Trading stopped manually",
+		"engine.kline_failed":     "Failed to get kline",
+		"engine.feed_finished":    "Data feed finished",
+		"engine.check_failed":     "Failed to check pending orders",
+		"engine.portfolio_failed": "Failed to get portfolio",
+		"engine.ladder_failed":    "❌ Failed to place take-profit ladder",
+		"engine.trailing_failed":  "❌ Failed to place trailing stop",
+		"engine.protect_failed":   "❌ Failed to place signal protection orders",
+		"engine.oco_failed":       "❌ Failed to place OCO orders",
+		"engine.strategy_failed":  "❌ Strategy failed",
+		"engine.paused":           "⏸️ Trading paused, ignoring %d signal(s): time=%s, reason=%s",
+		"engine.signal":           "🎯 %s signal: symbol=%s, signal_reason=%q, strength=%.1f",
+		"engine.signal_failed":    "❌ Failed to process signal",
+		"engine.progress":         "📈 Backtest progress: %d klines processed, time: %s, pending: %v",
+		"engine.finished":         "Trading finished: total_klines=%d",
+		"engine.process_signal":   "📋 Processing signal: symbol=%s, type=%s, signal_reason=%q, strength=%.1f, price=%s",
+		"engine.throttled":        "⏸️ Signal throttled, ignored: type=%s, reason=%s",
+		"engine.amount_too_small": "Trade amount too small, skipping buy: amount=%s, min=%s",
+		"engine.buy_order":        "🔵 Buy order created: type=%s, order_id=%s, symbol=%s, limit_price=%s, qty=%s, current_price=%s, signal_reason=%q",
+		"engine.no_position":      "No position, skipping sell signal",
+		"engine.sell_quantity":    "Selling signal quantity: quantity=%s, position=%s",
+		"engine.sell_all":         "Invalid signal strength, selling whole position: strength=%.1f",
+		"engine.sell_partial":     "Partial sell",
+		"engine.cancel_sell":      "Cancelling existing sell order: id=%s",
+		"engine.sell_order":       "🔴 Sell order created: type=%s, order_id=%s, symbol=%s, limit_price=%s, qty=%s, current_price=%s, signal_reason=%q",
+
+		// 命令行
+		"cli.unknown_subcommand": "❌ Error: unknown subcommand %s",
+		"cli.base_required":      "❌ Error: base currency is required",
+		"cli.quote_required":     "❌ Error: quote currency is required",
+		"cli.start_required":     "❌ Error: start date is required for backtest mode",
+		"cli.backtest_only":      "❌ Error: optimize and batch do not support --live or --dry",
+		"cli.signal_only":        "❌ Error: --signal-only runs on real-time data and cannot be combined with --live, --dry, -start, --watch, optimize or batch",
+		"cli.watch_backtest":     "❌ Error: --watch only supports backtest mode",
+		"cli.watch_params":       "❌ Error: --watch requires -params FILE",
+		"cli.tui_realtime":       "❌ Error: --tui only works with real-time runs (--live, --dry without -start, or --signal-only)",
+		"cli.testnet_binance":    "❌ Error: --testnet only supports -cex binance",
+		"cli.sell_params_failed": "❌ Failed to parse sell strategy parameters: %v",
+		"cli.error":              "❌ %v",
+		"cli.system_error":       "❌ Trading system error: %v",
+
+		// 盈亏报表
+		"pnl.col.period":     "Period",
+		"pnl.col.equity":     "Equity",
+		"pnl.col.realized":   "Realized",
+		"pnl.col.unrealized": "Unrealized",
+		"pnl.col.total":      "Total P&L",
+		"pnl.col.return":     "Return%",
+		"pnl.total":          "Total P&L: %s",
+
+		// 账户概览
+		"account.title":        "🏦 Account: %s (%s UTC)",
+		"account.balances":     "💰 Balances: %d non-zero assets",
+		"account.no_pairs":     "📭 No symbols to check open orders and fills (pass -pairs BASE/QUOTE)",
+		"account.open_orders":  "📋 Open orders: %d (%s)",
+		"account.fills":        "🧾 Recent fills: %d",
+		"account.warning":      "⚠️ %s",
+		"account.col.asset":    "Asset",
+		"account.col.free":     "Free",
+		"account.col.locked":   "Locked",
+		"account.col.total":    "Total",
+		"account.col.symbol":   "Symbol",
+		"account.col.side":     "Side",
+		"account.col.type":     "Type",
+		"account.col.price":    "Price",
+		"account.col.quantity": "Quantity",
+		"account.col.filled":   "Filled",
+		"account.col.status":   "Status",
+		"account.col.order_id": "Order ID",
+		"account.col.time":     "Time (UTC)",
+		"account.col.quote":    "Quote Qty",
+		"account.col.fee":      "Fee",
+		"account.col.role":     "Role",
+
+		// 行情查看
+		"price.last":              "💲 %s on %s: %s (1m bar %s UTC)",
+		"price.book":              "   bid %s / ask %s, spread %s bps",
+		"klines.title":            "🕯️ %s %s klines from %s: %d",
+		"klines.empty":            "📭 No %s %s klines in %s",
+		"klines.gaps":             "⚠️ %d missing bars between the first and last kline",
+		"klines.col.open_time":    "Open Time (UTC)",
+		"klines.col.open":         "Open",
+		"klines.col.high":         "High",
+		"klines.col.low":          "Low",
+		"klines.col.close":        "Close",
+		"klines.col.volume":       "Volume",
+		"klines.col.quote_volume": "Quote Volume",
+		"indicators.title":        "📈 %s %s from %s to %s: %d bars, indicators: %s",
+		"indicators.empty":        "📭 No %s %s klines between %s and %s",
+		"indicators.written":      "✅ %s chart written to %s",
+	},
+	LocaleZH: {
+		"report.title":              "📊 回测结果",
+		"report.strategy":           "策略: %s",
+		"report.default_strategy":   "布林带策略",
+		"report.symbol":             "交易对: %s",
+		"report.timeframe":          "K线周期: %s",
+		"report.trace_id":           "跟踪ID: %s",
+		"report.initial_capital":    "初始资金: $%.2f",
+		"report.performance":        "📈 收益指标",
+		"report.total_return":       "总收益率: %.2f%%",
+		"report.annual_return":      "年化收益率: %.2f%%",
+		"report.period":             "回测天数: %d 天",
+		"report.trading_stats":      "📊 交易统计",
+		"report.total_orders":       "订单总数: %d",
+		"report.completed_pairs":    "已完成交易: %d",
+		"report.winning_trades":     "盈利交易: %d",
+		"report.losing_trades":      "亏损交易: %d",
+		"report.win_rate":           "胜率: %.2f%%",
+		"report.total_pnl":          "总盈亏: $%.2f",
+		"report.realized_pnl":       "  已实现盈亏: $%.2f",
+		"report.unrealized_pnl":     "  未实现盈亏: $%.2f（按最后收盘价持仓市值 $%.2f）",
+		"report.total_commission":   "手续费合计: $%.2f",
+		"report.carrying_cost":      "资金成本: $%.2f",
+		"report.recent_trades":      "📋 最近交易（最近 10 笔）",
+		"report.notation_fallback":  "⚠️  %v，使用 fixed",
+		"report.col.time":           "时间",
+		"report.col.side":           "方向",
+		"report.col.quantity":       "数量",
+		"report.col.price":          "价格",
+		"report.col.amount":         "金额($)",
+		"report.col.pnl":            "盈亏",
+		"report.detailed":           "🔍 详细分析",
+		"report.completed_trades":   "已完成交易: %d",
+		"report.avg_holding":        "平均持仓时间: %v",
+		"report.max_holding":        "最长持仓时间: %v",
+		"report.min_holding":        "最短持仓时间: %v",
+		"report.avg_win":            "平均盈利: $%.2f",
+		"report.avg_loss":           "平均亏损: $%.2f",
+		"report.max_win":            "最大盈利: $%.2f",
+		"report.max_loss":           "最大亏损: $%.2f",
+		"report.profit_factor":      "盈亏比: %.2f",
+		"report.open_positions":     "🔓 未平仓: %d",
+		"report.open_header":        "买入时间   买入价格     数量         成本         标记价格     未实现盈亏        买入原因",
+		"report.all_trades":         "📊 全部已完成交易: %d",
+		"report.all_header":         "序号 买入时间      买入价格     买入金额     卖出时间      卖出价格     卖出金额      盈利%   净盈利$     持仓时间    卖出原因",
+		"report.reason_upper_band":  "触及上轨",
+		"report.profit_dist":        "📈 盈利分布",
+		"report.profit_range":       "%s: %2d笔交易, 总盈利: $%8.2f, 平均: $%7.2f",
+		"report.best_worst":         "🏆 最佳和最差交易",
+		"report.best_trade":         "🥇 最佳交易: %s -> %s (%.2f%%) 盈亏: $%.2f 持仓: %v",
+		"report.worst_trade":        "🥉 最差交易: %s -> %s (%.2f%%) 盈亏: $%.2f 持仓: %v",
+		"report.risk_metrics":       "📉 风险指标",
+		"report.max_drawdown":       "最大回撤: $%.2f (%.2f%%)",
+		"report.drawdown_duration":  "回撤持续时间: %v（峰值 %s，谷底 %s）",
+		"report.not_recovered":      "回撤恢复: 未恢复",
+		"report.recovery":           "回撤恢复: %s（谷底后 %v）",
+		"report.longest_underwater": "最长水下时间: %v",
+		"report.peak_value":         "资产峰值: $%.2f",
+		"report.sharpe":             "夏普比率: %.2f",
+		"report.current_drawdown":   "当前回撤: $%.2f (%.2f%%)",
+
+		"report.attribution":        "🧭 收益归因（对比买入持有）",
+		"report.beta":               "Beta: %.2f",
+		"report.buy_hold":           "买入持有收益率: %.2f%%",
+		"report.market_component":   "市场贡献: %.2f%%",
+		"report.alpha":              "策略 Alpha: %.2f%%",
+		"report.attribution_header": "月份           策略%     市场%  Beta×市场%   Alpha%",
+		"report.regimes":            "🌦 按市场状态拆分（MA%d 均线 %d 根K线斜率，阈值 ±%.1f%%）",
+		"report.regime_header":      "市场状态        K线数   占比%  区间      策略%     市场%     超额%   交易  胜率%        盈亏",
+		"regime.trending_up":        "上涨趋势",
+		"regime.trending_down":      "下跌趋势",
+		"regime.ranging":            "震荡",
+		"regime.warmup":             "预热",
+		"report.monthly_returns":    "📅 月度收益 (%)",
+		"report.year":               "年份",
+		"report.best_month":         "最好月份: %s (%s%%)",
+		"report.worst_month":        "最差月份: %s (%s%%)",
+		"report.positive_months":    "盈利月份: %d/%d",
+		"report.accounting":         "💱 记账货币 (%s)",
+		"report.conversion":         "换算: %s %s → %s",
+		"report.acc_initial":        "初始资金: %.2f %s",
+		"report.acc_final":          "最终资产: %.2f %s",
+		"report.acc_total_return":   "总收益率: %.2f%%",
+		"report.acc_realized":       "已实现盈亏: %.2f %s",
+		"report.acc_unrealized":     "未实现盈亏: %.2f %s",
+		"report.acc_currency_pnl":   "汇率盈亏: %.2f %s",
+		"report.acc_max_drawdown":   "最大回撤: %.2f%%",
+
+		"engine.start":            "🚀 开始交易引擎: symbol=%s, timeframe=%s",
+		"engine.ctx_done":         "收到停止信号，退出交易",
+		"engine.stopped":          "手动停止交易",
+		"engine.kline_failed":     "获取K线数据失败",
+		"engine.feed_finished":    "数据流结束",
+		"engine.check_failed":     "检查挂单失败",
+		"engine.portfolio_failed": "获取投资组合失败",
+		"engine.ladder_failed":    "❌ 止盈阶梯挂单失败",
+		"engine.trailing_failed":  "❌ 移动止损挂单失败",
+		"engine.protect_failed":   "❌ 信号保护单挂单失败",
+		"engine.oco_failed":       "❌ OCO挂单失败",
+		"engine.strategy_failed":  "❌ 策略执行失败",
+		"engine.paused":           "⏸️ 交易暂停，忽略%d个信号: time=%s, reason=%s",
+		"engine.signal":           "🎯 %s信号: symbol=%s, signal_reason=%q, strength=%.1f",
+		"engine.signal_failed":    "❌ 处理交易信号失败",
+		"engine.progress":         "📈 回测进度: %d根K线已处理, 时间: %s, 挂单: %v",
+		"engine.finished":         "交易完成: total_klines=%d",
+		"engine.process_signal":   "📋 处理交易信号: symbol=%s, type=%s, signal_reason=%q, strength=%.1f, price=%s",
+		"engine.throttled":        "⏸️ 信号被节流，忽略: type=%s, reason=%s",
+		"engine.amount_too_small": "交易金额过小，跳过买入: amount=%s, min=%s",
+		"engine.buy_order":        "🔵 生成买入挂单: type=%s, order_id=%s, symbol=%s, limit_price=%s, qty=%s, current_price=%s, signal_reason=%q",
+		"engine.no_position":      "无持仓，跳过卖出信号",
+		"engine.sell_quantity":    "按信号指定数量卖出: quantity=%s, position=%s",
+		"engine.sell_all":         "信号强度无效，执行全仓卖出: strength=%.1f",
+		"engine.sell_partial":     "执行部分卖出",
+		"engine.cancel_sell":      "取消现有卖出挂单: id=%s",
+		"engine.sell_order":       "🔴 生成卖出挂单: type=%s, order_id=%s, symbol=%s, limit_price=%s, qty=%s, current_price=%s, signal_reason=%q",
+
+		"cli.unknown_subcommand": "❌ 错误: 未知子命令 %s",
+		"cli.base_required":      "❌ 错误: 缺少基础货币 -base",
+		"cli.quote_required":     "❌ 错误: 缺少计价货币 -quote",
+		"cli.start_required":     "❌ 错误: 回测模式需要开始日期 -start",
+		"cli.backtest_only":      "❌ 错误: optimize 和 batch 不支持 --live 或 --dry",
+		"cli.signal_only":        "❌ 错误: --signal-only 使用实时数据，不能与 --live、--dry、-start、--watch、optimize 或 batch 同时使用",
+		"cli.watch_backtest":     "❌ 错误: --watch 只支持回测模式",
+		"cli.watch_params":       "❌ 错误: --watch 需要 -params 参数文件",
+		"cli.tui_realtime":       "❌ 错误: --tui 只用于实时运行（--live、不带 -start 的 --dry 或 --signal-only）",
+		"cli.testnet_binance":    "❌ 错误: --testnet 只支持 -cex binance",
+		"cli.sell_params_failed": "❌ 解析卖出策略参数失败: %v",
+		"cli.error":              "❌ %v",
+		"cli.system_error":       "❌ 交易系统错误: %v",
+
+		"pnl.col.period":     "周期",
+		"pnl.col.equity":     "权益",
+		"pnl.col.realized":   "已实现",
+		"pnl.col.unrealized": "未实现",
+		"pnl.col.total":      "总盈亏",
+		"pnl.col.return":     "收益率%",
+		"pnl.total":          "总盈亏: %s",
+
+		"account.title":        "🏦 账户: %s（%s UTC）",
+		"account.balances":     "💰 余额: %d 个非零资产",
+		"account.no_pairs":     "📭 没有需要查询挂单和成交的交易对（使用 -pairs BASE/QUOTE 指定）",
+		"account.open_orders":  "📋 挂单: %d（%s）",
+		"account.fills":        "🧾 最近成交: %d",
+		"account.warning":      "⚠️ %s",
+		"account.col.asset":    "资产",
+		"account.col.free":     "可用",
+		"account.col.locked":   "冻结",
+		"account.col.total":    "合计",
+		"account.col.symbol":   "交易对",
+		"account.col.side":     "方向",
+		"account.col.type":     "类型",
+		"account.col.price":    "价格",
+		"account.col.quantity": "数量",
+		"account.col.filled":   "已成交",
+		"account.col.status":   "状态",
+		"account.col.order_id": "订单ID",
+		"account.col.time":     "时间（UTC）",
+		"account.col.quote":    "成交额",
+		"account.col.fee":      "手续费",
+		"account.col.role":     "角色",
+
+		"price.last":              "💲 %s（%s）: %s（1 分钟K线 %s UTC）",
+		"price.book":              "   买一 %s / 卖一 %s，价差 %s 基点",
+		"klines.title":            "🕯️ %s %s K线（%s）: %d 根",
+		"klines.empty":            "📭 %s %s 在 %s 中没有K线",
+		"klines.gaps":             "⚠️ 首尾K线之间缺失 %d 根",
+		"klines.col.open_time":    "开盘时间（UTC）",
+		"klines.col.open":         "开盘",
+		"klines.col.high":         "最高",
+		"klines.col.low":          "最低",
+		"klines.col.close":        "收盘",
+		"klines.col.volume":       "成交量",
+		"klines.col.quote_volume": "成交额",
+		"indicators.title":        "📈 %s %s 从 %s 到 %s: %d 根K线，指标: %s",
+		"indicators.empty":        "📭 %s %s 在 %s 到 %s 之间没有K线",
+		"indicators.written":      "✅ %s 图表已写入 %s",
+	},
+}
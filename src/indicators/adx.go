@@ -0,0 +1,126 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ADX 平均趋向指数和 DMI（Wilder 平滑）：+DI/-DI 表示多空方向，ADX 表示趋势强度（0-100，通常 25 以上视为有趋势）
+type ADX struct {
+	Period int // 计算周期，通常为14
+
+	prevHigh, prevLow, prevClose decimal.Decimal
+	bars                         int // 已加入的K线数
+
+	// 前 Period 个 TR/+DM/-DM 求和作为初值，之后按 S = S - S/N + 当前值 平滑
+	smoothedTR, smoothedPlusDM, smoothedMinusDM decimal.Decimal
+
+	// 前 Period 个 DX 取平均作为 ADX 初值，之后按 ADX = (ADX × (N-1) + DX) / N 平滑
+	dxCount int
+	dxSum   decimal.Decimal
+	adx     decimal.Decimal
+}
+
+// ADXResult ADX/DMI 计算结果（0-100）
+type ADXResult struct {
+	ADX     decimal.Decimal
+	PlusDI  decimal.Decimal
+	MinusDI decimal.Decimal
+}
+
+// NewADX 创建新的 ADX 指标
+func NewADX(period int) *ADX {
+	return &ADX{Period: period}
+}
+
+// Calculate 计算最后一根K线的 ADX 和 ±DI，需要至少 2×Period 根K线
+func (a *ADX) Calculate(highs, lows, closes []decimal.Decimal) (*ADXResult, error) {
+	if a.Period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, ErrMismatchedLengths
+	}
+
+	stream := NewADX(a.Period)
+	var result *ADXResult
+	ok := false
+	for i := range closes {
+		result, ok = stream.Update(highs[i], lows[i], closes[i])
+	}
+	if !ok {
+		return nil, ErrInsufficientData
+	}
+	return result, nil
+}
+
+// Update 流式加入一根K线，ADX 数据不足（少于 2×Period 根）或周期无效时 ok 为 false
+func (a *ADX) Update(high, low, close decimal.Decimal) (*ADXResult, bool) {
+	if a.Period <= 0 {
+		return nil, false
+	}
+	a.bars++
+	if a.bars == 1 {
+		a.prevHigh, a.prevLow, a.prevClose = high, low, close
+		return nil, false
+	}
+
+	trueRange := TrueRange(high, low, a.prevClose)
+	plusDM, minusDM := directionalMovement(high, low, a.prevHigh, a.prevLow)
+	a.prevHigh, a.prevLow, a.prevClose = high, low, close
+
+	n := decimal.NewFromInt(int64(a.Period))
+	if a.bars <= a.Period+1 {
+		a.smoothedTR = a.smoothedTR.Add(trueRange)
+		a.smoothedPlusDM = a.smoothedPlusDM.Add(plusDM)
+		a.smoothedMinusDM = a.smoothedMinusDM.Add(minusDM)
+		if a.bars < a.Period+1 {
+			return nil, false
+		}
+	} else {
+		a.smoothedTR = a.smoothedTR.Sub(a.smoothedTR.Div(n)).Add(trueRange)
+		a.smoothedPlusDM = a.smoothedPlusDM.Sub(a.smoothedPlusDM.Div(n)).Add(plusDM)
+		a.smoothedMinusDM = a.smoothedMinusDM.Sub(a.smoothedMinusDM.Div(n)).Add(minusDM)
+	}
+
+	hundred := decimal.NewFromInt(100)
+	result := &ADXResult{}
+	if a.smoothedTR.IsPositive() {
+		result.PlusDI = a.smoothedPlusDM.Div(a.smoothedTR).Mul(hundred)
+		result.MinusDI = a.smoothedMinusDM.Div(a.smoothedTR).Mul(hundred)
+	}
+	dx := decimal.Zero
+	if sum := result.PlusDI.Add(result.MinusDI); sum.IsPositive() {
+		dx = result.PlusDI.Sub(result.MinusDI).Abs().Div(sum).Mul(hundred)
+	}
+
+	a.dxCount++
+	switch {
+	case a.dxCount < a.Period:
+		a.dxSum = a.dxSum.Add(dx)
+		return nil, false
+	case a.dxCount == a.Period:
+		a.adx = a.dxSum.Add(dx).Div(n)
+	default:
+		a.adx = a.adx.Mul(n.Sub(decimal.NewFromInt(1))).Add(dx).Div(n)
+	}
+	result.ADX = a.adx
+	return result, true
+}
+
+// Reset 清空流式计算状态
+func (a *ADX) Reset() {
+	*a = ADX{Period: a.Period}
+}
+
+// directionalMovement 趋向变动：上升幅度大于下降幅度且为正时计入 +DM，反之计入 -DM
+func directionalMovement(high, low, prevHigh, prevLow decimal.Decimal) (plusDM, minusDM decimal.Decimal) {
+	up := high.Sub(prevHigh)
+	down := prevLow.Sub(low)
+	if up.GreaterThan(down) && up.IsPositive() {
+		plusDM = up
+	}
+	if down.GreaterThan(up) && down.IsPositive() {
+		minusDM = down
+	}
+	return plusDM, minusDM
+}
@@ -0,0 +1,66 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestADX_Calculate(t *testing.T) {
+	highs := decimals(10, 11, 12, 11.5)
+	lows := decimals(9, 10, 10.5, 10)
+	closes := decimals(9.5, 10.5, 11.5, 10.5)
+
+	// TR = 1.5, 1.5, 1.5；+DM = 1, 1, 0；-DM = 0, 0, 0.5
+	// 第3根：TR14 = 3，+DM = 2，-DM = 0 → +DI 66.67，-DI 0，DX 100
+	// 第4根：TR = 3 - 1.5 + 1.5 = 3，+DM = 2 - 1 + 0 = 1，-DM = 0.5 → +DI 33.33，-DI 16.67，DX 33.33
+	// ADX 初值 = (100 + 33.33) / 2
+	result, err := NewADX(2).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.InDelta(t, 66.6667, result.ADX.InexactFloat64(), 1e-4)
+	assert.InDelta(t, 33.3333, result.PlusDI.InexactFloat64(), 1e-4)
+	assert.InDelta(t, 16.6667, result.MinusDI.InexactFloat64(), 1e-4)
+}
+
+func TestADX_Streaming(t *testing.T) {
+	highs := decimals(10, 11, 12, 11.5, 13, 14)
+	lows := decimals(9, 10, 10.5, 10, 11.5, 12.5)
+	closes := decimals(9.5, 10.5, 11.5, 10.5, 12.5, 13.5)
+
+	adx := NewADX(2)
+	var results []*ADXResult
+	for i := range closes {
+		result, ok := adx.Update(highs[i], lows[i], closes[i])
+		assert.Equal(t, i >= 3, ok, "bar %d", i)
+		if ok {
+			results = append(results, result)
+		}
+	}
+
+	// 之后按 ADX = (ADX × (N-1) + DX) / N 平滑
+	require.Len(t, results, 3)
+	assert.InDelta(t, 72.2222, results[1].ADX.InexactFloat64(), 1e-4)
+	assert.InDelta(t, 80.2288, results[2].ADX.InexactFloat64(), 1e-4)
+	assert.InDelta(t, 57.1429, results[2].PlusDI.InexactFloat64(), 1e-4)
+
+	// 批量计算与流式计算一致
+	batch, err := NewADX(2).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.True(t, batch.ADX.Equal(results[2].ADX))
+
+	adx.Reset()
+	_, ok := adx.Update(highs[0], lows[0], closes[0])
+	assert.False(t, ok)
+}
+
+func TestADX_Errors(t *testing.T) {
+	_, err := NewADX(0).Calculate(decimals(1), decimals(1), decimals(1))
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	_, err = NewADX(2).Calculate(decimals(1, 2), decimals(1), decimals(1, 2))
+	assert.ErrorIs(t, err, ErrMismatchedLengths)
+
+	_, err = NewADX(2).Calculate(decimals(1, 2, 3), decimals(1, 2, 3), decimals(1, 2, 3))
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
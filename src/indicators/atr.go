@@ -0,0 +1,59 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ATR 平均真实波幅指标（Wilder 平滑）
+type ATR struct {
+	Period int // 计算周期，通常为14
+}
+
+// NewATR 创建新的 ATR 指标
+func NewATR(period int) *ATR {
+	return &ATR{Period: period}
+}
+
+// TrueRange 真实波幅：max(最高-最低, |最高-前收|, |最低-前收|)
+func TrueRange(high, low, prevClose decimal.Decimal) decimal.Decimal {
+	return decimal.Max(high.Sub(low), high.Sub(prevClose).Abs(), low.Sub(prevClose).Abs())
+}
+
+// Calculate 计算最后一根K线的 ATR，需要至少 Period+1 根K线（第一根只提供前收盘价）
+func (a *ATR) Calculate(highs, lows, closes []decimal.Decimal) (decimal.Decimal, error) {
+	values, err := a.Series(highs, lows, closes)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return values[len(values)-1], nil
+}
+
+// Series 计算每根K线的 ATR，前 Period 根数据不足为零，需要至少 Period+1 根K线
+// 前 Period 个真实波幅取简单平均作为初值，之后按 ATR = (ATR × (N-1) + TR) / N 平滑
+func (a *ATR) Series(highs, lows, closes []decimal.Decimal) ([]decimal.Decimal, error) {
+	if a.Period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, ErrMismatchedLengths
+	}
+	if len(closes) < a.Period+1 {
+		return nil, ErrInsufficientData
+	}
+
+	values := make([]decimal.Decimal, len(closes))
+	n := decimal.NewFromInt(int64(a.Period))
+	atr := decimal.Zero
+	for i := 1; i <= a.Period; i++ {
+		atr = atr.Add(TrueRange(highs[i], lows[i], closes[i-1]))
+	}
+	atr = atr.Div(n)
+	values[a.Period] = atr
+
+	previousWeight := n.Sub(decimal.NewFromInt(1))
+	for i := a.Period + 1; i < len(closes); i++ {
+		atr = atr.Mul(previousWeight).Add(TrueRange(highs[i], lows[i], closes[i-1])).Div(n)
+		values[i] = atr
+	}
+	return values, nil
+}
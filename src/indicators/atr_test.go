@@ -0,0 +1,72 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decimals(values ...float64) []decimal.Decimal {
+	result := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		result[i] = decimal.NewFromFloat(v)
+	}
+	return result
+}
+
+func TestTrueRange(t *testing.T) {
+	// 区间内：最高-最低
+	assert.True(t, TrueRange(decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(11)).Equal(decimal.NewFromInt(2)))
+	// 跳空高开：最高-前收
+	assert.True(t, TrueRange(decimal.NewFromInt(15), decimal.NewFromInt(14), decimal.NewFromInt(11)).Equal(decimal.NewFromInt(4)))
+	// 跳空低开：前收-最低
+	assert.True(t, TrueRange(decimal.NewFromInt(9), decimal.NewFromInt(8), decimal.NewFromInt(11)).Equal(decimal.NewFromInt(3)))
+}
+
+func TestATR_Calculate(t *testing.T) {
+	highs := decimals(11, 12, 15, 14.5)
+	lows := decimals(9, 10, 14, 13.5)
+	closes := decimals(10, 11, 14.5, 14)
+
+	// TR = 2, 4, 1；初始 ATR = (2 + 4) / 2 = 3，Wilder 平滑 (3 × 1 + 1) / 2 = 2
+	atr, err := NewATR(2).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, atr.InexactFloat64(), 1e-9)
+
+	// 周期3：(2 + 4 + 1) / 3
+	atr, err = NewATR(3).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.InDelta(t, 7.0/3, atr.InexactFloat64(), 1e-9)
+}
+
+func TestATR_Series(t *testing.T) {
+	highs := decimals(11, 12, 15, 14.5)
+	lows := decimals(9, 10, 14, 13.5)
+	closes := decimals(10, 11, 14.5, 14)
+
+	// 前 Period 根为零，之后与 Calculate 逐根一致
+	values, err := NewATR(2).Series(highs, lows, closes)
+	require.NoError(t, err)
+	require.Len(t, values, 4)
+	assert.True(t, values[0].IsZero())
+	assert.True(t, values[1].IsZero())
+	assert.InDelta(t, 3, values[2].InexactFloat64(), 1e-9)
+	assert.InDelta(t, 2, values[3].InexactFloat64(), 1e-9)
+}
+
+func TestATR_Errors(t *testing.T) {
+	highs := decimals(11, 12, 15)
+	lows := decimals(9, 10, 14)
+	closes := decimals(10, 11, 14.5)
+
+	_, err := NewATR(0).Calculate(highs, lows, closes)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	_, err = NewATR(3).Calculate(highs, lows, closes)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	_, err = NewATR(2).Calculate(highs, lows[:2], closes)
+	assert.ErrorIs(t, err, ErrMismatchedLengths)
+}
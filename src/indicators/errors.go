@@ -14,4 +14,7 @@ var (
 	
 	// ErrEmptyPrices 空价格数组错误
 	ErrEmptyPrices = errors.New("empty prices array")
+	
+	// ErrMismatchedLengths 价格序列长度不一致错误
+	ErrMismatchedLengths = errors.New("high, low and close series must have the same length")
 )
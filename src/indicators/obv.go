@@ -0,0 +1,59 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// OBV 能量潮：收盘价上涨时累加成交量，下跌时减去成交量，持平不变（第一根K线为 0）
+type OBV struct {
+	value     decimal.Decimal
+	prevClose decimal.Decimal
+	started   bool
+}
+
+// NewOBV 创建新的 OBV 指标
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+// Update 加入一根K线，返回更新后的 OBV
+func (o *OBV) Update(close, volume decimal.Decimal) decimal.Decimal {
+	if o.started {
+		switch close.Cmp(o.prevClose) {
+		case 1:
+			o.value = o.value.Add(volume)
+		case -1:
+			o.value = o.value.Sub(volume)
+		}
+	}
+	o.started = true
+	o.prevClose = close
+	return o.value
+}
+
+// Value 当前 OBV
+func (o *OBV) Value() decimal.Decimal {
+	return o.value
+}
+
+// Reset 清空累计数据
+func (o *OBV) Reset() {
+	*o = OBV{}
+}
+
+// CalculateOBV 计算每根K线的 OBV 序列
+func CalculateOBV(closes, volumes []decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(closes) == 0 {
+		return nil, ErrEmptyPrices
+	}
+	if len(closes) != len(volumes) {
+		return nil, ErrMismatchedLengths
+	}
+
+	obv := NewOBV()
+	values := make([]decimal.Decimal, len(closes))
+	for i := range closes {
+		values[i] = obv.Update(closes[i], volumes[i])
+	}
+	return values, nil
+}
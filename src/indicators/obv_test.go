@@ -0,0 +1,42 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateOBV(t *testing.T) {
+	closes := decimals(10, 10.15, 10.17, 10.13, 10.11, 10.15, 10.20, 10.20, 10.22, 10.21)
+	volumes := decimals(25200, 30000, 25600, 32000, 23000, 40000, 36000, 20500, 23000, 27500)
+
+	values, err := CalculateOBV(closes, volumes)
+	require.NoError(t, err)
+
+	expected := []float64{0, 30000, 55600, 23600, 600, 40600, 76600, 76600, 99600, 72100}
+	require.Len(t, values, len(expected))
+	for i, want := range expected {
+		assert.InDelta(t, want, values[i].InexactFloat64(), 1e-9, "bar %d", i)
+	}
+}
+
+func TestOBV_Streaming(t *testing.T) {
+	obv := NewOBV()
+	obv.Update(decimals(10)[0], decimals(100)[0])
+	obv.Update(decimals(11)[0], decimals(50)[0])
+	assert.InDelta(t, 50, obv.Value().InexactFloat64(), 1e-9)
+
+	obv.Reset()
+	assert.True(t, obv.Value().IsZero())
+	// 重置后第一根K线重新作为基准
+	assert.True(t, obv.Update(decimals(9)[0], decimals(100)[0]).IsZero())
+}
+
+func TestCalculateOBV_Errors(t *testing.T) {
+	_, err := CalculateOBV(nil, nil)
+	assert.ErrorIs(t, err, ErrEmptyPrices)
+
+	_, err = CalculateOBV(decimals(1, 2), decimals(1))
+	assert.ErrorIs(t, err, ErrMismatchedLengths)
+}
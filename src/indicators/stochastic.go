@@ -0,0 +1,105 @@
+package indicators
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Stochastic 随机指标：%K = (收盘 - N 日最低) / (N 日最高 - N 日最低) × 100，
+// 按 SmoothK 做简单平均得到慢速 %K（SmoothK 为 1 时为快速 %K），%D 为 %K 的 DPeriod 简单平均
+type Stochastic struct {
+	KPeriod int // %K 的最高最低价窗口，通常为14
+	SmoothK int // %K 平滑周期，通常为3（1 表示不平滑）
+	DPeriod int // %D 周期，通常为3
+
+	highs, lows []decimal.Decimal // 最近 KPeriod 根K线
+	rawK        []decimal.Decimal // 最近 SmoothK 个未平滑 %K
+	k           []decimal.Decimal // 最近 DPeriod 个 %K
+}
+
+// StochasticResult 随机指标计算结果（0-100）
+type StochasticResult struct {
+	K decimal.Decimal
+	D decimal.Decimal
+}
+
+// NewStochastic 创建新的随机指标
+func NewStochastic(kPeriod, smoothK, dPeriod int) *Stochastic {
+	return &Stochastic{KPeriod: kPeriod, SmoothK: smoothK, DPeriod: dPeriod}
+}
+
+// validate 检查周期参数
+func (s *Stochastic) validate() error {
+	if s.KPeriod <= 0 || s.SmoothK <= 0 || s.DPeriod <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Calculate 计算最后一根K线的 %K/%D，需要至少 KPeriod+SmoothK+DPeriod-2 根K线
+func (s *Stochastic) Calculate(highs, lows, closes []decimal.Decimal) (*StochasticResult, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if len(highs) != len(lows) || len(highs) != len(closes) {
+		return nil, ErrMismatchedLengths
+	}
+
+	stream := NewStochastic(s.KPeriod, s.SmoothK, s.DPeriod)
+	var result *StochasticResult
+	ok := false
+	for i := range closes {
+		result, ok = stream.Update(highs[i], lows[i], closes[i])
+	}
+	if !ok {
+		return nil, ErrInsufficientData
+	}
+	return result, nil
+}
+
+// Update 流式加入一根K线，数据不足或周期无效时 ok 为 false
+func (s *Stochastic) Update(high, low, close decimal.Decimal) (*StochasticResult, bool) {
+	if s.validate() != nil {
+		return nil, false
+	}
+
+	s.highs = appendWindow(s.highs, high, s.KPeriod)
+	s.lows = appendWindow(s.lows, low, s.KPeriod)
+	if len(s.highs) < s.KPeriod {
+		return nil, false
+	}
+
+	highest, lowest := decimal.Max(s.highs[0], s.highs[1:]...), decimal.Min(s.lows[0], s.lows[1:]...)
+	rawK := decimal.NewFromInt(50) // 窗口内没有波动时取中间值
+	if span := highest.Sub(lowest); span.IsPositive() {
+		rawK = close.Sub(lowest).Div(span).Mul(decimal.NewFromInt(100))
+	}
+
+	s.rawK = appendWindow(s.rawK, rawK, s.SmoothK)
+	if len(s.rawK) < s.SmoothK {
+		return nil, false
+	}
+	s.k = appendWindow(s.k, average(s.rawK), s.DPeriod)
+	if len(s.k) < s.DPeriod {
+		return nil, false
+	}
+	return &StochasticResult{K: s.k[len(s.k)-1], D: average(s.k)}, true
+}
+
+// Reset 清空流式计算状态
+func (s *Stochastic) Reset() {
+	s.highs, s.lows, s.rawK, s.k = nil, nil, nil, nil
+}
+
+// appendWindow 追加值并只保留最近 size 个
+func appendWindow(values []decimal.Decimal, value decimal.Decimal, size int) []decimal.Decimal {
+	values = append(values, value)
+	if len(values) > size {
+		values = values[len(values)-size:]
+	}
+	return values
+}
+
+// average 简单平均
+func average(values []decimal.Decimal) decimal.Decimal {
+	return decimal.Sum(values[0], values[1:]...).Div(decimal.NewFromInt(int64(len(values))))
+}
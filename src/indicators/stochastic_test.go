@@ -0,0 +1,58 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStochastic_Calculate(t *testing.T) {
+	highs := decimals(10, 12, 11, 13)
+	lows := decimals(8, 9, 9, 10)
+	closes := decimals(9, 11, 10, 12)
+
+	// 快速 %K：第3根 (10-8)/(12-8) = 50，第4根 (12-9)/(13-9) = 75；%D = (50 + 75) / 2
+	result, err := NewStochastic(3, 1, 2).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.InDelta(t, 75, result.K.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 62.5, result.D.InexactFloat64(), 1e-9)
+
+	// 慢速 %K：两个快速 %K 的平均
+	result, err = NewStochastic(3, 2, 1).Calculate(highs, lows, closes)
+	require.NoError(t, err)
+	assert.InDelta(t, 62.5, result.K.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 62.5, result.D.InexactFloat64(), 1e-9)
+}
+
+func TestStochastic_Streaming(t *testing.T) {
+	stochastic := NewStochastic(2, 1, 1)
+	_, ok := stochastic.Update(decimal.NewFromInt(10), decimal.NewFromInt(10), decimal.NewFromInt(10))
+	assert.False(t, ok)
+
+	// 窗口内没有波动时 %K 取 50
+	result, ok := stochastic.Update(decimal.NewFromInt(10), decimal.NewFromInt(10), decimal.NewFromInt(10))
+	require.True(t, ok)
+	assert.InDelta(t, 50, result.K.InexactFloat64(), 1e-9)
+
+	result, ok = stochastic.Update(decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(12))
+	require.True(t, ok)
+	assert.InDelta(t, 100, result.K.InexactFloat64(), 1e-9)
+
+	stochastic.Reset()
+	_, ok = stochastic.Update(decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(12))
+	assert.False(t, ok)
+}
+
+func TestStochastic_Errors(t *testing.T) {
+	_, err := NewStochastic(0, 3, 3).Calculate(decimals(1), decimals(1), decimals(1))
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	_, err = NewStochastic(3, 1, 1).Calculate(decimals(1, 2), decimals(1), decimals(1, 2))
+	assert.ErrorIs(t, err, ErrMismatchedLengths)
+
+	// 需要 KPeriod+SmoothK+DPeriod-2 = 5 根
+	_, err = NewStochastic(3, 2, 2).Calculate(decimals(1, 2, 3, 4), decimals(1, 2, 3, 4), decimals(1, 2, 3, 4))
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
@@ -0,0 +1,92 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultVWAPSession 会话 VWAP 的默认会话长度（按 UTC 自然日重置）
+const defaultVWAPSession = 24 * time.Hour
+
+// VWAP 成交量加权平均价：Σ(典型价 × 成交量) / Σ成交量，典型价 = (最高 + 最低 + 收盘) / 3
+// Period > 0 时为最近 Period 根K线的滚动 VWAP，否则为会话 VWAP（每个 Session 开始时重置，按 UTC 对齐）
+type VWAP struct {
+	Period  int           // 滚动窗口K线数，0 表示按会话累计
+	Session time.Duration // 会话长度，默认 24h
+
+	sessionStart time.Time
+	priceVolume  decimal.Decimal // 窗口或会话内 Σ(典型价 × 成交量)
+	volume       decimal.Decimal // 窗口或会话内 Σ成交量
+	window       []vwapBar       // 滚动窗口内的K线
+}
+
+// vwapBar 滚动窗口中一根K线的贡献
+type vwapBar struct {
+	priceVolume decimal.Decimal
+	volume      decimal.Decimal
+}
+
+// NewSessionVWAP 创建会话 VWAP，session 为 0 时按 UTC 自然日重置
+func NewSessionVWAP(session time.Duration) *VWAP {
+	if session <= 0 {
+		session = defaultVWAPSession
+	}
+	return &VWAP{Session: session}
+}
+
+// NewRollingVWAP 创建最近 period 根K线的滚动 VWAP
+func NewRollingVWAP(period int) *VWAP {
+	return &VWAP{Period: period}
+}
+
+// TypicalPrice 典型价：(最高 + 最低 + 收盘) / 3
+func TypicalPrice(high, low, close decimal.Decimal) decimal.Decimal {
+	return high.Add(low).Add(close).Div(decimal.NewFromInt(3))
+}
+
+// Update 加入一根K线（openTime 用于判断会话），返回更新后的 VWAP
+func (v *VWAP) Update(openTime time.Time, high, low, close, volume decimal.Decimal) (decimal.Decimal, bool) {
+	bar := vwapBar{priceVolume: TypicalPrice(high, low, close).Mul(volume), volume: volume}
+
+	if v.Period > 0 {
+		v.window = append(v.window, bar)
+		if len(v.window) > v.Period {
+			dropped := v.window[0]
+			v.window = v.window[1:]
+			v.priceVolume = v.priceVolume.Sub(dropped.priceVolume)
+			v.volume = v.volume.Sub(dropped.volume)
+		}
+	} else {
+		session := v.Session
+		if session <= 0 {
+			session = defaultVWAPSession
+		}
+		if start := openTime.UTC().Truncate(session); !start.Equal(v.sessionStart) {
+			v.sessionStart = start
+			v.priceVolume, v.volume = decimal.Zero, decimal.Zero
+		}
+	}
+
+	v.priceVolume = v.priceVolume.Add(bar.priceVolume)
+	v.volume = v.volume.Add(bar.volume)
+	return v.Value()
+}
+
+// Value 当前 VWAP，滚动窗口未满或成交量为零时 ok 为 false
+func (v *VWAP) Value() (decimal.Decimal, bool) {
+	if v.Period > 0 && len(v.window) < v.Period {
+		return decimal.Zero, false
+	}
+	if !v.volume.IsPositive() {
+		return decimal.Zero, false
+	}
+	return v.priceVolume.Div(v.volume), true
+}
+
+// Reset 清空累计数据
+func (v *VWAP) Reset() {
+	v.sessionStart = time.Time{}
+	v.priceVolume, v.volume = decimal.Zero, decimal.Zero
+	v.window = nil
+}
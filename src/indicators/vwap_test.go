@@ -0,0 +1,55 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypicalPrice(t *testing.T) {
+	assert.True(t, TypicalPrice(decimal.NewFromInt(12), decimal.NewFromInt(9), decimal.NewFromInt(12)).Equal(decimal.NewFromInt(11)))
+}
+
+func TestVWAP_Session(t *testing.T) {
+	vwap := NewSessionVWAP(0)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := vwap.Value()
+	assert.False(t, ok)
+
+	// 典型价 11、12、13，成交量 100、200、100：(1100 + 2400 + 1300) / 400 = 12
+	vwap.Update(day, decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(11), decimal.NewFromInt(100))
+	vwap.Update(day.Add(time.Hour), decimal.NewFromInt(13), decimal.NewFromInt(11), decimal.NewFromInt(12), decimal.NewFromInt(200))
+	value, ok := vwap.Update(day.Add(2*time.Hour), decimal.NewFromInt(14), decimal.NewFromInt(12), decimal.NewFromInt(13), decimal.NewFromInt(100))
+	assert.True(t, ok)
+	assert.True(t, value.Equal(decimal.NewFromInt(12)), value.String())
+
+	// 新的 UTC 自然日重新累计
+	value, ok = vwap.Update(day.Add(24*time.Hour), decimal.NewFromInt(21), decimal.NewFromInt(19), decimal.NewFromInt(20), decimal.NewFromInt(50))
+	assert.True(t, ok)
+	assert.True(t, value.Equal(decimal.NewFromInt(20)), value.String())
+
+	// 成交量为零时没有 VWAP
+	vwap.Reset()
+	_, ok = vwap.Update(day, decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(11), decimal.Zero)
+	assert.False(t, ok)
+}
+
+func TestVWAP_Rolling(t *testing.T) {
+	vwap := NewRollingVWAP(2)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := vwap.Update(start, decimal.NewFromInt(12), decimal.NewFromInt(10), decimal.NewFromInt(11), decimal.NewFromInt(100))
+	assert.False(t, ok, "window not full")
+
+	value, ok := vwap.Update(start.Add(time.Hour), decimal.NewFromInt(13), decimal.NewFromInt(11), decimal.NewFromInt(12), decimal.NewFromInt(200))
+	assert.True(t, ok)
+	assert.InDelta(t, 3500.0/300, value.InexactFloat64(), 1e-9)
+
+	// 第一根移出窗口：(2400 + 1300) / 300
+	value, ok = vwap.Update(start.Add(2*time.Hour), decimal.NewFromInt(14), decimal.NewFromInt(12), decimal.NewFromInt(13), decimal.NewFromInt(100))
+	assert.True(t, ok)
+	assert.InDelta(t, 3700.0/300, value.InexactFloat64(), 1e-9)
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// ConfigChangeRecord 配置变更审计记录（实盘热更新配置）
+type ConfigChangeRecord struct {
+	Time    time.Time   `json:"time"`
+	Action  string      `json:"action"` // 固定为 config_change
+	Section string      `json:"section"`
+	Old     interface{} `json:"old"`
+	New     interface{} `json:"new"`
+	Error   string      `json:"error,omitempty"` // 校验或应用失败时的原因（配置未生效）
+	TraceID string      `json:"trace_id,omitempty"`
+}
+
+// AuditActionConfigChange 配置变更审计动作
+const AuditActionConfigChange = "config_change"
+
+// AuditLog 审计日志：每次下单/撤单请求及响应、每次配置变更追加写入一行 JSON，只追加不改写
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenAuditLog 以追加方式打开审计日志文件（不存在时创建，包括所在目录）
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	return &AuditLog{file: file}, nil
+}
+
+// Write 写入一条审计记录并落盘
+func (a *AuditLog) Write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return a.file.Sync()
+}
+
+// RecordOrder 实现 cex.OrderAuditor，写入失败只记录错误
+func (a *AuditLog) RecordOrder(ctx context.Context, record *cex.OrderAuditRecord) {
+	if record.TraceID == "" {
+		record.TraceID = TraceIDFromContext(ctx)
+	}
+	if err := a.Write(record); err != nil {
+		_, logger := log.WithCtx(ctx)
+		logger.Error(fmt.Sprintf("⚠️ 订单审计日志写入失败: action=%s, symbol=%s, error=%v", record.Action, record.Symbol, err))
+	}
+}
+
+// RecordConfigChange 记录配置变更，写入失败只记录错误
+func (a *AuditLog) RecordConfigChange(ctx context.Context, record *ConfigChangeRecord) {
+	record.Action = AuditActionConfigChange
+	if record.TraceID == "" {
+		record.TraceID = TraceIDFromContext(ctx)
+	}
+	if err := a.Write(record); err != nil {
+		_, logger := log.WithCtx(ctx)
+		logger.Error(fmt.Sprintf("⚠️ 配置变更审计日志写入失败: section=%s, error=%v", record.Section, err))
+	}
+}
+
+// Close 关闭审计日志文件
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"fmt"
+
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+	"github.com/xpwu/go-log/log"
+)
+
+// 日志输出格式
+const (
+	FormatText = "text" // go-log 默认文本格式
+	FormatJSON = "json" // 每行一个 JSON 对象，便于日志系统采集
+)
+
+// Config 日志配置
+type Config struct {
+	Format    string `json:"format"`     // 日志格式：text 或 json
+	AuditFile string `json:"audit_file"` // 实盘订单审计日志文件（追加写入，每行一条 JSON），为空时不记录
+}
+
+// ConfigValue 日志配置实例
+var ConfigValue = Config{
+	Format:    FormatText,
+	AuditFile: "",
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("LOGGING", &ConfigValue)
+}
+
+// Apply 按配置设置日志输出格式（配置加载后调用）
+func (c Config) Apply() error {
+	switch c.Format {
+	case "", FormatText:
+		return nil
+	case FormatJSON:
+		log.SetWriter(NewJSONWriter(log.Writer()))
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q, must be %q or %q", c.Format, FormatText, FormatJSON)
+	}
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// go-log 的行格式：2006/01/02 15:04:05.000000 file:line [LEVEL] prefix message
+var logLinePattern = regexp.MustCompile(`(?s)^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{6}) (\S+) \[([A-Z]+)\] (.*)$`)
+
+// 消息中的 key=value 字段（如 symbol=BTC/USDT, order_id=123, signal_reason="跌破下轨"）
+var fieldPattern = regexp.MustCompile(`(?:^|[\s,(])([a-z][a-z0-9_]*)=("(?:[^"\\]|\\.)*"|[^\s,)]+)`)
+
+const logTimeLayout = "2006/01/02 15:04:05.000000"
+
+// JSONEntry JSON 格式的一条日志
+type JSONEntry struct {
+	Time    string            `json:"time"`
+	Level   string            `json:"level,omitempty"`
+	Caller  string            `json:"caller,omitempty"`
+	Message string            `json:"msg"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// JSONWriter 把 go-log 的文本日志行转换为 JSON 行，消息中的 key=value 提取为字段
+type JSONWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewJSONWriter 创建 JSON 日志输出
+func NewJSONWriter(out io.Writer) *JSONWriter {
+	return &JSONWriter{out: out}
+}
+
+// Write 每次写入一行 go-log 日志
+func (w *JSONWriter) Write(p []byte) (int, error) {
+	data, err := json.Marshal(ParseLogLine(strings.TrimSuffix(string(p), "\n")))
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.out.Write(append(data, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ParseLogLine 解析 go-log 日志行，无法识别的行整体作为消息
+func ParseLogLine(line string) *JSONEntry {
+	match := logLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return &JSONEntry{Time: time.Now().Format(time.RFC3339Nano), Message: line}
+	}
+
+	entry := &JSONEntry{
+		Time:    match[1],
+		Level:   match[3],
+		Caller:  match[2],
+		Message: match[4],
+		Fields:  ParseFields(match[4]),
+	}
+	if t, err := time.ParseInLocation(logTimeLayout, match[1], time.Local); err == nil {
+		entry.Time = t.Format(time.RFC3339Nano)
+	}
+	return entry
+}
+
+// ParseFields 提取消息中的 key=value 字段（同名字段保留最后一个）
+func ParseFields(message string) map[string]string {
+	matches := fieldPattern.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(matches))
+	for _, match := range matches {
+		value := match[2]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		fields[match[1]] = value
+	}
+	return fields
+}
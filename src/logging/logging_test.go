@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpwu/go-log/log"
+)
+
+func TestParseLogLine_ExtractsLevelCallerAndFields(t *testing.T) {
+	line := `2026/10/16 08:30:00.123456 ...src/engine/trading_engine.go:371 [INFO] TradingEngine 🔵 生成买入限价单: order_id=buy_1, symbol=BTC/USDT, limit_price=100.5, signal_reason="跌破下轨, 超卖"`
+
+	entry := ParseLogLine(line)
+
+	assert.Equal(t, "INFO", entry.Level)
+	assert.Equal(t, "...src/engine/trading_engine.go:371", entry.Caller)
+	assert.Contains(t, entry.Message, "生成买入限价单")
+	assert.Equal(t, map[string]string{
+		"order_id":      "buy_1",
+		"symbol":        "BTC/USDT",
+		"limit_price":   "100.5",
+		"signal_reason": "跌破下轨, 超卖",
+	}, entry.Fields)
+
+	parsed, err := time.Parse(time.RFC3339Nano, entry.Time)
+	require.NoError(t, err)
+	assert.Equal(t, 123456000, parsed.Nanosecond())
+}
+
+func TestParseLogLine_UnknownFormatKeepsWholeLine(t *testing.T) {
+	entry := ParseLogLine("plain output")
+
+	assert.Equal(t, "plain output", entry.Message)
+	assert.Empty(t, entry.Level)
+	assert.Nil(t, entry.Fields)
+}
+
+func TestJSONWriter_WritesOneJSONObjectPerLine(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewJSONWriter(&out)
+
+	line := "2026/10/16 08:30:00.000000 main.go:10 [ERROR] 撤单失败: order_id=42\n"
+	n, err := writer.Write([]byte(line))
+	require.NoError(t, err)
+	assert.Equal(t, len(line), n)
+
+	var entry JSONEntry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "ERROR", entry.Level)
+	assert.Equal(t, "撤单失败: order_id=42", entry.Message)
+	assert.Equal(t, "42", entry.Fields["order_id"])
+	assert.Equal(t, byte('\n'), out.Bytes()[out.Len()-1])
+}
+
+func TestConfig_ApplyRejectsUnknownFormat(t *testing.T) {
+	assert.NoError(t, Config{Format: FormatText}.Apply())
+	assert.Error(t, Config{Format: "xml"}.Apply())
+}
+
+func TestAuditLog_AppendsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "orders.jsonl")
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	request := cex.BuyOrderRequest{TradingPair: pair, Type: cex.OrderTypeLimit, Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}
+
+	auditLog, err := OpenAuditLog(path)
+	require.NoError(t, err)
+	_, err = cex.AuditOrder(context.Background(), auditLog, "binance", cex.AuditActionBuy, pair, request, func() (*cex.OrderResult, error) {
+		return &cex.OrderResult{OrderID: "1001", Status: "NEW"}, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, auditLog.Close())
+
+	// 重新打开后追加，不覆盖已有记录
+	auditLog, err = OpenAuditLog(path)
+	require.NoError(t, err)
+	cancelErr := errors.New("unknown order")
+	err = cex.AuditCancel(context.Background(), auditLog, "binance", cex.AuditActionCancel, pair, map[string]interface{}{"order_id": "1001"}, func() error {
+		return cancelErr
+	})
+	assert.ErrorIs(t, err, cancelErr)
+	require.NoError(t, auditLog.Close())
+
+	records := readAuditRecords(t, path)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "buy", records[0]["action"])
+	assert.Equal(t, "binance", records[0]["exchange"])
+	assert.Equal(t, "BTC/USDT", records[0]["symbol"])
+	assert.Equal(t, "1001", records[0]["response"].(map[string]interface{})["order_id"])
+	assert.Equal(t, "100", records[0]["request"].(map[string]interface{})["price"])
+	assert.NotContains(t, records[0], "error")
+
+	assert.Equal(t, "cancel_order", records[1]["action"])
+	assert.Equal(t, "unknown order", records[1]["error"])
+	assert.NotContains(t, records[1], "response")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestTraceID_PropagatesToLogsAndAuditRecords(t *testing.T) {
+	traceID := NewTraceID(TraceKindLive)
+	assert.Regexp(t, regexp.MustCompile(`^live-\d{8}T\d{6}Z-[0-9a-f]{6}$`), traceID)
+	assert.NotEqual(t, traceID, NewTraceID(TraceKindLive))
+
+	assert.Empty(t, TraceIDFromContext(context.Background()))
+	ctx := WithTraceID(context.Background(), traceID)
+	childCtx, logger := log.WithCtx(ctx)
+	assert.Equal(t, traceID, TraceIDFromContext(childCtx))
+
+	// 派生的日志带 trace=<id> 前缀，JSON 格式下提取为字段
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetWriter(&buf)
+	logger.Info("订单已提交: order_id=1001")
+	log.SetWriter(previous)
+	entry := ParseLogLine(buf.String())
+	assert.Equal(t, traceID, entry.Fields["trace"])
+	assert.Equal(t, "1001", entry.Fields["order_id"])
+
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+	auditLog, err := OpenAuditLog(path)
+	require.NoError(t, err)
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	_, err = cex.AuditOrder(childCtx, auditLog, "binance", cex.AuditActionBuy, pair, map[string]string{"quantity": "1"}, func() (*cex.OrderResult, error) {
+		return &cex.OrderResult{OrderID: "1001"}, nil
+	})
+	require.NoError(t, err)
+	auditLog.RecordConfigChange(childCtx, &ConfigChangeRecord{Section: "Risk"})
+	require.NoError(t, auditLog.Close())
+
+	records := readAuditRecords(t, path)
+	require.Len(t, records, 2)
+	assert.Equal(t, traceID, records[0]["trace_id"])
+	assert.Equal(t, traceID, records[1]["trace_id"])
+}
+
+// readAuditRecords 按行读取审计日志
+func readAuditRecords(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// 运行类型，作为跟踪ID的前缀
+const (
+	TraceKindBacktest = "backtest"
+	TraceKindLive     = "live"
+	TraceKindDryRun   = "dry"
+)
+
+type traceIDKey struct{}
+
+// NewTraceID 生成一次运行（回测或实盘会话）的跟踪ID：<类型>-<UTC 启动时间>-<随机后缀>，如 live-20240301T080000Z-3f9a1c
+func NewTraceID(kind string) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%s-%s", kind, time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix))
+}
+
+// WithTraceID 在 ctx 中记录跟踪ID，并给由该 ctx 派生的日志加上 trace=<id> 前缀（JSON 日志格式下提取为 fields.trace）
+func WithTraceID(parent context.Context, traceID string) context.Context {
+	ctx, logger := log.WithCtx(context.WithValue(parent, traceIDKey{}, traceID))
+	logger.PushPrefix(fmt.Sprintf("trace=%s", traceID))
+	return ctx
+}
+
+// TraceIDFromContext ctx 中的跟踪ID，没有时为空
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
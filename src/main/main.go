@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	tradingcmd "tradingbot/src/cmd"
 	// 导入各模块配置，让 go-config 自动加载
 	_ "tradingbot/src/cex/binance" // 导入 Binance 配置和工厂注册
+	_ "tradingbot/src/cex/bybit"   // 导入 Bybit 配置和工厂注册
 	_ "tradingbot/src/database"
+	"tradingbot/src/envconfig"
+	"tradingbot/src/i18n"
+	"tradingbot/src/logging"
 	_ "tradingbot/src/trading"
 
 	"github.com/xpwu/go-cmd/arg"
@@ -22,12 +27,36 @@ import (
 func main() {
 	// 注册默认命令
 	cmd.RegisterCmd(cmd.DefaultCmdName, "start trading bot", func(args *arg.Arg) {
-		arg.ReadConfig(args)
+		var configPath string
+		args.String(&configPath, "c", "config file path (default config.json, or $"+envconfig.ConfigFileEnv+")")
 		args.Parse()
 
+		// 配置文件之上再用 TRADINGBOT_* 环境变量覆盖（容器中注入密钥）
+		configPath, overrides, err := envconfig.Load(configPath, os.LookupEnv)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+
+		if err := logging.ConfigValue.Apply(); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+		if err := i18n.ConfigValue.Apply(); err != nil {
+			fmt.Println(err)
+			os.Exit(-1)
+		}
+
 		_, logger := log.WithCtx(context.Background())
 		logger.PushPrefix("TradingBot")
 		logger.Info("交易机器人启动")
+		if configPath == "" {
+			logger.Info("未找到配置文件，使用默认配置和环境变量")
+		}
+		if len(overrides) > 0 {
+			// 只记录变量名，不记录值
+			logger.Info(fmt.Sprintf("环境变量覆盖配置: %s", strings.Join(overrides, ", ")))
+		}
 
 		// 注册交易相关命令
 		tradingcmd.RegisterAllTradingCommands()
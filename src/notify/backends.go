@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+)
+
+// ConsoleNotifier 输出到控制台
+type ConsoleNotifier struct{}
+
+func (n *ConsoleNotifier) Name() string { return "console" }
+
+func (n *ConsoleNotifier) Notify(ctx context.Context, msg *Message) error {
+	fmt.Printf("%s [%s] %s: %s\n", levelEmoji(msg.Level), msg.Time.UTC().Format("2006-01-02 15:04"), msg.Title, msg.body(" "))
+	return nil
+}
+
+// TelegramNotifier 通过 Telegram Bot API 发送消息
+type TelegramNotifier struct {
+	apiURL   string
+	botToken string
+	chatID   string
+	client   *http.Client
+}
+
+// NewTelegramNotifier 创建 Telegram 通知
+func NewTelegramNotifier(apiURL, botToken, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{apiURL: strings.TrimRight(apiURL, "/"), botToken: botToken, chatID: chatID, client: defaultHTTPClient}
+}
+
+func (n *TelegramNotifier) Name() string { return "telegram" }
+
+func (n *TelegramNotifier) Notify(ctx context.Context, msg *Message) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", n.apiURL, n.botToken)
+	payload := map[string]string{
+		"chat_id": n.chatID,
+		"text":    fmt.Sprintf("%s %s\n%s", levelEmoji(msg.Level), msg.Title, msg.body("\n")),
+	}
+	if err := postJSON(ctx, n.client, url, payload); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
+
+// DiscordNotifier 通过 Discord Webhook 发送消息
+type DiscordNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordNotifier 创建 Discord 通知
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL, client: defaultHTTPClient}
+}
+
+func (n *DiscordNotifier) Name() string { return "discord" }
+
+func (n *DiscordNotifier) Notify(ctx context.Context, msg *Message) error {
+	payload := map[string]string{
+		"content": fmt.Sprintf("%s **%s**\n%s", levelEmoji(msg.Level), msg.Title, msg.body("\n")),
+	}
+	if err := postJSON(ctx, n.client, n.webhookURL, payload); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
+
+// SlackNotifier 通过 Slack Incoming Webhook 发送消息
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier 创建 Slack 通知
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, client: defaultHTTPClient}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+func (n *SlackNotifier) Notify(ctx context.Context, msg *Message) error {
+	payload := map[string]string{
+		"text": fmt.Sprintf("%s *%s*\n%s", levelEmoji(msg.Level), msg.Title, msg.body("\n")),
+	}
+	if err := postJSON(ctx, n.client, n.webhookURL, payload); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+// WebhookNotifier 以 JSON 格式 POST 完整消息到自定义地址
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建自定义 Webhook 通知
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: defaultHTTPClient}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg *Message) error {
+	if err := postJSON(ctx, n.client, n.url, msg); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+// EmailNotifier 通过 SMTP 发送邮件
+type EmailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier 创建邮件通知，username 为空时不认证
+func NewEmailNotifier(host string, port int, username, password, from string, to []string) *EmailNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &EmailNotifier{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from, to: to, send: smtp.SendMail}
+}
+
+func (n *EmailNotifier) Name() string { return "email" }
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg *Message) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", n.from)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&body, "Subject: [tradingbot] %s\r\n", msg.Title)
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.body("\r\n"))
+	body.WriteString("\r\n")
+
+	if err := n.send(n.addr, n.auth, n.from, n.to, []byte(body.String())); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+// levelEmoji 通知级别对应的图标
+func levelEmoji(level Level) string {
+	switch level {
+	case LevelError:
+		return "🚨"
+	case LevelWarning:
+		return "⚠️"
+	default:
+		return "ℹ️"
+	}
+}
@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"fmt"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// Config 通知配置
+type Config struct {
+	Telegram  TelegramConfig `json:"telegram"`
+	Discord   WebhookConfig  `json:"discord"`
+	Slack     WebhookConfig  `json:"slack"`
+	Webhook   WebhookConfig  `json:"webhook"`
+	Email     EmailConfig    `json:"email"`
+	Routes    []RouteConfig  `json:"routes"`     // 路由规则，同一事件可发送到多个后端
+	QueueSize int            `json:"queue_size"` // 待发送通知队列长度，队列满时丢弃新通知
+}
+
+// TelegramConfig Telegram Bot 配置（BotToken 为空时不启用）
+type TelegramConfig struct {
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"`
+	APIURL   string `json:"api_url"`
+}
+
+// WebhookConfig Webhook 配置（URL 为空时不启用）
+type WebhookConfig struct {
+	URL string `json:"url"`
+}
+
+// EmailConfig SMTP 邮件配置（Host 为空时不启用）
+type EmailConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"` // 为空时不认证
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// RouteConfig 路由规则配置
+type RouteConfig struct {
+	Events    []string `json:"events"`    // 事件类型（见 engine.KnownEventTypes），为空时匹配全部
+	Notifiers []string `json:"notifiers"` // 后端：console、telegram、discord、slack、webhook、email
+}
+
+// ConfigValue 通知配置实例
+var ConfigValue = Config{
+	Telegram: TelegramConfig{APIURL: "https://api.telegram.org"},
+	Email:    EmailConfig{Port: 587, To: []string{}},
+	Routes: []RouteConfig{
+		{Events: []string{string(engine.EventRisk), string(engine.EventError), string(engine.EventShutdown)}, Notifiers: []string{"console"}},
+	},
+	QueueSize: 100,
+}
+
+// Notifiers 根据配置创建已启用的后端（console 始终可用）
+func (c Config) Notifiers() ([]Notifier, error) {
+	notifiers := []Notifier{&ConsoleNotifier{}}
+
+	if c.Telegram.BotToken != "" {
+		if c.Telegram.ChatID == "" {
+			return nil, fmt.Errorf("Telegram.ChatID is required when Telegram.BotToken is set")
+		}
+		notifiers = append(notifiers, NewTelegramNotifier(c.Telegram.APIURL, c.Telegram.BotToken, c.Telegram.ChatID))
+	}
+	if c.Discord.URL != "" {
+		notifiers = append(notifiers, NewDiscordNotifier(c.Discord.URL))
+	}
+	if c.Slack.URL != "" {
+		notifiers = append(notifiers, NewSlackNotifier(c.Slack.URL))
+	}
+	if c.Webhook.URL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(c.Webhook.URL))
+	}
+	if c.Email.Host != "" {
+		if c.Email.From == "" || len(c.Email.To) == 0 {
+			return nil, fmt.Errorf("email: from and to are required")
+		}
+		notifiers = append(notifiers, NewEmailNotifier(c.Email.Host, c.Email.Port, c.Email.Username, c.Email.Password, c.Email.From, c.Email.To))
+	}
+	return notifiers, nil
+}
+
+// NewRouter 根据配置创建通知路由
+func (c Config) NewRouter() (*Router, error) {
+	notifiers, routes, err := c.build()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := NewRouter(notifiers, routes, c.QueueSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify config: %w", err)
+	}
+	return router, nil
+}
+
+// ReloadRouter 按新配置替换运行中路由的后端和规则（队列长度不变），配置无效时保持原路由
+func (c Config) ReloadRouter(router *Router) error {
+	notifiers, routes, err := c.build()
+	if err != nil {
+		return err
+	}
+	if err := router.Reload(notifiers, routes); err != nil {
+		return fmt.Errorf("invalid notify config: %w", err)
+	}
+	return nil
+}
+
+// build 根据配置创建后端和路由规则
+func (c Config) build() ([]Notifier, []Route, error) {
+	notifiers, err := c.Notifiers()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid notify config: %w", err)
+	}
+
+	known := make(map[engine.EventType]bool)
+	for _, eventType := range engine.KnownEventTypes() {
+		known[eventType] = true
+	}
+	routes := make([]Route, 0, len(c.Routes))
+	for _, rc := range c.Routes {
+		route := Route{Notifiers: rc.Notifiers}
+		for _, name := range rc.Events {
+			eventType := engine.EventType(name)
+			if !known[eventType] {
+				return nil, nil, fmt.Errorf("invalid notify config: unknown event %q (supported: %v)", name, engine.KnownEventTypes())
+			}
+			route.Events = append(route.Events, eventType)
+		}
+		routes = append(routes, route)
+	}
+	return notifiers, routes, nil
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("NOTIFY", &ConfigValue)
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"tradingbot/src/engine"
+)
+
+// FormatEvent 将引擎事件转换为通知消息
+func FormatEvent(event *engine.Event) *Message {
+	msg := &Message{Event: event.Type, Level: LevelInfo, Time: event.Time}
+	pair := event.TradingPair.String()
+
+	switch event.Type {
+	case engine.EventKlineProcessed:
+		msg.Title = fmt.Sprintf("Kline %s", pair)
+		msg.Text = fmt.Sprintf("close %s, cash %s, position %s", event.Kline.Close.String(),
+			event.Portfolio.Cash.StringFixed(2), event.Portfolio.Position.String())
+	case engine.EventSignalGenerated:
+		signal := event.Signal
+		msg.Title = fmt.Sprintf("%s signal %s", signal.Type, pair)
+		msg.Text = fmt.Sprintf("%s (strength %.1f) @ %s", signal.Reason, signal.Strength, event.Kline.Close.String())
+		if len(event.Indicators) > 0 {
+			msg.Text += fmt.Sprintf("; %s", formatIndicators(event.Indicators))
+		}
+	case engine.EventOrderPlaced:
+		order := event.Order
+		msg.Title = fmt.Sprintf("Order placed %s", pair)
+		msg.Text = fmt.Sprintf("%s %s @ %s", order.Type, order.Quantity.String(), order.Price.String())
+		if order.Reason != "" {
+			msg.Text += fmt.Sprintf(" (%s)", order.Reason)
+		}
+	case engine.EventOrderFilled:
+		fill := event.Fill
+		msg.Title = fmt.Sprintf("Order filled %s", pair)
+		msg.Text = fmt.Sprintf("%s %s @ %s, fee %s", fill.Side, fill.Quantity.String(), fill.Price.String(), fill.Commission.String())
+	case engine.EventOrderCancelled:
+		order := event.Order
+		msg.Level = LevelWarning
+		msg.Title = fmt.Sprintf("Order %s %s", event.Message, pair)
+		msg.Text = fmt.Sprintf("%s %s @ %s", order.Type, order.Quantity.String(), order.Price.String())
+	case engine.EventPositionClosed:
+		fill := event.Fill
+		msg.Title = fmt.Sprintf("Position closed %s", pair)
+		msg.Text = fmt.Sprintf("SELL %s @ %s, cash %s", fill.Quantity.String(), fill.Price.String(), event.Portfolio.Cash.StringFixed(2))
+	case engine.EventRisk:
+		risk := event.Risk
+		switch risk.Type {
+		case engine.RiskEventHalted:
+			msg.Level = LevelError
+			msg.Title = fmt.Sprintf("Trading halted %s", pair)
+			msg.Text = fmt.Sprintf("%s; all orders cancelled, restart required", risk.Reason)
+		case engine.RiskEventDailyPaused:
+			msg.Level = LevelWarning
+			msg.Title = fmt.Sprintf("Entries paused %s", pair)
+			msg.Text = fmt.Sprintf("%s; entry orders cancelled until next UTC day", risk.Reason)
+		case engine.RiskEventDrawdown:
+			msg.Level = LevelError
+			msg.Title = fmt.Sprintf("Drawdown limit %s", pair)
+			msg.Text = fmt.Sprintf("%s; action %s", risk.Reason, risk.DrawdownAction)
+		case engine.RiskEventRecovered:
+			msg.Title = fmt.Sprintf("Drawdown recovered %s", pair)
+			msg.Text = risk.Reason
+		case engine.RiskEventManualPaused:
+			msg.Level = LevelWarning
+			msg.Title = fmt.Sprintf("Entries paused manually %s", pair)
+			msg.Text = risk.Reason
+		case engine.RiskEventManualResumed:
+			msg.Title = fmt.Sprintf("Entries resumed manually %s", pair)
+			msg.Text = risk.Reason
+		case engine.RiskEventManualFlatten:
+			msg.Level = LevelWarning
+			msg.Title = fmt.Sprintf("Position flattened manually %s", pair)
+			msg.Text = fmt.Sprintf("%s; all orders cancelled, entries paused", risk.Reason)
+		default:
+			msg.Title = fmt.Sprintf("Entries resumed %s", pair)
+			msg.Text = fmt.Sprintf("new UTC day, daily pnl %s", risk.DailyPnL.StringFixed(2))
+		}
+	case engine.EventError:
+		msg.Level = LevelError
+		msg.Title = fmt.Sprintf("Error %s", pair)
+		msg.Text = fmt.Sprintf("%s: %v", event.Message, event.Err)
+	case engine.EventEngineStopped:
+		msg.Level = LevelWarning
+		msg.Title = fmt.Sprintf("Engine stopped %s", pair)
+		msg.Text = event.Message
+	case engine.EventEngineRestarted:
+		msg.Level = LevelWarning
+		msg.Title = fmt.Sprintf("Engine restarted %s", pair)
+		msg.Text = event.Message
+	case engine.EventFeedStalled:
+		msg.Level = LevelError
+		msg.Title = fmt.Sprintf("Data feed stalled %s", pair)
+		msg.Text = fmt.Sprintf("%s; restarting feed", event.Message)
+	case engine.EventFeedRecovered:
+		msg.Title = fmt.Sprintf("Data feed recovered %s", pair)
+		msg.Text = event.Message
+	case engine.EventShutdown:
+		msg.Level = LevelWarning
+		if report := event.Shutdown; report.Abnormal || len(report.Errors) > 0 {
+			msg.Level = LevelError
+		}
+		msg.Title = fmt.Sprintf("Bot shut down %s", pair)
+		msg.Text = event.Message
+	default:
+		msg.Title = fmt.Sprintf("%s %s", event.Type, pair)
+		msg.Text = event.Message
+	}
+	return msg
+}
+
+// formatIndicators 按名称排序输出指标快照（8 位有效数字），如 "bb_lower=41800.5, bb_upper=43950.2"
+func formatIndicators(indicators map[string]float64) string {
+	names := make([]string, 0, len(indicators))
+	for name := range indicators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%s", name, strconv.FormatFloat(indicators[name], 'g', 8, 64))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"tradingbot/src/engine"
+)
+
+// Level 通知级别
+type Level string
+
+const (
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Message 通知消息
+type Message struct {
+	Event engine.EventType `json:"event"`
+	Level Level            `json:"level"`
+	Title string           `json:"title"`
+	Text  string           `json:"text"`
+	Time  time.Time        `json:"time"`
+
+	TraceID string `json:"trace_id,omitempty"` // 产生事件的运行跟踪ID，与日志 trace=<id> 对应
+}
+
+// body 消息正文，有跟踪ID时用 sep 分隔后附上
+func (m *Message) body(sep string) string {
+	if m.TraceID == "" {
+		return m.Text
+	}
+	return m.Text + sep + "trace=" + m.TraceID
+}
+
+// Notifier 通知后端
+type Notifier interface {
+	// Name 后端名称，路由规则按名称引用
+	Name() string
+
+	// Notify 发送通知
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// defaultHTTPClient 通知请求使用的 HTTP 客户端
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON 以 JSON 格式 POST 请求，非 2xx 响应返回错误
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
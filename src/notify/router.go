@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// Route 路由规则：匹配的事件发送到指定后端
+type Route struct {
+	Events    []engine.EventType // 为空时匹配全部事件
+	Notifiers []string           // 后端名称
+}
+
+// matches 事件是否匹配该规则
+func (r Route) matches(eventType engine.EventType) bool {
+	if len(r.Events) == 0 {
+		return true
+	}
+	for _, t := range r.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Router 订阅事件总线，按路由规则异步发送通知（发送慢或失败不阻塞引擎）
+type Router struct {
+	mu        sync.RWMutex
+	notifiers map[string]Notifier
+	routes    []Route
+	queue     chan *Message
+	pending   sync.WaitGroup // 已入队尚未发送完成的通知
+	prefix    string         // 通知标题前缀（如测试网的 [TESTNET]）
+	traceID   string         // 附加到每条通知的运行跟踪ID
+}
+
+// NewRouter 创建通知路由，规则引用的后端必须存在
+func NewRouter(notifiers []Notifier, routes []Route, queueSize int) (*Router, error) {
+	byName, err := indexNotifiers(notifiers, routes)
+	if err != nil {
+		return nil, err
+	}
+	if queueSize <= 0 {
+		queueSize = 100
+	}
+	return &Router{notifiers: byName, routes: routes, queue: make(chan *Message, queueSize)}, nil
+}
+
+// Reload 运行中替换通知后端和路由规则（实盘热更新配置），队列中待发送的通知按新规则发送
+func (r *Router) Reload(notifiers []Notifier, routes []Route) error {
+	byName, err := indexNotifiers(notifiers, routes)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifiers, r.routes = byName, routes
+	return nil
+}
+
+// indexNotifiers 按名称索引后端，规则引用的后端必须存在
+func indexNotifiers(notifiers []Notifier, routes []Route) (map[string]Notifier, error) {
+	byName := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byName[notifier.Name()] = notifier
+	}
+	for _, route := range routes {
+		for _, name := range route.Notifiers {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("route references unconfigured notifier %q", name)
+			}
+		}
+	}
+	return byName, nil
+}
+
+// SetTitlePrefix 设置通知标题前缀，需在订阅事件总线之前调用
+func (r *Router) SetTitlePrefix(prefix string) {
+	r.prefix = prefix
+}
+
+// SetTraceID 设置附加到每条通知的运行跟踪ID，需在订阅事件总线之前调用
+func (r *Router) SetTraceID(traceID string) {
+	r.traceID = traceID
+}
+
+// Subscribe 订阅事件总线，事件转换为通知后放入发送队列（队列满时丢弃）
+func (r *Router) Subscribe(bus *engine.EventBus) {
+	bus.Subscribe(func(ctx context.Context, event *engine.Event) {
+		if !r.routed(event.Type) {
+			return
+		}
+		msg := FormatEvent(event)
+		if r.prefix != "" {
+			msg.Title = r.prefix + " " + msg.Title
+		}
+		msg.TraceID = r.traceID
+		r.pending.Add(1)
+		select {
+		case r.queue <- msg:
+		default:
+			r.pending.Done()
+			_, logger := log.WithCtx(ctx)
+			logger.Warning(fmt.Sprintf("通知队列已满，丢弃通知: event=%s", event.Type))
+		}
+	})
+}
+
+// Start 后台发送队列中的通知，ctx 取消后退出
+func (r *Router) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-r.queue:
+				r.send(ctx, msg)
+			}
+		}
+	}()
+}
+
+// Flush 同步发送队列中剩余的通知，并等待正在发送的通知完成（退出前调用，ctx 超时后返回）
+func (r *Router) Flush(ctx context.Context) {
+	for drained := false; !drained; {
+		select {
+		case msg := <-r.queue:
+			r.send(ctx, msg)
+		default:
+			drained = true
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// send 发送一条通知，失败时记录日志
+func (r *Router) send(ctx context.Context, msg *Message) {
+	defer r.pending.Done()
+	if err := r.Dispatch(ctx, msg); err != nil {
+		_, logger := log.WithCtx(ctx)
+		logger.Error("发送通知失败", "event", msg.Event, "error", err)
+	}
+}
+
+// Dispatch 按路由规则同步发送通知，同一后端只发送一次
+func (r *Router) Dispatch(ctx context.Context, msg *Message) error {
+	r.mu.RLock()
+	notifiers, routes := r.notifiers, r.routes
+	r.mu.RUnlock()
+
+	sent := make(map[string]bool)
+	var errs []error
+	for _, route := range routes {
+		if !route.matches(msg.Event) {
+			continue
+		}
+		for _, name := range route.Notifiers {
+			if sent[name] {
+				continue
+			}
+			sent[name] = true
+			if err := notifiers[name].Notify(ctx, msg); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routed 是否有规则匹配该事件
+func (r *Router) routed(eventType engine.EventType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, route := range r.routes {
+		if route.matches(eventType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier 记录收到的通知
+type recordingNotifier struct {
+	name     string
+	messages []*Message
+	err      error
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Notify(ctx context.Context, msg *Message) error {
+	n.messages = append(n.messages, msg)
+	return n.err
+}
+
+func TestRouter_RoutesEventsToNotifiers(t *testing.T) {
+	slack := &recordingNotifier{name: "slack"}
+	telegram := &recordingNotifier{name: "telegram"}
+	router, err := NewRouter([]Notifier{slack, telegram}, []Route{
+		{Events: []engine.EventType{engine.EventError}, Notifiers: []string{"slack"}},
+		{Events: []engine.EventType{engine.EventOrderFilled}, Notifiers: []string{"telegram"}},
+		{Notifiers: []string{"slack"}}, // 全部事件，已发送过的后端不重复发送
+	}, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, router.Dispatch(context.Background(), &Message{Event: engine.EventError}))
+	require.NoError(t, router.Dispatch(context.Background(), &Message{Event: engine.EventOrderFilled}))
+
+	assert.Len(t, slack.messages, 2)
+	require.Len(t, telegram.messages, 1)
+	assert.Equal(t, engine.EventOrderFilled, telegram.messages[0].Event)
+
+	// 单个后端失败不影响其他后端
+	slack.err = errors.New("down")
+	err = router.Dispatch(context.Background(), &Message{Event: engine.EventOrderFilled})
+	assert.Error(t, err)
+	assert.Len(t, telegram.messages, 2)
+
+	_, err = NewRouter([]Notifier{slack}, []Route{{Notifiers: []string{"discord"}}}, 10)
+	assert.Error(t, err)
+}
+
+func TestRouter_Reload(t *testing.T) {
+	slack := &recordingNotifier{name: "slack"}
+	telegram := &recordingNotifier{name: "telegram"}
+	router, err := NewRouter([]Notifier{slack}, []Route{{Notifiers: []string{"slack"}}}, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, router.Reload([]Notifier{slack, telegram}, []Route{
+		{Events: []engine.EventType{engine.EventOrderFilled}, Notifiers: []string{"telegram"}},
+	}))
+	assert.False(t, router.routed(engine.EventError))
+	require.NoError(t, router.Dispatch(context.Background(), &Message{Event: engine.EventOrderFilled}))
+	assert.Empty(t, slack.messages)
+	assert.Len(t, telegram.messages, 1)
+
+	// 无效规则不生效
+	assert.Error(t, router.Reload([]Notifier{slack}, []Route{{Notifiers: []string{"discord"}}}))
+	assert.True(t, router.routed(engine.EventOrderFilled))
+}
+
+func TestRouter_SubscribesToEventBus(t *testing.T) {
+	notifier := &recordingNotifier{name: "webhook"}
+	router, err := NewRouter([]Notifier{notifier}, []Route{
+		{Events: []engine.EventType{engine.EventRisk}, Notifiers: []string{"webhook"}},
+	}, 10)
+	require.NoError(t, err)
+
+	bus := engine.NewEventBus()
+	router.Subscribe(bus)
+	bus.Publish(context.Background(), &engine.Event{Type: engine.EventOrderPlaced})
+	bus.Publish(context.Background(), &engine.Event{
+		Type:        engine.EventRisk,
+		TradingPair: cex.TradingPair{Base: "BTC", Quote: "USDT"},
+		Risk:        &engine.RiskEvent{Type: engine.RiskEventDailyPaused, Reason: "daily loss 60.00 exceeds limit 50", DailyPnL: decimal.NewFromInt(-60)},
+	})
+
+	// 未路由的事件不进入队列
+	require.Len(t, router.queue, 1)
+	msg := <-router.queue
+	assert.Equal(t, LevelWarning, msg.Level)
+	assert.Equal(t, "Entries paused BTC/USDT", msg.Title)
+}
+
+func TestRouter_FlushSendsQueuedNotifications(t *testing.T) {
+	console := &recordingNotifier{name: "console"}
+	router, err := NewRouter([]Notifier{console}, []Route{{Events: []engine.EventType{engine.EventShutdown}, Notifiers: []string{"console"}}}, 10)
+	require.NoError(t, err)
+	bus := engine.NewEventBus()
+	router.Subscribe(bus)
+
+	// 未启动后台发送（或已随 ctx 退出）时，Flush 同步发送队列中的通知
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus.Publish(context.Background(), &engine.Event{Type: engine.EventShutdown, TradingPair: pair,
+		Shutdown: &engine.ShutdownReport{Reason: "received interrupt"}, Message: "received interrupt; keep orders and position"})
+	router.Flush(context.Background())
+
+	require.Len(t, console.messages, 1)
+	assert.Equal(t, "Bot shut down BTC/USDT", console.messages[0].Title)
+	assert.Equal(t, LevelWarning, console.messages[0].Level)
+
+	// 非正常退出按错误级别通知
+	msg := FormatEvent(&engine.Event{Type: engine.EventShutdown, TradingPair: pair, Shutdown: &engine.ShutdownReport{Abnormal: true}})
+	assert.Equal(t, LevelError, msg.Level)
+}
+
+func TestRouter_TitlePrefix(t *testing.T) {
+	notifier := &recordingNotifier{name: "webhook"}
+	router, err := NewRouter([]Notifier{notifier}, []Route{{Notifiers: []string{"webhook"}}}, 10)
+	require.NoError(t, err)
+	router.SetTitlePrefix("[TESTNET]")
+
+	bus := engine.NewEventBus()
+	router.Subscribe(bus)
+	bus.Publish(context.Background(), &engine.Event{
+		Type:        engine.EventRisk,
+		TradingPair: cex.TradingPair{Base: "BTC", Quote: "USDT"},
+		Risk:        &engine.RiskEvent{Type: engine.RiskEventHalted, Reason: "3 consecutive losses"},
+	})
+
+	require.Len(t, router.queue, 1)
+	msg := <-router.queue
+	assert.True(t, strings.HasPrefix(msg.Title, "[TESTNET] "), msg.Title)
+}
+
+func TestRouter_TraceID(t *testing.T) {
+	notifier := &recordingNotifier{name: "webhook"}
+	router, err := NewRouter([]Notifier{notifier}, []Route{{Notifiers: []string{"webhook"}}}, 10)
+	require.NoError(t, err)
+	router.SetTraceID("live-20240301T080000Z-3f9a1c")
+
+	bus := engine.NewEventBus()
+	router.Subscribe(bus)
+	bus.Publish(context.Background(), &engine.Event{
+		Type:        engine.EventRisk,
+		TradingPair: cex.TradingPair{Base: "BTC", Quote: "USDT"},
+		Risk:        &engine.RiskEvent{Type: engine.RiskEventHalted, Reason: "3 consecutive losses"},
+	})
+
+	require.Len(t, router.queue, 1)
+	msg := <-router.queue
+	assert.Equal(t, "live-20240301T080000Z-3f9a1c", msg.TraceID)
+	assert.True(t, strings.HasSuffix(msg.body("\n"), "\ntrace=live-20240301T080000Z-3f9a1c"), msg.body("\n"))
+}
+
+func TestFormatEvent_SignalIndicators(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	kline := &cex.KlineData{Close: decimal.NewFromInt(41500)}
+	signal := &strategy.Signal{Type: "BUY", Reason: "price below lower band", Strength: 0.8}
+
+	msg := FormatEvent(&engine.Event{Type: engine.EventSignalGenerated, TradingPair: pair, Kline: kline, Signal: signal})
+	assert.Equal(t, "BUY signal BTC/USDT", msg.Title)
+	assert.Equal(t, "price below lower band (strength 0.8) @ 41500", msg.Text)
+
+	msg = FormatEvent(&engine.Event{Type: engine.EventSignalGenerated, TradingPair: pair, Kline: kline, Signal: signal,
+		Indicators: map[string]float64{"bb_upper": 43950.2, "bb_lower": 41800.123456789}})
+	assert.Equal(t, "price below lower band (strength 0.8) @ 41500; bb_lower=41800.123, bb_upper=43950.2", msg.Text)
+}
+
+func TestBackends_PostPayloads(t *testing.T) {
+	var paths []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	msg := &Message{Event: engine.EventError, Level: LevelError, Title: "Error BTC/USDT", Text: "boom", Time: time.Now()}
+	ctx := context.Background()
+	require.NoError(t, NewTelegramNotifier(server.URL, "token", "42").Notify(ctx, msg))
+	require.NoError(t, NewSlackNotifier(server.URL+"/slack").Notify(ctx, msg))
+	require.NoError(t, NewDiscordNotifier(server.URL+"/discord").Notify(ctx, msg))
+	require.NoError(t, NewWebhookNotifier(server.URL+"/hook").Notify(ctx, msg))
+
+	assert.Equal(t, []string{"/bottoken/sendMessage", "/slack", "/discord", "/hook"}, paths)
+	assert.Equal(t, "42", bodies[0]["chat_id"])
+	assert.Contains(t, bodies[1]["text"], "*Error BTC/USDT*")
+	assert.Contains(t, bodies[2]["content"], "**Error BTC/USDT**")
+	assert.Equal(t, "error", bodies[3]["event"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewSlackNotifier(failing.URL).Notify(ctx, msg))
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com", 587, "", "", "bot@example.com", []string{"me@example.com"})
+	var sent string
+	notifier.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		sent = string(msg)
+		return nil
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), &Message{Title: "Order filled BTC/USDT", Text: "BUY 1 @ 100"}))
+	assert.Contains(t, sent, "Subject: [tradingbot] Order filled BTC/USDT")
+	assert.Contains(t, sent, "BUY 1 @ 100")
+}
+
+func TestConfig_NewRouter(t *testing.T) {
+	config := Config{
+		Slack:  WebhookConfig{URL: "https://hooks.slack.com/services/x"},
+		Routes: []RouteConfig{{Events: []string{"error"}, Notifiers: []string{"slack"}}},
+	}
+	_, err := config.NewRouter()
+	require.NoError(t, err)
+
+	config.Routes = []RouteConfig{{Events: []string{"fills"}, Notifiers: []string{"slack"}}}
+	_, err = config.NewRouter()
+	assert.Error(t, err)
+
+	config.Routes = []RouteConfig{{Notifiers: []string{"telegram"}}}
+	_, err = config.NewRouter()
+	assert.Error(t, err)
+}
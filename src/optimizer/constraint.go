@@ -0,0 +1,126 @@
+package optimizer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"tradingbot/src/trading"
+)
+
+// constraintMetrics 可用于约束的回测指标（百分比指标以百分数表示，如 max_drawdown<20 表示最大回撤低于20%）
+var constraintMetrics = map[string]func(stats *trading.BacktestStatistics) float64{
+	"max_drawdown": func(stats *trading.BacktestStatistics) float64 {
+		return stats.MaxDrawdownPercent.InexactFloat64()
+	},
+	"return": func(stats *trading.BacktestStatistics) float64 {
+		return stats.TotalReturn.InexactFloat64() * 100
+	},
+	"annual_return": func(stats *trading.BacktestStatistics) float64 {
+		return stats.AnnualReturn.InexactFloat64()
+	},
+	"sharpe": func(stats *trading.BacktestStatistics) float64 {
+		return stats.SharpeRatio.InexactFloat64()
+	},
+	"profit_factor": func(stats *trading.BacktestStatistics) float64 {
+		return stats.ProfitFactor.InexactFloat64()
+	},
+	"trades": func(stats *trading.BacktestStatistics) float64 {
+		return float64(stats.TotalTrades)
+	},
+	"win_rate": func(stats *trading.BacktestStatistics) float64 {
+		if stats.TotalTrades == 0 {
+			return 0
+		}
+		return float64(stats.WinningTrades) / float64(stats.TotalTrades) * 100
+	},
+}
+
+// constraintOperators 按长度从长到短排列，保证先匹配 <= 和 >=
+var constraintOperators = []string{"<=", ">=", "<", ">"}
+
+// Constraint 回测结果约束，如 max_drawdown<20、trades>=10
+type Constraint struct {
+	Metric string
+	Op     string
+	Value  float64
+}
+
+// ParseConstraints 解析约束字符串，格式: "max_drawdown<20,trades>=10"
+func ParseConstraints(s string) ([]Constraint, error) {
+	var constraints []Constraint
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var constraint Constraint
+		for _, op := range constraintOperators {
+			if index := strings.Index(item, op); index > 0 {
+				constraint.Metric = strings.TrimSpace(item[:index])
+				constraint.Op = op
+				value, err := strconv.ParseFloat(strings.TrimSpace(item[index+len(op):]), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid constraint value: %s", item)
+				}
+				constraint.Value = value
+				break
+			}
+		}
+		if constraint.Op == "" {
+			return nil, fmt.Errorf("invalid constraint format: %s (expected metric<value, metric<=value, metric>value or metric>=value)", item)
+		}
+		if _, ok := constraintMetrics[constraint.Metric]; !ok {
+			return nil, fmt.Errorf("unknown constraint metric: %s (supported: %s)", constraint.Metric, strings.Join(constraintMetricNames(), ", "))
+		}
+
+		constraints = append(constraints, constraint)
+	}
+
+	return constraints, nil
+}
+
+// constraintMetricNames 可用的约束指标名称（排序）
+func constraintMetricNames() []string {
+	names := make([]string, 0, len(constraintMetrics))
+	for name := range constraintMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String 约束的文本形式，如 "max_drawdown<20"
+func (c Constraint) String() string {
+	return fmt.Sprintf("%s%s%g", c.Metric, c.Op, c.Value)
+}
+
+// Check 回测结果是否满足约束，不满足时返回原因
+func (c Constraint) Check(stats *trading.BacktestStatistics) error {
+	metric, ok := constraintMetrics[c.Metric]
+	if !ok {
+		return fmt.Errorf("unknown constraint metric: %s", c.Metric)
+	}
+
+	value := metric(stats)
+	var satisfied bool
+	switch c.Op {
+	case "<":
+		satisfied = value < c.Value
+	case "<=":
+		satisfied = value <= c.Value
+	case ">":
+		satisfied = value > c.Value
+	case ">=":
+		satisfied = value >= c.Value
+	default:
+		return fmt.Errorf("unknown constraint operator: %s", c.Op)
+	}
+	if !satisfied {
+		return fmt.Errorf("constraint %s violated: %s=%.4f", c, c.Metric, value)
+	}
+	return nil
+}
@@ -0,0 +1,432 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+)
+
+// Objective 优化目标
+type Objective string
+
+const (
+	ObjectiveSharpe         Objective = "sharpe"          // 夏普比率
+	ObjectiveTotalReturn    Objective = "return"          // 总收益率
+	ObjectiveProfitFactor   Objective = "profit_factor"   // 盈利因子
+	ObjectiveReturnDrawdown Objective = "return_drawdown" // 总收益率 / 最大回撤（回撤不足1%时按1%计）
+)
+
+// ObjectiveFunc 根据回测统计计算得分（越大越好）
+type ObjectiveFunc func(stats *trading.BacktestStatistics) float64
+
+var (
+	objectivesMu sync.RWMutex
+	objectives   = map[Objective]ObjectiveFunc{
+		ObjectiveSharpe: func(stats *trading.BacktestStatistics) float64 {
+			return stats.SharpeRatio.InexactFloat64()
+		},
+		ObjectiveTotalReturn: func(stats *trading.BacktestStatistics) float64 {
+			return stats.TotalReturn.InexactFloat64()
+		},
+		ObjectiveProfitFactor: func(stats *trading.BacktestStatistics) float64 {
+			return stats.ProfitFactor.InexactFloat64()
+		},
+		ObjectiveReturnDrawdown: func(stats *trading.BacktestStatistics) float64 {
+			return stats.TotalReturn.InexactFloat64() * 100 / math.Max(stats.MaxDrawdownPercent.InexactFloat64(), 1)
+		},
+	}
+)
+
+// RegisterObjective 注册自定义优化目标（同名时覆盖），注册后可通过 ParseObjective 使用
+func RegisterObjective(name string, fn ObjectiveFunc) {
+	objectivesMu.Lock()
+	defer objectivesMu.Unlock()
+	objectives[Objective(name)] = fn
+}
+
+// ParseObjective 解析优化目标
+func ParseObjective(s string) (Objective, error) {
+	objectivesMu.RLock()
+	defer objectivesMu.RUnlock()
+	if _, ok := objectives[Objective(s)]; ok {
+		return Objective(s), nil
+	}
+
+	names := make([]string, 0, len(objectives))
+	for name := range objectives {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown objective: %s (supported: %s)", s, strings.Join(names, ", "))
+}
+
+// Score 根据优化目标计算回测得分（越大越好），未注册的目标按夏普比率计算
+func (o Objective) Score(stats *trading.BacktestStatistics) float64 {
+	objectivesMu.RLock()
+	fn, ok := objectives[o]
+	if !ok {
+		fn = objectives[ObjectiveSharpe]
+	}
+	objectivesMu.RUnlock()
+	return fn(stats)
+}
+
+// ParamRange 单个参数的扫描范围 [Min, Max]，步长 Step
+type ParamRange struct {
+	Name string
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// Values 展开范围内的所有取值
+func (r ParamRange) Values() ([]float64, error) {
+	if r.Step <= 0 {
+		return nil, fmt.Errorf("step of %s must be positive, got %f", r.Name, r.Step)
+	}
+	if r.Max < r.Min {
+		return nil, fmt.Errorf("max of %s must not be less than min (%f < %f)", r.Name, r.Max, r.Min)
+	}
+
+	var values []float64
+	count := int(math.Floor((r.Max-r.Min)/r.Step+1e-9)) + 1
+	for i := 0; i < count; i++ {
+		// 四舍五入以消除浮点累加误差
+		value := math.Round((r.Min+float64(i)*r.Step)*1e8) / 1e8
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// GetDefaultParamRanges 获取默认的布林道参数扫描范围
+func GetDefaultParamRanges() []ParamRange {
+	return []ParamRange{
+		{Name: "period", Min: 10, Max: 50, Step: 5},
+		{Name: "multiplier", Min: 1.5, Max: 3.0, Step: 0.25},
+	}
+}
+
+// ParseParamRanges 解析参数范围字符串
+// 格式: "period=10:50:5,multiplier=1.5:3.0:0.25"（min:max:step）
+func ParseParamRanges(rangesStr string) ([]ParamRange, error) {
+	var ranges []ParamRange
+
+	for _, item := range strings.Split(rangesStr, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid range format: %s (expected name=min:max:step)", item)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		bounds := strings.Split(strings.TrimSpace(parts[1]), ":")
+		if len(bounds) != 3 {
+			return nil, fmt.Errorf("invalid range format: %s (expected name=min:max:step)", item)
+		}
+
+		var numbers [3]float64
+		for i, bound := range bounds {
+			value, err := strconv.ParseFloat(strings.TrimSpace(bound), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range value for %s: %s", name, bound)
+			}
+			numbers[i] = value
+		}
+
+		if err := applyParam(strategy.GetDefaultBollingerBandsParams(), name, numbers[0]); err != nil {
+			return nil, err
+		}
+
+		ranges = append(ranges, ParamRange{Name: name, Min: numbers[0], Max: numbers[1], Step: numbers[2]})
+	}
+
+	return ranges, nil
+}
+
+// applyParam 将参数值写入布林道参数
+func applyParam(params *strategy.BollingerBandsParams, name string, value float64) error {
+	switch name {
+	case "period":
+		params.Period = int(math.Round(value))
+	case "multiplier":
+		params.Multiplier = value
+	case "position_size":
+		params.PositionSizePercent = value
+	case "stop_loss":
+		params.StopLossPercent = value
+	case "take_profit":
+		params.TakeProfitPercent = value
+	case "cooldown":
+		params.CooldownBars = int(math.Round(value))
+	case "min_band_width":
+		params.MinBandWidth = value
+	case "max_band_width":
+		params.MaxBandWidth = value
+	case "volume_multiplier":
+		params.VolumeMultiplier = value
+		if params.VolumePeriod == 0 {
+			params.VolumePeriod = 20
+		}
+	default:
+		return fmt.Errorf("unknown optimizable parameter: %s (supported: period, multiplier, position_size, stop_loss, take_profit, cooldown, min_band_width, max_band_width, volume_multiplier)", name)
+	}
+	return nil
+}
+
+// BacktestFunc 使用给定参数运行一次回测
+type BacktestFunc func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error)
+
+// Result 单组参数的优化结果
+type Result struct {
+	Params *strategy.BollingerBandsParams
+	Stats  *trading.BacktestStatistics
+	Score  float64
+	Err    error
+
+	ScreenScore float64 // 快速回测粗筛的得分（未粗筛时为0）
+}
+
+// Progress 优化进度，每完成一组回测报告一次
+type Progress struct {
+	Stage    string  // 阶段：StageFull 或 StageScreen
+	Done     int     // 本阶段已完成的组合数
+	Total    int     // 本阶段的组合总数
+	Latest   *Result // 刚完成的结果
+	Best     *Result // 本阶段目前得分最高的成功结果，尚无成功结果时为 nil
+	Improved bool    // Latest 是否刷新了 Best
+}
+
+const (
+	StageFull   = "full"   // 完整引擎回测
+	StageScreen = "screen" // 快速回测粗筛
+)
+
+// ProgressHandler 进度回调（串行调用，耗时操作会拖慢回测）
+type ProgressHandler func(Progress)
+
+// GridOptimizer 网格搜索参数优化器
+type GridOptimizer struct {
+	baseParams  *strategy.BollingerBandsParams
+	ranges      []ParamRange
+	objective   Objective
+	workers     int
+	onProgress  ProgressHandler
+	constraints []Constraint
+}
+
+// NewGridOptimizer 创建网格搜索优化器（workers<=0 时使用 GOMAXPROCS）
+func NewGridOptimizer(baseParams *strategy.BollingerBandsParams, ranges []ParamRange, objective Objective, workers int) *GridOptimizer {
+	if baseParams == nil {
+		baseParams = strategy.GetDefaultBollingerBandsParams()
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &GridOptimizer{
+		baseParams: baseParams,
+		ranges:     ranges,
+		objective:  objective,
+		workers:    workers,
+	}
+}
+
+// SetProgressHandler 设置进度回调，用于实时输出中间最优结果
+func (o *GridOptimizer) SetProgressHandler(handler ProgressHandler) {
+	o.onProgress = handler
+}
+
+// SetConstraints 设置回测结果约束，不满足约束的组合记为失败（排在最后，不参与最优结果）
+func (o *GridOptimizer) SetConstraints(constraints []Constraint) {
+	o.constraints = constraints
+}
+
+// GenerateCandidates 生成所有参数组合（笛卡尔积）
+func (o *GridOptimizer) GenerateCandidates() ([]*strategy.BollingerBandsParams, error) {
+	candidates := []*strategy.BollingerBandsParams{copyParams(o.baseParams)}
+
+	for _, r := range o.ranges {
+		values, err := r.Values()
+		if err != nil {
+			return nil, err
+		}
+
+		next := make([]*strategy.BollingerBandsParams, 0, len(candidates)*len(values))
+		for _, candidate := range candidates {
+			for _, value := range values {
+				params := copyParams(candidate)
+				if err := applyParam(params, r.Name, value); err != nil {
+					return nil, err
+				}
+				next = append(next, params)
+			}
+		}
+		candidates = next
+	}
+
+	return candidates, nil
+}
+
+// Run 并发运行所有参数组合的回测，结果按得分从高到低排序（失败的组合排在最后）
+func (o *GridOptimizer) Run(ctx context.Context, backtest BacktestFunc) ([]*Result, error) {
+	candidates, err := o.GenerateCandidates()
+	if err != nil {
+		return nil, err
+	}
+	results, err := o.runCandidates(ctx, candidates, backtest, o.newProgress(StageFull, len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	SortResults(results)
+	return results, nil
+}
+
+// RunScreened 两阶段优化：先用 screen（如向量化快速回测）粗筛所有参数组合，
+// 再用 full（完整引擎）重新回测得分最高的 finalists 组，返回完整回测的结果（按得分排序）
+func (o *GridOptimizer) RunScreened(ctx context.Context, screen, full BacktestFunc, finalists int) ([]*Result, error) {
+	all, err := o.GenerateCandidates()
+	if err != nil {
+		return nil, err
+	}
+	screened, err := o.runCandidates(ctx, all, screen, o.newProgress(StageScreen, len(all)))
+	if err != nil {
+		return nil, err
+	}
+	SortResults(screened)
+
+	var candidates []*strategy.BollingerBandsParams
+	screenScores := make(map[*strategy.BollingerBandsParams]float64)
+	for _, r := range screened {
+		if r.Err != nil || len(candidates) >= finalists {
+			break
+		}
+		candidates = append(candidates, r.Params)
+		screenScores[r.Params] = r.Score
+	}
+
+	results, err := o.runCandidates(ctx, candidates, full, o.newProgress(StageFull, len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		r.ScreenScore = screenScores[r.Params]
+	}
+	SortResults(results)
+	return results, nil
+}
+
+// runCandidates 由固定数量的 worker 并发回测给定的参数组合，结果与 candidates 顺序一致；
+// ctx 取消后不再分发新的组合，正在运行的回测通过 ctx 中止
+func (o *GridOptimizer) runCandidates(ctx context.Context, candidates []*strategy.BollingerBandsParams, backtest BacktestFunc, progress *progressTracker) ([]*Result, error) {
+	results := make([]*Result, len(candidates))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				params := candidates[i]
+				result := &Result{Params: params}
+
+				if err := params.Validate(); err != nil {
+					result.Err = err
+				} else if stats, err := backtest(ctx, params); err != nil {
+					result.Err = err
+				} else {
+					result.Stats = stats
+					result.Score = o.objective.Score(stats)
+					result.Err = o.checkConstraints(stats)
+				}
+
+				results[i] = result
+				progress.add(result)
+			}
+		}()
+	}
+
+	for i := range candidates {
+		select {
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return nil, ctx.Err()
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// checkConstraints 检查回测结果是否满足所有约束
+func (o *GridOptimizer) checkConstraints(stats *trading.BacktestStatistics) error {
+	for _, constraint := range o.constraints {
+		if err := constraint.Check(stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newProgress 创建一个阶段的进度汇总
+func (o *GridOptimizer) newProgress(stage string, total int) *progressTracker {
+	return &progressTracker{stage: stage, total: total, handler: o.onProgress}
+}
+
+// progressTracker 汇总各 worker 完成的结果并串行调用进度回调
+type progressTracker struct {
+	mu      sync.Mutex
+	stage   string
+	total   int
+	done    int
+	best    *Result
+	handler ProgressHandler
+}
+
+func (t *progressTracker) add(result *Result) {
+	if t.handler == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done++
+	improved := result.Err == nil && (t.best == nil || result.Score > t.best.Score)
+	if improved {
+		t.best = result
+	}
+	t.handler(Progress{Stage: t.stage, Done: t.done, Total: t.total, Latest: result, Best: t.best, Improved: improved})
+}
+
+// SortResults 按得分从高到低排序，失败的结果排在最后
+func SortResults(results []*Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Score > results[j].Score
+	})
+}
+
+// copyParams 复制参数（卖出策略参数map共享只读）
+func copyParams(params *strategy.BollingerBandsParams) *strategy.BollingerBandsParams {
+	paramsCopy := *params
+	return &paramsCopy
+}
@@ -0,0 +1,206 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamRange_Values(t *testing.T) {
+	values, err := ParamRange{Name: "multiplier", Min: 1.5, Max: 3.0, Step: 0.25}.Values()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 1.75, 2.0, 2.25, 2.5, 2.75, 3.0}, values)
+
+	values, err = ParamRange{Name: "period", Min: 20, Max: 20, Step: 1}.Values()
+	require.NoError(t, err)
+	assert.Equal(t, []float64{20}, values)
+
+	_, err = ParamRange{Name: "period", Min: 10, Max: 50, Step: 0}.Values()
+	assert.Error(t, err)
+
+	_, err = ParamRange{Name: "period", Min: 50, Max: 10, Step: 5}.Values()
+	assert.Error(t, err)
+}
+
+func TestParseParamRanges(t *testing.T) {
+	ranges, err := ParseParamRanges("period=10:50:5, multiplier=1.5:3.0:0.5")
+	require.NoError(t, err)
+	require.Len(t, ranges, 2)
+	assert.Equal(t, ParamRange{Name: "period", Min: 10, Max: 50, Step: 5}, ranges[0])
+	assert.Equal(t, ParamRange{Name: "multiplier", Min: 1.5, Max: 3.0, Step: 0.5}, ranges[1])
+
+	_, err = ParseParamRanges("period=10:50")
+	assert.Error(t, err)
+
+	_, err = ParseParamRanges("unknown=1:2:1")
+	assert.Error(t, err)
+
+	_, err = ParseParamRanges("period=a:50:5")
+	assert.Error(t, err)
+}
+
+func TestParseObjective(t *testing.T) {
+	objective, err := ParseObjective("profit_factor")
+	require.NoError(t, err)
+	assert.Equal(t, ObjectiveProfitFactor, objective)
+
+	_, err = ParseObjective("calmar")
+	assert.Error(t, err)
+}
+
+func TestGridOptimizer_GenerateCandidates(t *testing.T) {
+	ranges := []ParamRange{
+		{Name: "period", Min: 10, Max: 30, Step: 10},
+		{Name: "multiplier", Min: 2, Max: 2.5, Step: 0.5},
+	}
+	opt := NewGridOptimizer(nil, ranges, ObjectiveSharpe, 2)
+
+	candidates, err := opt.GenerateCandidates()
+	require.NoError(t, err)
+	require.Len(t, candidates, 6)
+
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		seen[fmt.Sprintf("%d/%.1f", c.Period, c.Multiplier)] = true
+		assert.Equal(t, "moderate", c.SellStrategyName) // 未扫描的参数保持基础值
+	}
+	assert.Len(t, seen, 6)
+	assert.True(t, seen["30/2.5"])
+}
+
+func TestGridOptimizer_Run(t *testing.T) {
+	ranges := []ParamRange{
+		{Name: "period", Min: 10, Max: 40, Step: 10},
+		{Name: "multiplier", Min: 0, Max: 2, Step: 1}, // multiplier=0 参数无效
+	}
+	opt := NewGridOptimizer(nil, ranges, ObjectiveTotalReturn, 3)
+
+	backtest := func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		if params.Period == 40 && params.Multiplier == 1 {
+			return nil, fmt.Errorf("backtest failed")
+		}
+		// 收益率 = period * multiplier / 100
+		return &trading.BacktestStatistics{
+			TotalReturn: decimal.NewFromFloat(float64(params.Period) * params.Multiplier / 100),
+		}, nil
+	}
+
+	results, err := opt.Run(context.Background(), backtest)
+	require.NoError(t, err)
+	require.Len(t, results, 12)
+
+	best := results[0]
+	require.NoError(t, best.Err)
+	assert.Equal(t, 40, best.Params.Period)
+	assert.Equal(t, 2.0, best.Params.Multiplier)
+	assert.InDelta(t, 0.8, best.Score, 1e-9)
+
+	// 失败的组合排在最后：4个 multiplier=0 + 1个回测失败
+	failed := 0
+	for i, r := range results {
+		if r.Err != nil {
+			failed++
+			assert.GreaterOrEqual(t, i, len(results)-5)
+		}
+	}
+	assert.Equal(t, 5, failed)
+}
+
+func TestGridOptimizer_Run_Cancelled(t *testing.T) {
+	opt := NewGridOptimizer(nil, GetDefaultParamRanges(), ObjectiveSharpe, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := opt.Run(ctx, func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		return &trading.BacktestStatistics{}, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGridOptimizer_RunScreened(t *testing.T) {
+	ranges := []ParamRange{
+		{Name: "period", Min: 10, Max: 40, Step: 10},
+		{Name: "multiplier", Min: 1, Max: 3, Step: 1},
+	}
+	opt := NewGridOptimizer(nil, ranges, ObjectiveTotalReturn, 2)
+
+	// 粗筛按 period 排名，完整回测按 multiplier 排名
+	var fullRuns int32
+	screen := func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		if params.Period == 40 && params.Multiplier == 3 {
+			return nil, fmt.Errorf("screen failed")
+		}
+		return &trading.BacktestStatistics{TotalReturn: decimal.NewFromInt(int64(params.Period))}, nil
+	}
+	full := func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		atomic.AddInt32(&fullRuns, 1)
+		return &trading.BacktestStatistics{TotalReturn: decimal.NewFromFloat(params.Multiplier)}, nil
+	}
+
+	results, err := opt.RunScreened(context.Background(), screen, full, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fullRuns))
+	require.Len(t, results, 3)
+
+	// 入围的是 period=40 的两组和 period=30 得分最高的一组，按完整回测得分重新排序
+	for _, r := range results {
+		require.NoError(t, r.Err)
+		assert.Equal(t, r.Params.Multiplier, r.Score)
+		assert.Equal(t, float64(r.Params.Period), r.ScreenScore)
+		assert.GreaterOrEqual(t, r.Params.Period, 30)
+	}
+	assert.Equal(t, 40, results[0].Params.Period)
+	assert.Equal(t, 2.0, results[0].Params.Multiplier)
+}
+
+func TestGridOptimizer_ProgressStreamsBest(t *testing.T) {
+	ranges := []ParamRange{{Name: "period", Min: 10, Max: 50, Step: 10}}
+	opt := NewGridOptimizer(nil, ranges, ObjectiveTotalReturn, 3)
+
+	var updates []Progress
+	opt.SetProgressHandler(func(p Progress) { updates = append(updates, p) }) // 串行调用，无需加锁
+
+	_, err := opt.Run(context.Background(), func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		return &trading.BacktestStatistics{TotalReturn: decimal.NewFromInt(int64(params.Period))}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, updates, 5)
+
+	for i, p := range updates {
+		assert.Equal(t, StageFull, p.Stage)
+		assert.Equal(t, i+1, p.Done)
+		assert.Equal(t, 5, p.Total)
+		require.NotNil(t, p.Best)
+		assert.GreaterOrEqual(t, p.Best.Score, p.Latest.Score)
+		assert.Equal(t, p.Improved, p.Best == p.Latest)
+	}
+	assert.Equal(t, 50, updates[4].Best.Params.Period)
+}
+
+func TestGridOptimizer_CancelStopsRemainingRuns(t *testing.T) {
+	opt := NewGridOptimizer(nil, GetDefaultParamRanges(), ObjectiveSharpe, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 第二组完成后用户中止
+	opt.SetProgressHandler(func(p Progress) {
+		if p.Done == 2 {
+			cancel()
+		}
+	})
+	var runs int32
+	_, err := opt.Run(ctx, func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		atomic.AddInt32(&runs, 1)
+		return &trading.BacktestStatistics{}, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.LessOrEqual(t, atomic.LoadInt32(&runs), int32(3))
+}
@@ -0,0 +1,468 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"tradingbot/src/strategy"
+)
+
+// Method 参数搜索算法
+type Method string
+
+const (
+	MethodGrid    Method = "grid"    // 网格搜索：回测所有组合
+	MethodGenetic Method = "genetic" // 遗传算法
+	MethodTPE     Method = "tpe"     // 树结构 Parzen 估计（简化的贝叶斯优化）
+)
+
+// ParseMethod 解析搜索算法
+func ParseMethod(s string) (Method, error) {
+	switch Method(s) {
+	case MethodGrid, MethodGenetic, MethodTPE:
+		return Method(s), nil
+	default:
+		return "", fmt.Errorf("unknown search method: %s (supported: grid, genetic, tpe)", s)
+	}
+}
+
+// SearchConfig 遗传算法和 TPE 的搜索设置
+type SearchConfig struct {
+	Budget       int     // 最多回测的参数组合数（不重复），<=0 时为 100
+	Population   int     // 遗传算法的种群大小，<=0 时为 20，至少为精英数+1
+	MutationRate float64 // 遗传算法每个参数的变异概率，<=0 时为 0.2
+	Seed         int64   // 随机种子，相同种子和回测结果时搜索路径相同
+}
+
+const (
+	defaultSearchBudget  = 100
+	defaultPopulation    = 20
+	defaultMutationRate  = 0.2
+	geneticElites        = 2    // 每代直接保留的最优个体数
+	tournamentSize       = 3    // 锦标赛选择的参赛个体数
+	tpeGamma             = 0.25 // 得分最高的该比例观测作为“好”的分布
+	tpeCandidates        = 24   // 每次建议时从“好”的分布中抽取的候选数
+	uniqueGenomeAttempts = 100  // 随机生成未回测组合的尝试次数
+)
+
+// withDefaults 填充默认值
+func (c SearchConfig) withDefaults() SearchConfig {
+	if c.Budget <= 0 {
+		c.Budget = defaultSearchBudget
+	}
+	if c.Population <= 0 {
+		c.Population = defaultPopulation
+	}
+	c.Population = max(c.Population, geneticElites+1)
+	if c.MutationRate <= 0 {
+		c.MutationRate = defaultMutationRate
+	}
+	return c
+}
+
+// Search 按 method 在参数网格上搜索，结果按得分从高到低排序：
+// grid 回测所有组合；genetic、tpe 最多回测 Budget 个不重复的组合，网格不大于 Budget 时直接回测所有组合
+func (o *GridOptimizer) Search(ctx context.Context, method Method, backtest BacktestFunc, config SearchConfig) ([]*Result, error) {
+	if method == MethodGrid {
+		return o.Run(ctx, backtest)
+	}
+
+	config = config.withDefaults()
+	space, err := o.newSearchSpace()
+	if err != nil {
+		return nil, err
+	}
+	if space.size() <= config.Budget {
+		return o.Run(ctx, backtest)
+	}
+
+	run := &searchRun{
+		optimizer: o,
+		space:     space,
+		backtest:  backtest,
+		rng:       rand.New(rand.NewSource(config.Seed)),
+		evaluated: make(map[string]*Result),
+		budget:    config.Budget,
+		progress:  o.newProgress(string(method), config.Budget),
+	}
+	switch method {
+	case MethodGenetic:
+		err = run.genetic(ctx, config)
+	case MethodTPE:
+		err = run.tpe(ctx)
+	default:
+		return nil, fmt.Errorf("unknown search method: %s", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	SortResults(run.results)
+	return run.results, nil
+}
+
+// CountCandidates 参数组合总数（不展开组合；超过 math.MaxInt32 时截断）
+func (o *GridOptimizer) CountCandidates() (int, error) {
+	space, err := o.newSearchSpace()
+	if err != nil {
+		return 0, err
+	}
+	return space.size(), nil
+}
+
+// searchSpace 参数网格：每个参数的候选取值，组合以每个参数的取值下标（基因）表示
+type searchSpace struct {
+	base   *strategy.BollingerBandsParams
+	ranges []ParamRange
+	values [][]float64
+}
+
+func (o *GridOptimizer) newSearchSpace() (*searchSpace, error) {
+	space := &searchSpace{base: o.baseParams, ranges: o.ranges}
+	for _, r := range o.ranges {
+		values, err := r.Values()
+		if err != nil {
+			return nil, err
+		}
+		space.values = append(space.values, values)
+	}
+	return space, nil
+}
+
+// size 组合总数（超过 math.MaxInt32 时截断，只用于和预算比较）
+func (s *searchSpace) size() int {
+	size := 1
+	for _, values := range s.values {
+		size *= len(values)
+		if size > math.MaxInt32 {
+			return math.MaxInt32
+		}
+	}
+	return size
+}
+
+// params 基因对应的策略参数
+func (s *searchSpace) params(genome []int) (*strategy.BollingerBandsParams, error) {
+	params := copyParams(s.base)
+	for d, index := range genome {
+		if err := applyParam(params, s.ranges[d].Name, s.values[d][index]); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// random 随机基因
+func (s *searchSpace) random(rng *rand.Rand) []int {
+	genome := make([]int, len(s.values))
+	for d, values := range s.values {
+		genome[d] = rng.Intn(len(values))
+	}
+	return genome
+}
+
+// genomeKey 基因的去重键
+func genomeKey(genome []int) string {
+	parts := make([]string, len(genome))
+	for d, index := range genome {
+		parts[d] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, ",")
+}
+
+// searchRun 一次遗传算法或 TPE 搜索，已回测的组合不再重复回测
+type searchRun struct {
+	optimizer *GridOptimizer
+	space     *searchSpace
+	backtest  BacktestFunc
+	rng       *rand.Rand
+	budget    int
+	progress  *progressTracker
+
+	evaluated map[string]*Result // 按基因去重
+	genomes   [][]int            // 已回测的基因，与 results 顺序一致
+	results   []*Result
+}
+
+// remaining 剩余的回测预算
+func (r *searchRun) remaining() int {
+	return r.budget - len(r.results)
+}
+
+// evaluate 并发回测一批基因，已回测过的跳过，超出预算的部分丢弃
+func (r *searchRun) evaluate(ctx context.Context, genomes [][]int) error {
+	var batch [][]int
+	var candidates []*strategy.BollingerBandsParams
+	for _, genome := range genomes {
+		if len(batch) >= r.remaining() {
+			break
+		}
+		if _, ok := r.evaluated[genomeKey(genome)]; ok {
+			continue
+		}
+		params, err := r.space.params(genome)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, genome)
+		candidates = append(candidates, params)
+	}
+
+	results, err := r.optimizer.runCandidates(ctx, candidates, r.backtest, r.progress)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		r.evaluated[genomeKey(batch[i])] = result
+		r.genomes = append(r.genomes, batch[i])
+		r.results = append(r.results, result)
+	}
+	return nil
+}
+
+// fitness 组合的适应度，失败或不满足约束时为负无穷
+func fitness(result *Result) float64 {
+	if result == nil || result.Err != nil {
+		return math.Inf(-1)
+	}
+	return result.Score
+}
+
+// uniqueGenome 生成一个未回测且不在 planned 中的基因（调用方保证网格大于预算，总能找到）
+func (r *searchRun) uniqueGenome(planned map[string]bool) []int {
+	for attempt := 0; attempt < uniqueGenomeAttempts; attempt++ {
+		genome := r.space.random(r.rng)
+		if !r.seen(genome, planned) {
+			return genome
+		}
+	}
+
+	// 随机尝试失败时从随机起点按顺序枚举
+	genome := r.space.random(r.rng)
+	for {
+		for d := len(genome) - 1; d >= 0; d-- {
+			genome[d]++
+			if genome[d] < len(r.space.values[d]) {
+				break
+			}
+			genome[d] = 0
+		}
+		if !r.seen(genome, planned) {
+			return genome
+		}
+	}
+}
+
+func (r *searchRun) seen(genome []int, planned map[string]bool) bool {
+	key := genomeKey(genome)
+	_, evaluated := r.evaluated[key]
+	return evaluated || planned[key]
+}
+
+// genetic 遗传算法：锦标赛选择、均匀交叉、逐参数变异（相邻取值或随机取值），每代保留最优个体
+func (r *searchRun) genetic(ctx context.Context, config SearchConfig) error {
+	planned := make(map[string]bool)
+	var population [][]int
+	for len(population) < config.Population && len(population) < r.remaining() {
+		genome := r.uniqueGenome(planned)
+		planned[genomeKey(genome)] = true
+		population = append(population, genome)
+	}
+
+	for {
+		if err := r.evaluate(ctx, population); err != nil {
+			return err
+		}
+		if r.remaining() <= 0 {
+			return nil
+		}
+
+		// 种群按适应度排序
+		ranked := append([][]int(nil), population...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return fitness(r.evaluated[genomeKey(ranked[i])]) > fitness(r.evaluated[genomeKey(ranked[j])])
+		})
+
+		// 精英直接进入下一代（已回测，不占用预算），其余为新的子代
+		elites := min(geneticElites, len(ranked))
+		next := append([][]int(nil), ranked[:elites]...)
+		planned = make(map[string]bool)
+		for len(next) < config.Population && len(next)-elites < r.remaining() {
+			child := r.crossover(r.tournament(ranked), r.tournament(ranked))
+			r.mutate(child, config.MutationRate)
+			if r.seen(child, planned) {
+				r.mutate(child, 1)
+			}
+			if r.seen(child, planned) {
+				child = r.uniqueGenome(planned)
+			}
+			planned[genomeKey(child)] = true
+			next = append(next, child)
+		}
+		population = next
+	}
+}
+
+// tournament 锦标赛选择：随机抽取若干个体，返回适应度最高的
+func (r *searchRun) tournament(ranked [][]int) []int {
+	best := r.rng.Intn(len(ranked))
+	for i := 1; i < tournamentSize; i++ {
+		// ranked 已按适应度排序，下标越小越好
+		if candidate := r.rng.Intn(len(ranked)); candidate < best {
+			best = candidate
+		}
+	}
+	return ranked[best]
+}
+
+// crossover 均匀交叉
+func (r *searchRun) crossover(a, b []int) []int {
+	child := make([]int, len(a))
+	for d := range child {
+		if r.rng.Intn(2) == 0 {
+			child[d] = a[d]
+		} else {
+			child[d] = b[d]
+		}
+	}
+	return child
+}
+
+// mutate 每个参数以 rate 概率变异：一半移动到相邻取值，一半随机取值
+func (r *searchRun) mutate(genome []int, rate float64) {
+	for d := range genome {
+		count := len(r.space.values[d])
+		if count <= 1 || r.rng.Float64() >= rate {
+			continue
+		}
+		if r.rng.Intn(2) == 0 {
+			step := 1
+			if r.rng.Intn(2) == 0 {
+				step = -1
+			}
+			genome[d] = min(max(genome[d]+step, 0), count-1)
+		} else {
+			genome[d] = r.rng.Intn(count)
+		}
+	}
+}
+
+// tpe 树结构 Parzen 估计：先随机回测一批组合，之后把观测按得分分为“好”“差”两组，
+// 每个参数分别用核密度估计 l(x)、g(x)，从 l 中抽取候选并选择 l(x)/g(x) 最大的组合；每轮建议 workers 个组合并发回测
+func (r *searchRun) tpe(ctx context.Context) error {
+	startup := min(max(r.budget/5, 5), r.budget)
+	planned := make(map[string]bool)
+	var batch [][]int
+	for len(batch) < startup {
+		genome := r.uniqueGenome(planned)
+		planned[genomeKey(genome)] = true
+		batch = append(batch, genome)
+	}
+	if err := r.evaluate(ctx, batch); err != nil {
+		return err
+	}
+
+	for r.remaining() > 0 {
+		good, bad := r.splitObservations()
+		planned = make(map[string]bool)
+		batch = batch[:0]
+		for len(batch) < min(r.optimizer.workers, r.remaining()) {
+			genome := r.suggest(good, bad, planned)
+			planned[genomeKey(genome)] = true
+			batch = append(batch, genome)
+		}
+		if err := r.evaluate(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitObservations 按适应度把已回测的组合分为得分最高的 tpeGamma 部分（至少一个）和其余部分
+func (r *searchRun) splitObservations() ([][]int, [][]int) {
+	ranked := append([][]int(nil), r.genomes...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return fitness(r.evaluated[genomeKey(ranked[i])]) > fitness(r.evaluated[genomeKey(ranked[j])])
+	})
+
+	goodCount := max(int(math.Ceil(tpeGamma*float64(len(ranked)))), 1)
+	// 失败的组合不计入“好”的分布
+	for goodCount > 1 && math.IsInf(fitness(r.evaluated[genomeKey(ranked[goodCount-1])]), -1) {
+		goodCount--
+	}
+	return ranked[:goodCount], ranked[goodCount:]
+}
+
+// suggest 从“好”的分布中抽取候选，返回 l(x)/g(x) 最大且未回测的组合
+func (r *searchRun) suggest(good, bad [][]int, planned map[string]bool) []int {
+	goodDensity := make([][]float64, len(r.space.values))
+	badDensity := make([][]float64, len(r.space.values))
+	for d := range r.space.values {
+		goodDensity[d] = r.density(d, good)
+		badDensity[d] = r.density(d, bad)
+	}
+
+	var best []int
+	bestScore := math.Inf(-1)
+	for i := 0; i < tpeCandidates; i++ {
+		genome := make([]int, len(r.space.values))
+		score := 0.0
+		for d := range genome {
+			genome[d] = sampleIndex(r.rng, goodDensity[d])
+			score += math.Log(goodDensity[d][genome[d]]) - math.Log(badDensity[d][genome[d]])
+		}
+		if score > bestScore && !r.seen(genome, planned) {
+			best, bestScore = genome, score
+		}
+	}
+	if best == nil {
+		return r.uniqueGenome(planned)
+	}
+	return best
+}
+
+// density 参数 d 的取值下标上的核密度（高斯核，带宽为取值个数的 1/5，至少为1），加一份均匀先验避免为零
+func (r *searchRun) density(d int, observations [][]int) []float64 {
+	count := len(r.space.values[d])
+	bandwidth := math.Max(float64(count)/5, 1)
+
+	density := make([]float64, count)
+	for k := range density {
+		density[k] = 1 / float64(count)
+	}
+	for _, genome := range observations {
+		weights := make([]float64, count)
+		total := 0.0
+		for k := range weights {
+			distance := float64(k-genome[d]) / bandwidth
+			weights[k] = math.Exp(-0.5 * distance * distance)
+			total += weights[k]
+		}
+		for k := range density {
+			density[k] += weights[k] / total
+		}
+	}
+
+	total := float64(len(observations) + 1)
+	for k := range density {
+		density[k] /= total
+	}
+	return density
+}
+
+// sampleIndex 按概率分布抽取下标
+func sampleIndex(rng *rand.Rand, distribution []float64) int {
+	x := rng.Float64()
+	for k, p := range distribution {
+		x -= p
+		if x < 0 {
+			return k
+		}
+	}
+	return len(distribution) - 1
+}
@@ -0,0 +1,166 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchTestRanges 51×51×20 的网格，远大于搜索预算
+func searchTestRanges() []ParamRange {
+	return []ParamRange{
+		{Name: "period", Min: 10, Max: 60, Step: 1},
+		{Name: "multiplier", Min: 1, Max: 3.5, Step: 0.05},
+		{Name: "stop_loss", Min: 0.01, Max: 0.2, Step: 0.01},
+	}
+}
+
+// searchTestBacktest 收益率在 period=35, multiplier=2.25, stop_loss=0.05 处最高，并记录每组参数的回测次数
+func searchTestBacktest(runs map[string]int, mu *sync.Mutex) BacktestFunc {
+	return func(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+		mu.Lock()
+		runs[fmt.Sprintf("%d/%.2f/%.2f", params.Period, params.Multiplier, params.StopLossPercent)]++
+		mu.Unlock()
+
+		period := float64(params.Period-35) / 25
+		multiplier := (params.Multiplier - 2.25) / 1.25
+		stopLoss := (params.StopLossPercent - 0.05) / 0.1
+		score := 1 - period*period - multiplier*multiplier - stopLoss*stopLoss
+		return &trading.BacktestStatistics{
+			TotalReturn:        decimal.NewFromFloat(score),
+			MaxDrawdownPercent: decimal.NewFromFloat(float64(params.Period) / 2), // period>=40 时回撤超过20%
+		}, nil
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod("tpe")
+	require.NoError(t, err)
+	assert.Equal(t, MethodTPE, method)
+
+	_, err = ParseMethod("annealing")
+	assert.Error(t, err)
+}
+
+func TestGridOptimizer_SearchFindsOptimum(t *testing.T) {
+	for _, method := range []Method{MethodGenetic, MethodTPE} {
+		t.Run(string(method), func(t *testing.T) {
+			opt := NewGridOptimizer(nil, searchTestRanges(), ObjectiveTotalReturn, 4)
+			runs := make(map[string]int)
+			var mu sync.Mutex
+
+			results, err := opt.Search(context.Background(), method, searchTestBacktest(runs, &mu), SearchConfig{Budget: 300, Seed: 7})
+			require.NoError(t, err)
+			assert.Len(t, results, 300)
+			assert.Len(t, runs, 300) // 不重复回测
+			for key, count := range runs {
+				assert.Equal(t, 1, count, key)
+			}
+
+			best := results[0]
+			require.NoError(t, best.Err)
+			assert.Greater(t, best.Score, 0.9)
+			assert.InDelta(t, 35, best.Params.Period, 6)
+			assert.InDelta(t, 2.25, best.Params.Multiplier, 0.4)
+		})
+	}
+}
+
+func TestGridOptimizer_SearchReproducibleWithSeed(t *testing.T) {
+	for _, method := range []Method{MethodGenetic, MethodTPE} {
+		var bests []*strategy.BollingerBandsParams
+		for i := 0; i < 2; i++ {
+			opt := NewGridOptimizer(nil, searchTestRanges(), ObjectiveTotalReturn, 3)
+			results, err := opt.Search(context.Background(), method, searchTestBacktest(make(map[string]int), &sync.Mutex{}), SearchConfig{Budget: 60, Seed: 42})
+			require.NoError(t, err)
+			bests = append(bests, results[0].Params)
+		}
+		assert.Equal(t, bests[0], bests[1], method)
+	}
+}
+
+func TestGridOptimizer_SearchSmallGridRunsAll(t *testing.T) {
+	ranges := []ParamRange{{Name: "period", Min: 10, Max: 30, Step: 10}}
+	opt := NewGridOptimizer(nil, ranges, ObjectiveTotalReturn, 2)
+	runs := make(map[string]int)
+
+	results, err := opt.Search(context.Background(), MethodGenetic, searchTestBacktest(runs, &sync.Mutex{}), SearchConfig{Budget: 10})
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Len(t, runs, 3)
+}
+
+func TestGridOptimizer_SearchWithConstraints(t *testing.T) {
+	constraints, err := ParseConstraints("max_drawdown<20")
+	require.NoError(t, err)
+
+	opt := NewGridOptimizer(nil, searchTestRanges(), ObjectiveTotalReturn, 4)
+	opt.SetConstraints(constraints)
+	results, err := opt.Search(context.Background(), MethodTPE, searchTestBacktest(make(map[string]int), &sync.Mutex{}), SearchConfig{Budget: 200, Seed: 1})
+	require.NoError(t, err)
+
+	// 最优组合满足回测最大回撤 < 20%（period < 40）
+	best := results[0]
+	require.NoError(t, best.Err)
+	assert.Less(t, best.Params.Period, 40)
+	for _, r := range results {
+		if r.Params.Period >= 40 {
+			assert.ErrorContains(t, r.Err, "max_drawdown<20")
+		}
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	constraints, err := ParseConstraints("max_drawdown<20, trades>=10,win_rate>45.5")
+	require.NoError(t, err)
+	assert.Equal(t, []Constraint{
+		{Metric: "max_drawdown", Op: "<", Value: 20},
+		{Metric: "trades", Op: ">=", Value: 10},
+		{Metric: "win_rate", Op: ">", Value: 45.5},
+	}, constraints)
+	assert.Equal(t, "trades>=10", constraints[1].String())
+
+	for _, invalid := range []string{"max_drawdown=20", "drawdown<20", "trades>=ten", "<20"} {
+		_, err := ParseConstraints(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	stats := &trading.BacktestStatistics{
+		MaxDrawdownPercent: decimal.NewFromFloat(18.5),
+		TotalReturn:        decimal.NewFromFloat(0.12),
+		TotalTrades:        8,
+		WinningTrades:      6,
+	}
+
+	assert.NoError(t, Constraint{Metric: "max_drawdown", Op: "<", Value: 20}.Check(stats))
+	assert.NoError(t, Constraint{Metric: "return", Op: ">=", Value: 12}.Check(stats))
+	assert.NoError(t, Constraint{Metric: "win_rate", Op: ">", Value: 70}.Check(stats))
+	assert.ErrorContains(t, Constraint{Metric: "trades", Op: ">=", Value: 10}.Check(stats), "trades>=10 violated")
+	assert.Error(t, Constraint{Metric: "max_drawdown", Op: "<=", Value: 18}.Check(stats))
+}
+
+func TestRegisterObjective(t *testing.T) {
+	RegisterObjective("test_trades", func(stats *trading.BacktestStatistics) float64 {
+		return float64(stats.TotalTrades)
+	})
+
+	objective, err := ParseObjective("test_trades")
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, objective.Score(&trading.BacktestStatistics{TotalTrades: 12}))
+
+	score := ObjectiveReturnDrawdown.Score(&trading.BacktestStatistics{TotalReturn: decimal.NewFromFloat(0.3), MaxDrawdownPercent: decimal.NewFromInt(15)})
+	assert.InDelta(t, 2.0, score, 1e-9)
+	// 回撤不足1%时按1%计
+	score = ObjectiveReturnDrawdown.Score(&trading.BacktestStatistics{TotalReturn: decimal.NewFromFloat(0.05)})
+	assert.InDelta(t, 5.0, score, 1e-9)
+}
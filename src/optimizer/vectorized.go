@@ -0,0 +1,416 @@
+package optimizer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/shopspring/decimal"
+)
+
+// VectorizedConfig 向量化回测的账户设置
+type VectorizedConfig struct {
+	InitialCapital      float64
+	PositionSizePercent float64 // 开仓使用现金的比例
+	MinTradeAmount      float64 // 开仓金额低于该值时不买入
+	FeeRate             float64 // 按收盘价成交的手续费率（吃单）
+	Timeframe           timeframes.Timeframe
+}
+
+// VectorizedBacktester 布林道策略的向量化快速回测，用于参数扫描的粗筛：
+// 指标数组按周期预先计算并在参数组合间共享，信号在K线收盘价立即成交，不模拟挂单、滑点和成交模型
+type VectorizedBacktester struct {
+	klines []*cex.KlineData
+	config VectorizedConfig
+
+	closes  []float64
+	highs   []float64
+	lows    []float64
+	volumes []float64
+
+	mu    sync.Mutex
+	bands map[int]*rollingBands // 按布林道周期缓存
+	atrs  map[int][]float64     // 按 ATR 周期缓存
+}
+
+// rollingBands 每根K线的中轨和标准差（数据不足的位置为 NaN）
+type rollingBands struct {
+	middle []float64
+	stdDev []float64
+}
+
+// NewVectorizedBacktester 创建向量化回测（K线只读，可并发调用 Backtest）
+func NewVectorizedBacktester(klines []*cex.KlineData, config VectorizedConfig) (*VectorizedBacktester, error) {
+	if config.InitialCapital <= 0 {
+		return nil, fmt.Errorf("initial capital must be positive, got %f", config.InitialCapital)
+	}
+	if config.PositionSizePercent <= 0 || config.PositionSizePercent > 1 {
+		return nil, fmt.Errorf("position size percent must be between 0 and 1, got %f", config.PositionSizePercent)
+	}
+	if config.FeeRate < 0 || config.FeeRate >= 1 {
+		return nil, fmt.Errorf("fee rate must be in [0, 1), got %f", config.FeeRate)
+	}
+
+	b := &VectorizedBacktester{
+		klines:  klines,
+		config:  config,
+		closes:  make([]float64, len(klines)),
+		highs:   make([]float64, len(klines)),
+		lows:    make([]float64, len(klines)),
+		volumes: make([]float64, len(klines)),
+		bands:   make(map[int]*rollingBands),
+		atrs:    make(map[int][]float64),
+	}
+	for i, kline := range klines {
+		b.closes[i] = kline.Close.InexactFloat64()
+		b.highs[i] = kline.High.InexactFloat64()
+		b.lows[i] = kline.Low.InexactFloat64()
+		b.volumes[i] = kline.Volume.InexactFloat64()
+	}
+	return b, nil
+}
+
+// rollingBands 获取（必要时计算）给定周期的中轨和标准差
+func (b *VectorizedBacktester) rollingBands(period int) *rollingBands {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bands, ok := b.bands[period]; ok {
+		return bands
+	}
+
+	bands := &rollingBands{middle: make([]float64, len(b.closes)), stdDev: make([]float64, len(b.closes))}
+	sum := 0.0
+	for i, price := range b.closes {
+		sum += price
+		if i >= period {
+			sum -= b.closes[i-period]
+		}
+		if i < period-1 {
+			bands.middle[i], bands.stdDev[i] = math.NaN(), math.NaN()
+			continue
+		}
+
+		// 与布林道指标一致：总体标准差
+		mean := sum / float64(period)
+		variance := 0.0
+		for _, p := range b.closes[i-period+1 : i+1] {
+			variance += (p - mean) * (p - mean)
+		}
+		bands.middle[i] = mean
+		bands.stdDev[i] = math.Sqrt(variance / float64(period))
+	}
+	b.bands[period] = bands
+	return bands
+}
+
+// atr 获取（必要时计算）给定周期的 Wilder ATR（数据不足的位置为 0）
+func (b *VectorizedBacktester) atr(period int) []float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if atr, ok := b.atrs[period]; ok {
+		return atr
+	}
+
+	atr := make([]float64, len(b.closes))
+	value := 0.0
+	for i := 1; i < len(b.closes); i++ {
+		prevClose := b.closes[i-1]
+		trueRange := math.Max(b.highs[i]-b.lows[i], math.Max(math.Abs(b.highs[i]-prevClose), math.Abs(b.lows[i]-prevClose)))
+		switch {
+		case i < period:
+			value += trueRange
+		case i == period:
+			value = (value + trueRange) / float64(period)
+			atr[i] = value
+		default:
+			value = (value*float64(period-1) + trueRange) / float64(period)
+			atr[i] = value
+		}
+	}
+	b.atrs[period] = atr
+	return atr
+}
+
+// volumeConfirmed 当前成交量是否超过前 period 根K线平均成交量的 multiplier 倍
+func (b *VectorizedBacktester) volumeConfirmed(i, period int, multiplier float64) bool {
+	if i < period {
+		return false
+	}
+	sum := 0.0
+	for _, volume := range b.volumes[i-period : i] {
+		sum += volume
+	}
+	average := sum / float64(period)
+	return average > 0 && b.volumes[i]/average > multiplier
+}
+
+// Backtest 使用给定参数运行一次快速回测，签名与 BacktestFunc 一致
+// 统计只包含排名需要的字段：收益率、夏普比率、盈利因子、最大回撤和交易次数
+func (b *VectorizedBacktester) Backtest(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	bands := b.rollingBands(params.Period)
+	var atr []float64
+	if params.ATRPeriod > 0 && (params.ATRStopMultiple > 0 || params.ATRTakeProfitMultiple > 0) {
+		atr = b.atr(params.ATRPeriod)
+	}
+	// 卖出策略无效时与策略一致，退回基础止盈
+	var sellStrategy strategy.SellStrategy
+	if created, err := strategy.CreateSellStrategyWithParams(params.SellStrategyName, params.SellStrategyParams); err == nil {
+		sellStrategy = created
+	}
+	observer, _ := sellStrategy.(strategy.KlineObserver)
+
+	run := &vectorizedRun{
+		config:       b.config,
+		params:       params,
+		cash:         b.config.InitialCapital,
+		peak:         b.config.InitialCapital,
+		prevEquity:   b.config.InitialCapital,
+		lastTradeBar: -1,
+	}
+
+	for i, kline := range b.klines {
+		if observer != nil {
+			observer.Observe(kline)
+		}
+		if math.IsNaN(bands.middle[i]) { // 数据不足
+			run.mark(b.closes[i])
+			continue
+		}
+
+		price := b.closes[i]
+		if run.inTrade && price > run.highest {
+			run.highest = price
+		}
+
+		// 止损止盈（冷却期内同样检查），触发后本根K线不再开仓
+		if run.position > 0 && run.entryPrice > 0 {
+			if fraction, ok := run.exitSignal(kline, i, sellStrategy); ok {
+				run.sell(price, fraction)
+				run.resetTrade(i, sellStrategy)
+				run.mark(price)
+				continue
+			}
+		}
+
+		inCooldown := run.lastTradeBar >= 0 && i-run.lastTradeBar < params.CooldownBars
+		lower := bands.middle[i] - params.Multiplier*bands.stdDev[i]
+		if !inCooldown && run.position == 0 && price <= lower &&
+			b.bandWidthAllowed(params, bands.middle[i], bands.stdDev[i]) &&
+			(params.VolumeMultiplier <= 0 || params.VolumePeriod <= 0 || b.volumeConfirmed(i, params.VolumePeriod, params.VolumeMultiplier)) {
+			run.buy(price)
+			run.lastTradeBar = i
+			run.entryPrice, run.entryTime, run.highest, run.inTrade = price, kline.CloseTime, price, true
+			run.entryATR = 0
+			if atr != nil {
+				run.entryATR = atr[i]
+			}
+		}
+		run.mark(price)
+	}
+
+	return run.statistics(b.config.Timeframe), nil
+}
+
+// bandWidthAllowed 带宽是否在 [MinBandWidth, MaxBandWidth] 内（0 表示不限制）
+func (b *VectorizedBacktester) bandWidthAllowed(params *strategy.BollingerBandsParams, middle, stdDev float64) bool {
+	if middle == 0 {
+		return true
+	}
+	width := 2 * params.Multiplier * stdDev / middle
+	if params.MinBandWidth > 0 && width < params.MinBandWidth {
+		return false
+	}
+	return params.MaxBandWidth <= 0 || width <= params.MaxBandWidth
+}
+
+// vectorizedRun 单次快速回测的账户和持仓状态
+type vectorizedRun struct {
+	config VectorizedConfig
+	params *strategy.BollingerBandsParams
+
+	cash      float64
+	position  float64
+	costBasis float64 // 持仓成本（含买入手续费）
+
+	// 策略的持仓跟踪，与布林道策略相同：买入信号后即开始跟踪，任何卖出信号后重置
+	inTrade      bool
+	entryPrice   float64
+	entryTime    time.Time
+	entryATR     float64
+	highest      float64
+	lastTradeBar int
+
+	trades, winning, losing int
+	grossProfit, grossLoss  float64
+
+	// 逐根K线按收盘价估值的资金曲线统计
+	prevEquity         float64
+	equity             float64
+	peak               float64
+	maxDrawdown        float64
+	maxDrawdownPercent float64
+	count              int
+	mean, m2           float64
+	hasPrevValue       bool
+}
+
+// exitSignal 按收盘价检查止损、ATR 止盈和卖出策略，返回卖出比例
+func (r *vectorizedRun) exitSignal(kline *cex.KlineData, i int, sellStrategy strategy.SellStrategy) (float64, bool) {
+	price := kline.Close.InexactFloat64()
+	pnlPercent := (price - r.entryPrice) / r.entryPrice
+
+	stopLoss := -r.params.StopLossPercent
+	if r.params.ATRStopMultiple > 0 && r.entryATR > 0 {
+		stopLoss = -r.entryATR * r.params.ATRStopMultiple / r.entryPrice
+	}
+	if pnlPercent <= stopLoss {
+		return 1, true
+	}
+	if r.params.ATRTakeProfitMultiple > 0 && r.entryATR > 0 && price >= r.entryPrice+r.entryATR*r.params.ATRTakeProfitMultiple {
+		return 1, true
+	}
+	// 不模拟挂单：OCO 和移动止损按收盘价判断
+	if r.params.OCO && pnlPercent >= r.params.TakeProfitPercent {
+		return 1, true
+	}
+	if r.params.TrailingStop > 0 && price <= r.highest*(1-r.params.TrailingStop) {
+		return 1, true
+	}
+	if r.params.OCO {
+		return 0, false
+	}
+
+	if sellStrategy == nil {
+		return 1, pnlPercent >= r.params.TakeProfitPercent
+	}
+	tradeInfo := &strategy.TradeInfo{
+		EntryPrice:   decimal.NewFromFloat(r.entryPrice),
+		CurrentPrice: kline.Close,
+		CurrentPnL:   decimal.NewFromFloat(pnlPercent),
+		HighestPrice: decimal.NewFromFloat(r.highest),
+		EntryTime:    r.entryTime,
+		HoldingBars:  i - r.lastTradeBar,
+		HoldingDays:  int(kline.CloseTime.Sub(r.entryTime).Hours() / 24),
+	}
+	signal := sellStrategy.ShouldSell(kline, tradeInfo)
+	if !signal.ShouldSell {
+		return 0, false
+	}
+	// 与引擎一致：强度在 (0, 1] 内时按比例部分卖出，否则全部卖出
+	if signal.Strength <= 0 || signal.Strength > 1 {
+		return 1, true
+	}
+	return signal.Strength, true
+}
+
+// buy 按收盘价买入（含手续费），金额低于最小交易额时跳过
+func (r *vectorizedRun) buy(price float64) {
+	amount := r.cash * r.config.PositionSizePercent
+	if amount < r.config.MinTradeAmount || price <= 0 {
+		return
+	}
+	notional := math.Min(amount, r.cash/(1+r.config.FeeRate))
+	r.cash -= notional * (1 + r.config.FeeRate)
+	r.position += notional / price
+	r.costBasis += notional * (1 + r.config.FeeRate)
+}
+
+// sell 按收盘价卖出持仓的 fraction 部分并记录交易盈亏
+func (r *vectorizedRun) sell(price, fraction float64) {
+	quantity := r.position * fraction
+	proceeds := quantity * price * (1 - r.config.FeeRate)
+	cost := r.costBasis * fraction
+	r.cash += proceeds
+	r.position -= quantity
+	r.costBasis -= cost
+
+	pnl := proceeds - cost
+	r.trades++
+	if pnl > 0 {
+		r.winning++
+		r.grossProfit += pnl
+	} else if pnl < 0 {
+		r.losing++
+		r.grossLoss -= pnl
+	}
+}
+
+// resetTrade 卖出信号后重置持仓跟踪并开始冷却
+func (r *vectorizedRun) resetTrade(i int, sellStrategy strategy.SellStrategy) {
+	r.lastTradeBar = i
+	r.inTrade = false
+	r.entryPrice, r.entryATR, r.highest = 0, 0, 0
+	r.entryTime = time.Time{}
+	if sellStrategy != nil {
+		sellStrategy.Reset()
+	}
+}
+
+// mark 按收盘价估值，累计收益率的均值、方差（Welford）和最大回撤
+func (r *vectorizedRun) mark(price float64) {
+	r.equity = r.cash + r.position*price
+	if r.hasPrevValue && r.prevEquity > 0 {
+		ret := r.equity/r.prevEquity - 1
+		r.count++
+		delta := ret - r.mean
+		r.mean += delta / float64(r.count)
+		r.m2 += delta * (ret - r.mean)
+	}
+	r.prevEquity, r.hasPrevValue = r.equity, true
+
+	if r.equity > r.peak {
+		r.peak = r.equity
+	}
+	if drawdown := r.peak - r.equity; drawdown > r.maxDrawdown {
+		r.maxDrawdown = drawdown
+		r.maxDrawdownPercent = drawdown / r.peak * 100
+	}
+}
+
+// statistics 生成用于排名的回测统计
+func (r *vectorizedRun) statistics(timeframe timeframes.Timeframe) *trading.BacktestStatistics {
+	if !r.hasPrevValue {
+		r.equity = r.cash
+	}
+
+	sharpe := 0.0
+	if duration, err := timeframe.GetDuration(); err == nil && duration > 0 && r.count >= 2 {
+		if stdDev := math.Sqrt(r.m2 / float64(r.count-1)); stdDev > 0 {
+			sharpe = r.mean / stdDev * math.Sqrt(float64(365*24*time.Hour)/float64(duration))
+		}
+	}
+	profitFactor := 0.0
+	if r.grossProfit > 0 && r.grossLoss > 0 {
+		profitFactor = r.grossProfit / r.grossLoss
+	}
+
+	initialCapital := decimal.NewFromFloat(r.config.InitialCapital)
+	return &trading.BacktestStatistics{
+		StrategyName:       "Bollinger Bands Strategy (vectorized)",
+		InitialCapital:     initialCapital,
+		FinalPortfolio:     decimal.NewFromFloat(r.equity),
+		TotalReturn:        decimal.NewFromFloat(r.equity/r.config.InitialCapital - 1),
+		TotalTrades:        r.trades,
+		WinningTrades:      r.winning,
+		LosingTrades:       r.losing,
+		ProfitFactor:       decimal.NewFromFloat(profitFactor),
+		MaxDrawdown:        decimal.NewFromFloat(r.maxDrawdown),
+		MaxDrawdownPercent: decimal.NewFromFloat(r.maxDrawdownPercent),
+		PeakPortfolioValue: decimal.NewFromFloat(r.peak),
+		SharpeRatio:        decimal.NewFromFloat(sharpe),
+	}
+}
@@ -0,0 +1,139 @@
+package optimizer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/indicators"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vectorizedTestKlines 20根在 100/101 之间震荡的K线，之后依次为 prices
+func vectorizedTestKlines(prices ...float64) []*cex.KlineData {
+	var closes []float64
+	for i := 0; i < 20; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	closes = append(closes, prices...)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]*cex.KlineData, len(closes))
+	for i, price := range closes {
+		p := decimal.NewFromFloat(price)
+		klines[i] = &cex.KlineData{
+			OpenTime:  start.Add(time.Duration(i) * time.Hour),
+			CloseTime: start.Add(time.Duration(i+1)*time.Hour - time.Millisecond),
+			Open:      p,
+			High:      p,
+			Low:       p,
+			Close:     p,
+			Volume:    decimal.NewFromInt(1000),
+		}
+	}
+	return klines
+}
+
+func vectorizedTestParams() *strategy.BollingerBandsParams {
+	params := strategy.GetDefaultBollingerBandsParams()
+	params.SellStrategyName = "fixed"
+	params.SellStrategyParams = map[string]float64{"take_profit": 0.2}
+	params.CooldownBars = 0
+	return params
+}
+
+func newTestVectorizedBacktester(t *testing.T, klines []*cex.KlineData, feeRate float64) *VectorizedBacktester {
+	backtester, err := NewVectorizedBacktester(klines, VectorizedConfig{
+		InitialCapital:      1000,
+		PositionSizePercent: 1,
+		MinTradeAmount:      10,
+		FeeRate:             feeRate,
+		Timeframe:           timeframes.Timeframe1h,
+	})
+	require.NoError(t, err)
+	return backtester
+}
+
+func TestVectorizedBacktester_RollingBandsMatchIndicator(t *testing.T) {
+	klines := vectorizedTestKlines(97, 95, 99, 103, 104)
+	backtester := newTestVectorizedBacktester(t, klines, 0)
+	bands := backtester.rollingBands(20)
+
+	var prices []decimal.Decimal
+	for i, kline := range klines {
+		prices = append(prices, kline.Close)
+		if i < 19 {
+			assert.True(t, math.IsNaN(bands.middle[i]), "bar %d should not have bands", i)
+			continue
+		}
+		expected, err := indicators.NewBollingerBands(20, 2).Calculate(prices)
+		require.NoError(t, err)
+		lower := bands.middle[i] - 2*bands.stdDev[i]
+		assert.InDelta(t, expected.MiddleBand.InexactFloat64(), bands.middle[i], 1e-9)
+		assert.InDelta(t, expected.LowerBand.InexactFloat64(), lower, 1e-6)
+	}
+}
+
+func TestVectorizedBacktester_TakeProfitAtClose(t *testing.T) {
+	// 跌破下轨时按收盘价 90 买入，涨到 109（超过 +20%）时按收盘价止盈
+	klines := vectorizedTestKlines(90, 100, 109, 110)
+	stats, err := newTestVectorizedBacktester(t, klines, 0.001).Backtest(context.Background(), vectorizedTestParams())
+	require.NoError(t, err)
+
+	quantity := 1000 / 1.001 / 90
+	final := quantity * 109 * 0.999
+	assert.Equal(t, 1, stats.TotalTrades)
+	assert.Equal(t, 1, stats.WinningTrades)
+	assert.InDelta(t, final, stats.FinalPortfolio.InexactFloat64(), 1e-6)
+	assert.InDelta(t, final/1000-1, stats.TotalReturn.InexactFloat64(), 1e-9)
+	assert.True(t, stats.SharpeRatio.IsPositive())
+	assert.InDelta(t, (1-1/1.001)*100, stats.MaxDrawdownPercent.InexactFloat64(), 1e-6) // 买入手续费
+}
+
+func TestVectorizedBacktester_StopLossAndOpenPosition(t *testing.T) {
+	// 90 买入后跌到 80 止损，再跌破下轨于 70 买入，持仓到结束按最后收盘价估值
+	klines := vectorizedTestKlines(90, 80, 70, 72)
+	params := vectorizedTestParams()
+	params.StopLossPercent = 0.1
+
+	stats, err := newTestVectorizedBacktester(t, klines, 0).Backtest(context.Background(), params)
+	require.NoError(t, err)
+
+	afterStop := 1000.0 / 90 * 80
+	final := afterStop / 70 * 72
+	assert.Equal(t, 1, stats.TotalTrades)
+	assert.Equal(t, 1, stats.LosingTrades)
+	assert.True(t, stats.ProfitFactor.IsZero()) // 没有盈利交易
+	assert.InDelta(t, final, stats.FinalPortfolio.InexactFloat64(), 1e-6)
+	assert.InDelta(t, (1000-afterStop)/1000*100, stats.MaxDrawdownPercent.InexactFloat64(), 1e-6)
+}
+
+func TestVectorizedBacktester_Cooldown(t *testing.T) {
+	// 止损后冷却期内不再买入
+	klines := vectorizedTestKlines(90, 80, 70, 72)
+	params := vectorizedTestParams()
+	params.StopLossPercent = 0.1
+	params.CooldownBars = 3
+
+	stats, err := newTestVectorizedBacktester(t, klines, 0).Backtest(context.Background(), params)
+	require.NoError(t, err)
+	assert.InDelta(t, 1000.0/90*80, stats.FinalPortfolio.InexactFloat64(), 1e-6)
+}
+
+func TestVectorizedBacktester_InvalidParams(t *testing.T) {
+	params := vectorizedTestParams()
+	params.Multiplier = 0
+
+	_, err := newTestVectorizedBacktester(t, vectorizedTestKlines(90), 0).Backtest(context.Background(), params)
+	assert.Error(t, err)
+
+	_, err = NewVectorizedBacktester(nil, VectorizedConfig{InitialCapital: 1000, PositionSizePercent: 1.5})
+	assert.Error(t, err)
+}
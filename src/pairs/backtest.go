@@ -0,0 +1,163 @@
+package pairs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"tradingbot/src/timeframes"
+)
+
+// Config 配对交易回测配置
+type Config struct {
+	SymbolA        string
+	SymbolB        string
+	InitialCapital float64
+	FeeRate        float64 // 单边手续费率
+	BorrowRate     float64 // 空头年化借币利率
+	Timeframe      timeframes.Timeframe
+	StartTime      time.Time // 早于该时间的K线只用于计算价差，不交易
+}
+
+// EquityPoint 配对回测的逐K线组合价值
+type EquityPoint struct {
+	Time   time.Time `json:"time"` // K线收盘时间
+	Value  float64   `json:"value"`
+	ZScore float64   `json:"z_score"`
+}
+
+// Result 配对交易回测结果
+type Result struct {
+	Config Config
+	Params *Params
+
+	Trades      []Trade
+	Equity      []EquityPoint
+	OpenLegs    [2]Leg    // 回测结束时仍持有的两条腿（按最后收盘价计入 FinalValue）
+	OpenSince   time.Time // 未平仓持仓的开仓时间
+	Bars        int       // 交易区间内对齐的K线数
+	SkippedBars [2]int    // 两个交易对各自因另一边缺失而跳过的K线数
+
+	FinalValue         float64
+	TotalReturn        float64
+	MaxDrawdownPercent float64
+	SharpeRatio        float64 // 年化夏普比率（无风险利率为0）
+	WinningTrades      int
+	Fees               float64
+	BorrowCost         float64
+}
+
+// Backtest 在对齐的两个数据源上回测配对交易：逐根K线计算价差 z-score，两条腿按收盘价同时开平仓
+// 回测结束时未平仓的持仓按最后收盘价估值
+func Backtest(ctx context.Context, feed *SyncFeed, params *Params, config Config) (*Result, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pairs parameters: %w", err)
+	}
+	if config.InitialCapital <= 0 {
+		return nil, fmt.Errorf("initial capital must be positive, got %f", config.InitialCapital)
+	}
+	duration, err := config.Timeframe.GetDuration()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	result := &Result{Config: config, Params: params}
+	model := newSpreadModel(params)
+	executor := NewExecutor(config.SymbolA, config.SymbolB, config.InitialCapital, config.FeeRate, config.BorrowRate)
+
+	heldBars := 0
+	peak := config.InitialCapital
+	prevValue := config.InitialCapital
+	var returns []float64
+	var last *Bar
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		bar, err := feed.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if bar == nil {
+			break
+		}
+
+		state := model.add(bar.A.Close.InexactFloat64(), bar.B.Close.InexactFloat64())
+		if bar.Time.Before(config.StartTime) {
+			continue
+		}
+		result.Bars++
+		last = bar
+
+		if executor.Direction() != DirectionFlat {
+			executor.Accrue(bar, duration)
+			heldBars++
+		}
+		open, exitReason := decide(params, state, executor.Direction(), heldBars)
+		if exitReason != "" {
+			trade, err := executor.Close(bar, state.ZScore, exitReason)
+			if err != nil {
+				return nil, err
+			}
+			result.Trades = append(result.Trades, *trade)
+			if trade.PnL > 0 {
+				result.WinningTrades++
+			}
+		} else if open != DirectionFlat {
+			// 两条腿不能同时开仓时（如 β 过大导致敞口超限）跳过该信号
+			if err := executor.Open(open, bar, state.ZScore, state.Hedge, params.PositionSizePercent); err == nil {
+				heldBars = 0
+			}
+		}
+
+		value := executor.Equity(bar.A.Close.InexactFloat64(), bar.B.Close.InexactFloat64())
+		result.Equity = append(result.Equity, EquityPoint{Time: bar.A.CloseTime, Value: value, ZScore: state.ZScore})
+		if prevValue > 0 {
+			returns = append(returns, value/prevValue-1)
+		}
+		prevValue = value
+		if value > peak {
+			peak = value
+		} else if drawdown := (peak - value) / peak * 100; drawdown > result.MaxDrawdownPercent {
+			result.MaxDrawdownPercent = drawdown
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("no aligned bars for %s and %s after %s", config.SymbolA, config.SymbolB, config.StartTime.Format("2006-01-02 15:04"))
+	}
+	result.SkippedBars[0], result.SkippedBars[1] = feed.Skipped()
+	result.FinalValue = executor.Equity(last.A.Close.InexactFloat64(), last.B.Close.InexactFloat64())
+	result.TotalReturn = result.FinalValue/config.InitialCapital - 1
+	result.Fees = executor.Fees()
+	result.BorrowCost = executor.BorrowCost()
+	if executor.Direction() != DirectionFlat {
+		result.OpenLegs = executor.Legs()
+		result.OpenSince = executor.entryTime
+	}
+	result.SharpeRatio = annualizedSharpe(returns, duration)
+	return result, nil
+}
+
+// annualizedSharpe 逐K线收益率的年化夏普比率
+func annualizedSharpe(returns []float64, duration time.Duration) float64 {
+	if len(returns) < 2 || duration <= 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	if stdDev == 0 {
+		return 0
+	}
+	return mean / stdDev * math.Sqrt(float64(365*24*time.Hour)/float64(duration))
+}
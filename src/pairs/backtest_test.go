@@ -0,0 +1,176 @@
+package pairs
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"tradingbot/src/timeframes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pairsTestPrices B 围绕 40000 波动，A/B 比价围绕 0.05 上下 0.5% 交替，ratioShocks 指定某些K线的比价偏离
+func pairsTestPrices(n int, ratioShocks map[int]float64) (closesA, closesB []float64) {
+	for i := 0; i < n; i++ {
+		b := 40000 * (1 + 0.01*math.Sin(float64(i)))
+		ratio := 0.05 * (1 + 0.005*float64(1-2*(i%2)))
+		if shock, ok := ratioShocks[i]; ok {
+			ratio = 0.05 * (1 + shock)
+		}
+		closesA = append(closesA, b*ratio)
+		closesB = append(closesB, b)
+	}
+	return closesA, closesB
+}
+
+func pairsTestParams() *Params {
+	params := GetDefaultParams()
+	params.Lookback = 10
+	return params
+}
+
+func pairsTestConfig() Config {
+	return Config{
+		SymbolA:        "ETH/USDT",
+		SymbolB:        "BTC/USDT",
+		InitialCapital: 10000,
+		FeeRate:        0.001,
+		Timeframe:      timeframes.Timeframe1h,
+	}
+}
+
+func TestBacktest_EntersOnDeviationAndExitsOnReversion(t *testing.T) {
+	// 第 12 根K线 A 相对 B 便宜 3%，之后回到均值
+	closesA, closesB := pairsTestPrices(20, map[int]float64{12: -0.03})
+	result, err := Backtest(context.Background(), pairsTestFeed(closesA, closesB), pairsTestParams(), pairsTestConfig())
+	require.NoError(t, err)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.Equal(t, DirectionLong, trade.Direction)
+	assert.Equal(t, "reversion", trade.ExitReason)
+	assert.Less(t, trade.EntryZ, -2.0)
+	assert.Equal(t, pairsTestStart.Add(13*time.Hour-time.Millisecond), trade.EntryTime)
+	assert.Equal(t, pairsTestStart.Add(14*time.Hour-time.Millisecond), trade.ExitTime)
+	assert.Greater(t, trade.Legs[0].Quantity, 0.0) // 买入 A
+	assert.Less(t, trade.Legs[1].Quantity, 0.0)    // 卖空 B
+	assert.Greater(t, trade.PnL, 0.0)
+
+	assert.Equal(t, 1, result.WinningTrades)
+	assert.Equal(t, 20, result.Bars)
+	assert.Len(t, result.Equity, 20)
+	assert.InDelta(t, 10000+trade.PnL, result.FinalValue, 1e-6)
+	assert.InDelta(t, trade.PnL/10000, result.TotalReturn, 1e-9)
+	assert.InDelta(t, trade.Fees, result.Fees, 1e-9)
+	assert.Zero(t, result.OpenLegs[0].Quantity)
+}
+
+func TestBacktest_WarmupBeforeStartTime(t *testing.T) {
+	// 偏离发生在开始时间之前，只用于计算价差
+	closesA, closesB := pairsTestPrices(20, map[int]float64{12: -0.03})
+	config := pairsTestConfig()
+	config.StartTime = pairsTestStart.Add(14 * time.Hour)
+
+	result, err := Backtest(context.Background(), pairsTestFeed(closesA, closesB), pairsTestParams(), config)
+	require.NoError(t, err)
+	assert.Empty(t, result.Trades)
+	assert.Equal(t, 6, result.Bars)
+	assert.InDelta(t, 10000, result.FinalValue, 1e-9)
+}
+
+func TestBacktest_OpenPositionAtEnd(t *testing.T) {
+	// A 相对 B 持续走高，做空价差后到结束仍未回归
+	closesA, closesB := pairsTestPrices(14, map[int]float64{12: 0.03, 13: 0.035})
+	result, err := Backtest(context.Background(), pairsTestFeed(closesA, closesB), pairsTestParams(), pairsTestConfig())
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Trades)
+	assert.Less(t, result.OpenLegs[0].Quantity, 0.0)
+	assert.Greater(t, result.OpenLegs[1].Quantity, 0.0)
+	assert.Equal(t, pairsTestStart.Add(13*time.Hour-time.Millisecond), result.OpenSince)
+	assert.Less(t, result.FinalValue, 10000.0)
+}
+
+func TestBacktest_InvalidParams(t *testing.T) {
+	closesA, closesB := pairsTestPrices(5, nil)
+	params := pairsTestParams()
+	params.ExitZ = 3
+
+	_, err := Backtest(context.Background(), pairsTestFeed(closesA, closesB), params, pairsTestConfig())
+	assert.Error(t, err)
+
+	_, err = Backtest(context.Background(), pairsTestFeed(nil, nil), pairsTestParams(), pairsTestConfig())
+	assert.Error(t, err)
+}
+
+func TestSpreadModel_OLSHedge(t *testing.T) {
+	// ln(A) = 2·ln(B) + 常数：β = 2，价差恒定
+	params := pairsTestParams()
+	params.Hedge = HedgeOLS
+	model := newSpreadModel(params)
+
+	var state SpreadState
+	for i := 0; i < 10; i++ {
+		b := 100 + float64(i)
+		state = model.add(b*b/50, b)
+	}
+	assert.InDelta(t, 2, state.Hedge, 1e-9)
+	assert.InDelta(t, math.Log(1.0/50), state.Spread, 1e-9)
+	assert.False(t, state.Ready) // 标准差为0
+}
+
+func TestDecide(t *testing.T) {
+	params := pairsTestParams()
+	params.MaxHoldingBars = 24
+	ready := func(z float64) SpreadState { return SpreadState{ZScore: z, Ready: true} }
+
+	cases := []struct {
+		name     string
+		z        float64
+		position Direction
+		heldBars int
+		open     Direction
+		exit     string
+	}{
+		{"not ready", 0, DirectionFlat, 0, DirectionFlat, ""},
+		{"long entry", -2.2, DirectionFlat, 0, DirectionLong, ""},
+		{"short entry", 2.2, DirectionFlat, 0, DirectionShort, ""},
+		{"no entry beyond stop", -4.5, DirectionFlat, 0, DirectionFlat, ""},
+		{"hold", -1.5, DirectionLong, 3, DirectionFlat, ""},
+		{"long reversion", -0.4, DirectionLong, 3, DirectionFlat, "reversion"},
+		{"short overshoot reverts", -1, DirectionShort, 3, DirectionFlat, "reversion"},
+		{"long stop", -4.1, DirectionLong, 3, DirectionFlat, "stop"},
+		{"short stop", 4.1, DirectionShort, 3, DirectionFlat, "stop"},
+		{"max holding", 1.5, DirectionShort, 24, DirectionFlat, "max_holding"},
+	}
+	for _, c := range cases {
+		state := ready(c.z)
+		if c.name == "not ready" {
+			state = SpreadState{}
+		}
+		open, exit := decide(params, state, c.position, c.heldBars)
+		assert.Equal(t, c.open, open, c.name)
+		assert.Equal(t, c.exit, exit, c.name)
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	assert.NoError(t, GetDefaultParams().Validate())
+
+	for name, mutate := range map[string]func(p *Params){
+		"lookback": func(p *Params) { p.Lookback = 2 },
+		"entry":    func(p *Params) { p.EntryZ = 0 },
+		"exit":     func(p *Params) { p.ExitZ = p.EntryZ },
+		"stop":     func(p *Params) { p.StopZ = 1.5 },
+		"hedge":    func(p *Params) { p.Hedge = "kalman" },
+		"size":     func(p *Params) { p.PositionSizePercent = 1.5 },
+		"max bars": func(p *Params) { p.MaxHoldingBars = -1 },
+	} {
+		params := GetDefaultParams()
+		mutate(params)
+		assert.Error(t, params.Validate(), name)
+	}
+}
@@ -0,0 +1,204 @@
+package pairs
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxGrossExposure 两条腿名义金额之和与组合价值之比的上限
+const maxGrossExposure = 2.0
+
+// Leg 一条腿的持仓，Quantity 为正表示多头、为负表示空头（借币卖出）
+type Leg struct {
+	Symbol     string
+	Quantity   float64
+	EntryPrice float64
+}
+
+// TradeLeg 已平仓交易中一条腿的明细
+type TradeLeg struct {
+	Symbol     string  `json:"symbol"`
+	Quantity   float64 `json:"quantity"` // 正数为多头，负数为空头
+	EntryPrice float64 `json:"entry_price"`
+	ExitPrice  float64 `json:"exit_price"`
+	PnL        float64 `json:"pnl"` // 价格盈亏，不含手续费和借币成本
+}
+
+// Trade 一次完整的配对交易（两条腿同时开仓、同时平仓）
+type Trade struct {
+	Direction  Direction   `json:"direction"`
+	EntryTime  time.Time   `json:"entry_time"`
+	ExitTime   time.Time   `json:"exit_time"`
+	EntryZ     float64     `json:"entry_z"`
+	ExitZ      float64     `json:"exit_z"`
+	Hedge      float64     `json:"hedge"` // 开仓时的对冲比例 β
+	Legs       [2]TradeLeg `json:"legs"`
+	Fees       float64     `json:"fees"`
+	BorrowCost float64     `json:"borrow_cost"`
+	PnL        float64     `json:"pnl"` // 两条腿价格盈亏 - 手续费 - 借币成本
+	ExitReason string      `json:"exit_reason"`
+}
+
+// Executor 配对交易的保证金账户：两条腿作为一个整体开仓和平仓，任一条腿不满足条件时两条腿都不执行
+// 回测按K线收盘价成交，空头按年化借币利率逐根K线计提借币成本
+type Executor struct {
+	feeRate    float64
+	borrowRate float64
+	cash       float64
+	legs       [2]Leg
+
+	direction  Direction
+	entryTime  time.Time
+	entryZ     float64
+	hedge      float64
+	fees       float64 // 当前持仓累计的手续费
+	borrowCost float64 // 当前持仓累计的借币成本
+
+	totalFees       float64
+	totalBorrowCost float64
+}
+
+// NewExecutor 创建配对交易账户，feeRate 为单边手续费率，borrowRate 为空头年化借币利率
+func NewExecutor(symbolA, symbolB string, initialCapital, feeRate, borrowRate float64) *Executor {
+	return &Executor{
+		feeRate:    feeRate,
+		borrowRate: borrowRate,
+		cash:       initialCapital,
+		legs:       [2]Leg{{Symbol: symbolA}, {Symbol: symbolB}},
+	}
+}
+
+// Direction 当前持仓方向，空仓时为 DirectionFlat
+func (e *Executor) Direction() Direction {
+	return e.direction
+}
+
+// Legs 两条腿的当前持仓
+func (e *Executor) Legs() [2]Leg {
+	return e.legs
+}
+
+// Fees 累计手续费
+func (e *Executor) Fees() float64 {
+	return e.totalFees
+}
+
+// BorrowCost 累计借币成本
+func (e *Executor) BorrowCost() float64 {
+	return e.totalBorrowCost
+}
+
+// Equity 按两条腿的价格估值的组合价值
+func (e *Executor) Equity(priceA, priceB float64) float64 {
+	return e.cash + e.legs[0].Quantity*priceA + e.legs[1].Quantity*priceB
+}
+
+// Open 同时开两条腿：A 腿名义金额为组合价值的 sizePercent，B 腿为 A 腿的 hedge 倍，方向相反
+// 已有持仓、价格或对冲比例无效、总敞口超过组合价值的 2 倍时返回错误，两条腿都不执行
+func (e *Executor) Open(direction Direction, bar *Bar, z, hedge, sizePercent float64) error {
+	if e.direction != DirectionFlat {
+		return fmt.Errorf("pair position already open (%s)", e.direction)
+	}
+	if direction != DirectionLong && direction != DirectionShort {
+		return fmt.Errorf("invalid pair direction %q", direction)
+	}
+	priceA, priceB := bar.A.Close.InexactFloat64(), bar.B.Close.InexactFloat64()
+	if priceA <= 0 || priceB <= 0 {
+		return fmt.Errorf("invalid prices %s=%f, %s=%f", e.legs[0].Symbol, priceA, e.legs[1].Symbol, priceB)
+	}
+	if hedge <= 0 || math.IsNaN(hedge) || math.IsInf(hedge, 0) {
+		return fmt.Errorf("hedge ratio must be positive, got %f", hedge)
+	}
+	equity := e.Equity(priceA, priceB)
+	if equity <= 0 {
+		return fmt.Errorf("portfolio value must be positive, got %f", equity)
+	}
+
+	notionalA := equity * sizePercent
+	notionalB := notionalA * hedge
+	if gross := notionalA + notionalB; gross > equity*maxGrossExposure {
+		return fmt.Errorf("gross exposure %.2f exceeds %.0fx portfolio value %.2f (hedge ratio %.2f)", gross, maxGrossExposure, equity, hedge)
+	}
+
+	sign := 1.0
+	if direction == DirectionShort {
+		sign = -1
+	}
+	quantityA := sign * notionalA / priceA
+	quantityB := -sign * notionalB / priceB
+	fees := (notionalA + notionalB) * e.feeRate
+
+	// 两条腿都通过检查后一起记账
+	e.cash -= quantityA*priceA + quantityB*priceB + fees
+	e.legs[0].Quantity, e.legs[0].EntryPrice = quantityA, priceA
+	e.legs[1].Quantity, e.legs[1].EntryPrice = quantityB, priceB
+	e.direction = direction
+	e.entryTime = bar.A.CloseTime
+	e.entryZ = z
+	e.hedge = hedge
+	e.fees = fees
+	e.borrowCost = 0
+	e.totalFees += fees
+	return nil
+}
+
+// Accrue 按空头市值计提一根K线（duration）的借币成本
+func (e *Executor) Accrue(bar *Bar, duration time.Duration) {
+	if e.borrowRate == 0 || e.direction == DirectionFlat {
+		return
+	}
+	prices := [2]float64{bar.A.Close.InexactFloat64(), bar.B.Close.InexactFloat64()}
+	yearFraction := float64(duration) / float64(365*24*time.Hour)
+	for i, leg := range e.legs {
+		if leg.Quantity < 0 {
+			cost := -leg.Quantity * prices[i] * e.borrowRate * yearFraction
+			e.cash -= cost
+			e.borrowCost += cost
+			e.totalBorrowCost += cost
+		}
+	}
+}
+
+// Close 同时平掉两条腿，返回完整的配对交易
+func (e *Executor) Close(bar *Bar, z float64, reason string) (*Trade, error) {
+	if e.direction == DirectionFlat {
+		return nil, fmt.Errorf("no pair position to close")
+	}
+	prices := [2]float64{bar.A.Close.InexactFloat64(), bar.B.Close.InexactFloat64()}
+	if prices[0] <= 0 || prices[1] <= 0 {
+		return nil, fmt.Errorf("invalid prices %s=%f, %s=%f", e.legs[0].Symbol, prices[0], e.legs[1].Symbol, prices[1])
+	}
+
+	trade := &Trade{
+		Direction:  e.direction,
+		EntryTime:  e.entryTime,
+		ExitTime:   bar.A.CloseTime,
+		EntryZ:     e.entryZ,
+		ExitZ:      z,
+		Hedge:      e.hedge,
+		BorrowCost: e.borrowCost,
+		ExitReason: reason,
+	}
+	exitFees := 0.0
+	for i, leg := range e.legs {
+		notional := leg.Quantity * prices[i]
+		exitFees += math.Abs(notional) * e.feeRate
+		e.cash += notional
+		trade.Legs[i] = TradeLeg{
+			Symbol:     leg.Symbol,
+			Quantity:   leg.Quantity,
+			EntryPrice: leg.EntryPrice,
+			ExitPrice:  prices[i],
+			PnL:        leg.Quantity * (prices[i] - leg.EntryPrice),
+		}
+		e.legs[i] = Leg{Symbol: leg.Symbol}
+	}
+	e.cash -= exitFees
+	e.totalFees += exitFees
+
+	trade.Fees = e.fees + exitFees
+	trade.PnL = trade.Legs[0].PnL + trade.Legs[1].PnL - trade.Fees - trade.BorrowCost
+	e.direction = DirectionFlat
+	return trade, nil
+}
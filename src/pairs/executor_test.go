@@ -0,0 +1,77 @@
+package pairs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pairsTestBar 一组价格为 priceA、priceB 的K线
+func pairsTestBar(hour int, priceA, priceB float64) *Bar {
+	a := pairsTestKlines([]int{hour}, []float64{priceA})[0]
+	b := pairsTestKlines([]int{hour}, []float64{priceB})[0]
+	return &Bar{Time: a.OpenTime, A: a, B: b}
+}
+
+func TestExecutor_OpenAndCloseBothLegs(t *testing.T) {
+	executor := NewExecutor("ETH/USDT", "BTC/USDT", 10000, 0.001, 0)
+
+	// 做多价差：买入 5000 的 ETH、卖空 5000 的 BTC
+	require.NoError(t, executor.Open(DirectionLong, pairsTestBar(0, 2000, 40000), -2.1, 1, 0.5))
+	legs := executor.Legs()
+	assert.InDelta(t, 2.5, legs[0].Quantity, 1e-9)
+	assert.InDelta(t, -0.125, legs[1].Quantity, 1e-9)
+	assert.InDelta(t, 10000-10, executor.Equity(2000, 40000), 1e-9)
+
+	// 已有持仓时不能再开仓
+	assert.Error(t, executor.Open(DirectionShort, pairsTestBar(1, 2000, 40000), 2.5, 1, 0.5))
+
+	// ETH 涨 10%、BTC 涨 2%
+	trade, err := executor.Close(pairsTestBar(5, 2200, 40800), -0.3, "reversion")
+	require.NoError(t, err)
+	assert.Equal(t, DirectionLong, trade.Direction)
+	assert.Equal(t, "reversion", trade.ExitReason)
+	assert.InDelta(t, 500, trade.Legs[0].PnL, 1e-9)
+	assert.InDelta(t, -100, trade.Legs[1].PnL, 1e-9)
+	assert.InDelta(t, 10+(5500+5100)*0.001, trade.Fees, 1e-9)
+	assert.InDelta(t, 400-trade.Fees, trade.PnL, 1e-9)
+	assert.Equal(t, DirectionFlat, executor.Direction())
+	assert.InDelta(t, 10000+trade.PnL, executor.Equity(1, 1), 1e-9)
+	assert.InDelta(t, trade.Fees, executor.Fees(), 1e-9)
+}
+
+func TestExecutor_OpenIsAtomic(t *testing.T) {
+	executor := NewExecutor("ETH/USDT", "BTC/USDT", 10000, 0.001, 0)
+
+	// B 腿价格无效、对冲比例使总敞口超过 2 倍时两条腿都不开
+	assert.Error(t, executor.Open(DirectionLong, pairsTestBar(0, 2000, 0), -2.1, 1, 0.5))
+	assert.Error(t, executor.Open(DirectionLong, pairsTestBar(0, 2000, 40000), -2.1, 4, 0.5))
+	assert.Error(t, executor.Open(DirectionLong, pairsTestBar(0, 2000, 40000), -2.1, -0.5, 0.5))
+
+	assert.Equal(t, DirectionFlat, executor.Direction())
+	for _, leg := range executor.Legs() {
+		assert.Zero(t, leg.Quantity)
+	}
+	assert.InDelta(t, 10000, executor.Equity(2000, 40000), 1e-9)
+	assert.Zero(t, executor.Fees())
+
+	_, err := executor.Close(pairsTestBar(1, 2000, 40000), 0, "reversion")
+	assert.Error(t, err)
+}
+
+func TestExecutor_BorrowCostOnShortLeg(t *testing.T) {
+	executor := NewExecutor("ETH/USDT", "BTC/USDT", 10000, 0, 0.0876)
+	require.NoError(t, executor.Open(DirectionShort, pairsTestBar(0, 2000, 40000), 2.1, 1, 0.5))
+
+	// 卖空 5000 的 ETH，年化 8.76%，每小时 0.05
+	bar := pairsTestBar(1, 2000, 40000)
+	executor.Accrue(bar, time.Hour)
+	executor.Accrue(bar, time.Hour)
+	trade, err := executor.Close(bar, 0.2, "reversion")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, trade.BorrowCost, 1e-9)
+	assert.InDelta(t, -0.1, trade.PnL, 1e-9)
+	assert.InDelta(t, 0.1, executor.BorrowCost(), 1e-9)
+}
@@ -0,0 +1,72 @@
+package pairs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+)
+
+// Bar 两个交易对开盘时间相同的一组K线
+type Bar struct {
+	Time time.Time // 开盘时间
+	A    *cex.KlineData
+	B    *cex.KlineData
+}
+
+// SyncFeed 按开盘时间对齐两个数据源，只输出两边都有的K线，任一边缺失的K线跳过
+type SyncFeed struct {
+	a, b         engine.DataFeed
+	nextA, nextB *cex.KlineData
+	skippedA     int
+	skippedB     int
+}
+
+// NewSyncFeed 创建对齐两个数据源的数据喂入器（调用方负责 Start/Stop 两个数据源）
+func NewSyncFeed(a, b engine.DataFeed) *SyncFeed {
+	return &SyncFeed{a: a, b: b}
+}
+
+// Next 返回下一组对齐的K线，任一数据源结束时返回 nil
+func (f *SyncFeed) Next(ctx context.Context) (*Bar, error) {
+	for {
+		if f.nextA == nil {
+			kline, err := f.a.GetNext(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("leg A data feed: %w", err)
+			}
+			f.nextA = kline
+		}
+		if f.nextB == nil {
+			kline, err := f.b.GetNext(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("leg B data feed: %w", err)
+			}
+			f.nextB = kline
+		}
+		if f.nextA == nil || f.nextB == nil {
+			return nil, nil
+		}
+
+		// 落后的一边向前追赶，跳过另一边没有的K线
+		switch a, b := f.nextA.OpenTime.UnixMilli(), f.nextB.OpenTime.UnixMilli(); {
+		case a < b:
+			f.nextA = nil
+			f.skippedA++
+		case b < a:
+			f.nextB = nil
+			f.skippedB++
+		default:
+			bar := &Bar{Time: f.nextA.OpenTime, A: f.nextA, B: f.nextB}
+			f.nextA, f.nextB = nil, nil
+			return bar, nil
+		}
+	}
+}
+
+// Skipped 因另一边缺失而跳过的K线数量
+func (f *SyncFeed) Skipped() (a, b int) {
+	return f.skippedA, f.skippedB
+}
@@ -0,0 +1,74 @@
+package pairs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pairsTestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// pairsTestKlines 从 pairsTestStart 开始的 1h K线，hours 为每根K线的序号（可以不连续）
+func pairsTestKlines(hours []int, closes []float64) []*cex.KlineData {
+	klines := make([]*cex.KlineData, len(closes))
+	for i, c := range closes {
+		openTime := pairsTestStart.Add(time.Duration(hours[i]) * time.Hour)
+		price := decimal.NewFromFloat(c)
+		klines[i] = &cex.KlineData{
+			OpenTime:  openTime,
+			CloseTime: openTime.Add(time.Hour - time.Millisecond),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+		}
+	}
+	return klines
+}
+
+// pairsTestFeed 两个连续的 1h 数据源
+func pairsTestFeed(closesA, closesB []float64) *SyncFeed {
+	hours := make([]int, len(closesA))
+	for i := range hours {
+		hours[i] = i
+	}
+	return NewSyncFeed(
+		engine.NewBacktestDataFeed(pairsTestKlines(hours, closesA)),
+		engine.NewBacktestDataFeed(pairsTestKlines(hours[:len(closesB)], closesB)),
+	)
+}
+
+func TestSyncFeed_AlignsByOpenTime(t *testing.T) {
+	// A 缺少第 2 根，B 缺少第 0、3 根
+	a := pairsTestKlines([]int{0, 1, 3, 4}, []float64{10, 11, 13, 14})
+	b := pairsTestKlines([]int{1, 2, 4, 5}, []float64{21, 22, 24, 25})
+	feed := NewSyncFeed(engine.NewBacktestDataFeed(a), engine.NewBacktestDataFeed(b))
+
+	var bars []*Bar
+	for {
+		bar, err := feed.Next(context.Background())
+		require.NoError(t, err)
+		if bar == nil {
+			break
+		}
+		bars = append(bars, bar)
+	}
+
+	require.Len(t, bars, 2)
+	for i, hour := range []int{1, 4} {
+		assert.Equal(t, pairsTestStart.Add(time.Duration(hour)*time.Hour), bars[i].Time)
+		assert.Equal(t, bars[i].A.OpenTime, bars[i].B.OpenTime)
+	}
+	assert.True(t, bars[1].B.Close.Equal(decimal.NewFromInt(24)))
+
+	skippedA, skippedB := feed.Skipped()
+	assert.Equal(t, 2, skippedA) // 0 和 3
+	assert.Equal(t, 1, skippedB) // 2（B 的 5 在 A 结束后不再读取）
+}
@@ -0,0 +1,64 @@
+package pairs
+
+import (
+	"fmt"
+)
+
+// HedgeMethod 两条腿的对冲比例计算方式
+type HedgeMethod string
+
+const (
+	HedgeRatio HedgeMethod = "ratio" // 价差为 ln(A) - ln(B)，即比价 A/B，两条腿名义金额相等
+	HedgeOLS   HedgeMethod = "ols"   // 价差为 ln(A) - β·ln(B)，β 为回看窗口内 ln(A) 对 ln(B) 的回归系数，B 腿名义金额为 A 腿的 β 倍
+)
+
+// Params 配对交易参数：价差的 z-score 偏离 EntryZ 时开仓（做多被低估的一边、做空被高估的一边），回归到 ExitZ 以内时平仓
+type Params struct {
+	Lookback            int         `json:"lookback"`              // 计算价差均值、标准差（和 OLS 对冲比例）的K线数，默认 60
+	EntryZ              float64     `json:"entry_z"`               // 开仓的 |z| 阈值，默认 2.0
+	ExitZ               float64     `json:"exit_z"`                // 平仓的 |z| 阈值，默认 0.5
+	StopZ               float64     `json:"stop_z"`                // |z| 继续扩大到该值时止损平仓，0 表示不止损，默认 4.0
+	MaxHoldingBars      int         `json:"max_holding_bars"`      // 持仓超过该K线数时平仓，0 表示不限制
+	Hedge               HedgeMethod `json:"hedge"`                 // 对冲比例计算方式，默认 ratio
+	PositionSizePercent float64     `json:"position_size_percent"` // 每条腿名义金额占组合价值的比例（β>1 时 B 腿按 β 放大），默认 0.5
+}
+
+// GetDefaultParams 获取默认的配对交易参数
+func GetDefaultParams() *Params {
+	return &Params{
+		Lookback:            60,
+		EntryZ:              2.0,
+		ExitZ:               0.5,
+		StopZ:               4.0,
+		Hedge:               HedgeRatio,
+		PositionSizePercent: 0.5,
+	}
+}
+
+// Validate 验证参数有效性
+func (p *Params) Validate() error {
+	if p.Lookback < 3 {
+		return fmt.Errorf("lookback must be at least 3, got %d", p.Lookback)
+	}
+	if p.EntryZ <= 0 {
+		return fmt.Errorf("entry_z must be positive, got %f", p.EntryZ)
+	}
+	if p.ExitZ < 0 || p.ExitZ >= p.EntryZ {
+		return fmt.Errorf("exit_z must be in [0, entry_z), got %f", p.ExitZ)
+	}
+	if p.StopZ != 0 && p.StopZ <= p.EntryZ {
+		return fmt.Errorf("stop_z must be greater than entry_z or 0 to disable, got %f", p.StopZ)
+	}
+	if p.MaxHoldingBars < 0 {
+		return fmt.Errorf("max_holding_bars must be non-negative, got %d", p.MaxHoldingBars)
+	}
+	switch p.Hedge {
+	case HedgeRatio, HedgeOLS:
+	default:
+		return fmt.Errorf("unknown hedge method %q (supported: ratio, ols)", p.Hedge)
+	}
+	if p.PositionSizePercent <= 0 || p.PositionSizePercent > 1 {
+		return fmt.Errorf("position_size_percent must be between 0 and 1, got %f", p.PositionSizePercent)
+	}
+	return nil
+}
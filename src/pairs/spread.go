@@ -0,0 +1,135 @@
+package pairs
+
+import (
+	"math"
+)
+
+// Direction 价差持仓方向
+type Direction string
+
+const (
+	DirectionFlat  Direction = ""
+	DirectionLong  Direction = "LONG_SPREAD"  // 做多价差：买入 A、卖空 B（z-score 过低）
+	DirectionShort Direction = "SHORT_SPREAD" // 做空价差：卖空 A、买入 B（z-score 过高）
+)
+
+// minSpreadStdDev 价差标准差低于该值时视为恒定价差（浮点误差），不计算 z-score
+const minSpreadStdDev = 1e-9
+
+// SpreadState 一根K线的价差指标
+type SpreadState struct {
+	Spread float64 // ln(A) - β·ln(B)
+	Mean   float64
+	StdDev float64
+	ZScore float64
+	Hedge  float64 // 对冲比例 β
+	Ready  bool    // 回看窗口已满、价差不是恒定值
+}
+
+// spreadModel 逐根K线维护最近 Lookback 根K线的对数价格，计算价差的 z-score
+type spreadModel struct {
+	lookback int
+	hedge    HedgeMethod
+	logA     []float64
+	logB     []float64
+}
+
+func newSpreadModel(params *Params) *spreadModel {
+	return &spreadModel{lookback: params.Lookback, hedge: params.Hedge}
+}
+
+// add 加入一组收盘价，返回当前K线的价差状态
+func (m *spreadModel) add(priceA, priceB float64) SpreadState {
+	if priceA <= 0 || priceB <= 0 {
+		return SpreadState{}
+	}
+	m.logA = append(m.logA, math.Log(priceA))
+	m.logB = append(m.logB, math.Log(priceB))
+	if len(m.logA) > m.lookback {
+		m.logA = m.logA[1:]
+		m.logB = m.logB[1:]
+	}
+
+	beta := 1.0
+	if m.hedge == HedgeOLS && len(m.logA) >= 2 {
+		beta = olsSlope(m.logB, m.logA)
+	}
+
+	n := len(m.logA)
+	state := SpreadState{Hedge: beta, Spread: m.logA[n-1] - beta*m.logB[n-1]}
+	if n < m.lookback {
+		return state
+	}
+
+	spreads := make([]float64, n)
+	for i := range m.logA {
+		spreads[i] = m.logA[i] - beta*m.logB[i]
+		state.Mean += spreads[i]
+	}
+	state.Mean /= float64(n)
+	variance := 0.0
+	for _, spread := range spreads {
+		variance += (spread - state.Mean) * (spread - state.Mean)
+	}
+	variance /= float64(n - 1)
+	if math.Sqrt(variance) < minSpreadStdDev {
+		return state
+	}
+	state.StdDev = math.Sqrt(variance)
+	state.ZScore = (state.Spread - state.Mean) / state.StdDev
+	state.Ready = true
+	return state
+}
+
+// olsSlope y 对 x 的最小二乘回归斜率，x 方差为0时返回 1
+func olsSlope(x, y []float64) float64 {
+	n := float64(len(x))
+	meanX, meanY := 0.0, 0.0
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	covariance, variance := 0.0, 0.0
+	for i := range x {
+		covariance += (x[i] - meanX) * (y[i] - meanY)
+		variance += (x[i] - meanX) * (x[i] - meanX)
+	}
+	if variance == 0 {
+		return 1
+	}
+	return covariance / variance
+}
+
+// decide 根据 z-score 和持仓决定开仓方向或平仓原因（空字符串表示继续持有或保持空仓）
+// 偏离已达到止损阈值时不开仓
+func decide(params *Params, state SpreadState, position Direction, heldBars int) (open Direction, exitReason string) {
+	if !state.Ready {
+		return DirectionFlat, ""
+	}
+	z := state.ZScore
+	beyondStop := params.StopZ > 0 && math.Abs(z) >= params.StopZ
+
+	if position == DirectionFlat {
+		switch {
+		case beyondStop:
+		case z <= -params.EntryZ:
+			return DirectionLong, ""
+		case z >= params.EntryZ:
+			return DirectionShort, ""
+		}
+		return DirectionFlat, ""
+	}
+
+	switch {
+	case beyondStop && (position == DirectionLong) == (z < 0):
+		return DirectionFlat, "stop"
+	case position == DirectionLong && z >= -params.ExitZ, position == DirectionShort && z <= params.ExitZ:
+		return DirectionFlat, "reversion"
+	case params.MaxHoldingBars > 0 && heldBars >= params.MaxHoldingBars:
+		return DirectionFlat, "max_holding"
+	}
+	return DirectionFlat, ""
+}
@@ -0,0 +1,92 @@
+// Package precision 按交易所下单规则取整数量和价格，并按有效数字显示，
+// 适用于小数位很多的低价币（如 PEPE 价格 0.00001234、数量 40518638）
+package precision
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// FloorToStep 按步长向下取整（step 不为正时原样返回），用于数量：取整后不会超过可用余额
+func FloorToStep(value, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	return value.Div(step).Floor().Mul(step)
+}
+
+// CeilToStep 按步长向上取整（step 不为正时原样返回）
+func CeilToStep(value, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	return value.Div(step).Ceil().Mul(step)
+}
+
+// RoundToStep 按步长四舍五入（step 不为正时原样返回），用于价格取最近的最小变动单位
+func RoundToStep(value, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	return value.Div(step).Round(0).Mul(step)
+}
+
+// StepDecimals 步长对应的小数位数：0.001 → 3，0.00100000 → 3，1 → 0，10 → 0
+func StepDecimals(step decimal.Decimal) int32 {
+	if !step.IsPositive() {
+		return 0
+	}
+	// 去掉末尾的 0 后取小数位数
+	normalized, _ := decimal.NewFromString(step.String())
+	if exp := normalized.Exponent(); exp < 0 {
+		return -exp
+	}
+	return 0
+}
+
+// magnitude 最高有效数字的位置：12345.6 → 5，0.00001234 → -4（即 1.234e-5）
+func magnitude(value decimal.Decimal) int {
+	normalized, _ := decimal.NewFromString(value.Abs().String())
+	return normalized.NumDigits() + int(normalized.Exponent())
+}
+
+// RoundSignificant 保留 digits 位有效数字（四舍五入），digits <= 0 或 value 为 0 时原样返回
+func RoundSignificant(value decimal.Decimal, digits int) decimal.Decimal {
+	if digits <= 0 || value.IsZero() {
+		return value
+	}
+	return value.Round(int32(digits - magnitude(value)))
+}
+
+// FloorSignificant 保留 digits 位有效数字（向零截断），用于不能超出的数量
+func FloorSignificant(value decimal.Decimal, digits int) decimal.Decimal {
+	if digits <= 0 || value.IsZero() {
+		return value
+	}
+	return value.RoundDown(int32(digits - magnitude(value)))
+}
+
+// Format 按小数位显示并去掉末尾多余的 0（不使用科学计数法）：Format(1.50000, 4) → "1.5"
+func Format(value decimal.Decimal, decimals int32) string {
+	return trimZeros(value.StringFixed(decimals))
+}
+
+// FormatSignificant 按有效数字显示（不使用科学计数法），整数部分完整保留：
+// FormatSignificant(0.0000123456, 4) → "0.00001235"，FormatSignificant(40518638.57, 4) → "40518639"
+func FormatSignificant(value decimal.Decimal, digits int) string {
+	if value.IsZero() {
+		return "0"
+	}
+	decimals := int32(max(digits-magnitude(value), 0))
+	return Format(value.Round(decimals), decimals)
+}
+
+// trimZeros 去掉小数部分末尾的 0 和多余的小数点
+func trimZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
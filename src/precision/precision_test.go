@@ -0,0 +1,86 @@
+package precision
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestStepRounding(t *testing.T) {
+	tests := []struct {
+		name        string
+		value, step string
+		floor, ceil string
+		round       string
+	}{
+		{"BTC 数量", "0.123459", "0.00001", "0.12345", "0.12346", "0.12346"},
+		{"PEPE 整数步长", "40518638.5737439222", "1", "40518638", "40518639", "40518639"},
+		{"PEPE 价格", "0.0000123456789", "0.00000001", "0.00001234", "0.00001235", "0.00001235"},
+		{"十位以上小数", "0.000000001234567891", "0.0000000001", "0.0000000012", "0.0000000013", "0.0000000012"},
+		{"交易所返回带末尾 0 的步长", "1.23456", "0.00100000", "1.234", "1.235", "1.235"},
+		{"步长大于 1", "1234", "100", "1200", "1300", "1200"},
+		{"正好在步长上", "0.5", "0.1", "0.5", "0.5", "0.5"},
+		{"未配置步长", "1.23456789", "0", "1.23456789", "1.23456789", "1.23456789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.floor, FloorToStep(d(tt.value), d(tt.step)).String())
+			assert.Equal(t, tt.ceil, CeilToStep(d(tt.value), d(tt.step)).String())
+			assert.Equal(t, tt.round, RoundToStep(d(tt.value), d(tt.step)).String())
+		})
+	}
+}
+
+func TestStepDecimals(t *testing.T) {
+	tests := map[string]int32{
+		"0.00000001":   8,
+		"0.00100000":   3,
+		"0.0000000001": 10,
+		"1":            0,
+		"1.00000000":   0,
+		"100":          0,
+		"0":            0,
+	}
+	for step, want := range tests {
+		assert.Equal(t, want, StepDecimals(d(step)), step)
+	}
+}
+
+func TestSignificant(t *testing.T) {
+	tests := []struct {
+		value  string
+		digits int
+		round  string
+		floor  string
+		format string
+	}{
+		{"0.0000123456789", 4, "0.00001235", "0.00001234", "0.00001235"},
+		{"0.000000001234567891", 3, "0.00000000123", "0.00000000123", "0.00000000123"},
+		{"40518638.5737439222", 4, "40520000", "40510000", "40518639"},
+		{"12345.678", 6, "12345.7", "12345.6", "12345.7"},
+		{"-0.00098765", 2, "-0.00099", "-0.00098", "-0.00099"},
+		{"1.5", 8, "1.5", "1.5", "1.5"},
+		{"0", 4, "0", "0", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.round, RoundSignificant(d(tt.value), tt.digits).String())
+			assert.Equal(t, tt.floor, FloorSignificant(d(tt.value), tt.digits).String())
+			assert.Equal(t, tt.format, FormatSignificant(d(tt.value), tt.digits))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "1.5", Format(d("1.50000"), 4))
+	assert.Equal(t, "0.00001234", Format(d("0.0000123449"), 8))
+	assert.Equal(t, "40518638", Format(d("40518638.4"), 0))
+	assert.Equal(t, "0", Format(d("0.000000001"), 8))
+	// %12.0f 会把 PEPE 价格显示成 0，按步长小数位显示
+	assert.Equal(t, "0.0000000012", Format(d("0.0000000012345"), StepDecimals(d("0.0000000001"))))
+}
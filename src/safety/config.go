@@ -0,0 +1,34 @@
+package safety
+
+import (
+	"fmt"
+
+	"tradingbot/src/envconfig"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// Config 实盘安全门配置
+type Config struct {
+	RequireArm       bool    `json:"require_arm"`        // 实盘启动前显示摘要并要求输入 ARM 确认（无人值守运行时可关闭）
+	MaxOrderNotional float64 `json:"max_order_notional"` // 实盘单笔开仓挂单的最大金额（计价资产），0 表示不限制
+}
+
+// ConfigValue 实盘安全门配置实例
+var ConfigValue = Config{
+	RequireArm:       true,
+	MaxOrderNotional: 0,
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("SAFETY", &ConfigValue)
+}
+
+// Validate 检查配置
+func (c Config) Validate() error {
+	if c.MaxOrderNotional < 0 {
+		return fmt.Errorf("MaxOrderNotional must be non-negative, got %v", c.MaxOrderNotional)
+	}
+	return nil
+}
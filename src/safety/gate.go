@@ -0,0 +1,85 @@
+// Package safety 实盘安全门：启动前显示交易对、风险资金和策略参数摘要，要求输入 ARM 确认
+package safety
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ArmWord 启动实盘需要输入的确认词
+const ArmWord = "ARM"
+
+// ErrNotArmed 未确认启动实盘
+var ErrNotArmed = errors.New("live trading not armed")
+
+// Summary 实盘启动前的确认摘要
+type Summary struct {
+	Exchange  string
+	Testnet   bool
+	Base      string
+	Quote     string
+	Timeframe string
+
+	QuoteBalance  decimal.Decimal // 计价资产余额（可用+冻结）
+	BaseBalance   decimal.Decimal // 基础资产余额（可用+冻结）
+	CapitalAtRisk decimal.Decimal // 可用于开仓的计价资产（受最大持仓市值限制）
+
+	PositionSizing   string          // 仓位计算方式说明
+	MaxOrderNotional decimal.Decimal // 单笔开仓挂单上限，0 表示不限制
+	Risk             string          // 风控限制说明，为空表示未配置
+
+	Strategy       string
+	StrategyParams string
+}
+
+// Write 输出摘要
+func (s Summary) Write(w io.Writer) {
+	exchange := s.Exchange
+	if s.Testnet {
+		exchange += " (TESTNET)"
+	}
+	maxNotional := "unlimited"
+	if s.MaxOrderNotional.IsPositive() {
+		maxNotional = s.MaxOrderNotional.String() + " " + s.Quote
+	}
+	risk := s.Risk
+	if risk == "" {
+		risk = "none"
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintln(w, "🔴 LIVE TRADING SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+	fmt.Fprintf(w, "Exchange:         %s\n", exchange)
+	fmt.Fprintf(w, "Symbol:           %s/%s (%s)\n", s.Base, s.Quote, s.Timeframe)
+	fmt.Fprintf(w, "Balance:          %s %s, %s %s\n", s.QuoteBalance.String(), s.Quote, s.BaseBalance.String(), s.Base)
+	fmt.Fprintf(w, "Capital at risk:  %s %s\n", s.CapitalAtRisk.String(), s.Quote)
+	fmt.Fprintf(w, "Position sizing:  %s\n", s.PositionSizing)
+	fmt.Fprintf(w, "Max order:        %s\n", maxNotional)
+	fmt.Fprintf(w, "Risk limits:      %s\n", risk)
+	fmt.Fprintf(w, "Strategy:         %s\n", s.Strategy)
+	fmt.Fprintf(w, "Params:           %s\n", s.StrategyParams)
+	fmt.Fprintln(w, strings.Repeat("=", 50))
+}
+
+// Arm 显示摘要并读取一行确认，输入 ARM（区分大小写）时返回 nil，其他输入或读取结束返回 ErrNotArmed
+func Arm(in io.Reader, out io.Writer, summary Summary) error {
+	summary.Write(out)
+	fmt.Fprintf(out, "⚠️  Real orders will be placed. Type %s to start live trading: ", ArmWord)
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return fmt.Errorf("%w: no confirmation read", ErrNotArmed)
+	}
+	if strings.TrimSpace(line) != ArmWord {
+		return fmt.Errorf("%w: got %q", ErrNotArmed, strings.TrimSpace(line))
+	}
+	fmt.Fprintln(out, "✓ Armed")
+	return nil
+}
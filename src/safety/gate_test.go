@@ -0,0 +1,72 @@
+package safety
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSummary() Summary {
+	return Summary{
+		Exchange:         "binance",
+		Testnet:          true,
+		Base:             "BTC",
+		Quote:            "USDT",
+		Timeframe:        "1h",
+		QuoteBalance:     decimal.NewFromInt(1200),
+		BaseBalance:      decimal.RequireFromString("0.5"),
+		CapitalAtRisk:    decimal.NewFromInt(1000),
+		PositionSizing:   "fixed percent 95%",
+		MaxOrderNotional: decimal.NewFromInt(200),
+		Strategy:         "bollinger_bands",
+		StrategyParams:   "&{Period:20 Multiplier:2}",
+	}
+}
+
+func TestSummary_Write(t *testing.T) {
+	var out bytes.Buffer
+	testSummary().Write(&out)
+
+	text := out.String()
+	assert.Contains(t, text, "binance (TESTNET)")
+	assert.Contains(t, text, "BTC/USDT (1h)")
+	assert.Contains(t, text, "1200 USDT, 0.5 BTC")
+	assert.Contains(t, text, "Capital at risk:  1000 USDT")
+	assert.Contains(t, text, "Max order:        200 USDT")
+	assert.Contains(t, text, "Risk limits:      none")
+	assert.Contains(t, text, "Period:20")
+}
+
+func TestArm(t *testing.T) {
+	tests := []struct {
+		input string
+		armed bool
+	}{
+		{"ARM\n", true},
+		{"  ARM \r\n", true},
+		{"ARM", true}, // 没有换行直接结束
+		{"arm\n", false},
+		{"yes\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := Arm(strings.NewReader(tt.input), &out, testSummary())
+		if tt.armed {
+			assert.NoError(t, err, "input %q", tt.input)
+		} else {
+			assert.ErrorIs(t, err, ErrNotArmed, "input %q", tt.input)
+		}
+		assert.Contains(t, out.String(), "Type ARM to start live trading")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{MaxOrderNotional: 100}.Validate())
+	assert.Error(t, Config{MaxOrderNotional: -1}.Validate())
+}
@@ -0,0 +1,162 @@
+// Package scaffold 开发者脚手架：生成新策略的模板代码
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// registerCommandsFile 注册所有交易命令的文件（相对项目根目录）
+const registerCommandsFile = "src/cmd/bollinger_trading.go"
+
+// registerCommandsMarker 新策略命令插入在该注释之前
+const registerCommandsMarker = "\n\n\t// 可以添加其他交易策略命令"
+
+// strategyNamePattern 策略名：字母开头，字母数字，单词之间用 - 或 _ 分隔
+var strategyNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*([-_][a-zA-Z0-9]+)*$`)
+
+// StrategyNames 策略名的各种写法
+type StrategyNames struct {
+	Snake   string // 文件名和注册名，如 mean_reversion
+	Camel   string // 类型名，如 MeanReversion
+	Command string // CLI 命令名，如 mean-reversion
+	Display string // 显示名称，如 Mean Reversion
+}
+
+// ParseStrategyName 解析策略名，如 mean-reversion、mean_reversion
+func ParseStrategyName(name string) (StrategyNames, error) {
+	if !strategyNamePattern.MatchString(name) {
+		return StrategyNames{}, fmt.Errorf("invalid strategy name %q: use letters and digits separated by '-' or '_'", name)
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '-' || r == '_' })
+	titled := make([]string, len(words))
+	for i, word := range words {
+		titled[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	names := StrategyNames{
+		Snake:   strings.Join(words, "_"),
+		Camel:   strings.Join(titled, ""),
+		Command: strings.Join(words, "-"),
+		Display: strings.Join(titled, " "),
+	}
+	if names.Snake == "bollinger" {
+		return StrategyNames{}, fmt.Errorf("strategy %q already exists", name)
+	}
+	return names, nil
+}
+
+// GeneratedFile 生成的文件
+type GeneratedFile struct {
+	Path    string // 相对项目根目录的路径
+	Content []byte
+}
+
+// RenderStrategy 渲染新策略的全部文件（已 gofmt）
+func RenderStrategy(names StrategyNames) ([]GeneratedFile, error) {
+	files := []struct {
+		path string
+		tmpl string
+	}{
+		{filepath.Join("src", "strategy", names.Snake+"_params.go"), paramsTemplate},
+		{filepath.Join("src", "strategies", names.Snake+"_strategy.go"), strategyTemplate},
+		{filepath.Join("src", "strategies", names.Snake+"_strategy_test.go"), strategyTestTemplate},
+		{filepath.Join("src", "cmd", names.Snake+"_trading.go"), commandTemplate},
+	}
+
+	var generated []GeneratedFile
+	for _, f := range files {
+		content, err := render(f.path, f.tmpl, names)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, GeneratedFile{Path: f.path, Content: content})
+	}
+	return generated, nil
+}
+
+// GenerateStrategy 在项目根目录下生成新策略，并在 RegisterAllTradingCommands 中注册命令
+// 任何目标文件已存在时不写入任何文件
+func GenerateStrategy(root string, names StrategyNames) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(root, "src", "strategies")); err != nil {
+		return nil, fmt.Errorf("%s is not the project root (src/strategies not found)", root)
+	}
+
+	files, err := RenderStrategy(names)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(root, f.Path)); err == nil {
+			return nil, fmt.Errorf("%s already exists", f.Path)
+		}
+	}
+
+	registerPath := filepath.Join(root, registerCommandsFile)
+	registerSource, err := os.ReadFile(registerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", registerCommandsFile, err)
+	}
+	updated, err := addCommandRegistration(string(registerSource), names)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(root, f.Path), f.Content, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		written = append(written, f.Path)
+	}
+	if err := os.WriteFile(registerPath, []byte(updated), 0644); err != nil {
+		return written, fmt.Errorf("failed to update %s: %w", registerCommandsFile, err)
+	}
+	written = append(written, registerCommandsFile)
+
+	return written, nil
+}
+
+// addCommandRegistration 在 RegisterAllTradingCommands 中加入新策略命令的注册调用
+func addCommandRegistration(source string, names StrategyNames) (string, error) {
+	call := fmt.Sprintf("Register%sCmd()", names.Camel)
+	if strings.Contains(source, call) {
+		return "", fmt.Errorf("%s is already registered in %s", call, registerCommandsFile)
+	}
+
+	index := strings.Index(source, registerCommandsMarker)
+	if index < 0 {
+		return "", fmt.Errorf("registration marker not found in %s", registerCommandsFile)
+	}
+
+	return source[:index] + "\n\t" + call + source[index:], nil
+}
+
+// render 渲染模板并格式化
+func render(name, tmpl string, names StrategyNames) ([]byte, error) {
+	t, err := template.New(name).Funcs(template.FuncMap{
+		// tag 生成结构体 json 标签（模板写在反引号字符串中，无法直接包含反引号）
+		"tag": func(key string) string { return "`json:\"" + key + "\"`" },
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, names); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated %s is not valid Go: %w", name, err)
+	}
+	return formatted, nil
+}
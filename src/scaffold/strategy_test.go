@@ -0,0 +1,81 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrategyName(t *testing.T) {
+	for _, name := range []string{"mean-reversion", "mean_reversion", "Mean_Reversion"} {
+		names, err := ParseStrategyName(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, StrategyNames{
+			Snake:   "mean_reversion",
+			Camel:   "MeanReversion",
+			Command: "mean-reversion",
+			Display: "Mean Reversion",
+		}, names)
+	}
+
+	for _, name := range []string{"", "1macd", "mean reversion", "mean--reversion", "rsi-", "bollinger"} {
+		_, err := ParseStrategyName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRenderStrategy(t *testing.T) {
+	names, err := ParseStrategyName("rsi2")
+	require.NoError(t, err)
+
+	files, err := RenderStrategy(names)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	fset := token.NewFileSet()
+	for _, f := range files {
+		_, err := parser.ParseFile(fset, f.Path, f.Content, parser.AllErrors)
+		assert.NoError(t, err, f.Path)
+		assert.NotContains(t, string(f.Content), "{{.", f.Path)
+	}
+
+	assert.Equal(t, filepath.Join("src", "strategies", "rsi2_strategy.go"), files[1].Path)
+	assert.Contains(t, string(files[1].Content), `RegisterStrategy("rsi2"`)
+	assert.Contains(t, string(files[0].Content), "`json:\"period\"`")
+	assert.Contains(t, string(files[3].Content), `cmd.RegisterCmd("rsi2"`)
+}
+
+func TestGenerateStrategy(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"strategy", "strategies", "cmd"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "src", dir), 0755))
+	}
+	registerSource := "package cmd\n\nfunc RegisterAllTradingCommands() {\n\tRegisterBollingerTradingCmd()\n\n\t// 可以添加其他交易策略命令\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, registerCommandsFile), []byte(registerSource), 0644))
+
+	names, err := ParseStrategyName("mean-reversion")
+	require.NoError(t, err)
+
+	written, err := GenerateStrategy(root, names)
+	require.NoError(t, err)
+	assert.Len(t, written, 5)
+
+	updated, err := os.ReadFile(filepath.Join(root, registerCommandsFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "\tRegisterBollingerTradingCmd()\n\tRegisterMeanReversionCmd()\n\n\t// 可以添加其他交易策略命令")
+
+	// 再次生成不覆盖已有文件
+	_, err = GenerateStrategy(root, names)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "already exists"))
+
+	// 非项目根目录
+	_, err = GenerateStrategy(t.TempDir(), names)
+	assert.Error(t, err)
+}
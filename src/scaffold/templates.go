@@ -0,0 +1,302 @@
+package scaffold
+
+// paramsTemplate 策略参数：结构体、默认值和 Validate
+const paramsTemplate = `package strategy
+
+import "fmt"
+
+// {{.Camel}}Params {{.Display}} 策略参数
+type {{.Camel}}Params struct {
+	Period    int     {{tag "period"}}    // 均值计算周期
+	Threshold float64 {{tag "threshold"}} // 偏离均值的比例阈值，如 0.02 表示 2%
+}
+
+// GetDefault{{.Camel}}Params 获取默认的 {{.Display}} 策略参数
+func GetDefault{{.Camel}}Params() *{{.Camel}}Params {
+	return &{{.Camel}}Params{
+		Period:    20,
+		Threshold: 0.02,
+	}
+}
+
+// Validate 验证参数有效性
+func (p *{{.Camel}}Params) Validate() error {
+	if p.Period <= 0 {
+		return fmt.Errorf("period must be positive, got %d", p.Period)
+	}
+	if p.Threshold <= 0 || p.Threshold >= 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %f", p.Threshold)
+	}
+	return nil
+}
+`
+
+// strategyTemplate 策略实现：实现 strategy.Strategy 并在 init 中注册
+const strategyTemplate = `package strategies
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	RegisterStrategy("{{.Snake}}", func() strategy.Strategy { return New{{.Camel}}Strategy() })
+}
+
+// {{.Camel}}Strategy {{.Display}} 策略
+type {{.Camel}}Strategy struct {
+	params       strategy.{{.Camel}}Params
+	priceHistory []decimal.Decimal
+}
+
+// New{{.Camel}}Strategy 创建 {{.Display}} 策略（使用默认参数）
+func New{{.Camel}}Strategy() *{{.Camel}}Strategy {
+	return &{{.Camel}}Strategy{
+		params: *strategy.GetDefault{{.Camel}}Params(),
+	}
+}
+
+// OnData 处理新的K线数据，返回交易信号
+// TODO: 替换为实际的交易逻辑。示例：收盘价低于均值 Threshold 时买入，高于均值 Threshold 时卖出
+func (s *{{.Camel}}Strategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	s.priceHistory = append(s.priceHistory, kline.Close)
+	if len(s.priceHistory) > s.params.Period {
+		s.priceHistory = s.priceHistory[1:]
+	}
+	if len(s.priceHistory) < s.params.Period {
+		return nil, nil
+	}
+
+	mean := s.mean()
+	threshold := decimal.NewFromFloat(s.params.Threshold)
+	lower := mean.Mul(decimal.NewFromInt(1).Sub(threshold))
+	upper := mean.Mul(decimal.NewFromInt(1).Add(threshold))
+
+	if portfolio.Position.IsZero() && kline.Close.LessThan(lower) {
+		return []*strategy.Signal{{"{{"}}
+			Type:      "BUY",
+			Reason:    fmt.Sprintf("price %s below mean %s", kline.Close.StringFixed(4), mean.StringFixed(4)),
+			Strength:  1.0,
+			Timestamp: kline.OpenTime.Unix() * 1000,
+		{{"}}"}}, nil
+	}
+
+	if portfolio.Position.IsPositive() && kline.Close.GreaterThan(upper) {
+		return []*strategy.Signal{{"{{"}}
+			Type:      "SELL",
+			Reason:    fmt.Sprintf("price %s above mean %s", kline.Close.StringFixed(4), mean.StringFixed(4)),
+			Strength:  1.0,
+			Timestamp: kline.OpenTime.Unix() * 1000,
+		{{"}}"}}, nil
+	}
+
+	return nil, nil
+}
+
+// mean 计算价格历史均值
+func (s *{{.Camel}}Strategy) mean() decimal.Decimal {
+	sum := decimal.Zero
+	for _, price := range s.priceHistory {
+		sum = sum.Add(price)
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(s.priceHistory))))
+}
+
+// GetName 获取策略名称
+func (s *{{.Camel}}Strategy) GetName() string {
+	return "{{.Display}} Strategy"
+}
+
+// GetParams 获取策略参数
+func (s *{{.Camel}}Strategy) GetParams() strategy.StrategyParams {
+	params := s.params
+	return &params
+}
+
+// SetParams 设置策略参数
+func (s *{{.Camel}}Strategy) SetParams(params strategy.StrategyParams) error {
+	p, ok := params.(*strategy.{{.Camel}}Params)
+	if !ok {
+		return fmt.Errorf("invalid params type for {{.Display}} strategy: %T", params)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	s.params = *p
+	s.priceHistory = nil
+	return nil
+}
+
+// Save 序列化内部状态（实盘重启后由引擎恢复），无需恢复的状态可返回 nil
+func (s *{{.Camel}}Strategy) Save() ([]byte, error) {
+	return nil, nil
+}
+
+// Load 恢复 Save 保存的状态
+func (s *{{.Camel}}Strategy) Load(data []byte) error {
+	return nil
+}
+`
+
+// strategyTestTemplate 策略测试骨架：使用 enginetest 在回测引擎上运行
+const strategyTestTemplate = `package strategies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/engine/enginetest"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test{{.Camel}}Strategy_SetParams(t *testing.T) {
+	s := New{{.Camel}}Strategy()
+	assert.Equal(t, "{{.Display}} Strategy", s.GetName())
+	require.NoError(t, s.GetParams().Validate())
+
+	err := s.SetParams(&strategy.{{.Camel}}Params{Period: 0, Threshold: 0.02})
+	assert.Error(t, err)
+
+	err = s.SetParams(&strategy.{{.Camel}}Params{Period: 3, Threshold: 0.05})
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.GetParams().(*strategy.{{.Camel}}Params).Period)
+}
+
+func Test{{.Camel}}Strategy_Backtest(t *testing.T) {
+	s := New{{.Camel}}Strategy()
+	require.NoError(t, s.SetParams(&strategy.{{.Camel}}Params{Period: 3, Threshold: 0.05}))
+
+	// TODO: 构造能触发策略逻辑的价格序列
+	klines := enginetest.KlinesFromCloses(4*time.Hour, 100, 100, 100, 90, 85, 110, 110, 110)
+	result, err := enginetest.RunBacktest(context.Background(), s, timeframes.Timeframe4h, klines, 10000)
+	require.NoError(t, err)
+
+	// TODO: 断言策略行为（下单次数、持仓、收益等）
+	assert.Len(t, result.Engine.GetEquityCurve(), len(klines))
+	assert.NotEmpty(t, result.Orders)
+}
+`
+
+// commandTemplate CLI 命令：参数 flag 与回测入口
+const commandTemplate = `package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+
+	"github.com/xpwu/go-cmd/arg"
+	"github.com/xpwu/go-cmd/cmd"
+)
+
+// Register{{.Camel}}Cmd 注册 {{.Display}} 策略回测命令
+func Register{{.Camel}}Cmd() {
+	var base string
+	var quote string
+	var timeframe string
+	var cex string
+	var startDate string
+	var endDate string
+	var initialCapital float64
+
+	// 策略参数
+	var period int
+	var threshold float64
+
+	cmd.RegisterCmd("{{.Command}}", "run {{.Display}} strategy backtest", func(args *arg.Arg) {
+		args.String(&base, "base", "base currency (e.g., BTC, ETH)")
+		args.String(&quote, "quote", "quote currency (e.g., USDT)")
+		args.String(&timeframe, "t", "timeframe (e.g., 1h, 4h, 1d)")
+		args.String(&cex, "cex", "centralized exchange (default: binance)")
+		args.String(&startDate, "start", "backtest start date (YYYY-MM-DD) - required")
+		args.String(&endDate, "end", "backtest end date (YYYY-MM-DD)")
+		args.Float64(&initialCapital, "capital", "initial capital (default: 10000.0)")
+
+		args.Int(&period, "period", "mean period (default: 20)")
+		args.Float64(&threshold, "threshold", "deviation from mean to trade (default: 0.02)")
+
+		args.Parse()
+
+		if base == "" || quote == "" || startDate == "" {
+			fmt.Printf("❌ Error: -base, -quote and -start are required\n")
+			fmt.Printf("💡 Usage: ./bin/tradingbot {{.Command}} -base BTC -quote USDT -start 2024-01-01 [-end 2024-06-30] [-t 4h]\n")
+			os.Exit(1)
+		}
+		if endDate == "" {
+			endDate = time.Now().Format("2006-01-02 15:04:05")
+		}
+		if timeframe == "" {
+			timeframe = "4h"
+		}
+		if cex == "" {
+			cex = "binance"
+		}
+		if initialCapital == 0 {
+			initialCapital = 10000.0
+		}
+
+		params := strategy.GetDefault{{.Camel}}Params()
+		if period != 0 {
+			params.Period = period
+		}
+		if threshold != 0 {
+			params.Threshold = threshold
+		}
+
+		if err := run{{.Camel}}Backtest(base, quote, timeframe, cex, startDate, endDate, initialCapital, params); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	})
+}
+
+// run{{.Camel}}Backtest 运行 {{.Display}} 策略回测
+func run{{.Camel}}Backtest(base, quote, timeframe, cex, startDate, endDate string, initialCapital float64, params *strategy.{{.Camel}}Params) error {
+	fmt.Println("📈 {{.Display}} Strategy Backtest")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📊 Trading Pair: %s/%s\n", base, quote)
+	fmt.Printf("⏰ Timeframe: %s\n", timeframe)
+	fmt.Printf("📅 Period: %s ~ %s\n", startDate, endDate)
+	fmt.Printf("💰 Initial Capital: $%.2f\n", initialCapital)
+
+	strategyImpl := strategies.New{{.Camel}}Strategy()
+	if err := strategyImpl.SetParams(params); err != nil {
+		return fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+
+	tradingSystem, err := trading.NewTradingSystem()
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer tradingSystem.Stop()
+
+	pair := trading.CreateTradingPair(base, quote)
+	if err := tradingSystem.SetTradingPairTimeframeAndCEX(pair, timeframe, cex); err != nil {
+		return fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+
+	stats, err := tradingSystem.RunBacktestWithStrategy(pair, startDate, endDate, initialCapital, strategyImpl)
+	if err != nil {
+		return err
+	}
+
+	tradingSystem.PrintBacktestResults(pair, stats)
+	return nil
+}
+`
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"tradingbot/src/envconfig"
+	"tradingbot/src/optimizer"
+	"tradingbot/src/timeframes"
+
+	"github.com/xpwu/go-config/configs"
+)
+
+// 任务类型
+const (
+	JobTypeBacktest = "backtest" // 用当前参数回测最近一段时间
+	JobTypeOptimize = "optimize" // 网格搜索最近一段时间的最优参数，可推送给机器人
+)
+
+// 参数推送方式
+const (
+	ApprovalManual = "manual" // 生成待审批提案，人工批准后推送
+	ApprovalAuto   = "auto"   // 直接推送
+)
+
+// Config 定时任务配置
+type Config struct {
+	Jobs          []JobConfig `json:"jobs"`
+	ProposalsFile string      `json:"proposals_file"` // 参数提案保存文件（JSON），为空时只保存在内存中，重启后丢失
+}
+
+// JobConfig 单个定时任务配置
+type JobConfig struct {
+	Name           string  `json:"name"`            // 名称（唯一）
+	Schedule       string  `json:"schedule"`        // 运行计划（UTC）：cron "30 2 * * *"、@daily、@every 12h 等
+	Type           string  `json:"type"`            // backtest 或 optimize
+	Bot            string  `json:"bot"`             // 关联的机器人：交易对、周期、交易所和当前参数取自机器人配置，优化结果推送给它
+	Exchange       string  `json:"exchange"`        // 交易所，默认 binance（设置了 Bot 时忽略）
+	Base           string  `json:"base"`            // 基础货币（设置了 Bot 时忽略）
+	Quote          string  `json:"quote"`           // 计价货币（设置了 Bot 时忽略）
+	Timeframe      string  `json:"timeframe"`       // K线周期，默认使用交易配置的 Timeframe（设置了 Bot 时忽略）
+	ParamsFile     string  `json:"params_file"`     // 布林道参数文件，为空时使用默认参数（设置了 Bot 时使用机器人的参数文件）
+	LookbackDays   int     `json:"lookback_days"`   // 使用截至运行时最近多少天的K线（缺失部分从交易所补齐），默认 90
+	InitialCapital float64 `json:"initial_capital"` // 回测初始资金，默认 10000
+	SaveResults    bool    `json:"save_results"`    // backtest：结果存入数据库，可在监控面板中浏览
+	Ranges         string  `json:"ranges"`          // optimize：参数扫描范围，格式同 bollinger -optimize -ranges
+	Objective      string  `json:"objective"`       // optimize：优化目标，默认 sharpe
+	Workers        int     `json:"workers"`         // optimize：并发回测数，默认 GOMAXPROCS
+	Approval       string  `json:"approval"`        // optimize：manual（默认）或 auto
+	MinScoreGain   float64 `json:"min_score_gain"`  // optimize：最优参数得分至少比当前参数高出多少才推送
+}
+
+// ConfigValue 定时任务配置实例
+var ConfigValue = Config{
+	Jobs:          []JobConfig{},
+	ProposalsFile: "",
+}
+
+func init() {
+	configs.Unmarshal(&ConfigValue)
+	envconfig.Register("SCHEDULER", &ConfigValue)
+}
+
+// Validate 检查任务配置（机器人是否存在由 Service 检查）
+func (c JobConfig) Validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, " /") {
+		return fmt.Errorf("invalid job name %q: must be non-empty without spaces or slashes", c.Name)
+	}
+	if _, err := ParseSchedule(c.Schedule); err != nil {
+		return fmt.Errorf("job %s: %w", c.Name, err)
+	}
+	if c.Type != JobTypeBacktest && c.Type != JobTypeOptimize {
+		return fmt.Errorf("job %s: unknown type %q (must be %s or %s)", c.Name, c.Type, JobTypeBacktest, JobTypeOptimize)
+	}
+	if c.Bot == "" {
+		if c.Base == "" || c.Quote == "" {
+			return fmt.Errorf("job %s: bot or base/quote is required", c.Name)
+		}
+		if c.Timeframe != "" {
+			if _, err := timeframes.ParseTimeframe(c.Timeframe); err != nil {
+				return fmt.Errorf("job %s: %w", c.Name, err)
+			}
+		}
+	}
+	if c.LookbackDays < 0 || c.InitialCapital < 0 || c.Workers < 0 || c.MinScoreGain < 0 {
+		return fmt.Errorf("job %s: LookbackDays, InitialCapital, Workers and MinScoreGain must not be negative", c.Name)
+	}
+	if c.Type == JobTypeOptimize {
+		if _, err := optimizer.ParseParamRanges(c.ranges()); err != nil {
+			return fmt.Errorf("job %s: invalid ranges: %w", c.Name, err)
+		}
+		if _, err := optimizer.ParseObjective(c.objective()); err != nil {
+			return fmt.Errorf("job %s: %w", c.Name, err)
+		}
+		if c.Approval != "" && c.Approval != ApprovalManual && c.Approval != ApprovalAuto {
+			return fmt.Errorf("job %s: unknown approval %q (must be %s or %s)", c.Name, c.Approval, ApprovalManual, ApprovalAuto)
+		}
+	}
+	return nil
+}
+
+// Validate 检查所有任务配置
+func (c Config) Validate() error {
+	names := make(map[string]bool, len(c.Jobs))
+	for _, job := range c.Jobs {
+		if err := job.Validate(); err != nil {
+			return err
+		}
+		if names[job.Name] {
+			return fmt.Errorf("duplicate job name %s", job.Name)
+		}
+		names[job.Name] = true
+	}
+	return nil
+}
+
+// lookbackDays 回测天数，默认 90
+func (c JobConfig) lookbackDays() int {
+	if c.LookbackDays == 0 {
+		return 90
+	}
+	return c.LookbackDays
+}
+
+// initialCapital 回测初始资金，默认 10000
+func (c JobConfig) initialCapital() float64 {
+	if c.InitialCapital == 0 {
+		return 10000
+	}
+	return c.InitialCapital
+}
+
+// ranges 参数扫描范围，默认与 bollinger -optimize 一致
+func (c JobConfig) ranges() string {
+	if c.Ranges == "" {
+		return "period=10:50:5,multiplier=1.5:3.0:0.25"
+	}
+	return c.Ranges
+}
+
+// objective 优化目标，默认 sharpe
+func (c JobConfig) objective() string {
+	if c.Objective == "" {
+		return string(optimizer.ObjectiveSharpe)
+	}
+	return c.Objective
+}
+
+// approval 参数推送方式，默认人工审批
+func (c JobConfig) approval() string {
+	if c.Approval == "" {
+		return ApprovalManual
+	}
+	return c.Approval
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"tradingbot/src/dashboard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	// 2024-01-03 是周三
+	after := time.Date(2024, 1, 3, 10, 17, 30, 0, time.UTC)
+
+	for _, tc := range []struct {
+		spec string
+		next time.Time
+	}{
+		{"30 2 * * *", time.Date(2024, 1, 4, 2, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 3, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 1, 3, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 6,7", time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// 日和周都有限定时满足其一即可：15 号或周五
+		{"0 0 15 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", time.Date(2024, 1, 3, 16, 17, 30, 0, time.UTC)},
+	} {
+		schedule, err := ParseSchedule(tc.spec)
+		require.NoError(t, err, tc.spec)
+		assert.Equal(t, tc.next, schedule.Next(after), tc.spec)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "0 0 30 2 *", "@every 10s", "@every soon"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	now := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	runs := make(map[string]int)
+	release := make(chan struct{})
+
+	s := NewScheduler(ctx)
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	// 模拟时钟：等快速任务跑完后直接跳到下一次运行时间，跑完 7 小时后停止
+	s.sleep = func(ctx context.Context, d time.Duration) error {
+		require.Eventually(t, func() bool {
+			statuses := s.Statuses()
+			return !statuses[0].Running && !statuses[1].Running
+		}, time.Second, time.Millisecond)
+
+		mu.Lock()
+		now = now.Add(d)
+		done := now.After(time.Date(2024, 1, 3, 7, 0, 0, 0, time.UTC))
+		mu.Unlock()
+		if done {
+			cancel()
+		}
+		return ctx.Err()
+	}
+
+	count := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			runs[name]++
+			mu.Unlock()
+			return nil
+		}
+	}
+	require.NoError(t, s.Add("hourly", JobTypeBacktest, "@hourly", count("hourly")))
+	require.NoError(t, s.Add("every-3h", JobTypeOptimize, "@every 3h", count("every-3h")))
+	// 一直运行的任务：后续到期时跳过
+	require.NoError(t, s.Add("slow", JobTypeOptimize, "0 */2 * * *", func(ctx context.Context) error {
+		mu.Lock()
+		runs["slow"]++
+		mu.Unlock()
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return errors.New("interrupted")
+	}))
+	assert.Error(t, s.Add("hourly", JobTypeBacktest, "@daily", count("hourly")))
+	assert.Error(t, s.Add("bad", JobTypeBacktest, "daily", count("bad")))
+
+	s.Run()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 7, runs["hourly"])
+	assert.Equal(t, 2, runs["every-3h"])
+	assert.Equal(t, 1, runs["slow"])
+
+	statuses := s.Statuses()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "hourly", statuses[0].Name)
+	assert.False(t, statuses[2].Running)
+	assert.Equal(t, "interrupted", statuses[2].LastError)
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewScheduler(ctx)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	require.NoError(t, s.Add("nightly", JobTypeOptimize, "@daily", func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}))
+
+	require.NoError(t, s.Trigger("nightly"))
+	<-started
+	assert.True(t, s.Statuses()[0].Running)
+	assert.Error(t, s.Trigger("nightly"))
+	assert.ErrorIs(t, s.Trigger("unknown"), dashboard.ErrJobNotFound)
+
+	close(release)
+	require.Eventually(t, func() bool { return !s.Statuses()[0].Running }, time.Second, 5*time.Millisecond)
+	assert.NotNil(t, s.Statuses()[0].LastRun)
+	assert.Empty(t, s.Statuses()[0].LastError)
+}
@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"tradingbot/src/dashboard"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// JobFunc 定时任务，ctx 取消时应尽快返回
+type JobFunc func(ctx context.Context) error
+
+// Scheduler 按计划在后台运行任务：同一任务上一次尚未结束时跳过本次，错过的运行不补跑
+type Scheduler struct {
+	ctx   context.Context
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu    sync.Mutex
+	names []string // 添加顺序
+	jobs  map[string]*scheduledJob
+	wg    sync.WaitGroup
+}
+
+// scheduledJob 计划中的一个任务
+type scheduledJob struct {
+	name         string
+	kind         string
+	spec         string
+	schedule     Schedule
+	run          JobFunc
+	next         time.Time
+	running      bool
+	lastRun      *time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// NewScheduler 创建调度器，ctx 取消时 Run 返回，正在运行的任务收到取消
+func NewScheduler(ctx context.Context) *Scheduler {
+	return &Scheduler{
+		ctx:   ctx,
+		now:   time.Now,
+		sleep: sleepContext,
+		jobs:  make(map[string]*scheduledJob),
+	}
+}
+
+// sleepContext 等待 d 或 ctx 取消
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Add 添加任务（需在 Run 之前调用），kind 只用于展示
+func (s *Scheduler) Add(name, kind, spec string, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("duplicate job name %s", name)
+	}
+	s.names = append(s.names, name)
+	s.jobs[name] = &scheduledJob{
+		name:     name,
+		kind:     kind,
+		spec:     spec,
+		schedule: schedule,
+		run:      run,
+		next:     schedule.Next(s.now()),
+	}
+	return nil
+}
+
+// Run 阻塞运行直到 ctx 取消，返回前等待正在运行的任务退出
+func (s *Scheduler) Run() {
+	defer s.wg.Wait()
+
+	for {
+		next := s.nextRun()
+		if next.IsZero() {
+			<-s.ctx.Done()
+			return
+		}
+		if err := s.sleep(s.ctx, next.Sub(s.now())); err != nil {
+			return
+		}
+		s.runDue(s.now())
+	}
+}
+
+// nextRun 最早的下一次运行时间，没有任务时返回零值
+func (s *Scheduler) nextRun() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	for _, job := range s.jobs {
+		if next.IsZero() || job.next.Before(next) {
+			next = job.next
+		}
+	}
+	return next
+}
+
+// runDue 启动所有到期的任务并计算下一次运行时间
+func (s *Scheduler) runDue(now time.Time) {
+	_, logger := log.WithCtx(s.ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, name := range s.names {
+		job := s.jobs[name]
+		if job.next.After(now) {
+			continue
+		}
+		job.next = job.schedule.Next(now)
+		if job.running {
+			logger.Warning(fmt.Sprintf("⏭️ 定时任务上一次运行尚未结束，跳过本次: job=%s", name))
+			continue
+		}
+		s.start(job)
+	}
+}
+
+// Trigger 立即在后台运行任务（不影响计划中的下一次运行）
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", dashboard.ErrJobNotFound, name)
+	}
+	if job.running {
+		return fmt.Errorf("job %s is already running", name)
+	}
+	s.start(job)
+	return nil
+}
+
+// start 后台运行任务（调用方持有锁）
+func (s *Scheduler) start(job *scheduledJob) {
+	ctx, logger := log.WithCtx(s.ctx)
+	logger.PushPrefix(fmt.Sprintf("job=%s", job.name))
+
+	startedAt := s.now()
+	job.running = true
+	job.lastRun = &startedAt
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		logger.Info(fmt.Sprintf("⏰ 定时任务开始: type=%s", job.kind))
+		err := job.run(ctx)
+		duration := s.now().Sub(startedAt)
+
+		s.mu.Lock()
+		job.running = false
+		job.lastDuration = duration
+		job.lastErr = err
+		s.mu.Unlock()
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("❌ 定时任务失败: duration=%s, error=%v", duration.Round(time.Second), err))
+			return
+		}
+		logger.Info(fmt.Sprintf("✅ 定时任务完成: duration=%s", duration.Round(time.Second)))
+	}()
+}
+
+// Statuses 所有任务的状态（按添加顺序）
+func (s *Scheduler) Statuses() []*dashboard.ScheduleJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]*dashboard.ScheduleJobStatus, 0, len(s.names))
+	for _, name := range s.names {
+		job := s.jobs[name]
+		next := job.next
+		status := &dashboard.ScheduleJobStatus{
+			Name:     job.name,
+			Type:     job.kind,
+			Schedule: job.spec,
+			Running:  job.running,
+			NextRun:  &next,
+			LastRun:  job.lastRun,
+		}
+		if job.lastRun != nil && !job.running {
+			status.LastDuration = job.lastDuration.Round(time.Second).String()
+		}
+		if job.lastErr != nil {
+			status.LastError = job.lastErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,448 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
+	"tradingbot/src/optimizer"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+	"tradingbot/src/trading"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// ParamsTarget 接收优化参数的机器人（由 trading.BotManager 实现）
+type ParamsTarget interface {
+	Bot(name string) (trading.BotConfig, error)
+	UpdateBotParams(name string, params *strategy.BollingerBandsParams) error
+}
+
+// BacktestSession 一次任务运行中在同一段K线上反复回测
+type BacktestSession interface {
+	Backtest(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error)
+	Save(params *strategy.BollingerBandsParams, stats *trading.BacktestStatistics) (string, error)
+}
+
+// BacktestTarget 回测的交易对、周期和区间
+type BacktestTarget struct {
+	Exchange  string
+	Pair      cex.TradingPair
+	Timeframe timeframes.Timeframe
+	Start     time.Time
+	End       time.Time
+	Capital   float64
+}
+
+// sessionOpener 加载回测K线（缺失部分从交易所补齐）
+type sessionOpener func(ctx context.Context, target BacktestTarget) (BacktestSession, error)
+
+// Service 定时回测和参数优化：按计划在最近的K线上回测或网格搜索，
+// 优化结果优于机器人当前参数时生成参数提案，自动或人工批准后推送给机器人
+type Service struct {
+	ctx       context.Context
+	scheduler *Scheduler
+	target    ParamsTarget
+	open      sessionOpener
+	now       func() time.Time
+
+	mu            sync.Mutex
+	proposals     []*dashboard.ParamsProposal // 按创建时间顺序
+	proposalsFile string
+}
+
+// NewService 按配置创建定时任务服务（不启动调度），target 为空时关联机器人的任务报错
+func NewService(ctx context.Context, config Config, target ParamsTarget) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schedule config: %w", err)
+	}
+
+	service := &Service{
+		ctx:           ctx,
+		scheduler:     NewScheduler(ctx),
+		target:        target,
+		open:          openBacktestSession,
+		now:           time.Now,
+		proposalsFile: config.ProposalsFile,
+	}
+	if err := service.loadProposals(); err != nil {
+		return nil, err
+	}
+
+	for _, job := range config.Jobs {
+		if job.Bot != "" {
+			if target == nil {
+				return nil, fmt.Errorf("job %s: bot %s requires the bot manager", job.Name, job.Bot)
+			}
+			bot, err := target.Bot(job.Bot)
+			if err != nil {
+				return nil, fmt.Errorf("job %s: %w", job.Name, err)
+			}
+			if bot.Strategy != "" && bot.Strategy != "bollinger" {
+				return nil, fmt.Errorf("job %s: bot %s uses strategy %s, only bollinger bots can be scheduled", job.Name, job.Bot, bot.Strategy)
+			}
+		}
+
+		job := job
+		if err := service.scheduler.Add(job.Name, job.Type, job.Schedule, func(ctx context.Context) error {
+			return service.runJob(ctx, job)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+// Run 阻塞运行调度直到 ctx 取消
+func (s *Service) Run() {
+	s.scheduler.Run()
+}
+
+// ScheduleJobs 所有任务的状态
+func (s *Service) ScheduleJobs() []*dashboard.ScheduleJobStatus {
+	return s.scheduler.Statuses()
+}
+
+// RunScheduleJob 立即在后台运行任务
+func (s *Service) RunScheduleJob(name string) error {
+	return s.scheduler.Trigger(name)
+}
+
+// runJob 运行一次任务（结束时停止本次使用的交易系统）
+func (s *Service) runJob(ctx context.Context, job JobConfig) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	target, params, err := s.resolve(job)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.open(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if job.Type == JobTypeBacktest {
+		return s.runBacktest(ctx, job, session, params)
+	}
+	return s.runOptimize(ctx, job, session, params)
+}
+
+// resolve 回测目标和当前参数（关联机器人时取自机器人配置）
+func (s *Service) resolve(job JobConfig) (BacktestTarget, *strategy.BollingerBandsParams, error) {
+	exchange, base, quote, timeframe, paramsFile := job.Exchange, job.Base, job.Quote, job.Timeframe, job.ParamsFile
+	if job.Bot != "" {
+		bot, err := s.target.Bot(job.Bot)
+		if err != nil {
+			return BacktestTarget{}, nil, err
+		}
+		exchange, base, quote, timeframe, paramsFile = bot.Exchange, bot.Base, bot.Quote, bot.Timeframe, bot.ParamsFile
+	}
+	if exchange == "" {
+		exchange = "binance"
+	}
+	if timeframe == "" {
+		timeframe = trading.TradingConfigValue.Timeframe
+	}
+	tf, err := timeframes.ParseTimeframe(timeframe)
+	if err != nil {
+		return BacktestTarget{}, nil, err
+	}
+
+	params := strategy.GetDefaultBollingerBandsParams()
+	if paramsFile != "" {
+		if params, err = strategy.LoadBollingerBandsParamsFile(paramsFile, params); err != nil {
+			return BacktestTarget{}, nil, err
+		}
+	}
+
+	end := s.now().UTC().Truncate(time.Minute)
+	return BacktestTarget{
+		Exchange:  exchange,
+		Pair:      trading.CreateTradingPair(base, quote),
+		Timeframe: tf,
+		Start:     end.AddDate(0, 0, -job.lookbackDays()),
+		End:       end,
+		Capital:   job.initialCapital(),
+	}, params, nil
+}
+
+// runBacktest 用当前参数回测并记录结果
+func (s *Service) runBacktest(ctx context.Context, job JobConfig, session BacktestSession, params *strategy.BollingerBandsParams) error {
+	_, logger := log.WithCtx(ctx)
+
+	stats, err := session.Backtest(ctx, params)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("📊 定时回测结果: return=%s%%, sharpe=%s, max_drawdown=%s%%, trades=%d",
+		stats.TotalReturn.Mul(hundred).StringFixed(2), stats.SharpeRatio.StringFixed(2),
+		stats.MaxDrawdownPercent.StringFixed(2), stats.TotalTrades))
+
+	if job.SaveResults {
+		runID, err := session.Save(params, stats)
+		if err != nil {
+			return fmt.Errorf("failed to save backtest results: %w", err)
+		}
+		logger.Info(fmt.Sprintf("💾 回测结果已保存: run_id=%s", runID))
+	}
+	return nil
+}
+
+// runOptimize 网格搜索最优参数，优于当前参数时为关联的机器人生成提案
+func (s *Service) runOptimize(ctx context.Context, job JobConfig, session BacktestSession, current *strategy.BollingerBandsParams) error {
+	_, logger := log.WithCtx(ctx)
+
+	ranges, err := optimizer.ParseParamRanges(job.ranges())
+	if err != nil {
+		return err
+	}
+	objective, err := optimizer.ParseObjective(job.objective())
+	if err != nil {
+		return err
+	}
+
+	currentStats, err := session.Backtest(ctx, current)
+	if err != nil {
+		return fmt.Errorf("failed to backtest current params: %w", err)
+	}
+	currentScore := objective.Score(currentStats)
+
+	results, err := optimizer.NewGridOptimizer(current, ranges, objective, job.Workers).Run(ctx, session.Backtest)
+	if err != nil {
+		return fmt.Errorf("optimization failed: %w", err)
+	}
+	if len(results) == 0 || results[0].Err != nil {
+		return fmt.Errorf("optimization failed: no successful backtests")
+	}
+	best := results[0]
+	logger.Info(fmt.Sprintf("🏆 定时优化结果: objective=%s, best_score=%.4f, current_score=%.4f, period=%d, multiplier=%.2f",
+		objective, best.Score, currentScore, best.Params.Period, best.Params.Multiplier))
+
+	if job.Bot == "" {
+		return nil
+	}
+	if reflect.DeepEqual(best.Params, current) || best.Score-currentScore < job.MinScoreGain || best.Score <= currentScore {
+		logger.Info(fmt.Sprintf("🟰 当前参数仍然可用，不推送: bot=%s, min_score_gain=%.4f", job.Bot, job.MinScoreGain))
+		return nil
+	}
+
+	proposal, err := s.propose(job, objective, best, currentScore)
+	if err != nil {
+		return err
+	}
+	if job.approval() == ApprovalManual {
+		logger.Info(fmt.Sprintf("📝 参数提案等待审批: id=%s, bot=%s", proposal.ID, job.Bot))
+		return nil
+	}
+	_, err = s.ApproveProposal(proposal.ID)
+	return err
+}
+
+// hundred 收益率转换为百分比
+var hundred = decimal.NewFromInt(100)
+
+// propose 新增待审批提案，同一机器人之前未处理的提案标记为被取代
+func (s *Service) propose(job JobConfig, objective optimizer.Objective, best *optimizer.Result, currentScore float64) (*dashboard.ParamsProposal, error) {
+	data, err := json.MarshalIndent(best.Params, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	for _, proposal := range s.proposals {
+		if proposal.Bot == job.Bot && proposal.State == dashboard.ProposalStatePending {
+			proposal.State = dashboard.ProposalStateSuperseded
+			proposal.DecidedAt = &now
+		}
+	}
+	proposal := &dashboard.ParamsProposal{
+		ID:           fmt.Sprintf("%s-%s", job.Name, now.Format("20060102-150405")),
+		Job:          job.Name,
+		Bot:          job.Bot,
+		State:        dashboard.ProposalStatePending,
+		Objective:    string(objective),
+		Score:        best.Score,
+		CurrentScore: currentScore,
+		Params:       data,
+		CreatedAt:    now,
+	}
+	s.proposals = append(s.proposals, proposal)
+	if err := s.saveProposals(); err != nil {
+		return nil, err
+	}
+	copied := *proposal
+	return &copied, nil
+}
+
+// ParamsProposals 所有参数提案（最新的在前）
+func (s *Service) ParamsProposals() []*dashboard.ParamsProposal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proposals := make([]*dashboard.ParamsProposal, 0, len(s.proposals))
+	for i := len(s.proposals) - 1; i >= 0; i-- {
+		copied := *s.proposals[i]
+		proposals = append(proposals, &copied)
+	}
+	return proposals
+}
+
+// ApproveProposal 批准提案：写入机器人参数文件，机器人运行中时重启生效
+func (s *Service) ApproveProposal(id string) (*dashboard.ParamsProposal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proposal, err := s.pending(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.target == nil {
+		return nil, fmt.Errorf("bot manager is not running in this process")
+	}
+
+	var params strategy.BollingerBandsParams
+	if err := json.Unmarshal(proposal.Params, &params); err != nil {
+		return nil, fmt.Errorf("invalid proposal params: %w", err)
+	}
+	if err := s.target.UpdateBotParams(proposal.Bot, &params); err != nil {
+		return nil, fmt.Errorf("failed to update bot %s: %w", proposal.Bot, err)
+	}
+
+	_, logger := log.WithCtx(s.ctx)
+	logger.Info(fmt.Sprintf("✅ 参数提案已推送: id=%s, bot=%s", proposal.ID, proposal.Bot))
+	return s.decide(proposal, dashboard.ProposalStateApplied)
+}
+
+// RejectProposal 拒绝提案
+func (s *Service) RejectProposal(id string) (*dashboard.ParamsProposal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proposal, err := s.pending(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.decide(proposal, dashboard.ProposalStateRejected)
+}
+
+// pending 查找待审批的提案（调用方持有锁）
+func (s *Service) pending(id string) (*dashboard.ParamsProposal, error) {
+	for _, proposal := range s.proposals {
+		if proposal.ID != id {
+			continue
+		}
+		if proposal.State != dashboard.ProposalStatePending {
+			return nil, fmt.Errorf("proposal %s is already %s", id, proposal.State)
+		}
+		return proposal, nil
+	}
+	return nil, fmt.Errorf("%w: %s", dashboard.ErrProposalNotFound, id)
+}
+
+// decide 记录提案处理结果并保存（调用方持有锁）
+func (s *Service) decide(proposal *dashboard.ParamsProposal, state string) (*dashboard.ParamsProposal, error) {
+	now := s.now().UTC()
+	proposal.State = state
+	proposal.DecidedAt = &now
+	if err := s.saveProposals(); err != nil {
+		return nil, err
+	}
+	copied := *proposal
+	return &copied, nil
+}
+
+// loadProposals 从提案文件恢复（文件不存在时为空）
+func (s *Service) loadProposals() error {
+	if s.proposalsFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.proposalsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read proposals file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.proposals); err != nil {
+		return fmt.Errorf("failed to parse proposals file %s: %w", s.proposalsFile, err)
+	}
+	return nil
+}
+
+// saveProposals 写入提案文件（先写临时文件再替换，调用方持有锁）
+func (s *Service) saveProposals() error {
+	if s.proposalsFile == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.proposals, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.proposalsFile); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create proposals directory: %w", err)
+		}
+	}
+	tmp := s.proposalsFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write proposals file: %w", err)
+	}
+	return os.Rename(tmp, s.proposalsFile)
+}
+
+// backtestSession 基于交易系统的回测会话：K线只加载一次，所有回测共享（只读）
+type backtestSession struct {
+	ts     *trading.TradingSystem
+	target BacktestTarget
+	klines []*cex.KlineData
+}
+
+// openBacktestSession 创建交易系统并加载回测区间的K线，ctx 取消时交易系统停止
+func openBacktestSession(ctx context.Context, target BacktestTarget) (BacktestSession, error) {
+	ts, err := trading.NewTradingSystemWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trading system: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		ts.Stop()
+	}()
+
+	if err := ts.SetTradingPairTimeframeAndCEX(target.Pair, string(target.Timeframe), target.Exchange); err != nil {
+		return nil, fmt.Errorf("failed to set trading parameters: %w", err)
+	}
+	klines, err := ts.LoadBacktestKlines(target.Pair, target.Timeframe, target.Start, target.End)
+	if err != nil {
+		return nil, err
+	}
+	return &backtestSession{ts: ts, target: target, klines: klines}, nil
+}
+
+// Backtest 用给定参数回测
+func (b *backtestSession) Backtest(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+	return b.ts.RunBacktestOnKlines(ctx, b.target.Pair, b.target.Timeframe, b.klines, b.target.Start, b.target.End, b.target.Capital, params)
+}
+
+// Save 保存回测结果到数据库
+func (b *backtestSession) Save(params *strategy.BollingerBandsParams, stats *trading.BacktestStatistics) (string, error) {
+	strategyName := stats.StrategyName
+	if strategyName == "" {
+		strategyName = "bollinger"
+	}
+	return b.ts.SaveBacktestResults(b.target.Pair, strategyName, params, b.target.Start, b.target.End, stats)
+}
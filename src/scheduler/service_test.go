@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"tradingbot/src/dashboard"
+	"tradingbot/src/strategy"
+	"tradingbot/src/trading"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeParamsTarget 内存中的机器人配置，记录推送的参数
+type fakeParamsTarget struct {
+	mu      sync.Mutex
+	bots    map[string]trading.BotConfig
+	updated map[string]*strategy.BollingerBandsParams
+}
+
+func (f *fakeParamsTarget) Bot(name string) (trading.BotConfig, error) {
+	bot, exists := f.bots[name]
+	if !exists {
+		return trading.BotConfig{}, fmt.Errorf("%w: %s", dashboard.ErrBotNotFound, name)
+	}
+	return bot, nil
+}
+
+func (f *fakeParamsTarget) UpdateBotParams(name string, params *strategy.BollingerBandsParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated[name] = params
+	return nil
+}
+
+// fakeSession 按周期打分的回测：period 越接近 best 夏普越高
+type fakeSession struct {
+	best   int
+	target BacktestTarget
+	saved  int
+}
+
+func (f *fakeSession) Backtest(ctx context.Context, params *strategy.BollingerBandsParams) (*trading.BacktestStatistics, error) {
+	distance := params.Period - f.best
+	if distance < 0 {
+		distance = -distance
+	}
+	return &trading.BacktestStatistics{
+		TotalReturn: decimal.NewFromFloat(0.1),
+		SharpeRatio: decimal.NewFromInt(int64(10 - distance)),
+	}, nil
+}
+
+func (f *fakeSession) Save(params *strategy.BollingerBandsParams, stats *trading.BacktestStatistics) (string, error) {
+	f.saved++
+	return "run-1", nil
+}
+
+func newTestService(t *testing.T, config Config, session *fakeSession) (*Service, *fakeParamsTarget) {
+	target := &fakeParamsTarget{
+		bots: map[string]trading.BotConfig{
+			"btc-4h":  {Name: "btc-4h", Base: "BTC", Quote: "USDT", Timeframe: "4h"},
+			"eth-rsi": {Name: "eth-rsi", Base: "ETH", Quote: "USDT", Strategy: "rsi"},
+		},
+		updated: make(map[string]*strategy.BollingerBandsParams),
+	}
+	service, err := NewService(context.Background(), config, target)
+	require.NoError(t, err)
+	service.now = func() time.Time { return time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC) }
+	service.open = func(ctx context.Context, target BacktestTarget) (BacktestSession, error) {
+		session.target = target
+		return session, nil
+	}
+	return service, target
+}
+
+func TestService_OptimizeProposesParams(t *testing.T) {
+	config := Config{
+		Jobs: []JobConfig{
+			{Name: "nightly", Schedule: "30 2 * * *", Type: JobTypeOptimize, Bot: "btc-4h", Ranges: "period=10:30:5", LookbackDays: 30, MinScoreGain: 1},
+		},
+		ProposalsFile: filepath.Join(t.TempDir(), "proposals.json"),
+	}
+	session := &fakeSession{best: 25}
+	service, target := newTestService(t, config, session)
+
+	require.NoError(t, service.runJob(context.Background(), config.Jobs[0]))
+	assert.Equal(t, "BTC/USDT", session.target.Pair.String())
+	assert.Equal(t, "4h", string(session.target.Timeframe))
+	assert.Equal(t, "binance", session.target.Exchange)
+	assert.Equal(t, time.Date(2024, 1, 31, 2, 0, 0, 0, time.UTC), session.target.Start)
+
+	// 默认人工审批：只生成提案，不推送
+	proposals := service.ParamsProposals()
+	require.Len(t, proposals, 1)
+	proposal := proposals[0]
+	assert.Equal(t, dashboard.ProposalStatePending, proposal.State)
+	assert.Equal(t, "btc-4h", proposal.Bot)
+	assert.Equal(t, 10.0, proposal.Score)
+	assert.Equal(t, 5.0, proposal.CurrentScore)
+	assert.Empty(t, target.updated)
+
+	// 提案在重启后恢复
+	restored, _ := newTestService(t, config, session)
+	require.Len(t, restored.ParamsProposals(), 1)
+
+	approved, err := service.ApproveProposal(proposal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.ProposalStateApplied, approved.State)
+	assert.NotNil(t, approved.DecidedAt)
+	require.Contains(t, target.updated, "btc-4h")
+	assert.Equal(t, 25, target.updated["btc-4h"].Period)
+
+	_, err = service.ApproveProposal(proposal.ID)
+	assert.Error(t, err)
+	_, err = service.RejectProposal("unknown")
+	assert.ErrorIs(t, err, dashboard.ErrProposalNotFound)
+}
+
+func TestService_OptimizeAutoApproval(t *testing.T) {
+	job := JobConfig{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "btc-4h", Ranges: "period=10:30:5", Approval: ApprovalAuto}
+	service, target := newTestService(t, Config{Jobs: []JobConfig{job}}, &fakeSession{best: 15})
+
+	require.NoError(t, service.runJob(context.Background(), job))
+	require.Contains(t, target.updated, "btc-4h")
+	assert.Equal(t, 15, target.updated["btc-4h"].Period)
+	assert.Equal(t, dashboard.ProposalStateApplied, service.ParamsProposals()[0].State)
+}
+
+func TestService_OptimizeKeepsCurrentParams(t *testing.T) {
+	job := JobConfig{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "btc-4h", Ranges: "period=10:30:5"}
+
+	// 当前参数（period=20）已是最优
+	service, _ := newTestService(t, Config{Jobs: []JobConfig{job}}, &fakeSession{best: 20})
+	require.NoError(t, service.runJob(context.Background(), job))
+	assert.Empty(t, service.ParamsProposals())
+
+	// 提升不足 MinScoreGain
+	job.MinScoreGain = 6
+	service, _ = newTestService(t, Config{Jobs: []JobConfig{job}}, &fakeSession{best: 25})
+	require.NoError(t, service.runJob(context.Background(), job))
+	assert.Empty(t, service.ParamsProposals())
+}
+
+func TestService_NewProposalSupersedesPending(t *testing.T) {
+	job := JobConfig{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "btc-4h", Ranges: "period=10:30:5"}
+	session := &fakeSession{best: 25}
+	service, _ := newTestService(t, Config{Jobs: []JobConfig{job}}, session)
+
+	require.NoError(t, service.runJob(context.Background(), job))
+	service.now = func() time.Time { return time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC) }
+	session.best = 30
+	require.NoError(t, service.runJob(context.Background(), job))
+
+	proposals := service.ParamsProposals()
+	require.Len(t, proposals, 2)
+	assert.Equal(t, dashboard.ProposalStatePending, proposals[0].State)
+	assert.Equal(t, dashboard.ProposalStateSuperseded, proposals[1].State)
+
+	var params strategy.BollingerBandsParams
+	require.NoError(t, json.Unmarshal(proposals[0].Params, &params))
+	assert.Equal(t, 30, params.Period)
+
+	rejected, err := service.RejectProposal(proposals[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.ProposalStateRejected, rejected.State)
+}
+
+func TestService_Backtest(t *testing.T) {
+	job := JobConfig{Name: "weekly", Schedule: "@weekly", Type: JobTypeBacktest, Base: "ETH", Quote: "USDT", Timeframe: "1h", SaveResults: true}
+	session := &fakeSession{best: 20}
+	service, _ := newTestService(t, Config{Jobs: []JobConfig{job}}, session)
+
+	require.NoError(t, service.runJob(context.Background(), job))
+	assert.Equal(t, "ETH/USDT", session.target.Pair.String())
+	assert.Equal(t, 10000.0, session.target.Capital)
+	assert.Equal(t, 1, session.saved)
+}
+
+func TestNewService_ValidatesJobs(t *testing.T) {
+	target := &fakeParamsTarget{bots: map[string]trading.BotConfig{
+		"eth-rsi": {Name: "eth-rsi", Base: "ETH", Quote: "USDT", Strategy: "rsi"},
+	}}
+	valid := JobConfig{Name: "nightly", Schedule: "@daily", Type: JobTypeBacktest, Base: "BTC", Quote: "USDT"}
+
+	for _, job := range []JobConfig{
+		{Name: "", Schedule: "@daily", Type: JobTypeBacktest, Base: "BTC", Quote: "USDT"},
+		{Name: "nightly", Schedule: "daily", Type: JobTypeBacktest, Base: "BTC", Quote: "USDT"},
+		{Name: "nightly", Schedule: "@daily", Type: "walkforward", Base: "BTC", Quote: "USDT"},
+		{Name: "nightly", Schedule: "@daily", Type: JobTypeBacktest},
+		{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "unknown"},
+		{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "eth-rsi"},
+		{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Base: "BTC", Quote: "USDT", Ranges: "unknown=1:2:1"},
+		{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Base: "BTC", Quote: "USDT", Approval: "sometimes"},
+	} {
+		_, err := NewService(context.Background(), Config{Jobs: []JobConfig{job}}, target)
+		assert.Error(t, err, "%+v", job)
+	}
+
+	_, err := NewService(context.Background(), Config{Jobs: []JobConfig{valid, valid}}, target)
+	assert.Error(t, err)
+	_, err = NewService(context.Background(), Config{Jobs: []JobConfig{{Name: "nightly", Schedule: "@daily", Type: JobTypeOptimize, Bot: "btc"}}}, nil)
+	assert.Error(t, err)
+}
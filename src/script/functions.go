@@ -0,0 +1,346 @@
+package script
+
+import (
+	"fmt"
+	"math"
+
+	"tradingbot/src/indicators"
+
+	"github.com/shopspring/decimal"
+)
+
+// function 内置函数：参数以语法树传入，序列参数可在之前的K线上重新求值
+type function struct {
+	arity int
+	call  func(env *Env, args []node) (float64, error)
+}
+
+// functions 内置函数表：x 为任意表达式（如 close、(high+low)/2），n 为周期
+var functions = map[string]function{
+	"sma":         {2, windowFunc(mean)},                                    // sma(x, n) 简单移动平均
+	"stddev":      {2, windowFunc(stddev)},                                  // stddev(x, n) 总体标准差
+	"highest":     {2, windowFunc(highest)},                                 // highest(x, n) 最近 n 根的最大值
+	"lowest":      {2, windowFunc(lowest)},                                  // lowest(x, n) 最近 n 根的最小值
+	"ema":         {2, smoothedFunc(1, ema)},                                // ema(x, n) 指数移动平均
+	"rsi":         {2, smoothedFunc(2, rsi)},                                // rsi(x, n) 相对强弱指数（Wilder 平滑，0-100）
+	"bb_upper":    {3, bollingerFunc(1)},                                    // bb_upper(x, n, k) 布林带上轨
+	"bb_lower":    {3, bollingerFunc(-1)},                                   // bb_lower(x, n, k) 布林带下轨
+	"atr":         {1, atrFunc},                                             // atr(n) 平均真实波幅（Wilder 平滑）
+	"vwap":        {1, vwapFunc},                                            // vwap(n) 最近 n 根K线的成交量加权平均价（典型价加权）
+	"stoch":       {1, stochFunc},                                           // stoch(n) 快速随机指标 %K（0-100）
+	"adx":         {1, adxFunc},                                             // adx(n) 平均趋向指数（Wilder 平滑，0-100）
+	"prev":        {2, prevFunc},                                            // prev(x, n) n 根K线之前的值
+	"cross_above": {2, crossFunc(func(a, b float64) bool { return a > b })}, // cross_above(a, b) 本根 a 上穿 b
+	"cross_below": {2, crossFunc(func(a, b float64) bool { return a < b })}, // cross_below(a, b) 本根 a 下穿 b
+	"abs":         {1, mathFunc1(math.Abs)},
+	"min":         {2, mathFunc2(math.Min)},
+	"max":         {2, mathFunc2(math.Max)},
+}
+
+// maxPeriod 周期参数上限
+const maxPeriod = 1000
+
+// period 求值周期参数，必须是 1 到 maxPeriod 的整数
+func period(env *Env, arg node) (int, error) {
+	value, err := arg.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	if value != math.Trunc(value) || value < 1 || value > maxPeriod {
+		return 0, fmt.Errorf("period must be an integer between 1 and %d, got %v", maxPeriod, value)
+	}
+	return int(value), nil
+}
+
+// series 最近 n 根K线上 x 的值（最早的在前）
+func series(env *Env, x node, n int) ([]float64, error) {
+	if env.available() < n {
+		return nil, ErrNotEnoughData
+	}
+	values := make([]float64, n)
+	for i := 0; i < n; i++ {
+		value, err := x.eval(env.shifted(n - 1 - i))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// windowFunc 在最近 n 根K线窗口上计算的函数
+func windowFunc(calc func(values []float64) float64) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		n, err := period(env, args[1])
+		if err != nil {
+			return 0, err
+		}
+		values, err := series(env, args[0], n)
+		if err != nil {
+			return 0, err
+		}
+		return calc(values), nil
+	}
+}
+
+// smoothedFunc 递推平滑的函数：使用最多 4n 根K线（至少 n+extra-1 根）使结果收敛
+func smoothedFunc(extra int, calc func(values []float64, n int) float64) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		n, err := period(env, args[1])
+		if err != nil {
+			return 0, err
+		}
+		window := env.available()
+		if window > 4*n {
+			window = 4 * n
+		}
+		if window < n+extra-1 {
+			return 0, ErrNotEnoughData
+		}
+		values, err := series(env, args[0], window)
+		if err != nil {
+			return 0, err
+		}
+		return calc(values, n), nil
+	}
+}
+
+// bollingerFunc 布林带上轨（side=1）或下轨（side=-1）：均值 ± k 倍标准差
+func bollingerFunc(side float64) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		n, err := period(env, args[1])
+		if err != nil {
+			return 0, err
+		}
+		k, err := args[2].eval(env)
+		if err != nil {
+			return 0, err
+		}
+		values, err := series(env, args[0], n)
+		if err != nil {
+			return 0, err
+		}
+		return mean(values) + side*k*stddev(values), nil
+	}
+}
+
+// atrFunc 平均真实波幅
+func atrFunc(env *Env, args []node) (float64, error) {
+	n, err := period(env, args[0])
+	if err != nil {
+		return 0, err
+	}
+	window := env.available() - 1
+	if window > 4*n {
+		window = 4 * n
+	}
+	if window < n {
+		return 0, ErrNotEnoughData
+	}
+
+	end := len(env.Bars) - env.offset
+	bars := env.Bars[end-window-1 : end]
+	var atr float64
+	for i := 1; i < len(bars); i++ {
+		trueRange := math.Max(bars[i].High-bars[i].Low,
+			math.Max(math.Abs(bars[i].High-bars[i-1].Close), math.Abs(bars[i].Low-bars[i-1].Close)))
+		if i <= n {
+			atr += trueRange / float64(n)
+			continue
+		}
+		atr = (atr*float64(n-1) + trueRange) / float64(n)
+	}
+	return atr, nil
+}
+
+// vwapFunc 滚动 VWAP：Σ(典型价 × 成交量) / Σ成交量，成交量为零时视为数据不足
+func vwapFunc(env *Env, args []node) (float64, error) {
+	n, err := period(env, args[0])
+	if err != nil {
+		return 0, err
+	}
+	if env.available() < n {
+		return 0, ErrNotEnoughData
+	}
+
+	end := len(env.Bars) - env.offset
+	var priceVolume, volume float64
+	for _, bar := range env.Bars[end-n : end] {
+		priceVolume += (bar.High + bar.Low + bar.Close) / 3 * bar.Volume
+		volume += bar.Volume
+	}
+	if volume <= 0 {
+		return 0, ErrNotEnoughData
+	}
+	return priceVolume / volume, nil
+}
+
+// stochFunc 快速随机指标 %K
+func stochFunc(env *Env, args []node) (float64, error) {
+	n, err := period(env, args[0])
+	if err != nil {
+		return 0, err
+	}
+	if env.available() < n {
+		return 0, ErrNotEnoughData
+	}
+	highs, lows, closes := decimalBars(env, n)
+	result, err := indicators.NewStochastic(n, 1, 1).Calculate(highs, lows, closes)
+	if err != nil {
+		return 0, err
+	}
+	return result.K.InexactFloat64(), nil
+}
+
+// adxFunc 平均趋向指数：使用最多 6n 根K线（至少 2n 根）使结果收敛
+func adxFunc(env *Env, args []node) (float64, error) {
+	n, err := period(env, args[0])
+	if err != nil {
+		return 0, err
+	}
+	window := env.available()
+	if window > 6*n {
+		window = 6 * n
+	}
+	if window < 2*n {
+		return 0, ErrNotEnoughData
+	}
+	highs, lows, closes := decimalBars(env, window)
+	result, err := indicators.NewADX(n).Calculate(highs, lows, closes)
+	if err != nil {
+		return 0, err
+	}
+	return result.ADX.InexactFloat64(), nil
+}
+
+// decimalBars 求值K线之前（含）最近 n 根K线的最高、最低、收盘价
+func decimalBars(env *Env, n int) (highs, lows, closes []decimal.Decimal) {
+	end := len(env.Bars) - env.offset
+	for _, bar := range env.Bars[end-n : end] {
+		highs = append(highs, decimal.NewFromFloat(bar.High))
+		lows = append(lows, decimal.NewFromFloat(bar.Low))
+		closes = append(closes, decimal.NewFromFloat(bar.Close))
+	}
+	return highs, lows, closes
+}
+
+// prevFunc n 根K线之前的值
+func prevFunc(env *Env, args []node) (float64, error) {
+	n, err := period(env, args[1])
+	if err != nil {
+		return 0, err
+	}
+	if env.available() <= n {
+		return 0, ErrNotEnoughData
+	}
+	return args[0].eval(env.shifted(n))
+}
+
+// crossFunc 本根K线满足 cmp(a, b)，上一根K线不满足
+func crossFunc(cmp func(a, b float64) bool) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		var now, before [2]float64
+		for i, arg := range args {
+			var err error
+			if now[i], err = arg.eval(env); err != nil {
+				return 0, err
+			}
+			if env.available() < 2 {
+				return 0, ErrNotEnoughData
+			}
+			if before[i], err = arg.eval(env.shifted(1)); err != nil {
+				return 0, err
+			}
+		}
+		return boolValue(cmp(now[0], now[1]) && !cmp(before[0], before[1])), nil
+	}
+}
+
+// mathFunc1 单参数数学函数
+func mathFunc1(calc func(float64) float64) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		value, err := args[0].eval(env)
+		if err != nil {
+			return 0, err
+		}
+		return calc(value), nil
+	}
+}
+
+// mathFunc2 双参数数学函数
+func mathFunc2(calc func(a, b float64) float64) func(env *Env, args []node) (float64, error) {
+	return func(env *Env, args []node) (float64, error) {
+		a, err := args[0].eval(env)
+		if err != nil {
+			return 0, err
+		}
+		b, err := args[1].eval(env)
+		if err != nil {
+			return 0, err
+		}
+		return calc(a, b), nil
+	}
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func stddev(values []float64) float64 {
+	avg := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - avg) * (v - avg)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func highest(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = math.Max(result, v)
+	}
+	return result
+}
+
+func lowest(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = math.Min(result, v)
+	}
+	return result
+}
+
+// ema 以前 n 个值的均值为初值递推
+func ema(values []float64, n int) float64 {
+	alpha := 2 / float64(n+1)
+	result := mean(values[:n])
+	for _, v := range values[n:] {
+		result = alpha*v + (1-alpha)*result
+	}
+	return result
+}
+
+// rsi 以前 n 个变化的均值为初值，Wilder 平滑
+func rsi(values []float64, n int) float64 {
+	var gain, loss float64
+	for i := 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		up, down := math.Max(change, 0), math.Max(-change, 0)
+		if i <= n {
+			gain += up / float64(n)
+			loss += down / float64(n)
+			continue
+		}
+		gain = (gain*float64(n-1) + up) / float64(n)
+		loss = (loss*float64(n-1) + down) / float64(n)
+	}
+	if loss == 0 {
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
@@ -0,0 +1,244 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNotEnoughData K线数量不足以计算表达式（指标预热期），调用方应视为条件不满足
+var ErrNotEnoughData = errors.New("not enough klines")
+
+// Bar 一根K线
+type Bar struct {
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Env 表达式求值环境
+type Env struct {
+	Bars   []Bar              // 历史K线，最新的在最后
+	Vars   map[string]float64 // 变量（持仓、用户参数等）
+	offset int                // 求值所在的K线：0 为最新，1 为上一根
+}
+
+// seriesNames K线序列变量，取值为当前求值K线的对应字段
+var seriesNames = map[string]func(Bar) float64{
+	"open":   func(b Bar) float64 { return b.Open },
+	"high":   func(b Bar) float64 { return b.High },
+	"low":    func(b Bar) float64 { return b.Low },
+	"close":  func(b Bar) float64 { return b.Close },
+	"volume": func(b Bar) float64 { return b.Volume },
+}
+
+// Program 编译后的表达式
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile 解析表达式并检查函数和变量（vars 为允许使用的变量名，K线序列变量始终可用）
+func Compile(source string, vars []string) (*Program, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+
+	allowed := make(map[string]bool, len(vars))
+	for _, name := range vars {
+		allowed[name] = true
+	}
+	if err := check(root, allowed); err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Program{source: source, root: root}, nil
+}
+
+// String 表达式原文
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval 在最新K线上求值，条件表达式成立时结果为 1，不成立为 0
+func (p *Program) Eval(env *Env) (float64, error) {
+	at := *env
+	at.offset = 0
+	return p.root.eval(&at)
+}
+
+// Functions 支持的函数名（排序后）
+func Functions() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check 检查变量和函数是否存在、函数参数个数是否正确
+func check(n node, vars map[string]bool) error {
+	switch n := n.(type) {
+	case *identNode:
+		if _, isSeries := seriesNames[n.name]; !isSeries && !vars[n.name] {
+			return fmt.Errorf("unknown variable %s", n.name)
+		}
+	case *unaryNode:
+		return check(n.operand, vars)
+	case *binaryNode:
+		if err := check(n.left, vars); err != nil {
+			return err
+		}
+		return check(n.right, vars)
+	case *callNode:
+		fn, exists := functions[n.name]
+		if !exists {
+			return fmt.Errorf("unknown function %s (available: %v)", n.name, Functions())
+		}
+		if len(n.args) != fn.arity {
+			return fmt.Errorf("%s() takes %d arguments, got %d", n.name, fn.arity, len(n.args))
+		}
+		for _, arg := range n.args {
+			if err := check(arg, vars); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// node 语法树节点
+type node interface {
+	eval(env *Env) (float64, error)
+}
+
+// numberNode 数字常量
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(env *Env) (float64, error) {
+	return n.value, nil
+}
+
+// identNode K线序列或变量
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(env *Env) (float64, error) {
+	if field, isSeries := seriesNames[n.name]; isSeries {
+		index := len(env.Bars) - 1 - env.offset
+		if index < 0 {
+			return 0, ErrNotEnoughData
+		}
+		return field(env.Bars[index]), nil
+	}
+	return env.Vars[n.name], nil
+}
+
+// unaryNode 取反或负号
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env *Env) (float64, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -value, nil
+	}
+	return boolValue(value == 0), nil
+}
+
+// binaryNode 二元运算（&& 和 || 短路求值）
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(env *Env) (float64, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "&&":
+		if left == 0 {
+			return 0, nil
+		}
+	case "||":
+		if left != 0 {
+			return 1, nil
+		}
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "&&", "||":
+		return boolValue(right != 0), nil
+	case "==":
+		return boolValue(left == right), nil
+	case "!=":
+		return boolValue(left != right), nil
+	case "<":
+		return boolValue(left < right), nil
+	case "<=":
+		return boolValue(left <= right), nil
+	case ">":
+		return boolValue(left > right), nil
+	case ">=":
+		return boolValue(left >= right), nil
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return left / right, nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", n.op)
+}
+
+// callNode 函数调用
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(env *Env) (float64, error) {
+	return functions[n.name].call(env, n.args)
+}
+
+// boolValue 条件结果：成立为 1，不成立为 0
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// shifted 向前移动 bars 根K线的求值环境
+func (env *Env) shifted(bars int) *Env {
+	at := *env
+	at.offset += bars
+	return &at
+}
+
+// available 当前求值K线及之前的K线数量
+func (env *Env) available() int {
+	return len(env.Bars) - env.offset
+}
@@ -0,0 +1,129 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closes 只有收盘价的K线（最高、最低价为收盘价 ±1）
+func closes(values ...float64) []Bar {
+	bars := make([]Bar, len(values))
+	for i, v := range values {
+		bars[i] = Bar{Open: v, High: v + 1, Low: v - 1, Close: v, Volume: 100}
+	}
+	return bars
+}
+
+func eval(t *testing.T, source string, env *Env) float64 {
+	t.Helper()
+	var vars []string
+	for name := range env.Vars {
+		vars = append(vars, name)
+	}
+	program, err := Compile(source, vars)
+	require.NoError(t, err, source)
+	value, err := program.Eval(env)
+	require.NoError(t, err, source)
+	return value
+}
+
+func TestEval_Operators(t *testing.T) {
+	env := &Env{Bars: closes(10, 12), Vars: map[string]float64{"threshold": 11, "zero": 0}}
+
+	for source, expected := range map[string]float64{
+		"1 + 2 * 3":                  7,
+		"(1 + 2) * 3":                9,
+		"10 - 4 - 3":                 3,
+		"12 / 3 / 2":                 2,
+		"-close + 2":                 -10,
+		"close > threshold":          1,
+		"close <= threshold":         0,
+		"close == 12 && open != 11":  1,
+		"close < 5 || volume >= 100": 1,
+		"!(close > threshold)":       0,
+		"1 < 2 == 1":                 1,
+		"zero && 1 / zero":           0, // 短路，不会除零
+		"max(close, 20) - min(1, 2)": 19,
+		"abs(open - 15)":             3,
+	} {
+		assert.Equal(t, expected, eval(t, source, env), source)
+	}
+}
+
+func TestEval_Functions(t *testing.T) {
+	env := &Env{Bars: closes(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)}
+
+	assert.Equal(t, 9.0, eval(t, "sma(close, 3)", env))
+	assert.Equal(t, 10.0, eval(t, "highest(close, 5)", env))
+	assert.Equal(t, 6.0, eval(t, "lowest(close, 5)", env))
+	assert.Equal(t, 8.0, eval(t, "prev(close, 2)", env))
+	assert.Equal(t, 9.5, eval(t, "sma((high + low) / 2 + 0.5, 2) - 0.5", env))
+	assert.InDelta(t, 0.8165, eval(t, "stddev(close, 3)", env), 0.0001)
+	assert.InDelta(t, 9+2*0.8165, eval(t, "bb_upper(close, 3, 2)", env), 0.0001)
+	assert.InDelta(t, 9-2*0.8165, eval(t, "bb_lower(close, 3, 2)", env), 0.0001)
+	// 单边上涨：RSI 为 100，EMA 跟随价格但滞后
+	assert.Equal(t, 100.0, eval(t, "rsi(close, 3)", env))
+	assert.Equal(t, 9.0, eval(t, "ema(close, 3)", env))
+	// 每根K线波幅 2，前一收盘到当根高低点最多 2
+	assert.Equal(t, 2.0, eval(t, "atr(3)", env))
+	// 成交量相同：VWAP 等于典型价的平均
+	assert.InDelta(t, 9.0, eval(t, "vwap(3)", env), 1e-9)
+	weighted := &Env{Bars: []Bar{{High: 12, Low: 10, Close: 11, Volume: 100}, {High: 13, Low: 11, Close: 12, Volume: 300}}}
+	assert.InDelta(t, 11.75, eval(t, "vwap(2)", weighted), 1e-9)
+	// 收盘价在最近 3 根的最高最低价区间 [7, 11] 中的位置
+	assert.InDelta(t, 75.0, eval(t, "stoch(3)", env), 1e-9)
+	// 单边上涨只有 +DM：+DI 远大于 -DI，ADX 为 100
+	assert.InDelta(t, 100.0, eval(t, "adx(3)", env), 1e-9)
+
+	falling := &Env{Bars: closes(10, 9, 8, 9, 8, 7)}
+	// 变化 -1,-1,+1,-1,-1：Wilder 平滑后平均涨幅 0.125、平均跌幅 0.875
+	assert.InDelta(t, 12.5, eval(t, "rsi(close, 2)", falling), 0.0001)
+}
+
+func TestEval_Cross(t *testing.T) {
+	env := &Env{Bars: closes(5, 4, 3, 6)}
+	assert.Equal(t, 1.0, eval(t, "cross_above(close, sma(close, 2))", env))
+	assert.Equal(t, 0.0, eval(t, "cross_below(close, sma(close, 2))", env))
+
+	env = &Env{Bars: closes(5, 4, 3, 6, 7)}
+	assert.Equal(t, 0.0, eval(t, "cross_above(close, sma(close, 2))", env))
+}
+
+func TestEval_NotEnoughData(t *testing.T) {
+	env := &Env{Bars: closes(1, 2, 3)}
+
+	for _, source := range []string{"sma(close, 4)", "prev(close, 3)", "rsi(close, 3)", "atr(3)", "vwap(4)", "stoch(4)", "adx(2)", "cross_above(close, sma(close, 3))"} {
+		program, err := Compile(source, nil)
+		require.NoError(t, err, source)
+		_, err = program.Eval(env)
+		assert.ErrorIs(t, err, ErrNotEnoughData, source)
+	}
+
+	program, err := Compile("sma(close, 2.5)", nil)
+	require.NoError(t, err)
+	_, err = program.Eval(env)
+	assert.Error(t, err)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"close >",
+		"(close > 1",
+		"close > 1)",
+		"close # 1",
+		"sma(close 3)",
+		"unknown(close)",
+		"sma(close)",
+		"close > oversold",
+		"1.2.3",
+	} {
+		_, err := Compile(source, nil)
+		assert.Error(t, err, source)
+	}
+
+	_, err := Compile("rsi(close, period) < oversold", []string{"period", "oversold"})
+	assert.NoError(t, err)
+}
@@ -2,7 +2,10 @@ package strategies
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"tradingbot/src/cex"
 	"tradingbot/src/executor"
@@ -25,14 +28,43 @@ type BollingerBandsStrategy struct {
 	CooldownBars        int     `json:"cooldown_bars"`
 
 	// 卖出策略参数
-	SellStrategyName string `json:"sell_strategy_name"`
+	SellStrategyName string  `json:"sell_strategy_name"`
+	TakeProfitLadder bool    `json:"take_profit_ladder"` // 止盈阶梯由引擎预先挂单
+	TrailingStop     float64 `json:"trailing_stop"`      // 移动止损单由引擎挂出并逐根K线上移
+	OCO              bool    `json:"oco"`                // 止盈止损由引擎以 OCO 挂单
+
+	// ATR 止盈止损：开仓价 ± N×ATR（ATR 数据不足时使用固定比例）
+	ATRPeriod             int     `json:"atr_period"`
+	ATRStopMultiple       float64 `json:"atr_stop_multiple"`
+	ATRTakeProfitMultiple float64 `json:"atr_take_profit_multiple"`
+
+	// 波动率过滤：带宽超出 [MinBandWidth, MaxBandWidth] 时不开仓（0 表示不限制）
+	MinBandWidth float64 `json:"min_band_width"`
+	MaxBandWidth float64 `json:"max_band_width"`
+
+	// 成交量确认：成交量超过前 VolumePeriod 根K线平均值的 VolumeMultiplier 倍时才开仓（0 表示不过滤）
+	VolumePeriod     int     `json:"volume_period"`
+	VolumeMultiplier float64 `json:"volume_multiplier"`
+
+	// 资金费率过滤：最近一次资金费率高于 MaxFundingRate 时不开仓（0 表示不过滤）
+	MaxFundingRate float64 `json:"max_funding_rate"`
 
 	// 内部状态
 	bb             *indicators.BollingerBands
+	atr            *indicators.ATR
 	priceHistory   []decimal.Decimal
+	highHistory    []decimal.Decimal
+	lowHistory     []decimal.Decimal
+	entryATR       decimal.Decimal         // 开仓时的 ATR
+	volumeFilter   *strategy.VolumeFilter  // 未启用成交量确认时为 nil
+	fundingFilter  *strategy.FundingFilter // 未启用资金费率过滤时为 nil
+	vwap           *indicators.VWAP        // 会话 VWAP（UTC 自然日）
+	obv            *indicators.OBV
+	lastBands      *indicators.BollingerBandsResult // 最近一根K线的布林道（指标快照）
 	currentBar     int
 	lastTradeBar   int
 	lastTradePrice decimal.Decimal
+	entryTime      time.Time // 开仓信号K线的收盘时间
 
 	// 🔥 新增：跟踪持仓期间最高价格（移动止盈关键字段）
 	highestPriceSinceBuy decimal.Decimal
@@ -40,6 +72,16 @@ type BollingerBandsStrategy struct {
 
 	// 卖出策略
 	sellStrategy strategy.SellStrategy
+
+	// 运行中更新的卖出策略，在下一根K线开始时替换（实盘热更新配置）
+	pendingMu   sync.Mutex
+	pendingSell *pendingSellStrategy
+}
+
+// pendingSellStrategy 待替换的卖出策略
+type pendingSellStrategy struct {
+	name         string
+	sellStrategy strategy.SellStrategy
 }
 
 // NewBollingerBandsStrategy 创建布林道策略
@@ -72,9 +114,66 @@ func (s *BollingerBandsStrategy) GetParams() strategy.StrategyParams {
 		StopLossPercent:     s.StopLossPercent,
 		TakeProfitPercent:   s.TakeProfitPercent,
 		CooldownBars:        s.CooldownBars,
+		SellStrategyName:    s.SellStrategyName,
+		TakeProfitLadder:    s.TakeProfitLadder,
+		TrailingStop:        s.TrailingStop,
+		OCO:                 s.OCO,
+
+		ATRPeriod:             s.ATRPeriod,
+		ATRStopMultiple:       s.ATRStopMultiple,
+		ATRTakeProfitMultiple: s.ATRTakeProfitMultiple,
+
+		MinBandWidth: s.MinBandWidth,
+		MaxBandWidth: s.MaxBandWidth,
+
+		VolumePeriod:     s.VolumePeriod,
+		VolumeMultiplier: s.VolumeMultiplier,
+
+		MaxFundingRate: s.MaxFundingRate,
 	}
 }
 
+// UsesFuturesContext 启用资金费率过滤时需要合约辅助数据
+func (s *BollingerBandsStrategy) UsesFuturesContext() bool {
+	return s.fundingFilter != nil
+}
+
+// SetFuturesContext 注入合约辅助数据
+func (s *BollingerBandsStrategy) SetFuturesContext(fc *strategy.FuturesContext) {
+	if s.fundingFilter != nil {
+		s.fundingFilter.SetContext(fc)
+	}
+}
+
+// GetATRStops 获取 ATR 周期和止盈、止损倍数（未启用时倍数为0）
+func (s *BollingerBandsStrategy) GetATRStops() (int, float64, float64) {
+	return s.ATRPeriod, s.ATRTakeProfitMultiple, s.ATRStopMultiple
+}
+
+// GetOCOPercents 获取OCO止盈止损比例（未启用时返回0）
+func (s *BollingerBandsStrategy) GetOCOPercents() (float64, float64) {
+	if !s.OCO {
+		return 0, 0
+	}
+	return s.TakeProfitPercent, s.StopLossPercent
+}
+
+// GetTrailingStopPercent 获取移动止损回撤比例
+func (s *BollingerBandsStrategy) GetTrailingStopPercent() float64 {
+	return s.TrailingStop
+}
+
+// GetTakeProfitLadder 获取止盈阶梯（仅在启用阶梯挂单且卖出策略支持时返回）
+func (s *BollingerBandsStrategy) GetTakeProfitLadder() []strategy.PartialLevel {
+	if !s.TakeProfitLadder {
+		return nil
+	}
+	if ladder, ok := s.sellStrategy.(strategy.LadderSellStrategy); ok {
+		return ladder.GetLadderLevels()
+	}
+	return nil
+}
+
 // SetParams 设置策略参数
 func (s *BollingerBandsStrategy) SetParams(params strategy.StrategyParams) error {
 	if bollingerParams, ok := params.(*strategy.BollingerBandsParams); ok {
@@ -88,6 +187,17 @@ func (s *BollingerBandsStrategy) SetParams(params strategy.StrategyParams) error
 
 		// 设置卖出策略
 		s.SellStrategyName = bollingerParams.SellStrategyName
+		s.TakeProfitLadder = bollingerParams.TakeProfitLadder
+		s.TrailingStop = bollingerParams.TrailingStop
+		s.OCO = bollingerParams.OCO
+		s.ATRPeriod = bollingerParams.ATRPeriod
+		s.ATRStopMultiple = bollingerParams.ATRStopMultiple
+		s.ATRTakeProfitMultiple = bollingerParams.ATRTakeProfitMultiple
+		s.MinBandWidth = bollingerParams.MinBandWidth
+		s.MaxBandWidth = bollingerParams.MaxBandWidth
+		s.VolumePeriod = bollingerParams.VolumePeriod
+		s.VolumeMultiplier = bollingerParams.VolumeMultiplier
+		s.MaxFundingRate = bollingerParams.MaxFundingRate
 
 		// 创建卖出策略实例，统一使用 CreateSellStrategyWithParams（支持预设名称和直接类型）
 		sellStrategy, err := strategy.CreateSellStrategyWithParams(s.SellStrategyName, bollingerParams.SellStrategyParams)
@@ -100,6 +210,15 @@ func (s *BollingerBandsStrategy) SetParams(params strategy.StrategyParams) error
 
 	// 重新创建布林道指标
 	s.bb = indicators.NewBollingerBands(s.Period, s.Multiplier)
+	s.atr = indicators.NewATR(s.ATRPeriod)
+	s.volumeFilter = nil
+	if s.VolumeMultiplier > 0 && s.VolumePeriod > 0 {
+		s.volumeFilter = strategy.NewVolumeFilter(s.VolumePeriod, s.VolumeMultiplier)
+	}
+	s.fundingFilter = nil
+	if s.MaxFundingRate != 0 {
+		s.fundingFilter = strategy.NewFundingFilter(s.MaxFundingRate)
+	}
 	return nil
 }
 
@@ -109,6 +228,7 @@ func (s *BollingerBandsStrategy) OnData(ctx context.Context, kline *cex.KlineDat
 	logger.PushPrefix("BollingerStrategy")
 
 	s.currentBar++
+	s.applyPendingSellStrategy(ctx)
 
 	// 只在有持仓变化或重要节点时打印状态
 	if s.currentBar == 1 || (s.currentBar%50 == 0 && !portfolio.Position.IsZero()) {
@@ -118,11 +238,29 @@ func (s *BollingerBandsStrategy) OnData(ctx context.Context, kline *cex.KlineDat
 
 	// 添加价格到历史数据
 	s.priceHistory = append(s.priceHistory, kline.Close)
+	s.highHistory = append(s.highHistory, kline.High)
+	s.lowHistory = append(s.lowHistory, kline.Low)
+	if s.volumeFilter != nil {
+		s.volumeFilter.Observe(kline)
+	}
+	if s.vwap == nil {
+		s.vwap, s.obv = indicators.NewSessionVWAP(0), indicators.NewOBV()
+	}
+	s.vwap.Update(kline.OpenTime, kline.High, kline.Low, kline.Close, kline.Volume)
+	s.obv.Update(kline.Close, kline.Volume)
+	if observer, ok := s.sellStrategy.(strategy.KlineObserver); ok {
+		observer.Observe(kline)
+	}
 
-	// 保持历史数据长度
+	// 保持历史数据长度（ATR 保留 3 倍周期以便 Wilder 平滑收敛）
 	maxHistory := s.Period + 10
+	if s.usesATR() && 3*s.ATRPeriod+1 > maxHistory {
+		maxHistory = 3*s.ATRPeriod + 1
+	}
 	if len(s.priceHistory) > maxHistory {
 		s.priceHistory = s.priceHistory[1:]
+		s.highHistory = s.highHistory[1:]
+		s.lowHistory = s.lowHistory[1:]
 	}
 
 	// 检查是否有足够的数据计算布林道
@@ -142,6 +280,7 @@ func (s *BollingerBandsStrategy) OnData(ctx context.Context, kline *cex.KlineDat
 	}
 
 	bbResult.Timestamp = kline.OpenTime.Unix() * 1000
+	s.lastBands = bbResult
 
 	// 删除过于频繁的边界检测日志，在交易信号中会有更有意义的日志
 
@@ -198,7 +337,26 @@ func (s *BollingerBandsStrategy) generateTradeSignals(ctx context.Context, bb *i
 	// 简化买入条件分析日志（只在满足条件时打印）
 
 	// 买入信号：价格触及下轨且无持仓
-	if currentPrice.LessThanOrEqual(bb.LowerBand) && portfolio.Position.IsZero() {
+	touchedLower := currentPrice.LessThanOrEqual(bb.LowerBand) && portfolio.Position.IsZero()
+	if touchedLower {
+		if filtered := s.bandWidthFilter(bb); filtered != "" {
+			logger.Info("🚫 波动率过滤，跳过买入", "reason", filtered)
+			touchedLower = false
+		}
+	}
+	if touchedLower && s.volumeFilter != nil {
+		if confirmed, reason := s.volumeFilter.Confirm(); !confirmed {
+			logger.Info("🚫 成交量未确认，跳过买入", "reason", reason)
+			touchedLower = false
+		}
+	}
+	if touchedLower && s.fundingFilter != nil {
+		if confirmed, reason := s.fundingFilter.Confirm(kline.CloseTime); !confirmed {
+			logger.Info("🚫 资金费率过滤，跳过买入", "reason", reason)
+			touchedLower = false
+		}
+	}
+	if touchedLower {
 		reason := fmt.Sprintf("price %.8f touched lower band %.8f", currentPrice.InexactFloat64(), bb.LowerBand.InexactFloat64())
 		logger.Info("")  // 空行分隔
 		logger.Info(fmt.Sprintf("✅ 买入条件满足: reason=%s, signal_strength=%.1f", reason, 0.8))
@@ -212,6 +370,8 @@ func (s *BollingerBandsStrategy) generateTradeSignals(ctx context.Context, bb *i
 
 		s.lastTradeBar = s.currentBar
 		s.lastTradePrice = currentPrice
+		s.entryTime = kline.CloseTime
+		s.entryATR = s.currentATR()
 
 		// 🔥 初始化移动止盈跟踪
 		s.hasBought = true
@@ -227,6 +387,21 @@ func (s *BollingerBandsStrategy) generateTradeSignals(ctx context.Context, bb *i
 	return signals
 }
 
+// bandWidthFilter 带宽超出限制时返回跳过原因，否则返回空
+func (s *BollingerBandsStrategy) bandWidthFilter(bb *indicators.BollingerBandsResult) string {
+	if bb.MiddleBand.IsZero() {
+		return ""
+	}
+	width := bb.GetBandWidth().InexactFloat64()
+	if s.MinBandWidth > 0 && width < s.MinBandWidth {
+		return fmt.Sprintf("band width %.4f below squeeze threshold %.4f", width, s.MinBandWidth)
+	}
+	if s.MaxBandWidth > 0 && width > s.MaxBandWidth {
+		return fmt.Sprintf("band width %.4f above blow-off threshold %.4f", width, s.MaxBandWidth)
+	}
+	return ""
+}
+
 // checkStopConditions 检查止损止盈条件（使用卖出策略）
 func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) []*strategy.Signal {
 	ctx, logger := log.WithCtx(ctx)
@@ -238,12 +413,25 @@ func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline
 		return signals
 	}
 
+	// OCO 止盈止损已预先挂单，由挂单管理器成交
+	if s.OCO {
+		return signals
+	}
+
 	currentPrice := kline.Close
+	// 冷却期内不生成开仓信号，持仓最高价在这里更新，保证移动止盈、保本止损每根K线都能看到新高
+	if s.hasBought && currentPrice.GreaterThan(s.highestPriceSinceBuy) {
+		s.highestPriceSinceBuy = currentPrice
+	}
 	pnl := currentPrice.Sub(s.lastTradePrice)
 	pnlPercent := pnl.Div(s.lastTradePrice)
 
 	// 简化盈亏日志 - 只在关键时刻打印
 	stopLossThreshold := decimal.NewFromFloat(-s.StopLossPercent)
+	if s.ATRStopMultiple > 0 && s.entryATR.IsPositive() {
+		// 止损距离 N×ATR 换算为比例
+		stopLossThreshold = s.entryATR.Mul(decimal.NewFromFloat(s.ATRStopMultiple)).Div(s.lastTradePrice).Neg()
+	}
 	willStopLoss := pnlPercent.LessThanOrEqual(stopLossThreshold)
 
 	// 只在即将止损时打印详细信息
@@ -268,7 +456,30 @@ func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline
 		return signals
 	}
 
-	// 2. 使用卖出策略检查
+	// 2. 止盈阶梯已预先挂单，由挂单管理器成交，无需逐根K线判断
+	if len(s.GetTakeProfitLadder()) > 0 {
+		return signals
+	}
+
+	// ATR 止盈：价格达到开仓价 + N×ATR
+	if s.ATRTakeProfitMultiple > 0 && s.entryATR.IsPositive() {
+		target := s.lastTradePrice.Add(s.entryATR.Mul(decimal.NewFromFloat(s.ATRTakeProfitMultiple)))
+		if currentPrice.GreaterThanOrEqual(target) {
+			reason := fmt.Sprintf("ATR take profit: %.2f%% (%.1f×ATR)", pnlPercent.Mul(decimal.NewFromInt(100)).InexactFloat64(), s.ATRTakeProfitMultiple)
+			logger.Info(fmt.Sprintf("💎 触发ATR止盈: reason=%s", reason))
+
+			signals = append(signals, &strategy.Signal{
+				Type:      "SELL",
+				Reason:    reason,
+				Strength:  1.0,
+				Timestamp: kline.OpenTime.Unix() * 1000,
+			})
+			s.resetTradeState()
+			return signals
+		}
+	}
+
+	// 3. 使用卖出策略检查
 	if s.sellStrategy != nil {
 		// 创建交易信息
 		tradeInfo := &strategy.TradeInfo{
@@ -276,6 +487,11 @@ func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline
 			CurrentPrice: currentPrice,
 			CurrentPnL:   pnlPercent,
 			HighestPrice: s.highestPriceSinceBuy,
+			EntryTime:    s.entryTime,
+			HoldingBars:  s.currentBar - s.lastTradeBar,
+		}
+		if !s.entryTime.IsZero() {
+			tradeInfo.HoldingDays = int(kline.CloseTime.Sub(s.entryTime).Hours() / 24)
 		}
 
 		sellSignal := s.sellStrategy.ShouldSell(kline, tradeInfo)
@@ -294,7 +510,7 @@ func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline
 			return signals
 		}
 	} else {
-		// 3. 兜底：基础止盈检查
+		// 4. 兜底：基础止盈检查
 		takeProfitThreshold := decimal.NewFromFloat(s.TakeProfitPercent)
 		if pnlPercent.GreaterThanOrEqual(takeProfitThreshold) {
 			reason := fmt.Sprintf("take profit: %.2f%%", pnlPercent.Mul(decimal.NewFromInt(100)).InexactFloat64())
@@ -313,10 +529,45 @@ func (s *BollingerBandsStrategy) checkStopConditions(ctx context.Context, kline
 	return signals
 }
 
+// UpdateSellStrategy 运行中替换卖出策略，在下一根K线开始时生效；
+// 新卖出策略从头计算（分批止盈已执行的级别会重置），参数无效时返回错误且保持原策略
+func (s *BollingerBandsStrategy) UpdateSellStrategy(name string, params map[string]float64) error {
+	sellStrategy, err := strategy.CreateSellStrategyWithParams(name, params)
+	if err != nil {
+		return fmt.Errorf("invalid sell strategy: %w", err)
+	}
+	if _, ok := sellStrategy.(strategy.LadderSellStrategy); s.TakeProfitLadder && !ok {
+		return fmt.Errorf("take_profit_ladder requires a partial sell strategy (e.g. partial_pyramid), got %s", name)
+	}
+
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	s.pendingSell = &pendingSellStrategy{name: name, sellStrategy: sellStrategy}
+	return nil
+}
+
+// applyPendingSellStrategy 替换为运行中更新的卖出策略
+func (s *BollingerBandsStrategy) applyPendingSellStrategy(ctx context.Context) {
+	s.pendingMu.Lock()
+	pending := s.pendingSell
+	s.pendingSell = nil
+	s.pendingMu.Unlock()
+	if pending == nil {
+		return
+	}
+
+	_, logger := log.WithCtx(ctx)
+	logger.Info(fmt.Sprintf("🔧 卖出策略已更新: %s → %s", s.SellStrategyName, pending.name))
+	s.SellStrategyName = pending.name
+	s.sellStrategy = pending.sellStrategy
+}
+
 // resetTradeState 重置交易状态
 func (s *BollingerBandsStrategy) resetTradeState() {
 	s.lastTradeBar = s.currentBar
 	s.lastTradePrice = decimal.Zero
+	s.entryTime = time.Time{}
+	s.entryATR = decimal.Zero
 
 	// 🔥 重置移动止盈状态
 	s.hasBought = false
@@ -326,3 +577,145 @@ func (s *BollingerBandsStrategy) resetTradeState() {
 		s.sellStrategy.Reset()
 	}
 }
+
+// bollingerState 布林道策略的持久化状态
+type bollingerState struct {
+	CurrentBar     int               `json:"current_bar"`
+	LastTradeBar   int               `json:"last_trade_bar"`
+	LastTradePrice decimal.Decimal   `json:"last_trade_price"`
+	EntryTime      time.Time         `json:"entry_time"`
+	EntryATR       decimal.Decimal   `json:"entry_atr"`
+	HasBought      bool              `json:"has_bought"`
+	HighestPrice   decimal.Decimal   `json:"highest_price"`
+	PriceHistory   []decimal.Decimal `json:"price_history"`
+	HighHistory    []decimal.Decimal `json:"high_history"`
+	LowHistory     []decimal.Decimal `json:"low_history"`
+
+	// 卖出策略名称不同（配置已修改）时不恢复其状态
+	SellStrategy      string          `json:"sell_strategy,omitempty"`
+	SellStrategyState json.RawMessage `json:"sell_strategy_state,omitempty"`
+}
+
+// Save 保存持仓跟踪（开仓价、最高价、开仓 ATR）、冷却期计数、K线历史和卖出策略状态
+func (s *BollingerBandsStrategy) Save() ([]byte, error) {
+	state := bollingerState{
+		CurrentBar:     s.currentBar,
+		LastTradeBar:   s.lastTradeBar,
+		LastTradePrice: s.lastTradePrice,
+		EntryTime:      s.entryTime,
+		EntryATR:       s.entryATR,
+		HasBought:      s.hasBought,
+		HighestPrice:   s.highestPriceSinceBuy,
+		PriceHistory:   s.priceHistory,
+		HighHistory:    s.highHistory,
+		LowHistory:     s.lowHistory,
+	}
+	if s.sellStrategy != nil {
+		sellState, err := s.sellStrategy.Save()
+		if err != nil {
+			return nil, fmt.Errorf("failed to save sell strategy state: %w", err)
+		}
+		state.SellStrategy = s.sellStrategy.GetName()
+		state.SellStrategyState = sellState
+	}
+	return json.Marshal(state)
+}
+
+// Load 恢复 Save 保存的状态，卖出策略已更换时只恢复布林道自身的状态
+func (s *BollingerBandsStrategy) Load(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var state bollingerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode bollinger state: %w", err)
+	}
+	if len(state.HighHistory) != len(state.PriceHistory) || len(state.LowHistory) != len(state.PriceHistory) {
+		return fmt.Errorf("invalid bollinger state: %d prices, %d highs, %d lows",
+			len(state.PriceHistory), len(state.HighHistory), len(state.LowHistory))
+	}
+	if s.sellStrategy != nil && state.SellStrategy == s.sellStrategy.GetName() {
+		if err := s.sellStrategy.Load(state.SellStrategyState); err != nil {
+			return err
+		}
+	}
+
+	s.currentBar = state.CurrentBar
+	s.lastTradeBar = state.LastTradeBar
+	s.lastTradePrice = state.LastTradePrice
+	s.entryTime = state.EntryTime
+	s.entryATR = state.EntryATR
+	s.hasBought = state.HasBought
+	s.highestPriceSinceBuy = state.HighestPrice
+	s.priceHistory = state.PriceHistory
+	s.highHistory = state.HighHistory
+	s.lowHistory = state.LowHistory
+	return nil
+}
+
+// GetIndicators 获取最近一根K线的布林道上中下轨（启用 ATR 止盈止损时含 ATR），数据不足时返回空
+func (s *BollingerBandsStrategy) GetIndicators() map[string]float64 {
+	if s.lastBands == nil {
+		return nil
+	}
+	snapshot := map[string]float64{
+		"bb_upper":  s.lastBands.UpperBand.InexactFloat64(),
+		"bb_middle": s.lastBands.MiddleBand.InexactFloat64(),
+		"bb_lower":  s.lastBands.LowerBand.InexactFloat64(),
+	}
+	// %B：价格在上下轨之间的位置（0 为下轨，1 为上轨）；带宽：上下轨距离相对中轨的比例
+	if width := s.lastBands.UpperBand.Sub(s.lastBands.LowerBand); width.IsPositive() {
+		snapshot["bb_percent_b"] = s.lastBands.Price.Sub(s.lastBands.LowerBand).Div(width).InexactFloat64()
+		if s.lastBands.MiddleBand.IsPositive() {
+			snapshot["bb_width"] = width.Div(s.lastBands.MiddleBand).InexactFloat64()
+		}
+	}
+	if atr := s.currentATR(); atr.IsPositive() {
+		snapshot["atr"] = atr.InexactFloat64()
+	}
+	if s.volumeFilter != nil {
+		if ratio, ok := s.volumeFilter.Ratio(); ok {
+			snapshot["volume_ratio"] = ratio
+		}
+	}
+	if vwap, ok := s.VWAP(); ok {
+		snapshot["vwap"] = vwap.InexactFloat64()
+	}
+	if s.obv != nil {
+		snapshot["obv"] = s.obv.Value().InexactFloat64()
+	}
+	return snapshot
+}
+
+// VWAP 当日（UTC）会话 VWAP，没有成交量时 ok 为 false
+func (s *BollingerBandsStrategy) VWAP() (decimal.Decimal, bool) {
+	if s.vwap == nil {
+		return decimal.Zero, false
+	}
+	return s.vwap.Value()
+}
+
+// OBV 自第一根K线起累计的能量潮
+func (s *BollingerBandsStrategy) OBV() decimal.Decimal {
+	if s.obv == nil {
+		return decimal.Zero
+	}
+	return s.obv.Value()
+}
+
+// usesATR 是否启用 ATR 止盈止损
+func (s *BollingerBandsStrategy) usesATR() bool {
+	return s.ATRPeriod > 0 && (s.ATRStopMultiple > 0 || s.ATRTakeProfitMultiple > 0)
+}
+
+// currentATR 当前K线的 ATR，未启用或数据不足时返回 0
+func (s *BollingerBandsStrategy) currentATR() decimal.Decimal {
+	if !s.usesATR() || s.atr == nil {
+		return decimal.Zero
+	}
+	atr, err := s.atr.Calculate(s.highHistory, s.lowHistory, s.priceHistory)
+	if err != nil {
+		return decimal.Zero
+	}
+	return atr
+}
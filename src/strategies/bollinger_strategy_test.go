@@ -0,0 +1,222 @@
+package strategies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/indicators"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBollingerBandsStrategy_BandWidthFilter(t *testing.T) {
+	// 5 根K线 [100,100,100,100,90]：中轨 98，标准差 4，下轨 90，带宽 16/98 ≈ 0.163，最后一根触及下轨
+	run := func(minWidth, maxWidth float64) []*strategy.Signal {
+		s := NewBollingerBandsStrategy()
+		params := strategy.GetDefaultBollingerBandsParams()
+		params.Period = 5
+		params.MinBandWidth, params.MaxBandWidth = minWidth, maxWidth
+		require.NoError(t, s.SetParams(params))
+
+		ctx := context.Background()
+		flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		var signals []*strategy.Signal
+		for i, price := range []int64{100, 100, 100, 100, 90} {
+			openTime := start.Add(time.Duration(i) * time.Hour)
+			kline := &cex.KlineData{OpenTime: openTime, CloseTime: openTime.Add(time.Hour), Open: decimal.NewFromInt(price),
+				High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)}
+			var err error
+			signals, err = s.OnData(ctx, kline, flat)
+			require.NoError(t, err)
+		}
+		return signals
+	}
+
+	signals := run(0, 0)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Type)
+
+	assert.Len(t, run(0.1, 0.3), 1)
+	assert.Empty(t, run(0.2, 0), "squeeze threshold above band width skips the buy")
+	assert.Empty(t, run(0, 0.1), "blow-off threshold below band width skips the buy")
+}
+
+func TestBollingerBandsStrategy_BandWidthFilterReason(t *testing.T) {
+	bands := &indicators.BollingerBandsResult{
+		UpperBand:  decimal.NewFromInt(102),
+		MiddleBand: decimal.NewFromInt(100),
+		LowerBand:  decimal.NewFromInt(98),
+	}
+	s := &BollingerBandsStrategy{MinBandWidth: 0.05}
+	assert.Contains(t, s.bandWidthFilter(bands), "below squeeze threshold")
+
+	s = &BollingerBandsStrategy{MaxBandWidth: 0.03}
+	assert.Contains(t, s.bandWidthFilter(bands), "above blow-off threshold")
+
+	s = &BollingerBandsStrategy{MinBandWidth: 0.03, MaxBandWidth: 0.05}
+	assert.Empty(t, s.bandWidthFilter(bands))
+
+	// 中轨为零时不计算带宽
+	assert.Empty(t, s.bandWidthFilter(&indicators.BollingerBandsResult{}))
+}
+
+func TestBollingerBandsStrategy_VolumeFilter(t *testing.T) {
+	// 与带宽过滤相同的价格序列，最后一根触及下轨；前 4 根成交量 100
+	run := func(lastVolume int64) (*BollingerBandsStrategy, []*strategy.Signal) {
+		s := NewBollingerBandsStrategy()
+		params := strategy.GetDefaultBollingerBandsParams()
+		params.Period = 5
+		params.VolumePeriod, params.VolumeMultiplier = 3, 1.5
+		require.NoError(t, s.SetParams(params))
+
+		ctx := context.Background()
+		flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		var signals []*strategy.Signal
+		for i, price := range []int64{100, 100, 100, 100, 90} {
+			volume := int64(100)
+			if i == 4 {
+				volume = lastVolume
+			}
+			openTime := start.Add(time.Duration(i) * time.Hour)
+			kline := &cex.KlineData{OpenTime: openTime, CloseTime: openTime.Add(time.Hour), Open: decimal.NewFromInt(price),
+				High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price),
+				Volume: decimal.NewFromInt(volume)}
+			var err error
+			signals, err = s.OnData(ctx, kline, flat)
+			require.NoError(t, err)
+		}
+		return s, signals
+	}
+
+	_, signals := run(120)
+	assert.Empty(t, signals, "volume 1.2× average does not confirm the buy")
+
+	s, signals := run(200)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Type)
+	assert.InDelta(t, 2.0, s.GetIndicators()["volume_ratio"], 1e-9)
+}
+
+func TestBollingerBandsStrategy_FundingFilter(t *testing.T) {
+	// 与带宽过滤相同的价格序列，最后一根（04:00 开盘）触及下轨；00:00 结算的资金费率为 rate
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(maxFundingRate, rate float64) []*strategy.Signal {
+		s := NewBollingerBandsStrategy()
+		params := strategy.GetDefaultBollingerBandsParams()
+		params.Period = 5
+		params.MaxFundingRate = maxFundingRate
+		require.NoError(t, s.SetParams(params))
+		assert.Equal(t, maxFundingRate != 0, s.UsesFuturesContext())
+		s.SetFuturesContext(strategy.NewFuturesContext([]*cex.FundingRate{{Time: start, Rate: decimal.NewFromFloat(rate)}}, nil))
+
+		ctx := context.Background()
+		flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+		var signals []*strategy.Signal
+		for i, price := range []int64{100, 100, 100, 100, 90} {
+			openTime := start.Add(time.Duration(i) * time.Hour)
+			kline := &cex.KlineData{OpenTime: openTime, CloseTime: openTime.Add(time.Hour), Open: decimal.NewFromInt(price),
+				High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)}
+			var err error
+			signals, err = s.OnData(ctx, kline, flat)
+			require.NoError(t, err)
+		}
+		return signals
+	}
+
+	assert.Len(t, run(0, 0.001), 1, "filter disabled")
+	assert.Len(t, run(0.0005, 0.0001), 1)
+	assert.Empty(t, run(0.0005, 0.001), "crowded longs skip the buy")
+}
+
+func TestBollingerBandsStrategy_VWAPAndOBV(t *testing.T) {
+	s := NewBollingerBandsStrategy()
+	params := strategy.GetDefaultBollingerBandsParams()
+	params.Period = 2
+	require.NoError(t, s.SetParams(params))
+
+	_, ok := s.VWAP()
+	assert.False(t, ok)
+
+	ctx := context.Background()
+	flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, bar := range []struct{ high, low, close, volume int64 }{{12, 10, 11, 100}, {13, 11, 12, 300}} {
+		kline := &cex.KlineData{OpenTime: start.Add(time.Duration(i) * time.Hour), High: decimal.NewFromInt(bar.high),
+			Low: decimal.NewFromInt(bar.low), Close: decimal.NewFromInt(bar.close), Volume: decimal.NewFromInt(bar.volume)}
+		_, err := s.OnData(ctx, kline, flat)
+		require.NoError(t, err)
+	}
+
+	// (11×100 + 12×300) / 400；收盘上涨 OBV 累加 300
+	vwap, ok := s.VWAP()
+	assert.True(t, ok)
+	assert.InDelta(t, 11.75, vwap.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 300, s.OBV().InexactFloat64(), 1e-9)
+
+	indicators := s.GetIndicators()
+	assert.InDelta(t, 11.75, indicators["vwap"], 1e-9)
+	assert.InDelta(t, 300, indicators["obv"], 1e-9)
+}
+
+func TestBollingerBandsStrategy_SaveLoad(t *testing.T) {
+	newStrategy := func() *BollingerBandsStrategy {
+		s := NewBollingerBandsStrategy()
+		params := strategy.GetDefaultBollingerBandsParams()
+		params.Period = 5
+		params.StopLossPercent = 0.1
+		require.NoError(t, s.SetParams(params))
+		return s
+	}
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klineAt := func(i int, price int64) *cex.KlineData {
+		openTime := start.Add(time.Duration(i) * time.Hour)
+		return &cex.KlineData{OpenTime: openTime, CloseTime: openTime.Add(time.Hour), Open: decimal.NewFromInt(price),
+			High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)}
+	}
+
+	s := newStrategy()
+	flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	var signals []*strategy.Signal
+	for i, price := range []int64{100, 100, 100, 100, 90} {
+		var err error
+		signals, err = s.OnData(ctx, klineAt(i, price), flat)
+		require.NoError(t, err)
+	}
+	require.Len(t, signals, 1)
+	require.Equal(t, "BUY", signals[0].Type)
+
+	data, err := s.Save()
+	require.NoError(t, err)
+
+	// 重启后恢复开仓价和K线历史，持仓跌破 10% 止损
+	holding := &executor.Portfolio{Position: decimal.NewFromInt(1)}
+	restored := newStrategy()
+	require.NoError(t, restored.Load(data))
+	assert.Equal(t, s.currentBar, restored.currentBar)
+	assert.True(t, restored.entryTime.Equal(s.entryTime))
+	assert.Len(t, restored.priceHistory, 5)
+
+	signals, err = restored.OnData(ctx, klineAt(5, 80), holding)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "SELL", signals[0].Type)
+	assert.Contains(t, signals[0].Reason, "stop loss")
+
+	// 未恢复状态时没有开仓价，不会止损
+	signals, err = newStrategy().OnData(ctx, klineAt(5, 80), holding)
+	require.NoError(t, err)
+	assert.Empty(t, signals)
+
+	assert.Error(t, newStrategy().Load([]byte(`{"price_history": ["1"], "high_history": []}`)))
+	assert.NoError(t, newStrategy().Load(nil))
+}
@@ -0,0 +1,225 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+)
+
+func init() {
+	RegisterStrategy("composite", func() strategy.Strategy { return NewCompositeStrategy() })
+}
+
+// CompositeStrategy 组合策略：每根K线把行情交给所有子策略，按 all/any/weighted 规则合并同方向信号，
+// 不写新代码即可组合入场条件（如布林道下轨 AND 脚本 RSI 超卖）
+type CompositeStrategy struct {
+	params  strategy.CompositeParams
+	members []strategy.Strategy
+}
+
+// NewCompositeStrategy 创建组合策略（没有子策略，需要 SetParams）
+func NewCompositeStrategy() *CompositeStrategy {
+	return &CompositeStrategy{params: *strategy.GetDefaultCompositeParams()}
+}
+
+// memberSignal 子策略发出的信号
+type memberSignal struct {
+	member strategy.CompositeMember
+	signal *strategy.Signal
+}
+
+// OnData 所有子策略都处理每根K线（保持各自的指标历史），再按规则合并买入、卖出信号
+func (s *CompositeStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	votes := map[string][]memberSignal{}
+	for i, member := range s.members {
+		signals, err := member.OnData(ctx, kline, portfolio)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.params.Members[i].Label(), err)
+		}
+		seen := map[string]bool{}
+		for _, signal := range signals {
+			// 每个子策略每个方向只计一票
+			if seen[signal.Type] {
+				continue
+			}
+			seen[signal.Type] = true
+			votes[signal.Type] = append(votes[signal.Type], memberSignal{member: s.params.Members[i], signal: signal})
+		}
+	}
+
+	var merged []*strategy.Signal
+	if signal := s.merge(s.params.BuyRule, votes["BUY"]); signal != nil {
+		merged = append(merged, signal)
+	}
+	if signal := s.merge(s.params.SellRule, votes["SELL"]); signal != nil {
+		merged = append(merged, signal)
+	}
+	return merged, nil
+}
+
+// merge 按规则合并同方向信号，不满足规则时返回 nil；下单方式等字段沿用第一个发出信号的子策略
+func (s *CompositeStrategy) merge(rule strategy.CompositeRule, votes []memberSignal) *strategy.Signal {
+	if len(votes) == 0 {
+		return nil
+	}
+
+	var strength float64
+	switch rule {
+	case strategy.CompositeAll:
+		if len(votes) < len(s.members) {
+			return nil
+		}
+		strength = math.Inf(1)
+		for _, vote := range votes {
+			strength = math.Min(strength, vote.signal.Strength)
+		}
+	case strategy.CompositeAny:
+		for _, vote := range votes {
+			strength = math.Max(strength, vote.signal.Strength)
+		}
+	case strategy.CompositeWeighted:
+		var totalWeight, score float64
+		for _, member := range s.params.Members {
+			totalWeight += member.EffectiveWeight()
+		}
+		for _, vote := range votes {
+			score += vote.member.EffectiveWeight() * vote.signal.Strength
+		}
+		if totalWeight <= 0 || score/totalWeight < s.params.Threshold {
+			return nil
+		}
+		strength = score / totalWeight
+	default:
+		return nil
+	}
+
+	reasons := make([]string, len(votes))
+	for i, vote := range votes {
+		reasons[i] = fmt.Sprintf("%s: %s", vote.member.Label(), vote.signal.Reason)
+	}
+	signal := *votes[0].signal
+	signal.Reason = fmt.Sprintf("%s[%s]", rule, strings.Join(reasons, "; "))
+	signal.Strength = strength
+	return &signal
+}
+
+// GetName 获取策略名称
+func (s *CompositeStrategy) GetName() string {
+	labels := make([]string, len(s.params.Members))
+	for i, member := range s.params.Members {
+		labels[i] = member.Label()
+	}
+	return fmt.Sprintf("Composite(buy %s, sell %s: %s)", s.params.BuyRule, s.params.SellRule, strings.Join(labels, ", "))
+}
+
+// GetParams 获取策略参数
+func (s *CompositeStrategy) GetParams() strategy.StrategyParams {
+	params := s.params
+	params.Members = append([]strategy.CompositeMember(nil), s.params.Members...)
+	return &params
+}
+
+// SetParams 设置策略参数，按名称创建子策略并用成员参数覆盖其默认参数
+func (s *CompositeStrategy) SetParams(params strategy.StrategyParams) error {
+	compositeParams, ok := params.(*strategy.CompositeParams)
+	if !ok {
+		return fmt.Errorf("invalid parameter type, expected *strategy.CompositeParams")
+	}
+	if err := compositeParams.Validate(); err != nil {
+		return err
+	}
+
+	members := make([]strategy.Strategy, len(compositeParams.Members))
+	for i, config := range compositeParams.Members {
+		member, err := CreateStrategy(config.Strategy)
+		if err != nil {
+			return fmt.Errorf("member %s: %w", config.Label(), err)
+		}
+		if len(config.Params) > 0 {
+			memberParams := member.GetParams()
+			if err := json.Unmarshal(config.Params, memberParams); err != nil {
+				return fmt.Errorf("member %s: failed to parse params: %w", config.Label(), err)
+			}
+			if err := memberParams.Validate(); err != nil {
+				return fmt.Errorf("member %s: invalid params: %w", config.Label(), err)
+			}
+			if err := member.SetParams(memberParams); err != nil {
+				return fmt.Errorf("member %s: %w", config.Label(), err)
+			}
+		}
+		members[i] = member
+	}
+
+	s.params = *compositeParams
+	s.params.Members = append([]strategy.CompositeMember(nil), compositeParams.Members...)
+	s.members = members
+	return nil
+}
+
+// GetIndicators 合并子策略的指标快照，同名指标以靠前的子策略为准
+func (s *CompositeStrategy) GetIndicators() map[string]float64 {
+	var snapshot map[string]float64
+	for i := len(s.members) - 1; i >= 0; i-- {
+		provider, ok := s.members[i].(strategy.IndicatorProvider)
+		if !ok {
+			continue
+		}
+		for name, value := range provider.GetIndicators() {
+			if snapshot == nil {
+				snapshot = map[string]float64{}
+			}
+			snapshot[name] = value
+		}
+	}
+	return snapshot
+}
+
+// compositeMemberState 子策略的持久化状态
+type compositeMemberState struct {
+	Strategy string          `json:"strategy"`
+	State    json.RawMessage `json:"state,omitempty"`
+}
+
+// Save 按顺序保存各子策略的状态
+func (s *CompositeStrategy) Save() ([]byte, error) {
+	states := make([]compositeMemberState, len(s.members))
+	for i, member := range s.members {
+		state, err := member.Save()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.params.Members[i].Label(), err)
+		}
+		states[i] = compositeMemberState{Strategy: s.params.Members[i].Strategy, State: state}
+	}
+	return json.Marshal(states)
+}
+
+// Load 恢复各子策略的状态，子策略数量或类型与保存时不同时返回错误
+func (s *CompositeStrategy) Load(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var states []compositeMemberState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return fmt.Errorf("failed to decode composite state: %w", err)
+	}
+	if len(states) != len(s.members) {
+		return fmt.Errorf("composite state has %d members, strategy has %d", len(states), len(s.members))
+	}
+	for i, state := range states {
+		if state.Strategy != s.params.Members[i].Strategy {
+			return fmt.Errorf("member %s: saved state is for strategy %q", s.params.Members[i].Label(), state.Strategy)
+		}
+	}
+	for i, state := range states {
+		if err := s.members[i].Load(state.State); err != nil {
+			return fmt.Errorf("%s: %w", s.params.Members[i].Label(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptMember 用脚本条件构造子策略
+func scriptMember(name, buy string, weight float64) strategy.CompositeMember {
+	params, _ := json.Marshal(map[string]interface{}{"buy": buy, "sell": "close > 1000", "history": 10})
+	return strategy.CompositeMember{Name: name, Strategy: "script", Params: params, Weight: weight}
+}
+
+func newComposite(t *testing.T, rule strategy.CompositeRule, threshold float64, members ...strategy.CompositeMember) *CompositeStrategy {
+	params := strategy.GetDefaultCompositeParams()
+	params.BuyRule = rule
+	params.Threshold = threshold
+	params.Members = members
+	s := NewCompositeStrategy()
+	require.NoError(t, s.SetParams(params))
+	return s
+}
+
+func compositeOnData(t *testing.T, s *CompositeStrategy, price int64) []*strategy.Signal {
+	kline := &cex.KlineData{OpenTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Open: decimal.NewFromInt(price),
+		High: decimal.NewFromInt(price), Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)}
+	signals, err := s.OnData(context.Background(), kline, &executor.Portfolio{Cash: decimal.NewFromInt(1000)})
+	require.NoError(t, err)
+	return signals
+}
+
+func TestCompositeStrategy_All(t *testing.T) {
+	s := newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", "close > 100", 0), scriptMember("b", "close > 105", 0))
+
+	// 只有一个子策略发出信号
+	assert.Empty(t, compositeOnData(t, s, 103))
+
+	signals := compositeOnData(t, s, 106)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Type)
+	assert.Equal(t, 0.8, signals[0].Strength)
+	assert.Equal(t, "all[a: script: close > 100; b: script: close > 105]", signals[0].Reason)
+}
+
+func TestCompositeStrategy_Any(t *testing.T) {
+	s := newComposite(t, strategy.CompositeAny, 0.5, scriptMember("a", "close > 100", 0), scriptMember("b", "close > 105", 0))
+
+	assert.Empty(t, compositeOnData(t, s, 99))
+	signals := compositeOnData(t, s, 103)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "any[a: script: close > 100]", signals[0].Reason)
+}
+
+func TestCompositeStrategy_Weighted(t *testing.T) {
+	// 得分 = 0.8 × 1 / 4 = 0.2，低于阈值
+	s := newComposite(t, strategy.CompositeWeighted, 0.5, scriptMember("a", "close > 100", 1), scriptMember("b", "close > 105", 3))
+	assert.Empty(t, compositeOnData(t, s, 103))
+	signals := compositeOnData(t, s, 106)
+	require.Len(t, signals, 1)
+	assert.InDelta(t, 0.8, signals[0].Strength, 1e-9)
+
+	// 得分 = 0.8 × 3 / 4 = 0.6
+	s = newComposite(t, strategy.CompositeWeighted, 0.5, scriptMember("a", "close > 100", 3), scriptMember("b", "close > 105", 1))
+	signals = compositeOnData(t, s, 103)
+	require.Len(t, signals, 1)
+	assert.InDelta(t, 0.6, signals[0].Strength, 1e-9)
+}
+
+func TestCompositeStrategy_SetParams(t *testing.T) {
+	s := newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", "close > 100", 0))
+	assert.Equal(t, "Composite(buy all, sell any: a)", s.GetName())
+
+	invalid := []*strategy.CompositeParams{
+		{BuyRule: "majority", SellRule: strategy.CompositeAny, Threshold: 0.5, Members: []strategy.CompositeMember{scriptMember("a", "close > 100", 0)}},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0, Members: []strategy.CompositeMember{scriptMember("a", "close > 100", 0)}},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0.5},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0.5, Members: []strategy.CompositeMember{{Strategy: "unknown"}}},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0.5, Members: []strategy.CompositeMember{scriptMember("a", "close <", 0)}},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0.5, Members: []strategy.CompositeMember{scriptMember("a", "close > 100", -1)}},
+		{BuyRule: strategy.CompositeAll, SellRule: strategy.CompositeAny, Threshold: 0.5, Members: []strategy.CompositeMember{{Strategy: "script", Params: json.RawMessage(`{"history": "x"}`)}}},
+	}
+	for _, params := range invalid {
+		assert.Error(t, s.SetParams(params), "%+v", params)
+	}
+
+	// 无效参数不影响原有子策略
+	assert.Equal(t, "Composite(buy all, sell any: a)", s.GetName())
+	assert.Len(t, compositeOnData(t, s, 101), 1)
+}
+
+func TestCompositeStrategy_SaveLoad(t *testing.T) {
+	buy := "close > prev(close, 1)"
+	s := newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", buy, 0), scriptMember("b", "close > 100", 0))
+	assert.Empty(t, compositeOnData(t, s, 101))
+
+	data, err := s.Save()
+	require.NoError(t, err)
+
+	// 恢复K线历史后第一根K线即可比较前收盘价
+	restored := newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", buy, 0), scriptMember("b", "close > 100", 0))
+	require.NoError(t, restored.Load(data))
+	assert.Len(t, compositeOnData(t, restored, 102), 1)
+	assert.Empty(t, compositeOnData(t, newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", buy, 0), scriptMember("b", "close > 100", 0)), 102))
+
+	// 子策略数量或类型不同
+	assert.Error(t, newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", buy, 0)).Load(data))
+	other := newComposite(t, strategy.CompositeAll, 0.5, scriptMember("a", buy, 0), strategy.CompositeMember{Strategy: "bollinger"})
+	assert.Error(t, other.Load(data))
+}
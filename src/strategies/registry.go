@@ -0,0 +1,42 @@
+package strategies
+
+import (
+	"fmt"
+	"sort"
+
+	"tradingbot/src/strategy"
+)
+
+// StrategyFactory 策略工厂函数
+type StrategyFactory func() strategy.Strategy
+
+// StrategyFactoryRegistry 策略工厂注册表
+var StrategyFactoryRegistry = make(map[string]StrategyFactory)
+
+func init() {
+	RegisterStrategy("bollinger", func() strategy.Strategy { return NewBollingerBandsStrategy() })
+}
+
+// RegisterStrategy 注册策略（新策略在自己文件的 init 中调用）
+func RegisterStrategy(name string, factory StrategyFactory) {
+	StrategyFactoryRegistry[name] = factory
+}
+
+// CreateStrategy 根据名称创建策略实例
+func CreateStrategy(name string) (strategy.Strategy, error) {
+	factory, exists := StrategyFactoryRegistry[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported strategy: %s (available: %v)", name, GetSupportedStrategies())
+	}
+	return factory(), nil
+}
+
+// GetSupportedStrategies 获取已注册的策略列表
+func GetSupportedStrategies() []string {
+	var names []string
+	for name := range StrategyFactoryRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
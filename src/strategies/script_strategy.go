@@ -0,0 +1,174 @@
+package strategies
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/script"
+	"tradingbot/src/strategy"
+
+	"github.com/xpwu/go-log/log"
+)
+
+func init() {
+	RegisterStrategy("script", func() strategy.Strategy { return NewScriptStrategy() })
+}
+
+// ScriptStrategy 脚本策略：买卖条件由参数文件中的表达式定义
+type ScriptStrategy struct {
+	params strategy.ScriptParams
+	buy    *script.Program
+	sell   *script.Program
+
+	bars       []script.Bar
+	currentBar int
+	entryPrice float64
+	entryBar   int
+}
+
+// NewScriptStrategy 创建脚本策略（使用默认参数）
+func NewScriptStrategy() *ScriptStrategy {
+	s := &ScriptStrategy{}
+	if err := s.SetParams(strategy.GetDefaultScriptParams()); err != nil {
+		panic(fmt.Sprintf("invalid default script params: %v", err))
+	}
+	return s
+}
+
+// OnData 处理新的K线数据：无持仓时判断买入条件，有持仓时判断卖出条件
+func (s *ScriptStrategy) OnData(ctx context.Context, kline *cex.KlineData, portfolio *executor.Portfolio) ([]*strategy.Signal, error) {
+	ctx, logger := log.WithCtx(ctx)
+	logger.PushPrefix("ScriptStrategy")
+
+	s.currentBar++
+	s.bars = append(s.bars, script.Bar{
+		Open:   kline.Open.InexactFloat64(),
+		High:   kline.High.InexactFloat64(),
+		Low:    kline.Low.InexactFloat64(),
+		Close:  kline.Close.InexactFloat64(),
+		Volume: kline.Volume.InexactFloat64(),
+	})
+	if len(s.bars) > s.params.History {
+		s.bars = s.bars[len(s.bars)-s.params.History:]
+	}
+
+	holding := portfolio.Position.IsPositive()
+	if !holding {
+		s.entryPrice = 0
+	}
+	env := &script.Env{Bars: s.bars, Vars: s.vars(portfolio)}
+
+	condition, signalType, strength := s.buy, "BUY", 0.8
+	if holding {
+		condition, signalType, strength = s.sell, "SELL", 1.0
+	}
+	value, err := condition.Eval(env)
+	if errors.Is(err, script.ErrNotEnoughData) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s condition %q: %w", signalType, condition, err)
+	}
+	if value == 0 {
+		return nil, nil
+	}
+
+	if signalType == "BUY" {
+		s.entryPrice = env.Bars[len(env.Bars)-1].Close
+		s.entryBar = s.currentBar
+	}
+	reason := fmt.Sprintf("script: %s", condition)
+	logger.Info(fmt.Sprintf("✅ 脚本条件满足: signal=%s, price=%s, condition=%s", signalType, kline.Close.String(), condition))
+	return []*strategy.Signal{{
+		Type:      signalType,
+		Reason:    reason,
+		Strength:  strength,
+		Timestamp: kline.OpenTime.Unix() * 1000,
+	}}, nil
+}
+
+// vars 表达式变量：用户变量和持仓等内置变量
+func (s *ScriptStrategy) vars(portfolio *executor.Portfolio) map[string]float64 {
+	vars := make(map[string]float64, len(s.params.Vars)+len(strategy.ScriptVars))
+	for name, value := range s.params.Vars {
+		vars[name] = value
+	}
+	vars["position"] = portfolio.Position.InexactFloat64()
+	vars["cash"] = portfolio.Cash.InexactFloat64()
+	vars["bar"] = float64(s.currentBar)
+	if s.entryPrice > 0 {
+		vars["entry_price"] = s.entryPrice
+		vars["pnl"] = s.bars[len(s.bars)-1].Close/s.entryPrice - 1
+		vars["bars_held"] = float64(s.currentBar - s.entryBar)
+	}
+	return vars
+}
+
+// GetName 获取策略名称
+func (s *ScriptStrategy) GetName() string {
+	return "script"
+}
+
+// GetParams 获取策略参数
+func (s *ScriptStrategy) GetParams() strategy.StrategyParams {
+	params := s.params
+	params.Vars = make(map[string]float64, len(s.params.Vars))
+	for name, value := range s.params.Vars {
+		params.Vars[name] = value
+	}
+	return &params
+}
+
+// SetParams 设置策略参数（编译买卖条件）
+func (s *ScriptStrategy) SetParams(params strategy.StrategyParams) error {
+	scriptParams, ok := params.(*strategy.ScriptParams)
+	if !ok {
+		return fmt.Errorf("invalid parameter type, expected *strategy.ScriptParams")
+	}
+	if err := scriptParams.Validate(); err != nil {
+		return err
+	}
+	buy, sell, err := scriptParams.Compile()
+	if err != nil {
+		return err
+	}
+	s.params = *scriptParams
+	s.buy, s.sell = buy, sell
+	return nil
+}
+
+// scriptState 脚本策略的持久化状态
+type scriptState struct {
+	Bars       []script.Bar `json:"bars"`
+	CurrentBar int          `json:"current_bar"`
+	EntryPrice float64      `json:"entry_price"`
+	EntryBar   int          `json:"entry_bar"`
+}
+
+// Save 保存K线历史和开仓信息（pnl、bars_held 在重启后保持连续）
+func (s *ScriptStrategy) Save() ([]byte, error) {
+	return json.Marshal(scriptState{Bars: s.bars, CurrentBar: s.currentBar, EntryPrice: s.entryPrice, EntryBar: s.entryBar})
+}
+
+// Load 恢复 Save 保存的状态，K线历史超出 history 时只保留最近部分
+func (s *ScriptStrategy) Load(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var state scriptState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode script state: %w", err)
+	}
+	if len(state.Bars) > s.params.History {
+		state.Bars = state.Bars[len(state.Bars)-s.params.History:]
+	}
+	s.bars = state.Bars
+	s.currentBar = state.CurrentBar
+	s.entryPrice = state.EntryPrice
+	s.entryBar = state.EntryBar
+	return nil
+}
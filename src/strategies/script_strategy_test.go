@@ -0,0 +1,75 @@
+package strategies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptStrategy_Signals(t *testing.T) {
+	s := NewScriptStrategy()
+	require.NoError(t, s.SetParams(&strategy.ScriptParams{
+		Buy:     "close < lowest(prev(close, 1), 2)",
+		Sell:    "pnl >= target || bars_held >= 3",
+		Vars:    map[string]float64{"target": 0.1},
+		History: 10,
+	}))
+
+	ctx := context.Background()
+	flat := &executor.Portfolio{Cash: decimal.NewFromInt(1000)}
+	holding := &executor.Portfolio{Position: decimal.NewFromInt(1)}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	onData := func(price int64, portfolio *executor.Portfolio) []*strategy.Signal {
+		kline := &cex.KlineData{OpenTime: start, Open: decimal.NewFromInt(price), High: decimal.NewFromInt(price),
+			Low: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)}
+		start = start.Add(time.Hour)
+		signals, err := s.OnData(ctx, kline, portfolio)
+		require.NoError(t, err)
+		return signals
+	}
+
+	// 预热期K线不足时不出信号
+	assert.Empty(t, onData(100, flat))
+	assert.Empty(t, onData(101, flat))
+	assert.Empty(t, onData(102, flat))
+
+	signals := onData(99, flat)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BUY", signals[0].Type)
+	assert.Contains(t, signals[0].Reason, "lowest")
+
+	// 有持仓时只判断卖出条件：涨 10% 止盈
+	assert.Empty(t, onData(95, holding))
+	signals = onData(109, holding)
+	require.Len(t, signals, 1)
+	assert.Equal(t, "SELL", signals[0].Type)
+	assert.Equal(t, 1.0, signals[0].Strength)
+}
+
+func TestScriptStrategy_SetParams(t *testing.T) {
+	s := NewScriptStrategy()
+	params := s.GetParams().(*strategy.ScriptParams)
+	assert.Contains(t, params.Buy, "bb_lower")
+
+	for _, invalid := range []*strategy.ScriptParams{
+		{Buy: "close <", Sell: "close > 1", History: 10},
+		{Buy: "close < 1", Sell: "", History: 10},
+		{Buy: "close < limit", Sell: "close > 1", History: 10},
+		{Buy: "close < 1", Sell: "close > 1", Vars: map[string]float64{"pnl": 1}, History: 10},
+		{Buy: "close < 1", Sell: "close > 1", History: 0},
+	} {
+		assert.Error(t, s.SetParams(invalid), "%+v", invalid)
+	}
+	assert.Error(t, s.SetParams(strategy.GetDefaultBollingerBandsParams()))
+
+	// 无效参数不影响原有参数
+	assert.Equal(t, params.Buy, s.GetParams().(*strategy.ScriptParams).Buy)
+}
@@ -0,0 +1,82 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CompositeRule 组合策略合并子策略信号的规则
+type CompositeRule string
+
+const (
+	CompositeAll      CompositeRule = "all"      // 所有子策略同时发出同方向信号，强度取最小值
+	CompositeAny      CompositeRule = "any"      // 任一子策略发出信号，强度取最大值
+	CompositeWeighted CompositeRule = "weighted" // 加权投票：Σ(权重 × 强度) / Σ权重 达到阈值，强度取该得分
+)
+
+// CompositeMember 组合策略中的一个子策略
+type CompositeMember struct {
+	Name     string          `json:"name,omitempty"`   // 显示名称（信号原因中使用），默认为策略名称
+	Strategy string          `json:"strategy"`         // 已注册的策略名称（如 bollinger、script）
+	Params   json.RawMessage `json:"params,omitempty"` // 策略参数，覆盖该策略的默认参数（格式与该策略的参数文件相同）
+	Weight   float64         `json:"weight,omitempty"` // weighted 规则的权重，默认 1
+}
+
+// Label 信号原因中显示的名称
+func (m CompositeMember) Label() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Strategy
+}
+
+// EffectiveWeight 投票权重（未设置时为 1）
+func (m CompositeMember) EffectiveWeight() float64 {
+	if m.Weight == 0 {
+		return 1
+	}
+	return m.Weight
+}
+
+// CompositeParams 组合策略参数：每根K线把行情交给所有子策略，按规则合并买入、卖出信号
+type CompositeParams struct {
+	BuyRule   CompositeRule     `json:"buy_rule"`  // 买入信号的合并规则，默认 all
+	SellRule  CompositeRule     `json:"sell_rule"` // 卖出信号的合并规则，默认 any
+	Threshold float64           `json:"threshold"` // weighted 规则的得分阈值（0-1），默认 0.5
+	Members   []CompositeMember `json:"members"`
+}
+
+// GetDefaultCompositeParams 获取默认的组合策略参数（没有子策略，需要在参数文件中配置）
+func GetDefaultCompositeParams() *CompositeParams {
+	return &CompositeParams{
+		BuyRule:   CompositeAll,
+		SellRule:  CompositeAny,
+		Threshold: 0.5,
+	}
+}
+
+// Validate 验证参数有效性（子策略名称和参数在创建子策略时检查）
+func (p *CompositeParams) Validate() error {
+	for _, rule := range []CompositeRule{p.BuyRule, p.SellRule} {
+		switch rule {
+		case CompositeAll, CompositeAny, CompositeWeighted:
+		default:
+			return fmt.Errorf("unknown composite rule %q (supported: all, any, weighted)", rule)
+		}
+	}
+	if p.Threshold <= 0 || p.Threshold > 1 {
+		return fmt.Errorf("threshold must be in (0, 1], got %f", p.Threshold)
+	}
+	if len(p.Members) == 0 {
+		return fmt.Errorf("composite strategy requires at least one member")
+	}
+	for i, member := range p.Members {
+		if member.Strategy == "" {
+			return fmt.Errorf("member %d: strategy is required", i+1)
+		}
+		if member.Weight < 0 {
+			return fmt.Errorf("member %s: weight must be non-negative, got %f", member.Label(), member.Weight)
+		}
+	}
+	return nil
+}
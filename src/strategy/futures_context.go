@@ -0,0 +1,141 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+)
+
+// FundingStaleAfter 最新资金费率早于该时长时视为缺失（币安结算间隔最长 8 小时）
+const FundingStaleAfter = 24 * time.Hour
+
+// FuturesSnapshot 某一时刻已知的永续合约辅助数据
+type FuturesSnapshot struct {
+	HasFunding  bool
+	FundingRate float64   // 最近一次结算的资金费率
+	FundingTime time.Time // 最近一次结算时间
+
+	HasOpenInterest       bool
+	OpenInterest          float64   // 最近的持仓量（基础货币）
+	OpenInterestValue     float64   // 最近的持仓价值（计价货币）
+	OpenInterestTime      time.Time // 持仓量统计时间
+	OpenInterestChange    float64   // 相对上一条记录的持仓量变化比例
+	HasOpenInterestChange bool
+}
+
+// FuturesContext 永续合约资金费率和持仓量序列，按时间查询时只返回当时已知的数据（无未来函数）
+// 实盘可在运行中整体替换数据，查询和替换可并发调用
+type FuturesContext struct {
+	mu           sync.RWMutex
+	funding      []*cex.FundingRate
+	openInterest []*cex.OpenInterest
+}
+
+// NewFuturesContext 创建合约辅助数据，记录按时间排序
+func NewFuturesContext(funding []*cex.FundingRate, openInterest []*cex.OpenInterest) *FuturesContext {
+	c := &FuturesContext{}
+	c.Replace(funding, openInterest)
+	return c
+}
+
+// Replace 替换全部数据（实盘定期刷新）
+func (c *FuturesContext) Replace(funding []*cex.FundingRate, openInterest []*cex.OpenInterest) {
+	funding = append([]*cex.FundingRate(nil), funding...)
+	sort.Slice(funding, func(i, j int) bool { return funding[i].Time.Before(funding[j].Time) })
+	openInterest = append([]*cex.OpenInterest(nil), openInterest...)
+	sort.Slice(openInterest, func(i, j int) bool { return openInterest[i].Time.Before(openInterest[j].Time) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.funding = funding
+	c.openInterest = openInterest
+}
+
+// Counts 资金费率和持仓量记录数
+func (c *FuturesContext) Counts() (funding, openInterest int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.funding), len(c.openInterest)
+}
+
+// At 时刻 t（含）之前最近的资金费率和持仓量
+func (c *FuturesContext) At(t time.Time) FuturesSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var snapshot FuturesSnapshot
+	if i := sort.Search(len(c.funding), func(i int) bool { return c.funding[i].Time.After(t) }); i > 0 {
+		rate := c.funding[i-1]
+		snapshot.HasFunding = true
+		snapshot.FundingRate = rate.Rate.InexactFloat64()
+		snapshot.FundingTime = rate.Time
+	}
+	if i := sort.Search(len(c.openInterest), func(i int) bool { return c.openInterest[i].Time.After(t) }); i > 0 {
+		oi := c.openInterest[i-1]
+		snapshot.HasOpenInterest = true
+		snapshot.OpenInterest = oi.OpenInterest.InexactFloat64()
+		snapshot.OpenInterestValue = oi.Value.InexactFloat64()
+		snapshot.OpenInterestTime = oi.Time
+		if i > 1 {
+			if previous := c.openInterest[i-2].OpenInterest; previous.IsPositive() {
+				snapshot.OpenInterestChange = oi.OpenInterest.Div(previous).InexactFloat64() - 1
+				snapshot.HasOpenInterestChange = true
+			}
+		}
+	}
+	return snapshot
+}
+
+// FuturesContextConsumer 使用永续合约资金费率、持仓量作为辅助数据的策略（现货交易也可使用）
+// 引擎启动前从数据库加载 futures-sync 同步的数据并注入，UsesFuturesContext 返回 false 时不加载
+type FuturesContextConsumer interface {
+	// UsesFuturesContext 当前参数是否需要合约辅助数据
+	UsesFuturesContext() bool
+
+	// SetFuturesContext 注入合约辅助数据
+	SetFuturesContext(fc *FuturesContext)
+}
+
+// FundingFilter 资金费率过滤：最近一次资金费率高于 MaxRate 时（多头拥挤）不开仓
+type FundingFilter struct {
+	MaxRate float64
+
+	fc *FuturesContext
+}
+
+// NewFundingFilter 创建资金费率过滤器，数据通过 SetContext 注入
+func NewFundingFilter(maxRate float64) *FundingFilter {
+	return &FundingFilter{MaxRate: maxRate}
+}
+
+// SetContext 设置合约辅助数据
+func (f *FundingFilter) SetContext(fc *FuturesContext) {
+	f.fc = fc
+}
+
+// Rate 时刻 t 已知的最近资金费率，无数据或数据过期时 ok 为 false
+func (f *FundingFilter) Rate(t time.Time) (rate float64, ok bool) {
+	if f.fc == nil {
+		return 0, false
+	}
+	snapshot := f.fc.At(t)
+	if !snapshot.HasFunding || t.Sub(snapshot.FundingTime) > FundingStaleAfter {
+		return 0, false
+	}
+	return snapshot.FundingRate, true
+}
+
+// Confirm 时刻 t 是否允许开仓，未确认时返回原因（无数据时不确认）
+func (f *FundingFilter) Confirm(t time.Time) (bool, string) {
+	rate, ok := f.Rate(t)
+	if !ok {
+		return false, fmt.Sprintf("no funding rate within %s before %s (run futures-sync)", FundingStaleAfter, t.UTC().Format("2006-01-02 15:04"))
+	}
+	if rate > f.MaxRate {
+		return false, fmt.Sprintf("funding rate %.4f%% above %.4f%%", rate*100, f.MaxRate*100)
+	}
+	return true, ""
+}
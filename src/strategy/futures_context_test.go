@@ -0,0 +1,91 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var futuresTestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// futuresTestContext 每 8 小时一次资金费率，每小时一条持仓量
+func futuresTestContext(rates []float64, openInterest []float64) *FuturesContext {
+	var funding []*cex.FundingRate
+	for i, rate := range rates {
+		funding = append(funding, &cex.FundingRate{Time: futuresTestStart.Add(time.Duration(i) * 8 * time.Hour), Rate: decimal.NewFromFloat(rate)})
+	}
+	var history []*cex.OpenInterest
+	// 倒序传入，由 FuturesContext 排序
+	for i := len(openInterest) - 1; i >= 0; i-- {
+		history = append(history, &cex.OpenInterest{
+			Time:         futuresTestStart.Add(time.Duration(i) * time.Hour),
+			OpenInterest: decimal.NewFromFloat(openInterest[i]),
+			Value:        decimal.NewFromFloat(openInterest[i] * 40000),
+		})
+	}
+	return NewFuturesContext(funding, history)
+}
+
+func TestFuturesContext_AtUsesOnlyKnownData(t *testing.T) {
+	fc := futuresTestContext([]float64{0.0001, 0.0003}, []float64{100, 110, 99})
+
+	// 第一次结算之前没有数据
+	snapshot := fc.At(futuresTestStart.Add(-time.Millisecond))
+	assert.False(t, snapshot.HasFunding)
+	assert.False(t, snapshot.HasOpenInterest)
+
+	// 第二次结算（8:00）之前只能看到第一次
+	snapshot = fc.At(futuresTestStart.Add(8*time.Hour - time.Millisecond))
+	assert.True(t, snapshot.HasFunding)
+	assert.InDelta(t, 0.0001, snapshot.FundingRate, 1e-12)
+	assert.Equal(t, futuresTestStart, snapshot.FundingTime)
+	assert.InDelta(t, 99, snapshot.OpenInterest, 1e-9)
+	assert.InDelta(t, 99*40000, snapshot.OpenInterestValue, 1e-6)
+	assert.True(t, snapshot.HasOpenInterestChange)
+	assert.InDelta(t, 99.0/110-1, snapshot.OpenInterestChange, 1e-12)
+
+	snapshot = fc.At(futuresTestStart.Add(8 * time.Hour))
+	assert.InDelta(t, 0.0003, snapshot.FundingRate, 1e-12)
+
+	// 只有一条持仓量时没有变化比例
+	snapshot = fc.At(futuresTestStart.Add(30 * time.Minute))
+	assert.True(t, snapshot.HasOpenInterest)
+	assert.False(t, snapshot.HasOpenInterestChange)
+}
+
+func TestFuturesContext_Replace(t *testing.T) {
+	fc := futuresTestContext([]float64{0.0001}, nil)
+	fc.Replace(nil, nil)
+
+	funding, openInterest := fc.Counts()
+	assert.Zero(t, funding)
+	assert.Zero(t, openInterest)
+	assert.False(t, fc.At(futuresTestStart.Add(time.Hour)).HasFunding)
+}
+
+func TestFundingFilter_Confirm(t *testing.T) {
+	filter := NewFundingFilter(0.0002)
+
+	// 未注入数据时不确认
+	confirmed, reason := filter.Confirm(futuresTestStart)
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "futures-sync")
+
+	filter.SetContext(futuresTestContext([]float64{0.0001, 0.0005}, nil))
+
+	confirmed, _ = filter.Confirm(futuresTestStart.Add(4 * time.Hour))
+	assert.True(t, confirmed)
+
+	confirmed, reason = filter.Confirm(futuresTestStart.Add(9 * time.Hour))
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "above")
+
+	// 最近一次结算超过 FundingStaleAfter 视为缺失
+	confirmed, reason = filter.Confirm(futuresTestStart.Add(8*time.Hour + FundingStaleAfter + time.Minute))
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "no funding rate")
+}
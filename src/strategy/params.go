@@ -1,22 +1,43 @@
 package strategy
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 )
 
 // BollingerBandsParams 布林道策略参数
 type BollingerBandsParams struct {
-	Period              int     // 计算周期，默认20
-	Multiplier          float64 // 标准差倍数，默认2.0
-	PositionSizePercent float64 // 仓位比例，默认0.95
-	MinTradeAmount      float64 // 最小交易额，默认10
-	StopLossPercent     float64 // 止损比例，默认1.0 (100%，即不止损)
-	TakeProfitPercent   float64 // 基础止盈比例，默认0.2 (20%)
-	CooldownBars        int     // 冷却期K线数，默认1
+	Period              int     `json:"period"`                // 计算周期，默认20
+	Multiplier          float64 `json:"multiplier"`            // 标准差倍数，默认2.0
+	PositionSizePercent float64 `json:"position_size_percent"` // 仓位比例，默认0.95
+	MinTradeAmount      float64 `json:"min_trade_amount"`      // 最小交易额，默认10
+	StopLossPercent     float64 `json:"stop_loss_percent"`     // 止损比例，默认1.0 (100%，即不止损)
+	TakeProfitPercent   float64 `json:"take_profit_percent"`   // 基础止盈比例，默认0.2 (20%)
+	CooldownBars        int     `json:"cooldown_bars"`         // 冷却期K线数，默认1
 
 	// 卖出策略参数
-	SellStrategyName   string             // 卖出策略名称，默认"moderate"
-	SellStrategyParams map[string]float64 // 卖出策略用户参数，用于覆盖默认配置
+	SellStrategyName   string             `json:"sell_strategy_name"`             // 卖出策略名称，默认"moderate"
+	SellStrategyParams map[string]float64 `json:"sell_strategy_params,omitempty"` // 卖出策略用户参数，用于覆盖默认配置
+	TakeProfitLadder   bool               `json:"take_profit_ladder,omitempty"`   // 开仓后立即挂出分批止盈阶梯（需要分批止盈卖出策略）
+	TrailingStop       float64            `json:"trailing_stop,omitempty"`        // 开仓后挂出移动止损单的回撤比例，0 表示不使用
+	OCO                bool               `json:"oco,omitempty"`                  // 开仓后按 TakeProfitPercent/StopLossPercent 挂出 OCO 止盈止损单
+
+	// ATR 止盈止损：以开仓价 ± N×ATR 代替固定比例（ATR 数据不足时仍用固定比例）
+	ATRPeriod             int     `json:"atr_period,omitempty"`               // ATR 周期
+	ATRStopMultiple       float64 `json:"atr_stop_multiple,omitempty"`        // 止损距离的 ATR 倍数，0 表示使用 StopLossPercent
+	ATRTakeProfitMultiple float64 `json:"atr_take_profit_multiple,omitempty"` // 止盈距离的 ATR 倍数，0 表示使用 TakeProfitPercent
+
+	// 波动率过滤：带宽 (上轨-下轨)/中轨 过窄（挤压）或过宽（暴涨暴跌）时不开仓
+	MinBandWidth float64 `json:"min_band_width,omitempty"` // 带宽低于该值时不开仓，0 表示不限制
+	MaxBandWidth float64 `json:"max_band_width,omitempty"` // 带宽高于该值时不开仓，0 表示不限制
+
+	// 成交量确认：当前成交量超过前 VolumePeriod 根K线平均成交量的 VolumeMultiplier 倍时才开仓
+	VolumePeriod     int     `json:"volume_period,omitempty"`     // 平均成交量周期
+	VolumeMultiplier float64 `json:"volume_multiplier,omitempty"` // 成交量倍数，0 表示不过滤
+
+	// 资金费率过滤：同名永续合约最近一次资金费率高于该值（多头拥挤）时不开仓，0 表示不过滤（需先运行 futures-sync）
+	MaxFundingRate float64 `json:"max_funding_rate,omitempty"`
 }
 
 // GetDefaultBollingerBandsParams 获取默认的布林道策略参数
@@ -52,5 +73,71 @@ func (p *BollingerBandsParams) Validate() error {
 	if p.CooldownBars < 0 {
 		return fmt.Errorf("cooldown_bars must be non-negative, got %d", p.CooldownBars)
 	}
+	if p.TrailingStop < 0 || p.TrailingStop >= 1 {
+		return fmt.Errorf("trailing_stop must be in [0, 1), got %f", p.TrailingStop)
+	}
+	if p.ATRStopMultiple < 0 || p.ATRTakeProfitMultiple < 0 {
+		return fmt.Errorf("atr multiples must be non-negative, got stop=%f take_profit=%f", p.ATRStopMultiple, p.ATRTakeProfitMultiple)
+	}
+	if (p.ATRStopMultiple > 0 || p.ATRTakeProfitMultiple > 0) && p.ATRPeriod <= 0 {
+		return fmt.Errorf("atr stops require atr_period > 0, got %d", p.ATRPeriod)
+	}
+	if p.MinBandWidth < 0 || p.MaxBandWidth < 0 {
+		return fmt.Errorf("band width limits must be non-negative, got min=%f max=%f", p.MinBandWidth, p.MaxBandWidth)
+	}
+	if p.MinBandWidth > 0 && p.MaxBandWidth > 0 && p.MinBandWidth >= p.MaxBandWidth {
+		return fmt.Errorf("min_band_width must be less than max_band_width, got min=%f max=%f", p.MinBandWidth, p.MaxBandWidth)
+	}
+	if p.VolumeMultiplier < 0 {
+		return fmt.Errorf("volume_multiplier must be non-negative, got %f", p.VolumeMultiplier)
+	}
+	if p.VolumeMultiplier > 0 && p.VolumePeriod <= 0 {
+		return fmt.Errorf("volume filter requires volume_period > 0, got %d", p.VolumePeriod)
+	}
+	if p.MaxFundingRate <= -0.01 || p.MaxFundingRate >= 0.01 {
+		return fmt.Errorf("max_funding_rate is a per-settlement rate and must be within (-0.01, 0.01), e.g. 0.0005 = 0.05%%, got %f", p.MaxFundingRate)
+	}
+	if p.OCO {
+		if p.TakeProfitPercent <= 0 {
+			return fmt.Errorf("oco requires take_profit_percent > 0, got %f", p.TakeProfitPercent)
+		}
+		if p.StopLossPercent <= 0 || p.StopLossPercent >= 1 {
+			return fmt.Errorf("oco requires stop_loss_percent between 0 and 1, got %f", p.StopLossPercent)
+		}
+		if p.TakeProfitLadder || p.TrailingStop > 0 {
+			return fmt.Errorf("oco cannot be combined with take_profit_ladder or trailing_stop")
+		}
+	}
+	if p.TakeProfitLadder {
+		sellStrategy, err := CreateSellStrategyWithParams(p.SellStrategyName, p.SellStrategyParams)
+		if err != nil {
+			return fmt.Errorf("invalid sell strategy for take_profit_ladder: %w", err)
+		}
+		if _, ok := sellStrategy.(LadderSellStrategy); !ok {
+			return fmt.Errorf("take_profit_ladder requires a partial sell strategy (e.g. partial_pyramid), got %s", p.SellStrategyName)
+		}
+	}
 	return nil
 }
+
+// LoadBollingerBandsParamsFile 从JSON参数文件加载策略参数，文件中未出现的字段沿用 base
+func LoadBollingerBandsParamsFile(path string, base *BollingerBandsParams) (*BollingerBandsParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read params file: %w", err)
+	}
+
+	params := *base
+	if base.SellStrategyParams != nil {
+		params.SellStrategyParams = make(map[string]float64, len(base.SellStrategyParams))
+		for k, v := range base.SellStrategyParams {
+			params.SellStrategyParams[k] = v
+		}
+	}
+
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse params file %s: %w", path, err)
+	}
+
+	return &params, nil
+}
@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+
+	"tradingbot/src/script"
+)
+
+// ScriptVars 脚本策略内置变量（用户变量不能与之重名）
+var ScriptVars = []string{
+	"position",    // 持仓数量
+	"cash",        // 现金余额
+	"entry_price", // 最近一次买入信号时的收盘价，无持仓时为 0
+	"pnl",         // 相对 entry_price 的浮动收益率（如 0.05 表示 5%），无持仓时为 0
+	"bars_held",   // 买入信号后经过的K线数，无持仓时为 0
+	"bar",         // 已处理的K线数
+}
+
+// ScriptParams 脚本策略参数：买卖条件写成表达式，改参数文件即可试验信号，不需要重新编译
+type ScriptParams struct {
+	Buy     string             `json:"buy"`     // 买入条件（无持仓时判断），如 "rsi(close, 14) < 30"
+	Sell    string             `json:"sell"`    // 卖出条件（有持仓时判断），如 "rsi(close, 14) > 70 || pnl < -0.05"
+	Vars    map[string]float64 `json:"vars"`    // 用户变量，可在表达式中按名称引用，如 {"oversold": 30}
+	History int                `json:"history"` // 保留的K线数（指标最长周期的 4 倍以上可使 ema/rsi/atr 收敛）
+}
+
+// GetDefaultScriptParams 获取默认的脚本策略参数（与布林道策略默认的入场、出场条件相同）
+func GetDefaultScriptParams() *ScriptParams {
+	return &ScriptParams{
+		Buy:     "close < bb_lower(close, period, multiplier)",
+		Sell:    "close > bb_upper(close, period, multiplier)",
+		Vars:    map[string]float64{"period": 20, "multiplier": 2},
+		History: 200,
+	}
+}
+
+// Validate 验证参数有效性（编译买卖条件）
+func (p *ScriptParams) Validate() error {
+	if p.History <= 0 {
+		return fmt.Errorf("history must be positive, got %d", p.History)
+	}
+	_, _, err := p.Compile()
+	return err
+}
+
+// Compile 编译买卖条件，表达式中可使用 K线序列、ScriptVars 和 Vars
+func (p *ScriptParams) Compile() (buy, sell *script.Program, err error) {
+	if p.Buy == "" || p.Sell == "" {
+		return nil, nil, fmt.Errorf("both buy and sell expressions are required")
+	}
+
+	vars := append([]string(nil), ScriptVars...)
+	for _, builtin := range ScriptVars {
+		if _, exists := p.Vars[builtin]; exists {
+			return nil, nil, fmt.Errorf("var %s conflicts with a built-in variable", builtin)
+		}
+	}
+	for name := range p.Vars {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+
+	if buy, err = script.Compile(p.Buy, vars); err != nil {
+		return nil, nil, fmt.Errorf("buy: %w", err)
+	}
+	if sell, err = script.Compile(p.Sell, vars); err != nil {
+		return nil, nil, fmt.Errorf("sell: %w", err)
+	}
+	return buy, sell, nil
+}
@@ -1,8 +1,12 @@
 package strategy
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 	"tradingbot/src/cex"
+	"tradingbot/src/indicators"
 
 	"github.com/shopspring/decimal"
 )
@@ -36,6 +40,10 @@ func (s *FixedSellStrategy) GetName() string {
 
 func (s *FixedSellStrategy) Reset() {}
 
+func (s *FixedSellStrategy) Save() ([]byte, error) { return nil, nil }
+
+func (s *FixedSellStrategy) Load(data []byte) error { return nil }
+
 // TrailingSellStrategy 移动止盈策略
 type TrailingSellStrategy struct {
 	TrailingPercent      float64
@@ -82,6 +90,10 @@ func (s *TrailingSellStrategy) GetName() string {
 
 func (s *TrailingSellStrategy) Reset() {}
 
+func (s *TrailingSellStrategy) Save() ([]byte, error) { return nil, nil }
+
+func (s *TrailingSellStrategy) Load(data []byte) error { return nil }
+
 // TechnicalSellStrategy 技术指标止盈策略
 type TechnicalSellStrategy struct {
 	MinProfitForTechnical float64
@@ -121,22 +133,327 @@ func (s *TechnicalSellStrategy) GetName() string {
 
 func (s *TechnicalSellStrategy) Reset() {}
 
-// ComboSellStrategy 组合止盈策略
+func (s *TechnicalSellStrategy) Save() ([]byte, error) { return nil, nil }
+
+func (s *TechnicalSellStrategy) Load(data []byte) error { return nil }
+
+// defaultStopLossATRPeriod 未配置 atr_period 时的 ATR 周期
+const defaultStopLossATRPeriod = 14
+
+// StopLossSellStrategy 止损策略：固定比例止损，或按开仓时 ATR 止损（止损价 = 开仓价 - N×ATR）
+type StopLossSellStrategy struct {
+	StopLossPercent float64 // 固定止损比例，配置 ATR 时作为 ATR 数据不足的兜底，0 表示不使用
+	ATRMultiple     float64 // ATR 止损倍数，0 表示只用固定比例
+	ATRPeriod       int
+
+	highs, lows, closes []decimal.Decimal // 最近K线，用于计算 ATR
+	entryATR            decimal.Decimal   // 本次持仓开仓时的 ATR（持仓后首次判断时锁定）
+	entryLocked         bool
+}
+
+func NewStopLossSellStrategy(stopLossPercent, atrMultiple float64, atrPeriod int) *StopLossSellStrategy {
+	if atrMultiple > 0 && atrPeriod <= 0 {
+		atrPeriod = defaultStopLossATRPeriod
+	}
+	return &StopLossSellStrategy{
+		StopLossPercent: stopLossPercent,
+		ATRMultiple:     atrMultiple,
+		ATRPeriod:       atrPeriod,
+	}
+}
+
+// Observe 记录K线用于计算 ATR（未配置 ATR 时忽略）
+func (s *StopLossSellStrategy) Observe(kline *cex.KlineData) {
+	if s.ATRMultiple <= 0 {
+		return
+	}
+	s.highs = append(s.highs, kline.High)
+	s.lows = append(s.lows, kline.Low)
+	s.closes = append(s.closes, kline.Close)
+
+	// 保留 3 倍周期以便 Wilder 平滑收敛
+	if maxHistory := 3*s.ATRPeriod + 1; len(s.closes) > maxHistory {
+		s.highs = s.highs[1:]
+		s.lows = s.lows[1:]
+		s.closes = s.closes[1:]
+	}
+}
+
+func (s *StopLossSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInfo) *SellSignal {
+	if !s.entryLocked {
+		s.entryLocked = true
+		if s.ATRMultiple > 0 {
+			if atr, err := indicators.NewATR(s.ATRPeriod).Calculate(s.highs, s.lows, s.closes); err == nil {
+				s.entryATR = atr
+			}
+		}
+	}
+
+	// ATR 止损
+	if s.ATRMultiple > 0 && s.entryATR.IsPositive() {
+		stopPrice := tradeInfo.EntryPrice.Sub(s.entryATR.Mul(decimal.NewFromFloat(s.ATRMultiple)))
+		if tradeInfo.CurrentPrice.LessThanOrEqual(stopPrice) {
+			pnl, _ := tradeInfo.CurrentPnL.Float64()
+			return &SellSignal{
+				ShouldSell: true,
+				Reason:     fmt.Sprintf("ATR stop loss: %.2f%% (%.1f×ATR, stop %s)", pnl*100, s.ATRMultiple, stopPrice.String()),
+				Strength:   1.0,
+			}
+		}
+		return &SellSignal{ShouldSell: false}
+	}
+
+	// 固定比例止损（或 ATR 数据不足时兜底）
+	if s.StopLossPercent > 0 && tradeInfo.CurrentPnL.LessThanOrEqual(decimal.NewFromFloat(-s.StopLossPercent)) {
+		pnl, _ := tradeInfo.CurrentPnL.Float64()
+		return &SellSignal{
+			ShouldSell: true,
+			Reason:     fmt.Sprintf("stop loss: %.2f%% (limit %.1f%%)", pnl*100, s.StopLossPercent*100),
+			Strength:   1.0,
+		}
+	}
+
+	return &SellSignal{ShouldSell: false}
+}
+
+func (s *StopLossSellStrategy) GetName() string {
+	if s.ATRMultiple > 0 {
+		if s.StopLossPercent > 0 {
+			return fmt.Sprintf("StopLoss(%.1f×ATR%d, fallback %.1f%%)", s.ATRMultiple, s.ATRPeriod, s.StopLossPercent*100)
+		}
+		return fmt.Sprintf("StopLoss(%.1f×ATR%d)", s.ATRMultiple, s.ATRPeriod)
+	}
+	return fmt.Sprintf("StopLoss(%.1f%%)", s.StopLossPercent*100)
+}
+
+// Reset 平仓后解锁开仓 ATR（K线历史保留，下次开仓可直接使用）
+func (s *StopLossSellStrategy) Reset() {
+	s.entryATR = decimal.Zero
+	s.entryLocked = false
+}
+
+// stopLossState 止损策略的持久化状态
+type stopLossState struct {
+	Highs       []decimal.Decimal `json:"highs,omitempty"`
+	Lows        []decimal.Decimal `json:"lows,omitempty"`
+	Closes      []decimal.Decimal `json:"closes,omitempty"`
+	EntryATR    decimal.Decimal   `json:"entry_atr"`
+	EntryLocked bool              `json:"entry_locked"`
+}
+
+// Save 保存K线历史和已锁定的开仓 ATR
+func (s *StopLossSellStrategy) Save() ([]byte, error) {
+	return json.Marshal(stopLossState{Highs: s.highs, Lows: s.lows, Closes: s.closes, EntryATR: s.entryATR, EntryLocked: s.entryLocked})
+}
+
+func (s *StopLossSellStrategy) Load(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var state stopLossState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode stop loss state: %w", err)
+	}
+	if len(state.Highs) != len(state.Closes) || len(state.Lows) != len(state.Closes) {
+		return fmt.Errorf("invalid stop loss state: %d highs, %d lows, %d closes", len(state.Highs), len(state.Lows), len(state.Closes))
+	}
+	s.highs, s.lows, s.closes = state.Highs, state.Lows, state.Closes
+	s.entryATR, s.entryLocked = state.EntryATR, state.EntryLocked
+	return nil
+}
+
+// TimeExitSellStrategy 按时间平仓：持仓超过 N 根K线或 N 小时，或到达指定时刻（如周五收盘前）平仓，避免持仓穿过流动性差的时段
+type TimeExitSellStrategy struct {
+	TimeExitConfig
+}
+
+func NewTimeExitSellStrategy(config TimeExitConfig) *TimeExitSellStrategy {
+	return &TimeExitSellStrategy{TimeExitConfig: config}
+}
+
+func (s *TimeExitSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInfo) *SellSignal {
+	if s.MaxBars > 0 && tradeInfo.HoldingBars >= s.MaxBars {
+		return &SellSignal{
+			ShouldSell: true,
+			Reason:     fmt.Sprintf("time exit: held %d bars (max %d)", tradeInfo.HoldingBars, s.MaxBars),
+			Strength:   1.0,
+		}
+	}
+
+	// 持仓时长和指定时刻需要开仓时间，按K线收盘时间判断
+	now := kline.CloseTime
+	if now.IsZero() {
+		now = kline.OpenTime
+	}
+	if tradeInfo.EntryTime.IsZero() || now.IsZero() {
+		return &SellSignal{ShouldSell: false}
+	}
+
+	if held := now.Sub(tradeInfo.EntryTime).Hours(); s.MaxHours > 0 && held >= s.MaxHours {
+		return &SellSignal{
+			ShouldSell: true,
+			Reason:     fmt.Sprintf("time exit: held %.1f hours (max %.1f)", held, s.MaxHours),
+			Strength:   1.0,
+		}
+	}
+
+	if s.ExitHour >= 0 && !now.Before(s.nextExitTime(tradeInfo.EntryTime)) {
+		return &SellSignal{
+			ShouldSell: true,
+			Reason:     fmt.Sprintf("time exit: scheduled exit at %s", s.exitTimeLabel()),
+			Strength:   1.0,
+		}
+	}
+
+	return &SellSignal{ShouldSell: false}
+}
+
+// nextExitTime 开仓后第一个平仓时刻（UTC）
+func (s *TimeExitSellStrategy) nextExitTime(entry time.Time) time.Time {
+	entry = entry.UTC()
+	exitAt := time.Date(entry.Year(), entry.Month(), entry.Day(), 0, 0, 0, 0, time.UTC).
+		Add(time.Duration(s.ExitHour * float64(time.Hour)))
+	for !exitAt.After(entry) || (s.ExitWeekday >= 0 && exitAt.Weekday() != time.Weekday(s.ExitWeekday)) {
+		exitAt = exitAt.AddDate(0, 0, 1)
+	}
+	return exitAt
+}
+
+// exitTimeLabel 平仓时刻，如 "Fri 20:00 UTC"
+func (s *TimeExitSellStrategy) exitTimeLabel() string {
+	minutes := int(s.ExitHour * 60)
+	label := fmt.Sprintf("%02d:%02d UTC", minutes/60, minutes%60)
+	if s.ExitWeekday >= 0 {
+		label = time.Weekday(s.ExitWeekday).String()[:3] + " " + label
+	}
+	return label
+}
+
+func (s *TimeExitSellStrategy) GetName() string {
+	var parts []string
+	if s.MaxBars > 0 {
+		parts = append(parts, fmt.Sprintf("%d bars", s.MaxBars))
+	}
+	if s.MaxHours > 0 {
+		parts = append(parts, fmt.Sprintf("%.1fh", s.MaxHours))
+	}
+	if s.ExitHour >= 0 {
+		parts = append(parts, s.exitTimeLabel())
+	}
+	return fmt.Sprintf("TimeExit(%s)", strings.Join(parts, ", "))
+}
+
+func (s *TimeExitSellStrategy) Reset() {}
+
+func (s *TimeExitSellStrategy) Save() ([]byte, error) { return nil, nil }
+
+func (s *TimeExitSellStrategy) Load(data []byte) error { return nil }
+
+// BreakEvenSellStrategy 保本止损：持仓期间最高盈利达到 TriggerPercent 后，价格回落到保本价（开仓价加买卖手续费）时卖出
+type BreakEvenSellStrategy struct {
+	TriggerPercent float64 // 激活保本止损的盈利比例
+	FeeRate        float64 // 单边手续费率
+}
+
+func NewBreakEvenSellStrategy(triggerPercent, feeRate float64) *BreakEvenSellStrategy {
+	return &BreakEvenSellStrategy{
+		TriggerPercent: triggerPercent,
+		FeeRate:        feeRate,
+	}
+}
+
+// BreakEvenPrice 保本价：按该价卖出后扣除买卖手续费不亏损，entry × (1 + fee) / (1 - fee)
+func (s *BreakEvenSellStrategy) BreakEvenPrice(entryPrice decimal.Decimal) decimal.Decimal {
+	fee := decimal.NewFromFloat(s.FeeRate)
+	one := decimal.NewFromInt(1)
+	return entryPrice.Mul(one.Add(fee)).Div(one.Sub(fee))
+}
+
+// Activated 持仓期间最高价（或当前价）是否达到激活盈利
+func (s *BreakEvenSellStrategy) Activated(tradeInfo *TradeInfo) bool {
+	if tradeInfo.EntryPrice.IsZero() {
+		return false
+	}
+	highest := decimal.Max(tradeInfo.HighestPrice, tradeInfo.CurrentPrice)
+	peakPnL := highest.Sub(tradeInfo.EntryPrice).Div(tradeInfo.EntryPrice)
+	return peakPnL.GreaterThanOrEqual(decimal.NewFromFloat(s.TriggerPercent))
+}
+
+func (s *BreakEvenSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInfo) *SellSignal {
+	if !s.Activated(tradeInfo) {
+		return &SellSignal{ShouldSell: false}
+	}
+
+	level := s.BreakEvenPrice(tradeInfo.EntryPrice)
+	if tradeInfo.CurrentPrice.LessThanOrEqual(level) {
+		return &SellSignal{
+			ShouldSell: true,
+			Reason:     fmt.Sprintf("break-even stop: price %s <= %s (activated at +%.1f%%)", tradeInfo.CurrentPrice.String(), level.String(), s.TriggerPercent*100),
+			Strength:   1.0,
+		}
+	}
+
+	return &SellSignal{ShouldSell: false}
+}
+
+func (s *BreakEvenSellStrategy) GetName() string {
+	return fmt.Sprintf("BreakEven(after %.1f%%, fee %.2f%%)", s.TriggerPercent*100, s.FeeRate*100)
+}
+
+func (s *BreakEvenSellStrategy) Reset() {}
+
+func (s *BreakEvenSellStrategy) Save() ([]byte, error) { return nil, nil }
+
+func (s *BreakEvenSellStrategy) Load(data []byte) error { return nil }
+
+// ComboSellStrategy 组合止盈策略，配置止损时先检查止损
 type ComboSellStrategy struct {
-	FixedStrategy    *FixedSellStrategy
-	TrailingStrategy *TrailingSellStrategy
-	MaxHoldingDays   int
+	FixedStrategy     *FixedSellStrategy
+	TrailingStrategy  *TrailingSellStrategy
+	StopLossStrategy  *StopLossSellStrategy  // 未配置止损时为 nil
+	TimeExitStrategy  *TimeExitSellStrategy  // 未配置按时间平仓时为 nil
+	BreakEvenStrategy *BreakEvenSellStrategy // 未配置保本止损时为 nil
+	MaxHoldingDays    int
 }
 
 func NewComboSellStrategy(config *SellStrategyConfig) *ComboSellStrategy {
-	return &ComboSellStrategy{
+	combo := &ComboSellStrategy{
 		FixedStrategy:    NewFixedSellStrategy(config.FixedTakeProfit),
 		TrailingStrategy: NewTrailingSellStrategy(config.TrailingPercent, config.MinProfitForTrailing),
 		MaxHoldingDays:   config.MaxHoldingDays,
 	}
+	if config.hasStopLoss() {
+		combo.StopLossStrategy = NewStopLossSellStrategy(config.StopLossPercent, config.ATRStopMultiple, config.ATRPeriod)
+	}
+	if config.BreakEvenTrigger > 0 {
+		combo.BreakEvenStrategy = NewBreakEvenSellStrategy(config.BreakEvenTrigger, config.BreakEvenFee)
+	}
+	if config.TimeExit != nil {
+		combo.TimeExitStrategy = NewTimeExitSellStrategy(*config.TimeExit)
+	}
+	return combo
+}
+
+// Observe 转发K线给止损策略
+func (s *ComboSellStrategy) Observe(kline *cex.KlineData) {
+	if s.StopLossStrategy != nil {
+		s.StopLossStrategy.Observe(kline)
+	}
 }
 
 func (s *ComboSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInfo) *SellSignal {
+	// 0. 检查止损
+	if s.StopLossStrategy != nil {
+		if stopSignal := s.StopLossStrategy.ShouldSell(kline, tradeInfo); stopSignal.ShouldSell {
+			return stopSignal
+		}
+	}
+	if s.BreakEvenStrategy != nil {
+		if breakEvenSignal := s.BreakEvenStrategy.ShouldSell(kline, tradeInfo); breakEvenSignal.ShouldSell {
+			return breakEvenSignal
+		}
+	}
+
 	// 1. 检查最大持仓时间
 	if s.MaxHoldingDays > 0 && tradeInfo.HoldingDays >= s.MaxHoldingDays {
 		return &SellSignal{
@@ -145,6 +462,11 @@ func (s *ComboSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInf
 			Strength:   1.0,
 		}
 	}
+	if s.TimeExitStrategy != nil {
+		if timeSignal := s.TimeExitStrategy.ShouldSell(kline, tradeInfo); timeSignal.ShouldSell {
+			return timeSignal
+		}
+	}
 
 	// 2. 检查移动止盈
 	trailingSignal := s.TrailingStrategy.ShouldSell(kline, tradeInfo)
@@ -165,12 +487,41 @@ func (s *ComboSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeInf
 }
 
 func (s *ComboSellStrategy) GetName() string {
-	return fmt.Sprintf("Combo(%s + %s)", s.TrailingStrategy.GetName(), s.FixedStrategy.GetName())
+	var names []string
+	if s.StopLossStrategy != nil {
+		names = append(names, s.StopLossStrategy.GetName())
+	}
+	if s.BreakEvenStrategy != nil {
+		names = append(names, s.BreakEvenStrategy.GetName())
+	}
+	if s.TimeExitStrategy != nil {
+		names = append(names, s.TimeExitStrategy.GetName())
+	}
+	names = append(names, s.TrailingStrategy.GetName(), s.FixedStrategy.GetName())
+	return fmt.Sprintf("Combo(%s)", strings.Join(names, " + "))
 }
 
 func (s *ComboSellStrategy) Reset() {
 	s.FixedStrategy.Reset()
 	s.TrailingStrategy.Reset()
+	if s.StopLossStrategy != nil {
+		s.StopLossStrategy.Reset()
+	}
+}
+
+// Save 保存止损策略的状态（其余子策略无状态）
+func (s *ComboSellStrategy) Save() ([]byte, error) {
+	if s.StopLossStrategy == nil {
+		return nil, nil
+	}
+	return s.StopLossStrategy.Save()
+}
+
+func (s *ComboSellStrategy) Load(data []byte) error {
+	if s.StopLossStrategy == nil {
+		return nil
+	}
+	return s.StopLossStrategy.Load(data)
 }
 
 // PartialSellStrategy 分批止盈策略
@@ -209,6 +560,11 @@ func (s *PartialSellStrategy) ShouldSell(kline *cex.KlineData, tradeInfo *TradeI
 	return &SellSignal{ShouldSell: false}
 }
 
+// GetLadderLevels 获取止盈阶梯
+func (s *PartialSellStrategy) GetLadderLevels() []PartialLevel {
+	return s.Levels
+}
+
 func (s *PartialSellStrategy) GetName() string {
 	return fmt.Sprintf("Partial(%d levels)", len(s.Levels))
 }
@@ -216,3 +572,28 @@ func (s *PartialSellStrategy) GetName() string {
 func (s *PartialSellStrategy) Reset() {
 	s.ExecutedLevel = -1
 }
+
+// partialState 分批止盈的持久化状态
+type partialState struct {
+	ExecutedLevel int `json:"executed_level"`
+}
+
+// Save 保存已执行的级别，重启后不会重复卖出
+func (s *PartialSellStrategy) Save() ([]byte, error) {
+	return json.Marshal(partialState{ExecutedLevel: s.ExecutedLevel})
+}
+
+func (s *PartialSellStrategy) Load(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var state partialState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode partial sell state: %w", err)
+	}
+	if state.ExecutedLevel < -1 || state.ExecutedLevel >= len(s.Levels) {
+		return fmt.Errorf("invalid partial sell state: executed level %d with %d levels", state.ExecutedLevel, len(s.Levels))
+	}
+	s.ExecutedLevel = state.ExecutedLevel
+	return nil
+}
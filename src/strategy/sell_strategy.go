@@ -28,27 +28,50 @@ type SellStrategy interface {
 
 	// Reset 重置策略状态
 	Reset()
+
+	// Save 序列化持仓期间的状态（如已执行的分批止盈级别），无状态时返回 nil
+	Save() ([]byte, error)
+
+	// Load 恢复 Save 保存的状态，data 为空时不做处理
+	Load(data []byte) error
+}
+
+// LadderSellStrategy 可预先挂出全部止盈阶梯的卖出策略
+type LadderSellStrategy interface {
+	SellStrategy
+
+	// GetLadderLevels 获取止盈阶梯（按盈利比例递增）
+	GetLadderLevels() []PartialLevel
+}
+
+// KlineObserver 需要持仓前行情的卖出策略（如 ATR 止损），买入策略每根K线调用 Observe
+type KlineObserver interface {
+	Observe(kline *cex.KlineData)
 }
 
 // TradeInfo 交易信息
 type TradeInfo struct {
 	EntryPrice   decimal.Decimal // 开仓价格
 	EntryTime    time.Time       // 开仓时间
-	HighestPrice decimal.Decimal // 持仓期间最高价格
+	HighestPrice decimal.Decimal // 持仓期间最高价格（每根K线收盘后更新，含冷却期）
 	CurrentPrice decimal.Decimal // 当前价格
 	CurrentPnL   decimal.Decimal // 当前盈亏百分比
 	HoldingDays  int             // 持仓天数
+	HoldingBars  int             // 持仓K线数
 }
 
 // SellStrategyType 卖出策略类型
 type SellStrategyType string
 
 const (
-	SellStrategyFixed     SellStrategyType = "fixed"     // 固定止盈
-	SellStrategyTrailing  SellStrategyType = "trailing"  // 移动止盈
-	SellStrategyTechnical SellStrategyType = "technical" // 技术指标
-	SellStrategyCombo     SellStrategyType = "combo"     // 组合策略
-	SellStrategyPartial   SellStrategyType = "partial"   // 分批止盈
+	SellStrategyFixed     SellStrategyType = "fixed"      // 固定止盈
+	SellStrategyTrailing  SellStrategyType = "trailing"   // 移动止盈
+	SellStrategyTechnical SellStrategyType = "technical"  // 技术指标
+	SellStrategyCombo     SellStrategyType = "combo"      // 组合策略
+	SellStrategyPartial   SellStrategyType = "partial"    // 分批止盈
+	SellStrategyStopLoss  SellStrategyType = "stop_loss"  // 止损
+	SellStrategyTimeExit  SellStrategyType = "time_exit"  // 按持仓时长或时间点平仓
+	SellStrategyBreakEven SellStrategyType = "break_even" // 保本止损
 )
 
 // PartialLevel 分批止盈配置
@@ -65,10 +88,77 @@ type SellStrategyConfig struct {
 	MinProfitForTrailing float64          `json:"min_profit_for_trailing"` // 启用移动止盈的最小盈利
 	MaxHoldingDays       int              `json:"max_holding_days"`        // 最大持仓天数
 	PartialLevels        []PartialLevel   `json:"partial_levels"`          // 分批止盈配置
+	StopLossPercent      float64          `json:"stop_loss_percent"`       // 固定止损比例（ATR 数据不足时也作为兜底）
+	ATRStopMultiple      float64          `json:"atr_stop_multiple"`       // ATR 止损倍数：止损价 = 开仓价 - N×ATR
+	ATRPeriod            int              `json:"atr_period"`              // ATR 周期
+	TimeExit             *TimeExitConfig  `json:"time_exit,omitempty"`     // 按时间平仓，nil 表示不使用
+	BreakEvenTrigger     float64          `json:"break_even_trigger"`      // 盈利达到该比例后把止损移到保本价，0 表示不使用
+	BreakEvenFee         float64          `json:"break_even_fee"`          // 计算保本价的单边手续费率
+}
+
+// TimeExitConfig 按时间平仓配置（时间点按 UTC）
+type TimeExitConfig struct {
+	MaxBars     int     `json:"max_bars"`     // 最多持仓K线数，0 表示不限制
+	MaxHours    float64 `json:"max_hours"`    // 最多持仓小时数，0 表示不限制
+	ExitHour    float64 `json:"exit_hour"`    // 到达该时刻平仓（如 20.5 表示 20:30），-1 表示不使用
+	ExitWeekday int     `json:"exit_weekday"` // 只在星期几的 ExitHour 平仓（0=周日，5=周五），-1 表示每天
+}
+
+// NewTimeExitConfig 创建未启用任何条件的按时间平仓配置
+func NewTimeExitConfig() *TimeExitConfig {
+	return &TimeExitConfig{ExitHour: -1, ExitWeekday: -1}
+}
+
+// Validate 验证按时间平仓配置
+func (c *TimeExitConfig) Validate() error {
+	if c.MaxBars < 0 || c.MaxHours < 0 {
+		return fmt.Errorf("max_bars and max_hours must not be negative")
+	}
+	if c.ExitHour >= 24 || (c.ExitHour < 0 && c.ExitHour != -1) {
+		return fmt.Errorf("exit_hour must be within [0, 24), got %g", c.ExitHour)
+	}
+	if c.ExitWeekday < -1 || c.ExitWeekday > 6 {
+		return fmt.Errorf("exit_weekday must be within 0 (Sunday) to 6 (Saturday), got %d", c.ExitWeekday)
+	}
+	if c.ExitWeekday >= 0 && c.ExitHour < 0 {
+		return fmt.Errorf("exit_weekday requires exit_hour")
+	}
+	if c.MaxBars == 0 && c.MaxHours == 0 && c.ExitHour < 0 {
+		return fmt.Errorf("time exit requires max_bars, max_hours or exit_hour")
+	}
+	return nil
+}
+
+// hasStopLoss 是否配置了止损
+func (c *SellStrategyConfig) hasStopLoss() bool {
+	return c.StopLossPercent > 0 || c.ATRStopMultiple > 0
 }
 
 // CreateSellStrategy 创建卖出策略
 func CreateSellStrategy(config *SellStrategyConfig) (SellStrategy, error) {
+	if config.hasStopLoss() && config.Type != SellStrategyCombo && config.Type != SellStrategyStopLoss {
+		return nil, fmt.Errorf("%s sell strategy does not support stop loss, use combo or stop_loss", config.Type)
+	}
+	if config.BreakEvenTrigger > 0 {
+		if config.Type != SellStrategyCombo && config.Type != SellStrategyBreakEven {
+			return nil, fmt.Errorf("%s sell strategy does not support break-even stop, use combo or break_even", config.Type)
+		}
+		if config.BreakEvenFee < 0 || config.BreakEvenFee >= 0.1 {
+			return nil, fmt.Errorf("break_even_fee must be within [0, 0.1), got %g", config.BreakEvenFee)
+		}
+		if config.BreakEvenTrigger <= 2*config.BreakEvenFee {
+			return nil, fmt.Errorf("break_even_trigger %g must exceed the round-trip fee %g", config.BreakEvenTrigger, 2*config.BreakEvenFee)
+		}
+	}
+	if config.TimeExit != nil {
+		if config.Type != SellStrategyCombo && config.Type != SellStrategyTimeExit {
+			return nil, fmt.Errorf("%s sell strategy does not support time exit, use combo or time_exit", config.Type)
+		}
+		if err := config.TimeExit.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid time exit: %w", err)
+		}
+	}
+
 	switch config.Type {
 	case SellStrategyFixed:
 		return NewFixedSellStrategy(config.FixedTakeProfit), nil
@@ -80,6 +170,21 @@ func CreateSellStrategy(config *SellStrategyConfig) (SellStrategy, error) {
 		return NewComboSellStrategy(config), nil
 	case SellStrategyPartial:
 		return NewPartialSellStrategy(config.PartialLevels), nil
+	case SellStrategyStopLoss:
+		if !config.hasStopLoss() {
+			return nil, fmt.Errorf("stop loss sell strategy requires stop_loss or atr_multiple")
+		}
+		return NewStopLossSellStrategy(config.StopLossPercent, config.ATRStopMultiple, config.ATRPeriod), nil
+	case SellStrategyTimeExit:
+		if config.TimeExit == nil {
+			return nil, fmt.Errorf("time exit sell strategy requires max_bars, max_hours or exit_hour")
+		}
+		return NewTimeExitSellStrategy(*config.TimeExit), nil
+	case SellStrategyBreakEven:
+		if config.BreakEvenTrigger <= 0 {
+			return nil, fmt.Errorf("break-even sell strategy requires break_even_trigger > 0")
+		}
+		return NewBreakEvenSellStrategy(config.BreakEvenTrigger, config.BreakEvenFee), nil
 	default:
 		return nil, fmt.Errorf("unknown sell strategy type: %s", config.Type)
 	}
@@ -125,6 +230,20 @@ func GetDefaultSellStrategyConfigs() map[string]*SellStrategyConfig {
 				{ProfitPercent: 0.60, SellPercent: 1.00}, // 60%盈利全卖
 			},
 		},
+		"stop_loss_5": {
+			Type:            SellStrategyStopLoss,
+			StopLossPercent: 0.05, // 亏损5%止损
+		},
+		"atr_stop": {
+			Type:            SellStrategyStopLoss,
+			ATRStopMultiple: 2,    // 开仓价 - 2×ATR 止损
+			ATRPeriod:       14,   // ATR14
+			StopLossPercent: 0.05, // ATR 数据不足时按5%止损
+		},
+		"weekend_flat": {
+			Type:     SellStrategyTimeExit,
+			TimeExit: &TimeExitConfig{ExitHour: 20, ExitWeekday: int(time.Friday)}, // 周五 20:00 UTC 平仓，不持仓过周末
+		},
 	}
 }
 
@@ -170,6 +289,10 @@ func CreateSellStrategyWithParams(strategyName string, userParams map[string]flo
 	if config, exists := defaultConfigs[strategyName]; exists {
 		// 复制配置以避免修改默认值
 		configCopy := *config
+		if config.TimeExit != nil {
+			timeExit := *config.TimeExit
+			configCopy.TimeExit = &timeExit
+		}
 
 		// 根据用户参数覆盖默认值
 		if takeProfit, ok := userParams["take_profit"]; ok {
@@ -181,6 +304,9 @@ func CreateSellStrategyWithParams(strategyName string, userParams map[string]flo
 		if minProfit, ok := userParams["min_profit"]; ok {
 			configCopy.MinProfitForTrailing = minProfit
 		}
+		applyStopLossParams(&configCopy, userParams)
+		applyTimeExitParams(&configCopy, userParams)
+		applyBreakEvenParams(&configCopy, userParams)
 
 		return CreateSellStrategy(&configCopy)
 	}
@@ -232,6 +358,19 @@ func CreateSellStrategyWithParams(strategyName string, userParams map[string]flo
 			config.MaxHoldingDays = int(maxDays)
 		}
 
+	case "time_exit":
+		config.Type = SellStrategyTimeExit
+		// 无默认值，需通过 max_bars、max_hours 或 exit_hour 指定
+
+	case "break_even":
+		config.Type = SellStrategyBreakEven
+		config.BreakEvenTrigger = 0.05 // 默认盈利5%后激活
+		config.BreakEvenFee = 0.001    // 默认单边手续费0.1%
+
+	case "stop_loss":
+		config.Type = SellStrategyStopLoss
+		config.StopLossPercent = 0.05 // 默认亏损5%止损，配置 atr_multiple 后作为 ATR 数据不足时的兜底
+
 	case "partial":
 		config.Type = SellStrategyPartial
 		// 使用默认分批配置
@@ -245,6 +384,56 @@ func CreateSellStrategyWithParams(strategyName string, userParams map[string]flo
 	default:
 		return nil, fmt.Errorf("unknown sell strategy: %s", strategyName)
 	}
+	applyStopLossParams(config, userParams)
+	applyTimeExitParams(config, userParams)
+	applyBreakEvenParams(config, userParams)
 
 	return CreateSellStrategy(config)
 }
+
+// applyStopLossParams 应用止损参数：stop_loss 固定止损比例，atr_multiple/atr_period ATR 止损（组合策略同时止盈止损）
+func applyStopLossParams(config *SellStrategyConfig, userParams map[string]float64) {
+	if stopLoss, ok := userParams["stop_loss"]; ok {
+		config.StopLossPercent = stopLoss
+	}
+	if multiple, ok := userParams["atr_multiple"]; ok {
+		config.ATRStopMultiple = multiple
+	}
+	if period, ok := userParams["atr_period"]; ok {
+		config.ATRPeriod = int(period)
+	}
+	if config.ATRStopMultiple > 0 && config.ATRPeriod <= 0 {
+		config.ATRPeriod = defaultStopLossATRPeriod
+	}
+}
+
+// applyTimeExitParams 应用按时间平仓参数：max_bars、max_hours、exit_hour、exit_weekday（组合策略同时按时间平仓）
+func applyTimeExitParams(config *SellStrategyConfig, userParams map[string]float64) {
+	timeExit := config.TimeExit
+	set := func(key string, apply func(value float64)) {
+		if value, ok := userParams[key]; ok {
+			if timeExit == nil {
+				timeExit = NewTimeExitConfig()
+			}
+			apply(value)
+		}
+	}
+	set("max_bars", func(value float64) { timeExit.MaxBars = int(value) })
+	set("max_hours", func(value float64) { timeExit.MaxHours = value })
+	set("exit_hour", func(value float64) { timeExit.ExitHour = value })
+	set("exit_weekday", func(value float64) { timeExit.ExitWeekday = int(value) })
+	config.TimeExit = timeExit
+}
+
+// applyBreakEvenParams 应用保本止损参数：break_even_trigger 激活盈利比例，break_even_fee 单边手续费率（组合策略同时保本止损）
+func applyBreakEvenParams(config *SellStrategyConfig, userParams map[string]float64) {
+	if trigger, ok := userParams["break_even_trigger"]; ok {
+		config.BreakEvenTrigger = trigger
+		if _, ok := userParams["break_even_fee"]; !ok && config.BreakEvenFee == 0 {
+			config.BreakEvenFee = 0.001
+		}
+	}
+	if fee, ok := userParams["break_even_fee"]; ok {
+		config.BreakEvenFee = fee
+	}
+}
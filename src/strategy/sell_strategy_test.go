@@ -1,6 +1,8 @@
 package strategy
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -244,6 +246,253 @@ func TestPartialSellStrategy_GetName(t *testing.T) {
 }
 
 // Test CreateSellStrategy function
+func TestStopLossSellStrategy_ShouldSell(t *testing.T) {
+	t.Run("fixed percent", func(t *testing.T) {
+		strategy := NewStopLossSellStrategy(0.05, 0, 0)
+		assert.Equal(t, "StopLoss(5.0%)", strategy.GetName())
+
+		assert.False(t, strategy.ShouldSell(createTestKline(48000), createTestTradeInfo(50000, 48000, 1)).ShouldSell)
+
+		signal := strategy.ShouldSell(createTestKline(47000), createTestTradeInfo(50000, 47000, 1))
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "stop loss: -6.00%")
+		assert.Equal(t, 1.0, signal.Strength)
+	})
+
+	t.Run("ATR locked at entry", func(t *testing.T) {
+		strategy := NewStopLossSellStrategy(0.05, 2, 3)
+		assert.Equal(t, "StopLoss(2.0×ATR3, fallback 5.0%)", strategy.GetName())
+		for i := 0; i < 5; i++ {
+			// 每根K线波幅 100，ATR = 100
+			strategy.Observe(&cex.KlineData{High: decimal.NewFromInt(50050), Low: decimal.NewFromInt(49950), Close: decimal.NewFromInt(50000)})
+		}
+
+		// 止损价 = 50000 - 2×100 = 49800
+		assert.False(t, strategy.ShouldSell(createTestKline(49850), createTestTradeInfo(50000, 49850, 1)).ShouldSell)
+
+		// 开仓后波幅扩大不移动止损价
+		strategy.Observe(&cex.KlineData{High: decimal.NewFromInt(51000), Low: decimal.NewFromInt(49000), Close: decimal.NewFromInt(49800)})
+		signal := strategy.ShouldSell(createTestKline(49800), createTestTradeInfo(50000, 49800, 1))
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "ATR stop loss")
+		assert.Contains(t, signal.Reason, "stop 49800")
+	})
+
+	t.Run("ATR falls back to fixed percent without data", func(t *testing.T) {
+		strategy := NewStopLossSellStrategy(0.05, 2, 14)
+		strategy.Observe(createTestKline(50000))
+
+		signal := strategy.ShouldSell(createTestKline(47000), createTestTradeInfo(50000, 47000, 1))
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "stop loss: -6.00%")
+	})
+
+	t.Run("reset unlocks entry ATR", func(t *testing.T) {
+		strategy := NewStopLossSellStrategy(0, 2, 3)
+		strategy.ShouldSell(createTestKline(50000), createTestTradeInfo(50000, 50000, 1))
+		for i := 0; i < 5; i++ {
+			strategy.Observe(&cex.KlineData{High: decimal.NewFromInt(50050), Low: decimal.NewFromInt(49950), Close: decimal.NewFromInt(50000)})
+		}
+		// 开仓时 ATR 数据不足，本次持仓不止损
+		assert.False(t, strategy.ShouldSell(createTestKline(40000), createTestTradeInfo(50000, 40000, 1)).ShouldSell)
+
+		strategy.Reset()
+		assert.True(t, strategy.ShouldSell(createTestKline(49800), createTestTradeInfo(50000, 49800, 1)).ShouldSell)
+	})
+}
+
+func TestComboSellStrategy_StopLoss(t *testing.T) {
+	config := &SellStrategyConfig{
+		Type:                 SellStrategyCombo,
+		FixedTakeProfit:      0.3,
+		TrailingPercent:      0.05,
+		MinProfitForTrailing: 0.15,
+		StopLossPercent:      0.05,
+	}
+	comboStrategy := NewComboSellStrategy(config)
+	require.NotNil(t, comboStrategy.StopLossStrategy)
+	assert.Equal(t, "Combo(StopLoss(5.0%) + Trailing(5.0% after 15.0%) + Fixed(30.0%))", comboStrategy.GetName())
+
+	signal := comboStrategy.ShouldSell(createTestKline(47000), createTestTradeInfo(50000, 47000, 1))
+	assert.True(t, signal.ShouldSell)
+	assert.Contains(t, signal.Reason, "stop loss")
+
+	signal = comboStrategy.ShouldSell(createTestKline(72500), createTestTradeInfo(50000, 72500, 1))
+	assert.True(t, signal.ShouldSell)
+	assert.Contains(t, signal.Reason, "enhanced")
+}
+
+func TestTimeExitSellStrategy_ShouldSell(t *testing.T) {
+	// 2024-03-06 是周三
+	entry := time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC)
+	klineAt := func(closeTime time.Time) *cex.KlineData {
+		kline := createTestKline(51000)
+		kline.OpenTime, kline.CloseTime = closeTime.Add(-time.Hour), closeTime
+		return kline
+	}
+	tradeInfo := func(bars int) *TradeInfo {
+		info := createTestTradeInfo(50000, 51000, 1)
+		info.EntryTime, info.HoldingBars = entry, bars
+		return info
+	}
+
+	t.Run("max bars", func(t *testing.T) {
+		strategy := NewTimeExitSellStrategy(TimeExitConfig{MaxBars: 5, ExitHour: -1, ExitWeekday: -1})
+		assert.False(t, strategy.ShouldSell(klineAt(entry.Add(4*time.Hour)), tradeInfo(4)).ShouldSell)
+
+		signal := strategy.ShouldSell(klineAt(entry.Add(5*time.Hour)), tradeInfo(5))
+		assert.True(t, signal.ShouldSell)
+		assert.Equal(t, "time exit: held 5 bars (max 5)", signal.Reason)
+	})
+
+	t.Run("max hours", func(t *testing.T) {
+		strategy := NewTimeExitSellStrategy(TimeExitConfig{MaxHours: 24, ExitHour: -1, ExitWeekday: -1})
+		assert.False(t, strategy.ShouldSell(klineAt(entry.Add(23*time.Hour)), tradeInfo(23)).ShouldSell)
+		assert.True(t, strategy.ShouldSell(klineAt(entry.Add(24*time.Hour)), tradeInfo(24)).ShouldSell)
+	})
+
+	t.Run("daily exit time", func(t *testing.T) {
+		strategy := NewTimeExitSellStrategy(TimeExitConfig{ExitHour: 20.5, ExitWeekday: -1})
+		assert.Equal(t, "TimeExit(20:30 UTC)", strategy.GetName())
+		assert.False(t, strategy.ShouldSell(klineAt(time.Date(2024, 3, 6, 20, 0, 0, 0, time.UTC)), tradeInfo(10)).ShouldSell)
+
+		signal := strategy.ShouldSell(klineAt(time.Date(2024, 3, 6, 21, 0, 0, 0, time.UTC)), tradeInfo(11))
+		assert.True(t, signal.ShouldSell)
+		assert.Equal(t, "time exit: scheduled exit at 20:30 UTC", signal.Reason)
+
+		// 在平仓时刻之后开仓的持仓等到第二天
+		lateEntry := tradeInfo(1)
+		lateEntry.EntryTime = time.Date(2024, 3, 6, 22, 0, 0, 0, time.UTC)
+		assert.False(t, strategy.ShouldSell(klineAt(time.Date(2024, 3, 7, 8, 0, 0, 0, time.UTC)), lateEntry).ShouldSell)
+		assert.True(t, strategy.ShouldSell(klineAt(time.Date(2024, 3, 7, 20, 30, 0, 0, time.UTC)), lateEntry).ShouldSell)
+	})
+
+	t.Run("flatten before weekend", func(t *testing.T) {
+		strategy := NewTimeExitSellStrategy(TimeExitConfig{ExitHour: 20, ExitWeekday: int(time.Friday)})
+		assert.Equal(t, "TimeExit(Fri 20:00 UTC)", strategy.GetName())
+		assert.False(t, strategy.ShouldSell(klineAt(time.Date(2024, 3, 7, 21, 0, 0, 0, time.UTC)), tradeInfo(35)).ShouldSell)
+		assert.False(t, strategy.ShouldSell(klineAt(time.Date(2024, 3, 8, 19, 0, 0, 0, time.UTC)), tradeInfo(57)).ShouldSell)
+
+		signal := strategy.ShouldSell(klineAt(time.Date(2024, 3, 8, 20, 0, 0, 0, time.UTC)), tradeInfo(58))
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "Fri 20:00 UTC")
+	})
+
+	t.Run("without entry time only bars apply", func(t *testing.T) {
+		strategy := NewTimeExitSellStrategy(TimeExitConfig{MaxHours: 1, ExitHour: 0, ExitWeekday: -1})
+		info := tradeInfo(100)
+		info.EntryTime = time.Time{}
+		assert.False(t, strategy.ShouldSell(klineAt(entry.Add(100*time.Hour)), info).ShouldSell)
+	})
+}
+
+func TestComboSellStrategy_TimeExit(t *testing.T) {
+	strategy, err := CreateSellStrategyWithParams("combo_smart", map[string]float64{"max_bars": 48, "stop_loss": 0.05})
+	require.NoError(t, err)
+	combo, ok := strategy.(*ComboSellStrategy)
+	require.True(t, ok)
+	require.NotNil(t, combo.TimeExitStrategy)
+	assert.Equal(t, "Combo(StopLoss(5.0%) + TimeExit(48 bars) + Trailing(8.0% after 18.0%) + Fixed(25.0%))", combo.GetName())
+
+	tradeInfo := createTestTradeInfo(50000, 51000, 1)
+	tradeInfo.HoldingBars = 48
+	signal := combo.ShouldSell(createTestKline(51000), tradeInfo)
+	assert.True(t, signal.ShouldSell)
+	assert.Contains(t, signal.Reason, "time exit")
+
+	// 止损优先于按时间平仓
+	tradeInfo = createTestTradeInfo(50000, 47000, 1)
+	tradeInfo.HoldingBars = 48
+	assert.Contains(t, combo.ShouldSell(createTestKline(47000), tradeInfo).Reason, "stop loss")
+}
+
+func TestCreateSellStrategyWithParams_TimeExit(t *testing.T) {
+	strategy, err := CreateSellStrategyWithParams("time_exit", map[string]float64{"exit_hour": 21, "exit_weekday": 5})
+	require.NoError(t, err)
+	timeExit, ok := strategy.(*TimeExitSellStrategy)
+	require.True(t, ok)
+	assert.Equal(t, 21.0, timeExit.ExitHour)
+	assert.Equal(t, 5, timeExit.ExitWeekday)
+	assert.Equal(t, 0, timeExit.MaxBars)
+
+	strategy, err = CreateSellStrategyWithParams("weekend_flat", map[string]float64{"max_hours": 72})
+	require.NoError(t, err)
+	assert.Equal(t, "TimeExit(72.0h, Fri 20:00 UTC)", strategy.GetName())
+	// 预设配置不被修改
+	assert.Equal(t, 0.0, GetDefaultSellStrategyConfigs()["weekend_flat"].TimeExit.MaxHours)
+
+	_, err = CreateSellStrategyWithParams("time_exit", map[string]float64{})
+	assert.ErrorContains(t, err, "requires max_bars, max_hours or exit_hour")
+
+	_, err = CreateSellStrategyWithParams("time_exit", map[string]float64{"exit_hour": 24})
+	assert.ErrorContains(t, err, "exit_hour must be within [0, 24)")
+
+	_, err = CreateSellStrategyWithParams("time_exit", map[string]float64{"exit_weekday": 5})
+	assert.ErrorContains(t, err, "exit_weekday requires exit_hour")
+
+	_, err = CreateSellStrategyWithParams("moderate", map[string]float64{"max_bars": 10})
+	assert.ErrorContains(t, err, "does not support time exit")
+}
+
+func TestBreakEvenSellStrategy_ShouldSell(t *testing.T) {
+	strategy := NewBreakEvenSellStrategy(0.05, 0.001)
+	assert.Equal(t, "BreakEven(after 5.0%, fee 0.10%)", strategy.GetName())
+	// 保本价 = 50000 × 1.001 / 0.999
+	level := strategy.BreakEvenPrice(decimal.NewFromInt(50000))
+	assert.InDelta(t, 50100.1, level.InexactFloat64(), 0.01)
+
+	t.Run("not activated below trigger", func(t *testing.T) {
+		tradeInfo := createTestTradeInfo(50000, 50000, 1)
+		tradeInfo.HighestPrice = decimal.NewFromInt(52000) // 最高 +4%
+		assert.False(t, strategy.Activated(tradeInfo))
+		assert.False(t, strategy.ShouldSell(createTestKline(50000), tradeInfo).ShouldSell)
+	})
+
+	t.Run("activated holds above break-even", func(t *testing.T) {
+		tradeInfo := createTestTradeInfo(50000, 51000, 1)
+		tradeInfo.HighestPrice = decimal.NewFromInt(53000) // 最高 +6%
+		assert.True(t, strategy.Activated(tradeInfo))
+		assert.False(t, strategy.ShouldSell(createTestKline(51000), tradeInfo).ShouldSell)
+	})
+
+	t.Run("sells when price returns to break-even", func(t *testing.T) {
+		tradeInfo := createTestTradeInfo(50000, 50100, 1)
+		tradeInfo.HighestPrice = decimal.NewFromInt(53000)
+		signal := strategy.ShouldSell(createTestKline(50100), tradeInfo)
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "break-even stop")
+		assert.Equal(t, 1.0, signal.Strength)
+	})
+}
+
+func TestCreateSellStrategyWithParams_BreakEven(t *testing.T) {
+	strategy, err := CreateSellStrategyWithParams("break_even", map[string]float64{"break_even_trigger": 0.08})
+	require.NoError(t, err)
+	breakEven, ok := strategy.(*BreakEvenSellStrategy)
+	require.True(t, ok)
+	assert.Equal(t, 0.08, breakEven.TriggerPercent)
+	assert.Equal(t, 0.001, breakEven.FeeRate)
+
+	strategy, err = CreateSellStrategyWithParams("combo_smart", map[string]float64{"break_even_trigger": 0.1, "stop_loss": 0.05})
+	require.NoError(t, err)
+	combo, ok := strategy.(*ComboSellStrategy)
+	require.True(t, ok)
+	require.NotNil(t, combo.BreakEvenStrategy)
+	assert.Equal(t, 0.001, combo.BreakEvenStrategy.FeeRate)
+	assert.Equal(t, "Combo(StopLoss(5.0%) + BreakEven(after 10.0%, fee 0.10%) + Trailing(8.0% after 18.0%) + Fixed(25.0%))", combo.GetName())
+
+	// 组合策略：盈利回吐到保本价时卖出，先于止盈检查
+	tradeInfo := createTestTradeInfo(50000, 50050, 1)
+	tradeInfo.HighestPrice = decimal.NewFromInt(56000)
+	assert.Contains(t, combo.ShouldSell(createTestKline(50050), tradeInfo).Reason, "break-even stop")
+
+	_, err = CreateSellStrategyWithParams("break_even", map[string]float64{"break_even_trigger": 0.001})
+	assert.ErrorContains(t, err, "must exceed the round-trip fee")
+
+	_, err = CreateSellStrategyWithParams("trailing_5", map[string]float64{"break_even_trigger": 0.05})
+	assert.ErrorContains(t, err, "does not support break-even stop")
+}
+
 func TestCreateSellStrategy(t *testing.T) {
 	t.Run("create fixed strategy", func(t *testing.T) {
 		config := &SellStrategyConfig{
@@ -335,6 +584,7 @@ func TestGetDefaultSellStrategyConfigs(t *testing.T) {
 		"conservative", "moderate", "aggressive",
 		"trailing_5", "trailing_10",
 		"combo_smart", "partial_pyramid",
+		"stop_loss_5", "atr_stop", "weekend_flat",
 	}
 
 	for _, name := range expectedStrategies {
@@ -440,6 +690,26 @@ func TestCreateSellStrategyWithParams(t *testing.T) {
 		assert.Equal(t, 0.20, fixedStrategy.TakeProfitPercent) // 默认值
 	})
 
+	t.Run("stop loss params", func(t *testing.T) {
+		strategy, err := CreateSellStrategyWithParams("stop_loss", map[string]float64{"atr_multiple": 2.5})
+		require.NoError(t, err)
+		stopLoss, ok := strategy.(*StopLossSellStrategy)
+		require.True(t, ok)
+		assert.Equal(t, 0.05, stopLoss.StopLossPercent)
+		assert.Equal(t, 2.5, stopLoss.ATRMultiple)
+		assert.Equal(t, 14, stopLoss.ATRPeriod)
+
+		strategy, err = CreateSellStrategyWithParams("combo_smart", map[string]float64{"stop_loss": 0.04})
+		require.NoError(t, err)
+		combo, ok := strategy.(*ComboSellStrategy)
+		require.True(t, ok)
+		require.NotNil(t, combo.StopLossStrategy)
+		assert.Equal(t, 0.04, combo.StopLossStrategy.StopLossPercent)
+
+		_, err = CreateSellStrategyWithParams("conservative", map[string]float64{"stop_loss": 0.04})
+		assert.ErrorContains(t, err, "does not support stop loss")
+	})
+
 	t.Run("unknown strategy", func(t *testing.T) {
 		strategy, err := CreateSellStrategyWithParams("unknown", map[string]float64{})
 		assert.Error(t, err)
@@ -547,6 +817,63 @@ func TestSellStrategies_Reset(t *testing.T) {
 	})
 }
 
+func TestSellStrategies_SaveLoad(t *testing.T) {
+	t.Run("partial keeps executed level", func(t *testing.T) {
+		levels := []PartialLevel{{ProfitPercent: 0.2, SellPercent: 0.3}, {ProfitPercent: 0.4, SellPercent: 1.0}}
+		strategy := NewPartialSellStrategy(levels)
+		require.True(t, strategy.ShouldSell(createTestKline(60000), createTestTradeInfo(50000, 60000, 1)).ShouldSell)
+
+		data, err := strategy.Save()
+		require.NoError(t, err)
+		restored := NewPartialSellStrategy(levels)
+		require.NoError(t, restored.Load(data))
+		assert.Equal(t, 0, restored.ExecutedLevel)
+
+		// 重启后不重复卖出第一级
+		assert.False(t, restored.ShouldSell(createTestKline(60000), createTestTradeInfo(50000, 60000, 1)).ShouldSell)
+
+		assert.Error(t, NewPartialSellStrategy(levels[:0]).Load(data), "executed level beyond configured levels")
+		assert.Error(t, restored.Load([]byte("{")))
+		assert.NoError(t, restored.Load(nil))
+		assert.Equal(t, 0, restored.ExecutedLevel)
+	})
+
+	t.Run("stop loss keeps entry ATR", func(t *testing.T) {
+		strategy := NewStopLossSellStrategy(0, 2, 3)
+		for i := 0; i < 5; i++ {
+			strategy.Observe(&cex.KlineData{High: decimal.NewFromInt(50050), Low: decimal.NewFromInt(49950), Close: decimal.NewFromInt(50000)})
+		}
+		require.False(t, strategy.ShouldSell(createTestKline(49850), createTestTradeInfo(50000, 49850, 1)).ShouldSell)
+
+		data, err := strategy.Save()
+		require.NoError(t, err)
+		restored := NewComboSellStrategy(&SellStrategyConfig{Type: SellStrategyCombo, FixedTakeProfit: 0.3, TrailingPercent: 0.05,
+			MinProfitForTrailing: 0.15, ATRStopMultiple: 2, ATRPeriod: 3})
+		require.NoError(t, restored.Load(data))
+		assert.True(t, restored.StopLossStrategy.entryLocked)
+		assert.True(t, restored.StopLossStrategy.entryATR.Equal(decimal.NewFromInt(100)))
+		assert.Len(t, restored.StopLossStrategy.closes, 5)
+
+		signal := restored.ShouldSell(createTestKline(49800), createTestTradeInfo(50000, 49800, 1))
+		assert.True(t, signal.ShouldSell)
+		assert.Contains(t, signal.Reason, "ATR stop loss")
+	})
+
+	t.Run("stateless strategies save nothing", func(t *testing.T) {
+		for _, strategy := range []SellStrategy{
+			NewFixedSellStrategy(0.2),
+			NewTrailingSellStrategy(0.05, 0.15),
+			NewBreakEvenSellStrategy(0.03, 0.001),
+			NewComboSellStrategy(&SellStrategyConfig{Type: SellStrategyCombo, FixedTakeProfit: 0.3, TrailingPercent: 0.05}),
+		} {
+			data, err := strategy.Save()
+			require.NoError(t, err)
+			assert.Nil(t, data, strategy.GetName())
+			assert.NoError(t, strategy.Load(data))
+		}
+	})
+}
+
 // Test TechnicalSellStrategy ShouldSell
 func TestTechnicalSellStrategy_ShouldSell(t *testing.T) {
 	strategy := NewTechnicalSellStrategy()
@@ -679,3 +1006,132 @@ func TestGetDefaultBollingerBandsParams(t *testing.T) {
 	// Note: This test requires GetDefaultBollingerBandsParams to be imported properly
 	t.Skip("Skipping GetDefaultBollingerBandsParams test - function not available in this package")
 }
+
+// Test take profit ladder validation and ladder levels
+func TestBollingerBandsParams_ValidateTakeProfitLadder(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.TakeProfitLadder = true
+
+	// 默认 moderate 为固定止盈，不支持阶梯挂单
+	assert.Error(t, params.Validate())
+
+	params.SellStrategyName = "partial_pyramid"
+	assert.NoError(t, params.Validate())
+
+	sellStrategy, err := CreateSellStrategyWithParams("partial_pyramid", nil)
+	assert.NoError(t, err)
+	ladder, ok := sellStrategy.(LadderSellStrategy)
+	assert.True(t, ok)
+	assert.Len(t, ladder.GetLadderLevels(), 3)
+}
+
+func TestBollingerBandsParams_ValidateTrailingStop(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	assert.NoError(t, params.Validate())
+
+	params.TrailingStop = 0.05
+	assert.NoError(t, params.Validate())
+
+	params.TrailingStop = -0.1
+	assert.Error(t, params.Validate())
+
+	params.TrailingStop = 1
+	assert.Error(t, params.Validate())
+}
+
+func TestBollingerBandsParams_ValidateOCO(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.OCO = true
+
+	// 默认 stop_loss_percent=1.0 表示不止损，不能用于 OCO
+	assert.Error(t, params.Validate())
+
+	params.StopLossPercent = 0.05
+	assert.NoError(t, params.Validate())
+
+	params.TrailingStop = 0.05
+	assert.Error(t, params.Validate())
+}
+
+func TestBollingerBandsParams_ValidateATRStops(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.ATRStopMultiple = 2
+
+	// 启用 ATR 止损需要 ATR 周期
+	assert.Error(t, params.Validate())
+
+	params.ATRPeriod = 14
+	params.ATRTakeProfitMultiple = 3
+	assert.NoError(t, params.Validate())
+
+	params.ATRTakeProfitMultiple = -1
+	assert.Error(t, params.Validate())
+}
+
+func TestBollingerBandsParams_ValidateBandWidth(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.MinBandWidth = 0.02
+	params.MaxBandWidth = 0.3
+	assert.NoError(t, params.Validate())
+
+	params.MinBandWidth = 0.3
+	assert.Error(t, params.Validate())
+
+	params.MinBandWidth, params.MaxBandWidth = -0.01, 0
+	assert.Error(t, params.Validate())
+}
+
+func TestBollingerBandsParams_ValidateVolumeFilter(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.VolumeMultiplier = 1.5
+
+	// 启用成交量确认需要平均周期
+	assert.Error(t, params.Validate())
+
+	params.VolumePeriod = 20
+	assert.NoError(t, params.Validate())
+
+	params.VolumeMultiplier = -1
+	assert.Error(t, params.Validate())
+}
+
+func TestBollingerBandsParams_ValidateFundingFilter(t *testing.T) {
+	params := GetDefaultBollingerBandsParams()
+	params.MaxFundingRate = 0.0005
+	assert.NoError(t, params.Validate())
+
+	params.MaxFundingRate = -0.0001
+	assert.NoError(t, params.Validate())
+
+	// 按百分数填写（0.05 表示 0.05%）时报错
+	params.MaxFundingRate = 0.05
+	assert.Error(t, params.Validate())
+}
+
+// Test loading params file over base params
+func TestLoadBollingerBandsParamsFile(t *testing.T) {
+	base := GetDefaultBollingerBandsParams()
+	base.SellStrategyParams = map[string]float64{"take_profit": 0.25}
+
+	path := filepath.Join(t.TempDir(), "params.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"period": 30, "multiplier": 2.5, "sell_strategy_params": {"take_profit": 0.3}}`), 0644))
+
+	params, err := LoadBollingerBandsParamsFile(path, base)
+	require.NoError(t, err)
+	assert.Equal(t, 30, params.Period)
+	assert.Equal(t, 2.5, params.Multiplier)
+	assert.Equal(t, base.PositionSizePercent, params.PositionSizePercent)
+	assert.Equal(t, "moderate", params.SellStrategyName)
+	assert.Equal(t, 0.3, params.SellStrategyParams["take_profit"])
+
+	// base 不应被修改
+	assert.Equal(t, 20, base.Period)
+	assert.Equal(t, 0.25, base.SellStrategyParams["take_profit"])
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"period": `), 0644))
+	_, err = LoadBollingerBandsParamsFile(path, base)
+	assert.Error(t, err)
+
+	_, err = LoadBollingerBandsParamsFile(filepath.Join(t.TempDir(), "missing.json"), base)
+	assert.Error(t, err)
+}
@@ -2,9 +2,12 @@ package strategy
 
 import (
 	"context"
+	"time"
 
 	"tradingbot/src/cex"
 	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
 )
 
 // Signal 交易信号
@@ -13,6 +16,22 @@ type Signal struct {
 	Reason    string  `json:"reason"`    // 信号原因
 	Strength  float64 `json:"strength"`  // 信号强度 0-1
 	Timestamp int64   `json:"timestamp"` // 信号时间戳
+
+	// 下单方式：为空或 LIMIT 时挂限价单，价格为收盘价偏移 LimitOffset（为空时 0.1%，买入向下、卖出向上）；
+	// MARKET 时下市价单，回测按下一根K线开盘价成交
+	OrderType   executor.OrderType `json:"order_type,omitempty"`
+	LimitOffset *float64           `json:"limit_offset,omitempty"`
+
+	// 价格和数量提示（为零时不使用）：LimitPrice 指定限价（优先于 LimitOffset），Quantity 指定下单数量
+	// （买入不超过可用现金，卖出不超过持仓，优先于 Strength）；买入信号的 StopLoss/TakeProfit 在开仓成交后由引擎挂出保护单
+	LimitPrice decimal.Decimal `json:"limit_price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	StopLoss   decimal.Decimal `json:"stop_loss"`
+	TakeProfit decimal.Decimal `json:"take_profit"`
+
+	// 挂单有效方式（为空时使用引擎默认）；GTD 挂单在 ValidFor 后过期，为 0 时使用引擎默认有效期
+	TimeInForce cex.TimeInForce `json:"time_in_force,omitempty"`
+	ValidFor    time.Duration   `json:"valid_for,omitempty"`
 }
 
 // StrategyParams 策略参数接口
@@ -34,4 +53,51 @@ type Strategy interface {
 
 	// SetParams 设置策略参数
 	SetParams(params StrategyParams) error
+
+	// Save 序列化内部状态（JSON），实盘由引擎保存，重启后恢复持仓跟踪、冷却期等；无状态时返回 nil
+	Save() ([]byte, error)
+
+	// Load 恢复 Save 保存的状态（在 SetParams 之后、第一根K线之前调用），data 为空时不做处理
+	Load(data []byte) error
+}
+
+// TakeProfitLadderProvider 支持止盈阶梯挂单的策略
+// 开仓成交后由引擎立即挂出全部止盈限价单，而不是逐根K线判断是否卖出
+type TakeProfitLadderProvider interface {
+	// GetTakeProfitLadder 获取止盈阶梯，返回空表示不使用阶梯挂单
+	GetTakeProfitLadder() []PartialLevel
+}
+
+// TrailingStopProvider 支持移动止损挂单的策略
+// 开仓成交后由引擎挂出移动止损单，触发价随K线新高上移
+type TrailingStopProvider interface {
+	// GetTrailingStopPercent 获取回撤比例（如 0.05 表示自最高价回撤 5% 卖出），0 表示不使用
+	GetTrailingStopPercent() float64
+}
+
+// OCOProvider 支持 OCO 挂单的策略
+// 开仓成交后由引擎同时挂出止盈限价单和止损单，一个成交后撤销另一个
+type OCOProvider interface {
+	// GetOCOPercents 获取止盈、止损比例，任一为 0 表示不使用
+	GetOCOPercents() (takeProfit, stopLoss float64)
+}
+
+// SellStrategyUpdater 支持运行中替换卖出策略的策略（实盘热更新配置）
+type SellStrategyUpdater interface {
+	// UpdateSellStrategy 按名称和用户参数重建卖出策略，参数无效时返回错误且保持原策略
+	UpdateSellStrategy(name string, params map[string]float64) error
+}
+
+// ATRStopProvider 按 ATR 设置止盈止损距离的策略
+// 引擎挂出 OCO 时以开仓价 ± N×ATR 代替固定比例（K线不足以计算 ATR 时仍用固定比例）
+type ATRStopProvider interface {
+	// GetATRStops 获取 ATR 周期和止盈、止损的 ATR 倍数，倍数为 0 表示不使用
+	GetATRStops() (period int, takeProfitMultiple, stopLossMultiple float64)
+}
+
+// IndicatorProvider 可提供指标快照的策略
+// 引擎发布信号事件时附带快照，通知中可看到信号产生时的指标值
+type IndicatorProvider interface {
+	// GetIndicators 获取最近一根K线的指标值（如布林道上中下轨），数据不足时返回空
+	GetIndicators() map[string]float64
 }
@@ -0,0 +1,59 @@
+package strategy
+
+import (
+	"fmt"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+)
+
+// VolumeFilter 成交量确认：当前K线成交量超过前 Period 根K线平均成交量的 Multiplier 倍时才确认开仓
+// 策略每根K线调用 Observe，产生开仓信号前调用 Confirm
+type VolumeFilter struct {
+	Period     int
+	Multiplier float64
+
+	volumes []decimal.Decimal // 最近 Period+1 根K线的成交量（最后一个为当前K线）
+}
+
+// NewVolumeFilter 创建成交量确认过滤器
+func NewVolumeFilter(period int, multiplier float64) *VolumeFilter {
+	return &VolumeFilter{Period: period, Multiplier: multiplier}
+}
+
+// Observe 记录K线成交量
+func (f *VolumeFilter) Observe(kline *cex.KlineData) {
+	f.volumes = append(f.volumes, kline.Volume)
+	if len(f.volumes) > f.Period+1 {
+		f.volumes = f.volumes[len(f.volumes)-f.Period-1:]
+	}
+}
+
+// Ratio 当前成交量相对前 Period 根K线平均成交量的倍数，数据不足或平均成交量为零时 ok 为 false
+func (f *VolumeFilter) Ratio() (ratio float64, ok bool) {
+	if f.Period <= 0 || len(f.volumes) < f.Period+1 {
+		return 0, false
+	}
+	sum := decimal.Zero
+	for _, volume := range f.volumes[:f.Period] {
+		sum = sum.Add(volume)
+	}
+	average := sum.Div(decimal.NewFromInt(int64(f.Period)))
+	if !average.IsPositive() {
+		return 0, false
+	}
+	return f.volumes[f.Period].Div(average).InexactFloat64(), true
+}
+
+// Confirm 当前K线成交量是否确认开仓，未确认时返回原因（数据不足时不确认）
+func (f *VolumeFilter) Confirm() (bool, string) {
+	ratio, ok := f.Ratio()
+	if !ok {
+		return false, fmt.Sprintf("insufficient volume history (need %d bars)", f.Period+1)
+	}
+	if ratio <= f.Multiplier {
+		return false, fmt.Sprintf("volume %.2f× SMA%d not above %.2f×", ratio, f.Period, f.Multiplier)
+	}
+	return true, ""
+}
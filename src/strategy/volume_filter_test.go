@@ -0,0 +1,49 @@
+package strategy
+
+import (
+	"testing"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeFilter_Confirm(t *testing.T) {
+	filter := NewVolumeFilter(3, 1.5)
+	observe := func(volume int64) {
+		filter.Observe(&cex.KlineData{Volume: decimal.NewFromInt(volume)})
+	}
+
+	observe(100)
+	observe(100)
+	observe(100)
+	confirmed, reason := filter.Confirm()
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "insufficient volume history")
+
+	// 150 = 1.5× 平均成交量，需要严格大于
+	observe(150)
+	confirmed, reason = filter.Confirm()
+	assert.False(t, confirmed)
+	assert.Contains(t, reason, "volume 1.50× SMA3 not above 1.50×")
+
+	// 前 3 根平均 (100+100+150)/3，当前 200 ≈ 1.71×
+	observe(200)
+	confirmed, _ = filter.Confirm()
+	assert.True(t, confirmed)
+	ratio, ok := filter.Ratio()
+	assert.True(t, ok)
+	assert.InDelta(t, 1.714, ratio, 0.001)
+}
+
+func TestVolumeFilter_ZeroAverage(t *testing.T) {
+	filter := NewVolumeFilter(2, 1)
+	for _, volume := range []int64{0, 0, 10} {
+		filter.Observe(&cex.KlineData{Volume: decimal.NewFromInt(volume)})
+	}
+	_, ok := filter.Ratio()
+	assert.False(t, ok)
+	confirmed, _ := filter.Confirm()
+	assert.False(t, confirmed)
+}
@@ -0,0 +1,205 @@
+package timeframes
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+)
+
+// Resample 将 fromTF 周期的K线聚合为 toTF 周期的K线
+//
+// 聚合规则：开盘价取第一根，收盘价取最后一根，最高/最低取极值，
+// 成交量、成交额、主动买入量/额求和。分桶按 UTC 对齐（与币安一致）：
+// 日内及 1d/3d 周期按 Unix 纪元对齐，1w 从周一 00:00 开始，1M 从每月1日开始，
+// 因此与本地时区及夏令时无关。
+//
+// 首尾未覆盖完整周期的K线（部分K线）也会输出，其收盘时间为最后一根源K线的收盘时间；
+// 只需要完整K线时使用 ResampleComplete。
+func Resample(klines []*cex.KlineData, fromTF, toTF Timeframe) ([]*cex.KlineData, error) {
+	buckets, err := resampleBuckets(klines, fromTF, toTF)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*cex.KlineData, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, b.kline)
+	}
+	return result, nil
+}
+
+// ResampleComplete 与 Resample 相同，但丢弃源K线不足一个完整周期的部分K线（如尚未收盘的当前K线）
+func ResampleComplete(klines []*cex.KlineData, fromTF, toTF Timeframe) ([]*cex.KlineData, error) {
+	buckets, err := resampleBuckets(klines, fromTF, toTF)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*cex.KlineData, 0, len(buckets))
+	for _, b := range buckets {
+		if b.count == b.expected {
+			result = append(result, b.kline)
+		}
+	}
+	return result, nil
+}
+
+// CanResample 检查 fromTF 能否聚合为 toTF：目标周期必须是源周期的整数倍
+func CanResample(fromTF, toTF Timeframe) error {
+	fromDuration, err := fromTF.GetDuration()
+	if err != nil {
+		return err
+	}
+	toDuration, err := toTF.GetDuration()
+	if err != nil {
+		return err
+	}
+
+	if fromTF == toTF {
+		return nil
+	}
+
+	// 月线长度不固定，源周期必须能整除一天
+	if toTF == Timeframe1M {
+		if fromDuration > 24*time.Hour || (24*time.Hour)%fromDuration != 0 {
+			return fmt.Errorf("cannot resample %s to %s", fromTF, toTF)
+		}
+		return nil
+	}
+
+	if fromTF == Timeframe1M || toDuration < fromDuration || toDuration%fromDuration != 0 {
+		return fmt.Errorf("cannot resample %s to %s", fromTF, toTF)
+	}
+	return nil
+}
+
+// BucketStart 返回时间 t 所在 tf 周期K线的开盘时间（UTC）
+func BucketStart(t time.Time, tf Timeframe) (time.Time, error) {
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t = t.UTC()
+	switch tf {
+	case Timeframe1M:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case Timeframe1w:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		// 币安周线从周一开始
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset), nil
+	default:
+		ms := t.UnixMilli()
+		step := duration.Milliseconds()
+		start := ms - ((ms%step)+step)%step
+		return time.UnixMilli(start).UTC(), nil
+	}
+}
+
+// NextOpenTime 返回时间 t 所在 tf 周期K线的下一根K线开盘时间（UTC）
+func NextOpenTime(t time.Time, tf Timeframe) (time.Time, error) {
+	start, err := BucketStart(t, tf)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bucketEnd(start, tf), nil
+}
+
+// bucketEnd 返回 tf 周期K线的结束时间（下一根K线的开盘时间）
+func bucketEnd(start time.Time, tf Timeframe) time.Time {
+	switch tf {
+	case Timeframe1M:
+		return start.AddDate(0, 1, 0)
+	case Timeframe1w:
+		return start.AddDate(0, 0, 7)
+	default:
+		duration, _ := tf.GetDuration()
+		return start.Add(duration)
+	}
+}
+
+// resampleBucket 聚合中的一根目标K线
+type resampleBucket struct {
+	kline    *cex.KlineData
+	count    int // 已聚合的源K线数量
+	expected int // 完整周期应包含的源K线数量
+}
+
+// resampleBuckets 按目标周期分桶聚合
+func resampleBuckets(klines []*cex.KlineData, fromTF, toTF Timeframe) ([]*resampleBucket, error) {
+	if err := CanResample(fromTF, toTF); err != nil {
+		return nil, err
+	}
+	fromDuration, _ := fromTF.GetDuration()
+
+	sorted := make([]*cex.KlineData, 0, len(klines))
+	for _, k := range klines {
+		if k != nil {
+			sorted = append(sorted, k)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].OpenTime.Before(sorted[j].OpenTime) })
+
+	var buckets []*resampleBucket
+	var current *resampleBucket
+	var lastOpen time.Time
+
+	for i, k := range sorted {
+		if i > 0 && k.TradingPair != sorted[0].TradingPair {
+			return nil, fmt.Errorf("cannot resample mixed trading pairs: %s and %s", sorted[0].TradingPair, k.TradingPair)
+		}
+		// 重复的源K线只计一次
+		if current != nil && k.OpenTime.Equal(lastOpen) {
+			continue
+		}
+		lastOpen = k.OpenTime
+
+		start, err := BucketStart(k.OpenTime, toTF)
+		if err != nil {
+			return nil, err
+		}
+
+		if current == nil || !start.Equal(current.kline.OpenTime) {
+			end := bucketEnd(start, toTF)
+			current = &resampleBucket{
+				kline: &cex.KlineData{
+					TradingPair:         k.TradingPair,
+					OpenTime:            start,
+					Open:                k.Open,
+					High:                k.High,
+					Low:                 k.Low,
+					Close:               k.Close,
+					Volume:              k.Volume,
+					CloseTime:           k.CloseTime.UTC(),
+					QuoteVolume:         k.QuoteVolume,
+					TakerBuyVolume:      k.TakerBuyVolume,
+					TakerBuyQuoteVolume: k.TakerBuyQuoteVolume,
+				},
+				count:    1,
+				expected: int(end.Sub(start) / fromDuration),
+			}
+			buckets = append(buckets, current)
+			continue
+		}
+
+		b := current.kline
+		if k.High.GreaterThan(b.High) {
+			b.High = k.High
+		}
+		if k.Low.LessThan(b.Low) {
+			b.Low = k.Low
+		}
+		b.Close = k.Close
+		b.CloseTime = k.CloseTime.UTC()
+		b.Volume = b.Volume.Add(k.Volume)
+		b.QuoteVolume = b.QuoteVolume.Add(k.QuoteVolume)
+		b.TakerBuyVolume = b.TakerBuyVolume.Add(k.TakerBuyVolume)
+		b.TakerBuyQuoteVolume = b.TakerBuyQuoteVolume.Add(k.TakerBuyQuoteVolume)
+		current.count++
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,242 @@
+package timeframes
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testResamplePair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+// makeKlines 生成从 start 开始、间隔 tf 的连续K线，价格依次为 prices
+func makeKlines(t *testing.T, start time.Time, tf Timeframe, prices ...float64) []*cex.KlineData {
+	duration, err := tf.GetDuration()
+	require.NoError(t, err)
+
+	klines := make([]*cex.KlineData, 0, len(prices))
+	for i, p := range prices {
+		openTime := start.Add(time.Duration(i) * duration)
+		price := decimal.NewFromFloat(p)
+		klines = append(klines, &cex.KlineData{
+			TradingPair:         testResamplePair,
+			OpenTime:            openTime,
+			Open:                price,
+			High:                price.Add(decimal.NewFromInt(1)),
+			Low:                 price.Sub(decimal.NewFromInt(1)),
+			Close:               price.Add(decimal.NewFromFloat(0.5)),
+			Volume:              decimal.NewFromInt(2),
+			CloseTime:           openTime.Add(duration - time.Millisecond),
+			QuoteVolume:         price.Mul(decimal.NewFromInt(2)),
+			TakerBuyVolume:      decimal.NewFromInt(1),
+			TakerBuyQuoteVolume: price,
+		})
+	}
+	return klines
+}
+
+func TestResample_AggregatesOHLCV(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := makeKlines(t, start, Timeframe1h, 100, 105, 98, 102, 110, 120, 115, 111)
+
+	result, err := Resample(klines, Timeframe1h, Timeframe4h)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	first := result[0]
+	assert.Equal(t, start, first.OpenTime)
+	assert.Equal(t, start.Add(4*time.Hour-time.Millisecond), first.CloseTime)
+	assert.True(t, first.Open.Equal(decimal.NewFromInt(100)))
+	assert.True(t, first.High.Equal(decimal.NewFromInt(106)))
+	assert.True(t, first.Low.Equal(decimal.NewFromInt(97)))
+	assert.True(t, first.Close.Equal(decimal.NewFromFloat(102.5)))
+	assert.True(t, first.Volume.Equal(decimal.NewFromInt(8)))
+	assert.True(t, first.QuoteVolume.Equal(decimal.NewFromInt(810)))
+	assert.True(t, first.TakerBuyVolume.Equal(decimal.NewFromInt(4)))
+	assert.True(t, first.TakerBuyQuoteVolume.Equal(decimal.NewFromInt(405)))
+
+	second := result[1]
+	assert.Equal(t, start.Add(4*time.Hour), second.OpenTime)
+	assert.True(t, second.Open.Equal(decimal.NewFromInt(110)))
+	assert.True(t, second.High.Equal(decimal.NewFromInt(121)))
+	assert.True(t, second.Low.Equal(decimal.NewFromInt(109)))
+	assert.True(t, second.Close.Equal(decimal.NewFromFloat(111.5)))
+}
+
+func TestResample_PartialCandles(t *testing.T) {
+	// 从 02:00 开始到 09:00：00:00 桶缺前两根，08:00 桶只有一根
+	start := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	klines := makeKlines(t, start, Timeframe1h, 1, 2, 3, 4, 5, 6, 7)
+
+	all, err := Resample(klines, Timeframe1h, Timeframe4h)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), all[0].OpenTime)
+	assert.True(t, all[0].Volume.Equal(decimal.NewFromInt(4)))
+	// 部分K线的收盘时间为最后一根源K线的收盘时间
+	last := all[2]
+	assert.Equal(t, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), last.OpenTime)
+	assert.Equal(t, time.Date(2024, 1, 1, 8, 59, 59, 999000000, time.UTC), last.CloseTime)
+
+	complete, err := ResampleComplete(klines, Timeframe1h, Timeframe4h)
+	require.NoError(t, err)
+	require.Len(t, complete, 1)
+	assert.Equal(t, time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC), complete[0].OpenTime)
+	assert.True(t, complete[0].Open.Equal(decimal.NewFromInt(3)))
+	assert.True(t, complete[0].Close.Equal(decimal.NewFromFloat(6.5)))
+}
+
+func TestResample_UnsortedAndDuplicateInput(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := makeKlines(t, start, Timeframe15m, 10, 11, 12, 13)
+	shuffled := []*cex.KlineData{klines[2], klines[0], klines[3], klines[1], klines[1]}
+
+	result, err := Resample(shuffled, Timeframe15m, Timeframe1h)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.True(t, result[0].Open.Equal(decimal.NewFromInt(10)))
+	assert.True(t, result[0].Close.Equal(decimal.NewFromFloat(13.5)))
+	assert.True(t, result[0].Volume.Equal(decimal.NewFromInt(8)))
+}
+
+func TestResample_DSTBoundaryUsesUTC(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// 2024-03-10 02:00 纽约夏令时开始，本地时间跳过一小时，UTC 日线不受影响
+	start := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC).In(newYork)
+	prices := make([]float64, 72)
+	for i := range prices {
+		prices[i] = float64(100 + i)
+	}
+	klines := makeKlines(t, start, Timeframe1h, prices...)
+
+	result, err := Resample(klines, Timeframe1h, Timeframe1d)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	for i, k := range result {
+		assert.Equal(t, time.Date(2024, 3, 9+i, 0, 0, 0, 0, time.UTC), k.OpenTime)
+		assert.Equal(t, time.UTC, k.OpenTime.Location())
+		assert.True(t, k.Volume.Equal(decimal.NewFromInt(48)), "day %d should contain 24 candles", i)
+		assert.True(t, k.Open.Equal(decimal.NewFromInt(int64(100+24*i))))
+	}
+
+	complete, err := ResampleComplete(klines, Timeframe1h, Timeframe1d)
+	require.NoError(t, err)
+	assert.Len(t, complete, 3)
+}
+
+func TestResample_WeeklyAndMonthlyAlignment(t *testing.T) {
+	// 2024-01-31 是周三
+	start := time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)
+	prices := make([]float64, 35)
+	for i := range prices {
+		prices[i] = float64(i + 1)
+	}
+	klines := makeKlines(t, start, Timeframe1d, prices...)
+
+	weekly, err := Resample(klines, Timeframe1d, Timeframe1w)
+	require.NoError(t, err)
+	require.Len(t, weekly, 5)
+	for _, k := range weekly {
+		assert.Equal(t, time.Monday, k.OpenTime.Weekday())
+	}
+
+	monthly, err := Resample(klines, Timeframe1d, Timeframe1M)
+	require.NoError(t, err)
+	require.Len(t, monthly, 3)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), monthly[0].OpenTime)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), monthly[1].OpenTime)
+
+	// 2024年2月有29天
+	completeMonthly, err := ResampleComplete(klines, Timeframe1d, Timeframe1M)
+	require.NoError(t, err)
+	require.Len(t, completeMonthly, 1)
+	assert.Equal(t, time.February, completeMonthly[0].OpenTime.Month())
+	assert.True(t, completeMonthly[0].Volume.Equal(decimal.NewFromInt(58)))
+}
+
+func TestResample_SameTimeframe(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := makeKlines(t, start, Timeframe1h, 1, 2, 3)
+
+	result, err := Resample(klines, Timeframe1h, Timeframe1h)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	assert.NotSame(t, klines[0], result[0])
+	assert.Equal(t, klines[1].OpenTime, result[1].OpenTime)
+}
+
+func TestResample_Errors(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := makeKlines(t, start, Timeframe1h, 1, 2)
+
+	tests := []struct {
+		name   string
+		fromTF Timeframe
+		toTF   Timeframe
+	}{
+		{"downsample", Timeframe4h, Timeframe1h},
+		{"not a multiple", Timeframe3d, Timeframe1w},
+		{"monthly source", Timeframe1M, Timeframe1w},
+		{"invalid timeframe", Timeframe("7m"), Timeframe1h},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resample(klines, tt.fromTF, tt.toTF)
+			assert.Error(t, err)
+		})
+	}
+
+	mixed := append(makeKlines(t, start, Timeframe1h, 1), &cex.KlineData{
+		TradingPair: cex.TradingPair{Base: "ETH", Quote: "USDT"},
+		OpenTime:    start.Add(time.Hour),
+	})
+	_, err := Resample(mixed, Timeframe1h, Timeframe4h)
+	assert.Error(t, err)
+}
+
+func TestBucketStart(t *testing.T) {
+	ts := time.Date(2024, 5, 15, 13, 47, 12, 0, time.UTC)
+
+	tests := []struct {
+		tf       Timeframe
+		expected time.Time
+	}{
+		{Timeframe15m, time.Date(2024, 5, 15, 13, 45, 0, 0, time.UTC)},
+		{Timeframe4h, time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)},
+		{Timeframe1d, time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)},
+		{Timeframe1w, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)},
+		{Timeframe1M, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tf), func(t *testing.T) {
+			start, err := BucketStart(ts, tt.tf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, start)
+		})
+	}
+}
+
+func TestNextOpenTime(t *testing.T) {
+	ts := time.Date(2024, 1, 31, 13, 47, 0, 0, time.UTC)
+
+	next, err := NextOpenTime(ts, Timeframe4h)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 31, 16, 0, 0, 0, time.UTC), next)
+
+	next, err = NextOpenTime(ts, Timeframe1M)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), next)
+
+	_, err = NextOpenTime(ts, Timeframe("7m"))
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ type Timeframe string
 
 const (
 	// 支持的时间刻度
+	Timeframe1s  Timeframe = "1s"  // 1秒（币安现货，用于K线内撮合回测）
 	Timeframe1m  Timeframe = "1m"  // 1分钟
 	Timeframe3m  Timeframe = "3m"  // 3分钟
 	Timeframe5m  Timeframe = "5m"  // 5分钟
@@ -30,6 +31,8 @@ const (
 // GetDuration 获取时间刻度对应的Duration
 func (tf Timeframe) GetDuration() (time.Duration, error) {
 	switch tf {
+	case Timeframe1s:
+		return time.Second, nil
 	case Timeframe1m:
 		return time.Minute, nil
 	case Timeframe3m:
@@ -88,6 +91,7 @@ func (tf Timeframe) IsValid() bool {
 // GetAllTimeframes 获取所有支持的时间刻度
 func GetAllTimeframes() []Timeframe {
 	return []Timeframe{
+		Timeframe1s,
 		Timeframe1m,
 		Timeframe3m,
 		Timeframe5m,
@@ -124,6 +128,8 @@ func (tf Timeframe) GetBinanceInterval() string {
 // GetMaxHistoryDays 获取该时间刻度建议的最大历史数据天数
 func (tf Timeframe) GetMaxHistoryDays() int {
 	switch tf {
+	case Timeframe1s:
+		return 1 // 1秒K线数据量大，最多取1天
 	case Timeframe1m, Timeframe3m, Timeframe5m:
 		return 7 // 短周期最多取7天
 	case Timeframe15m, Timeframe30m:
@@ -14,6 +14,9 @@ func TestTimeframe_GetDuration(t *testing.T) {
 		expected  time.Duration
 		wantErr   bool
 	}{
+		// 秒
+		{"1s", Timeframe1s, time.Second, false},
+
 		// 分钟
 		{"1m", Timeframe1m, time.Minute, false},
 		{"3m", Timeframe3m, 3 * time.Minute, false},
@@ -164,7 +167,7 @@ func TestGetAllTimeframes(t *testing.T) {
 	}
 
 	// 验证数量
-	assert.Equal(t, 15, len(timeframes)) // 应该有15个时间周期
+	assert.Equal(t, 16, len(timeframes)) // 应该有16个时间周期
 }
 
 func TestParseTimeframe(t *testing.T) {
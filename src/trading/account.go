@@ -0,0 +1,184 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/i18n"
+)
+
+// AccountOverview 账户概览：非零余额、挂单和最近成交，用于实盘前核对 API 密钥和账户状态
+type AccountOverview struct {
+	Exchange   string
+	Testnet    bool
+	Time       time.Time
+	Balances   []*cex.AccountBalance // 非零余额，按资产排序
+	Pairs      []cex.TradingPair     // 查询挂单和成交的交易对
+	OpenOrders []*cex.OrderResult
+	Fills      []*cex.AccountTrade // 按时间从新到旧
+	Warnings   []string            // 单个交易对查询失败或交易所不支持的查询
+}
+
+// FetchAccountOverview 查询账户余额，再查询交易对的挂单和最近 fillLimit 笔成交；
+// pairs 为空时用非零余额的资产和 quote 组成交易对。余额查询失败时返回错误，单个交易对失败只记录警告
+func FetchAccountOverview(ctx context.Context, client cex.CEXClient, pairs []cex.TradingPair, quote string, fillLimit int) (*AccountOverview, error) {
+	balances, err := client.GetAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account balances: %w", err)
+	}
+
+	overview := &AccountOverview{
+		Exchange: client.GetName(),
+		Testnet:  cex.IsTestnet(client),
+		Time:     time.Now(),
+	}
+	for _, balance := range balances {
+		if !balance.Free.Add(balance.Locked).IsZero() {
+			overview.Balances = append(overview.Balances, balance)
+		}
+	}
+	sort.Slice(overview.Balances, func(i, j int) bool { return overview.Balances[i].Asset < overview.Balances[j].Asset })
+
+	if len(pairs) == 0 {
+		pairs = balancePairs(overview.Balances, quote)
+	}
+	overview.Pairs = pairs
+	if len(pairs) == 0 {
+		return overview, nil
+	}
+
+	orderClient, ok := client.(cex.OpenOrderClient)
+	if !ok {
+		overview.Warnings = append(overview.Warnings, fmt.Sprintf("%s does not support querying open orders", overview.Exchange))
+	}
+	tradeClient, hasTrades := client.(cex.TradeHistoryClient)
+	if !hasTrades && fillLimit > 0 {
+		overview.Warnings = append(overview.Warnings, fmt.Sprintf("%s does not support querying fills", overview.Exchange))
+	}
+
+	for _, pair := range pairs {
+		if ok {
+			orders, err := orderClient.GetOpenOrders(ctx, pair)
+			if err != nil {
+				overview.Warnings = append(overview.Warnings, fmt.Sprintf("%s open orders: %v", pair.String(), err))
+			}
+			overview.OpenOrders = append(overview.OpenOrders, orders...)
+		}
+		if hasTrades && fillLimit > 0 {
+			trades, err := tradeClient.GetRecentTrades(ctx, pair, fillLimit)
+			if err != nil {
+				overview.Warnings = append(overview.Warnings, fmt.Sprintf("%s fills: %v", pair.String(), err))
+			}
+			overview.Fills = append(overview.Fills, trades...)
+		}
+	}
+	sort.SliceStable(overview.Fills, func(i, j int) bool { return overview.Fills[i].Time.After(overview.Fills[j].Time) })
+	return overview, nil
+}
+
+// balancePairs 非计价资产的余额与 quote 组成交易对
+func balancePairs(balances []*cex.AccountBalance, quote string) []cex.TradingPair {
+	if quote == "" {
+		return nil
+	}
+	var pairs []cex.TradingPair
+	for _, balance := range balances {
+		if !strings.EqualFold(balance.Asset, quote) {
+			pairs = append(pairs, CreateTradingPair(balance.Asset, quote))
+		}
+	}
+	return pairs
+}
+
+// WriteAccountOverview 以表格输出账户概览
+func WriteAccountOverview(w io.Writer, overview *AccountOverview) {
+	exchange := overview.Exchange
+	if overview.Testnet {
+		exchange += " (TESTNET)"
+	}
+	fmt.Fprintln(w, i18n.T("account.title", exchange, overview.Time.UTC().Format("2006-01-02 15:04:05")))
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, i18n.T("account.balances", len(overview.Balances)))
+	if len(overview.Balances) > 0 {
+		rows := make([][]string, 0, len(overview.Balances))
+		for _, balance := range overview.Balances {
+			rows = append(rows, []string{balance.Asset, balance.Free.String(), balance.Locked.String(), balance.Free.Add(balance.Locked).String()})
+		}
+		WriteTable(w, []TableColumn{
+			{Header: i18n.T("account.col.asset")},
+			{Header: i18n.T("account.col.free"), Right: true},
+			{Header: i18n.T("account.col.locked"), Right: true},
+			{Header: i18n.T("account.col.total"), Right: true},
+		}, rows)
+	}
+
+	if len(overview.Pairs) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, i18n.T("account.no_pairs"))
+	} else {
+		symbols := make([]string, len(overview.Pairs))
+		for i, pair := range overview.Pairs {
+			symbols[i] = pair.String()
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, i18n.T("account.open_orders", len(overview.OpenOrders), strings.Join(symbols, ", ")))
+		if len(overview.OpenOrders) > 0 {
+			rows := make([][]string, 0, len(overview.OpenOrders))
+			for _, order := range overview.OpenOrders {
+				rows = append(rows, []string{
+					order.TradingPair.String(), string(order.Side), string(order.Type), order.Price.String(),
+					order.OrigQuantity.String(), order.Quantity.String(), order.Status, order.OrderID,
+				})
+			}
+			WriteTable(w, []TableColumn{
+				{Header: i18n.T("account.col.symbol")},
+				{Header: i18n.T("account.col.side")},
+				{Header: i18n.T("account.col.type")},
+				{Header: i18n.T("account.col.price"), Right: true},
+				{Header: i18n.T("account.col.quantity"), Right: true},
+				{Header: i18n.T("account.col.filled"), Right: true},
+				{Header: i18n.T("account.col.status")},
+				{Header: i18n.T("account.col.order_id")},
+			}, rows)
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, i18n.T("account.fills", len(overview.Fills)))
+		if len(overview.Fills) > 0 {
+			rows := make([][]string, 0, len(overview.Fills))
+			for _, fill := range overview.Fills {
+				role := "taker"
+				if fill.IsMaker {
+					role = "maker"
+				}
+				rows = append(rows, []string{
+					fill.Time.UTC().Format("2006-01-02 15:04:05"), fill.TradingPair.String(), string(fill.Side),
+					fill.Price.String(), fill.Quantity.String(), fill.QuoteQuantity.String(),
+					strings.TrimSpace(fill.Commission.String() + " " + fill.CommissionAsset), role, fill.OrderID,
+				})
+			}
+			WriteTable(w, []TableColumn{
+				{Header: i18n.T("account.col.time")},
+				{Header: i18n.T("account.col.symbol")},
+				{Header: i18n.T("account.col.side")},
+				{Header: i18n.T("account.col.price"), Right: true},
+				{Header: i18n.T("account.col.quantity"), Right: true},
+				{Header: i18n.T("account.col.quote"), Right: true},
+				{Header: i18n.T("account.col.fee"), Right: true},
+				{Header: i18n.T("account.col.role")},
+				{Header: i18n.T("account.col.order_id")},
+			}, rows)
+		}
+	}
+
+	for _, warning := range overview.Warnings {
+		fmt.Fprintln(w, i18n.T("account.warning", warning))
+	}
+}
@@ -0,0 +1,120 @@
+package trading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAccountClient 返回固定余额、挂单和成交的交易所mock
+type mockAccountClient struct {
+	cex.CEXClient
+	balances []*cex.AccountBalance
+	orders   map[string][]*cex.OrderResult
+	trades   map[string][]*cex.AccountTrade
+	limits   []int
+}
+
+func (m *mockAccountClient) GetName() string {
+	return "mock"
+}
+
+func (m *mockAccountClient) GetAccount(ctx context.Context) ([]*cex.AccountBalance, error) {
+	return m.balances, nil
+}
+
+func (m *mockAccountClient) GetOpenOrders(ctx context.Context, pair cex.TradingPair) ([]*cex.OrderResult, error) {
+	orders, ok := m.orders[pair.String()]
+	if !ok {
+		return nil, errors.New("invalid symbol")
+	}
+	return orders, nil
+}
+
+func (m *mockAccountClient) GetOrder(ctx context.Context, pair cex.TradingPair, orderID string) (*cex.OrderResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAccountClient) GetRecentTrades(ctx context.Context, pair cex.TradingPair, limit int) ([]*cex.AccountTrade, error) {
+	m.limits = append(m.limits, limit)
+	return m.trades[pair.String()], nil
+}
+
+func TestFetchAccountOverview(t *testing.T) {
+	btc := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &mockAccountClient{
+		balances: []*cex.AccountBalance{
+			{Asset: "USDT", Free: decimal.NewFromInt(500), Locked: decimal.NewFromInt(100)},
+			{Asset: "ETH", Free: decimal.Zero, Locked: decimal.Zero},
+			{Asset: "LDBTC", Free: decimal.NewFromInt(1), Locked: decimal.Zero},
+			{Asset: "BTC", Free: decimal.RequireFromString("0.01"), Locked: decimal.Zero},
+		},
+		orders: map[string][]*cex.OrderResult{
+			"BTC/USDT": {{TradingPair: btc, OrderID: "1", Side: cex.OrderSideBuy, Type: cex.OrderTypeLimit, Price: decimal.NewFromInt(40000),
+				OrigQuantity: decimal.RequireFromString("0.0025"), Quantity: decimal.Zero, Status: cex.OrderStatusNew}},
+		},
+		trades: map[string][]*cex.AccountTrade{
+			"BTC/USDT": {
+				{TradingPair: btc, OrderID: "7", Side: cex.OrderSideBuy, Price: decimal.NewFromInt(42000), Quantity: decimal.RequireFromString("0.01"),
+					QuoteQuantity: decimal.NewFromInt(420), Commission: decimal.RequireFromString("0.00001"), CommissionAsset: "BTC", Time: t0},
+				{TradingPair: btc, OrderID: "8", Side: cex.OrderSideSell, Time: t0.Add(time.Hour), IsMaker: true},
+			},
+		},
+	}
+
+	// 未指定交易对时按非零余额推断，零余额资产不显示
+	overview, err := FetchAccountOverview(context.Background(), client, nil, "USDT", 5)
+	require.NoError(t, err)
+	assert.Equal(t, "mock", overview.Exchange)
+	require.Len(t, overview.Balances, 3)
+	assert.Equal(t, "BTC", overview.Balances[0].Asset)
+	assert.Equal(t, []cex.TradingPair{btc, {Base: "LDBTC", Quote: "USDT"}}, overview.Pairs)
+	require.Len(t, overview.OpenOrders, 1)
+	require.Len(t, overview.Fills, 2)
+	assert.Equal(t, "8", overview.Fills[0].OrderID) // 从新到旧
+	assert.Equal(t, []int{5, 5}, client.limits)
+
+	// 单个交易对查询失败只记录警告
+	require.Len(t, overview.Warnings, 1)
+	assert.Contains(t, overview.Warnings[0], "LDBTC/USDT open orders: invalid symbol")
+
+	var buf bytes.Buffer
+	WriteAccountOverview(&buf, overview)
+	output := buf.String()
+	assert.Contains(t, output, "600")
+	assert.Contains(t, output, "0.0025")
+	assert.Contains(t, output, "0.00001 BTC")
+	assert.Contains(t, output, "maker")
+	assert.Contains(t, output, "invalid symbol")
+}
+
+func TestFetchAccountOverview_ExplicitPairs(t *testing.T) {
+	client := &mockAccountClient{
+		balances: []*cex.AccountBalance{{Asset: "USDT", Free: decimal.NewFromInt(10)}},
+		orders:   map[string][]*cex.OrderResult{"ETH/USDT": {}},
+	}
+	pairs := []cex.TradingPair{{Base: "ETH", Quote: "USDT"}}
+
+	overview, err := FetchAccountOverview(context.Background(), client, pairs, "USDT", 0)
+	require.NoError(t, err)
+	assert.Equal(t, pairs, overview.Pairs)
+	assert.Empty(t, overview.Warnings)
+	assert.Empty(t, client.limits) // fillLimit 为 0 时不查询成交
+
+	// 只有计价资产时没有可查询的交易对
+	overview, err = FetchAccountOverview(context.Background(), client, nil, "USDT", 5)
+	require.NoError(t, err)
+	assert.Empty(t, overview.Pairs)
+	var buf bytes.Buffer
+	WriteAccountOverview(&buf, overview)
+	assert.Contains(t, buf.String(), "-pairs")
+}
@@ -0,0 +1,160 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/i18n"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// usdStablecoins 视为与美元 1:1 的计价货币
+var usdStablecoins = map[string]bool{
+	"USD": true, "USDT": true, "USDC": true, "BUSD": true, "FDUSD": true, "TUSD": true, "DAI": true,
+}
+
+// SameAccountingValue 计价货币与记账货币是否无需换算（相同，或都是美元稳定币）
+func SameAccountingValue(quote, currency string) bool {
+	quote, currency = strings.ToUpper(quote), strings.ToUpper(currency)
+	return quote == currency || (usdStablecoins[quote] && usdStablecoins[currency])
+}
+
+// ConversionPair 把计价货币换算为记账货币使用的交易对（如 BTC 计价、USDT 记账使用 BTC/USDT）
+func ConversionPair(quote, currency string) cex.TradingPair {
+	return cex.TradingPair{Base: strings.ToUpper(quote), Quote: strings.ToUpper(currency)}
+}
+
+// ConversionRates 计价货币到记账货币的汇率，按换算交易对的K线收盘价查询；没有K线时汇率为1
+type ConversionRates struct {
+	klines []*cex.KlineData
+}
+
+// NewConversionRates 由换算交易对的K线（按时间升序）创建汇率表
+func NewConversionRates(klines []*cex.KlineData) *ConversionRates {
+	return &ConversionRates{klines: klines}
+}
+
+// RateAt 时间 t 的汇率：t 之前最后一根已收盘K线的收盘价，早于所有K线时使用第一根
+func (r *ConversionRates) RateAt(t time.Time) decimal.Decimal {
+	if len(r.klines) == 0 {
+		return decimal.NewFromInt(1)
+	}
+	i := sort.Search(len(r.klines), func(i int) bool { return r.klines[i].CloseTime.After(t) })
+	if i == 0 {
+		return r.klines[0].Close
+	}
+	return r.klines[i-1].Close
+}
+
+// AccountingSummary 换算为记账货币的回测结果，不同计价货币的回测可以直接比较
+// 已实现盈亏按每笔卖出时的汇率换算，未实现盈亏和期末价值按期末汇率换算，差额为汇率变动带来的盈亏
+type AccountingSummary struct {
+	Currency           string          `json:"currency"`             // 记账货币
+	Pair               string          `json:"pair,omitempty"`       // 换算交易对，无需换算时为空
+	StartRate          decimal.Decimal `json:"start_rate"`           // 期初汇率（1 计价货币 = ? 记账货币）
+	EndRate            decimal.Decimal `json:"end_rate"`             // 期末汇率
+	InitialCapital     decimal.Decimal `json:"initial_capital"`      // 期初资金
+	FinalPortfolio     decimal.Decimal `json:"final_portfolio"`      // 期末组合价值
+	TotalReturn        decimal.Decimal `json:"total_return"`         // 总收益率
+	RealizedPnL        decimal.Decimal `json:"realized_pnl"`         // 已实现盈亏
+	UnrealizedPnL      decimal.Decimal `json:"unrealized_pnl"`       // 未实现盈亏
+	CurrencyPnL        decimal.Decimal `json:"currency_pnl"`         // 汇率变动带来的盈亏
+	MaxDrawdownPercent decimal.Decimal `json:"max_drawdown_percent"` // 按换算后的资金曲线计算的最大回撤（%）
+}
+
+// ConvertBacktestStatistics 把回测统计和资金曲线换算为记账货币
+func ConvertBacktestStatistics(stats *BacktestStatistics, equity []engine.EquityPoint, rates *ConversionRates, currency string, startTime, endTime time.Time) *AccountingSummary {
+	summary := &AccountingSummary{
+		Currency:           strings.ToUpper(currency),
+		StartRate:          rates.RateAt(startTime),
+		EndRate:            rates.RateAt(endTime),
+		RealizedPnL:        decimal.Zero,
+		MaxDrawdownPercent: decimal.Zero,
+	}
+	if len(equity) > 0 {
+		summary.StartRate = rates.RateAt(equity[0].Timestamp)
+		summary.EndRate = rates.RateAt(equity[len(equity)-1].Timestamp)
+	}
+
+	summary.InitialCapital = stats.InitialCapital.Mul(summary.StartRate)
+	summary.FinalPortfolio = stats.FinalPortfolio.Mul(summary.EndRate)
+	if summary.InitialCapital.IsPositive() {
+		summary.TotalReturn = summary.FinalPortfolio.Sub(summary.InitialCapital).Div(summary.InitialCapital)
+	}
+
+	for _, trade := range stats.Trades {
+		if trade.SellOrder != nil {
+			summary.RealizedPnL = summary.RealizedPnL.Add(trade.PnL.Mul(rates.RateAt(trade.SellOrder.Timestamp)))
+		}
+	}
+	summary.UnrealizedPnL = stats.UnrealizedPnL.Mul(summary.EndRate)
+	summary.CurrencyPnL = summary.FinalPortfolio.Sub(summary.InitialCapital).Sub(summary.RealizedPnL).Sub(summary.UnrealizedPnL)
+
+	peak := decimal.Zero
+	for _, point := range equity {
+		value := point.PortfolioValue.Mul(rates.RateAt(point.Timestamp))
+		if value.GreaterThan(peak) {
+			peak = value
+		}
+		if peak.IsPositive() {
+			if drawdown := peak.Sub(value).Div(peak).Mul(decimal.NewFromInt(100)); drawdown.GreaterThan(summary.MaxDrawdownPercent) {
+				summary.MaxDrawdownPercent = drawdown
+			}
+		}
+	}
+	return summary
+}
+
+// convertToAccountingCurrency 按配置的记账货币换算回测结果，未配置时返回 nil
+func (ts *TradingSystem) convertToAccountingCurrency(pair cex.TradingPair, stats *BacktestStatistics, equity []engine.EquityPoint, startTime, endTime time.Time) (*AccountingSummary, error) {
+	currency := TradingConfigValue.AccountingCurrency
+	if currency == "" {
+		return nil, nil
+	}
+
+	rates := NewConversionRates(nil)
+	conversionPair := ""
+	if !SameAccountingValue(pair.Quote, currency) {
+		timeframe, err := timeframes.ParseTimeframe(ts.Timeframe())
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeframe: %w", err)
+		}
+		convPair := ConversionPair(pair.Quote, currency)
+		klines, err := ts.LoadBacktestKlines(convPair, timeframe, startTime, endTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s klines for accounting currency %s: %w", convPair.String(), currency, err)
+		}
+		rates = NewConversionRates(klines)
+		conversionPair = convPair.String()
+	}
+
+	summary := ConvertBacktestStatistics(stats, equity, rates, currency, startTime, endTime)
+	summary.Pair = conversionPair
+	return summary, nil
+}
+
+// printAccountingSummary 打印换算为记账货币的回测结果
+func printAccountingSummary(summary *AccountingSummary) {
+	if summary == nil {
+		return
+	}
+
+	fmt.Println("\n" + i18n.T("report.accounting", summary.Currency))
+	fmt.Println("------------------------------")
+	if summary.Pair != "" {
+		fmt.Println(i18n.T("report.conversion", summary.Pair, summary.StartRate.String(), summary.EndRate.String()))
+	}
+	fmt.Println(i18n.T("report.acc_initial", summary.InitialCapital.InexactFloat64(), summary.Currency))
+	fmt.Println(i18n.T("report.acc_final", summary.FinalPortfolio.InexactFloat64(), summary.Currency))
+	fmt.Println(i18n.T("report.acc_total_return", summary.TotalReturn.Mul(decimal.NewFromInt(100)).InexactFloat64()))
+	fmt.Println(i18n.T("report.acc_realized", summary.RealizedPnL.InexactFloat64(), summary.Currency))
+	fmt.Println(i18n.T("report.acc_unrealized", summary.UnrealizedPnL.InexactFloat64(), summary.Currency))
+	fmt.Println(i18n.T("report.acc_currency_pnl", summary.CurrencyPnL.InexactFloat64(), summary.Currency))
+	fmt.Println(i18n.T("report.acc_max_drawdown", summary.MaxDrawdownPercent.InexactFloat64()))
+}
@@ -0,0 +1,67 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameAccountingValue(t *testing.T) {
+	assert.True(t, SameAccountingValue("USDT", "usdt"))
+	assert.True(t, SameAccountingValue("FDUSD", "USDT"))
+	assert.False(t, SameAccountingValue("BTC", "USDT"))
+	assert.Equal(t, "BTC/USDT", ConversionPair("btc", "usdt").String())
+}
+
+func TestConvertBacktestStatistics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	// BTCUSDT 从 40000 涨到 50000
+	var btcusdt []*cex.KlineData
+	for i, price := range []int64{40000, 45000, 50000} {
+		btcusdt = append(btcusdt, &cex.KlineData{CloseTime: at(i * 4), Close: decimal.NewFromInt(price)})
+	}
+	rates := NewConversionRates(btcusdt)
+	assert.Equal(t, "40000", rates.RateAt(start.Add(-time.Hour)).String())
+	assert.Equal(t, "45000", rates.RateAt(at(6)).String())
+
+	// 以 BTC 计价：1 BTC 期初，期末 1.1 BTC（已实现 0.05，未实现 0.05）
+	stats := &BacktestStatistics{
+		InitialCapital: decimal.NewFromInt(1),
+		FinalPortfolio: decimal.NewFromFloat(1.1),
+		UnrealizedPnL:  decimal.NewFromFloat(0.05),
+		Trades: []TradeAnalysis{{
+			PnL:       decimal.NewFromFloat(0.05),
+			SellOrder: &executor.OrderResult{Timestamp: at(4)},
+		}},
+	}
+	equity := []engine.EquityPoint{
+		{Timestamp: at(0), PortfolioValue: decimal.NewFromInt(1)},
+		{Timestamp: at(4), PortfolioValue: decimal.NewFromFloat(0.8)},
+		{Timestamp: at(8), PortfolioValue: decimal.NewFromFloat(1.1)},
+	}
+
+	summary := ConvertBacktestStatistics(stats, equity, rates, "usdt", at(0), at(8))
+	assert.Equal(t, "USDT", summary.Currency)
+	assert.Equal(t, "40000", summary.InitialCapital.String())
+	assert.Equal(t, "55000", summary.FinalPortfolio.String())
+	assert.Equal(t, "0.375", summary.TotalReturn.String())
+	assert.Equal(t, "2250", summary.RealizedPnL.String())
+	assert.Equal(t, "2500", summary.UnrealizedPnL.String())
+	// 其余为 BTC 上涨带来的盈亏
+	assert.Equal(t, "10250", summary.CurrencyPnL.String())
+	// 40000 → 36000：换算后回撤 10%（以 BTC 计为 20%）
+	assert.Equal(t, "10", summary.MaxDrawdownPercent.String())
+
+	// 无需换算时汇率为 1
+	same := ConvertBacktestStatistics(stats, equity, NewConversionRates(nil), "BTC", at(0), at(8))
+	assert.True(t, same.FinalPortfolio.Equal(stats.FinalPortfolio))
+	assert.True(t, same.CurrencyPnL.IsZero())
+}
@@ -0,0 +1,42 @@
+package trading
+
+import (
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// MonthlyAttribution 单月收益归因
+type MonthlyAttribution struct {
+	Month           string          `json:"month"`            // 月份（UTC），如 2024-01
+	StrategyReturn  decimal.Decimal `json:"strategy_return"`  // 策略当月收益率
+	MarketReturn    decimal.Decimal `json:"market_return"`    // 买入持有基准当月收益率
+	MarketComponent decimal.Decimal `json:"market_component"` // 市场贡献 = Beta × 基准收益率
+	Alpha           decimal.Decimal `json:"alpha"`            // 择时/选择超额收益 = 策略收益 - 市场贡献
+}
+
+// ReturnAttribution 收益归因：区分市场Beta与策略Alpha
+type ReturnAttribution struct {
+	Beta            decimal.Decimal      `json:"beta"`             // 策略相对买入持有基准的Beta（逐根K线收益回归）
+	MarketReturn    decimal.Decimal      `json:"market_return"`    // 买入持有基准总收益率
+	MarketComponent decimal.Decimal      `json:"market_component"` // 总收益中的市场贡献
+	Alpha           decimal.Decimal      `json:"alpha"`            // 总收益中的策略超额收益
+	Monthly         []MonthlyAttribution `json:"monthly"`          // 按月归因
+}
+
+// CalculateReturnAttribution 以买入持有为基准，将回测收益分解为市场贡献和策略Alpha
+func CalculateReturnAttribution(orders []executor.OrderResult, klines []*cex.KlineData, initialCapital decimal.Decimal) ReturnAttribution {
+	tracker := newAttributionTracker(orders, initialCapital)
+	for _, kline := range klines {
+		tracker.add(kline)
+	}
+	return tracker.result()
+}
+
+// monthKey 获取时间对应的月份键（UTC）
+func monthKey(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
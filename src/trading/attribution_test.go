@@ -0,0 +1,79 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateReturnAttribution(t *testing.T) {
+	baseTime := time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)
+	closes := []float64{101, 103, 99, 104, 108, 105, 110}
+	klines := make([]*cex.KlineData, len(closes))
+	prevClose := 100.0
+	for i, c := range closes {
+		klines[i] = &cex.KlineData{
+			OpenTime:  baseTime.Add(time.Duration(i) * 24 * time.Hour),
+			CloseTime: baseTime.Add(time.Duration(i+1)*24*time.Hour - time.Millisecond),
+			Open:      decimal.NewFromFloat(prevClose),
+			Close:     decimal.NewFromFloat(c),
+		}
+		prevClose = c
+	}
+	initialCapital := decimal.NewFromFloat(10000)
+
+	t.Run("buy and hold is all market", func(t *testing.T) {
+		orders := []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Price: decimal.NewFromFloat(100), Quantity: decimal.NewFromFloat(100), Timestamp: baseTime},
+		}
+		attribution := CalculateReturnAttribution(orders, klines, initialCapital)
+
+		assert.InDelta(t, 1.0, attribution.Beta.InexactFloat64(), 1e-9)
+		assert.InDelta(t, 0.10, attribution.MarketReturn.InexactFloat64(), 1e-9)
+		assert.InDelta(t, 0.10, attribution.MarketComponent.InexactFloat64(), 1e-9)
+		assert.InDelta(t, 0.0, attribution.Alpha.InexactFloat64(), 1e-9)
+
+		// 1月29日-31日 与 2月1日-4日
+		require.Len(t, attribution.Monthly, 2)
+		assert.Equal(t, "2024-01", attribution.Monthly[0].Month)
+		assert.Equal(t, "2024-02", attribution.Monthly[1].Month)
+		assert.InDelta(t, -0.01, attribution.Monthly[0].MarketReturn.InexactFloat64(), 1e-9)
+		assert.InDelta(t, 110.0/99-1, attribution.Monthly[1].MarketReturn.InexactFloat64(), 1e-9)
+		for _, monthly := range attribution.Monthly {
+			assert.InDelta(t, 0.0, monthly.Alpha.InexactFloat64(), 1e-9)
+		}
+	})
+
+	t.Run("cash only has no market exposure", func(t *testing.T) {
+		attribution := CalculateReturnAttribution(nil, klines, initialCapital)
+
+		assert.True(t, attribution.Beta.IsZero())
+		assert.True(t, attribution.MarketComponent.IsZero())
+		assert.True(t, attribution.Alpha.IsZero())
+		assert.InDelta(t, 0.10, attribution.MarketReturn.InexactFloat64(), 1e-9)
+	})
+
+	t.Run("sitting out a down month is alpha", func(t *testing.T) {
+		// 只在2月持仓，避开1月的下跌
+		orders := []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Price: decimal.NewFromFloat(99), Quantity: decimal.NewFromFloat(100), Timestamp: klines[2].CloseTime},
+		}
+		attribution := CalculateReturnAttribution(orders, klines, initialCapital)
+
+		require.Len(t, attribution.Monthly, 2)
+		assert.True(t, attribution.Monthly[0].StrategyReturn.IsZero())
+		assert.True(t, attribution.Monthly[0].Alpha.IsPositive())
+	})
+
+	t.Run("no klines", func(t *testing.T) {
+		attribution := CalculateReturnAttribution(nil, nil, initialCapital)
+		assert.Empty(t, attribution.Monthly)
+		assert.True(t, attribution.Beta.IsZero())
+	})
+}
@@ -0,0 +1,30 @@
+package trading
+
+import (
+	"fmt"
+
+	"tradingbot/src/logging"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// openAuditLog 按日志配置打开审计日志（实盘订单、配置热更新），交易系统停止时关闭（未配置文件时返回 nil）
+func (ts *TradingSystem) openAuditLog() (*logging.AuditLog, error) {
+	path := logging.ConfigValue.AuditFile
+	if path == "" {
+		return nil, nil
+	}
+
+	auditLog, err := logging.OpenAuditLog(path)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ts.ctx.Done()
+		_ = auditLog.Close()
+	}()
+
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 审计日志: file=%s", path))
+	return auditLog, nil
+}
@@ -0,0 +1,241 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+)
+
+// BacktestResult 回测结果文件：运行记录、逐笔成交和资金曲线（-result-out 写出，backtests compare 读取）
+type BacktestResult struct {
+	Run    *database.BacktestRun   `json:"run"`
+	Trades []*database.TradeRecord `json:"trades"`
+	Equity []engine.EquityPoint    `json:"equity"`
+
+	// 按月、按年收益（只有结果文件包含，数据库中的回测为空）
+	Returns *PeriodReturns `json:"returns,omitempty"`
+}
+
+// BacktestRunStore 回测记录查询（由 database.PostgresDB 实现）
+type BacktestRunStore interface {
+	GetBacktestRun(ctx context.Context, id string) (*database.BacktestRun, error)
+	GetTrades(ctx context.Context, backtestRunID string) ([]*database.TradeRecord, error)
+}
+
+// SetBacktestResultFile 设置回测结果文件，回测完成后写出运行记录、逐笔成交和资金曲线（为空时不写）
+func (ts *TradingSystem) SetBacktestResultFile(path string) {
+	ts.resultFile = path
+}
+
+// newBacktestResult 将回测统计和资金曲线转换为结果文件内容
+func newBacktestResult(pair cex.TradingPair, timeframe, strategyName string, params strategy.StrategyParams, startTime, endTime time.Time, stats *BacktestStatistics, equity []engine.EquityPoint) (*BacktestResult, error) {
+	run, err := buildBacktestRun(pair, timeframe, strategyName, params, startTime, endTime, stats)
+	if err != nil {
+		return nil, err
+	}
+	run.ID = stats.RunID
+	run.CreatedAt = *run.CompletedAt
+	if equity == nil {
+		equity = []engine.EquityPoint{}
+	}
+	return &BacktestResult{Run: run, Trades: buildTradeRecords(run.ID, pair, stats), Equity: equity, Returns: &stats.Returns}, nil
+}
+
+// WriteBacktestResultFile 写出回测结果文件（JSON）
+func WriteBacktestResultFile(path string, result *BacktestResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backtest result: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write backtest result: %w", err)
+	}
+	return nil
+}
+
+// LoadBacktestResultFile 读取回测结果文件
+func LoadBacktestResultFile(path string) (*BacktestResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backtest result: %w", err)
+	}
+	var result BacktestResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse backtest result %s: %w", path, err)
+	}
+	if result.Run == nil {
+		return nil, fmt.Errorf("backtest result %s has no run", path)
+	}
+	return &result, nil
+}
+
+// LoadBacktestResult 从数据库读取已保存的回测
+// 数据库不保存逐K线资金曲线，使用与面板相同的按已实现盈亏累计的资金曲线
+func LoadBacktestResult(ctx context.Context, store BacktestRunStore, id string) (*BacktestResult, error) {
+	run, err := store.GetBacktestRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	trades, err := store.GetTrades(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := dashboard.BacktestEquityCurve(run, trades)
+	equity := make([]engine.EquityPoint, len(steps))
+	for i, step := range steps {
+		equity[i] = engine.EquityPoint{Timestamp: step.Time, Cash: step.Equity, PortfolioValue: step.Equity}
+	}
+	return &BacktestResult{Run: run, Trades: trades, Equity: equity}, nil
+}
+
+// ParamChange 两次回测之间变化的策略参数（只在一次回测中出现的参数另一侧为 nil）
+type ParamChange struct {
+	Name     string      `json:"name"`
+	Previous interface{} `json:"previous"`
+	Current  interface{} `json:"current"`
+}
+
+// BacktestComparison 两次回测的对比
+type BacktestComparison struct {
+	Metrics  []MetricDiff  `json:"metrics"`
+	Params   []ParamChange `json:"params"`
+	Warnings []string      `json:"warnings"` // 交易对、周期或回测区间不同，对比可能没有意义
+}
+
+// Improved 变好的指标名称
+func (c *BacktestComparison) Improved() []string {
+	return c.metricNames(1)
+}
+
+// Worsened 变差的指标名称
+func (c *BacktestComparison) Worsened() []string {
+	return c.metricNames(-1)
+}
+
+// metricNames 按改善方向筛选指标名称
+func (c *BacktestComparison) metricNames(improvement int) []string {
+	var names []string
+	for _, metric := range c.Metrics {
+		if metric.Improvement() == improvement {
+			names = append(names, metric.Name)
+		}
+	}
+	return names
+}
+
+// runMetrics 从回测记录提取对比指标（顺序即展示顺序）
+func runMetrics(run *database.BacktestRun) []MetricDiff {
+	return []MetricDiff{
+		{Name: "Total Return", Unit: "%", Current: run.TotalReturn.InexactFloat64() * 100, Better: BetterHigher},
+		{Name: "Max Drawdown", Unit: "%", Current: run.MaxDrawdown.InexactFloat64(), Better: BetterLower},
+		{Name: "Sharpe Ratio", Current: run.SharpeRatio.InexactFloat64(), Better: BetterHigher},
+		{Name: "Final Capital", Current: run.FinalCapital.InexactFloat64(), Better: BetterHigher},
+		{Name: "Trades", Current: float64(run.TotalTrades)},
+		{Name: "Winning Trades", Current: float64(run.WinningTrades), Better: BetterHigher},
+		{Name: "Losing Trades", Current: float64(run.LosingTrades), Better: BetterLower},
+		{Name: "Win Rate", Unit: "%", Current: run.WinRate.InexactFloat64() * 100, Better: BetterHigher},
+		{Name: "Commission", Current: run.TotalCommission.InexactFloat64(), Better: BetterLower},
+	}
+}
+
+// CompareBacktestResults 对比两次回测（previous 为基准）的指标和策略参数
+func CompareBacktestResults(previous, current *BacktestResult) *BacktestComparison {
+	comparison := &BacktestComparison{Metrics: runMetrics(current.Run)}
+	prevMetrics := runMetrics(previous.Run)
+	for i := range comparison.Metrics {
+		comparison.Metrics[i].Previous = prevMetrics[i].Current
+		comparison.Metrics[i].Delta = comparison.Metrics[i].Current - comparison.Metrics[i].Previous
+	}
+
+	names := make(map[string]bool)
+	for name := range previous.Run.StrategyParams {
+		names[name] = true
+	}
+	for name := range current.Run.StrategyParams {
+		names[name] = true
+	}
+	for name := range names {
+		prev, cur := previous.Run.StrategyParams[name], current.Run.StrategyParams[name]
+		if !reflect.DeepEqual(prev, cur) {
+			comparison.Params = append(comparison.Params, ParamChange{Name: name, Previous: prev, Current: cur})
+		}
+	}
+	sort.Slice(comparison.Params, func(i, j int) bool { return comparison.Params[i].Name < comparison.Params[j].Name })
+
+	if previous.Run.Symbol != current.Run.Symbol {
+		comparison.Warnings = append(comparison.Warnings, fmt.Sprintf("symbols differ: %s vs %s", previous.Run.Symbol, current.Run.Symbol))
+	}
+	if previous.Run.Timeframe != current.Run.Timeframe {
+		comparison.Warnings = append(comparison.Warnings, fmt.Sprintf("timeframes differ: %s vs %s", previous.Run.Timeframe, current.Run.Timeframe))
+	}
+	if !previous.Run.StartTime.Equal(current.Run.StartTime) || !previous.Run.EndTime.Equal(current.Run.EndTime) {
+		comparison.Warnings = append(comparison.Warnings, "backtest periods differ")
+	}
+	return comparison
+}
+
+// MergeEquityCurves 把两条资金曲线按相同时间刻度对齐，返回各刻度时间和相对各自初始资金的收益率（%）
+// 刻度均分两条曲线覆盖的整个区间，每个刻度取该时间及之前的最后一个点，曲线尚未开始的刻度为 NaN
+func MergeEquityCurves(a, b []engine.EquityPoint, columns int) ([]time.Time, []float64, []float64) {
+	if columns < 2 || (len(a) == 0 && len(b) == 0) {
+		return nil, nil, nil
+	}
+
+	start, end := curveRange(a, b)
+	step := end.Sub(start) / time.Duration(columns-1)
+	times := make([]time.Time, columns)
+	for i := range times {
+		times[i] = start.Add(step * time.Duration(i))
+	}
+	times[columns-1] = end
+
+	return times, resampleReturns(a, times), resampleReturns(b, times)
+}
+
+// curveRange 两条曲线覆盖的时间区间
+func curveRange(curves ...[]engine.EquityPoint) (time.Time, time.Time) {
+	var start, end time.Time
+	for _, curve := range curves {
+		if len(curve) == 0 {
+			continue
+		}
+		if start.IsZero() || curve[0].Timestamp.Before(start) {
+			start = curve[0].Timestamp
+		}
+		if last := curve[len(curve)-1].Timestamp; last.After(end) {
+			end = last
+		}
+	}
+	return start, end
+}
+
+// resampleReturns 按刻度取收益率（%），曲线为空或尚未开始时为 NaN
+func resampleReturns(curve []engine.EquityPoint, times []time.Time) []float64 {
+	returns := make([]float64, len(times))
+	index := -1
+	for i, t := range times {
+		for index+1 < len(curve) && !curve[index+1].Timestamp.After(t) {
+			index++
+		}
+		if index < 0 || curve[0].PortfolioValue.IsZero() {
+			returns[i] = math.NaN()
+			continue
+		}
+		returns[i] = curve[index].PortfolioValue.Div(curve[0].PortfolioValue).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+	return returns
+}
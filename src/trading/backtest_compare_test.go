@@ -0,0 +1,141 @@
+package trading
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBacktestRunStore 内存中的回测记录
+type memoryBacktestRunStore struct {
+	runs   map[string]*database.BacktestRun
+	trades map[string][]*database.TradeRecord
+}
+
+func (s *memoryBacktestRunStore) GetBacktestRun(ctx context.Context, id string) (*database.BacktestRun, error) {
+	run, ok := s.runs[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return run, nil
+}
+
+func (s *memoryBacktestRunStore) GetTrades(ctx context.Context, backtestRunID string) ([]*database.TradeRecord, error) {
+	return s.trades[backtestRunID], nil
+}
+
+func TestLoadBacktestResult_RealizedEquityCurve(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &memoryBacktestRunStore{
+		runs: map[string]*database.BacktestRun{"run-1": {
+			ID: "run-1", StartTime: start, EndTime: start.Add(72 * time.Hour),
+			InitialCapital: decimal.NewFromInt(1000), FinalCapital: decimal.NewFromInt(1080),
+		}},
+		trades: map[string][]*database.TradeRecord{"run-1": {
+			{Side: "BUY", Timestamp: start.Add(time.Hour)},
+			{Side: "SELL", PnL: decimal.NewFromInt(100), Timestamp: start.Add(24 * time.Hour)},
+			{Side: "BUY", Timestamp: start.Add(25 * time.Hour)},
+			{Side: "SELL", PnL: decimal.NewFromInt(-20), Timestamp: start.Add(48 * time.Hour)},
+		}},
+	}
+
+	result, err := LoadBacktestResult(context.Background(), store, "run-1")
+	require.NoError(t, err)
+	require.Len(t, result.Trades, 4)
+
+	var values []string
+	for _, point := range result.Equity {
+		values = append(values, point.PortfolioValue.String())
+	}
+	assert.Equal(t, []string{"1000", "1100", "1080", "1080"}, values)
+	assert.Equal(t, start.Add(72*time.Hour), result.Equity[3].Timestamp)
+
+	_, err = LoadBacktestResult(context.Background(), store, "missing")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestCompareBacktestResults(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := &BacktestResult{Run: &database.BacktestRun{
+		Symbol: "BTCUSDT", Timeframe: "4h", StartTime: start, EndTime: start.AddDate(0, 1, 0),
+		StrategyParams: map[string]interface{}{"period": 20.0, "multiplier": 2.0, "oco": false},
+		TotalReturn:    decimal.NewFromFloat(0.10), MaxDrawdown: decimal.NewFromFloat(8),
+		TotalTrades: 10, WinningTrades: 6, LosingTrades: 4, WinRate: decimal.NewFromFloat(0.6),
+	}}
+	current := &BacktestResult{Run: &database.BacktestRun{
+		Symbol: "BTCUSDT", Timeframe: "4h", StartTime: start, EndTime: start.AddDate(0, 1, 0),
+		StrategyParams: map[string]interface{}{"period": 25.0, "multiplier": 2.0, "atr_period": 14.0},
+		TotalReturn:    decimal.NewFromFloat(0.12), MaxDrawdown: decimal.NewFromFloat(10),
+		TotalTrades: 8, WinningTrades: 6, LosingTrades: 2, WinRate: decimal.NewFromFloat(0.75),
+	}}
+
+	comparison := CompareBacktestResults(previous, current)
+	assert.Empty(t, comparison.Warnings)
+	assert.Equal(t, []ParamChange{
+		{Name: "atr_period", Current: 14.0},
+		{Name: "oco", Previous: false},
+		{Name: "period", Previous: 20.0, Current: 25.0},
+	}, comparison.Params)
+
+	assert.InDelta(t, 2.0, comparison.Metrics[0].Delta, 1e-9)
+	assert.Equal(t, []string{"Total Return", "Losing Trades", "Win Rate"}, comparison.Improved())
+	// 回撤变大是变差，交易次数减少不分好坏
+	assert.Equal(t, []string{"Max Drawdown"}, comparison.Worsened())
+
+	current.Run.Timeframe = "1h"
+	current.Run.StartTime = start.AddDate(0, 0, 1)
+	assert.Len(t, CompareBacktestResults(previous, current).Warnings, 2)
+}
+
+func TestBacktestResultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.json")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &BacktestResult{
+		Run:    &database.BacktestRun{Symbol: "BTCUSDT", StrategyParams: map[string]interface{}{"period": 20.0}, TotalReturn: decimal.NewFromFloat(0.05)},
+		Trades: []*database.TradeRecord{{Side: "BUY", Price: decimal.NewFromInt(100)}},
+		Equity: []engine.EquityPoint{{Timestamp: start, PortfolioValue: decimal.NewFromInt(1000)}},
+	}
+	require.NoError(t, WriteBacktestResultFile(path, result))
+
+	loaded, err := LoadBacktestResultFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", loaded.Run.Symbol)
+	assert.Equal(t, 20.0, loaded.Run.StrategyParams["period"])
+	assert.True(t, loaded.Run.TotalReturn.Equal(decimal.NewFromFloat(0.05)))
+	require.Len(t, loaded.Equity, 1)
+	assert.True(t, loaded.Equity[0].Timestamp.Equal(start))
+
+	_, err = LoadBacktestResultFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestMergeEquityCurves(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	point := func(hours int, value int64) engine.EquityPoint {
+		return engine.EquityPoint{Timestamp: start.Add(time.Duration(hours) * time.Hour), PortfolioValue: decimal.NewFromInt(value)}
+	}
+	a := []engine.EquityPoint{point(0, 1000), point(2, 1100), point(4, 1200)}
+	b := []engine.EquityPoint{point(2, 500), point(3, 450)}
+
+	times, returnsA, returnsB := MergeEquityCurves(a, b, 5)
+	require.Len(t, times, 5)
+	assert.Equal(t, start, times[0])
+	assert.Equal(t, start.Add(4*time.Hour), times[4])
+
+	assert.Equal(t, []float64{0, 0, 10, 10, 20}, returnsA)
+	// B 从第 2 小时开始，之前的刻度没有数据
+	assert.True(t, math.IsNaN(returnsB[0]) && math.IsNaN(returnsB[1]))
+	assert.Equal(t, []float64{0, -10, -10}, returnsB[2:])
+
+	times, _, _ = MergeEquityCurves(nil, nil, 5)
+	assert.Empty(t, times)
+}
@@ -0,0 +1,163 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxTradeReasonLength trades.reason 字段长度上限
+const maxTradeReasonLength = 100
+
+// GetPostgresDB 从 CEX 客户端获取数据库连接（未连接时返回错误）
+func GetPostgresDB(client cex.CEXClient) (*database.PostgresDB, error) {
+	if client == nil {
+		return nil, fmt.Errorf("CEX client not initialized")
+	}
+
+	db, ok := client.GetDatabase().(*database.PostgresDB)
+	if !ok || db == nil {
+		return nil, fmt.Errorf("database not available for %s", client.GetName())
+	}
+
+	return db, nil
+}
+
+// DatabaseSymbol 交易对在数据库中的标识（如 BTCUSDT）
+func DatabaseSymbol(pair cex.TradingPair) string {
+	return strings.ToUpper(pair.Base + pair.Quote)
+}
+
+// SaveBacktestResults 持久化回测结果：运行记录、策略参数、统计和逐笔成交
+func (ts *TradingSystem) SaveBacktestResults(pair cex.TradingPair, strategyName string, params strategy.StrategyParams, startTime, endTime time.Time, stats *BacktestStatistics) (string, error) {
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return "", err
+	}
+
+	run, err := buildBacktestRun(pair, ts.Timeframe(), strategyName, params, startTime, endTime, stats)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ts.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := db.SaveBacktestRun(ctx, run); err != nil {
+		return "", err
+	}
+
+	if err := db.SaveTrades(ctx, buildTradeRecords(run.ID, pair, stats)); err != nil {
+		return run.ID, fmt.Errorf("backtest run %s saved but trades failed: %w", run.ID, err)
+	}
+
+	return run.ID, nil
+}
+
+// buildBacktestRun 将回测统计转换为数据库运行记录
+func buildBacktestRun(pair cex.TradingPair, timeframe, strategyName string, params strategy.StrategyParams, startTime, endTime time.Time, stats *BacktestStatistics) (*database.BacktestRun, error) {
+	paramsMap := make(map[string]interface{})
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal strategy params: %w", err)
+		}
+		if err := json.Unmarshal(data, &paramsMap); err != nil {
+			return nil, fmt.Errorf("failed to convert strategy params: %w", err)
+		}
+	}
+
+	winRate := decimal.Zero
+	if stats.TotalTrades > 0 {
+		winRate = decimal.NewFromInt(int64(stats.WinningTrades)).Div(decimal.NewFromInt(int64(stats.TotalTrades)))
+	}
+
+	totalCommission := decimal.Zero
+	for _, order := range stats.Orders {
+		totalCommission = totalCommission.Add(order.Commission)
+	}
+
+	var manifest json.RawMessage
+	if stats.Manifest != nil {
+		data, err := json.Marshal(stats.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal run manifest: %w", err)
+		}
+		manifest = data
+	}
+
+	completedAt := time.Now()
+
+	return &database.BacktestRun{
+		Name: fmt.Sprintf("%s %s %s %s~%s", strategyName, pair.String(), timeframe,
+			startTime.Format("2006-01-02"), endTime.Format("2006-01-02")),
+		Symbol:          DatabaseSymbol(pair),
+		Timeframe:       timeframe,
+		StrategyName:    strategyName,
+		StrategyParams:  paramsMap,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		InitialCapital:  stats.InitialCapital,
+		FinalCapital:    stats.FinalPortfolio,
+		TotalReturn:     stats.TotalReturn,
+		MaxDrawdown:     stats.MaxDrawdownPercent,
+		SharpeRatio:     stats.SharpeRatio,
+		WinRate:         winRate,
+		TotalTrades:     stats.TotalTrades,
+		WinningTrades:   stats.WinningTrades,
+		LosingTrades:    stats.LosingTrades,
+		TotalCommission: totalCommission,
+		Status:          "COMPLETED",
+		CompletedAt:     &completedAt,
+		Manifest:        manifest,
+	}, nil
+}
+
+// buildTradeRecords 将回测订单转换为数据库交易记录（卖出单附带已实现盈亏，平掉多个批次时累加）
+func buildTradeRecords(runID string, pair cex.TradingPair, stats *BacktestStatistics) []*database.TradeRecord {
+	sellPnL := make(map[string]decimal.Decimal)
+	sellReason := make(map[string]string)
+	for _, trade := range stats.Trades {
+		if trade.SellOrder != nil {
+			// 一笔卖出可能平掉多个持仓批次
+			sellPnL[trade.SellOrder.OrderID] = sellPnL[trade.SellOrder.OrderID].Add(trade.PnL)
+			sellReason[trade.SellOrder.OrderID] = trade.SellReason
+		}
+	}
+
+	records := make([]*database.TradeRecord, 0, len(stats.Orders))
+	for _, order := range stats.Orders {
+		record := &database.TradeRecord{
+			BacktestRunID: runID,
+			Symbol:        DatabaseSymbol(pair),
+			Side:          string(order.Side),
+			Quantity:      order.Quantity,
+			Price:         order.Price,
+			Commission:    order.Commission,
+			Timestamp:     order.Timestamp,
+			KlineOpenTime: order.Timestamp.UnixMilli(),
+		}
+
+		if order.Side == executor.OrderSideSell {
+			record.PnL = sellPnL[order.OrderID]
+			record.Reason = sellReason[order.OrderID]
+		}
+
+		if reason := []rune(record.Reason); len(reason) > maxTradeReasonLength {
+			record.Reason = string(reason[:maxTradeReasonLength])
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
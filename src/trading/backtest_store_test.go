@@ -0,0 +1,75 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseSymbol(t *testing.T) {
+	assert.Equal(t, "PEPEUSDT", DatabaseSymbol(cex.TradingPair{Base: "pepe", Quote: "usdt"}))
+}
+
+func TestBuildBacktestRun(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	stats := &BacktestStatistics{
+		InitialCapital:     decimal.NewFromInt(10000),
+		FinalPortfolio:     decimal.NewFromInt(11000),
+		TotalReturn:        decimal.NewFromFloat(0.1),
+		TotalTrades:        4,
+		WinningTrades:      3,
+		LosingTrades:       1,
+		MaxDrawdownPercent: decimal.NewFromFloat(12.5),
+		Orders: []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Commission: decimal.NewFromFloat(1.5)},
+			{Side: executor.OrderSideSell, Commission: decimal.NewFromFloat(2)},
+		},
+	}
+
+	run, err := buildBacktestRun(pair, TradingConfigValue.Timeframe, "Bollinger Bands Strategy", strategy.GetDefaultBollingerBandsParams(), startTime, endTime, stats)
+	require.NoError(t, err)
+
+	assert.Equal(t, "BTCUSDT", run.Symbol)
+	assert.Equal(t, "COMPLETED", run.Status)
+	assert.True(t, run.WinRate.Equal(decimal.NewFromFloat(0.75)))
+	assert.True(t, run.TotalCommission.Equal(decimal.NewFromFloat(3.5)))
+	assert.True(t, run.MaxDrawdown.Equal(decimal.NewFromFloat(12.5)))
+	assert.Equal(t, float64(20), run.StrategyParams["period"])
+	assert.Equal(t, "moderate", run.StrategyParams["sell_strategy_name"])
+	assert.NotNil(t, run.CompletedAt)
+}
+
+func TestBuildTradeRecords(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	orders := []executor.OrderResult{
+		{OrderID: "b1", Side: executor.OrderSideBuy, Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(2), Timestamp: baseTime},
+		{OrderID: "s1", Side: executor.OrderSideSell, Price: decimal.NewFromInt(120), Quantity: decimal.NewFromInt(2), Timestamp: baseTime.Add(time.Hour)},
+		{OrderID: "b2", Side: executor.OrderSideBuy, Price: decimal.NewFromInt(110), Quantity: decimal.NewFromInt(1), Timestamp: baseTime.Add(2 * time.Hour)},
+	}
+	trades, _, _, _, _, _, _, _, _, _ := AnalyzeTrades(orders)
+
+	records := buildTradeRecords("run-1", pair, &BacktestStatistics{Orders: orders, Trades: trades})
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "run-1", records[0].BacktestRunID)
+	assert.Equal(t, "BUY", records[0].Side)
+	assert.True(t, records[0].PnL.IsZero())
+
+	assert.Equal(t, "SELL", records[1].Side)
+	assert.True(t, records[1].PnL.Equal(decimal.NewFromInt(40)))
+	assert.Equal(t, baseTime.Add(time.Hour).UnixMilli(), records[1].KlineOpenTime)
+
+	assert.True(t, records[2].PnL.IsZero())
+}
@@ -0,0 +1,168 @@
+package trading
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+)
+
+// BatchBacktestFunc 回测一个交易对，返回回测统计和逐K线资金曲线
+type BatchBacktestFunc func(ctx context.Context, pair cex.TradingPair) (*BacktestStatistics, []engine.EquityPoint, error)
+
+// BatchResult 批量回测中一个交易对的结果
+type BatchResult struct {
+	Pair   cex.TradingPair
+	Stats  *BacktestStatistics
+	Equity []engine.EquityPoint // 用于计算交易对之间的相关性
+	Score  float64
+	Err    error
+}
+
+// ParseBatchSymbols 解析批量回测的交易对列表：BASE/QUOTE，或只写 BASE 时使用 defaultQuote；重复的交易对只保留一个
+func ParseBatchSymbols(symbols []string, defaultQuote string) ([]cex.TradingPair, error) {
+	var normalized []string
+	for _, symbol := range symbols {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			continue
+		}
+		if !strings.Contains(symbol, "/") {
+			if defaultQuote == "" {
+				return nil, fmt.Errorf("symbol %q has no quote currency, use BASE/QUOTE or set -quote", symbol)
+			}
+			symbol += "/" + defaultQuote
+		}
+		normalized = append(normalized, symbol)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("no symbols to backtest")
+	}
+
+	parsed, err := ParseTradingPairs(normalized)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []cex.TradingPair
+	seen := make(map[string]bool)
+	for _, pair := range parsed {
+		if !seen[pair.String()] {
+			seen[pair.String()] = true
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs, nil
+}
+
+// LoadSymbolsFile 读取交易对列表文件：每行一个或多个交易对（逗号或空白分隔），# 之后为注释
+func LoadSymbolsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open symbols file: %w", err)
+	}
+	defer file.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		symbols = append(symbols, strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols file: %w", err)
+	}
+	return symbols, nil
+}
+
+// RunBatchBacktest 并发回测多个交易对（workers<=0 时使用 GOMAXPROCS），结果与 pairs 顺序一致
+// ctx 取消后未开始的交易对记为失败
+func RunBatchBacktest(ctx context.Context, pairs []cex.TradingPair, workers int, backtest BatchBacktestFunc) []*BatchResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]*BatchResult, len(pairs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &BatchResult{Pair: pairs[i]}
+				result.Stats, result.Equity, result.Err = backtest(ctx, pairs[i])
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range pairs {
+		select {
+		case <-ctx.Done():
+			results[i] = &BatchResult{Pair: pairs[i], Err: ctx.Err()}
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// BatchPortfolioRisk 以成功回测的交易对（按 results 顺序）计算等权组合的相关性和风险指标
+func BatchPortfolioRisk(results []*BatchResult, timeframe timeframes.Timeframe, maxCorrelation float64) (*PortfolioRisk, error) {
+	var series []PortfolioSeries
+	for _, result := range results {
+		if result.Err == nil {
+			series = append(series, PortfolioSeries{Symbol: result.Pair.String(), Equity: result.Equity})
+		}
+	}
+	return CalculatePortfolioRisk(series, timeframe, maxCorrelation)
+}
+
+// RankBatchResults 按得分从高到低排序，失败的交易对排在最后
+func RankBatchResults(results []*BatchResult, score func(stats *BacktestStatistics) float64) {
+	for _, result := range results {
+		if result.Err == nil {
+			result.Score = score(result.Stats)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Score > results[j].Score
+	})
+}
+
+// BacktestSymbol 为一个交易对创建独立的交易系统并回测（供批量回测并发调用），limiter 为交易对间共享的请求限频器
+// 按全局配置 SaveBacktest 持久化回测结果
+func BacktestSymbol(ctx context.Context, pair cex.TradingPair, timeframe, cexName, startDate, endDate string, initialCapital float64, params strategy.StrategyParams, limiter *cex.RateLimiter) (*BacktestStatistics, []engine.EquityPoint, error) {
+	ts, err := NewTradingSystemWithContext(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer ts.Stop()
+	ts.SetRateLimiter(limiter)
+
+	if err := ts.SetTradingPairTimeframeAndCEX(pair, timeframe, cexName); err != nil {
+		return nil, nil, fmt.Errorf("failed to set trading pair, timeframe and CEX: %w", err)
+	}
+	stats, err := ts.RunBacktestWithParamsAndCapital(pair, startDate, endDate, initialCapital, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stats, ts.GetEquityCurve(), nil
+}
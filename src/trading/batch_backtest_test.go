@@ -0,0 +1,90 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchSymbols(t *testing.T) {
+	pairs, err := ParseBatchSymbols([]string{"btc", " ETH/BTC ", "", "BTC/USDT", "sol"}, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, []cex.TradingPair{
+		{Base: "BTC", Quote: "USDT"},
+		{Base: "ETH", Quote: "BTC"},
+		{Base: "SOL", Quote: "USDT"},
+	}, pairs)
+
+	_, err = ParseBatchSymbols([]string{"BTC"}, "")
+	assert.Error(t, err)
+	_, err = ParseBatchSymbols([]string{"BTC/"}, "USDT")
+	assert.Error(t, err)
+	_, err = ParseBatchSymbols(nil, "USDT")
+	assert.Error(t, err)
+}
+
+func TestLoadSymbolsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "symbols.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# majors\nBTC, ETH\n\nPEPE/USDT WIF # memes\n"), 0o644))
+
+	symbols, err := LoadSymbolsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC", "ETH", "PEPE/USDT", "WIF"}, symbols)
+
+	_, err = LoadSymbolsFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestRunBatchBacktest(t *testing.T) {
+	pairs, err := ParseBatchSymbols([]string{"BTC", "ETH", "SOL", "DOGE"}, "USDT")
+	require.NoError(t, err)
+	returns := map[string]float64{"BTC/USDT": 0.1, "ETH/USDT": 0.3, "DOGE/USDT": -0.2}
+
+	var calls int32
+	results := RunBatchBacktest(context.Background(), pairs, 2, func(ctx context.Context, pair cex.TradingPair) (*BacktestStatistics, []engine.EquityPoint, error) {
+		atomic.AddInt32(&calls, 1)
+		r, ok := returns[pair.String()]
+		if !ok {
+			return nil, nil, fmt.Errorf("no historical data")
+		}
+		return &BacktestStatistics{TotalReturn: decimal.NewFromFloat(r)}, nil, nil
+	})
+	assert.Equal(t, int32(4), calls)
+
+	// 结果与输入顺序一致
+	require.Len(t, results, 4)
+	for i, result := range results {
+		assert.Equal(t, pairs[i], result.Pair)
+	}
+
+	// 按得分排名，失败的交易对排在最后
+	RankBatchResults(results, func(stats *BacktestStatistics) float64 { return stats.TotalReturn.InexactFloat64() })
+	ranked := make([]string, len(results))
+	for i, result := range results {
+		ranked[i] = result.Pair.String()
+	}
+	assert.Equal(t, []string{"ETH/USDT", "BTC/USDT", "DOGE/USDT", "SOL/USDT"}, ranked)
+	assert.Equal(t, 0.3, results[0].Score)
+	assert.Error(t, results[3].Err)
+
+	// 已取消时不再开始新的回测
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = RunBatchBacktest(ctx, pairs, 1, func(ctx context.Context, pair cex.TradingPair) (*BacktestStatistics, []engine.EquityPoint, error) {
+		return &BacktestStatistics{}, nil, nil
+	})
+	require.Len(t, results, 4)
+	for _, result := range results {
+		assert.NotNil(t, result)
+	}
+}
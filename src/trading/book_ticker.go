@@ -0,0 +1,157 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// BookTickerDB 盘口买一卖一记录存储（由 database.PostgresDB 实现）
+type BookTickerDB interface {
+	// SaveBookTicker 保存一条记录
+	SaveBookTicker(ctx context.Context, record *database.BookTickerRecord) error
+}
+
+// SpreadStatsDB 盘口价差统计（由 database.PostgresDB 实现）
+type SpreadStatsDB interface {
+	// GetSpreadHourStats 按 UTC 小时汇总 [start, end) 内的价差，零值时间表示不限制
+	GetSpreadHourStats(ctx context.Context, symbol string, start, end time.Time) ([]*database.SpreadHourStat, error)
+}
+
+// BookTickerRecorder 定期查询盘口买一卖一并保存，供回测按实际价差模拟吃单成本
+type BookTickerRecorder struct {
+	book     cex.OrderBookProvider
+	db       BookTickerDB
+	pair     cex.TradingPair
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewBookTickerRecorder 创建盘口记录器
+func NewBookTickerRecorder(book cex.OrderBookProvider, db BookTickerDB, pair cex.TradingPair, interval time.Duration) *BookTickerRecorder {
+	return &BookTickerRecorder{book: book, db: db, pair: pair, interval: interval, now: time.Now}
+}
+
+// Record 查询并保存一次买一卖一，盘口一侧为空时不保存
+func (r *BookTickerRecorder) Record(ctx context.Context) error {
+	book, err := r.book.GetOrderBook(ctx, r.pair, 1)
+	if err != nil {
+		return fmt.Errorf("failed to get order book: %w", err)
+	}
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil
+	}
+	return r.db.SaveBookTicker(ctx, &database.BookTickerRecord{
+		Symbol:      DatabaseSymbol(r.pair),
+		Time:        r.now(),
+		BidPrice:    book.Bids[0].Price,
+		BidQuantity: book.Bids[0].Quantity,
+		AskPrice:    book.Asks[0].Price,
+		AskQuantity: book.Asks[0].Quantity,
+	})
+}
+
+// Start 后台每隔 interval 记录一次，ctx 取消后退出
+func (r *BookTickerRecorder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := r.Record(ctx); err != nil && ctx.Err() == nil {
+				_, logger := log.WithCtx(ctx)
+				logger.Warning(fmt.Sprintf("记录盘口失败: symbol=%s, error=%v", r.pair.String(), err))
+			}
+		}
+	}()
+}
+
+// startBookTickerRecorder 实盘和 Dry Run 定期记录盘口买一卖一到 book_tickers 表（数据库不可用、交易所不支持盘口或间隔为 0 时不记录）
+func (ts *TradingSystem) startBookTickerRecorder(pair cex.TradingPair) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	seconds := TradingConfigValue.BookTickerSeconds
+	if seconds <= 0 {
+		return
+	}
+	book, ok := ts.cexClient.(cex.OrderBookProvider)
+	if !ok {
+		logger.Warning(fmt.Sprintf("⚠️ %s 不支持查询盘口，不记录买一卖一", ts.cexClient.GetName()))
+		return
+	}
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("⚠️ 数据库不可用，不记录买一卖一: %v", err))
+		return
+	}
+
+	NewBookTickerRecorder(book, db, pair, time.Duration(seconds)*time.Second).Start(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 盘口记录: symbol=%s, interval=%ds", DatabaseSymbol(pair), seconds))
+}
+
+// LoadSpreadStats 汇总交易对记录的盘口价差，lookbackDays > 0 时只使用最近 lookbackDays 天的记录
+func LoadSpreadStats(ctx context.Context, db SpreadStatsDB, pair cex.TradingPair, lookbackDays int) (engine.SpreadStats, error) {
+	var start time.Time
+	if lookbackDays > 0 {
+		start = time.Now().AddDate(0, 0, -lookbackDays)
+	}
+	hours, err := db.GetSpreadHourStats(ctx, DatabaseSymbol(pair), start, time.Time{})
+	if err != nil {
+		return engine.SpreadStats{}, err
+	}
+
+	var stats engine.SpreadStats
+	var total float64
+	for _, hour := range hours {
+		if hour.Hour < 0 || hour.Hour >= 24 || hour.Samples <= 0 {
+			continue
+		}
+		stats.Hourly[hour.Hour] = hour.MeanSpread
+		stats.HourSamples[hour.Hour] = hour.Samples
+		stats.Samples += hour.Samples
+		total += hour.MeanSpread * float64(hour.Samples)
+	}
+	if stats.Samples > 0 {
+		stats.Mean = total / float64(stats.Samples)
+	}
+	return stats, nil
+}
+
+// newSpreadFillModel 按配置 Backtest.CapturedSpread 用记录的盘口价差创建成交模型，未启用时返回 nil
+func (ts *TradingSystem) newSpreadFillModel(pair cex.TradingPair) (engine.FillModel, error) {
+	config := TradingConfigValue.Backtest
+	if !config.CapturedSpread {
+		return nil, nil
+	}
+	if config.SpreadLookbackDays < 0 {
+		return nil, fmt.Errorf("invalid backtest config: spread_lookback_days must be non-negative, got %d", config.SpreadLookbackDays)
+	}
+
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return nil, fmt.Errorf("captured spread requires the database: %w", err)
+	}
+	stats, err := LoadSpreadStats(ts.ctx, db, pair, config.SpreadLookbackDays)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Samples == 0 {
+		return nil, fmt.Errorf("no book tickers recorded for %s, set book_ticker_seconds and run live/dry first or disable captured_spread", pair.String())
+	}
+
+	model := engine.NewSpreadFillModel(stats)
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid captured spread for %s: %w", pair.String(), err)
+	}
+	return model, nil
+}
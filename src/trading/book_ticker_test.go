@@ -0,0 +1,87 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticBookProvider 返回固定盘口
+type staticBookProvider struct {
+	book *cex.OrderBook
+	err  error
+}
+
+func (p *staticBookProvider) GetOrderBook(ctx context.Context, pair cex.TradingPair, limit int) (*cex.OrderBook, error) {
+	return p.book, p.err
+}
+
+// memoryBookTickerDB 内存盘口记录
+type memoryBookTickerDB struct {
+	records []*database.BookTickerRecord
+	hours   []*database.SpreadHourStat
+	start   time.Time
+}
+
+func (db *memoryBookTickerDB) SaveBookTicker(ctx context.Context, record *database.BookTickerRecord) error {
+	db.records = append(db.records, record)
+	return nil
+}
+
+func (db *memoryBookTickerDB) GetSpreadHourStats(ctx context.Context, symbol string, start, end time.Time) ([]*database.SpreadHourStat, error) {
+	db.start = start
+	return db.hours, nil
+}
+
+func TestBookTickerRecorder_Record(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	level := func(price, quantity int64) cex.OrderBookLevel {
+		return cex.OrderBookLevel{Price: decimal.NewFromInt(price), Quantity: decimal.NewFromInt(quantity)}
+	}
+	book := &staticBookProvider{book: &cex.OrderBook{Bids: []cex.OrderBookLevel{level(99, 3)}, Asks: []cex.OrderBookLevel{level(101, 2)}}}
+	db := &memoryBookTickerDB{}
+	recorder := NewBookTickerRecorder(book, db, syncTestPair, time.Second)
+	recorder.now = func() time.Time { return now }
+
+	require.NoError(t, recorder.Record(context.Background()))
+	require.Len(t, db.records, 1)
+	assert.Equal(t, "BTCUSDT", db.records[0].Symbol)
+	assert.Equal(t, now, db.records[0].Time)
+	assert.Equal(t, "99", db.records[0].BidPrice.String())
+	assert.Equal(t, "2", db.records[0].AskQuantity.String())
+
+	// 盘口一侧为空时不保存
+	book.book = &cex.OrderBook{Bids: []cex.OrderBookLevel{level(99, 3)}}
+	require.NoError(t, recorder.Record(context.Background()))
+	assert.Len(t, db.records, 1)
+
+	book.err = errors.New("timeout")
+	assert.Error(t, recorder.Record(context.Background()))
+}
+
+func TestLoadSpreadStats(t *testing.T) {
+	db := &memoryBookTickerDB{hours: []*database.SpreadHourStat{
+		{Hour: 0, Samples: 30, MeanSpread: 0.001},
+		{Hour: 14, Samples: 10, MeanSpread: 0.005},
+	}}
+
+	stats, err := LoadSpreadStats(context.Background(), db, syncTestPair, 0)
+	require.NoError(t, err)
+	assert.True(t, db.start.IsZero())
+	assert.Equal(t, 40, stats.Samples)
+	assert.InDelta(t, 0.002, stats.Mean, 1e-12) // 按样本数加权
+	assert.Equal(t, 0.005, stats.At(time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)))
+	assert.InDelta(t, 0.002, stats.At(time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC)), 1e-12)
+
+	_, err = LoadSpreadStats(context.Background(), db, syncTestPair, 7)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), db.start, time.Minute)
+}
@@ -0,0 +1,434 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
+	"tradingbot/src/logging"
+	"tradingbot/src/safety"
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// botRunner 运行一个机器人，直到 ctx 取消或引擎退出
+type botRunner func(ctx context.Context, config BotConfig, limiter *cex.RateLimiter, live *dashboard.LiveState) error
+
+// BotManager 在同一进程内运行和监管多个机器人（交易对、策略、交易所各不相同），
+// 每个机器人使用独立的交易系统和日志前缀，访问同一交易所的机器人共享请求限频器
+type BotManager struct {
+	ctx         context.Context
+	rateLimit   cex.RateLimitConfig
+	historySize int
+	run         botRunner
+
+	mu       sync.Mutex
+	names    []string // 配置顺序
+	bots     map[string]*managedBot
+	limiters map[string]*cex.RateLimiter // 按交易所
+	wg       sync.WaitGroup
+}
+
+// managedBot 管理器中的一个机器人
+type managedBot struct {
+	config    BotConfig
+	state     string
+	live      *dashboard.LiveState // 首次启动后才有
+	cancel    context.CancelFunc
+	done      chan struct{}
+	startedAt *time.Time
+	stoppedAt *time.Time
+	err       error
+	traceID   string // 最近一次启动的跟踪ID
+}
+
+// NewBotManager 按配置创建机器人管理器（不启动任何机器人），ctx 取消时所有机器人停止
+func NewBotManager(ctx context.Context, config BotsConfig) (*BotManager, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bots config: %w", err)
+	}
+
+	manager := &BotManager{
+		ctx:         ctx,
+		rateLimit:   config.RateLimit,
+		historySize: dashboard.ConfigValue.HistorySize,
+		run:         runBot,
+		bots:        make(map[string]*managedBot, len(config.Bots)),
+		limiters:    make(map[string]*cex.RateLimiter),
+	}
+	for _, botConfig := range config.Bots {
+		manager.names = append(manager.names, botConfig.Name)
+		manager.bots[botConfig.Name] = &managedBot{config: botConfig, state: dashboard.BotStateStopped}
+	}
+	return manager, nil
+}
+
+// StartAutoStart 启动配置了 AutoStart 的机器人，返回启动的数量（单个机器人启动失败只记录错误）
+func (m *BotManager) StartAutoStart() int {
+	_, logger := log.WithCtx(m.ctx)
+
+	started := 0
+	for _, name := range m.names {
+		if !m.bots[name].config.AutoStart {
+			continue
+		}
+		if err := m.StartBot(name); err != nil {
+			logger.Error(fmt.Sprintf("❌ 机器人启动失败: bot=%s, error=%v", name, err))
+			continue
+		}
+		started++
+	}
+	return started
+}
+
+// StartBot 启动机器人（后台运行，引擎退出后状态变为 stopped 或 failed），RequireArm 开启时拒绝启动实盘机器人
+func (m *BotManager) StartBot(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bot, err := m.lookup(name)
+	if err != nil {
+		return err
+	}
+	if bot.state == dashboard.BotStateRunning {
+		return fmt.Errorf("bot %s is already running", name)
+	}
+	// 机器人在后台运行，无法输入 ARM 确认：实盘机器人要求关闭 RequireArm
+	if !bot.config.DryRun && !bot.config.SignalOnly && safety.ConfigValue.RequireArm {
+		return fmt.Errorf("%w: bot %s trades live and cannot be confirmed interactively (set RequireArm to false in the safety config to run live bots)", safety.ErrNotArmed, name)
+	}
+	limiter, err := m.limiterFor(bot.config.exchange())
+	if err != nil {
+		return err
+	}
+
+	// 机器人的日志都带 bot=<name> 和本次启动的 trace=<id> 前缀，JSON 日志格式下提取为 fields.bot、fields.trace
+	ctx, cancel := context.WithCancel(m.ctx)
+	ctx, logger := log.WithCtx(ctx)
+	logger.PushPrefix(fmt.Sprintf("bot=%s", name))
+	traceKind := logging.TraceKindLive
+	if bot.config.DryRun || bot.config.SignalOnly {
+		traceKind = logging.TraceKindDryRun
+	}
+	traceID := logging.NewTraceID(traceKind)
+	ctx = logging.WithTraceID(ctx, traceID)
+	_, logger = log.WithCtx(ctx)
+
+	now := time.Now()
+	done := make(chan struct{})
+	bot.state = dashboard.BotStateRunning
+	bot.live = dashboard.NewLiveState(m.historySize)
+	bot.cancel = cancel
+	bot.done = done
+	bot.startedAt = &now
+	bot.stoppedAt = nil
+	bot.err = nil
+	bot.traceID = traceID
+
+	config, live := bot.config, bot.live
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer close(done)
+		err := m.run(ctx, config, limiter, live)
+		cancel()
+		m.finish(ctx, bot, err)
+	}()
+
+	logger.Info(fmt.Sprintf("🤖 机器人已启动: exchange=%s, symbol=%s/%s, dry_run=%v, signal_only=%v", config.exchange(), config.Base, config.Quote, config.DryRun, config.SignalOnly))
+	return nil
+}
+
+// finish 记录机器人退出（停止时 ctx 已取消，其余错误视为异常退出）
+func (m *BotManager) finish(ctx context.Context, bot *managedBot, err error) {
+	_, logger := log.WithCtx(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	bot.stoppedAt = &now
+	if err != nil && !errors.Is(err, context.Canceled) {
+		bot.state = dashboard.BotStateFailed
+		bot.err = err
+		logger.Error(fmt.Sprintf("❌ 机器人异常退出: error=%v", err))
+		return
+	}
+	bot.state = dashboard.BotStateStopped
+	logger.Info("🛑 机器人已停止")
+}
+
+// StopBot 停止机器人并等待引擎退出
+func (m *BotManager) StopBot(name string) error {
+	m.mu.Lock()
+	bot, err := m.lookup(name)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	if bot.state != dashboard.BotStateRunning {
+		m.mu.Unlock()
+		return fmt.Errorf("bot %s is not running", name)
+	}
+	bot.cancel()
+	done := bot.done
+	m.mu.Unlock()
+
+	<-done
+	return nil
+}
+
+// StopAll 停止所有机器人并等待退出
+func (m *BotManager) StopAll() {
+	m.mu.Lock()
+	for _, bot := range m.bots {
+		if bot.state == dashboard.BotStateRunning {
+			bot.cancel()
+		}
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// BotStatuses 所有机器人的状态（按配置顺序）
+func (m *BotManager) BotStatuses() []*dashboard.BotStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]*dashboard.BotStatus, 0, len(m.names))
+	for _, name := range m.names {
+		statuses = append(statuses, m.bots[name].status())
+	}
+	return statuses
+}
+
+// BotStatus 单个机器人的状态
+func (m *BotManager) BotStatus(name string) (*dashboard.BotStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bot, err := m.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return bot.status(), nil
+}
+
+// BotLive 单个机器人最近一次运行的实盘状态
+func (m *BotManager) BotLive(name string) (*dashboard.LiveSnapshot, error) {
+	m.mu.Lock()
+	bot, err := m.lookup(name)
+	var live *dashboard.LiveState
+	if err == nil {
+		live = bot.live
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if live == nil {
+		return nil, fmt.Errorf("bot %s has not been started", name)
+	}
+	return live.Snapshot(), nil
+}
+
+// Bot 机器人配置
+func (m *BotManager) Bot(name string) (BotConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bot, err := m.lookup(name)
+	if err != nil {
+		return BotConfig{}, err
+	}
+	return bot.config, nil
+}
+
+// UpdateBotParams 更新布林道机器人的参数文件，机器人运行中时重启使新参数生效
+func (m *BotManager) UpdateBotParams(name string, params *strategy.BollingerBandsParams) error {
+	m.mu.Lock()
+	bot, err := m.lookup(name)
+	var config BotConfig
+	var running bool
+	if err == nil {
+		config, running = bot.config, bot.state == dashboard.BotStateRunning
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if config.strategy() != "bollinger" {
+		return fmt.Errorf("bot %s uses strategy %s, only bollinger params can be updated", name, config.strategy())
+	}
+	if config.ParamsFile == "" {
+		return fmt.Errorf("bot %s has no params_file to update", name)
+	}
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := config.ParamsFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write params file: %w", err)
+	}
+	if err := os.Rename(tmp, config.ParamsFile); err != nil {
+		return fmt.Errorf("failed to write params file: %w", err)
+	}
+
+	_, logger := log.WithCtx(m.ctx)
+	logger.Info(fmt.Sprintf("📝 机器人参数已更新: bot=%s, file=%s, restart=%v", name, config.ParamsFile, running))
+	if !running {
+		return nil
+	}
+	if err := m.StopBot(name); err != nil {
+		return err
+	}
+	return m.StartBot(name)
+}
+
+// lookup 按名称查找机器人（调用方持有锁）
+func (m *BotManager) lookup(name string) (*managedBot, error) {
+	bot, exists := m.bots[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", dashboard.ErrBotNotFound, name)
+	}
+	return bot, nil
+}
+
+// limiterFor 交易所共享的限频器，未启用限频时返回 nil（调用方持有锁）
+func (m *BotManager) limiterFor(exchange string) (*cex.RateLimiter, error) {
+	if limiter, exists := m.limiters[exchange]; exists {
+		return limiter, nil
+	}
+	limiter, err := m.rateLimit.NewRateLimiter()
+	if err != nil {
+		return nil, err
+	}
+	m.limiters[exchange] = limiter
+	return limiter, nil
+}
+
+// status 机器人状态（调用方持有锁）
+func (b *managedBot) status() *dashboard.BotStatus {
+	status := &dashboard.BotStatus{
+		Name:       b.config.Name,
+		Exchange:   b.config.exchange(),
+		Symbol:     CreateTradingPair(b.config.Base, b.config.Quote).String(),
+		Timeframe:  b.config.timeframe(),
+		Strategy:   b.config.strategy(),
+		DryRun:     b.config.DryRun,
+		SignalOnly: b.config.SignalOnly,
+		State:      b.state,
+		StartedAt:  b.startedAt,
+		StoppedAt:  b.stoppedAt,
+		TraceID:    b.traceID,
+	}
+	if b.err != nil {
+		status.Error = b.err.Error()
+	}
+	return status
+}
+
+// exchange 交易所，默认 binance
+func (c BotConfig) exchange() string {
+	if c.Exchange == "" {
+		return "binance"
+	}
+	return c.Exchange
+}
+
+// timeframe K线周期，默认使用交易配置的 Timeframe
+func (c BotConfig) timeframe() string {
+	if c.Timeframe == "" {
+		return TradingConfigValue.Timeframe
+	}
+	return c.Timeframe
+}
+
+// strategy 策略名称，默认 bollinger
+func (c BotConfig) strategy() string {
+	if c.Strategy == "" {
+		return "bollinger"
+	}
+	return c.Strategy
+}
+
+// runBot 创建独立的交易系统运行机器人的实盘或 Dry Run
+func runBot(ctx context.Context, config BotConfig, limiter *cex.RateLimiter, live *dashboard.LiveState) error {
+	ts, err := NewTradingSystemWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create trading system: %w", err)
+	}
+	defer ts.Stop()
+	ts.SetRateLimiter(limiter)
+	ts.SetLiveState(live)
+
+	pair := CreateTradingPair(config.Base, config.Quote)
+	if err := ts.SetTradingPairTimeframeAndCEX(pair, config.timeframe(), config.exchange()); err != nil {
+		return fmt.Errorf("failed to set trading parameters: %w", err)
+	}
+	ts.SetSignalOnly(config.SignalOnly)
+	if config.DryRun || config.SignalOnly {
+		capital := config.InitialCapital
+		if capital == 0 {
+			capital = 10000
+		}
+		ts.SetPaperSession(config.Session, capital)
+	}
+
+	// 布林道策略与 bollinger 命令的实盘一致（配置的卖出策略覆盖参数文件）
+	if config.strategy() == "bollinger" {
+		params := strategy.GetDefaultBollingerBandsParams()
+		if config.ParamsFile != "" {
+			params, err = strategy.LoadBollingerBandsParamsFile(config.ParamsFile, params)
+			if err != nil {
+				return err
+			}
+		}
+		return ts.RunLiveTradingWithParams(pair, params, config.DryRun)
+	}
+
+	strategyImpl, err := strategies.CreateStrategy(config.strategy())
+	if err != nil {
+		return err
+	}
+	if err := loadStrategyParamsFile(strategyImpl, config.ParamsFile); err != nil {
+		return err
+	}
+	return ts.RunLiveTradingWithStrategy(pair, strategyImpl, config.DryRun)
+}
+
+// loadStrategyParamsFile 从JSON参数文件覆盖策略的默认参数（文件中未出现的字段保持默认值）
+func loadStrategyParamsFile(strategyImpl strategy.Strategy, path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read params file: %w", err)
+	}
+
+	params := strategyImpl.GetParams()
+	if err := json.Unmarshal(data, params); err != nil {
+		return fmt.Errorf("failed to parse params file %s: %w", path, err)
+	}
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+	return strategyImpl.SetParams(params)
+}
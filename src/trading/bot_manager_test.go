@@ -0,0 +1,224 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
+	"tradingbot/src/logging"
+	"tradingbot/src/safety"
+	"tradingbot/src/strategy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBotRunner 记录机器人拿到的限频器，运行到 ctx 取消或收到 fail 的错误
+type fakeBotRunner struct {
+	mu       sync.Mutex
+	limiters map[string]*cex.RateLimiter
+	traces   map[string]string
+	started  chan string
+	fail     chan error
+}
+
+func newFakeBotRunner() *fakeBotRunner {
+	return &fakeBotRunner{
+		limiters: make(map[string]*cex.RateLimiter),
+		traces:   make(map[string]string),
+		started:  make(chan string, 10),
+		fail:     make(chan error, 1),
+	}
+}
+
+func (r *fakeBotRunner) run(ctx context.Context, config BotConfig, limiter *cex.RateLimiter, live *dashboard.LiveState) error {
+	r.mu.Lock()
+	r.limiters[config.Name] = limiter
+	r.traces[config.Name] = logging.TraceIDFromContext(ctx)
+	r.mu.Unlock()
+	r.started <- config.Name
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-r.fail:
+		return err
+	}
+}
+
+// disableRequireArm 关闭实盘 ARM 确认，使测试可以启动实盘机器人
+func disableRequireArm(t *testing.T) {
+	requireArm := safety.ConfigValue.RequireArm
+	safety.ConfigValue.RequireArm = false
+	t.Cleanup(func() { safety.ConfigValue.RequireArm = requireArm })
+}
+
+func newTestBotManager(t *testing.T, runner *fakeBotRunner) *BotManager {
+	disableRequireArm(t)
+	config := BotsConfig{
+		Bots: []BotConfig{
+			{Name: "btc-4h", Base: "BTC", Quote: "USDT", Timeframe: "4h", AutoStart: true},
+			{Name: "eth-1h", Base: "ETH", Quote: "USDT", Timeframe: "1h", DryRun: true, AutoStart: true},
+			{Name: "sol-bybit", Exchange: "bybit", Base: "SOL", Quote: "USDT"},
+		},
+		RateLimit: cex.RateLimitConfig{RequestsPerSecond: 10, Burst: 20},
+	}
+	manager, err := NewBotManager(context.Background(), config)
+	require.NoError(t, err)
+	manager.run = runner.run
+	t.Cleanup(manager.StopAll)
+	return manager
+}
+
+func TestBotManager_StartStop(t *testing.T) {
+	runner := newFakeBotRunner()
+	manager := newTestBotManager(t, runner)
+
+	assert.Equal(t, 2, manager.StartAutoStart())
+	<-runner.started
+	<-runner.started
+
+	statuses := manager.BotStatuses()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, []string{"btc-4h", "eth-1h", "sol-bybit"}, []string{statuses[0].Name, statuses[1].Name, statuses[2].Name})
+	assert.Equal(t, dashboard.BotStateRunning, statuses[0].State)
+	assert.Equal(t, "BTC/USDT", statuses[0].Symbol)
+	assert.Equal(t, "binance", statuses[0].Exchange)
+	assert.Equal(t, "bollinger", statuses[0].Strategy)
+	assert.True(t, statuses[1].DryRun)
+	assert.Equal(t, dashboard.BotStateStopped, statuses[2].State)
+
+	// 每次启动分配跟踪ID并通过 ctx 传给机器人
+	runner.mu.Lock()
+	assert.Regexp(t, `^live-`, statuses[0].TraceID)
+	assert.Regexp(t, `^dry-`, statuses[1].TraceID)
+	assert.Equal(t, statuses[0].TraceID, runner.traces["btc-4h"])
+	runner.mu.Unlock()
+
+	// 同一交易所的机器人共享限频器
+	runner.mu.Lock()
+	assert.Same(t, runner.limiters["btc-4h"], runner.limiters["eth-1h"])
+	runner.mu.Unlock()
+	require.NoError(t, manager.StartBot("sol-bybit"))
+	<-runner.started
+	runner.mu.Lock()
+	assert.NotSame(t, runner.limiters["btc-4h"], runner.limiters["sol-bybit"])
+	runner.mu.Unlock()
+
+	assert.Error(t, manager.StartBot("btc-4h"))
+
+	require.NoError(t, manager.StopBot("btc-4h"))
+	status, err := manager.BotStatus("btc-4h")
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.BotStateStopped, status.State)
+	assert.NotNil(t, status.StoppedAt)
+	assert.Error(t, manager.StopBot("btc-4h"))
+
+	// 停止后可以重新启动
+	require.NoError(t, manager.StartBot("btc-4h"))
+	<-runner.started
+
+	_, err = manager.BotStatus("unknown")
+	assert.ErrorIs(t, err, dashboard.ErrBotNotFound)
+	assert.ErrorIs(t, manager.StartBot("unknown"), dashboard.ErrBotNotFound)
+	assert.ErrorIs(t, manager.StopBot("unknown"), dashboard.ErrBotNotFound)
+}
+
+func TestBotManager_LiveBotRequiresArmDisabled(t *testing.T) {
+	runner := newFakeBotRunner()
+	manager := newTestBotManager(t, runner)
+	safety.ConfigValue.RequireArm = true
+
+	// 实盘机器人无法输入 ARM 确认，拒绝启动；模拟盘不受影响
+	assert.Equal(t, 1, manager.StartAutoStart())
+	assert.Equal(t, "eth-1h", <-runner.started)
+	err := manager.StartBot("btc-4h")
+	assert.ErrorIs(t, err, safety.ErrNotArmed)
+	assert.Contains(t, err.Error(), "RequireArm")
+	status, err := manager.BotStatus("btc-4h")
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.BotStateStopped, status.State)
+}
+
+func TestBotManager_RecordsFailure(t *testing.T) {
+	runner := newFakeBotRunner()
+	manager := newTestBotManager(t, runner)
+
+	require.NoError(t, manager.StartBot("sol-bybit"))
+	<-runner.started
+	runner.fail <- errors.New("failed to connect to CEX")
+
+	require.Eventually(t, func() bool {
+		status, _ := manager.BotStatus("sol-bybit")
+		return status.State == dashboard.BotStateFailed
+	}, time.Second, 5*time.Millisecond)
+
+	status, err := manager.BotStatus("sol-bybit")
+	require.NoError(t, err)
+	assert.Equal(t, "failed to connect to CEX", status.Error)
+
+	snapshot, err := manager.BotLive("sol-bybit")
+	require.NoError(t, err)
+	assert.NotNil(t, snapshot)
+	_, err = manager.BotLive("btc-4h")
+	assert.Error(t, err)
+}
+
+func TestBotsConfig_Validate(t *testing.T) {
+	valid := BotConfig{Name: "btc", Base: "BTC", Quote: "USDT"}
+	assert.NoError(t, BotsConfig{Bots: []BotConfig{valid}}.Validate())
+
+	for _, bots := range [][]BotConfig{
+		{valid, valid},
+		{{Name: "", Base: "BTC", Quote: "USDT"}},
+		{{Name: "btc usdt", Base: "BTC", Quote: "USDT"}},
+		{{Name: "btc", Base: "BTC"}},
+		{{Name: "btc", Base: "BTC", Quote: "USDT", Timeframe: "7x"}},
+		{{Name: "btc", Base: "BTC", Quote: "USDT", Strategy: "unknown"}},
+	} {
+		assert.Error(t, BotsConfig{Bots: bots}.Validate(), "%+v", bots)
+	}
+
+	_, err := NewBotManager(context.Background(), BotsConfig{Bots: []BotConfig{valid, valid}})
+	assert.Error(t, err)
+}
+
+func TestBotManager_UpdateBotParams(t *testing.T) {
+	runner := newFakeBotRunner()
+	disableRequireArm(t)
+	paramsFile := filepath.Join(t.TempDir(), "btc.json")
+	manager, err := NewBotManager(context.Background(), BotsConfig{Bots: []BotConfig{
+		{Name: "btc-4h", Base: "BTC", Quote: "USDT", ParamsFile: paramsFile},
+		{Name: "eth-1h", Base: "ETH", Quote: "USDT"},
+	}})
+	require.NoError(t, err)
+	manager.run = runner.run
+	t.Cleanup(manager.StopAll)
+
+	require.NoError(t, manager.StartBot("btc-4h"))
+	<-runner.started
+
+	params := strategy.GetDefaultBollingerBandsParams()
+	params.Period = 30
+	require.NoError(t, manager.UpdateBotParams("btc-4h", params))
+
+	// 运行中的机器人重启后使用新参数文件
+	<-runner.started
+	status, err := manager.BotStatus("btc-4h")
+	require.NoError(t, err)
+	assert.Equal(t, dashboard.BotStateRunning, status.State)
+	loaded, err := strategy.LoadBollingerBandsParamsFile(paramsFile, strategy.GetDefaultBollingerBandsParams())
+	require.NoError(t, err)
+	assert.Equal(t, 30, loaded.Period)
+
+	// 没有参数文件或参数无效时拒绝
+	assert.Error(t, manager.UpdateBotParams("eth-1h", params))
+	params.Period = 0
+	assert.Error(t, manager.UpdateBotParams("btc-4h", params))
+	assert.ErrorIs(t, manager.UpdateBotParams("unknown", params), dashboard.ErrBotNotFound)
+}
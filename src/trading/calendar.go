@@ -0,0 +1,110 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+)
+
+// calendarWildcard 对所有交易对生效的日历键
+const calendarWildcard = "*"
+
+// BuildTradingCalendar 合并配置和数据库中的禁止交易时段，生成交易对的交易日历
+func BuildTradingCalendar(symbol string, configs []CalendarWindowConfig, records []*database.TradingCalendarRecord) (*engine.TradingCalendar, error) {
+	calendar := engine.NewTradingCalendar()
+
+	for _, cfg := range configs {
+		if cfg.Symbol != calendarWildcard && !strings.EqualFold(cfg.Symbol, symbol) {
+			continue
+		}
+		if err := addCalendarWindow(calendar, cfg); err != nil {
+			return nil, fmt.Errorf("invalid no-trade window for %s: %w", cfg.Symbol, err)
+		}
+	}
+
+	for _, record := range records {
+		err := calendar.AddWindow(engine.NoTradeWindow{
+			Start:  record.StartTime,
+			End:    record.EndTime,
+			Reason: record.Reason,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar record %d: %w", record.ID, err)
+		}
+	}
+
+	return calendar, nil
+}
+
+// addCalendarWindow 解析单个禁止交易时段配置并加入日历
+func addCalendarWindow(calendar *engine.TradingCalendar, cfg CalendarWindowConfig) error {
+	if cfg.Daily {
+		start, err := parseTimeOfDay(cfg.Start)
+		if err != nil {
+			return err
+		}
+		end, err := parseTimeOfDay(cfg.End)
+		if err != nil {
+			return err
+		}
+		return calendar.AddDailyWindow(engine.DailyNoTradeWindow{Start: start, End: end, Reason: cfg.Reason})
+	}
+
+	start, err := parseCalendarTime(cfg.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseCalendarTime(cfg.End)
+	if err != nil {
+		return err
+	}
+	return calendar.AddWindow(engine.NoTradeWindow{Start: start, End: end, Reason: cfg.Reason})
+}
+
+// parseTimeOfDay 解析每日时刻 HH:MM（"24:00" 表示当日结束）
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported time of day: %s (expected HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseCalendarTime 解析UTC日期时间
+func parseCalendarTime(s string) (time.Time, error) {
+	formats := []string{
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	for _, format := range formats {
+		if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format: %s (supported: YYYY-MM-DD, YYYY-MM-DD HH:MM, YYYY-MM-DD HH:MM:SS)", s)
+}
+
+// loadTradingCalendar 加载交易对的交易日历（数据库不可用时只使用配置）
+func (ts *TradingSystem) loadTradingCalendar(ctx context.Context, pair cex.TradingPair) (*engine.TradingCalendar, error) {
+	symbol := DatabaseSymbol(pair)
+
+	var records []*database.TradingCalendarRecord
+	if db, err := GetPostgresDB(ts.cexClient); err == nil {
+		records, err = db.GetTradingCalendar(ctx, symbol)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to load trading calendar from database: %v\n", err)
+			records = nil
+		}
+	}
+
+	return BuildTradingCalendar(symbol, TradingConfigValue.NoTradeWindows, records)
+}
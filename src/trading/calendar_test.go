@@ -0,0 +1,57 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTradingCalendar(t *testing.T) {
+	configs := []CalendarWindowConfig{
+		{Symbol: "*", Start: "00:00", End: "00:05", Daily: true, Reason: "daily settlement"},
+		{Symbol: "pepeusdt", Start: "2024-03-01 02:00", End: "2024-03-01 04:00", Reason: "wallet upgrade"},
+		{Symbol: "BTCUSDT", Start: "2024-03-02", End: "2024-03-03", Reason: "not for pepe"},
+	}
+	records := []*database.TradingCalendarRecord{
+		{ID: 1, Symbol: "PEPEUSDT", StartTime: time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC), Reason: "exchange maintenance"},
+	}
+
+	calendar, err := BuildTradingCalendar("PEPEUSDT", configs, records)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calendar.WindowCount())
+
+	allowed, reason := calendar.IsTradingAllowed(time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC))
+	assert.False(t, allowed)
+	assert.Equal(t, "wallet upgrade", reason)
+
+	allowed, reason = calendar.IsTradingAllowed(time.Date(2024, 4, 1, 8, 30, 0, 0, time.UTC))
+	assert.False(t, allowed)
+	assert.Equal(t, "exchange maintenance", reason)
+
+	allowed, reason = calendar.IsTradingAllowed(time.Date(2024, 6, 1, 0, 2, 0, 0, time.UTC))
+	assert.False(t, allowed)
+	assert.Equal(t, "daily settlement", reason)
+
+	allowed, _ = calendar.IsTradingAllowed(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+	assert.True(t, allowed)
+}
+
+func TestBuildTradingCalendar_InvalidConfig(t *testing.T) {
+	_, err := BuildTradingCalendar("BTCUSDT", []CalendarWindowConfig{
+		{Symbol: "BTCUSDT", Start: "25:00", End: "01:00", Daily: true},
+	}, nil)
+	assert.Error(t, err)
+
+	_, err = BuildTradingCalendar("BTCUSDT", []CalendarWindowConfig{
+		{Symbol: "BTCUSDT", Start: "2024-03-01 04:00", End: "2024-03-01 02:00"},
+	}, nil)
+	assert.Error(t, err)
+
+	calendar, err := BuildTradingCalendar("BTCUSDT", nil, nil)
+	require.NoError(t, err)
+	assert.True(t, calendar.IsEmpty())
+}
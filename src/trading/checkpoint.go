@@ -0,0 +1,87 @@
+package trading
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// BacktestCheckpointKey 回测参数的摘要：交易所、交易对、周期、策略及参数、时间范围、初始资金和交易配置
+// （断点设置、显示格式和市场状态报告除外），只有相同摘要的回测才能从断点继续
+func BacktestCheckpointKey(exchange string, pair cex.TradingPair, timeframe timeframes.Timeframe, strategyName string, params strategy.StrategyParams, startTime, endTime time.Time, initialCapital float64) (string, error) {
+	config := TradingConfigValue
+	config.Backtest.CheckpointFile = ""
+	config.Backtest.CheckpointEveryBars = 0
+	config.Backtest.NumberNotation = ""
+	config.Backtest.Regimes = RegimeConfig{}
+
+	data, err := json.Marshal(struct {
+		Exchange       string                  `json:"exchange"`
+		Symbol         string                  `json:"symbol"`
+		Timeframe      string                  `json:"timeframe"`
+		StrategyName   string                  `json:"strategy_name"`
+		StrategyParams strategy.StrategyParams `json:"strategy_params"`
+		StartTime      time.Time               `json:"start_time"`
+		EndTime        time.Time               `json:"end_time"`
+		InitialCapital float64                 `json:"initial_capital"`
+		Config         TradingConfig           `json:"config"`
+	}{exchange, DatabaseSymbol(pair), timeframe.String(), strategyName, params, startTime, endTime, initialCapital, config})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backtest parameters: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// setupBacktestCheckpoint 配置了断点文件时让引擎定期保存断点，已有相同参数的断点时从断点继续
+func (ts *TradingSystem) setupBacktestCheckpoint(tradingEngine *engine.TradingEngine, pair cex.TradingPair, timeframe timeframes.Timeframe, strategyName string, params strategy.StrategyParams, startTime, endTime time.Time, initialCapital float64) error {
+	path := TradingConfigValue.Backtest.CheckpointFile
+	if path == "" {
+		return nil
+	}
+	_, logger := log.WithCtx(ts.ctx)
+
+	key, err := BacktestCheckpointKey(ts.cexName, pair, timeframe, strategyName, params, startTime, endTime, initialCapital)
+	if err != nil {
+		return err
+	}
+	if err := tradingEngine.SetCheckpoint(path, TradingConfigValue.Backtest.CheckpointEveryBars, key); err != nil {
+		return fmt.Errorf("invalid backtest checkpoint config: %w", err)
+	}
+
+	checkpoint, err := engine.LoadBacktestCheckpoint(path)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		logger.Info(fmt.Sprintf("💾 回测断点: file=%s, every=%d bars", path, TradingConfigValue.Backtest.CheckpointEveryBars))
+		return nil
+	}
+	if err := tradingEngine.ResumeFrom(checkpoint); err != nil {
+		return fmt.Errorf("cannot resume from %s (delete it to start over): %w", path, err)
+	}
+	return nil
+}
+
+// removeBacktestCheckpoint 回测完成后删除断点文件，下次以相同参数运行时从头开始
+func (ts *TradingSystem) removeBacktestCheckpoint() {
+	path := TradingConfigValue.Backtest.CheckpointFile
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		_, logger := log.WithCtx(ts.ctx)
+		logger.Warning(fmt.Sprintf("⚠️ 删除回测断点失败: file=%s, error=%v", path, err))
+	}
+}
@@ -0,0 +1,44 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacktestCheckpointKey(t *testing.T) {
+	original := TradingConfigValue
+	defer func() { TradingConfigValue = original }()
+
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	params := strategy.GetDefaultBollingerBandsParams()
+	key := func() string {
+		k, err := BacktestCheckpointKey("binance", pair, timeframes.Timeframe1h, "BollingerBands", params, start, end, 10000)
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key()
+	assert.Len(t, base, 64)
+
+	// 断点设置和显示格式不影响摘要
+	TradingConfigValue.Backtest.CheckpointFile = "other.json"
+	TradingConfigValue.Backtest.CheckpointEveryBars = 50
+	TradingConfigValue.Backtest.NumberNotation = "compact"
+	assert.Equal(t, base, key())
+
+	// 策略参数或交易配置不同时不能从断点继续
+	params.Period = 30
+	changed := key()
+	assert.NotEqual(t, base, changed)
+	TradingConfigValue.PositionSizePercent = 0.5
+	assert.NotEqual(t, changed, key())
+}
@@ -1,6 +1,17 @@
 package trading
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/envconfig"
+	"tradingbot/src/executor"
+	"tradingbot/src/strategies"
+	"tradingbot/src/timeframes"
+
 	"github.com/xpwu/go-config/configs"
 )
 
@@ -10,6 +21,389 @@ type TradingConfig struct {
 	MaxPositions        int     `json:"max_positions"`         // 最大持仓数
 	PositionSizePercent float64 `json:"position_size_percent"` // 仓位比例
 	MinTradeAmount      float64 `json:"min_trade_amount"`      // 最小交易额
+	SaveBacktest        bool    `json:"save_backtest"`         // 回测结果是否持久化到数据库
+
+	// 信号挂单有效方式（GTC、IOC、FOK、GTD），策略信号指定时以信号为准，为空时为 GTD
+	TimeInForce        string  `json:"time_in_force"`
+	OrderValidityHours float64 `json:"order_validity_hours"` // GTD 挂单有效期（小时），0 为 24 小时
+
+	// 信号买入挂单连续未成交时追价：撤单后按更接近市场的价格重挂（默认不追价）
+	OrderChase engine.OrderChasePolicy `json:"order_chase"`
+
+	// 引擎层信号节流：同方向信号冷却、重复信号抑制、每个方向挂单数量上限（默认不节流）
+	SignalThrottle engine.SignalThrottle `json:"signal_throttle"`
+
+	// 仓位计算方式（默认按 PositionSizePercent 固定比例）
+	PositionSizing PositionSizingConfig `json:"position_sizing"`
+
+	// 实盘每个交易对的挂单数量限制（0 表示不限制）
+	MaxOpenOrdersSoft int `json:"max_open_orders_soft"` // 软上限：超过后输出警告
+	MaxOpenOrdersHard int `json:"max_open_orders_hard"` // 硬上限：超过后拒绝挂单（Binance 为 200）
+
+	// 交易日历：禁止交易时段（维护、集合竞价等）
+	NoTradeWindows []CalendarWindowConfig `json:"no_trade_windows"`
+
+	// 回测撮合：滑点、成交量上限、部分成交
+	Backtest BacktestConfig `json:"backtest"`
+
+	// 批量回测（bollinger batch）的交易对列表：BASE/QUOTE，或只写 BASE 时使用 -quote
+	Symbols []string `json:"symbols"`
+
+	// 回测记账货币（如 USDT）：计价货币不同时用换算交易对（如 BTC/USDT）的K线换算盈亏和资金曲线，为空时不换算
+	AccountingCurrency string `json:"accounting_currency"`
+
+	// 回测流动性成交模型（PEPE/WIF 等低流动性币种）
+	IlliquidFill IlliquidFillConfig `json:"illiquid_fill"`
+
+	// 历史K线同步（sync 命令）
+	Sync SyncConfig `json:"sync"`
+
+	// 永续合约资金费率和持仓量同步（futures-sync 命令），策略可作为辅助数据过滤开仓（现货交易也可使用）
+	FuturesData FuturesDataConfig `json:"futures_data"`
+
+	// 实盘对账：定期核对交易所挂单和余额
+	Reconcile ReconcileConfig `json:"reconcile"`
+
+	// 实盘订阅交易所账户数据流，成交回报和余额变化实时推送（交易所支持时生效）
+	UserDataStream bool `json:"user_data_stream"`
+
+	// 实盘定期从交易所刷新 symbols 表和当前交易对的下单规则（小时），0 表示只在启动时加载
+	SymbolRefreshHours int `json:"symbol_refresh_hours"`
+
+	// 实盘和 Dry Run 保存账户快照（余额、持仓、按市价计算的权益）到 equity_snapshots 表的间隔（分钟），0 表示不保存
+	EquitySnapshotMinutes int `json:"equity_snapshot_minutes"`
+
+	// 实盘和 Dry Run 记录盘口买一卖一到 book_tickers 表的间隔（秒），回测可按记录的价差模拟吃单成本，0 表示不记录
+	BookTickerSeconds int `json:"book_ticker_seconds"`
+
+	// 实盘和 Dry Run 停止（Ctrl+C、bots stop）时对挂单和持仓的处理，默认都保留
+	Shutdown engine.ShutdownPolicy `json:"shutdown"`
+
+	// 实盘和 Dry Run 超过 N 个K线周期没有新K线时判定数据喂入停滞：发送 feed_stalled 告警、重启数据喂入，/healthz 返回 503（0 表示不检查）
+	FeedStallTimeframes float64 `json:"feed_stall_timeframes"`
+
+	// 实盘和 Dry Run 引擎意外退出（panic、运行错误）后的自动重启：一小时内的重启次数上限和重启前等待时间
+	Supervisor SupervisorConfig `json:"supervisor"`
+
+	// 实盘和 Dry Run 延迟告警：K线收盘到处理的延迟、下单往返耗时、本地时钟与交易所的偏差（0 表示不告警）
+	Latency engine.LatencyLimits `json:"latency"`
+
+	// 全局风控：持仓市值、交易对敞口、单日亏损、连续亏损限制（0 表示不限制）
+	Risk engine.RiskLimits `json:"risk"`
+
+	// 实盘卖出策略：配置 Name 后覆盖命令行的 -sell-strategy / -sell-strategy-params
+	SellStrategy SellStrategyConfig `json:"sell_strategy"`
+
+	// 实盘检查配置文件变更的间隔（秒），风控限制、通知和卖出策略的修改无需重启即生效，0 表示不检查
+	ConfigReloadSeconds int `json:"config_reload_seconds"`
+
+	// 多机器人：同一进程内运行多个交易对/策略/交易所的实盘或 Dry Run（bots 命令）
+	Bots BotsConfig `json:"bots"`
+}
+
+// BotsConfig 多机器人配置
+type BotsConfig struct {
+	Bots      []BotConfig         `json:"bots"`
+	RateLimit cex.RateLimitConfig `json:"rate_limit"` // 同一交易所的所有机器人共享的 REST 请求限频（每个交易所一个令牌桶）
+}
+
+// BotConfig 单个机器人配置
+type BotConfig struct {
+	Name           string  `json:"name"`            // 名称（唯一），用于启停和日志前缀
+	Exchange       string  `json:"exchange"`        // 交易所（binance、bybit），默认 binance
+	Base           string  `json:"base"`            // 基础货币（如 BTC）
+	Quote          string  `json:"quote"`           // 计价货币（如 USDT）
+	Timeframe      string  `json:"timeframe"`       // K线周期，默认使用 Timeframe
+	Strategy       string  `json:"strategy"`        // 已注册的策略名称，默认 bollinger
+	ParamsFile     string  `json:"params_file"`     // JSON 策略参数文件，为空时使用策略默认参数
+	DryRun         bool    `json:"dry_run"`         // 模拟盘：实时行情，按盘口模拟成交
+	Session        string  `json:"session"`         // Dry Run 模拟盘会话名（为空时按交易所和交易对生成）
+	SignalOnly     bool    `json:"signal_only"`     // 只发信号：实时行情，信号发送到通知，不下单（优先于 DryRun）
+	InitialCapital float64 `json:"initial_capital"` // Dry Run 新建会话的初始资金，默认 10000
+	AutoStart      bool    `json:"auto_start"`      // 管理器启动时自动启动
+}
+
+// Validate 检查机器人配置
+func (c BotConfig) Validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, "/ ") {
+		return fmt.Errorf("bot name must be non-empty without spaces or slashes, got %q", c.Name)
+	}
+	if c.Base == "" || c.Quote == "" {
+		return fmt.Errorf("bot %s: base and quote are required", c.Name)
+	}
+	if c.Timeframe != "" {
+		if _, err := timeframes.ParseTimeframe(c.Timeframe); err != nil {
+			return fmt.Errorf("bot %s: invalid timeframe: %w", c.Name, err)
+		}
+	}
+	if c.Strategy != "" {
+		if _, exists := strategies.StrategyFactoryRegistry[c.Strategy]; !exists {
+			return fmt.Errorf("bot %s: unsupported strategy %s (available: %v)", c.Name, c.Strategy, strategies.GetSupportedStrategies())
+		}
+	}
+	if c.InitialCapital < 0 {
+		return fmt.Errorf("bot %s: InitialCapital must be non-negative, got %v", c.Name, c.InitialCapital)
+	}
+	return nil
+}
+
+// Validate 检查多机器人配置（名称不能重复）
+func (c BotsConfig) Validate() error {
+	names := make(map[string]bool, len(c.Bots))
+	for _, bot := range c.Bots {
+		if err := bot.Validate(); err != nil {
+			return err
+		}
+		if names[bot.Name] {
+			return fmt.Errorf("duplicate bot name %s", bot.Name)
+		}
+		names[bot.Name] = true
+	}
+	if err := c.RateLimit.Validate(); err != nil {
+		return fmt.Errorf("invalid RateLimit: %w", err)
+	}
+	return nil
+}
+
+// SellStrategyConfig 实盘卖出策略配置
+type SellStrategyConfig struct {
+	Name   string              `json:"name"`   // 卖出策略名称（如 moderate、trailing_5、partial_pyramid），为空时使用命令行参数
+	Params []SellStrategyParam `json:"params"` // 覆盖预设的用户参数（take_profit、trailing_percent、min_profit 等）
+}
+
+// SellStrategyParam 卖出策略用户参数
+type SellStrategyParam struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// ParamMap 用户参数（未配置时返回 nil）
+func (c SellStrategyConfig) ParamMap() map[string]float64 {
+	if len(c.Params) == 0 {
+		return nil
+	}
+	params := make(map[string]float64, len(c.Params))
+	for _, param := range c.Params {
+		params[param.Name] = param.Value
+	}
+	return params
+}
+
+// NewRiskManager 根据配置创建风控管理器，未配置任何限制时返回 nil
+func (c TradingConfig) NewRiskManager() (*engine.RiskManager, error) {
+	if err := c.Risk.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid risk config: %w", err)
+	}
+	if !c.Risk.Enabled() {
+		return nil, nil
+	}
+	return engine.NewRiskManager(c.Risk), nil
+}
+
+// ApplyTimeInForce 按配置设置引擎的信号挂单有效方式
+func (c TradingConfig) ApplyTimeInForce(tradingEngine *engine.TradingEngine) error {
+	tif, err := cex.ParseTimeInForce(c.TimeInForce)
+	if err != nil {
+		return err
+	}
+	if c.OrderValidityHours < 0 {
+		return fmt.Errorf("OrderValidityHours cannot be negative, got %v", c.OrderValidityHours)
+	}
+	tradingEngine.SetTimeInForce(tif, time.Duration(c.OrderValidityHours*float64(time.Hour)))
+	return nil
+}
+
+// ReconcileConfig 实盘对账配置
+type ReconcileConfig struct {
+	IntervalSeconds int     `json:"interval_seconds"` // 对账间隔（秒），0 表示只在启动时对账一次
+	Tolerance       float64 `json:"tolerance"`        // 余额相对偏差容差，超出后按交易所余额校正本地状态
+}
+
+// SyncConfig 历史K线同步配置
+type SyncConfig struct {
+	Pairs             []string `json:"pairs"`               // 交易对，格式 BASE/QUOTE（如 BTC/USDT）
+	Timeframes        []string `json:"timeframes"`          // K线周期（如 1h、4h）
+	StartDate         string   `json:"start_date"`          // 首次同步的起始日期（UTC）
+	BatchSize         int      `json:"batch_size"`          // 每次请求的K线数量（币安上限 1000）
+	RequestIntervalMs int      `json:"request_interval_ms"` // 两次请求之间的最小间隔（毫秒），用于遵守交易所限频
+	MaxRetries        int      `json:"max_retries"`         // 请求失败后的重试次数（指数退避）
+}
+
+// FuturesDataConfig 永续合约辅助数据同步配置
+type FuturesDataConfig struct {
+	Pairs              []string `json:"pairs"`                // 交易对（对应同名U本位永续合约），为空时使用 Sync.Pairs
+	StartDate          string   `json:"start_date"`           // 首次同步资金费率的起始日期（UTC），为空时使用 Sync.StartDate
+	OpenInterestPeriod string   `json:"open_interest_period"` // 持仓量统计周期（5m/15m/30m/1h/2h/4h/6h/12h/1d），币安只保留最近 30 天
+	RefreshMinutes     int      `json:"refresh_minutes"`      // 实盘和 Dry Run 同步并刷新当前交易对合约数据的间隔（分钟），0 表示只在启动时从数据库加载
+}
+
+// 仓位计算方式
+const (
+	SizingFixedPercent  = "fixed_percent"  // 可用现金 × PositionSizePercent
+	SizingFixedNotional = "fixed_notional" // 每次买入固定金额
+	SizingKelly         = "kelly"          // 凯利公式
+	SizingVolatility    = "volatility"     // 按 ATR 止损距离控制每笔风险
+)
+
+// PositionSizingConfig 仓位计算配置
+type PositionSizingConfig struct {
+	Method             string  `json:"method"`               // fixed_percent / fixed_notional / kelly / volatility，空为 fixed_percent
+	Notional           float64 `json:"notional"`             // fixed_notional：每次买入金额
+	KellyWinRate       float64 `json:"kelly_win_rate"`       // kelly：胜率
+	KellyPayoffRatio   float64 `json:"kelly_payoff_ratio"`   // kelly：平均盈利 / 平均亏损
+	KellyFraction      float64 `json:"kelly_fraction"`       // kelly：凯利比例缩放（0.5 为半凯利）
+	RiskPercent        float64 `json:"risk_percent"`         // volatility：每笔交易承担的权益风险比例
+	ATRPeriod          int     `json:"atr_period"`           // volatility：ATR 周期
+	ATRMultiplier      float64 `json:"atr_multiplier"`       // volatility：止损距离的 ATR 倍数
+	MaxPositionPercent float64 `json:"max_position_percent"` // kelly/volatility：单次仓位占权益的上限
+}
+
+// SupportedSizingMethods 支持的仓位计算方式
+func SupportedSizingMethods() []string {
+	return []string{SizingFixedPercent, SizingFixedNotional, SizingKelly, SizingVolatility}
+}
+
+// NewPositionSizer 根据配置创建仓位计算器，fixed_percent 使用 positionSizePercent
+func (c PositionSizingConfig) NewPositionSizer(positionSizePercent float64) (engine.PositionSizer, error) {
+	var sizer interface {
+		engine.PositionSizer
+		Validate() error
+	}
+
+	switch c.Method {
+	case "", SizingFixedPercent:
+		sizer = engine.NewFixedPercentSizer(positionSizePercent)
+	case SizingFixedNotional:
+		sizer = engine.NewFixedNotionalSizer(c.Notional)
+	case SizingKelly:
+		sizer = engine.NewKellySizer(c.KellyWinRate, c.KellyPayoffRatio, c.KellyFraction, c.MaxPositionPercent)
+	case SizingVolatility:
+		sizer = engine.NewVolatilitySizer(c.RiskPercent, c.ATRPeriod, c.ATRMultiplier, c.MaxPositionPercent)
+	default:
+		return nil, fmt.Errorf("unknown position sizing method %q (supported: %v)", c.Method, SupportedSizingMethods())
+	}
+
+	if err := sizer.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid position sizing config: %w", err)
+	}
+	return sizer, nil
+}
+
+// BacktestConfig 回测撮合配置，默认按挂单价完美成交
+type BacktestConfig struct {
+	SlippageBps      float64 `json:"slippage_bps"`      // 固定滑点（基点），0 表示不加滑点
+	MaxParticipation float64 `json:"max_participation"` // 单根K线最多成交该K线成交量的比例，0 表示不限制
+	PartialFills     bool    `json:"partial_fills"`     // 在成交量上限内按随机比例部分成交（需要 max_participation）
+	Seed             int64   `json:"seed"`              // 随机种子（相同种子结果可复现）
+
+	// 交易分析的持仓批次匹配方式（fifo / lifo / average），决定部分卖出和加仓时每笔交易的成本、持仓时间和盈亏
+	LotMatching string `json:"lot_matching"`
+
+	// 资金成本：闲置现金收益和持仓成本（年化），每根K线计提，默认不计提
+	CarryingCost executor.CarryingCostModel `json:"carrying_cost"`
+
+	// 从数据库分批流式读取K线（需先 sync），多年 1m 回测不把全部K线放进内存
+	StreamKlines    bool `json:"stream_klines"`
+	StreamBatchSize int  `json:"stream_batch_size"` // 每批读取的K线数量
+
+	// K线内撮合使用的细粒度K线周期（如 1s、1m，需先 sync），有挂单时按时间顺序逐根撮合，空表示按整根K线撮合
+	IntrabarTimeframe string `json:"intrabar_timeframe"`
+
+	// 按实盘/Dry Run 记录的盘口价差（book_ticker_seconds）模拟吃单成本：市价、止损单成交价加上半个价差（按 UTC 小时统计）
+	CapturedSpread     bool `json:"captured_spread"`
+	SpreadLookbackDays int  `json:"spread_lookback_days"` // 只使用最近多少天的记录，0 表示全部
+
+	// 回测断点文件：每 CheckpointEveryBars 根K线保存一次状态，中断后以相同参数重新运行时从断点继续，完成后删除；空表示不保存
+	CheckpointFile      string `json:"checkpoint_file"`
+	CheckpointEveryBars int    `json:"checkpoint_every_bars"`
+
+	// 交易明细中数量和价格的显示方式（fixed / scientific / compact），默认 fixed
+	NumberNotation string `json:"number_notation"`
+
+	// 按均线斜率把回测区间划分为上涨趋势/下跌趋势/震荡，报告各市场状态下的策略表现
+	Regimes RegimeConfig `json:"regimes"`
+}
+
+// NewFillModels 根据配置创建成交模型列表
+func (c BacktestConfig) NewFillModels() ([]engine.FillModel, error) {
+	var models []engine.FillModel
+
+	if c.SlippageBps != 0 {
+		model := engine.NewFixedSlippageFillModel(c.SlippageBps)
+		if err := model.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid backtest config: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	if c.MaxParticipation != 0 {
+		model := engine.NewVolumeCapFillModel(c.MaxParticipation, c.PartialFills, c.Seed)
+		if err := model.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid backtest config: %w", err)
+		}
+		models = append(models, model)
+	} else if c.PartialFills {
+		return nil, fmt.Errorf("invalid backtest config: PartialFills requires MaxParticipation")
+	}
+
+	return models, nil
+}
+
+// NewFillModel 组合回测撮合配置和流动性成交模型，全部未启用时返回 nil（按挂单价完美成交）
+func (c TradingConfig) NewFillModel() (engine.FillModel, error) {
+	models, err := c.Backtest.NewFillModels()
+	if err != nil {
+		return nil, err
+	}
+
+	illiquid, err := c.IlliquidFill.NewFillModel()
+	if err != nil {
+		return nil, err
+	}
+	if illiquid != nil {
+		models = append(models, illiquid)
+	}
+
+	return engine.NewChainFillModel(models...), nil
+}
+
+// SetSeed 设置所有随机成交模型（部分成交、流动性成交模型）的种子
+func (c *TradingConfig) SetSeed(seed int64) {
+	c.Backtest.Seed = seed
+	c.IlliquidFill.Seed = seed
+}
+
+// IlliquidFillConfig 流动性感知成交模型配置，参与率 = 订单金额 / K线成交额
+type IlliquidFillConfig struct {
+	Enabled               bool    `json:"enabled"`                 // 是否启用
+	SlippageCoefficient   float64 `json:"slippage_coefficient"`    // 滑点 = 系数 × 参与率^指数
+	SlippageExponent      float64 `json:"slippage_exponent"`       // 滑点曲线指数（0.5 为平方根冲击）
+	MaxSlippage           float64 `json:"max_slippage"`            // 最大滑点比例
+	HalfFillParticipation float64 `json:"half_fill_participation"` // 成交概率为50%时的参与率
+	FillCurveExponent     float64 `json:"fill_curve_exponent"`     // 成交概率曲线陡峭程度
+	Seed                  int64   `json:"seed"`                    // 随机种子（相同种子结果可复现）
+}
+
+// NewFillModel 根据配置创建成交模型，未启用时返回 nil
+func (c IlliquidFillConfig) NewFillModel() (*engine.IlliquidityFillModel, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	model := engine.NewIlliquidityFillModel(c.SlippageCoefficient, c.SlippageExponent, c.MaxSlippage,
+		c.HalfFillParticipation, c.FillCurveExponent, c.Seed)
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid illiquid fill config: %w", err)
+	}
+	return model, nil
+}
+
+// CalendarWindowConfig 禁止交易时段配置（时间均为UTC）
+type CalendarWindowConfig struct {
+	Symbol string `json:"symbol"` // 交易对（如 BTCUSDT），"*" 对所有交易对生效
+	Start  string `json:"start"`  // 一次性: "2024-03-01 02:00"；每日: "02:00"
+	End    string `json:"end"`    // 一次性: "2024-03-01 04:00"；每日: "02:30"
+	Daily  bool   `json:"daily"`  // 是否每日重复
+	Reason string `json:"reason"` // 暂停原因（维护、集合竞价等）
 }
 
 // TradingConfigValue 交易配置实例
@@ -18,8 +412,89 @@ var TradingConfigValue = TradingConfig{
 	MaxPositions:        1,
 	PositionSizePercent: 0.95,
 	MinTradeAmount:      10.0,
+	PositionSizing: PositionSizingConfig{
+		Method:             SizingFixedPercent,
+		Notional:           1000,
+		KellyWinRate:       0.5,
+		KellyPayoffRatio:   1.5,
+		KellyFraction:      0.5,
+		RiskPercent:        0.01,
+		ATRPeriod:          14,
+		ATRMultiplier:      2,
+		MaxPositionPercent: 0.95,
+	},
+	SaveBacktest:      false,
+	MaxOpenOrdersSoft: 150,
+	MaxOpenOrdersHard: 200,
+	NoTradeWindows:    []CalendarWindowConfig{},
+	Symbols:           []string{},
+	Backtest: BacktestConfig{
+		SlippageBps:         0,
+		MaxParticipation:    0,
+		PartialFills:        false,
+		Seed:                1,
+		LotMatching:         LotMatchingFIFO,
+		StreamBatchSize:     DefaultKlineStreamBatchSize,
+		CheckpointEveryBars: 1000,
+	},
+	IlliquidFill: IlliquidFillConfig{
+		Enabled:               false,
+		SlippageCoefficient:   0.1, // 参与率1%时滑点1%
+		SlippageExponent:      0.5,
+		MaxSlippage:           0.05,
+		HalfFillParticipation: 0.1,
+		FillCurveExponent:     2,
+		Seed:                  1,
+	},
+	Sync: SyncConfig{
+		Pairs:             []string{"BTC/USDT"},
+		Timeframes:        []string{"4h"},
+		StartDate:         "2024-01-01",
+		BatchSize:         1000,
+		RequestIntervalMs: 250, // 币安K线接口权重为2，每分钟上限6000
+		MaxRetries:        3,
+	},
+	FuturesData: FuturesDataConfig{
+		Pairs:              []string{},
+		OpenInterestPeriod: "1h",
+		RefreshMinutes:     60,
+	},
+	Reconcile: ReconcileConfig{
+		IntervalSeconds: 60,
+		Tolerance:       0.001,
+	},
+	UserDataStream:        true,
+	SymbolRefreshHours:    24,
+	EquitySnapshotMinutes: 60,
+	Latency: engine.LatencyLimits{
+		MaxDataDelayMs:    10000,
+		MaxOrderLatencyMs: 2000,
+		MaxClockDriftMs:   1000,
+		ClockCheckMinutes: 10,
+	},
+	Risk: engine.RiskLimits{
+		SymbolExposure: []engine.SymbolExposure{},
+	},
+	SellStrategy: SellStrategyConfig{
+		Name:   "",
+		Params: []SellStrategyParam{},
+	},
+	FeedStallTimeframes: 2,
+	Supervisor: SupervisorConfig{
+		MaxRestartsPerHour:  3,
+		RestartDelaySeconds: 10,
+	},
+	ConfigReloadSeconds: 10,
+	Bots: BotsConfig{
+		Bots: []BotConfig{},
+		RateLimit: cex.RateLimitConfig{
+			RequestsPerSecond: 10, // 保守取值，远低于币安每分钟 6000 权重的上限
+			Burst:             20,
+		},
+	},
 }
 
 func init() {
 	configs.Unmarshal(&TradingConfigValue)
+	envconfig.Register("TRADING", &TradingConfigValue)
 }
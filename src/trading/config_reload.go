@@ -0,0 +1,251 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/envconfig"
+	"tradingbot/src/logging"
+	"tradingbot/src/notify"
+	"tradingbot/src/strategy"
+
+	"github.com/xpwu/go-config/configs"
+	"github.com/xpwu/go-log/log"
+	"github.com/xpwu/go-x/jsontype"
+)
+
+// 可热更新的配置段
+const (
+	configSectionRisk         = "risk"
+	configSectionNotify       = "notify"
+	configSectionSellStrategy = "sell_strategy"
+)
+
+// ConfigReloader 实盘运行时检查配置文件，风控限制、通知和卖出策略的修改校验通过后立即生效，
+// 每次变更（包括校验失败未生效的）都写入审计日志；其他配置的修改仍需重启
+type ConfigReloader struct {
+	path    string
+	modTime time.Time
+
+	risk     *engine.RiskManager
+	router   *notify.Router    // 为空时不更新通知
+	strategy strategy.Strategy // 不支持 strategy.SellStrategyUpdater 时不更新卖出策略
+	audit    *logging.AuditLog // 为空时只写日志
+
+	// 最近一次生效的配置
+	trading TradingConfig
+	notify  notify.Config
+}
+
+// NewConfigReloader 创建配置热更新，以当前生效的配置为基准
+func NewConfigReloader(path string, risk *engine.RiskManager, router *notify.Router, strategyImpl strategy.Strategy, audit *logging.AuditLog) *ConfigReloader {
+	reloader := &ConfigReloader{
+		path:     path,
+		risk:     risk,
+		router:   router,
+		strategy: strategyImpl,
+		audit:    audit,
+		trading:  TradingConfigValue,
+		notify:   notify.ConfigValue,
+	}
+	if info, err := os.Stat(path); err == nil {
+		reloader.modTime = info.ModTime()
+	}
+	return reloader
+}
+
+// ConfigFilePath 启动时读取的配置文件路径（未通过配置文件启动时返回空）
+func ConfigFilePath() string {
+	jsonConfig, ok := configs.GetConfigurator().(*configs.JsonConfig)
+	if !ok {
+		return ""
+	}
+	path := jsonConfig.ReadFile
+	if path == "" {
+		path = "config.json" // 与 go-config 的默认文件一致
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Run 按间隔检查配置文件修改时间，变化时重新加载，ctx 取消后退出
+func (r *ConfigReloader) Run(ctx context.Context, interval time.Duration) {
+	ctx, logger := log.WithCtx(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				logger.Warning(fmt.Sprintf("⚠️ 检查配置文件失败: file=%s, error=%v", r.path, err))
+				continue
+			}
+			if info.ModTime().Equal(r.modTime) {
+				continue
+			}
+			r.modTime = info.ModTime()
+			if err := r.Reload(ctx); err != nil {
+				logger.Error(fmt.Sprintf("❌ 配置热更新失败，相关配置保持不变: file=%s, error=%v", r.path, err))
+			}
+		}
+	}
+}
+
+// Reload 重新读取配置文件并应用有变化的配置段，无效的配置段不生效，其余照常应用
+func (r *ConfigReloader) Reload(ctx context.Context) error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	tradingConfig, notifyConfig := r.trading, r.notify
+	if err := parseConfigSections(data, &tradingConfig, &notifyConfig); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", r.path, err)
+	}
+	// 环境变量优先于配置文件，重新读取后同样覆盖
+	if _, err := envconfig.ApplyTo(os.LookupEnv, &tradingConfig, &notifyConfig); err != nil {
+		return err
+	}
+
+	var errs []error
+	if !reflect.DeepEqual(tradingConfig.Risk, r.trading.Risk) {
+		err := r.applyRisk(tradingConfig.Risk)
+		r.record(ctx, configSectionRisk, r.trading.Risk, tradingConfig.Risk, err)
+		if err == nil {
+			r.trading.Risk = tradingConfig.Risk
+		}
+		errs = append(errs, err)
+	}
+	if !reflect.DeepEqual(notifyConfig, r.notify) {
+		err := r.applyNotify(notifyConfig)
+		r.record(ctx, configSectionNotify, newNotifyAuditValue(r.notify), newNotifyAuditValue(notifyConfig), err)
+		if err == nil {
+			r.notify = notifyConfig
+		}
+		errs = append(errs, err)
+	}
+	if !reflect.DeepEqual(tradingConfig.SellStrategy, r.trading.SellStrategy) {
+		err := r.applySellStrategy(tradingConfig.SellStrategy)
+		r.record(ctx, configSectionSellStrategy, r.trading.SellStrategy, tradingConfig.SellStrategy, err)
+		if err == nil {
+			r.trading.SellStrategy = tradingConfig.SellStrategy
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// applyRisk 更新风控限制
+func (r *ConfigReloader) applyRisk(limits engine.RiskLimits) error {
+	if r.risk == nil {
+		return fmt.Errorf("risk manager is not running")
+	}
+	if err := r.risk.SetLimits(limits); err != nil {
+		return fmt.Errorf("invalid risk config: %w", err)
+	}
+	return nil
+}
+
+// applyNotify 更新通知后端和路由规则（队列长度修改需重启）
+func (r *ConfigReloader) applyNotify(config notify.Config) error {
+	if r.router == nil {
+		return fmt.Errorf("notification router is not running")
+	}
+	return config.ReloadRouter(r.router)
+}
+
+// applySellStrategy 替换卖出策略，名称为空时保持当前卖出策略
+func (r *ConfigReloader) applySellStrategy(config SellStrategyConfig) error {
+	if config.Name == "" {
+		return nil
+	}
+	updater, ok := r.strategy.(strategy.SellStrategyUpdater)
+	if !ok {
+		return fmt.Errorf("%s does not support updating the sell strategy", r.strategy.GetName())
+	}
+	return updater.UpdateSellStrategy(config.Name, config.ParamMap())
+}
+
+// record 记录配置变更到日志和审计日志
+func (r *ConfigReloader) record(ctx context.Context, section string, oldValue, newValue interface{}, err error) {
+	_, logger := log.WithCtx(ctx)
+
+	record := &logging.ConfigChangeRecord{Time: time.Now().UTC(), Section: section, Old: oldValue, New: newValue}
+	if err != nil {
+		record.Error = err.Error()
+		logger.Error(fmt.Sprintf("❌ 配置变更未生效: section=%s, error=%v", section, err))
+	} else {
+		logger.Warning(fmt.Sprintf("🔧 配置已热更新: section=%s, old=%+v, new=%+v", section, oldValue, newValue))
+	}
+	if r.audit != nil {
+		r.audit.RecordConfigChange(ctx, record)
+	}
+}
+
+// notifyAuditValue 通知配置变更的审计内容（不记录令牌、密码等凭据）
+type notifyAuditValue struct {
+	Notifiers []string             `json:"notifiers"`
+	Routes    []notify.RouteConfig `json:"routes"`
+	QueueSize int                  `json:"queue_size"`
+}
+
+func newNotifyAuditValue(config notify.Config) notifyAuditValue {
+	notifiers, _ := config.Notifiers()
+	names := make([]string, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		names = append(names, notifier.Name())
+	}
+	return notifyAuditValue{Notifiers: names, Routes: config.Routes, QueueSize: config.QueueSize}
+}
+
+// parseConfigSections 按 go-config 的格式（"包路径:类型名" 为键、字段名为配置键）解析交易和通知配置段，
+// 文件中未出现的字段保持传入的值
+func parseConfigSections(data []byte, tradingConfig *TradingConfig, notifyConfig *notify.Config) error {
+	values, err := jsontype.FromJson(data)
+	if err != nil {
+		return err
+	}
+
+	sections := map[string]interface{}{
+		configKey(tradingConfig): tradingConfig,
+		configKey(notifyConfig):  notifyConfig,
+	}
+	// 本项目配置未使用 conf 标签，配置键即字段名
+	return jsontype.ToGoType(values, &sections, func(tag reflect.StructTag) string { return "" })
+}
+
+// configKey go-config 的配置段键
+func configKey(config interface{}) string {
+	t := reflect.TypeOf(config).Elem()
+	return t.PkgPath() + ":" + t.Name()
+}
+
+// startConfigReload 按配置的间隔在后台检查配置文件，风控限制、通知和卖出策略的修改无需重启即生效
+func (ts *TradingSystem) startConfigReload(risk *engine.RiskManager, router *notify.Router, strategyImpl strategy.Strategy, audit *logging.AuditLog) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	seconds := TradingConfigValue.ConfigReloadSeconds
+	if seconds <= 0 {
+		return
+	}
+	path := ConfigFilePath()
+	if path == "" {
+		logger.Warning("⚠️ 未使用配置文件启动，配置热更新不可用")
+		return
+	}
+
+	reloader := NewConfigReloader(path, risk, router, strategyImpl, audit)
+	go reloader.Run(ts.ctx, time.Duration(seconds)*time.Second)
+	logger.Info(fmt.Sprintf("✓ 配置热更新: file=%s, ConfigReloadSeconds=%d", path, seconds))
+}
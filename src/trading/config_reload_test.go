@@ -0,0 +1,148 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/logging"
+	"tradingbot/src/notify"
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigSections(t *testing.T) {
+	tradingConfig := TradingConfigValue
+	tradingConfig.Risk = engine.RiskLimits{MaxDailyLoss: 100, SymbolExposure: []engine.SymbolExposure{}}
+	notifyConfig := notify.ConfigValue
+
+	data := []byte(`{
+		"tradingbot/src/trading:TradingConfig": {
+			"Risk": {"MaxConsecutiveLosses": 3},
+			"SellStrategy": {"Name": "trailing_5", "Params": [{"Name": "trailing_percent", "Value": 0.03}]}
+		},
+		"tradingbot/src/notify:Config": {"QueueSize": 7},
+		"tradingbot/src/cex/binance:Config": {"APIKey": "ignored"}
+	}`)
+	require.NoError(t, parseConfigSections(data, &tradingConfig, &notifyConfig))
+
+	// 文件中未出现的字段保持原值
+	assert.Equal(t, 100.0, tradingConfig.Risk.MaxDailyLoss)
+	assert.Equal(t, 3, tradingConfig.Risk.MaxConsecutiveLosses)
+	assert.Equal(t, TradingConfigValue.Timeframe, tradingConfig.Timeframe)
+	assert.Equal(t, "trailing_5", tradingConfig.SellStrategy.Name)
+	assert.Equal(t, map[string]float64{"trailing_percent": 0.03}, tradingConfig.SellStrategy.ParamMap())
+	assert.Equal(t, 7, notifyConfig.QueueSize)
+
+	assert.Error(t, parseConfigSections([]byte(`{"tradingbot/src/trading:TradingConfig": `), &tradingConfig, &notifyConfig))
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	auditPath := filepath.Join(dir, "audit.log")
+
+	auditLog, err := logging.OpenAuditLog(auditPath)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	risk := engine.NewRiskManager(TradingConfigValue.Risk)
+	router, err := notify.ConfigValue.NewRouter()
+	require.NoError(t, err)
+	strategyImpl := strategies.NewBollingerBandsStrategy()
+	require.NoError(t, strategyImpl.SetParams(strategy.GetDefaultBollingerBandsParams()))
+
+	reloader := NewConfigReloader(path, risk, router, strategyImpl, auditLog)
+	ctx := context.Background()
+
+	// 未修改的配置不产生审计记录
+	require.NoError(t, os.WriteFile(path, []byte(`{"tradingbot/src/trading:TradingConfig": {}}`), 0o644))
+	require.NoError(t, reloader.Reload(ctx))
+
+	// 有效修改立即生效
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"tradingbot/src/trading:TradingConfig": {
+			"Risk": {"MaxDailyLoss": 250},
+			"SellStrategy": {"Name": "conservative", "Params": []}
+		}
+	}`), 0o644))
+	require.NoError(t, reloader.Reload(ctx))
+	assert.Equal(t, 250.0, risk.Limits().MaxDailyLoss)
+	assert.Equal(t, "conservative", reloader.trading.SellStrategy.Name)
+
+	// 无效的配置段不生效，其余照常应用
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"tradingbot/src/trading:TradingConfig": {
+			"Risk": {"MaxDailyLoss": -1},
+			"SellStrategy": {"Name": "aggressive", "Params": []}
+		}
+	}`), 0o644))
+	err = reloader.Reload(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid risk config")
+	assert.Equal(t, 250.0, risk.Limits().MaxDailyLoss)
+	assert.Equal(t, 250.0, reloader.trading.Risk.MaxDailyLoss)
+	assert.Equal(t, "aggressive", reloader.trading.SellStrategy.Name)
+
+	data, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 4)
+
+	var records []logging.ConfigChangeRecord
+	for _, line := range lines {
+		var record logging.ConfigChangeRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, logging.AuditActionConfigChange, record.Action)
+		records = append(records, record)
+	}
+	assert.Equal(t, configSectionRisk, records[0].Section)
+	assert.Empty(t, records[0].Error)
+	assert.Equal(t, configSectionSellStrategy, records[1].Section)
+	assert.Equal(t, configSectionRisk, records[2].Section)
+	assert.Contains(t, records[2].Error, "invalid risk config")
+	assert.Equal(t, configSectionSellStrategy, records[3].Section)
+	assert.Empty(t, records[3].Error)
+}
+
+func TestConfigReloader_EnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	risk := engine.NewRiskManager(TradingConfigValue.Risk)
+	router, err := notify.ConfigValue.NewRouter()
+	require.NoError(t, err)
+	strategyImpl := strategies.NewBollingerBandsStrategy()
+	require.NoError(t, strategyImpl.SetParams(strategy.GetDefaultBollingerBandsParams()))
+	reloader := NewConfigReloader(path, risk, router, strategyImpl, nil)
+
+	// 环境变量优先于配置文件中的值
+	t.Setenv("TRADINGBOT_TRADING_RISK_MAX_DAILY_LOSS", "80")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"tradingbot/src/trading:TradingConfig": {"Risk": {"MaxDailyLoss": 250, "MaxConsecutiveLosses": 4}}
+	}`), 0o644))
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Equal(t, 80.0, risk.Limits().MaxDailyLoss)
+	assert.Equal(t, 4, risk.Limits().MaxConsecutiveLosses)
+
+	t.Setenv("TRADINGBOT_TRADING_RISK_MAX_DAILY_LOSS", "abc")
+	err = reloader.Reload(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TRADINGBOT_TRADING_RISK_MAX_DAILY_LOSS")
+}
+
+func TestConfigReloader_NotifyAuditOmitsCredentials(t *testing.T) {
+	config := notify.ConfigValue
+	config.Telegram.BotToken = "secret-token"
+	config.Telegram.ChatID = "42"
+
+	data, err := json.Marshal(newNotifyAuditValue(config))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-token")
+	assert.Contains(t, string(data), "telegram")
+}
@@ -0,0 +1,35 @@
+package trading
+
+import (
+	"testing"
+
+	"tradingbot/src/engine"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionSizingConfig_NewPositionSizer(t *testing.T) {
+	config := TradingConfigValue.PositionSizing
+
+	for _, method := range SupportedSizingMethods() {
+		config.Method = method
+		sizer, err := config.NewPositionSizer(0.95)
+		require.NoError(t, err, method)
+		assert.NotEmpty(t, sizer.Describe(), method)
+	}
+
+	config.Method = ""
+	sizer, err := config.NewPositionSizer(0.5)
+	require.NoError(t, err)
+	assert.IsType(t, &engine.FixedPercentSizer{}, sizer)
+
+	config.Method = "martingale"
+	_, err = config.NewPositionSizer(0.95)
+	assert.Error(t, err)
+
+	config.Method = SizingKelly
+	config.KellyWinRate = 1.2
+	_, err = config.NewPositionSizer(0.95)
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package trading
+
+import (
+	"fmt"
+
+	"tradingbot/src/dashboard"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// startDashboard 实盘运行时启动监控面板（配置了监听地址时），同时可浏览历史回测；配置了 HealthAddr 时启动健康检查服务。
+// 多机器人模式下只更新管理器提供的实盘状态
+func (ts *TradingSystem) startDashboard(bus *engine.EventBus) error {
+	if ts.live != nil {
+		ts.live.Subscribe(bus)
+		return nil
+	}
+
+	config := dashboard.ConfigValue
+	if config.HealthAddr != "" {
+		addr, err := dashboard.StartHealthServer(ts.ctx, config.HealthAddr, dashboard.HealthChecks)
+		if err != nil {
+			return fmt.Errorf("failed to start health server: %w", err)
+		}
+		_, logger := log.WithCtx(ts.ctx)
+		logger.Info(fmt.Sprintf("✓ 健康检查: url=http://%s/healthz", addr))
+	}
+	if config.LiveAddr == "" {
+		return nil
+	}
+
+	live := dashboard.NewLiveState(config.HistorySize)
+	live.Subscribe(bus)
+
+	var backtests dashboard.BacktestStore
+	if db, err := GetPostgresDB(ts.cexClient); err == nil {
+		backtests = db
+	}
+
+	addr, err := dashboard.NewServer(config.LiveAddr, live, backtests).Start(ts.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start dashboard: %w", err)
+	}
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 监控面板: url=http://%s", addr))
+	return nil
+}
@@ -0,0 +1,225 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// 账户快照的运行模式
+const (
+	SnapshotModeLive    = "live"
+	SnapshotModeTestnet = "testnet"
+	SnapshotModeDry     = "dry"
+)
+
+// EquitySnapshotDB 账户快照存储（由 database.PostgresDB 实现）
+type EquitySnapshotDB interface {
+	// SaveEquitySnapshot 保存一条快照
+	SaveEquitySnapshot(ctx context.Context, record *database.EquitySnapshotRecord) error
+
+	// GetLatestEquitySnapshot 获取会话最近一条快照，不存在时返回 nil
+	GetLatestEquitySnapshot(ctx context.Context, sessionID string) (*database.EquitySnapshotRecord, error)
+}
+
+// DefaultLiveSessionID 实盘账户快照的会话名（同一交易所、交易对的实盘共用一个会话，测试网单独记录）
+func DefaultLiveSessionID(cexName string, pair cex.TradingPair, testnet bool) string {
+	prefix := SnapshotModeLive
+	if testnet {
+		prefix = SnapshotModeTestnet
+	}
+	return fmt.Sprintf("%s_%s_%s", prefix, strings.ToLower(cexName), DatabaseSymbol(pair))
+}
+
+// EquitySnapshotter 订阅事件总线，按平均成本跟踪持仓成本和已实现盈亏，每隔 interval 在K线处理完成后保存一条账户快照
+type EquitySnapshotter struct {
+	db        EquitySnapshotDB
+	sessionID string
+	symbol    string
+	mode      string
+	traceID   string
+	interval  time.Duration
+	queue     chan *database.EquitySnapshotRecord
+
+	mu        sync.Mutex
+	last      time.Time       // 最近一次快照的K线时间
+	position  decimal.Decimal // 跟踪的持仓数量
+	costBasis decimal.Decimal // 持仓成本（含买入手续费）
+	realized  decimal.Decimal // 累计已实现盈亏
+}
+
+// NewEquitySnapshotter 创建账户快照记录器
+func NewEquitySnapshotter(db EquitySnapshotDB, sessionID, symbol, mode string, interval time.Duration) *EquitySnapshotter {
+	return &EquitySnapshotter{
+		db:        db,
+		sessionID: sessionID,
+		symbol:    symbol,
+		mode:      mode,
+		interval:  interval,
+		queue:     make(chan *database.EquitySnapshotRecord, 16),
+	}
+}
+
+// SetTraceID 设置写入快照的运行跟踪ID，需在订阅事件总线之前调用
+func (s *EquitySnapshotter) SetTraceID(traceID string) {
+	s.traceID = traceID
+}
+
+// Restore 从会话最近一条快照恢复持仓成本和累计已实现盈亏（重启后继续累计）
+func (s *EquitySnapshotter) Restore(ctx context.Context) error {
+	latest, err := s.db.GetLatestEquitySnapshot(ctx, s.sessionID)
+	if err != nil || latest == nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position, s.costBasis, s.realized = latest.Position, latest.CostBasis, latest.RealizedPnL
+	return nil
+}
+
+// Subscribe 订阅事件总线
+func (s *EquitySnapshotter) Subscribe(bus *engine.EventBus) {
+	bus.Subscribe(s.handle, engine.EventOrderFilled, engine.EventKlineProcessed)
+}
+
+// Start 后台保存快照（数据库写入不阻塞引擎），ctx 取消后退出
+func (s *EquitySnapshotter) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case record := <-s.queue:
+				if err := s.db.SaveEquitySnapshot(ctx, record); err != nil {
+					_, logger := log.WithCtx(ctx)
+					logger.Error("保存账户快照失败", "session", s.sessionID, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// handle 处理引擎事件
+func (s *EquitySnapshotter) handle(ctx context.Context, event *engine.Event) {
+	switch event.Type {
+	case engine.EventOrderFilled:
+		s.applyFill(event.Fill)
+	case engine.EventKlineProcessed:
+		record := s.snapshot(event.Time, event.Kline.Close, event.Portfolio)
+		if record == nil {
+			return
+		}
+		select {
+		case s.queue <- record:
+		default:
+			_, logger := log.WithCtx(ctx)
+			logger.Warning(fmt.Sprintf("账户快照队列已满，丢弃快照: session=%s", s.sessionID))
+		}
+	}
+}
+
+// applyFill 按平均成本记账：买入增加成本，卖出按平均成本结转已实现盈亏
+func (s *EquitySnapshotter) applyFill(fill *executor.OrderResult) {
+	if fill == nil || !fill.Quantity.IsPositive() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value := fill.Quantity.Mul(fill.Price)
+	if fill.Side == executor.OrderSideBuy {
+		s.costBasis = s.costBasis.Add(value).Add(fill.Commission)
+		s.position = s.position.Add(fill.Quantity)
+		return
+	}
+
+	closed := decimal.Min(fill.Quantity, s.position)
+	closedCost := decimal.Zero
+	if s.position.IsPositive() {
+		closedCost = s.costBasis.Mul(closed).Div(s.position)
+	}
+	s.realized = s.realized.Add(closed.Mul(fill.Price)).Sub(fill.Commission).Sub(closedCost)
+	s.costBasis = s.costBasis.Sub(closedCost)
+	s.position = s.position.Sub(closed)
+}
+
+// snapshot 与账户持仓对齐后生成快照，距上次快照不足 interval 时返回 nil
+func (s *EquitySnapshotter) snapshot(at time.Time, price decimal.Decimal, portfolio *executor.Portfolio) *database.EquitySnapshotRecord {
+	if portfolio == nil || !price.IsPositive() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 启动时已有的持仓、对账校正的持仓没有成交记录：增加的部分按当前价格计成本，减少的部分按比例扣减成本
+	if diff := portfolio.Position.Sub(s.position); !diff.IsZero() {
+		if diff.IsPositive() {
+			s.costBasis = s.costBasis.Add(diff.Mul(price))
+		} else if s.position.IsPositive() {
+			s.costBasis = s.costBasis.Mul(portfolio.Position).Div(s.position)
+		}
+		s.position = portfolio.Position
+	}
+
+	if !s.last.IsZero() && at.Sub(s.last) < s.interval {
+		return nil
+	}
+	s.last = at
+
+	marketValue := s.position.Mul(price)
+	return &database.EquitySnapshotRecord{
+		SessionID:     s.sessionID,
+		Symbol:        s.symbol,
+		Mode:          s.mode,
+		Time:          at,
+		Price:         price,
+		Cash:          portfolio.Cash,
+		Position:      s.position,
+		Equity:        portfolio.Cash.Add(marketValue),
+		CostBasis:     s.costBasis,
+		RealizedPnL:   s.realized,
+		UnrealizedPnL: marketValue.Sub(s.costBasis),
+		TraceID:       s.traceID,
+	}
+}
+
+// startEquitySnapshots 实盘和 Dry Run 定期把账户快照写入 equity_snapshots 表（数据库不可用或间隔为 0 时不记录）
+func (ts *TradingSystem) startEquitySnapshots(pair cex.TradingPair, bus *engine.EventBus, dryRun bool) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	minutes := TradingConfigValue.EquitySnapshotMinutes
+	if ts.signalOnly || minutes <= 0 {
+		return
+	}
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("⚠️ 数据库不可用，不记录账户快照: %v", err))
+		return
+	}
+
+	sessionID, mode := DefaultLiveSessionID(ts.cexName, pair, ts.Testnet()), SnapshotModeLive
+	if dryRun {
+		sessionID, mode = ts.paperSessionID(pair), SnapshotModeDry
+	} else if ts.Testnet() {
+		mode = SnapshotModeTestnet
+	}
+
+	snapshotter := NewEquitySnapshotter(db, sessionID, DatabaseSymbol(pair), mode, time.Duration(minutes)*time.Minute)
+	snapshotter.SetTraceID(ts.traceID)
+	if err := snapshotter.Restore(ts.ctx); err != nil {
+		logger.Warning(fmt.Sprintf("⚠️ 读取最近的账户快照失败，已实现盈亏从 0 开始累计: %v", err))
+	}
+	snapshotter.Subscribe(bus)
+	snapshotter.Start(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 账户快照: session=%s, interval=%dm", sessionID, minutes))
+}
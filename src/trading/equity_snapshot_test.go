@@ -0,0 +1,149 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEquitySnapshotDB 内存账户快照表
+type mockEquitySnapshotDB struct {
+	records []*database.EquitySnapshotRecord
+}
+
+func (m *mockEquitySnapshotDB) SaveEquitySnapshot(ctx context.Context, record *database.EquitySnapshotRecord) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *mockEquitySnapshotDB) GetLatestEquitySnapshot(ctx context.Context, sessionID string) (*database.EquitySnapshotRecord, error) {
+	if len(m.records) == 0 {
+		return nil, nil
+	}
+	return m.records[len(m.records)-1], nil
+}
+
+// publishKline 发布一条K线处理完成事件
+func publishKline(bus *engine.EventBus, at time.Time, price, cash, position float64) {
+	bus.Publish(context.Background(), &engine.Event{
+		Type:      engine.EventKlineProcessed,
+		Time:      at,
+		Kline:     &cex.KlineData{Close: decimal.NewFromFloat(price)},
+		Portfolio: &executor.Portfolio{Cash: decimal.NewFromFloat(cash), Position: decimal.NewFromFloat(position)},
+	})
+}
+
+// publishFill 发布一条成交事件
+func publishFill(bus *engine.EventBus, side executor.OrderSide, quantity, price, commission float64) {
+	bus.Publish(context.Background(), &engine.Event{
+		Type: engine.EventOrderFilled,
+		Fill: &executor.OrderResult{
+			Side:       side,
+			Quantity:   decimal.NewFromFloat(quantity),
+			Price:      decimal.NewFromFloat(price),
+			Commission: decimal.NewFromFloat(commission),
+		},
+	})
+}
+
+// drainSnapshots 取出队列中的快照
+func drainSnapshots(s *EquitySnapshotter) []*database.EquitySnapshotRecord {
+	var records []*database.EquitySnapshotRecord
+	for {
+		select {
+		case record := <-s.queue:
+			records = append(records, record)
+		default:
+			return records
+		}
+	}
+}
+
+func TestEquitySnapshotter_AverageCost(t *testing.T) {
+	snapshotter := NewEquitySnapshotter(&mockEquitySnapshotDB{}, "live_binance_BTCUSDT", "BTCUSDT", SnapshotModeLive, time.Hour)
+	bus := engine.NewEventBus()
+	snapshotter.Subscribe(bus)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	publishKline(bus, t0, 10, 1000, 0)
+	publishFill(bus, executor.OrderSideBuy, 10, 10, 1)
+	publishKline(bus, t0.Add(30*time.Minute), 11, 899, 10) // 不足一个间隔，不保存
+	publishKline(bus, t0.Add(time.Hour), 12, 899, 10)
+	publishFill(bus, executor.OrderSideSell, 5, 12, 0.5)
+	publishKline(bus, t0.Add(2*time.Hour), 12, 958.5, 5)
+
+	records := drainSnapshots(snapshotter)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "1000", records[0].Equity.String())
+	assert.True(t, records[0].Position.IsZero())
+
+	// 买入成本含手续费：10×10+1
+	assert.Equal(t, "1019", records[1].Equity.String())
+	assert.Equal(t, "101", records[1].CostBasis.String())
+	assert.Equal(t, "19", records[1].UnrealizedPnL.String())
+	assert.True(t, records[1].RealizedPnL.IsZero())
+
+	// 卖出一半：5×12 - 0.5 - 101/2 = 9
+	assert.Equal(t, "9", records[2].RealizedPnL.String())
+	assert.Equal(t, "50.5", records[2].CostBasis.String())
+	assert.Equal(t, "9.5", records[2].UnrealizedPnL.String())
+	assert.Equal(t, "5", records[2].Position.String())
+	assert.Equal(t, SnapshotModeLive, records[2].Mode)
+	assert.Equal(t, "live_binance_BTCUSDT", records[2].SessionID)
+}
+
+func TestEquitySnapshotter_UntrackedPosition(t *testing.T) {
+	snapshotter := NewEquitySnapshotter(&mockEquitySnapshotDB{}, "s", "BTCUSDT", SnapshotModeDry, time.Hour)
+	bus := engine.NewEventBus()
+	snapshotter.Subscribe(bus)
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 启动时已有持仓：按当前价格计成本
+	publishKline(bus, t0, 20, 100, 4)
+	// 对账后持仓减半：成本按比例扣减
+	publishKline(bus, t0.Add(time.Hour), 25, 100, 2)
+
+	records := drainSnapshots(snapshotter)
+	require.Len(t, records, 2)
+	assert.Equal(t, "80", records[0].CostBasis.String())
+	assert.True(t, records[0].UnrealizedPnL.IsZero())
+	assert.Equal(t, "40", records[1].CostBasis.String())
+	assert.Equal(t, "10", records[1].UnrealizedPnL.String())
+}
+
+func TestEquitySnapshotter_Restore(t *testing.T) {
+	db := &mockEquitySnapshotDB{records: []*database.EquitySnapshotRecord{{
+		Position:    decimal.NewFromInt(2),
+		CostBasis:   decimal.NewFromInt(20),
+		RealizedPnL: decimal.NewFromInt(5),
+	}}}
+	snapshotter := NewEquitySnapshotter(db, "s", "BTCUSDT", SnapshotModeLive, time.Hour)
+	require.NoError(t, snapshotter.Restore(context.Background()))
+
+	bus := engine.NewEventBus()
+	snapshotter.Subscribe(bus)
+	publishFill(bus, executor.OrderSideSell, 2, 15, 0)
+	publishKline(bus, time.Now(), 15, 130, 0)
+
+	records := drainSnapshots(snapshotter)
+	require.Len(t, records, 1)
+	// 重启前已实现 5，本次卖出 2×15-20=10
+	assert.Equal(t, "15", records[0].RealizedPnL.String())
+	assert.True(t, records[0].CostBasis.IsZero())
+}
+
+func TestDefaultLiveSessionID(t *testing.T) {
+	pair := cex.TradingPair{Base: "btc", Quote: "usdt"}
+	assert.Equal(t, "live_binance_BTCUSDT", DefaultLiveSessionID("Binance", pair, false))
+	assert.Equal(t, "testnet_binance_BTCUSDT", DefaultLiveSessionID("binance", pair, true))
+}
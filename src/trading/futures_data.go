@@ -0,0 +1,352 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// 合约辅助数据分批下载：每批覆盖的时间不超过单次请求上限能返回的记录数
+const (
+	fundingRatesPerRequest  = 1000
+	fundingMinInterval      = time.Hour // 资金费率最短结算间隔，用于计算每批覆盖的时间
+	openInterestPerRequest  = 500
+	openInterestHistoryDays = 30 // 币安持仓量历史只保留最近 30 天
+)
+
+// openInterestPeriods 币安持仓量统计支持的周期
+var openInterestPeriods = map[string]bool{
+	"5m": true, "15m": true, "30m": true, "1h": true, "2h": true, "4h": true, "6h": true, "12h": true, "1d": true,
+}
+
+// FuturesDataStore 合约资金费率和持仓量存储（由 database.PostgresDB 实现）
+type FuturesDataStore interface {
+	// SaveFundingRates 批量保存资金费率（已存在的结算时间会被更新）
+	SaveFundingRates(ctx context.Context, symbol string, rates []*cex.FundingRate) error
+
+	// GetLatestFundingTime 最新资金费率的结算时间，无数据时返回零值
+	GetLatestFundingTime(ctx context.Context, symbol string) (time.Time, error)
+
+	// GetFundingRates 获取 [start, end] 内的资金费率，零值时间表示不限制
+	GetFundingRates(ctx context.Context, symbol string, start, end time.Time) ([]*cex.FundingRate, error)
+
+	// SaveOpenInterest 批量保存持仓量（已存在的统计时间会被更新）
+	SaveOpenInterest(ctx context.Context, symbol, period string, history []*cex.OpenInterest) error
+
+	// GetLatestOpenInterestTime 最新持仓量的统计时间，无数据时返回零值
+	GetLatestOpenInterestTime(ctx context.Context, symbol, period string) (time.Time, error)
+
+	// GetOpenInterest 获取 [start, end] 内的持仓量，零值时间表示不限制
+	GetOpenInterest(ctx context.Context, symbol, period string, start, end time.Time) ([]*cex.OpenInterest, error)
+}
+
+// FuturesSyncResult 单个交易对的合约辅助数据同步结果
+type FuturesSyncResult struct {
+	Symbol             string
+	FundingRates       int       // 本次保存的资金费率条数
+	OpenInterest       int       // 本次保存的持仓量条数
+	LatestFunding      time.Time // 库中最新资金费率的结算时间
+	LatestOpenInterest time.Time // 库中最新持仓量的统计时间
+}
+
+// FuturesDataSyncer 从交易所下载同名永续合约的资金费率和持仓量并保存：从最新记录续传，按间隔限频
+type FuturesDataSyncer struct {
+	provider        cex.FuturesDataProvider
+	store           FuturesDataStore
+	period          string
+	periodDuration  time.Duration
+	requestInterval time.Duration
+
+	lastRequest time.Time
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// NewFuturesDataSyncer 创建合约辅助数据同步器，请求间隔沿用K线同步的 Sync.RequestIntervalMs
+func NewFuturesDataSyncer(provider cex.FuturesDataProvider, store FuturesDataStore, config FuturesDataConfig, requestIntervalMs int) (*FuturesDataSyncer, error) {
+	if !openInterestPeriods[config.OpenInterestPeriod] {
+		return nil, fmt.Errorf("invalid open interest period %q (supported: 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d)", config.OpenInterestPeriod)
+	}
+	tf, err := timeframes.ParseTimeframe(config.OpenInterestPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid open interest period: %w", err)
+	}
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return nil, fmt.Errorf("invalid open interest period: %w", err)
+	}
+	if requestIntervalMs < 0 {
+		return nil, fmt.Errorf("sync request interval cannot be negative")
+	}
+
+	return &FuturesDataSyncer{
+		provider:        provider,
+		store:           store,
+		period:          config.OpenInterestPeriod,
+		periodDuration:  duration,
+		requestInterval: time.Duration(requestIntervalMs) * time.Millisecond,
+		now:             time.Now,
+		sleep:           sleepContext,
+	}, nil
+}
+
+// Sync 同步一个交易对：资金费率从最新记录（无记录时从 startTime）续传，持仓量最多回溯 30 天
+func (s *FuturesDataSyncer) Sync(ctx context.Context, pair cex.TradingPair, startTime time.Time) (*FuturesSyncResult, error) {
+	symbol := DatabaseSymbol(pair)
+	result := &FuturesSyncResult{Symbol: symbol}
+	now := s.now().UTC()
+
+	latest, err := s.store.GetLatestFundingTime(ctx, symbol)
+	if err != nil {
+		return result, err
+	}
+	result.LatestFunding = latest
+	from := startTime
+	if !latest.IsZero() {
+		from = latest.Add(time.Millisecond)
+	}
+	for batchFrom := from; batchFrom.Before(now); {
+		batchEnd := minTime(batchFrom.Add(fundingRatesPerRequest*fundingMinInterval), now)
+		if err := s.wait(ctx); err != nil {
+			return result, err
+		}
+		rates, err := s.provider.GetFundingRates(ctx, pair, batchFrom, batchEnd.Add(-time.Millisecond), fundingRatesPerRequest)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch %s funding rates from %s: %w", pair.String(), batchFrom.Format("2006-01-02 15:04"), err)
+		}
+		rates = fundingRatesInRange(rates, batchFrom, batchEnd)
+		if err := s.store.SaveFundingRates(ctx, symbol, rates); err != nil {
+			return result, err
+		}
+		if len(rates) > 0 {
+			result.FundingRates += len(rates)
+			result.LatestFunding = rates[len(rates)-1].Time
+		}
+		batchFrom = batchEnd
+	}
+
+	latest, err = s.store.GetLatestOpenInterestTime(ctx, symbol, s.period)
+	if err != nil {
+		return result, err
+	}
+	result.LatestOpenInterest = latest
+	from = startTime
+	if !latest.IsZero() {
+		from = latest.Add(time.Millisecond)
+	}
+	// 更早的持仓量交易所已不提供，多留一个周期避免请求落在保留期边界之外
+	if earliest := now.AddDate(0, 0, -openInterestHistoryDays).Add(s.periodDuration); from.Before(earliest) {
+		from = earliest
+	}
+	for batchFrom := from; batchFrom.Before(now); {
+		batchEnd := minTime(batchFrom.Add(openInterestPerRequest*s.periodDuration), now)
+		if err := s.wait(ctx); err != nil {
+			return result, err
+		}
+		history, err := s.provider.GetOpenInterestHistory(ctx, pair, s.period, batchFrom, batchEnd.Add(-time.Millisecond), openInterestPerRequest)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch %s open interest from %s: %w", pair.String(), batchFrom.Format("2006-01-02 15:04"), err)
+		}
+		history = openInterestInRange(history, batchFrom, batchEnd)
+		if err := s.store.SaveOpenInterest(ctx, symbol, s.period, history); err != nil {
+			return result, err
+		}
+		if len(history) > 0 {
+			result.OpenInterest += len(history)
+			result.LatestOpenInterest = history[len(history)-1].Time
+		}
+		batchFrom = batchEnd
+	}
+
+	return result, nil
+}
+
+// wait 限频：距上次请求不足 requestInterval 时等待
+func (s *FuturesDataSyncer) wait(ctx context.Context) error {
+	if err := s.sleep(ctx, s.requestInterval-s.now().Sub(s.lastRequest)); err != nil {
+		return err
+	}
+	s.lastRequest = s.now()
+	return nil
+}
+
+// fundingRatesInRange 过滤出 [from, to) 内的资金费率并按时间排序（交易所可能返回范围外的记录）
+func fundingRatesInRange(rates []*cex.FundingRate, from, to time.Time) []*cex.FundingRate {
+	result := make([]*cex.FundingRate, 0, len(rates))
+	for _, rate := range rates {
+		if !rate.Time.Before(from) && rate.Time.Before(to) {
+			result = append(result, rate)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// openInterestInRange 过滤出 [from, to) 内的持仓量并按时间排序
+func openInterestInRange(history []*cex.OpenInterest, from, to time.Time) []*cex.OpenInterest {
+	result := make([]*cex.OpenInterest, 0, len(history))
+	for _, oi := range history {
+		if !oi.Time.Before(from) && oi.Time.Before(to) {
+			result = append(result, oi)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// minTime 较早的时间
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// LoadFuturesContext 从数据库加载交易对已同步的全部资金费率和持仓量
+func LoadFuturesContext(ctx context.Context, store FuturesDataStore, pair cex.TradingPair, period string) (*strategy.FuturesContext, error) {
+	funding, openInterest, err := loadFuturesRecords(ctx, store, pair, period)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.NewFuturesContext(funding, openInterest), nil
+}
+
+// loadFuturesRecords 读取交易对的全部资金费率和持仓量
+func loadFuturesRecords(ctx context.Context, store FuturesDataStore, pair cex.TradingPair, period string) ([]*cex.FundingRate, []*cex.OpenInterest, error) {
+	symbol := DatabaseSymbol(pair)
+	funding, err := store.GetFundingRates(ctx, symbol, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, nil, err
+	}
+	openInterest, err := store.GetOpenInterest(ctx, symbol, period, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return funding, openInterest, nil
+}
+
+// attachBacktestFuturesContext 回测时为需要合约辅助数据的策略注入数据（按交易对缓存，并发回测共享只读数据）
+func (ts *TradingSystem) attachBacktestFuturesContext(strategyImpl strategy.Strategy, pair cex.TradingPair) error {
+	consumer, ok := strategyImpl.(strategy.FuturesContextConsumer)
+	if !ok || !consumer.UsesFuturesContext() {
+		return nil
+	}
+
+	ts.futuresMu.Lock()
+	defer ts.futuresMu.Unlock()
+	fc, ok := ts.futuresContexts[pair]
+	if !ok {
+		db, err := ts.futuresDataStore()
+		if err != nil {
+			return err
+		}
+		if fc, err = LoadFuturesContext(ts.ctx, db, pair, TradingConfigValue.FuturesData.OpenInterestPeriod); err != nil {
+			return fmt.Errorf("failed to load futures data for %s: %w", pair.String(), err)
+		}
+		if err := ts.checkFuturesContext(fc, pair); err != nil {
+			return err
+		}
+		if ts.futuresContexts == nil {
+			ts.futuresContexts = make(map[cex.TradingPair]*strategy.FuturesContext)
+		}
+		ts.futuresContexts[pair] = fc
+	}
+	consumer.SetFuturesContext(fc)
+	return nil
+}
+
+// futuresDataStore 合约辅助数据所在的数据库
+func (ts *TradingSystem) futuresDataStore() (FuturesDataStore, error) {
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return nil, fmt.Errorf("futures data filters require the database: %w", err)
+	}
+	return db, nil
+}
+
+// checkFuturesContext 没有资金费率记录时返回错误，否则输出记录数
+func (ts *TradingSystem) checkFuturesContext(fc *strategy.FuturesContext, pair cex.TradingPair) error {
+	funding, openInterest := fc.Counts()
+	if funding == 0 {
+		return fmt.Errorf("no funding rates stored for %s, run futures-sync first or disable the funding filter", pair.String())
+	}
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("📑 合约辅助数据: symbol=%s, funding_rates=%d, open_interest=%d", pair.String(), funding, openInterest))
+	return nil
+}
+
+// startFuturesContext 实盘和 Dry Run 为需要合约辅助数据的策略注入数据，并按 FuturesData.RefreshMinutes 定期从交易所同步、刷新
+func (ts *TradingSystem) startFuturesContext(strategyImpl strategy.Strategy, pair cex.TradingPair) error {
+	consumer, ok := strategyImpl.(strategy.FuturesContextConsumer)
+	if !ok || !consumer.UsesFuturesContext() {
+		return nil
+	}
+	_, logger := log.WithCtx(ts.ctx)
+
+	config := TradingConfigValue.FuturesData
+	db, err := ts.futuresDataStore()
+	if err != nil {
+		return err
+	}
+	var syncer *FuturesDataSyncer
+	if provider, ok := ts.cexClient.(cex.FuturesDataProvider); ok {
+		if syncer, err = NewFuturesDataSyncer(provider, db, config, TradingConfigValue.Sync.RequestIntervalMs); err != nil {
+			return err
+		}
+	} else {
+		logger.Warning(fmt.Sprintf("⚠️ %s 不支持查询合约资金费率，只使用数据库中已同步的数据", ts.cexClient.GetName()))
+	}
+
+	// 从最新记录续传（库中没有记录时只回溯 FundingStaleAfter，完整历史用 futures-sync 下载）
+	syncNow := func() {
+		if syncer == nil {
+			return
+		}
+		if _, err := syncer.Sync(ts.ctx, pair, time.Now().Add(-strategy.FundingStaleAfter)); err != nil && ts.ctx.Err() == nil {
+			logger.Warning(fmt.Sprintf("同步合约辅助数据失败: symbol=%s, error=%v", pair.String(), err))
+		}
+	}
+
+	syncNow()
+	fc, err := LoadFuturesContext(ts.ctx, db, pair, config.OpenInterestPeriod)
+	if err != nil {
+		return fmt.Errorf("failed to load futures data for %s: %w", pair.String(), err)
+	}
+	if err := ts.checkFuturesContext(fc, pair); err != nil {
+		return err
+	}
+	consumer.SetFuturesContext(fc)
+
+	if config.RefreshMinutes <= 0 {
+		return nil
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(config.RefreshMinutes) * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ts.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			syncNow()
+			funding, openInterest, err := loadFuturesRecords(ts.ctx, db, pair, config.OpenInterestPeriod)
+			if err != nil {
+				if ts.ctx.Err() == nil {
+					logger.Warning(fmt.Sprintf("刷新合约辅助数据失败，继续使用旧数据: symbol=%s, error=%v", pair.String(), err))
+				}
+				continue
+			}
+			fc.Replace(funding, openInterest)
+		}
+	}()
+	logger.Info(fmt.Sprintf("✓ 合约辅助数据每 %d 分钟刷新", config.RefreshMinutes))
+	return nil
+}
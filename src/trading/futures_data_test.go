@@ -0,0 +1,156 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFuturesProvider 每 8 小时结算一次资金费率、每小时一条持仓量的交易所mock
+type mockFuturesProvider struct {
+	now              time.Time
+	fundingRequests  int
+	interestRequests [][2]time.Time
+}
+
+func (m *mockFuturesProvider) GetFundingRates(ctx context.Context, pair cex.TradingPair, startTime, endTime time.Time, limit int) ([]*cex.FundingRate, error) {
+	m.fundingRequests++
+	var rates []*cex.FundingRate
+	for t := startTime.Truncate(8 * time.Hour); !t.After(endTime) && len(rates) < limit; t = t.Add(8 * time.Hour) {
+		if t.Before(startTime) {
+			continue
+		}
+		rates = append(rates, &cex.FundingRate{TradingPair: pair, Time: t, Rate: decimal.NewFromFloat(0.0001)})
+	}
+	return rates, nil
+}
+
+func (m *mockFuturesProvider) GetOpenInterestHistory(ctx context.Context, pair cex.TradingPair, period string, startTime, endTime time.Time, limit int) ([]*cex.OpenInterest, error) {
+	m.interestRequests = append(m.interestRequests, [2]time.Time{startTime, endTime})
+	var history []*cex.OpenInterest
+	for t := startTime.Truncate(time.Hour); !t.After(endTime) && len(history) < limit; t = t.Add(time.Hour) {
+		if t.Before(startTime) || t.Before(m.now.AddDate(0, 0, -30)) {
+			continue
+		}
+		history = append(history, &cex.OpenInterest{TradingPair: pair, Time: t, OpenInterest: decimal.NewFromInt(100)})
+	}
+	return history, nil
+}
+
+// mockFuturesStore 内存合约辅助数据存储
+type mockFuturesStore struct {
+	funding      map[time.Time]*cex.FundingRate
+	openInterest map[time.Time]*cex.OpenInterest
+}
+
+func newMockFuturesStore() *mockFuturesStore {
+	return &mockFuturesStore{funding: make(map[time.Time]*cex.FundingRate), openInterest: make(map[time.Time]*cex.OpenInterest)}
+}
+
+func (s *mockFuturesStore) SaveFundingRates(ctx context.Context, symbol string, rates []*cex.FundingRate) error {
+	for _, rate := range rates {
+		s.funding[rate.Time] = rate
+	}
+	return nil
+}
+
+func (s *mockFuturesStore) GetLatestFundingTime(ctx context.Context, symbol string) (time.Time, error) {
+	var latest time.Time
+	for t := range s.funding {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest, nil
+}
+
+func (s *mockFuturesStore) GetFundingRates(ctx context.Context, symbol string, start, end time.Time) ([]*cex.FundingRate, error) {
+	var rates []*cex.FundingRate
+	for _, rate := range s.funding {
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (s *mockFuturesStore) SaveOpenInterest(ctx context.Context, symbol, period string, history []*cex.OpenInterest) error {
+	for _, oi := range history {
+		s.openInterest[oi.Time] = oi
+	}
+	return nil
+}
+
+func (s *mockFuturesStore) GetLatestOpenInterestTime(ctx context.Context, symbol, period string) (time.Time, error) {
+	var latest time.Time
+	for t := range s.openInterest {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest, nil
+}
+
+func (s *mockFuturesStore) GetOpenInterest(ctx context.Context, symbol, period string, start, end time.Time) ([]*cex.OpenInterest, error) {
+	var history []*cex.OpenInterest
+	for _, oi := range s.openInterest {
+		history = append(history, oi)
+	}
+	return history, nil
+}
+
+func newTestFuturesSyncer(t *testing.T, provider *mockFuturesProvider, store FuturesDataStore) *FuturesDataSyncer {
+	syncer, err := NewFuturesDataSyncer(provider, store, FuturesDataConfig{OpenInterestPeriod: "1h"}, 0)
+	require.NoError(t, err)
+	syncer.now = func() time.Time { return provider.now }
+	syncer.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return syncer
+}
+
+func TestFuturesDataSyncer_SyncAndResume(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := &mockFuturesProvider{now: start.AddDate(0, 3, 0)}
+	store := newMockFuturesStore()
+	syncer := newTestFuturesSyncer(t, provider, store)
+
+	result, err := syncer.Sync(context.Background(), syncTestPair, start)
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", result.Symbol)
+
+	// 2024-01-01 到 2024-04-01 共 91 天，每天 3 次结算；每批最多覆盖 1000 小时
+	assert.Equal(t, 91*3, result.FundingRates)
+	assert.Equal(t, 3, provider.fundingRequests)
+	assert.Equal(t, provider.now.Add(-8*time.Hour), result.LatestFunding)
+
+	// 持仓量只回溯 30 天（多留一个周期），每批最多 500 条
+	require.NotEmpty(t, provider.interestRequests)
+	assert.Equal(t, provider.now.AddDate(0, 0, -30).Add(time.Hour), provider.interestRequests[0][0])
+	assert.Equal(t, 30*24-1, result.OpenInterest)
+	assert.Len(t, provider.interestRequests, 2)
+
+	// 8 小时后续传，只下载新数据
+	provider.now = provider.now.Add(8 * time.Hour)
+	provider.fundingRequests = 0
+	provider.interestRequests = nil
+	result, err = syncer.Sync(context.Background(), syncTestPair, start)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.FundingRates)
+	assert.Equal(t, 8, result.OpenInterest)
+	assert.Equal(t, 1, provider.fundingRequests)
+	assert.Len(t, store.funding, 91*3+1)
+
+	fc, err := LoadFuturesContext(context.Background(), store, syncTestPair, "1h")
+	require.NoError(t, err)
+	funding, openInterest := fc.Counts()
+	assert.Equal(t, 91*3+1, funding)
+	assert.Equal(t, 30*24-1+8, openInterest)
+}
+
+func TestNewFuturesDataSyncer_InvalidPeriod(t *testing.T) {
+	_, err := NewFuturesDataSyncer(&mockFuturesProvider{}, newMockFuturesStore(), FuturesDataConfig{OpenInterestPeriod: "3h"}, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,213 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// defaultFeeRate 未配置交易所手续费时使用的单边手续费率（Binance 现货 taker 0.1%）
+	defaultFeeRate = 0.001
+	// maxBarParticipation 仓位金额超过K线成交额中位数的该比例时，回测成交价不可信
+	maxBarParticipation = 0.1
+	// minExpectedTrades 预期交易次数低于该值时统计结果不可靠
+	minExpectedTrades = 30
+)
+
+// GuardrailWarning 回测配置合理性警告
+type GuardrailWarning struct {
+	Code       string // 警告类型
+	Message    string // 问题描述
+	Suggestion string // 修改建议
+}
+
+// GuardrailInput 回测合理性检查输入
+type GuardrailInput struct {
+	Params         *strategy.BollingerBandsParams
+	InitialCapital float64
+	Timeframe      timeframes.Timeframe
+	StartTime      time.Time
+	EndTime        time.Time
+	Klines         []*cex.KlineData // 已加载的K线（可包含预热数据）
+	FeeRate        float64          // 单边手续费率，0 使用默认值
+}
+
+// CheckBacktestGuardrails 回测开始前检查配置是否明显不现实，返回可操作的警告
+func CheckBacktestGuardrails(in GuardrailInput) []GuardrailWarning {
+	var warnings []GuardrailWarning
+	if in.Params == nil {
+		return warnings
+	}
+
+	feeRate := in.FeeRate
+	if feeRate <= 0 {
+		feeRate = defaultFeeRate
+	}
+
+	// 只统计回测区间内的K线（排除指标预热数据）
+	var klines []*cex.KlineData
+	for _, kline := range in.Klines {
+		if !kline.OpenTime.Before(in.StartTime) && kline.OpenTime.Before(in.EndTime) {
+			klines = append(klines, kline)
+		}
+	}
+
+	positionNotional := in.InitialCapital * in.Params.PositionSizePercent
+
+	// 1. 仓位相对K线成交额过大
+	if medianVolume := medianQuoteVolume(klines); medianVolume > 0 {
+		participation := positionNotional / medianVolume
+		if participation > maxBarParticipation {
+			warnings = append(warnings, GuardrailWarning{
+				Code: "position_vs_volume",
+				Message: fmt.Sprintf("position size $%.2f is %.0f%% of the median bar quote volume $%.2f; fills at the limit price are unrealistic",
+					positionNotional, participation*100, medianVolume),
+				Suggestion: "lower -capital / -position-size, use a longer timeframe, or enable -illiquid to simulate slippage",
+			})
+		}
+	}
+
+	// 2. 止盈不足以覆盖往返手续费
+	roundTripFee := 2 * feeRate
+	if takeProfit, source := minTakeProfit(in.Params); takeProfit > 0 && takeProfit <= roundTripFee {
+		warnings = append(warnings, GuardrailWarning{
+			Code: "take_profit_below_fees",
+			Message: fmt.Sprintf("%s %.3f%% does not cover round-trip fees %.3f%%; every take-profit exit loses money live",
+				source, takeProfit*100, roundTripFee*100),
+			Suggestion: fmt.Sprintf("set take profit well above %.2f%%", roundTripFee*100),
+		})
+	}
+
+	// 3. 数据粒度比时间周期更粗
+	if duration, err := in.Timeframe.GetDuration(); err == nil {
+		if granularity := medianKlineInterval(in.Klines); granularity > duration {
+			warnings = append(warnings, GuardrailWarning{
+				Code: "timeframe_below_granularity",
+				Message: fmt.Sprintf("timeframe %s is shorter than the data granularity %s; bars are missing or aggregated",
+					in.Timeframe, granularity),
+				Suggestion: "sync data for this timeframe or choose a timeframe matching the stored klines",
+			})
+		}
+	}
+
+	// 4. 回测区间太短，交易次数不足以得出结论
+	if len(klines) > 0 {
+		if len(klines) < in.Params.Period {
+			warnings = append(warnings, GuardrailWarning{
+				Code:       "range_shorter_than_period",
+				Message:    fmt.Sprintf("only %d bars in range, fewer than Bollinger period %d", len(klines), in.Params.Period),
+				Suggestion: "extend the date range",
+			})
+		}
+
+		expected := EstimateExpectedTrades(len(klines), in.Params.Multiplier)
+		if expected < minExpectedTrades {
+			warnings = append(warnings, GuardrailWarning{
+				Code: "few_expected_trades",
+				Message: fmt.Sprintf("about %.0f trades expected over %d bars (< %d); results will not be statistically meaningful",
+					expected, len(klines), minExpectedTrades),
+				Suggestion: "extend the date range, use a shorter timeframe, or lower -multiplier",
+			})
+		}
+	}
+
+	// 5. 单笔金额低于最小交易额，永远不会下单
+	if positionNotional < in.Params.MinTradeAmount {
+		warnings = append(warnings, GuardrailWarning{
+			Code:       "position_below_min_trade",
+			Message:    fmt.Sprintf("position size $%.2f is below min trade amount $%.2f; no order will be placed", positionNotional, in.Params.MinTradeAmount),
+			Suggestion: "raise -capital / -position-size or lower -min-trade",
+		})
+	}
+
+	return warnings
+}
+
+// EstimateExpectedTrades 粗略估计交易次数：收盘价近似正态分布时，跌破下轨的概率为 Φ(-multiplier)
+func EstimateExpectedTrades(bars int, multiplier float64) float64 {
+	touchProbability := 0.5 * math.Erfc(multiplier/math.Sqrt2)
+	return float64(bars) * touchProbability
+}
+
+// PrintGuardrailWarnings 打印回测合理性警告
+func PrintGuardrailWarnings(warnings []GuardrailWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⚠️ BACKTEST GUARDRAILS: %d warning(s)\n", len(warnings))
+	fmt.Println("------------------------------------------------------------")
+	for _, w := range warnings {
+		fmt.Printf("⚠️ [%s] %s\n", w.Code, w.Message)
+		fmt.Printf("   💡 %s\n", w.Suggestion)
+	}
+	fmt.Println()
+}
+
+// minTakeProfit 获取配置中最小的止盈比例及其来源
+func minTakeProfit(params *strategy.BollingerBandsParams) (float64, string) {
+	takeProfit, source := params.TakeProfitPercent, "take profit"
+
+	consider := func(value float64, name string) {
+		if value > 0 && (takeProfit <= 0 || value < takeProfit) {
+			takeProfit, source = value, name
+		}
+	}
+
+	userTakeProfit, hasUserTakeProfit := params.SellStrategyParams["take_profit"]
+	if config, ok := strategy.GetDefaultSellStrategyConfigs()[params.SellStrategyName]; ok {
+		switch config.Type {
+		case strategy.SellStrategyFixed, strategy.SellStrategyCombo:
+			// 用户参数覆盖预设止盈
+			if hasUserTakeProfit {
+				consider(userTakeProfit, "sell strategy param take_profit")
+			} else {
+				consider(config.FixedTakeProfit, fmt.Sprintf("sell strategy %s take profit", params.SellStrategyName))
+			}
+		case strategy.SellStrategyPartial:
+			if len(config.PartialLevels) > 0 {
+				consider(config.PartialLevels[0].ProfitPercent, fmt.Sprintf("sell strategy %s first level", params.SellStrategyName))
+			}
+		}
+	} else if hasUserTakeProfit {
+		consider(userTakeProfit, "sell strategy param take_profit")
+	}
+
+	return takeProfit, source
+}
+
+// medianQuoteVolume K线成交额中位数
+func medianQuoteVolume(klines []*cex.KlineData) float64 {
+	volumes := make([]float64, 0, len(klines))
+	for _, kline := range klines {
+		if kline.QuoteVolume.GreaterThan(decimal.Zero) {
+			volumes = append(volumes, kline.QuoteVolume.InexactFloat64())
+		}
+	}
+	if len(volumes) == 0 {
+		return 0
+	}
+	sort.Float64s(volumes)
+	return volumes[len(volumes)/2]
+}
+
+// medianKlineInterval 相邻K线开盘时间间隔的中位数
+func medianKlineInterval(klines []*cex.KlineData) time.Duration {
+	if len(klines) < 2 {
+		return 0
+	}
+	intervals := make([]time.Duration, 0, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		intervals = append(intervals, klines[i].OpenTime.Sub(klines[i-1].OpenTime))
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+	return intervals[len(intervals)/2]
+}
@@ -0,0 +1,91 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBacktestGuardrails(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	makeKlines := func(count int, interval time.Duration, quoteVolume float64) []*cex.KlineData {
+		klines := make([]*cex.KlineData, count)
+		for i := range klines {
+			klines[i] = &cex.KlineData{
+				OpenTime:    start.Add(time.Duration(i) * interval),
+				CloseTime:   start.Add(time.Duration(i+1)*interval - time.Millisecond),
+				Close:       decimal.NewFromInt(100),
+				QuoteVolume: decimal.NewFromFloat(quoteVolume),
+			}
+		}
+		return klines
+	}
+	codes := func(warnings []GuardrailWarning) []string {
+		result := make([]string, 0, len(warnings))
+		for _, w := range warnings {
+			result = append(result, w.Code)
+		}
+		return result
+	}
+
+	t.Run("realistic config has no warnings", func(t *testing.T) {
+		// 4h 两年约 4380 根K线，预期约 100 笔交易
+		klines := makeKlines(4380, 4*time.Hour, 10000000)
+		warnings := CheckBacktestGuardrails(GuardrailInput{
+			Params:         strategy.GetDefaultBollingerBandsParams(),
+			InitialCapital: 10000,
+			Timeframe:      timeframes.Timeframe4h,
+			StartTime:      start,
+			EndTime:        klines[len(klines)-1].CloseTime,
+			Klines:         klines,
+		})
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("unrealistic config warns", func(t *testing.T) {
+		// 数据为日线但请求1h周期，成交额很小，止盈低于手续费，区间太短
+		klines := makeKlines(10, 24*time.Hour, 5000)
+		params := strategy.GetDefaultBollingerBandsParams()
+		params.SellStrategyParams = map[string]float64{"take_profit": 0.001}
+		warnings := CheckBacktestGuardrails(GuardrailInput{
+			Params:         params,
+			InitialCapital: 10000,
+			Timeframe:      timeframes.Timeframe1h,
+			StartTime:      start,
+			EndTime:        klines[len(klines)-1].CloseTime,
+			Klines:         klines,
+		})
+		assert.ElementsMatch(t, []string{
+			"position_vs_volume",
+			"take_profit_below_fees",
+			"timeframe_below_granularity",
+			"range_shorter_than_period",
+			"few_expected_trades",
+		}, codes(warnings))
+	})
+
+	t.Run("position below min trade", func(t *testing.T) {
+		klines := makeKlines(4380, 4*time.Hour, 10000000)
+		warnings := CheckBacktestGuardrails(GuardrailInput{
+			Params:         strategy.GetDefaultBollingerBandsParams(),
+			InitialCapital: 5,
+			Timeframe:      timeframes.Timeframe4h,
+			StartTime:      start,
+			EndTime:        klines[len(klines)-1].CloseTime,
+			Klines:         klines,
+		})
+		assert.Equal(t, []string{"position_below_min_trade"}, codes(warnings))
+	})
+}
+
+func TestEstimateExpectedTrades(t *testing.T) {
+	// 2倍标准差下轨的触及概率约 2.28%
+	assert.InDelta(t, 22.75, EstimateExpectedTrades(1000, 2.0), 0.1)
+	assert.Greater(t, EstimateExpectedTrades(1000, 1.5), EstimateExpectedTrades(1000, 2.0))
+}
@@ -0,0 +1,232 @@
+package trading
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// chartPanel 图表中共用一个纵坐标的一组序列
+type chartPanel struct {
+	name   string
+	series []chartSeries // 第一个序列绘制在最上层
+}
+
+// chartSeries 图表中的一条序列，与 IndicatorPreview.Rows 一一对应
+type chartSeries struct {
+	name   string
+	values []decimal.NullDecimal
+}
+
+// bounds 面板内有效值的最小最大值，没有有效值时 ok 为 false
+func (p chartPanel) bounds() (low, high float64, ok bool) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, series := range p.series {
+		for _, value := range series.values {
+			if value.Valid {
+				v := value.Decimal.InexactFloat64()
+				low, high = math.Min(low, v), math.Max(high, v)
+				ok = true
+			}
+		}
+	}
+	return low, high, ok
+}
+
+// indicatorChartPanels 价格面板（收盘价和叠加指标）在前，其余指标按标签各占一个面板
+func indicatorChartPanels(preview *IndicatorPreview) []chartPanel {
+	closes := make([]decimal.NullDecimal, len(preview.Rows))
+	for i, row := range preview.Rows {
+		closes[i] = decimal.NullDecimal{Decimal: row.Kline.Close, Valid: true}
+	}
+	panels := []chartPanel{{name: "price", series: []chartSeries{{name: "close", values: closes}}}}
+	index := map[string]int{"": 0}
+
+	for j, column := range preview.Columns {
+		values := make([]decimal.NullDecimal, len(preview.Rows))
+		for i, row := range preview.Rows {
+			values[i] = row.Values[j]
+		}
+		k, ok := index[column.Panel]
+		if !ok {
+			k = len(panels)
+			index[column.Panel] = k
+			panels = append(panels, chartPanel{name: column.Panel})
+		}
+		panels[k].series = append(panels[k].series, chartSeries{name: column.Name, values: values})
+	}
+	return panels
+}
+
+// formatChartValue 坐标轴刻度（8 位有效数字）
+func formatChartValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 8, 64)
+}
+
+// chartMarkers ASCII 图中各序列的标记，按面板内顺序分配
+var chartMarkers = []rune{'*', '+', '-', 'o', '#', 'x', '%', '@', '=', '~'}
+
+// WriteIndicatorChart 以 ASCII 图输出指标：K线多于 width 时每列取区间内最后一根，
+// 价格面板高 height 行，其余指标面板高度减半
+func WriteIndicatorChart(w io.Writer, preview *IndicatorPreview, width, height int) {
+	if len(preview.Rows) == 0 || width <= 0 || height <= 0 {
+		return
+	}
+	columns := min(width, len(preview.Rows))
+	sampled := make([]int, columns)
+	for c := range sampled {
+		sampled[c] = (c+1)*len(preview.Rows)/columns - 1
+	}
+	first := preview.Rows[0].Kline.OpenTime.UTC().Format("2006-01-02 15:04")
+	last := preview.Rows[len(preview.Rows)-1].Kline.OpenTime.UTC().Format("2006-01-02 15:04")
+
+	for p, panel := range indicatorChartPanels(preview) {
+		rows := height
+		if p > 0 {
+			rows = max(height/2, 3)
+		}
+
+		legend := make([]string, len(panel.series))
+		for i, series := range panel.series {
+			legend[i] = fmt.Sprintf("%c %s", chartMarkers[i%len(chartMarkers)], series.name)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "[%s] %s\n", panel.name, strings.Join(legend, "  "))
+
+		low, high, ok := panel.bounds()
+		if !ok {
+			fmt.Fprintln(w, "(insufficient data)")
+			continue
+		}
+
+		grid := make([][]rune, rows)
+		for r := range grid {
+			grid[r] = []rune(strings.Repeat(" ", columns))
+		}
+		for i := len(panel.series) - 1; i >= 0; i-- {
+			marker := chartMarkers[i%len(chartMarkers)]
+			for c, index := range sampled {
+				value := panel.series[i].values[index]
+				if !value.Valid {
+					continue
+				}
+				r := rows / 2
+				if high > low {
+					r = int(math.Round((high - value.Decimal.InexactFloat64()) / (high - low) * float64(rows-1)))
+				}
+				grid[r][c] = marker
+			}
+		}
+
+		labels := make([]string, rows)
+		labels[0], labels[rows/2], labels[rows-1] = formatChartValue(high), formatChartValue((high+low)/2), formatChartValue(low)
+		labelWidth := 0
+		for _, label := range labels {
+			labelWidth = max(labelWidth, len(label))
+		}
+		for r, line := range grid {
+			fmt.Fprintf(w, "%*s │%s\n", labelWidth, labels[r], strings.TrimRight(string(line), " "))
+		}
+		fmt.Fprintf(w, "%*s └%s\n", labelWidth, "", strings.Repeat("─", columns))
+		gap := max(columns-len(first)-len(last), 1)
+		fmt.Fprintf(w, "%*s  %s%s%s\n", labelWidth, "", first, strings.Repeat(" ", gap), last)
+	}
+}
+
+// chartColors HTML 图中各序列的颜色，按面板内顺序分配
+var chartColors = []string{"#222222", "#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+// WriteIndicatorHTML 输出独立的 HTML 图表（内嵌 SVG，不依赖外部脚本），每个面板一张图
+func WriteIndicatorHTML(w io.Writer, preview *IndicatorPreview, title string) error {
+	const (
+		chartWidth  = 1000.0
+		marginLeft  = 90.0
+		marginRight = 10.0
+		marginY     = 12.0
+	)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString("<style>body{font-family:sans-serif;margin:20px}h1{font-size:18px}.legend span{margin-right:16px}svg{display:block;margin-bottom:24px}text{font-size:11px;fill:#555}</style>\n")
+	fmt.Fprintf(&b, "</head>\n<body>\n<h1>%s</h1>\n", html.EscapeString(title))
+
+	n := len(preview.Rows)
+	if n > 0 {
+		first := preview.Rows[0].Kline.OpenTime.UTC().Format("2006-01-02 15:04")
+		last := preview.Rows[n-1].Kline.OpenTime.UTC().Format("2006-01-02 15:04")
+		x := func(i int) float64 {
+			if n == 1 {
+				return marginLeft + (chartWidth-marginLeft-marginRight)/2
+			}
+			return marginLeft + float64(i)*(chartWidth-marginLeft-marginRight)/float64(n-1)
+		}
+
+		for p, panel := range indicatorChartPanels(preview) {
+			chartHeight := 360.0
+			if p > 0 {
+				chartHeight = 180.0
+			}
+
+			b.WriteString("<div class=\"legend\">")
+			fmt.Fprintf(&b, "<strong>%s</strong> ", html.EscapeString(panel.name))
+			for i, series := range panel.series {
+				fmt.Fprintf(&b, "<span style=\"color:%s\">■ %s</span>", chartColors[i%len(chartColors)], html.EscapeString(series.name))
+			}
+			b.WriteString("</div>\n")
+
+			low, high, ok := panel.bounds()
+			if !ok {
+				b.WriteString("<p>insufficient data</p>\n")
+				continue
+			}
+			plotBottom := chartHeight - 2*marginY
+			y := func(v float64) float64 {
+				if high == low {
+					return (marginY + plotBottom) / 2
+				}
+				return marginY + (high-v)/(high-low)*(plotBottom-marginY)
+			}
+
+			fmt.Fprintf(&b, "<svg width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n", chartWidth, chartHeight, chartWidth, chartHeight)
+			fmt.Fprintf(&b, "<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" fill=\"none\" stroke=\"#ccc\"/>\n",
+				marginLeft, marginY, chartWidth-marginLeft-marginRight, plotBottom-marginY)
+			for _, tick := range []float64{high, (high + low) / 2, low} {
+				fmt.Fprintf(&b, "<text x=\"%.0f\" y=\"%.1f\" text-anchor=\"end\" dominant-baseline=\"middle\">%s</text>\n", marginLeft-6, y(tick), formatChartValue(tick))
+			}
+			fmt.Fprintf(&b, "<text x=\"%.0f\" y=\"%.0f\">%s</text>\n", marginLeft, chartHeight-6, first)
+			fmt.Fprintf(&b, "<text x=\"%.0f\" y=\"%.0f\" text-anchor=\"end\">%s</text>\n", chartWidth-marginRight, chartHeight-6, last)
+
+			// 先画后面的序列，收盘价在最上层；无效值处断开折线
+			for i := len(panel.series) - 1; i >= 0; i-- {
+				color := chartColors[i%len(chartColors)]
+				var points []string
+				flush := func() {
+					if len(points) > 0 {
+						fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"1.2\" points=\"%s\"/>\n", color, strings.Join(points, " "))
+						points = nil
+					}
+				}
+				for k, value := range panel.series[i].values {
+					if !value.Valid {
+						flush()
+						continue
+					}
+					points = append(points, fmt.Sprintf("%.1f,%.1f", x(k), y(value.Decimal.InexactFloat64())))
+				}
+				flush()
+			}
+			b.WriteString("</svg>\n")
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("写入HTML失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package trading
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/indicators"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// 指标预览支持的指标
+const (
+	PreviewIndicatorBB    = "bb"    // 布林带 bb[:周期[:倍数]]，默认 20、2
+	PreviewIndicatorATR   = "atr"   // ATR atr[:周期]，默认 14
+	PreviewIndicatorADX   = "adx"   // ADX/DMI adx[:周期]，默认 14
+	PreviewIndicatorStoch = "stoch" // 随机指标 stoch[:K周期[:K平滑[:D周期]]]，默认 14、3、3
+	PreviewIndicatorVWAP  = "vwap"  // VWAP，不带参数为按 UTC 日重置的会话 VWAP，vwap:N 为滚动 VWAP
+	PreviewIndicatorOBV   = "obv"   // 能量潮
+)
+
+// previewIndicatorDefaults 各指标的默认参数（参数个数上限）
+var previewIndicatorDefaults = map[string][]float64{
+	PreviewIndicatorBB:    {20, 2},
+	PreviewIndicatorATR:   {14},
+	PreviewIndicatorADX:   {14},
+	PreviewIndicatorStoch: {14, 3, 3},
+	PreviewIndicatorVWAP:  {0},
+	PreviewIndicatorOBV:   {},
+}
+
+// IndicatorSpec 指标预览的指标及参数
+type IndicatorSpec struct {
+	Name   string
+	Params []float64 // 缺省的参数已补全为默认值
+}
+
+// ParseIndicatorSpecs 解析逗号分隔的指标列表，如 "bb:20:2,atr:14,stoch"，为空时只预览默认参数的布林带
+func ParseIndicatorSpecs(value string) ([]IndicatorSpec, error) {
+	if strings.TrimSpace(value) == "" {
+		value = PreviewIndicatorBB
+	}
+
+	var specs []IndicatorSpec
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		defaults, ok := previewIndicatorDefaults[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q (supported: bb, atr, adx, stoch, vwap, obv)", name)
+		}
+		if len(parts)-1 > len(defaults) {
+			return nil, fmt.Errorf("indicator %s takes at most %d parameters: %s", name, len(defaults), item)
+		}
+
+		params := append([]float64(nil), defaults...)
+		for i, raw := range parts[1:] {
+			param, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %q for %s: %w", raw, name, err)
+			}
+			params[i] = param
+		}
+		spec := IndicatorSpec{Name: name, Params: params}
+		if err := spec.validate(); err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicators in %q", value)
+	}
+	return specs, nil
+}
+
+// validate 周期必须是正整数（会话 VWAP 为 0），布林带倍数必须为正
+func (s IndicatorSpec) validate() error {
+	for i, param := range s.Params {
+		if s.Name == PreviewIndicatorBB && i == 1 {
+			if param <= 0 {
+				return fmt.Errorf("%s: %w", s.Label(), indicators.ErrInvalidMultiplier)
+			}
+			continue
+		}
+		if param != math.Trunc(param) || param < 0 || (param == 0 && s.Name != PreviewIndicatorVWAP) {
+			return fmt.Errorf("%s: period must be a positive integer", s.Label())
+		}
+	}
+	return nil
+}
+
+// period 第 i 个参数作为周期
+func (s IndicatorSpec) period(i int) int {
+	return int(s.Params[i])
+}
+
+// Label 指标标签，用作列名前缀，如 bb_20_2、atr_14、vwap
+func (s IndicatorSpec) Label() string {
+	parts := []string{s.Name}
+	for _, param := range s.Params {
+		if s.Name == PreviewIndicatorVWAP && param == 0 {
+			continue
+		}
+		parts = append(parts, strconv.FormatFloat(param, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
+
+// Overlay 指标与价格同一坐标（布林带、VWAP）
+func (s IndicatorSpec) Overlay() bool {
+	return s.Name == PreviewIndicatorBB || s.Name == PreviewIndicatorVWAP
+}
+
+// Warmup 第一个有效值之前需要的K线数
+func (s IndicatorSpec) Warmup() int {
+	switch s.Name {
+	case PreviewIndicatorBB:
+		return s.period(0) - 1
+	case PreviewIndicatorATR:
+		return s.period(0)
+	case PreviewIndicatorADX:
+		return 2*s.period(0) - 1
+	case PreviewIndicatorStoch:
+		return s.period(0) + s.period(1) + s.period(2) - 3
+	case PreviewIndicatorVWAP:
+		return max(s.period(0)-1, 0)
+	default:
+		return 0
+	}
+}
+
+// IndicatorWarmup 多个指标中最长的预热K线数
+func IndicatorWarmup(specs []IndicatorSpec) int {
+	warmup := 0
+	for _, spec := range specs {
+		warmup = max(warmup, spec.Warmup())
+	}
+	return warmup
+}
+
+// IndicatorColumn 指标预览的一列
+type IndicatorColumn struct {
+	Name  string // 列名（CSV 表头和图例），如 bb_20_2_upper
+	Panel string // 所在图：叠加在价格上时为空，否则为指标标签
+}
+
+// IndicatorPreviewRow 一根K线及其指标值
+type IndicatorPreviewRow struct {
+	Kline  *cex.KlineData
+	Values []decimal.NullDecimal // 与 Columns 对应，数据不足时无效
+}
+
+// IndicatorPreview 区间内每根K线的指标值
+type IndicatorPreview struct {
+	Columns []IndicatorColumn
+	Rows    []IndicatorPreviewRow
+}
+
+// ComputeIndicatorPreview 在全部K线上计算指标，只保留开盘时间不早于 from 的K线（之前的用于预热）
+func ComputeIndicatorPreview(klines []*cex.KlineData, specs []IndicatorSpec, from time.Time) (*IndicatorPreview, error) {
+	preview := &IndicatorPreview{}
+	var series [][]decimal.NullDecimal
+	for _, spec := range specs {
+		names, values, err := spec.series(klines)
+		if err != nil {
+			return nil, err
+		}
+		panel := ""
+		if !spec.Overlay() {
+			panel = spec.Label()
+		}
+		for _, name := range names {
+			preview.Columns = append(preview.Columns, IndicatorColumn{Name: name, Panel: panel})
+		}
+		series = append(series, values...)
+	}
+
+	for i, kline := range klines {
+		if kline.OpenTime.Before(from) {
+			continue
+		}
+		row := IndicatorPreviewRow{Kline: kline, Values: make([]decimal.NullDecimal, len(series))}
+		for j := range series {
+			row.Values[j] = series[j][i]
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+	return preview, nil
+}
+
+// series 逐根K线计算指标，返回列名和每列的值序列
+func (s IndicatorSpec) series(klines []*cex.KlineData) ([]string, [][]decimal.NullDecimal, error) {
+	label := s.Label()
+	newColumns := func(count int) [][]decimal.NullDecimal {
+		columns := make([][]decimal.NullDecimal, count)
+		for i := range columns {
+			columns[i] = make([]decimal.NullDecimal, len(klines))
+		}
+		return columns
+	}
+	valid := func(value decimal.Decimal) decimal.NullDecimal {
+		return decimal.NullDecimal{Decimal: value, Valid: true}
+	}
+
+	switch s.Name {
+	case PreviewIndicatorBB:
+		bb := indicators.NewBollingerBands(s.period(0), s.Params[1])
+		columns := newColumns(3)
+		closes := make([]decimal.Decimal, len(klines))
+		for i, kline := range klines {
+			closes[i] = kline.Close
+			result, err := bb.Calculate(closes[max(i+1-bb.Period, 0) : i+1])
+			if err != nil {
+				continue
+			}
+			columns[0][i], columns[1][i], columns[2][i] = valid(result.UpperBand), valid(result.MiddleBand), valid(result.LowerBand)
+		}
+		return []string{label + "_upper", label + "_middle", label + "_lower"}, columns, nil
+
+	case PreviewIndicatorATR:
+		columns := newColumns(1)
+		highs, lows, closes := make([]decimal.Decimal, len(klines)), make([]decimal.Decimal, len(klines)), make([]decimal.Decimal, len(klines))
+		for i, kline := range klines {
+			highs[i], lows[i], closes[i] = kline.High, kline.Low, kline.Close
+		}
+		atr := indicators.NewATR(s.period(0))
+		values, err := atr.Series(highs, lows, closes)
+		if err == nil {
+			for i := atr.Period; i < len(values); i++ {
+				columns[0][i] = valid(values[i])
+			}
+		}
+		return []string{label}, columns, nil
+
+	case PreviewIndicatorADX:
+		adx := indicators.NewADX(s.period(0))
+		columns := newColumns(3)
+		for i, kline := range klines {
+			if result, ok := adx.Update(kline.High, kline.Low, kline.Close); ok {
+				columns[0][i], columns[1][i], columns[2][i] = valid(result.ADX), valid(result.PlusDI), valid(result.MinusDI)
+			}
+		}
+		return []string{label, label + "_plus_di", label + "_minus_di"}, columns, nil
+
+	case PreviewIndicatorStoch:
+		stoch := indicators.NewStochastic(s.period(0), s.period(1), s.period(2))
+		columns := newColumns(2)
+		for i, kline := range klines {
+			if result, ok := stoch.Update(kline.High, kline.Low, kline.Close); ok {
+				columns[0][i], columns[1][i] = valid(result.K), valid(result.D)
+			}
+		}
+		return []string{label + "_k", label + "_d"}, columns, nil
+
+	case PreviewIndicatorVWAP:
+		vwap := indicators.NewSessionVWAP(0)
+		if s.period(0) > 0 {
+			vwap = indicators.NewRollingVWAP(s.period(0))
+		}
+		columns := newColumns(1)
+		for i, kline := range klines {
+			if value, ok := vwap.Update(kline.OpenTime, kline.High, kline.Low, kline.Close, kline.Volume); ok {
+				columns[0][i] = valid(value)
+			}
+		}
+		return []string{label}, columns, nil
+
+	case PreviewIndicatorOBV:
+		obv := indicators.NewOBV()
+		columns := newColumns(1)
+		for i, kline := range klines {
+			columns[0][i] = valid(obv.Update(kline.Close, kline.Volume))
+		}
+		return []string{label}, columns, nil
+	}
+	return nil, nil, fmt.Errorf("unknown indicator %q", s.Name)
+}
+
+// LoadIndicatorKlines 加载 [startTime, endTime] 的K线，并向前多取 warmup 根用于指标预热；
+// 数据库可用时先读缓存，只向交易所请求缺失区间（与回测相同）
+func LoadIndicatorKlines(ctx context.Context, client cex.CEXClient, pair cex.TradingPair, tf timeframes.Timeframe, startTime, endTime time.Time, warmup int) ([]*cex.KlineData, error) {
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+	actualStartTime := startTime.Add(-time.Duration(warmup) * duration)
+
+	var klines []*cex.KlineData
+	if db, dbErr := GetPostgresDB(client); dbErr == nil {
+		klines, err = NewCachedKlineProvider(client, db).GetKlinesWithTimeRange(ctx, pair, tf, actualStartTime, endTime)
+	} else {
+		klines, err = client.GetKlinesWithTimeRange(ctx, pair, tf.GetBinanceInterval(), actualStartTime, endTime, 1000)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s %s klines: %w", pair.String(), tf, err)
+	}
+	return klines, nil
+}
+
+// formatIndicatorValue 指标值保留 8 位小数，无效时为空
+func formatIndicatorValue(value decimal.NullDecimal) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.Decimal.Round(8).String()
+}
+
+// WriteIndicatorCSV 以CSV格式写出K线和指标值，数据不足的指标为空
+func WriteIndicatorCSV(w io.Writer, preview *IndicatorPreview) error {
+	writer := csv.NewWriter(w)
+	header := []string{"open_time", "open", "high", "low", "close", "volume"}
+	for _, column := range preview.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+	for _, row := range preview.Rows {
+		kline := row.Kline
+		record := []string{
+			kline.OpenTime.UTC().Format(time.RFC3339),
+			kline.Open.String(), kline.High.String(), kline.Low.String(), kline.Close.String(), kline.Volume.String(),
+		}
+		for _, value := range row.Values {
+			record = append(record, formatIndicatorValue(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV失败: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,111 @@
+package trading
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tradingbot/src/indicators"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndicatorSpecs(t *testing.T) {
+	// 为空时只有默认布林带
+	specs, err := ParseIndicatorSpecs("")
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "bb_20_2", specs[0].Label())
+
+	// 缺省参数补默认值
+	specs, err = ParseIndicatorSpecs("BB:25:2.2, atr, stoch:9, vwap, vwap:20, obv")
+	require.NoError(t, err)
+	labels := make([]string, len(specs))
+	for i, spec := range specs {
+		labels[i] = spec.Label()
+	}
+	assert.Equal(t, []string{"bb_25_2.2", "atr_14", "stoch_9_3_3", "vwap", "vwap_20", "obv"}, labels)
+	assert.True(t, specs[0].Overlay())
+	assert.False(t, specs[1].Overlay())
+	assert.Equal(t, 24, IndicatorWarmup(specs))
+
+	for _, value := range []string{"macd", "atr:14:2", "atr:2.5", "adx:0", "bb:20:0", "stoch:x"} {
+		_, err := ParseIndicatorSpecs(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestComputeIndicatorPreview(t *testing.T) {
+	klines := streamTestKlines(40)
+	specs, err := ParseIndicatorSpecs("bb:5:2,atr:3,obv")
+	require.NoError(t, err)
+
+	// 从第一根开始：预热期内无效
+	preview, err := ComputeIndicatorPreview(klines, specs, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, preview.Rows, 40)
+	names := make([]string, len(preview.Columns))
+	for i, column := range preview.Columns {
+		names[i] = column.Name
+	}
+	assert.Equal(t, []string{"bb_5_2_upper", "bb_5_2_middle", "bb_5_2_lower", "atr_3", "obv"}, names)
+	assert.Equal(t, "", preview.Columns[0].Panel)
+	assert.Equal(t, "atr_3", preview.Columns[3].Panel)
+	assert.False(t, preview.Rows[3].Values[0].Valid)
+	assert.True(t, preview.Rows[4].Values[0].Valid)
+	assert.False(t, preview.Rows[2].Values[3].Valid)
+	assert.True(t, preview.Rows[3].Values[3].Valid)
+	assert.True(t, preview.Rows[0].Values[4].Valid)
+
+	// 从第 11 根开始：之前的K线只用于预热，值与直接计算一致
+	preview, err = ComputeIndicatorPreview(klines, specs, klines[10].OpenTime)
+	require.NoError(t, err)
+	require.Len(t, preview.Rows, 30)
+	assert.Equal(t, klines[10], preview.Rows[0].Kline)
+
+	closes := make([]decimal.Decimal, 11)
+	for i := range closes {
+		closes[i] = klines[i].Close
+	}
+	bb, err := indicators.NewBollingerBands(5, 2).Calculate(closes)
+	require.NoError(t, err)
+	assert.True(t, bb.UpperBand.Equal(preview.Rows[0].Values[0].Decimal))
+	assert.True(t, bb.LowerBand.Equal(preview.Rows[0].Values[2].Decimal))
+}
+
+func TestWriteIndicatorOutputs(t *testing.T) {
+	klines := streamTestKlines(30)
+	specs, err := ParseIndicatorSpecs("bb:5:2,atr:3")
+	require.NoError(t, err)
+	preview, err := ComputeIndicatorPreview(klines, specs, time.Time{})
+	require.NoError(t, err)
+
+	// CSV：数据不足的指标为空
+	var buf bytes.Buffer
+	require.NoError(t, WriteIndicatorCSV(&buf, preview))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 31)
+	assert.Equal(t, "open_time,open,high,low,close,volume,bb_5_2_upper,bb_5_2_middle,bb_5_2_lower,atr_3", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "2024-01-31T12:00:00Z,"))
+	assert.True(t, strings.HasSuffix(lines[1], ",,,,"))
+
+	// ASCII 图：价格面板和 ATR 面板，K线多于宽度时按宽度抽样
+	buf.Reset()
+	WriteIndicatorChart(&buf, preview, 20, 8)
+	output := buf.String()
+	assert.Contains(t, output, "[price] * close  + bb_5_2_upper  - bb_5_2_middle  o bb_5_2_lower")
+	assert.Contains(t, output, "[atr_3] * atr_3")
+	assert.Contains(t, output, "└"+strings.Repeat("─", 20)+"\n")
+	assert.Equal(t, 12+8, strings.Count(output, "\n"))
+
+	// HTML：每个面板一张 SVG，标题转义
+	buf.Reset()
+	require.NoError(t, WriteIndicatorHTML(&buf, preview, "BTC/USDT <1h>"))
+	output = buf.String()
+	assert.Contains(t, output, "<title>BTC/USDT &lt;1h&gt;</title>")
+	assert.Equal(t, 2, strings.Count(output, "<svg"))
+	assert.Equal(t, 5, strings.Count(output, "<polyline"))
+}
@@ -0,0 +1,69 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/timeframes"
+)
+
+// DatabaseIntrabarSource 从数据库读取回测K线内的细粒度K线（如 1s、1m，需先 sync 对应周期）
+type DatabaseIntrabarSource struct {
+	reader    KlineReader
+	pair      cex.TradingPair
+	timeframe timeframes.Timeframe
+}
+
+// NewDatabaseIntrabarSource 创建数据库细粒度行情来源
+func NewDatabaseIntrabarSource(reader KlineReader, pair cex.TradingPair, tf timeframes.Timeframe) *DatabaseIntrabarSource {
+	return &DatabaseIntrabarSource{reader: reader, pair: pair, timeframe: tf}
+}
+
+// Intrabar 读取开盘时间在K线开盘和收盘之间的细粒度K线
+func (s *DatabaseIntrabarSource) Intrabar(ctx context.Context, kline *cex.KlineData) ([]*cex.KlineData, error) {
+	bars, err := s.reader.GetKlines(ctx, DatabaseSymbol(s.pair), s.timeframe.String(), kline.OpenTime.UnixMilli(), kline.CloseTime.UnixMilli(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s klines: %w", s.timeframe, err)
+	}
+	for _, bar := range bars {
+		bar.TradingPair = s.pair
+	}
+	return bars, nil
+}
+
+// Describe 数据来源说明
+func (s *DatabaseIntrabarSource) Describe() string {
+	return fmt.Sprintf("database %s %s klines", DatabaseSymbol(s.pair), s.timeframe)
+}
+
+// newIntrabarSource 按配置 Backtest.IntrabarTimeframe 创建K线内细粒度行情来源，未配置时返回 nil
+// 细粒度周期必须短于回测周期，且数据库中已有该周期的K线
+func (ts *TradingSystem) newIntrabarSource(pair cex.TradingPair, timeframe timeframes.Timeframe) (engine.IntrabarSource, error) {
+	if TradingConfigValue.Backtest.IntrabarTimeframe == "" {
+		return nil, nil
+	}
+	intrabarTimeframe, err := timeframes.ParseTimeframe(TradingConfigValue.Backtest.IntrabarTimeframe)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backtest config: IntrabarTimeframe: %w", err)
+	}
+	intrabarDuration, _ := intrabarTimeframe.GetDuration()
+	duration, _ := timeframe.GetDuration()
+	if intrabarDuration >= duration {
+		return nil, fmt.Errorf("invalid backtest config: IntrabarTimeframe %s must be shorter than the backtest timeframe %s", intrabarTimeframe, timeframe)
+	}
+
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return nil, fmt.Errorf("intrabar backtest requires the kline database: %w", err)
+	}
+	latest, err := db.GetLatestKlineTime(ts.ctx, DatabaseSymbol(pair), intrabarTimeframe.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read klines from database: %w", err)
+	}
+	if latest == 0 {
+		return nil, fmt.Errorf("no %s klines in database for %s, run 'sync -t %s' first or disable Backtest.IntrabarTimeframe", intrabarTimeframe, pair.String(), intrabarTimeframe)
+	}
+	return NewDatabaseIntrabarSource(db, pair, intrabarTimeframe), nil
+}
@@ -0,0 +1,59 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseIntrabarSource_Intrabar(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var minutes []*cex.KlineData
+	for i := 0; i < 180; i++ {
+		price := decimal.NewFromInt(int64(100 + i))
+		minutes = append(minutes, &cex.KlineData{OpenTime: start.Add(time.Duration(i) * time.Minute),
+			CloseTime: start.Add(time.Duration(i+1)*time.Minute - time.Millisecond), Open: price, High: price, Low: price, Close: price})
+	}
+	source := NewDatabaseIntrabarSource(&sliceKlineReader{klines: minutes}, syncTestPair, timeframes.Timeframe1m)
+
+	// 第二根小时K线内的 60 根分钟K线
+	hour := &cex.KlineData{OpenTime: start.Add(time.Hour), CloseTime: start.Add(2*time.Hour - time.Millisecond)}
+	bars, err := source.Intrabar(context.Background(), hour)
+	require.NoError(t, err)
+	require.Len(t, bars, 60)
+	assert.Equal(t, hour.OpenTime, bars[0].OpenTime)
+	assert.Equal(t, start.Add(119*time.Minute), bars[59].OpenTime)
+	assert.Equal(t, syncTestPair, bars[0].TradingPair)
+}
+
+func TestTradingSystem_NewIntrabarSource(t *testing.T) {
+	saved := TradingConfigValue.Backtest.IntrabarTimeframe
+	defer func() { TradingConfigValue.Backtest.IntrabarTimeframe = saved }()
+	ts := &TradingSystem{ctx: context.Background()}
+
+	// 未配置时按整根K线撮合
+	TradingConfigValue.Backtest.IntrabarTimeframe = ""
+	source, err := ts.newIntrabarSource(syncTestPair, timeframes.Timeframe4h)
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	TradingConfigValue.Backtest.IntrabarTimeframe = "4h"
+	_, err = ts.newIntrabarSource(syncTestPair, timeframes.Timeframe4h)
+	assert.ErrorContains(t, err, "must be shorter")
+
+	TradingConfigValue.Backtest.IntrabarTimeframe = "2s"
+	_, err = ts.newIntrabarSource(syncTestPair, timeframes.Timeframe4h)
+	assert.ErrorContains(t, err, "invalid timeframe")
+
+	// 需要K线数据库
+	TradingConfigValue.Backtest.IntrabarTimeframe = "1s"
+	_, err = ts.newIntrabarSource(syncTestPair, timeframes.Timeframe4h)
+	assert.ErrorContains(t, err, "requires the kline database")
+}
@@ -0,0 +1,128 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+)
+
+// KlineCacheStore K线缓存存储（由 database.PostgresDB 实现）
+type KlineCacheStore interface {
+	// GetKlines 获取开盘时间在 [startTime, endTime]（毫秒）内的K线，按开盘时间升序
+	GetKlines(ctx context.Context, symbol, timeframe string, startTime, endTime int64, limit int) ([]*cex.KlineData, error)
+
+	// SaveKlinesBatch 批量保存K线（已存在的K线会被更新）
+	SaveKlinesBatch(ctx context.Context, symbol, timeframe string, klines []*cex.KlineData) error
+}
+
+// CachedKlineProvider 带数据库缓存的K线来源：先读数据库，只向交易所请求缺失区间，
+// 已收盘的K线写回数据库，同一交易对/区间重复回测时无需再请求交易所
+type CachedKlineProvider struct {
+	client cex.CEXClient
+	store  KlineCacheStore
+	now    func() time.Time
+}
+
+// NewCachedKlineProvider 创建带缓存的K线来源
+func NewCachedKlineProvider(client cex.CEXClient, store KlineCacheStore) *CachedKlineProvider {
+	return &CachedKlineProvider{
+		client: client,
+		store:  store,
+		now:    time.Now,
+	}
+}
+
+// GetKlinesWithTimeRange 获取开盘时间在 [startTime, endTime] 内的K线
+func (p *CachedKlineProvider) GetKlinesWithTimeRange(ctx context.Context, pair cex.TradingPair, tf timeframes.Timeframe, startTime, endTime time.Time) ([]*cex.KlineData, error) {
+	symbol := DatabaseSymbol(pair)
+
+	cached, err := p.store.GetKlines(ctx, symbol, tf.String(), startTime.UnixMilli(), endTime.UnixMilli(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kline cache: %w", err)
+	}
+
+	merged := make(map[int64]*cex.KlineData, len(cached))
+	openTimes := make([]time.Time, 0, len(cached))
+	for _, k := range cached {
+		k.TradingPair = pair
+		merged[k.OpenTime.UnixMilli()] = k
+		openTimes = append(openTimes, k.OpenTime)
+	}
+
+	missing, err := missingKlineRanges(openTimes, tf, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var fetched int
+	for _, r := range missing {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("kline loading cancelled: %w", err)
+		}
+
+		// 交易所客户端负责分页，缺失区间再长也会完整加载
+		klines, err := p.client.GetKlinesWithTimeRange(ctx, pair, tf.GetBinanceInterval(), r.Start, r.End.Add(-time.Millisecond), 1000)
+		if err != nil {
+			return nil, err
+		}
+
+		// 只缓存已收盘的K线，当前K线下次仍从交易所获取
+		now := p.now()
+		closed := make([]*cex.KlineData, 0, len(klines))
+		for _, k := range klines {
+			if k.OpenTime.Before(startTime) || k.OpenTime.After(endTime) {
+				continue
+			}
+			merged[k.OpenTime.UnixMilli()] = k
+			fetched++
+			if k.CloseTime.Before(now) {
+				closed = append(closed, k)
+			}
+		}
+
+		// 写缓存失败不影响本次结果
+		if err := p.store.SaveKlinesBatch(ctx, symbol, tf.String(), closed); err != nil {
+			fmt.Printf("⚠️ Failed to cache klines: %v\n", err)
+		}
+	}
+
+	result := make([]*cex.KlineData, 0, len(merged))
+	for _, k := range merged {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OpenTime.Before(result[j].OpenTime) })
+
+	fmt.Printf("📦 Kline cache: %d from database, %d from %s (%d missing ranges)\n",
+		len(cached), fetched, p.client.GetName(), len(missing))
+
+	return result, nil
+}
+
+// missingKlineRanges 根据已缓存K线的开盘时间计算 [startTime, endTime] 内需要请求的区间（左闭右开）
+func missingKlineRanges(openTimes []time.Time, tf timeframes.Timeframe, startTime, endTime time.Time) ([]KlineGap, error) {
+	ranges, err := FindKlineGaps(openTimes, tf, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// 最后一根缓存K线之后的部分
+	tailStart := startTime
+	for _, openTime := range openTimes {
+		next, err := timeframes.NextOpenTime(openTime, tf)
+		if err != nil {
+			return nil, err
+		}
+		if next.After(tailStart) {
+			tailStart = next
+		}
+	}
+	if !tailStart.After(endTime) {
+		ranges = append(ranges, KlineGap{Start: tailStart, End: endTime.Add(time.Millisecond)})
+	}
+
+	return ranges, nil
+}
@@ -0,0 +1,81 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/timeframes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingKlineRanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	ranges, err := missingKlineRanges(nil, timeframes.Timeframe1h, start, at(5))
+	require.NoError(t, err)
+	assert.Equal(t, []KlineGap{{Start: start, End: at(5).Add(time.Millisecond)}}, ranges)
+
+	ranges, err = missingKlineRanges([]time.Time{at(1), at(2), at(4)}, timeframes.Timeframe1h, start, at(6))
+	require.NoError(t, err)
+	assert.Equal(t, []KlineGap{
+		{Start: at(0), End: at(1)},
+		{Start: at(3), End: at(4)},
+		{Start: at(5), End: at(6).Add(time.Millisecond)},
+	}, ranges)
+
+	// 缓存已覆盖到结束时间
+	ranges, err = missingKlineRanges([]time.Time{at(0), at(1), at(2)}, timeframes.Timeframe1h, start, at(2))
+	require.NoError(t, err)
+	assert.Empty(t, ranges)
+}
+
+func TestCachedKlineProvider_FetchesOnlyMissingRanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &mockSyncClient{tf: timeframes.Timeframe1h}
+	store := newMockKlineStore()
+	provider := NewCachedKlineProvider(client, store)
+	provider.now = func() time.Time { return start.Add(100 * time.Hour) }
+	ctx := context.Background()
+
+	klines, err := provider.GetKlinesWithTimeRange(ctx, syncTestPair, timeframes.Timeframe1h, start, start.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, klines, 10)
+	assert.Len(t, client.requests, 1)
+	assert.Len(t, store.klines, 10)
+
+	// 相同区间直接从缓存读取
+	klines, err = provider.GetKlinesWithTimeRange(ctx, syncTestPair, timeframes.Timeframe1h, start, start.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, klines, 10)
+	assert.Len(t, client.requests, 1)
+	assert.Equal(t, syncTestPair, klines[0].TradingPair)
+
+	// 扩大区间只请求新增部分，结果按时间排序
+	klines, err = provider.GetKlinesWithTimeRange(ctx, syncTestPair, timeframes.Timeframe1h, start.Add(-2*time.Hour), start.Add(11*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, klines, 14)
+	require.Len(t, client.requests, 3)
+	assert.Equal(t, start.Add(-2*time.Hour), client.requests[1][0])
+	assert.Equal(t, start.Add(10*time.Hour), client.requests[2][0])
+	for i := 1; i < len(klines); i++ {
+		assert.True(t, klines[i].OpenTime.After(klines[i-1].OpenTime))
+	}
+}
+
+func TestCachedKlineProvider_DoesNotCacheOpenKline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &mockSyncClient{tf: timeframes.Timeframe1h}
+	store := newMockKlineStore()
+	provider := NewCachedKlineProvider(client, store)
+	// 当前时间 03:30，03:00 的K线尚未收盘
+	provider.now = func() time.Time { return start.Add(3*time.Hour + 30*time.Minute) }
+
+	klines, err := provider.GetKlinesWithTimeRange(context.Background(), syncTestPair, timeframes.Timeframe1h, start, start.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, klines, 4)
+	assert.Len(t, store.klines, 3)
+}
@@ -0,0 +1,314 @@
+package trading
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// KlineStats 逐根K线累计的回测统计：夏普比率、收益归因、市场状态和数据指纹，不需要保留全部K线
+type KlineStats struct {
+	orders         []executor.OrderResult
+	initialCapital decimal.Decimal
+
+	sharpe      *sharpeTracker
+	attribution *attributionTracker
+	regimes     *regimeTracker // 未启用时为 nil
+	fingerprint *dataFingerprinter
+	lastKline   *cex.KlineData
+}
+
+// NewKlineStats 创建K线统计累计器，orders 为回测的全部成交
+func NewKlineStats(orders []executor.OrderResult, initialCapital decimal.Decimal) *KlineStats {
+	return &KlineStats{
+		orders:         orders,
+		initialCapital: initialCapital,
+		sharpe:         newSharpeTracker(orders, initialCapital),
+		attribution:    newAttributionTracker(orders, initialCapital),
+		fingerprint:    newDataFingerprinter(),
+	}
+}
+
+// Add 累计一根K线（按时间顺序调用）
+func (s *KlineStats) Add(kline *cex.KlineData) {
+	s.sharpe.add(kline)
+	s.attribution.add(kline)
+	if s.regimes != nil {
+		s.regimes.add(kline)
+	}
+	s.fingerprint.add(kline)
+	s.lastKline = kline
+}
+
+// EnableRegimes 按市场状态拆分策略表现（在第一次 Add 之前调用）
+func (s *KlineStats) EnableRegimes(config RegimeConfig) {
+	s.regimes = newRegimeTracker(config, s.orders, s.initialCapital)
+}
+
+// LastKline 最后一根K线（没有K线时为 nil）
+func (s *KlineStats) LastKline() *cex.KlineData {
+	return s.lastKline
+}
+
+// SharpeRatio 年化夏普比率
+func (s *KlineStats) SharpeRatio(timeframe timeframes.Timeframe) decimal.Decimal {
+	return s.sharpe.result(timeframe)
+}
+
+// Attribution 相对买入持有基准的收益归因
+func (s *KlineStats) Attribution() ReturnAttribution {
+	return s.attribution.result()
+}
+
+// Regimes 各市场状态下的策略表现，已平仓交易按开仓时间归入市场状态（未启用时为 nil）
+func (s *KlineStats) Regimes(trades []TradeAnalysis) *RegimeReport {
+	if s.regimes == nil {
+		return nil
+	}
+	return s.regimes.result(trades)
+}
+
+// Fingerprint K线范围和内容哈希
+func (s *KlineStats) Fingerprint() DataFingerprint {
+	return s.fingerprint.result()
+}
+
+// portfolioValuer 逐根K线按收盘价计算组合价值（计入手续费）
+type portfolioValuer struct {
+	orders     []executor.OrderResult // 按时间排序
+	orderIndex int
+	cash       decimal.Decimal
+	position   decimal.Decimal
+}
+
+func newPortfolioValuer(orders []executor.OrderResult, initialCapital decimal.Decimal) *portfolioValuer {
+	return &portfolioValuer{
+		orders:   sortOrdersByTime(orders),
+		cash:     initialCapital,
+		position: decimal.Zero,
+	}
+}
+
+// next 计入收盘前的成交，返回按K线收盘价估值的组合价值
+func (v *portfolioValuer) next(kline *cex.KlineData) decimal.Decimal {
+	for v.orderIndex < len(v.orders) && !v.orders[v.orderIndex].Timestamp.After(kline.CloseTime) {
+		order := v.orders[v.orderIndex]
+		notional := order.Price.Mul(order.Quantity)
+		if order.Side == executor.OrderSideBuy {
+			v.cash = v.cash.Sub(notional).Sub(order.Commission)
+			v.position = v.position.Add(order.Quantity)
+		} else if order.Side == executor.OrderSideSell {
+			v.cash = v.cash.Add(notional).Sub(order.Commission)
+			v.position = v.position.Sub(order.Quantity)
+		}
+		v.orderIndex++
+	}
+
+	return v.cash.Add(v.position.Mul(kline.Close))
+}
+
+// sharpeTracker 逐根K线累计收益率的均值和方差（Welford 算法）
+type sharpeTracker struct {
+	valuer    *portfolioValuer
+	prevValue float64
+	hasPrev   bool
+
+	count int
+	mean  float64
+	m2    float64 // 与均值差的平方和
+}
+
+func newSharpeTracker(orders []executor.OrderResult, initialCapital decimal.Decimal) *sharpeTracker {
+	return &sharpeTracker{valuer: newPortfolioValuer(orders, initialCapital)}
+}
+
+func (t *sharpeTracker) add(kline *cex.KlineData) {
+	value := t.valuer.next(kline).InexactFloat64()
+	if t.hasPrev && t.prevValue > 0 {
+		r := value/t.prevValue - 1
+		t.count++
+		delta := r - t.mean
+		t.mean += delta / float64(t.count)
+		t.m2 += delta * (r - t.mean)
+	}
+	t.prevValue, t.hasPrev = value, true
+}
+
+// result 年化夏普比率（无风险利率为0）
+func (t *sharpeTracker) result(timeframe timeframes.Timeframe) decimal.Decimal {
+	if t.count < 2 {
+		return decimal.Zero
+	}
+
+	stdDev := math.Sqrt(t.m2 / float64(t.count-1))
+	if stdDev == 0 {
+		return decimal.Zero
+	}
+
+	// 按时间周期年化
+	duration, err := timeframe.GetDuration()
+	if err != nil || duration <= 0 {
+		return decimal.Zero
+	}
+	periodsPerYear := float64(365*24*time.Hour) / float64(duration)
+
+	return decimal.NewFromFloat(t.mean / stdDev * math.Sqrt(periodsPerYear))
+}
+
+// attributionTracker 逐根K线累计收益归因：Beta 用在线协方差计算，按月收益在月份切换时结算
+type attributionTracker struct {
+	valuer         *portfolioValuer
+	initialCapital decimal.Decimal
+	disabled       bool // 初始资金或首根K线开盘价不为正时不做归因
+
+	firstKline *cex.KlineData
+	lastKline  *cex.KlineData
+	lastValue  decimal.Decimal
+	prevValue  float64
+	prevPrice  float64
+
+	// 策略收益 s 与基准收益 m 的在线均值、协方差和方差
+	count        int
+	meanS, meanM float64
+	coMoment     float64
+	m2M          float64
+
+	// 当前月份的起点
+	monthStartValue decimal.Decimal
+	monthStartPrice decimal.Decimal
+	monthly         []MonthlyAttribution
+}
+
+func newAttributionTracker(orders []executor.OrderResult, initialCapital decimal.Decimal) *attributionTracker {
+	return &attributionTracker{
+		valuer:         newPortfolioValuer(orders, initialCapital),
+		initialCapital: initialCapital,
+		disabled:       !initialCapital.IsPositive(),
+	}
+}
+
+func (t *attributionTracker) add(kline *cex.KlineData) {
+	if t.disabled {
+		return
+	}
+	if t.firstKline == nil {
+		if !kline.Open.IsPositive() {
+			t.disabled = true
+			return
+		}
+		t.firstKline = kline
+		t.prevValue = t.initialCapital.InexactFloat64()
+		t.prevPrice = kline.Open.InexactFloat64()
+		t.monthStartValue = t.initialCapital
+		t.monthStartPrice = kline.Open
+	} else if monthKey(kline.OpenTime) != monthKey(t.lastKline.OpenTime) {
+		t.closeMonth()
+	}
+
+	value := t.valuer.next(kline)
+
+	// 逐根K线收益率，首根K线以初始资金和开盘价为起点
+	valueFloat := value.InexactFloat64()
+	price := kline.Close.InexactFloat64()
+	if t.prevValue > 0 && t.prevPrice > 0 {
+		s, m := valueFloat/t.prevValue-1, price/t.prevPrice-1
+		t.count++
+		deltaS := s - t.meanS
+		deltaM := m - t.meanM
+		t.meanS += deltaS / float64(t.count)
+		t.meanM += deltaM / float64(t.count)
+		t.coMoment += deltaS * (m - t.meanM)
+		t.m2M += deltaM * (m - t.meanM)
+	}
+	t.prevValue, t.prevPrice = valueFloat, price
+
+	t.lastKline = kline
+	t.lastValue = value
+}
+
+// closeMonth 以上一根K线（月末）的收盘值结算当前月份
+func (t *attributionTracker) closeMonth() {
+	monthly := MonthlyAttribution{Month: monthKey(t.lastKline.OpenTime)}
+	if t.monthStartValue.IsPositive() {
+		monthly.StrategyReturn = t.lastValue.Div(t.monthStartValue).Sub(decimal.NewFromInt(1))
+	}
+	if t.monthStartPrice.IsPositive() {
+		monthly.MarketReturn = t.lastKline.Close.Div(t.monthStartPrice).Sub(decimal.NewFromInt(1))
+	}
+	t.monthly = append(t.monthly, monthly)
+
+	t.monthStartValue = t.lastValue
+	t.monthStartPrice = t.lastKline.Close
+}
+
+// beta 策略收益对基准收益的回归系数 cov(s, m) / var(m)
+func (t *attributionTracker) beta() float64 {
+	if t.count < 2 || t.m2M == 0 {
+		return 0
+	}
+	return t.coMoment / t.m2M
+}
+
+func (t *attributionTracker) result() ReturnAttribution {
+	attribution := ReturnAttribution{Monthly: []MonthlyAttribution{}}
+	if t.disabled || t.lastKline == nil {
+		return attribution
+	}
+
+	attribution.Beta = decimal.NewFromFloat(t.beta())
+
+	// 结算最后一个月（不修改累计状态，可重复调用）
+	monthly := t.monthly
+	last := *t
+	last.monthly = nil
+	last.closeMonth()
+	monthly = append(append([]MonthlyAttribution(nil), monthly...), last.monthly...)
+	for _, m := range monthly {
+		m.MarketComponent = attribution.Beta.Mul(m.MarketReturn)
+		m.Alpha = m.StrategyReturn.Sub(m.MarketComponent)
+		attribution.Monthly = append(attribution.Monthly, m)
+	}
+
+	totalReturn := t.lastValue.Div(t.initialCapital).Sub(decimal.NewFromInt(1))
+	attribution.MarketReturn = t.lastKline.Close.Div(t.firstKline.Open).Sub(decimal.NewFromInt(1))
+	attribution.MarketComponent = attribution.Beta.Mul(attribution.MarketReturn)
+	attribution.Alpha = totalReturn.Sub(attribution.MarketComponent)
+
+	return attribution
+}
+
+// dataFingerprinter 逐根K线累计数据范围和内容哈希
+type dataFingerprinter struct {
+	hash        hash.Hash
+	fingerprint DataFingerprint
+}
+
+func newDataFingerprinter() *dataFingerprinter {
+	return &dataFingerprinter{hash: sha256.New()}
+}
+
+func (f *dataFingerprinter) add(kline *cex.KlineData) {
+	fmt.Fprintf(f.hash, "%d|%s|%s|%s|%s|%s\n", kline.OpenTime.UnixMilli(),
+		kline.Open.String(), kline.High.String(), kline.Low.String(), kline.Close.String(), kline.Volume.String())
+
+	if f.fingerprint.Klines == 0 {
+		f.fingerprint.FirstOpen = kline.OpenTime
+	}
+	f.fingerprint.LastOpen = kline.OpenTime
+	f.fingerprint.Klines++
+}
+
+func (f *dataFingerprinter) result() DataFingerprint {
+	fingerprint := f.fingerprint
+	fingerprint.Hash = hex.EncodeToString(f.hash.Sum(nil))
+	return fingerprint
+}
@@ -0,0 +1,158 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+)
+
+// DefaultKlineStreamBatchSize 流式读取K线的默认批次大小
+const DefaultKlineStreamBatchSize = 10000
+
+// KlineReader 按开盘时间范围读取K线（由 database.PostgresDB 实现）
+type KlineReader interface {
+	// GetKlines 获取开盘时间在 [startTime, endTime]（毫秒）内的K线，按开盘时间升序，最多 limit 根
+	GetKlines(ctx context.Context, symbol, timeframe string, startTime, endTime int64, limit int) ([]*cex.KlineData, error)
+}
+
+// KlineStream 以开盘时间为游标分批读取数据库中的K线，内存中只保留当前批次
+// 每批从上一批最后一根K线之后开始（keyset 分页），不依赖长事务，可重复遍历
+type KlineStream struct {
+	reader    KlineReader
+	pair      cex.TradingPair
+	timeframe timeframes.Timeframe
+	startTime time.Time
+	endTime   time.Time
+	batchSize int
+}
+
+// NewKlineStream 创建开盘时间在 [startTime, endTime] 内的K线流，batchSize <= 0 时使用默认批次大小
+func NewKlineStream(reader KlineReader, pair cex.TradingPair, tf timeframes.Timeframe, startTime, endTime time.Time, batchSize int) *KlineStream {
+	if batchSize <= 0 {
+		batchSize = DefaultKlineStreamBatchSize
+	}
+	return &KlineStream{
+		reader:    reader,
+		pair:      pair,
+		timeframe: tf,
+		startTime: startTime,
+		endTime:   endTime,
+		batchSize: batchSize,
+	}
+}
+
+// fetch 读取开盘时间不早于 from 的下一批K线
+func (s *KlineStream) fetch(ctx context.Context, from time.Time) ([]*cex.KlineData, error) {
+	klines, err := s.reader.GetKlines(ctx, DatabaseSymbol(s.pair), s.timeframe.String(), from.UnixMilli(), s.endTime.UnixMilli(), s.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream klines from %s: %w", from.Format("2006-01-02T15:04"), err)
+	}
+	for _, kline := range klines {
+		kline.TradingPair = s.pair
+	}
+	return klines, nil
+}
+
+// ForEach 按时间顺序把K线逐根交给 fn
+func (s *KlineStream) ForEach(ctx context.Context, fn func(kline *cex.KlineData)) error {
+	from := s.startTime
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := s.fetch(ctx, from)
+		if err != nil {
+			return err
+		}
+		for _, kline := range batch {
+			fn(kline)
+		}
+		if len(batch) < s.batchSize {
+			return nil
+		}
+		from = batch[len(batch)-1].OpenTime.Add(time.Millisecond)
+	}
+}
+
+// DatabaseDataFeed 从数据库分批流式读取K线的回测数据喂入器，不把整个回测区间的K线放进内存
+type DatabaseDataFeed struct {
+	stream *KlineStream
+
+	batch       []*cex.KlineData
+	index       int
+	next        time.Time // 下一批的起始开盘时间
+	exhausted   bool
+	currentTime time.Time
+	err         error // 读取失败后数据流结束，回测应视为失败
+}
+
+// NewDatabaseDataFeed 创建数据库流式数据喂入器
+func NewDatabaseDataFeed(stream *KlineStream) *DatabaseDataFeed {
+	return &DatabaseDataFeed{
+		stream:      stream,
+		next:        stream.startTime,
+		currentTime: stream.startTime,
+	}
+}
+
+func (f *DatabaseDataFeed) Start(ctx context.Context) error {
+	f.batch = nil
+	f.index = 0
+	f.next = f.stream.startTime
+	f.exhausted = false
+	f.currentTime = f.stream.startTime
+	f.err = nil
+	return nil
+}
+
+func (f *DatabaseDataFeed) GetNext(ctx context.Context) (*cex.KlineData, error) {
+	if f.index >= len(f.batch) {
+		if f.exhausted {
+			return nil, nil // 数据流结束
+		}
+
+		batch, err := f.stream.fetch(ctx, f.next)
+		if err != nil {
+			// 引擎遇到错误会继续取下一根，结束数据流避免反复重试，由 Err 报告失败
+			f.err = err
+			f.exhausted = true
+			f.batch = nil
+			return nil, err
+		}
+		if len(batch) < f.stream.batchSize {
+			f.exhausted = true
+		}
+		if len(batch) == 0 {
+			f.batch = nil
+			return nil, nil
+		}
+		f.batch = batch
+		f.index = 0
+		f.next = batch[len(batch)-1].OpenTime.Add(time.Millisecond)
+	}
+
+	kline := f.batch[f.index]
+	f.batch[f.index] = nil // 已交给引擎的K线不再由批次引用
+	f.index++
+	f.currentTime = kline.OpenTime
+
+	return kline, nil
+}
+
+func (f *DatabaseDataFeed) Stop() error {
+	f.exhausted = true
+	f.batch = nil
+	return nil
+}
+
+func (f *DatabaseDataFeed) GetCurrentTime() time.Time {
+	return f.currentTime
+}
+
+// Err 读取K线失败时返回错误（数据流因此提前结束）
+func (f *DatabaseDataFeed) Err() error {
+	return f.err
+}
@@ -0,0 +1,140 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceKlineReader 按开盘时间和 limit 读取内存K线，记录每次读取的起始时间
+type sliceKlineReader struct {
+	klines []*cex.KlineData
+	calls  []int64
+	err    error
+}
+
+func (r *sliceKlineReader) GetKlines(ctx context.Context, symbol, timeframe string, startTime, endTime int64, limit int) ([]*cex.KlineData, error) {
+	r.calls = append(r.calls, startTime)
+	if r.err != nil {
+		return nil, r.err
+	}
+	var klines []*cex.KlineData
+	for _, k := range r.klines {
+		openTime := k.OpenTime.UnixMilli()
+		if openTime >= startTime && openTime <= endTime && (limit <= 0 || len(klines) < limit) {
+			klines = append(klines, &cex.KlineData{OpenTime: k.OpenTime, CloseTime: k.CloseTime, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume})
+		}
+	}
+	return klines, nil
+}
+
+// streamTestKlines 跨月的小时K线，价格先涨后跌再回升
+func streamTestKlines(n int) []*cex.KlineData {
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	klines := make([]*cex.KlineData, n)
+	for i := range klines {
+		price := decimal.NewFromInt(int64(100 + (i%7)*3 - (i%5)*2))
+		klines[i] = &cex.KlineData{
+			OpenTime:  start.Add(time.Duration(i) * time.Hour),
+			CloseTime: start.Add(time.Duration(i+1)*time.Hour - time.Millisecond),
+			Open:      price.Sub(decimal.NewFromInt(1)), High: price.Add(decimal.NewFromInt(2)), Low: price.Sub(decimal.NewFromInt(2)), Close: price,
+			Volume: decimal.NewFromInt(int64(10 + i)),
+		}
+	}
+	return klines
+}
+
+func TestKlineStream_ForEachReadsInBatches(t *testing.T) {
+	klines := streamTestKlines(10)
+	reader := &sliceKlineReader{klines: klines}
+	stream := NewKlineStream(reader, syncTestPair, timeframes.Timeframe1h, klines[0].OpenTime, klines[9].OpenTime, 3)
+
+	var got []*cex.KlineData
+	require.NoError(t, stream.ForEach(context.Background(), func(kline *cex.KlineData) { got = append(got, kline) }))
+
+	require.Len(t, got, 10)
+	for i, kline := range got {
+		assert.Equal(t, klines[i].OpenTime, kline.OpenTime)
+		assert.Equal(t, syncTestPair, kline.TradingPair)
+	}
+	// 3 + 3 + 3 + 1，每批从上一批最后一根之后开始
+	require.Len(t, reader.calls, 4)
+	assert.Equal(t, klines[2].OpenTime.UnixMilli()+1, reader.calls[1])
+	assert.Equal(t, klines[8].OpenTime.UnixMilli()+1, reader.calls[3])
+}
+
+func TestDatabaseDataFeed(t *testing.T) {
+	klines := streamTestKlines(7)
+	reader := &sliceKlineReader{klines: klines}
+	feed := NewDatabaseDataFeed(NewKlineStream(reader, syncTestPair, timeframes.Timeframe1h, klines[0].OpenTime, klines[6].OpenTime, 7))
+	ctx := context.Background()
+
+	// 批次大小恰好等于K线数量时多读一次空批次后结束；Start 后可重新遍历
+	for round := 0; round < 2; round++ {
+		require.NoError(t, feed.Start(ctx))
+		for i := range klines {
+			kline, err := feed.GetNext(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, kline)
+			assert.Equal(t, klines[i].OpenTime, kline.OpenTime)
+			assert.Equal(t, klines[i].OpenTime, feed.GetCurrentTime())
+		}
+		kline, err := feed.GetNext(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, kline)
+		assert.NoError(t, feed.Err())
+	}
+	assert.Len(t, reader.calls, 4)
+}
+
+func TestDatabaseDataFeed_ReadErrorEndsStream(t *testing.T) {
+	reader := &sliceKlineReader{err: errors.New("connection reset")}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feed := NewDatabaseDataFeed(NewKlineStream(reader, syncTestPair, timeframes.Timeframe1h, start, start.Add(time.Hour), 0))
+	ctx := context.Background()
+	require.NoError(t, feed.Start(ctx))
+
+	_, err := feed.GetNext(ctx)
+	require.Error(t, err)
+
+	// 引擎继续取数据时流已结束，不会反复重试
+	kline, err := feed.GetNext(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, kline)
+	assert.Len(t, reader.calls, 1)
+	assert.ErrorContains(t, feed.Err(), "connection reset")
+}
+
+func TestKlineStats_StreamMatchesInMemory(t *testing.T) {
+	klines := streamTestKlines(60)
+	initialCapital := decimal.NewFromInt(10000)
+	orders := []executor.OrderResult{
+		{Side: executor.OrderSideBuy, Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(50), Commission: decimal.NewFromInt(5), Timestamp: klines[2].CloseTime},
+		{Side: executor.OrderSideSell, Price: decimal.NewFromInt(104), Quantity: decimal.NewFromInt(20), Commission: decimal.NewFromInt(2), Timestamp: klines[20].CloseTime},
+		{Side: executor.OrderSideSell, Price: decimal.NewFromInt(98), Quantity: decimal.NewFromInt(30), Commission: decimal.NewFromInt(3), Timestamp: klines[41].CloseTime},
+	}
+
+	stats := NewKlineStats(orders, initialCapital)
+	stream := NewKlineStream(&sliceKlineReader{klines: klines}, syncTestPair, timeframes.Timeframe1h, klines[0].OpenTime, klines[59].OpenTime, 8)
+	require.NoError(t, stream.ForEach(context.Background(), stats.Add))
+
+	assert.True(t, CalculateSharpeRatio(orders, klines, initialCapital, timeframes.Timeframe1h).Equal(stats.SharpeRatio(timeframes.Timeframe1h)))
+	assert.Equal(t, CalculateReturnAttribution(orders, klines, initialCapital), stats.Attribution())
+	assert.Equal(t, NewDataFingerprint(klines), stats.Fingerprint())
+	assert.Equal(t, klines[59].OpenTime, stats.LastKline().OpenTime)
+
+	// 跨月：1月和2月两个月份
+	attribution := stats.Attribution()
+	require.Len(t, attribution.Monthly, 2)
+	assert.Equal(t, "2024-01", attribution.Monthly[0].Month)
+	assert.Equal(t, "2024-02", attribution.Monthly[1].Month)
+}
@@ -0,0 +1,319 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+)
+
+// 同步状态（sync_status.status）
+const (
+	SyncStatusRunning   = "RUNNING"
+	SyncStatusCompleted = "COMPLETED"
+	SyncStatusFailed    = "FAILED"
+)
+
+// KlineStore K线存储（由 database.PostgresDB 实现）
+type KlineStore interface {
+	// GetLatestKlineTime 获取最新K线开盘时间（毫秒），无数据时返回 0
+	GetLatestKlineTime(ctx context.Context, symbol, timeframe string) (int64, error)
+
+	// GetKlineOpenTimes 获取已保存K线的开盘时间（毫秒，升序）
+	GetKlineOpenTimes(ctx context.Context, symbol, timeframe string, startTime, endTime int64) ([]int64, error)
+
+	// SaveKlinesBatch 批量保存K线（已存在的K线会被更新）
+	SaveKlinesBatch(ctx context.Context, symbol, timeframe string, klines []*cex.KlineData) error
+
+	// UpdateSyncStatus 更新同步状态
+	UpdateSyncStatus(ctx context.Context, symbol, timeframe string, lastOpenTime int64, totalRecords int, status, errorMsg string) error
+}
+
+// KlineGap 缺失的K线区间 [Start, End)
+type KlineGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// SyncResult 单个交易对/周期的同步结果
+type SyncResult struct {
+	Symbol       string
+	Timeframe    timeframes.Timeframe
+	Fetched      int       // 本次下载并保存的K线数
+	GapsFilled   int       // 回补的缺口数
+	TotalRecords int       // 同步后库中K线总数
+	LastOpenTime time.Time // 库中最新K线的开盘时间
+}
+
+// lastOpenTimeMs 最新K线开盘时间（毫秒），无数据时为 0
+func (r *SyncResult) lastOpenTimeMs() int64 {
+	if r.LastOpenTime.IsZero() {
+		return 0
+	}
+	return r.LastOpenTime.UnixMilli()
+}
+
+// KlineSyncer 从交易所下载历史K线并保存到数据库：从最新K线续传，回补缺口，按间隔限频
+type KlineSyncer struct {
+	client          cex.CEXClient
+	store           KlineStore
+	batchSize       int
+	requestInterval time.Duration
+	maxRetries      int
+
+	lastRequest time.Time
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// NewKlineSyncer 创建K线同步器
+func NewKlineSyncer(client cex.CEXClient, store KlineStore, config SyncConfig) (*KlineSyncer, error) {
+	if config.BatchSize <= 0 || config.BatchSize > 1000 {
+		return nil, fmt.Errorf("sync batch size must be in (0, 1000], got %d", config.BatchSize)
+	}
+	if config.RequestIntervalMs < 0 || config.MaxRetries < 0 {
+		return nil, fmt.Errorf("sync request interval and max retries cannot be negative")
+	}
+
+	return &KlineSyncer{
+		client:          client,
+		store:           store,
+		batchSize:       config.BatchSize,
+		requestInterval: time.Duration(config.RequestIntervalMs) * time.Millisecond,
+		maxRetries:      config.MaxRetries,
+		now:             time.Now,
+		sleep:           sleepContext,
+	}, nil
+}
+
+// sleepContext 等待 d，ctx 取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ParseTradingPairs 解析 BASE/QUOTE 格式的交易对列表
+func ParseTradingPairs(pairs []string) ([]cex.TradingPair, error) {
+	result := make([]cex.TradingPair, 0, len(pairs))
+	for _, p := range pairs {
+		parts := strings.Split(strings.TrimSpace(p), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid trading pair %q, expected BASE/QUOTE", p)
+		}
+		result = append(result, CreateTradingPair(parts[0], parts[1]))
+	}
+	return result, nil
+}
+
+// StartTime 解析同步起始时间（UTC，支持 YYYY-MM-DD 和 YYYY-MM-DD HH:MM）
+func (c SyncConfig) StartTime() (time.Time, error) {
+	for _, format := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(format, c.StartDate, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid sync start date %q (supported: YYYY-MM-DD, YYYY-MM-DD HH:MM)", c.StartDate)
+}
+
+// FindKlineGaps 根据已保存K线的开盘时间查找 startTime 之后的缺口（不含最新K线之后的部分）
+func FindKlineGaps(openTimes []time.Time, tf timeframes.Timeframe, startTime time.Time) ([]KlineGap, error) {
+	expected, err := timeframes.BucketStart(startTime, tf)
+	if err != nil {
+		return nil, err
+	}
+	if expected.Before(startTime) {
+		expected, _ = timeframes.NextOpenTime(expected, tf)
+	}
+
+	sorted := append([]time.Time(nil), openTimes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var gaps []KlineGap
+	for _, openTime := range sorted {
+		if openTime.Before(expected) {
+			continue
+		}
+		if openTime.After(expected) {
+			gaps = append(gaps, KlineGap{Start: expected, End: openTime.UTC()})
+		}
+		expected, _ = timeframes.NextOpenTime(openTime, tf)
+	}
+	return gaps, nil
+}
+
+// Sync 同步一个交易对/周期：先回补 startTime 之后的缺口，再从最新K线续传到最后一根已收盘K线
+func (s *KlineSyncer) Sync(ctx context.Context, pair cex.TradingPair, tf timeframes.Timeframe, startTime time.Time) (*SyncResult, error) {
+	symbol := DatabaseSymbol(pair)
+	result := &SyncResult{Symbol: symbol, Timeframe: tf}
+
+	err := s.sync(ctx, pair, tf, startTime, result)
+
+	status, errorMsg := SyncStatusCompleted, ""
+	if err != nil {
+		status, errorMsg = SyncStatusFailed, err.Error()
+	}
+	// ctx 可能已取消，状态写入不受影响
+	if updateErr := s.store.UpdateSyncStatus(context.Background(), symbol, tf.String(),
+		result.lastOpenTimeMs(), result.TotalRecords, status, errorMsg); updateErr != nil && err == nil {
+		err = fmt.Errorf("failed to update sync status: %w", updateErr)
+	}
+
+	return result, err
+}
+
+// sync 执行同步，进度写入 result
+func (s *KlineSyncer) sync(ctx context.Context, pair cex.TradingPair, tf timeframes.Timeframe, startTime time.Time, result *SyncResult) error {
+	symbol := result.Symbol
+
+	// 只同步已收盘的K线
+	end, err := timeframes.BucketStart(s.now(), tf)
+	if err != nil {
+		return err
+	}
+
+	latest, err := s.store.GetLatestKlineTime(ctx, symbol, tf.String())
+	if err != nil {
+		return err
+	}
+
+	from := startTime
+	if latest > 0 {
+		openTimesMs, err := s.store.GetKlineOpenTimes(ctx, symbol, tf.String(), 0, 0)
+		if err != nil {
+			return err
+		}
+		result.TotalRecords = len(openTimesMs)
+		result.LastOpenTime = time.UnixMilli(latest).UTC()
+
+		openTimes := make([]time.Time, len(openTimesMs))
+		for i, ms := range openTimesMs {
+			openTimes[i] = time.UnixMilli(ms).UTC()
+		}
+		gaps, err := FindKlineGaps(openTimes, tf, startTime)
+		if err != nil {
+			return err
+		}
+
+		for _, gap := range gaps {
+			fmt.Printf("🩹 %s %s gap: %s ~ %s\n", symbol, tf, gap.Start.Format("2006-01-02 15:04"), gap.End.Format("2006-01-02 15:04"))
+			fetched := result.Fetched
+			if err := s.fetchRange(ctx, pair, tf, gap.Start, gap.End, result); err != nil {
+				return err
+			}
+			// 交易所停机等原因造成的缺口无法回补
+			if result.Fetched > fetched {
+				result.GapsFilled++
+			}
+		}
+
+		if from, err = timeframes.NextOpenTime(result.LastOpenTime, tf); err != nil {
+			return err
+		}
+	}
+
+	return s.fetchRange(ctx, pair, tf, from, end, result)
+}
+
+// fetchRange 分批下载 [from, to) 的K线并保存，每批保存后更新同步进度
+func (s *KlineSyncer) fetchRange(ctx context.Context, pair cex.TradingPair, tf timeframes.Timeframe, from, to time.Time, result *SyncResult) error {
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return err
+	}
+	batchDuration := time.Duration(s.batchSize) * duration
+
+	for from.Before(to) {
+		batchEnd := from.Add(batchDuration)
+		if batchEnd.After(to) {
+			batchEnd = to
+		}
+
+		klines, err := s.fetchBatch(ctx, pair, tf, from, batchEnd)
+		if err != nil {
+			return err
+		}
+
+		// 交易所可能返回范围外或尚未收盘的K线
+		batch := make([]*cex.KlineData, 0, len(klines))
+		for _, k := range klines {
+			if !k.OpenTime.Before(from) && k.OpenTime.Before(batchEnd) {
+				batch = append(batch, k)
+			}
+		}
+
+		if len(batch) == 0 {
+			// 该区间交易所无数据（如上市之前），跳到下一批
+			from = batchEnd
+			continue
+		}
+
+		if err := s.store.SaveKlinesBatch(ctx, result.Symbol, tf.String(), batch); err != nil {
+			return err
+		}
+
+		last := batch[len(batch)-1].OpenTime.UTC()
+		result.Fetched += len(batch)
+		result.TotalRecords += len(batch)
+		if last.After(result.LastOpenTime) {
+			result.LastOpenTime = last
+		}
+
+		if err := s.store.UpdateSyncStatus(ctx, result.Symbol, tf.String(),
+			result.lastOpenTimeMs(), result.TotalRecords, SyncStatusRunning, ""); err != nil {
+			return fmt.Errorf("failed to update sync status: %w", err)
+		}
+		fmt.Printf("📥 %s %s: +%d klines up to %s (total %d)\n",
+			result.Symbol, tf, len(batch), last.Format("2006-01-02 15:04"), result.TotalRecords)
+
+		if from, err = timeframes.NextOpenTime(last, tf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchBatch 限频请求一批K线，失败时按指数退避重试
+func (s *KlineSyncer) fetchBatch(ctx context.Context, pair cex.TradingPair, tf timeframes.Timeframe, from, to time.Time) ([]*cex.KlineData, error) {
+	backoff := s.requestInterval
+	if backoff < time.Second {
+		backoff = time.Second
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("⚠️ Request failed (%v), retrying in %s...\n", lastErr, backoff)
+			if err := s.sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
+			backoff *= 2
+		}
+
+		if err := s.sleep(ctx, s.requestInterval-s.now().Sub(s.lastRequest)); err != nil {
+			return nil, err
+		}
+		s.lastRequest = s.now()
+
+		klines, err := s.client.GetKlinesWithTimeRange(ctx, pair, tf.GetBinanceInterval(), from, to.Add(-time.Millisecond), s.batchSize)
+		if err == nil {
+			return klines, nil
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("failed to fetch %s %s klines from %s: %w", pair.String(), tf, from.Format("2006-01-02 15:04"), lastErr)
+}
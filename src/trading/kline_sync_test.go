@@ -0,0 +1,233 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var syncTestPair = cex.TradingPair{Base: "BTC", Quote: "USDT"}
+
+// mockSyncClient 按请求范围返回连续K线的交易所mock
+type mockSyncClient struct {
+	cex.CEXClient
+	tf        timeframes.Timeframe
+	listing   time.Time // 最早有数据的时间
+	requests  [][2]time.Time
+	failTimes int
+}
+
+func (m *mockSyncClient) GetName() string { return "mock" }
+
+func (m *mockSyncClient) GetKlinesWithTimeRange(ctx context.Context, pair cex.TradingPair, interval string, startTime, endTime time.Time, limit int) ([]*cex.KlineData, error) {
+	m.requests = append(m.requests, [2]time.Time{startTime, endTime})
+	if m.failTimes > 0 {
+		m.failTimes--
+		return nil, errors.New("too many requests")
+	}
+
+	duration, _ := m.tf.GetDuration()
+	var klines []*cex.KlineData
+	for t := startTime; !t.After(endTime) && len(klines) < limit; t = t.Add(duration) {
+		if t.Before(m.listing) {
+			continue
+		}
+		klines = append(klines, &cex.KlineData{
+			TradingPair: pair,
+			OpenTime:    t,
+			CloseTime:   t.Add(duration - time.Millisecond),
+			Close:       decimal.NewFromInt(t.Unix()),
+		})
+	}
+	return klines, nil
+}
+
+// mockKlineStore 内存K线存储
+type mockKlineStore struct {
+	klines   map[int64]*cex.KlineData
+	statuses []string
+	lastOpen int64
+	total    int
+	errorMsg string
+}
+
+func newMockKlineStore() *mockKlineStore {
+	return &mockKlineStore{klines: make(map[int64]*cex.KlineData)}
+}
+
+func (s *mockKlineStore) GetLatestKlineTime(ctx context.Context, symbol, timeframe string) (int64, error) {
+	var latest int64
+	for openTime := range s.klines {
+		if openTime > latest {
+			latest = openTime
+		}
+	}
+	return latest, nil
+}
+
+func (s *mockKlineStore) GetKlineOpenTimes(ctx context.Context, symbol, timeframe string, startTime, endTime int64) ([]int64, error) {
+	openTimes := make([]int64, 0, len(s.klines))
+	for openTime := range s.klines {
+		openTimes = append(openTimes, openTime)
+	}
+	sort.Slice(openTimes, func(i, j int) bool { return openTimes[i] < openTimes[j] })
+	return openTimes, nil
+}
+
+func (s *mockKlineStore) GetKlines(ctx context.Context, symbol, timeframe string, startTime, endTime int64, limit int) ([]*cex.KlineData, error) {
+	var klines []*cex.KlineData
+	for openTime, k := range s.klines {
+		if openTime >= startTime && openTime <= endTime {
+			// 数据库不保存交易对
+			klines = append(klines, &cex.KlineData{OpenTime: k.OpenTime, CloseTime: k.CloseTime, Close: k.Close})
+		}
+	}
+	sort.Slice(klines, func(i, j int) bool { return klines[i].OpenTime.Before(klines[j].OpenTime) })
+	return klines, nil
+}
+
+func (s *mockKlineStore) SaveKlinesBatch(ctx context.Context, symbol, timeframe string, klines []*cex.KlineData) error {
+	for _, k := range klines {
+		s.klines[k.OpenTime.UnixMilli()] = k
+	}
+	return nil
+}
+
+func (s *mockKlineStore) UpdateSyncStatus(ctx context.Context, symbol, timeframe string, lastOpenTime int64, totalRecords int, status, errorMsg string) error {
+	s.statuses = append(s.statuses, status)
+	s.lastOpen, s.total, s.errorMsg = lastOpenTime, totalRecords, errorMsg
+	return nil
+}
+
+// newTestKlineSyncer 创建使用固定时间、不实际等待的同步器
+func newTestKlineSyncer(t *testing.T, client cex.CEXClient, store KlineStore, now time.Time, sleeps *[]time.Duration) *KlineSyncer {
+	syncer, err := NewKlineSyncer(client, store, SyncConfig{BatchSize: 4, RequestIntervalMs: 100, MaxRetries: 2})
+	require.NoError(t, err)
+	syncer.now = func() time.Time { return now }
+	syncer.sleep = func(ctx context.Context, d time.Duration) error {
+		if d > 0 {
+			*sleeps = append(*sleeps, d)
+		}
+		return nil
+	}
+	return syncer
+}
+
+func TestFindKlineGaps(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	// 起始时间未对齐，从 01:00 开始期望；01:00 缺失、04:00~05:00 缺失
+	openTimes := []time.Time{at(7), at(2), at(3), at(6), at(0)}
+	gaps, err := FindKlineGaps(openTimes, timeframes.Timeframe1h, base.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []KlineGap{
+		{Start: at(1), End: at(2)},
+		{Start: at(4), End: at(6)},
+	}, gaps)
+
+	gaps, err = FindKlineGaps([]time.Time{at(0), at(1), at(2)}, timeframes.Timeframe1h, base)
+	require.NoError(t, err)
+	assert.Empty(t, gaps)
+}
+
+func TestParseTradingPairs(t *testing.T) {
+	pairs, err := ParseTradingPairs([]string{"btc/usdt", " PEPE/USDC "})
+	require.NoError(t, err)
+	assert.Equal(t, []cex.TradingPair{{Base: "BTC", Quote: "USDT"}, {Base: "PEPE", Quote: "USDC"}}, pairs)
+
+	_, err = ParseTradingPairs([]string{"BTCUSDT"})
+	assert.Error(t, err)
+}
+
+func TestKlineSyncer_InitialSync(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 当前时间 10:30，10:00 的K线尚未收盘
+	now := start.Add(10*time.Hour + 30*time.Minute)
+	client := &mockSyncClient{tf: timeframes.Timeframe1h, listing: start.Add(2 * time.Hour)}
+	store := newMockKlineStore()
+	var sleeps []time.Duration
+
+	result, err := newTestKlineSyncer(t, client, store, now, &sleeps).Sync(context.Background(), syncTestPair, timeframes.Timeframe1h, start)
+	require.NoError(t, err)
+
+	// 上市前的 00:00~01:00 无数据，02:00~09:00 共8根
+	assert.Equal(t, 8, result.Fetched)
+	assert.Len(t, store.klines, 8)
+	assert.Equal(t, start.Add(9*time.Hour), result.LastOpenTime)
+	assert.Equal(t, "BTCUSDT", result.Symbol)
+	assert.Len(t, client.requests, 3)
+	assert.Equal(t, start.Add(10*time.Hour-time.Millisecond), client.requests[2][1])
+
+	assert.Equal(t, SyncStatusCompleted, store.statuses[len(store.statuses)-1])
+	assert.Contains(t, store.statuses, SyncStatusRunning)
+	assert.Equal(t, start.Add(9*time.Hour).UnixMilli(), store.lastOpen)
+	assert.Equal(t, 8, store.total)
+}
+
+func TestKlineSyncer_ResumeAndBackfillGaps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(12 * time.Hour)
+	client := &mockSyncClient{tf: timeframes.Timeframe1h}
+	store := newMockKlineStore()
+	for _, hour := range []int{0, 1, 2, 5, 6, 7} {
+		openTime := start.Add(time.Duration(hour) * time.Hour)
+		store.klines[openTime.UnixMilli()] = &cex.KlineData{OpenTime: openTime}
+	}
+	var sleeps []time.Duration
+
+	result, err := newTestKlineSyncer(t, client, store, now, &sleeps).Sync(context.Background(), syncTestPair, timeframes.Timeframe1h, start)
+	require.NoError(t, err)
+
+	// 回补 03:00~04:00，再从 08:00 续传到 11:00
+	require.Len(t, client.requests, 2)
+	assert.Equal(t, start.Add(3*time.Hour), client.requests[0][0])
+	assert.Equal(t, start.Add(8*time.Hour), client.requests[1][0])
+	assert.Equal(t, 1, result.GapsFilled)
+	assert.Equal(t, 6, result.Fetched)
+	assert.Equal(t, 12, result.TotalRecords)
+	assert.Len(t, store.klines, 12)
+	assert.Equal(t, start.Add(11*time.Hour), result.LastOpenTime)
+
+	// 第二次请求前按间隔限频
+	assert.NotEmpty(t, sleeps)
+}
+
+func TestKlineSyncer_RetryAndFailure(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(3 * time.Hour)
+	var sleeps []time.Duration
+
+	client := &mockSyncClient{tf: timeframes.Timeframe1h, failTimes: 1}
+	store := newMockKlineStore()
+	result, err := newTestKlineSyncer(t, client, store, now, &sleeps).Sync(context.Background(), syncTestPair, timeframes.Timeframe1h, start)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Fetched)
+	assert.Contains(t, sleeps, time.Second)
+
+	client = &mockSyncClient{tf: timeframes.Timeframe1h, failTimes: 10}
+	store = newMockKlineStore()
+	_, err = newTestKlineSyncer(t, client, store, now, &sleeps).Sync(context.Background(), syncTestPair, timeframes.Timeframe1h, start)
+	require.Error(t, err)
+	assert.Len(t, client.requests, 3)
+	assert.Equal(t, SyncStatusFailed, store.statuses[len(store.statuses)-1])
+	assert.Contains(t, store.errorMsg, "too many requests")
+}
+
+func TestSyncConfig_StartTime(t *testing.T) {
+	start, err := SyncConfig{StartDate: "2024-03-10 02:30"}.StartTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC), start)
+
+	_, err = SyncConfig{StartDate: "03/10/2024"}.StartTime()
+	assert.Error(t, err)
+}
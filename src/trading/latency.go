@@ -0,0 +1,35 @@
+package trading
+
+import (
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// startLatencyMonitor 创建实盘延迟统计，交易所支持查询服务器时间时在后台定期检查本地时钟偏差
+func (ts *TradingSystem) startLatencyMonitor() (*engine.LatencyMonitor, error) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	limits := TradingConfigValue.Latency
+	if err := limits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid latency config: %w", err)
+	}
+	monitor := engine.NewLatencyMonitor(limits)
+	logger.Info(fmt.Sprintf("✓ 延迟告警: max_data_delay=%dms, max_order_latency=%dms, max_clock_drift=%dms",
+		limits.MaxDataDelayMs, limits.MaxOrderLatencyMs, limits.MaxClockDriftMs))
+
+	if limits.ClockCheckMinutes <= 0 {
+		return monitor, nil
+	}
+	provider, ok := ts.cexClient.(cex.ServerTimeProvider)
+	if !ok {
+		logger.Warning(fmt.Sprintf("⚠️ %s 不支持查询服务器时间，不检查时钟偏差", ts.cexClient.GetName()))
+		return monitor, nil
+	}
+	monitor.StartClockCheck(ts.ctx, provider)
+	logger.Info(fmt.Sprintf("✓ 时钟偏差检查: interval=%dm", limits.ClockCheckMinutes))
+	return monitor, nil
+}
@@ -0,0 +1,104 @@
+package trading
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/safety"
+	"tradingbot/src/strategies"
+	"tradingbot/src/strategy"
+
+	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
+)
+
+// ArmLive 实盘启动前的安全门：显示交易对、账户余额、风险资金、仓位和风控限制、策略参数，要求输入 ARM 确认。
+// 配置 RequireArm 为 false 时只输出警告日志
+func (ts *TradingSystem) ArmLive(pair cex.TradingPair, strategyParams strategy.StrategyParams, in io.Reader, out io.Writer) error {
+	_, logger := log.WithCtx(ts.ctx)
+
+	if err := safety.ConfigValue.Validate(); err != nil {
+		return fmt.Errorf("invalid safety config: %w", err)
+	}
+	// 没有交易权限时不必确认；检查通过后启动实盘时不再重复检查
+	if err := ts.VerifyLivePermissions(); err != nil {
+		return err
+	}
+	ts.tradingVerified = true
+	if !safety.ConfigValue.RequireArm {
+		logger.Warning("⚠️ 实盘 ARM 确认已关闭（RequireArm=false）")
+		return nil
+	}
+	if ts.cexClient == nil {
+		return fmt.Errorf("CEX client not initialized")
+	}
+
+	summary, err := ts.liveSummary(pair, liveStrategyParams(strategyParams))
+	if err != nil {
+		return err
+	}
+	if err := safety.Arm(in, out, summary); err != nil {
+		return err
+	}
+	logger.Warning(fmt.Sprintf("🔴 实盘已确认: symbol=%s, capital_at_risk=%s", pair.String(), summary.CapitalAtRisk.String()))
+	return nil
+}
+
+// VerifyLivePermissions 实盘下单前检查交易权限：配置的 EnableTrading/ReadOnly 和 API 密钥的现货交易权限（查询账户接口）
+func (ts *TradingSystem) VerifyLivePermissions() error {
+	if ts.cexClient == nil {
+		return fmt.Errorf("CEX client not initialized")
+	}
+	if err := cex.VerifyTradingPermissions(ts.ctx, ts.cexClient); err != nil {
+		if errors.Is(err, cex.ErrTradingDisabled) {
+			return fmt.Errorf("%w (set EnableTrading to true and ReadOnly to false in the %s config to place real orders)", err, ts.cexClient.GetName())
+		}
+		return err
+	}
+	return nil
+}
+
+// liveSummary 查询账户余额，生成实盘确认摘要
+func (ts *TradingSystem) liveSummary(pair cex.TradingPair, params strategy.StrategyParams) (safety.Summary, error) {
+	balances, err := ts.cexClient.GetAccount(ts.ctx)
+	if err != nil {
+		return safety.Summary{}, fmt.Errorf("failed to get account balances: %w", err)
+	}
+
+	summary := safety.Summary{
+		Exchange:         ts.cexClient.GetName(),
+		Testnet:          cex.IsTestnet(ts.cexClient),
+		Base:             pair.Base,
+		Quote:            pair.Quote,
+		Timeframe:        ts.Timeframe(),
+		MaxOrderNotional: decimal.NewFromFloat(safety.ConfigValue.MaxOrderNotional),
+		Risk:             TradingConfigValue.Risk.Describe(),
+		Strategy:         strategies.NewBollingerBandsStrategy().GetName(),
+		StrategyParams:   fmt.Sprintf("%+v", params),
+	}
+	var quoteFree decimal.Decimal
+	for _, balance := range balances {
+		switch balance.Asset {
+		case pair.Quote:
+			summary.QuoteBalance = balance.Free.Add(balance.Locked)
+			quoteFree = balance.Free
+		case pair.Base:
+			summary.BaseBalance = balance.Free.Add(balance.Locked)
+		}
+	}
+
+	// 可用的计价资产都可能用于开仓，配置了最大持仓市值时以其为上限
+	summary.CapitalAtRisk = quoteFree
+	if maxPosition := decimal.NewFromFloat(TradingConfigValue.Risk.MaxPositionValue); maxPosition.IsPositive() && maxPosition.LessThan(quoteFree) {
+		summary.CapitalAtRisk = maxPosition
+	}
+
+	sizer, err := TradingConfigValue.PositionSizing.NewPositionSizer(TradingConfigValue.PositionSizePercent)
+	if err != nil {
+		return safety.Summary{}, err
+	}
+	summary.PositionSizing = sizer.Describe()
+	return summary, nil
+}
@@ -0,0 +1,112 @@
+package trading
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/strategy"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// RunManifest 回测复现清单：代码版本、配置快照、策略参数、数据范围和哈希、随机种子
+// 与回测结果一起保存（数据库和 -result-out 结果文件），相同清单的回测结果应完全一致
+type RunManifest struct {
+	GitCommit string `json:"git_commit,omitempty"` // 构建时的 git 提交（go build 记录的 vcs.revision）
+	GitDirty  bool   `json:"git_dirty,omitempty"`  // 构建时工作区有未提交的修改，提交号不足以复现
+	GoVersion string `json:"go_version"`
+
+	Exchange       string          `json:"exchange"`
+	Symbol         string          `json:"symbol"`
+	Timeframe      string          `json:"timeframe"`
+	StartTime      time.Time       `json:"start_time"`
+	EndTime        time.Time       `json:"end_time"`
+	InitialCapital decimal.Decimal `json:"initial_capital"`
+
+	StrategyName   string           `json:"strategy_name"`
+	StrategyParams json.RawMessage  `json:"strategy_params,omitempty"`
+	Config         json.RawMessage  `json:"config"` // 回测时的交易配置快照
+	Seeds          map[string]int64 `json:"seeds"`  // 随机成交模型的种子（backtest、illiquid_fill）
+
+	Data DataFingerprint `json:"data"`
+
+	TraceID   string    `json:"trace_id,omitempty"` // 本次回测的跟踪ID，可与日志、审计记录对应
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DataFingerprint 回测使用的K线（含指标预热部分）的范围和内容哈希
+type DataFingerprint struct {
+	Klines    int       `json:"klines"`
+	FirstOpen time.Time `json:"first_open"`
+	LastOpen  time.Time `json:"last_open"`
+	Hash      string    `json:"hash"` // 按顺序对每根K线的开盘时间和 OHLCV 计算的 SHA-256
+}
+
+// NewDataFingerprint 计算K线的范围和内容哈希，K线数据被修订（补缺口、重新下载）时哈希随之变化
+func NewDataFingerprint(klines []*cex.KlineData) DataFingerprint {
+	fingerprinter := newDataFingerprinter()
+	for _, kline := range klines {
+		fingerprinter.add(kline)
+	}
+	return fingerprinter.result()
+}
+
+// buildInfo 构建时记录的 git 提交和工作区状态（go test 或非 git 目录构建时为空）
+func buildInfo() (commit string, dirty bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", false
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			commit = setting.Value
+		case "vcs.modified":
+			dirty = setting.Value == "true"
+		}
+	}
+	return commit, dirty
+}
+
+// newRunManifest 生成一次回测的复现清单
+func (ts *TradingSystem) newRunManifest(pair cex.TradingPair, timeframe timeframes.Timeframe, strategyName string, params strategy.StrategyParams, data DataFingerprint, startTime, endTime time.Time, initialCapital float64) (*RunManifest, error) {
+	config, err := json.Marshal(TradingConfigValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot trading config: %w", err)
+	}
+
+	var paramsJSON json.RawMessage
+	if params != nil {
+		if paramsJSON, err = json.Marshal(params); err != nil {
+			return nil, fmt.Errorf("failed to marshal strategy params: %w", err)
+		}
+	}
+
+	commit, dirty := buildInfo()
+	return &RunManifest{
+		GitCommit:      commit,
+		GitDirty:       dirty,
+		GoVersion:      runtime.Version(),
+		Exchange:       ts.cexName,
+		Symbol:         pair.String(),
+		Timeframe:      timeframe.String(),
+		StartTime:      startTime,
+		EndTime:        endTime,
+		InitialCapital: decimal.NewFromFloat(initialCapital),
+		StrategyName:   strategyName,
+		StrategyParams: paramsJSON,
+		Config:         config,
+		Seeds: map[string]int64{
+			"backtest":      TradingConfigValue.Backtest.Seed,
+			"illiquid_fill": TradingConfigValue.IlliquidFill.Seed,
+		},
+		Data:      data,
+		TraceID:   ts.traceID,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package trading
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDataFingerprint(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := []*cex.KlineData{
+		{OpenTime: start, Open: decimal.NewFromInt(100), High: decimal.NewFromInt(105), Low: decimal.NewFromInt(95), Close: decimal.NewFromInt(102), Volume: decimal.NewFromInt(10)},
+		{OpenTime: start.Add(time.Hour), Open: decimal.NewFromInt(102), High: decimal.NewFromInt(108), Low: decimal.NewFromInt(101), Close: decimal.NewFromInt(107), Volume: decimal.NewFromInt(12)},
+	}
+
+	fingerprint := NewDataFingerprint(klines)
+	assert.Equal(t, 2, fingerprint.Klines)
+	assert.Equal(t, start, fingerprint.FirstOpen)
+	assert.Equal(t, start.Add(time.Hour), fingerprint.LastOpen)
+	assert.Len(t, fingerprint.Hash, 64)
+	assert.Equal(t, fingerprint, NewDataFingerprint(klines), "same data must hash the same")
+
+	// 修订K线数据后哈希变化
+	revised := []*cex.KlineData{klines[0], {OpenTime: klines[1].OpenTime, Open: klines[1].Open, High: klines[1].High, Low: klines[1].Low, Close: decimal.NewFromInt(106), Volume: klines[1].Volume}}
+	assert.NotEqual(t, fingerprint.Hash, NewDataFingerprint(revised).Hash)
+
+	empty := NewDataFingerprint(nil)
+	assert.Equal(t, 0, empty.Klines)
+	assert.True(t, empty.FirstOpen.IsZero())
+}
+
+func TestSetSeed(t *testing.T) {
+	config := TradingConfigValue
+	config.SetSeed(42)
+	assert.Equal(t, int64(42), config.Backtest.Seed)
+	assert.Equal(t, int64(42), config.IlliquidFill.Seed)
+}
+
+func TestBuildBacktestRunManifest(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	stats := &BacktestStatistics{
+		InitialCapital: decimal.NewFromInt(10000),
+		FinalPortfolio: decimal.NewFromInt(10000),
+		Manifest: &RunManifest{
+			Symbol: pair.String(),
+			Seeds:  map[string]int64{"backtest": 7},
+			Data:   DataFingerprint{Klines: 10, Hash: "abc"},
+		},
+	}
+
+	run, err := buildBacktestRun(pair, TradingConfigValue.Timeframe, "Bollinger Bands Strategy", nil, startTime, endTime, stats)
+	require.NoError(t, err)
+	require.NotEmpty(t, run.Manifest)
+
+	var manifest RunManifest
+	require.NoError(t, json.Unmarshal(run.Manifest, &manifest))
+	assert.Equal(t, int64(7), manifest.Seeds["backtest"])
+	assert.Equal(t, "abc", manifest.Data.Hash)
+}
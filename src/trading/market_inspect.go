@@ -0,0 +1,178 @@
+package trading
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/i18n"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+)
+
+// 查看K线的数据来源
+const (
+	KlineSourceExchange = "exchange"
+	KlineSourceDB       = "db"
+)
+
+// MaxInspectKlines 查看K线的最大根数（交易所单次请求上限）
+const MaxInspectKlines = 1000
+
+// RecentKlineStore 最近K线的数据库来源（由 database.PostgresDB 实现）
+type RecentKlineStore interface {
+	// GetLatestKlineTime 获取最新K线开盘时间（毫秒），无数据时返回 0
+	GetLatestKlineTime(ctx context.Context, symbol, timeframe string) (int64, error)
+
+	// GetKlines 获取开盘时间在 [startTime, endTime]（毫秒）内的K线，按开盘时间升序
+	GetKlines(ctx context.Context, symbol, timeframe string, startTime, endTime int64, limit int) ([]*cex.KlineData, error)
+}
+
+// FetchRecentKlines 从交易所获取最近 n 根K线（最后一根可能尚未收盘）
+func FetchRecentKlines(ctx context.Context, client cex.CEXClient, pair cex.TradingPair, tf timeframes.Timeframe, n int) ([]*cex.KlineData, error) {
+	klines, err := client.GetKlines(ctx, pair, tf.GetBinanceInterval(), n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s klines from %s: %w", pair.String(), tf, client.GetName(), err)
+	}
+	return klines, nil
+}
+
+// LoadRecentKlines 从数据库读取最新一根及之前共 n 个周期内的K线，缺失的K线不补（可用 CountKlineGaps 检查）
+func LoadRecentKlines(ctx context.Context, store RecentKlineStore, pair cex.TradingPair, tf timeframes.Timeframe, n int) ([]*cex.KlineData, error) {
+	duration, err := tf.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+	symbol := DatabaseSymbol(pair)
+	latest, err := store.GetLatestKlineTime(ctx, symbol, tf.String())
+	if err != nil {
+		return nil, err
+	}
+	if latest == 0 {
+		return nil, nil
+	}
+	start := latest - int64(n-1)*duration.Milliseconds()
+	return store.GetKlines(ctx, symbol, tf.String(), start, latest, n)
+}
+
+// CountKlineGaps 首尾K线之间缺失的根数
+func CountKlineGaps(klines []*cex.KlineData, tf timeframes.Timeframe) int {
+	if len(klines) < 2 {
+		return 0
+	}
+	openTimes := make([]time.Time, len(klines))
+	for i, kline := range klines {
+		openTimes[i] = kline.OpenTime
+	}
+	gaps, err := FindKlineGaps(openTimes, tf, klines[0].OpenTime)
+	if err != nil {
+		return 0
+	}
+
+	missing := 0
+	for _, gap := range gaps {
+		for openTime := gap.Start; openTime.Before(gap.End); missing++ {
+			if openTime, err = timeframes.NextOpenTime(openTime, tf); err != nil {
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// WriteKlinesTable 以表格输出K线（UTC 开盘时间）
+func WriteKlinesTable(w io.Writer, klines []*cex.KlineData) {
+	rows := make([][]string, 0, len(klines))
+	for _, kline := range klines {
+		rows = append(rows, []string{
+			kline.OpenTime.UTC().Format("2006-01-02 15:04"),
+			kline.Open.String(), kline.High.String(), kline.Low.String(), kline.Close.String(),
+			kline.Volume.String(), kline.QuoteVolume.String(),
+		})
+	}
+	WriteTable(w, []TableColumn{
+		{Header: i18n.T("klines.col.open_time")},
+		{Header: i18n.T("klines.col.open"), Right: true},
+		{Header: i18n.T("klines.col.high"), Right: true},
+		{Header: i18n.T("klines.col.low"), Right: true},
+		{Header: i18n.T("klines.col.close"), Right: true},
+		{Header: i18n.T("klines.col.volume"), Right: true},
+		{Header: i18n.T("klines.col.quote_volume"), Right: true},
+	}, rows)
+}
+
+// WriteKlinesCSV 以CSV格式写出K线
+func WriteKlinesCSV(w io.Writer, klines []*cex.KlineData) error {
+	writer := csv.NewWriter(w)
+	header := []string{"open_time", "close_time", "open", "high", "low", "close", "volume", "quote_volume", "taker_buy_volume", "taker_buy_quote_volume"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+	for _, kline := range klines {
+		record := []string{
+			kline.OpenTime.UTC().Format(time.RFC3339),
+			kline.CloseTime.UTC().Format(time.RFC3339),
+			kline.Open.String(), kline.High.String(), kline.Low.String(), kline.Close.String(),
+			kline.Volume.String(), kline.QuoteVolume.String(),
+			kline.TakerBuyVolume.String(), kline.TakerBuyQuoteVolume.String(),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV失败: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PriceQuote 交易对当前价格
+type PriceQuote struct {
+	Exchange string
+	Pair     cex.TradingPair
+	Last     decimal.Decimal // 最新成交价（当前 1 分钟K线收盘价）
+	Time     time.Time       // 最新价所在 1 分钟K线的开盘时间
+	Bid      decimal.Decimal // 买一价（交易所不支持盘口时为 0）
+	Ask      decimal.Decimal // 卖一价
+}
+
+// FetchPriceQuote 查询最新价和买一/卖一价（交易所支持盘口时）
+func FetchPriceQuote(ctx context.Context, client cex.CEXClient, pair cex.TradingPair) (*PriceQuote, error) {
+	klines, err := client.GetKlines(ctx, pair, timeframes.Timeframe1m.GetBinanceInterval(), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s price from %s: %w", pair.String(), client.GetName(), err)
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no price for %s on %s, check the symbol", pair.String(), client.GetName())
+	}
+
+	latest := klines[len(klines)-1]
+	quote := &PriceQuote{Exchange: client.GetName(), Pair: pair, Last: latest.Close, Time: latest.OpenTime}
+	if provider, ok := client.(cex.OrderBookProvider); ok {
+		book, err := provider.GetOrderBook(ctx, pair, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s order book from %s: %w", pair.String(), client.GetName(), err)
+		}
+		quote.Bid, quote.Ask = book.BestBid(), book.BestAsk()
+	}
+	return quote, nil
+}
+
+// Spread 买卖价差（基点，相对中间价），没有盘口时为 0
+func (q *PriceQuote) Spread() decimal.Decimal {
+	if !q.Bid.IsPositive() || !q.Ask.IsPositive() {
+		return decimal.Zero
+	}
+	mid := q.Bid.Add(q.Ask).Div(decimal.NewFromInt(2))
+	return q.Ask.Sub(q.Bid).Div(mid).Mul(decimal.NewFromInt(10000))
+}
+
+// WritePriceQuote 输出当前价格
+func WritePriceQuote(w io.Writer, quote *PriceQuote) {
+	fmt.Fprintln(w, i18n.T("price.last", quote.Pair.String(), quote.Exchange, quote.Last.String(), quote.Time.UTC().Format("2006-01-02 15:04")))
+	if quote.Bid.IsPositive() || quote.Ask.IsPositive() {
+		fmt.Fprintln(w, i18n.T("price.book", quote.Bid.String(), quote.Ask.String(), quote.Spread().StringFixed(2)))
+	}
+}
@@ -0,0 +1,100 @@
+package trading
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPriceClient 返回固定 1 分钟K线和盘口的交易所mock
+type mockPriceClient struct {
+	cex.CEXClient
+	klines    []*cex.KlineData
+	intervals []string
+	book      *cex.OrderBook
+}
+
+func (m *mockPriceClient) GetName() string {
+	return "mock"
+}
+
+func (m *mockPriceClient) GetKlines(ctx context.Context, pair cex.TradingPair, interval string, limit int) ([]*cex.KlineData, error) {
+	m.intervals = append(m.intervals, interval)
+	return m.klines, nil
+}
+
+func (m *mockPriceClient) GetOrderBook(ctx context.Context, pair cex.TradingPair, limit int) (*cex.OrderBook, error) {
+	return m.book, nil
+}
+
+func TestFetchPriceQuote(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	openTime := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	client := &mockPriceClient{
+		klines: []*cex.KlineData{{OpenTime: openTime, Close: decimal.NewFromInt(50000)}},
+		book: &cex.OrderBook{
+			Bids: []cex.OrderBookLevel{{Price: decimal.NewFromInt(49995)}},
+			Asks: []cex.OrderBookLevel{{Price: decimal.NewFromInt(50005)}},
+		},
+	}
+
+	quote, err := FetchPriceQuote(context.Background(), client, pair)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1m"}, client.intervals)
+	assert.Equal(t, "50000", quote.Last.String())
+	assert.Equal(t, openTime, quote.Time)
+	assert.Equal(t, "2.00", quote.Spread().StringFixed(2))
+
+	var buf bytes.Buffer
+	WritePriceQuote(&buf, quote)
+	assert.Contains(t, buf.String(), "50000")
+	assert.Contains(t, buf.String(), "49995")
+
+	// 没有K线时提示检查交易对
+	client.klines = nil
+	_, err = FetchPriceQuote(context.Background(), client, pair)
+	assert.ErrorContains(t, err, "check the symbol")
+}
+
+func TestLoadRecentKlines(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMockKlineStore()
+
+	klines, err := LoadRecentKlines(context.Background(), store, pair, timeframes.Timeframe1h, 3)
+	require.NoError(t, err)
+	assert.Empty(t, klines)
+
+	// 02:00 缺失
+	for _, hour := range []int{0, 1, 3, 4} {
+		openTime := base.Add(time.Duration(hour) * time.Hour)
+		require.NoError(t, store.SaveKlinesBatch(context.Background(), "BTCUSDT", "1h", []*cex.KlineData{
+			{OpenTime: openTime, CloseTime: openTime.Add(time.Hour - time.Millisecond), Close: decimal.NewFromInt(int64(100 + hour))},
+		}))
+	}
+
+	// 最近 4 个周期为 01:00~04:00
+	klines, err = LoadRecentKlines(context.Background(), store, pair, timeframes.Timeframe1h, 4)
+	require.NoError(t, err)
+	require.Len(t, klines, 3)
+	assert.Equal(t, base.Add(time.Hour), klines[0].OpenTime)
+	assert.Equal(t, 1, CountKlineGaps(klines, timeframes.Timeframe1h))
+	assert.Equal(t, 0, CountKlineGaps(klines[1:], timeframes.Timeframe1h))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteKlinesCSV(&buf, klines))
+	assert.Contains(t, buf.String(), "open_time,close_time,open,high,low,close")
+	assert.Contains(t, buf.String(), "2024-01-01T04:00:00Z,2024-01-01T04:59:59Z")
+
+	buf.Reset()
+	WriteKlinesTable(&buf, klines)
+	assert.Contains(t, buf.String(), "2024-01-01 03:00")
+}
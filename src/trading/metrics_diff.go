@@ -0,0 +1,69 @@
+package trading
+
+// MetricDiff 两次回测之间单个关键指标的变化
+type MetricDiff struct {
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"` // "%" 或空
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+	Better   string  `json:"better,omitempty"` // BetterHigher、BetterLower，为空时变化不分好坏（如交易次数）
+}
+
+const (
+	BetterHigher = "higher" // 指标越高越好
+	BetterLower  = "lower"  // 指标越低越好
+)
+
+// Improvement 指标变化方向：1 变好，-1 变差，0 不变或不分好坏
+func (d MetricDiff) Improvement() int {
+	sign := 0
+	if d.Delta > 0 {
+		sign = 1
+	} else if d.Delta < 0 {
+		sign = -1
+	}
+
+	switch d.Better {
+	case BetterHigher:
+		return sign
+	case BetterLower:
+		return -sign
+	default:
+		return 0
+	}
+}
+
+// keyBacktestMetrics 提取用于对比的关键指标（顺序即展示顺序）
+func keyBacktestMetrics(stats *BacktestStatistics) []MetricDiff {
+	winRate := 0.0
+	if stats.TotalTrades > 0 {
+		winRate = float64(stats.WinningTrades) / float64(stats.TotalTrades) * 100
+	}
+
+	return []MetricDiff{
+		{Name: "Total Return", Unit: "%", Current: stats.TotalReturn.InexactFloat64() * 100, Better: BetterHigher},
+		{Name: "Annual Return", Unit: "%", Current: stats.AnnualReturn.InexactFloat64(), Better: BetterHigher},
+		{Name: "Max Drawdown", Unit: "%", Current: stats.MaxDrawdownPercent.InexactFloat64(), Better: BetterLower},
+		{Name: "Sharpe Ratio", Current: stats.SharpeRatio.InexactFloat64(), Better: BetterHigher},
+		{Name: "Profit Factor", Current: stats.ProfitFactor.InexactFloat64(), Better: BetterHigher},
+		{Name: "Trades", Current: float64(stats.TotalTrades)},
+		{Name: "Win Rate", Unit: "%", Current: winRate, Better: BetterHigher},
+		{Name: "Alpha", Unit: "%", Current: stats.Attribution.Alpha.InexactFloat64() * 100, Better: BetterHigher},
+	}
+}
+
+// CompareBacktestMetrics 对比两次回测的关键指标，previous 为 nil 时 Delta 为 0
+func CompareBacktestMetrics(previous, current *BacktestStatistics) []MetricDiff {
+	diffs := keyBacktestMetrics(current)
+	if previous == nil {
+		return diffs
+	}
+
+	prevMetrics := keyBacktestMetrics(previous)
+	for i := range diffs {
+		diffs[i].Previous = prevMetrics[i].Current
+		diffs[i].Delta = diffs[i].Current - diffs[i].Previous
+	}
+	return diffs
+}
@@ -0,0 +1,51 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareBacktestMetrics(t *testing.T) {
+	previous := &BacktestStatistics{
+		TotalReturn:   decimal.NewFromFloat(0.10),
+		SharpeRatio:   decimal.NewFromFloat(1.2),
+		TotalTrades:   4,
+		WinningTrades: 2,
+	}
+	current := &BacktestStatistics{
+		TotalReturn:   decimal.NewFromFloat(0.15),
+		SharpeRatio:   decimal.NewFromFloat(1.0),
+		TotalTrades:   5,
+		WinningTrades: 4,
+	}
+
+	find := func(diffs []MetricDiff, name string) MetricDiff {
+		for _, d := range diffs {
+			if d.Name == name {
+				return d
+			}
+		}
+		require.Failf(t, "metric not found", name)
+		return MetricDiff{}
+	}
+
+	t.Run("first run has no delta", func(t *testing.T) {
+		diffs := CompareBacktestMetrics(nil, current)
+		for _, d := range diffs {
+			assert.Zero(t, d.Delta)
+			assert.Zero(t, d.Previous)
+		}
+		assert.InDelta(t, 15.0, find(diffs, "Total Return").Current, 1e-9)
+	})
+
+	t.Run("delta against previous run", func(t *testing.T) {
+		diffs := CompareBacktestMetrics(previous, current)
+		assert.InDelta(t, 5.0, find(diffs, "Total Return").Delta, 1e-9)
+		assert.InDelta(t, -0.2, find(diffs, "Sharpe Ratio").Delta, 1e-9)
+		assert.InDelta(t, 1.0, find(diffs, "Trades").Delta, 1e-9)
+		assert.InDelta(t, 30.0, find(diffs, "Win Rate").Delta, 1e-9)
+	})
+}
@@ -0,0 +1,35 @@
+package trading
+
+import (
+	"fmt"
+	"strings"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/notify"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// startNotifications 按通知配置的路由规则把事件总线上的事件发送到各通知后端
+func (ts *TradingSystem) startNotifications(bus *engine.EventBus) (*notify.Router, error) {
+	router, err := notify.ConfigValue.NewRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	if ts.Testnet() {
+		router.SetTitlePrefix("[TESTNET]")
+	}
+	router.SetTraceID(ts.traceID)
+	router.Subscribe(bus)
+	router.Start(ts.ctx)
+
+	notifiers, _ := notify.ConfigValue.Notifiers()
+	names := make([]string, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		names = append(names, notifier.Name())
+	}
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 通知: notifiers=%s, routes=%d", strings.Join(names, "|"), len(notify.ConfigValue.Routes)))
+	return router, nil
+}
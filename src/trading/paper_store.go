@@ -0,0 +1,111 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultPaperCapital 新建模拟盘会话的默认初始资金
+const defaultPaperCapital = 10000.0
+
+// PaperSessionDB 模拟盘会话存储（由 database.PostgresDB 实现）
+type PaperSessionDB interface {
+	// SavePaperSession 保存会话状态（JSON），已存在时覆盖
+	SavePaperSession(ctx context.Context, sessionID, symbol string, state []byte) error
+
+	// GetPaperSession 获取会话状态（JSON），不存在时返回 nil
+	GetPaperSession(ctx context.Context, sessionID string) ([]byte, error)
+}
+
+// paperStateStore 将模拟盘状态以 JSON 保存到数据库
+type paperStateStore struct {
+	db PaperSessionDB
+}
+
+// NewPaperStateStore 创建基于数据库的模拟盘状态存储
+func NewPaperStateStore(db PaperSessionDB) executor.PaperStateStore {
+	return &paperStateStore{db: db}
+}
+
+// LoadPaperState 读取会话状态，不存在时返回 nil
+func (s *paperStateStore) LoadPaperState(ctx context.Context, sessionID string) (*executor.PaperState, error) {
+	data, err := s.db.GetPaperSession(ctx, sessionID)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var state executor.PaperState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode paper session %s: %w", sessionID, err)
+	}
+	return &state, nil
+}
+
+// SavePaperState 保存会话状态
+func (s *paperStateStore) SavePaperState(ctx context.Context, state *executor.PaperState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode paper session %s: %w", state.SessionID, err)
+	}
+	return s.db.SavePaperSession(ctx, state.SessionID, state.Symbol, data)
+}
+
+// DefaultPaperSessionID 默认模拟盘会话名（同一交易所、交易对的 Dry Run 共用一个会话）
+func DefaultPaperSessionID(cexName string, pair cex.TradingPair) string {
+	return fmt.Sprintf("paper_%s_%s", strings.ToLower(cexName), DatabaseSymbol(pair))
+}
+
+// SetPaperSession 设置 Dry Run 模拟盘会话：同名会话已保存时恢复其账户，否则以 initialCapital 新建
+func (ts *TradingSystem) SetPaperSession(sessionID string, initialCapital float64) {
+	ts.paperSession = sessionID
+	ts.paperCapital = initialCapital
+}
+
+// paperSessionID 当前 Dry Run 的模拟盘会话名
+func (ts *TradingSystem) paperSessionID(pair cex.TradingPair) string {
+	if ts.paperSession == "" {
+		return DefaultPaperSessionID(ts.cexName, pair)
+	}
+	return ts.paperSession
+}
+
+// newPaperExecutor 创建模拟盘执行器，数据库可用时持久化会话状态
+func (ts *TradingSystem) newPaperExecutor(pair cex.TradingPair) (*executor.PaperExecutor, error) {
+	sessionID := ts.paperSessionID(pair)
+	capital := ts.paperCapital
+	if capital <= 0 {
+		capital = defaultPaperCapital
+	}
+
+	var store executor.PaperStateStore
+	if db, err := GetPostgresDB(ts.cexClient); err == nil {
+		store = NewPaperStateStore(db)
+	} else {
+		fmt.Printf("⚠️ %v, paper session will not survive restarts\n", err)
+	}
+
+	paperExecutor, err := executor.NewPaperExecutor(ts.ctx, ts.cexClient, pair, sessionID, decimal.NewFromFloat(capital), store)
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.applyFeeSchedule(paperExecutor); err != nil {
+		return nil, err
+	}
+
+	state := paperExecutor.State()
+	if paperExecutor.IsResumed() {
+		fmt.Printf("♻️ Resumed paper session %s: cash %s %s, position %s %s, %d fills since %s\n",
+			sessionID, state.Cash.StringFixed(2), pair.Quote, state.Position.String(), pair.Base,
+			len(state.Orders), state.CreatedAt.Format("2006-01-02 15:04"))
+	} else {
+		fmt.Printf("📝 New paper session %s: %s %s\n", sessionID, state.Cash.StringFixed(2), pair.Quote)
+	}
+	return paperExecutor, nil
+}
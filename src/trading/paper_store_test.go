@@ -0,0 +1,68 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPaperSessionDB 内存模拟盘会话表
+type mockPaperSessionDB struct {
+	sessions map[string][]byte
+	symbols  map[string]string
+}
+
+func (m *mockPaperSessionDB) SavePaperSession(ctx context.Context, sessionID, symbol string, state []byte) error {
+	m.sessions[sessionID] = state
+	m.symbols[sessionID] = symbol
+	return nil
+}
+
+func (m *mockPaperSessionDB) GetPaperSession(ctx context.Context, sessionID string) ([]byte, error) {
+	return m.sessions[sessionID], nil
+}
+
+func TestPaperStateStore_RoundTrip(t *testing.T) {
+	db := &mockPaperSessionDB{sessions: make(map[string][]byte), symbols: make(map[string]string)}
+	store := NewPaperStateStore(db)
+	ctx := context.Background()
+
+	missing, err := store.LoadPaperState(ctx, "paper_binance_BTCUSDT")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	state := &executor.PaperState{
+		SessionID: "paper_binance_BTCUSDT",
+		Symbol:    "BTC/USDT",
+		Cash:      decimal.RequireFromString("899.5"),
+		Position:  decimal.NewFromInt(1),
+		Orders: []executor.OrderResult{{
+			OrderID:   "paper_1",
+			Side:      executor.OrderSideBuy,
+			Quantity:  decimal.NewFromInt(1),
+			Price:     decimal.NewFromInt(100),
+			Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Success:   true,
+		}},
+	}
+	require.NoError(t, store.SavePaperState(ctx, state))
+	assert.Equal(t, "BTC/USDT", db.symbols[state.SessionID])
+
+	loaded, err := store.LoadPaperState(ctx, state.SessionID)
+	require.NoError(t, err)
+	assert.True(t, state.Cash.Equal(loaded.Cash))
+	require.Len(t, loaded.Orders, 1)
+	assert.Equal(t, "paper_1", loaded.Orders[0].OrderID)
+	assert.True(t, state.Orders[0].Timestamp.Equal(loaded.Orders[0].Timestamp))
+}
+
+func TestDefaultPaperSessionID(t *testing.T) {
+	assert.Equal(t, "paper_bybit_PEPEUSDT", DefaultPaperSessionID("Bybit", cex.TradingPair{Base: "pepe", Quote: "usdt"}))
+}
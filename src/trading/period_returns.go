@@ -0,0 +1,117 @@
+package trading
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/i18n"
+
+	"github.com/shopspring/decimal"
+)
+
+// PeriodReturn 单个月份或年份的收益
+type PeriodReturn struct {
+	Period     string          `json:"period"`      // 月份（如 2024-01）或年份（如 2024），UTC
+	StartValue decimal.Decimal `json:"start_value"` // 期初组合价值（上一期末或初始资金）
+	EndValue   decimal.Decimal `json:"end_value"`   // 期末组合价值
+	Return     decimal.Decimal `json:"return"`      // 收益率（如 0.032 表示 3.2%）
+}
+
+// PeriodReturns 按月、按年的收益分解
+type PeriodReturns struct {
+	Monthly        []PeriodReturn `json:"monthly"`
+	Yearly         []PeriodReturn `json:"yearly"`
+	BestMonth      *PeriodReturn  `json:"best_month,omitempty"`
+	WorstMonth     *PeriodReturn  `json:"worst_month,omitempty"`
+	PositiveMonths int            `json:"positive_months"` // 收益为正的月数
+}
+
+// CalculatePeriodReturns 由资金曲线计算每月、每年的收益率
+// 每期以上一期最后一个点的组合价值为期初（第一期为初始资金）；收盘时间恰为月初零点的K线计入上月
+func CalculatePeriodReturns(curve []engine.EquityPoint, initialCapital decimal.Decimal) PeriodReturns {
+	returns := PeriodReturns{
+		Monthly: periodReturns(curve, initialCapital, "2006-01"),
+		Yearly:  periodReturns(curve, initialCapital, "2006"),
+	}
+
+	for i := range returns.Monthly {
+		month := &returns.Monthly[i]
+		if month.Return.IsPositive() {
+			returns.PositiveMonths++
+		}
+		if returns.BestMonth == nil || month.Return.GreaterThan(returns.BestMonth.Return) {
+			returns.BestMonth = month
+		}
+		if returns.WorstMonth == nil || month.Return.LessThan(returns.WorstMonth.Return) {
+			returns.WorstMonth = month
+		}
+	}
+	return returns
+}
+
+// periodReturns 按时间格式（月或年）分期汇总收益
+func periodReturns(curve []engine.EquityPoint, initialCapital decimal.Decimal, layout string) []PeriodReturn {
+	periods := []PeriodReturn{}
+	startValue := initialCapital
+	for i, point := range curve {
+		period := periodKey(point.Timestamp, layout)
+		if i < len(curve)-1 && periodKey(curve[i+1].Timestamp, layout) == period {
+			continue
+		}
+
+		result := PeriodReturn{Period: period, StartValue: startValue, EndValue: point.PortfolioValue}
+		if startValue.IsPositive() {
+			result.Return = point.PortfolioValue.Div(startValue).Sub(decimal.NewFromInt(1))
+		}
+		periods = append(periods, result)
+		startValue = point.PortfolioValue
+	}
+	return periods
+}
+
+// periodKey 资金曲线点所属的月份或年份（UTC）
+func periodKey(t time.Time, layout string) string {
+	return t.Add(-time.Nanosecond).UTC().Format(layout)
+}
+
+// printPeriodReturns 打印按年排列的月度收益表和最好、最差月份
+func printPeriodReturns(returns PeriodReturns) {
+	if len(returns.Monthly) == 0 {
+		return
+	}
+
+	percent := func(d decimal.Decimal) string {
+		return fmt.Sprintf("%+.2f", d.Mul(decimal.NewFromInt(100)).InexactFloat64())
+	}
+
+	fmt.Println("\n" + i18n.T("report.monthly_returns"))
+	fmt.Println(strings.Repeat("-", 114))
+	header := padRight(i18n.T("report.year"), 6)
+	for month := time.January; month <= time.December; month++ {
+		header += fmt.Sprintf("%8s", month.String()[:3])
+	}
+	fmt.Println(header + padLeft(i18n.T("report.year"), 12))
+	fmt.Println(strings.Repeat("-", 114))
+
+	monthly := make(map[string]PeriodReturn, len(returns.Monthly))
+	for _, month := range returns.Monthly {
+		monthly[month.Period] = month
+	}
+	for _, year := range returns.Yearly {
+		row := fmt.Sprintf("%-6s", year.Period)
+		for month := 1; month <= 12; month++ {
+			cell := ""
+			if result, ok := monthly[fmt.Sprintf("%s-%02d", year.Period, month)]; ok {
+				cell = percent(result.Return)
+			}
+			row += fmt.Sprintf("%8s", cell)
+		}
+		fmt.Println(row + fmt.Sprintf("%12s", percent(year.Return)))
+	}
+
+	fmt.Println(i18n.T("report.best_month", returns.BestMonth.Period, percent(returns.BestMonth.Return)))
+	fmt.Println(i18n.T("report.worst_month", returns.WorstMonth.Period, percent(returns.WorstMonth.Return)))
+	fmt.Println(i18n.T("report.positive_months", returns.PositiveMonths, len(returns.Monthly)))
+}
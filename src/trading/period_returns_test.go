@@ -0,0 +1,49 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/engine"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatePeriodReturns(t *testing.T) {
+	point := func(t time.Time, value int64) engine.EquityPoint {
+		return engine.EquityPoint{Timestamp: t, PortfolioValue: decimal.NewFromInt(value)}
+	}
+	curve := []engine.EquityPoint{
+		point(time.Date(2023, 12, 10, 0, 0, 0, 0, time.UTC), 1010),
+		// 收盘时间恰为 1 月 1 日零点的K线计入 12 月
+		point(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1100),
+		point(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), 1050),
+		point(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 990),
+		point(time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC), 1089),
+	}
+
+	returns := CalculatePeriodReturns(curve, decimal.NewFromInt(1000))
+
+	require.Len(t, returns.Monthly, 3)
+	assert.Equal(t, []string{"2023-12", "2024-01", "2024-02"}, []string{returns.Monthly[0].Period, returns.Monthly[1].Period, returns.Monthly[2].Period})
+	assert.Equal(t, "0.1", returns.Monthly[0].Return.String())
+	assert.Equal(t, "-0.1", returns.Monthly[1].Return.String())
+	assert.True(t, returns.Monthly[1].StartValue.Equal(decimal.NewFromInt(1100)))
+	assert.Equal(t, "0.1", returns.Monthly[2].Return.String())
+
+	require.Len(t, returns.Yearly, 2)
+	assert.Equal(t, "0.1", returns.Yearly[0].Return.String())
+	assert.Equal(t, "2024", returns.Yearly[1].Period)
+	assert.Equal(t, "-0.01", returns.Yearly[1].Return.String())
+
+	// 收益相同时取第一个
+	assert.Equal(t, "2023-12", returns.BestMonth.Period)
+	assert.Equal(t, "2024-01", returns.WorstMonth.Period)
+	assert.Equal(t, 2, returns.PositiveMonths)
+
+	empty := CalculatePeriodReturns(nil, decimal.NewFromInt(1000))
+	assert.Empty(t, empty.Monthly)
+	assert.Nil(t, empty.BestMonth)
+}
@@ -0,0 +1,113 @@
+package trading
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"tradingbot/src/database"
+	"tradingbot/src/i18n"
+
+	"github.com/shopspring/decimal"
+)
+
+// PnLPeriod 盈亏报表的统计周期
+type PnLPeriod string
+
+const (
+	PnLPeriodDaily  PnLPeriod = "daily"  // 按 UTC 自然日
+	PnLPeriodWeekly PnLPeriod = "weekly" // 按 UTC 自然周（周一开始）
+)
+
+// ParsePnLPeriod 解析统计周期，空字符串为 daily
+func ParsePnLPeriod(s string) (PnLPeriod, error) {
+	switch PnLPeriod(s) {
+	case "", PnLPeriodDaily:
+		return PnLPeriodDaily, nil
+	case PnLPeriodWeekly:
+		return PnLPeriodWeekly, nil
+	}
+	return "", fmt.Errorf("unknown period %q (supported: daily, weekly)", s)
+}
+
+// start 时间所在周期的起点
+func (p PnLPeriod) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if p == PnLPeriodWeekly {
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为 0
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// PnLRow 一个周期的盈亏：已实现和未实现盈亏为周期内的变化量，权益为周期末的值
+type PnLRow struct {
+	Start      time.Time
+	Equity     decimal.Decimal
+	Realized   decimal.Decimal
+	Unrealized decimal.Decimal
+	Total      decimal.Decimal // 已实现 + 未实现
+	Return     decimal.Decimal // 权益相对周期初的变化比例
+}
+
+// SummarizePnL 按周期汇总快照（按时间升序）：周期初取上一周期最后一条快照，第一个周期取本周期第一条快照
+func SummarizePnL(snapshots []*database.EquitySnapshotRecord, period PnLPeriod) []PnLRow {
+	var rows []PnLRow
+	var open, last *database.EquitySnapshotRecord
+	flush := func() {
+		row := PnLRow{
+			Start:      period.start(last.Time),
+			Equity:     last.Equity,
+			Realized:   last.RealizedPnL.Sub(open.RealizedPnL),
+			Unrealized: last.UnrealizedPnL.Sub(open.UnrealizedPnL),
+		}
+		row.Total = row.Realized.Add(row.Unrealized)
+		if open.Equity.IsPositive() {
+			row.Return = last.Equity.Sub(open.Equity).Div(open.Equity)
+		}
+		rows = append(rows, row)
+	}
+
+	for _, snapshot := range snapshots {
+		if last != nil && !period.start(snapshot.Time).Equal(period.start(last.Time)) {
+			flush()
+			open = last
+		}
+		if open == nil {
+			open = snapshot
+		}
+		last = snapshot
+	}
+	if last != nil {
+		flush()
+	}
+	return rows
+}
+
+// WritePnLReport 输出盈亏报表（金额单位为计价币种）
+func WritePnLReport(w io.Writer, rows []PnLRow) {
+	total := decimal.Zero
+	table := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		total = total.Add(row.Total)
+		table = append(table, []string{
+			row.Start.Format("2006-01-02"),
+			row.Equity.StringFixed(2),
+			row.Realized.StringFixed(2),
+			row.Unrealized.StringFixed(2),
+			row.Total.StringFixed(2),
+			row.Return.Mul(decimal.NewFromInt(100)).StringFixed(2),
+		})
+	}
+
+	WriteTable(w, []TableColumn{
+		{Header: i18n.T("pnl.col.period")},
+		{Header: i18n.T("pnl.col.equity"), Right: true},
+		{Header: i18n.T("pnl.col.realized"), Right: true},
+		{Header: i18n.T("pnl.col.unrealized"), Right: true},
+		{Header: i18n.T("pnl.col.total"), Right: true},
+		{Header: i18n.T("pnl.col.return"), Right: true},
+	}, table)
+	fmt.Fprintln(w, i18n.T("pnl.total", total.StringFixed(2)))
+}
@@ -0,0 +1,70 @@
+package trading
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"tradingbot/src/database"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// equitySnapshot 构造快照
+func equitySnapshot(at string, equity, realized, unrealized int64) *database.EquitySnapshotRecord {
+	t, _ := time.Parse("2006-01-02 15:04", at)
+	return &database.EquitySnapshotRecord{
+		Time:          t,
+		Equity:        decimal.NewFromInt(equity),
+		RealizedPnL:   decimal.NewFromInt(realized),
+		UnrealizedPnL: decimal.NewFromInt(unrealized),
+	}
+}
+
+func TestSummarizePnL(t *testing.T) {
+	snapshots := []*database.EquitySnapshotRecord{
+		equitySnapshot("2024-01-01 00:00", 1000, 0, 0),
+		equitySnapshot("2024-01-01 12:00", 1010, 0, 10),
+		equitySnapshot("2024-01-02 06:00", 1030, 20, 10),
+		equitySnapshot("2024-01-02 18:00", 1020, 20, 0),
+	}
+
+	daily := SummarizePnL(snapshots, PnLPeriodDaily)
+	require.Len(t, daily, 2)
+	assert.Equal(t, "2024-01-01", daily[0].Start.Format("2006-01-02"))
+	assert.Equal(t, "10", daily[0].Total.String())
+	assert.Equal(t, "0.01", daily[0].Return.String())
+	// 第二天从前一天最后一条快照算起
+	assert.Equal(t, "20", daily[1].Realized.String())
+	assert.Equal(t, "-10", daily[1].Unrealized.String())
+	assert.Equal(t, "10", daily[1].Total.String())
+	assert.Equal(t, "1020", daily[1].Equity.String())
+
+	weekly := SummarizePnL(snapshots, PnLPeriodWeekly)
+	require.Len(t, weekly, 1)
+	assert.Equal(t, "20", weekly[0].Total.String())
+	assert.Equal(t, "0.02", weekly[0].Return.String())
+
+	assert.Empty(t, SummarizePnL(nil, PnLPeriodDaily))
+}
+
+func TestPnLPeriod_Start(t *testing.T) {
+	sunday := time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2024-01-01", PnLPeriodWeekly.start(sunday).Format("2006-01-02"))
+	assert.Equal(t, "2024-01-07", PnLPeriodDaily.start(sunday).Format("2006-01-02"))
+
+	_, err := ParsePnLPeriod("monthly")
+	assert.Error(t, err)
+}
+
+func TestWritePnLReport(t *testing.T) {
+	var buf bytes.Buffer
+	WritePnLReport(&buf, SummarizePnL([]*database.EquitySnapshotRecord{
+		equitySnapshot("2024-01-01 00:00", 1000, 0, 0),
+		equitySnapshot("2024-01-01 12:00", 1010, 0, 10),
+	}, PnLPeriodDaily))
+	assert.Contains(t, buf.String(), "2024-01-01")
+	assert.Contains(t, buf.String(), "10.00")
+}
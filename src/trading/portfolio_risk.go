@@ -0,0 +1,188 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/timeframes"
+)
+
+// DefaultMaxCorrelation 默认的高相关阈值，相关系数不低于该值的两个交易对视为近似重复
+const DefaultMaxCorrelation = 0.9
+
+// PortfolioSeries 组合中一个交易对的回测资金曲线
+type PortfolioSeries struct {
+	Symbol string
+	Equity []engine.EquityPoint
+}
+
+// CorrelatedPair 高度相关的两个交易对，First 在输入中排在前面
+type CorrelatedPair struct {
+	First       string  `json:"first"`
+	Second      string  `json:"second"`
+	Correlation float64 `json:"correlation"`
+}
+
+// PortfolioRisk 多交易对等权组合的风险指标，基于共同K线时间点上逐K线策略收益率（组合价值变化率）
+type PortfolioRisk struct {
+	Symbols              []string         `json:"symbols"`
+	Observations         int              `json:"observations"`          // 收益率样本数
+	Correlation          [][]float64      `json:"correlation"`           // 相关系数矩阵（方差为0的交易对与其他交易对记为0）
+	Covariance           [][]float64      `json:"covariance"`            // 年化协方差矩阵
+	Volatility           []float64        `json:"volatility"`            // 各交易对的年化波动率
+	PortfolioVolatility  float64          `json:"portfolio_volatility"`  // 等权组合的年化波动率
+	DiversificationRatio float64          `json:"diversification_ratio"` // 加权平均波动率 / 组合波动率，1 表示没有分散效果
+	Correlated           []CorrelatedPair `json:"correlated"`            // 相关系数不低于阈值的交易对，按相关系数从高到低
+	Selected             []string         `json:"selected"`              // 按输入顺序依次选取、跳过与已选交易对高度相关的交易对
+}
+
+// CalculatePortfolioRisk 计算等权组合的相关性、协方差、组合波动率和分散化比率
+// series 按优先级排序（如批量回测排名），maxCorrelation<=0 时使用 DefaultMaxCorrelation
+func CalculatePortfolioRisk(series []PortfolioSeries, timeframe timeframes.Timeframe, maxCorrelation float64) (*PortfolioRisk, error) {
+	if len(series) < 2 {
+		return nil, fmt.Errorf("portfolio risk needs at least 2 symbols, got %d", len(series))
+	}
+	if maxCorrelation <= 0 {
+		maxCorrelation = DefaultMaxCorrelation
+	}
+	duration, err := timeframe.GetDuration()
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("invalid timeframe %q for portfolio risk", timeframe)
+	}
+	periodsPerYear := float64(365*24*time.Hour) / float64(duration)
+
+	returns := alignedReturns(series)
+	observations := len(returns[0])
+	if observations < 2 {
+		return nil, fmt.Errorf("portfolio risk needs at least 3 bars common to all symbols")
+	}
+
+	n := len(series)
+	risk := &PortfolioRisk{
+		Symbols:      make([]string, n),
+		Observations: observations,
+		Correlation:  make([][]float64, n),
+		Covariance:   make([][]float64, n),
+		Volatility:   make([]float64, n),
+		Correlated:   []CorrelatedPair{},
+	}
+
+	means := make([]float64, n)
+	for i := range series {
+		risk.Symbols[i] = series[i].Symbol
+		for _, r := range returns[i] {
+			means[i] += r
+		}
+		means[i] /= float64(observations)
+	}
+
+	// 样本协方差，按K线周期年化
+	for i := 0; i < n; i++ {
+		risk.Covariance[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := 0.0
+			for k := 0; k < observations; k++ {
+				sum += (returns[i][k] - means[i]) * (returns[j][k] - means[j])
+			}
+			covariance := sum / float64(observations-1) * periodsPerYear
+			risk.Covariance[i][j] = covariance
+			risk.Covariance[j][i] = covariance
+		}
+		risk.Volatility[i] = math.Sqrt(risk.Covariance[i][i])
+	}
+
+	for i := 0; i < n; i++ {
+		risk.Correlation[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			switch {
+			case i == j:
+				risk.Correlation[i][j] = 1
+			case risk.Volatility[i] > 0 && risk.Volatility[j] > 0:
+				risk.Correlation[i][j] = risk.Covariance[i][j] / (risk.Volatility[i] * risk.Volatility[j])
+			}
+		}
+	}
+
+	// 等权组合：σp² = Σ wi·wj·cov(i,j)，分散化比率 = Σ wi·σi / σp
+	weight := 1 / float64(n)
+	variance, weightedVolatility := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		weightedVolatility += weight * risk.Volatility[i]
+		for j := 0; j < n; j++ {
+			variance += weight * weight * risk.Covariance[i][j]
+		}
+	}
+	risk.PortfolioVolatility = math.Sqrt(math.Max(variance, 0))
+	if risk.PortfolioVolatility > 0 {
+		risk.DiversificationRatio = weightedVolatility / risk.PortfolioVolatility
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if risk.Correlation[i][j] >= maxCorrelation {
+				risk.Correlated = append(risk.Correlated, CorrelatedPair{First: risk.Symbols[i], Second: risk.Symbols[j], Correlation: risk.Correlation[i][j]})
+			}
+		}
+	}
+	sort.SliceStable(risk.Correlated, func(a, b int) bool {
+		return risk.Correlated[a].Correlation > risk.Correlated[b].Correlation
+	})
+
+	var selected []int
+	for i := 0; i < n; i++ {
+		redundant := false
+		for _, j := range selected {
+			if risk.Correlation[i][j] >= maxCorrelation {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			selected = append(selected, i)
+			risk.Selected = append(risk.Selected, risk.Symbols[i])
+		}
+	}
+
+	return risk, nil
+}
+
+// alignedReturns 只保留所有资金曲线都有的时间点（按毫秒时间戳对齐），返回每条曲线在相邻共同时间点间的收益率
+func alignedReturns(series []PortfolioSeries) [][]float64 {
+	counts := make(map[int64]int)
+	for _, s := range series {
+		seen := make(map[int64]bool)
+		for _, point := range s.Equity {
+			if key := point.Timestamp.UnixMilli(); !seen[key] {
+				seen[key] = true
+				counts[key]++
+			}
+		}
+	}
+	var common []int64
+	for key, count := range counts {
+		if count == len(series) {
+			common = append(common, key)
+		}
+	}
+	sort.Slice(common, func(i, j int) bool { return common[i] < common[j] })
+
+	returns := make([][]float64, len(series))
+	for i, s := range series {
+		values := make(map[int64]float64, len(s.Equity))
+		for _, point := range s.Equity {
+			values[point.Timestamp.UnixMilli()] = point.PortfolioValue.InexactFloat64()
+		}
+		returns[i] = make([]float64, 0, len(common))
+		for k := 1; k < len(common); k++ {
+			r := 0.0
+			if prev := values[common[k-1]]; prev > 0 {
+				r = values[common[k]]/prev - 1
+			}
+			returns[i] = append(returns[i], r)
+		}
+	}
+	return returns
+}
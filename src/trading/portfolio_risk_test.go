@@ -0,0 +1,100 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"tradingbot/src/engine"
+	"tradingbot/src/timeframes"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// portfolioTestSeries 由逐K线收益率生成资金曲线（从 1000 开始，每小时一个点），offset 为起始K线序号
+func portfolioTestSeries(symbol string, offset int, returns ...float64) PortfolioSeries {
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	value := 1000.0
+	equity := []engine.EquityPoint{{Timestamp: baseTime.Add(time.Duration(offset) * time.Hour), PortfolioValue: decimal.NewFromFloat(value)}}
+	for i, r := range returns {
+		value *= 1 + r
+		equity = append(equity, engine.EquityPoint{
+			Timestamp:      baseTime.Add(time.Duration(offset+i+1) * time.Hour),
+			PortfolioValue: decimal.NewFromFloat(value),
+		})
+	}
+	return PortfolioSeries{Symbol: symbol, Equity: equity}
+}
+
+func TestCalculatePortfolioRisk(t *testing.T) {
+	returns := []float64{0.01, -0.02, 0.015, 0.005, -0.01, 0.02}
+	inverse := make([]float64, len(returns))
+	for i, r := range returns {
+		inverse[i] = -r
+	}
+
+	// BTC/FDUSD 与 BTC/USDT 收益相同，ETH/USDT 完全相反
+	risk, err := CalculatePortfolioRisk([]PortfolioSeries{
+		portfolioTestSeries("BTC/USDT", 0, returns...),
+		portfolioTestSeries("ETH/USDT", 0, inverse...),
+		portfolioTestSeries("BTC/FDUSD", 0, returns...),
+	}, timeframes.Timeframe1h, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT", "BTC/FDUSD"}, risk.Symbols)
+	assert.Equal(t, 6, risk.Observations)
+	assert.InDelta(t, 1.0, risk.Correlation[0][2], 1e-9)
+	assert.InDelta(t, -1.0, risk.Correlation[0][1], 1e-9)
+	assert.InDelta(t, risk.Correlation[0][1], risk.Correlation[1][0], 1e-12)
+	assert.InDelta(t, risk.Volatility[0], risk.Volatility[1], 1e-9)
+
+	// 年化波动率 = 样本标准差 × sqrt(8760)
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	sum := 0.0
+	for _, r := range returns {
+		sum += (r - mean) * (r - mean)
+	}
+	volatility := math.Sqrt(sum / float64(len(returns)-1) * 8760)
+	assert.InDelta(t, volatility, risk.Volatility[0], 1e-9)
+	assert.InDelta(t, volatility*volatility, risk.Covariance[0][0], 1e-9)
+
+	// 等权组合中 ETH 抵消一份 BTC：σp = σ/3，分散化比率 = σ / (σ/3) = 3
+	assert.InDelta(t, volatility/3, risk.PortfolioVolatility, 1e-9)
+	assert.InDelta(t, 3.0, risk.DiversificationRatio, 1e-9)
+
+	assert.Equal(t, []CorrelatedPair{{First: "BTC/USDT", Second: "BTC/FDUSD", Correlation: risk.Correlation[0][2]}}, risk.Correlated)
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT"}, risk.Selected)
+}
+
+func TestCalculatePortfolioRisk_AlignsTimestamps(t *testing.T) {
+	// SOL 晚一根K线开始，只使用共同的时间点；没有成交的交易对方差为0，相关系数记为0
+	risk, err := CalculatePortfolioRisk([]PortfolioSeries{
+		portfolioTestSeries("BTC/USDT", 0, 0.01, 0.02, -0.01, 0.03),
+		portfolioTestSeries("SOL/USDT", 1, 0, 0, 0),
+	}, timeframes.Timeframe1h, 0.5)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, risk.Observations)
+	assert.Zero(t, risk.Volatility[1])
+	assert.Zero(t, risk.Correlation[0][1])
+	assert.Empty(t, risk.Correlated)
+	assert.Equal(t, []string{"BTC/USDT", "SOL/USDT"}, risk.Selected)
+}
+
+func TestCalculatePortfolioRisk_Errors(t *testing.T) {
+	_, err := CalculatePortfolioRisk([]PortfolioSeries{portfolioTestSeries("BTC/USDT", 0, 0.01, 0.02)}, timeframes.Timeframe1h, 0)
+	assert.Error(t, err)
+
+	// 没有重叠的时间点
+	_, err = CalculatePortfolioRisk([]PortfolioSeries{
+		portfolioTestSeries("BTC/USDT", 0, 0.01, 0.02),
+		portfolioTestSeries("ETH/USDT", 10, 0.01, 0.02),
+	}, timeframes.Timeframe1h, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// 持仓批次匹配方式（卖出时平掉哪些批次）
+const (
+	LotMatchingFIFO    = "fifo"    // 先进先出：平掉最早的批次
+	LotMatchingLIFO    = "lifo"    // 后进先出：平掉最近的批次
+	LotMatchingAverage = "average" // 平均成本：加仓合并为一个批次，成本和开仓时间按数量加权
+)
+
+// SupportedLotMatchings 支持的持仓批次匹配方式
+func SupportedLotMatchings() []string {
+	return []string{LotMatchingFIFO, LotMatchingLIFO, LotMatchingAverage}
+}
+
+// ValidateLotMatching 检查批次匹配方式，空为 fifo
+func ValidateLotMatching(method string) error {
+	switch method {
+	case "", LotMatchingFIFO, LotMatchingLIFO, LotMatchingAverage:
+		return nil
+	}
+	return fmt.Errorf("unknown lot matching method %q (supported: %v)", method, SupportedLotMatchings())
+}
+
+// positionLot 一笔买入形成的持仓批次，部分卖出后剩余数量和手续费按比例减少
+type positionLot struct {
+	order      executor.OrderResult
+	remaining  decimal.Decimal // 未平仓数量
+	commission decimal.Decimal // 未平仓数量对应的买入手续费
+}
+
+// PositionLedger 按批次跟踪持仓，卖出按匹配方式（FIFO/LIFO/平均成本）平仓，支持部分平仓
+// 一次卖出可能平掉多个批次，每个批次（或其一部分）形成一笔已完成交易
+type PositionLedger struct {
+	method   string
+	lots     []positionLot   // 未平仓批次为 lots[head:]
+	head     int             // 第一个未平仓批次，FIFO 平仓后不再移动切片
+	position decimal.Decimal // 未平仓总数量
+}
+
+// NewPositionLedger 创建持仓账本，method 为空或未知时按 FIFO
+func NewPositionLedger(method string) *PositionLedger {
+	return &PositionLedger{method: method, position: decimal.Zero}
+}
+
+// Position 当前未平仓总数量
+func (l *PositionLedger) Position() decimal.Decimal {
+	return l.position
+}
+
+// Apply 记录一笔成交订单，卖出时返回平掉的交易
+func (l *PositionLedger) Apply(order executor.OrderResult) []TradeAnalysis {
+	switch order.Side {
+	case executor.OrderSideBuy:
+		l.Buy(order)
+	case executor.OrderSideSell:
+		return l.Sell(order)
+	}
+	return nil
+}
+
+// Buy 新增一个持仓批次（平均成本方式下并入当前批次）
+func (l *PositionLedger) Buy(order executor.OrderResult) {
+	if !order.Quantity.IsPositive() {
+		return
+	}
+	l.position = l.position.Add(order.Quantity)
+
+	if l.method == LotMatchingAverage && l.head < len(l.lots) {
+		l.lots[l.head].merge(order)
+		return
+	}
+	l.lots = append(l.lots, positionLot{order: order, remaining: order.Quantity, commission: order.Commission})
+}
+
+// merge 加仓并入批次：成本价和开仓时间按数量加权，手续费累加
+func (lot *positionLot) merge(order executor.OrderResult) {
+	total := lot.remaining.Add(order.Quantity)
+	weight := order.Quantity.Div(total).InexactFloat64()
+
+	lot.order.Price = lot.order.Price.Mul(lot.remaining).Add(order.Price.Mul(order.Quantity)).Div(total)
+	lot.order.Timestamp = lot.order.Timestamp.Add(time.Duration(float64(order.Timestamp.Sub(lot.order.Timestamp)) * weight))
+	lot.order.Quantity = total
+	lot.order.Commission = lot.order.Commission.Add(order.Commission)
+	lot.remaining = total
+	lot.commission = lot.commission.Add(order.Commission)
+}
+
+// nextLot 下一个要平仓的批次：LIFO 取最近的批次，其他取最早的批次
+func (l *PositionLedger) nextLot() *positionLot {
+	if l.method == LotMatchingLIFO {
+		return &l.lots[len(l.lots)-1]
+	}
+	return &l.lots[l.head]
+}
+
+// closeLot 移除已全部平仓的批次
+func (l *PositionLedger) closeLot() {
+	if l.method == LotMatchingLIFO {
+		l.lots = l.lots[:len(l.lots)-1]
+		return
+	}
+	l.head++
+}
+
+// Sell 按匹配方式平仓，返回每个被平掉批次的交易；超出持仓的卖出数量忽略
+// 买入手续费按平仓数量占批次剩余数量的比例分摊，卖出手续费按各批次平仓数量占卖出数量的比例分摊
+func (l *PositionLedger) Sell(order executor.OrderResult) []TradeAnalysis {
+	if !order.Quantity.IsPositive() {
+		return nil
+	}
+
+	var trades []TradeAnalysis
+	sellOrder := order
+	left := order.Quantity
+	for left.IsPositive() && l.head < len(l.lots) {
+		lot := l.nextLot()
+		quantity := decimal.Min(left, lot.remaining)
+
+		buyCommission := lot.commission
+		if quantity.LessThan(lot.remaining) {
+			buyCommission = lot.commission.Mul(quantity).Div(lot.remaining)
+		}
+		sellCommission := order.Commission
+		if quantity.LessThan(order.Quantity) {
+			sellCommission = order.Commission.Mul(quantity).Div(order.Quantity)
+		}
+
+		cost := lot.order.Price.Mul(quantity)
+		pnl := order.Price.Mul(quantity).Sub(cost).Sub(buyCommission).Sub(sellCommission)
+		pnlPercent := decimal.Zero
+		if cost.IsPositive() {
+			pnlPercent = pnl.Div(cost).Mul(decimal.NewFromInt(100))
+		}
+
+		trades = append(trades, TradeAnalysis{
+			BuyOrder:   lot.order,
+			SellOrder:  &sellOrder,
+			Quantity:   quantity,
+			Duration:   order.Timestamp.Sub(lot.order.Timestamp),
+			PnL:        pnl,
+			PnLPercent: pnlPercent,
+			Commission: buyCommission.Add(sellCommission),
+			BuyReason:  orderReason(lot.order),
+			SellReason: orderReason(order),
+		})
+
+		lot.remaining = lot.remaining.Sub(quantity)
+		lot.commission = lot.commission.Sub(buyCommission)
+		l.position = l.position.Sub(quantity)
+		left = left.Sub(quantity)
+		if !lot.remaining.IsPositive() {
+			l.closeLot()
+		}
+	}
+	return trades
+}
+
+// OpenPositions 未平仓批次（数量为剩余数量）
+func (l *PositionLedger) OpenPositions() []TradeAnalysis {
+	var positions []TradeAnalysis
+	for _, lot := range l.lots[l.head:] {
+		positions = append(positions, TradeAnalysis{
+			BuyOrder:   lot.order,
+			Quantity:   lot.remaining,
+			Commission: lot.commission,
+			IsOpen:     true,
+			BuyReason:  orderReason(lot.order),
+		})
+	}
+	return positions
+}
+
+// defaultTradeReason 成交没有记录挂单原因时（如旧的回测结果文件）使用的原因
+const defaultTradeReason = "strategy signal"
+
+// orderReason 触发成交的挂单原因（策略信号原因或保护单原因）
+func orderReason(order executor.OrderResult) string {
+	if order.Reason != "" {
+		return order.Reason
+	}
+	if order.Signal != nil && order.Signal.Reason != "" {
+		return order.Signal.Reason
+	}
+	return defaultTradeReason
+}
+
+// sortOrdersByTime 按成交时间排序订单副本（时间相同时保持原顺序）
+func sortOrdersByTime(orders []executor.OrderResult) []executor.OrderResult {
+	sorted := make([]executor.OrderResult, len(orders))
+	copy(sorted, orders)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	return sorted
+}
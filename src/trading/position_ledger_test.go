@@ -0,0 +1,175 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ledgerOrder(side executor.OrderSide, price, quantity, commission float64, at time.Time) executor.OrderResult {
+	return executor.OrderResult{
+		OrderID:    string(side) + at.Format("1504"),
+		Side:       side,
+		Price:      decimal.NewFromFloat(price),
+		Quantity:   decimal.NewFromFloat(quantity),
+		Commission: decimal.NewFromFloat(commission),
+		Timestamp:  at,
+	}
+}
+
+func TestPositionLedger_PartialCloses(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ledger := NewPositionLedger(LotMatchingFIFO)
+	ledger.Buy(ledgerOrder(executor.OrderSideBuy, 100, 10, 10, start))
+	ledger.Buy(ledgerOrder(executor.OrderSideBuy, 200, 5, 5, start.Add(time.Hour)))
+
+	// 卖出 4 个：只平第一批的一部分，买入手续费按 4/10 分摊
+	trades := ledger.Sell(ledgerOrder(executor.OrderSideSell, 150, 4, 2, start.Add(2*time.Hour)))
+	require.Len(t, trades, 1)
+	assert.Equal(t, "4", trades[0].Quantity.String())
+	assert.Equal(t, "194", trades[0].PnL.String()) // 600 - 400 - 4 - 2
+	assert.Equal(t, "48.5", trades[0].PnLPercent.String())
+	assert.Equal(t, 2*time.Hour, trades[0].Duration)
+	assert.Equal(t, "11", ledger.Position().String())
+
+	// 卖出 8 个：平掉第一批剩余 6 个和第二批 2 个，卖出手续费按数量拆分
+	trades = ledger.Sell(ledgerOrder(executor.OrderSideSell, 250, 8, 8, start.Add(3*time.Hour)))
+	require.Len(t, trades, 2)
+	assert.Equal(t, "6", trades[0].Quantity.String())
+	assert.Equal(t, "888", trades[0].PnL.String()) // 1500 - 600 - 6 - 6
+	assert.Equal(t, "2", trades[1].Quantity.String())
+	assert.Equal(t, "96", trades[1].PnL.String()) // 500 - 400 - 2 - 2
+	assert.Same(t, trades[0].SellOrder, trades[1].SellOrder)
+
+	open := ledger.OpenPositions()
+	require.Len(t, open, 1)
+	assert.True(t, open[0].IsOpen)
+	assert.Equal(t, "3", open[0].Quantity.String())
+	assert.Equal(t, "3", open[0].Commission.String())
+
+	// 超出持仓的卖出数量忽略
+	trades = ledger.Sell(ledgerOrder(executor.OrderSideSell, 200, 5, 0, start.Add(4*time.Hour)))
+	require.Len(t, trades, 1)
+	assert.Equal(t, "3", trades[0].Quantity.String())
+	assert.True(t, ledger.Position().IsZero())
+	assert.Empty(t, ledger.OpenPositions())
+	assert.Nil(t, ledger.Sell(ledgerOrder(executor.OrderSideSell, 200, 1, 0, start.Add(5*time.Hour))))
+}
+
+func TestAnalyzeTrades_PartialSells(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := []executor.OrderResult{
+		// 乱序输入按时间排序
+		ledgerOrder(executor.OrderSideSell, 120, 5, 0, start.Add(2*time.Hour)),
+		ledgerOrder(executor.OrderSideBuy, 100, 10, 0, start),
+		ledgerOrder(executor.OrderSideSell, 90, 5, 0, start.Add(4*time.Hour)),
+	}
+
+	trades, openPositions, avgHolding, _, _, avgWin, avgLoss, _, _, profitFactor := AnalyzeTrades(orders)
+	require.Len(t, trades, 2)
+	assert.Empty(t, openPositions)
+	assert.Equal(t, "100", trades[0].PnL.String())
+	assert.Equal(t, "-50", trades[1].PnL.String())
+	assert.Equal(t, 3*time.Hour, avgHolding)
+	assert.Equal(t, "100", avgWin.String())
+	assert.Equal(t, "-50", avgLoss.String())
+	assert.Equal(t, "2", profitFactor.String())
+
+	// 卖出单的已实现盈亏为各批次之和
+	orders = append(orders, ledgerOrder(executor.OrderSideBuy, 100, 2, 0, start.Add(5*time.Hour)))
+	orders = append(orders, ledgerOrder(executor.OrderSideBuy, 110, 2, 0, start.Add(6*time.Hour)))
+	orders = append(orders, ledgerOrder(executor.OrderSideSell, 120, 4, 0, start.Add(7*time.Hour)))
+	stats := &BacktestStatistics{Orders: sortOrdersByTime(orders)}
+	stats.Trades, _, _, _, _, _, _, _, _, _ = AnalyzeTrades(orders)
+	records := buildTradeRecords("run", cex.TradingPair{Base: "BTC", Quote: "USDT"}, stats)
+	assert.Equal(t, "60", records[len(records)-1].PnL.String())
+}
+
+func TestPositionLedger_LotMatching(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 金字塔加仓两次，再按 30%、50%、剩余全部分批止盈
+	orders := []executor.OrderResult{
+		ledgerOrder(executor.OrderSideBuy, 100, 6, 0, start),
+		ledgerOrder(executor.OrderSideBuy, 120, 4, 0, start.Add(4*time.Hour)),
+		ledgerOrder(executor.OrderSideSell, 130, 3, 0, start.Add(8*time.Hour)),
+		ledgerOrder(executor.OrderSideSell, 140, 3.5, 0, start.Add(12*time.Hour)),
+		ledgerOrder(executor.OrderSideSell, 150, 3.5, 0, start.Add(16*time.Hour)),
+	}
+	closeAll := func(method string) []TradeAnalysis {
+		ledger := NewPositionLedger(method)
+		var trades []TradeAnalysis
+		for _, order := range orders {
+			trades = append(trades, ledger.Apply(order)...)
+		}
+		assert.True(t, ledger.Position().IsZero())
+		return trades
+	}
+	summary := func(trades []TradeAnalysis) []string {
+		var lines []string
+		for _, trade := range trades {
+			lines = append(lines, trade.Quantity.String()+"@"+trade.BuyOrder.Price.String()+" "+trade.PnL.String()+" "+trade.Duration.String())
+		}
+		return lines
+	}
+
+	assert.Equal(t, []string{
+		"3@100 90 8h0m0s",
+		"3@100 120 12h0m0s",
+		"0.5@120 10 8h0m0s",
+		"3.5@120 105 12h0m0s",
+	}, summary(closeAll(LotMatchingFIFO)))
+
+	assert.Equal(t, []string{
+		"3@120 30 4h0m0s",
+		"1@120 20 8h0m0s",
+		"2.5@100 100 12h0m0s",
+		"3.5@100 175 16h0m0s",
+	}, summary(closeAll(LotMatchingLIFO)))
+
+	// 平均成本 108，开仓时间按数量加权为 1:36
+	assert.Equal(t, []string{
+		"3@108 66 6h24m0s",
+		"3.5@108 112 10h24m0s",
+		"3.5@108 147 14h24m0s",
+	}, summary(closeAll(LotMatchingAverage)))
+
+	// 三种方式的总盈亏相同
+	for _, method := range SupportedLotMatchings() {
+		total := decimal.Zero
+		for _, trade := range closeAll(method) {
+			total = total.Add(trade.PnL)
+		}
+		assert.Equal(t, "325", total.String(), method)
+	}
+
+	assert.NoError(t, ValidateLotMatching(""))
+	assert.Error(t, ValidateLotMatching("hifo"))
+}
+
+func TestPositionLedger_TradeReasons(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ledger := NewPositionLedger(LotMatchingFIFO)
+
+	buy := ledgerOrder(executor.OrderSideBuy, 100, 2, 0, start)
+	buy.Reason, buy.Origin = "price below lower band", "BUY"
+	ledger.Buy(buy)
+	assert.Equal(t, "price below lower band", ledger.OpenPositions()[0].BuyReason)
+
+	stop := ledgerOrder(executor.OrderSideSell, 95, 1, 0, start.Add(time.Hour))
+	stop.Reason, stop.Origin = "trailing stop: 5.0% from high", "TRAILING_STOP"
+	trades := ledger.Sell(stop)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "price below lower band", trades[0].BuyReason)
+	assert.Equal(t, "trailing stop: 5.0% from high", trades[0].SellReason)
+
+	// 没有记录原因的成交（旧结果文件）使用默认原因
+	trades = ledger.Sell(ledgerOrder(executor.OrderSideSell, 110, 1, 0, start.Add(2*time.Hour)))
+	require.Len(t, trades, 1)
+	assert.Equal(t, defaultTradeReason, trades[0].SellReason)
+}
@@ -0,0 +1,291 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+	"tradingbot/src/i18n"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarketRegime 市场状态
+type MarketRegime string
+
+const (
+	RegimeTrendingUp   MarketRegime = "trending_up"   // 上涨趋势
+	RegimeTrendingDown MarketRegime = "trending_down" // 下跌趋势
+	RegimeRanging      MarketRegime = "ranging"       // 震荡
+	RegimeWarmup       MarketRegime = "warmup"        // 均线数据不足，无法判断
+)
+
+// regimeOrder 报告中市场状态的顺序
+var regimeOrder = []MarketRegime{RegimeTrendingUp, RegimeTrendingDown, RegimeRanging, RegimeWarmup}
+
+// RegimeConfig 按均线斜率划分市场状态：收盘价 MAPeriod 均线在 SlopeLookback 根K线内的变化率
+// 超过 +SlopeThreshold 为上涨趋势，低于 -SlopeThreshold 为下跌趋势，否则为震荡
+type RegimeConfig struct {
+	Enabled        bool    `json:"enabled"`         // 回测报告中输出各市场状态下的策略表现
+	MAPeriod       int     `json:"ma_period"`       // 均线周期，默认 50
+	SlopeLookback  int     `json:"slope_lookback"`  // 斜率回看K线数，默认 10
+	SlopeThreshold float64 `json:"slope_threshold"` // 趋势判定的均线变化率，默认 0.02（2%）
+}
+
+// WithDefaults 填充未设置的默认值
+func (c RegimeConfig) WithDefaults() RegimeConfig {
+	if c.MAPeriod == 0 {
+		c.MAPeriod = 50
+	}
+	if c.SlopeLookback == 0 {
+		c.SlopeLookback = 10
+	}
+	if c.SlopeThreshold == 0 {
+		c.SlopeThreshold = 0.02
+	}
+	return c
+}
+
+// Validate 验证市场状态配置
+func (c RegimeConfig) Validate() error {
+	if c.MAPeriod < 0 || c.SlopeLookback < 0 || c.SlopeThreshold < 0 {
+		return fmt.Errorf("regime ma_period, slope_lookback and slope_threshold must not be negative, got %d, %d, %f",
+			c.MAPeriod, c.SlopeLookback, c.SlopeThreshold)
+	}
+	return nil
+}
+
+// RegimeSegment 连续处于同一市场状态的区间（K线开盘时间 Start 到最后一根K线收盘时间 End）
+type RegimeSegment struct {
+	Regime MarketRegime `json:"regime"`
+	Start  time.Time    `json:"start"`
+	End    time.Time    `json:"end"`
+	Bars   int          `json:"bars"`
+}
+
+// RegimePerformance 单个市场状态下的策略表现
+type RegimePerformance struct {
+	Regime         MarketRegime    `json:"regime"`
+	Bars           int             `json:"bars"`
+	Share          decimal.Decimal `json:"share"`           // K线数占比
+	Segments       int             `json:"segments"`        // 区间数
+	StrategyReturn decimal.Decimal `json:"strategy_return"` // 该状态下各根K线组合收益率的复利
+	MarketReturn   decimal.Decimal `json:"market_return"`   // 该状态下各根K线价格涨跌幅的复利（买入持有）
+	ExcessReturn   decimal.Decimal `json:"excess_return"`   // 策略收益率 - 市场收益率
+	Trades         int             `json:"trades"`          // 开仓于该状态的已平仓交易
+	WinningTrades  int             `json:"winning_trades"`
+	PnL            decimal.Decimal `json:"pnl"` // 这些交易的已实现盈亏
+}
+
+// RegimeReport 按市场状态拆分的回测表现
+type RegimeReport struct {
+	Config   RegimeConfig        `json:"config"`
+	Regimes  []RegimePerformance `json:"regimes"` // 只包含出现过的状态
+	Segments []RegimeSegment     `json:"segments"`
+}
+
+// regimeTracker 逐根K线划分市场状态并累计各状态下的策略和市场收益
+type regimeTracker struct {
+	config RegimeConfig
+	valuer *portfolioValuer
+
+	closes   []float64 // 最近 MAPeriod 根K线的收盘价
+	closeSum float64
+	averages []float64 // 最近 SlopeLookback+1 个均线值
+
+	prevValue float64
+	prevPrice float64
+	started   bool
+
+	growth   map[MarketRegime]*regimeGrowth
+	segments []RegimeSegment
+}
+
+// regimeGrowth 单个市场状态下的K线数和累计净值
+type regimeGrowth struct {
+	bars     int
+	strategy float64
+	market   float64
+}
+
+func newRegimeTracker(config RegimeConfig, orders []executor.OrderResult, initialCapital decimal.Decimal) *regimeTracker {
+	return &regimeTracker{
+		config:    config.WithDefaults(),
+		valuer:    newPortfolioValuer(orders, initialCapital),
+		prevValue: initialCapital.InexactFloat64(),
+		growth:    make(map[MarketRegime]*regimeGrowth),
+	}
+}
+
+// classify 加入一根K线的收盘价，返回该K线所处的市场状态
+func (t *regimeTracker) classify(price float64) MarketRegime {
+	t.closes = append(t.closes, price)
+	t.closeSum += price
+	if len(t.closes) > t.config.MAPeriod {
+		t.closeSum -= t.closes[0]
+		t.closes = t.closes[1:]
+	}
+	if len(t.closes) < t.config.MAPeriod {
+		return RegimeWarmup
+	}
+
+	t.averages = append(t.averages, t.closeSum/float64(t.config.MAPeriod))
+	if len(t.averages) > t.config.SlopeLookback+1 {
+		t.averages = t.averages[1:]
+	}
+	if len(t.averages) < t.config.SlopeLookback+1 || t.averages[0] <= 0 {
+		return RegimeWarmup
+	}
+
+	slope := t.averages[len(t.averages)-1]/t.averages[0] - 1
+	switch {
+	case slope > t.config.SlopeThreshold:
+		return RegimeTrendingUp
+	case slope < -t.config.SlopeThreshold:
+		return RegimeTrendingDown
+	default:
+		return RegimeRanging
+	}
+}
+
+func (t *regimeTracker) add(kline *cex.KlineData) {
+	price := kline.Close.InexactFloat64()
+	value := t.valuer.next(kline).InexactFloat64()
+	regime := t.classify(price)
+
+	// 首根K线以初始资金和开盘价为起点
+	if !t.started {
+		t.prevPrice = kline.Open.InexactFloat64()
+		t.started = true
+	}
+
+	growth, ok := t.growth[regime]
+	if !ok {
+		growth = &regimeGrowth{strategy: 1, market: 1}
+		t.growth[regime] = growth
+	}
+	growth.bars++
+	if t.prevValue > 0 {
+		growth.strategy *= value / t.prevValue
+	}
+	if t.prevPrice > 0 {
+		growth.market *= price / t.prevPrice
+	}
+	t.prevValue, t.prevPrice = value, price
+
+	if n := len(t.segments); n > 0 && t.segments[n-1].Regime == regime {
+		t.segments[n-1].End = kline.CloseTime
+		t.segments[n-1].Bars++
+	} else {
+		t.segments = append(t.segments, RegimeSegment{Regime: regime, Start: kline.OpenTime, End: kline.CloseTime, Bars: 1})
+	}
+}
+
+// result 生成报告，已平仓交易按开仓时间归入所在区间的市场状态
+func (t *regimeTracker) result(trades []TradeAnalysis) *RegimeReport {
+	report := &RegimeReport{Config: t.config, Regimes: []RegimePerformance{}, Segments: t.segments}
+	if report.Segments == nil {
+		report.Segments = []RegimeSegment{}
+	}
+
+	totalBars := 0
+	segmentCounts := make(map[MarketRegime]int)
+	for _, segment := range t.segments {
+		totalBars += segment.Bars
+		segmentCounts[segment.Regime]++
+	}
+
+	performance := make(map[MarketRegime]*RegimePerformance)
+	for _, regime := range regimeOrder {
+		growth, ok := t.growth[regime]
+		if !ok {
+			continue
+		}
+		strategyReturn := decimal.NewFromFloat(growth.strategy - 1)
+		marketReturn := decimal.NewFromFloat(growth.market - 1)
+		performance[regime] = &RegimePerformance{
+			Regime:         regime,
+			Bars:           growth.bars,
+			Share:          decimal.NewFromInt(int64(growth.bars)).Div(decimal.NewFromInt(int64(totalBars))),
+			Segments:       segmentCounts[regime],
+			StrategyReturn: strategyReturn,
+			MarketReturn:   marketReturn,
+			ExcessReturn:   strategyReturn.Sub(marketReturn),
+		}
+	}
+
+	for _, trade := range trades {
+		if trade.IsOpen {
+			continue
+		}
+		segment := t.segmentAt(trade.BuyOrder.Timestamp)
+		if segment == nil {
+			continue
+		}
+		regimePerformance := performance[segment.Regime]
+		regimePerformance.Trades++
+		if trade.PnL.IsPositive() {
+			regimePerformance.WinningTrades++
+		}
+		regimePerformance.PnL = regimePerformance.PnL.Add(trade.PnL)
+	}
+
+	for _, regime := range regimeOrder {
+		if regimePerformance, ok := performance[regime]; ok {
+			report.Regimes = append(report.Regimes, *regimePerformance)
+		}
+	}
+	return report
+}
+
+// segmentAt 时间点所在的区间（早于第一个区间时为 nil，晚于最后一个区间时归入最后一个）
+func (t *regimeTracker) segmentAt(at time.Time) *RegimeSegment {
+	index := sort.Search(len(t.segments), func(i int) bool {
+		return t.segments[i].Start.After(at)
+	})
+	if index == 0 {
+		return nil
+	}
+	return &t.segments[index-1]
+}
+
+// regimeName 市场状态的显示名称
+func regimeName(regime MarketRegime) string {
+	return i18n.T("regime." + string(regime))
+}
+
+// printRegimeReport 打印各市场状态下的策略表现
+func printRegimeReport(report *RegimeReport) {
+	if report == nil || len(report.Regimes) == 0 {
+		return
+	}
+
+	percent := func(d decimal.Decimal) float64 {
+		return d.Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+
+	fmt.Println("\n" + i18n.T("report.regimes", report.Config.MAPeriod, report.Config.SlopeLookback, report.Config.SlopeThreshold*100))
+	fmt.Println("--------------------------------------------------------------------------------")
+	fmt.Println(i18n.T("report.regime_header"))
+	fmt.Println("--------------------------------------------------------------------------------")
+	for _, regime := range report.Regimes {
+		winRate := 0.0
+		if regime.Trades > 0 {
+			winRate = float64(regime.WinningTrades) / float64(regime.Trades) * 100
+		}
+		fmt.Printf("%-14s %6d %7.1f %5d %10.2f %9.2f %9.2f %6d %6.1f %11.2f\n",
+			regimeName(regime.Regime),
+			regime.Bars,
+			percent(regime.Share),
+			regime.Segments,
+			percent(regime.StrategyReturn),
+			percent(regime.MarketReturn),
+			percent(regime.ExcessReturn),
+			regime.Trades,
+			winRate,
+			regime.PnL.InexactFloat64(),
+		)
+	}
+}
@@ -0,0 +1,98 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regimeTestKlines 每根K线的开盘价为上一根收盘价（首根为 100）
+func regimeTestKlines(closes ...float64) []*cex.KlineData {
+	baseTime := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]*cex.KlineData, len(closes))
+	prevClose := 100.0
+	for i, c := range closes {
+		klines[i] = &cex.KlineData{
+			OpenTime:  baseTime.Add(time.Duration(i) * time.Hour),
+			CloseTime: baseTime.Add(time.Duration(i+1)*time.Hour - time.Millisecond),
+			Open:      decimal.NewFromFloat(prevClose),
+			Close:     decimal.NewFromFloat(c),
+		}
+		prevClose = c
+	}
+	return klines
+}
+
+func TestKlineStats_Regimes(t *testing.T) {
+	// MA3 在2根K线内上涨/下跌超过2%为趋势：0-3 预热，4-5 震荡，6-10 上涨，11-13 下跌
+	klines := regimeTestKlines(100, 100, 100, 100, 100, 100, 110, 120, 130, 140, 120, 100, 80, 70)
+	config := RegimeConfig{Enabled: true, MAPeriod: 3, SlopeLookback: 2, SlopeThreshold: 0.02}
+
+	// 震荡期买入，上涨期卖出
+	buy := executor.OrderResult{Side: executor.OrderSideBuy, Price: decimal.NewFromInt(100), Quantity: decimal.NewFromInt(10), Timestamp: klines[5].OpenTime.Add(30 * time.Minute)}
+	sell := executor.OrderResult{Side: executor.OrderSideSell, Price: decimal.NewFromInt(140), Quantity: decimal.NewFromInt(10), Timestamp: klines[9].OpenTime.Add(30 * time.Minute)}
+	trades := []TradeAnalysis{{BuyOrder: buy, SellOrder: &sell, PnL: decimal.NewFromInt(400)}}
+
+	stats := NewKlineStats([]executor.OrderResult{buy, sell}, decimal.NewFromInt(1000))
+	stats.EnableRegimes(config)
+	for _, kline := range klines {
+		stats.Add(kline)
+	}
+	report := stats.Regimes(trades)
+	require.NotNil(t, report)
+
+	segments := make([]MarketRegime, len(report.Segments))
+	for i, segment := range report.Segments {
+		segments[i] = segment.Regime
+	}
+	assert.Equal(t, []MarketRegime{RegimeWarmup, RegimeRanging, RegimeTrendingUp, RegimeTrendingDown}, segments)
+	assert.Equal(t, klines[6].OpenTime, report.Segments[2].Start)
+	assert.Equal(t, klines[10].CloseTime, report.Segments[2].End)
+
+	require.Len(t, report.Regimes, 4)
+	byRegime := make(map[MarketRegime]RegimePerformance)
+	for _, regime := range report.Regimes {
+		byRegime[regime.Regime] = regime
+	}
+
+	up := byRegime[RegimeTrendingUp]
+	assert.Equal(t, 5, up.Bars)
+	assert.Equal(t, 1, up.Segments)
+	assert.InDelta(t, 5.0/14, up.Share.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 0.4, up.StrategyReturn.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 0.2, up.MarketReturn.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 0.2, up.ExcessReturn.InexactFloat64(), 1e-9)
+	assert.Equal(t, 0, up.Trades)
+
+	// 交易按开仓时间归入震荡
+	ranging := byRegime[RegimeRanging]
+	assert.Equal(t, 2, ranging.Bars)
+	assert.Equal(t, 1, ranging.Trades)
+	assert.Equal(t, 1, ranging.WinningTrades)
+	assert.True(t, ranging.PnL.Equal(decimal.NewFromInt(400)))
+
+	// 下跌期空仓
+	down := byRegime[RegimeTrendingDown]
+	assert.InDelta(t, 0.0, down.StrategyReturn.InexactFloat64(), 1e-9)
+	assert.InDelta(t, 70.0/120-1, down.MarketReturn.InexactFloat64(), 1e-9)
+}
+
+func TestKlineStats_RegimesDisabled(t *testing.T) {
+	stats := NewKlineStats(nil, decimal.NewFromInt(1000))
+	stats.Add(regimeTestKlines(101)[0])
+	assert.Nil(t, stats.Regimes(nil))
+}
+
+func TestRegimeConfig(t *testing.T) {
+	config := RegimeConfig{}.WithDefaults()
+	assert.Equal(t, RegimeConfig{MAPeriod: 50, SlopeLookback: 10, SlopeThreshold: 0.02}, config)
+
+	assert.NoError(t, config.Validate())
+	assert.Error(t, RegimeConfig{MAPeriod: -1}.Validate())
+}
@@ -0,0 +1,56 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/notify"
+)
+
+// shutdownTimeout 停止时撤单、清仓和发送通知的最长时间
+const shutdownTimeout = 30 * time.Second
+
+// RequestShutdown 请求停止实盘（如收到退出信号），引擎退出后按 Shutdown 配置处理挂单和持仓
+func (ts *TradingSystem) RequestShutdown(reason string) {
+	ts.shutdownMu.Lock()
+	ts.shutdownReason = reason
+	ts.shutdownMu.Unlock()
+	ts.Stop()
+}
+
+// finishLive 引擎退出后按 Shutdown 配置撤单、清仓，保存策略状态并发送停止通知。
+// 引擎不是因停止请求而退出（启动失败、数据喂入结束）或处理出错时返回错误，命令以非零状态退出
+func (ts *TradingSystem) finishLive(router *notify.Router, runErr error) error {
+	requested := ts.ctx.Err() != nil
+
+	ts.shutdownMu.Lock()
+	reason := ts.shutdownReason
+	ts.shutdownMu.Unlock()
+	switch {
+	case reason != "":
+	case requested:
+		reason = "stopped"
+	case runErr != nil:
+		reason = runErr.Error()
+	default:
+		reason = "data feed finished"
+	}
+
+	// 运行 ctx 已取消，撤单、清仓和发送通知使用独立的超时 ctx
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ts.ctx), shutdownTimeout)
+	defer cancel()
+	report := ts.tradingEngine.Shutdown(ctx, TradingConfigValue.Shutdown, reason, !requested)
+	router.Flush(ctx)
+
+	if !requested {
+		if runErr != nil {
+			return fmt.Errorf("engine stopped unexpectedly: %w", runErr)
+		}
+		return fmt.Errorf("engine stopped unexpectedly: %s", reason)
+	}
+	if err := report.Err(); err != nil {
+		return fmt.Errorf("shutdown incomplete: %w", err)
+	}
+	return nil
+}
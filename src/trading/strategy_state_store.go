@@ -0,0 +1,71 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// StrategyStateDB 策略状态表（由 database.PostgresDB 实现）
+type StrategyStateDB interface {
+	// SaveStrategyState 保存策略状态（JSON），已存在时覆盖
+	SaveStrategyState(ctx context.Context, key, strategyName string, state []byte) error
+
+	// GetStrategyState 获取策略状态（JSON），不存在时返回 nil
+	GetStrategyState(ctx context.Context, key string) ([]byte, error)
+}
+
+// strategyStateStore 将策略状态保存到 strategy_states 表
+type strategyStateStore struct {
+	db StrategyStateDB
+}
+
+// NewStrategyStateStore 创建基于数据库的策略状态存储
+func NewStrategyStateStore(db StrategyStateDB) engine.StrategyStateStore {
+	return &strategyStateStore{db: db}
+}
+
+// LoadStrategyState 读取策略状态，不存在时返回 nil
+func (s *strategyStateStore) LoadStrategyState(ctx context.Context, key string) ([]byte, error) {
+	return s.db.GetStrategyState(ctx, key)
+}
+
+// SaveStrategyState 保存策略状态
+func (s *strategyStateStore) SaveStrategyState(ctx context.Context, key, strategyName string, state []byte) error {
+	return s.db.SaveStrategyState(ctx, key, strategyName, state)
+}
+
+// StrategyStateKey 策略状态的存储键：<会话名>_<周期>（同一会话不同周期的机器人分别保存）
+func StrategyStateKey(sessionID, timeframe string) string {
+	return fmt.Sprintf("%s_%s", sessionID, timeframe)
+}
+
+// strategyStateSessionID 策略状态的会话名：Dry Run 为模拟盘会话名，只发信号为 signal_<交易所>_<交易对>，实盘与账户快照相同
+func (ts *TradingSystem) strategyStateSessionID(pair cex.TradingPair, dryRun bool) string {
+	switch {
+	case ts.signalOnly:
+		return fmt.Sprintf("signal_%s", DefaultLiveSessionID(ts.cexName, pair, ts.Testnet()))
+	case dryRun:
+		return ts.paperSessionID(pair)
+	default:
+		return DefaultLiveSessionID(ts.cexName, pair, ts.Testnet())
+	}
+}
+
+// applyStrategyStateStore 数据库可用时由引擎保存、恢复策略状态，否则重启后策略从头开始
+func (ts *TradingSystem) applyStrategyStateStore(pair cex.TradingPair, dryRun bool) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("⚠️ 数据库不可用，策略状态不会在重启后恢复: %v", err))
+		return
+	}
+	key := StrategyStateKey(ts.strategyStateSessionID(pair, dryRun), ts.Timeframe())
+	ts.tradingEngine.SetStrategyStateStore(NewStrategyStateStore(db), key)
+	logger.Info(fmt.Sprintf("✓ 策略状态持久化: key=%s", key))
+}
@@ -0,0 +1,95 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// restartWindow 重启次数的统计窗口
+const restartWindow = time.Hour
+
+// SupervisorConfig 实盘引擎意外退出（panic、运行错误）后的自动重启策略
+type SupervisorConfig struct {
+	MaxRestartsPerHour  int `json:"max_restarts_per_hour"` // 一小时内最多自动重启的次数，超过后停止运行（0 表示不重启）
+	RestartDelaySeconds int `json:"restart_delay_seconds"` // 重启前等待的秒数
+}
+
+// Validate 检查重启策略
+func (c SupervisorConfig) Validate() error {
+	if c.MaxRestartsPerHour < 0 {
+		return fmt.Errorf("MaxRestartsPerHour cannot be negative, got %d", c.MaxRestartsPerHour)
+	}
+	if c.RestartDelaySeconds < 0 {
+		return fmt.Errorf("RestartDelaySeconds cannot be negative, got %d", c.RestartDelaySeconds)
+	}
+	return nil
+}
+
+// restartLimiter 统计最近一小时内的重启次数
+type restartLimiter struct {
+	max      int
+	restarts []time.Time
+}
+
+// allow 记录一次重启，最近一小时内的重启次数已达上限时返回 false
+func (l *restartLimiter) allow(now time.Time) bool {
+	recent := l.restarts[:0]
+	for _, t := range l.restarts {
+		if now.Sub(t) < restartWindow {
+			recent = append(recent, t)
+		}
+	}
+	l.restarts = recent
+	if len(l.restarts) >= l.max {
+		return false
+	}
+	l.restarts = append(l.restarts, now)
+	return true
+}
+
+// supervise 运行 run，意外退出（返回错误且 ctx 未取消）时按重启策略等待后重新运行。
+// 正常退出、ctx 取消或超过每小时重启次数时返回最后一次的结果；每次重启前调用 onRestart
+func supervise(ctx context.Context, config SupervisorConfig, run func(ctx context.Context) error, onRestart func(restarts int, err error)) error {
+	_, logger := log.WithCtx(ctx)
+	limiter := &restartLimiter{max: config.MaxRestartsPerHour}
+	delay := time.Duration(config.RestartDelaySeconds) * time.Second
+
+	for {
+		err := run(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if !limiter.allow(time.Now()) {
+			logger.Error(fmt.Sprintf("🛑 引擎意外退出，一小时内重启次数已达上限，不再重启: MaxRestartsPerHour=%d, err=%v", config.MaxRestartsPerHour, err))
+			return err
+		}
+
+		restarts := len(limiter.restarts)
+		logger.Warning(fmt.Sprintf("♻️ 引擎意外退出，%s 后重启: restart=%d/%d, err=%v", delay, restarts, config.MaxRestartsPerHour, err))
+		if onRestart != nil {
+			onRestart(restarts, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// runLiveSupervised 运行实盘引擎，策略或挂单管理器以外的 panic、数据喂入启动失败等导致引擎意外退出时按 Supervisor 配置自动重启，
+// 重启前发布 engine_restarted 事件；挂单、持仓和策略状态保留，重启后从数据库恢复策略状态继续运行
+func (ts *TradingSystem) runLiveSupervised(pair cex.TradingPair, events *engine.EventBus) error {
+	return supervise(ts.ctx, TradingConfigValue.Supervisor, ts.tradingEngine.RunLive, func(restarts int, err error) {
+		events.Publish(ts.ctx, &engine.Event{Type: engine.EventEngineRestarted, Time: time.Now(), TradingPair: pair,
+			Message: fmt.Sprintf("%v; restart %d/%d in the last hour", err, restarts, TradingConfigValue.Supervisor.MaxRestartsPerHour)})
+	})
+}
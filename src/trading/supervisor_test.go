@@ -0,0 +1,84 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervisorConfig_Validate(t *testing.T) {
+	assert.NoError(t, SupervisorConfig{}.Validate())
+	assert.NoError(t, SupervisorConfig{MaxRestartsPerHour: 3, RestartDelaySeconds: 10}.Validate())
+	assert.Error(t, SupervisorConfig{MaxRestartsPerHour: -1}.Validate())
+	assert.Error(t, SupervisorConfig{RestartDelaySeconds: -1}.Validate())
+}
+
+func TestRestartLimiter_SlidingHour(t *testing.T) {
+	limiter := &restartLimiter{max: 2}
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	assert.True(t, limiter.allow(start))
+	assert.True(t, limiter.allow(start.Add(10*time.Minute)))
+	assert.False(t, limiter.allow(start.Add(30*time.Minute)))
+	// 第一次重启滑出一小时窗口后允许再次重启
+	assert.True(t, limiter.allow(start.Add(61*time.Minute)))
+	assert.False(t, limiter.allow(start.Add(65*time.Minute)))
+
+	assert.False(t, (&restartLimiter{}).allow(start), "max 0 disables restarts")
+}
+
+func TestSupervise_RestartsUntilLimit(t *testing.T) {
+	crash := errors.New("panic in 引擎运行: boom")
+	runs := 0
+	var restarts []int
+	err := supervise(context.Background(), SupervisorConfig{MaxRestartsPerHour: 2}, func(ctx context.Context) error {
+		runs++
+		return crash
+	}, func(restart int, err error) {
+		restarts = append(restarts, restart)
+		assert.ErrorIs(t, err, crash)
+	})
+
+	assert.ErrorIs(t, err, crash)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, []int{1, 2}, restarts)
+}
+
+func TestSupervise_StopsOnCleanExitOrCancel(t *testing.T) {
+	// 重启后正常退出
+	runs := 0
+	err := supervise(context.Background(), SupervisorConfig{MaxRestartsPerHour: 5}, func(ctx context.Context) error {
+		runs++
+		if runs == 1 {
+			return errors.New("data feed start failed")
+		}
+		return nil
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+
+	// 停止请求（ctx 取消）后不重启
+	ctx, cancel := context.WithCancel(context.Background())
+	runs = 0
+	err = supervise(ctx, SupervisorConfig{MaxRestartsPerHour: 5}, func(ctx context.Context) error {
+		runs++
+		cancel()
+		return ctx.Err()
+	}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, runs)
+
+	// 等待重启期间停止
+	ctx, cancel = context.WithCancel(context.Background())
+	runs = 0
+	err = supervise(ctx, SupervisorConfig{MaxRestartsPerHour: 5, RestartDelaySeconds: 60}, func(ctx context.Context) error {
+		runs++
+		return errors.New("crashed")
+	}, func(int, error) { cancel() })
+	assert.EqualError(t, err, "crashed")
+	assert.Equal(t, 1, runs)
+}
@@ -0,0 +1,92 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+)
+
+// SymbolInfoDB 交易对信息表（由 database.PostgresDB 实现）
+type SymbolInfoDB interface {
+	GetSymbolInfo(symbol string) (*database.SymbolInfo, error)
+	SaveSymbolInfo(info *database.SymbolInfo) error
+}
+
+// symbolFilterStore 将交易对下单规则保存到 symbols 表
+type symbolFilterStore struct {
+	db SymbolInfoDB
+}
+
+// NewSymbolFilterStore 创建基于 symbols 表的下单规则存储
+func NewSymbolFilterStore(db SymbolInfoDB) engine.SymbolFilterStore {
+	return &symbolFilterStore{db: db}
+}
+
+// LoadSymbolFilters 读取下单规则（交易对不存在或未上线时返回错误）
+func (s *symbolFilterStore) LoadSymbolFilters(ctx context.Context, pair cex.TradingPair) (*cex.SymbolFilters, error) {
+	info, err := s.db.GetSymbolInfo(DatabaseSymbol(pair))
+	if err != nil {
+		return nil, err
+	}
+	return &cex.SymbolFilters{
+		Symbol:      info.Symbol,
+		MinQty:      info.MinQty,
+		MaxQty:      info.MaxQty,
+		StepSize:    info.StepSize,
+		MinPrice:    info.MinPrice,
+		MaxPrice:    info.MaxPrice,
+		TickSize:    info.TickSize,
+		MinNotional: info.MinNotional,
+	}, nil
+}
+
+// SaveSymbolFilters 保存下单规则
+func (s *symbolFilterStore) SaveSymbolFilters(ctx context.Context, pair cex.TradingPair, filters *cex.SymbolFilters) error {
+	return s.db.SaveSymbolInfo(&database.SymbolInfo{
+		Symbol:      DatabaseSymbol(pair),
+		BaseAsset:   pair.Base,
+		QuoteAsset:  pair.Quote,
+		Status:      "TRADING",
+		MinQty:      filters.MinQty,
+		MaxQty:      filters.MaxQty,
+		StepSize:    filters.StepSize,
+		MinPrice:    filters.MinPrice,
+		MaxPrice:    filters.MaxPrice,
+		TickSize:    filters.TickSize,
+		MinNotional: filters.MinNotional,
+	})
+}
+
+// loadSymbolFilters 加载交易对下单规则（交易所和数据库都不可用时不取整，只输出警告）
+func (ts *TradingSystem) loadSymbolFilters(pair cex.TradingPair) *engine.SymbolFilterService {
+	var store engine.SymbolFilterStore
+	if db, err := GetPostgresDB(ts.cexClient); err == nil {
+		store = NewSymbolFilterStore(db)
+	}
+
+	service := engine.NewSymbolFilterService(ts.cexClient, store)
+	filters, err := service.Load(ts.ctx, pair)
+	if err != nil {
+		fmt.Printf("⚠️ Symbol filters unavailable, orders will not be rounded: %v\n", err)
+		return service
+	}
+	fmt.Printf("✓ Symbol filters for %s: step=%s, tick=%s, min_qty=%s, min_notional=%s\n",
+		pair.String(), filters.StepSize.String(), filters.TickSize.String(), filters.MinQty.String(), filters.MinNotional.String())
+	return service
+}
+
+// displaySymbolFilters 从 symbols 表读取下单规则，用于回测结果的显示精度（数据库不可用或没有记录时为 nil）
+func (ts *TradingSystem) displaySymbolFilters(pair cex.TradingPair) *cex.SymbolFilters {
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return nil
+	}
+	filters, err := NewSymbolFilterStore(db).LoadSymbolFilters(ts.ctx, pair)
+	if err != nil {
+		return nil
+	}
+	return filters
+}
@@ -0,0 +1,109 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+	"tradingbot/src/engine"
+)
+
+// SymbolStore 交易对信息表（由 database.PostgresDB 实现）
+type SymbolStore interface {
+	// SaveSymbolInfos 批量保存交易对信息，已存在时覆盖
+	SaveSymbolInfos(ctx context.Context, infos []*database.SymbolInfo) error
+}
+
+// SymbolSyncResult 交易对信息同步结果
+type SymbolSyncResult struct {
+	Total   int // 交易所返回的现货交易对数
+	Trading int // 写入 symbols 表的 TRADING 交易对数
+}
+
+// SymbolSyncer 从交易所 exchangeInfo 同步交易对及其下单规则到 symbols 表
+type SymbolSyncer struct {
+	client cex.ExchangeInfoProvider
+	store  SymbolStore
+}
+
+// NewSymbolSyncer 创建交易对同步器（交易所需支持获取全部交易对）
+func NewSymbolSyncer(client cex.CEXClient, store SymbolStore) (*SymbolSyncer, error) {
+	provider, ok := client.(cex.ExchangeInfoProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support exchange info sync", client.GetName())
+	}
+	return &SymbolSyncer{client: provider, store: store}, nil
+}
+
+// Sync 拉取交易所全部现货交易对，写入所有 TRADING 状态的交易对
+func (s *SymbolSyncer) Sync(ctx context.Context) (*SymbolSyncResult, error) {
+	symbols, err := s.client.GetExchangeSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*database.SymbolInfo, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol.Status != cex.SymbolStatusTrading {
+			continue
+		}
+		filters := symbol.Filters
+		infos = append(infos, &database.SymbolInfo{
+			Symbol:      DatabaseSymbol(cex.TradingPair{Base: symbol.BaseAsset, Quote: symbol.QuoteAsset}),
+			BaseAsset:   symbol.BaseAsset,
+			QuoteAsset:  symbol.QuoteAsset,
+			Status:      symbol.Status,
+			MinQty:      filters.MinQty,
+			MaxQty:      filters.MaxQty,
+			StepSize:    filters.StepSize,
+			MinPrice:    filters.MinPrice,
+			MaxPrice:    filters.MaxPrice,
+			TickSize:    filters.TickSize,
+			MinNotional: filters.MinNotional,
+		})
+	}
+
+	if err := s.store.SaveSymbolInfos(ctx, infos); err != nil {
+		return nil, err
+	}
+	return &SymbolSyncResult{Total: len(symbols), Trading: len(infos)}, nil
+}
+
+// startSymbolRefresh 实盘后台定期刷新 symbols 表和当前交易对的下单规则
+func (ts *TradingSystem) startSymbolRefresh(pair cex.TradingPair, filters *engine.SymbolFilterService) {
+	hours := TradingConfigValue.SymbolRefreshHours
+	if hours <= 0 {
+		return
+	}
+
+	var syncer *SymbolSyncer
+	if db, err := GetPostgresDB(ts.cexClient); err == nil {
+		syncer, _ = NewSymbolSyncer(ts.cexClient, db)
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(hours) * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ts.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if syncer != nil {
+				if result, err := syncer.Sync(ts.ctx); err != nil {
+					fmt.Printf("⚠️ Failed to refresh symbols table: %v\n", err)
+				} else {
+					fmt.Printf("🔄 Refreshed symbols table: %d trading symbols\n", result.Trading)
+				}
+			}
+			if _, err := filters.Load(ts.ctx, pair); err != nil {
+				fmt.Printf("⚠️ Failed to refresh symbol filters for %s, keeping previous: %v\n", pair.String(), err)
+			}
+		}
+	}()
+	fmt.Printf("✓ Symbol filters refresh every %dh\n", hours)
+}
@@ -0,0 +1,63 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/database"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockExchangeInfoClient 返回固定交易对列表的交易所mock
+type mockExchangeInfoClient struct {
+	cex.CEXClient
+	symbols []*cex.ExchangeSymbol
+}
+
+func (m *mockExchangeInfoClient) GetExchangeSymbols(ctx context.Context) ([]*cex.ExchangeSymbol, error) {
+	return m.symbols, nil
+}
+
+// mockSymbolStore 记录写入的交易对
+type mockSymbolStore struct {
+	saved []*database.SymbolInfo
+}
+
+func (m *mockSymbolStore) SaveSymbolInfos(ctx context.Context, infos []*database.SymbolInfo) error {
+	m.saved = append(m.saved, infos...)
+	return nil
+}
+
+func TestSymbolSyncer_SavesTradingSymbols(t *testing.T) {
+	client := &mockExchangeInfoClient{symbols: []*cex.ExchangeSymbol{
+		{BaseAsset: "BTC", QuoteAsset: "USDT", Status: cex.SymbolStatusTrading, Filters: cex.SymbolFilters{
+			Symbol:      "BTCUSDT",
+			StepSize:    decimal.RequireFromString("0.00001"),
+			TickSize:    decimal.RequireFromString("0.01"),
+			MinNotional: decimal.NewFromInt(5),
+		}},
+		{BaseAsset: "LUNA", QuoteAsset: "USDT", Status: "BREAK"},
+	}}
+	store := &mockSymbolStore{}
+
+	syncer, err := NewSymbolSyncer(client, store)
+	require.NoError(t, err)
+	result, err := syncer.Sync(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 1, result.Trading)
+	require.Len(t, store.saved, 1)
+	assert.Equal(t, "BTCUSDT", store.saved[0].Symbol)
+	assert.Equal(t, "TRADING", store.saved[0].Status)
+	assert.Equal(t, "0.01", store.saved[0].TickSize.String())
+}
+
+func TestNewSymbolSyncer_RequiresExchangeInfo(t *testing.T) {
+	_, err := NewSymbolSyncer(&mockSyncClient{}, &mockSymbolStore{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package trading
+
+import (
+	"fmt"
+
+	"tradingbot/src/logging"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// startTrace 为本次运行分配跟踪ID（上层已在 ctx 中分配时沿用，如多机器人管理器），
+// 之后交易系统的日志、数据库记录、通知和导出文件都带上该ID
+func (ts *TradingSystem) startTrace(kind string) {
+	if ts.traceID != "" {
+		return
+	}
+	if traceID := logging.TraceIDFromContext(ts.ctx); traceID != "" {
+		ts.traceID = traceID
+		return
+	}
+
+	ts.traceID = logging.NewTraceID(kind)
+	ts.ctx = logging.WithTraceID(ts.ctx, ts.traceID)
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("🔖 跟踪ID: trace_id=%s", ts.traceID))
+}
+
+// TraceID 本次运行的跟踪ID（回测或实盘开始前为空）
+func (ts *TradingSystem) TraceID() string {
+	return ts.traceID
+}
@@ -0,0 +1,221 @@
+package trading
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+)
+
+// JournalEntry 交易日志中的一笔已完成交易：开平仓的信号、原因、指标快照、持仓时间、手续费和相对信号价格的滑点
+type JournalEntry struct {
+	No       int             `json:"no"`
+	Quantity decimal.Decimal `json:"quantity"`
+
+	EntryTime        time.Time          `json:"entry_time"`
+	EntryPrice       decimal.Decimal    `json:"entry_price"`
+	EntrySignal      string             `json:"entry_signal,omitempty"` // 信号类型，保护单为空
+	EntryReason      string             `json:"entry_reason"`
+	EntrySignalPrice decimal.Decimal    `json:"entry_signal_price"` // 信号K线收盘价，没有信号时为 0
+	EntrySlippageBps float64            `json:"entry_slippage_bps"` // 成交价相对信号价格的不利滑点（基点），正数表示成交更差
+	EntryIndicators  map[string]float64 `json:"entry_indicators,omitempty"`
+
+	ExitTime        time.Time          `json:"exit_time"`
+	ExitPrice       decimal.Decimal    `json:"exit_price"`
+	ExitSignal      string             `json:"exit_signal,omitempty"`
+	ExitReason      string             `json:"exit_reason"`
+	ExitSignalPrice decimal.Decimal    `json:"exit_signal_price"`
+	ExitSlippageBps float64            `json:"exit_slippage_bps"`
+	ExitIndicators  map[string]float64 `json:"exit_indicators,omitempty"`
+
+	HoldingTime time.Duration   `json:"holding_time"` // 纳秒
+	Commission  decimal.Decimal `json:"commission"`
+	PnL         decimal.Decimal `json:"pnl"`
+	PnLPercent  decimal.Decimal `json:"pnl_percent"`
+
+	TraceID string `json:"trace_id,omitempty"` // 产生该交易的运行跟踪ID
+}
+
+// BuildTradeJournal 由已完成交易生成交易日志（未平仓的交易不包含）
+func BuildTradeJournal(trades []TradeAnalysis) []JournalEntry {
+	entries := make([]JournalEntry, 0, len(trades))
+	for _, trade := range trades {
+		if trade.IsOpen || trade.SellOrder == nil {
+			continue
+		}
+		entry := JournalEntry{
+			No:          len(entries) + 1,
+			Quantity:    trade.Quantity,
+			EntryTime:   trade.BuyOrder.Timestamp,
+			EntryPrice:  trade.BuyOrder.Price,
+			EntryReason: trade.BuyReason,
+			ExitTime:    trade.SellOrder.Timestamp,
+			ExitPrice:   trade.SellOrder.Price,
+			ExitReason:  trade.SellReason,
+			HoldingTime: trade.Duration,
+			Commission:  trade.Commission,
+			PnL:         trade.PnL,
+			PnLPercent:  trade.PnLPercent,
+		}
+		if signal := trade.BuyOrder.Signal; signal != nil {
+			entry.EntrySignal, entry.EntryReason, entry.EntrySignalPrice, entry.EntryIndicators = signal.Type, signal.Reason, signal.Price, signal.Indicators
+			entry.EntrySlippageBps = slippageBps(executor.OrderSideBuy, signal.Price, entry.EntryPrice)
+		}
+		if signal := trade.SellOrder.Signal; signal != nil {
+			entry.ExitSignal, entry.ExitReason, entry.ExitSignalPrice, entry.ExitIndicators = signal.Type, signal.Reason, signal.Price, signal.Indicators
+			entry.ExitSlippageBps = slippageBps(executor.OrderSideSell, signal.Price, entry.ExitPrice)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// slippageBps 成交价相对信号价格的不利滑点（基点）：买入成交高于信号价、卖出成交低于信号价为正
+func slippageBps(side executor.OrderSide, signalPrice, fillPrice decimal.Decimal) float64 {
+	if !signalPrice.IsPositive() {
+		return 0
+	}
+	diff := fillPrice.Sub(signalPrice)
+	if side == executor.OrderSideSell {
+		diff = diff.Neg()
+	}
+	return diff.Div(signalPrice).Mul(decimal.NewFromInt(10000)).InexactFloat64()
+}
+
+// journalIndicatorNames 所有交易出现过的指标名（排序），CSV 每个指标一列
+func journalIndicatorNames(entries []JournalEntry) []string {
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for name := range entry.EntryIndicators {
+			seen[name] = true
+		}
+		for name := range entry.ExitIndicators {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteTradeJournalCSV 以CSV格式写出交易日志，指标列为 entry_<指标名> 和 exit_<指标名>，没有该指标时为空
+func WriteTradeJournalCSV(w io.Writer, entries []JournalEntry) error {
+	names := journalIndicatorNames(entries)
+	header := []string{
+		"no", "quantity",
+		"entry_time", "entry_price", "entry_signal", "entry_reason", "entry_signal_price", "entry_slippage_bps",
+		"exit_time", "exit_price", "exit_signal", "exit_reason", "exit_signal_price", "exit_slippage_bps",
+		"holding_time", "holding_hours", "commission", "pnl", "pnl_percent", "trace_id",
+	}
+	for _, name := range names {
+		header = append(header, "entry_"+name)
+	}
+	for _, name := range names {
+		header = append(header, "exit_"+name)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入CSV表头失败: %w", err)
+	}
+	for _, entry := range entries {
+		record := []string{
+			strconv.Itoa(entry.No),
+			entry.Quantity.String(),
+			entry.EntryTime.UTC().Format(time.RFC3339),
+			entry.EntryPrice.String(),
+			entry.EntrySignal,
+			entry.EntryReason,
+			entry.EntrySignalPrice.String(),
+			formatFloat(entry.EntrySlippageBps),
+			entry.ExitTime.UTC().Format(time.RFC3339),
+			entry.ExitPrice.String(),
+			entry.ExitSignal,
+			entry.ExitReason,
+			entry.ExitSignalPrice.String(),
+			formatFloat(entry.ExitSlippageBps),
+			entry.HoldingTime.String(),
+			formatFloat(entry.HoldingTime.Hours()),
+			entry.Commission.String(),
+			entry.PnL.String(),
+			entry.PnLPercent.StringFixed(4),
+			entry.TraceID,
+		}
+		for _, name := range names {
+			record = append(record, indicatorCell(entry.EntryIndicators, name))
+		}
+		for _, name := range names {
+			record = append(record, indicatorCell(entry.ExitIndicators, name))
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入CSV记录失败: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatFloat 按最短表示输出浮点数
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// indicatorCell 指标值，没有该指标时为空
+func indicatorCell(indicators map[string]float64, name string) string {
+	value, ok := indicators[name]
+	if !ok {
+		return ""
+	}
+	return formatFloat(value)
+}
+
+// WriteTradeJournalJSON 以JSON格式写出交易日志
+func WriteTradeJournalJSON(w io.Writer, entries []JournalEntry) error {
+	if entries == nil {
+		entries = []JournalEntry{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(entries); err != nil {
+		return fmt.Errorf("写入JSON失败: %w", err)
+	}
+	return nil
+}
+
+// ExportTradeJournal 导出交易日志到文件，按扩展名选择格式（.csv 或 .json）
+func ExportTradeJournal(path string, entries []JournalEntry) error {
+	var write func(io.Writer, []JournalEntry) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		write = WriteTradeJournalCSV
+	case ".json":
+		write = WriteTradeJournalJSON
+	default:
+		return fmt.Errorf("unsupported trade journal format: %s (expected .csv or .json)", path)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建交易日志文件失败: %w", err)
+	}
+
+	if err := write(file, entries); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
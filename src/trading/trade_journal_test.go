@@ -0,0 +1,116 @@
+package trading
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journalTrades 一笔信号开平仓的交易、一笔止损平仓的交易和一笔未平仓持仓
+func journalTrades() []TradeAnalysis {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buy := executor.OrderResult{Side: executor.OrderSideBuy, Price: decimal.NewFromInt(101), Timestamp: t0,
+		Signal: &executor.SignalContext{Type: "BUY", Reason: "lower band touch", Price: decimal.NewFromInt(100),
+			Indicators: map[string]float64{"bb_percent_b": -0.05, "bb_width": 0.08}}}
+	sell := executor.OrderResult{Side: executor.OrderSideSell, Price: decimal.NewFromInt(119), Timestamp: t0.Add(26 * time.Hour),
+		Signal: &executor.SignalContext{Type: "SELL", Reason: "upper band touch", Price: decimal.NewFromInt(120),
+			Indicators: map[string]float64{"bb_percent_b": 1.02, "atr": 3}}}
+	stop := executor.OrderResult{Side: executor.OrderSideSell, Price: decimal.NewFromInt(95), Timestamp: t0.Add(48 * time.Hour)}
+
+	return []TradeAnalysis{
+		{BuyOrder: buy, SellOrder: &sell, Quantity: decimal.NewFromInt(2), Duration: 26 * time.Hour,
+			PnL: decimal.NewFromInt(35), PnLPercent: decimal.RequireFromString("17.3267"), Commission: decimal.NewFromInt(1),
+			BuyReason: "strategy signal", SellReason: "strategy signal"},
+		{BuyOrder: buy, SellOrder: &stop, Quantity: decimal.NewFromInt(1), Duration: 48 * time.Hour,
+			PnL: decimal.NewFromInt(-6), BuyReason: "strategy signal", SellReason: "stop loss"},
+		{BuyOrder: buy, Quantity: decimal.NewFromInt(1), IsOpen: true},
+	}
+}
+
+func TestBuildTradeJournal(t *testing.T) {
+	entries := BuildTradeJournal(journalTrades())
+	require.Len(t, entries, 2)
+
+	first := entries[0]
+	assert.Equal(t, 1, first.No)
+	assert.Equal(t, "lower band touch", first.EntryReason)
+	assert.Equal(t, "upper band touch", first.ExitReason)
+	assert.Equal(t, "100", first.EntrySignalPrice.String())
+	// 买入比信号价高 1%、卖出比信号价低 1/120，均为不利滑点
+	assert.InDelta(t, 100, first.EntrySlippageBps, 1e-9)
+	assert.InDelta(t, 83.333, first.ExitSlippageBps, 0.001)
+	assert.Equal(t, -0.05, first.EntryIndicators["bb_percent_b"])
+	assert.Equal(t, 26*time.Hour, first.HoldingTime)
+
+	// 保护单没有信号：原因取交易统计，滑点为 0
+	second := entries[1]
+	assert.Equal(t, "stop loss", second.ExitReason)
+	assert.Empty(t, second.ExitSignal)
+	assert.Zero(t, second.ExitSlippageBps)
+	assert.True(t, second.ExitSignalPrice.IsZero())
+}
+
+func TestWriteTradeJournalCSV(t *testing.T) {
+	var buf bytes.Buffer
+	entries := BuildTradeJournal(journalTrades())
+	entries[0].TraceID = "backtest-20240101T000000Z-abcdef"
+	require.NoError(t, WriteTradeJournalCSV(&buf, entries))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	header := records[0]
+	column := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return -1
+	}
+	// 指标列为所有交易出现过的指标并集
+	assert.Equal(t, []string{"entry_atr", "entry_bb_percent_b", "entry_bb_width", "exit_atr", "exit_bb_percent_b", "exit_bb_width"},
+		header[len(header)-6:])
+
+	row := records[1]
+	assert.Equal(t, "2024-01-01T00:00:00Z", row[column("entry_time")])
+	assert.Equal(t, "26h0m0s", row[column("holding_time")])
+	assert.Equal(t, "26", row[column("holding_hours")])
+	assert.Equal(t, "100", row[column("entry_slippage_bps")])
+	assert.Equal(t, "-0.05", row[column("entry_bb_percent_b")])
+	assert.Equal(t, "", row[column("entry_atr")])
+	assert.Equal(t, "3", row[column("exit_atr")])
+	assert.Equal(t, "stop loss", records[2][column("exit_reason")])
+	assert.Equal(t, "backtest-20240101T000000Z-abcdef", row[column("trace_id")])
+	assert.Equal(t, "", records[2][column("trace_id")])
+}
+
+func TestExportTradeJournal(t *testing.T) {
+	dir := t.TempDir()
+	entries := BuildTradeJournal(journalTrades())
+
+	path := filepath.Join(dir, "journal.json")
+	require.NoError(t, ExportTradeJournal(path, entries))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded []JournalEntry
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, "upper band touch", decoded[0].ExitReason)
+	assert.Equal(t, 1.02, decoded[0].ExitIndicators["bb_percent_b"])
+
+	require.NoError(t, ExportTradeJournal(filepath.Join(dir, "journal.csv"), entries))
+	assert.Error(t, ExportTradeJournal(filepath.Join(dir, "journal.txt"), entries))
+}
@@ -0,0 +1,218 @@
+package trading
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/precision"
+
+	"github.com/shopspring/decimal"
+)
+
+// NumberNotation 交易明细中数量和价格的显示方式
+type NumberNotation string
+
+const (
+	NotationFixed      NumberNotation = "fixed"      // 按步长/最小变动单位的小数位显示（默认）
+	NotationScientific NumberNotation = "scientific" // 科学计数法，如 1.2340e-05
+	NotationCompact    NumberNotation = "compact"    // 大数用 K/M/B/T 后缀，小数用下标表示前导零个数，如 0.0₄1234
+)
+
+// tableSignificantDigits 没有下单规则时按最小值保留的有效数字位数
+const tableSignificantDigits = 6
+
+// maxTableDecimals 显示的最大小数位数
+const maxTableDecimals = 18
+
+// ParseNumberNotation 解析显示方式，空字符串为 fixed
+func ParseNumberNotation(s string) (NumberNotation, error) {
+	switch NumberNotation(strings.ToLower(s)) {
+	case "", NotationFixed:
+		return NotationFixed, nil
+	case NotationScientific:
+		return NotationScientific, nil
+	case NotationCompact:
+		return NotationCompact, nil
+	}
+	return "", fmt.Errorf("unknown notation %q (supported: fixed, scientific, compact)", s)
+}
+
+// TradeTableFormat 交易明细中数量和价格的小数位数和显示方式
+type TradeTableFormat struct {
+	QuantityDecimals int32
+	PriceDecimals    int32
+	Notation         NumberNotation
+}
+
+// NewTradeTableFormat 小数位数取自交易对的数量步长和价格最小变动单位；
+// 没有下单规则（filters 为 nil 或未配置步长）时按数据中最小的非零值保留 6 位有效数字
+func NewTradeTableFormat(filters *cex.SymbolFilters, quantities, prices []decimal.Decimal, notation NumberNotation) TradeTableFormat {
+	format := TradeTableFormat{
+		QuantityDecimals: adaptiveDecimals(quantities),
+		PriceDecimals:    adaptiveDecimals(prices),
+		Notation:         notation,
+	}
+	if filters != nil && filters.StepSize.IsPositive() {
+		format.QuantityDecimals = precision.StepDecimals(filters.StepSize)
+	}
+	if filters != nil && filters.TickSize.IsPositive() {
+		format.PriceDecimals = precision.StepDecimals(filters.TickSize)
+	}
+	return format
+}
+
+// adaptiveDecimals 让最小的非零值也能显示 6 位有效数字所需的小数位数
+func adaptiveDecimals(values []decimal.Decimal) int32 {
+	var decimals int32
+	for _, value := range values {
+		if value.IsZero() {
+			continue
+		}
+		rounded := precision.RoundSignificant(value, tableSignificantDigits)
+		normalized, _ := decimal.NewFromString(rounded.String())
+		decimals = max(decimals, -normalized.Exponent())
+	}
+	return min(decimals, maxTableDecimals)
+}
+
+// Quantity 格式化数量
+func (f TradeTableFormat) Quantity(value decimal.Decimal) string {
+	return formatNumber(value, f.QuantityDecimals, f.Notation)
+}
+
+// Price 格式化价格
+func (f TradeTableFormat) Price(value decimal.Decimal) string {
+	return formatNumber(value, f.PriceDecimals, f.Notation)
+}
+
+// formatNumber 按显示方式格式化；fixed 保留末尾的 0，右对齐时小数点对齐
+func formatNumber(value decimal.Decimal, decimals int32, notation NumberNotation) string {
+	switch notation {
+	case NotationScientific:
+		return fmt.Sprintf("%.4e", value.InexactFloat64())
+	case NotationCompact:
+		return formatCompact(value)
+	}
+	return value.StringFixed(decimals)
+}
+
+// compactSuffixes 大数后缀
+var compactSuffixes = []struct {
+	threshold decimal.Decimal
+	suffix    string
+}{
+	{decimal.New(1, 12), "T"},
+	{decimal.New(1, 9), "B"},
+	{decimal.New(1, 6), "M"},
+	{decimal.New(1, 3), "K"},
+}
+
+// subscriptDigits 下标数字
+var subscriptDigits = []rune("₀₁₂₃₄₅₆₇₈₉")
+
+// formatCompact 紧凑显示：40518638 → 40.52M，0.00001234 → 0.0₄1234（小数点后 4 个 0），其余保留 4 位有效数字
+func formatCompact(value decimal.Decimal) string {
+	sign := ""
+	if value.IsNegative() {
+		sign = "-"
+		value = value.Abs()
+	}
+
+	for _, s := range compactSuffixes {
+		if value.GreaterThanOrEqual(s.threshold) {
+			return sign + precision.Format(value.Div(s.threshold), 2) + s.suffix
+		}
+	}
+
+	formatted := precision.FormatSignificant(value, 4)
+	zeros := 0
+	if strings.HasPrefix(formatted, "0.") {
+		zeros = len(formatted) - len(strings.TrimLeft(formatted[2:], "0")) - 2
+	}
+	if zeros < 3 {
+		return sign + formatted
+	}
+	var subscript strings.Builder
+	for _, digit := range fmt.Sprint(zeros) {
+		subscript.WriteRune(subscriptDigits[digit-'0'])
+	}
+	return sign + "0.0" + subscript.String() + formatted[2+zeros:]
+}
+
+// TableColumn 表格列：数值列右对齐
+type TableColumn struct {
+	Header string
+	Right  bool
+}
+
+// displayWidth 终端显示宽度：中日韩文字和全角字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana) || (r >= 0xFF01 && r <= 0xFF60) || (r >= 0x3000 && r <= 0x303F) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// padRight 按显示宽度在右侧补空格
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-displayWidth(s), 0))
+}
+
+// padLeft 按显示宽度在左侧补空格
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-displayWidth(s), 0)) + s
+}
+
+// WriteTable 按每列最长的内容对齐输出表格（宽度按终端显示宽度计算）
+func WriteTable(w io.Writer, columns []TableColumn, rows [][]string) {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = displayWidth(column.Header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], displayWidth(cell))
+			}
+		}
+	}
+
+	writeRow := func(cells []string) {
+		parts := make([]string, len(columns))
+		for i, column := range columns {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			if column.Right {
+				parts[i] = padLeft(cell, widths[i])
+			} else {
+				parts[i] = padRight(cell, widths[i])
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, "  "), " "))
+	}
+
+	total := 2 * (len(columns) - 1)
+	for _, width := range widths {
+		total += width
+	}
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	fmt.Fprintln(w, strings.Repeat("=", total))
+	writeRow(headers)
+	fmt.Fprintln(w, strings.Repeat("=", total))
+	for _, row := range rows {
+		writeRow(row)
+	}
+}
@@ -0,0 +1,99 @@
+package trading
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"tradingbot/src/cex"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTradeTableFormat(t *testing.T) {
+	quantities := []decimal.Decimal{decimal.RequireFromString("40518638.5737"), decimal.RequireFromString("2000000")}
+	prices := []decimal.Decimal{decimal.RequireFromString("0.0000123456789"), decimal.RequireFromString("0.00001301")}
+
+	// 没有下单规则：最小的非零值保留 6 位有效数字
+	format := NewTradeTableFormat(nil, quantities, prices, NotationFixed)
+	assert.Equal(t, int32(0), format.QuantityDecimals)
+	assert.Equal(t, int32(10), format.PriceDecimals)
+	assert.Equal(t, "40518639", format.Quantity(quantities[0]))
+	assert.Equal(t, "0.0000123457", format.Price(prices[0]))
+	assert.Equal(t, "0.0000130100", format.Price(prices[1]))
+
+	// 有下单规则：按步长和最小变动单位
+	filters := &cex.SymbolFilters{StepSize: decimal.RequireFromString("1.00000000"), TickSize: decimal.RequireFromString("0.00000001")}
+	format = NewTradeTableFormat(filters, quantities, prices, NotationFixed)
+	assert.Equal(t, int32(0), format.QuantityDecimals)
+	assert.Equal(t, int32(8), format.PriceDecimals)
+	assert.Equal(t, "0.00001235", format.Price(prices[0]))
+}
+
+func TestParseNumberNotation(t *testing.T) {
+	notation, err := ParseNumberNotation("")
+	require.NoError(t, err)
+	assert.Equal(t, NotationFixed, notation)
+
+	notation, err = ParseNumberNotation("Compact")
+	require.NoError(t, err)
+	assert.Equal(t, NotationCompact, notation)
+
+	_, err = ParseNumberNotation("hex")
+	assert.Error(t, err)
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		value      string
+		scientific string
+		compact    string
+	}{
+		{"40518638.5737", "4.0519e+07", "40.52M"},
+		{"1234.5", "1.2345e+03", "1.23K"},
+		{"0.0000123456789", "1.2346e-05", "0.0₄1235"},
+		{"0.00012", "1.2000e-04", "0.0₃12"},
+		{"0.0123", "1.2300e-02", "0.0123"},
+		{"-0.000000001", "-1.0000e-09", "-0.0₈1"},
+		{"0", "0.0000e+00", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			value := decimal.RequireFromString(tt.value)
+			assert.Equal(t, tt.scientific, formatNumber(value, 8, NotationScientific))
+			assert.Equal(t, tt.compact, formatNumber(value, 8, NotationCompact))
+		})
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	WriteTable(&buf, []TableColumn{{Header: "Side"}, {Header: "Price", Right: true}}, [][]string{
+		{"BUY", "0.00001234"},
+		{"SELL", "65000.5"},
+		{"BUY", "0.0₄1234"},
+	})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, "================", lines[0])
+	assert.Equal(t, "Side       Price", lines[1])
+	assert.Equal(t, "BUY   0.00001234", lines[3])
+	assert.Equal(t, "SELL     65000.5", lines[4])
+	// 下标数字按一个字符计算宽度
+	assert.Equal(t, "BUY     0.0₄1234", lines[5])
+}
+
+func TestWriteTable_WideCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	WriteTable(&buf, []TableColumn{{Header: "时间"}, {Header: "价格", Right: true}}, [][]string{{"01-02 15:04", "1.5"}})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	// 中文占两列，表头和数据按终端显示宽度对齐
+	assert.Equal(t, "时间         价格", lines[1])
+	assert.Equal(t, "01-02 15:04   1.5", lines[3])
+	assert.Equal(t, 17, displayWidth(lines[1]))
+}
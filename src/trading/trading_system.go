@@ -4,17 +4,24 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
 	"tradingbot/src/engine"
 	"tradingbot/src/executor"
+	"tradingbot/src/i18n"
+	"tradingbot/src/logging"
+	"tradingbot/src/safety"
 	"tradingbot/src/strategies"
 	"tradingbot/src/strategy"
 	"tradingbot/src/timeframes"
 
 	"github.com/shopspring/decimal"
+	"github.com/xpwu/go-log/log"
 )
 
 // parseFlexibleDateTime 解析灵活的日期时间格式
@@ -40,15 +47,37 @@ func parseFlexibleDateTime(dateStr string) (time.Time, error) {
 
 // TradingSystem 交易系统（重构版）
 type TradingSystem struct {
-	cexClient     cex.CEXClient
-	tradingEngine *engine.TradingEngine
-	ctx           context.Context
-	cancel        context.CancelFunc
+	cexClient       cex.CEXClient
+	tradingEngine   *engine.TradingEngine
+	calendar        *engine.TradingCalendar // 当前交易对的交易日历
+	cexName         string
+	timeframe       string               // K线周期（为空时使用配置 Timeframe）
+	rateLimiter     *cex.RateLimiter     // 多个交易系统共享的交易所请求限频器（为空时不限频）
+	live            *dashboard.LiveState // 多机器人模式下由管理器提供的实盘状态，设置后不单独启动面板
+	paperSession    string               // Dry Run 模拟盘会话名（为空时使用默认会话）
+	paperCapital    float64              // 新建模拟盘会话（或只发信号模式假想持仓）的初始资金
+	signalOnly      bool                 // 只发信号：实时行情和策略信号照常，不下单
+	tradingVerified bool                 // ARM 确认前已检查过交易权限，启动时不再重复查询
+	resultFile      string               // 回测结果文件（为空时不写出）
+	traceID         string               // 本次运行的跟踪ID（日志前缀 trace=<id>）
+	tui             bool                 // 实时运行时显示终端界面
+	stopTUI         func()               // 关闭终端界面并恢复日志输出（界面未启动时为空）
+	futuresMu       sync.Mutex
+	futuresContexts map[cex.TradingPair]*strategy.FuturesContext // 回测按交易对缓存的合约辅助数据
+	shutdownMu      sync.Mutex
+	shutdownReason  string // 停止请求的原因（如收到的退出信号）
+	ctx             context.Context
+	cancel          context.CancelFunc
 }
 
 // NewTradingSystem 创建新的交易系统
 func NewTradingSystem() (*TradingSystem, error) {
-	ctx, cancel := context.WithCancel(context.Background())
+	return NewTradingSystemWithContext(context.Background())
+}
+
+// NewTradingSystemWithContext 创建交易系统，parent 取消时交易系统随之停止，日志沿用 parent 中的前缀
+func NewTradingSystemWithContext(parent context.Context) (*TradingSystem, error) {
+	ctx, cancel := context.WithCancel(parent)
 
 	return &TradingSystem{
 		ctx:    ctx,
@@ -66,20 +95,31 @@ func CreateTradingPair(base, quote string) cex.TradingPair {
 
 // SetTradingPairTimeframeAndCEX 设置交易对、时间周期和交易所
 func (ts *TradingSystem) SetTradingPairTimeframeAndCEX(pair cex.TradingPair, timeframe, cexName string) error {
+	_, logger := log.WithCtx(ts.ctx)
+
 	// 验证时间周期格式
 	_, err := timeframes.ParseTimeframe(timeframe)
 	if err != nil {
 		return fmt.Errorf("invalid timeframe: %s", timeframe)
 	}
 
-	// 设置时间周期到交易配置
-	TradingConfigValue.Timeframe = timeframe
+	ts.timeframe = timeframe
 
 	// 初始化 CEX 客户端
 	if err := ts.initializeCEX(cexName); err != nil {
 		return fmt.Errorf("failed to initialize CEX: %w", err)
 	}
 
+	// 加载交易日历（禁止交易时段）
+	calendar, err := ts.loadTradingCalendar(ts.ctx, pair)
+	if err != nil {
+		return fmt.Errorf("failed to load trading calendar: %w", err)
+	}
+	ts.calendar = calendar
+	if !calendar.IsEmpty() {
+		logger.Info(fmt.Sprintf("📅 交易日历: symbol=%s, no_trade_windows=%d", pair.String(), calendar.WindowCount()))
+	}
+
 	return nil
 }
 
@@ -92,10 +132,53 @@ func (ts *TradingSystem) initializeCEX(cexName string) error {
 	}
 
 	ts.cexClient = client
+	ts.cexName = cexName
+	if cex.IsTestnet(client) {
+		// 测试网运行时所有日志都带 TESTNET 前缀，避免与实盘混淆
+		ctx, logger := log.WithCtx(ts.ctx)
+		logger.PushPrefix("TESTNET")
+		ts.ctx = ctx
+	}
+	if limitedClient, ok := client.(cex.RateLimitedClient); ok && ts.rateLimiter != nil {
+		limitedClient.SetRateLimiter(ts.rateLimiter)
+	}
 
 	return nil
 }
 
+// Testnet 是否连接交易所测试网
+func (ts *TradingSystem) Testnet() bool {
+	return ts.cexClient != nil && cex.IsTestnet(ts.cexClient)
+}
+
+// Timeframe 当前K线周期
+func (ts *TradingSystem) Timeframe() string {
+	if ts.timeframe == "" {
+		return TradingConfigValue.Timeframe
+	}
+	return ts.timeframe
+}
+
+// SetRateLimiter 设置交易所请求限频器（多个交易系统共用同一交易所账户时共享），需在设置交易所之前调用
+func (ts *TradingSystem) SetRateLimiter(limiter *cex.RateLimiter) {
+	ts.rateLimiter = limiter
+}
+
+// SetLiveState 设置实盘状态（多机器人模式由管理器统一提供面板），设置后实盘运行时不再单独启动面板
+func (ts *TradingSystem) SetLiveState(live *dashboard.LiveState) {
+	ts.live = live
+}
+
+// SetSignalOnly 设置只发信号模式：实盘运行时只把策略信号（含指标快照）发布到通知和面板，不下单（优先于 Dry Run）
+func (ts *TradingSystem) SetSignalOnly(enabled bool) {
+	ts.signalOnly = enabled
+}
+
+// SetTUI 实时运行（实盘、Dry Run、只发信号）时显示终端界面
+func (ts *TradingSystem) SetTUI(enabled bool) {
+	ts.tui = enabled
+}
+
 // RunBacktestWithParamsAndCapital 使用指定策略参数和初始资金运行回测
 func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, startDate, endDate string, initialCapital float64, strategyParams strategy.StrategyParams) (*BacktestStatistics, error) {
 
@@ -104,10 +187,8 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 		return nil, fmt.Errorf("CEX client not initialized")
 	}
 
-	fmt.Println("🔄 Starting backtest...")
-
-	// 创建策略（目前只支持布林道策略）
-	strategyImpl := strategies.NewBollingerBandsStrategy()
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("🔄 开始回测: symbol=%s", pair.String()))
 
 	// 使用传入的参数或默认参数
 	var params strategy.StrategyParams
@@ -123,45 +204,206 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 		return nil, fmt.Errorf("invalid strategy parameters: %w", err)
 	}
 
-	err := strategyImpl.SetParams(params)
-	if err != nil {
+	// 创建策略（布林道策略）
+	strategyImpl := strategies.NewBollingerBandsStrategy()
+	if err := strategyImpl.SetParams(params); err != nil {
 		return nil, fmt.Errorf("failed to set strategy parameters: %w", err)
 	}
-	fmt.Printf("✓ Initialized %s with params: %+v\n", strategyImpl.GetName(), strategyImpl.GetParams())
 
-	// 创建回测执行器
-	initialCapitalDecimal := decimal.NewFromFloat(initialCapital)
-	orderStrategy := executor.NewBacktestOrderStrategy(pair)
-	backtestExecutor := executor.NewTradingExecutor(pair, initialCapitalDecimal)
-	backtestExecutor.SetOrderStrategy(orderStrategy)
+	return ts.runBacktest(pair, startDate, endDate, initialCapital, strategyImpl, params)
+}
+
+// RunBacktestWithStrategy 使用任意已设置好参数的策略运行回测
+func (ts *TradingSystem) RunBacktestWithStrategy(pair cex.TradingPair, startDate, endDate string, initialCapital float64, strategyImpl strategy.Strategy) (*BacktestStatistics, error) {
+	if ts.cexClient == nil {
+		return nil, fmt.Errorf("CEX client not initialized")
+	}
+
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("🔄 开始回测: symbol=%s", pair.String()))
+
+	params := strategyImpl.GetParams()
+	if params != nil {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid strategy parameters: %w", err)
+		}
+	}
+
+	return ts.runBacktest(pair, startDate, endDate, initialCapital, strategyImpl, params)
+}
+
+// runBacktest 加载历史数据并运行一次回测
+func (ts *TradingSystem) runBacktest(pair cex.TradingPair, startDate, endDate string, initialCapital float64, strategyImpl strategy.Strategy, params strategy.StrategyParams) (*BacktestStatistics, error) {
+	ts.startTrace(logging.TraceKindBacktest)
+	_, logger := log.WithCtx(ts.ctx)
 
 	// 获取时间周期
-	timeframe, err := timeframes.ParseTimeframe(TradingConfigValue.Timeframe)
+	timeframe, err := timeframes.ParseTimeframe(ts.Timeframe())
 	if err != nil {
 		return nil, fmt.Errorf("invalid timeframe: %w", err)
 	}
 
 	// 解析时间范围（支持多种格式）
+	startTime, endTime, err := ParseBacktestRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🔄 获取历史数据用于回测（流式回测从数据库分批读取，不加载全部K线）
+	var dataFeed engine.DataFeed
+	var stream *KlineStream
+	if TradingConfigValue.Backtest.StreamKlines {
+		stream, err = ts.newBacktestKlineStream(pair, timeframe, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		dataFeed = NewDatabaseDataFeed(stream)
+		logger.Info(fmt.Sprintf("📊 流式读取历史数据: symbol=%s, timeframe=%s, batch_size=%d（跳过回测配置检查）",
+			pair.String(), timeframe.String(), stream.batchSize))
+	} else {
+		logger.Info(fmt.Sprintf("📊 加载历史数据: symbol=%s, timeframe=%s", pair.String(), timeframe.String()))
+		klines, err := ts.LoadBacktestKlines(pair, timeframe, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		// ⚠️ 回测前检查配置是否明显不现实（只警告，不阻止回测）
+		if bollingerParams, ok := params.(*strategy.BollingerBandsParams); ok {
+			PrintGuardrailWarnings(CheckBacktestGuardrails(GuardrailInput{
+				Params:         bollingerParams,
+				InitialCapital: initialCapital,
+				Timeframe:      timeframe,
+				StartTime:      startTime,
+				EndTime:        endTime,
+				Klines:         klines,
+				FeeRate:        ts.tradingFee(),
+			}))
+		}
+		dataFeed = engine.NewBacktestDataFeed(klines)
+	}
+
+	// 🎯 创建回测引擎
+	backtestEngine, backtestExecutor, err := ts.newBacktestEngineWithStrategy(pair, timeframe, dataFeed, initialCapital, strategyImpl)
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		backtestEngine.engine.SetKlineWindow(streamingKlineWindow)
+	}
+	logger.Info(fmt.Sprintf("✓ 策略已初始化: strategy=%s, params=%+v", backtestEngine.strategyName, params))
+	ts.tradingEngine = backtestEngine.engine
+	if err := ts.setupBacktestCheckpoint(ts.tradingEngine, pair, timeframe, backtestEngine.strategyName, params, startTime, endTime, initialCapital); err != nil {
+		return nil, err
+	}
+
+	// 🚀 运行统一的tick-by-tick回测
+	logger.Info(fmt.Sprintf("🎮 开始逐K线回测: symbol=%s", pair.String()))
+	err = ts.tradingEngine.RunBacktest(ts.ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("backtest failed: %w", err)
+	}
+
+	logger.Info(fmt.Sprintf("✅ 回测完成: symbol=%s", pair.String()))
+
+	// 📐 逐根K线累计夏普比率和收益归因（流式回测再从数据库读一遍，成交已全部确定），回撤由引擎逐根K线跟踪
+	klineStats := newExecutorKlineStats(backtestExecutor)
+	if stream != nil {
+		if err := dataFeed.(*DatabaseDataFeed).Err(); err != nil {
+			return nil, fmt.Errorf("backtest failed: %w", err)
+		}
+		if err := stream.ForEach(ts.ctx, klineStats.Add); err != nil {
+			return nil, fmt.Errorf("failed to calculate backtest statistics: %w", err)
+		}
+	} else {
+		for _, kline := range ts.tradingEngine.GetKlines() {
+			klineStats.Add(kline)
+		}
+	}
+
+	if ts.ctx.Err() == nil {
+		ts.removeBacktestCheckpoint()
+	}
+
+	result := buildBacktestStatistics(backtestExecutor, klineStats, ts.tradingEngine.GetDrawdown(), ts.tradingEngine.GetEquityCurve(), backtestEngine.lotMatching, timeframe, startTime, endTime)
+	result.StrategyName = backtestEngine.strategyName
+	result.TraceID = ts.traceID
+	result.Manifest, err = ts.newRunManifest(pair, timeframe, backtestEngine.strategyName, params, klineStats.Fingerprint(), startTime, endTime, initialCapital)
+	if err != nil {
+		return nil, err
+	}
+
+	// 💱 换算为记账货币（计价货币不是记账货币时使用换算交易对的K线）
+	result.Accounting, err = ts.convertToAccountingCurrency(pair, result, ts.tradingEngine.GetEquityCurve(), startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// 💾 持久化回测结果（失败不影响回测本身）
+	if TradingConfigValue.SaveBacktest {
+		runID, err := ts.SaveBacktestResults(pair, backtestEngine.strategyName, params, startTime, endTime, result)
+		if err != nil {
+			logger.Warning(fmt.Sprintf("⚠️ 保存回测结果失败: symbol=%s, error=%v", pair.String(), err))
+		} else {
+			result.RunID = runID
+			logger.Info(fmt.Sprintf("💾 回测结果已保存: symbol=%s, run_id=%s", pair.String(), runID))
+		}
+	}
+
+	// 📄 写出回测结果文件（供 backtests compare 对比）
+	if ts.resultFile != "" {
+		file, err := newBacktestResult(pair, ts.Timeframe(), backtestEngine.strategyName, params, startTime, endTime, result, ts.GetEquityCurve())
+		if err != nil {
+			return nil, err
+		}
+		if err := WriteBacktestResultFile(ts.resultFile, file); err != nil {
+			return nil, err
+		}
+		logger.Info(fmt.Sprintf("📄 回测结果文件已写出: symbol=%s, file=%s", pair.String(), ts.resultFile))
+	}
+
+	return result, nil
+}
+
+// GetEquityCurve 获取最近一次运行的资金曲线
+func (ts *TradingSystem) GetEquityCurve() []engine.EquityPoint {
+	if ts.tradingEngine == nil {
+		return nil
+	}
+	return ts.tradingEngine.GetEquityCurve()
+}
+
+// ParseBacktestRange 解析回测起止时间（支持多种格式）
+func ParseBacktestRange(startDate, endDate string) (time.Time, time.Time, error) {
 	startTime, err := parseFlexibleDateTime(startDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start date format: %w", err)
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format: %w", err)
 	}
 
 	endTime, err := parseFlexibleDateTime(endDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid end date format: %w", err)
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format: %w", err)
 	}
 
-	// 🔄 获取历史数据用于回测
-	fmt.Println("📊 Loading historical data...")
+	return startTime, endTime, nil
+}
 
-	// 计算实际需要的开始时间（为了获取足够的历史数据计算指标）
-	timeframeDuration, _ := timeframe.GetDuration()
-	// 向前推30个时间周期以确保有足够的数据计算布林带
-	actualStartTime := startTime.Add(-30 * timeframeDuration)
+// LoadBacktestKlines 加载回测所需的历史K线（向前多取数据用于指标预热）
+func (ts *TradingSystem) LoadBacktestKlines(pair cex.TradingPair, timeframe timeframes.Timeframe, startTime, endTime time.Time) ([]*cex.KlineData, error) {
+	if ts.cexClient == nil {
+		return nil, fmt.Errorf("CEX client not initialized")
+	}
+
+	actualStartTime := backtestWarmupStart(timeframe, startTime)
 
-	klines, err := ts.cexClient.GetKlinesWithTimeRange(ts.ctx, pair, timeframe.GetBinanceInterval(),
-		actualStartTime, endTime, 1000)
+	var klines []*cex.KlineData
+	var err error
+	if db, dbErr := GetPostgresDB(ts.cexClient); dbErr == nil {
+		// 数据库可用时先读缓存，只请求缺失区间
+		klines, err = NewCachedKlineProvider(ts.cexClient, db).GetKlinesWithTimeRange(ts.ctx, pair, timeframe, actualStartTime, endTime)
+	} else {
+		klines, err = ts.cexClient.GetKlinesWithTimeRange(ts.ctx, pair, timeframe.GetBinanceInterval(),
+			actualStartTime, endTime, 1000)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load historical data: %w", err)
 	}
@@ -170,18 +412,166 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 		return nil, fmt.Errorf("no historical data available for the requested time range. Check if the start time is not too recent")
 	}
 
-	fmt.Printf("✓ Loaded %d klines for %s (from %s to %s)\n",
-		len(klines), pair.String(),
-		actualStartTime.Format("01-02 15:04"), endTime.Format("01-02 15:04"))
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 历史K线已加载: symbol=%s, klines=%d, from=%s, to=%s",
+		pair.String(), len(klines),
+		actualStartTime.Format("2006-01-02T15:04"), endTime.Format("2006-01-02T15:04")))
+
+	return klines, nil
+}
+
+// backtestWarmupStart 计算实际需要的开始时间（为了获取足够的历史数据计算指标）
+func backtestWarmupStart(timeframe timeframes.Timeframe, startTime time.Time) time.Time {
+	timeframeDuration, _ := timeframe.GetDuration()
+	// 向前推30个时间周期以确保有足够的数据计算布林带
+	return startTime.Add(-30 * timeframeDuration)
+}
+
+// streamingKlineWindow 流式回测时引擎保留的最近K线数量（ATR止损、波动率仓位的回看长度远小于此）
+const streamingKlineWindow = 1000
+
+// newBacktestKlineStream 创建从数据库流式读取回测K线（含指标预热部分）的K线流，数据库中没有该区间的K线时报错
+func (ts *TradingSystem) newBacktestKlineStream(pair cex.TradingPair, timeframe timeframes.Timeframe, startTime, endTime time.Time) (*KlineStream, error) {
+	db, err := GetPostgresDB(ts.cexClient)
+	if err != nil {
+		return nil, fmt.Errorf("streaming backtest requires the kline database: %w", err)
+	}
+
+	stream := NewKlineStream(db, pair, timeframe, backtestWarmupStart(timeframe, startTime), endTime, TradingConfigValue.Backtest.StreamBatchSize)
+	first, err := db.GetKlines(ts.ctx, DatabaseSymbol(pair), timeframe.String(), stream.startTime.UnixMilli(), endTime.UnixMilli(), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read klines from database: %w", err)
+	}
+	if len(first) == 0 {
+		return nil, fmt.Errorf("no klines in database for %s %s, run 'sync' first or disable stream_klines", pair.String(), timeframe.String())
+	}
+	return stream, nil
+}
+
+// backtestEngine 回测引擎及其策略名称
+type backtestEngine struct {
+	engine       *engine.TradingEngine
+	strategyName string
+	lotMatching  string // 交易分析的持仓批次匹配方式
+}
+
+// tradingFee 交易所单边 taker 费率，未连接交易所时返回 0（使用默认值）
+func (ts *TradingSystem) tradingFee() float64 {
+	if ts.cexClient == nil {
+		return 0
+	}
+	return ts.cexClient.GetTradingFee()
+}
 
-	// 🎯 创建回测数据喂入器
-	dataFeed := engine.NewBacktestDataFeed(klines)
+// TakerFeeRate 回测按收盘价成交使用的吃单费率：交易所配置了手续费表时取其基础等级（含 BNB 抵扣），否则同 tradingFee
+func (ts *TradingSystem) TakerFeeRate() (float64, error) {
+	provider, ok := ts.cexClient.(cex.FeeScheduleProvider)
+	if !ok {
+		return ts.tradingFee(), nil
+	}
+	schedule := provider.GetFeeSchedule()
+	if err := schedule.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid fee schedule for %s: %w", ts.cexClient.GetName(), err)
+	}
+	return schedule.Rate(false, 0), nil
+}
 
-	// 🎯 创建回测挂单管理器
+// feeScheduleSetter 支持设置手续费表的执行器
+type feeScheduleSetter interface {
+	SetFeeSchedule(schedule cex.FeeSchedule)
+}
+
+// applyFeeSchedule 使用交易所配置的手续费表（回测和实盘按同一规则扣手续费）
+func (ts *TradingSystem) applyFeeSchedule(tradingExecutor feeScheduleSetter) error {
+	provider, ok := ts.cexClient.(cex.FeeScheduleProvider)
+	if !ok {
+		return nil
+	}
+	schedule := provider.GetFeeSchedule()
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid fee schedule for %s: %w", ts.cexClient.GetName(), err)
+	}
+	tradingExecutor.SetFeeSchedule(schedule)
+	return nil
+}
+
+// newBacktestEngine 基于给定K线创建独立的回测引擎（不共享状态，可并发运行）
+func (ts *TradingSystem) newBacktestEngine(pair cex.TradingPair, timeframe timeframes.Timeframe, klines []*cex.KlineData, initialCapital float64, params strategy.StrategyParams) (*backtestEngine, *executor.TradingExecutor, error) {
+	// 创建策略（目前只支持布林道策略）
+	strategyImpl := strategies.NewBollingerBandsStrategy()
+	if err := strategyImpl.SetParams(params); err != nil {
+		return nil, nil, fmt.Errorf("failed to set strategy parameters: %w", err)
+	}
+
+	return ts.newBacktestEngineWithStrategy(pair, timeframe, engine.NewBacktestDataFeed(klines), initialCapital, strategyImpl)
+}
+
+// newBacktestEngineWithStrategy 使用给定策略实例和数据喂入器创建回测引擎
+func (ts *TradingSystem) newBacktestEngineWithStrategy(pair cex.TradingPair, timeframe timeframes.Timeframe, dataFeed engine.DataFeed, initialCapital float64, strategyImpl strategy.Strategy) (*backtestEngine, *executor.TradingExecutor, error) {
+	// 资金费率等合约辅助数据（策略启用相关过滤时从数据库加载）
+	if err := ts.attachBacktestFuturesContext(strategyImpl, pair); err != nil {
+		return nil, nil, err
+	}
+
+	// 创建回测执行器
+	initialCapitalDecimal := decimal.NewFromFloat(initialCapital)
+	orderStrategy := executor.NewBacktestOrderStrategy(pair)
+	backtestExecutor := executor.NewTradingExecutor(pair, initialCapitalDecimal)
+	backtestExecutor.SetOrderStrategy(orderStrategy)
+	if err := ts.applyFeeSchedule(backtestExecutor); err != nil {
+		return nil, nil, err
+	}
+
+	// 🎯 创建回测挂单管理器（交易暂停时段不模拟成交）
 	orderManager := engine.NewBacktestOrderManager(backtestExecutor)
+	orderManager.SetTradingCalendar(ts.calendar)
+
+	// 成交模型：固定滑点、成交量上限/部分成交、低流动性币种的成交概率和滑点
+	fillModel, err := TradingConfigValue.NewFillModel()
+	if err != nil {
+		return nil, nil, err
+	}
+	// 盘口价差：按实盘记录的买一卖一模拟吃单成本
+	spreadModel, err := ts.newSpreadFillModel(pair)
+	if err != nil {
+		return nil, nil, err
+	}
+	if spreadModel != nil {
+		if fillModel != nil {
+			fillModel = engine.NewChainFillModel(fillModel, spreadModel)
+		} else {
+			fillModel = spreadModel
+		}
+	}
+	if fillModel != nil {
+		orderManager.SetFillModel(fillModel)
+	}
+
+	// K线内细粒度行情：同一根K线内触发的挂单按实际时间顺序成交
+	intrabarSource, err := ts.newIntrabarSource(pair, timeframe)
+	if err != nil {
+		return nil, nil, err
+	}
+	if intrabarSource != nil {
+		orderManager.SetIntrabarSource(intrabarSource)
+	}
+
+	lotMatching := TradingConfigValue.Backtest.LotMatching
+	if err := ValidateLotMatching(lotMatching); err != nil {
+		return nil, nil, fmt.Errorf("invalid backtest config: %w", err)
+	}
+
+	// 资金成本：每根K线按闲置现金和持仓市值计提
+	if err := TradingConfigValue.Backtest.CarryingCost.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid backtest config: %w", err)
+	}
+	backtestExecutor.SetCarryingCost(TradingConfigValue.Backtest.CarryingCost)
+	if err := TradingConfigValue.Backtest.Regimes.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid backtest config: %w", err)
+	}
 
 	// 创建交易引擎
-	ts.tradingEngine = engine.NewTradingEngine(
+	tradingEngine := engine.NewTradingEngine(
 		pair,
 		timeframe,
 		strategyImpl,
@@ -192,29 +582,104 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 	)
 
 	// 设置交易参数
-	ts.tradingEngine.SetPositionSizePercent(TradingConfigValue.PositionSizePercent)
-	ts.tradingEngine.SetMinTradeAmount(TradingConfigValue.MinTradeAmount)
+	sizer, err := TradingConfigValue.PositionSizing.NewPositionSizer(TradingConfigValue.PositionSizePercent)
+	if err != nil {
+		return nil, nil, err
+	}
+	tradingEngine.SetPositionSizePercent(TradingConfigValue.PositionSizePercent)
+	tradingEngine.SetPositionSizer(sizer)
+	tradingEngine.SetMinTradeAmount(TradingConfigValue.MinTradeAmount)
+	tradingEngine.SetTradingCalendar(ts.calendar)
+	if err := TradingConfigValue.ApplyTimeInForce(tradingEngine); err != nil {
+		return nil, nil, err
+	}
+	if err := TradingConfigValue.OrderChase.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid order chase config: %w", err)
+	}
+	tradingEngine.SetOrderChasePolicy(TradingConfigValue.OrderChase)
+	if err := TradingConfigValue.SignalThrottle.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid signal throttle config: %w", err)
+	}
+	tradingEngine.SetSignalThrottle(TradingConfigValue.SignalThrottle)
 
-	// 🚀 运行统一的tick-by-tick回测
-	fmt.Println("🎮 Starting tick-by-tick backtest simulation...")
-	err = ts.tradingEngine.RunBacktest(ts.ctx, startTime, endTime)
+	riskManager, err := TradingConfigValue.NewRiskManager()
+	if err != nil {
+		return nil, nil, err
+	}
+	if riskManager != nil {
+		tradingEngine.SetRiskManager(riskManager)
+	}
+
+	return &backtestEngine{engine: tradingEngine, strategyName: strategyImpl.GetName(), lotMatching: lotMatching}, backtestExecutor, nil
+}
+
+// RunBacktestOnKlines 在已加载的K线上运行一次独立回测（不修改交易系统状态，供参数优化等并发调用）
+func (ts *TradingSystem) RunBacktestOnKlines(ctx context.Context, pair cex.TradingPair, timeframe timeframes.Timeframe, klines []*cex.KlineData, startTime, endTime time.Time, initialCapital float64, params strategy.StrategyParams) (*BacktestStatistics, error) {
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid strategy parameters: %w", err)
+	}
+
+	backtestEngine, backtestExecutor, err := ts.newBacktestEngine(pair, timeframe, klines, initialCapital, params)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := backtestEngine.engine.RunBacktest(ctx, startTime, endTime); err != nil {
 		return nil, fmt.Errorf("backtest failed: %w", err)
 	}
 
-	fmt.Println("✅ Backtest completed")
+	klineStats := newExecutorKlineStats(backtestExecutor)
+	for _, kline := range backtestEngine.engine.GetKlines() {
+		klineStats.Add(kline)
+	}
+	result := buildBacktestStatistics(backtestExecutor, klineStats, backtestEngine.engine.GetDrawdown(), backtestEngine.engine.GetEquityCurve(), backtestEngine.lotMatching, timeframe, startTime, endTime)
+	result.Manifest, err = ts.newRunManifest(pair, timeframe, backtestEngine.strategyName, params, NewDataFingerprint(klines), startTime, endTime, initialCapital)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newExecutorKlineStats 以执行器的成交和初始资金创建K线统计累计器
+func newExecutorKlineStats(backtestExecutor *executor.TradingExecutor) *KlineStats {
+	klineStats := NewKlineStats(backtestExecutor.GetOrders(), backtestExecutor.GetStatistics()["initial_capital"].(decimal.Decimal))
+	if TradingConfigValue.Backtest.Regimes.Enabled {
+		klineStats.EnableRegimes(TradingConfigValue.Backtest.Regimes)
+	}
+	return klineStats
+}
 
+// buildBacktestStatistics 根据执行器、逐根K线累计的统计、引擎跟踪的回撤和资金曲线生成回测统计，交易按 lotMatching 匹配持仓批次
+func buildBacktestStatistics(backtestExecutor *executor.TradingExecutor, klineStats *KlineStats, drawdownInfo DrawdownInfo, equity []engine.EquityPoint, lotMatching string, timeframe timeframes.Timeframe, startTime, endTime time.Time) *BacktestStatistics {
 	// 获取回测统计
 	stats := backtestExecutor.GetStatistics()
 	orders := backtestExecutor.GetOrders()
 
 	// 进行详细交易分析
-	trades, openPositions, avgHoldingTime, maxHoldingTime, minHoldingTime, avgWinningPnL, avgLosingPnL, maxWin, maxLoss, profitFactor := AnalyzeTrades(orders)
+	trades, openPositions, avgHoldingTime, maxHoldingTime, minHoldingTime, avgWinningPnL, avgLosingPnL, maxWin, maxLoss, profitFactor := AnalyzeTradesWithLotMatching(orders, lotMatching)
+
+	// 期末按最后一根K线收盘价估值未平仓持仓（执行器的组合价值按最后成交价计算）
+	initialCapital := stats["initial_capital"].(decimal.Decimal)
+	finalPortfolio := stats["final_portfolio"].(decimal.Decimal)
+	totalReturn := stats["total_return"].(decimal.Decimal)
+	unrealizedPnL, openPositionValue := decimal.Zero, decimal.Zero
+	if position := stats["position"].(decimal.Decimal); position.IsPositive() && klineStats.LastKline() != nil {
+		markPrice := klineStats.LastKline().Close
+		unrealizedPnL = valueOpenPositions(openPositions, markPrice)
+		openPositionValue = position.Mul(markPrice)
+		finalPortfolio = stats["cash"].(decimal.Decimal).Add(openPositionValue)
+		if initialCapital.IsPositive() {
+			totalReturn = finalPortfolio.Sub(initialCapital).Div(initialCapital)
+		}
+	}
+	realizedPnL := decimal.Zero
+	for _, trade := range trades {
+		realizedPnL = realizedPnL.Add(trade.PnL)
+	}
 
-	// 计算最大回撤 - 使用真实K线数据
-	capitalForDrawdown := stats["initial_capital"].(decimal.Decimal)
-	klines = ts.tradingEngine.GetKlines() // 获取回测过程中的K线数据
-	drawdownInfo := CalculateDrawdownWithKlines(orders, klines, capitalForDrawdown)
+	// 夏普比率和相对买入持有基准的收益归因（按K线收盘价估值）
+	sharpeRatio := klineStats.SharpeRatio(timeframe)
+	attribution := klineStats.Attribution()
 
 	// 计算年化收益率 (APR)
 	backtestDays := int(endTime.Sub(startTime).Hours() / 24)
@@ -225,8 +690,8 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 	var annualReturn decimal.Decimal
 	if backtestDays > 0 {
 		// APR = ((Final / Initial)^(365/Days) - 1) * 100
-		initialCap := stats["initial_capital"].(decimal.Decimal)
-		finalPort := stats["final_portfolio"].(decimal.Decimal)
+		initialCap := initialCapital
+		finalPort := finalPortfolio
 
 		if initialCap.IsPositive() {
 			totalReturn := finalPort.Div(initialCap) // Final/Initial
@@ -247,9 +712,9 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 	}
 
 	return &BacktestStatistics{
-		InitialCapital: stats["initial_capital"].(decimal.Decimal),
-		FinalPortfolio: stats["final_portfolio"].(decimal.Decimal),
-		TotalReturn:    stats["total_return"].(decimal.Decimal),
+		InitialCapital: initialCapital,
+		FinalPortfolio: finalPortfolio,
+		TotalReturn:    totalReturn,
 		TotalTrades:    stats["total_trades"].(int),
 		WinningTrades:  stats["winning_trades"].(int),
 		LosingTrades:   stats["losing_trades"].(int),
@@ -267,77 +732,114 @@ func (ts *TradingSystem) RunBacktestWithParamsAndCapital(pair cex.TradingPair, s
 		MaxLoss:        maxLoss,
 		ProfitFactor:   profitFactor,
 
+		// 已实现和未实现盈亏
+		RealizedPnL:       realizedPnL,
+		UnrealizedPnL:     unrealizedPnL,
+		OpenPositionValue: openPositionValue,
+		CarryingCost:      backtestExecutor.CarryingCost(),
+
 		// 最大回撤统计
 		MaxDrawdown:        drawdownInfo.MaxDrawdown,
 		MaxDrawdownPercent: drawdownInfo.MaxDrawdownPercent,
 		DrawdownDuration:   drawdownInfo.DrawdownDuration,
+		DrawdownPeakTime:   drawdownInfo.DrawdownPeakTime,
+		DrawdownTroughTime: drawdownInfo.DrawdownTroughTime,
+		RecoveryTime:       drawdownInfo.RecoveryTime,
+		RecoveryDuration:   drawdownInfo.RecoveryDuration,
+		LongestUnderwater:  drawdownInfo.LongestUnderwater,
 		CurrentDrawdown:    drawdownInfo.CurrentDrawdown,
 		PeakPortfolioValue: drawdownInfo.PeakValue,
+		SharpeRatio:        sharpeRatio,
 
 		// 年化收益率统计
 		AnnualReturn: annualReturn,
 		BacktestDays: backtestDays,
-	}, nil
+
+		// 收益归因
+		Attribution: attribution,
+
+		// 按市场状态拆分的表现
+		Regimes: klineStats.Regimes(trades),
+
+		// 按月、按年收益
+		Returns: CalculatePeriodReturns(equity, initialCapital),
+	}
 }
 
 // RunLiveTradingWithParams 使用指定策略参数运行实时交易
 func (ts *TradingSystem) RunLiveTradingWithParams(pair cex.TradingPair, strategyParams strategy.StrategyParams, dryRun bool) error {
-	// 测试 CEX 连接
-	err := ts.cexClient.Ping(ts.ctx)
-	if err != nil {
-		return fmt.Errorf("failed to connect to CEX: %w", err)
-	}
-	fmt.Println("✓ Connected to CEX API")
+	_, logger := log.WithCtx(ts.ctx)
 
-	// 检查 CEX 客户端是否已初始化
-	if ts.cexClient == nil {
-		return fmt.Errorf("CEX client not initialized")
+	// 创建策略（布林道策略）
+	strategyImpl := strategies.NewBollingerBandsStrategy()
+	params := liveStrategyParams(strategyParams)
+
+	// 验证参数
+	if err := params.Validate(); err != nil {
+		return fmt.Errorf("invalid strategy parameters: %w", err)
 	}
 
-	fmt.Println("🔴 Starting live trading...")
+	if err := strategyImpl.SetParams(params); err != nil {
+		return fmt.Errorf("failed to set strategy parameters: %w", err)
+	}
+	logger.Info(fmt.Sprintf("✓ 策略已初始化: strategy=%s, params=%+v", strategyImpl.GetName(), strategyImpl.GetParams()))
 
-	// 创建策略（目前只支持布林道策略）
-	strategyImpl := strategies.NewBollingerBandsStrategy()
+	return ts.RunLiveTradingWithStrategy(pair, strategyImpl, dryRun)
+}
 
-	// 使用传入的参数或默认参数
+// liveStrategyParams 实盘使用的布林道策略参数：未传入时使用默认参数，配置文件中的卖出策略覆盖命令行参数
+func liveStrategyParams(strategyParams strategy.StrategyParams) strategy.StrategyParams {
 	var params strategy.StrategyParams
 	if strategyParams != nil {
 		params = strategyParams
 	} else {
-		// 使用默认参数
 		params = strategy.GetDefaultBollingerBandsParams()
 	}
 
-	// 验证参数
-	if err := params.Validate(); err != nil {
-		return fmt.Errorf("invalid strategy parameters: %w", err)
+	if sellConfig := TradingConfigValue.SellStrategy; sellConfig.Name != "" {
+		if bollingerParams, ok := params.(*strategy.BollingerBandsParams); ok {
+			overridden := *bollingerParams
+			overridden.SellStrategyName = sellConfig.Name
+			overridden.SellStrategyParams = sellConfig.ParamMap()
+			params = &overridden
+		}
 	}
+	return params
+}
 
-	err = strategyImpl.SetParams(params)
+// RunLiveTradingWithStrategy 使用任意已设置好参数的策略运行实时交易
+func (ts *TradingSystem) RunLiveTradingWithStrategy(pair cex.TradingPair, strategyImpl strategy.Strategy, dryRun bool) error {
+	if dryRun || ts.signalOnly {
+		ts.startTrace(logging.TraceKindDryRun)
+	} else {
+		ts.startTrace(logging.TraceKindLive)
+	}
+	_, logger := log.WithCtx(ts.ctx)
+
+	// 检查 CEX 客户端是否已初始化
+	if ts.cexClient == nil {
+		return fmt.Errorf("CEX client not initialized")
+	}
+
+	// 测试 CEX 连接
+	err := ts.cexClient.Ping(ts.ctx)
 	if err != nil {
-		return fmt.Errorf("failed to set strategy parameters: %w", err)
+		return fmt.Errorf("failed to connect to CEX: %w", err)
 	}
-	fmt.Printf("✓ Initialized %s with params: %+v\n", strategyImpl.GetName(), strategyImpl.GetParams())
+	logger.Info(fmt.Sprintf("✓ 已连接交易所: exchange=%s", ts.cexClient.GetName()))
 
-	// 创建执行器（根据是否为Dry Run选择不同类型）
-	var orderStrategy executor.OrderStrategy
-	if dryRun {
-		// Dry Run模式：使用回测订单策略（不真实下单）
-		fmt.Println("🧪 Dry Run Mode: Real-time data, simulated orders")
-		orderStrategy = executor.NewBacktestOrderStrategy(pair)
-	} else {
-		// 真实交易模式：使用实盘订单策略
-		fmt.Println("💰 Live Trading Mode: Real orders will be placed!")
-		orderStrategy = executor.NewLiveOrderStrategy(ts.cexClient, pair)
+	// 真实下单前检查交易权限，不满足时不启动（bollinger --live 在 ARM 确认前已检查）
+	if !dryRun && !ts.signalOnly && !ts.tradingVerified {
+		if err := ts.VerifyLivePermissions(); err != nil {
+			return fmt.Errorf("live trading not permitted: %w", err)
+		}
+		logger.Info(fmt.Sprintf("✓ 交易权限检查通过: exchange=%s", ts.cexClient.GetName()))
 	}
 
-	// 假设实盘交易也有初始资金（可以从账户获取真实余额）
-	initialCapitalDecimal := decimal.NewFromFloat(10000) // TODO: 从账户获取真实余额
-	liveExecutor := executor.NewTradingExecutor(pair, initialCapitalDecimal)
-	liveExecutor.SetOrderStrategy(orderStrategy)
+	logger.Info(fmt.Sprintf("🔴 启动实盘交易: symbol=%s, dry_run=%v, signal_only=%v, testnet=%v", pair.String(), dryRun, ts.signalOnly, ts.Testnet()))
 
 	// 获取时间周期
-	timeframe, err := timeframes.ParseTimeframe(TradingConfigValue.Timeframe)
+	timeframe, err := timeframes.ParseTimeframe(ts.Timeframe())
 	if err != nil {
 		return fmt.Errorf("invalid timeframe: %w", err)
 	}
@@ -349,14 +851,117 @@ func (ts *TradingSystem) RunLiveTradingWithParams(pair cex.TradingPair, strategy
 	}
 	dataFeed := engine.NewLiveDataFeed(ts.cexClient, pair, timeframe.GetBinanceInterval(), tickerInterval)
 
-	// 🎯 创建挂单管理器（根据是否为Dry Run选择不同类型）
+	// 交易对下单规则：新挂单按步长/最小变动单位取整，低于最小下单金额的挂单不提交
+	symbolFilters := ts.loadSymbolFilters(pair)
+	ts.startSymbolRefresh(pair, symbolFilters)
+
+	// 事件总线：挂单、成交、风控和错误事件按路由规则发送通知，并推送到监控面板
+	events := engine.NewEventBus()
+	router, err := ts.startNotifications(events)
+	if err != nil {
+		return err
+	}
+	if err := ts.startDashboard(events); err != nil {
+		return err
+	}
+	ts.startEquitySnapshots(pair, events, dryRun)
+	ts.startBookTickerRecorder(pair)
+	if err := ts.startFuturesContext(strategyImpl, pair); err != nil {
+		return err
+	}
+
+	// 审计日志：实盘下单/撤单和配置热更新
+	auditLog, err := ts.openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	// 延迟统计：K线处理延迟、下单往返耗时和本地时钟偏差
+	latencyMonitor, err := ts.startLatencyMonitor()
+	if err != nil {
+		return err
+	}
+
+	// 🎯 创建执行器和挂单管理器（根据是否为Dry Run选择不同类型）
+	var liveExecutor executor.Executor
 	var orderManager engine.OrderManager
-	if dryRun {
-		// Dry Run模式：使用回测挂单管理器（本地模拟）
-		orderManager = engine.NewBacktestOrderManager(liveExecutor)
+	if ts.signalOnly {
+		// 只发信号模式：引擎不生成挂单，本地执行器只提供假想持仓的初始资金
+		logger.Info("📣 只发信号模式：实时行情，策略信号发送到通知，不下单")
+		capital := ts.paperCapital
+		if capital <= 0 {
+			capital = defaultPaperCapital
+		}
+		signalExecutor := executor.NewTradingExecutor(pair, decimal.NewFromFloat(capital))
+		signalExecutor.SetOrderStrategy(executor.NewBacktestOrderStrategy(pair))
+		liveExecutor = signalExecutor
+		orderManager = engine.NewBacktestOrderManager(signalExecutor)
+	} else if dryRun {
+		// Dry Run模式：模拟盘执行器按实时盘口撮合，挂单在本地模拟
+		logger.Info("🧪 Dry Run 模式：实时行情，按实时盘口模拟成交")
+		paperExecutor, err := ts.newPaperExecutor(pair)
+		if err != nil {
+			return err
+		}
+		if filters := symbolFilters.Filters(pair); filters != nil {
+			paperExecutor.SetQuantityStep(filters.StepSize)
+		}
+		liveExecutor = paperExecutor
+
+		backtestOrderManager := engine.NewBacktestOrderManager(paperExecutor)
+		backtestOrderManager.SetTradingCalendar(ts.calendar)
+		orderManager = backtestOrderManager
 	} else {
-		// 真实交易模式：使用实盘挂单管理器
-		orderManager = engine.NewLiveOrderManager(ts.cexClient)
+		// 真实交易模式：使用实盘订单策略
+		logger.Warning("💰 实盘模式：将在交易所真实下单！")
+
+		// 初始资金为占位值，启动对账时按账户真实余额校正
+		initialCapitalDecimal := decimal.NewFromFloat(10000)
+		var auditLogAuditor cex.OrderAuditor
+		if auditLog != nil {
+			auditLogAuditor = auditLog
+		}
+		auditor := cex.CombineOrderAuditors(auditLogAuditor, latencyMonitor)
+
+		tradingExecutor := executor.NewTradingExecutor(pair, initialCapitalDecimal)
+		liveOrderStrategy := executor.NewLiveOrderStrategy(ts.cexClient, pair)
+		liveOrderStrategy.SetOrderAuditor(auditor)
+		tradingExecutor.SetOrderStrategy(liveOrderStrategy)
+		if err := ts.applyFeeSchedule(tradingExecutor); err != nil {
+			return err
+		}
+		liveExecutor = tradingExecutor
+
+		// 实盘挂单管理器（交易所对单个交易对的挂单数量有上限）
+		limits := engine.OpenOrderLimits{
+			SoftLimit: TradingConfigValue.MaxOpenOrdersSoft,
+			HardLimit: TradingConfigValue.MaxOpenOrdersHard,
+		}
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("invalid open order limits: %w", err)
+		}
+		liveOrderManager := engine.NewLiveOrderManager(ts.cexClient)
+		liveOrderManager.SetOpenOrderLimits(limits)
+		liveOrderManager.SetSymbolFilters(symbolFilters)
+		liveOrderManager.SetEventBus(events)
+		liveOrderManager.SetOrderAuditor(auditor)
+		orderManager = liveOrderManager
+		logger.Info(fmt.Sprintf("✓ 挂单数量限制: symbol=%s, soft=%d, hard=%d", pair.String(), limits.SoftLimit, limits.HardLimit))
+
+		// 安全门：单笔开仓金额上限
+		if err := safety.ConfigValue.Validate(); err != nil {
+			return fmt.Errorf("invalid safety config: %w", err)
+		}
+		if maxNotional := safety.ConfigValue.MaxOrderNotional; maxNotional > 0 {
+			liveOrderManager.SetMaxOrderNotional(decimal.NewFromFloat(maxNotional))
+			logger.Info(fmt.Sprintf("🛡️ 单笔开仓金额上限: symbol=%s, MaxOrderNotional=%v %s", pair.String(), maxNotional, pair.Quote))
+		}
+
+		reconciler, err := ts.startReconciler(pair, liveOrderManager, tradingExecutor)
+		if err != nil {
+			return err
+		}
+		ts.startUserDataStream(pair, liveOrderManager, tradingExecutor, reconciler)
 	}
 
 	// 创建交易引擎
@@ -371,12 +976,116 @@ func (ts *TradingSystem) RunLiveTradingWithParams(pair cex.TradingPair, strategy
 	)
 
 	// 设置交易参数
+	sizer, err := TradingConfigValue.PositionSizing.NewPositionSizer(TradingConfigValue.PositionSizePercent)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("✓ 仓位计算: %s", sizer.Describe()))
 	ts.tradingEngine.SetPositionSizePercent(TradingConfigValue.PositionSizePercent)
+	ts.tradingEngine.SetPositionSizer(sizer)
 	ts.tradingEngine.SetMinTradeAmount(TradingConfigValue.MinTradeAmount)
+	ts.tradingEngine.SetTradingCalendar(ts.calendar)
+	ts.tradingEngine.SetSymbolFilters(symbolFilters)
+	ts.tradingEngine.SetEventBus(events)
+	ts.tradingEngine.SetSignalOnly(ts.signalOnly)
+	ts.tradingEngine.SetLatencyMonitor(latencyMonitor)
+	if err := TradingConfigValue.ApplyTimeInForce(ts.tradingEngine); err != nil {
+		return err
+	}
+	if err := TradingConfigValue.OrderChase.Validate(); err != nil {
+		return fmt.Errorf("invalid order chase config: %w", err)
+	}
+	ts.tradingEngine.SetOrderChasePolicy(TradingConfigValue.OrderChase)
+	if err := TradingConfigValue.SignalThrottle.Validate(); err != nil {
+		return fmt.Errorf("invalid signal throttle config: %w", err)
+	}
+	ts.tradingEngine.SetSignalThrottle(TradingConfigValue.SignalThrottle)
+	ts.applyStrategyStateStore(pair, dryRun)
+	if err := TradingConfigValue.Supervisor.Validate(); err != nil {
+		return fmt.Errorf("invalid supervisor config: %w", err)
+	}
+
+	// 实盘始终创建风控管理器，未配置限制时不拦截，运行中可通过热更新配置启用
+	if err := TradingConfigValue.Risk.Validate(); err != nil {
+		return fmt.Errorf("invalid risk config: %w", err)
+	}
+	riskManager := engine.NewRiskManager(TradingConfigValue.Risk)
+	ts.tradingEngine.SetRiskManager(riskManager)
+	if TradingConfigValue.Risk.Enabled() {
+		logger.Info(fmt.Sprintf("🛡️ 风控限制: MaxPositionValue=%v, MaxDailyLoss=%v, MaxDailyLossPercent=%v, MaxConsecutiveLosses=%d, MaxSymbolExposure=%v, MaxDrawdownPercent=%v, DrawdownAction=%s",
+			TradingConfigValue.Risk.MaxPositionValue, TradingConfigValue.Risk.MaxDailyLoss, TradingConfigValue.Risk.MaxDailyLossPercent,
+			TradingConfigValue.Risk.MaxConsecutiveLosses, TradingConfigValue.Risk.MaxSymbolExposure,
+			TradingConfigValue.Risk.MaxDrawdownPercent, TradingConfigValue.Risk.DrawdownAction))
+	}
+
+	ts.startConfigReload(riskManager, router, strategyImpl, auditLog)
+
+	stopWatchdog, err := ts.startFeedWatchdog(pair, timeframe.String(), tickerInterval, dataFeed, events)
+	if err != nil {
+		return err
+	}
+	defer stopWatchdog()
+
+	if ts.tui {
+		defer ts.startTUI(pair, events, dryRun)()
+	}
 
 	// 🚀 运行统一的tick-by-tick实盘交易
-	fmt.Println("🔴 Starting tick-by-tick live trading...")
-	return ts.tradingEngine.RunLive(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 停止时处理: %s", TradingConfigValue.Shutdown.Describe()))
+	logger.Info(fmt.Sprintf("✓ 意外退出自动重启: MaxRestartsPerHour=%d, RestartDelaySeconds=%d",
+		TradingConfigValue.Supervisor.MaxRestartsPerHour, TradingConfigValue.Supervisor.RestartDelaySeconds))
+	logger.Info(fmt.Sprintf("🔴 开始逐K线实盘交易: symbol=%s", pair.String()))
+	runErr := ts.runLiveSupervised(pair, events)
+	return ts.finishLive(router, runErr)
+}
+
+// startReconciler 启动前先与交易所对账一次（以账户真实余额为准），之后在后台定期对账
+func (ts *TradingSystem) startReconciler(pair cex.TradingPair, orders *engine.LiveOrderManager, balances engine.BalanceSyncer) (*engine.Reconciler, error) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	config := TradingConfigValue.Reconcile
+	if config.IntervalSeconds < 0 {
+		return nil, fmt.Errorf("invalid reconcile config: IntervalSeconds must be non-negative, got %d", config.IntervalSeconds)
+	}
+	reconciler := engine.NewReconciler(ts.cexClient, pair, orders, balances, config.Tolerance)
+	if err := reconciler.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reconcile config: %w", err)
+	}
+	if _, ok := ts.cexClient.(cex.OpenOrderClient); !ok {
+		logger.Warning(fmt.Sprintf("⚠️ 交易所不支持查询挂单，只对账余额: exchange=%s", ts.cexClient.GetName()))
+	}
+
+	report, err := reconciler.Reconcile(ts.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initial reconciliation failed: %w", err)
+	}
+	logger.Info(fmt.Sprintf("✓ 启动对账完成: exchange=%s, symbol=%s, cash=%s, position=%s",
+		ts.cexClient.GetName(), pair.String(), report.Cash.String(), report.Position.String()))
+
+	if config.IntervalSeconds > 0 {
+		go reconciler.Run(ts.ctx, time.Duration(config.IntervalSeconds)*time.Second)
+		logger.Info(fmt.Sprintf("✓ 后台定期对账: Reconcile.IntervalSeconds=%d", config.IntervalSeconds))
+	}
+	return reconciler, nil
+}
+
+// startUserDataStream 交易所支持时订阅账户数据流，止损单、OCO 的成交回报实时记入执行器
+func (ts *TradingSystem) startUserDataStream(pair cex.TradingPair, orders *engine.LiveOrderManager, fillRecorder engine.FillRecorder, reconciler *engine.Reconciler) {
+	_, logger := log.WithCtx(ts.ctx)
+
+	if !TradingConfigValue.UserDataStream {
+		return
+	}
+	client, ok := ts.cexClient.(cex.UserDataStreamClient)
+	if !ok {
+		logger.Warning(fmt.Sprintf("⚠️ 交易所不支持账户数据流，依赖定期对账: exchange=%s", ts.cexClient.GetName()))
+		return
+	}
+
+	stream := engine.NewUserDataStream(client, pair, orders, fillRecorder)
+	stream.SetReconciler(reconciler)
+	go stream.Run(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 已订阅账户数据流（成交回报）: symbol=%s", pair.String()))
 }
 
 // Stop 停止交易系统
@@ -385,13 +1094,20 @@ func (ts *TradingSystem) Stop() {
 		ts.tradingEngine.Stop()
 	}
 	ts.cancel()
-	fmt.Println("Trading system stopped")
+	if ts.stopTUI != nil {
+		ts.stopTUI()
+	}
+
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info("交易系统已停止")
 }
 
 // TradeAnalysis 单笔交易分析
 type TradeAnalysis struct {
 	BuyOrder   executor.OrderResult  `json:"buy_order"`
 	SellOrder  *executor.OrderResult `json:"sell_order,omitempty"`
+	Quantity   decimal.Decimal       `json:"quantity"`   // 本笔平仓数量（部分平仓时小于买入数量），未平仓时为剩余数量
+	MarkPrice  decimal.Decimal       `json:"mark_price"` // 未平仓持仓的估值价格（最后一根K线收盘价）
 	Duration   time.Duration         `json:"duration"`
 	PnL        decimal.Decimal       `json:"pnl"`
 	PnLPercent decimal.Decimal       `json:"pnl_percent"`
@@ -403,6 +1119,9 @@ type TradeAnalysis struct {
 
 // BacktestStatistics 回测统计结果
 type BacktestStatistics struct {
+	RunID          string                 `json:"run_id,omitempty"`   // 持久化后的回测记录ID
+	TraceID        string                 `json:"trace_id,omitempty"` // 本次回测的跟踪ID（日志前缀 trace=<id>）
+	StrategyName   string                 `json:"strategy_name,omitempty"`
 	InitialCapital decimal.Decimal        `json:"initial_capital"`
 	FinalPortfolio decimal.Decimal        `json:"final_portfolio"`
 	TotalReturn    decimal.Decimal        `json:"total_return"`
@@ -423,63 +1142,123 @@ type BacktestStatistics struct {
 	MaxLoss        decimal.Decimal `json:"max_loss"`
 	ProfitFactor   decimal.Decimal `json:"profit_factor"`
 
+	// 已实现盈亏（已完成交易）和未实现盈亏（未平仓持仓按最后一根K线收盘价估值），两者都计入 FinalPortfolio 和 TotalReturn
+	RealizedPnL       decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPnL     decimal.Decimal `json:"unrealized_pnl"`
+	OpenPositionValue decimal.Decimal `json:"open_position_value"` // 未平仓持仓市值
+	CarryingCost      decimal.Decimal `json:"carrying_cost"`       // 累计净资金成本（持仓成本减闲置现金收益），已从现金扣除
+
 	// 最大回撤相关统计
 	MaxDrawdown        decimal.Decimal `json:"max_drawdown"`         // 最大回撤金额
 	MaxDrawdownPercent decimal.Decimal `json:"max_drawdown_percent"` // 最大回撤百分比
-	DrawdownDuration   time.Duration   `json:"drawdown_duration"`    // 最大回撤持续时间
+	DrawdownDuration   time.Duration   `json:"drawdown_duration"`    // 最大回撤持续时间（峰值到恢复，未恢复时到回测结束）
+	DrawdownPeakTime   time.Time       `json:"drawdown_peak_time"`   // 最大回撤开始前的峰值时间
+	DrawdownTroughTime time.Time       `json:"drawdown_trough_time"` // 最大回撤的谷底时间
+	RecoveryTime       time.Time       `json:"recovery_time"`        // 回到峰值的时间，未恢复时为零值
+	RecoveryDuration   time.Duration   `json:"recovery_duration"`    // 谷底到恢复的时间
+	LongestUnderwater  time.Duration   `json:"longest_underwater"`   // 最长水下时间（低于前高的最长连续时间）
 	CurrentDrawdown    decimal.Decimal `json:"current_drawdown"`     // 当前回撤
 	PeakPortfolioValue decimal.Decimal `json:"peak_portfolio_value"` // 历史最高组合价值
+	SharpeRatio        decimal.Decimal `json:"sharpe_ratio"`         // 年化夏普比率（无风险利率为0）
 
 	// 年化收益率统计
 	AnnualReturn decimal.Decimal `json:"annual_return"` // 年化收益率 (APR)
 	BacktestDays int             `json:"backtest_days"` // 回测天数
+
+	// 收益归因（市场Beta vs 策略Alpha）
+	Attribution ReturnAttribution `json:"attribution"`
+
+	// 按市场状态（上涨趋势/下跌趋势/震荡）拆分的表现（启用 backtest.regimes 时）
+	Regimes *RegimeReport `json:"regimes,omitempty"`
+
+	// 按月、按年收益（由资金曲线计算）
+	Returns PeriodReturns `json:"returns"`
+
+	// 换算为记账货币的结果（配置 AccountingCurrency 时）
+	Accounting *AccountingSummary `json:"accounting,omitempty"`
+
+	// 复现清单：代码版本、配置快照、策略参数、数据哈希和随机种子
+	Manifest *RunManifest `json:"manifest,omitempty"`
 }
 
 // PrintBacktestResults 打印回测结果
 func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *BacktestStatistics) {
 	fmt.Println("\n============================================================")
-	fmt.Println("📊 BACKTEST RESULTS")
+	fmt.Println(i18n.T("report.title"))
 	fmt.Println("============================================================")
-	fmt.Printf("Strategy: Bollinger Bands Strategy\n")
-	fmt.Printf("Symbol: %s\n", pair.String())
-	fmt.Printf("Timeframe: %s\n", TradingConfigValue.Timeframe)
-	fmt.Printf("Initial Capital: $%.2f\n", stats.InitialCapital.InexactFloat64())
+	strategyName := stats.StrategyName
+	if strategyName == "" {
+		strategyName = i18n.T("report.default_strategy")
+	}
+	fmt.Println(i18n.T("report.strategy", strategyName))
+	fmt.Println(i18n.T("report.symbol", pair.String()))
+	fmt.Println(i18n.T("report.timeframe", ts.Timeframe()))
+	if stats.TraceID != "" {
+		fmt.Println(i18n.T("report.trace_id", stats.TraceID))
+	}
+	fmt.Println(i18n.T("report.initial_capital", stats.InitialCapital.InexactFloat64()))
 
-	fmt.Println("\n📈 PERFORMANCE METRICS")
+	fmt.Println("\n" + i18n.T("report.performance"))
 	fmt.Println("------------------------------")
 	totalReturnPercent := stats.TotalReturn.Mul(decimal.NewFromInt(100))
-	fmt.Printf("Total Return: %.2f%%\n", totalReturnPercent.InexactFloat64())
-	fmt.Printf("Annual Return (APR): %.2f%%\n", stats.AnnualReturn.InexactFloat64())
-	fmt.Printf("Backtest Period: %d days\n", stats.BacktestDays)
+	fmt.Println(i18n.T("report.total_return", totalReturnPercent.InexactFloat64()))
+	fmt.Println(i18n.T("report.annual_return", stats.AnnualReturn.InexactFloat64()))
+	fmt.Println(i18n.T("report.period", stats.BacktestDays))
 
 	winRate := decimal.Zero
 	if stats.TotalTrades > 0 {
 		winRate = decimal.NewFromInt(int64(stats.WinningTrades)).Div(decimal.NewFromInt(int64(stats.TotalTrades))).Mul(decimal.NewFromInt(100))
 	}
 
-	fmt.Println("\n📊 TRADING STATISTICS")
+	fmt.Println("\n" + i18n.T("report.trading_stats"))
 	fmt.Println("------------------------------")
-	fmt.Printf("Total Orders: %d\n", len(stats.Orders))
-	fmt.Printf("Completed Trade Pairs: %d\n", stats.TotalTrades)
-	fmt.Printf("Winning Trades: %d\n", stats.WinningTrades)
-	fmt.Printf("Losing Trades: %d\n", stats.LosingTrades)
-	fmt.Printf("Win Rate: %.2f%%\n", winRate.InexactFloat64())
+	fmt.Println(i18n.T("report.total_orders", len(stats.Orders)))
+	fmt.Println(i18n.T("report.completed_pairs", stats.TotalTrades))
+	fmt.Println(i18n.T("report.winning_trades", stats.WinningTrades))
+	fmt.Println(i18n.T("report.losing_trades", stats.LosingTrades))
+	fmt.Println(i18n.T("report.win_rate", winRate.InexactFloat64()))
 
 	totalPnL := stats.FinalPortfolio.Sub(stats.InitialCapital)
-	fmt.Printf("Total P&L: $%.2f\n", totalPnL.InexactFloat64())
+	fmt.Println(i18n.T("report.total_pnl", totalPnL.InexactFloat64()))
+	if stats.OpenPositionValue.IsPositive() {
+		fmt.Println(i18n.T("report.realized_pnl", stats.RealizedPnL.InexactFloat64()))
+		fmt.Println(i18n.T("report.unrealized_pnl",
+			stats.UnrealizedPnL.InexactFloat64(), stats.OpenPositionValue.InexactFloat64()))
+	}
+
+	totalCommission := decimal.Zero
+	for _, order := range stats.Orders {
+		totalCommission = totalCommission.Add(order.Commission)
+	}
+	fmt.Println(i18n.T("report.total_commission", totalCommission.InexactFloat64()))
+	if !stats.CarryingCost.IsZero() {
+		fmt.Println(i18n.T("report.carrying_cost", stats.CarryingCost.InexactFloat64()))
+	}
 
 	// 显示最近的交易
 	if len(stats.Orders) > 0 {
-		fmt.Println("\n📋 RECENT TRADES (Last 10)")
-		fmt.Println("================================================================================================")
-		fmt.Println("Time       Side Quantity     Price     Amount($)     P&L          Reason         ")
-		fmt.Println("================================================================================================")
+		fmt.Println("\n" + i18n.T("report.recent_trades"))
 
 		displayCount := len(stats.Orders)
 		if displayCount > 10 {
 			displayCount = 10
 		}
+		recent := stats.Orders[len(stats.Orders)-displayCount:]
 
+		// 数量和价格按交易对步长/最小变动单位的小数位显示，低价币不会显示成 0
+		quantities := make([]decimal.Decimal, len(recent))
+		prices := make([]decimal.Decimal, len(recent))
+		for i, order := range recent {
+			quantities[i], prices[i] = order.Quantity, order.Price
+		}
+		notation, err := ParseNumberNotation(TradingConfigValue.Backtest.NumberNotation)
+		if err != nil {
+			fmt.Println(i18n.T("report.notation_fallback", err))
+			notation = NotationFixed
+		}
+		format := NewTradeTableFormat(ts.displaySymbolFilters(pair), quantities, prices, notation)
+
+		rows := make([][]string, 0, len(recent))
 		for i := len(stats.Orders) - displayCount; i < len(stats.Orders); i++ {
 			order := stats.Orders[i]
 			pnlStr := "-"
@@ -495,59 +1274,66 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 			// 计算交易金额 (数量 × 价格)
 			amount := order.Quantity.Mul(order.Price)
 
-			fmt.Printf("%s %4s %12.0f %12.8f %10.2f %12s %s\n",
+			rows = append(rows, []string{
 				order.Timestamp.Format("01-02 15:04"),
-				order.Side,
-				order.Quantity.InexactFloat64(),
-				order.Price.InexactFloat64(),
-				amount.InexactFloat64(),
+				string(order.Side),
+				format.Quantity(order.Quantity),
+				format.Price(order.Price),
+				amount.StringFixed(2),
 				pnlStr,
-				"", // reason 暂时为空
-			)
+			})
 		}
+		WriteTable(os.Stdout, []TableColumn{
+			{Header: i18n.T("report.col.time")}, {Header: i18n.T("report.col.side")},
+			{Header: i18n.T("report.col.quantity"), Right: true}, {Header: i18n.T("report.col.price"), Right: true},
+			{Header: i18n.T("report.col.amount"), Right: true}, {Header: i18n.T("report.col.pnl"), Right: true},
+		}, rows)
 	}
 
 	// 显示详细分析
-	fmt.Println("\n🔍 DETAILED ANALYSIS")
+	fmt.Println("\n" + i18n.T("report.detailed"))
 	fmt.Println("------------------------------")
 
 	if len(stats.Trades) > 0 {
-		fmt.Printf("Completed Trades: %d\n", len(stats.Trades))
-		fmt.Printf("Average Holding Time: %v\n", formatDuration(stats.AvgHoldingTime))
-		fmt.Printf("Max Holding Time: %v\n", formatDuration(stats.MaxHoldingTime))
-		fmt.Printf("Min Holding Time: %v\n", formatDuration(stats.MinHoldingTime))
+		fmt.Println(i18n.T("report.completed_trades", len(stats.Trades)))
+		fmt.Println(i18n.T("report.avg_holding", formatDuration(stats.AvgHoldingTime)))
+		fmt.Println(i18n.T("report.max_holding", formatDuration(stats.MaxHoldingTime)))
+		fmt.Println(i18n.T("report.min_holding", formatDuration(stats.MinHoldingTime)))
 
 		if !stats.AvgWinningPnL.IsZero() {
-			fmt.Printf("Average Winning P&L: $%.2f\n", stats.AvgWinningPnL.InexactFloat64())
+			fmt.Println(i18n.T("report.avg_win", stats.AvgWinningPnL.InexactFloat64()))
 		}
 		if !stats.AvgLosingPnL.IsZero() {
-			fmt.Printf("Average Losing P&L: $%.2f\n", stats.AvgLosingPnL.InexactFloat64())
+			fmt.Println(i18n.T("report.avg_loss", stats.AvgLosingPnL.InexactFloat64()))
 		}
 		if !stats.MaxWin.IsZero() {
-			fmt.Printf("Max Win: $%.2f\n", stats.MaxWin.InexactFloat64())
+			fmt.Println(i18n.T("report.max_win", stats.MaxWin.InexactFloat64()))
 		}
 		if !stats.MaxLoss.IsZero() {
-			fmt.Printf("Max Loss: $%.2f\n", stats.MaxLoss.InexactFloat64())
+			fmt.Println(i18n.T("report.max_loss", stats.MaxLoss.InexactFloat64()))
 		}
 		if !stats.ProfitFactor.IsZero() {
-			fmt.Printf("Profit Factor: %.2f\n", stats.ProfitFactor.InexactFloat64())
+			fmt.Println(i18n.T("report.profit_factor", stats.ProfitFactor.InexactFloat64()))
 		}
 	}
 
 	// 显示未平仓订单
 	if len(stats.OpenPositions) > 0 {
-		fmt.Printf("\n🔓 OPEN POSITIONS: %d\n", len(stats.OpenPositions))
+		fmt.Println("\n" + i18n.T("report.open_positions", len(stats.OpenPositions)))
 		fmt.Println("--------------------------------------------------------------------------------")
-		fmt.Println("Buy Time   Buy Price    Quantity     Cost         Reason")
+		fmt.Println(i18n.T("report.open_header"))
 		fmt.Println("--------------------------------------------------------------------------------")
 
 		for _, pos := range stats.OpenPositions {
-			cost := pos.BuyOrder.Price.Mul(pos.BuyOrder.Quantity)
-			fmt.Printf("%s %12.6f %12.6f $%10.2f %s\n",
+			cost := pos.BuyOrder.Price.Mul(pos.Quantity)
+			fmt.Printf("%s %12.6f %12.6f $%10.2f %12.6f $%8.2f (%6.2f%%) %s\n",
 				pos.BuyOrder.Timestamp.Format("01-02 15:04"),
 				pos.BuyOrder.Price.InexactFloat64(),
-				pos.BuyOrder.Quantity.InexactFloat64(),
+				pos.Quantity.InexactFloat64(),
 				cost.InexactFloat64(),
+				pos.MarkPrice.InexactFloat64(),
+				pos.PnL.InexactFloat64(),
+				pos.PnLPercent.InexactFloat64(),
 				pos.BuyReason,
 			)
 		}
@@ -555,24 +1341,24 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 
 	// 显示每笔交易的详细情况
 	if len(stats.Trades) > 0 {
-		fmt.Printf("\n📊 ALL COMPLETED TRADES: %d\n", len(stats.Trades))
+		fmt.Println("\n" + i18n.T("report.all_trades", len(stats.Trades)))
 		fmt.Println("================================================================================================================================================")
-		fmt.Println("序号 买入时间      买入价格     买入金额     卖出时间      卖出价格     卖出金额      盈利%   净盈利$     持仓时间    卖出原因")
+		fmt.Println(i18n.T("report.all_header"))
 		fmt.Println("================================================================================================================================================")
 
 		for i, trade := range stats.Trades {
-			// 计算盈利百分比
-			profitPercent := trade.PnL.Div(trade.BuyOrder.Price.Mul(trade.BuyOrder.Quantity)).Mul(decimal.NewFromInt(100))
+			// 盈利百分比
+			profitPercent := trade.PnLPercent
 
-			// 计算买入金额和卖出金额
-			buyAmount := trade.BuyOrder.Quantity.Mul(trade.BuyOrder.Price)
-			sellAmount := trade.SellOrder.Quantity.Mul(trade.SellOrder.Price)
+			// 计算本笔平仓数量的买入金额和卖出金额
+			buyAmount := trade.Quantity.Mul(trade.BuyOrder.Price)
+			sellAmount := trade.Quantity.Mul(trade.SellOrder.Price)
 
 			// 确定卖出原因
-			sellReason := "触及上轨"
+			sellReason := i18n.T("report.reason_upper_band")
 			if trade.SellReason != "" {
-				if trade.SellReason == "strategy signal" {
-					sellReason = "触及上轨"
+				if trade.SellReason == defaultTradeReason {
+					sellReason = i18n.T("report.reason_upper_band")
 				} else {
 					sellReason = trade.SellReason
 				}
@@ -596,7 +1382,7 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 		fmt.Println("================================================================================================================================================")
 
 		// 统计不同盈利范围的交易
-		fmt.Println("\n📈 PROFIT DISTRIBUTION")
+		fmt.Println("\n" + i18n.T("report.profit_dist"))
 		fmt.Println("------------------------------")
 
 		ranges := map[string][2]float64{
@@ -612,8 +1398,7 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 			totalProfit := decimal.Zero
 
 			for _, trade := range stats.Trades {
-				profitPercent := trade.PnL.Div(trade.BuyOrder.Price.Mul(trade.BuyOrder.Quantity)).Mul(decimal.NewFromInt(100))
-				percent := profitPercent.InexactFloat64()
+				percent := trade.PnLPercent.InexactFloat64()
 
 				if percent >= bounds[0] && percent < bounds[1] {
 					count++
@@ -623,15 +1408,15 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 
 			if count > 0 {
 				avgProfit := totalProfit.Div(decimal.NewFromInt(int64(count)))
-				fmt.Printf("%s: %2d笔交易, 总盈利: $%8.2f, 平均: $%7.2f\n",
-					rangeName, count, totalProfit.InexactFloat64(), avgProfit.InexactFloat64())
+				fmt.Println(i18n.T("report.profit_range",
+					rangeName, count, totalProfit.InexactFloat64(), avgProfit.InexactFloat64()))
 			}
 		}
 	}
 
 	// 显示最佳和最差交易
 	if len(stats.Trades) > 0 {
-		fmt.Println("\n🏆 BEST & WORST TRADES")
+		fmt.Println("\n" + i18n.T("report.best_worst"))
 		fmt.Println("--------------------------------------------------------------------------------")
 
 		var bestTrade, worstTrade *TradeAnalysis
@@ -646,54 +1431,100 @@ func (ts *TradingSystem) PrintBacktestResults(pair cex.TradingPair, stats *Backt
 		}
 
 		if bestTrade != nil {
-			fmt.Printf("🥇 Best Trade: %s -> %s (%.2f%%) P&L: $%.2f Duration: %v\n",
+			fmt.Println(i18n.T("report.best_trade",
 				bestTrade.BuyOrder.Timestamp.Format("01-02 15:04"),
 				bestTrade.SellOrder.Timestamp.Format("01-02 15:04"),
 				bestTrade.PnLPercent.InexactFloat64(),
 				bestTrade.PnL.InexactFloat64(),
 				formatDuration(bestTrade.Duration),
-			)
+			))
 		}
 
 		if worstTrade != nil {
-			fmt.Printf("🥉 Worst Trade: %s -> %s (%.2f%%) P&L: $%.2f Duration: %v\n",
+			fmt.Println(i18n.T("report.worst_trade",
 				worstTrade.BuyOrder.Timestamp.Format("01-02 15:04"),
 				worstTrade.SellOrder.Timestamp.Format("01-02 15:04"),
 				worstTrade.PnLPercent.InexactFloat64(),
 				worstTrade.PnL.InexactFloat64(),
 				formatDuration(worstTrade.Duration),
-			)
+			))
 		}
 	}
 
 	// 显示最大回撤信息
-	fmt.Println("\n📉 RISK METRICS")
+	fmt.Println("\n" + i18n.T("report.risk_metrics"))
 	fmt.Println("--------------------------------------------------------------------------------")
-	fmt.Printf("Max Drawdown: $%.2f (%.2f%%)\n",
+	fmt.Println(i18n.T("report.max_drawdown",
 		stats.MaxDrawdown.InexactFloat64(),
-		stats.MaxDrawdownPercent.InexactFloat64())
+		stats.MaxDrawdownPercent.InexactFloat64()))
 
 	if stats.DrawdownDuration > 0 {
-		fmt.Printf("Drawdown Duration: %v\n", formatDuration(stats.DrawdownDuration))
+		fmt.Println(i18n.T("report.drawdown_duration", formatDuration(stats.DrawdownDuration),
+			stats.DrawdownPeakTime.Format("2006-01-02 15:04"), stats.DrawdownTroughTime.Format("2006-01-02 15:04")))
+		if stats.RecoveryTime.IsZero() {
+			fmt.Println(i18n.T("report.not_recovered"))
+		} else {
+			fmt.Println(i18n.T("report.recovery", stats.RecoveryTime.Format("2006-01-02 15:04"), formatDuration(stats.RecoveryDuration)))
+		}
+	}
+	if stats.LongestUnderwater > 0 {
+		fmt.Println(i18n.T("report.longest_underwater", formatDuration(stats.LongestUnderwater)))
 	}
 
-	fmt.Printf("Peak Portfolio Value: $%.2f\n", stats.PeakPortfolioValue.InexactFloat64())
+	fmt.Println(i18n.T("report.peak_value", stats.PeakPortfolioValue.InexactFloat64()))
+	fmt.Println(i18n.T("report.sharpe", stats.SharpeRatio.InexactFloat64()))
 
 	if stats.CurrentDrawdown.IsPositive() {
 		currentDrawdownPercent := decimal.Zero
 		if stats.PeakPortfolioValue.IsPositive() {
 			currentDrawdownPercent = stats.CurrentDrawdown.Div(stats.PeakPortfolioValue).Mul(decimal.NewFromInt(100))
 		}
-		fmt.Printf("Current Drawdown: $%.2f (%.2f%%)\n",
+		fmt.Println(i18n.T("report.current_drawdown",
 			stats.CurrentDrawdown.InexactFloat64(),
-			currentDrawdownPercent.InexactFloat64())
+			currentDrawdownPercent.InexactFloat64()))
 	} else {
-		fmt.Printf("Current Drawdown: $0.00 (0.00%%)\n")
+		fmt.Println(i18n.T("report.current_drawdown", 0.0, 0.0))
 	}
 
+	printPeriodReturns(stats.Returns)
+	printReturnAttribution(stats)
+	printRegimeReport(stats.Regimes)
+	printAccountingSummary(stats.Accounting)
+
 	fmt.Println("\n============================================================")
 }
 
+// printReturnAttribution 打印收益归因：收益来自策略还是来自标的上涨
+func printReturnAttribution(stats *BacktestStatistics) {
+	attribution := stats.Attribution
+	if len(attribution.Monthly) == 0 {
+		return
+	}
+
+	percent := func(d decimal.Decimal) float64 {
+		return d.Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+
+	fmt.Println("\n" + i18n.T("report.attribution"))
+	fmt.Println("--------------------------------------------------------------------------------")
+	fmt.Println(i18n.T("report.beta", attribution.Beta.InexactFloat64()))
+	fmt.Println(i18n.T("report.buy_hold", percent(attribution.MarketReturn)))
+	fmt.Println(i18n.T("report.market_component", percent(attribution.MarketComponent)))
+	fmt.Println(i18n.T("report.alpha", percent(attribution.Alpha)))
+
+	fmt.Println("\n" + i18n.T("report.attribution_header"))
+	fmt.Println("--------------------------------------------------------------------------------")
+	for _, monthly := range attribution.Monthly {
+		fmt.Printf("%-8s %10.2f %9.2f %11.2f %8.2f\n",
+			monthly.Month,
+			percent(monthly.StrategyReturn),
+			percent(monthly.MarketReturn),
+			percent(monthly.MarketComponent),
+			percent(monthly.Alpha),
+		)
+	}
+}
+
 // formatDuration 格式化时间间隔
 func formatDuration(d time.Duration) string {
 	if d == 0 {
@@ -724,47 +1555,24 @@ func findPreviousBuyOrder(orders []executor.OrderResult, currentIndex int) *exec
 	return nil
 }
 
-// AnalyzeTrades 分析交易数据，计算详细统计信息
+// AnalyzeTrades 分析交易数据，计算详细统计信息（按 FIFO 匹配持仓批次）
 func AnalyzeTrades(orders []executor.OrderResult) ([]TradeAnalysis, []TradeAnalysis, time.Duration, time.Duration, time.Duration, decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal) {
-	var trades []TradeAnalysis
-	var openPositions []TradeAnalysis
-	var holdingTimes []time.Duration
+	return AnalyzeTradesWithLotMatching(orders, LotMatchingFIFO)
+}
+
+// AnalyzeTradesWithLotMatching 按指定的持仓批次匹配方式（fifo/lifo/average）分析交易数据
+func AnalyzeTradesWithLotMatching(orders []executor.OrderResult, lotMatching string) ([]TradeAnalysis, []TradeAnalysis, time.Duration, time.Duration, time.Duration, decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	holdingTimes := make([]time.Duration, 0, len(orders)/2)
 	var winningPnLs []decimal.Decimal
 	var losingPnLs []decimal.Decimal
 
-	// 配对买入和卖出订单
-	var pendingBuys []executor.OrderResult
-
-	for _, order := range orders {
-		if order.Side == executor.OrderSideBuy {
-			pendingBuys = append(pendingBuys, order)
-		} else if order.Side == executor.OrderSideSell && len(pendingBuys) > 0 {
-			// 找到对应的买入订单（FIFO）
-			buyOrder := pendingBuys[0]
-			pendingBuys = pendingBuys[1:]
-
-			// 计算持仓时间
-			duration := order.Timestamp.Sub(buyOrder.Timestamp)
-
-			// 计算盈亏
-			buyValue := buyOrder.Price.Mul(buyOrder.Quantity)
-			sellValue := order.Price.Mul(order.Quantity)
-			pnl := sellValue.Sub(buyValue)
-			pnlPercent := pnl.Div(buyValue).Mul(decimal.NewFromInt(100))
-
-			trade := TradeAnalysis{
-				BuyOrder:   buyOrder,
-				SellOrder:  &order,
-				Duration:   duration,
-				PnL:        pnl,
-				PnLPercent: pnlPercent,
-				IsOpen:     false,
-				BuyReason:  "strategy signal", // 默认原因
-				SellReason: "strategy signal", // 默认原因
-			}
-
+	// 按持仓批次配对买入和卖出，部分卖出只平掉对应数量
+	ledger := NewPositionLedger(lotMatching)
+	trades := make([]TradeAnalysis, 0, len(orders)/2)
+	for _, order := range sortOrdersByTime(orders) {
+		for _, trade := range ledger.Apply(order) {
 			trades = append(trades, trade)
-			holdingTimes = append(holdingTimes, duration)
+			holdingTimes = append(holdingTimes, trade.Duration)
 
 			if trade.PnL.IsPositive() {
 				winningPnLs = append(winningPnLs, trade.PnL)
@@ -773,21 +1581,7 @@ func AnalyzeTrades(orders []executor.OrderResult) ([]TradeAnalysis, []TradeAnaly
 			}
 		}
 	}
-
-	// 处理未平仓订单
-	for _, buyOrder := range pendingBuys {
-		trade := TradeAnalysis{
-			BuyOrder:   buyOrder,
-			SellOrder:  nil,
-			Duration:   0,
-			PnL:        decimal.Zero,
-			PnLPercent: decimal.Zero,
-			IsOpen:     true,
-			BuyReason:  "strategy signal", // 默认原因
-			SellReason: "",
-		}
-		openPositions = append(openPositions, trade)
-	}
+	openPositions := ledger.OpenPositions()
 
 	// 计算统计信息
 	var avgHoldingTime, maxHoldingTime, minHoldingTime time.Duration
@@ -852,16 +1646,27 @@ func AnalyzeTrades(orders []executor.OrderResult) ([]TradeAnalysis, []TradeAnaly
 	return trades, openPositions, avgHoldingTime, maxHoldingTime, minHoldingTime, avgWinningPnL, avgLosingPnL, maxWin, maxLoss, profitFactor
 }
 
-// DrawdownInfo 回撤信息结构
-type DrawdownInfo struct {
-	MaxDrawdown        decimal.Decimal // 最大回撤金额
-	MaxDrawdownPercent decimal.Decimal // 最大回撤百分比
-	DrawdownDuration   time.Duration   // 最大回撤持续时间
-	CurrentDrawdown    decimal.Decimal // 当前回撤
-	PeakValue          decimal.Decimal // 历史最高价值
+// valueOpenPositions 按估值价格计算每个未平仓持仓的未实现盈亏（扣除剩余买入手续费），返回合计
+func valueOpenPositions(openPositions []TradeAnalysis, markPrice decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for i := range openPositions {
+		pos := &openPositions[i]
+		cost := pos.BuyOrder.Price.Mul(pos.Quantity)
+		pos.MarkPrice = markPrice
+		pos.PnL = markPrice.Mul(pos.Quantity).Sub(cost).Sub(pos.Commission)
+		if cost.IsPositive() {
+			pos.PnLPercent = pos.PnL.Div(cost).Mul(decimal.NewFromInt(100))
+		}
+		total = total.Add(pos.PnL)
+	}
+	return total
 }
 
-// CalculateDrawdownWithKlines 计算最大回撤（使用K线数据获取实时价格）
+// DrawdownInfo 回撤信息结构
+type DrawdownInfo = engine.DrawdownInfo
+
+// CalculateDrawdownWithKlines 根据成交回放计算最大回撤（按K线收盘价估值持仓，不计手续费）
+// 回测统计使用引擎逐根K线跟踪的回撤，这里用于只有成交记录和K线的场景
 func CalculateDrawdownWithKlines(orders []executor.OrderResult, klines []*cex.KlineData, initialCapital decimal.Decimal) DrawdownInfo {
 	if len(orders) == 0 || len(klines) == 0 {
 		return DrawdownInfo{
@@ -870,26 +1675,15 @@ func CalculateDrawdownWithKlines(orders []executor.OrderResult, klines []*cex.Kl
 	}
 
 	// 按时间排序订单
-	ordersCopy := make([]executor.OrderResult, len(orders))
-	copy(ordersCopy, orders)
-	for i := 0; i < len(ordersCopy)-1; i++ {
-		for j := i + 1; j < len(ordersCopy); j++ {
-			if ordersCopy[i].Timestamp.After(ordersCopy[j].Timestamp) {
-				ordersCopy[i], ordersCopy[j] = ordersCopy[j], ordersCopy[i]
-			}
-		}
-	}
+	ordersCopy := sortOrdersByTime(orders)
 
 	currentCash := initialCapital
-	peakValue := initialCapital
-	maxDrawdown := decimal.Zero
-	maxDrawdownPercent := decimal.Zero
+	tracker := engine.NewDrawdownTracker(initialCapital)
 
-	// 跟踪当前持仓
-	var currentPositions []executor.OrderResult // 所有未平仓的买入订单
+	// 跟踪当前持仓（只用总数量，与批次匹配方式无关）
+	ledger := NewPositionLedger(LotMatchingFIFO)
 	orderIndex := 0
 
-	// 🔥 关键修复：遍历每个K线时间点，而不是只在订单时间点
 	for _, kline := range klines {
 		// 处理当前K线时间之前的所有订单
 		for orderIndex < len(ordersCopy) && !ordersCopy[orderIndex].Timestamp.After(kline.CloseTime) {
@@ -898,65 +1692,27 @@ func CalculateDrawdownWithKlines(orders []executor.OrderResult, klines []*cex.Kl
 			if order.Side == executor.OrderSideBuy {
 				// 买入：现金减少，记录持仓
 				currentCash = currentCash.Sub(order.Price.Mul(order.Quantity))
-				currentPositions = append(currentPositions, order)
-			} else if order.Side == executor.OrderSideSell && len(currentPositions) > 0 {
-				// 卖出：现金增加，移除第一个持仓（FIFO）
-				sellValue := order.Price.Mul(order.Quantity)
-				currentCash = currentCash.Add(sellValue)
-
-				// 移除对应的买入订单（简化处理：FIFO）
-				if len(currentPositions) > 0 {
-					currentPositions = currentPositions[1:]
-				}
+				ledger.Buy(order)
+			} else if order.Side == executor.OrderSideSell && ledger.Position().IsPositive() {
+				// 卖出：现金增加，按 FIFO 平掉对应数量的持仓
+				currentCash = currentCash.Add(order.Price.Mul(order.Quantity))
+				ledger.Sell(order)
 			}
 			orderIndex++
 		}
 
-		// 🔥 使用当前K线的收盘价估值所有持仓
-		currentValue := currentCash
-		marketPrice := kline.Close
-
-		for _, position := range currentPositions {
-			positionValue := position.Quantity.Mul(marketPrice)
-			currentValue = currentValue.Add(positionValue)
-		}
-
-		// 更新峰值
-		if currentValue.GreaterThan(peakValue) {
-			peakValue = currentValue
-		}
-
-		// 计算当前回撤
-		currentDrawdown := peakValue.Sub(currentValue)
-		currentDrawdownPercent := decimal.Zero
-		if peakValue.IsPositive() {
-			currentDrawdownPercent = currentDrawdown.Div(peakValue).Mul(decimal.NewFromInt(100))
-		}
-
-		// 更新最大回撤
-		if currentDrawdown.GreaterThan(maxDrawdown) {
-			maxDrawdown = currentDrawdown
-			maxDrawdownPercent = currentDrawdownPercent
-		}
+		// 使用当前K线的收盘价估值所有持仓
+		tracker.Update(kline, currentCash.Add(ledger.Position().Mul(kline.Close)))
 	}
 
-	// 计算最终状态
-	finalCash := currentCash
-	for _, position := range currentPositions {
-		// 使用最后一个K线价格估值剩余持仓
-		if len(klines) > 0 {
-			lastPrice := klines[len(klines)-1].Close
-			finalCash = finalCash.Add(position.Quantity.Mul(lastPrice))
-		}
-	}
-
-	currentDrawdown := peakValue.Sub(finalCash)
+	return tracker.Drawdown()
+}
 
-	return DrawdownInfo{
-		MaxDrawdown:        maxDrawdown,
-		MaxDrawdownPercent: maxDrawdownPercent,
-		DrawdownDuration:   time.Duration(0), // 简化，暂不计算持续时间
-		CurrentDrawdown:    currentDrawdown,
-		PeakValue:          peakValue,
+// CalculateSharpeRatio 计算年化夏普比率（按K线收盘价估值的逐根收益率，无风险利率为0）
+func CalculateSharpeRatio(orders []executor.OrderResult, klines []*cex.KlineData, initialCapital decimal.Decimal, timeframe timeframes.Timeframe) decimal.Decimal {
+	tracker := newSharpeTracker(orders, initialCapital)
+	for _, kline := range klines {
+		tracker.add(kline)
 	}
+	return tracker.result(timeframe)
 }
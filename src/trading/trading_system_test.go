@@ -1,11 +1,13 @@
 package trading
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"tradingbot/src/cex"
 	"tradingbot/src/executor"
+	"tradingbot/src/timeframes"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -96,8 +98,34 @@ func TestCalculateDrawdown(t *testing.T) {
 		// 应该有明显的回撤
 		assert.True(t, drawdown.MaxDrawdown.GreaterThan(decimal.Zero))
 		assert.True(t, drawdown.MaxDrawdownPercent.GreaterThan(decimal.Zero))
-		// DrawdownDuration计算当前被简化为0，不测试具体持续时间
-		assert.True(t, drawdown.DrawdownDuration.Seconds() >= 0)
+		// 回测结束时仍未恢复，持续时间计到最后一根K线
+		assert.True(t, drawdown.RecoveryTime.IsZero())
+		assert.Equal(t, drawdown.DrawdownPeakTime, klines[0].CloseTime)
+		assert.Equal(t, klines[1].CloseTime.Sub(klines[0].CloseTime), drawdown.DrawdownDuration)
+	})
+
+	t.Run("drawdown duration and recovery", func(t *testing.T) {
+		initialCapital := decimal.NewFromFloat(1000)
+		baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		orders := []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Price: decimal.NewFromFloat(100), Quantity: decimal.NewFromFloat(10), Timestamp: baseTime},
+		}
+
+		// 满仓持有：100 → 110（峰值）→ 90 → 80（谷底）→ 112（恢复）→ 105 → 100（未恢复的小回撤）
+		var klines []*cex.KlineData
+		for i, price := range []float64{100, 110, 90, 80, 112, 105, 100} {
+			klines = append(klines, &cex.KlineData{CloseTime: baseTime.Add(time.Duration(i) * time.Hour), Close: decimal.NewFromFloat(price)})
+		}
+		drawdown := CalculateDrawdownWithKlines(orders, klines, initialCapital)
+
+		assert.True(t, drawdown.MaxDrawdown.Equal(decimal.NewFromFloat(300)))
+		assert.Equal(t, baseTime.Add(time.Hour), drawdown.DrawdownPeakTime)
+		assert.Equal(t, baseTime.Add(3*time.Hour), drawdown.DrawdownTroughTime)
+		assert.Equal(t, baseTime.Add(4*time.Hour), drawdown.RecoveryTime)
+		assert.Equal(t, 3*time.Hour, drawdown.DrawdownDuration)
+		assert.Equal(t, time.Hour, drawdown.RecoveryDuration)
+		// 最长水下区间为峰值 110 到恢复的 3 小时，末尾的小回撤只有 2 小时
+		assert.Equal(t, 3*time.Hour, drawdown.LongestUnderwater)
 	})
 
 	t.Run("multiple trades with drawdown recovery", func(t *testing.T) {
@@ -132,6 +160,44 @@ func TestCalculateDrawdown(t *testing.T) {
 	})
 }
 
+// Test CalculateSharpeRatio - 夏普比率计算测试
+func TestCalculateSharpeRatio(t *testing.T) {
+	baseTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	makeKlines := func(closes ...float64) []*cex.KlineData {
+		klines := make([]*cex.KlineData, len(closes))
+		for i, c := range closes {
+			klines[i] = &cex.KlineData{
+				OpenTime:  baseTime.Add(time.Duration(i) * 24 * time.Hour),
+				CloseTime: baseTime.Add(time.Duration(i+1)*24*time.Hour - time.Millisecond),
+				Close:     decimal.NewFromFloat(c),
+			}
+		}
+		return klines
+	}
+	initialCapital := decimal.NewFromFloat(10000)
+
+	t.Run("no orders", func(t *testing.T) {
+		sharpe := CalculateSharpeRatio(nil, makeKlines(100, 110, 90, 120), initialCapital, timeframes.Timeframe1d)
+		assert.True(t, sharpe.IsZero())
+	})
+
+	t.Run("rising market is positive", func(t *testing.T) {
+		orders := []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Price: decimal.NewFromFloat(100), Quantity: decimal.NewFromFloat(100), Timestamp: baseTime},
+		}
+		sharpe := CalculateSharpeRatio(orders, makeKlines(100, 102, 101, 105, 108), initialCapital, timeframes.Timeframe1d)
+		assert.True(t, sharpe.GreaterThan(decimal.Zero))
+	})
+
+	t.Run("falling market is negative", func(t *testing.T) {
+		orders := []executor.OrderResult{
+			{Side: executor.OrderSideBuy, Price: decimal.NewFromFloat(100), Quantity: decimal.NewFromFloat(100), Timestamp: baseTime},
+		}
+		sharpe := CalculateSharpeRatio(orders, makeKlines(100, 97, 98, 94, 90), initialCapital, timeframes.Timeframe1d)
+		assert.True(t, sharpe.LessThan(decimal.Zero))
+	})
+}
+
 // Test AnalyzeTrades - 交易分析测试
 func TestAnalyzeTrades(t *testing.T) {
 	orders := []executor.OrderResult{
@@ -178,6 +244,41 @@ func TestAnalyzeTrades(t *testing.T) {
 	}
 }
 
+func TestBuildBacktestStatistics_UnrealizedPnL(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	backtestExecutor := executor.NewTradingExecutor(pair, decimal.NewFromInt(1000))
+	backtestExecutor.RecordFill(context.Background(), &executor.OrderResult{OrderID: "buy", Side: executor.OrderSideBuy,
+		Quantity: decimal.NewFromInt(5), Price: decimal.NewFromInt(100), Commission: decimal.NewFromInt(1), Timestamp: start})
+	backtestExecutor.RecordFill(context.Background(), &executor.OrderResult{OrderID: "sell", Side: executor.OrderSideSell,
+		Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(110), Commission: decimal.NewFromFloat(0.5), Timestamp: start.Add(4 * time.Hour)})
+
+	var klines []*cex.KlineData
+	for i, price := range []int64{100, 110, 120} {
+		klines = append(klines, &cex.KlineData{OpenTime: start.Add(time.Duration(i) * 4 * time.Hour),
+			CloseTime: start.Add(time.Duration(i+1) * 4 * time.Hour), Open: decimal.NewFromInt(price), Close: decimal.NewFromInt(price)})
+	}
+
+	klineStats := newExecutorKlineStats(backtestExecutor)
+	for _, kline := range klines {
+		klineStats.Add(kline)
+	}
+	stats := buildBacktestStatistics(backtestExecutor, klineStats, DrawdownInfo{}, nil, LotMatchingFIFO, timeframes.Timeframe4h, start, start.Add(12*time.Hour))
+
+	// 剩余 3 个按最后收盘价 120 估值：现金 718.5 + 360
+	assert.Equal(t, "1078.5", stats.FinalPortfolio.String())
+	assert.Equal(t, "0.0785", stats.TotalReturn.String())
+	assert.Equal(t, "360", stats.OpenPositionValue.String())
+	// 已实现：2 × (110 - 100) - 0.4 - 0.5；未实现：3 × (120 - 100) - 0.6
+	assert.Equal(t, "19.1", stats.RealizedPnL.String())
+	assert.Equal(t, "59.4", stats.UnrealizedPnL.String())
+	assert.True(t, stats.RealizedPnL.Add(stats.UnrealizedPnL).Equal(stats.FinalPortfolio.Sub(stats.InitialCapital)))
+
+	require.Len(t, stats.OpenPositions, 1)
+	assert.Equal(t, "120", stats.OpenPositions[0].MarkPrice.String())
+	assert.Equal(t, "59.4", stats.OpenPositions[0].PnL.String())
+}
+
 // Test formatDuration helper function
 func TestFormatDuration(t *testing.T) {
 	testCases := []struct {
@@ -274,6 +375,51 @@ func BenchmarkAnalyzeTrades(b *testing.B) {
 	}
 }
 
+// largeOrderHistory 生成 n 笔订单：每两笔买入后分两次卖出，卖出数量跨越批次（用于大规模基准测试）
+func largeOrderHistory(n int) ([]executor.OrderResult, []*cex.KlineData) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := make([]executor.OrderResult, 0, n)
+	klines := make([]*cex.KlineData, 0, n)
+	quantities := []float64{1, 1, 1.5, 0.5}
+	for i := 0; i < n; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		price := decimal.NewFromInt(int64(100 + i%50))
+		side := executor.OrderSideBuy
+		if i%4 >= 2 {
+			side = executor.OrderSideSell
+		}
+		orders = append(orders, executor.OrderResult{
+			Side: side, Price: price, Quantity: decimal.NewFromFloat(quantities[i%4]),
+			Commission: decimal.NewFromFloat(0.1), Timestamp: at,
+		})
+		klines = append(klines, &cex.KlineData{CloseTime: at, Close: price})
+	}
+	// 倒序输入，包含排序开销
+	for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+		orders[i], orders[j] = orders[j], orders[i]
+	}
+	return orders, klines
+}
+
+func BenchmarkAnalyzeTrades_100k(b *testing.B) {
+	orders, _ := largeOrderHistory(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AnalyzeTrades(orders)
+	}
+}
+
+func BenchmarkCalculateDrawdown_100k(b *testing.B) {
+	orders, klines := largeOrderHistory(100_000)
+	initialCapital := decimal.NewFromFloat(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculateDrawdownWithKlines(orders, klines, initialCapital)
+	}
+}
+
 // Test BacktestStatistics structure and calculations
 func TestBacktestStatistics_Calculations(t *testing.T) {
 	// 创建模拟的交易数据
@@ -0,0 +1,61 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/tui"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// tuiLogLines 终端界面保留的日志行数
+const tuiLogLines = 500
+
+// startTUI 启动终端界面：日志改写到界面的日志区，按键操作交易引擎；
+// 返回的函数关闭界面、恢复终端和日志输出（可重复调用）
+func (ts *TradingSystem) startTUI(pair cex.TradingPair, events *engine.EventBus, dryRun bool) func() {
+	_, logger := log.WithCtx(ts.ctx)
+
+	state := tui.NewState()
+	state.Subscribe(events)
+	logs := tui.NewLogBuffer(tuiLogLines)
+
+	mode := "LIVE"
+	if ts.signalOnly {
+		mode = "SIGNAL ONLY"
+	} else if dryRun {
+		mode = "DRY RUN"
+	}
+	if ts.Testnet() {
+		mode += " · TESTNET"
+	}
+	app := tui.NewApp(fmt.Sprintf("%s %s", pair.String(), ts.Timeframe()), mode, state, logs, ts.tradingEngine, ts.Stop)
+
+	previous := log.Writer()
+	log.SetWriter(logs)
+
+	ctx, cancel := context.WithCancel(ts.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := app.Run(ctx); err != nil {
+			log.SetWriter(previous)
+			logger.Warning(fmt.Sprintf("⚠️ 终端界面不可用，继续以日志方式运行: %v", err))
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+			log.SetWriter(previous)
+		})
+	}
+	ts.stopTUI = stop
+	return stop
+}
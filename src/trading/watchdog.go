@@ -0,0 +1,49 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/dashboard"
+	"tradingbot/src/engine"
+
+	"github.com/xpwu/go-log/log"
+)
+
+// startFeedWatchdog 启动实盘数据喂入看门狗：超过 FeedStallTimeframes 个K线周期没有新K线时告警并重启数据喂入，
+// 状态注册到 /healthz；返回停止函数（停止检查并注销健康检查），未启用时返回空操作
+func (ts *TradingSystem) startFeedWatchdog(pair cex.TradingPair, timeframe string, timeframeDuration time.Duration, feed engine.DataFeed, events *engine.EventBus) (func(), error) {
+	factor := TradingConfigValue.FeedStallTimeframes
+	if factor < 0 {
+		return nil, fmt.Errorf("invalid FeedStallTimeframes: cannot be negative, got %v", factor)
+	}
+	if factor == 0 {
+		return func() {}, nil
+	}
+
+	timeout := time.Duration(factor * float64(timeframeDuration))
+	watchdog := engine.NewFeedWatchdog(pair, timeout, feed, events)
+	ts.tradingEngine.SetFeedWatchdog(watchdog)
+
+	ctx, cancel := context.WithCancel(ts.ctx)
+	go watchdog.Run(ctx)
+
+	// 名称带跟踪ID，多机器人交易同一交易对时不冲突，并可与日志对应
+	name := fmt.Sprintf("feed/%s/%s/%s", ts.cexClient.GetName(), pair.String(), timeframe)
+	if ts.traceID != "" {
+		name += "/" + ts.traceID
+	}
+	unregister := dashboard.HealthChecks.Register(name, func() dashboard.ComponentHealth {
+		health := watchdog.Health()
+		return dashboard.ComponentHealth{Healthy: health.Healthy, Detail: health}
+	})
+
+	_, logger := log.WithCtx(ts.ctx)
+	logger.Info(fmt.Sprintf("✓ 数据喂入看门狗: timeout=%s (%v x %s)", timeout, factor, timeframe))
+	return func() {
+		cancel()
+		unregister()
+	}, nil
+}
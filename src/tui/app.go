@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Controller 终端界面按键对应的机器人操作（由交易引擎实现）
+type Controller interface {
+	PauseEntries(ctx context.Context, reason string) error
+	ResumeEntries(ctx context.Context, reason string) error
+	EntriesPaused() bool
+	Flatten(ctx context.Context, reason string) error
+}
+
+// refreshInterval 没有事件时的重绘间隔（刷新时钟和日志）
+const refreshInterval = time.Second
+
+// App 终端界面
+type App struct {
+	title      string
+	mode       string
+	state      *State
+	logs       *LogBuffer
+	controller Controller
+	stop       func() // 停止机器人
+
+	out      io.Writer
+	terminal terminal
+
+	mu             sync.Mutex
+	status         string
+	confirmFlatten bool // 已按 f，等待 y 确认
+	stopping       bool
+}
+
+// NewApp 创建终端界面，stop 在按 q 时调用
+func NewApp(title, mode string, state *State, logs *LogBuffer, controller Controller, stop func()) *App {
+	return &App{title: title, mode: mode, state: state, logs: logs, controller: controller, stop: stop, out: os.Stdout}
+}
+
+// Run 切换到备用屏幕并逐键读取，直到 ctx 结束；退出时恢复终端
+func (a *App) Run(ctx context.Context) error {
+	if err := a.terminal.enterRawMode(); err != nil {
+		return err
+	}
+	fmt.Fprint(a.out, enterAltScreen)
+	defer func() {
+		fmt.Fprint(a.out, leaveAltScreen)
+		a.terminal.restore()
+	}()
+
+	keys := make(chan byte)
+	go readKeys(os.Stdin, keys)
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	a.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key := <-keys:
+			a.HandleKey(ctx, key)
+		case <-a.state.Changed():
+		case <-ticker.C:
+		}
+		a.draw()
+	}
+}
+
+// readKeys 逐字节读取按键，读取结束（标准输入关闭）时退出
+func readKeys(in io.Reader, keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := in.Read(buf); err != nil {
+			return
+		}
+		keys <- buf[0]
+	}
+}
+
+// HandleKey 处理按键：p 暂停/恢复开仓，f 后按 y 清仓，q 停止机器人
+func (a *App) HandleKey(ctx context.Context, key byte) {
+	a.mu.Lock()
+	confirm := a.confirmFlatten
+	a.confirmFlatten = false
+	a.mu.Unlock()
+
+	if confirm {
+		if key == 'y' || key == 'Y' {
+			a.setStatus(a.result("flatten requested", a.controller.Flatten(ctx, "manual flatten from terminal")))
+		} else {
+			a.setStatus("flatten cancelled")
+		}
+		return
+	}
+
+	switch key {
+	case 'p', 'P':
+		if a.controller.EntriesPaused() {
+			a.setStatus(a.result("entries resumed", a.controller.ResumeEntries(ctx, "resumed from terminal")))
+		} else {
+			a.setStatus(a.result("entries paused, entry orders cancelled", a.controller.PauseEntries(ctx, "paused from terminal")))
+		}
+	case 'f', 'F':
+		a.mu.Lock()
+		a.confirmFlatten = true
+		a.mu.Unlock()
+		a.setStatus("cancel all orders and sell the whole position at market? press y to confirm, any other key to cancel")
+	case 'q', 'Q':
+		a.mu.Lock()
+		stopping := a.stopping
+		a.stopping = true
+		a.mu.Unlock()
+		if !stopping {
+			a.setStatus("stopping bot...")
+			go a.stop()
+		}
+	}
+}
+
+// result 操作结果提示
+func (a *App) result(message string, err error) string {
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return "✓ " + message
+}
+
+// setStatus 设置底部提示
+func (a *App) setStatus(status string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status = status
+}
+
+// Status 底部提示
+func (a *App) Status() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.status
+}
+
+// draw 清屏后重绘
+func (a *App) draw() {
+	width, height := a.terminal.size()
+	view := View{
+		Title:         a.title,
+		Mode:          a.mode,
+		Snapshot:      a.state.Snapshot(),
+		EntriesPaused: a.controller.EntriesPaused(),
+		Status:        a.Status(),
+		Logs:          a.logs.Tail(height),
+		Now:           time.Now(),
+	}
+	// 逐键模式下终端仍会把 \n 转为 \r\n，不需要额外处理
+	fmt.Fprint(a.out, clearScreen+Render(view, width, height))
+}
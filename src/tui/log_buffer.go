@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+)
+
+// LogBuffer 保留最近的日志行，终端界面运行时替代日志输出（避免日志打乱界面）
+type LogBuffer struct {
+	mu      sync.Mutex
+	size    int
+	lines   []string
+	partial string // 未以换行结束的部分
+}
+
+// NewLogBuffer 创建日志缓冲，size 为保留的行数
+func NewLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		size = 200
+	}
+	return &LogBuffer{size: size}
+}
+
+// Write 按行写入，超出上限时丢弃最早的行
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	text := b.partial + string(p)
+	parts := strings.Split(text, "\n")
+	b.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		b.lines = append(b.lines, line)
+	}
+	if len(b.lines) > b.size {
+		b.lines = append([]string(nil), b.lines[len(b.lines)-b.size:]...)
+	}
+	return len(p), nil
+}
+
+// Tail 最近 n 行（最早的在前）
+func (b *LogBuffer) Tail(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 {
+		return nil
+	}
+	start := max(len(b.lines)-n, 0)
+	return append([]string(nil), b.lines[start:]...)
+}
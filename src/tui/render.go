@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingbot/src/precision"
+
+	"github.com/shopspring/decimal"
+)
+
+// View 一帧界面的内容
+type View struct {
+	Title         string // 如 "PEPE/USDT 1h"
+	Mode          string // LIVE / DRY RUN / SIGNAL ONLY
+	Snapshot      Snapshot
+	EntriesPaused bool
+	Status        string   // 最近一次按键操作的结果或确认提示
+	Logs          []string // 最近的日志行（最早的在前）
+	Now           time.Time
+}
+
+// maxOrderRows 最多显示的挂单行数
+const maxOrderRows = 8
+
+// Render 按终端宽高渲染一帧（不含清屏控制符），超出宽度的行截断，日志填满剩余高度
+func Render(view View, width, height int) string {
+	snapshot := view.Snapshot
+	var lines []string
+	rule := strings.Repeat("─", width)
+
+	state := "● running"
+	if !snapshot.Running {
+		state = "■ stopped: " + snapshot.StopReason
+	}
+	if view.EntriesPaused {
+		state += " | ⏸ entries paused"
+	}
+	lines = append(lines,
+		fmt.Sprintf(" TradingBot  %s  [%s]  %s   %s", view.Title, view.Mode, state, view.Now.Format("2006-01-02 15:04:05")),
+		rule)
+
+	updated := "-"
+	if !snapshot.UpdatedAt.IsZero() {
+		updated = snapshot.UpdatedAt.Format("15:04:05")
+	}
+	lines = append(lines, fmt.Sprintf(" Price %s   (kline %s)", number(snapshot.Price), updated))
+	lines = append(lines, " Indicators "+formatIndicators(snapshot.Indicators))
+	lines = append(lines, fmt.Sprintf(" Position %s   Cash %s   Equity %s   Peak %s   Drawdown %s%%",
+		number(snapshot.Position), snapshot.Cash.StringFixed(2), snapshot.Equity.StringFixed(2),
+		snapshot.PeakEquity.StringFixed(2), snapshot.Drawdown.Mul(decimal.NewFromInt(100)).StringFixed(2)))
+	if snapshot.Risk != "" {
+		lines = append(lines, " Risk "+snapshot.Risk)
+	}
+
+	lines = append(lines, rule, fmt.Sprintf(" Open orders (%d)", len(snapshot.PendingOrders)))
+	for i, order := range snapshot.PendingOrders {
+		if i == maxOrderRows {
+			lines = append(lines, fmt.Sprintf("   ... %d more", len(snapshot.PendingOrders)-maxOrderRows))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("   %-22s %-12s %14s @ %-14s %s", order.ID, order.Type,
+			number(order.Quantity), number(order.Price), order.Reason))
+	}
+
+	lines = append(lines, rule, " Recent fills")
+	for _, fill := range snapshot.Fills {
+		lines = append(lines, fmt.Sprintf("   %s %-4s %14s @ %s", fill.Time.Format("01-02 15:04:05"), fill.Side,
+			number(fill.Quantity), number(fill.Price)))
+	}
+
+	footer := []string{rule, " [p] pause/resume entries   [f] flatten position   [q] stop bot"}
+	if view.Status != "" {
+		footer = append(footer, " "+view.Status)
+	}
+
+	lines = append(lines, rule, " Log")
+	if room := height - len(lines) - len(footer); room > 0 {
+		for _, line := range tail(view.Logs, room) {
+			lines = append(lines, "   "+line)
+		}
+		for len(lines) < height-len(footer) {
+			lines = append(lines, "")
+		}
+	}
+	lines = append(lines, footer...)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// number 按有效数字显示数量和价格（低价币不显示成 0）
+func number(value decimal.Decimal) string {
+	return precision.FormatSignificant(value, 8)
+}
+
+// formatIndicators 按名称排序显示指标
+func formatIndicators(indicators map[string]float64) string {
+	if len(indicators) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(indicators))
+	for name := range indicators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + number(decimal.NewFromFloat(indicators[name]))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// tail 最后 n 个元素
+func tail(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// truncate 截断到 width 个字符
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
@@ -0,0 +1,134 @@
+// Package tui 实盘和 Dry Run 的终端界面：订阅引擎事件总线，显示价格、指标、挂单、持仓、权益和最近日志，
+// 并通过按键暂停开仓、清仓或停止机器人
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"tradingbot/src/engine"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxRecentFills 界面保留的最近成交条数
+const maxRecentFills = 5
+
+// Snapshot 终端界面显示的状态
+type Snapshot struct {
+	TradingPair string
+	UpdatedAt   time.Time
+	Price       decimal.Decimal
+	Indicators  map[string]float64
+
+	Cash       decimal.Decimal
+	Position   decimal.Decimal
+	Equity     decimal.Decimal
+	PeakEquity decimal.Decimal
+	Drawdown   decimal.Decimal // 当前权益相对峰值的回撤比例（0.2 表示 20%）
+
+	PendingOrders []engine.PendingOrder
+	Fills         []FillLine // 最新的在前
+
+	Risk       string // 最近一次风控状态变化
+	Running    bool
+	StopReason string
+}
+
+// FillLine 成交记录
+type FillLine struct {
+	Time     time.Time
+	Side     string
+	Quantity decimal.Decimal
+	Price    decimal.Decimal
+}
+
+// State 订阅事件总线，维护终端界面的状态；状态变化时通知界面重绘
+type State struct {
+	mu       sync.RWMutex
+	snapshot Snapshot
+	changed  chan struct{}
+}
+
+// NewState 创建终端界面状态
+func NewState() *State {
+	return &State{snapshot: Snapshot{Running: true}, changed: make(chan struct{}, 1)}
+}
+
+// Subscribe 订阅事件总线
+func (s *State) Subscribe(bus *engine.EventBus) {
+	bus.Subscribe(s.handle,
+		engine.EventKlineProcessed, engine.EventSignalGenerated, engine.EventOrderFilled,
+		engine.EventRisk, engine.EventEngineStopped, engine.EventEngineRestarted)
+}
+
+// Changed 状态变化通知（多次变化合并为一次）
+func (s *State) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// handle 处理引擎事件
+func (s *State) handle(ctx context.Context, event *engine.Event) {
+	s.mu.Lock()
+	snapshot := &s.snapshot
+	snapshot.TradingPair = event.TradingPair.String()
+
+	switch event.Type {
+	case engine.EventKlineProcessed:
+		snapshot.UpdatedAt = event.Time
+		snapshot.Price = event.Kline.Close
+		snapshot.Cash, snapshot.Position = event.Portfolio.Cash, event.Portfolio.Position
+		snapshot.Equity = event.Portfolio.Cash.Add(event.Portfolio.Position.Mul(event.Kline.Close))
+		if snapshot.Equity.GreaterThan(snapshot.PeakEquity) {
+			snapshot.PeakEquity = snapshot.Equity
+		}
+		snapshot.Drawdown = decimal.Zero
+		if snapshot.PeakEquity.IsPositive() {
+			snapshot.Drawdown = snapshot.PeakEquity.Sub(snapshot.Equity).Div(snapshot.PeakEquity)
+		}
+		if event.Indicators != nil {
+			snapshot.Indicators = event.Indicators
+		}
+		snapshot.PendingOrders = event.PendingOrders
+	case engine.EventSignalGenerated:
+		if event.Indicators != nil {
+			snapshot.Indicators = event.Indicators
+		}
+	case engine.EventOrderFilled:
+		fill := FillLine{Time: event.Fill.Timestamp, Side: string(event.Fill.Side), Quantity: event.Fill.Quantity, Price: event.Fill.Price}
+		if fill.Time.IsZero() {
+			fill.Time = event.Time
+		}
+		snapshot.Fills = append([]FillLine{fill}, snapshot.Fills...)
+		if len(snapshot.Fills) > maxRecentFills {
+			snapshot.Fills = snapshot.Fills[:maxRecentFills]
+		}
+	case engine.EventRisk:
+		snapshot.Risk = fmt.Sprintf("%s %s: %s", event.Risk.Time.Format("15:04:05"), event.Risk.Type, event.Risk.Reason)
+	case engine.EventEngineStopped:
+		snapshot.Running = false
+		snapshot.StopReason = event.Message
+	case engine.EventEngineRestarted:
+		snapshot.Running = true
+		snapshot.StopReason = ""
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Snapshot 当前状态（副本）
+func (s *State) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := s.snapshot
+	snapshot.PendingOrders = append([]engine.PendingOrder(nil), s.snapshot.PendingOrders...)
+	snapshot.Fills = append([]FillLine(nil), s.snapshot.Fills...)
+	return snapshot
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ANSI 控制序列
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l" // 切换到备用屏幕并隐藏光标
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l" // 显示光标并回到主屏幕
+	clearScreen    = "\x1b[H\x1b[2J"        // 光标回到左上角并清屏
+)
+
+// 终端不支持查询大小时的默认宽高
+const (
+	defaultWidth  = 120
+	defaultHeight = 40
+)
+
+// terminal 通过 stty 把终端切换为逐键读取、不回显，退出时恢复原设置
+type terminal struct {
+	saved string // stty -g 保存的原设置，为空时未切换
+}
+
+// stty 对标准输入所在终端执行 stty
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+// enterRawMode 切换为逐键读取（保留 Ctrl+C 信号）
+func (t *terminal) enterRawMode() error {
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+	t.saved = saved
+	return nil
+}
+
+// restore 恢复原终端设置
+func (t *terminal) restore() {
+	if t.saved != "" {
+		stty(t.saved)
+		t.saved = ""
+	}
+}
+
+// size 终端宽高，无法查询时使用默认值
+func (t *terminal) size() (int, int) {
+	output, err := stty("size")
+	if err != nil {
+		return defaultWidth, defaultHeight
+	}
+	var rows, cols int
+	if _, err := fmt.Sscanf(output, "%d %d", &rows, &cols); err != nil || rows <= 0 || cols <= 0 {
+		return defaultWidth, defaultHeight
+	}
+	return cols, rows
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tradingbot/src/cex"
+	"tradingbot/src/engine"
+	"tradingbot/src/executor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeController 记录按键触发的操作
+type fakeController struct {
+	mu       sync.Mutex
+	paused   bool
+	flattens int
+	err      error
+}
+
+func (c *fakeController) PauseEntries(ctx context.Context, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+	return c.err
+}
+
+func (c *fakeController) ResumeEntries(ctx context.Context, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+	return c.err
+}
+
+func (c *fakeController) EntriesPaused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+func (c *fakeController) Flatten(ctx context.Context, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flattens++
+	return c.err
+}
+
+func TestLogBuffer(t *testing.T) {
+	buffer := NewLogBuffer(3)
+
+	// 不完整的行等换行后再写入
+	buffer.Write([]byte("first"))
+	assert.Empty(t, buffer.Tail(10))
+	buffer.Write([]byte(" line\r\n\nsecond\nthird\nfourth\n"))
+
+	assert.Equal(t, []string{"second", "third", "fourth"}, buffer.Tail(10))
+	assert.Equal(t, []string{"fourth"}, buffer.Tail(1))
+	assert.Nil(t, buffer.Tail(0))
+}
+
+func TestState_HandleEvents(t *testing.T) {
+	pair := cex.TradingPair{Base: "BTC", Quote: "USDT"}
+	bus := engine.NewEventBus()
+	state := NewState()
+	state.Subscribe(bus)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	bus.Publish(ctx, &engine.Event{
+		Type:        engine.EventKlineProcessed,
+		Time:        now,
+		TradingPair: pair,
+		Kline:       &cex.KlineData{Close: decimal.NewFromInt(100)},
+		Portfolio:   &executor.Portfolio{Cash: decimal.NewFromInt(100), Position: decimal.NewFromInt(2)},
+		Indicators:  map[string]float64{"upper": 110},
+		PendingOrders: []engine.PendingOrder{
+			{ID: "stop-1", Type: engine.PendingOrderTypeStopLoss, Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(90)},
+		},
+	})
+	// 价格下跌，回撤相对峰值 300 计算
+	bus.Publish(ctx, &engine.Event{
+		Type:        engine.EventKlineProcessed,
+		Time:        now.Add(time.Hour),
+		TradingPair: pair,
+		Kline:       &cex.KlineData{Close: decimal.NewFromInt(50)},
+		Portfolio:   &executor.Portfolio{Cash: decimal.NewFromInt(100), Position: decimal.NewFromInt(2)},
+	})
+	for i := 0; i < maxRecentFills+1; i++ {
+		bus.Publish(ctx, &engine.Event{Type: engine.EventOrderFilled, Time: now, TradingPair: pair,
+			Fill: &executor.OrderResult{Side: executor.OrderSideBuy, Quantity: decimal.NewFromInt(int64(i + 1)), Price: decimal.NewFromInt(100)}})
+	}
+	bus.Publish(ctx, &engine.Event{Type: engine.EventRisk, Time: now, TradingPair: pair,
+		Risk: &engine.RiskEvent{Type: engine.RiskEventManualPaused, Reason: "test", Time: now}})
+	bus.Publish(ctx, &engine.Event{Type: engine.EventEngineStopped, Time: now, TradingPair: pair, Message: "context canceled"})
+
+	select {
+	case <-state.Changed():
+	default:
+		t.Fatal("expected change notification")
+	}
+
+	snapshot := state.Snapshot()
+	assert.Equal(t, "BTC/USDT", snapshot.TradingPair)
+	assert.True(t, decimal.NewFromInt(50).Equal(snapshot.Price))
+	assert.True(t, decimal.NewFromInt(200).Equal(snapshot.Equity))
+	assert.True(t, decimal.NewFromInt(300).Equal(snapshot.PeakEquity))
+	assert.Equal(t, "0.3333", snapshot.Drawdown.StringFixed(4))
+	assert.Equal(t, map[string]float64{"upper": 110}, snapshot.Indicators)
+	assert.Empty(t, snapshot.PendingOrders)
+	require.Len(t, snapshot.Fills, maxRecentFills)
+	assert.True(t, decimal.NewFromInt(maxRecentFills+1).Equal(snapshot.Fills[0].Quantity))
+	assert.Contains(t, snapshot.Risk, "MANUAL_PAUSED")
+	assert.False(t, snapshot.Running)
+	assert.Equal(t, "context canceled", snapshot.StopReason)
+}
+
+func TestRender(t *testing.T) {
+	var orders []engine.PendingOrder
+	for i := 0; i < maxOrderRows+2; i++ {
+		orders = append(orders, engine.PendingOrder{ID: fmt.Sprintf("order-%d", i), Type: engine.PendingOrderTypeBuyLimit,
+			Quantity: decimal.NewFromInt(1000), Price: decimal.RequireFromString("0.00001234")})
+	}
+	view := View{
+		Title: "PEPE/USDT 1h",
+		Mode:  "DRY RUN",
+		Snapshot: Snapshot{
+			Price:         decimal.RequireFromString("0.00001234"),
+			Indicators:    map[string]float64{"upper": 2, "lower": 1},
+			PendingOrders: orders,
+			Running:       true,
+		},
+		EntriesPaused: true,
+		Status:        "✓ entries paused",
+		Logs:          []string{"old", "newer", "newest"},
+		Now:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	output := Render(view, 100, 40)
+	lines := strings.Split(output, "\n")
+	assert.Len(t, lines, 40)
+	assert.Contains(t, lines[0], "PEPE/USDT 1h")
+	assert.Contains(t, lines[0], "entries paused")
+	assert.Contains(t, output, "Price 0.00001234")
+	assert.Contains(t, output, "lower=1  upper=2")
+	assert.Contains(t, output, "Open orders (10)")
+	assert.Contains(t, output, "... 2 more")
+	assert.Contains(t, output, "   newest")
+	assert.Contains(t, output, "[q] stop bot")
+	assert.Equal(t, " ✓ entries paused", lines[len(lines)-1])
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(line)), 100)
+	}
+
+	// 高度不足时只保留最近的日志
+	lines = strings.Split(Render(view, 100, 25), "\n")
+	assert.Len(t, lines, 25)
+	assert.NotContains(t, strings.Join(lines, "\n"), "   old")
+}
+
+func TestApp_HandleKey(t *testing.T) {
+	controller := &fakeController{}
+	stopped := make(chan struct{}, 2)
+	app := NewApp("BTC/USDT 1h", "LIVE", NewState(), NewLogBuffer(10), controller, func() { stopped <- struct{}{} })
+	ctx := context.Background()
+
+	app.HandleKey(ctx, 'p')
+	assert.True(t, controller.EntriesPaused())
+	app.HandleKey(ctx, 'p')
+	assert.False(t, controller.EntriesPaused())
+	assert.Contains(t, app.Status(), "entries resumed")
+
+	// 清仓需要确认
+	app.HandleKey(ctx, 'f')
+	assert.Equal(t, 0, controller.flattens)
+	app.HandleKey(ctx, 'n')
+	assert.Equal(t, 0, controller.flattens)
+	assert.Equal(t, "flatten cancelled", app.Status())
+	app.HandleKey(ctx, 'f')
+	app.HandleKey(ctx, 'y')
+	assert.Equal(t, 1, controller.flattens)
+
+	controller.err = errors.New("no kline processed yet")
+	app.HandleKey(ctx, 'p')
+	assert.Contains(t, app.Status(), "no kline processed yet")
+
+	// 重复按 q 只停止一次
+	app.HandleKey(ctx, 'q')
+	app.HandleKey(ctx, 'q')
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("stop not called")
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, stopped)
+}